@@ -0,0 +1,124 @@
+// Command migrate applies the versioned SQL migrations in migrations/ to
+// the configured database.
+//
+// Usage:
+//
+//	migrate up               apply all pending migrations
+//	migrate up-to VERSION    apply pending migrations up to VERSION
+//	migrate down             roll back the latest migration
+//	migrate down-to VERSION  roll back to VERSION (0 for an empty schema)
+//	migrate status           list migrations and whether they're applied
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"orus/internal/config"
+	"orus/internal/repositories"
+	"orus/migrations"
+
+	"github.com/pressly/goose/v3"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cfg := config.MustLoad()
+
+	db, err := repositories.ConnectPostgres(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("Failed to get database instance:", err)
+	}
+	defer sqlDB.Close()
+
+	provider, err := migrations.NewProvider(sqlDB)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		results, err := provider.Up(ctx)
+		report(results)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Keep the coming months' transaction partitions ready
+		created, err := repositories.NewPartitionManager(db).EnsurePartitions(repositories.PartitionMonthsAhead)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if created > 0 {
+			log.Printf("Created %d transaction partitions", created)
+		}
+	case "up-to":
+		results, err := provider.UpTo(ctx, version())
+		report(results)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "down":
+		result, err := provider.Down(ctx)
+		if result != nil {
+			report([]*goose.MigrationResult{result})
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "down-to":
+		results, err := provider.DownTo(ctx, version())
+		report(results)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.State == goose.StateApplied {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%5d  %-40s  %s\n", s.Source.Version, s.Source.Path, applied)
+		}
+	default:
+		usage()
+	}
+}
+
+func report(results []*goose.MigrationResult) {
+	if len(results) == 0 {
+		log.Println("No migrations to run")
+	}
+	for _, r := range results {
+		log.Println(r)
+	}
+}
+
+func version() int64 {
+	if len(os.Args) < 3 {
+		usage()
+	}
+	v, err := strconv.ParseInt(os.Args[2], 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid version %q", os.Args[2])
+	}
+	return v
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | up-to VERSION | down | down-to VERSION | status")
+	os.Exit(2)
+}
@@ -5,17 +5,22 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"orus/internal/config"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 
-	// "orus/internal/handlers"
-	"orus/internal/repositories"
+	"orus/internal/container"
+	"orus/internal/middleware"
 	"orus/internal/routes"
+	"orus/internal/utils/response"
+	"orus/migrations"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
@@ -28,30 +33,28 @@ import (
 // - Configures routes
 // - Starts the HTTP server
 func main() {
-	// Load environment variables
-	config.LoadEnv()
+	// Load and validate configuration; bad or unsafe settings stop startup
+	cfg := config.MustLoad()
 
-	// Initialize databases (PostgreSQL + Redis)
-	repositories.InitDB()
-
-	sqlDB, err := repositories.DB.DB()
+	// Connect to PostgreSQL and Redis and wire the application on them
+	c, err := container.New(cfg)
 	if err != nil {
-		log.Fatalf("Failed to get database instance: %v", err)
+		log.Fatalf("Failed to initialize application: %v", err)
 	}
+	defer c.Close()
 
-	maxIdleConns, _ := strconv.Atoi(config.GetEnv("DB_MAX_IDLE_CONNS", "10"))
-	maxOpenConns, _ := strconv.Atoi(config.GetEnv("DB_MAX_OPEN_CONNS", "100"))
-	connMaxLifetime, _ := time.ParseDuration(config.GetEnv("DB_CONN_MAX_LIFETIME", "1h"))
-	connMaxIdleTime, _ := time.ParseDuration(config.GetEnv("DB_CONN_MAX_IDLE_TIME", "30m"))
+	sqlDB, err := c.DB.DB()
 	if err != nil {
-		maxIdleConns = 10
-		log.Printf("Invalid DB_MAX_IDLE_CONNS, using default: %d", maxIdleConns)
+		log.Fatalf("Failed to get database instance: %v", err)
 	}
 
-	sqlDB.SetMaxIdleConns(maxIdleConns)       // Maximum number of idle connections
-	sqlDB.SetMaxOpenConns(maxOpenConns)       // Maximum number of open connections
-	sqlDB.SetConnMaxLifetime(connMaxLifetime) // Maximum lifetime of a connection
-	sqlDB.SetConnMaxIdleTime(connMaxIdleTime) // Maximum idle time for a connection
+	// The server never changes the schema; refuse to run against one that
+	// is behind the code
+	if pending, err := migrations.Pending(context.Background(), sqlDB); err != nil {
+		log.Fatalf("Failed to check migrations: %v", err)
+	} else if pending {
+		log.Fatal("Database has pending migrations; run `go run ./cmd/migrate up` first")
+	}
 
 	// Add a periodic check of connection pool stats
 	go func() {
@@ -70,47 +73,27 @@ func main() {
 		log.Println("✅ Successfully connected to database with connection pooling")
 	}
 
-	// Clear Redis cache on startup using CacheService
-	if repositories.CacheService != nil {
-		err := repositories.CacheService.FlushAll(context.Background())
-		if err != nil {
-			log.Printf("⚠️ Failed to flush Redis cache: %v", err)
-		} else {
-			log.Println("✅ Redis cache flushed on startup")
-		}
-	}
-
-	defer func() {
-		// Close PostgreSQL connection
-		if repositories.DB != nil {
-			sqlDB, err := repositories.DB.DB()
-			if err != nil {
-				log.Printf("⚠️ Failed to get database instance: %v", err)
-				return
-			}
-			if err := sqlDB.Close(); err != nil {
-				log.Printf("⚠️ Failed to close database connection: %v", err)
-			}
-		}
-
-		// Close Redis connection via CacheService
-		if repositories.CacheService != nil {
-			if err := repositories.CacheService.Close(); err != nil {
-				log.Printf("⚠️ Failed to close Redis connection: %v", err)
-			}
-		}
-	}()
-
-	// Create Fiber app
-	app := fiber.New()
-
-	// CORS middleware
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "http://localhost:5173",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH",
-		AllowCredentials: true,
-	}))
+	// Create Fiber app; every returned error is rendered as the error envelope
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+		// Client IPs are checked against merchant API allowlists, so the
+		// forwarded header is only believed from the configured proxies
+		ProxyHeader:             cfg.Server.ProxyHeader,
+		EnableTrustedProxyCheck: len(cfg.Server.TrustedProxies) > 0,
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+
+	// Orchestrator probes and metrics, registered first so they skip
+	// logging, CORS and rate limits
+	health := c.Handlers.Health
+	app.Get("/healthz", health.Live)
+	app.Get("/readyz", health.Ready)
+	app.Get("/metrics", health.Metrics)
+
+	// CORS and security headers, with their own policy for the public
+	// checkout pages
+	routes.SetupSecurity(app, cfg.Security)
 
 	// Middleware
 	app.Use(logger.New(logger.Config{
@@ -124,9 +107,7 @@ func main() {
 			return c.IP()
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(429).JSON(fiber.Map{
-				"error": "Too many requests. Please try again later.",
-			})
+			return response.Error(c, fiber.StatusTooManyRequests, "Too many requests. Please try again later.")
 		},
 	}))
 
@@ -137,15 +118,77 @@ func main() {
 			return c.IP()
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(429).JSON(fiber.Map{
-				"error": "Too many requests. Please try again later.",
-			})
+			return response.Error(c, fiber.StatusTooManyRequests, "Too many requests. Please try again later.")
+		},
+	}))
+
+	// One-time codes are limited per client and per user, on top of the
+	// attempts each code allows
+	app.Use("/api/verify-otp", limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 1 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return response.Error(c, fiber.StatusTooManyRequests, "Too many requests. Please try again later.")
+		},
+	}), limiter.New(limiter.Config{
+		Max:        10,
+		Expiration: 15 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			var body struct {
+				UserID uint `json:"user_id"`
+			}
+			_ = json.Unmarshal(c.Body(), &body)
+			return "user:" + strconv.FormatUint(uint64(body.UserID), 10)
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return response.Error(c, fiber.StatusTooManyRequests, "Too many requests. Please try again later.")
 		},
 	}))
 
 	// Routes
-	routes.SetupRoutes(app, repositories.DB)
+	routes.SetupRoutes(app, c)
+
+	// Background jobs and cache invalidation
+	c.Start(context.Background())
+
+	// Start server and serve until SIGINT or SIGTERM
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(":" + cfg.Server.Port)
+	}()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-listenErr:
+		log.Printf("⚠️ Server stopped: %v", err)
+	case sig := <-quit:
+		log.Printf("Received %s, shutting down", sig)
+	}
+
+	shutdown(cfg.Server, app, c)
+}
+
+// shutdown stops the server in order: readiness fails so load balancers
+// stop routing here, in-flight requests such as payments complete, then
+// running jobs finish. The deferred Close in main runs after it returns.
+func shutdown(cfg config.ServerConfig, app *fiber.App, c *container.Container) {
+	c.Handlers.Health.Drain()
+	time.Sleep(cfg.ShutdownDrainDelay)
 
-	// Start server
-	log.Fatal(app.Listen(":" + config.GetEnv("PORT", "3000")))
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		log.Printf("⚠️ Failed to drain HTTP requests: %v", err)
+	} else {
+		log.Println("✅ HTTP requests drained")
+	}
+	if err := c.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ Background jobs cancelled before finishing: %v", err)
+	} else {
+		log.Println("✅ Background jobs stopped")
+	}
 }
@@ -1,39 +1,610 @@
+// Package config loads the application configuration into a typed Config.
+// Defaults are overridden by an optional YAML file, which is overridden by
+// environment variables, so deployments can keep a shared file and set
+// secrets through the environment.
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-// LoadEnv loads variables from a .env file if present.
-func LoadEnv() {
-	if err := godotenv.Load(); err != nil {
-		log.Printf("no .env file found: %v", err)
+// Environments
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// defaultConfigFile is read when CONFIG_FILE isn't set, if it exists
+const defaultConfigFile = "config.yaml"
+
+// Config is the whole application configuration. The env tags name the
+// environment variable overriding each field.
+type Config struct {
+	Env        string           `yaml:"env" env:"ENV"`
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Redis      RedisConfig      `yaml:"redis"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Passwords  PasswordConfig   `yaml:"passwords"`
+	Social     SocialConfig     `yaml:"social_login"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Fraud      FraudConfig      `yaml:"fraud"`
+	Compliance ComplianceConfig `yaml:"compliance"`
+	Transfers  TransferConfig   `yaml:"transfers"`
+	Disputes   DisputeConfig    `yaml:"disputes"`
+	Escrow     EscrowConfig     `yaml:"escrow"`
+	Reserve    ReserveConfig    `yaml:"reserve"`
+	Funding    FundingConfig    `yaml:"funding"`
+	Issuing    IssuingConfig    `yaml:"issuing"`
+	FX         FXConfig         `yaml:"fx"`
+	Vault      VaultConfig      `yaml:"vault"`
+	Cards      CardConfig       `yaml:"cards"`
+	Exports    ExportConfig     `yaml:"exports"`
+	Accounting AccountingConfig `yaml:"accounting"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Metadata   MetadataConfig   `yaml:"metadata"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Security   SecurityConfig   `yaml:"security"`
+	Features   FeaturesConfig   `yaml:"features"`
+}
+
+type ServerConfig struct {
+	Port          string `yaml:"port" env:"PORT"`
+	PublicBaseURL string `yaml:"public_base_url" env:"PUBLIC_BASE_URL"` // Base of links sent to customers
+	// ShutdownTimeout bounds draining requests and jobs on SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// ShutdownDrainDelay keeps serving after readiness starts failing, so
+	// load balancers notice before the listener closes
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// ProxyHeader carries the client IP set by the load balancer, such as
+	// X-Forwarded-For. It is only read from TrustedProxies, and client IPs
+	// are taken from the connection when it is empty.
+	ProxyHeader    string   `yaml:"proxy_header" env:"PROXY_HEADER"`
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	// CountryHeader carries the client's country as resolved by the edge,
+	// such as CF-IPCountry. Logins from a new country need a one-time code.
+	CountryHeader string `yaml:"country_header" env:"COUNTRY_HEADER"`
+}
+
+type DatabaseConfig struct {
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            int           `yaml:"port" env:"DB_PORT"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD"`
+	Name            string        `yaml:"name" env:"DB_NAME"`
+	SSLMode         string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	// ReplicaDSN points heavy reads at a read replica; empty keeps every
+	// query on the primary
+	ReplicaDSN string `yaml:"replica_dsn" env:"DB_REPLICA_DSN"`
+	// ReplicaMaxLag sends reads back to the primary while the replica is
+	// further behind, and for this long after a user's own writes
+	ReplicaMaxLag time.Duration `yaml:"replica_max_lag" env:"DB_REPLICA_MAX_LAG"`
+}
+
+// DSN is the connection string for the configured database
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode)
+}
+
+type RedisConfig struct {
+	Host     string `yaml:"host" env:"REDIS_HOST"`
+	Port     string `yaml:"port" env:"REDIS_PORT"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB"`
+
+	// Namespace prefixes every cache key so environments can share a
+	// Redis server; empty uses "orus:<env>:"
+	Namespace string        `yaml:"namespace" env:"REDIS_NAMESPACE"`
+	TTL       time.Duration `yaml:"ttl" env:"REDIS_TTL"`
+	// TTLJitter stretches each entry's TTL by up to this fraction so
+	// entries cached together don't all expire together
+	TTLJitter float64 `yaml:"ttl_jitter" env:"REDIS_TTL_JITTER"`
+	// Timeout bounds connecting to Redis and each command, so a stalled
+	// Redis delays requests by at most this before they use the database
+	Timeout time.Duration `yaml:"timeout" env:"REDIS_TIMEOUT"`
+}
+
+type AuthConfig struct {
+	JWTSecret     string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	RefreshSecret string `yaml:"refresh_secret" env:"REFRESH_SECRET"`
+	// JWTKeyDir holds PEM private keys, RSA or Ed25519, named <key id>.pem.
+	// When set, access tokens are signed with JWTActiveKey instead of
+	// JWTSecret and every key in the directory verifies them; rotate by
+	// adding a key, making it active, and removing the old one a day later.
+	JWTKeyDir    string `yaml:"jwt_key_dir" env:"JWT_KEY_DIR"`
+	JWTActiveKey string `yaml:"jwt_active_key" env:"JWT_ACTIVE_KEY"`
+	// PaymentCodeSecret keys the hashes one-time payment codes are stored
+	// under; a short numeric code hashed without a key is easy to reverse
+	PaymentCodeSecret string `yaml:"payment_code_secret" env:"PAYMENT_CODE_SECRET"`
+	// PaymentIntentSecret signs payment intent links, so a link's amount,
+	// recipient and expiry can't be altered
+	PaymentIntentSecret string `yaml:"payment_intent_secret" env:"PAYMENT_INTENT_SECRET"`
+}
+
+// PasswordConfig sets how passwords are hashed. New hashes are argon2id
+// with these parameters; bcrypt hashes and hashes made with older
+// parameters still verify and are replaced at the user's next login.
+type PasswordConfig struct {
+	// Memory is in KiB
+	Memory      int `yaml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY"`
+	Iterations  int `yaml:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS"`
+	Parallelism int `yaml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM"`
+	// Pepper keys every argon2id hash and is kept out of the database, so
+	// a leaked users table can't be cracked alone. PepperFile, a secret
+	// mounted from the secret store, takes precedence over Pepper.
+	// Changing the pepper makes every argon2id hash unverifiable.
+	Pepper     string `yaml:"pepper" env:"PASSWORD_PEPPER"`
+	PepperFile string `yaml:"pepper_file" env:"PASSWORD_PEPPER_FILE"`
+}
+
+// SocialConfig holds the OpenID Connect clients users sign in with Google
+// and Apple through. A provider without a client ID is off; admins can
+// also turn a configured one off with its feature flag. Both redirect to
+// <public base URL>/api/login/<provider>/callback.
+type SocialConfig struct {
+	GoogleClientID     string `yaml:"google_client_id" env:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `yaml:"google_client_secret" env:"GOOGLE_CLIENT_SECRET"`
+	// AppleClientID is the Services ID. Apple has no static client
+	// secret; each token request is signed with the team's private key.
+	AppleClientID       string `yaml:"apple_client_id" env:"APPLE_CLIENT_ID"`
+	AppleTeamID         string `yaml:"apple_team_id" env:"APPLE_TEAM_ID"`
+	AppleKeyID          string `yaml:"apple_key_id" env:"APPLE_KEY_ID"`
+	ApplePrivateKeyFile string `yaml:"apple_private_key_file" env:"APPLE_PRIVATE_KEY_FILE"`
+}
+
+type StorageConfig struct {
+	Driver    string `yaml:"driver" env:"STORAGE_DRIVER"` // "local" or "s3"
+	LocalDir  string `yaml:"local_dir" env:"STORAGE_DIR"`
+	Endpoint  string `yaml:"s3_endpoint" env:"S3_ENDPOINT"`
+	Region    string `yaml:"s3_region" env:"S3_REGION"`
+	Bucket    string `yaml:"s3_bucket" env:"S3_BUCKET"`
+	AccessKey string `yaml:"s3_access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"s3_secret_key" env:"S3_SECRET_KEY"`
+}
+
+// FraudConfig holds the platform-wide fraud rules; zero disables a rule
+type FraudConfig struct {
+	MaxChargeAmount            float64  `yaml:"max_charge_amount" env:"FRAUD_MAX_CHARGE_AMOUNT"`
+	MaxChargesPerCustomerDaily int      `yaml:"max_charges_per_customer_daily" env:"FRAUD_MAX_CHARGES_PER_CUSTOMER_DAILY"`
+	BlockedCountries           []string `yaml:"blocked_countries" env:"FRAUD_BLOCKED_COUNTRIES"`
+}
+
+// ComplianceConfig sets when transactions are drafted into regulatory
+// reports for compliance officers
+type ComplianceConfig struct {
+	// LargeTransactionThreshold drafts a large transaction report (CTR)
+	// for every transaction of at least this much
+	LargeTransactionThreshold float64 `yaml:"large_transaction_threshold" env:"COMPLIANCE_LARGE_TRANSACTION_THRESHOLD"`
+	// A suspicious activity report (SAR) is drafted when a user makes at
+	// least StructuringMinCount transactions of StructuringFloor or more,
+	// each below the large transaction threshold, adding up to
+	// StructuringMinTotal within StructuringWindow
+	StructuringWindow   time.Duration `yaml:"structuring_window" env:"COMPLIANCE_STRUCTURING_WINDOW"`
+	StructuringMinCount int           `yaml:"structuring_min_count" env:"COMPLIANCE_STRUCTURING_MIN_COUNT"`
+	StructuringMinTotal float64       `yaml:"structuring_min_total" env:"COMPLIANCE_STRUCTURING_MIN_TOTAL"`
+	StructuringFloor    float64       `yaml:"structuring_floor" env:"COMPLIANCE_STRUCTURING_FLOOR"`
+}
+
+type TransferConfig struct {
+	BeneficiaryCoolingOffHours int `yaml:"beneficiary_cooling_off_hours" env:"BENEFICIARY_COOLING_OFF_HOURS"`
+	// ProcessingTimeout bounds each wallet, transaction and QR payment
+	// operation; one running longer is cancelled and answered with a 504
+	ProcessingTimeout time.Duration `yaml:"processing_timeout" env:"PROCESSING_TIMEOUT"`
+	// MaxOverdraft caps the overdraft limit an admin may give a wallet
+	MaxOverdraft float64 `yaml:"max_overdraft" env:"MAX_OVERDRAFT"`
+	// PendingTTL is how long a transaction may stay pending before the
+	// cleanup job expires it and releases its holds
+	PendingTTL time.Duration `yaml:"pending_ttl" env:"PENDING_TRANSACTION_TTL"`
+	// ConfirmationDigestThreshold is how many payment confirmations a user
+	// must have waiting for them to be sent as one digest
+	ConfirmationDigestThreshold int `yaml:"confirmation_digest_threshold" env:"PAYMENT_CONFIRMATION_DIGEST_THRESHOLD"`
+	// DeviceConfirmationThreshold is the transfer amount from which the
+	// sender must approve on a trusted device; zero turns it off
+	DeviceConfirmationThreshold float64 `yaml:"device_confirmation_threshold" env:"TRANSFER_DEVICE_CONFIRMATION_THRESHOLD"`
+	// DeviceConfirmationWindow is how long a transfer waits for approval
+	// before it is cancelled
+	DeviceConfirmationWindow time.Duration `yaml:"device_confirmation_window" env:"TRANSFER_DEVICE_CONFIRMATION_WINDOW"`
+	// PINThreshold is the transfer amount from which users who have set a
+	// payment PIN must enter it
+	PINThreshold float64 `yaml:"pin_threshold" env:"TRANSFER_PIN_THRESHOLD"`
+	// ScanConfirmationWindow is how long a merchant's scan of a payment
+	// code waits for the payer to approve it, when they asked to
+	ScanConfirmationWindow time.Duration `yaml:"scan_confirmation_window" env:"SCAN_CONFIRMATION_WINDOW"`
+}
+
+type DisputeConfig struct {
+	ResponseDays                int     `yaml:"response_days" env:"DISPUTE_RESPONSE_DAYS"`
+	ChargebackRepresentmentDays int     `yaml:"chargeback_representment_days" env:"CHARGEBACK_REPRESENTMENT_DAYS"`
+	ChargebackFee               float64 `yaml:"chargeback_fee" env:"CHARGEBACK_FEE"`
+}
+
+type EscrowConfig struct {
+	AutoReleaseDays int `yaml:"auto_release_days" env:"ESCROW_AUTO_RELEASE_DAYS"`
+}
+
+// ReserveConfig is the rolling reserve of merchants without their own
+type ReserveConfig struct {
+	// Percent of each payment a merchant receives that is withheld, 0.1
+	// for 10%; zero withholds nothing
+	Percent float64 `yaml:"percent" env:"RESERVE_PERCENT"`
+	// HoldDays is how long withheld funds stay held
+	HoldDays int `yaml:"hold_days" env:"RESERVE_HOLD_DAYS"`
+}
+
+type FundingConfig struct {
+	BankProvider  string  `yaml:"bank_provider" env:"BANK_PROVIDER"`
+	WebhookSecret string  `yaml:"webhook_secret" env:"BANK_WEBHOOK_SECRET"`
+	NSFFee        float64 `yaml:"nsf_fee" env:"ACH_NSF_FEE"`
+}
+
+type IssuingConfig struct {
+	CardIssuer    string `yaml:"card_issuer" env:"CARD_ISSUER"`
+	WebhookSecret string `yaml:"webhook_secret" env:"CARD_ISSUER_WEBHOOK_SECRET"`
+}
+
+// FXConfig picks where exchange rates come from: "sandbox", "ecb" or
+// "fixer", which needs APIKey
+type FXConfig struct {
+	Provider string `yaml:"provider" env:"FX_PROVIDER"`
+	APIKey   string `yaml:"api_key" env:"FX_API_KEY"`
+	// CacheTTL is how long rates are quoted before the provider is asked
+	// again; the ECB publishes once a day
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"FX_CACHE_TTL"`
+}
+
+// VaultConfig holds the key card tokens are encrypted under. Changing it
+// leaves the cards already vaulted unreadable.
+type VaultConfig struct {
+	Key string `yaml:"key" env:"VAULT_KEY"`
+}
+
+// Security profiles, picked by environment unless set
+const (
+	SecurityProfileStrict  = "strict"  // Staging and production
+	SecurityProfileRelaxed = "relaxed" // Development and test
+)
+
+// SecurityConfig holds the CORS and security headers sent with responses.
+// Fields left empty take the profile's value.
+type SecurityConfig struct {
+	Profile string `yaml:"profile" env:"SECURITY_PROFILE"`
+	// AllowedOrigins may call the API from a browser, with credentials
+	AllowedOrigins        []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	ContentSecurityPolicy string        `yaml:"content_security_policy" env:"CONTENT_SECURITY_POLICY"`
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+	// Checkout overrides the above on the public checkout pages, which
+	// merchants call and embed from their own sites
+	Checkout CheckoutSecurityConfig `yaml:"checkout"`
+	// ClientCertHeader carries the SHA-256 fingerprint of the merchant API
+	// client certificate when TLS ends at a trusted proxy. It requires
+	// Server.TrustedProxies.
+	ClientCertHeader string `yaml:"client_cert_header" env:"CLIENT_CERT_HEADER"`
+}
+
+type CheckoutSecurityConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CHECKOUT_ALLOWED_ORIGINS"` // "*" allows any, without credentials
+	FrameAncestors []string `yaml:"frame_ancestors" env:"CHECKOUT_FRAME_ANCESTORS"`
+}
+
+// applyProfile fills the fields left empty from the profile, which
+// defaults to strict in staging and production and relaxed elsewhere
+func (s *SecurityConfig) applyProfile(env string) {
+	if s.Profile == "" {
+		s.Profile = SecurityProfileRelaxed
+		if env == EnvStaging || env == EnvProduction {
+			s.Profile = SecurityProfileStrict
+		}
+	}
+
+	if s.ContentSecurityPolicy == "" {
+		s.ContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+	}
+	if s.Checkout.AllowedOrigins == nil {
+		s.Checkout.AllowedOrigins = []string{"*"}
+	}
+	if s.Checkout.FrameAncestors == nil {
+		s.Checkout.FrameAncestors = []string{"*"}
+	}
+
+	switch s.Profile {
+	case SecurityProfileStrict:
+		// Browsers must use HTTPS for a year; origins must be configured
+		if s.HSTSMaxAge == 0 {
+			s.HSTSMaxAge = 365 * 24 * time.Hour
+		}
+	case SecurityProfileRelaxed:
+		// The web app's dev server
+		if s.AllowedOrigins == nil {
+			s.AllowedOrigins = []string{"http://localhost:5173"}
+		}
 	}
 }
 
-// GetEnv returns an environment variable or a default value.
-func GetEnv(key, defaultVal string) string {
-	if val, ok := os.LookupEnv(key); ok && val != "" {
-		return val
+// CardConfig covers top-ups charged to linked cards
+type CardConfig struct {
+	// ThreeDSThreshold steps top-ups of at least this much up to 3-D
+	// Secure; zero leaves it to the risk rules
+	ThreeDSThreshold float64 `yaml:"three_ds_threshold" env:"CARD_3DS_THRESHOLD"`
+	// WebhookSecret authenticates the processor's payment webhooks
+	WebhookSecret string `yaml:"webhook_secret" env:"CARD_PROCESSOR_WEBHOOK_SECRET"`
+}
+
+type ExportConfig struct {
+	StorageDir string `yaml:"storage_dir" env:"EXPORT_STORAGE_DIR"`
+}
+
+// AccountingConfig sets how the daily accounting journals are exported
+type AccountingConfig struct {
+	// Formats are rendered and delivered every day: csv, ofx, quickbooks
+	// or xero
+	Formats []string `yaml:"formats" env:"ACCOUNTING_FORMATS"`
+	// Accounts maps internal accounts, such as fee_revenue or
+	// customer_funds, to the chart of accounts; unmapped ones keep their
+	// internal code and name
+	Accounts map[string]AccountingAccount `yaml:"accounts"`
+	// Delivery is "s3" or "sftp"; empty keeps journals for download only
+	Delivery string               `yaml:"delivery" env:"ACCOUNTING_DELIVERY"`
+	S3       AccountingS3Config   `yaml:"s3"`
+	SFTP     AccountingSFTPConfig `yaml:"sftp"`
+}
+
+// AccountingAccount is an account in the accounting system's chart. Xero
+// matches accounts by code, QuickBooks by name.
+type AccountingAccount struct {
+	Code string `yaml:"code"`
+	Name string `yaml:"name"`
+}
+
+type AccountingS3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"ACCOUNTING_S3_ENDPOINT"`
+	Region    string `yaml:"region" env:"ACCOUNTING_S3_REGION"`
+	Bucket    string `yaml:"bucket" env:"ACCOUNTING_S3_BUCKET"`
+	AccessKey string `yaml:"access_key" env:"ACCOUNTING_S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"ACCOUNTING_S3_SECRET_KEY"`
+	Prefix    string `yaml:"prefix" env:"ACCOUNTING_S3_PREFIX"`
+}
+
+type AccountingSFTPConfig struct {
+	Host     string `yaml:"host" env:"ACCOUNTING_SFTP_HOST"`
+	Port     int    `yaml:"port" env:"ACCOUNTING_SFTP_PORT"`
+	User     string `yaml:"user" env:"ACCOUNTING_SFTP_USER"`
+	Password string `yaml:"password" env:"ACCOUNTING_SFTP_PASSWORD"`
+	// PrivateKeyFile is a PEM key, used instead of the password when set
+	PrivateKeyFile string `yaml:"private_key_file" env:"ACCOUNTING_SFTP_PRIVATE_KEY_FILE"`
+	// HostKey is the server's public key in authorized_keys format; the
+	// connection is refused if the server presents another
+	HostKey string `yaml:"host_key" env:"ACCOUNTING_SFTP_HOST_KEY"`
+	Dir     string `yaml:"dir" env:"ACCOUNTING_SFTP_DIR"`
+}
+
+type RetentionConfig struct {
+	// ArchiveAfterDays moves settled transactions to the archive; 0 keeps
+	// them all in the hot table
+	ArchiveAfterDays int `yaml:"archive_after_days" env:"TRANSACTION_ARCHIVE_AFTER_DAYS"`
+}
+
+type MetadataConfig struct {
+	SchemaMode string `yaml:"schema_mode" env:"METADATA_SCHEMA_MODE"` // "flag" or "strict"
+}
+
+// RateLimitConfig sets how many requests a user or merchant API key may
+// make per window in each endpoint class; admins override it per subject
+type RateLimitConfig struct {
+	Enabled  bool          `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Window   time.Duration `yaml:"window" env:"RATE_LIMIT_WINDOW"`
+	Reads    int           `yaml:"reads" env:"RATE_LIMIT_READS"`
+	Writes   int           `yaml:"writes" env:"RATE_LIMIT_WRITES"`
+	Payments int           `yaml:"payments" env:"RATE_LIMIT_PAYMENTS"`
+}
+
+// FeaturesConfig turns feature flags on or off over their defaults for the
+// environment. Admins can still override either at runtime.
+type FeaturesConfig struct {
+	Enabled  []string `yaml:"enabled" env:"FEATURES_ENABLED"`
+	Disabled []string `yaml:"disabled" env:"FEATURES_DISABLED"`
+}
+
+// Default returns the configuration used for anything not set elsewhere.
+// Its secrets only suit development; Validate rejects them in production.
+func Default() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:            "3000",
+			PublicBaseURL:   "http://localhost:3000",
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Password:        "postgres",
+			Name:            "orus",
+			SSLMode:         "disable",
+			MaxIdleConns:    10,
+			MaxOpenConns:    100,
+			ConnMaxLifetime: time.Hour,
+			ConnMaxIdleTime: 30 * time.Minute,
+			ReplicaMaxLag:   5 * time.Second,
+		},
+		Redis: RedisConfig{
+			Host:      "localhost",
+			Port:      "6379",
+			TTL:       24 * time.Hour,
+			TTLJitter: 0.1,
+			Timeout:   500 * time.Millisecond,
+		},
+		Auth: AuthConfig{
+			JWTSecret:     "orus",
+			RefreshSecret: "your-refresh-secret",
+			// Development only, like the secrets above
+			PaymentCodeSecret:   "orus-payment-codes",
+			PaymentIntentSecret: "orus-payment-intents",
+		},
+		Passwords: PasswordConfig{
+			Memory:      64 * 1024,
+			Iterations:  3,
+			Parallelism: 2,
+			Pepper:      "orus-password-pepper", // Development only
+		},
+		Storage: StorageConfig{
+			Driver:   "local",
+			LocalDir: "./uploads",
+			Region:   "us-east-1",
+		},
+		Compliance: ComplianceConfig{
+			LargeTransactionThreshold: 10000,
+			StructuringWindow:         24 * time.Hour,
+			StructuringMinCount:       5,
+			StructuringMinTotal:       8000,
+			StructuringFloor:          500,
+		},
+		Transfers: TransferConfig{
+			ProcessingTimeout:           10 * time.Second,
+			MaxOverdraft:                50,
+			PendingTTL:                  24 * time.Hour,
+			ConfirmationDigestThreshold: 10,
+			DeviceConfirmationThreshold: 1000,
+			DeviceConfirmationWindow:    5 * time.Minute,
+			PINThreshold:                200,
+			ScanConfirmationWindow:      2 * time.Minute,
+		},
+		Disputes: DisputeConfig{
+			ResponseDays:                7,
+			ChargebackRepresentmentDays: 10,
+			ChargebackFee:               15,
+		},
+		Escrow: EscrowConfig{
+			AutoReleaseDays: 7,
+		},
+		Reserve: ReserveConfig{
+			HoldDays: 90,
+		},
+		Funding: FundingConfig{
+			BankProvider: "sandbox",
+			NSFFee:       15,
+		},
+		Issuing: IssuingConfig{
+			CardIssuer: "sandbox",
+		},
+		FX: FXConfig{
+			Provider: "sandbox",
+			CacheTTL: time.Hour,
+		},
+		Cards: CardConfig{
+			ThreeDSThreshold: 500,
+		},
+		Vault: VaultConfig{
+			Key: "orus-card-vault", // Development only
+		},
+		Exports: ExportConfig{
+			StorageDir: "./exports",
+		},
+		Accounting: AccountingConfig{
+			Formats: []string{"csv"},
+			SFTP: AccountingSFTPConfig{
+				Port: 22,
+			},
+		},
+		Retention: RetentionConfig{
+			ArchiveAfterDays: 730,
+		},
+		Metadata: MetadataConfig{
+			SchemaMode: "flag",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:  true,
+			Window:   time.Minute,
+			Reads:    300,
+			Writes:   60,
+			Payments: 20,
+		},
 	}
-	return defaultVal
 }
 
-// GetIntEnv returns an int environment variable or a default value.
-func GetIntEnv(key string, defaultVal int) int {
-	if val, ok := os.LookupEnv(key); ok {
-		if i, err := strconv.Atoi(val); err == nil {
-			return i
+// Load reads the configuration: defaults, then the YAML file named by
+// CONFIG_FILE (or config.yaml if present), then environment variables,
+// including those in a .env file. It fails if the result doesn't validate.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	cfg := Default()
+
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit {
+		path = defaultConfigFile
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
 		}
+	case explicit || !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Passwords.loadPepper(); err != nil {
+		return nil, err
 	}
-	return defaultVal
+	cfg.Security.applyProfile(cfg.Env)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadPepper reads the pepper from PepperFile when one is set
+func (p *PasswordConfig) loadPepper() error {
+	if p.PepperFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(p.PepperFile)
+	if err != nil {
+		return fmt.Errorf("failed to read password pepper: %w", err)
+	}
+	p.Pepper = strings.TrimSpace(string(data))
+	return nil
 }
 
-// IsProduction checks if the app runs in production mode.
-func IsProduction() bool {
-	return GetEnv("ENV", "development") == "production"
+// MustLoad loads the configuration or exits; commands call it first thing
+func MustLoad() *Config {
+	cfg, err := Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	return cfg
+}
+
+// IsProduction reports whether the app runs in production mode
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// CacheNamespace is the prefix of every cache key
+func (c *Config) CacheNamespace() string {
+	if c.Redis.Namespace != "" {
+		return c.Redis.Namespace
+	}
+	return "orus:" + c.Env + ":"
 }
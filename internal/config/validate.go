@@ -0,0 +1,242 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// minSecretLength is the shortest signing secret production accepts
+const minSecretLength = 32
+
+// developmentSecrets are defaults that have shipped with the code; anyone
+// can sign tokens with them
+var developmentSecrets = map[string]bool{
+	"orus":                 true,
+	"your-secret-key":      true,
+	"your-refresh-secret":  true,
+	"orus-password-pepper": true,
+	"secret":               true,
+	"changeme":             true,
+	"orus-payment-codes":   true,
+	"orus-card-vault":      true,
+}
+
+// Validate checks the configuration is complete and consistent. In
+// production it also requires real secrets; elsewhere it only warns.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Env {
+	case EnvDevelopment, EnvTest, EnvStaging, EnvProduction:
+	default:
+		add("ENV must be development, test, staging or production, not %q", c.Env)
+	}
+	if c.Server.Port == "" {
+		add("PORT is required")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		add("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.Server.ProxyHeader != "" && len(c.Server.TrustedProxies) == 0 {
+		add("TRUSTED_PROXIES is required with PROXY_HEADER, or any client can set its own IP")
+	}
+	if c.Security.ClientCertHeader != "" && len(c.Server.TrustedProxies) == 0 {
+		add("TRUSTED_PROXIES is required with CLIENT_CERT_HEADER, or any client can claim a certificate")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				add("TRUSTED_PROXIES entry %q is not an IP address or CIDR block", proxy)
+			}
+		}
+	}
+	if c.Database.Host == "" || c.Database.Name == "" || c.Database.User == "" {
+		add("DB_HOST, DB_NAME and DB_USER are required")
+	}
+	if c.Database.Port <= 0 {
+		add("DB_PORT must be positive")
+	}
+	if c.Database.ReplicaDSN != "" && c.Database.ReplicaMaxLag <= 0 {
+		add("DB_REPLICA_MAX_LAG must be positive with a read replica")
+	}
+	switch c.Storage.Driver {
+	case "local":
+	case "s3":
+		if c.Storage.Bucket == "" {
+			add("S3_BUCKET is required with the s3 storage driver")
+		}
+	default:
+		add("STORAGE_DRIVER must be local or s3, not %q", c.Storage.Driver)
+	}
+	if c.Metadata.SchemaMode != "flag" && c.Metadata.SchemaMode != "strict" {
+		add("METADATA_SCHEMA_MODE must be flag or strict, not %q", c.Metadata.SchemaMode)
+	}
+	if c.Redis.TTL <= 0 {
+		add("REDIS_TTL must be positive")
+	}
+	if c.Redis.TTLJitter < 0 || c.Redis.TTLJitter > 1 {
+		add("REDIS_TTL_JITTER must be between 0 and 1")
+	}
+	if c.Redis.Timeout <= 0 {
+		add("REDIS_TIMEOUT must be positive")
+	}
+	if c.RateLimit.Window <= 0 {
+		add("RATE_LIMIT_WINDOW must be positive")
+	}
+	if c.RateLimit.Reads <= 0 || c.RateLimit.Writes <= 0 || c.RateLimit.Payments <= 0 {
+		add("RATE_LIMIT_READS, RATE_LIMIT_WRITES and RATE_LIMIT_PAYMENTS must be positive")
+	}
+	if c.Auth.JWTKeyDir != "" && c.Auth.JWTActiveKey == "" {
+		add("JWT_ACTIVE_KEY is required with JWT_KEY_DIR")
+	}
+	if c.Passwords.Iterations <= 0 || c.Passwords.Parallelism <= 0 || c.Passwords.Parallelism > 255 {
+		add("PASSWORD_ARGON2_ITERATIONS must be positive and PASSWORD_ARGON2_PARALLELISM between 1 and 255")
+	}
+	if c.Passwords.Memory < 8*c.Passwords.Parallelism {
+		add("PASSWORD_ARGON2_MEMORY must be at least 8 KiB per PASSWORD_ARGON2_PARALLELISM")
+	}
+	if c.Social.GoogleClientID != "" && c.Social.GoogleClientSecret == "" {
+		add("GOOGLE_CLIENT_SECRET is required with GOOGLE_CLIENT_ID")
+	}
+	if c.Social.AppleClientID != "" && (c.Social.AppleTeamID == "" || c.Social.AppleKeyID == "" || c.Social.ApplePrivateKeyFile == "") {
+		add("APPLE_TEAM_ID, APPLE_KEY_ID and APPLE_PRIVATE_KEY_FILE are required with APPLE_CLIENT_ID")
+	}
+	switch c.Security.Profile {
+	case SecurityProfileStrict, SecurityProfileRelaxed:
+	default:
+		add("SECURITY_PROFILE must be strict or relaxed, not %q", c.Security.Profile)
+	}
+	if slices.Contains(c.Security.AllowedOrigins, "*") {
+		add("CORS_ALLOWED_ORIGINS can't be *: the API is called with credentials")
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		add("HSTS_MAX_AGE must not be negative")
+	}
+	if c.Transfers.ProcessingTimeout <= 0 {
+		add("PROCESSING_TIMEOUT must be positive")
+	}
+	if c.Transfers.MaxOverdraft < 0 {
+		add("MAX_OVERDRAFT must not be negative")
+	}
+	if c.Transfers.PendingTTL <= 0 {
+		add("PENDING_TRANSACTION_TTL must be positive")
+	}
+	if c.Transfers.ConfirmationDigestThreshold < 2 {
+		add("PAYMENT_CONFIRMATION_DIGEST_THRESHOLD must be at least 2")
+	}
+	if c.Transfers.DeviceConfirmationThreshold < 0 {
+		add("TRANSFER_DEVICE_CONFIRMATION_THRESHOLD must not be negative")
+	}
+	if c.Transfers.DeviceConfirmationWindow <= 0 {
+		add("TRANSFER_DEVICE_CONFIRMATION_WINDOW must be positive")
+	}
+	if c.Transfers.PINThreshold < 0 {
+		add("TRANSFER_PIN_THRESHOLD must not be negative")
+	}
+	if c.Transfers.ScanConfirmationWindow <= 0 {
+		add("SCAN_CONFIRMATION_WINDOW must be positive")
+	}
+	if c.Reserve.Percent < 0 || c.Reserve.Percent >= 1 {
+		add("RESERVE_PERCENT must be at least 0 and below 1")
+	}
+	if c.Reserve.HoldDays <= 0 {
+		add("RESERVE_HOLD_DAYS must be positive")
+	}
+	switch c.Accounting.Delivery {
+	case "":
+	case "s3":
+		if c.Accounting.S3.Endpoint == "" || c.Accounting.S3.Bucket == "" {
+			add("ACCOUNTING_S3_ENDPOINT and ACCOUNTING_S3_BUCKET are required with s3 accounting delivery")
+		}
+	case "sftp":
+		if c.Accounting.SFTP.Host == "" || c.Accounting.SFTP.User == "" {
+			add("ACCOUNTING_SFTP_HOST and ACCOUNTING_SFTP_USER are required with sftp accounting delivery")
+		}
+		if c.Accounting.SFTP.HostKey == "" {
+			add("ACCOUNTING_SFTP_HOST_KEY is required with sftp accounting delivery, or the server can't be verified")
+		}
+	default:
+		add("ACCOUNTING_DELIVERY must be empty, s3 or sftp, not %q", c.Accounting.Delivery)
+	}
+	if c.Compliance.LargeTransactionThreshold <= 0 {
+		add("COMPLIANCE_LARGE_TRANSACTION_THRESHOLD must be positive")
+	}
+	if c.Compliance.StructuringWindow <= 0 || c.Compliance.StructuringMinCount < 2 || c.Compliance.StructuringMinTotal <= 0 {
+		add("COMPLIANCE_STRUCTURING_WINDOW and COMPLIANCE_STRUCTURING_MIN_TOTAL must be positive and COMPLIANCE_STRUCTURING_MIN_COUNT at least 2")
+	}
+	if c.Compliance.StructuringFloor < 0 || c.Compliance.StructuringFloor >= c.Compliance.LargeTransactionThreshold {
+		add("COMPLIANCE_STRUCTURING_FLOOR must be between 0 and the large transaction threshold")
+	}
+	if c.Retention.ArchiveAfterDays < 0 {
+		add("TRANSACTION_ARCHIVE_AFTER_DAYS must not be negative")
+	}
+	switch c.FX.Provider {
+	case "sandbox", "ecb":
+	case "fixer":
+		if c.FX.APIKey == "" {
+			add("FX_API_KEY is required with the fixer rate provider")
+		}
+	default:
+		add("FX_PROVIDER must be sandbox, ecb or fixer, not %q", c.FX.Provider)
+	}
+	if c.FX.CacheTTL <= 0 {
+		add("FX_CACHE_TTL must be positive")
+	}
+
+	secrets := c.secretProblems()
+	if c.IsProduction() {
+		problems = append(problems, secrets...)
+	} else {
+		for _, problem := range secrets {
+			log.Printf("⚠️ Config: %s (required in production)", problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// secretProblems lists the secrets that are missing or unsafe
+func (c *Config) secretProblems() []string {
+	var problems []string
+	check := func(name, value string) {
+		switch {
+		case value == "":
+			problems = append(problems, name+" is not set")
+		case developmentSecrets[value]:
+			problems = append(problems, name+" is a well-known default")
+		case len(value) < minSecretLength:
+			problems = append(problems, fmt.Sprintf("%s must be at least %d characters", name, minSecretLength))
+		}
+	}
+
+	// Access tokens signed with a key pair don't use JWT_SECRET
+	if c.Auth.JWTKeyDir == "" {
+		check("JWT_SECRET", c.Auth.JWTSecret)
+		if c.Auth.JWTSecret != "" && c.Auth.JWTSecret == c.Auth.RefreshSecret {
+			problems = append(problems, "JWT_SECRET and REFRESH_SECRET must differ")
+		}
+	}
+	check("REFRESH_SECRET", c.Auth.RefreshSecret)
+	check("PAYMENT_CODE_SECRET", c.Auth.PaymentCodeSecret)
+	check("PAYMENT_INTENT_SECRET", c.Auth.PaymentIntentSecret)
+	check("PASSWORD_PEPPER", c.Passwords.Pepper)
+	check("VAULT_KEY", c.Vault.Key)
+	// The provider callbacks are public endpoints guarded only by these
+	if c.Funding.BankProvider != "sandbox" && c.Funding.WebhookSecret == "" {
+		problems = append(problems, "BANK_WEBHOOK_SECRET is not set")
+	}
+	if c.Issuing.CardIssuer != "sandbox" && c.Issuing.WebhookSecret == "" {
+		problems = append(problems, "CARD_ISSUER_WEBHOOK_SECRET is not set")
+	}
+	return problems
+}
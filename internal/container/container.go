@@ -0,0 +1,109 @@
+// Package container builds the application's dependency graph. Every
+// repository, service and handler is created here from the configuration
+// and the database and cache connections and handed down explicitly, so no
+// package reaches for shared state of its own.
+package container
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/config"
+	"orus/internal/jobs"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
+
+	"gorm.io/gorm"
+)
+
+// Container holds the wired application
+type Container struct {
+	Config *config.Config
+	DB     *gorm.DB
+	Cache  *cache.CacheService
+	// Breakers guard Redis and the external providers
+	Breakers *resilience.Registry
+
+	Repositories *Repositories
+	Services     *Services
+	Handlers     *Handlers
+
+	scheduler   *jobs.Scheduler
+	invalidator *cache.Invalidator
+}
+
+// New connects to PostgreSQL and Redis and builds the container on them
+func New(cfg *config.Config) (*Container, error) {
+	db, err := repositories.ConnectPostgres(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	if err := repositories.ConnectReplica(cfg.Database, db); err != nil {
+		return nil, err
+	}
+	return Build(cfg, db, repositories.ConnectRedis(cfg))
+}
+
+// Build wires the application on existing connections. Nothing runs in the
+// background until Start.
+func Build(cfg *config.Config, db *gorm.DB, cacheSvc *cache.CacheService) (*Container, error) {
+	c := &Container{
+		Config:      cfg,
+		DB:          db,
+		Cache:       cacheSvc,
+		Breakers:    resilience.NewRegistry(),
+		invalidator: cache.NewInvalidator(cacheSvc, 4),
+	}
+	c.Breakers.Register(cacheSvc.Breaker())
+	c.Repositories = newRepositories(db, cacheSvc)
+
+	services, err := newServices(cfg, db, cacheSvc, c.Repositories, c.invalidator, c.Breakers)
+	if err != nil {
+		return nil, err
+	}
+	c.Services = services
+
+	handlers, err := newHandlers(cfg, db, cacheSvc, c.Repositories, c.Services, c.invalidator, c.Breakers)
+	if err != nil {
+		return nil, err
+	}
+	c.Handlers = handlers
+
+	c.scheduler = newScheduler(c.Repositories, c.Services)
+	return c, nil
+}
+
+// Start runs the background cache invalidation and the scheduled jobs
+func (c *Container) Start(ctx context.Context) {
+	c.invalidator.Start(ctx)
+	c.scheduler.Start(ctx)
+}
+
+// Shutdown lets running jobs finish, cancelling them if ctx ends first,
+// then drains the queued cache invalidations. It must run before Close.
+func (c *Container) Shutdown(ctx context.Context) error {
+	err := c.scheduler.Shutdown(ctx)
+	c.invalidator.Stop()
+	return err
+}
+
+// Close closes the database and Redis connections
+func (c *Container) Close() error {
+	var errs []error
+	if sqlDB, err := c.DB.DB(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get database instance: %w", err))
+	} else if err := sqlDB.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database connection: %w", err))
+	}
+	if err := c.Cache.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close Redis connection: %w", err))
+	}
+	for _, err := range errs {
+		log.Printf("⚠️ %v", err)
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
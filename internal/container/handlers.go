@@ -0,0 +1,164 @@
+package container
+
+import (
+	"fmt"
+	"orus/internal/config"
+	"orus/internal/handlers"
+	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
+
+	"gorm.io/gorm"
+)
+
+// Handlers serve the HTTP routes
+type Handlers struct {
+	Health             *handlers.HealthHandler
+	Admin              *handlers.AdminHandler
+	Role               *handlers.RoleHandler
+	RateLimit          *handlers.RateLimitHandler
+	Auth               *handlers.AuthHandler
+	SocialLogin        *handlers.SocialLoginHandler
+	OAuth              *handlers.OAuthHandler
+	User               *handlers.UserHandler
+	Wallet             *handlers.WalletHandler
+	CreditCard         *handlers.CreditCardHandler
+	QR                 *handlers.QRHandler
+	KYC                *handlers.KYCHandler
+	Payment            *handlers.PaymentHandler
+	PaymentCode        *handlers.PaymentCodeHandler
+	Intent             *handlers.PaymentIntentHandler
+	Transaction        *handlers.TransactionHandler
+	Transfer           *handlers.TransferHandler
+	SharedWallet       *handlers.SharedWalletHandler
+	Enterprise         *handlers.EnterpriseHandler
+	Pot                *handlers.PotHandler
+	Overdraft          *handlers.OverdraftHandler
+	Fraud              *handlers.FraudHandler
+	MerchantRisk       *handlers.MerchantRiskHandler
+	Reserve            *handlers.ReserveHandler
+	SpendingControl    *handlers.SpendingControlHandler
+	DeviceConfirmation *handlers.DeviceConfirmationHandler
+	ScanConfirmation   *handlers.ScanConfirmationHandler
+	RefundRequest      *handlers.RefundRequestHandler
+	PaymentPIN         *handlers.PaymentPINHandler
+	Investigation      *handlers.InvestigationHandler
+	Bulk               *handlers.BulkHandler
+	FeatureFlag        *handlers.FeatureFlagHandler
+	Maintenance        *handlers.MaintenanceHandler
+	Accounting         *handlers.AccountingHandler
+	Compliance         *handlers.ComplianceHandler
+	TaxSummary         *handlers.TaxSummaryHandler
+	Saga               *handlers.SagaHandler
+	PendingTransaction *handlers.PendingTransactionHandler
+	Security           *handlers.MerchantSecurityHandler
+	Promotion          *handlers.PromotionHandler
+	Loyalty            *handlers.LoyaltyHandler
+	Contact            *handlers.ContactHandler
+	Receipt            *handlers.ReceiptHandler
+	AutoTopUp          *handlers.AutoTopUpHandler
+	Category           *handlers.CategoryHandler
+	FX                 *handlers.FXHandler
+	Treasury           *handlers.TreasuryHandler
+	Dashboard          *handlers.DashboardHandler
+	Dispute            *handlers.DisputeHandler
+	Escrow             *handlers.EscrowHandler
+	Funding            *handlers.FundingHandler
+	VirtualCard        *handlers.VirtualCardHandler
+	Checkout           *handlers.CheckoutHandler
+	Sandbox            *handlers.SandboxHandler
+	Webhook            *handlers.WebhookHandler
+	Subscription       *handlers.SubscriptionHandler
+	Export             *handlers.ExportHandler
+	Invoice            *handlers.InvoiceHandler
+	Split              *handlers.SplitHandler
+	Handle             *handlers.HandleHandler
+	Confirmation       *handlers.ConfirmationHandler
+	Merchant           *handlers.MerchantHandler
+	Staff              *handlers.StaffHandler
+	Terminal           *handlers.TerminalHandler
+	GraphQL            *handlers.GraphQLHandler
+}
+
+func newHandlers(
+	cfg *config.Config,
+	db *gorm.DB,
+	cacheSvc *cache.CacheService,
+	r *Repositories,
+	s *Services,
+	invalidator *cache.Invalidator,
+	breakers *resilience.Registry,
+) (*Handlers, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	authHandler := handlers.NewAuthHandler(s.Auth, s.JWTKeys, cfg.Auth.RefreshSecret, cfg.IsProduction(), cfg.Server.CountryHeader)
+	return &Handlers{
+		Health:             handlers.NewHealthHandler(sqlDB, cacheSvc, breakers),
+		Admin:              handlers.NewAdminHandler(s.UserAdmin, r.Users, r.Wallets, r.CreditCards, r.Transactions, invalidator),
+		Role:               handlers.NewRoleHandler(s.RBAC),
+		RateLimit:          handlers.NewRateLimitHandler(s.RateLimits),
+		Auth:               authHandler,
+		SocialLogin:        handlers.NewSocialLoginHandler(s.SocialLogin, authHandler),
+		OAuth:              handlers.NewOAuthHandler(s.OAuth, s.Wallets, s.Users),
+		User:               handlers.NewUserHandler(s.Users, s.Wallets, s.QR),
+		Wallet:             handlers.NewWalletHandler(s.Wallets),
+		CreditCard:         handlers.NewCreditCardHandler(s.CreditCards),
+		QR:                 handlers.NewQRHandler(s.QR),
+		KYC:                handlers.NewKYCHandler(s.KYC),
+		Payment:            handlers.NewPaymentHandler(s.QR, s.Payments, s.Handles, s.Loyalty),
+		PaymentCode:        handlers.NewPaymentCodeHandler(s.PaymentCodes),
+		Intent:             handlers.NewPaymentIntentHandler(s.Intents),
+		Transaction:        handlers.NewTransactionHandler(s.Transactions),
+		Transfer:           handlers.NewTransferHandler(s.Transfers),
+		SharedWallet:       handlers.NewSharedWalletHandler(s.SharedWallet),
+		Enterprise:         handlers.NewEnterpriseHandler(s.Enterprise),
+		Pot:                handlers.NewPotHandler(s.Pots),
+		Overdraft:          handlers.NewOverdraftHandler(s.Overdrafts),
+		Fraud:              handlers.NewFraudHandler(s.Fraud),
+		MerchantRisk:       handlers.NewMerchantRiskHandler(s.MerchantRisk),
+		Reserve:            handlers.NewReserveHandler(s.Reserves),
+		SpendingControl:    handlers.NewSpendingControlHandler(s.SpendingControls),
+		DeviceConfirmation: handlers.NewDeviceConfirmationHandler(s.DeviceConfirmations),
+		ScanConfirmation:   handlers.NewScanConfirmationHandler(s.ScanConfirmations, s.Merchants),
+		RefundRequest:      handlers.NewRefundRequestHandler(s.RefundRequests),
+		PaymentPIN:         handlers.NewPaymentPINHandler(s.PaymentPINs),
+		Investigation:      handlers.NewInvestigationHandler(s.Investigation),
+		Bulk:               handlers.NewBulkHandler(s.Bulk),
+		FeatureFlag:        handlers.NewFeatureFlagHandler(s.Features),
+		Maintenance:        handlers.NewMaintenanceHandler(s.Maintenance),
+		Accounting:         handlers.NewAccountingHandler(s.Accounting),
+		Compliance:         handlers.NewComplianceHandler(s.Compliance),
+		TaxSummary:         handlers.NewTaxSummaryHandler(s.TaxSummaries),
+		Saga:               handlers.NewSagaHandler(s.Sagas),
+		PendingTransaction: handlers.NewPendingTransactionHandler(s.PendingTransactions),
+		Security:           handlers.NewMerchantSecurityHandler(s.APIAccess),
+		Promotion:          handlers.NewPromotionHandler(s.Promotions),
+		Loyalty:            handlers.NewLoyaltyHandler(s.Loyalty),
+		Contact:            handlers.NewContactHandler(s.Contacts),
+		Receipt:            handlers.NewReceiptHandler(s.Receipts),
+		AutoTopUp:          handlers.NewAutoTopUpHandler(s.AutoTopUps),
+		Category:           handlers.NewCategoryHandler(s.Categories),
+		FX:                 handlers.NewFXHandler(s.FX),
+		Treasury:           handlers.NewTreasuryHandler(s.Treasury),
+		Dashboard:          handlers.NewDashboardHandler(s.Dashboard),
+		Dispute:            handlers.NewDisputeHandler(s.Disputes),
+		Escrow:             handlers.NewEscrowHandler(s.Escrows),
+		Funding:            handlers.NewFundingHandler(s.Funding),
+		VirtualCard:        handlers.NewVirtualCardHandler(s.Issuing),
+		Checkout:           handlers.NewCheckoutHandler(s.Checkout),
+		Sandbox:            handlers.NewSandboxHandler(s.Sandbox),
+		Webhook:            handlers.NewWebhookHandler(s.Webhooks),
+		Subscription:       handlers.NewSubscriptionHandler(s.Subscription),
+		Export:             handlers.NewExportHandler(s.Exports),
+		Invoice:            handlers.NewInvoiceHandler(s.Invoices),
+		Split:              handlers.NewSplitHandler(s.Splits),
+		Handle:             handlers.NewHandleHandler(s.Handles),
+		Confirmation:       handlers.NewConfirmationHandler(s.Confirmations),
+		Merchant:           handlers.NewMerchantHandler(s.Merchants, s.QR, r.Transactions),
+		Staff:              handlers.NewStaffHandler(s.Staff, s.Merchants),
+		Terminal:           handlers.NewTerminalHandler(s.Terminals, s.Merchants),
+		GraphQL:            handlers.NewGraphQLHandler(r.Users, r.Wallets, s.Wallets, r.Merchants, r.Transactions, r.QRCodes),
+	}, nil
+}
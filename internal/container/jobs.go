@@ -0,0 +1,65 @@
+package container
+
+import (
+	"orus/internal/jobs"
+	"orus/internal/services/accounting"
+	"orus/internal/services/autotopup"
+	"orus/internal/services/bulk"
+	"orus/internal/services/category"
+	"orus/internal/services/compliance"
+	"orus/internal/services/confirmation"
+	"orus/internal/services/dashboard"
+	"orus/internal/services/deviceconfirm"
+	"orus/internal/services/dispute"
+	"orus/internal/services/escrow"
+	"orus/internal/services/export"
+	"orus/internal/services/invoice"
+	"orus/internal/services/merchantrisk"
+	"orus/internal/services/overdraft"
+	"orus/internal/services/pendingtx"
+	"orus/internal/services/pot"
+	qr "orus/internal/services/qr_code"
+	"orus/internal/services/reserve"
+	"orus/internal/services/retention"
+	"orus/internal/services/saga"
+	"orus/internal/services/scanconfirm"
+	"orus/internal/services/split"
+	"orus/internal/services/subscription"
+	"orus/internal/services/taxsummary"
+	"orus/internal/services/wallet"
+	"orus/internal/services/webhook"
+	"time"
+)
+
+// newScheduler registers the background jobs; they run once Start is called
+func newScheduler(r *Repositories, s *Services) *jobs.Scheduler {
+	scheduler := jobs.NewScheduler(10 * time.Minute)
+	scheduler.Register(export.NewJob(s.Exports), 5*time.Minute)
+	scheduler.Register(accounting.NewJob(s.Accounting), time.Hour)
+	scheduler.Register(compliance.NewJob(s.Compliance), 15*time.Minute)
+	scheduler.Register(taxsummary.NewJob(s.TaxSummaries), time.Minute)
+	scheduler.Register(saga.NewJob(s.Sagas), time.Minute)
+	scheduler.Register(pendingtx.NewJob(s.PendingTransactions), 15*time.Minute)
+	scheduler.Register(invoice.NewJob(s.Invoices), time.Hour)
+	scheduler.Register(split.NewJob(s.Splits), time.Hour)
+	scheduler.Register(pot.NewJob(s.Pots), 15*time.Minute)
+	scheduler.Register(overdraft.NewJob(s.Overdrafts), time.Hour)
+	scheduler.Register(wallet.NewJob(s.Wallets), 5*time.Minute)
+	scheduler.Register(autotopup.NewJob(s.AutoTopUps), time.Minute)
+	scheduler.Register(escrow.NewJob(s.Escrows), time.Hour)
+	scheduler.Register(qr.NewJob(s.QR), 15*time.Minute)
+	scheduler.Register(dispute.NewJob(s.Disputes), time.Hour)
+	scheduler.Register(merchantrisk.NewJob(s.MerchantRisk), time.Hour)
+	scheduler.Register(bulk.NewJob(s.Bulk), time.Minute)
+	scheduler.Register(webhook.NewJob(s.Webhooks), time.Minute)
+	scheduler.Register(subscription.NewJob(s.Subscription), 15*time.Minute)
+	scheduler.Register(dashboard.NewJob(s.Projector), time.Minute)
+	scheduler.Register(confirmation.NewJob(s.Confirmations), time.Minute)
+	scheduler.Register(reserve.NewJob(s.Reserves), time.Minute)
+	scheduler.Register(deviceconfirm.NewJob(s.DeviceConfirmations), time.Minute)
+	scheduler.Register(scanconfirm.NewJob(s.ScanConfirmations), time.Minute)
+	scheduler.Register(category.NewJob(s.Categories), time.Hour)
+	scheduler.Register(retention.NewJob(s.Retention), time.Hour)
+	scheduler.Register(retention.NewPartitionJob(r.Partitions), 24*time.Hour)
+	return scheduler
+}
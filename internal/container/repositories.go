@@ -0,0 +1,151 @@
+package container
+
+import (
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+
+	"gorm.io/gorm"
+)
+
+// Repositories are the data access objects, one per aggregate
+type Repositories struct {
+	Users                 repositories.UserRepository
+	UserActivity          repositories.UserActivityRepository
+	LoginEvents           repositories.LoginEventRepository
+	Sessions              repositories.SessionRepository
+	UserIdentities        repositories.UserIdentityRepository
+	OAuth                 repositories.OAuthRepository
+	Roles                 repositories.RoleRepository
+	Wallets               repositories.WalletRepository
+	WalletLocks           repositories.WalletLockRepository
+	CreditCards           repositories.CreditCardRepository
+	CardVault             repositories.CardVaultRepository
+	CardTopUps            repositories.CardTopUpRepository
+	QRCodes               repositories.QRCodeRepository
+	Transactions          repositories.TransactionRepository
+	TransactionCategories repositories.TransactionCategoryRepository
+	TransactionArchive    repositories.TransactionArchiveRepository
+	Partitions            repositories.PartitionManager
+	Merchants             repositories.MerchantRepository
+	KYC                   repositories.KYCRepository
+	SharedWallets         repositories.SharedWalletRepository
+	Enterprises           repositories.EnterpriseRepository
+	Pots                  repositories.PotRepository
+	AutoTopUps            repositories.AutoTopUpRepository
+	FraudRules            repositories.FraudRuleRepository
+	MerchantRisk          repositories.MerchantRiskRepository
+	Investigations        repositories.InvestigationRepository
+	BulkOperations        repositories.BulkOperationRepository
+	FeatureFlags          repositories.FeatureFlagRepository
+	Maintenance           repositories.MaintenanceRepository
+	Accounting            repositories.AccountingRepository
+	Compliance            repositories.ComplianceRepository
+	TaxSummaries          repositories.TaxSummaryRepository
+	Sagas                 repositories.SagaRepository
+	PendingTransactions   repositories.PendingTransactionRepository
+	PaymentConfirmations  repositories.PaymentConfirmationRepository
+	Reserves              repositories.ReserveRepository
+	SpendingControls      repositories.SpendingControlRepository
+	TrustedDevices        repositories.TrustedDeviceRepository
+	ScanConfirmations     repositories.ScanConfirmationRepository
+	RefundRequests        repositories.RefundRequestRepository
+	PaymentPINs           repositories.PaymentPINRepository
+	MerchantAPIAccess     repositories.MerchantAPIAccessRepository
+	Promotions            repositories.PromotionRepository
+	Loyalty               repositories.LoyaltyRepository
+	Contacts              repositories.ContactRepository
+	Receipts              repositories.ReceiptRepository
+	Treasury              repositories.TreasuryRepository
+	Projections           repositories.DashboardProjectionRepository
+	Analytics             repositories.AnalyticsRepository
+	Disputes              repositories.DisputeRepository
+	Chargebacks           repositories.ChargebackRepository
+	Escrows               repositories.EscrowRepository
+	BankAccounts          repositories.BankAccountRepository
+	Deposits              repositories.DepositRepository
+	VirtualCards          repositories.VirtualCardRepository
+	WebhookDeliveries     repositories.WebhookDeliveryRepository
+	PaymentLinks          repositories.PaymentLinkRepository
+	PaymentCodes          repositories.PaymentCodeRepository
+	PaymentIntents        repositories.PaymentIntentRepository
+	Subscriptions         repositories.SubscriptionRepository
+	ExportSchedules       repositories.ExportScheduleRepository
+	Invoices              repositories.InvoiceRepository
+	Splits                repositories.SplitRepository
+	MerchantStaff         repositories.MerchantStaffRepository
+	Terminals             repositories.TerminalRepository
+	Sandbox               repositories.SandboxRepository
+	RateLimits            repositories.RateLimitOverrideRepository
+}
+
+func newRepositories(db *gorm.DB, cacheSvc *cache.CacheService) *Repositories {
+	return &Repositories{
+		Users:                 repositories.NewUserRepository(db, cacheSvc),
+		UserActivity:          repositories.NewUserActivityRepository(db),
+		LoginEvents:           repositories.NewLoginEventRepository(db),
+		Sessions:              repositories.NewSessionRepository(db),
+		UserIdentities:        repositories.NewUserIdentityRepository(db),
+		OAuth:                 repositories.NewOAuthRepository(db),
+		Roles:                 repositories.NewRoleRepository(db),
+		Wallets:               repositories.NewWalletRepository(db),
+		WalletLocks:           repositories.NewWalletLockRepository(db),
+		CreditCards:           repositories.NewCreditCardRepository(db),
+		CardVault:             repositories.NewCardVaultRepository(db),
+		CardTopUps:            repositories.NewCardTopUpRepository(db),
+		QRCodes:               repositories.NewQRCodeRepository(db),
+		Transactions:          repositories.NewTransactionRepository(db),
+		TransactionCategories: repositories.NewTransactionCategoryRepository(db),
+		TransactionArchive:    repositories.NewTransactionArchiveRepository(db),
+		Partitions:            repositories.NewPartitionManager(db),
+		Merchants:             repositories.NewMerchantRepository(db),
+		KYC:                   repositories.NewKYCRepository(db),
+		SharedWallets:         repositories.NewSharedWalletRepository(db),
+		Enterprises:           repositories.NewEnterpriseRepository(db),
+		Pots:                  repositories.NewPotRepository(db),
+		AutoTopUps:            repositories.NewAutoTopUpRepository(db),
+		FraudRules:            repositories.NewFraudRuleRepository(db),
+		MerchantRisk:          repositories.NewMerchantRiskRepository(db),
+		Investigations:        repositories.NewInvestigationRepository(db),
+		BulkOperations:        repositories.NewBulkOperationRepository(db),
+		FeatureFlags:          repositories.NewFeatureFlagRepository(db),
+		Maintenance:           repositories.NewMaintenanceRepository(db),
+		Accounting:            repositories.NewAccountingRepository(db),
+		Compliance:            repositories.NewComplianceRepository(db),
+		TaxSummaries:          repositories.NewTaxSummaryRepository(db),
+		Sagas:                 repositories.NewSagaRepository(db),
+		PendingTransactions:   repositories.NewPendingTransactionRepository(db),
+		PaymentConfirmations:  repositories.NewPaymentConfirmationRepository(db),
+		Reserves:              repositories.NewReserveRepository(db),
+		SpendingControls:      repositories.NewSpendingControlRepository(db),
+		TrustedDevices:        repositories.NewTrustedDeviceRepository(db),
+		ScanConfirmations:     repositories.NewScanConfirmationRepository(db),
+		RefundRequests:        repositories.NewRefundRequestRepository(db),
+		PaymentPINs:           repositories.NewPaymentPINRepository(db),
+		MerchantAPIAccess:     repositories.NewMerchantAPIAccessRepository(db),
+		Promotions:            repositories.NewPromotionRepository(db),
+		Loyalty:               repositories.NewLoyaltyRepository(db),
+		Contacts:              repositories.NewContactRepository(db),
+		Receipts:              repositories.NewReceiptRepository(db),
+		Treasury:              repositories.NewTreasuryRepository(db),
+		Projections:           repositories.NewDashboardProjectionRepository(db),
+		Analytics:             repositories.NewAnalyticsRepository(db),
+		Disputes:              repositories.NewDisputeRepository(db),
+		Chargebacks:           repositories.NewChargebackRepository(db),
+		Escrows:               repositories.NewEscrowRepository(db),
+		BankAccounts:          repositories.NewBankAccountRepository(db),
+		Deposits:              repositories.NewDepositRepository(db),
+		VirtualCards:          repositories.NewVirtualCardRepository(db),
+		WebhookDeliveries:     repositories.NewWebhookDeliveryRepository(db),
+		PaymentLinks:          repositories.NewPaymentLinkRepository(db),
+		PaymentCodes:          repositories.NewPaymentCodeRepository(db),
+		PaymentIntents:        repositories.NewPaymentIntentRepository(db),
+		Subscriptions:         repositories.NewSubscriptionRepository(db),
+		ExportSchedules:       repositories.NewExportScheduleRepository(db),
+		Invoices:              repositories.NewInvoiceRepository(db),
+		Splits:                repositories.NewSplitRepository(db),
+		MerchantStaff:         repositories.NewMerchantStaffRepository(db),
+		Terminals:             repositories.NewTerminalRepository(db),
+		Sandbox:               repositories.NewSandboxRepository(db),
+		RateLimits:            repositories.NewRateLimitOverrideRepository(db),
+	}
+}
@@ -0,0 +1,640 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/config"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
+	"orus/internal/services/accounting"
+	"orus/internal/services/apiaccess"
+	"orus/internal/services/auth"
+	"orus/internal/services/autotopup"
+	"orus/internal/services/bulk"
+	"orus/internal/services/category"
+	"orus/internal/services/checkout"
+	"orus/internal/services/compliance"
+	"orus/internal/services/confirmation"
+	"orus/internal/services/contact"
+	creditcard "orus/internal/services/credit-card"
+	"orus/internal/services/dashboard"
+	"orus/internal/services/deviceconfirm"
+	"orus/internal/services/dispute"
+	"orus/internal/services/enterprise"
+	"orus/internal/services/escrow"
+	"orus/internal/services/export"
+	"orus/internal/services/featureflag"
+	"orus/internal/services/fraud"
+	"orus/internal/services/funding"
+	"orus/internal/services/fxrates"
+	"orus/internal/services/handle"
+	"orus/internal/services/investigation"
+	"orus/internal/services/invoice"
+	"orus/internal/services/issuing"
+	"orus/internal/services/kyc"
+	"orus/internal/services/loyalty"
+	"orus/internal/services/maintenance"
+	"orus/internal/services/merchant"
+	"orus/internal/services/merchantrisk"
+	"orus/internal/services/notification"
+	"orus/internal/services/oauth"
+	"orus/internal/services/overdraft"
+	"orus/internal/services/password"
+	"orus/internal/services/payment"
+	"orus/internal/services/paymentcode"
+	"orus/internal/services/paymentintent"
+	"orus/internal/services/paymentpin"
+	"orus/internal/services/pendingtx"
+	"orus/internal/services/pot"
+	"orus/internal/services/promotion"
+	qr "orus/internal/services/qr_code"
+	"orus/internal/services/ratelimit"
+	"orus/internal/services/rbac"
+	"orus/internal/services/receipt"
+	"orus/internal/services/refundrequest"
+	"orus/internal/services/reserve"
+	"orus/internal/services/retention"
+	"orus/internal/services/saga"
+	"orus/internal/services/sandbox"
+	"orus/internal/services/scanconfirm"
+	"orus/internal/services/sociallogin"
+	"orus/internal/services/spendcontrol"
+	"orus/internal/services/split"
+	"orus/internal/services/staff"
+	"orus/internal/services/subscription"
+	"orus/internal/services/taxsummary"
+	"orus/internal/services/terminal"
+	"orus/internal/services/transaction"
+	"orus/internal/services/transfer"
+	"orus/internal/services/treasury"
+	"orus/internal/services/user"
+	"orus/internal/services/useradmin"
+	"orus/internal/services/vault"
+	"orus/internal/services/wallet"
+	"orus/internal/services/webhook"
+	"orus/internal/utils/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Services are the business operations the handlers and jobs call
+type Services struct {
+	RBAC                rbac.Service
+	Features            featureflag.Service
+	Maintenance         maintenance.Service
+	Auth                auth.Service
+	JWTKeys             *auth.KeySet
+	Passwords           *password.Hasher
+	Vault               vault.Service
+	CreditCards         creditcard.Service
+	Users               user.Service
+	UserAdmin           useradmin.Service
+	Wallets             wallet.Service
+	FX                  fxrates.Service
+	SharedWallet        wallet.SharedService
+	Enterprise          enterprise.Service
+	Pots                pot.Service
+	AutoTopUps          autotopup.Service
+	Overdrafts          overdraft.Service
+	Fraud               fraud.Service
+	MerchantRisk        merchantrisk.Service
+	Reserves            reserve.Service
+	SpendingControls    spendcontrol.Service
+	DeviceConfirmations deviceconfirm.Service
+	ScanConfirmations   scanconfirm.Service
+	RefundRequests      refundrequest.Service
+	PaymentPINs         paymentpin.Service
+	Investigation       investigation.Service
+	Bulk                bulk.Service
+	APIAccess           apiaccess.Service
+	Promotions          promotion.Service
+	Loyalty             loyalty.Service
+	Transactions        transaction.Service
+	Categories          category.Service
+	QR                  qr.Service
+	SocialLogin         sociallogin.Service
+	Contacts            contact.Service
+	Payments            payment.Service
+	PaymentCodes        paymentcode.Service
+	Intents             paymentintent.Service
+	Notification        *notification.Service
+	Receipts            receipt.Service
+	Treasury            treasury.Service
+	Transfers           transfer.Service
+	OAuth               oauth.Service
+	Dashboard           dashboard.Service
+	Projector           dashboard.Projector
+	Disputes            *dispute.Service
+	Escrows             escrow.Service
+	Funding             funding.Service
+	Issuing             issuing.Service
+	Webhooks            webhook.Service
+	Checkout            checkout.Service
+	Sandbox             sandbox.Service
+	Subscription        subscription.Service
+	Exports             export.Service
+	Accounting          accounting.Service
+	Compliance          compliance.Service
+	TaxSummaries        taxsummary.Service
+	Sagas               saga.Service
+	PendingTransactions pendingtx.Service
+	Invoices            invoice.Service
+	Splits              split.Service
+	Retention           retention.Service
+	KYC                 kyc.Service
+	Handles             handle.Service
+	Confirmations       confirmation.Service
+	Staff               staff.Service
+	Terminals           terminal.Service
+	Merchants           *merchant.Service
+	RateLimits          ratelimit.Service
+}
+
+// newServices wires the services in dependency order
+func newServices(cfg *config.Config, db *gorm.DB, cacheSvc *cache.CacheService, r *Repositories, invalidator *cache.Invalidator, breakers *resilience.Registry) (*Services, error) {
+	s := &Services{}
+
+	// Roles and permissions; tokens carry the permissions of the user's role
+	s.RBAC = rbac.NewService(r.Roles, r.Users, cacheSvc, invalidator)
+
+	// Feature flags gating diagnostics and experimental routes
+	features, err := featureflag.NewService(r.FeatureFlags, cacheSvc, featureflag.Config{
+		Env:      cfg.Env,
+		Enabled:  cfg.Features.Enabled,
+		Disabled: cfg.Features.Disabled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize feature flags: %w", err)
+	}
+	s.Features = features
+
+	// Maintenance switch pausing payment-mutating endpoints
+	s.Maintenance = maintenance.NewService(r.Maintenance, cacheSvc)
+	if err := s.RBAC.EnsureDefaults(context.Background()); err != nil {
+		log.Printf("Failed to create built-in roles: %v", err)
+	}
+
+	// Per-user and per-API-key request budgets, overridable by admins
+	s.RateLimits = ratelimit.NewService(r.RateLimits, cacheSvc, ratelimit.Limits{
+		Window:   cfg.RateLimit.Window,
+		Reads:    cfg.RateLimit.Reads,
+		Writes:   cfg.RateLimit.Writes,
+		Payments: cfg.RateLimit.Payments,
+	})
+
+	// Access tokens are signed with the shared secret unless a key
+	// directory is configured for RS256 or EdDSA
+	s.JWTKeys = auth.NewSecretKeySet(cfg.Auth.JWTSecret)
+	if cfg.Auth.JWTKeyDir != "" {
+		keys, err := auth.LoadKeySet(cfg.Auth.JWTKeyDir, cfg.Auth.JWTActiveKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT signing keys: %w", err)
+		}
+		s.JWTKeys = keys
+	}
+	s.Passwords = password.NewHasher(password.Params{
+		Memory:      uint32(cfg.Passwords.Memory),
+		Iterations:  uint32(cfg.Passwords.Iterations),
+		Parallelism: uint8(cfg.Passwords.Parallelism),
+	}, cfg.Passwords.Pepper)
+	s.Notification = notification.NewService()
+	s.Auth = auth.NewService(
+		r.Users,
+		r.UserActivity,
+		r.LoginEvents,
+		r.Sessions,
+		s.RBAC,
+		s.JWTKeys,
+		cfg.Auth.RefreshSecret,
+		s.Passwords,
+		cacheSvc,
+		s.Notification,
+		cfg.Server.PublicBaseURL,
+	)
+	// The vault is the only holder of card tokens; cards linked before it
+	// have their tokens moved in at startup
+	vaultSvc, err := vault.NewService(r.CardVault, cfg.Vault.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize card vault: %w", err)
+	}
+	s.Vault = vaultSvc
+	if imported, err := s.Vault.ImportLegacyCards(context.Background()); err != nil {
+		log.Printf("Failed to move card tokens into the vault: %v", err)
+	} else if imported > 0 {
+		log.Printf("Moved %d card tokens into the vault", imported)
+	}
+
+	// Linked cards are verified with a refundable charge before they can
+	// fund the wallet
+	// Optional payment PINs for large transfers and card removal
+	s.PaymentPINs = paymentpin.NewService(r.PaymentPINs, r.Users, s.Passwords, cfg.Transfers.PINThreshold)
+	s.CreditCards = creditcard.NewService(r.CreditCards, s.Vault, creditcard.NewSandboxProcessor(), s.PaymentPINs)
+	s.Users = user.NewService(r.Users, r.Transactions, r.TransactionArchive, s.Passwords)
+
+	// Admin account management: edits, suspensions, roles and the timeline
+	s.UserAdmin = useradmin.NewService(r.Users, s.RBAC, r.UserActivity, r.Transactions, invalidator)
+	s.Wallets = wallet.NewService(
+		r.Wallets,
+		r.WalletLocks,
+		r.CardTopUps,
+		cacheSvc,
+		s.CreditCards,
+		wallet.WalletConfig{
+			ProcessingTimeout: cfg.Transfers.ProcessingTimeout,
+			ThreeDSThreshold:  cfg.Cards.ThreeDSThreshold,
+		},
+		&wallet.NoopMetricsCollector{},
+		s.Notification,
+	)
+
+	// Exchange rates, cached so quotes hold until the next provider read
+	rateProvider, err := fxrates.NewProvider(cfg.FX.Provider, cfg.FX.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize exchange rate provider: %w", err)
+	}
+	rateProvider = fxrates.WithResilience(
+		rateProvider,
+		breakers.Breaker("fx_provider", resilience.DefaultBreakerConfig),
+		resilience.DefaultPolicy,
+	)
+	s.FX = fxrates.NewService(rateProvider, cacheSvc, cfg.FX.CacheTTL)
+
+	// Family and team wallets
+	s.SharedWallet = wallet.NewSharedService(r.SharedWallets, r.Users, s.Wallets, s.FX, cacheSvc)
+
+	// Savings pots
+	s.Pots = pot.NewService(r.Pots, s.Wallets, cacheSvc)
+
+	// Card top-ups set off when payments take the balance below a threshold
+	s.AutoTopUps = autotopup.NewService(r.AutoTopUps, s.Wallets, s.CreditCards, cacheSvc)
+
+	// Overdraft limits, and recovery of negative balances from pots
+	s.Overdrafts = overdraft.NewService(r.Wallets, r.UserActivity, s.Pots, cacheSvc, cfg.Transfers.MaxOverdraft)
+
+	// Merchant fraud rules, enforced on top of the platform-wide limits
+	s.Fraud = fraud.NewService(r.FraudRules, r.Users, r.Merchants, fraud.PlatformRules{
+		MaxChargeAmount:            cfg.Fraud.MaxChargeAmount,
+		MaxChargesPerCustomerDaily: cfg.Fraud.MaxChargesPerCustomerDaily,
+		BlockedCountries:           cfg.Fraud.BlockedCountries,
+	})
+
+	// Spending controls users, and organization admins for their members,
+	// put on payments to merchant categories and foreign merchants
+	s.SpendingControls = spendcontrol.NewService(r.SpendingControls, r.Enterprises, r.Users, r.Merchants)
+
+	// Rolling reserves withhold part of what merchants receive for a while;
+	// risk monitoring raises them for restricted merchants
+	s.Reserves = reserve.NewService(r.Reserves, r.Projections, r.Merchants, reserve.Config{
+		Percent:  cfg.Reserve.Percent,
+		HoldDays: cfg.Reserve.HoldDays,
+	})
+
+	// Merchant risk monitoring, lowering limits when chargebacks, refunds
+	// or volume spike
+	s.MerchantRisk = merchantrisk.NewService(r.MerchantRisk, r.Merchants, s.Reserves, cacheSvc)
+
+	// Linked view of a transaction for fraud analysts
+	s.Investigation = investigation.NewService(r.Investigations, r.Users, r.Transactions, r.UserActivity, s.MerchantRisk)
+
+	// IP allowlists and mutual TLS guarding the merchant API
+	s.APIAccess = apiaccess.NewService(r.MerchantAPIAccess, r.Merchants)
+
+	// Cashback and bonus campaigns, credited after qualifying payments
+	s.Promotions = promotion.NewService(r.Promotions, r.Merchants, cacheSvc)
+
+	// Merchant loyalty programs, accrued on payments and spent as discounts
+	s.Loyalty = loyalty.NewService(r.Loyalty, r.Merchants)
+
+	// Spending categories, set on every transaction as it is created
+	s.Categories = category.NewService(r.TransactionCategories, r.Transactions, cacheSvc)
+	if err := db.Use(repositories.NewCategorizePlugin(s.Categories)); err != nil {
+		return nil, fmt.Errorf("failed to register transaction categorization: %w", err)
+	}
+
+	s.Transactions = transaction.NewService(
+		r.Transactions,
+		s.Wallets,
+		s.Wallets,
+		cacheSvc,
+		s.Fraud,
+		s.SpendingControls,
+		s.Promotions,
+		s.Loyalty,
+		cfg.Transfers.ProcessingTimeout,
+	)
+	s.QR = qr.NewService(r.QRCodes, r.Users, r.Merchants, r.Terminals, cacheSvc, s.Transactions, s.Wallets, cfg.Transfers.ProcessingTimeout)
+
+	// Sign in with Google and Apple; new users get a wallet and QR codes
+	// like a registration
+	socialSvc, err := sociallogin.NewService(
+		sociallogin.Config{
+			RedirectBase:        cfg.Server.PublicBaseURL,
+			GoogleClientID:      cfg.Social.GoogleClientID,
+			GoogleClientSecret:  cfg.Social.GoogleClientSecret,
+			AppleClientID:       cfg.Social.AppleClientID,
+			AppleTeamID:         cfg.Social.AppleTeamID,
+			AppleKeyID:          cfg.Social.AppleKeyID,
+			ApplePrivateKeyFile: cfg.Social.ApplePrivateKeyFile,
+		},
+		r.UserIdentities,
+		r.Users,
+		s.Passwords,
+		s.Auth,
+		s.Features,
+		s.Wallets,
+		s.QR,
+		cacheSvc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize social login: %w", err)
+	}
+	s.SocialLogin = socialSvc
+
+	// Wallet locks, suspensions and QR code expiry in bulk, run by the job
+	s.Bulk = bulk.NewService(r.BulkOperations, s.Wallets, s.UserAdmin, s.QR)
+
+	// Saved P2P recipients, with an optional cooling-off period before a
+	// new beneficiary's first payment
+	s.Contacts = contact.NewService(
+		r.Contacts,
+		r.Users,
+		r.QRCodes,
+		time.Duration(cfg.Transfers.BeneficiaryCoolingOffHours)*time.Hour,
+	)
+	// Large transfers wait for their sender to approve them on a trusted
+	// device
+	s.DeviceConfirmations = deviceconfirm.NewService(r.TrustedDevices, r.LoginEvents, s.Transactions, s.Notification, deviceconfirm.Config{
+		Threshold: cfg.Transfers.DeviceConfirmationThreshold,
+		Window:    cfg.Transfers.DeviceConfirmationWindow,
+	})
+	s.Payments = payment.NewService(r.Merchants, s.Wallets, s.Transactions, s.QR, s.Contacts, s.DeviceConfirmations, s.PaymentPINs)
+
+	// Numeric one-time codes, an alternative to payment code QRs
+	s.PaymentCodes = paymentcode.NewService(r.PaymentCodes, s.Wallets, cacheSvc, cfg.Auth.PaymentCodeSecret)
+
+	// Signed payment requests, shared as links or written to NFC tags
+	s.Intents = paymentintent.NewService(
+		r.PaymentIntents,
+		r.Users,
+		r.Merchants,
+		s.Transactions,
+		s.Contacts,
+		cfg.Auth.PaymentIntentSecret,
+		cfg.Server.PublicBaseURL,
+	)
+
+	// Enterprise organizations with their own wallets, spending policies
+	// and payment approvals
+	s.Enterprise = enterprise.NewService(r.Enterprises, r.Users, s.Wallets, s.Notification, cacheSvc)
+
+	// Itemized receipts for merchant payments
+	s.Receipts = receipt.NewService(r.Receipts, r.Transactions, r.Users, r.Merchants, s.Notification)
+
+	// Platform system accounts, kept apart from customer wallets
+	s.Treasury = treasury.NewService(r.Treasury)
+	if err := s.Treasury.EnsureAccounts(context.Background()); err != nil {
+		log.Printf("Failed to create system accounts: %v", err)
+	}
+
+	s.Transfers = transfer.NewService(s.Wallets, s.Notification, s.Contacts, s.DeviceConfirmations, s.PaymentPINs)
+
+	// Payments third-party apps request are sent as transfers once the
+	// user approves them
+	s.OAuth = oauth.NewService(r.OAuth, r.Users, s.Transfers, s.Notification)
+
+	// Dashboards read daily aggregates the projector builds from completed
+	// transactions
+	s.Projector = dashboard.NewProjector(r.Projections, r.Merchants)
+
+	// Both parties of a completed payment are sent a confirmation; they
+	// follow the same transaction stream as the dashboards
+	s.Confirmations = confirmation.NewService(r.PaymentConfirmations, r.Projections, r.Users, s.Notification, confirmation.Config{
+		BaseURL:         cfg.Server.PublicBaseURL,
+		DigestThreshold: cfg.Transfers.ConfirmationDigestThreshold,
+	})
+	s.Dashboard = dashboard.NewService(r.Projections, r.Analytics, r.Transactions, r.Wallets, r.Merchants, r.PaymentLinks)
+
+	// Dispute evidence lives in local storage or an S3-compatible bucket
+	files, err := storage.New(storage.Config{
+		Driver:    cfg.Storage.Driver,
+		LocalDir:  cfg.Storage.LocalDir,
+		Endpoint:  cfg.Storage.Endpoint,
+		Region:    cfg.Storage.Region,
+		Bucket:    cfg.Storage.Bucket,
+		AccessKey: cfg.Storage.AccessKey,
+		SecretKey: cfg.Storage.SecretKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+	s.Disputes = dispute.NewService(
+		r.Disputes,
+		r.Transactions,
+		r.Chargebacks,
+		files,
+		s.Transactions,
+		cacheSvc,
+		dispute.Config{
+			ResponseWindow:      time.Duration(cfg.Disputes.ResponseDays) * 24 * time.Hour,
+			RepresentmentWindow: time.Duration(cfg.Disputes.ChargebackRepresentmentDays) * 24 * time.Hour,
+			ChargebackFee:       cfg.Disputes.ChargebackFee,
+		},
+	)
+
+	// Escrow payments, released by the buyer or automatically, with
+	// objections handed to the dispute service
+	s.Escrows = escrow.NewService(
+		r.Escrows,
+		r.Users,
+		s.Wallets,
+		s.Disputes,
+		s.Notification,
+		cacheSvc,
+		cfg.Escrow.AutoReleaseDays,
+	)
+
+	// Bank funding sources; an outage at the provider fails fast rather
+	// than holding up top-ups and withdrawals
+	bankProvider, err := funding.NewProvider(cfg.Funding.BankProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank provider: %w", err)
+	}
+	bankProvider = funding.WithResilience(
+		bankProvider,
+		breakers.Breaker("bank_provider", funding.ProviderBreakerConfig),
+		resilience.DefaultPolicy,
+	)
+	s.Funding = funding.NewService(
+		r.BankAccounts,
+		r.Deposits,
+		r.Wallets,
+		s.Wallets,
+		bankProvider,
+		s.Notification,
+		cacheSvc,
+		cfg.Funding.NSFFee,
+	)
+
+	// Virtual card issuing
+	cardIssuer, err := issuing.NewProvider(cfg.Issuing.CardIssuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize card issuer: %w", err)
+	}
+	cardIssuer = issuing.WithResilience(
+		cardIssuer,
+		breakers.Breaker("card_issuer", resilience.DefaultBreakerConfig),
+		resilience.DefaultPolicy,
+	)
+	s.Issuing = issuing.NewService(r.VirtualCards, r.Wallets, s.Wallets, cardIssuer, cacheSvc)
+
+	// Merchant webhooks are queued and delivered with retries by a job
+	s.Webhooks = webhook.NewService(r.WebhookDeliveries, r.Merchants)
+
+	// Transactions left pending are expired and their holds released
+	s.PendingTransactions = pendingtx.NewService(r.PendingTransactions, s.Webhooks, pendingtx.Config{
+		TTL: cfg.Transfers.PendingTTL,
+	})
+
+	// Sandbox API keys run against simulated customers, cards and banks
+	s.Sandbox = sandbox.NewService(r.Sandbox, s.Webhooks)
+
+	// Merchant payment links and hosted checkout
+	s.Checkout = checkout.NewService(
+		r.PaymentLinks,
+		r.Merchants,
+		s.Transactions,
+		s.Webhooks,
+		s.Loyalty,
+		cfg.Server.PublicBaseURL,
+	)
+
+	// Merchant subscription plans and recurring customer billing
+	s.Subscription = subscription.NewService(
+		r.Subscriptions,
+		r.Users,
+		r.Merchants,
+		s.Transactions,
+		s.Notification,
+		s.Webhooks,
+	)
+
+	// Scheduled transaction exports
+	s.Exports = export.NewService(
+		r.ExportSchedules,
+		r.Users,
+		export.NewLogMailer(),
+		export.NewHTTPPoster(),
+		s.Notification,
+		export.NewLocalStorage(cfg.Exports.StorageDir),
+	)
+
+	// Daily accounting journals, delivered to S3 or SFTP when configured
+	journals, err := newAccountingDelivery(cfg.Accounting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize accounting delivery: %w", err)
+	}
+	mappings := make(map[string]accounting.AccountMapping, len(cfg.Accounting.Accounts))
+	for internal, account := range cfg.Accounting.Accounts {
+		mappings[internal] = accounting.AccountMapping{Code: account.Code, Name: account.Name}
+	}
+	s.Accounting, err = accounting.NewService(r.Accounting, journals, accounting.Config{
+		Formats:  cfg.Accounting.Formats,
+		Accounts: mappings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize accounting exports: %w", err)
+	}
+
+	// Regulatory reports drafted from large and structured transactions
+	s.Compliance = compliance.NewService(r.Compliance, r.Users, compliance.Config{
+		LargeTransactionThreshold: cfg.Compliance.LargeTransactionThreshold,
+		StructuringWindow:         cfg.Compliance.StructuringWindow,
+		StructuringMinCount:       cfg.Compliance.StructuringMinCount,
+		StructuringMinTotal:       cfg.Compliance.StructuringMinTotal,
+		StructuringFloor:          cfg.Compliance.StructuringFloor,
+	})
+
+	// Tax year summaries, generated in the background for large accounts
+	s.TaxSummaries = taxsummary.NewService(r.TaxSummaries)
+
+	// Multi-step payments, undone step by step when one fails
+	s.Sagas = saga.NewService(r.Sagas)
+
+	// Merchant invoicing
+	s.Invoices = invoice.NewService(
+		r.Invoices,
+		r.Merchants,
+		s.Transactions,
+		s.Receipts,
+		s.Notification,
+		cfg.Server.PublicBaseURL,
+	)
+
+	// Split bills between users
+	s.Splits = split.NewService(r.Splits, r.Transactions, r.Users, s.Transactions, s.Notification)
+
+	// Settled transactions older than the retention age move to the
+	// archive; 0 keeps them all in the hot table
+	s.Retention = retention.NewService(
+		r.TransactionArchive,
+		time.Duration(cfg.Retention.ArchiveAfterDays)*24*time.Hour,
+	)
+
+	s.KYC = kyc.NewService(r.KYC)
+	s.Handles = handle.NewService(r.Users)
+
+	// Merchant staff operate the point of sale with their own PINs
+	s.Staff = staff.NewService(r.MerchantStaff, r.Users, r.Merchants)
+	s.Terminals = terminal.NewService(r.Terminals, r.Merchants)
+	// Customers can ask to approve merchant scans of their payment code
+	s.ScanConfirmations = scanconfirm.NewService(r.ScanConfirmations, s.Notification, scanconfirm.Config{
+		Window: cfg.Transfers.ScanConfirmationWindow,
+	})
+	s.Merchants = merchant.NewService(
+		r.Merchants,
+		r.QRCodes,
+		r.Transactions,
+		s.QR,
+		s.Transactions,
+		s.Wallets,
+		s.Receipts,
+		s.Staff,
+		s.Terminals,
+		s.PaymentCodes,
+		s.ScanConfirmations,
+		s.Sagas,
+	)
+	// Customers ask merchants for refunds, paid within the merchant's policy
+	s.RefundRequests = refundrequest.NewService(r.RefundRequests, r.Transactions, s.Merchants, s.Webhooks, s.Notification)
+
+	return s, nil
+}
+
+// newAccountingDelivery returns where the journals are delivered, or nil
+// to keep them for download only
+func newAccountingDelivery(cfg config.AccountingConfig) (accounting.Deliverer, error) {
+	switch cfg.Delivery {
+	case "s3":
+		return accounting.NewS3Delivery(storage.Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		}, cfg.S3.Prefix)
+	case "sftp":
+		return accounting.NewSFTPDelivery(accounting.SFTPConfig{
+			Host:           cfg.SFTP.Host,
+			Port:           cfg.SFTP.Port,
+			User:           cfg.SFTP.User,
+			Password:       cfg.SFTP.Password,
+			PrivateKeyFile: cfg.SFTP.PrivateKeyFile,
+			HostKey:        cfg.SFTP.HostKey,
+			Dir:            cfg.SFTP.Dir,
+		})
+	}
+	return nil, nil
+}
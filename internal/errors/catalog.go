@@ -0,0 +1,642 @@
+package errors
+
+import "net/http"
+
+// Definition documents an error code: the HTTP status it's sent with and a
+// description in English. The message a client receives is usually more
+// specific, such as which field was invalid; clients should branch on the
+// code and only show the message.
+type Definition struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// definitions lists every code the API returns. Codes are stable: rename
+// one only with a new API version.
+var definitions = []Definition{
+	// Generic
+	{CodeBadRequest, http.StatusBadRequest, "the request is invalid"},
+	{CodeUnauthorized, http.StatusUnauthorized, "authentication is required"},
+	{CodeForbidden, http.StatusForbidden, "you are not allowed to do this"},
+	{CodeNotFound, http.StatusNotFound, "resource not found"},
+	{CodeMethodNotAllowed, http.StatusMethodNotAllowed, "method not allowed"},
+	{CodeConflict, http.StatusConflict, "the request conflicts with the current state"},
+	{CodeGone, http.StatusGone, "resource is no longer available"},
+	{CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "request body is too large"},
+	{CodeUnprocessable, http.StatusUnprocessableEntity, "the request cannot be processed"},
+	{CodeRateLimited, http.StatusTooManyRequests, "too many requests, try again later"},
+	{CodeInternal, http.StatusInternalServerError, "internal server error"},
+	{CodeUpstream, http.StatusBadGateway, "an upstream provider failed"},
+	{CodeServiceUnavailable, http.StatusServiceUnavailable, "service unavailable"},
+	{CodeTimeout, http.StatusGatewayTimeout, "the request took too long, try again later"},
+
+	// Authentication
+	{CodeInvalidCredentials, http.StatusUnauthorized, "invalid email or password"},
+	{CodeInvalidToken, http.StatusUnauthorized, "token is invalid or has expired"},
+	{CodeAccountSuspended, http.StatusForbidden, "account is suspended"},
+	{"LOGIN_REPORT_LINK_INVALID", http.StatusNotFound, "this link is invalid or has expired"},
+	{"SESSION_NOT_FOUND", http.StatusNotFound, "session not found or already ended"},
+
+	// Social login
+	{"UNKNOWN_LOGIN_PROVIDER", http.StatusNotFound, "sign-in provider not found"},
+	{"LOGIN_PROVIDER_DISABLED", http.StatusForbidden, "sign-in with this provider is disabled"},
+	{"INVALID_LOGIN_STATE", http.StatusBadRequest, "sign-in request is invalid or has expired, start again"},
+	{"LOGIN_PROVIDER_REFUSED", http.StatusUnauthorized, "the provider refused the sign-in"},
+	{"INVALID_ID_TOKEN", http.StatusUnauthorized, "the provider's ID token could not be verified"},
+	{"PROVIDER_EMAIL_UNVERIFIED", http.StatusForbidden, "the provider account has no verified email"},
+	{"SIGN_IN_TO_LINK", http.StatusConflict, "an account uses this email, sign in to link the provider account"},
+	{"IDENTITY_ALREADY_LINKED", http.StatusConflict, "the provider account is linked to another user"},
+
+	// Third-party apps
+	{"OAUTH_CLIENT_NOT_FOUND", http.StatusNotFound, "app not found"},
+	{"INVALID_OAUTH_CLIENT", http.StatusBadRequest, "app registration is invalid"},
+	{"INVALID_AUTHORIZATION_REQUEST", http.StatusBadRequest, "authorization request is invalid: unknown app or redirect URI"},
+	{"INVALID_ACCESS_TOKEN", http.StatusUnauthorized, "access token is invalid or has expired"},
+	{"INSUFFICIENT_SCOPE", http.StatusForbidden, "access token lacks the required scope"},
+	{"APP_NOT_CONNECTED", http.StatusNotFound, "app is not connected"},
+	{"APP_PAYMENT_REQUEST_NOT_FOUND", http.StatusNotFound, "payment request not found"},
+	{"APP_PAYMENT_REQUEST_DECIDED", http.StatusConflict, "payment request is no longer pending"},
+	{"INVALID_APP_PAYMENT_REQUEST", http.StatusBadRequest, "payment request needs another user as recipient and a positive amount"},
+
+	// Request validation
+	{"INVALID_REQUEST", http.StatusBadRequest, "invalid request"},
+	{CodeValidationFailed, http.StatusBadRequest, "one or more fields are invalid"},
+	{"INVALID_QR", http.StatusBadRequest, "invalid QR code"},
+	{"INVALID_WALLET", http.StatusBadRequest, "invalid wallet"},
+	{"LIMIT_EXCEEDED", http.StatusForbidden, "limit exceeded"},
+
+	// Shared by several features
+	{"INVALID_AMOUNT", http.StatusBadRequest, "invalid amount"},
+	{"SELF_PAYMENT", http.StatusBadRequest, "merchants cannot pay themselves"},
+	{"NAME_REQUIRED", http.StatusBadRequest, "name is required"},
+	{"INVALID_OUTCOME", http.StatusBadRequest, "invalid outcome"},
+	{"INVALID_STATUS", http.StatusBadRequest, "invalid status"},
+	{"INVALID_EMAIL", http.StatusBadRequest, "a valid email address is required"},
+	{"INVALID_LIMIT", http.StatusBadRequest, "limits cannot be negative"},
+	{"INVALID_COUNTRY", http.StatusBadRequest, "countries must be 2-letter ISO codes"},
+	{"MERCHANT_NOT_FOUND", http.StatusNotFound, "merchant profile not found"},
+	{"INVALID_PERIOD", http.StatusBadRequest, "report period is invalid"},
+	{"RECIPIENT_NOT_FOUND", http.StatusNotFound, "recipient not found"},
+
+	// Users and cards
+	{"USER_NOT_FOUND", http.StatusNotFound, "user not found"},
+	{"EMAIL_TAKEN", http.StatusConflict, "email already taken"},
+	{"PHONE_TAKEN", http.StatusConflict, "phone number already taken"},
+	{"HANDLE_TAKEN", http.StatusConflict, "handle already taken"},
+	{"CARD_NOT_FOUND", http.StatusNotFound, "credit card not found"},
+
+	// Wallets
+	{"INSUFFICIENT_BALANCE", http.StatusBadRequest, "insufficient balance"},
+	{"OVERDRAFT_LIMIT_EXCEEDED", http.StatusBadRequest, "balance would exceed the wallet's overdraft limit"},
+	{"INVALID_OVERDRAFT_LIMIT", http.StatusBadRequest, "overdraft limit must be between zero and the configured maximum"},
+	{"SHARED_WALLET_NOT_FOUND", http.StatusNotFound, "shared wallet not found"},
+	{"MEMBER_NOT_FOUND", http.StatusNotFound, "member not found"},
+	{"SHARED_PAYMENT_NOT_FOUND", http.StatusNotFound, "payment not found"},
+	{"WALLET_ROLE_FORBIDDEN", http.StatusForbidden, "your role on this wallet does not allow this"},
+	{"MEMBER_LIMIT_EXCEEDED", http.StatusForbidden, "payment exceeds your per-transaction limit"},
+	{"MEMBER_DAILY_LIMIT", http.StatusForbidden, "payment exceeds your daily spending limit"},
+	{"WALLET_LOCKED", http.StatusForbidden, "wallet is locked"},
+	{"WALLET_BUSY", http.StatusConflict, "wallet is busy with another operation, try again"},
+	{"MEMBER_EXISTS", http.StatusConflict, "user is already a member"},
+	{"LAST_OWNER", http.StatusConflict, "a shared wallet must keep at least one owner"},
+	{"SHARED_PAYMENT_NOT_PENDING", http.StatusConflict, "payment is not pending approval"},
+	{"TOO_MANY_MEMBERS", http.StatusConflict, "shared wallet has reached its member limit"},
+	{"INVALID_WALLET_NAME", http.StatusBadRequest, "wallet name is required"},
+	{"INVALID_WALLET_ROLE", http.StatusBadRequest, "role must be owner, spender or viewer"},
+	{"INVALID_CURRENCY", http.StatusBadRequest, "invalid currency"},
+	{"INSUFFICIENT_SHARED_FUNDS", http.StatusBadRequest, "insufficient funds in shared wallet"},
+	{"WALLET_NOT_FOUND", http.StatusNotFound, "wallet not found"},
+	{"DAILY_LIMIT_EXCEEDED", http.StatusForbidden, "daily limit exceeded"},
+	{"MONTHLY_LIMIT_EXCEEDED", http.StatusForbidden, "monthly limit exceeded"},
+	{"HOLD_NOT_ACTIVE", http.StatusConflict, "hold is not active"},
+	{"INVALID_OPERATION", http.StatusBadRequest, "invalid operation"},
+
+	// Linked cards
+	{"CARD_NOT_ACTIVE", http.StatusConflict, "card is not active"},
+	{"CARD_EXPIRED", http.StatusBadRequest, "card has expired"},
+	{"CARD_NOT_VERIFIED", http.StatusForbidden, "card must be verified before it can fund the wallet"},
+	{"CARD_ALREADY_VERIFIED", http.StatusConflict, "card is already verified"},
+	{"CARD_VERIFICATION_NOT_STARTED", http.StatusConflict, "card verification has not been started"},
+	{"CARD_VERIFICATION_FAILED", http.StatusBadRequest, "verification amount does not match"},
+	{"CARD_VERIFICATION_LOCKED", http.StatusForbidden, "too many verification attempts; link the card again"},
+	{"INVALID_CARD_EXPIRY", http.StatusBadRequest, "invalid expiry date"},
+	{"INVALID_BILLING_COUNTRY", http.StatusBadRequest, "billing country must be a two-letter code"},
+	{"CARD_DECLINED", http.StatusPaymentRequired, "card was declined"},
+
+	// Card top-ups
+	{"TOP_UP_NOT_FOUND", http.StatusNotFound, "top-up not found"},
+	{"INVALID_TOP_UP_EVENT", http.StatusBadRequest, "invalid top-up event"},
+
+	// Card vault
+	{"INVALID_CARD_NUMBER", http.StatusBadRequest, "invalid card number"},
+	{"CARD_TOKEN_REQUIRED", http.StatusBadRequest, "card numbers can't be linked directly; link a token from the payment SDK"},
+
+	// Wallet locks
+	{"DEBITS_LOCKED", http.StatusForbidden, "wallet is locked for outgoing payments"},
+	{"CREDITS_LOCKED", http.StatusForbidden, "wallet is locked for incoming payments"},
+	{"INVALID_LOCK_SCOPE", http.StatusBadRequest, "lock scope must be debits, credits or full"},
+	{"INVALID_LOCK_REASON", http.StatusBadRequest, "lock reason must be fraud, legal or user_requested"},
+	{"INVALID_LOCK_EXPIRY", http.StatusBadRequest, "lock expiry must be in the future"},
+	{"LOCK_NOT_OWNED", http.StatusForbidden, "this lock can only be lifted by support"},
+	{"WALLET_LOCK_NOT_FOUND", http.StatusNotFound, "wallet has no active lock"},
+
+	// Payments
+	{"HIGH_RISK_TRANSACTION", http.StatusForbidden, "transaction risk too high"},
+
+	// QR codes
+	{"INVALID_QR_TYPE", http.StatusBadRequest, "invalid QR code type"},
+	{"INVALID_USER_TYPE", http.StatusBadRequest, "invalid user type"},
+	{"QR_EXPIRED", http.StatusGone, "QR code has expired"},
+	{"QR_INACTIVE", http.StatusGone, "QR code is not active"},
+	{"QR_LIMIT_EXCEEDED", http.StatusForbidden, "QR code usage limit exceeded"},
+	{"QR_NOT_FOUND", http.StatusNotFound, "QR code not found"},
+	{"INVALID_QR_STATUS", http.StatusBadRequest, "status must be active, revoked or expired"},
+	{"QR_NOT_REGENERABLE", http.StatusBadRequest, "only receive, payment and poster codes can be regenerated"},
+	{"QR_NOT_POSTER", http.StatusBadRequest, "QR code is not a merchant poster"},
+	{"QR_DAILY_LIMIT_EXCEEDED", http.StatusForbidden, "payment exceeds the QR code's daily limit"},
+	{"QR_MONTHLY_LIMIT_EXCEEDED", http.StatusForbidden, "payment exceeds the QR code's monthly limit"},
+
+	// Contacts
+	{"CONTACT_NOT_FOUND", http.StatusNotFound, "contact not found"},
+	{"CONTACT_EXISTS", http.StatusConflict, "contact already saved"},
+	{"INVALID_RECIPIENT", http.StatusBadRequest, "give exactly one of phone, email, qr_code or user_id"},
+	{"SELF_CONTACT", http.StatusBadRequest, "you cannot add yourself as a contact"},
+	{"NICKNAME_TOO_LONG", http.StatusBadRequest, "nickname is too long"},
+	{"NOT_BENEFICIARY", http.StatusForbidden, "add the recipient to your contacts before paying them for the first time"},
+	{"COOLING_OFF", http.StatusForbidden, "new beneficiaries can't be paid until the cooling-off period ends"},
+
+	// Fraud rules
+	{"PAYMENT_DECLINED", http.StatusForbidden, "payment declined by fraud rules"},
+	{"NOT_MERCHANT", http.StatusForbidden, "merchant profile not found"},
+	{"INVALID_VELOCITY", http.StatusBadRequest, "max charges per customer per day cannot be negative"},
+	{"TOO_MANY_COUNTRIES", http.StatusBadRequest, "too many blocked countries"},
+
+	// Merchant API access
+	{"IP_NOT_ALLOWED", http.StatusForbidden, "requests from this IP address are not allowed for this API key"},
+	{"CLIENT_CERT_REQUIRED", http.StatusUnauthorized, "a client certificate is required for this API key"},
+	{"CLIENT_CERT_NOT_ALLOWED", http.StatusForbidden, "client certificate is not registered for this merchant"},
+	{"INVALID_IP_RANGE", http.StatusBadRequest, "IP range must be an IP address or CIDR block"},
+	{"INVALID_KEY_MODE", http.StatusBadRequest, "key mode must be live or sandbox"},
+	{"TOO_MANY_IP_RANGES", http.StatusBadRequest, "too many IP ranges"},
+	{"INVALID_CERTIFICATE", http.StatusBadRequest, "certificate must be a PEM encoded X.509 certificate"},
+	{"CERTIFICATE_EXPIRED", http.StatusBadRequest, "certificate has expired"},
+	{"CERTIFICATE_EXISTS", http.StatusConflict, "certificate is already registered"},
+	{"NO_CLIENT_CERTIFICATES", http.StatusConflict, "register a client certificate before requiring mutual TLS"},
+	{"LAST_CLIENT_CERTIFICATE", http.StatusConflict, "turn off mutual TLS before removing the last client certificate"},
+	{"IP_RANGE_NOT_FOUND", http.StatusNotFound, "IP range not found"},
+	{"CLIENT_CERT_NOT_FOUND", http.StatusNotFound, "client certificate not found"},
+
+	// Merchants
+	{"CHARGE_NOT_FOUND", http.StatusNotFound, "charge not found"},
+	{"REFUND_EXCEEDS_CHARGE", http.StatusBadRequest, "refund exceeds the amount left to refund on this charge"},
+	{"MERCHANT_INACTIVE", http.StatusForbidden, "merchant is not active"},
+	{"TRANSACTION_LIMIT_EXCEEDED", http.StatusForbidden, "transaction limit exceeded"},
+	{"UNKNOWN_FEE_PLAN", http.StatusBadRequest, "fee plan must be standard, plus or enterprise"},
+	{"INVALID_FEE_RATE", http.StatusBadRequest, "negotiated rate must be at least 0 and below 1"},
+
+	// Reversals
+	{"NOT_REVERSIBLE", http.StatusConflict, "only completed payments and transfers can be reversed"},
+	{"ALREADY_REVERSED", http.StatusConflict, "transaction has already been reversed"},
+	{"TRANSACTION_HAS_REFUNDS", http.StatusConflict, "transaction has refunds; reverse or settle them through refunds instead"},
+	{"REVERSAL_REASON_REQUIRED", http.StatusBadRequest, "a reason is required to reverse a transaction"},
+	{"REVERSAL_UNFUNDED", http.StatusConflict, "recipient's balance no longer covers the reversal"},
+
+	// Receipts
+	{"TRANSACTION_NOT_FOUND", http.StatusNotFound, "transaction not found"},
+	{"NOT_MERCHANT_PAYMENT", http.StatusConflict, "receipts are only available for completed merchant payments"},
+	{"INVALID_ITEM", http.StatusBadRequest, "items need a description, positive quantity and non-negative price"},
+	{"INVALID_AMOUNTS", http.StatusBadRequest, "tax and tip must be non-negative and no more than the amount"},
+	{"ITEMS_MISMATCH", http.StatusBadRequest, "items do not add up to the amount less tax and tip"},
+
+	// Transaction categories
+	{"INVALID_CATEGORY", http.StatusBadRequest, "unknown transaction category"},
+	{"NOT_SPENDING", http.StatusConflict, "only payments you sent can be categorized"},
+	{"INVALID_CATEGORY_RULE", http.StatusBadRequest, "rule needs a payee or a pattern"},
+	{"TOO_MANY_CATEGORY_RULES", http.StatusBadRequest, "category rule limit reached"},
+	{"NO_PAYEE", http.StatusBadRequest, "transaction has no payee to remember"},
+	{"CATEGORY_RULE_NOT_FOUND", http.StatusNotFound, "category rule not found"},
+
+	// Merchant staff
+	{"STAFF_NOT_FOUND", http.StatusNotFound, "staff member not found"},
+	{"STAFF_INACTIVE", http.StatusForbidden, "staff membership is not active"},
+	{"SCOPE_DENIED", http.StatusForbidden, "operator is not allowed to do this"},
+	{"WRONG_PIN", http.StatusUnauthorized, "incorrect operator PIN"},
+	{"PIN_NOT_SET", http.StatusUnauthorized, "operator has not set a PIN"},
+	{"PIN_LOCKED", http.StatusTooManyRequests, "too many incorrect PINs, try again later"},
+	{"STAFF_EXISTS", http.StatusConflict, "user is already on your staff"},
+	{"NOT_INVITED", http.StatusConflict, "invitation is no longer pending"},
+	{"SELF_INVITE", http.StatusBadRequest, "you cannot add yourself as staff"},
+	{"TOO_MANY_STAFF", http.StatusBadRequest, "staff limit reached"},
+	{"INVALID_PIN", http.StatusBadRequest, "PIN must be 4 to 6 digits"},
+
+	// Terminals
+	{"TERMINAL_NOT_FOUND", http.StatusNotFound, "terminal not found"},
+	{"TERMINAL_INACTIVE", http.StatusConflict, "terminal is not active"},
+	{"TERMINAL_DEACTIVATED", http.StatusConflict, "terminal has been deactivated"},
+	{"INVALID_PAIRING_CODE", http.StatusUnauthorized, "pairing code is invalid or has expired"},
+	{"INVALID_TERMINAL_CREDENTIALS", http.StatusUnauthorized, "invalid terminal credentials"},
+	{"TOO_MANY_TERMINALS", http.StatusBadRequest, "terminal limit reached"},
+
+	// One-time payment codes
+	{"INVALID_CODE_LENGTH", http.StatusBadRequest, "payment codes are 6 to 8 digits long"},
+	{"INVALID_CODE_EXPIRY", http.StatusBadRequest, "payment codes expire after 1 to 30 minutes"},
+	{"INVALID_PAYMENT_CODE", http.StatusBadRequest, "payment code is invalid, expired or already used"},
+	{"PAYMENT_CODE_AMOUNT_EXCEEDED", http.StatusBadRequest, "charge exceeds the amount the payment code was issued for"},
+	{"PAYMENT_CODE_LOCKED", http.StatusTooManyRequests, "too many invalid payment codes entered, try again later"},
+	{"PAYMENT_CODE_UNAVAILABLE", http.StatusServiceUnavailable, "could not issue a unique payment code, try again"},
+
+	// Payment intents
+	{"INVALID_INTENT_EXPIRY", http.StatusBadRequest, "payment intents expire after 1 minute to 7 days"},
+	{"INVALID_PAYMENT_INTENT", http.StatusBadRequest, "payment intent link is invalid"},
+	{"PAYMENT_INTENT_EXPIRED", http.StatusGone, "payment intent has expired"},
+	{"PAYMENT_INTENT_PAID", http.StatusConflict, "payment intent has already been paid"},
+	{"SELF_PAYMENT_INTENT", http.StatusBadRequest, "you cannot pay your own payment intent"},
+	{"PAYMENT_INTENT_RECIPIENT_GONE", http.StatusGone, "payment intent recipient no longer exists"},
+
+	// Payment links and checkout
+	{"PAYMENT_LINK_NOT_FOUND", http.StatusNotFound, "payment link not found"},
+	{"CHECKOUT_SESSION_NOT_FOUND", http.StatusNotFound, "checkout session not found"},
+	{"CHECKOUT_SESSION_NOT_ALLOWED", http.StatusNotFound, "checkout session belongs to another user"},
+	{"PAYMENT_LINK_UNAVAILABLE", http.StatusGone, "payment link is no longer available"},
+	{"CHECKOUT_SESSION_EXPIRED", http.StatusGone, "checkout session has expired"},
+	{"CHECKOUT_SESSION_NOT_OPEN", http.StatusGone, "checkout session is not open"},
+	{"INVALID_EXPIRY", http.StatusBadRequest, "expiry must be in the future"},
+	{"INVALID_MAX_USES", http.StatusBadRequest, "max uses must be -1 (unlimited) or a positive number"},
+	{"INVALID_SESSION_TTL", http.StatusBadRequest, "expires_in_minutes must be between 5 and 1440"},
+	{"INVALID_RETURN_URL", http.StatusBadRequest, "return URLs must be absolute http or https URLs"},
+	{"INVALID_METADATA", http.StatusBadRequest, "metadata is limited to 20 keys"},
+	{"INVALID_REFERENCE", http.StatusBadRequest, "client reference is limited to 100 characters"},
+
+	// Invoices
+	{"INVOICE_NOT_FOUND", http.StatusNotFound, "invoice not found"},
+	{"INVOICE_NOT_EDITABLE", http.StatusConflict, "only draft invoices can be changed"},
+	{"INVOICE_NOT_PAYABLE", http.StatusConflict, "invoice is not payable"},
+	{"INVOICE_NOT_VOIDABLE", http.StatusConflict, "paid or void invoices cannot be voided"},
+	{"NO_LINE_ITEMS", http.StatusBadRequest, "invoice must have at least one line item"},
+	{"INVALID_LINE_ITEM", http.StatusBadRequest, "line items need a description, positive quantity and non-negative price"},
+	{"INVALID_TAX_RATE", http.StatusBadRequest, "tax rate must be between 0 and 100"},
+	{"INVALID_DUE_DATE", http.StatusBadRequest, "due date must be in the future"},
+	{"INVALID_INVOICE_TOTAL", http.StatusBadRequest, "invoice total must be greater than zero"},
+
+	// Subscriptions
+	{"PLAN_NOT_FOUND", http.StatusNotFound, "subscription plan not found"},
+	{"SUBSCRIPTION_NOT_FOUND", http.StatusNotFound, "subscription not found"},
+	{"ALREADY_SUBSCRIBED", http.StatusConflict, "you already have a subscription to this plan"},
+	{"SUBSCRIPTION_CANCELED", http.StatusConflict, "subscription is already canceled"},
+	{"SUBSCRIPTION_NOT_PAUSABLE", http.StatusConflict, "only active subscriptions can be paused"},
+	{"SUBSCRIPTION_NOT_PAUSED", http.StatusConflict, "subscription is not paused"},
+	{"PLAN_ARCHIVED", http.StatusGone, "subscription plan is no longer available"},
+	{"INVALID_INTERVAL", http.StatusBadRequest, "interval must be day, week, month or year, every 1 to 12"},
+	{"INVALID_TRIAL", http.StatusBadRequest, "trial must be between 0 and 90 days"},
+	{"SELF_SUBSCRIPTION", http.StatusBadRequest, "merchants cannot subscribe to their own plans"},
+	{"CONSENT_REQUIRED", http.StatusBadRequest, "you must authorize recurring charges to subscribe"},
+
+	// Loyalty
+	{"LOYALTY_PROGRAM_NOT_FOUND", http.StatusNotFound, "merchant has no loyalty program"},
+	{"LOYALTY_ACCOUNT_NOT_FOUND", http.StatusNotFound, "no loyalty points with this merchant"},
+	{"INVALID_EARN_RATE", http.StatusBadRequest, "earn rate must be above 0 and at most 100 percent"},
+	{"INVALID_BURN_RATE", http.StatusBadRequest, "burn rate must be between 1 and 10000 points per unit of currency"},
+	{"INVALID_REDEEM_LIMIT", http.StatusBadRequest, "max redeem percent must be above 0 and at most 100"},
+	{"INVALID_MINIMUM", http.StatusBadRequest, "minimum redeemable points cannot be negative"},
+	{"INVALID_POINTS", http.StatusBadRequest, "points must be greater than zero"},
+	{"BELOW_REDEMPTION_MINIMUM", http.StatusBadRequest, "not enough points to meet the program's minimum redemption"},
+	{"INSUFFICIENT_POINTS", http.StatusBadRequest, "insufficient loyalty points"},
+	{"DISCOUNT_TOO_SMALL", http.StatusBadRequest, "points are worth less than the smallest discount"},
+	{"SELF_REDEMPTION", http.StatusBadRequest, "merchants cannot redeem points at their own program"},
+
+	// Promotions
+	{"PROMOTION_NOT_FOUND", http.StatusNotFound, "promotion not found"},
+	{"PROMO_CODE_NOT_FOUND", http.StatusNotFound, "promo code is not valid"},
+	{"PROMO_CODE_TAKEN", http.StatusConflict, "promo code is already in use"},
+	{"PROMOTION_NOT_PAUSABLE", http.StatusConflict, "only active promotions can be paused"},
+	{"PROMOTION_NOT_RESUMABLE", http.StatusConflict, "only paused promotions with budget left can be resumed"},
+	{"PROMOTION_ENDED", http.StatusConflict, "promotion has already ended"},
+	{"PROMO_CODE_ALREADY_REDEEMED", http.StatusConflict, "you have already redeemed this promo code"},
+	{"PROMO_CODE_EXPIRED", http.StatusGone, "promo code is no longer available"},
+	{"INVALID_PROMOTION_TYPE", http.StatusBadRequest, "type must be cashback, fixed_bonus or first_transaction"},
+	{"INVALID_CASHBACK_RATE", http.StatusBadRequest, "cashback rate must be above 0 and at most 100 percent"},
+	{"INVALID_BUDGET", http.StatusBadRequest, "budget must be greater than zero"},
+	{"INVALID_FUNDING", http.StatusBadRequest, "funded_by must be platform or merchant"},
+	{"MERCHANT_REQUIRED", http.StatusBadRequest, "merchant-funded promotions must name the merchant"},
+	{"INVALID_SCHEDULE", http.StatusBadRequest, "promotion must end after it starts"},
+	{"INVALID_PROMO_CODE", http.StatusBadRequest, "code must be 3 to 32 letters, digits, dashes or underscores"},
+
+	// Dashboards
+	{"INVALID_SORT", http.StatusBadRequest, "sort must be volume, payments or recent"},
+	{"INVALID_GRANULARITY", http.StatusBadRequest, "granularity must be day, week or month"},
+	{"INVALID_METRIC", http.StatusBadRequest, "metrics must be volume, count, average or unique_customers"},
+	{"INVALID_TIMEZONE", http.StatusBadRequest, "unknown timezone"},
+	{"INVALID_DATE", http.StatusBadRequest, "dates must be in YYYY-MM-DD format"},
+	{"INVALID_DATE_RANGE", http.StatusBadRequest, "start date must not be after end date"},
+	{"DATE_RANGE_TOO_LONG", http.StatusBadRequest, "date range has too many periods for the granularity"},
+
+	// Savings pots
+	{"POT_NOT_FOUND", http.StatusNotFound, "pot not found"},
+	{"POT_LOCKED", http.StatusConflict, "pot is locked until its target date"},
+	{"POT_CLOSED", http.StatusConflict, "pot is closed"},
+	{"ROUND_UP_POT_EXISTS", http.StatusConflict, "round-ups already go to another pot"},
+	{"INVALID_TARGET", http.StatusBadRequest, "target amount cannot be negative"},
+	{"INVALID_ROUND_UP", http.StatusBadRequest, "round-up must be one of 1, 5 or 10"},
+	{"INVALID_SWEEP", http.StatusBadRequest, "sweeps need a positive amount and a daily, weekly or monthly frequency"},
+	{"LOCK_IN_PAST", http.StatusBadRequest, "lock date must be in the future"},
+	{"LOCK_SHORTENED", http.StatusBadRequest, "a pot's lock can be extended but not shortened or removed"},
+	{"INSUFFICIENT_POT_FUNDS", http.StatusBadRequest, "insufficient funds in pot"},
+	{"TOO_MANY_POTS", http.StatusBadRequest, "pot limit reached"},
+
+	// Auto top-up
+	{"INVALID_THRESHOLD", http.StatusBadRequest, "threshold cannot be negative"},
+	{"INVALID_TOP_UP_CAP", http.StatusBadRequest, "caps cannot be negative or below the top-up amount"},
+	{"NO_DEFAULT_CARD", http.StatusBadRequest, "set a default card or choose one for auto top-up"},
+	{"AUTO_TOP_UP_NOT_FOUND", http.StatusNotFound, "auto top-up is not set up"},
+	{"UNSUPPORTED_CURRENCY", http.StatusBadRequest, "no exchange rate for this currency"},
+
+	// Bulk operations
+	{"BULK_SOURCE_REQUIRED", http.StatusBadRequest, "upload a CSV file of IDs or give a filter, not both"},
+	{"BULK_FILTER_EMPTY", http.StatusBadRequest, "filter must narrow the targets by at least one field"},
+	{"INVALID_CSV", http.StatusBadRequest, "CSV must have one numeric ID per row"},
+	{"BULK_NO_TARGETS", http.StatusBadRequest, "no targets to apply the operation to"},
+	{"BULK_TOO_MANY_TARGETS", http.StatusBadRequest, "too many targets for one bulk operation"},
+	{"BULK_OPERATION_NOT_FOUND", http.StatusNotFound, "bulk operation not found"},
+
+	// Merchant risk
+	{"MERCHANT_NOT_RESTRICTED", http.StatusConflict, "merchant has no risk restrictions to clear"},
+	{"MERCHANT_RISK_BUSY", http.StatusConflict, "merchant is being assessed, try again"},
+
+	// Split bills
+	{"SPLIT_NOT_FOUND", http.StatusNotFound, "split not found"},
+	{"PARTICIPANT_NOT_FOUND", http.StatusNotFound, "participant not found"},
+	{"NOT_ORGANIZER", http.StatusForbidden, "only the organizer can do this"},
+	{"NOT_PARTICIPANT", http.StatusForbidden, "you have no share in this split"},
+	{"TRANSACTION_NOT_OWNED", http.StatusForbidden, "only bills you paid can be split"},
+	{"ALREADY_SPLIT", http.StatusConflict, "transaction has already been split"},
+	{"SHARE_ALREADY_PAID", http.StatusConflict, "share has already been paid"},
+	{"SPLIT_NOT_PAYABLE", http.StatusConflict, "split is no longer accepting payments"},
+	{"SPLIT_NOT_CANCELLABLE", http.StatusConflict, "splits can only be cancelled before anyone pays"},
+	{"TITLE_REQUIRED", http.StatusBadRequest, "title is required"},
+	{"NO_PARTICIPANTS", http.StatusBadRequest, "at least one participant is required"},
+	{"TOO_MANY_PARTICIPANTS", http.StatusBadRequest, "too many participants"},
+	{"DUPLICATE_PARTICIPANT", http.StatusBadRequest, "participant listed more than once"},
+	{"ORGANIZER_IS_PARTICIPANT", http.StatusBadRequest, "organizer cannot be a participant"},
+	{"MIXED_SHARES", http.StatusBadRequest, "give every participant an amount or none"},
+	{"SHARES_EXCEED_TOTAL", http.StatusBadRequest, "shares exceed the split total"},
+
+	// Escrow
+	{"ESCROW_NOT_FOUND", http.StatusNotFound, "escrow not found"},
+	{"SELLER_NOT_FOUND", http.StatusNotFound, "seller not found"},
+	{"NOT_BUYER", http.StatusForbidden, "only the buyer can do this"},
+	{"NOT_SELLER", http.StatusForbidden, "only the seller can do this"},
+	{"ESCROW_NOT_OPEN", http.StatusConflict, "escrow has already been settled"},
+	{"ESCROW_DISPUTED", http.StatusConflict, "escrow is under dispute"},
+	{"ESCROW_NOT_DISPUTED", http.StatusConflict, "escrow is not disputed"},
+	{"ESCROW_ALREADY_DISPUTED", http.StatusConflict, "escrow is already disputed"},
+	{"SELF_ESCROW", http.StatusBadRequest, "you cannot open an escrow with yourself"},
+	{"DESCRIPTION_REQUIRED", http.StatusBadRequest, "description is required"},
+	{"INVALID_RELEASE_DAYS", http.StatusBadRequest, "release period is out of range"},
+	{"REASON_REQUIRED", http.StatusBadRequest, "reason is required"},
+
+	// Disputes and chargebacks
+	{"DISPUTE_NOT_FOUND", http.StatusNotFound, "dispute not found"},
+	{"EVIDENCE_NOT_FOUND", http.StatusNotFound, "dispute evidence not found"},
+	{"CHARGEBACK_NOT_FOUND", http.StatusNotFound, "chargeback not found"},
+	{"NOT_COUNTERPARTY", http.StatusForbidden, "only the merchant can respond to this dispute"},
+	{"DISPUTE_CLOSED", http.StatusConflict, "dispute has already been resolved"},
+	{"INVALID_DISPUTE_TRANSITION", http.StatusConflict, "dispute is not in a state that allows this"},
+	{"TOO_MUCH_EVIDENCE", http.StatusConflict, "evidence limit reached for this dispute"},
+	{"CHARGEBACK_CLOSED", http.StatusConflict, "chargeback has already been settled"},
+	{"ALREADY_REPRESENTED", http.StatusConflict, "chargeback has already been represented"},
+	{"REPRESENTMENT_CLOSED", http.StatusConflict, "representment deadline has passed"},
+	{"FILE_TOO_LARGE", http.StatusRequestEntityTooLarge, "evidence file is too large"},
+	{"EMPTY_FILE", http.StatusBadRequest, "evidence file is empty"},
+	{"INVALID_FILE_TYPE", http.StatusBadRequest, "evidence must be a PDF, PNG or JPEG file"},
+	{"RESPONSE_REQUIRED", http.StatusBadRequest, "response is required"},
+	{"INVALID_DEADLINE", http.StatusBadRequest, "response deadline is out of range"},
+	{"INVALID_REFUND", http.StatusBadRequest, "refund amount must be positive and no more than the transaction amount"},
+	{"NO_COUNTERPARTY", http.StatusBadRequest, "dispute has no counterparty to refund from"},
+	{"ESCROW_DISPUTE", http.StatusBadRequest, "escrow disputes are settled through the escrow"},
+	{"INVALID_DISPUTE_CATEGORY", http.StatusBadRequest, "category must be unauthorized, not_received, duplicate or other"},
+	{"INVALID_DISPUTE_AMOUNT", http.StatusBadRequest, "disputed amount must be positive and no more than the transaction amount"},
+	{"TOO_MANY_ATTACHMENTS", http.StatusBadRequest, "a dispute can reference at most 10 attachments"},
+	{"REPRESENTMENT_REQUIRED", http.StatusBadRequest, "representment note is required"},
+	{"INVALID_CHARGEBACK_AMOUNT", http.StatusBadRequest, "chargeback amount must be positive and no more than the transaction amount"},
+	{"INVALID_CHARGEBACK_OUTCOME", http.StatusBadRequest, "outcome must be won or lost"},
+
+	// Bank funding
+	{"BANK_ACCOUNT_NOT_FOUND", http.StatusNotFound, "bank account not found"},
+	{"DEPOSIT_NOT_FOUND", http.StatusNotFound, "deposit not found"},
+	{"INVALID_ACCOUNT_DETAILS", http.StatusBadRequest, "invalid bank account details"},
+	{"INVALID_PUBLIC_TOKEN", http.StatusBadRequest, "invalid public token"},
+	{"BANK_ACCOUNT_NOT_VERIFIED", http.StatusBadRequest, "bank account is not verified"},
+	{"BANK_ACCOUNT_ALREADY_VERIFIED", http.StatusBadRequest, "bank account is already verified"},
+	{"VERIFICATION_FAILED", http.StatusBadRequest, "micro-deposit amounts do not match"},
+	{"TOO_MANY_VERIFICATION_ATTEMPTS", http.StatusBadRequest, "too many verification attempts"},
+	{"INVALID_DEPOSIT_EVENT", http.StatusBadRequest, "invalid deposit event"},
+	{"BANK_TRANSFER_FAILED", http.StatusBadGateway, "bank transfer failed"},
+
+	// Virtual cards
+	{"VIRTUAL_CARD_NOT_FOUND", http.StatusNotFound, "virtual card not found"},
+	{"INVALID_CARD_TYPE", http.StatusBadRequest, "invalid card type"},
+	{"INVALID_SPEND_LIMIT", http.StatusBadRequest, "invalid spend limit"},
+	{"CARD_CLOSED", http.StatusBadRequest, "virtual card is closed"},
+	{"CARD_ALREADY_FROZEN", http.StatusBadRequest, "virtual card is already frozen"},
+	{"CARD_NOT_FROZEN", http.StatusBadRequest, "virtual card is not frozen"},
+	{"WALLET_NOT_ACTIVE", http.StatusBadRequest, "wallet is not active"},
+	{"TOO_MANY_ACTIVE_CARDS", http.StatusBadRequest, "active virtual card limit reached"},
+
+	// Scheduled exports
+	{"EXPORT_SCHEDULE_NOT_FOUND", http.StatusNotFound, "export schedule not found"},
+	{"INVALID_DAY_OF_MONTH", http.StatusBadRequest, "day of month must be between 1 and 28"},
+	{"INVALID_DAY_OF_WEEK", http.StatusBadRequest, "day of week must be between 1 (Monday) and 7 (Sunday)"},
+	{"INVALID_FREQUENCY", http.StatusBadRequest, "frequency must be weekly or monthly"},
+	{"INVALID_WEBHOOK_URL", http.StatusBadRequest, "webhook URL must be an https URL"},
+	{"INVALID_DESTINATION", http.StatusBadRequest, "destination must be email, storage or webhook"},
+	{"UNKNOWN_CONNECTOR", http.StatusBadRequest, "unknown storage connector"},
+	{"STORAGE_PATH_REQUIRED", http.StatusBadRequest, "storage path is required for storage exports"},
+	{"TOO_MANY_SCHEDULES", http.StatusBadRequest, "export schedule limit reached"},
+
+	// Handles
+	{"INVALID_HANDLE", http.StatusBadRequest, "handles are 3-20 characters of lowercase letters, digits, dots or underscores, starting with a letter"},
+	{"RESERVED_HANDLE", http.StatusBadRequest, "handle is reserved"},
+	{"NO_HANDLE", http.StatusBadRequest, "you have not claimed a handle"},
+	{"INVALID_IDENTIFIER", http.StatusBadRequest, "enter an @handle or phone number"},
+
+	// Treasury
+	{"TREASURY_TRANSFER_NOT_FOUND", http.StatusNotFound, "treasury transfer not found"},
+	{"UNKNOWN_SYSTEM_ACCOUNT", http.StatusNotFound, "unknown system account"},
+	{"SELF_APPROVAL", http.StatusForbidden, "a transfer must be reviewed by someone other than its requester"},
+	{"TREASURY_TRANSFER_NOT_PENDING", http.StatusConflict, "treasury transfer is not pending"},
+	{"INSUFFICIENT_SYSTEM_FUNDS", http.StatusConflict, "insufficient funds in source account"},
+	{"SAME_ACCOUNT", http.StatusBadRequest, "cannot transfer to the same account"},
+	{"CUSTODIAL_ACCOUNT", http.StatusBadRequest, "custodial accounts hold customer funds and can't be used in treasury transfers"},
+
+	// User administration
+	{"UNKNOWN_ROLE", http.StatusBadRequest, "unknown role"},
+	{"SUPER_ADMIN_ROLE", http.StatusForbidden, "only super admins can change super admin roles"},
+	{"SELF_ADMIN_ACTION", http.StatusForbidden, "admins cannot suspend or change the role of their own account"},
+	{"ALREADY_SUSPENDED", http.StatusConflict, "user is already suspended"},
+	{"NOT_SUSPENDED", http.StatusConflict, "user is not suspended"},
+	{"INVALID_NAME", http.StatusBadRequest, "name cannot be empty"},
+
+	// Roles and permissions
+	{"ROLE_NOT_FOUND", http.StatusNotFound, "role not found"},
+	{"UNKNOWN_PERMISSION", http.StatusBadRequest, "unknown permission"},
+	{"INVALID_ROLE_NAME", http.StatusBadRequest, "role name must be 3-50 lowercase letters, digits or underscores, starting with a letter"},
+	{"ROLE_EXISTS", http.StatusConflict, "role already exists"},
+	{"SYSTEM_ROLE", http.StatusForbidden, "built-in roles can't be changed"},
+	{"ROLE_IN_USE", http.StatusConflict, "role is assigned to users"},
+	{"NO_PERMISSIONS", http.StatusBadRequest, "a role must grant at least one permission"},
+	{"CANNOT_GRANT_PERMISSION", http.StatusForbidden, "you can't grant a permission you don't hold"},
+
+	// Enterprise organizations
+	{"ORGANIZATION_NOT_FOUND", http.StatusNotFound, "organization not found"},
+	{"ORGANIZATION_ROLE_FORBIDDEN", http.StatusForbidden, "your role in this organization does not allow this"},
+	{"INVALID_ORGANIZATION_ROLE", http.StatusBadRequest, "role must be owner, admin, finance or member"},
+	{"ORGANIZATION_EXISTS", http.StatusConflict, "you have already created an organization"},
+	{"REGISTRATION_NO_REQUIRED", http.StatusBadRequest, "company registration number is required"},
+	{"REGISTRATION_NO_TAKEN", http.StatusConflict, "company registration number is already registered"},
+	{"INVALID_BILLING_CYCLE", http.StatusBadRequest, "billing cycle must be monthly or yearly"},
+	{"LAST_ORGANIZATION_OWNER", http.StatusConflict, "an organization must keep at least one owner"},
+	{"OWNER_ROLE_REQUIRED", http.StatusForbidden, "only an owner can grant or change the owner role"},
+	{"ORGANIZATION_WALLET_NOT_FOUND", http.StatusNotFound, "organization wallet not found"},
+	{"ORGANIZATION_WALLET_INACTIVE", http.StatusConflict, "organization wallet is not active"},
+	{"INSUFFICIENT_ORGANIZATION_FUNDS", http.StatusBadRequest, "insufficient funds in organization wallet"},
+	{"MEMBER_MONTHLY_LIMIT", http.StatusForbidden, "payment exceeds your monthly spending limit"},
+	{"INVALID_STATEMENT_MONTH", http.StatusBadRequest, "month must be formatted YYYY-MM"},
+	{"APPROVAL_RULE_NOT_FOUND", http.StatusNotFound, "approval rule not found"},
+	{"APPROVAL_RULE_NAME_REQUIRED", http.StatusBadRequest, "approval rule name is required"},
+	{"INVALID_APPROVER_ROLE", http.StatusBadRequest, "approver role must be owner, admin or finance"},
+	{"ORGANIZATION_PAYMENT_NOT_FOUND", http.StatusNotFound, "payment not found"},
+	{"ORGANIZATION_PAYMENT_NOT_PENDING", http.StatusConflict, "payment is not pending approval"},
+	{"SELF_REVIEW", http.StatusForbidden, "you cannot review your own payment"},
+	{"NOT_PAYMENT_REQUESTER", http.StatusForbidden, "only the member who requested the payment can cancel it"},
+
+	// Sandbox
+	{"SANDBOX_CUSTOMER_NOT_FOUND", http.StatusNotFound, "sandbox customer not found"},
+	{"SANDBOX_CHARGE_NOT_FOUND", http.StatusNotFound, "sandbox charge not found"},
+	{"INVALID_SANDBOX_SOURCE", http.StatusBadRequest, "source must be wallet, card or bank"},
+	{"SANDBOX_CUSTOMER_REQUIRED", http.StatusBadRequest, "wallet charges need a sandbox customer"},
+	{"CARD_NUMBER_REQUIRED", http.StatusBadRequest, "card charges need a test card number"},
+	{"UNKNOWN_TEST_CARD", http.StatusBadRequest, "card number is not a sandbox test card"},
+	{"UNKNOWN_SANDBOX_SCENARIO", http.StatusBadRequest, "unknown sandbox failure scenario"},
+	{"SANDBOX_CHARGE_NOT_REFUNDABLE", http.StatusConflict, "only succeeded charges can be refunded"},
+	{"SANDBOX_CHARGE_NOT_PENDING", http.StatusConflict, "only pending charges can be settled"},
+	{"INVALID_SANDBOX_OUTCOME", http.StatusBadRequest, "outcome must be succeeded or failed"},
+
+	// Merchant webhooks
+	{"NO_WEBHOOK_URL", http.StatusConflict, "set a webhook URL before sending webhooks"},
+	{"UNKNOWN_WEBHOOK_EVENT", http.StatusBadRequest, "unknown webhook event type"},
+	{"WEBHOOK_DELIVERY_NOT_FOUND", http.StatusNotFound, "webhook delivery not found"},
+
+	// Rate limits
+	{"UNKNOWN_RATE_LIMIT_CLASS", http.StatusBadRequest, "class must be reads, writes or payments"},
+	{"INVALID_RATE_LIMIT_SUBJECT", http.StatusBadRequest, "subject must be a user or merchant ID"},
+	{"INVALID_RATE_LIMIT", http.StatusBadRequest, "limit must be greater than zero"},
+	{"RATE_LIMIT_OVERRIDE_NOT_FOUND", http.StatusNotFound, "rate limit override not found"},
+
+	// Feature flags
+	{"UNKNOWN_FEATURE_FLAG", http.StatusNotFound, "unknown feature flag"},
+	{"FEATURE_FLAG_OVERRIDE_NOT_FOUND", http.StatusNotFound, "feature flag override not found"},
+
+	// Accounting exports
+	{"UNKNOWN_JOURNAL_FORMAT", http.StatusBadRequest, "format must be csv, ofx, quickbooks or xero"},
+	{"INVALID_JOURNAL_DATE", http.StatusBadRequest, "date must be YYYY-MM-DD"},
+	{"JOURNAL_DAY_NOT_OVER", http.StatusBadRequest, "only days that are over can be exported"},
+	{"JOURNAL_EXPORT_BUSY", http.StatusConflict, "this day's journal is already being exported"},
+	{"JOURNAL_DELIVERY_FAILED", http.StatusBadGateway, "journal delivery failed"},
+
+	// Compliance reports
+	{"UNKNOWN_REPORT_TYPE", http.StatusBadRequest, "type must be ctr or sar"},
+	{"UNKNOWN_REPORT_STATUS", http.StatusBadRequest, "status must be draft, in_review, filed or dismissed"},
+	{"FILING_REFERENCE_REQUIRED", http.StatusBadRequest, "a filing reference is required to file a report"},
+	{"REVIEW_NOTE_REQUIRED", http.StatusBadRequest, "a note is required to dismiss a report"},
+	{"COMPLIANCE_REPORT_NOT_FOUND", http.StatusNotFound, "compliance report not found"},
+	{"COMPLIANCE_REPORT_CLOSED", http.StatusConflict, "compliance report was already filed or dismissed"},
+
+	// Tax summaries
+	{"UNKNOWN_TAX_SUMMARY_FORMAT", http.StatusBadRequest, "format must be json, csv or pdf"},
+	{"INVALID_TAX_YEAR", http.StatusBadRequest, "year must be between 2000 and the current year"},
+	{"TAX_SUMMARY_NOT_READY", http.StatusConflict, "tax summary is still being generated"},
+	{"TAX_SUMMARY_FAILED", http.StatusInternalServerError, "tax summary could not be generated"},
+	{"TAX_SUMMARY_NOT_FOUND", http.StatusNotFound, "tax summary report not found"},
+
+	// Pending transactions
+	{"INVALID_PENDING_AGE", http.StatusBadRequest, "older_than must be a positive duration such as 30m or 2h"},
+
+	// Merchant reserves
+	{"INVALID_RESERVE_PERCENT", http.StatusBadRequest, "reserve percent must be at least 0 and below 1"},
+	{"INVALID_RESERVE_HOLD_DAYS", http.StatusBadRequest, "reserve hold days must be between 1 and 365"},
+
+	// Payment confirmations
+	{"INVALID_CONFIRMATION_CHANNEL", http.StatusBadRequest, "channel must be email or push"},
+
+	// Spending controls
+	{"INVALID_SPENDING_SCOPE", http.StatusBadRequest, "scope must be category or foreign"},
+	{"INVALID_SPENDING_CATEGORY", http.StatusBadRequest, "category must be gambling, crypto, adult, alcohol or tobacco"},
+	{"INVALID_SPENDING_ACTION", http.StatusBadRequest, "action must be block or cap"},
+	{"INVALID_SPENDING_CAP", http.StatusBadRequest, "a cap needs an amount greater than zero and a daily or monthly period"},
+	{"SPENDING_CONTROL_NOT_FOUND", http.StatusNotFound, "spending control not found"},
+	{"SPENDING_CONTROL_MANAGED", http.StatusForbidden, "this control was set by your organization and only its admins can change it"},
+	{"MERCHANT_CATEGORY_BLOCKED", http.StatusForbidden, "payments to this merchant category are blocked by your spending controls"},
+	{"MERCHANT_CATEGORY_CAP_EXCEEDED", http.StatusForbidden, "payment exceeds your spending cap for this merchant category"},
+	{"FOREIGN_MERCHANT_BLOCKED", http.StatusForbidden, "payments to foreign merchants are blocked by your spending controls"},
+	{"FOREIGN_MERCHANT_CAP_EXCEEDED", http.StatusForbidden, "payment exceeds your spending cap for foreign merchants"},
+
+	// Trusted device confirmation of large transfers
+	{"TRUSTED_DEVICE_REQUIRED", http.StatusForbidden, "transfers of this size must be approved on a trusted device; register one first"},
+	{"DEVICE_ID_REQUIRED", http.StatusBadRequest, "the X-Device-ID header is required"},
+	{"UNKNOWN_DEVICE", http.StatusForbidden, "sign in on this device before trusting it"},
+	{"DEVICE_NOT_TRUSTED", http.StatusForbidden, "transfers can only be approved or denied on one of your trusted devices"},
+	{"TRUSTED_DEVICE_NOT_FOUND", http.StatusNotFound, "trusted device not found"},
+	{"TRANSFER_CONFIRMATION_NOT_FOUND", http.StatusNotFound, "transfer confirmation not found"},
+	{"TRANSFER_CONFIRMATION_CLOSED", http.StatusConflict, "transfer is no longer awaiting confirmation"},
+	{"TRANSFER_CONFIRMATION_EXPIRED", http.StatusGone, "the time to approve this transfer has passed"},
+
+	// Customer approval of merchant scans
+	{"CHARGE_CONFIRMATION_NOT_FOUND", http.StatusNotFound, "charge confirmation not found"},
+	{"CHARGE_CONFIRMATION_CLOSED", http.StatusConflict, "charge is no longer awaiting confirmation"},
+	{"CHARGE_CONFIRMATION_EXPIRED", http.StatusGone, "the time to approve this charge has passed"},
+
+	// Customer refund requests
+	{"REFUND_REQUESTS_NOT_ACCEPTED", http.StatusForbidden, "this merchant doesn't accept refund requests"},
+	{"REFUND_WINDOW_CLOSED", http.StatusUnprocessableEntity, "the time to ask for a refund of this charge has passed"},
+	{"REFUND_ALREADY_REQUESTED", http.StatusConflict, "a refund of this charge is already in progress"},
+	{"REFUND_REQUEST_NOT_FOUND", http.StatusNotFound, "refund request not found"},
+	{"REFUND_REQUEST_CLOSED", http.StatusConflict, "refund request is no longer pending"},
+
+	// Payment PINs
+	{"PAYMENT_PIN_NOT_SET", http.StatusNotFound, "you have not set a payment PIN"},
+	{"PAYMENT_PIN_ALREADY_SET", http.StatusConflict, "you already have a payment PIN; change or reset it instead"},
+	{"PAYMENT_PIN_REQUIRED", http.StatusUnauthorized, "enter your payment PIN to continue"},
+	{"WRONG_PAYMENT_PIN", http.StatusUnauthorized, "incorrect payment PIN"},
+	{"PAYMENT_PIN_LOCKED", http.StatusTooManyRequests, "too many incorrect payment PINs, try again later"},
+	{"WRONG_PASSWORD", http.StatusUnauthorized, "incorrect password"},
+
+	// Sagas
+	{"SAGA_NOT_ATTENTION", http.StatusConflict, "only sagas that require attention can be retried"},
+	{"SAGA_NOT_FOUND", http.StatusNotFound, "saga not found"},
+
+	// Maintenance
+	{CodeMaintenance, http.StatusServiceUnavailable, "the platform is under maintenance, try again later"},
+	{"MAINTENANCE_NOT_ENABLED", http.StatusConflict, "maintenance mode is not enabled"},
+	{"INVALID_RETRY_AFTER", http.StatusBadRequest, "retry_after must be between 0 and 86400 seconds"},
+	{"MAINTENANCE_ENDS_IN_PAST", http.StatusBadRequest, "ends_at must be in the future"},
+}
+
+var byCode = func() map[string]Definition {
+	m := make(map[string]Definition, len(definitions))
+	for _, d := range definitions {
+		m[d.Code] = d
+	}
+	return m
+}()
+
+// Lookup returns the definition of a code
+func Lookup(code string) (Definition, bool) {
+	d, ok := byCode[code]
+	return d, ok
+}
+
+// Definitions returns every documented code, grouped by feature
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
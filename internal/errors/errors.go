@@ -1,5 +1,11 @@
+// Package errors defines the API's error codes. Each failed request gets an
+// envelope with a stable, machine-readable code from the catalog, the HTTP
+// status that code is documented with, and a human-readable message in the
+// client's language. Clients should branch on the code; messages can change.
 package errors
 
+import "strings"
+
 type DomainError struct {
 	Code    string
 	Message string
@@ -12,3 +18,54 @@ func (e *DomainError) Error() string {
 	}
 	return e.Message
 }
+
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches domain errors by code, so a wrapped or rebuilt error still
+// matches the sentinel
+func (e *DomainError) Is(target error) bool {
+	t, ok := target.(*DomainError)
+	return ok && t.Code == e.Code
+}
+
+// StatusError gives an error without a code of its own the HTTP status to
+// report it with
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+// WithStatus reports err with status unless it carries a known code
+func WithStatus(status int, err error) error {
+	return &StatusError{Status: status, Err: err}
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError says why one field of a request body is invalid. Field is its
+// JSON path, such as "address.city".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a request body
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + " " + f.Message
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,80 @@
+package errors
+
+import "orus/internal/i18n"
+
+// DefaultLanguage is the language error messages are written in
+const DefaultLanguage = i18n.DefaultLanguage
+
+// Languages lists the languages error messages are available in
+var Languages = i18n.Languages
+
+// translations holds messages in languages other than English. A code
+// missing here keeps its English message.
+var translations = map[string]map[string]string{
+	"fr": {
+		CodeBadRequest:         "La requête est invalide",
+		CodeUnauthorized:       "Authentification requise",
+		CodeForbidden:          "Vous n'êtes pas autorisé à effectuer cette action",
+		CodeNotFound:           "Ressource introuvable",
+		CodeRateLimited:        "Trop de requêtes, réessayez plus tard",
+		CodeInternal:           "Erreur interne du serveur",
+		CodeUpstream:           "Un fournisseur externe a échoué",
+		CodeServiceUnavailable: "Service indisponible",
+		CodeTimeout:            "La requête a pris trop de temps, réessayez plus tard",
+		CodeInvalidCredentials: "Email ou mot de passe incorrect",
+		CodeInvalidToken:       "Jeton invalide ou expiré",
+		CodeAccountSuspended:   "Ce compte est suspendu",
+		CodeValidationFailed:   "Certains champs de la requête sont invalides",
+
+		"INVALID_AMOUNT":             "Montant invalide",
+		"INSUFFICIENT_BALANCE":       "Solde insuffisant",
+		"DAILY_LIMIT_EXCEEDED":       "Plafond journalier dépassé",
+		"MONTHLY_LIMIT_EXCEEDED":     "Plafond mensuel dépassé",
+		"TRANSACTION_LIMIT_EXCEEDED": "Plafond de transaction dépassé",
+		"LIMIT_EXCEEDED":             "Plafond dépassé",
+		"WALLET_NOT_FOUND":           "Portefeuille introuvable",
+		"WALLET_LOCKED":              "Le portefeuille est bloqué",
+		"WALLET_NOT_ACTIVE":          "Le portefeuille n'est pas actif",
+		"USER_NOT_FOUND":             "Utilisateur introuvable",
+		"RECIPIENT_NOT_FOUND":        "Destinataire introuvable",
+		"EMAIL_TAKEN":                "Cet email est déjà utilisé",
+		"PHONE_TAKEN":                "Ce numéro de téléphone est déjà utilisé",
+		"HANDLE_TAKEN":               "Cet identifiant est déjà pris",
+		"CARD_NOT_FOUND":             "Carte bancaire introuvable",
+		"MERCHANT_NOT_FOUND":         "Profil marchand introuvable",
+		"MERCHANT_INACTIVE":          "Le marchand n'est pas actif",
+		"NOT_MERCHANT":               "Profil marchand introuvable",
+		"TRANSACTION_NOT_FOUND":      "Transaction introuvable",
+		"HIGH_RISK_TRANSACTION":      "Transaction refusée : risque trop élevé",
+		"PAYMENT_DECLINED":           "Paiement refusé par les règles antifraude",
+		"INVALID_QR":                 "QR code invalide",
+		"QR_EXPIRED":                 "Le QR code a expiré",
+		"QR_INACTIVE":                "Le QR code n'est pas actif",
+		"QR_LIMIT_EXCEEDED":          "Limite d'utilisation du QR code atteinte",
+		"NOT_BENEFICIARY":            "Ajoutez le destinataire à vos contacts avant de lui envoyer de l'argent pour la première fois",
+		"COOLING_OFF":                "Les nouveaux bénéficiaires ne peuvent pas être payés avant la fin du délai de sécurité",
+		"CHECKOUT_SESSION_EXPIRED":   "La session de paiement a expiré",
+		"PAYMENT_LINK_UNAVAILABLE":   "Ce lien de paiement n'est plus disponible",
+		"INVOICE_NOT_PAYABLE":        "Cette facture ne peut pas être payée",
+		"PROMO_CODE_NOT_FOUND":       "Code promo invalide",
+		"PROMO_CODE_EXPIRED":         "Ce code promo n'est plus disponible",
+		"INSUFFICIENT_POINTS":        "Points de fidélité insuffisants",
+		"INSUFFICIENT_POT_FUNDS":     "Fonds insuffisants dans la cagnotte",
+		"INSUFFICIENT_SHARED_FUNDS":  "Fonds insuffisants dans le portefeuille partagé",
+		"WRONG_PIN":                  "Code PIN incorrect",
+		"PIN_LOCKED":                 "Trop de codes PIN incorrects, réessayez plus tard",
+		"BANK_TRANSFER_FAILED":       "Le virement bancaire a échoué",
+	},
+}
+
+// Message returns the message for code in lang, if there is a translation
+func Message(code, lang string) (string, bool) {
+	msg, ok := translations[lang][code]
+	return msg, ok
+}
+
+// Language picks the supported language a client prefers from its
+// Accept-Language header; see i18n.Match
+func Language(acceptLanguage string) string {
+	return i18n.Match(acceptLanguage)
+}
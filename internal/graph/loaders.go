@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait is how long a loader collects keys before querying, so that
+// sibling fields resolved concurrently share one batch
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// Loaders batch lookups made while resolving a single request. They're
+// created per request so cached results never leak between users.
+type Loaders struct {
+	Users     *dataloader.Loader[uint, *models.User]
+	Merchants *dataloader.Loader[uint, *models.Merchant]
+}
+
+// NewLoaders creates request-scoped loaders over the repositories
+func NewLoaders(users repositories.UserRepository, merchants repositories.MerchantRepository) *Loaders {
+	return &Loaders{
+		Users: dataloader.NewBatchedLoader(batchUsers(users),
+			dataloader.WithWait[uint, *models.User](loaderWait)),
+		Merchants: dataloader.NewBatchedLoader(batchMerchants(merchants),
+			dataloader.WithWait[uint, *models.Merchant](loaderWait)),
+	}
+}
+
+// Middleware gives every request its own loaders
+func Middleware(users repositories.UserRepository, merchants repositories.MerchantRepository, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, NewLoaders(users, merchants))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func loadersFor(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
+
+// batchUsers loads users by ID; a missing user resolves to nil
+func batchUsers(repo repositories.UserRepository) dataloader.BatchFunc[uint, *models.User] {
+	return func(ctx context.Context, ids []uint) []*dataloader.Result[*models.User] {
+		users, err := repo.GetByIDs(ids)
+		byID := make(map[uint]*models.User, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
+		results := make([]*dataloader.Result[*models.User], len(ids))
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*models.User]{Data: byID[id], Error: err}
+		}
+		return results
+	}
+}
+
+// batchMerchants loads merchant profiles by their owner's user ID; a user
+// without one resolves to nil
+func batchMerchants(repo repositories.MerchantRepository) dataloader.BatchFunc[uint, *models.Merchant] {
+	return func(ctx context.Context, userIDs []uint) []*dataloader.Result[*models.Merchant] {
+		merchants, err := repo.GetByUserIDs(userIDs)
+		byUser := make(map[uint]*models.Merchant, len(merchants))
+		for i := range merchants {
+			byUser[merchants[i].UserID] = &merchants[i]
+		}
+		results := make([]*dataloader.Result[*models.Merchant], len(userIDs))
+		for i, id := range userIDs {
+			results[i] = &dataloader.Result[*models.Merchant]{Data: byUser[id], Error: err}
+		}
+		return results
+	}
+}
@@ -0,0 +1,80 @@
+// Package graph is the GraphQL gateway for client apps. It reads through
+// the same repositories and services as the REST API, batching per-request lookups with
+// dataloaders so lists don't turn into one query per item.
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate --config gqlgen.yml
+
+import (
+	"context"
+	"errors"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/wallet"
+)
+
+const (
+	defaultRecentTransactions = 10
+	maxRecentTransactions     = 50
+)
+
+// errUnauthenticated is returned when a request reaches the gateway
+// without the claims the auth middleware adds
+var errUnauthenticated = errors.New("authentication is required")
+
+// Resolver holds the repositories and services the gateway reads from
+type Resolver struct {
+	users        repositories.UserRepository
+	wallets      repositories.WalletRepository
+	balances     wallet.Service
+	merchants    repositories.MerchantRepository
+	transactions repositories.TransactionRepository
+	qrCodes      repositories.QRCodeRepository
+}
+
+// NewResolver creates a new Resolver
+func NewResolver(
+	users repositories.UserRepository,
+	wallets repositories.WalletRepository,
+	balances wallet.Service,
+	merchants repositories.MerchantRepository,
+	transactions repositories.TransactionRepository,
+	qrCodes repositories.QRCodeRepository,
+) *Resolver {
+	return &Resolver{
+		users:        users,
+		wallets:      wallets,
+		balances:     balances,
+		merchants:    merchants,
+		transactions: transactions,
+		qrCodes:      qrCodes,
+	}
+}
+
+// Wallet is the user's wallet with its balance split the way the REST
+// balance endpoint reports it
+type Wallet struct {
+	ID        uint
+	Balance   float64
+	Available float64
+	Held      float64
+	Currency  string
+	Status    string
+}
+
+// Counterparty is the public profile of the other side of a transaction
+type Counterparty struct {
+	ID           uint
+	Name         string
+	Handle       *string
+	BusinessName *string
+}
+
+// viewer returns the claims of the authenticated user making the request
+func viewer(ctx context.Context) (*models.UserClaims, error) {
+	claims, ok := ctx.Value("claims").(*models.UserClaims)
+	if !ok {
+		return nil, errUnauthenticated
+	}
+	return claims, nil
+}
@@ -0,0 +1,133 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.66
+
+import (
+	"context"
+	"errors"
+	"orus/internal/models"
+	"orus/internal/repositories"
+)
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*models.User, error) {
+	claims, err := viewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.users.GetByID(claims.UserID)
+}
+
+// Direction is the resolver for the direction field.
+func (r *transactionResolver) Direction(ctx context.Context, obj *models.Transaction) (string, error) {
+	claims, err := viewer(ctx)
+	if err != nil {
+		return "", err
+	}
+	if obj.SenderID == claims.UserID {
+		return "sent", nil
+	}
+	return "received", nil
+}
+
+// Counterparty is the resolver for the counterparty field.
+func (r *transactionResolver) Counterparty(ctx context.Context, obj *models.Transaction) (*Counterparty, error) {
+	claims, err := viewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	otherID := obj.SenderID
+	if obj.SenderID == claims.UserID {
+		otherID = obj.ReceiverID
+	}
+
+	// Queue both lookups before waiting so they join the same batches
+	loaders := loadersFor(ctx)
+	userThunk := loaders.Users.Load(ctx, otherID)
+	merchantThunk := loaders.Merchants.Load(ctx, otherID)
+
+	user, err := userThunk()
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+	merchant, err := merchantThunk()
+	if err != nil {
+		return nil, err
+	}
+
+	party := &Counterparty{ID: user.ID, Name: user.Name, Handle: user.Handle}
+	if merchant != nil {
+		party.BusinessName = &merchant.BusinessName
+	}
+	return party, nil
+}
+
+// Wallet is the resolver for the wallet field.
+func (r *userResolver) Wallet(ctx context.Context, obj *models.User) (*Wallet, error) {
+	wallet, err := r.wallets.GetByUserID(obj.ID)
+	if errors.Is(err, repositories.ErrWalletNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Holds count against what the user can spend, as on the REST API
+	details, err := r.balances.GetBalanceDetails(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		ID:        details.WalletID,
+		Balance:   details.Total,
+		Available: details.Available,
+		Held:      details.Held,
+		Currency:  details.Currency,
+		Status:    wallet.Status,
+	}, nil
+}
+
+// Merchant is the resolver for the merchant field.
+func (r *userResolver) Merchant(ctx context.Context, obj *models.User) (*models.Merchant, error) {
+	return loadersFor(ctx).Merchants.Load(ctx, obj.ID)()
+}
+
+// RecentTransactions is the resolver for the recentTransactions field.
+func (r *userResolver) RecentTransactions(ctx context.Context, obj *models.User, limit *int) ([]*models.Transaction, error) {
+	n := defaultRecentTransactions
+	if limit != nil && *limit > 0 {
+		n = min(*limit, maxRecentTransactions)
+	}
+	transactions, _, err := r.transactions.GetUserTransactions(obj.ID, n, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Transaction, len(transactions))
+	for i := range transactions {
+		result[i] = &transactions[i]
+	}
+	return result, nil
+}
+
+// QRCodes is the resolver for the qrCodes field.
+func (r *userResolver) QRCodes(ctx context.Context, obj *models.User) ([]*models.QRCode, error) {
+	return r.qrCodes.GetQRCodesByUserID(ctx, obj.ID, "")
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Transaction returns TransactionResolver implementation.
+func (r *Resolver) Transaction() TransactionResolver { return &transactionResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type queryResolver struct{ *Resolver }
+type transactionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
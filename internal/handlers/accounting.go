@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/accounting"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccountingHandler lets admins download the daily accounting journals,
+// review their exports and export a day again.
+type AccountingHandler struct {
+	service accounting.Service
+}
+
+// NewAccountingHandler creates a new AccountingHandler.
+func NewAccountingHandler(s accounting.Service) *AccountingHandler {
+	return &AccountingHandler{service: s}
+}
+
+// GetAccounts returns the chart of accounts the journals post to.
+func (h *AccountingHandler) GetAccounts(c *fiber.Ctx) error {
+	return response.Success(c, "accounts retrieved", h.service.Accounts())
+}
+
+// GetJournal returns a day's journal: as JSON by default, or as a file
+// with ?format=csv, ofx, quickbooks or xero.
+func (h *AccountingHandler) GetJournal(c *fiber.Ctx) error {
+	date, err := accounting.ParseDate(c.Params("date"))
+	if err != nil {
+		return err
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		journal, err := h.service.Journal(c.Context(), date)
+		if err != nil {
+			return err
+		}
+		return response.Success(c, "journal retrieved", journal)
+	}
+
+	file, err := h.service.Render(c.Context(), date, format)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.Name))
+	return c.Send(file.Content)
+}
+
+// ListExports pages through the journals exported, newest day first.
+// ?format narrows it to one format.
+func (h *AccountingHandler) ListExports(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	exports, total, err := h.service.ListExports(c.Context(), c.Query("format"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, exports))
+}
+
+// Export renders and delivers a day's journal again.
+func (h *AccountingHandler) Export(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[accounting.ExportRequest](c)
+
+	export, err := h.service.Export(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "journal exported", export)
+}
@@ -1,31 +1,63 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"orus/internal/models"
 	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/useradmin"
 	"strconv"
+	"time"
 
 	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func GetUsersPaginated(c *fiber.Ctx) error {
+// AdminHandler groups the admin operations
+type AdminHandler struct {
+	userAdmin       useradmin.Service
+	userRepo        repositories.UserRepository
+	walletRepo      repositories.WalletRepository
+	cardRepo        repositories.CreditCardRepository
+	transactionRepo repositories.TransactionRepository
+	invalidator     *cache.Invalidator
+}
+
+func NewAdminHandler(
+	userAdmin useradmin.Service,
+	userRepo repositories.UserRepository,
+	walletRepo repositories.WalletRepository,
+	cardRepo repositories.CreditCardRepository,
+	transactionRepo repositories.TransactionRepository,
+	invalidator *cache.Invalidator,
+) *AdminHandler {
+	return &AdminHandler{
+		userAdmin:       userAdmin,
+		userRepo:        userRepo,
+		walletRepo:      walletRepo,
+		cardRepo:        cardRepo,
+		transactionRepo: transactionRepo,
+		invalidator:     invalidator,
+	}
+}
+
+func (h *AdminHandler) GetUsersPaginated(c *fiber.Ctx) error {
 	// Verify admin permissions
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok || !claims.HasPermission(models.PermissionReadAdmin) {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied. Admin privileges required",
-		})
+		return response.Error(c, fiber.StatusForbidden, "Access denied. Admin privileges required")
 	}
 
 	p := pagination.ParseFromRequest(c)
 
-	users, total, err := repositories.GetUsersPaginated(p.Limit, p.Offset)
+	users, total, err := h.userRepo.List(p.Offset, p.Limit)
 	if err != nil {
 		log.Printf("Error fetching paginated users: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch users"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch users")
 	}
 
 	p.Total = total
@@ -33,23 +65,19 @@ func GetUsersPaginated(c *fiber.Ctx) error {
 }
 
 // GetAllWallets retrieves all wallets in a paginated manner (Admin only)
-func GetAllWallets(c *fiber.Ctx) error {
+func (h *AdminHandler) GetAllWallets(c *fiber.Ctx) error {
 	// Verify admin permissions
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok || !claims.HasPermission(models.PermissionReadAdmin) {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied. Admin privileges required.",
-		})
+		return response.Error(c, fiber.StatusForbidden, "Access denied. Admin privileges required.")
 	}
 
 	p := pagination.ParseFromRequest(c)
 
-	wallets, total, err := repositories.GetWalletsPaginated(p.Limit, p.Offset)
+	wallets, total, err := h.walletRepo.List(p.Limit, p.Offset)
 	if err != nil {
 		log.Printf("Error fetching wallets: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch wallets",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch wallets")
 	}
 
 	p.Total = total
@@ -57,53 +85,43 @@ func GetAllWallets(c *fiber.Ctx) error {
 }
 
 // GetAllCreditCards retrieves all credit cards in a paginated manner (Admin only)
-func GetAllCreditCards(c *fiber.Ctx) error {
+func (h *AdminHandler) GetAllCreditCards(c *fiber.Ctx) error {
 	// Verify admin permissions
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok || !claims.HasPermission(models.PermissionReadAdmin) {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied. Admin privileges required.",
-		})
+		return response.Error(c, fiber.StatusForbidden, "Access denied. Admin privileges required.")
 	}
 
 	p := pagination.ParseFromRequest(c)
 
-	creditCards, total, err := repositories.GetCreditCardsPaginated(p.Limit, p.Offset)
+	creditCards, total, err := h.cardRepo.List(p.Limit, p.Offset)
 	if err != nil {
 		log.Printf("Error fetching credit cards: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch credit cards",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch credit cards")
 	}
 
 	p.Total = total
 	return c.JSON(pagination.Response(p, creditCards))
 }
 
-func GetAllTransactions(c *fiber.Ctx) error {
+func (h *AdminHandler) GetAllTransactions(c *fiber.Ctx) error {
 	// Get claims from context
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid claims",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "Invalid claims")
 	}
 
 	// Check if user has admin read permission
 	if !claims.HasPermission(models.PermissionReadAdmin) {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied. Admin privileges required.",
-		})
+		return response.Error(c, fiber.StatusForbidden, "Access denied. Admin privileges required.")
 	}
 
 	p := pagination.ParseFromRequest(c)
 
 	// Fetch all transactions
-	transactions, total, err := repositories.GetTransactions(p.Limit, p.Offset)
+	transactions, total, err := h.transactionRepo.List(p.Limit, p.Offset)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch transactions",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch transactions")
 	}
 
 	p.Total = total
@@ -111,31 +129,217 @@ func GetAllTransactions(c *fiber.Ctx) error {
 }
 
 // DeleteUser allows admins to delete a user by their ID
-func DeleteUser(c *fiber.Ctx) error {
+func (h *AdminHandler) DeleteUser(c *fiber.Ctx) error {
 	// Verify admin permissions
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok || !claims.HasPermission(models.PermissionWriteAdmin) {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied. Admin privileges required",
-		})
+		return response.Error(c, fiber.StatusForbidden, "Access denied. Admin privileges required")
 	}
 
-	userID := c.Params("id")
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
 
 	// Add audit logging
-	log.Printf("Admin %d attempting to delete user %s", claims.UserID, userID)
+	log.Printf("Admin %d attempting to delete user %d", claims.UserID, userID)
 
-	if err := repositories.DeleteUserByID(userID); err != nil {
-		log.Printf("Error deleting user %s: %v", userID, err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete user"})
-	}
-
-	// Add cache invalidation
-	if userIDUint, err := strconv.ParseUint(userID, 10, 32); err == nil {
-		repositories.InvalidateUserCache(uint(userIDUint))
+	// Deleting also drops the user's cache entries
+	if err := h.userRepo.Delete(uint(userID)); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Error deleting user %d: %v", userID, err)
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to delete user")
 	}
 
 	return c.JSON(fiber.Map{
 		"message": "User deleted successfully",
 	})
 }
+
+// UpdateUser changes a user's name, email, phone or country.
+func (h *AdminHandler) UpdateUser(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var input useradmin.UpdateUserRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	user, err := h.userAdmin.UpdateUser(c.Context(), claims.UserID, uint(userID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "User updated", user)
+}
+
+// SuspendUser blocks a user from logging in and paying, ending their sessions.
+func (h *AdminHandler) SuspendUser(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	user, err := h.userAdmin.Suspend(c.Context(), claims.UserID, uint(userID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "User suspended", user)
+}
+
+// UnsuspendUser reinstates a suspended user.
+func (h *AdminHandler) UnsuspendUser(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	user, err := h.userAdmin.Unsuspend(c.Context(), claims.UserID, uint(userID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "User reinstated", user)
+}
+
+// RevokeUserSessions logs a user out of every device.
+func (h *AdminHandler) RevokeUserSessions(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	if err := h.userAdmin.RevokeSessions(c.Context(), claims.UserID, uint(userID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "User sessions revoked", nil)
+}
+
+// UpdateUserRole gives a user a new role and the permissions that go with it.
+func (h *AdminHandler) UpdateUserRole(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var input struct {
+		Role string `json:"role"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	change, err := h.userAdmin.ChangeRole(c.Context(), claims, uint(userID), input.Role)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "User role updated", change)
+}
+
+// GetUserTimeline returns a user's account events and transactions, newest
+// first. Query parameters: before (RFC 3339, from a previous page's
+// next_before) and limit.
+func (h *AdminHandler) GetUserTimeline(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var before time.Time
+	if v := c.Query("before"); v != "" {
+		before, err = time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return response.BadRequest(c, "Invalid before timestamp")
+		}
+	}
+
+	timeline, err := h.userAdmin.Timeline(c.Context(), uint(userID), before, c.QueryInt("limit", 50))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "User timeline retrieved", timeline)
+}
+
+// InvalidateCache schedules a bulk invalidation by role, users, tags or key prefixes.
+// It is used after permission or limits template changes that affect many users.
+func (h *AdminHandler) InvalidateCache(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input struct {
+		Roles    []string `json:"roles"`
+		UserIDs  []uint   `json:"user_ids"`
+		Tags     []string `json:"tags"`
+		Prefixes []string `json:"prefixes"`
+		Reason   string   `json:"reason"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	if input.Reason == "" {
+		input.Reason = fmt.Sprintf("admin %d bulk invalidation", claims.UserID)
+	}
+
+	jobs := make([]cache.InvalidationJob, 0, len(input.Roles)+len(input.UserIDs)+1)
+	for _, role := range input.Roles {
+		jobs = append(jobs, cache.RoleInvalidationJob(role, input.Reason))
+	}
+	for _, userID := range input.UserIDs {
+		jobs = append(jobs, cache.UserInvalidationJob(userID, input.Reason))
+	}
+	for _, prefix := range input.Prefixes {
+		if prefix == "" {
+			return response.BadRequest(c, "Prefixes must not be empty")
+		}
+	}
+	if len(input.Tags) > 0 || len(input.Prefixes) > 0 {
+		jobs = append(jobs, cache.InvalidationJob{
+			Tags:     input.Tags,
+			Prefixes: input.Prefixes,
+			Reason:   input.Reason,
+		})
+	}
+
+	if len(jobs) == 0 {
+		return response.BadRequest(c, "Nothing to invalidate")
+	}
+
+	for _, job := range jobs {
+		if err := h.invalidator.Enqueue(job); err != nil {
+			return response.Error(c, fiber.StatusServiceUnavailable, err.Error())
+		}
+	}
+
+	log.Printf("Admin %d scheduled %d cache invalidation jobs: %s", claims.UserID, len(jobs), input.Reason)
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Cache invalidation scheduled",
+		"jobs":    len(jobs),
+	})
+}
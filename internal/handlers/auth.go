@@ -3,12 +3,13 @@ package handlers
 import (
 	"errors"
 	"log"
-	"orus/internal/config"
 	"orus/internal/models"
+	"orus/internal/repositories"
 	"orus/internal/services/auth"
 	"orus/internal/utils"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,13 +18,19 @@ import (
 
 type AuthHandler struct {
 	authService   auth.Service
+	keys          *auth.KeySet
 	refreshSecret string
+	secureCookies bool   // Only send cookies over HTTPS
+	countryHeader string // Set by the edge to the client's country
 }
 
-func NewAuthHandler(authService auth.Service, refreshSecret string) *AuthHandler {
+func NewAuthHandler(authService auth.Service, keys *auth.KeySet, refreshSecret string, secureCookies bool, countryHeader string) *AuthHandler {
 	return &AuthHandler{
 		authService:   authService,
+		keys:          keys,
 		refreshSecret: refreshSecret,
+		secureCookies: secureCookies,
+		countryHeader: countryHeader,
 	}
 }
 
@@ -36,34 +43,33 @@ func (h *AuthHandler) LoginUser(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	// Validate input
 	if (input.Email == "" && input.Phone == "") || input.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email/phone and password are required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "Email/phone and password are required")
 	}
 
-	user, accessToken, refreshToken, err := h.authService.Login(input.Email, input.Phone, input.Password)
+	user, accessToken, refreshToken, err := h.authService.Login(input.Email, input.Phone, input.Password, h.loginContext(c))
 	if err != nil {
-		if errors.Is(err, auth.ErrMFARequired) {
+		// Both are completed through /verify-otp
+		if errors.Is(err, auth.ErrMFARequired) || errors.Is(err, auth.ErrStepUpRequired) {
 			return c.JSON(fiber.Map{
-				"mfa_required": true,
-				"user_id":      user.ID,
+				"mfa_required":     true,
+				"step_up_required": errors.Is(err, auth.ErrStepUpRequired),
+				"user_id":          user.ID,
 			})
 		}
-		if err.Error() == "invalid credentials" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid email or password",
-			})
+		if errors.Is(err, auth.ErrInvalidCredentials) || errors.Is(err, repositories.ErrUserNotFound) {
+			return auth.ErrInvalidCredentials
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Authentication failed",
-		})
+		return err
+	}
+
+	permissions, err := h.authService.GetPermissions(user.Role)
+	if err != nil {
+		return err
 	}
 
 	h.setAuthCookies(c, accessToken, refreshToken)
@@ -75,7 +81,7 @@ func (h *AuthHandler) LoginUser(c *fiber.Ctx) error {
 			"id":          user.ID,
 			"email":       user.Email,
 			"role":        user.Role,
-			"permissions": models.GetDefaultPermissions(user.Role),
+			"permissions": permissions,
 		},
 	})
 }
@@ -117,41 +123,63 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	})
 }
 
-// LogoutUser handles user logout
+// LogoutUser ends the session the request was made with, leaving the
+// user's other sessions open
 func (h *AuthHandler) LogoutUser(c *fiber.Ctx) error {
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok {
 		return utils.Unauthorized(c, "Invalid claims")
 	}
 
-	// Increment token version to invalidate all existing tokens
-	if err := h.authService.Logout(claims.UserID); err != nil {
+	if err := h.authService.Logout(claims.UserID, claims.SessionID); err != nil {
 		return utils.InternalError(c, "Failed to logout")
 	}
+	h.clearAuthCookies(c)
 
-	// Clear cookies
-	c.Cookie(&fiber.Cookie{
-		Name:     "access_token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour),
-		HTTPOnly: true,
-		Secure:   config.IsProduction(),
-		Path:     "/",
-	})
-	c.Cookie(&fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour),
-		HTTPOnly: true,
-		Secure:   config.IsProduction(),
-		Path:     "/",
+	return utils.Success(c, fiber.Map{
+		"message": "Successfully logged out",
 	})
+}
+
+// LogoutAll ends every session of the user, on every device
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*models.UserClaims)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid claims")
+	}
+
+	if err := h.authService.LogoutAll(claims.UserID); err != nil {
+		return utils.InternalError(c, "Failed to logout")
+	}
+	h.clearAuthCookies(c)
 
 	return utils.Success(c, fiber.Map{
-		"message": "Successfully logged out",
+		"message": "Logged out of every session",
 	})
 }
 
+// ListSessions returns the user's open sessions, marking the one the
+// request was made with
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	sessions, err := h.authService.ListSessions(c.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, "sessions retrieved", sessions)
+}
+
+// RevokeSession ends one of the user's sessions, such as on a lost phone
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	if err := h.authService.RevokeSession(c.Context(), claims.UserID, c.Params("id")); err != nil {
+		return err
+	}
+	return response.Success(c, "session ended", nil)
+}
+
 // ChangePassword handles password change requests
 func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
 	var input struct {
@@ -190,9 +218,17 @@ func (h *AuthHandler) VerifyOTP(c *fiber.Ctx) error {
 
 	user, access, refresh, err := h.authService.VerifyOTP(input.UserID, input.Code)
 	if err != nil {
+		if errors.Is(err, auth.ErrTooManyOTPAttempts) {
+			return response.Error(c, fiber.StatusTooManyRequests, err.Error())
+		}
 		return utils.BadRequest(c, err.Error())
 	}
 
+	permissions, err := h.authService.GetPermissions(user.Role)
+	if err != nil {
+		return err
+	}
+
 	h.setAuthCookies(c, access, refresh)
 
 	return utils.Success(c, fiber.Map{
@@ -202,18 +238,48 @@ func (h *AuthHandler) VerifyOTP(c *fiber.Ctx) error {
 			"id":          user.ID,
 			"email":       user.Email,
 			"role":        user.Role,
-			"permissions": models.GetDefaultPermissions(user.Role),
+			"permissions": permissions,
 		},
 	})
 }
 
+// ListLogins returns the user's login history, with where each came from
+func (h *AuthHandler) ListLogins(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	logins, total, err := h.authService.ListLogins(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, logins))
+}
+
+// GetReportedLogin shows the login a "this wasn't me" link names, so the
+// user can confirm before locking their account
+func (h *AuthHandler) GetReportedLogin(c *fiber.Ctx) error {
+	login, err := h.authService.GetReportedLogin(c.Context(), c.Params("token"))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, "login retrieved", login)
+}
+
+// ReportLogin locks the account after a login its holder didn't make
+func (h *AuthHandler) ReportLogin(c *fiber.Ctx) error {
+	if err := h.authService.ReportLogin(c.Context(), c.Params("token")); err != nil {
+		return err
+	}
+	return response.Success(c, "account locked; contact support to restore access", nil)
+}
+
 // GetTokenVersion handles getting the token version of a user
 func (h *AuthHandler) GetTokenVersion(c *fiber.Ctx) error {
 	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	version, err := h.authService.GetUserTokenVersion(uint(userID))
@@ -230,34 +296,40 @@ func (h *AuthHandler) GetTokenVersion(c *fiber.Ctx) error {
 	})
 }
 
-// Add this new handler to debug the token
+// JWKS publishes the public keys access tokens are signed with. Retired
+// keys stay listed while they still verify live tokens.
+func (h *AuthHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.keys.JWKS())
+}
+
+// DebugToken decodes an access or refresh token and compares its version
+// with the user's current one, to explain why a session was rejected
 func (h *AuthHandler) DebugToken(c *fiber.Ctx) error {
-	// Get the token from the Authorization header
-	authHeader := c.Get("Authorization")
-	if authHeader == "" || len(strings.Split(authHeader, " ")) != 2 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing or invalid Authorization header",
-		})
+	var input struct {
+		Token string `json:"token"`
+	}
+	if err := c.BodyParser(&input); err != nil || input.Token == "" {
+		return response.BadRequest(c, "token is required")
 	}
 
-	tokenString := strings.Split(authHeader, " ")[1]
-
-	// Parse the token
-	token, err := jwt.ParseWithClaims(tokenString, &models.UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(h.refreshSecret), nil
-	})
-
+	// Access tokens are signed by the key set, refresh tokens with the
+	// refresh secret
+	claims := &models.UserClaims{}
+	_, err := jwt.ParseWithClaims(input.Token, claims, h.keys.Keyfunc)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid token",
-			"details": err.Error(),
+		claims = &models.UserClaims{}
+		_, err = jwt.ParseWithClaims(input.Token, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, auth.ErrUnexpectedSigningMethod
+			}
+			return []byte(h.refreshSecret), nil
 		})
 	}
-
-	claims, ok := token.Claims.(*models.UserClaims)
-	if !ok {
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid token claims",
+			"error":   "Invalid token",
+			"details": err.Error(),
 		})
 	}
 
@@ -277,14 +349,76 @@ func (h *AuthHandler) DebugToken(c *fiber.Ctx) error {
 	})
 }
 
+// DebugClaims returns the claims of the caller's own access token
+func (h *AuthHandler) DebugClaims(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*models.UserClaims)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid claims")
+	}
+
+	return c.JSON(fiber.Map{
+		"user_id":       claims.UserID,
+		"email":         claims.Email,
+		"role":          claims.Role,
+		"permissions":   claims.Permissions,
+		"token_version": claims.TokenVersion,
+	})
+}
+
 // Helper methods
 
+// clearAuthCookies removes the token cookies from the browser
+func (h *AuthHandler) clearAuthCookies(c *fiber.Ctx) {
+	for _, name := range []string{"access_token", "refresh_token"} {
+		c.Cookie(&fiber.Cookie{
+			Name:     name,
+			Value:    "",
+			Expires:  time.Now().Add(-time.Hour),
+			HTTPOnly: true,
+			Secure:   h.secureCookies,
+			Path:     "/",
+		})
+	}
+}
+
+// loginContext describes where a login came from. Devices are told apart
+// by an X-Device-ID header from the apps, or a long-lived cookie issued to
+// browsers on their first login.
+func (h *AuthHandler) loginContext(c *fiber.Ctx) auth.LoginContext {
+	deviceID := c.Get("X-Device-ID")
+	if deviceID == "" {
+		deviceID = c.Cookies("device_id")
+	}
+	if deviceID == "" || len(deviceID) > 64 {
+		deviceID = utils.MustGenerateSecureCode()
+		c.Cookie(&fiber.Cookie{
+			Name:     "device_id",
+			Value:    deviceID,
+			HTTPOnly: true,
+			Secure:   h.secureCookies,
+			Path:     "/",
+			SameSite: "Strict",
+			MaxAge:   5 * 365 * 24 * 60 * 60, // 5 years
+		})
+	}
+
+	login := auth.LoginContext{
+		IP:        c.IP(),
+		DeviceID:  deviceID,
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+	if h.countryHeader != "" && c.IsProxyTrusted() {
+		login.Country = c.Get(h.countryHeader)
+	}
+	return login
+}
+
 func (h *AuthHandler) setAuthCookies(c *fiber.Ctx, accessToken, refreshToken string) {
 	c.Cookie(&fiber.Cookie{
 		Name:     "access_token",
 		Value:    accessToken,
 		HTTPOnly: true,
-		Secure:   config.IsProduction(),
+		Secure:   h.secureCookies,
 		Path:     "/",
 		SameSite: "Strict",
 		MaxAge:   15 * 60, // 15 minutes
@@ -294,7 +428,7 @@ func (h *AuthHandler) setAuthCookies(c *fiber.Ctx, accessToken, refreshToken str
 		Name:     "refresh_token",
 		Value:    refreshToken,
 		HTTPOnly: true,
-		Secure:   config.IsProduction(),
+		Secure:   h.secureCookies,
 		Path:     "/",
 		SameSite: "Strict",
 		MaxAge:   7 * 24 * 60 * 60, // 7 days
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/autotopup"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AutoTopUpHandler exposes the user's auto top-up rule and its history.
+type AutoTopUpHandler struct {
+	service autotopup.Service
+}
+
+// NewAutoTopUpHandler creates a new AutoTopUpHandler.
+func NewAutoTopUpHandler(s autotopup.Service) *AutoTopUpHandler {
+	return &AutoTopUpHandler{service: s}
+}
+
+// GetRule returns the user's auto top-up rule.
+func (h *AutoTopUpHandler) GetRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	rule, err := h.service.GetRule(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "auto top-up retrieved", rule)
+}
+
+// SaveRule sets up the user's auto top-up or replaces it.
+func (h *AutoTopUpHandler) SaveRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	input := middleware.Body[autotopup.RuleRequest](c)
+	rule, err := h.service.SaveRule(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "auto top-up saved", rule)
+}
+
+// DeleteRule turns auto top-up off by removing the rule.
+func (h *AutoTopUpHandler) DeleteRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	if err := h.service.DeleteRule(c.Context(), claims.UserID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "auto top-up removed", nil)
+}
+
+// ListRuns pages through the auto top-ups the user's payments set off.
+func (h *AutoTopUpHandler) ListRuns(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	runs, total, err := h.service.ListRuns(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, runs))
+}
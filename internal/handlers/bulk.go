@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/bulk"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BulkHandler lets admins act on many users or QR codes at once and follow
+// the background job doing it.
+type BulkHandler struct {
+	service bulk.Service
+}
+
+// NewBulkHandler creates a new BulkHandler.
+func NewBulkHandler(s bulk.Service) *BulkHandler {
+	return &BulkHandler{service: s}
+}
+
+// LockWallets queues wallet locks for the users in an uploaded CSV file or
+// matching a filter.
+func (h *BulkHandler) LockWallets(c *fiber.Ctx) error {
+	return h.create(c, models.BulkActionLockWallets)
+}
+
+// SuspendUsers queues suspensions for the users in an uploaded CSV file or
+// matching a filter.
+func (h *BulkHandler) SuspendUsers(c *fiber.Ctx) error {
+	return h.create(c, models.BulkActionSuspendUsers)
+}
+
+// ExpireQRCodes queues expiry of the QR codes in an uploaded CSV file or
+// matching a filter.
+func (h *BulkHandler) ExpireQRCodes(c *fiber.Ctx) error {
+	return h.create(c, models.BulkActionExpireQRCodes)
+}
+
+// create takes the targets from a multipart "file" upload when there is
+// one, and from the JSON filter otherwise
+func (h *BulkHandler) create(c *fiber.Ctx, action string) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[bulk.Request](c)
+
+	if header, err := c.FormFile("file"); err == nil {
+		if header.Size > bulk.MaxCSVSize {
+			return bulk.ErrFileTooLarge
+		}
+		file, err := header.Open()
+		if err != nil {
+			return response.BadRequest(c, "Failed to read file")
+		}
+		defer file.Close()
+		input.CSV, err = io.ReadAll(io.LimitReader(file, bulk.MaxCSVSize+1))
+		if err != nil {
+			return response.BadRequest(c, "Failed to read file")
+		}
+	}
+
+	op, err := h.service.Create(c.Context(), claims.UserID, action, *input)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return response.Success(c, "bulk operation queued", op)
+}
+
+// ListOperations pages through bulk operations, newest first.
+func (h *BulkHandler) ListOperations(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	ops, total, err := h.service.List(c.Context(), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, ops))
+}
+
+// GetOperation returns a bulk operation's status and progress.
+func (h *BulkHandler) GetOperation(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid operation ID")
+	}
+
+	op, err := h.service.Get(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "bulk operation retrieved", op)
+}
+
+// ListItems pages through what happened to each target. ?status narrows it
+// to pending, succeeded, failed or skipped.
+func (h *BulkHandler) ListItems(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid operation ID")
+	}
+	p := pagination.ParseFromRequest(c)
+
+	items, total, err := h.service.ListItems(c.Context(), uint(id), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, items))
+}
+
+// GetReport downloads every target's result as CSV.
+func (h *BulkHandler) GetReport(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid operation ID")
+	}
+
+	op, content, err := h.service.Report(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("bulk-%s-%d.csv", op.Action, op.ID)))
+	return c.Send(content)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/category"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryHandler lets users re-categorize their spending and manage the
+// rules that categorize future payments.
+type CategoryHandler struct {
+	service category.Service
+}
+
+// NewCategoryHandler creates a new CategoryHandler.
+func NewCategoryHandler(s category.Service) *CategoryHandler {
+	return &CategoryHandler{service: s}
+}
+
+// ListCategories returns the categories transactions can be filed under.
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	return response.Success(c, "categories retrieved", models.TransactionCategories)
+}
+
+// Recategorize files one of the user's payments under another category,
+// optionally remembering it for the payee.
+func (h *CategoryHandler) Recategorize(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	txID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid transaction ID")
+	}
+
+	input := middleware.Body[category.RecategorizeRequest](c)
+	tx, err := h.service.Recategorize(c.Context(), claims.UserID, uint(txID), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "transaction category updated", fiber.Map{
+		"transaction_id":  tx.ID,
+		"category":        tx.Category,
+		"category_source": tx.CategorySource,
+	})
+}
+
+// ListRules returns the user's category rules, newest first.
+func (h *CategoryHandler) ListRules(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	rules, err := h.service.ListRules(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "category rules retrieved", rules)
+}
+
+// CreateRule adds a rule categorizing the user's future payments.
+func (h *CategoryHandler) CreateRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	input := middleware.Body[category.RuleRequest](c)
+	rule, err := h.service.CreateRule(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "category rule created", rule)
+}
+
+// DeleteRule removes one of the user's category rules.
+func (h *CategoryHandler) DeleteRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	ruleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid rule ID")
+	}
+
+	if err := h.service.DeleteRule(c.Context(), claims.UserID, uint(ruleID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "category rule removed", nil)
+}
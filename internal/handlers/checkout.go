@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/checkout"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CheckoutHandler exposes merchant payment links and hosted checkout sessions.
+type CheckoutHandler struct {
+	service checkout.Service
+}
+
+// NewCheckoutHandler creates a new CheckoutHandler.
+func NewCheckoutHandler(s checkout.Service) *CheckoutHandler {
+	return &CheckoutHandler{service: s}
+}
+
+// CreatePaymentLink creates a shareable payment link for the merchant.
+func (h *CheckoutHandler) CreatePaymentLink(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input checkout.CreateLinkRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	link, err := h.service.CreateLink(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment link created", link)
+}
+
+// GetPaymentLinks lists the merchant's payment links.
+func (h *CheckoutHandler) GetPaymentLinks(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	links, total, err := h.service.ListLinks(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get payment links")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, links))
+}
+
+// GetPaymentLink returns a payment link with its analytics.
+func (h *CheckoutHandler) GetPaymentLink(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	linkID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid payment link ID")
+	}
+
+	link, err := h.service.GetLink(c.Context(), claims.UserID, uint(linkID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment link retrieved", link)
+}
+
+// DisablePaymentLink stops a payment link from accepting payments.
+func (h *CheckoutHandler) DisablePaymentLink(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	linkID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid payment link ID")
+	}
+
+	link, err := h.service.DisableLink(c.Context(), claims.UserID, uint(linkID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment link disabled", link)
+}
+
+// GetHostedLink returns the public details shown on the hosted checkout page.
+func (h *CheckoutHandler) GetHostedLink(c *fiber.Ctx) error {
+	link, err := h.service.GetHostedLink(c.Context(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment link retrieved", link)
+}
+
+// OpenCheckout starts a checkout session for the authenticated payer.
+func (h *CheckoutHandler) OpenCheckout(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	session, err := h.service.OpenSession(c.Context(), claims.UserID, c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "checkout session created", session)
+}
+
+// GetCheckoutSession returns one of the payer's checkout sessions.
+func (h *CheckoutHandler) GetCheckoutSession(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	session, err := h.service.GetSession(c.Context(), claims.UserID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "checkout session retrieved", session)
+}
+
+// CompleteCheckout pays the checkout session from the payer's wallet,
+// optionally spending loyalty points at the merchant for a discount.
+func (h *CheckoutHandler) CompleteCheckout(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input struct {
+		LoyaltyPoints int64 `json:"loyalty_points"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	result, err := h.service.CompleteSession(c.Context(), claims.UserID, c.Params("id"), input.LoyaltyPoints)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment successful", result)
+}
+
+// GetHostedSession returns the public details shown on the hosted checkout
+// page for an order session.
+func (h *CheckoutHandler) GetHostedSession(c *fiber.Ctx) error {
+	session, err := h.service.GetHostedSession(c.Context(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "checkout session retrieved", session)
+}
+
+// CreateOrderSession opens a checkout session for an online order on behalf
+// of the API-key merchant.
+func (h *CheckoutHandler) CreateOrderSession(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	var input checkout.CreateSessionRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	session, err := h.service.CreateOrderSession(c.Context(), merchant, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "checkout session created", session)
+}
+
+// ListOrderSessions lists the API-key merchant's order sessions, optionally
+// by status.
+func (h *CheckoutHandler) ListOrderSessions(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+	p := pagination.ParseFromRequest(c)
+
+	sessions, total, err := h.service.ListOrderSessions(c.Context(), merchant.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get checkout sessions")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, sessions))
+}
+
+// GetOrderSession returns an order session and its status.
+func (h *CheckoutHandler) GetOrderSession(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	session, err := h.service.GetOrderSession(c.Context(), merchant.UserID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "checkout session retrieved", session)
+}
+
+// ExpireOrderSession closes an order session that hasn't been paid.
+func (h *CheckoutHandler) ExpireOrderSession(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	session, err := h.service.ExpireOrderSession(c.Context(), merchant.UserID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "checkout session expired", session)
+}
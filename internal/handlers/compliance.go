@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/compliance"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ComplianceHandler lets compliance officers review the large transaction
+// and suspicious activity reports drafted by monitoring, and file them.
+type ComplianceHandler struct {
+	service compliance.Service
+}
+
+// NewComplianceHandler creates a new ComplianceHandler.
+func NewComplianceHandler(s compliance.Service) *ComplianceHandler {
+	return &ComplianceHandler{service: s}
+}
+
+// ListReports pages through the reports, newest first. ?type, ?status,
+// ?user_id and ?assigned_to narrow it.
+func (h *ComplianceHandler) ListReports(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+	filter := compliance.ListFilter{
+		Type:          c.Query("type"),
+		Status:        c.Query("status"),
+		SubjectUserID: uint(c.QueryInt("user_id")),
+		AssignedTo:    uint(c.QueryInt("assigned_to")),
+	}
+
+	reports, total, err := h.service.List(c.Context(), filter, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, reports))
+}
+
+// GetReport returns a report with the transactions it covers.
+func (h *ComplianceHandler) GetReport(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+
+	report, err := h.service.Get(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "compliance report retrieved", report)
+}
+
+// ExportReport downloads a report as CSV for filing.
+func (h *ComplianceHandler) ExportReport(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+
+	content, err := h.service.Export(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("compliance-report-%d.csv", id)))
+	return c.Send(content)
+}
+
+// StartReview assigns a report to the caller and marks it in review.
+func (h *ComplianceHandler) StartReview(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+
+	report, err := h.service.StartReview(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "compliance report in review", report)
+}
+
+// UpdateNarrative replaces a report's narrative.
+func (h *ComplianceHandler) UpdateNarrative(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+	input := middleware.Body[compliance.NarrativeRequest](c)
+
+	report, err := h.service.UpdateNarrative(c.Context(), claims.UserID, uint(id), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "compliance report updated", report)
+}
+
+// FileReport records that a report was filed with the regulator.
+func (h *ComplianceHandler) FileReport(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+	input := middleware.Body[compliance.FileRequest](c)
+
+	report, err := h.service.File(c.Context(), claims.UserID, uint(id), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "compliance report filed", report)
+}
+
+// DismissReport closes a report without filing it.
+func (h *ComplianceHandler) DismissReport(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+	input := middleware.Body[compliance.DismissRequest](c)
+
+	report, err := h.service.Dismiss(c.Context(), claims.UserID, uint(id), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "compliance report dismissed", report)
+}
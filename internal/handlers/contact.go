@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/contact"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContactHandler exposes the user's directory of saved P2P recipients.
+type ContactHandler struct {
+	service contact.Service
+}
+
+// NewContactHandler creates a new ContactHandler.
+func NewContactHandler(s contact.Service) *ContactHandler {
+	return &ContactHandler{service: s}
+}
+
+// AddContact saves a recipient found by phone, email, QR code or user ID.
+func (h *ContactHandler) AddContact(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input contact.AddRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	ct, err := h.service.Add(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "contact added", ct)
+}
+
+// GetContacts lists saved recipients, favorites first. Filter with
+// ?favorites=true and search names with ?q=.
+func (h *ContactHandler) GetContacts(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	contacts, total, err := h.service.List(c.Context(), claims.UserID, c.QueryBool("favorites"), c.Query("q"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get contacts")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, contacts))
+}
+
+// GetContact returns a saved recipient.
+func (h *ContactHandler) GetContact(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	contactID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid contact ID")
+	}
+
+	ct, err := h.service.Get(c.Context(), claims.UserID, uint(contactID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "contact retrieved", ct)
+}
+
+// UpdateContact changes a contact's nickname or favorite flag.
+func (h *ContactHandler) UpdateContact(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	contactID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid contact ID")
+	}
+
+	var input contact.UpdateRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	ct, err := h.service.Update(c.Context(), claims.UserID, uint(contactID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "contact updated", ct)
+}
+
+// RemoveContact deletes a saved recipient.
+func (h *ContactHandler) RemoveContact(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	contactID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid contact ID")
+	}
+
+	if err := h.service.Remove(c.Context(), claims.UserID, uint(contactID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "contact removed", nil)
+}
+
+// FavoriteContact marks a contact as a favorite.
+func (h *ContactHandler) FavoriteContact(c *fiber.Ctx) error {
+	return h.setFavorite(c, true)
+}
+
+// UnfavoriteContact removes a contact from favorites.
+func (h *ContactHandler) UnfavoriteContact(c *fiber.Ctx) error {
+	return h.setFavorite(c, false)
+}
+
+func (h *ContactHandler) setFavorite(c *fiber.Ctx, favorite bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	contactID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid contact ID")
+	}
+
+	ct, err := h.service.SetFavorite(c.Context(), claims.UserID, uint(contactID), favorite)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "contact updated", ct)
+}
@@ -1,8 +1,8 @@
 package handlers
 
 import (
+	"orus/internal/middleware"
 	"orus/internal/models"
-	"orus/internal/repositories"
 	creditcard "orus/internal/services/credit-card"
 	"orus/internal/utils/response"
 
@@ -13,9 +13,9 @@ type CreditCardHandler struct {
 	cardService creditcard.Service
 }
 
-func NewCreditCardHandler(cardRepo repositories.CreditCardRepository) *CreditCardHandler {
+func NewCreditCardHandler(cardService creditcard.Service) *CreditCardHandler {
 	return &CreditCardHandler{
-		cardService: creditcard.NewService(cardRepo),
+		cardService: cardService,
 	}
 }
 
@@ -27,14 +27,14 @@ func (h *CreditCardHandler) LinkCard(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Invalid request format")
 	}
 
-	card, err := h.cardService.LinkCard(claims.UserID, input)
+	card, err := h.cardService.LinkCard(c.Context(), claims.UserID, input)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return response.Success(c, "Credit card linked successfully", fiber.Map{
 		"card_type": card.CardType,
-		"last_four": card.CardNumber[len(card.CardNumber)-4:],
+		"last_four": card.LastFour,
 		"expiry":    card.ExpiryMonth + "/" + card.ExpiryYear,
 	})
 }
@@ -57,9 +57,82 @@ func (h *CreditCardHandler) DeleteCard(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Invalid card ID")
 	}
 
-	if err := h.cardService.DeleteCard(claims.UserID, uint(cardID)); err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "Failed to delete card")
+	// Cards are only removed with the payment PIN, for users who set one
+	if err := h.cardService.DeleteCard(withPaymentPIN(c.Context(), c), claims.UserID, uint(cardID)); err != nil {
+		return err
 	}
 
 	return response.Success(c, "Card deleted successfully", nil)
 }
+
+// SetDefaultCard makes the card the user's default
+func (h *CreditCardHandler) SetDefaultCard(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	cardID, err := c.ParamsInt("id")
+	if err != nil {
+		return response.BadRequest(c, "Invalid card ID")
+	}
+
+	card, err := h.cardService.SetDefault(claims.UserID, uint(cardID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Default card updated", card)
+}
+
+// UpdateCard changes the card's expiry and billing details
+func (h *CreditCardHandler) UpdateCard(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	cardID, err := c.ParamsInt("id")
+	if err != nil {
+		return response.BadRequest(c, "Invalid card ID")
+	}
+	input := middleware.Body[creditcard.UpdateCardInput](c)
+
+	card, err := h.cardService.UpdateCard(claims.UserID, uint(cardID), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Card updated", card)
+}
+
+// StartCardVerification makes the refundable verification charge
+func (h *CreditCardHandler) StartCardVerification(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	cardID, err := c.ParamsInt("id")
+	if err != nil {
+		return response.BadRequest(c, "Invalid card ID")
+	}
+
+	card, err := h.cardService.StartVerification(c.Context(), claims.UserID, uint(cardID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Verification charge made, check your statement for the amount", card)
+}
+
+// ConfirmCardVerification verifies the card with the charged amount
+func (h *CreditCardHandler) ConfirmCardVerification(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	cardID, err := c.ParamsInt("id")
+	if err != nil {
+		return response.BadRequest(c, "Invalid card ID")
+	}
+
+	var input struct {
+		Amount float64 `json:"amount"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request format")
+	}
+
+	card, err := h.cardService.ConfirmVerification(c.Context(), claims.UserID, uint(cardID), input.Amount)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Card verified", card)
+}
@@ -3,8 +3,9 @@ package handlers
 import (
 	"orus/internal/models"
 	"orus/internal/services/dashboard"
+	"orus/internal/utils/pagination"
 	"orus/internal/utils/response"
-	"time"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -47,21 +48,64 @@ func (h *DashboardHandler) GetMerchantDashboard(c *fiber.Ctx) error {
 	return response.Success(c, "Merchant dashboard data retrieved successfully", stats)
 }
 
-// GetTransactionAnalytics returns detailed transaction analytics
+// GetTransactionAnalytics returns a time series of the user's transactions.
+// Query parameters: start_date and end_date (YYYY-MM-DD), granularity (day,
+// week or month), metrics (comma-separated) and timezone (IANA name).
 func (h *DashboardHandler) GetTransactionAnalytics(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 
-	// Parse date range from query parameters
-	startDate := c.Query("start_date", time.Now().AddDate(0, -1, 0).Format("2006-01-02"))
-	endDate := c.Query("end_date", time.Now().Format("2006-01-02"))
-
-	start, _ := time.Parse("2006-01-02", startDate)
-	end, _ := time.Parse("2006-01-02", endDate)
+	query := dashboard.AnalyticsQuery{
+		StartDate:   c.Query("start_date"),
+		EndDate:     c.Query("end_date"),
+		Granularity: c.Query("granularity"),
+		Timezone:    c.Query("timezone"),
+	}
+	if metrics := c.Query("metrics"); metrics != "" {
+		query.Metrics = strings.Split(metrics, ",")
+	}
 
-	analytics, err := h.dashboardService.GetTransactionAnalytics(c.Context(), claims.UserID, start, end)
+	analytics, err := h.dashboardService.GetTransactionAnalytics(c.Context(), claims.UserID, query)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "Failed to get transaction analytics")
+		return err
 	}
 
 	return response.Success(c, "Transaction analytics retrieved successfully", analytics)
 }
+
+// GetCustomerInsights returns new vs returning customers, top spenders and
+// churn indicators for the merchant. Query parameters: start_date and
+// end_date (YYYY-MM-DD), timezone, sort (volume, payments or recent) and
+// pagination.
+func (h *DashboardHandler) GetCustomerInsights(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	query := dashboard.CustomerQuery{
+		StartDate: c.Query("start_date"),
+		EndDate:   c.Query("end_date"),
+		Timezone:  c.Query("timezone"),
+		Sort:      c.Query("sort"),
+	}
+	insights, err := h.dashboardService.GetCustomerInsights(c.Context(), claims.UserID, query, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = insights.Total
+	result := pagination.Response(p, insights.Customers)
+	result["summary"] = insights.Summary
+	return c.JSON(result)
+}
+
+// GetEarningsForecast projects the merchant's next settlement from pending
+// sales, the fees they will incur, reserves and pending refunds
+func (h *DashboardHandler) GetEarningsForecast(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	forecast, err := h.dashboardService.GetEarningsForecast(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Earnings forecast retrieved successfully", forecast)
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"fmt"
+	"io"
+	"orus/internal/middleware"
 	"orus/internal/models"
 	"orus/internal/services/dispute"
+	"orus/internal/utils/pagination"
 	"orus/internal/utils/response"
 	"strconv"
 
@@ -17,30 +21,40 @@ func NewDisputeHandler(disputeService *dispute.Service) *DisputeHandler {
 	return &DisputeHandler{disputeService: disputeService}
 }
 
+// FileDispute files a dispute over a merchant payment, under a category
+// and for all or part of its amount
 func (h *DisputeHandler) FileDispute(c *fiber.Ctx) error {
-	var input struct {
-		TransactionID uint   `json:"transaction_id"`
-		Reason        string `json:"reason"`
-	}
+	input := middleware.Body[dispute.FileRequest](c)
 
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request format")
+	claims := c.Locals("claims").(*models.UserClaims)
+	d, err := h.disputeService.FileDispute(claims.UserID, *input)
+	if err != nil {
+		return err
 	}
 
+	return response.Success(c, "Dispute filed successfully", d)
+}
+
+// ListMyDisputes pages through the disputes the caller filed, newest
+// first, to track their status. ?status narrows it to one status.
+func (h *DisputeHandler) ListMyDisputes(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
-	dispute, err := h.disputeService.FileDispute(input.TransactionID, claims.UserID, input.Reason)
+	p := pagination.ParseFromRequest(c)
+
+	disputes, total, err := h.disputeService.ListMine(claims.UserID, c.Query("status"), p.Limit, p.Offset)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	return response.Success(c, "Dispute filed successfully", dispute)
+	p.Total = total
+	return c.JSON(pagination.Response(p, disputes))
 }
 
 func (h *DisputeHandler) GetDisputes(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 	disputes, err := h.disputeService.GetDisputes(claims.UserID)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return response.Success(c, "Disputes retrieved successfully", disputes)
@@ -50,7 +64,7 @@ func (h *DisputeHandler) GetMerchantDisputes(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 	disputes, err := h.disputeService.GetMerchantDisputes(claims.UserID)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return response.Success(c, "Merchant disputes retrieved successfully", disputes)
@@ -70,10 +84,343 @@ func (h *DisputeHandler) RefundDispute(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid dispute ID")
 	}
 
-	err = h.disputeService.ProcessRefund(uint(disputeID)) // Pass the converted uint
+	d, err := h.disputeService.ProcessRefund(c.Context(), claims.UserID, uint(disputeID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Refund processed successfully", d)
+}
+
+// GetDispute returns a dispute and its evidence to either party
+func (h *DisputeHandler) GetDispute(c *fiber.Ctx) error {
+	return h.getDispute(c, false)
+}
+
+// UploadEvidence attaches a file to a dispute. Expects multipart form data
+// with a "file" field and an optional "description".
+func (h *DisputeHandler) UploadEvidence(c *fiber.Ctx) error {
+	return h.uploadEvidence(c, false)
+}
+
+// DownloadEvidence streams an evidence file to either party
+func (h *DisputeHandler) DownloadEvidence(c *fiber.Ctx) error {
+	return h.downloadEvidence(c, false)
+}
+
+// RespondDispute records the merchant's response
+func (h *DisputeHandler) RespondDispute(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	var input struct {
+		Response string `json:"response"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request format")
+	}
+
+	d, err := h.disputeService.Respond(claims.UserID, uint(disputeID), input.Response)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Dispute response submitted", d)
+}
+
+// ListAllDisputes lists disputes for admin review, optionally by status
+func (h *DisputeHandler) ListAllDisputes(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	disputes, total, err := h.disputeService.List(c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "Failed to get disputes")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, disputes))
+}
+
+// AdminGetDispute returns any dispute and its evidence
+func (h *DisputeHandler) AdminGetDispute(c *fiber.Ctx) error {
+	return h.getDispute(c, true)
+}
+
+// AdminUploadEvidence attaches a file to a dispute on the platform's behalf
+func (h *DisputeHandler) AdminUploadEvidence(c *fiber.Ctx) error {
+	return h.uploadEvidence(c, true)
+}
+
+// AdminDownloadEvidence streams any dispute's evidence file
+func (h *DisputeHandler) AdminDownloadEvidence(c *fiber.Ctx) error {
+	return h.downloadEvidence(c, true)
+}
+
+// RequestEvidence asks the merchant to respond by a deadline
+func (h *DisputeHandler) RequestEvidence(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	var input struct {
+		Days int `json:"days"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "Invalid request format")
+		}
+	}
+
+	d, err := h.disputeService.RequestEvidence(claims.UserID, uint(disputeID), input.Days)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Evidence requested", d)
+}
+
+// StartReview puts a dispute under review
+func (h *DisputeHandler) StartReview(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	d, err := h.disputeService.StartReview(claims.UserID, uint(disputeID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Dispute under review", d)
+}
+
+// ResolveDispute records the admin's decision, refunding the customer when
+// decided in their favour
+func (h *DisputeHandler) ResolveDispute(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	var input dispute.ResolveRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request format")
+	}
+
+	d, err := h.disputeService.Resolve(c.Context(), claims.UserID, uint(disputeID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Dispute resolved", d)
+}
+
+func (h *DisputeHandler) getDispute(c *fiber.Ctx, isAdmin bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	detail, err := h.disputeService.Get(claims.UserID, isAdmin, uint(disputeID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Dispute retrieved successfully", detail)
+}
+
+func (h *DisputeHandler) uploadEvidence(c *fiber.Ctx, isAdmin bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "file is required")
+	}
+	if header.Size > dispute.MaxEvidenceSize {
+		return dispute.ErrFileTooLarge
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.BadRequest(c, "Failed to read file")
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, dispute.MaxEvidenceSize+1))
+	if err != nil {
+		return response.BadRequest(c, "Failed to read file")
+	}
+
+	evidence, err := h.disputeService.AddEvidence(c.Context(), claims.UserID, isAdmin, uint(disputeID), dispute.EvidenceUpload{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Content:     content,
+		Description: c.FormValue("description"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Evidence uploaded", evidence)
+}
+
+func (h *DisputeHandler) downloadEvidence(c *fiber.Ctx, isAdmin bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+	evidenceID, err := strconv.ParseUint(c.Params("evidenceId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid evidence ID")
+	}
+
+	evidence, content, err := h.disputeService.GetEvidenceFile(c.Context(), claims.UserID, isAdmin, uint(disputeID), uint(evidenceID))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, evidence.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", evidence.FileName))
+	return c.Send(content)
+}
+
+// ListChargebacks lists chargebacks against the merchant
+func (h *DisputeHandler) ListChargebacks(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	chargebacks, total, err := h.disputeService.ListChargebacks(claims.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "Failed to get chargebacks")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, chargebacks))
+}
+
+// GetChargeback returns a chargeback and its representment evidence
+func (h *DisputeHandler) GetChargeback(c *fiber.Ctx) error {
+	return h.getChargeback(c, false)
+}
+
+// RepresentChargeback submits the merchant's contest of a chargeback
+func (h *DisputeHandler) RepresentChargeback(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	chargebackID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid chargeback ID")
+	}
+
+	var input struct {
+		Note string `json:"note"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request format")
+	}
+
+	cb, err := h.disputeService.Represent(claims.UserID, uint(chargebackID), input.Note)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Representment submitted", cb)
+}
+
+// OpenChargeback charges a disputed payment back to the merchant
+func (h *DisputeHandler) OpenChargeback(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	disputeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid dispute ID")
+	}
+
+	var input dispute.OpenChargebackRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "Invalid request format")
+		}
+	}
+
+	cb, err := h.disputeService.OpenChargeback(c.Context(), claims.UserID, uint(disputeID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Chargeback opened", cb)
+}
+
+// ListAllChargebacks lists chargebacks for admin review, optionally by status
+func (h *DisputeHandler) ListAllChargebacks(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	chargebacks, total, err := h.disputeService.ListChargebacks(0, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "Failed to get chargebacks")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, chargebacks))
+}
+
+// AdminGetChargeback returns any chargeback and its representment evidence
+func (h *DisputeHandler) AdminGetChargeback(c *fiber.Ctx) error {
+	return h.getChargeback(c, true)
+}
+
+// SettleChargeback records the admin's decision on a chargeback
+func (h *DisputeHandler) SettleChargeback(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	chargebackID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid chargeback ID")
+	}
+
+	var input dispute.SettleChargebackRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request format")
+	}
+
+	cb, err := h.disputeService.SettleChargeback(c.Context(), claims.UserID, uint(chargebackID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Chargeback settled", cb)
+}
+
+func (h *DisputeHandler) getChargeback(c *fiber.Ctx, isAdmin bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	chargebackID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid chargeback ID")
+	}
+
+	detail, err := h.disputeService.GetChargeback(claims.UserID, isAdmin, uint(chargebackID))
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	return response.Success(c, "Refund processed successfully", nil)
+	return response.Success(c, "Chargeback retrieved successfully", detail)
 }
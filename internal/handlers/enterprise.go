@@ -0,0 +1,533 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/enterprise"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EnterpriseHandler exposes organizations with their members, wallets,
+// spending policies and statements.
+type EnterpriseHandler struct {
+	service enterprise.Service
+}
+
+// NewEnterpriseHandler creates a new EnterpriseHandler.
+func NewEnterpriseHandler(s enterprise.Service) *EnterpriseHandler {
+	return &EnterpriseHandler{service: s}
+}
+
+// CreateOrganization registers an organization owned by the caller.
+func (h *EnterpriseHandler) CreateOrganization(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input enterprise.OrganizationRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	view, err := h.service.CreateOrganization(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organization created", view)
+}
+
+// GetOrganizations lists the organizations the caller belongs to.
+func (h *EnterpriseHandler) GetOrganizations(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	organizations, err := h.service.ListOrganizations(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organizations retrieved", organizations)
+}
+
+// GetOrganization returns an organization with its members and wallets.
+func (h *EnterpriseHandler) GetOrganization(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	view, err := h.service.GetOrganization(c.Context(), claims.UserID, uint(orgID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organization retrieved", view)
+}
+
+// UpdateOrganization changes an organization's profile or billing cycle.
+func (h *EnterpriseHandler) UpdateOrganization(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	var input enterprise.OrganizationRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	view, err := h.service.UpdateOrganization(c.Context(), claims.UserID, uint(orgID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organization updated", view)
+}
+
+// GetMembers lists an organization's members and their spending policies.
+func (h *EnterpriseHandler) GetMembers(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	members, err := h.service.ListMembers(c.Context(), claims.UserID, uint(orgID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "members retrieved", members)
+}
+
+// AddMember adds a user to an organization.
+func (h *EnterpriseHandler) AddMember(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	var input enterprise.MemberRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	member, err := h.service.AddMember(c.Context(), claims.UserID, uint(orgID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "member added", member)
+}
+
+// UpdateMember changes a member's role or spending policy.
+func (h *EnterpriseHandler) UpdateMember(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+
+	var input enterprise.MemberRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	member, err := h.service.UpdateMember(c.Context(), claims.UserID, uint(orgID), uint(memberID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "member updated", member)
+}
+
+// RemoveMember removes a user from an organization.
+func (h *EnterpriseHandler) RemoveMember(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+
+	if err := h.service.RemoveMember(c.Context(), claims.UserID, uint(orgID), uint(memberID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "member removed", nil)
+}
+
+// GetWallets lists an organization's wallets.
+func (h *EnterpriseHandler) GetWallets(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	wallets, err := h.service.ListWallets(c.Context(), claims.UserID, uint(orgID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organization wallets retrieved", wallets)
+}
+
+// CreateWallet opens a new wallet for an organization.
+func (h *EnterpriseHandler) CreateWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	var input enterprise.WalletRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	w, err := h.service.CreateWallet(c.Context(), claims.UserID, uint(orgID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organization wallet created", w)
+}
+
+// UpdateWallet renames, freezes or unfreezes an organization wallet.
+func (h *EnterpriseHandler) UpdateWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	walletID, err := strconv.ParseUint(c.Params("walletId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input enterprise.WalletRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	w, err := h.service.UpdateWallet(c.Context(), claims.UserID, uint(orgID), uint(walletID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "organization wallet updated", w)
+}
+
+// FundWallet moves funds from the caller's wallet into an organization wallet.
+func (h *EnterpriseHandler) FundWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	walletID, err := strconv.ParseUint(c.Params("walletId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input struct {
+		Amount float64 `json:"amount"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	w, err := h.service.FundWallet(c.Context(), claims.UserID, uint(orgID), uint(walletID), input.Amount)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "funds added", w)
+}
+
+// Pay pays a user from an organization wallet.
+func (h *EnterpriseHandler) Pay(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	walletID, err := strconv.ParseUint(c.Params("walletId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input enterprise.PaymentRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	payment, err := h.service.Pay(c.Context(), claims.UserID, uint(orgID), uint(walletID), input)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status == models.EnterprisePaymentPendingApproval {
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "payment awaiting approval", payment)
+	}
+	return response.Success(c, "payment sent", payment)
+}
+
+// GetPayments lists payments out of an organization's wallets. Query
+// parameters: wallet_id, member_id and status.
+func (h *EnterpriseHandler) GetPayments(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	filter := enterprise.PaymentFilter{
+		WalletID: uint(c.QueryInt("wallet_id")),
+		MemberID: uint(c.QueryInt("member_id")),
+		Status:   c.Query("status"),
+	}
+	payments, total, err := h.service.ListPayments(c.Context(), claims.UserID, uint(orgID), filter, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, payments))
+}
+
+// GetStatement returns an organization's consolidated statement for the
+// month given as ?month=YYYY-MM, the current month by default.
+func (h *EnterpriseHandler) GetStatement(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	statement, err := h.service.Statement(c.Context(), claims.UserID, uint(orgID), c.Query("month"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "statement retrieved", statement)
+}
+
+// GetPaymentHistory returns the audit trail of an organization payment.
+func (h *EnterpriseHandler) GetPaymentHistory(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	paymentID, err := strconv.ParseUint(c.Params("paymentId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid payment ID")
+	}
+
+	events, err := h.service.PaymentHistory(c.Context(), claims.UserID, uint(orgID), uint(paymentID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment history retrieved", events)
+}
+
+// GetApprovals lists the held payments the caller can review.
+func (h *EnterpriseHandler) GetApprovals(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	payments, total, err := h.service.ListApprovals(c.Context(), claims.UserID, uint(orgID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, payments))
+}
+
+// ApprovePayment sends a payment that was held for approval.
+func (h *EnterpriseHandler) ApprovePayment(c *fiber.Ctx) error {
+	return h.reviewPayment(c, true)
+}
+
+// RejectPayment declines a payment that was held for approval.
+func (h *EnterpriseHandler) RejectPayment(c *fiber.Ctx) error {
+	return h.reviewPayment(c, false)
+}
+
+func (h *EnterpriseHandler) reviewPayment(c *fiber.Ctx, approve bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	paymentID, err := strconv.ParseUint(c.Params("paymentId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid payment ID")
+	}
+
+	var input struct {
+		Note string `json:"note"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	if approve {
+		payment, err := h.service.ApprovePayment(c.Context(), claims.UserID, uint(orgID), uint(paymentID), input.Note)
+		if err != nil {
+			return err
+		}
+		return response.Success(c, "payment approved", payment)
+	}
+
+	payment, err := h.service.RejectPayment(c.Context(), claims.UserID, uint(orgID), uint(paymentID), input.Note)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, "payment rejected", payment)
+}
+
+// CancelPayment withdraws the caller's own payment while it awaits approval.
+func (h *EnterpriseHandler) CancelPayment(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	paymentID, err := strconv.ParseUint(c.Params("paymentId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid payment ID")
+	}
+
+	payment, err := h.service.CancelPayment(c.Context(), claims.UserID, uint(orgID), uint(paymentID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment cancelled", payment)
+}
+
+// GetRules lists an organization's approval rules.
+func (h *EnterpriseHandler) GetRules(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	rules, err := h.service.ListRules(c.Context(), claims.UserID, uint(orgID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "approval rules retrieved", rules)
+}
+
+// CreateRule adds an approval rule to an organization.
+func (h *EnterpriseHandler) CreateRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+
+	var input enterprise.RuleRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	rule, err := h.service.CreateRule(c.Context(), claims.UserID, uint(orgID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "approval rule created", rule)
+}
+
+// UpdateRule changes an approval rule or turns it on or off.
+func (h *EnterpriseHandler) UpdateRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	ruleID, err := strconv.ParseUint(c.Params("ruleId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid rule ID")
+	}
+
+	var input enterprise.RuleRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	rule, err := h.service.UpdateRule(c.Context(), claims.UserID, uint(orgID), uint(ruleID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "approval rule updated", rule)
+}
+
+// DeleteRule removes an approval rule. Payments it already held stay queued.
+func (h *EnterpriseHandler) DeleteRule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	ruleID, err := strconv.ParseUint(c.Params("ruleId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid rule ID")
+	}
+
+	if err := h.service.DeleteRule(c.Context(), claims.UserID, uint(orgID), uint(ruleID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "approval rule deleted", nil)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	apperrors "orus/internal/errors"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListErrorCodes documents every error code the API returns with its HTTP
+// status and English description, and the languages messages come in.
+func ListErrorCodes(c *fiber.Ctx) error {
+	return response.Success(c, "error codes retrieved", fiber.Map{
+		"languages": apperrors.Languages,
+		"codes":     apperrors.Definitions(),
+	})
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/escrow"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EscrowHandler exposes escrow payments between buyers and sellers.
+type EscrowHandler struct {
+	service escrow.Service
+}
+
+// NewEscrowHandler creates a new EscrowHandler.
+func NewEscrowHandler(s escrow.Service) *EscrowHandler {
+	return &EscrowHandler{service: s}
+}
+
+// CreateEscrow moves the buyer's funds into escrow for a seller.
+func (h *EscrowHandler) CreateEscrow(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input escrow.CreateRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	e, err := h.service.Create(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "escrow funded", e)
+}
+
+// GetEscrows lists escrows the user is buying or selling in. Filter with
+// ?role=buyer|seller and ?status=.
+func (h *EscrowHandler) GetEscrows(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	escrows, total, err := h.service.List(c.Context(), claims.UserID, c.Query("role"), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get escrows")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, escrows))
+}
+
+// GetEscrow returns an escrow to its buyer or seller.
+func (h *EscrowHandler) GetEscrow(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	escrowID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid escrow ID")
+	}
+
+	e, err := h.service.Get(c.Context(), claims.UserID, uint(escrowID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "escrow retrieved", e)
+}
+
+// ConfirmEscrow lets the buyer release the funds to the seller.
+func (h *EscrowHandler) ConfirmEscrow(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	escrowID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid escrow ID")
+	}
+
+	e, err := h.service.Confirm(c.Context(), claims.UserID, uint(escrowID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "escrow released", e)
+}
+
+// RefundEscrow lets the seller return the funds to the buyer.
+func (h *EscrowHandler) RefundEscrow(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	escrowID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid escrow ID")
+	}
+
+	e, err := h.service.Refund(c.Context(), claims.UserID, uint(escrowID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "escrow refunded", e)
+}
+
+// DisputeEscrow lets either party object, stopping auto-release.
+func (h *EscrowHandler) DisputeEscrow(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	escrowID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid escrow ID")
+	}
+
+	var input escrow.DisputeRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	e, err := h.service.Dispute(c.Context(), claims.UserID, uint(escrowID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "escrow disputed", e)
+}
+
+// ResolveEscrow lets an admin settle a disputed escrow.
+func (h *EscrowHandler) ResolveEscrow(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	escrowID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid escrow ID")
+	}
+
+	var input escrow.ResolveRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	e, err := h.service.Resolve(c.Context(), claims.UserID, uint(escrowID), input.Outcome)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "escrow dispute resolved", e)
+}
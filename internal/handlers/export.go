@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/export"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportHandler manages scheduled transaction exports.
+type ExportHandler struct {
+	service export.Service
+}
+
+// NewExportHandler creates a new ExportHandler.
+func NewExportHandler(s export.Service) *ExportHandler { return &ExportHandler{service: s} }
+
+// CreateSchedule schedules a weekly or monthly transaction export.
+func (h *ExportHandler) CreateSchedule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input export.ScheduleRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	schedule, err := h.service.CreateSchedule(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "export schedule created", schedule)
+}
+
+// GetSchedules lists the user's export schedules.
+func (h *ExportHandler) GetSchedules(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	schedules, err := h.service.ListSchedules(c.Context(), claims.UserID)
+	if err != nil {
+		return response.ServerError(c, "failed to get export schedules")
+	}
+
+	return response.Success(c, "export schedules retrieved", schedules)
+}
+
+// UpdateSchedule changes an export schedule's settings.
+func (h *ExportHandler) UpdateSchedule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	scheduleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid export schedule ID")
+	}
+
+	var input export.ScheduleRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	schedule, err := h.service.UpdateSchedule(c.Context(), claims.UserID, uint(scheduleID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "export schedule updated", schedule)
+}
+
+// DeleteSchedule removes an export schedule.
+func (h *ExportHandler) DeleteSchedule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	scheduleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid export schedule ID")
+	}
+
+	if err := h.service.DeleteSchedule(c.Context(), claims.UserID, uint(scheduleID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "export schedule deleted", nil)
+}
+
+// RunSchedule runs an export immediately.
+func (h *ExportHandler) RunSchedule(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	scheduleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid export schedule ID")
+	}
+
+	run, err := h.service.RunNow(c.Context(), claims.UserID, uint(scheduleID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "export run completed", run)
+}
+
+// GetRuns lists past runs of an export schedule.
+func (h *ExportHandler) GetRuns(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	scheduleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid export schedule ID")
+	}
+
+	p := pagination.ParseFromRequest(c)
+	runs, total, err := h.service.GetRuns(c.Context(), claims.UserID, uint(scheduleID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, runs))
+}
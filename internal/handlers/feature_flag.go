@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/featureflag"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlagHandler lets admins review the feature flags and switch them
+// at runtime.
+type FeatureFlagHandler struct {
+	service featureflag.Service
+}
+
+// NewFeatureFlagHandler creates a new FeatureFlagHandler.
+func NewFeatureFlagHandler(s featureflag.Service) *FeatureFlagHandler {
+	return &FeatureFlagHandler{service: s}
+}
+
+// ListFlags returns every flag with its state and what decided it.
+func (h *FeatureFlagHandler) ListFlags(c *fiber.Ctx) error {
+	flags, err := h.service.List(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "feature flags retrieved", flags)
+}
+
+// SetOverride turns a flag on or off, regardless of the configuration.
+func (h *FeatureFlagHandler) SetOverride(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input featureflag.OverrideRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	override, err := h.service.SetOverride(c.Context(), claims.UserID, c.Params("key"), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "feature flag override saved", override)
+}
+
+// DeleteOverride returns a flag to its configured state.
+func (h *FeatureFlagHandler) DeleteOverride(c *fiber.Ctx) error {
+	if err := h.service.DeleteOverride(c.Context(), c.Params("key")); err != nil {
+		return err
+	}
+
+	return response.Success(c, "feature flag override removed", nil)
+}
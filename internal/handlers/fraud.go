@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/fraud"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FraudHandler exposes merchant self-service fraud rules.
+type FraudHandler struct {
+	service fraud.Service
+}
+
+// NewFraudHandler creates a new FraudHandler.
+func NewFraudHandler(s fraud.Service) *FraudHandler {
+	return &FraudHandler{service: s}
+}
+
+// GetRules returns the merchant's fraud rules alongside the enforced limits.
+func (h *FraudHandler) GetRules(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	rules, err := h.service.GetRules(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "fraud rules retrieved", rules)
+}
+
+// UpdateRules changes the merchant's fraud rules.
+func (h *FraudHandler) UpdateRules(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input fraud.UpdateRulesRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	rules, err := h.service.UpdateRules(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "fraud rules updated", rules)
+}
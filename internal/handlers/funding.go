@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"orus/internal/models"
+	"orus/internal/services/funding"
+	"orus/internal/services/wallet"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FundingHandler exposes linked bank account endpoints.
+type FundingHandler struct {
+	service funding.Service
+}
+
+// NewFundingHandler creates a new FundingHandler.
+func NewFundingHandler(s funding.Service) *FundingHandler { return &FundingHandler{service: s} }
+
+// LinkAccount links a bank account either instantly with a provider public token
+// or manually with account details followed by micro-deposit verification.
+func (h *FundingHandler) LinkAccount(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input struct {
+		PublicToken string `json:"public_token"`
+		funding.AccountDetails
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	var account *models.BankAccount
+	var err error
+	if input.PublicToken != "" {
+		account, err = h.service.LinkInstant(c.Context(), claims.UserID, input.PublicToken)
+	} else {
+		account, err = h.service.LinkManual(c.Context(), claims.UserID, input.AccountDetails)
+	}
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "bank account linked", account)
+}
+
+// VerifyAccount confirms a manually linked account with micro-deposit amounts.
+func (h *FundingHandler) VerifyAccount(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	accountID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid bank account ID")
+	}
+
+	var input struct {
+		Amounts [2]float64 `json:"amounts"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	account, err := h.service.VerifyMicroDeposits(c.Context(), claims.UserID, uint(accountID), input.Amounts)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "bank account verified", account)
+}
+
+// GetAccounts lists the user's linked bank accounts.
+func (h *FundingHandler) GetAccounts(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	accounts, err := h.service.ListAccounts(c.Context(), claims.UserID)
+	if err != nil {
+		return response.ServerError(c, "failed to get bank accounts")
+	}
+
+	return response.Success(c, "bank accounts retrieved", accounts)
+}
+
+// RemoveAccount unlinks a bank account.
+func (h *FundingHandler) RemoveAccount(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	accountID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid bank account ID")
+	}
+
+	if err := h.service.RemoveAccount(c.Context(), claims.UserID, uint(accountID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "bank account removed", nil)
+}
+
+// TopUp moves funds from a linked bank account into the wallet over ACH.
+func (h *FundingHandler) TopUp(c *fiber.Ctx) error {
+	return h.move(c, h.service.TopUp, "top up initiated")
+}
+
+// Withdraw moves funds from the wallet to a linked bank account over ACH.
+func (h *FundingHandler) Withdraw(c *fiber.Ctx) error {
+	return h.move(c, h.service.Withdraw, "withdrawal initiated")
+}
+
+func (h *FundingHandler) move(
+	c *fiber.Ctx,
+	fn func(ctx context.Context, userID, accountID uint, amount float64) (*models.Transaction, error),
+	message string,
+) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	accountID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid bank account ID")
+	}
+
+	var input struct {
+		Amount float64 `json:"amount"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	ctx := context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role)
+	tx, err := fn(ctx, claims.UserID, uint(accountID), input.Amount)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, message, tx)
+}
+
+// GetDeposits lists the user's bank deposits and their settlement status.
+func (h *FundingHandler) GetDeposits(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	deposits, total, err := h.service.ListDeposits(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get deposits")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, deposits))
+}
+
+// GetDeposit returns a bank deposit with its provider event history.
+func (h *FundingHandler) GetDeposit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	depositID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid deposit ID")
+	}
+
+	deposit, err := h.service.GetDeposit(c.Context(), claims.UserID, uint(depositID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "deposit retrieved", deposit)
+}
+
+// HandleDepositWebhook applies a bank provider's deposit status update.
+func (h *FundingHandler) HandleDepositWebhook(c *fiber.Ctx) error {
+	var event funding.DepositEvent
+	if err := c.BodyParser(&event); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	deposit, err := h.service.HandleDepositEvent(c.Context(), event)
+	if err != nil {
+		// Acknowledge redeliveries so the provider stops retrying
+		if errors.Is(err, funding.ErrDuplicateDepositEvent) {
+			return response.Success(c, err.Error(), nil)
+		}
+		return err
+	}
+
+	return response.Success(c, "deposit event processed", deposit)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"orus/internal/services/fxrates"
+	"orus/internal/utils/response"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FXHandler exposes exchange rates for clients to quote conversions.
+type FXHandler struct {
+	service fxrates.Service
+}
+
+// NewFXHandler creates a new FXHandler.
+func NewFXHandler(s fxrates.Service) *FXHandler {
+	return &FXHandler{service: s}
+}
+
+// GetRates returns the current rates from ?base= (USD by default) to each
+// currency in the comma-separated ?symbols=, or to every currency. Money
+// converted before expires_at uses these rates.
+func (h *FXHandler) GetRates(c *fiber.Ctx) error {
+	var symbols []string
+	if raw := c.Query("symbols"); raw != "" {
+		symbols = strings.Split(raw, ",")
+	}
+
+	rates, err := h.service.Rates(c.Context(), c.Query("base"), symbols)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "exchange rates retrieved", rates)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"orus/internal/graph"
+	"orus/internal/repositories"
+	"orus/internal/services/wallet"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// graphQLComplexityLimit bounds how much work one query can ask for
+const graphQLComplexityLimit = 200
+
+// GraphQLHandler serves the GraphQL gateway that client apps use to fetch
+// the profile, wallet, transactions, QR codes and merchant data they show
+// on startup in a single request.
+type GraphQLHandler struct {
+	serve fiber.Handler
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler.
+func NewGraphQLHandler(
+	users repositories.UserRepository,
+	wallets repositories.WalletRepository,
+	balances wallet.Service,
+	merchants repositories.MerchantRepository,
+	transactions repositories.TransactionRepository,
+	qrCodes repositories.QRCodeRepository,
+) *GraphQLHandler {
+	resolver := graph.NewResolver(users, wallets, balances, merchants, transactions, qrCodes)
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(graphQLComplexityLimit))
+
+	return &GraphQLHandler{
+		serve: adaptor.HTTPHandler(graph.Middleware(users, merchants, srv)),
+	}
+}
+
+// Query executes a GraphQL query for the authenticated user. Fiber locals,
+// including the auth middleware's claims, are visible to resolvers through
+// the request context.
+func (h *GraphQLHandler) Query(c *fiber.Ctx) error {
+	return h.serve(c)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/handle"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleHandler exposes payment @handles and discoverability settings.
+type HandleHandler struct {
+	service handle.Service
+}
+
+// NewHandleHandler creates a new HandleHandler.
+func NewHandleHandler(s handle.Service) *HandleHandler {
+	return &HandleHandler{service: s}
+}
+
+// Resolve looks up who to pay from an @handle or phone number.
+func (h *HandleHandler) Resolve(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	recipient, err := h.service.Resolve(c.Context(), claims.UserID, c.Params("handle"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "recipient resolved", recipient)
+}
+
+// GetHandle returns the user's handle and privacy settings.
+func (h *HandleHandler) GetHandle(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	settings, err := h.service.GetSettings(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "handle retrieved", settings)
+}
+
+// ClaimHandle claims or changes the user's handle.
+func (h *HandleHandler) ClaimHandle(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input struct {
+		Handle string `json:"handle"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	settings, err := h.service.Claim(c.Context(), claims.UserID, input.Handle)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "handle claimed", settings)
+}
+
+// ReleaseHandle gives up the user's handle.
+func (h *HandleHandler) ReleaseHandle(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	settings, err := h.service.Release(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "handle released", settings)
+}
+
+// UpdatePrivacy controls whether others can find the user by handle or phone.
+func (h *HandleHandler) UpdatePrivacy(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input handle.PrivacyRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	settings, err := h.service.UpdatePrivacy(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "privacy settings updated", settings)
+}
@@ -1,24 +1,89 @@
 package handlers
 
 import (
-	"orus/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
+	"orus/migrations"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func HealthCheck(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"status":  "ok",
-		"version": "1.0.0",
-		"services": fiber.Map{
-			"database": "connected",
-			"redis":    "connected",
-		},
+// readinessTimeout bounds each dependency check of a readiness probe
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler answers orchestrator liveness and readiness probes
+type HealthHandler struct {
+	db       *sql.DB
+	cache    *cache.CacheService
+	breakers *resilience.Registry
+	draining atomic.Bool
+}
+
+func NewHealthHandler(db *sql.DB, cache *cache.CacheService, breakers *resilience.Registry) *HealthHandler {
+	return &HealthHandler{db: db, cache: cache, breakers: breakers}
+}
+
+// Drain fails every later readiness probe so load balancers stop routing
+// requests here while the server shuts down
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is up. It checks no dependencies, so an
+// outage elsewhere doesn't get the instance restarted.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Ready reports whether the instance can serve traffic: the database and
+// Redis answer and the schema is migrated
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	if h.draining.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "draining"})
+	}
+
+	checks := fiber.Map{}
+	ready := true
+	check := func(name string, fn func(ctx context.Context) error) {
+		ctx, cancel := context.WithTimeout(c.Context(), readinessTimeout)
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			checks[name] = err.Error()
+			ready = false
+			return
+		}
+		checks[name] = "ok"
+	}
+
+	check("database", h.db.PingContext)
+	check("redis", h.cache.HealthCheck)
+	check("migrations", func(ctx context.Context) error {
+		pending, err := migrations.Pending(ctx, h.db)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return errors.New("pending migrations")
+		}
+		return nil
 	})
+
+	if !ready {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "unavailable", "checks": checks})
+	}
+	return c.JSON(fiber.Map{"status": "ready", "checks": checks})
 }
 
-func CacheStats(c *fiber.Ctx) error {
-	poolStats := repositories.CacheService.GetStats(c.Context())
+// CacheStats reports the Redis connection pool counters
+func (h *HealthHandler) CacheStats(c *fiber.Ctx) error {
+	poolStats := h.cache.GetStats(c.Context())
 
 	return c.JSON(fiber.Map{
 		"pool_stats": fiber.Map{
@@ -31,3 +96,37 @@ func CacheStats(c *fiber.Ctx) error {
 		},
 	})
 }
+
+// Metrics reports the circuit breakers and distributed locks in the
+// Prometheus text format
+func (h *HealthHandler) Metrics(c *fiber.Ctx) error {
+	var b strings.Builder
+	stats := h.breakers.Stats()
+
+	metric := func(name, kind, help string, value func(s resilience.BreakerStats) float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, s := range stats {
+			fmt.Fprintf(&b, "%s{breaker=%q} %g\n", name, s.Name, value(s))
+		}
+	}
+	metric("orus_circuit_breaker_state", "gauge", "Circuit breaker state: 0 closed, 1 open, 2 half-open.",
+		func(s resilience.BreakerStats) float64 { return float64(s.State) })
+	metric("orus_circuit_breaker_failures", "gauge", "Consecutive failures counted toward opening the breaker.",
+		func(s resilience.BreakerStats) float64 { return float64(s.Failures) })
+	metric("orus_circuit_breaker_trips_total", "counter", "Times the breaker has opened.",
+		func(s resilience.BreakerStats) float64 { return float64(s.Trips) })
+	metric("orus_circuit_breaker_rejected_total", "counter", "Calls failed fast while the breaker was open.",
+		func(s resilience.BreakerStats) float64 { return float64(s.Rejected) })
+
+	locks := h.cache.LockStats()
+	counter := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n%s %g\n", name, help, name, name, value)
+	}
+	counter("orus_lock_acquired_total", "Distributed locks acquired.", float64(locks.Acquired))
+	counter("orus_lock_busy_total", "Lock waits that gave up because the lock stayed held.", float64(locks.Busy))
+	counter("orus_lock_unavailable_total", "Operations that ran without their lock because Redis was down.", float64(locks.Unavailable))
+	counter("orus_lock_wait_seconds_total", "Time spent acquiring distributed locks.", locks.Wait.Seconds())
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4")
+	return c.SendString(b.String())
+}
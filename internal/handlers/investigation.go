@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"orus/internal/services/investigation"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InvestigationHandler gives fraud analysts the linked view of a transaction.
+type InvestigationHandler struct {
+	service investigation.Service
+}
+
+// NewInvestigationHandler creates a new InvestigationHandler.
+func NewInvestigationHandler(s investigation.Service) *InvestigationHandler {
+	return &InvestigationHandler{service: s}
+}
+
+// GetGraph returns a transaction with its refunds and reversals, disputes,
+// ledger entries, QR code, the login it was made from, risk signals and
+// both parties' recent activity.
+func (h *InvestigationHandler) GetGraph(c *fiber.Ctx) error {
+	transactionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid transaction ID")
+	}
+
+	graph, err := h.service.Graph(c.Context(), uint(transactionID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "transaction graph retrieved", graph)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/services/invoice"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InvoiceHandler exposes merchant invoicing and customer invoice payment.
+type InvoiceHandler struct {
+	service invoice.Service
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler.
+func NewInvoiceHandler(s invoice.Service) *InvoiceHandler {
+	return &InvoiceHandler{service: s}
+}
+
+// CreateInvoice creates a draft invoice for the merchant.
+func (h *InvoiceHandler) CreateInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input invoice.InvoiceRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	inv, err := h.service.CreateInvoice(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice created", inv)
+}
+
+// GetInvoices lists the merchant's invoices, optionally filtered by status.
+func (h *InvoiceHandler) GetInvoices(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	invoices, total, err := h.service.ListInvoices(c.Context(), claims.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get invoices")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, invoices))
+}
+
+// GetInvoice returns one of the merchant's invoices.
+func (h *InvoiceHandler) GetInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	invoiceID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid invoice ID")
+	}
+
+	inv, err := h.service.GetInvoice(c.Context(), claims.UserID, uint(invoiceID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice retrieved", inv)
+}
+
+// UpdateInvoice replaces the contents of a draft invoice.
+func (h *InvoiceHandler) UpdateInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	invoiceID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid invoice ID")
+	}
+
+	var input invoice.InvoiceRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	inv, err := h.service.UpdateInvoice(c.Context(), claims.UserID, uint(invoiceID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice updated", inv)
+}
+
+// DeleteInvoice deletes a draft invoice.
+func (h *InvoiceHandler) DeleteInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	invoiceID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid invoice ID")
+	}
+
+	if err := h.service.DeleteInvoice(c.Context(), claims.UserID, uint(invoiceID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice deleted", nil)
+}
+
+// SendInvoice issues a draft invoice and emails it to the customer.
+func (h *InvoiceHandler) SendInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	invoiceID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid invoice ID")
+	}
+
+	inv, err := h.service.SendInvoice(c.Context(), claims.UserID, uint(invoiceID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice sent", inv)
+}
+
+// VoidInvoice cancels an unpaid invoice.
+func (h *InvoiceHandler) VoidInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	invoiceID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid invoice ID")
+	}
+
+	inv, err := h.service.VoidInvoice(c.Context(), claims.UserID, uint(invoiceID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice voided", inv)
+}
+
+// GetInvoicePDF downloads one of the merchant's invoices as a PDF.
+func (h *InvoiceHandler) GetInvoicePDF(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	invoiceID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid invoice ID")
+	}
+
+	inv, pdf, err := h.service.RenderPDF(c.Context(), claims.UserID, uint(invoiceID))
+	if err != nil {
+		return err
+	}
+
+	return sendInvoicePDF(c, inv, pdf)
+}
+
+// GetPublicInvoice returns an issued invoice to whoever holds its link.
+func (h *InvoiceHandler) GetPublicInvoice(c *fiber.Ctx) error {
+	inv, err := h.service.GetPublicInvoice(c.Context(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice retrieved", inv)
+}
+
+// GetPublicInvoicePDF downloads an issued invoice as a PDF from its link.
+func (h *InvoiceHandler) GetPublicInvoicePDF(c *fiber.Ctx) error {
+	inv, pdf, err := h.service.RenderPublicPDF(c.Context(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return sendInvoicePDF(c, inv, pdf)
+}
+
+// PayInvoice pays an invoice from the authenticated user's wallet.
+func (h *InvoiceHandler) PayInvoice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	result, err := h.service.PayInvoice(c.Context(), claims.UserID, c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invoice paid", result)
+}
+
+func sendInvoicePDF(c *fiber.Ctx, inv *models.Invoice, pdf []byte) error {
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", inv.Number+".pdf"))
+	return c.Send(pdf)
+}
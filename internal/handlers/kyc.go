@@ -2,17 +2,17 @@ package handlers
 
 import (
 	"orus/internal/models"
-	"orus/internal/services"
+	"orus/internal/services/kyc"
 	"orus/internal/utils/response"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type KYCHandler struct {
-	service services.KYCService
+	service kyc.Service
 }
 
-func NewKYCHandler(s services.KYCService) *KYCHandler { return &KYCHandler{service: s} }
+func NewKYCHandler(s kyc.Service) *KYCHandler { return &KYCHandler{service: s} }
 
 func (h *KYCHandler) SubmitKYC(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
@@ -25,7 +25,7 @@ func (h *KYCHandler) SubmitKYC(c *fiber.Ctx) error {
 	}
 	kyc, err := h.service.SubmitKYC(c.Context(), claims.UserID, input.DocumentID, input.ScanURL)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return response.Success(c, "KYC submitted", kyc)
 }
@@ -34,7 +34,7 @@ func (h *KYCHandler) GetStatus(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 	kyc, err := h.service.GetStatus(c.Context(), claims.UserID)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return response.Success(c, "KYC status", kyc)
 }
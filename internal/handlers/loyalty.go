@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/loyalty"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoyaltyHandler exposes merchant loyalty programs and customers' points.
+type LoyaltyHandler struct {
+	service loyalty.Service
+}
+
+// NewLoyaltyHandler creates a new LoyaltyHandler.
+func NewLoyaltyHandler(s loyalty.Service) *LoyaltyHandler {
+	return &LoyaltyHandler{service: s}
+}
+
+// GetProgram returns the merchant's loyalty program.
+func (h *LoyaltyHandler) GetProgram(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	program, err := h.service.GetProgram(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "loyalty program retrieved", program)
+}
+
+// SaveProgram creates the merchant's loyalty program or changes its rates.
+func (h *LoyaltyHandler) SaveProgram(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input loyalty.ProgramRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	program, err := h.service.SaveProgram(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "loyalty program saved", program)
+}
+
+// ListMembers lists the customers holding points with the merchant.
+func (h *LoyaltyHandler) ListMembers(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	members, total, err := h.service.ListMembers(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, members))
+}
+
+// ListAccounts lists the customer's points balances across merchants.
+func (h *LoyaltyHandler) ListAccounts(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	accounts, err := h.service.ListAccounts(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "loyalty accounts retrieved", accounts)
+}
+
+// GetAccount returns the customer's points balance with one merchant.
+func (h *LoyaltyHandler) GetAccount(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	merchantID, err := strconv.ParseUint(c.Params("merchantId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid merchant ID")
+	}
+
+	account, err := h.service.GetAccount(c.Context(), claims.UserID, uint(merchantID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "loyalty account retrieved", account)
+}
+
+// ListEntries lists the points the customer earned and spent with one merchant.
+func (h *LoyaltyHandler) ListEntries(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	merchantID, err := strconv.ParseUint(c.Params("merchantId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid merchant ID")
+	}
+	p := pagination.ParseFromRequest(c)
+
+	entries, total, err := h.service.ListEntries(c.Context(), claims.UserID, uint(merchantID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, entries))
+}
+
+// QuoteRedemption shows the discount points would give on a payment
+// without spending them.
+func (h *LoyaltyHandler) QuoteRedemption(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	merchantID, err := strconv.ParseUint(c.Params("merchantId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid merchant ID")
+	}
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
+		return response.BadRequest(c, "invalid amount")
+	}
+	points, err := strconv.ParseInt(c.Query("points"), 10, 64)
+	if err != nil {
+		return response.BadRequest(c, "invalid points")
+	}
+
+	quote, err := h.service.Quote(c.Context(), claims.UserID, uint(merchantID), amount, points)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "loyalty discount quoted", quote)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/maintenance"
+	"orus/internal/utils/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaintenanceHandler announces maintenance to clients and lets admins
+// switch it on and off.
+type MaintenanceHandler struct {
+	service maintenance.Service
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler.
+func NewMaintenanceHandler(s maintenance.Service) *MaintenanceHandler {
+	return &MaintenanceHandler{service: s}
+}
+
+// GetStatus tells clients whether maintenance is on and what to show.
+func (h *MaintenanceHandler) GetStatus(c *fiber.Ctx) error {
+	status := maintenance.StatusOf(h.service.Current(c.Context()), time.Now())
+	return response.Success(c, "maintenance status retrieved", status)
+}
+
+// GetMaintenance returns the maintenance state with its allowlist.
+func (h *MaintenanceHandler) GetMaintenance(c *fiber.Ctx) error {
+	mode, err := h.service.Get(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "maintenance mode retrieved", mode)
+}
+
+// EnableMaintenance starts maintenance or updates the one under way.
+func (h *MaintenanceHandler) EnableMaintenance(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[maintenance.EnableRequest](c)
+
+	mode, err := h.service.Enable(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "maintenance mode enabled", mode)
+}
+
+// DisableMaintenance reopens the platform.
+func (h *MaintenanceHandler) DisableMaintenance(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	mode, err := h.service.Disable(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "maintenance mode disabled", mode)
+}
@@ -4,14 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	apperrors "orus/internal/errors"
+	"orus/internal/middleware"
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"orus/internal/services/merchant"
 	qr "orus/internal/services/qr_code"
-	"orus/internal/utils"
-
 	"orus/internal/utils/pagination"
 	"orus/internal/utils/response"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -31,28 +32,27 @@ func NewMerchantHandler(merchantSvc *merchant.Service, qrSvc qr.Service, transac
 	}
 }
 
+// CreateMerchantRequest opens a merchant profile
+type CreateMerchantRequest struct {
+	UserID             uint   `json:"user_id"`
+	BusinessName       string `json:"business_name" validate:"required,max=255"`
+	BusinessType       string `json:"business_type" validate:"max=100"`
+	BusinessAddress    string `json:"business_address" validate:"max=500"`
+	BusinessID         string `json:"business_id"`
+	TaxID              string `json:"tax_id"`
+	Website            string `json:"website" validate:"omitempty,url"`
+	MerchantCategory   string `json:"merchant_category"`
+	LegalEntityType    string `json:"legal_entity_type"`
+	RegistrationNumber string `json:"registration_number"`
+	YearEstablished    int    `json:"year_established" validate:"omitempty,gte=1800"`
+	SupportEmail       string `json:"support_email" validate:"omitempty,email"`
+	SupportPhone       string `json:"support_phone" validate:"omitempty,e164"`
+}
+
 func (h *MerchantHandler) CreateMerchant(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 
-	var input struct {
-		UserID             uint   `json:"user_id"`
-		BusinessName       string `json:"business_name"`
-		BusinessType       string `json:"business_type"`
-		BusinessAddress    string `json:"business_address"`
-		BusinessID         string `json:"business_id"`
-		TaxID              string `json:"tax_id"`
-		Website            string `json:"website"`
-		MerchantCategory   string `json:"merchant_category"`
-		LegalEntityType    string `json:"legal_entity_type"`
-		RegistrationNumber string `json:"registration_number"`
-		YearEstablished    int    `json:"year_established"`
-		SupportEmail       string `json:"support_email"`
-		SupportPhone       string `json:"support_phone"`
-	}
-
-	if err := c.BodyParser(&input); err != nil {
-		return utils.BadRequest(c, "Invalid request format")
-	}
+	input := middleware.Body[CreateMerchantRequest](c)
 
 	// Use the authenticated user's ID if not specified
 	if input.UserID == 0 {
@@ -94,7 +94,7 @@ func (h *MerchantHandler) CreateMerchant(c *fiber.Ctx) error {
 
 	result, err := h.merchantService.CreateMerchant(merchant)
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return apperrors.WithStatus(fiber.StatusBadRequest, err)
 	}
 
 	return response.Success(c, "Merchant profile created successfully", fiber.Map{
@@ -104,7 +104,7 @@ func (h *MerchantHandler) CreateMerchant(c *fiber.Ctx) error {
 
 func (h *MerchantHandler) GetMerchantProfile(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
-	merchant, err := repositories.GetMerchantByUserID(claims.UserID)
+	merchant, err := h.merchantService.GetMerchant(c.Context(), claims.UserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			defaultMerchant := &models.Merchant{
@@ -125,70 +125,144 @@ func (h *MerchantHandler) GetMerchantProfile(c *fiber.Ctx) error {
 				return response.Error(c, fiber.StatusInternalServerError, "Failed to create merchant profile")
 			}
 
-			return response.Success(c, "Default merchant profile created", result)
+			return response.Success(c, "Default merchant profile created", MerchantProfileResponse{Merchant: result, EffectiveFee: result.EffectiveFee()})
 		}
 
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Merchant profile not found",
-		})
+		return response.Error(c, fiber.StatusNotFound, "Merchant profile not found")
+	}
+	return c.JSON(MerchantProfileResponse{Merchant: merchant, EffectiveFee: merchant.EffectiveFee()})
+}
+
+// MerchantProfileResponse is a merchant's profile with the processing fee
+// their payments are charged
+type MerchantProfileResponse struct {
+	*models.Merchant
+	EffectiveFee models.ProcessingFee `json:"effective_fee"`
+}
+
+// SetFeePlan moves a merchant to a fee plan and sets their negotiated
+// rate; for admins.
+func (h *MerchantHandler) SetFeePlan(c *fiber.Ctx) error {
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+	input := middleware.Body[merchant.FeePlanInput](c)
+
+	updated, err := h.merchantService.SetFeePlan(c.Context(), uint(merchantID), *input)
+	if err != nil {
+		return err
 	}
-	return c.JSON(merchant)
+
+	return response.Success(c, "merchant fee plan updated", MerchantProfileResponse{Merchant: updated, EffectiveFee: updated.EffectiveFee()})
 }
 
 func (h *MerchantHandler) ProcessDirectCharge(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
-	var input merchant.ChargeInput
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request format")
+	input := middleware.Body[merchant.ChargeInput](c)
+
+	tx, held, err := h.merchantService.ProcessDirectCharge(claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return chargeResponse(c, tx, held)
+}
+
+// GetChargeConfirmation returns a charge held for the customer's approval,
+// for the point of sale to follow
+func (h *MerchantHandler) GetChargeConfirmation(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid charge confirmation ID")
 	}
 
-	tx, err := h.merchantService.ProcessDirectCharge(claims.UserID, input)
+	confirmation, err := h.merchantService.GetScanCharge(c.Context(), claims.UserID, uint(id))
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
+	return response.Success(c, "charge confirmation retrieved", confirmation)
+}
+
+// chargeResponse answers a direct charge, which completed or is held for
+// the customer to approve
+func chargeResponse(c *fiber.Ctx, tx *models.Transaction, held *models.ScanConfirmation) error {
+	if held != nil {
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "charge awaiting the customer's approval", held)
+	}
 	return response.Success(c, "Transaction processed successfully", tx)
 }
 
-func (h *MerchantHandler) UpdateMerchantProfile(c *fiber.Ctx) error {
+// RefundCharge refunds all or part of a charge, optionally attributed to
+// a staff operator
+func (h *MerchantHandler) RefundCharge(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[merchant.RefundInput](c)
 
-	log.Printf("Attempting to retrieve merchant for userID: %d", claims.UserID)
-
-	var input struct {
-		BusinessInfo struct {
-			Name               string `json:"name"`
-			Type               string `json:"type"`
-			RegistrationNumber string `json:"registration_number"`
-			TaxID              string `json:"tax_id"`
-		} `json:"business_info"`
-		ContactInfo struct {
-			BusinessEmail string `json:"business_email"`
-			BusinessPhone string `json:"business_phone"`
-			Website       string `json:"website"`
-		} `json:"contact_info"`
-		Address struct {
-			Street     string `json:"street"`
-			Unit       string `json:"unit"`
-			City       string `json:"city"`
-			PostalCode string `json:"postal_code"`
-			Country    string `json:"country"`
-		} `json:"address"`
-		SettlementInfo struct {
-			BankName      string `json:"bank_name"`
-			AccountNumber string `json:"account_number"`
-			AccountHolder string `json:"account_holder"`
-			Currency      string `json:"currency"`
-		} `json:"settlement_info"`
-		BusinessHours map[string]string `json:"business_hours"`
+	tx, err := h.merchantService.RefundCharge(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
 	}
 
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+	return response.Success(c, "Refund processed successfully", tx)
+}
+
+// ReverseCharge reverses a charge taken in error, optionally attributed to
+// a staff operator
+func (h *MerchantHandler) ReverseCharge(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[merchant.ReverseInput](c)
+
+	reversal, err := h.merchantService.ReverseCharge(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
 	}
 
-	// Get existing merchant using the repository directly
-	merchant, err := repositories.GetMerchantByUserID(claims.UserID)
+	return response.Success(c, "Charge reversed successfully", reversal)
+}
+
+// UpdateMerchantProfileRequest replaces a merchant's profile
+type UpdateMerchantProfileRequest struct {
+	BusinessInfo struct {
+		Name               string `json:"name" validate:"required,max=255"`
+		Type               string `json:"type" validate:"max=100"`
+		RegistrationNumber string `json:"registration_number"`
+		TaxID              string `json:"tax_id"`
+	} `json:"business_info"`
+	ContactInfo struct {
+		BusinessEmail string `json:"business_email" validate:"omitempty,email"`
+		BusinessPhone string `json:"business_phone" validate:"omitempty,e164"`
+		Website       string `json:"website" validate:"omitempty,url"`
+	} `json:"contact_info"`
+	Address struct {
+		Street     string `json:"street"`
+		Unit       string `json:"unit"`
+		City       string `json:"city"`
+		PostalCode string `json:"postal_code"`
+		Country    string `json:"country" validate:"omitempty,iso3166_1_alpha2"`
+	} `json:"address"`
+	SettlementInfo struct {
+		BankName      string `json:"bank_name"`
+		AccountNumber string `json:"account_number"`
+		AccountHolder string `json:"account_holder"`
+		Currency      string `json:"currency" validate:"omitempty,iso4217"`
+	} `json:"settlement_info"`
+	BusinessHours map[string]string `json:"business_hours"`
+}
+
+func (h *MerchantHandler) UpdateMerchantProfile(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	log.Printf("Attempting to retrieve merchant for userID: %d", claims.UserID)
+
+	input := middleware.Body[UpdateMerchantProfileRequest](c)
+
+	// Get existing merchant
+	merchant, err := h.merchantService.GetMerchant(c.Context(), claims.UserID)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, "Merchant profile not found")
 	}
@@ -216,7 +290,7 @@ func (h *MerchantHandler) UpdateMerchantProfile(c *fiber.Ctx) error {
 	})
 
 	// Save updated merchant
-	if err := repositories.DB.Save(merchant).Error; err != nil {
+	if err := h.merchantService.SaveMerchant(merchant); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to update merchant profile")
 	}
 
@@ -234,23 +308,34 @@ func (h *MerchantHandler) GenerateAPIKey(c *fiber.Ctx) error {
 	return response.Success(c, "API key generated", fiber.Map{"api_key": apiKey})
 }
 
-func (h *MerchantHandler) SetWebhookURL(c *fiber.Ctx) error {
+func (h *MerchantHandler) GenerateSandboxAPIKey(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
-	var input struct {
-		WebhookURL string `json:"webhook_url"`
-	}
 
-	// Parse the request body
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+	apiKey, err := h.merchantService.GenerateSandboxAPIKey(claims.UserID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to generate sandbox API key")
 	}
 
+	return response.Success(c, "sandbox API key generated", fiber.Map{"api_key": apiKey})
+}
+
+// WebhookURLRequest sets where a merchant's webhooks are delivered
+type WebhookURLRequest struct {
+	WebhookURL string `json:"webhook_url" validate:"required,http_url"`
+}
+
+func (h *MerchantHandler) SetWebhookURL(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[WebhookURLRequest](c)
+
 	// Call the service to set the webhook URL
-	if err := h.merchantService.SetWebhookURL(claims.UserID, input.WebhookURL); err != nil {
+	secret, err := h.merchantService.SetWebhookURL(claims.UserID, input.WebhookURL)
+	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to set webhook URL")
 	}
 
-	return response.Success(c, "Webhook URL updated successfully", nil)
+	// Deliveries carry an X-Orus-Signature header signed with this secret
+	return response.Success(c, "Webhook URL updated successfully", fiber.Map{"webhook_secret": secret})
 }
 
 func (h *MerchantHandler) GetMerchantTransactions(c *fiber.Ctx) error {
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/merchantrisk"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MerchantRiskHandler exposes merchants' risk scores, history and review
+// queue to admins. Merchants are addressed by their user ID.
+type MerchantRiskHandler struct {
+	service merchantrisk.Service
+}
+
+// NewMerchantRiskHandler creates a new MerchantRiskHandler.
+func NewMerchantRiskHandler(s merchantrisk.Service) *MerchantRiskHandler {
+	return &MerchantRiskHandler{service: s}
+}
+
+// ListRestricted pages through merchants whose limits monitoring lowered,
+// highest score first. ?status narrows it to tightened or review.
+func (h *MerchantRiskHandler) ListRestricted(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	profiles, total, err := h.service.ListRestricted(c.Context(), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, profiles))
+}
+
+// GetRisk returns the merchant's current risk score and status.
+func (h *MerchantRiskHandler) GetRisk(c *fiber.Ctx) error {
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+
+	profile, err := h.service.GetProfile(c.Context(), uint(merchantID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "merchant risk retrieved", profile)
+}
+
+// GetHistory pages through the merchant's risk assessments, latest first.
+func (h *MerchantRiskHandler) GetHistory(c *fiber.Ctx) error {
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+	p := pagination.ParseFromRequest(c)
+
+	assessments, total, err := h.service.ListHistory(c.Context(), uint(merchantID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, assessments))
+}
+
+// Assess re-scores the merchant now instead of waiting for the hourly run.
+func (h *MerchantRiskHandler) Assess(c *fiber.Ctx) error {
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+
+	assessment, err := h.service.Assess(c.Context(), uint(merchantID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "merchant risk assessed", assessment)
+}
+
+// Clear restores a restricted merchant's limit once an admin has reviewed
+// them.
+func (h *MerchantRiskHandler) Clear(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+	input := middleware.Body[merchantrisk.ClearRequest](c)
+
+	profile, err := h.service.Clear(c.Context(), uint(merchantID), claims.UserID, input.Note)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "merchant cleared", profile)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/apiaccess"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MerchantSecurityHandler exposes the merchant security page: the IP
+// allowlists and client certificates guarding the merchant API.
+type MerchantSecurityHandler struct {
+	service apiaccess.Service
+}
+
+// NewMerchantSecurityHandler creates a new MerchantSecurityHandler.
+func NewMerchantSecurityHandler(s apiaccess.Service) *MerchantSecurityHandler {
+	return &MerchantSecurityHandler{service: s}
+}
+
+// GetSettings returns the merchant's API access rules and recent rejections.
+func (h *MerchantSecurityHandler) GetSettings(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	settings, err := h.service.GetSettings(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "security settings retrieved", settings)
+}
+
+// AddIPRange allows an API key to be used from an address or CIDR block.
+func (h *MerchantSecurityHandler) AddIPRange(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[apiaccess.AddIPRangeInput](c)
+
+	ipRange, err := h.service.AddIPRange(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "IP range added", ipRange)
+}
+
+// RemoveIPRange removes an IP range from the allowlist.
+func (h *MerchantSecurityHandler) RemoveIPRange(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid IP range ID")
+	}
+
+	if err := h.service.RemoveIPRange(c.Context(), claims.UserID, uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "IP range removed", nil)
+}
+
+// AddCertificate registers a client certificate for mutual TLS.
+func (h *MerchantSecurityHandler) AddCertificate(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[apiaccess.AddCertificateInput](c)
+
+	cert, err := h.service.AddCertificate(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "client certificate added", cert)
+}
+
+// RemoveCertificate removes a registered client certificate.
+func (h *MerchantSecurityHandler) RemoveCertificate(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid certificate ID")
+	}
+
+	if err := h.service.RemoveCertificate(c.Context(), claims.UserID, uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "client certificate removed", nil)
+}
+
+// SetMTLS turns the mutual TLS requirement on or off.
+func (h *MerchantSecurityHandler) SetMTLS(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input apiaccess.SetMTLSInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	settings, err := h.service.SetRequireMTLS(c.Context(), claims.UserID, input.Require)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "mutual TLS requirement updated", settings)
+}
+
+// ListRejections lists the merchant API requests refused by the access rules.
+func (h *MerchantSecurityHandler) ListRejections(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	rejections, total, err := h.service.ListRejections(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, rejections))
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/oauth"
+	"orus/internal/services/user"
+	"orus/internal/services/wallet"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OAuthHandler lets users authorize third-party apps, and serves the apps
+// the token endpoints and the data their tokens were granted
+type OAuthHandler struct {
+	service oauth.Service
+	wallets wallet.Service
+	users   user.Service
+}
+
+func NewOAuthHandler(service oauth.Service, wallets wallet.Service, users user.Service) *OAuthHandler {
+	return &OAuthHandler{service: service, wallets: wallets, users: users}
+}
+
+// RegisterClient registers an app owned by the user. Its secret is only in
+// this response.
+func (h *OAuthHandler) RegisterClient(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[oauth.RegisterClientRequest](c)
+
+	client, err := h.service.RegisterClient(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "app registered, store its secret now as it won't be shown again", client)
+}
+
+// ListClients returns the apps the user registered
+func (h *OAuthHandler) ListClients(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	clients, err := h.service.ListClients(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "apps retrieved", clients)
+}
+
+// DeleteClient removes one of the user's apps
+func (h *OAuthHandler) DeleteClient(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	if err := h.service.DeleteClient(c.Context(), claims.UserID, c.Params("clientId")); err != nil {
+		return err
+	}
+
+	return response.Success(c, "app deleted", nil)
+}
+
+// Consent returns what the consent screen shows for the authorization
+// request in the query string
+func (h *OAuthHandler) Consent(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var req oauth.AuthorizeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request")
+	}
+
+	consent, err := h.service.Consent(c.Context(), claims.UserID, req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	return response.Success(c, "consent required", consent)
+}
+
+// Authorize records the user's decision on the consent screen and returns
+// where to send them back to the app
+func (h *OAuthHandler) Authorize(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var decision oauth.ConsentDecision
+	if err := c.BodyParser(&decision); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	redirect, err := h.service.Authorize(c.Context(), claims.UserID, decision)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "authorization recorded", redirect)
+}
+
+// Token is the token endpoint apps exchange authorization codes and
+// refresh tokens at
+func (h *OAuthHandler) Token(c *fiber.Ctx) error {
+	var req oauth.TokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthError(c, &oauth.Error{Code: "invalid_request", Status: fiber.StatusBadRequest})
+	}
+	if creds, ok := basicCredentials(c); ok {
+		req.ClientID, req.ClientSecret = creds.ClientID, creds.ClientSecret
+	}
+
+	tokens, err := h.service.Token(c.Context(), req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(tokens)
+}
+
+// Introspect tells an app whether one of its tokens is active
+func (h *OAuthHandler) Introspect(c *fiber.Ctx) error {
+	introspection, err := h.service.Introspect(c.Context(), clientCredentials(c), c.FormValue("token"))
+	if err != nil {
+		return oauthError(c, err)
+	}
+	return c.JSON(introspection)
+}
+
+// Revoke revokes one of an app's tokens
+func (h *OAuthHandler) Revoke(c *fiber.Ctx) error {
+	if err := h.service.Revoke(c.Context(), clientCredentials(c), c.FormValue("token")); err != nil {
+		return oauthError(c, err)
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// ListConnections returns the apps the user has authorized
+func (h *OAuthHandler) ListConnections(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	grants, err := h.service.ListConnections(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "connected apps retrieved", grants)
+}
+
+// Disconnect withdraws the user's authorization of an app
+func (h *OAuthHandler) Disconnect(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	if err := h.service.Disconnect(c.Context(), claims.UserID, c.Params("clientId")); err != nil {
+		return err
+	}
+
+	return response.Success(c, "app disconnected", nil)
+}
+
+// ListPaymentRequests returns the payments apps asked the user for,
+// optionally only those with ?status=
+func (h *OAuthHandler) ListPaymentRequests(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	reqs, err := h.service.ListPaymentRequests(c.Context(), claims.UserID, c.Query("status"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment requests retrieved", reqs)
+}
+
+// ApprovePaymentRequest sends a payment an app asked for. Large payments
+// need the payment PIN like any transfer.
+func (h *OAuthHandler) ApprovePaymentRequest(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid payment request ID")
+	}
+
+	ctx := withPaymentPIN(context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role), c)
+	payment, err := h.service.ApprovePaymentRequest(ctx, claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment request approved", payment)
+}
+
+// DeclinePaymentRequest refuses a payment an app asked for
+func (h *OAuthHandler) DeclinePaymentRequest(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid payment request ID")
+	}
+
+	payment, err := h.service.DeclinePaymentRequest(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment request declined", payment)
+}
+
+// ConnectBalance returns the balance of the user the app's token is for
+func (h *OAuthHandler) ConnectBalance(c *fiber.Ctx) error {
+	token := c.Locals("oauth").(*models.OAuthToken)
+
+	balance, err := h.wallets.GetBalanceDetails(c.Context(), token.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "balance retrieved", balance)
+}
+
+// ConnectTransactions returns the transactions of the user the app's token
+// is for
+func (h *OAuthHandler) ConnectTransactions(c *fiber.Ctx) error {
+	token := c.Locals("oauth").(*models.OAuthToken)
+
+	p := pagination.ParseFromRequest(c)
+	transactions, total, err := h.users.GetTransactions(token.UserID, p.Limit, p.Offset, false)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, transactions))
+}
+
+// ConnectRequestPayment asks the user for a payment, which they approve in
+// the wallet before anything is sent
+func (h *OAuthHandler) ConnectRequestPayment(c *fiber.Ctx) error {
+	token := c.Locals("oauth").(*models.OAuthToken)
+	input := middleware.Body[oauth.PaymentRequest](c)
+
+	payment, err := h.service.RequestPayment(c.Context(), token, *input)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return response.Success(c, "payment requested, awaiting the user's approval", payment)
+}
+
+// clientCredentials reads an app's credentials from HTTP Basic
+// authentication, or failing that from the form
+func clientCredentials(c *fiber.Ctx) oauth.ClientCredentials {
+	if creds, ok := basicCredentials(c); ok {
+		return creds
+	}
+	return oauth.ClientCredentials{ClientID: c.FormValue("client_id"), ClientSecret: c.FormValue("client_secret")}
+}
+
+// basicCredentials reads HTTP Basic credentials, which RFC 6749 section
+// 2.3.1 has apps form-encode before joining
+func basicCredentials(c *fiber.Ctx) (oauth.ClientCredentials, bool) {
+	encoded, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Basic ")
+	if !ok {
+		return oauth.ClientCredentials{}, false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return oauth.ClientCredentials{}, false
+	}
+	id, secret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return oauth.ClientCredentials{}, false
+	}
+	id, idErr := url.QueryUnescape(id)
+	secret, secretErr := url.QueryUnescape(secret)
+	if idErr != nil || secretErr != nil {
+		return oauth.ClientCredentials{}, false
+	}
+	return oauth.ClientCredentials{ClientID: id, ClientSecret: secret}, true
+}
+
+// oauthError writes the OAuth errors apps expect from the token endpoints,
+// leaving any other error to the error handler
+func oauthError(c *fiber.Ctx, err error) error {
+	var oauthErr *oauth.Error
+	if !errors.As(err, &oauthErr) {
+		return err
+	}
+	if oauthErr.Status == fiber.StatusUnauthorized {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="orus"`)
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(oauthErr.Status).JSON(oauthErr)
+}
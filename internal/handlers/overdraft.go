@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/overdraft"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OverdraftHandler exposes wallet overdraft policy to admins.
+type OverdraftHandler struct {
+	service overdraft.Service
+}
+
+// NewOverdraftHandler creates a new OverdraftHandler.
+func NewOverdraftHandler(s overdraft.Service) *OverdraftHandler {
+	return &OverdraftHandler{service: s}
+}
+
+// SetOverdraftLimit sets how far below zero a user's wallet may go.
+func (h *OverdraftHandler) SetOverdraftLimit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var input struct {
+		Limit float64 `json:"limit"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	wallet, err := h.service.SetLimit(c.Context(), claims.UserID, uint(userID), input.Limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Overdraft limit updated", wallet)
+}
+
+// GetNegativeWallets reports the wallets below zero, most overdrawn first.
+func (h *OverdraftHandler) GetNegativeWallets(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	report, err := h.service.Report(c.Context(), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "negative balance report retrieved", report)
+}
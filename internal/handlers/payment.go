@@ -3,26 +3,43 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"log"
+	apperrors "orus/internal/errors"
+	"orus/internal/middleware"
 	"orus/internal/models"
+	"orus/internal/services/handle"
+	"orus/internal/services/loyalty"
 	"orus/internal/services/payment"
 	qr "orus/internal/services/qr_code"
 	"orus/internal/services/wallet"
 	"orus/internal/utils"
 	"orus/internal/utils/response"
-	"orus/internal/validation"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// SendMoneyRequest is a P2P transfer to a user named by ID, or by @handle
+// or phone number
+type SendMoneyRequest struct {
+	ReceiverID  uint    `json:"receiver_id" validate:"required_without=To"`
+	To          string  `json:"to"`
+	Amount      float64 `json:"amount" validate:"gt=0"`
+	Description string  `json:"description" validate:"max=255"`
+}
+
 type PaymentHandler struct {
 	qrService      qr.Service
 	paymentService payment.Service
+	handleService  handle.Service
+	loyaltyService loyalty.Service
 }
 
-func NewPaymentHandler(qrSvc qr.Service, paymentSvc payment.Service) *PaymentHandler {
+func NewPaymentHandler(qrSvc qr.Service, paymentSvc payment.Service, handleSvc handle.Service, loyaltySvc loyalty.Service) *PaymentHandler {
 	return &PaymentHandler{
 		qrService:      qrSvc,
 		paymentService: paymentSvc,
+		handleService:  handleSvc,
+		loyaltyService: loyaltySvc,
 	}
 }
 
@@ -30,19 +47,7 @@ func NewPaymentHandler(qrSvc qr.Service, paymentSvc payment.Service) *PaymentHan
 func (h *PaymentHandler) ProcessQRPayment(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 
-	var input models.QRPaymentRequest
-
-	if err := c.BodyParser(&input); err != nil {
-		return utils.BadRequest(c, "Invalid request format")
-	}
-
-	v := validation.New()
-	v.QRPayment(&input)
-	if !v.Valid() {
-		for _, msg := range v.Errors {
-			return utils.BadRequest(c, msg)
-		}
-	}
+	input := middleware.Body[models.QRPaymentRequest](c)
 
 	// Enrich metadata based on who is scanning
 	if input.Metadata == nil {
@@ -58,16 +63,48 @@ func (h *PaymentHandler) ProcessQRPayment(c *fiber.Ctx) error {
 		input.Description = fmt.Sprintf("User payment: %s", input.Description)
 	}
 
+	// Loyalty points come off the amount before it is charged and are
+	// handed back if the payment fails
+	amount := input.Amount
+	var redemption *loyalty.Redemption
+	if input.LoyaltyPoints > 0 {
+		if claims.Role == "merchant" {
+			return utils.BadRequest(c, "Loyalty points can only be redeemed by the payer")
+		}
+		merchantID, err := h.qrService.ValidateQRCode(c.Context(), input.QRCode, input.Amount)
+		if err != nil {
+			return apperrors.WithStatus(fiber.StatusBadRequest, err)
+		}
+		redemption, err = h.loyaltyService.Reserve(c.Context(), claims.UserID, merchantID, input.Amount, input.LoyaltyPoints)
+		if err != nil {
+			return err
+		}
+		amount = redemption.NetAmount
+		input.Metadata["loyalty_points"] = redemption.Points
+		input.Metadata["loyalty_discount"] = redemption.Discount
+		input.Metadata["gross_amount"] = input.Amount
+	}
+
 	tx, err := h.qrService.ProcessQRPayment(
 		c.Context(),
 		input.QRCode,
-		input.Amount,
+		amount,
 		claims.UserID,
 		input.Description,
 		input.Metadata,
 	)
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		if redemption != nil {
+			if releaseErr := h.loyaltyService.Release(c.Context(), redemption); releaseErr != nil {
+				log.Printf("Failed to return %d loyalty points to user %d: %v", redemption.Points, claims.UserID, releaseErr)
+			}
+		}
+		return apperrors.WithStatus(fiber.StatusBadRequest, err)
+	}
+	if redemption != nil {
+		if err := h.loyaltyService.Confirm(c.Context(), redemption, tx.ID); err != nil {
+			log.Printf("Failed to link loyalty redemption to transaction %d: %v", tx.ID, err)
+		}
 	}
 
 	return response.Success(c, "Payment successful", tx)
@@ -77,18 +114,18 @@ func (h *PaymentHandler) ProcessQRPayment(c *fiber.Ctx) error {
 func (h *PaymentHandler) SendMoney(c *fiber.Ctx) error {
 	claims := c.Locals("claims").(*models.UserClaims)
 
-	var input struct {
-		ReceiverID  uint    `json:"receiver_id"`
-		Amount      float64 `json:"amount"`
-		Description string  `json:"description"`
-	}
+	input := middleware.Body[SendMoneyRequest](c)
 
-	if err := c.BodyParser(&input); err != nil {
-		return utils.BadRequest(c, "Invalid request format")
+	if input.ReceiverID == 0 && input.To != "" {
+		recipient, err := h.handleService.Resolve(c.Context(), claims.UserID, input.To)
+		if err != nil {
+			return err
+		}
+		input.ReceiverID = recipient.UserID
 	}
 
-	// Create context with user role
-	ctx := context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role)
+	// Create context with user role and the payment PIN for large transfers
+	ctx := withPaymentPIN(context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role), c)
 
 	// Debug log
 	fmt.Printf("SendMoney - User Role: %s, From: %d, To: %d, Amount: %.2f\n",
@@ -102,26 +139,18 @@ func (h *PaymentHandler) SendMoney(c *fiber.Ctx) error {
 		input.Description,
 	)
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return apperrors.WithStatus(fiber.StatusBadRequest, err)
 	}
 
+	if tx.Status == models.TransactionStatusAwaitingConfirmation {
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "Transfer awaiting approval on your trusted device", tx)
+	}
 	return response.Success(c, "Transfer successful", tx)
 }
 
 func (h *PaymentHandler) ProcessPayment(c *fiber.Ctx) error {
-	var req models.PaymentRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request format")
-	}
-
-	v := validation.New()
-	v.Payment(&req) // Use the Payment validation method
-
-	if !v.Valid() {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"errors": v.Errors,
-		})
-	}
+	req := middleware.Body[models.PaymentRequest](c)
 
 	// Get user ID from context
 	claims := c.Locals("claims").(*models.UserClaims)
@@ -129,8 +158,8 @@ func (h *PaymentHandler) ProcessPayment(c *fiber.Ctx) error {
 		return response.Unauthorized(c)
 	}
 
-	// Create context with user role
-	ctx := context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role)
+	// Create context with user role and the payment PIN for large transfers
+	ctx := withPaymentPIN(context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role), c)
 
 	// Process payment based on type
 	var result *models.Transaction
@@ -156,9 +185,13 @@ func (h *PaymentHandler) ProcessPayment(c *fiber.Ctx) error {
 	}
 
 	if err != nil {
-		return response.ServerError(c, err.Error())
+		return err
 	}
 
+	if result.Status == models.TransactionStatusAwaitingConfirmation {
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "Payment awaiting approval on your trusted device", result)
+	}
 	return response.Success(c, "Payment processed successfully", result)
 }
 
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/paymentcode"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentCodeHandler issues numeric one-time payment codes, for paying at
+// tills that can't scan a QR code.
+type PaymentCodeHandler struct {
+	service paymentcode.Service
+}
+
+// NewPaymentCodeHandler creates a new PaymentCodeHandler.
+func NewPaymentCodeHandler(s paymentcode.Service) *PaymentCodeHandler {
+	return &PaymentCodeHandler{service: s}
+}
+
+// GenerateCode issues a code for up to the given amount. The code is only
+// shown in this response.
+func (h *PaymentCodeHandler) GenerateCode(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[paymentcode.GenerateRequest](c)
+
+	code, err := h.service.Generate(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Payment code generated", code)
+}
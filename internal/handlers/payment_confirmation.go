@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/confirmation"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConfirmationHandler lets users choose how they hear about their
+// completed payments.
+type ConfirmationHandler struct {
+	service confirmation.Service
+}
+
+// NewConfirmationHandler creates a new ConfirmationHandler.
+func NewConfirmationHandler(s confirmation.Service) *ConfirmationHandler {
+	return &ConfirmationHandler{service: s}
+}
+
+// GetSettings returns the user's payment confirmation channel and opt-out.
+func (h *ConfirmationHandler) GetSettings(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	settings, err := h.service.GetSettings(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment confirmation settings retrieved", settings)
+}
+
+// UpdateSettings switches the user's confirmations between email and push,
+// or turns them off.
+func (h *ConfirmationHandler) UpdateSettings(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input confirmation.SettingsRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	settings, err := h.service.UpdateSettings(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment confirmation settings updated", settings)
+}
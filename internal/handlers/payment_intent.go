@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/paymentintent"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentIntentHandler issues and pays signed payment intents, shared as
+// links or NFC tags that open the app with the payment pre-filled.
+type PaymentIntentHandler struct {
+	service paymentintent.Service
+}
+
+// NewPaymentIntentHandler creates a new PaymentIntentHandler.
+func NewPaymentIntentHandler(s paymentintent.Service) *PaymentIntentHandler {
+	return &PaymentIntentHandler{service: s}
+}
+
+// CreateIntent asks for a payment to the user, returning its token, link
+// and app URI.
+func (h *PaymentIntentHandler) CreateIntent(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[paymentintent.CreateRequest](c)
+
+	intent, err := h.service.Create(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Payment intent created", intent)
+}
+
+// GetIntent shows the payment behind a token so the payer can review it.
+func (h *PaymentIntentHandler) GetIntent(c *fiber.Ctx) error {
+	intent, err := h.service.Preview(c.Context(), c.Params("token"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Payment intent retrieved", intent)
+}
+
+// ConfirmIntent pays the intent behind a token from the user's wallet.
+func (h *PaymentIntentHandler) ConfirmIntent(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[paymentintent.ConfirmRequest](c)
+
+	tx, err := h.service.Confirm(c.Context(), claims.UserID, input.Token)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Payment processed successfully", tx)
+}
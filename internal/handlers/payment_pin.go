@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/paymentpin"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentPINHeader carries the payment PIN on requests that may need it
+const PaymentPINHeader = "X-Payment-PIN"
+
+// PaymentPINHandler lets users set, change and reset their payment PIN.
+type PaymentPINHandler struct {
+	service paymentpin.Service
+}
+
+// NewPaymentPINHandler creates a new PaymentPINHandler.
+func NewPaymentPINHandler(s paymentpin.Service) *PaymentPINHandler {
+	return &PaymentPINHandler{service: s}
+}
+
+// GetStatus says whether the user has a payment PIN and whether it is
+// locked.
+func (h *PaymentPINHandler) GetStatus(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	status, err := h.service.Status(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment PIN status retrieved", status)
+}
+
+// SetPIN sets the user's first payment PIN.
+func (h *PaymentPINHandler) SetPIN(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[paymentpin.SetRequest](c)
+
+	if err := h.service.Set(c.Context(), claims.UserID, *input); err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment PIN set", nil)
+}
+
+// ChangePIN replaces the payment PIN, given the current one.
+func (h *PaymentPINHandler) ChangePIN(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[paymentpin.ChangeRequest](c)
+
+	if err := h.service.Change(c.Context(), claims.UserID, *input); err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment PIN changed", nil)
+}
+
+// ResetPIN replaces a forgotten or locked payment PIN, given the password.
+func (h *PaymentPINHandler) ResetPIN(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[paymentpin.ResetRequest](c)
+
+	if err := h.service.Reset(c.Context(), claims.UserID, *input); err != nil {
+		return err
+	}
+
+	return response.Success(c, "payment PIN reset", nil)
+}
+
+// withPaymentPIN attaches the PIN sent in the X-Payment-PIN header to ctx
+func withPaymentPIN(ctx context.Context, c *fiber.Ctx) context.Context {
+	return paymentpin.WithPIN(ctx, c.Get(PaymentPINHeader))
+}
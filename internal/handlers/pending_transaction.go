@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"orus/internal/services/pendingtx"
+	"orus/internal/utils/pagination"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PendingTransactionHandler shows admins the transactions stuck pending
+// before the cleanup job expires them.
+type PendingTransactionHandler struct {
+	service pendingtx.Service
+}
+
+// NewPendingTransactionHandler creates a new PendingTransactionHandler.
+func NewPendingTransactionHandler(s pendingtx.Service) *PendingTransactionHandler {
+	return &PendingTransactionHandler{service: s}
+}
+
+// ListPending pages through the transactions pending longer than
+// ?older_than (a duration such as 30m or 2h, an hour by default), oldest
+// first, with when each expires.
+func (h *PendingTransactionHandler) ListPending(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	var olderThan time.Duration
+	if raw := c.Query("older_than"); raw != "" {
+		var err error
+		if olderThan, err = time.ParseDuration(raw); err != nil || olderThan <= 0 {
+			return pendingtx.ErrInvalidAge
+		}
+	}
+
+	items, total, err := h.service.List(c.Context(), olderThan, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, items))
+}
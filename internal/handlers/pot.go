@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/pot"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PotHandler exposes savings pots set aside from the user's wallet.
+type PotHandler struct {
+	service pot.Service
+}
+
+// NewPotHandler creates a new PotHandler.
+func NewPotHandler(s pot.Service) *PotHandler {
+	return &PotHandler{service: s}
+}
+
+// CreatePot opens a new savings pot.
+func (h *PotHandler) CreatePot(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input pot.PotRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	p, err := h.service.Create(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "pot created", p)
+}
+
+// GetPots lists the user's pots. Pass ?include_closed=true for closed ones too.
+func (h *PotHandler) GetPots(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	pots, err := h.service.List(c.Context(), claims.UserID, c.QueryBool("include_closed"))
+	if err != nil {
+		return response.ServerError(c, "failed to get pots")
+	}
+
+	return response.Success(c, "pots retrieved", pots)
+}
+
+// GetPot returns a pot with its progress towards the goal.
+func (h *PotHandler) GetPot(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	potID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid pot ID")
+	}
+
+	p, err := h.service.Get(c.Context(), claims.UserID, uint(potID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "pot retrieved", p)
+}
+
+// UpdatePot changes a pot's goal, lock, round-up or sweep settings.
+func (h *PotHandler) UpdatePot(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	potID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid pot ID")
+	}
+
+	var input pot.PotRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	p, err := h.service.Update(c.Context(), claims.UserID, uint(potID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "pot updated", p)
+}
+
+// ClosePot returns the pot's balance to the wallet and closes it.
+func (h *PotHandler) ClosePot(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	potID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid pot ID")
+	}
+
+	p, err := h.service.Close(c.Context(), claims.UserID, uint(potID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "pot closed", p)
+}
+
+// Deposit moves money from the main balance into a pot.
+func (h *PotHandler) Deposit(c *fiber.Ctx) error {
+	return h.move(c, true)
+}
+
+// Withdraw moves money from a pot back to the main balance.
+func (h *PotHandler) Withdraw(c *fiber.Ctx) error {
+	return h.move(c, false)
+}
+
+func (h *PotHandler) move(c *fiber.Ctx, deposit bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	potID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid pot ID")
+	}
+
+	var input pot.AmountRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	var result *pot.MoveResult
+	message := "money moved to pot"
+	if deposit {
+		result, err = h.service.Deposit(c.Context(), claims.UserID, uint(potID), input.Amount)
+	} else {
+		result, err = h.service.Withdraw(c.Context(), claims.UserID, uint(potID), input.Amount)
+		message = "money moved from pot"
+	}
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, message, result)
+}
+
+// GetPotEntries lists money moved into and out of a pot.
+func (h *PotHandler) GetPotEntries(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	potID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid pot ID")
+	}
+
+	entries, total, err := h.service.Entries(c.Context(), claims.UserID, uint(potID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, entries))
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/promotion"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PromotionHandler exposes promotional campaigns to admins and their
+// offers and rewards to customers.
+type PromotionHandler struct {
+	service promotion.Service
+}
+
+// NewPromotionHandler creates a new PromotionHandler.
+func NewPromotionHandler(s promotion.Service) *PromotionHandler {
+	return &PromotionHandler{service: s}
+}
+
+// CreatePromotion starts a new campaign.
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input promotion.CreateRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	created, err := h.service.Create(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "promotion created", created)
+}
+
+// ListPromotions lists every campaign, optionally filtered by status.
+func (h *PromotionHandler) ListPromotions(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	promotions, total, err := h.service.List(c.Context(), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, promotions))
+}
+
+// GetPromotion returns a campaign with its budget usage.
+func (h *PromotionHandler) GetPromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid promotion ID")
+	}
+
+	found, err := h.service.Get(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "promotion retrieved", found)
+}
+
+// ListPromotionRewards lists the rewards paid out under a campaign.
+func (h *PromotionHandler) ListPromotionRewards(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid promotion ID")
+	}
+	p := pagination.ParseFromRequest(c)
+
+	rewards, total, err := h.service.ListRewards(c.Context(), uint(id), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, rewards))
+}
+
+// PausePromotion stops a campaign paying rewards until it is resumed.
+func (h *PromotionHandler) PausePromotion(c *fiber.Ctx) error {
+	return h.transition(c, h.service.Pause, "promotion paused")
+}
+
+// ResumePromotion restarts a paused campaign.
+func (h *PromotionHandler) ResumePromotion(c *fiber.Ctx) error {
+	return h.transition(c, h.service.Resume, "promotion resumed")
+}
+
+// EndPromotion closes a campaign for good.
+func (h *PromotionHandler) EndPromotion(c *fiber.Ctx) error {
+	return h.transition(c, h.service.End, "promotion ended")
+}
+
+func (h *PromotionHandler) transition(
+	c *fiber.Ctx,
+	action func(ctx context.Context, promotionID uint) (*models.Promotion, error),
+	message string,
+) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid promotion ID")
+	}
+
+	updated, err := action(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, message, updated)
+}
+
+// GetOffers lists the promotions the customer can currently earn.
+func (h *PromotionHandler) GetOffers(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	offers, err := h.service.Available(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "promotions retrieved", offers)
+}
+
+// RedeemCode enrolls the customer in the promotion behind a promo code.
+func (h *PromotionHandler) RedeemCode(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	offer, err := h.service.Redeem(c.Context(), claims.UserID, input.Code)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "promo code redeemed", offer)
+}
+
+// GetRewards lists the rewards the customer has earned.
+func (h *PromotionHandler) GetRewards(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	rewards, total, err := h.service.Rewards(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, rewards))
+}
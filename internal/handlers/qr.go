@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"fmt"
+	"orus/internal/middleware"
 	qr "orus/internal/services/qr_code"
 	"orus/internal/utils/response"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// RevokeQRRequest says why a QR code is being revoked
+type RevokeQRRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
 type QRHandler struct {
 	qrService qr.Service
 }
@@ -41,14 +48,112 @@ func (h *QRHandler) GetPaymentQR(c *fiber.Ctx) error {
 	return response.Success(c, "Payment QR code retrieved", qrCode)
 }
 
-// GetUserQRCodes gets all QR codes for a user
+// GetUserQRCodes gets a user's QR codes with their usage, optionally
+// filtered by ?status=
 func (h *QRHandler) GetUserQRCodes(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(uint)
 
-	qrCodes, err := h.qrService.GetUserQRCodes(c.Context(), userID)
+	qrCodes, err := h.qrService.GetUserQRCodes(c.Context(), userID, c.Query("status"))
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "Failed to get QR codes")
+		return err
 	}
 
 	return response.Success(c, "QR codes retrieved", qrCodes)
 }
+
+// GetQRCodeStats reports a QR code's payments, payers and remaining limits
+func (h *QRHandler) GetQRCodeStats(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	qrID, err := c.ParamsInt("id")
+	if err != nil || qrID <= 0 {
+		return response.BadRequest(c, "invalid QR code ID")
+	}
+
+	stats, err := h.qrService.GetQRCodeStats(c.Context(), userID, uint(qrID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "QR code stats retrieved", stats)
+}
+
+// RevokeQRCode stops a compromised QR code from being accepted
+func (h *QRHandler) RevokeQRCode(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	qrID, err := c.ParamsInt("id")
+	if err != nil || qrID <= 0 {
+		return response.BadRequest(c, "invalid QR code ID")
+	}
+	input := middleware.Body[RevokeQRRequest](c)
+
+	qrCode, err := h.qrService.RevokeQRCode(c.Context(), userID, uint(qrID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "QR code revoked", qrCode)
+}
+
+// RegenerateQRCode replaces a receive or payment QR code with a new one,
+// revoking the old code
+func (h *QRHandler) RegenerateQRCode(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	qrID, err := c.ParamsInt("id")
+	if err != nil || qrID <= 0 {
+		return response.BadRequest(c, "invalid QR code ID")
+	}
+
+	qrCode, err := h.qrService.RegenerateQRCode(c.Context(), userID, uint(qrID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "QR code regenerated", qrCode)
+}
+
+// CreatePoster makes a printable QR code for one of the merchant's tills
+// or locations
+func (h *QRHandler) CreatePoster(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	poster, err := h.qrService.CreatePoster(c.Context(), userID, *middleware.Body[qr.PosterRequest](c))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "QR poster created", poster)
+}
+
+// ListPosters lists the merchant's active QR posters
+func (h *QRHandler) ListPosters(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	posters, err := h.qrService.ListPosters(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "QR posters retrieved", posters)
+}
+
+// GetPosterPDF downloads one of the merchant's QR posters as a printable PDF
+func (h *QRHandler) GetPosterPDF(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	qrID, err := c.ParamsInt("id")
+	if err != nil || qrID <= 0 {
+		return response.BadRequest(c, "invalid QR code ID")
+	}
+
+	poster, pdf, err := h.qrService.RenderPoster(c.Context(), userID, uint(qrID))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("qr-poster-%d.pdf", poster.ID)))
+	return c.Send(pdf)
+}
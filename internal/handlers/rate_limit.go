@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/ratelimit"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitHandler lets admins review the rate limits and override them
+// for individual users and merchants.
+type RateLimitHandler struct {
+	service ratelimit.Service
+}
+
+// NewRateLimitHandler creates a new RateLimitHandler.
+func NewRateLimitHandler(s ratelimit.Service) *RateLimitHandler {
+	return &RateLimitHandler{service: s}
+}
+
+// GetSettings returns the window and the default budget of each class.
+func (h *RateLimitHandler) GetSettings(c *fiber.Ctx) error {
+	return response.Success(c, "rate limits retrieved", h.service.Settings())
+}
+
+// ListOverrides lists the per-subject budgets admins have set.
+func (h *RateLimitHandler) ListOverrides(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	overrides, total, err := h.service.ListOverrides(c.Context(), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, overrides))
+}
+
+// SetOverride sets a user's or merchant's budget for one endpoint class.
+func (h *RateLimitHandler) SetOverride(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input ratelimit.OverrideRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	override, err := h.service.SetOverride(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "rate limit override saved", override)
+}
+
+// DeleteOverride returns a subject to the default budget.
+func (h *RateLimitHandler) DeleteOverride(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid override ID")
+	}
+
+	if err := h.service.DeleteOverride(c.Context(), uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "rate limit override removed", nil)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/services/receipt"
+	"orus/internal/utils/response"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReceiptHandler exposes itemized receipts for merchant payments.
+type ReceiptHandler struct {
+	service receipt.Service
+}
+
+// NewReceiptHandler creates a new ReceiptHandler.
+func NewReceiptHandler(s receipt.Service) *ReceiptHandler {
+	return &ReceiptHandler{service: s}
+}
+
+// GetReceipt returns a transaction's receipt as JSON, or as a PDF when
+// requested with ?format=pdf or an Accept: application/pdf header.
+func (h *ReceiptHandler) GetReceipt(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	txID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid transaction ID")
+	}
+
+	if c.Query("format") == "pdf" || strings.Contains(c.Get(fiber.HeaderAccept), "application/pdf") {
+		r, pdf, err := h.service.RenderPDF(c.Context(), claims.UserID, uint(txID))
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", r.Number+".pdf"))
+		return c.Send(pdf)
+	}
+
+	r, err := h.service.GetForTransaction(c.Context(), claims.UserID, uint(txID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "receipt retrieved", r)
+}
+
+// EmailReceipt emails a transaction's receipt, by default to the user's own address.
+func (h *ReceiptHandler) EmailReceipt(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	txID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid transaction ID")
+	}
+
+	var input struct {
+		Email string `json:"email"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	r, err := h.service.Email(c.Context(), claims.UserID, uint(txID), input.Email)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "receipt sent", r)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/refundrequest"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RefundRequestHandler lets customers ask merchants for refunds, and
+// merchants set their refund policy and decide the requests it leaves to
+// them.
+type RefundRequestHandler struct {
+	service refundrequest.Service
+}
+
+// NewRefundRequestHandler creates a new RefundRequestHandler.
+func NewRefundRequestHandler(s refundrequest.Service) *RefundRequestHandler {
+	return &RefundRequestHandler{service: s}
+}
+
+// Request asks for a refund of one of the user's charges. It is refunded
+// straight away within the merchant's policy, otherwise accepted for the
+// merchant to decide.
+func (h *RefundRequestHandler) Request(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[refundrequest.CreateRequest](c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid transaction ID")
+	}
+
+	req, err := h.service.Request(c.Context(), claims.UserID, uint(id), *input)
+	if err != nil {
+		return err
+	}
+
+	if req.Status == models.RefundRequestRefunded {
+		return response.Success(c, "refund issued", req)
+	}
+	c.Status(fiber.StatusAccepted)
+	return response.Success(c, "refund requested, awaiting the merchant's decision", req)
+}
+
+// List returns the refunds the user asked for.
+func (h *RefundRequestHandler) List(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	p := pagination.ParseFromRequest(c)
+	reqs, total, err := h.service.ListForCustomer(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, reqs))
+}
+
+// Cancel withdraws one of the user's pending refund requests.
+func (h *RefundRequestHandler) Cancel(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid refund request ID")
+	}
+
+	req, err := h.service.Cancel(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "refund request canceled", req)
+}
+
+// GetPolicy returns the merchant's refund policy.
+func (h *RefundRequestHandler) GetPolicy(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	policy, err := h.service.GetPolicy(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "refund policy retrieved", policy)
+}
+
+// UpdatePolicy changes whether customers can ask for refunds, for how long
+// after a charge, and up to what amount they're paid without the merchant.
+func (h *RefundRequestHandler) UpdatePolicy(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[refundrequest.PolicyRequest](c)
+
+	policy, err := h.service.UpdatePolicy(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "refund policy updated", policy)
+}
+
+// ListForMerchant returns the refunds customers asked the merchant for,
+// optionally only those with ?status=
+func (h *RefundRequestHandler) ListForMerchant(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	p := pagination.ParseFromRequest(c)
+	reqs, total, err := h.service.ListForMerchant(c.Context(), claims.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, reqs))
+}
+
+// Approve refunds a customer's pending request from the merchant's wallet.
+func (h *RefundRequestHandler) Approve(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[refundrequest.DecisionRequest](c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid refund request ID")
+	}
+
+	req, err := h.service.Approve(c.Context(), claims.UserID, uint(id), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "refund issued", req)
+}
+
+// Reject turns down a customer's pending request.
+func (h *RefundRequestHandler) Reject(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[refundrequest.DecisionRequest](c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid refund request ID")
+	}
+
+	req, err := h.service.Reject(c.Context(), claims.UserID, uint(id), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "refund request rejected", req)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/reserve"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReserveHandler shows merchants their rolling reserve and lets admins
+// set it.
+type ReserveHandler struct {
+	service reserve.Service
+}
+
+// NewReserveHandler creates a new ReserveHandler.
+func NewReserveHandler(s reserve.Service) *ReserveHandler {
+	return &ReserveHandler{service: s}
+}
+
+// GetReserve returns the merchant's reserve, what is held and when it is
+// released.
+func (h *ReserveHandler) GetReserve(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	summary, err := h.service.Get(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "reserve retrieved", summary)
+}
+
+// ListEntries pages through the amounts withheld from the merchant's
+// payments, latest first.
+func (h *ReserveHandler) ListEntries(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	entries, total, err := h.service.ListEntries(c.Context(), claims.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, entries))
+}
+
+// AdminGetReserve returns a merchant's reserve.
+func (h *ReserveHandler) AdminGetReserve(c *fiber.Ctx) error {
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+
+	summary, err := h.service.Get(c.Context(), uint(merchantID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "reserve retrieved", summary)
+}
+
+// SetReserve gives a merchant their own reserve percentage and hold days.
+func (h *ReserveHandler) SetReserve(c *fiber.Ctx) error {
+	merchantID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid merchant ID")
+	}
+	input := middleware.Body[reserve.SetRequest](c)
+
+	summary, err := h.service.Set(c.Context(), uint(merchantID), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "reserve updated", summary)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/rbac"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleHandler lets admins manage roles and the permissions they grant.
+type RoleHandler struct {
+	service rbac.Service
+}
+
+// NewRoleHandler creates a new RoleHandler.
+func NewRoleHandler(s rbac.Service) *RoleHandler {
+	return &RoleHandler{service: s}
+}
+
+// ListRoles returns the built-in and custom roles with their permissions.
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.service.ListRoles(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "roles retrieved", roles)
+}
+
+// GetRole returns one role.
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	role, err := h.service.GetRole(c.Context(), c.Params("name"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "role retrieved", role)
+}
+
+// CreateRole adds a custom role.
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input rbac.CreateRoleRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	role, err := h.service.CreateRole(c.Context(), claims, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "role created", role)
+}
+
+// UpdateRole changes a custom role's description or permissions. Changing
+// its permissions logs its users out.
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input rbac.UpdateRoleRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	role, err := h.service.UpdateRole(c.Context(), claims, c.Params("name"), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "role updated", role)
+}
+
+// DeleteRole removes a custom role no user holds.
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	if err := h.service.DeleteRole(c.Context(), claims, c.Params("name")); err != nil {
+		return err
+	}
+
+	return response.Success(c, "role deleted", nil)
+}
+
+// ListPermissions returns every permission a role can grant.
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.service.ListPermissions(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "permissions retrieved", permissions)
+}
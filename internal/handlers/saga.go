@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"orus/internal/services/saga"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SagaHandler lets admins follow multi-step payments and retry the ones
+// whose compensation failed.
+type SagaHandler struct {
+	service saga.Service
+}
+
+// NewSagaHandler creates a new SagaHandler.
+func NewSagaHandler(s saga.Service) *SagaHandler {
+	return &SagaHandler{service: s}
+}
+
+// ListSagas pages through the sagas, newest first. ?status narrows it,
+// e.g. to requires_attention.
+func (h *SagaHandler) ListSagas(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	sagas, total, err := h.service.List(c.Context(), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, sagas))
+}
+
+// GetSaga returns a saga with its steps.
+func (h *SagaHandler) GetSaga(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid saga ID")
+	}
+
+	s, err := h.service.Get(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "saga retrieved", s)
+}
+
+// RetrySaga runs the compensation of a saga that requires attention
+// again.
+func (h *SagaHandler) RetrySaga(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid saga ID")
+	}
+
+	s, err := h.service.Retry(c.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "saga compensation retried", s)
+}
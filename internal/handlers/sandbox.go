@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/sandbox"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SandboxHandler exposes the sandbox API that merchants' test integrations
+// call with their sandbox API key.
+type SandboxHandler struct {
+	service sandbox.Service
+}
+
+// NewSandboxHandler creates a new SandboxHandler.
+func NewSandboxHandler(s sandbox.Service) *SandboxHandler {
+	return &SandboxHandler{service: s}
+}
+
+// CreateCustomer creates a sandbox customer with a simulated balance.
+func (h *SandboxHandler) CreateCustomer(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	var input sandbox.CustomerRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	customer, err := h.service.CreateCustomer(c.Context(), merchant, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox customer created", customer)
+}
+
+// ListCustomers lists the merchant's sandbox customers.
+func (h *SandboxHandler) ListCustomers(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+	p := pagination.ParseFromRequest(c)
+
+	customers, total, err := h.service.ListCustomers(c.Context(), merchant, p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, customers))
+}
+
+// GetCustomer returns a sandbox customer and their balance.
+func (h *SandboxHandler) GetCustomer(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	customerID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid customer ID")
+	}
+
+	customer, err := h.service.GetCustomer(c.Context(), merchant, uint(customerID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox customer retrieved", customer)
+}
+
+// FundCustomer adds simulated money to a sandbox customer's balance.
+func (h *SandboxHandler) FundCustomer(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	customerID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid customer ID")
+	}
+	var input sandbox.FundRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	customer, err := h.service.FundCustomer(c.Context(), merchant, uint(customerID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox customer funded", customer)
+}
+
+// CreateCharge takes a sandbox payment. Declined charges are returned with
+// status failed and a failure code.
+func (h *SandboxHandler) CreateCharge(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	var input sandbox.ChargeRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	charge, err := h.service.CreateCharge(c.Context(), merchant, input)
+	if err != nil {
+		return err
+	}
+
+	if charge.Status == models.SandboxStatusPending {
+		c.Status(fiber.StatusAccepted)
+	}
+	return response.Success(c, "sandbox charge created", charge)
+}
+
+// ListCharges lists the merchant's sandbox charges, optionally by status.
+func (h *SandboxHandler) ListCharges(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+	p := pagination.ParseFromRequest(c)
+
+	charges, total, err := h.service.ListCharges(c.Context(), merchant, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, charges))
+}
+
+// GetCharge returns a sandbox charge with its refunds.
+func (h *SandboxHandler) GetCharge(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	charge, err := h.service.GetCharge(c.Context(), merchant, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox charge retrieved", charge)
+}
+
+// SettleCharge resolves a pending bank charge as succeeded or failed.
+func (h *SandboxHandler) SettleCharge(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	var input sandbox.SettleRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	charge, err := h.service.SettleCharge(c.Context(), merchant, c.Params("id"), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox charge settled", charge)
+}
+
+// Refund returns part or all of a sandbox charge.
+func (h *SandboxHandler) Refund(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	var input sandbox.RefundRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	refund, err := h.service.Refund(c.Context(), merchant, c.Params("id"), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox refund created", refund)
+}
+
+// GetBalance sums the merchant's sandbox charges.
+func (h *SandboxHandler) GetBalance(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	balance, err := h.service.GetBalance(c.Context(), merchant)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox balance retrieved", balance)
+}
+
+// GetScenarios lists the test cards and failures the sandbox can simulate.
+func (h *SandboxHandler) GetScenarios(c *fiber.Ctx) error {
+	return response.Success(c, "sandbox scenarios retrieved", h.service.Scenarios())
+}
+
+// Reset deletes the merchant's sandbox customers, charges and refunds.
+func (h *SandboxHandler) Reset(c *fiber.Ctx) error {
+	merchant := c.Locals("merchant").(*models.Merchant)
+
+	if err := h.service.Reset(c.Context(), merchant); err != nil {
+		return err
+	}
+
+	return response.Success(c, "sandbox data reset", nil)
+}
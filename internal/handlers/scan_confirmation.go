@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/merchant"
+	"orus/internal/services/scanconfirm"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ScanConfirmationHandler lets customers approve the charges merchants take
+// by scanning their payment code.
+type ScanConfirmationHandler struct {
+	service   scanconfirm.Service
+	merchants *merchant.Service
+}
+
+// NewScanConfirmationHandler creates a new ScanConfirmationHandler.
+func NewScanConfirmationHandler(s scanconfirm.Service, merchants *merchant.Service) *ScanConfirmationHandler {
+	return &ScanConfirmationHandler{service: s, merchants: merchants}
+}
+
+// GetSettings returns whether the user approves merchant scans, and up to
+// what amount they don't.
+func (h *ScanConfirmationHandler) GetSettings(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	settings, err := h.service.GetSettings(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charge confirmation settings retrieved", settings)
+}
+
+// UpdateSettings turns approval of merchant scans on or off, or changes the
+// amount charges go through without it.
+func (h *ScanConfirmationHandler) UpdateSettings(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[scanconfirm.SettingsRequest](c)
+
+	settings, err := h.service.UpdateSettings(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charge confirmation settings updated", settings)
+}
+
+// ListPending returns the merchant charges waiting for the user.
+func (h *ScanConfirmationHandler) ListPending(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	confirmations, err := h.service.ListPending(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charges awaiting approval retrieved", confirmations)
+}
+
+// Approve takes a held charge.
+func (h *ScanConfirmationHandler) Approve(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid confirmation ID")
+	}
+
+	tx, err := h.merchants.ApproveScanCharge(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charge approved", tx)
+}
+
+// Decline cancels a held charge.
+func (h *ScanConfirmationHandler) Decline(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid confirmation ID")
+	}
+
+	confirmation, err := h.service.Decline(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charge declined", confirmation)
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/wallet"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SharedWalletHandler exposes family and team wallets owned by several users.
+type SharedWalletHandler struct {
+	service wallet.SharedService
+}
+
+// NewSharedWalletHandler creates a new SharedWalletHandler.
+func NewSharedWalletHandler(s wallet.SharedService) *SharedWalletHandler {
+	return &SharedWalletHandler{service: s}
+}
+
+// CreateSharedWallet creates a shared wallet owned by the caller.
+func (h *SharedWalletHandler) CreateSharedWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input wallet.SharedWalletRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	view, err := h.service.CreateSharedWallet(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "shared wallet created", view)
+}
+
+// GetSharedWallets lists the shared wallets the caller belongs to.
+func (h *SharedWalletHandler) GetSharedWallets(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	wallets, err := h.service.ListSharedWallets(c.Context(), claims.UserID)
+	if err != nil {
+		return response.ServerError(c, "failed to get shared wallets")
+	}
+
+	return response.Success(c, "shared wallets retrieved", wallets)
+}
+
+// GetSharedWallet returns a shared wallet with its members.
+func (h *SharedWalletHandler) GetSharedWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	view, err := h.service.GetSharedWallet(c.Context(), claims.UserID, uint(walletID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "shared wallet retrieved", view)
+}
+
+// UpdateSharedWallet renames a shared wallet or changes its approval threshold.
+func (h *SharedWalletHandler) UpdateSharedWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input wallet.SharedWalletRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	view, err := h.service.UpdateSharedWallet(c.Context(), claims.UserID, uint(walletID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "shared wallet updated", view)
+}
+
+// AddMember adds a user to a shared wallet.
+func (h *SharedWalletHandler) AddMember(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input wallet.MemberRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	member, err := h.service.AddMember(c.Context(), claims.UserID, uint(walletID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "member added", member)
+}
+
+// UpdateMember changes a member's role or spending limits.
+func (h *SharedWalletHandler) UpdateMember(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+
+	var input wallet.MemberRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	member, err := h.service.UpdateMember(c.Context(), claims.UserID, uint(walletID), uint(memberID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "member updated", member)
+}
+
+// RemoveMember removes a member, or lets the caller leave the wallet.
+func (h *SharedWalletHandler) RemoveMember(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+
+	if err := h.service.RemoveMember(c.Context(), claims.UserID, uint(walletID), uint(memberID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "member removed", nil)
+}
+
+// Contribute moves funds from the caller's wallet into the shared wallet.
+func (h *SharedWalletHandler) Contribute(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input struct {
+		Amount float64 `json:"amount"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	w, err := h.service.Contribute(c.Context(), claims.UserID, uint(walletID), input.Amount)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "funds added", w)
+}
+
+// Pay pays a user from the shared wallet.
+func (h *SharedWalletHandler) Pay(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	var input wallet.SharedPaymentRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	payment, err := h.service.Pay(c.Context(), claims.UserID, uint(walletID), input)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status == models.SharedPaymentPendingApproval {
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "payment awaiting owner approval", payment)
+	}
+	return response.Success(c, "payment sent", payment)
+}
+
+// GetPayments lists payments out of the shared wallet.
+func (h *SharedWalletHandler) GetPayments(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+
+	payments, total, err := h.service.ListPayments(c.Context(), claims.UserID, uint(walletID), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, payments))
+}
+
+// ApprovePayment sends a payment that was held for owner approval.
+func (h *SharedWalletHandler) ApprovePayment(c *fiber.Ctx) error {
+	return h.reviewPayment(c, true)
+}
+
+// RejectPayment declines a payment that was held for owner approval.
+func (h *SharedWalletHandler) RejectPayment(c *fiber.Ctx) error {
+	return h.reviewPayment(c, false)
+}
+
+func (h *SharedWalletHandler) reviewPayment(c *fiber.Ctx, approve bool) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	walletID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid wallet ID")
+	}
+	paymentID, err := strconv.ParseUint(c.Params("paymentId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid payment ID")
+	}
+
+	if approve {
+		payment, err := h.service.ApprovePayment(c.Context(), claims.UserID, uint(walletID), uint(paymentID))
+		if err != nil {
+			return err
+		}
+		return response.Success(c, "payment approved", payment)
+	}
+
+	payment, err := h.service.RejectPayment(c.Context(), claims.UserID, uint(walletID), uint(paymentID))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, "payment rejected", payment)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"orus/internal/models"
+	"orus/internal/services/auth"
+	"orus/internal/services/sociallogin"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SocialLoginHandler signs users in with Google and Apple. Tokens, cookies
+// and login challenges are the same as a password login's.
+type SocialLoginHandler struct {
+	service sociallogin.Service
+	auth    *AuthHandler
+}
+
+func NewSocialLoginHandler(service sociallogin.Service, auth *AuthHandler) *SocialLoginHandler {
+	return &SocialLoginHandler{service: service, auth: auth}
+}
+
+// ListProviders returns the providers client apps can show sign-in
+// buttons for
+func (h *SocialLoginHandler) ListProviders(c *fiber.Ctx) error {
+	return response.Success(c, "providers retrieved", h.service.Providers(c.Context()))
+}
+
+// stateCookie holds the state of the sign-in the browser started, so a
+// callback only completes in the browser that began it
+const stateCookie = "social_login_state"
+
+// Begin returns the provider's authorization URL to send the user to
+func (h *SocialLoginHandler) Begin(c *fiber.Ctx) error {
+	authorization, err := h.service.Begin(c.Context(), c.Params("provider"))
+	if err != nil {
+		return err
+	}
+	h.setStateCookie(c, authorization.State, int(sociallogin.StateTTL.Seconds()))
+	return response.Success(c, "authorization started", authorization)
+}
+
+// BeginLink starts a sign-in that links the provider account to the
+// signed-in user's
+func (h *SocialLoginHandler) BeginLink(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	authorization, err := h.service.BeginLink(c.Context(), c.Params("provider"), claims.UserID)
+	if err != nil {
+		return err
+	}
+	h.setStateCookie(c, authorization.State, int(sociallogin.StateTTL.Seconds()))
+	return response.Success(c, "authorization started", authorization)
+}
+
+// setStateCookie sets the state cookie, or clears it for a negative maxAge.
+// Apple posts its callback from its own site, which only sends a cookie
+// that is SameSite=None, and so Secure.
+func (h *SocialLoginHandler) setStateCookie(c *fiber.Ctx, state string, maxAge int) {
+	sameSite := "Lax"
+	if h.auth.secureCookies {
+		sameSite = "None"
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		HTTPOnly: true,
+		Secure:   h.auth.secureCookies,
+		Path:     "/api/login",
+		SameSite: sameSite,
+		MaxAge:   maxAge,
+	})
+}
+
+// Callback completes the sign-in the provider redirected back from:
+// Google with a query string, Apple with a form post
+func (h *SocialLoginHandler) Callback(c *fiber.Ctx) error {
+	var req sociallogin.CallbackRequest
+	if c.Method() == fiber.MethodPost {
+		if err := c.BodyParser(&req); err != nil {
+			return response.BadRequest(c, "Invalid request body")
+		}
+	} else if err := c.QueryParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request")
+	}
+
+	// A callback without the state this browser was given is someone
+	// else's sign-in, which would sign this browser in to their account
+	started := c.Cookies(stateCookie)
+	h.setStateCookie(c, "", -1)
+	if started == "" || subtle.ConstantTimeCompare([]byte(started), []byte(req.State)) != 1 {
+		return sociallogin.ErrInvalidState
+	}
+
+	result, err := h.service.Complete(c.Context(), c.Params("provider"), req, h.auth.loginContext(c))
+	if err != nil {
+		// Completed through /verify-otp, like a password login
+		if errors.Is(err, auth.ErrMFARequired) || errors.Is(err, auth.ErrStepUpRequired) {
+			return c.JSON(fiber.Map{
+				"mfa_required":     true,
+				"step_up_required": errors.Is(err, auth.ErrStepUpRequired),
+				"user_id":          result.User.ID,
+			})
+		}
+		return err
+	}
+
+	permissions, err := h.auth.authService.GetPermissions(result.User.Role)
+	if err != nil {
+		return err
+	}
+
+	h.auth.setAuthCookies(c, result.AccessToken, result.RefreshToken)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+		"created":       result.Created,
+		"linked":        result.Linked,
+		"user": fiber.Map{
+			"id":          result.User.ID,
+			"email":       result.User.Email,
+			"role":        result.User.Role,
+			"permissions": permissions,
+		},
+	})
+}
+
+// ListIdentities returns the Google and Apple accounts linked to the user
+func (h *SocialLoginHandler) ListIdentities(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	identities, err := h.service.ListIdentities(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, "linked accounts retrieved", identities)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/spendcontrol"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SpendingControlHandler lets users block or cap their spending at merchant
+// categories and foreign merchants, and organization admins do the same
+// for their members.
+type SpendingControlHandler struct {
+	service spendcontrol.Service
+}
+
+// NewSpendingControlHandler creates a new SpendingControlHandler.
+func NewSpendingControlHandler(s spendcontrol.Service) *SpendingControlHandler {
+	return &SpendingControlHandler{service: s}
+}
+
+// ListControls returns the user's spending controls, including those set
+// by their organizations.
+func (h *SpendingControlHandler) ListControls(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	controls, err := h.service.List(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "spending controls retrieved", controls)
+}
+
+// SetControl adds or replaces one of the user's spending controls.
+func (h *SpendingControlHandler) SetControl(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[spendcontrol.ControlRequest](c)
+
+	control, err := h.service.Set(c.Context(), claims.UserID, *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "spending control saved", control)
+}
+
+// DeleteControl removes one of the user's spending controls.
+func (h *SpendingControlHandler) DeleteControl(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	controlID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid spending control ID")
+	}
+
+	if err := h.service.Delete(c.Context(), claims.UserID, uint(controlID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "spending control removed", nil)
+}
+
+// ListMemberControls returns the spending controls an organization set on
+// its member.
+func (h *SpendingControlHandler) ListMemberControls(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+
+	controls, err := h.service.ListMember(c.Context(), claims.UserID, uint(orgID), uint(memberID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "spending controls retrieved", controls)
+}
+
+// SetMemberControl adds or replaces a spending control on an organization
+// member.
+func (h *SpendingControlHandler) SetMemberControl(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+	input := middleware.Body[spendcontrol.ControlRequest](c)
+
+	control, err := h.service.SetMember(c.Context(), claims.UserID, uint(orgID), uint(memberID), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "spending control saved", control)
+}
+
+// DeleteMemberControl removes a spending control from an organization
+// member.
+func (h *SpendingControlHandler) DeleteMemberControl(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	orgID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid organization ID")
+	}
+	memberID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid user ID")
+	}
+	controlID, err := strconv.ParseUint(c.Params("controlId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid spending control ID")
+	}
+
+	if err := h.service.DeleteMember(c.Context(), claims.UserID, uint(orgID), uint(memberID), uint(controlID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "spending control removed", nil)
+}
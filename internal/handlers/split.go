@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/split"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SplitHandler exposes split bill payments between users.
+type SplitHandler struct {
+	service split.Service
+}
+
+// NewSplitHandler creates a new SplitHandler.
+func NewSplitHandler(s split.Service) *SplitHandler {
+	return &SplitHandler{service: s}
+}
+
+// CreateSplit splits a transaction or amount with other users.
+func (h *SplitHandler) CreateSplit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input split.CreateRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	s, err := h.service.Create(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "split created", s)
+}
+
+// GetSplits lists splits the user organized or takes part in. Filter with
+// ?role=organizer or ?role=participant.
+func (h *SplitHandler) GetSplits(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	splits, total, err := h.service.List(c.Context(), claims.UserID, c.Query("role"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get splits")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, splits))
+}
+
+// GetSplit returns a split with the status of every share.
+func (h *SplitHandler) GetSplit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	splitID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid split ID")
+	}
+
+	s, err := h.service.Get(c.Context(), claims.UserID, uint(splitID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "split retrieved", s)
+}
+
+// PaySplit pays the user's share to the organizer.
+func (h *SplitHandler) PaySplit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	splitID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid split ID")
+	}
+
+	result, err := h.service.Pay(c.Context(), claims.UserID, uint(splitID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "split share paid", result)
+}
+
+// RemindSplit reminds participants who haven't paid.
+func (h *SplitHandler) RemindSplit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	splitID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid split ID")
+	}
+
+	sent, err := h.service.Remind(c.Context(), claims.UserID, uint(splitID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "reminders sent", fiber.Map{"reminders_sent": sent})
+}
+
+// CancelSplit cancels a split before anyone has paid.
+func (h *SplitHandler) CancelSplit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	splitID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid split ID")
+	}
+
+	s, err := h.service.Cancel(c.Context(), claims.UserID, uint(splitID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "split cancelled", s)
+}
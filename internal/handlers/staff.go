@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/merchant"
+	"orus/internal/services/staff"
+	"orus/internal/utils/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StaffHandler serves merchant staff management and the staff-side POS
+// endpoints.
+type StaffHandler struct {
+	service   staff.Service
+	merchants *merchant.Service
+}
+
+// NewStaffHandler creates a new StaffHandler.
+func NewStaffHandler(s staff.Service, merchants *merchant.Service) *StaffHandler {
+	return &StaffHandler{service: s, merchants: merchants}
+}
+
+// InviteStaff invites a user to operate the merchant's point of sale.
+func (h *StaffHandler) InviteStaff(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input staff.InviteRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	member, err := h.service.Invite(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "staff member invited", member)
+}
+
+// ListStaff lists the merchant's staff and pending invitations.
+func (h *StaffHandler) ListStaff(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	members, err := h.service.List(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "staff retrieved", members)
+}
+
+// UpdateStaff changes a staff member's name or scopes.
+func (h *StaffHandler) UpdateStaff(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	staffID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid staff ID")
+	}
+
+	var input staff.UpdateRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	member, err := h.service.Update(c.Context(), claims.UserID, uint(staffID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "staff member updated", member)
+}
+
+// RevokeStaff removes a staff member's access.
+func (h *StaffHandler) RevokeStaff(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	staffID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid staff ID")
+	}
+
+	if err := h.service.Revoke(c.Context(), claims.UserID, uint(staffID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "staff member revoked", nil)
+}
+
+// GetShiftReport breaks the merchant's takings down by operator.
+func (h *StaffHandler) GetShiftReport(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	return h.shiftReport(c, claims.UserID)
+}
+
+// ListMemberships lists the caller's staff positions and invitations.
+func (h *StaffHandler) ListMemberships(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	memberships, err := h.service.Memberships(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "memberships retrieved", memberships)
+}
+
+// AcceptInvite joins a merchant's staff.
+func (h *StaffHandler) AcceptInvite(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	staffID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid staff ID")
+	}
+
+	member, err := h.service.Accept(c.Context(), claims.UserID, uint(staffID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "invitation accepted", member)
+}
+
+// DeclineInvite turns down a merchant's staff invitation.
+func (h *StaffHandler) DeclineInvite(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	staffID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid staff ID")
+	}
+
+	if err := h.service.Decline(c.Context(), claims.UserID, uint(staffID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "invitation declined", nil)
+}
+
+// SetPIN sets the caller's operator PIN for one merchant.
+func (h *StaffHandler) SetPIN(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	staffID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid staff ID")
+	}
+
+	var input struct {
+		PIN string `json:"pin"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	if err := h.service.SetPIN(c.Context(), claims.UserID, uint(staffID), input.PIN); err != nil {
+		return err
+	}
+
+	return response.Success(c, "PIN updated", nil)
+}
+
+// StaffCharge charges a customer on the merchant's behalf. The operator
+// PIN is required and the charge is attributed to the caller.
+func (h *StaffHandler) StaffCharge(c *fiber.Ctx) error {
+	member, err := h.membership(c)
+	if err != nil {
+		return err
+	}
+
+	var input merchant.ChargeInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+	input.OperatorID = member.ID
+
+	tx, held, err := h.merchants.ProcessDirectCharge(member.MerchantUserID, input)
+	if err != nil {
+		return err
+	}
+
+	return chargeResponse(c, tx, held)
+}
+
+// StaffChargeConfirmation returns a charge held for the customer's approval.
+func (h *StaffHandler) StaffChargeConfirmation(c *fiber.Ctx) error {
+	member, err := h.membership(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("confirmationId"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid charge confirmation ID")
+	}
+
+	confirmation, err := h.merchants.GetScanCharge(c.Context(), member.MerchantUserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charge confirmation retrieved", confirmation)
+}
+
+// StaffRefund refunds a charge on the merchant's behalf.
+func (h *StaffHandler) StaffRefund(c *fiber.Ctx) error {
+	member, err := h.membership(c)
+	if err != nil {
+		return err
+	}
+
+	var input merchant.RefundInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+	input.OperatorID = member.ID
+
+	tx, err := h.merchants.RefundCharge(c.Context(), member.MerchantUserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Refund processed successfully", tx)
+}
+
+// StaffShiftReport shows the merchant's shift report to staff with the
+// reports scope.
+func (h *StaffHandler) StaffShiftReport(c *fiber.Ctx) error {
+	member, err := h.membership(c)
+	if err != nil {
+		return err
+	}
+	if !member.HasScope(models.StaffScopeReports) {
+		return staff.ErrScopeDenied
+	}
+	return h.shiftReport(c, member.MerchantUserID)
+}
+
+func (h *StaffHandler) membership(c *fiber.Ctx) (*models.MerchantStaff, error) {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	staffID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return nil, staff.ErrStaffNotFound
+	}
+	return h.service.GetMembership(c.Context(), claims.UserID, uint(staffID))
+}
+
+// shiftReport covers from to to (RFC 3339), defaulting to today so far.
+func (h *StaffHandler) shiftReport(c *fiber.Ctx, merchantUserID uint) error {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := now
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return response.BadRequest(c, "invalid from")
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return response.BadRequest(c, "invalid to")
+		}
+		to = t
+	}
+
+	report, err := h.service.ShiftReport(c.Context(), merchantUserID, from, to)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "shift report retrieved", report)
+}
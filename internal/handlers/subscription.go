@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/subscription"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SubscriptionHandler serves merchant subscription plans and customers'
+// subscriptions to them.
+type SubscriptionHandler struct {
+	service subscription.Service
+}
+
+// NewSubscriptionHandler creates a new SubscriptionHandler.
+func NewSubscriptionHandler(s subscription.Service) *SubscriptionHandler {
+	return &SubscriptionHandler{service: s}
+}
+
+// CreatePlan defines a new subscription plan for the merchant.
+func (h *SubscriptionHandler) CreatePlan(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input subscription.PlanRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	plan, err := h.service.CreatePlan(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription plan created", plan)
+}
+
+// ListPlans lists the merchant's plans, optionally by status.
+func (h *SubscriptionHandler) ListPlans(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	plans, total, err := h.service.ListPlans(c.Context(), claims.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get subscription plans")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, plans))
+}
+
+// GetPlan returns one of the merchant's plans.
+func (h *SubscriptionHandler) GetPlan(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	planID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid plan ID")
+	}
+
+	plan, err := h.service.GetPlan(c.Context(), claims.UserID, uint(planID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription plan retrieved", plan)
+}
+
+// UpdatePlan renames a plan or changes its description.
+func (h *SubscriptionHandler) UpdatePlan(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	planID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid plan ID")
+	}
+
+	var input subscription.PlanUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	plan, err := h.service.UpdatePlan(c.Context(), claims.UserID, uint(planID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription plan updated", plan)
+}
+
+// ArchivePlan closes a plan to new subscribers.
+func (h *SubscriptionHandler) ArchivePlan(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	planID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid plan ID")
+	}
+
+	plan, err := h.service.ArchivePlan(c.Context(), claims.UserID, uint(planID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription plan archived", plan)
+}
+
+// ListSubscribers lists subscriptions to the merchant's plans, optionally
+// by plan_id and status.
+func (h *SubscriptionHandler) ListSubscribers(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	var planID uint
+	if v := c.Query("plan_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return response.BadRequest(c, "invalid plan_id")
+		}
+		planID = uint(id)
+	}
+
+	subscriptions, total, err := h.service.ListSubscribers(c.Context(), claims.UserID, planID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get subscriptions")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, subscriptions))
+}
+
+// GetSubscriber returns a subscription to one of the merchant's plans.
+func (h *SubscriptionHandler) GetSubscriber(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	subscriptionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid subscription ID")
+	}
+
+	detail, err := h.service.GetSubscriber(c.Context(), claims.UserID, uint(subscriptionID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription retrieved", detail)
+}
+
+// CancelSubscriber ends a customer's subscription from the merchant side.
+func (h *SubscriptionHandler) CancelSubscriber(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	subscriptionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid subscription ID")
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	canceled, err := h.service.CancelSubscriber(c.Context(), claims.UserID, uint(subscriptionID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription canceled", canceled)
+}
+
+// ViewPlan shows an active plan to a prospective subscriber.
+func (h *SubscriptionHandler) ViewPlan(c *fiber.Ctx) error {
+	planID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid plan ID")
+	}
+
+	plan, err := h.service.ViewPlan(c.Context(), uint(planID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription plan retrieved", plan)
+}
+
+// Subscribe signs the user up to a plan, recording their consent to
+// recurring charges.
+func (h *SubscriptionHandler) Subscribe(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input subscription.SubscribeRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	detail, err := h.service.Subscribe(c.Context(), claims.UserID, input, subscription.ClientInfo{
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscribed successfully", detail)
+}
+
+// ListSubscriptions lists the user's subscriptions, optionally by status.
+func (h *SubscriptionHandler) ListSubscriptions(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	subscriptions, total, err := h.service.List(c.Context(), claims.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get subscriptions")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, subscriptions))
+}
+
+// GetSubscription returns one of the user's subscriptions with its charges.
+func (h *SubscriptionHandler) GetSubscription(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	subscriptionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid subscription ID")
+	}
+
+	detail, err := h.service.Get(c.Context(), claims.UserID, uint(subscriptionID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription retrieved", detail)
+}
+
+// CancelSubscription ends the user's subscription and revokes their consent.
+func (h *SubscriptionHandler) CancelSubscription(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	subscriptionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid subscription ID")
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	canceled, err := h.service.Cancel(c.Context(), claims.UserID, uint(subscriptionID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription canceled", canceled)
+}
+
+// PauseSubscription stops billing until the user resumes.
+func (h *SubscriptionHandler) PauseSubscription(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	subscriptionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid subscription ID")
+	}
+
+	paused, err := h.service.Pause(c.Context(), claims.UserID, uint(subscriptionID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription paused", paused)
+}
+
+// ResumeSubscription restarts billing for a paused subscription.
+func (h *SubscriptionHandler) ResumeSubscription(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	subscriptionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid subscription ID")
+	}
+
+	resumed, err := h.service.Resume(c.Context(), claims.UserID, uint(subscriptionID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "subscription resumed", resumed)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/services/taxsummary"
+	"orus/internal/utils/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TaxSummaryHandler lets users and merchants download a summary of their
+// tax year.
+type TaxSummaryHandler struct {
+	service taxsummary.Service
+}
+
+// NewTaxSummaryHandler creates a new TaxSummaryHandler.
+func NewTaxSummaryHandler(s taxsummary.Service) *TaxSummaryHandler {
+	return &TaxSummaryHandler{service: s}
+}
+
+// GetTaxSummary summarizes ?year, by default the last full year, as JSON
+// or with ?format=csv or pdf as a file. Accounts with many transactions
+// get 202 and a report to poll, downloaded once it completes.
+func (h *TaxSummaryHandler) GetTaxSummary(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	year := time.Now().UTC().Year() - 1
+	if raw := c.Query("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return taxsummary.ErrInvalidYear
+		}
+		year = parsed
+	}
+	format := c.Query("format", taxsummary.FormatJSON)
+
+	result, err := h.service.Request(c.Context(), claims.UserID, year, format)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case result.Report != nil:
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "tax summary is being generated", result.Report)
+	case result.File != nil:
+		return sendTaxSummary(c, result.File)
+	default:
+		return response.Success(c, "tax summary retrieved", result.Summary)
+	}
+}
+
+// GetTaxSummaryReport returns a tax summary being generated in the
+// background.
+func (h *TaxSummaryHandler) GetTaxSummaryReport(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+
+	report, err := h.service.GetReport(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "tax summary report retrieved", report)
+}
+
+// DownloadTaxSummaryReport downloads a tax summary generated in the
+// background once it completes.
+func (h *TaxSummaryHandler) DownloadTaxSummaryReport(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid report ID")
+	}
+
+	file, err := h.service.Download(c.Context(), claims.UserID, uint(id))
+	if err != nil {
+		return err
+	}
+	return sendTaxSummary(c, file)
+}
+
+func sendTaxSummary(c *fiber.Ctx, file *taxsummary.File) error {
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.Name))
+	return c.Send(file.Content)
+}
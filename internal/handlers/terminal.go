@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/merchant"
+	"orus/internal/services/terminal"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TerminalHandler serves terminal management for merchants and admins, and
+// the endpoints paired devices call.
+type TerminalHandler struct {
+	service   terminal.Service
+	merchants *merchant.Service
+}
+
+// NewTerminalHandler creates a new TerminalHandler.
+func NewTerminalHandler(s terminal.Service, merchants *merchant.Service) *TerminalHandler {
+	return &TerminalHandler{service: s, merchants: merchants}
+}
+
+// RegisterTerminal adds a terminal and returns its one-time pairing code.
+func (h *TerminalHandler) RegisterTerminal(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input terminal.RegisterRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	pairing, err := h.service.Register(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "terminal registered", pairing)
+}
+
+// ListTerminals lists the merchant's terminals, optionally by status.
+func (h *TerminalHandler) ListTerminals(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	terminals, total, err := h.service.List(c.Context(), claims.UserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get terminals")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, terminals))
+}
+
+// GetTerminal returns one of the merchant's terminals.
+func (h *TerminalHandler) GetTerminal(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	terminalID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid terminal ID")
+	}
+
+	t, err := h.service.Get(c.Context(), claims.UserID, uint(terminalID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "terminal retrieved", t)
+}
+
+// NewPairingCode issues a fresh pairing code, unpairing the current device.
+func (h *TerminalHandler) NewPairingCode(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	terminalID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid terminal ID")
+	}
+
+	pairing, err := h.service.NewPairingCode(c.Context(), claims.UserID, uint(terminalID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "pairing code issued", pairing)
+}
+
+// DeactivateTerminal permanently disables one of the merchant's terminals.
+func (h *TerminalHandler) DeactivateTerminal(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	terminalID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid terminal ID")
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	t, err := h.service.Deactivate(c.Context(), claims.UserID, uint(terminalID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "terminal deactivated", t)
+}
+
+// GetTerminalTransactions lists the payments taken on a terminal.
+func (h *TerminalHandler) GetTerminalTransactions(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	terminalID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid terminal ID")
+	}
+
+	transactions, total, err := h.service.Transactions(c.Context(), claims.UserID, uint(terminalID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, transactions))
+}
+
+// AdminListTerminals lists terminals across merchants, optionally filtered
+// by merchant_user_id and status.
+func (h *TerminalHandler) AdminListTerminals(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	var merchantUserID uint
+	if v := c.Query("merchant_user_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return response.BadRequest(c, "invalid merchant_user_id")
+		}
+		merchantUserID = uint(id)
+	}
+
+	terminals, total, err := h.service.List(c.Context(), merchantUserID, c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return response.ServerError(c, "failed to get terminals")
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, terminals))
+}
+
+// AdminDeactivateTerminal remotely disables a lost or compromised terminal.
+func (h *TerminalHandler) AdminDeactivateTerminal(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	terminalID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid terminal ID")
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BodyParser(&input); err != nil || input.Reason == "" {
+		return response.BadRequest(c, "a reason is required")
+	}
+
+	t, err := h.service.AdminDeactivate(c.Context(), claims.UserID, uint(terminalID), input.Reason)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "terminal deactivated", t)
+}
+
+// PairTerminal exchanges a pairing code for the device's credentials.
+func (h *TerminalHandler) PairTerminal(c *fiber.Ctx) error {
+	var input terminal.PairRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	credentials, err := h.service.Pair(c.Context(), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "terminal paired", credentials)
+}
+
+// CurrentTerminal returns the authenticated terminal.
+func (h *TerminalHandler) CurrentTerminal(c *fiber.Ctx) error {
+	return response.Success(c, "terminal retrieved", c.Locals("terminal").(*models.Terminal))
+}
+
+// TerminalCharge takes a payment on the authenticated terminal.
+func (h *TerminalHandler) TerminalCharge(c *fiber.Ctx) error {
+	t := c.Locals("terminal").(*models.Terminal)
+
+	var input merchant.ChargeInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+	input.TerminalID = t.ID
+
+	tx, held, err := h.merchants.ProcessDirectCharge(t.MerchantUserID, input)
+	if err != nil {
+		return err
+	}
+
+	return chargeResponse(c, tx, held)
+}
+
+// TerminalChargeConfirmation returns a charge held for the customer's
+// approval.
+func (h *TerminalHandler) TerminalChargeConfirmation(c *fiber.Ctx) error {
+	t := c.Locals("terminal").(*models.Terminal)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid charge confirmation ID")
+	}
+
+	confirmation, err := h.merchants.GetScanCharge(c.Context(), t.MerchantUserID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "charge confirmation retrieved", confirmation)
+}
+
+// TerminalRefund refunds a charge from the authenticated terminal.
+func (h *TerminalHandler) TerminalRefund(c *fiber.Ctx) error {
+	t := c.Locals("terminal").(*models.Terminal)
+
+	var input merchant.RefundInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+	input.TerminalID = t.ID
+
+	tx, err := h.merchants.RefundCharge(c.Context(), t.MerchantUserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Refund processed successfully", tx)
+}
+
+// terminalError maps terminal errors, falling back to the staff and
+// merchant errors a till payment can also fail with.
@@ -1 +1,65 @@
 package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/transaction"
+	"orus/internal/utils/response"
+	"orus/internal/validation/metadata"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TransactionHandler struct {
+	transactions transaction.Service
+}
+
+func NewTransactionHandler(transactions transaction.Service) *TransactionHandler {
+	return &TransactionHandler{transactions: transactions}
+}
+
+// ReverseTransaction undoes an erroneous completed transaction, recording
+// the admin and their reason.
+func (h *TransactionHandler) ReverseTransaction(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	txID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid transaction ID")
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	reversal, err := h.transactions.Reverse(c.Context(), transaction.ReversalRequest{
+		TransactionID: uint(txID),
+		Reason:        input.Reason,
+		ReversedBy:    claims.UserID,
+		Actor:         models.ReversalActorAdmin,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Transaction reversed", reversal)
+}
+
+// GetMetadataSchemas lists the versioned metadata schemas for each transaction type
+func GetMetadataSchemas(c *fiber.Ctx) error {
+	if txType := c.Query("type"); txType != "" {
+		schema, ok := metadata.Current(txType)
+		if !ok {
+			return response.Error(c, fiber.StatusNotFound, "no metadata schema for transaction type")
+		}
+		return response.Success(c, "metadata schema retrieved", schema)
+	}
+
+	return response.Success(c, "metadata schemas retrieved", fiber.Map{
+		"mode":    metadata.CurrentMode(),
+		"schemas": metadata.All(),
+	})
+}
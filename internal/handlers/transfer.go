@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	apperrors "orus/internal/errors"
 	"orus/internal/models"
 	"orus/internal/services/transfer"
 	"orus/internal/services/wallet"
@@ -31,10 +32,14 @@ func (h *TransferHandler) Transfer(c *fiber.Ctx) error {
 		return response.BadRequest(c, "invalid request")
 	}
 
-	ctx := context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role)
+	ctx := withPaymentPIN(context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role), c)
 	tx, err := h.service.Transfer(ctx, claims.UserID, req.ReceiverID, req.Amount, req.Description)
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return apperrors.WithStatus(fiber.StatusBadRequest, err)
+	}
+	if tx.Status == models.TransactionStatusAwaitingConfirmation {
+		c.Status(fiber.StatusAccepted)
+		return response.Success(c, "transfer awaiting approval on your trusted device", tx)
 	}
 	return response.Success(c, "transfer completed", tx)
 }
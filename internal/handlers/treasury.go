@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/treasury"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TreasuryHandler exposes the platform's system accounts to admins.
+type TreasuryHandler struct {
+	service treasury.Service
+}
+
+// NewTreasuryHandler creates a new TreasuryHandler.
+func NewTreasuryHandler(s treasury.Service) *TreasuryHandler {
+	return &TreasuryHandler{service: s}
+}
+
+// GetReport returns system account balances and their activity between
+// start_date and end_date (inclusive, YYYY-MM-DD). Defaults to month to date.
+func (h *TreasuryHandler) GetReport(c *fiber.Ctx) error {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	if v := c.Query("start_date"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return response.BadRequest(c, "invalid start_date")
+		}
+		from = d
+	}
+	if v := c.Query("end_date"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return response.BadRequest(c, "invalid end_date")
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+
+	report, err := h.service.Report(c.Context(), from, to)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "treasury report retrieved", report)
+}
+
+// GetEntries lists ledger entries for one system account.
+func (h *TreasuryHandler) GetEntries(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	entries, total, err := h.service.ListEntries(c.Context(), c.Params("code"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, entries))
+}
+
+// ListTransfers lists treasury transfers, optionally filtered by status.
+func (h *TreasuryHandler) ListTransfers(c *fiber.Ctx) error {
+	p := pagination.ParseFromRequest(c)
+
+	transfers, total, err := h.service.ListTransfers(c.Context(), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, transfers))
+}
+
+// RequestTransfer records a transfer between system accounts for approval.
+func (h *TreasuryHandler) RequestTransfer(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input treasury.TransferRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	transfer, err := h.service.RequestTransfer(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "treasury transfer awaiting approval", transfer)
+}
+
+// ApproveTransfer executes a pending transfer requested by another super-admin.
+func (h *TreasuryHandler) ApproveTransfer(c *fiber.Ctx) error {
+	return h.review(c, h.service.ApproveTransfer, "treasury transfer executed")
+}
+
+// RejectTransfer closes a pending transfer without moving funds.
+func (h *TreasuryHandler) RejectTransfer(c *fiber.Ctx) error {
+	return h.review(c, h.service.RejectTransfer, "treasury transfer rejected")
+}
+
+func (h *TreasuryHandler) review(
+	c *fiber.Ctx,
+	action func(ctx context.Context, checkerID, transferID uint, note string) (*models.TreasuryTransfer, error),
+	message string,
+) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid transfer ID")
+	}
+
+	var input struct {
+		Note string `json:"note"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return response.BadRequest(c, "invalid request")
+		}
+	}
+
+	transfer, err := action(c.Context(), claims.UserID, uint(id), input.Note)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, message, transfer)
+}
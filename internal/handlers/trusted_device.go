@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/deviceconfirm"
+	"orus/internal/services/wallet"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeviceConfirmationHandler manages trusted devices and the large transfers
+// waiting to be approved on them.
+type DeviceConfirmationHandler struct {
+	service deviceconfirm.Service
+}
+
+// NewDeviceConfirmationHandler creates a new DeviceConfirmationHandler.
+func NewDeviceConfirmationHandler(s deviceconfirm.Service) *DeviceConfirmationHandler {
+	return &DeviceConfirmationHandler{service: s}
+}
+
+// ListDevices returns the user's trusted devices.
+func (h *DeviceConfirmationHandler) ListDevices(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	devices, err := h.service.ListDevices(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "trusted devices retrieved", devices)
+}
+
+// RegisterDevice trusts the device the request is made from.
+func (h *DeviceConfirmationHandler) RegisterDevice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[deviceconfirm.DeviceRequest](c)
+
+	device, err := h.service.RegisterDevice(c.Context(), claims.UserID, requestDeviceID(c), *input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "trusted device registered", device)
+}
+
+// RemoveDevice stops trusting one of the user's devices.
+func (h *DeviceConfirmationHandler) RemoveDevice(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid device ID")
+	}
+
+	if err := h.service.RemoveDevice(c.Context(), claims.UserID, uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, "trusted device removed", nil)
+}
+
+// ListPending returns the user's transfers waiting for approval.
+func (h *DeviceConfirmationHandler) ListPending(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	confirmations, err := h.service.ListPending(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "transfers awaiting approval retrieved", confirmations)
+}
+
+// Approve completes a held transfer from a trusted device.
+func (h *DeviceConfirmationHandler) Approve(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid confirmation ID")
+	}
+
+	ctx := context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role)
+	confirmation, err := h.service.Approve(ctx, claims.UserID, requestDeviceID(c), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "transfer approved", confirmation)
+}
+
+// Deny cancels a held transfer from a trusted device.
+func (h *DeviceConfirmationHandler) Deny(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid confirmation ID")
+	}
+
+	confirmation, err := h.service.Deny(c.Context(), claims.UserID, requestDeviceID(c), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "transfer denied", confirmation)
+}
+
+// requestDeviceID is the device a request came from: the apps' X-Device-ID
+// header, or the cookie browsers are given on login
+func requestDeviceID(c *fiber.Ctx) string {
+	if deviceID := c.Get("X-Device-ID"); deviceID != "" {
+		return deviceID
+	}
+	return c.Cookies("device_id")
+}
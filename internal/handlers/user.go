@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	apperrors "orus/internal/errors"
 	"orus/internal/models"
 	qr "orus/internal/services/qr_code"
 	"orus/internal/services/user"
@@ -50,7 +51,7 @@ func (h *UserHandler) RegisterUser(c *fiber.Ctx) error {
 
 	user, err := h.userService.Create(&input)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	// Create wallet for the new user
@@ -118,6 +119,13 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	if input.Phone != "" {
 		user.Phone = input.Phone
 	}
+	if input.Country != "" {
+		country, ok := models.NormalizeCountryCode(input.Country)
+		if !ok {
+			return response.BadRequest(c, "country must be a 2-letter ISO code")
+		}
+		user.Country = country
+	}
 
 	if err := h.userService.Update(user); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to update profile")
@@ -140,7 +148,7 @@ func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
 	}
 
 	if err := h.userService.ChangePassword(claims.UserID, input.OldPassword, input.NewPassword); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return apperrors.WithStatus(fiber.StatusBadRequest, err)
 	}
 
 	return response.Success(c, "Password changed successfully", nil)
@@ -152,7 +160,11 @@ func (h *UserHandler) GetUserTransactions(c *fiber.Ctx) error {
 
 	p := pagination.ParseFromRequest(c)
 
-	transactions, total, err := h.userService.GetTransactions(claims.UserID, p.Limit, p.Offset)
+	// Transactions past the retention age are only read from the archive
+	// when asked for
+	includeArchived := c.QueryBool("include_archived")
+
+	transactions, total, err := h.userService.GetTransactions(claims.UserID, p.Limit, p.Offset, includeArchived)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch transactions")
 	}
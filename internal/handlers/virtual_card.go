@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/issuing"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VirtualCardHandler exposes virtual card issuance and management endpoints.
+type VirtualCardHandler struct {
+	service issuing.Service
+}
+
+// NewVirtualCardHandler creates a new VirtualCardHandler.
+func NewVirtualCardHandler(s issuing.Service) *VirtualCardHandler {
+	return &VirtualCardHandler{service: s}
+}
+
+// IssueCard issues a new virtual card. The card number and CVV are only
+// returned in this response.
+func (h *VirtualCardHandler) IssueCard(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input issuing.IssueCardRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	issued, err := h.service.IssueCard(c.Context(), claims.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "virtual card issued", issued)
+}
+
+// GetCards lists the user's virtual cards.
+func (h *VirtualCardHandler) GetCards(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	cards, err := h.service.ListCards(c.Context(), claims.UserID)
+	if err != nil {
+		return response.ServerError(c, "failed to get virtual cards")
+	}
+
+	return response.Success(c, "virtual cards retrieved", cards)
+}
+
+// GetCard returns a single virtual card.
+func (h *VirtualCardHandler) GetCard(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	cardID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid card ID")
+	}
+
+	card, err := h.service.GetCard(c.Context(), claims.UserID, uint(cardID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "virtual card retrieved", card)
+}
+
+// FreezeCard blocks authorizations on a card.
+func (h *VirtualCardHandler) FreezeCard(c *fiber.Ctx) error {
+	return h.changeStatus(c, h.service.FreezeCard, "virtual card frozen")
+}
+
+// UnfreezeCard re-enables a frozen card.
+func (h *VirtualCardHandler) UnfreezeCard(c *fiber.Ctx) error {
+	return h.changeStatus(c, h.service.UnfreezeCard, "virtual card unfrozen")
+}
+
+// CloseCard permanently cancels a card.
+func (h *VirtualCardHandler) CloseCard(c *fiber.Ctx) error {
+	return h.changeStatus(c, h.service.CloseCard, "virtual card closed")
+}
+
+// UpdateSpendLimit changes a card's spend limit.
+func (h *VirtualCardHandler) UpdateSpendLimit(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	cardID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid card ID")
+	}
+
+	var input issuing.SpendLimitRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	card, err := h.service.UpdateSpendLimit(c.Context(), claims.UserID, uint(cardID), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "spend limit updated", card)
+}
+
+// GetCardTransactions returns the wallet debits made with a card.
+func (h *VirtualCardHandler) GetCardTransactions(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	cardID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid card ID")
+	}
+
+	p := pagination.ParseFromRequest(c)
+	transactions, total, err := h.service.GetTransactions(c.Context(), claims.UserID, uint(cardID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, transactions))
+}
+
+// Authorize handles real-time authorization requests from the card issuer.
+func (h *VirtualCardHandler) Authorize(c *fiber.Ctx) error {
+	var input issuing.AuthorizationRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	decision, err := h.service.Authorize(c.Context(), input)
+	if err != nil {
+		return response.ServerError(c, "failed to authorize card payment")
+	}
+
+	return c.JSON(decision)
+}
+
+func (h *VirtualCardHandler) changeStatus(
+	c *fiber.Ctx,
+	fn func(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error),
+	message string,
+) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	cardID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "invalid card ID")
+	}
+
+	card, err := fn(c.Context(), claims.UserID, uint(cardID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, message, card)
+}
@@ -4,15 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"orus/internal/middleware"
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"orus/internal/services/wallet"
 	"orus/internal/utils"
+	"orus/internal/utils/response"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// CardTransferRequest moves money between the wallet and a linked card
+type CardTransferRequest struct {
+	Amount float64 `json:"amount" validate:"gt=0"`
+	CardID uint    `json:"card_id" validate:"required"`
+}
+
 type WalletHandler struct {
 	walletService wallet.Service
 }
@@ -43,66 +52,113 @@ func (h *WalletHandler) GetWallet(c *fiber.Ctx) error {
 		return utils.InternalError(c, "Failed to get wallet")
 	}
 
+	balance, err := h.walletService.GetBalanceDetails(c.Context(), claims.UserID)
+	if err != nil {
+		return utils.InternalError(c, "Failed to get wallet balance")
+	}
+
 	return utils.Success(c, fiber.Map{
-		"wallet": wallet,
+		"wallet":  wallet,
+		"balance": balance,
 	})
 }
 
-func (h *WalletHandler) TopUpWallet(c *fiber.Ctx) error {
+// GetBalance returns the total, held and available balance with holds itemized
+func (h *WalletHandler) GetBalance(c *fiber.Ctx) error {
 	claims, err := extractUserClaims(c)
 	if err != nil {
 		return utils.Unauthorized(c, "invalid claims")
 	}
 
-	// Debug log
-	fmt.Printf("User Role: %s\n", claims.Role)
-
-	var input struct {
-		Amount float64 `json:"amount" validate:"required,gt=0"`
-		CardID uint    `json:"card_id" validate:"required"`
+	balance, err := h.walletService.GetBalanceDetails(c.Context(), claims.UserID)
+	if err != nil {
+		return utils.InternalError(c, "Failed to get wallet balance")
 	}
 
-	if err := c.BodyParser(&input); err != nil {
-		return utils.BadRequest(c, "Invalid request format")
-	}
+	return utils.Success(c, fiber.Map{
+		"balance": balance,
+	})
+}
 
-	if input.Amount <= 0 {
-		return utils.BadRequest(c, "Amount must be greater than 0")
+func (h *WalletHandler) TopUpWallet(c *fiber.Ctx) error {
+	claims, err := extractUserClaims(c)
+	if err != nil {
+		return utils.Unauthorized(c, "invalid claims")
 	}
 
+	// Debug log
+	fmt.Printf("User Role: %s\n", claims.Role)
+
+	input := middleware.Body[CardTransferRequest](c)
+
 	// Create context with user role
 	ctx := context.WithValue(c.Context(), wallet.UserRoleContextKey, claims.Role)
 
-	err = h.walletService.TopUp(ctx, claims.UserID, input.CardID, input.Amount)
+	topUp, err := h.walletService.TopUp(ctx, claims.UserID, input.CardID, input.Amount)
 	if err != nil {
-		return utils.InternalError(c, err.Error())
+		return err
+	}
+
+	// The wallet is credited once the cardholder passes the 3-D Secure challenge
+	if topUp.Status == models.CardTopUpRequiresAction {
+		return utils.Respond(c, fiber.StatusAccepted, fiber.Map{
+			"message": "Card authentication required",
+			"amount":  input.Amount,
+			"top_up":  topUp,
+		})
 	}
 
 	return utils.Success(c, fiber.Map{
 		"message": "Top up successful",
 		"amount":  input.Amount,
+		"top_up":  topUp,
 	})
 }
 
-func (h *WalletHandler) WithdrawToCard(c *fiber.Ctx) error {
+// GetTopUp returns a card top-up, for clients waiting on 3-D Secure
+func (h *WalletHandler) GetTopUp(c *fiber.Ctx) error {
 	claims, err := extractUserClaims(c)
 	if err != nil {
 		return utils.Unauthorized(c, "invalid claims")
 	}
 
-	var input struct {
-		Amount float64 `json:"amount" validate:"required,gt=0"`
-		CardID uint    `json:"card_id" validate:"required"`
+	topUpID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid top-up ID")
 	}
 
-	if err := c.BodyParser(&input); err != nil {
-		return utils.BadRequest(c, "Invalid request format")
+	topUp, err := h.walletService.GetTopUp(c.Context(), claims.UserID, uint(topUpID))
+	if err != nil {
+		return err
 	}
 
-	if input.Amount <= 0 {
-		return utils.BadRequest(c, "Amount must be greater than 0")
+	return response.Success(c, "Top-up retrieved", topUp)
+}
+
+// HandleCardPaymentWebhook applies the card processor's 3-D Secure outcome
+// to a pending top-up.
+func (h *WalletHandler) HandleCardPaymentWebhook(c *fiber.Ctx) error {
+	var event wallet.CardTopUpEvent
+	if err := c.BodyParser(&event); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	topUp, err := h.walletService.HandleCardTopUpEvent(c.Context(), event)
+	if err != nil {
+		return err
 	}
 
+	return response.Success(c, "card payment event processed", topUp)
+}
+
+func (h *WalletHandler) WithdrawToCard(c *fiber.Ctx) error {
+	claims, err := extractUserClaims(c)
+	if err != nil {
+		return utils.Unauthorized(c, "invalid claims")
+	}
+
+	input := middleware.Body[CardTransferRequest](c)
+
 	// Get fee percentage from service
 	feePercent := h.walletService.GetWithdrawalFeePercent()
 	fee := input.Amount * feePercent
@@ -118,20 +174,21 @@ func (h *WalletHandler) WithdrawToCard(c *fiber.Ctx) error {
 		if strings.Contains(err.Error(), "not active") {
 			return utils.BadRequest(c, "Card is not active")
 		}
-		return utils.InternalError(c, err.Error())
+		return err
 	}
 
 	// Get updated wallet balance
-	wallet, err := h.walletService.GetWallet(c.Context(), claims.UserID)
+	balance, err := h.walletService.GetBalanceDetails(c.Context(), claims.UserID)
 	if err != nil {
 		return utils.InternalError(c, "Failed to get updated wallet balance")
 	}
 
 	return utils.Success(c, fiber.Map{
-		"message":        "Withdrawal successful",
-		"amount":         input.Amount,
-		"fee":            fee,
-		"total_deducted": input.Amount + fee,
-		"new_balance":    wallet.Balance,
+		"message":           "Withdrawal successful",
+		"amount":            input.Amount,
+		"fee":               fee,
+		"total_deducted":    input.Amount + fee,
+		"new_balance":       balance.Total,
+		"available_balance": balance.Available,
 	})
 }
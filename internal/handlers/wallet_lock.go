@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"orus/internal/middleware"
+	"orus/internal/models"
+	"orus/internal/services/wallet"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WalletLockRequest locks a wallet. Reason is only read from admins;
+// owners locking their own wallet always lock it as user_requested.
+type WalletLockRequest struct {
+	Scope     string     `json:"scope" validate:"required,oneof=debits credits full"`
+	Reason    string     `json:"reason" validate:"omitempty,oneof=fraud legal user_requested"`
+	Note      string     `json:"note" validate:"max=500"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *WalletLockRequest) lockRequest() wallet.LockRequest {
+	return wallet.LockRequest{
+		Scope:     r.Scope,
+		Reason:    r.Reason,
+		Note:      r.Note,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+// GetWalletLock returns the active lock on the caller's wallet
+func (h *WalletHandler) GetWalletLock(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	lock, err := h.walletService.GetLock(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Wallet lock retrieved", lock)
+}
+
+// LockOwnWallet lets users lock their own wallet, say after losing their phone
+func (h *WalletHandler) LockOwnWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	input := middleware.Body[WalletLockRequest](c)
+
+	lock, err := h.walletService.LockWallet(c.Context(), nil, claims.UserID, input.lockRequest())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Wallet locked", lock)
+}
+
+// UnlockOwnWallet lifts a lock the user placed on their own wallet
+func (h *WalletHandler) UnlockOwnWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	lock, err := h.walletService.UnlockWallet(c.Context(), nil, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Wallet unlocked", lock)
+}
+
+// LockUserWallet locks a user's wallet for fraud, legal or user-requested reasons
+func (h *WalletHandler) LockUserWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+	input := middleware.Body[WalletLockRequest](c)
+	if input.Reason == "" {
+		return wallet.ErrInvalidLockReason
+	}
+
+	lock, err := h.walletService.LockWallet(c.Context(), &claims.UserID, uint(userID), input.lockRequest())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Wallet locked", lock)
+}
+
+// UnlockUserWallet lifts the lock on a user's wallet
+func (h *WalletHandler) UnlockUserWallet(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	lock, err := h.walletService.UnlockWallet(c.Context(), &claims.UserID, uint(userID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Wallet unlocked", lock)
+}
+
+// GetWalletLockEvents lists the locks placed on and lifted from a user's
+// wallet, and the operations they refused, newest first
+func (h *WalletHandler) GetWalletLockEvents(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+	p := pagination.ParseFromRequest(c)
+
+	events, total, err := h.walletService.LockEvents(c.Context(), uint(userID), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, events))
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/webhook"
+	"orus/internal/utils/pagination"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookHandler lets merchants inspect, test and replay the webhooks sent
+// to their endpoint.
+type WebhookHandler struct {
+	service webhook.Service
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(s webhook.Service) *WebhookHandler {
+	return &WebhookHandler{service: s}
+}
+
+// SendTest posts a sample signed event to the merchant's webhook URL and
+// reports how the endpoint answered.
+func (h *WebhookHandler) SendTest(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	var input webhook.TestRequest
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "invalid request")
+	}
+
+	result, err := h.service.SendTest(c.Context(), claims.UserID, input.Event)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "test webhook sent", result)
+}
+
+// ListTestEvents lists the event types a test webhook can send.
+func (h *WebhookHandler) ListTestEvents(c *fiber.Ctx) error {
+	return response.Success(c, "webhook events retrieved", h.service.TestEvents())
+}
+
+// ListDeliveries lists the merchant's webhook deliveries, optionally by
+// event and status.
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+	p := pagination.ParseFromRequest(c)
+
+	deliveries, total, err := h.service.ListDeliveries(c.Context(), claims.UserID, c.Query("event"), c.Query("status"), p.Limit, p.Offset)
+	if err != nil {
+		return err
+	}
+
+	p.Total = total
+	return c.JSON(pagination.Response(p, deliveries))
+}
+
+// Replay resends a past delivery to the merchant's webhook URL and reports
+// how the endpoint answered.
+func (h *WebhookHandler) Replay(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*models.UserClaims)
+
+	result, err := h.service.Replay(c.Context(), claims.UserID, c.Params("eventId"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "webhook replayed", result)
+}
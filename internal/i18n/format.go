@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numberFormat is how a language writes numbers
+type numberFormat struct {
+	group   string
+	decimal string
+}
+
+var numberFormats = map[string]numberFormat{
+	"en": {group: ",", decimal: "."},
+	"fr": {group: "\u00a0", decimal: ","}, // A no-break space keeps groups on one line
+}
+
+// zeroDecimalCurrencies have no minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+	"XOF": true,
+	"XAF": true,
+}
+
+// currencySymbols are written before the amount in English and after it
+// in French; other currencies are written with their code
+var currencySymbols = map[string]map[string]string{
+	"en": {"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥"},
+	"fr": {"USD": "$US", "EUR": "€", "GBP": "£", "JPY": "¥", "XOF": "FCFA", "XAF": "FCFA"},
+}
+
+var frenchMonths = [...]string{
+	"janv.", "févr.", "mars", "avr.", "mai", "juin",
+	"juil.", "août", "sept.", "oct.", "nov.", "déc.",
+}
+
+// CurrencyDecimals is how many decimals amounts in currency are shown with
+func CurrencyDecimals(currency string) int {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return 0
+	}
+	return 2
+}
+
+// FormatNumber writes value with decimals places, grouping thousands the
+// way lang does
+func FormatNumber(lang string, value float64, decimals int) string {
+	format, ok := numberFormats[lang]
+	if !ok {
+		format = numberFormats[DefaultLanguage]
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	digits := strconv.FormatFloat(value, 'f', decimals, 64)
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(format.group)
+		}
+		b.WriteRune(d)
+	}
+	if fraction != "" {
+		b.WriteString(format.decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// FormatAmount writes amount in currency the way lang does, such as
+// $1,234.50 in English and 1 234,50 € in French
+func FormatAmount(lang string, amount float64, currency string) string {
+	currency = strings.ToUpper(currency)
+	decimals := CurrencyDecimals(currency)
+	number := FormatNumber(lang, math.Abs(amount), decimals)
+	sign := ""
+	if amount < 0 && number != FormatNumber(lang, 0, decimals) {
+		sign = "-"
+	}
+
+	symbol, known := currencySymbols[lang][currency]
+	switch {
+	case lang == "fr" && known:
+		return sign + number + "\u00a0" + symbol
+	case lang == "fr":
+		return sign + number + "\u00a0" + currency
+	case known:
+		return sign + symbol + number
+	default:
+		return sign + currency + " " + number
+	}
+}
+
+// FormatDate writes t's date the way lang does, such as Mar 4, 2025 in
+// English and 4 mars 2025 in French
+func FormatDate(lang string, t time.Time) string {
+	if lang == "fr" {
+		return strconv.Itoa(t.Day()) + " " + frenchMonths[t.Month()-1] + " " + strconv.Itoa(t.Year())
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// FormatDateTime writes t's date and time, with its zone, the way lang does
+func FormatDateTime(lang string, t time.Time) string {
+	if lang == "fr" {
+		return FormatDate(lang, t) + " " + t.Format("15:04 MST")
+	}
+	return t.Format("Jan 2, 2006 3:04 PM MST")
+}
@@ -0,0 +1,71 @@
+// Package i18n translates user-facing strings, such as validation errors,
+// notifications and document labels, and formats amounts and dates for
+// the language a client asks for in its Accept-Language header.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultLanguage is the language strings are written in
+const DefaultLanguage = "en"
+
+// Languages lists the languages strings are available in
+var Languages = []string{DefaultLanguage, "fr"}
+
+type contextKey struct{}
+
+// ContextKey is the request local, and context value, holding the
+// language a request was made in
+var ContextKey = contextKey{}
+
+// Match picks the supported language a client prefers from its
+// Accept-Language header, ignoring quality values; the first supported
+// language wins
+func Match(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		base := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		for _, lang := range Languages {
+			if base == lang {
+				return lang
+			}
+		}
+	}
+	return DefaultLanguage
+}
+
+// WithLanguage returns a copy of ctx carrying lang, for work started
+// outside a request
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ContextKey, lang)
+}
+
+// FromContext returns the language ctx's request was made in, or the
+// default for work no request started
+func FromContext(ctx context.Context) string {
+	if ctx != nil {
+		if lang, ok := ctx.Value(ContextKey).(string); ok && lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// T returns the string for key in lang, falling back to English, with
+// args formatted into it
+func T(lang, key string, args ...interface{}) string {
+	text, ok := messages[lang][key]
+	if !ok {
+		text, ok = messages[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
@@ -0,0 +1,225 @@
+package i18n
+
+// messages holds every translatable string by language and key. Keys
+// missing from a language fall back to English.
+var messages = map[string]map[string]string{
+	"en": {
+		// Validation errors, one per rule a request body field can break
+		"validation.required":  "is required",
+		"validation.gt":        "must be greater than %s",
+		"validation.gte":       "must be at least %s",
+		"validation.lt":        "must be less than %s",
+		"validation.lte":       "must be at most %s",
+		"validation.gt.chars":  "must be more than %s characters long",
+		"validation.gte.chars": "must be at least %s characters long",
+		"validation.lt.chars":  "must be less than %s characters long",
+		"validation.lte.chars": "must be at most %s characters long",
+		"validation.gt.items":  "must have more than %s items",
+		"validation.gte.items": "must have at least %s items",
+		"validation.lt.items":  "must have fewer than %s items",
+		"validation.lte.items": "must have at most %s items",
+		"validation.len":       "must be exactly %s characters long",
+		"validation.oneof":     "must be one of: %s",
+		"validation.email":     "must be a valid email address",
+		"validation.url":       "must be a valid URL",
+		"validation.e164":      "must be a phone number in international format",
+		"validation.country":   "must be a 2-letter country code",
+		"validation.currency":  "must be a 3-letter currency code",
+		"validation.invalid":   "is invalid",
+
+		// Receipt and invoice labels
+		"doc.receipt":        "RECEIPT",
+		"doc.invoice":        "INVOICE",
+		"doc.date":           "Date",
+		"doc.payment":        "Payment",
+		"doc.ref":            "Ref",
+		"doc.from":           "From",
+		"doc.bill_to":        "Bill to",
+		"doc.issued":         "Issued",
+		"doc.due":            "Due",
+		"doc.status":         "Status",
+		"doc.item":           "Item",
+		"doc.description":    "Description",
+		"doc.qty":            "Qty",
+		"doc.unit_price":     "Unit price",
+		"doc.amount":         "Amount",
+		"doc.subtotal":       "Subtotal",
+		"doc.tax":            "Tax",
+		"doc.tax_rate":       "Tax (%s%%)",
+		"doc.tip":            "Tip",
+		"doc.total":          "Total",
+		"doc.processing_fee": "Processing fee",
+		"doc.notes":          "Notes:",
+		"doc.thanks":         "Thank you for your purchase.",
+
+		// Tax summary labels; amount columns fit 14 characters
+		"doc.tax_summary":      "TAX SUMMARY %d",
+		"doc.income":           "Income",
+		"doc.merchant_revenue": "Sales",
+		"doc.refunds_received": "Refunds",
+		"doc.fees_paid":        "Fees paid",
+		"doc.by_category":      "By category",
+		"doc.no_transactions":  "No transactions this year.",
+
+		// Notifications and emails. Subscription emails all take the
+		// amount, plan name and next charge date, using what they need.
+		"notify.transfer":                      "Transfer %s of %s completed",
+		"notify.export_failed":                 "Your scheduled export failed: %s",
+		"notify.deposit":                       "Your bank deposit of %s is %s",
+		"notify.deposit.initiated":             "Your bank deposit of %s has started",
+		"notify.deposit.pending":               "Your bank deposit of %s is on its way",
+		"notify.deposit.settled":               "Your bank deposit of %s has arrived",
+		"notify.deposit.returned":              "Your bank deposit of %s was returned by your bank",
+		"notify.deposit.failed":                "Your bank deposit of %s failed",
+		"notify.split.request":                 "You have been asked to pay %s for %q",
+		"notify.split.reminder":                "Reminder: your share of %s for %q is still unpaid",
+		"notify.split.paid":                    "A participant paid %s for %q (%d of %d shares paid)",
+		"notify.escrow":                        "Escrow payment of %s: %s",
+		"notify.escrow.funded":                 "An escrow payment of %s for you has been funded",
+		"notify.escrow.disputed":               "The escrow payment of %s has been disputed",
+		"notify.escrow.released":               "The escrow payment of %s has been released",
+		"notify.escrow.refunded":               "The escrow payment of %s has been refunded",
+		"notify.enterprise.approval":           "A payment of %s awaits your approval",
+		"notify.enterprise.completed":          "Your organization payment of %s was approved",
+		"notify.enterprise.reviewed":           "Your organization payment of %s is now %s",
+		"notify.enterprise.rejected":           "Your organization payment of %s was rejected",
+		"notify.wallet_lock.locked":            "Your wallet has been locked %s",
+		"notify.wallet_lock.released":          "The lock on your wallet has been lifted",
+		"notify.wallet_lock.expired":           "The lock on your wallet has expired",
+		"notify.wallet_lock.refused":           "An operation was blocked by the lock on your wallet",
+		"notify.wallet_lock.until":             "until %s",
+		"notify.wallet_lock.indefinite":        "until it is lifted",
+		"notify.payment.payer":                 "You paid %s to %s (fee %s). Receipt: %s",
+		"notify.payment.payee":                 "You received %s from %s (fee %s). Receipt: %s",
+		"notify.payment.digest":                "%d payments completed, totalling %s. See them at %s",
+		"notify.payment.someone":               "another user",
+		"notify.transfer.approval":             "Approve your transfer of %s to user %d before %s, or deny it if it wasn't you",
+		"notify.oauth.payment_request":         "%s is asking to pay %s to user %d from your wallet. Approve or decline it before %s",
+		"notify.scan.approval":                 "%s is charging you %s. Approve or decline it before %s",
+		"notify.refund.requested":              "A customer asked for a refund of %s: %s. Approve or reject refund request %d",
+		"notify.refund.refunded":               "%s refunded you %s",
+		"notify.refund.rejected":               "%s declined your refund of %s: %s",
+		"email.invoice.issued":                 "Invoice %s for %s is due on %s. Pay it at %s",
+		"email.invoice.reminder":               "Reminder: invoice %s for %s is due on %s. Pay it at %s",
+		"email.invoice.overdue":                "Invoice %s for %s was due on %s and is overdue. Pay it at %s",
+		"email.invoice.paid":                   "Invoice %s for %s (due %s) has been paid. Thank you! %s",
+		"email.receipt":                        "Your receipt %s for %s from %s",
+		"email.subscription.payment_failed":    "Your payment of %s for %s failed; we'll try again on %s",
+		"email.subscription.payment_recovered": "Your payment of %s for %s went through; your next charge is on %s",
+		"email.subscription.canceled_unpaid":   "Your subscription to %[2]s (%[1]s) was canceled after payments failed",
+		"email.subscription.canceled":          "Your subscription to %[2]s (%[1]s) was canceled",
+		"email.login_code":                     "Your login code is %s. It expires in %d minutes. Never share it",
+		"email.new_login":                      "New login to your account from %s (%s) on %s. Not you? Lock your account: %s",
+		"email.unknown_country":                "an unknown country",
+	},
+	"fr": {
+		"validation.required":  "est obligatoire",
+		"validation.gt":        "doit être supérieur à %s",
+		"validation.gte":       "doit être au moins égal à %s",
+		"validation.lt":        "doit être inférieur à %s",
+		"validation.lte":       "doit être au plus égal à %s",
+		"validation.gt.chars":  "doit comporter plus de %s caractères",
+		"validation.gte.chars": "doit comporter au moins %s caractères",
+		"validation.lt.chars":  "doit comporter moins de %s caractères",
+		"validation.lte.chars": "doit comporter au plus %s caractères",
+		"validation.gt.items":  "doit contenir plus de %s éléments",
+		"validation.gte.items": "doit contenir au moins %s éléments",
+		"validation.lt.items":  "doit contenir moins de %s éléments",
+		"validation.lte.items": "doit contenir au plus %s éléments",
+		"validation.len":       "doit comporter exactement %s caractères",
+		"validation.oneof":     "doit être l'une des valeurs : %s",
+		"validation.email":     "doit être une adresse email valide",
+		"validation.url":       "doit être une URL valide",
+		"validation.e164":      "doit être un numéro de téléphone au format international",
+		"validation.country":   "doit être un code pays à 2 lettres",
+		"validation.currency":  "doit être un code devise à 3 lettres",
+		"validation.invalid":   "est invalide",
+
+		"doc.receipt":        "REÇU",
+		"doc.invoice":        "FACTURE",
+		"doc.date":           "Date",
+		"doc.payment":        "Paiement",
+		"doc.ref":            "Réf.",
+		"doc.from":           "De",
+		"doc.bill_to":        "Client",
+		"doc.issued":         "Émise le",
+		"doc.due":            "Échéance",
+		"doc.status":         "Statut",
+		"doc.item":           "Article",
+		"doc.description":    "Description",
+		"doc.qty":            "Qté",
+		"doc.unit_price":     "Prix unit.",
+		"doc.amount":         "Montant",
+		"doc.subtotal":       "Sous-total",
+		"doc.tax":            "TVA",
+		"doc.tax_rate":       "TVA (%s %%)",
+		"doc.tip":            "Pourboire",
+		"doc.total":          "Total",
+		"doc.processing_fee": "Frais de traitement",
+		"doc.notes":          "Remarques :",
+		"doc.thanks":         "Merci pour votre achat.",
+
+		"doc.tax_summary":      "RÉCAPITULATIF FISCAL %d",
+		"doc.income":           "Revenus",
+		"doc.merchant_revenue": "Ventes",
+		"doc.refunds_received": "Rembours.",
+		"doc.fees_paid":        "Frais payés",
+		"doc.by_category":      "Par catégorie",
+		"doc.no_transactions":  "Aucune transaction cette année.",
+
+		"notify.transfer":                      "Transfert %s de %s effectué",
+		"notify.export_failed":                 "Votre export programmé a échoué : %s",
+		"notify.deposit":                       "Votre dépôt bancaire de %s est %s",
+		"notify.deposit.initiated":             "Votre dépôt bancaire de %s a commencé",
+		"notify.deposit.pending":               "Votre dépôt bancaire de %s est en cours",
+		"notify.deposit.settled":               "Votre dépôt bancaire de %s est arrivé",
+		"notify.deposit.returned":              "Votre dépôt bancaire de %s a été rejeté par votre banque",
+		"notify.deposit.failed":                "Votre dépôt bancaire de %s a échoué",
+		"notify.split.request":                 "Vous êtes invité à payer %s pour %q",
+		"notify.split.reminder":                "Rappel : votre part de %s pour %q reste à payer",
+		"notify.split.paid":                    "Un participant a payé %s pour %q (%d parts payées sur %d)",
+		"notify.escrow":                        "Paiement séquestre de %s : %s",
+		"notify.escrow.funded":                 "Un paiement séquestre de %s à votre intention a été approvisionné",
+		"notify.escrow.disputed":               "Le paiement séquestre de %s est contesté",
+		"notify.escrow.released":               "Le paiement séquestre de %s a été débloqué",
+		"notify.escrow.refunded":               "Le paiement séquestre de %s a été remboursé",
+		"notify.enterprise.approval":           "Un paiement de %s attend votre approbation",
+		"notify.enterprise.completed":          "Le paiement de %s de votre organisation a été approuvé",
+		"notify.enterprise.reviewed":           "Le paiement de %s de votre organisation est désormais %s",
+		"notify.enterprise.rejected":           "Le paiement de %s de votre organisation a été refusé",
+		"notify.wallet_lock.locked":            "Votre portefeuille a été bloqué %s",
+		"notify.wallet_lock.released":          "Le blocage de votre portefeuille a été levé",
+		"notify.wallet_lock.expired":           "Le blocage de votre portefeuille a expiré",
+		"notify.wallet_lock.refused":           "Une opération a été bloquée par le blocage de votre portefeuille",
+		"notify.wallet_lock.until":             "jusqu'au %s",
+		"notify.wallet_lock.indefinite":        "jusqu'à nouvel ordre",
+		"notify.payment.payer":                 "Vous avez payé %s à %s (frais %s). Reçu : %s",
+		"notify.payment.payee":                 "Vous avez reçu %s de %s (frais %s). Reçu : %s",
+		"notify.payment.digest":                "%d paiements effectués, pour un total de %s. Consultez-les sur %s",
+		"notify.payment.someone":               "un autre utilisateur",
+		"notify.transfer.approval":             "Approuvez votre virement de %s à l'utilisateur %d avant le %s, ou refusez-le si ce n'était pas vous",
+		"notify.oauth.payment_request":         "%s demande à payer %s à l'utilisateur %d depuis votre portefeuille. Approuvez ou refusez avant le %s",
+		"notify.scan.approval":                 "%s vous débite %s. Approuvez ou refusez avant le %s",
+		"notify.refund.requested":              "Un client demande le remboursement de %s : %s. Approuvez ou refusez la demande de remboursement %d",
+		"notify.refund.refunded":               "%s vous a remboursé %s",
+		"notify.refund.rejected":               "%s a refusé votre remboursement de %s : %s",
+		"email.invoice.issued":                 "La facture %s de %s est à régler avant le %s. Réglez-la sur %s",
+		"email.invoice.reminder":               "Rappel : la facture %s de %s est à régler avant le %s. Réglez-la sur %s",
+		"email.invoice.overdue":                "La facture %s de %s était à régler avant le %s et est en retard. Réglez-la sur %s",
+		"email.invoice.paid":                   "La facture %s de %s (échéance %s) a été réglée. Merci ! %s",
+		"email.receipt":                        "Votre reçu %s de %s chez %s",
+		"email.subscription.payment_failed":    "Votre paiement de %s pour %s a échoué ; nous réessaierons le %s",
+		"email.subscription.payment_recovered": "Votre paiement de %s pour %s a abouti ; prochain prélèvement le %s",
+		"email.subscription.canceled_unpaid":   "Votre abonnement à %[2]s (%[1]s) a été résilié après des paiements échoués",
+		"email.subscription.canceled":          "Votre abonnement à %[2]s (%[1]s) a été résilié",
+		"email.login_code":                     "Votre code de connexion est %s. Il expire dans %d minutes. Ne le partagez jamais",
+		"email.new_login":                      "Nouvelle connexion à votre compte depuis %s (%s) le %s. Ce n'était pas vous ? Bloquez votre compte : %s",
+		"email.unknown_country":                "un pays inconnu",
+	},
+}
+
+// Has reports whether key is a known string
+func Has(key string) bool {
+	_, ok := messages[DefaultLanguage][key]
+	return ok
+}
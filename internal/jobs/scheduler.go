@@ -0,0 +1,132 @@
+// Package jobs runs recurring background work such as scheduled exports.
+// Jobs are expected to claim their own units of work in the database so that
+// running several API instances doesn't execute the same work twice.
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of recurring background work
+type Job interface {
+	// Name identifies the job in logs
+	Name() string
+
+	// Run performs one pass of the job
+	Run(ctx context.Context) error
+}
+
+type registration struct {
+	job      Job
+	interval time.Duration
+}
+
+// Scheduler runs registered jobs on fixed intervals
+type Scheduler struct {
+	jobs    []registration
+	timeout time.Duration
+	cancel  context.CancelFunc // Aborts running jobs
+	halt    context.CancelFunc // Stops starting new runs
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// NewScheduler creates a scheduler; timeout bounds a single job run
+func NewScheduler(timeout time.Duration) *Scheduler {
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	return &Scheduler{timeout: timeout}
+}
+
+// Register adds a job to run every interval. Jobs must be registered before Start.
+func (s *Scheduler) Register(job Job, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, registration{job: job, interval: interval})
+}
+
+// Start launches one goroutine per job; they exit when ctx is cancelled or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	ticking, halt := context.WithCancel(ctx)
+	s.halt = halt
+	for _, reg := range s.jobs {
+		s.wg.Add(1)
+		go func(reg registration) {
+			defer s.wg.Done()
+			ticker := time.NewTicker(reg.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticking.Done():
+					return
+				case <-ticker.C:
+					s.run(ctx, reg.job)
+				}
+			}
+		}(reg)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// Shutdown stops starting new runs and waits for the running ones to
+// finish. If ctx ends first it cancels them, waits for them to return and
+// reports ctx's error.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	halt, cancel := s.halt, s.cancel
+	s.mu.Unlock()
+	if halt == nil {
+		return nil
+	}
+	halt()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v", job.Name(), r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s failed after %s: %v", job.Name(), time.Since(start), err)
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"orus/internal/utils/response"
+	"strings"
+
+	"orus/internal/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MerchantAPIKey authenticates server-to-server merchant requests by their
+// X-API-Key header and adds the merchant to the request context as
+// "merchant". Sandbox keys also set "sandbox" to true; LiveModeOnly and
+// SandboxModeOnly keep each kind of key on its own routes.
+func MerchantAPIKey(merchants repositories.MerchantRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
+		if apiKey == "" {
+			return response.Error(c, fiber.StatusUnauthorized, "missing API key")
+		}
+
+		sandbox := strings.HasPrefix(apiKey, repositories.SandboxAPIKeyPrefix)
+		lookup := merchants.GetByAPIKey
+		if sandbox {
+			lookup = merchants.GetBySandboxAPIKey
+		}
+		merchant, err := lookup(apiKey)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return response.Error(c, fiber.StatusUnauthorized, "invalid API key")
+			}
+			log.Printf("API key lookup failed: %v", err)
+			return response.Error(c, fiber.StatusInternalServerError, "failed to authenticate API key")
+		}
+		if merchant.Status != "active" {
+			return response.Error(c, fiber.StatusForbidden, "merchant account is not active")
+		}
+
+		c.Locals("merchant", merchant)
+		c.Locals("sandbox", sandbox)
+		return c.Next()
+	}
+}
+
+// LiveModeOnly rejects requests made with a sandbox API key, so test
+// integrations never move real money.
+func LiveModeOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if sandbox, _ := c.Locals("sandbox").(bool); sandbox {
+			return response.Error(c, fiber.StatusForbidden, "sandbox API keys can only call sandbox endpoints")
+		}
+		return c.Next()
+	}
+}
+
+// SandboxModeOnly rejects requests made with a live API key.
+func SandboxModeOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if sandbox, _ := c.Locals("sandbox").(bool); !sandbox {
+			return response.Error(c, fiber.StatusForbidden, "sandbox endpoints need a sandbox API key")
+		}
+		return c.Next()
+	}
+}
@@ -5,9 +5,10 @@ package middleware
 
 import (
 	"log"
+	apperrors "orus/internal/errors"
+	"orus/internal/utils/response"
 	"strings"
 
-	"orus/internal/config"
 	"orus/internal/models"
 	"orus/internal/services/auth"
 
@@ -22,11 +23,13 @@ import (
 // and adds the user claims to the request context.
 type AuthMiddleware struct {
 	authService auth.Service
+	keys        *auth.KeySet
 }
 
-func NewAuthMiddleware(authService auth.Service) *AuthMiddleware {
+func NewAuthMiddleware(authService auth.Service, keys *auth.KeySet) *AuthMiddleware {
 	return &AuthMiddleware{
 		authService: authService,
+		keys:        keys,
 	}
 }
 
@@ -36,6 +39,7 @@ func NewAuthMiddleware(authService auth.Service) *AuthMiddleware {
 // - Valid JWT signature
 // - Token expiration
 // - Token version matches current user version
+// - The token's session hasn't been ended
 func (m *AuthMiddleware) Handler(c *fiber.Ctx) error {
 
 	if isPublicRoute(c.Path()) {
@@ -46,65 +50,70 @@ func (m *AuthMiddleware) Handler(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
 		log.Println("Missing Authorization header")
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "missing authorization header"})
+		return response.Error(c, fiber.StatusUnauthorized, "missing authorization header")
 	}
 
 	// Check if the header has the Bearer prefix
 	if !strings.HasPrefix(authHeader, "Bearer ") {
 		log.Println("Invalid Authorization format")
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid authorization format"})
+		return response.Error(c, fiber.StatusUnauthorized, "invalid authorization format")
 	}
 
 	// Extract the token
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 	// Parse and validate the token
-	token, err := jwt.ParseWithClaims(tokenString, &models.UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.GetEnv("JWT_SECRET", "your-secret-key")), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &models.UserClaims{}, m.keys.Keyfunc)
 
 	if err != nil {
 		log.Printf("Token validation error: %v", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid token"})
+		return response.Fail(c, fiber.StatusUnauthorized, apperrors.CodeInvalidToken, "invalid token")
 	}
 
 	// Check if the token is valid
 	if !token.Valid {
 		log.Println("Token is invalid")
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid token"})
+		return response.Fail(c, fiber.StatusUnauthorized, apperrors.CodeInvalidToken, "invalid token")
 	}
 
 	// Extract the claims
 	claims, ok := token.Claims.(*models.UserClaims)
 	if !ok {
 		log.Println("Failed to extract claims")
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid claims"})
+		return response.Error(c, fiber.StatusUnauthorized, "invalid claims")
 	}
 
-	// Add this debug line
-	log.Printf("Token claims: %+v", claims)
-
-	// Get current token version from auth service
-	currentVersion, err := m.authService.GetUserTokenVersion(claims.UserID)
+	// The session state is cached and dropped whenever the token version
+	// or suspension changes, so this doesn't reach the database
+	session, err := m.authService.SessionState(claims.UserID)
 	if err != nil {
-		log.Printf("Error getting token version: %v", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid token"})
+		log.Printf("Error getting session state for user %d: %v", claims.UserID, err)
+		return response.Fail(c, fiber.StatusUnauthorized, apperrors.CodeInvalidToken, "invalid token")
 	}
 
 	// Check if token version matches current version
-	log.Printf("Auth check - User ID: %d, Token version: %d, Current version: %d",
-		claims.UserID, claims.TokenVersion, currentVersion)
-	if claims.TokenVersion != currentVersion {
-		log.Printf("Token version mismatch for user %d. Token: %d, DB: %d",
-			claims.UserID, claims.TokenVersion, currentVersion)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "session expired"})
+	if claims.TokenVersion != session.TokenVersion {
+		log.Printf("Token version mismatch for user %d. Token: %d, current: %d",
+			claims.UserID, claims.TokenVersion, session.TokenVersion)
+		return response.Fail(c, fiber.StatusUnauthorized, apperrors.CodeInvalidToken, "session expired")
+	}
+
+	// A session can be ended on its own; tokens from before sessions are
+	// only checked against the version
+	if claims.SessionID != "" {
+		active, err := m.authService.SessionActive(claims.UserID, claims.SessionID)
+		if err != nil {
+			log.Printf("Error checking session %s of user %d: %v", claims.SessionID, claims.UserID, err)
+			return response.Fail(c, fiber.StatusUnauthorized, apperrors.CodeInvalidToken, "invalid token")
+		}
+		if !active {
+			return response.Fail(c, fiber.StatusUnauthorized, apperrors.CodeInvalidToken, "session expired")
+		}
 	}
 
-	// Add this after extracting claims
-	_, err = m.authService.GetUserByID(claims.UserID)
-	if err != nil {
-		log.Printf("User %d from token not found", claims.UserID)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid token"})
+	// Suspension ends sessions, but a token minted in between must not pay
+	if session.Suspended {
+		return response.Fail(c, fiber.StatusForbidden, apperrors.CodeAccountSuspended, "account is suspended")
 	}
 
 	// Store the claims in the context
@@ -119,7 +128,7 @@ func AdminAuthMiddleware(c *fiber.Ctx) error {
 	claims, ok := c.Locals("claims").(*models.UserClaims)
 	if !ok {
 		log.Println("Claims not found in context")
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid claims"})
+		return response.Error(c, fiber.StatusUnauthorized, "Invalid claims")
 	}
 
 	// Add more detailed debug logging
@@ -127,9 +136,26 @@ func AdminAuthMiddleware(c *fiber.Ctx) error {
 	log.Printf("User permissions: %v", claims.Permissions)
 	log.Printf("Raw claims: %+v", claims)
 
-	if claims.Role != "admin" {
+	// Custom roles such as support agents get in with admin read access
+	if claims.Role != "admin" && claims.Role != "super_admin" && !claims.HasPermission(models.PermissionReadAdmin) {
 		log.Printf("Access denied: User role is %s, not admin", claims.Role)
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Insufficient permissions"})
+		return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
+	}
+
+	return c.Next()
+}
+
+// SuperAdminMiddleware restricts a route to super-admins. Unlike permission
+// checks, regular admins are not let through.
+func SuperAdminMiddleware(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*models.UserClaims)
+	if !ok {
+		return response.Error(c, fiber.StatusUnauthorized, "Invalid claims")
+	}
+
+	if claims.Role != "super_admin" {
+		log.Printf("Access denied: User %d with role %s is not a super admin", claims.UserID, claims.Role)
+		return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
 	}
 
 	return c.Next()
@@ -140,13 +166,13 @@ func HasPermission(permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims, ok := c.Locals("claims").(*models.UserClaims)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+			return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 		}
 		log.Printf("Checking permission: %s", permission)
 		log.Printf("User claims: %+v", claims)
 
 		// If user is admin, allow all permissions
-		if claims.Role == "admin" {
+		if claims.Role == "admin" || claims.Role == "super_admin" {
 			return c.Next()
 		}
 
@@ -154,7 +180,7 @@ func HasPermission(permission string) fiber.Handler {
 			return c.Next()
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Insufficient permissions"})
+		return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
 	}
 }
 
@@ -163,12 +189,12 @@ func Protected() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims, ok := c.Locals("claims").(*models.UserClaims)
 		if !ok || claims == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+			return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 		}
 
 		requiredRole := getRequiredRole(c.Path())
 		if !hasRequiredRole(claims.Role, requiredRole) {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Insufficient permissions"})
+			return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
 		}
 
 		return c.Next()
@@ -194,10 +220,11 @@ func getRequiredRole(path string) string {
 // hasRequiredRole compares the user role and the required role based on a hierarchy.
 func hasRequiredRole(userRole, requiredRole string) bool {
 	roleHierarchy := map[string]int{
-		"user":       1,
-		"merchant":   2,
-		"enterprise": 3,
-		"admin":      4,
+		"user":        1,
+		"merchant":    2,
+		"enterprise":  3,
+		"admin":       4,
+		"super_admin": 5,
 	}
 
 	userRoleLevel := roleHierarchy[userRole]
@@ -0,0 +1,793 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	apperrors "orus/internal/errors"
+	"orus/internal/repositories"
+	"orus/internal/resilience"
+	"orus/internal/services/accounting"
+	"orus/internal/services/apiaccess"
+	"orus/internal/services/auth"
+	"orus/internal/services/autotopup"
+	"orus/internal/services/bulk"
+	"orus/internal/services/category"
+	"orus/internal/services/checkout"
+	"orus/internal/services/compliance"
+	"orus/internal/services/confirmation"
+	"orus/internal/services/contact"
+	creditcard "orus/internal/services/credit-card"
+	"orus/internal/services/dashboard"
+	"orus/internal/services/deviceconfirm"
+	"orus/internal/services/dispute"
+	"orus/internal/services/enterprise"
+	"orus/internal/services/escrow"
+	"orus/internal/services/export"
+	"orus/internal/services/featureflag"
+	"orus/internal/services/fraud"
+	"orus/internal/services/funding"
+	"orus/internal/services/fxrates"
+	"orus/internal/services/handle"
+	"orus/internal/services/investigation"
+	"orus/internal/services/invoice"
+	"orus/internal/services/issuing"
+	"orus/internal/services/loyalty"
+	"orus/internal/services/maintenance"
+	"orus/internal/services/merchant"
+	"orus/internal/services/merchantrisk"
+	"orus/internal/services/oauth"
+	"orus/internal/services/overdraft"
+	"orus/internal/services/paymentcode"
+	"orus/internal/services/paymentintent"
+	"orus/internal/services/paymentpin"
+	"orus/internal/services/pendingtx"
+	"orus/internal/services/pot"
+	"orus/internal/services/promotion"
+	qr "orus/internal/services/qr_code"
+	"orus/internal/services/ratelimit"
+	"orus/internal/services/rbac"
+	"orus/internal/services/receipt"
+	"orus/internal/services/refundrequest"
+	"orus/internal/services/reserve"
+	"orus/internal/services/saga"
+	"orus/internal/services/sandbox"
+	"orus/internal/services/scanconfirm"
+	"orus/internal/services/sociallogin"
+	"orus/internal/services/spendcontrol"
+	"orus/internal/services/split"
+	"orus/internal/services/staff"
+	"orus/internal/services/subscription"
+	"orus/internal/services/taxsummary"
+	"orus/internal/services/terminal"
+	"orus/internal/services/transaction"
+	"orus/internal/services/treasury"
+	"orus/internal/services/useradmin"
+	"orus/internal/services/vault"
+	"orus/internal/services/wallet"
+	"orus/internal/services/webhook"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// domainErrors maps the sentinel errors services return to their API codes.
+// A *apperrors.DomainError carries its code itself.
+var domainErrors = map[error]string{
+	// Authentication
+	auth.ErrInvalidCredentials:      apperrors.CodeInvalidCredentials,
+	auth.ErrAccountSuspended:        apperrors.CodeAccountSuspended,
+	auth.ErrReportLinkInvalid:       "LOGIN_REPORT_LINK_INVALID",
+	repositories.ErrSessionNotFound: "SESSION_NOT_FOUND",
+
+	// Social login
+	sociallogin.ErrUnknownProvider:  "UNKNOWN_LOGIN_PROVIDER",
+	sociallogin.ErrProviderDisabled: "LOGIN_PROVIDER_DISABLED",
+	sociallogin.ErrInvalidState:     "INVALID_LOGIN_STATE",
+	sociallogin.ErrExchangeFailed:   "LOGIN_PROVIDER_REFUSED",
+	sociallogin.ErrInvalidIDToken:   "INVALID_ID_TOKEN",
+	sociallogin.ErrEmailUnverified:  "PROVIDER_EMAIL_UNVERIFIED",
+	sociallogin.ErrSignInToLink:     "SIGN_IN_TO_LINK",
+	sociallogin.ErrIdentityLinked:   "IDENTITY_ALREADY_LINKED",
+
+	// Third-party apps
+	oauth.ErrClientNotFound:         "OAUTH_CLIENT_NOT_FOUND",
+	oauth.ErrInvalidClient:          "INVALID_OAUTH_CLIENT",
+	oauth.ErrInvalidAuthorization:   "INVALID_AUTHORIZATION_REQUEST",
+	oauth.ErrInvalidToken:           "INVALID_ACCESS_TOKEN",
+	oauth.ErrInsufficientScope:      "INSUFFICIENT_SCOPE",
+	oauth.ErrConnectionNotFound:     "APP_NOT_CONNECTED",
+	oauth.ErrPaymentRequestNotFound: "APP_PAYMENT_REQUEST_NOT_FOUND",
+	oauth.ErrPaymentRequestDecided:  "APP_PAYMENT_REQUEST_DECIDED",
+	oauth.ErrInvalidPaymentRequest:  "INVALID_APP_PAYMENT_REQUEST",
+
+	// Users and cards
+	repositories.ErrUserNotFound: "USER_NOT_FOUND",
+	repositories.ErrEmailTaken:   "EMAIL_TAKEN",
+	repositories.ErrPhoneTaken:   "PHONE_TAKEN",
+	repositories.ErrHandleTaken:  "HANDLE_TAKEN",
+	repositories.ErrCardNotFound: "CARD_NOT_FOUND",
+
+	// Wallets
+	wallet.ErrInsufficientBalance:       "INSUFFICIENT_BALANCE",
+	wallet.ErrInvalidAmount:             "INVALID_AMOUNT",
+	wallet.ErrSharedWalletNotFound:      "SHARED_WALLET_NOT_FOUND",
+	wallet.ErrMemberNotFound:            "MEMBER_NOT_FOUND",
+	wallet.ErrRecipientNotFound:         "RECIPIENT_NOT_FOUND",
+	wallet.ErrPaymentNotFound:           "SHARED_PAYMENT_NOT_FOUND",
+	wallet.ErrWalletRoleForbidden:       "WALLET_ROLE_FORBIDDEN",
+	wallet.ErrMemberLimitExceeded:       "MEMBER_LIMIT_EXCEEDED",
+	wallet.ErrMemberDailyLimit:          "MEMBER_DAILY_LIMIT",
+	wallet.ErrWalletLocked:              "WALLET_LOCKED",
+	wallet.ErrWalletBusy:                "WALLET_BUSY",
+	wallet.ErrMemberExists:              "MEMBER_EXISTS",
+	wallet.ErrLastOwner:                 "LAST_OWNER",
+	wallet.ErrPaymentNotPending:         "SHARED_PAYMENT_NOT_PENDING",
+	wallet.ErrTooManyMembers:            "TOO_MANY_MEMBERS",
+	wallet.ErrInvalidWalletName:         "INVALID_WALLET_NAME",
+	wallet.ErrInvalidWalletRole:         "INVALID_WALLET_ROLE",
+	wallet.ErrInvalidLimit:              "INVALID_LIMIT",
+	wallet.ErrInvalidCurrency:           "INVALID_CURRENCY",
+	wallet.ErrInsufficientSharedFunds:   "INSUFFICIENT_SHARED_FUNDS",
+	repositories.ErrWalletNotFound:      "WALLET_NOT_FOUND",
+	repositories.ErrOverdraftExceeded:   "OVERDRAFT_LIMIT_EXCEEDED",
+	repositories.ErrInsufficientBalance: "INSUFFICIENT_BALANCE",
+	wallet.ErrDailyLimitExceeded:        "DAILY_LIMIT_EXCEEDED",
+	wallet.ErrMonthlyLimitExceeded:      "MONTHLY_LIMIT_EXCEEDED",
+	wallet.ErrWalletNotFound:            "WALLET_NOT_FOUND",
+	wallet.ErrHoldNotActive:             "HOLD_NOT_ACTIVE",
+	wallet.ErrInvalidOperation:          "INVALID_OPERATION",
+
+	// Overdrafts
+	overdraft.ErrInvalidLimit: "INVALID_OVERDRAFT_LIMIT",
+
+	// Linked cards
+	creditcard.ErrCardNotOwned:           "CARD_NOT_FOUND",
+	creditcard.ErrCardNotActive:          "CARD_NOT_ACTIVE",
+	creditcard.ErrCardExpired:            "CARD_EXPIRED",
+	creditcard.ErrCardNotVerified:        "CARD_NOT_VERIFIED",
+	creditcard.ErrAlreadyVerified:        "CARD_ALREADY_VERIFIED",
+	creditcard.ErrVerificationNotStarted: "CARD_VERIFICATION_NOT_STARTED",
+	creditcard.ErrVerificationFailed:     "CARD_VERIFICATION_FAILED",
+	creditcard.ErrTooManyAttempts:        "CARD_VERIFICATION_LOCKED",
+	creditcard.ErrInvalidExpiry:          "INVALID_CARD_EXPIRY",
+	creditcard.ErrInvalidBillingCountry:  "INVALID_BILLING_COUNTRY",
+	creditcard.ErrChargeDeclined:         "CARD_DECLINED",
+
+	// Card top-ups
+	repositories.ErrCardTopUpNotFound: "TOP_UP_NOT_FOUND",
+	wallet.ErrInvalidTopUpEvent:       "INVALID_TOP_UP_EVENT",
+
+	// Card vault
+	vault.ErrInvalidCardNumber:  "INVALID_CARD_NUMBER",
+	vault.ErrDirectTokenization: "CARD_TOKEN_REQUIRED",
+
+	// Wallet locks
+	wallet.ErrDebitsLocked:             "DEBITS_LOCKED",
+	wallet.ErrCreditsLocked:            "CREDITS_LOCKED",
+	wallet.ErrInvalidLockScope:         "INVALID_LOCK_SCOPE",
+	wallet.ErrInvalidLockReason:        "INVALID_LOCK_REASON",
+	wallet.ErrInvalidLockExpiry:        "INVALID_LOCK_EXPIRY",
+	wallet.ErrLockNotOwned:             "LOCK_NOT_OWNED",
+	repositories.ErrWalletLockNotFound: "WALLET_LOCK_NOT_FOUND",
+
+	// Payments
+	transaction.ErrInsufficientBalance: "INSUFFICIENT_BALANCE",
+	transaction.ErrHighRiskTransaction: "HIGH_RISK_TRANSACTION",
+
+	// Reversals
+	transaction.ErrTransactionNotFound: "TRANSACTION_NOT_FOUND",
+	transaction.ErrNotReversible:       "NOT_REVERSIBLE",
+	transaction.ErrAlreadyReversed:     "ALREADY_REVERSED",
+	transaction.ErrHasRefunds:          "TRANSACTION_HAS_REFUNDS",
+	transaction.ErrReasonRequired:      "REVERSAL_REASON_REQUIRED",
+	transaction.ErrReversalUnfunded:    "REVERSAL_UNFUNDED",
+
+	// QR codes
+	qr.ErrInvalidRequest:           "INVALID_REQUEST",
+	qr.ErrInvalidQRType:            "INVALID_QR_TYPE",
+	qr.ErrInvalidUserType:          "INVALID_USER_TYPE",
+	qr.ErrQRExpired:                "QR_EXPIRED",
+	qr.ErrQRInactive:               "QR_INACTIVE",
+	qr.ErrQRLimitExceeded:          "QR_LIMIT_EXCEEDED",
+	qr.ErrInvalidAmount:            "INVALID_AMOUNT",
+	qr.ErrInsufficientFunds:        "INSUFFICIENT_BALANCE",
+	qr.ErrInvalidQRStatus:          "INVALID_QR_STATUS",
+	qr.ErrQRNotRegenerable:         "QR_NOT_REGENERABLE",
+	qr.ErrQRDailyLimit:             "QR_DAILY_LIMIT_EXCEEDED",
+	qr.ErrQRMonthlyLimit:           "QR_MONTHLY_LIMIT_EXCEEDED",
+	qr.ErrNotPoster:                "QR_NOT_POSTER",
+	qr.ErrTerminalNotFound:         "TERMINAL_NOT_FOUND",
+	repositories.ErrQRCodeNotFound: "QR_NOT_FOUND",
+
+	// Contacts
+	contact.ErrContactNotFound:   "CONTACT_NOT_FOUND",
+	contact.ErrRecipientNotFound: "RECIPIENT_NOT_FOUND",
+	contact.ErrContactExists:     "CONTACT_EXISTS",
+	contact.ErrInvalidRecipient:  "INVALID_RECIPIENT",
+	contact.ErrSelfContact:       "SELF_CONTACT",
+	contact.ErrNicknameTooLong:   "NICKNAME_TOO_LONG",
+	contact.ErrNotBeneficiary:    "NOT_BENEFICIARY",
+	contact.ErrCoolingOff:        "COOLING_OFF",
+
+	// Fraud rules
+	fraud.ErrDeclined:         "PAYMENT_DECLINED",
+	fraud.ErrNotMerchant:      "NOT_MERCHANT",
+	fraud.ErrInvalidAmount:    "INVALID_AMOUNT",
+	fraud.ErrInvalidVelocity:  "INVALID_VELOCITY",
+	fraud.ErrInvalidCountry:   "INVALID_COUNTRY",
+	fraud.ErrTooManyCountries: "TOO_MANY_COUNTRIES",
+
+	// Merchant API access
+	apiaccess.ErrIPNotAllowed:          "IP_NOT_ALLOWED",
+	apiaccess.ErrClientCertRequired:    "CLIENT_CERT_REQUIRED",
+	apiaccess.ErrClientCertNotAllowed:  "CLIENT_CERT_NOT_ALLOWED",
+	apiaccess.ErrInvalidCIDR:           "INVALID_IP_RANGE",
+	apiaccess.ErrInvalidKeyMode:        "INVALID_KEY_MODE",
+	apiaccess.ErrTooManyIPRanges:       "TOO_MANY_IP_RANGES",
+	apiaccess.ErrInvalidCertificate:    "INVALID_CERTIFICATE",
+	apiaccess.ErrCertificateExpired:    "CERTIFICATE_EXPIRED",
+	apiaccess.ErrCertificateExists:     "CERTIFICATE_EXISTS",
+	apiaccess.ErrNoClientCertificates:  "NO_CLIENT_CERTIFICATES",
+	apiaccess.ErrLastCertificate:       "LAST_CLIENT_CERTIFICATE",
+	apiaccess.ErrNotMerchant:           "NOT_MERCHANT",
+	repositories.ErrIPRangeNotFound:    "IP_RANGE_NOT_FOUND",
+	repositories.ErrClientCertNotFound: "CLIENT_CERT_NOT_FOUND",
+
+	// Merchants
+	merchant.ErrChargeNotFound:      "CHARGE_NOT_FOUND",
+	merchant.ErrRefundExceedsCharge: "REFUND_EXCEEDS_CHARGE",
+	merchant.ErrInvalidAmount:       "INVALID_AMOUNT",
+	merchant.ErrMerchantInactive:    "MERCHANT_INACTIVE",
+	merchant.ErrLimitExceeded:       "TRANSACTION_LIMIT_EXCEEDED",
+	merchant.ErrMerchantNotFound:    "MERCHANT_NOT_FOUND",
+	merchant.ErrUnknownFeePlan:      "UNKNOWN_FEE_PLAN",
+	merchant.ErrInvalidFeeRate:      "INVALID_FEE_RATE",
+
+	// Receipts
+	receipt.ErrTransactionNotFound: "TRANSACTION_NOT_FOUND",
+	receipt.ErrNotMerchantPayment:  "NOT_MERCHANT_PAYMENT",
+	receipt.ErrInvalidEmail:        "INVALID_EMAIL",
+	receipt.ErrInvalidItem:         "INVALID_ITEM",
+	receipt.ErrInvalidAmounts:      "INVALID_AMOUNTS",
+	receipt.ErrItemsMismatch:       "ITEMS_MISMATCH",
+
+	// Transaction categories
+	category.ErrTransactionNotFound:      "TRANSACTION_NOT_FOUND",
+	category.ErrInvalidCategory:          "INVALID_CATEGORY",
+	category.ErrNotSpending:              "NOT_SPENDING",
+	category.ErrInvalidRule:              "INVALID_CATEGORY_RULE",
+	category.ErrTooManyRules:             "TOO_MANY_CATEGORY_RULES",
+	category.ErrNoPayee:                  "NO_PAYEE",
+	repositories.ErrCategoryRuleNotFound: "CATEGORY_RULE_NOT_FOUND",
+
+	// Merchant staff
+	staff.ErrStaffNotFound:    "STAFF_NOT_FOUND",
+	staff.ErrMerchantNotFound: "MERCHANT_NOT_FOUND",
+	staff.ErrUserNotFound:     "USER_NOT_FOUND",
+	staff.ErrStaffInactive:    "STAFF_INACTIVE",
+	staff.ErrScopeDenied:      "SCOPE_DENIED",
+	staff.ErrWrongPIN:         "WRONG_PIN",
+	staff.ErrPINNotSet:        "PIN_NOT_SET",
+	staff.ErrPINLocked:        "PIN_LOCKED",
+	staff.ErrStaffExists:      "STAFF_EXISTS",
+	staff.ErrNotInvited:       "NOT_INVITED",
+	staff.ErrSelfInvite:       "SELF_INVITE",
+	staff.ErrTooManyStaff:     "TOO_MANY_STAFF",
+	staff.ErrInvalidPIN:       "INVALID_PIN",
+	staff.ErrInvalidPeriod:    "INVALID_PERIOD",
+
+	// Terminals
+	terminal.ErrTerminalNotFound:    "TERMINAL_NOT_FOUND",
+	terminal.ErrMerchantNotFound:    "MERCHANT_NOT_FOUND",
+	terminal.ErrTerminalInactive:    "TERMINAL_INACTIVE",
+	terminal.ErrTerminalDeactivated: "TERMINAL_DEACTIVATED",
+	terminal.ErrInvalidPairingCode:  "INVALID_PAIRING_CODE",
+	terminal.ErrInvalidCredentials:  "INVALID_TERMINAL_CREDENTIALS",
+	terminal.ErrNameRequired:        "NAME_REQUIRED",
+	terminal.ErrTooManyTerminals:    "TOO_MANY_TERMINALS",
+
+	// One-time payment codes
+	paymentcode.ErrInvalidAmount:      "INVALID_AMOUNT",
+	paymentcode.ErrInvalidDigits:      "INVALID_CODE_LENGTH",
+	paymentcode.ErrInvalidExpiry:      "INVALID_CODE_EXPIRY",
+	paymentcode.ErrInvalidCode:        "INVALID_PAYMENT_CODE",
+	paymentcode.ErrAmountExceedsCode:  "PAYMENT_CODE_AMOUNT_EXCEEDED",
+	paymentcode.ErrSelfRedemption:     "SELF_PAYMENT",
+	paymentcode.ErrTooManyAttempts:    "PAYMENT_CODE_LOCKED",
+	paymentcode.ErrCodeSpaceExhausted: "PAYMENT_CODE_UNAVAILABLE",
+
+	// Payment intents
+	paymentintent.ErrInvalidAmount: "INVALID_AMOUNT",
+	paymentintent.ErrInvalidExpiry: "INVALID_INTENT_EXPIRY",
+	paymentintent.ErrInvalidIntent: "INVALID_PAYMENT_INTENT",
+	paymentintent.ErrIntentExpired: "PAYMENT_INTENT_EXPIRED",
+	paymentintent.ErrIntentPaid:    "PAYMENT_INTENT_PAID",
+	paymentintent.ErrSelfPayment:   "SELF_PAYMENT_INTENT",
+	paymentintent.ErrRecipientGone: "PAYMENT_INTENT_RECIPIENT_GONE",
+
+	// Payment links and checkout
+	repositories.ErrPaymentLinkNotFound:     "PAYMENT_LINK_NOT_FOUND",
+	repositories.ErrCheckoutSessionNotFound: "CHECKOUT_SESSION_NOT_FOUND",
+	checkout.ErrSessionNotAllowed:           "CHECKOUT_SESSION_NOT_ALLOWED",
+	checkout.ErrNotMerchant:                 "NOT_MERCHANT",
+	checkout.ErrLinkUnavailable:             "PAYMENT_LINK_UNAVAILABLE",
+	checkout.ErrSessionExpired:              "CHECKOUT_SESSION_EXPIRED",
+	checkout.ErrSessionNotOpen:              "CHECKOUT_SESSION_NOT_OPEN",
+	checkout.ErrInvalidAmount:               "INVALID_AMOUNT",
+	checkout.ErrInvalidExpiry:               "INVALID_EXPIRY",
+	checkout.ErrInvalidMaxUses:              "INVALID_MAX_USES",
+	checkout.ErrSelfPayment:                 "SELF_PAYMENT",
+	checkout.ErrInvalidSessionTTL:           "INVALID_SESSION_TTL",
+	checkout.ErrInvalidReturnURL:            "INVALID_RETURN_URL",
+	checkout.ErrInvalidMetadata:             "INVALID_METADATA",
+	checkout.ErrInvalidReference:            "INVALID_REFERENCE",
+
+	// Invoices
+	repositories.ErrInvoiceNotFound: "INVOICE_NOT_FOUND",
+	invoice.ErrNotMerchant:          "NOT_MERCHANT",
+	invoice.ErrNotEditable:          "INVOICE_NOT_EDITABLE",
+	invoice.ErrNotPayable:           "INVOICE_NOT_PAYABLE",
+	invoice.ErrCannotVoid:           "INVOICE_NOT_VOIDABLE",
+	invoice.ErrNoLineItems:          "NO_LINE_ITEMS",
+	invoice.ErrInvalidLineItem:      "INVALID_LINE_ITEM",
+	invoice.ErrInvalidTaxRate:       "INVALID_TAX_RATE",
+	invoice.ErrInvalidEmail:         "INVALID_EMAIL",
+	invoice.ErrInvalidDueDate:       "INVALID_DUE_DATE",
+	invoice.ErrInvalidTotal:         "INVALID_INVOICE_TOTAL",
+	invoice.ErrSelfPayment:          "SELF_PAYMENT",
+
+	// Subscriptions
+	subscription.ErrPlanNotFound:         "PLAN_NOT_FOUND",
+	subscription.ErrSubscriptionNotFound: "SUBSCRIPTION_NOT_FOUND",
+	subscription.ErrNotMerchant:          "NOT_MERCHANT",
+	subscription.ErrAlreadySubscribed:    "ALREADY_SUBSCRIBED",
+	subscription.ErrNotCancelable:        "SUBSCRIPTION_CANCELED",
+	subscription.ErrNotPausable:          "SUBSCRIPTION_NOT_PAUSABLE",
+	subscription.ErrNotPaused:            "SUBSCRIPTION_NOT_PAUSED",
+	subscription.ErrPlanArchived:         "PLAN_ARCHIVED",
+	subscription.ErrNameRequired:         "NAME_REQUIRED",
+	subscription.ErrInvalidAmount:        "INVALID_AMOUNT",
+	subscription.ErrInvalidInterval:      "INVALID_INTERVAL",
+	subscription.ErrInvalidTrial:         "INVALID_TRIAL",
+	subscription.ErrSelfSubscription:     "SELF_SUBSCRIPTION",
+	subscription.ErrConsentRequired:      "CONSENT_REQUIRED",
+
+	// Loyalty
+	loyalty.ErrNotMerchant:        "NOT_MERCHANT",
+	loyalty.ErrProgramNotFound:    "LOYALTY_PROGRAM_NOT_FOUND",
+	loyalty.ErrAccountNotFound:    "LOYALTY_ACCOUNT_NOT_FOUND",
+	loyalty.ErrNameRequired:       "NAME_REQUIRED",
+	loyalty.ErrInvalidEarnRate:    "INVALID_EARN_RATE",
+	loyalty.ErrInvalidBurnRate:    "INVALID_BURN_RATE",
+	loyalty.ErrInvalidRedeemLimit: "INVALID_REDEEM_LIMIT",
+	loyalty.ErrInvalidMinimum:     "INVALID_MINIMUM",
+	loyalty.ErrInvalidStatus:      "INVALID_STATUS",
+	loyalty.ErrInvalidPoints:      "INVALID_POINTS",
+	loyalty.ErrInvalidAmount:      "INVALID_AMOUNT",
+	loyalty.ErrBelowMinimum:       "BELOW_REDEMPTION_MINIMUM",
+	loyalty.ErrInsufficientPoints: "INSUFFICIENT_POINTS",
+	loyalty.ErrDiscountTooSmall:   "DISCOUNT_TOO_SMALL",
+	loyalty.ErrSelfRedemption:     "SELF_REDEMPTION",
+
+	// Promotions
+	promotion.ErrPromotionNotFound: "PROMOTION_NOT_FOUND",
+	promotion.ErrCodeNotFound:      "PROMO_CODE_NOT_FOUND",
+	promotion.ErrCodeTaken:         "PROMO_CODE_TAKEN",
+	promotion.ErrNotPausable:       "PROMOTION_NOT_PAUSABLE",
+	promotion.ErrNotResumable:      "PROMOTION_NOT_RESUMABLE",
+	promotion.ErrAlreadyEnded:      "PROMOTION_ENDED",
+	promotion.ErrAlreadyRedeemed:   "PROMO_CODE_ALREADY_REDEEMED",
+	promotion.ErrCodeExpired:       "PROMO_CODE_EXPIRED",
+	promotion.ErrNameRequired:      "NAME_REQUIRED",
+	promotion.ErrInvalidType:       "INVALID_PROMOTION_TYPE",
+	promotion.ErrInvalidRate:       "INVALID_CASHBACK_RATE",
+	promotion.ErrInvalidAmount:     "INVALID_AMOUNT",
+	promotion.ErrInvalidBudget:     "INVALID_BUDGET",
+	promotion.ErrInvalidLimit:      "INVALID_LIMIT",
+	promotion.ErrInvalidFunding:    "INVALID_FUNDING",
+	promotion.ErrMerchantRequired:  "MERCHANT_REQUIRED",
+	promotion.ErrMerchantNotFound:  "MERCHANT_NOT_FOUND",
+	promotion.ErrInvalidSchedule:   "INVALID_SCHEDULE",
+	promotion.ErrInvalidCode:       "INVALID_PROMO_CODE",
+
+	// Dashboards
+	dashboard.ErrNotMerchant:        "NOT_MERCHANT",
+	dashboard.ErrInvalidSort:        "INVALID_SORT",
+	dashboard.ErrInvalidGranularity: "INVALID_GRANULARITY",
+	dashboard.ErrInvalidMetric:      "INVALID_METRIC",
+	dashboard.ErrInvalidTimezone:    "INVALID_TIMEZONE",
+	dashboard.ErrInvalidDate:        "INVALID_DATE",
+	dashboard.ErrInvalidDateRange:   "INVALID_DATE_RANGE",
+	dashboard.ErrRangeTooLong:       "DATE_RANGE_TOO_LONG",
+
+	// Savings pots
+	pot.ErrPotNotFound:          "POT_NOT_FOUND",
+	pot.ErrPotLocked:            "POT_LOCKED",
+	pot.ErrPotClosed:            "POT_CLOSED",
+	pot.ErrRoundUpPotExists:     "ROUND_UP_POT_EXISTS",
+	pot.ErrNameRequired:         "NAME_REQUIRED",
+	pot.ErrInvalidAmount:        "INVALID_AMOUNT",
+	pot.ErrInvalidTarget:        "INVALID_TARGET",
+	pot.ErrInvalidRoundUp:       "INVALID_ROUND_UP",
+	pot.ErrInvalidSweep:         "INVALID_SWEEP",
+	pot.ErrLockInPast:           "LOCK_IN_PAST",
+	pot.ErrLockShortened:        "LOCK_SHORTENED",
+	pot.ErrInsufficientPotFunds: "INSUFFICIENT_POT_FUNDS",
+	pot.ErrTooManyPots:          "TOO_MANY_POTS",
+
+	// Auto top-up
+	autotopup.ErrInvalidAmount:            "INVALID_AMOUNT",
+	autotopup.ErrInvalidThreshold:         "INVALID_THRESHOLD",
+	autotopup.ErrInvalidCap:               "INVALID_TOP_UP_CAP",
+	autotopup.ErrCardNotFound:             "CARD_NOT_FOUND",
+	autotopup.ErrNoDefaultCard:            "NO_DEFAULT_CARD",
+	repositories.ErrAutoTopUpRuleNotFound: "AUTO_TOP_UP_NOT_FOUND",
+
+	// Bulk operations
+	bulk.ErrSourceRequired:                "BULK_SOURCE_REQUIRED",
+	bulk.ErrEmptyFilter:                   "BULK_FILTER_EMPTY",
+	bulk.ErrInvalidCSV:                    "INVALID_CSV",
+	bulk.ErrFileTooLarge:                  "FILE_TOO_LARGE",
+	bulk.ErrNoTargets:                     "BULK_NO_TARGETS",
+	bulk.ErrTooManyTargets:                "BULK_TOO_MANY_TARGETS",
+	repositories.ErrBulkOperationNotFound: "BULK_OPERATION_NOT_FOUND",
+
+	// Transaction investigation
+	investigation.ErrTransactionNotFound: "TRANSACTION_NOT_FOUND",
+
+	// Merchant risk
+	merchantrisk.ErrMerchantNotFound: "MERCHANT_NOT_FOUND",
+	merchantrisk.ErrNotRestricted:    "MERCHANT_NOT_RESTRICTED",
+	merchantrisk.ErrInvalidStatus:    "INVALID_STATUS",
+	merchantrisk.ErrBusy:             "MERCHANT_RISK_BUSY",
+
+	// Exchange rates
+	fxrates.ErrUnsupportedCurrency: "UNSUPPORTED_CURRENCY",
+	fxrates.ErrInvalidAmount:       "INVALID_AMOUNT",
+	fxrates.ErrInvalidRates:        apperrors.CodeServiceUnavailable,
+
+	// Split bills
+	split.ErrSplitNotFound:          "SPLIT_NOT_FOUND",
+	split.ErrTransactionNotFound:    "TRANSACTION_NOT_FOUND",
+	split.ErrParticipantNotFound:    "PARTICIPANT_NOT_FOUND",
+	split.ErrNotOrganizer:           "NOT_ORGANIZER",
+	split.ErrNotParticipant:         "NOT_PARTICIPANT",
+	split.ErrTransactionNotOwned:    "TRANSACTION_NOT_OWNED",
+	split.ErrAlreadySplit:           "ALREADY_SPLIT",
+	split.ErrShareAlreadyPaid:       "SHARE_ALREADY_PAID",
+	split.ErrNotPayable:             "SPLIT_NOT_PAYABLE",
+	split.ErrNotCancellable:         "SPLIT_NOT_CANCELLABLE",
+	split.ErrInvalidAmount:          "INVALID_AMOUNT",
+	split.ErrTitleRequired:          "TITLE_REQUIRED",
+	split.ErrNoParticipants:         "NO_PARTICIPANTS",
+	split.ErrTooManyParticipants:    "TOO_MANY_PARTICIPANTS",
+	split.ErrDuplicateParticipant:   "DUPLICATE_PARTICIPANT",
+	split.ErrOrganizerIsParticipant: "ORGANIZER_IS_PARTICIPANT",
+	split.ErrMixedShares:            "MIXED_SHARES",
+	split.ErrSharesExceedTotal:      "SHARES_EXCEED_TOTAL",
+
+	// Escrow
+	escrow.ErrEscrowNotFound:      "ESCROW_NOT_FOUND",
+	escrow.ErrSellerNotFound:      "SELLER_NOT_FOUND",
+	escrow.ErrNotBuyer:            "NOT_BUYER",
+	escrow.ErrNotSeller:           "NOT_SELLER",
+	escrow.ErrNotOpen:             "ESCROW_NOT_OPEN",
+	escrow.ErrDisputed:            "ESCROW_DISPUTED",
+	escrow.ErrNotDisputed:         "ESCROW_NOT_DISPUTED",
+	escrow.ErrAlreadyDisputed:     "ESCROW_ALREADY_DISPUTED",
+	escrow.ErrSelfEscrow:          "SELF_ESCROW",
+	escrow.ErrInvalidAmount:       "INVALID_AMOUNT",
+	escrow.ErrDescriptionRequired: "DESCRIPTION_REQUIRED",
+	escrow.ErrInvalidReleaseDays:  "INVALID_RELEASE_DAYS",
+	escrow.ErrReasonRequired:      "REASON_REQUIRED",
+	escrow.ErrInvalidOutcome:      "INVALID_OUTCOME",
+
+	// Disputes and chargebacks
+	dispute.ErrDisputeNotFound:          "DISPUTE_NOT_FOUND",
+	dispute.ErrEvidenceNotFound:         "EVIDENCE_NOT_FOUND",
+	dispute.ErrChargebackNotFound:       "CHARGEBACK_NOT_FOUND",
+	dispute.ErrNotCounterparty:          "NOT_COUNTERPARTY",
+	dispute.ErrDisputeClosed:            "DISPUTE_CLOSED",
+	dispute.ErrInvalidTransition:        "INVALID_DISPUTE_TRANSITION",
+	dispute.ErrTooMuchEvidence:          "TOO_MUCH_EVIDENCE",
+	dispute.ErrChargebackClosed:         "CHARGEBACK_CLOSED",
+	dispute.ErrAlreadyRepresented:       "ALREADY_REPRESENTED",
+	dispute.ErrRepresentmentClosed:      "REPRESENTMENT_CLOSED",
+	dispute.ErrFileTooLarge:             "FILE_TOO_LARGE",
+	dispute.ErrEmptyFile:                "EMPTY_FILE",
+	dispute.ErrFileType:                 "INVALID_FILE_TYPE",
+	dispute.ErrResponseRequired:         "RESPONSE_REQUIRED",
+	dispute.ErrInvalidDeadline:          "INVALID_DEADLINE",
+	dispute.ErrInvalidOutcome:           "INVALID_OUTCOME",
+	dispute.ErrInvalidRefund:            "INVALID_REFUND",
+	dispute.ErrNoCounterparty:           "NO_COUNTERPARTY",
+	dispute.ErrEscrowDispute:            "ESCROW_DISPUTE",
+	dispute.ErrInvalidCategory:          "INVALID_DISPUTE_CATEGORY",
+	dispute.ErrInvalidAmount:            "INVALID_DISPUTE_AMOUNT",
+	dispute.ErrTooManyAttachments:       "TOO_MANY_ATTACHMENTS",
+	dispute.ErrRepresentmentRequired:    "REPRESENTMENT_REQUIRED",
+	dispute.ErrInvalidChargebackAmount:  "INVALID_CHARGEBACK_AMOUNT",
+	dispute.ErrInvalidChargebackOutcome: "INVALID_CHARGEBACK_OUTCOME",
+
+	// Bank funding
+	repositories.ErrBankAccountNotFound: "BANK_ACCOUNT_NOT_FOUND",
+	repositories.ErrDepositNotFound:     "DEPOSIT_NOT_FOUND",
+	funding.ErrInvalidAmount:            "INVALID_AMOUNT",
+	funding.ErrInvalidAccountDetails:    "INVALID_ACCOUNT_DETAILS",
+	funding.ErrInvalidPublicToken:       "INVALID_PUBLIC_TOKEN",
+	funding.ErrAccountNotVerified:       "BANK_ACCOUNT_NOT_VERIFIED",
+	funding.ErrAlreadyVerified:          "BANK_ACCOUNT_ALREADY_VERIFIED",
+	funding.ErrVerificationFailed:       "VERIFICATION_FAILED",
+	funding.ErrTooManyAttempts:          "TOO_MANY_VERIFICATION_ATTEMPTS",
+	funding.ErrInvalidDepositEvent:      "INVALID_DEPOSIT_EVENT",
+	funding.ErrTransferFailed:           "BANK_TRANSFER_FAILED",
+
+	// Virtual cards
+	repositories.ErrVirtualCardNotFound: "VIRTUAL_CARD_NOT_FOUND",
+	issuing.ErrInvalidCardType:          "INVALID_CARD_TYPE",
+	issuing.ErrInvalidSpendLimit:        "INVALID_SPEND_LIMIT",
+	issuing.ErrCardClosed:               "CARD_CLOSED",
+	issuing.ErrCardAlreadyFrozen:        "CARD_ALREADY_FROZEN",
+	issuing.ErrCardNotFrozen:            "CARD_NOT_FROZEN",
+	issuing.ErrWalletNotActive:          "WALLET_NOT_ACTIVE",
+	issuing.ErrTooManyActiveCards:       "TOO_MANY_ACTIVE_CARDS",
+
+	// Scheduled exports
+	repositories.ErrExportScheduleNotFound: "EXPORT_SCHEDULE_NOT_FOUND",
+	export.ErrInvalidDayOfMonth:            "INVALID_DAY_OF_MONTH",
+	export.ErrInvalidDayOfWeek:             "INVALID_DAY_OF_WEEK",
+	export.ErrInvalidFrequency:             "INVALID_FREQUENCY",
+	export.ErrInvalidWebhookURL:            "INVALID_WEBHOOK_URL",
+	export.ErrInvalidDestination:           "INVALID_DESTINATION",
+	export.ErrInvalidStatus:                "INVALID_STATUS",
+	export.ErrUnknownConnector:             "UNKNOWN_CONNECTOR",
+	export.ErrStoragePathRequired:          "STORAGE_PATH_REQUIRED",
+	export.ErrTooManySchedules:             "TOO_MANY_SCHEDULES",
+
+	// Handles
+	handle.ErrRecipientNotFound: "RECIPIENT_NOT_FOUND",
+	handle.ErrHandleTaken:       "HANDLE_TAKEN",
+	handle.ErrInvalidHandle:     "INVALID_HANDLE",
+	handle.ErrReservedHandle:    "RESERVED_HANDLE",
+	handle.ErrNoHandle:          "NO_HANDLE",
+	handle.ErrInvalidIdentifier: "INVALID_IDENTIFIER",
+
+	// Treasury
+	treasury.ErrTransferNotFound:   "TREASURY_TRANSFER_NOT_FOUND",
+	treasury.ErrUnknownAccount:     "UNKNOWN_SYSTEM_ACCOUNT",
+	treasury.ErrSelfApproval:       "SELF_APPROVAL",
+	treasury.ErrTransferNotPending: "TREASURY_TRANSFER_NOT_PENDING",
+	treasury.ErrInsufficientFunds:  "INSUFFICIENT_SYSTEM_FUNDS",
+	treasury.ErrSameAccount:        "SAME_ACCOUNT",
+	treasury.ErrCustodialAccount:   "CUSTODIAL_ACCOUNT",
+	treasury.ErrInvalidAmount:      "INVALID_AMOUNT",
+	treasury.ErrReasonRequired:     "REASON_REQUIRED",
+	treasury.ErrInvalidPeriod:      "INVALID_PERIOD",
+	treasury.ErrInvalidStatus:      "INVALID_STATUS",
+
+	// User administration
+	useradmin.ErrUnknownRole:      "UNKNOWN_ROLE",
+	useradmin.ErrSuperAdminRole:   "SUPER_ADMIN_ROLE",
+	useradmin.ErrSelfAction:       "SELF_ADMIN_ACTION",
+	useradmin.ErrAlreadySuspended: "ALREADY_SUSPENDED",
+	useradmin.ErrNotSuspended:     "NOT_SUSPENDED",
+	useradmin.ErrReasonRequired:   "REASON_REQUIRED",
+	useradmin.ErrInvalidName:      "INVALID_NAME",
+	useradmin.ErrInvalidCountry:   "INVALID_COUNTRY",
+
+	// Roles and permissions
+	repositories.ErrRoleNotFound:      "ROLE_NOT_FOUND",
+	repositories.ErrUnknownPermission: "UNKNOWN_PERMISSION",
+	rbac.ErrInvalidRoleName:           "INVALID_ROLE_NAME",
+	rbac.ErrRoleExists:                "ROLE_EXISTS",
+	rbac.ErrSystemRole:                "SYSTEM_ROLE",
+	rbac.ErrRoleInUse:                 "ROLE_IN_USE",
+	rbac.ErrNoPermissions:             "NO_PERMISSIONS",
+	rbac.ErrCannotGrant:               "CANNOT_GRANT_PERMISSION",
+
+	// Enterprise organizations
+	enterprise.ErrOrganizationNotFound:  "ORGANIZATION_NOT_FOUND",
+	enterprise.ErrRoleForbidden:         "ORGANIZATION_ROLE_FORBIDDEN",
+	enterprise.ErrInvalidRole:           "INVALID_ORGANIZATION_ROLE",
+	enterprise.ErrNameRequired:          "NAME_REQUIRED",
+	enterprise.ErrRegistrationRequired:  "REGISTRATION_NO_REQUIRED",
+	enterprise.ErrRegistrationTaken:     "REGISTRATION_NO_TAKEN",
+	enterprise.ErrAlreadyCreated:        "ORGANIZATION_EXISTS",
+	enterprise.ErrInvalidBillingCycle:   "INVALID_BILLING_CYCLE",
+	enterprise.ErrInvalidLimit:          "INVALID_LIMIT",
+	enterprise.ErrMemberNotFound:        "MEMBER_NOT_FOUND",
+	enterprise.ErrMemberExists:          "MEMBER_EXISTS",
+	enterprise.ErrTooManyMembers:        "TOO_MANY_MEMBERS",
+	enterprise.ErrLastOwner:             "LAST_ORGANIZATION_OWNER",
+	enterprise.ErrOwnerRequired:         "OWNER_ROLE_REQUIRED",
+	enterprise.ErrWalletNotFound:        "ORGANIZATION_WALLET_NOT_FOUND",
+	enterprise.ErrWalletNameRequired:    "INVALID_WALLET_NAME",
+	enterprise.ErrWalletInactive:        "ORGANIZATION_WALLET_INACTIVE",
+	enterprise.ErrInvalidAmount:         "INVALID_AMOUNT",
+	enterprise.ErrInsufficientFunds:     "INSUFFICIENT_ORGANIZATION_FUNDS",
+	enterprise.ErrRecipientNotFound:     "RECIPIENT_NOT_FOUND",
+	enterprise.ErrTransactionLimit:      "MEMBER_LIMIT_EXCEEDED",
+	enterprise.ErrDailyLimit:            "MEMBER_DAILY_LIMIT",
+	enterprise.ErrMonthlyLimit:          "MEMBER_MONTHLY_LIMIT",
+	enterprise.ErrInvalidStatementMonth: "INVALID_STATEMENT_MONTH",
+	enterprise.ErrRuleNotFound:          "APPROVAL_RULE_NOT_FOUND",
+	enterprise.ErrRuleNameRequired:      "APPROVAL_RULE_NAME_REQUIRED",
+	enterprise.ErrInvalidApproverRole:   "INVALID_APPROVER_ROLE",
+	enterprise.ErrPaymentNotFound:       "ORGANIZATION_PAYMENT_NOT_FOUND",
+	enterprise.ErrPaymentNotPending:     "ORGANIZATION_PAYMENT_NOT_PENDING",
+	enterprise.ErrSelfReview:            "SELF_REVIEW",
+	enterprise.ErrNotRequester:          "NOT_PAYMENT_REQUESTER",
+
+	// Sandbox
+	repositories.ErrSandboxCustomerNotFound: "SANDBOX_CUSTOMER_NOT_FOUND",
+	repositories.ErrSandboxChargeNotFound:   "SANDBOX_CHARGE_NOT_FOUND",
+	repositories.ErrSandboxRefundExceeds:    "REFUND_EXCEEDS_CHARGE",
+	sandbox.ErrInvalidAmount:                "INVALID_AMOUNT",
+	sandbox.ErrNameRequired:                 "NAME_REQUIRED",
+	sandbox.ErrInvalidSource:                "INVALID_SANDBOX_SOURCE",
+	sandbox.ErrCustomerRequired:             "SANDBOX_CUSTOMER_REQUIRED",
+	sandbox.ErrCardNumberRequired:           "CARD_NUMBER_REQUIRED",
+	sandbox.ErrUnknownTestCard:              "UNKNOWN_TEST_CARD",
+	sandbox.ErrUnknownScenario:              "UNKNOWN_SANDBOX_SCENARIO",
+	sandbox.ErrChargeNotRefundable:          "SANDBOX_CHARGE_NOT_REFUNDABLE",
+	sandbox.ErrChargeNotPending:             "SANDBOX_CHARGE_NOT_PENDING",
+	sandbox.ErrInvalidOutcome:               "INVALID_SANDBOX_OUTCOME",
+
+	// Merchant webhooks
+	webhook.ErrNoWebhookURL:                 "NO_WEBHOOK_URL",
+	webhook.ErrUnknownEvent:                 "UNKNOWN_WEBHOOK_EVENT",
+	repositories.ErrWebhookDeliveryNotFound: "WEBHOOK_DELIVERY_NOT_FOUND",
+
+	// Feature flags
+	featureflag.ErrUnknownFlag:                  "UNKNOWN_FEATURE_FLAG",
+	repositories.ErrFeatureFlagOverrideNotFound: "FEATURE_FLAG_OVERRIDE_NOT_FOUND",
+
+	// Accounting exports
+	accounting.ErrUnknownFormat:          "UNKNOWN_JOURNAL_FORMAT",
+	accounting.ErrInvalidDate:            "INVALID_JOURNAL_DATE",
+	accounting.ErrDayNotOver:             "JOURNAL_DAY_NOT_OVER",
+	accounting.ErrDeliveryFailed:         "JOURNAL_DELIVERY_FAILED",
+	repositories.ErrAccountingExportBusy: "JOURNAL_EXPORT_BUSY",
+
+	// Compliance reports
+	compliance.ErrUnknownType:                "UNKNOWN_REPORT_TYPE",
+	compliance.ErrUnknownStatus:              "UNKNOWN_REPORT_STATUS",
+	compliance.ErrReferenceRequired:          "FILING_REFERENCE_REQUIRED",
+	compliance.ErrNoteRequired:               "REVIEW_NOTE_REQUIRED",
+	repositories.ErrComplianceReportNotFound: "COMPLIANCE_REPORT_NOT_FOUND",
+	repositories.ErrComplianceReportClosed:   "COMPLIANCE_REPORT_CLOSED",
+
+	// Tax summaries
+	taxsummary.ErrUnknownFormat:              "UNKNOWN_TAX_SUMMARY_FORMAT",
+	taxsummary.ErrInvalidYear:                "INVALID_TAX_YEAR",
+	taxsummary.ErrNotReady:                   "TAX_SUMMARY_NOT_READY",
+	taxsummary.ErrFailed:                     "TAX_SUMMARY_FAILED",
+	repositories.ErrTaxSummaryReportNotFound: "TAX_SUMMARY_NOT_FOUND",
+
+	// Pending transactions
+	pendingtx.ErrInvalidAge: "INVALID_PENDING_AGE",
+
+	// Merchant reserves
+	reserve.ErrMerchantNotFound: "MERCHANT_NOT_FOUND",
+	reserve.ErrInvalidPercent:   "INVALID_RESERVE_PERCENT",
+	reserve.ErrInvalidHoldDays:  "INVALID_RESERVE_HOLD_DAYS",
+
+	// Payment confirmations
+	confirmation.ErrInvalidChannel: "INVALID_CONFIRMATION_CHANNEL",
+
+	// Spending controls
+	spendcontrol.ErrInvalidScope:         "INVALID_SPENDING_SCOPE",
+	spendcontrol.ErrInvalidCategory:      "INVALID_SPENDING_CATEGORY",
+	spendcontrol.ErrInvalidAction:        "INVALID_SPENDING_ACTION",
+	spendcontrol.ErrInvalidCap:           "INVALID_SPENDING_CAP",
+	spendcontrol.ErrControlNotFound:      "SPENDING_CONTROL_NOT_FOUND",
+	spendcontrol.ErrManagedControl:       "SPENDING_CONTROL_MANAGED",
+	spendcontrol.ErrOrganizationNotFound: "ORGANIZATION_NOT_FOUND",
+	spendcontrol.ErrRoleForbidden:        "ORGANIZATION_ROLE_FORBIDDEN",
+	spendcontrol.ErrMemberNotFound:       "MEMBER_NOT_FOUND",
+	spendcontrol.ErrCategoryBlocked:      "MERCHANT_CATEGORY_BLOCKED",
+	spendcontrol.ErrCategoryCapExceeded:  "MERCHANT_CATEGORY_CAP_EXCEEDED",
+	spendcontrol.ErrForeignBlocked:       "FOREIGN_MERCHANT_BLOCKED",
+	spendcontrol.ErrForeignCapExceeded:   "FOREIGN_MERCHANT_CAP_EXCEEDED",
+
+	// Trusted device confirmation of large transfers
+	deviceconfirm.ErrTrustedDeviceRequired: "TRUSTED_DEVICE_REQUIRED",
+	deviceconfirm.ErrDeviceIDRequired:      "DEVICE_ID_REQUIRED",
+	deviceconfirm.ErrUnknownDevice:         "UNKNOWN_DEVICE",
+	deviceconfirm.ErrDeviceNotTrusted:      "DEVICE_NOT_TRUSTED",
+	deviceconfirm.ErrDeviceNotFound:        "TRUSTED_DEVICE_NOT_FOUND",
+	deviceconfirm.ErrConfirmationNotFound:  "TRANSFER_CONFIRMATION_NOT_FOUND",
+	deviceconfirm.ErrConfirmationClosed:    "TRANSFER_CONFIRMATION_CLOSED",
+	deviceconfirm.ErrConfirmationExpired:   "TRANSFER_CONFIRMATION_EXPIRED",
+	transaction.ErrNotAwaitingConfirmation: "TRANSFER_CONFIRMATION_CLOSED",
+
+	// Customer approval of merchant scans
+	scanconfirm.ErrConfirmationNotFound: "CHARGE_CONFIRMATION_NOT_FOUND",
+	scanconfirm.ErrConfirmationClosed:   "CHARGE_CONFIRMATION_CLOSED",
+	scanconfirm.ErrConfirmationExpired:  "CHARGE_CONFIRMATION_EXPIRED",
+
+	// Customer refund requests
+	refundrequest.ErrChargeNotFound:        "CHARGE_NOT_FOUND",
+	refundrequest.ErrRefundsNotAccepted:    "REFUND_REQUESTS_NOT_ACCEPTED",
+	refundrequest.ErrWindowClosed:          "REFUND_WINDOW_CLOSED",
+	refundrequest.ErrAlreadyRequested:      "REFUND_ALREADY_REQUESTED",
+	refundrequest.ErrNothingToRefund:       "REFUND_EXCEEDS_CHARGE",
+	refundrequest.ErrExceedsCharge:         "REFUND_EXCEEDS_CHARGE",
+	refundrequest.ErrRefundRequestNotFound: "REFUND_REQUEST_NOT_FOUND",
+	refundrequest.ErrRefundRequestClosed:   "REFUND_REQUEST_CLOSED",
+
+	// Payment PINs
+	paymentpin.ErrInvalidPIN:    "INVALID_PIN",
+	paymentpin.ErrPINNotSet:     "PAYMENT_PIN_NOT_SET",
+	paymentpin.ErrPINAlreadySet: "PAYMENT_PIN_ALREADY_SET",
+	paymentpin.ErrPINRequired:   "PAYMENT_PIN_REQUIRED",
+	paymentpin.ErrWrongPIN:      "WRONG_PAYMENT_PIN",
+	paymentpin.ErrPINLocked:     "PAYMENT_PIN_LOCKED",
+	paymentpin.ErrWrongPassword: "WRONG_PASSWORD",
+
+	// Sagas
+	saga.ErrNotAttention:         "SAGA_NOT_ATTENTION",
+	repositories.ErrSagaNotFound: "SAGA_NOT_FOUND",
+
+	// Maintenance
+	maintenance.ErrNotEnabled:        "MAINTENANCE_NOT_ENABLED",
+	maintenance.ErrInvalidRetryAfter: "INVALID_RETRY_AFTER",
+	maintenance.ErrEndsInPast:        "MAINTENANCE_ENDS_IN_PAST",
+
+	// Rate limits
+	ratelimit.ErrUnknownClass:                 "UNKNOWN_RATE_LIMIT_CLASS",
+	ratelimit.ErrInvalidSubject:               "INVALID_RATE_LIMIT_SUBJECT",
+	ratelimit.ErrInvalidLimit:                 "INVALID_RATE_LIMIT",
+	repositories.ErrRateLimitOverrideNotFound: "RATE_LIMIT_OVERRIDE_NOT_FOUND",
+
+	// Unavailable dependencies
+	resilience.ErrCircuitOpen: apperrors.CodeServiceUnavailable,
+	context.DeadlineExceeded:  apperrors.CodeTimeout,
+}
+
+// ErrorHandler renders every error a handler or middleware returns as the
+// error envelope; register it as the app's fiber.Config.ErrorHandler. Known
+// domain errors get their catalog code and status, errors given a status
+// with apperrors.WithStatus keep it, and anything else is logged and
+// reported as an internal error without its details.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	if code, ok := domainCode(err); ok {
+		def, _ := apperrors.Lookup(code)
+		return response.Fail(c, def.Status, code, err.Error())
+	}
+
+	var validationErr *apperrors.ValidationError
+	if errors.As(err, &validationErr) {
+		return response.Invalid(c, validationErr)
+	}
+
+	var statusErr *apperrors.StatusError
+	if errors.As(err, &statusErr) {
+		return response.Error(c, statusErr.Status, err.Error())
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return response.Error(c, fiberErr.Code, fiberErr.Message)
+	}
+
+	log.Printf("Unhandled error on %s %s: %v", c.Method(), c.Path(), err)
+	return response.Fail(c, fiber.StatusInternalServerError, apperrors.CodeInternal, "internal server error")
+}
+
+// domainCode finds the catalog code of the outermost known error in err's
+// chain
+func domainCode(err error) (string, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if code, ok := domainErrors[e]; ok {
+			return code, true
+		}
+		if domainErr, ok := e.(*apperrors.DomainError); ok {
+			if _, known := apperrors.Lookup(domainErr.Code); known {
+				return domainErr.Code, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"orus/internal/services/featureflag"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Feature hides the routes behind it while the feature flag is off,
+// answering as though they didn't exist
+func Feature(flags featureflag.Service, key string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !flags.Enabled(c.Context(), key) {
+			return fiber.ErrNotFound
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"orus/internal/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language picks the language each request is answered in from its
+// Accept-Language header. Services read it from the request context with
+// i18n.FromContext, so messages and documents they produce follow it.
+func Language() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(i18n.ContextKey, i18n.Match(c.Get(fiber.HeaderAcceptLanguage)))
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
+
+// RequestLanguage is the language the request is answered in
+func RequestLanguage(c *fiber.Ctx) string {
+	if lang, ok := c.Locals(i18n.ContextKey).(string); ok {
+		return lang
+	}
+	return i18n.Match(c.Get(fiber.HeaderAcceptLanguage))
+}
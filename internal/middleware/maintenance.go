@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	apperrors "orus/internal/errors"
+	"orus/internal/models"
+	"orus/internal/services/maintenance"
+	"orus/internal/utils/response"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maintenanceExempt lists writes that don't touch money and must keep
+// working during maintenance. The GraphQL gateway only has queries.
+var maintenanceExempt = []string{
+	"/api/logout",
+	"/api/graphql",
+}
+
+// Maintenance refuses writes with 503 and Retry-After while maintenance is
+// on, carrying the announcement as the error message. Reads pass, marked
+// with an X-Maintenance header, as do the admin console and the users
+// maintenance allows. It must run after authentication to see who those
+// users are.
+func Maintenance(svc maintenance.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		mode := svc.Current(c.Context())
+		if !mode.Enabled {
+			return c.Next()
+		}
+		c.Set("X-Maintenance", "true")
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+		path := c.Path()
+		if strings.HasPrefix(path, "/api/admin/") || slices.Contains(maintenanceExempt, path) {
+			return c.Next()
+		}
+		if claims, ok := c.Locals("claims").(*models.UserClaims); ok && claims != nil && mode.Allows(claims.UserID) {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(mode.RetryAfterAt(time.Now())))
+		return response.Fail(c, fiber.StatusServiceUnavailable, apperrors.CodeMaintenance, mode.Message)
+	}
+}
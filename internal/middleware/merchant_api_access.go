@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/apiaccess"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MerchantAPIAccess enforces the IP allowlist and mutual TLS requirement of
+// the merchant authenticated by MerchantAPIKey. The client certificate is
+// read from the TLS connection, or when TLS ends at a proxy, from the
+// certHeader it forwards the certificate's SHA-256 fingerprint in. The
+// header is only believed from a trusted proxy.
+func MerchantAPIAccess(access apiaccess.Service, certHeader string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		merchant, ok := c.Locals("merchant").(*models.Merchant)
+		if !ok {
+			return fiber.ErrUnauthorized
+		}
+		sandbox, _ := c.Locals("sandbox").(bool)
+
+		err := access.Check(c.Context(), merchant, apiaccess.Request{
+			IP:          c.IP(),
+			Method:      c.Method(),
+			Path:        c.Path(),
+			Sandbox:     sandbox,
+			Fingerprint: clientCertFingerprint(c, certHeader),
+		})
+		if err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// clientCertFingerprint returns the fingerprint of the client certificate.
+// IsProxyTrusted holds for every client when no proxies are configured, so
+// the header is ignored unless some are.
+func clientCertFingerprint(c *fiber.Ctx, certHeader string) string {
+	if state := c.Context().TLSConnectionState(); state != nil && len(state.PeerCertificates) > 0 {
+		return apiaccess.Fingerprint(state.PeerCertificates[0].Raw)
+	}
+	if certHeader != "" && len(c.App().Config().TrustedProxies) > 0 && c.IsProxyTrusted() {
+		return apiaccess.NormalizeFingerprint(c.Get(certHeader))
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"orus/internal/models"
+	"orus/internal/services/oauth"
+	"orus/internal/utils/response"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OAuthToken authenticates third-party app requests by the OAuth access
+// token in their Authorization header and adds the token to the request
+// context as "oauth". Routes check its scopes with OAuthScope.
+func OAuthToken(service oauth.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accessToken, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || accessToken == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="orus"`)
+			return response.Error(c, fiber.StatusUnauthorized, "missing access token")
+		}
+
+		token, err := service.Authenticate(c.Context(), accessToken)
+		if err != nil {
+			if errors.Is(err, oauth.ErrInvalidToken) {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="orus", error="invalid_token"`)
+				return response.Error(c, fiber.StatusUnauthorized, err.Error())
+			}
+			log.Printf("OAuth token lookup failed: %v", err)
+			return response.Error(c, fiber.StatusInternalServerError, "failed to authenticate access token")
+		}
+
+		c.Locals("oauth", token)
+		return c.Next()
+	}
+}
+
+// OAuthScope rejects OAuth requests whose token wasn't granted scope
+func OAuthScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("oauth").(*models.OAuthToken)
+		if !ok || token == nil {
+			return response.Error(c, fiber.StatusUnauthorized, "missing access token")
+		}
+		if !token.HasScope(scope) {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="orus", error="insufficient_scope", scope="`+scope+`"`)
+			return response.Error(c, fiber.StatusForbidden, oauth.ErrInsufficientScope.Error())
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"orus/internal/services/terminal"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TerminalAuth authenticates a paired point-of-sale terminal by its
+// X-Terminal-Key and X-Terminal-Secret headers and adds it to the request
+// context as "terminal".
+func TerminalAuth(terminals terminal.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		t, err := terminals.Authenticate(c.Context(), c.Get("X-Terminal-Key"), c.Get("X-Terminal-Secret"))
+		if err != nil {
+			return err
+		}
+
+		c.Locals("terminal", t)
+		return c.Next()
+	}
+}
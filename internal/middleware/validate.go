@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"orus/internal/utils/response"
+	"orus/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bodyKey is the local the validated request body is stored under
+const bodyKey = "body"
+
+// Validate parses the request body into a T and checks it against T's
+// validate tags. An invalid body is answered with every invalid field;
+// a valid one is handed to the handler through Body. Messages are in the
+// request's language.
+func Validate[T any]() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		body := new(T)
+		if err := c.BodyParser(body); err != nil {
+			return response.BadRequest(c, "Invalid request format")
+		}
+		if err := validation.Struct(body, RequestLanguage(c)); err != nil {
+			return err
+		}
+		c.Locals(bodyKey, body)
+		return c.Next()
+	}
+}
+
+// Body returns the request body Validate parsed and checked
+func Body[T any](c *fiber.Ctx) *T {
+	return c.Locals(bodyKey).(*T)
+}
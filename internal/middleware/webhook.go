@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookSecret authenticates inbound provider callbacks by a shared secret header
+func WebhookSecret(header, secret string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		provided := c.Get(header)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			return response.Error(c, fiber.StatusUnauthorized, "invalid webhook secret")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Accounting journal formats
+const (
+	AccountingFormatCSV        = "csv"
+	AccountingFormatOFX        = "ofx"
+	AccountingFormatQuickBooks = "quickbooks"
+	AccountingFormatXero       = "xero"
+)
+
+// Accounting export statuses
+const (
+	AccountingExportRunning   = "running"
+	AccountingExportCompleted = "completed"
+	AccountingExportFailed    = "failed"
+)
+
+// Accounts the journals post to besides the system accounts. Customer
+// funds is what the platform owes wallet holders; funding clearing is
+// money in transit with the banks and card processors.
+const (
+	AccountingAccountCustomerFunds   = "customer_funds"
+	AccountingAccountFundingClearing = "funding_clearing"
+)
+
+// AccountingExport records one day's journal in one format: that it was
+// generated, its totals and where it was delivered
+type AccountingExport struct {
+	ID       uint      `gorm:"primarykey" json:"id"`
+	Date     time.Time `gorm:"type:date;not null;uniqueIndex:idx_accounting_exports_day" json:"date"`
+	Format   string    `gorm:"size:20;not null;uniqueIndex:idx_accounting_exports_day" json:"format"`
+	Status   string    `gorm:"size:20;not null;index" json:"status"`
+	Attempts int       `gorm:"not null;default:0" json:"attempts"`
+	Entries  int       `gorm:"not null;default:0" json:"entries"`
+	Debits   float64   `gorm:"type:decimal(20,2);not null;default:0" json:"debits"`
+	Credits  float64   `gorm:"type:decimal(20,2);not null;default:0" json:"credits"`
+	FileName string    `json:"file_name"`
+	// Location is where the journal was delivered; empty when it's only
+	// kept for download
+	Location string `json:"location,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// RequestedBy is the admin who re-exported the day; nil for the job
+	RequestedBy *uint      `json:"requested_by,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ArchivedTransaction is a settled transaction moved out of the hot
+// transactions table once it passed the retention age. It keeps the same
+// ID and columns so archived and hot rows can be read together.
+//
+// Rows only ever arrive through the archiver's SQL. Never create them with
+// GORM: the embedded Transaction's hooks would validate metadata and book
+// fees a second time.
+type ArchivedTransaction struct {
+	Transaction
+	ArchivedAt time.Time `gorm:"not null;index"`
+}
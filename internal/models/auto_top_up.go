@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Auto top-up run statuses
+const (
+	AutoTopUpPending        = "pending" // Claimed, card not charged yet
+	AutoTopUpSucceeded      = "succeeded"
+	AutoTopUpRequiresAction = "requires_action" // The card asked for 3-D Secure
+	AutoTopUpFailed         = "failed"
+)
+
+// AutoTopUpRule tops a user's wallet up from a card when a payment takes
+// their spendable balance below Threshold. Caps of 0 mean no cap.
+type AutoTopUpRule struct {
+	ID         uint    `gorm:"primarykey" json:"id"`
+	UserID     uint    `gorm:"not null;uniqueIndex" json:"user_id"`
+	CardID     uint    `gorm:"default:0" json:"card_id,omitempty"` // 0 charges the default card
+	Threshold  float64 `gorm:"not null" json:"threshold"`
+	Amount     float64 `gorm:"not null" json:"amount"`
+	DailyCap   float64 `gorm:"default:0" json:"daily_cap"`
+	MonthlyCap float64 `gorm:"default:0" json:"monthly_cap"`
+	Enabled    bool    `gorm:"default:true;index" json:"enabled"`
+	// CheckedAfterID is the last of the user's transactions evaluated
+	CheckedAfterID uint `gorm:"default:0" json:"-"`
+	// ConsecutiveFailures counts failed charges since the last success;
+	// the rule turns itself off after too many
+	ConsecutiveFailures int       `gorm:"default:0" json:"consecutive_failures"`
+	LastFailure         string    `json:"last_failure,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// AutoTopUpRun is one auto top-up a payment set off. A payment sets off
+// at most one, whichever instance evaluates it.
+type AutoTopUpRun struct {
+	ID     uint `gorm:"primarykey" json:"id"`
+	RuleID uint `gorm:"not null;uniqueIndex:idx_auto_top_up_trigger" json:"rule_id"`
+	UserID uint `gorm:"not null;index" json:"user_id"`
+	// TriggerTransactionID is the payment that took the balance below the threshold
+	TriggerTransactionID uint      `gorm:"not null;uniqueIndex:idx_auto_top_up_trigger" json:"trigger_transaction_id"`
+	Balance              float64   `json:"balance"` // Spendable balance when evaluated
+	Amount               float64   `gorm:"not null" json:"amount"`
+	CardID               uint      `json:"card_id"`
+	Status               string    `gorm:"size:20;not null" json:"status"`
+	CardTopUpID          *uint     `json:"card_top_up_id,omitempty"`
+	FailureReason        string    `json:"failure_reason,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Bank account statuses
+const (
+	BankAccountStatusPendingVerification = "pending_verification"
+	BankAccountStatusVerified            = "verified"
+	BankAccountStatusFailed              = "verification_failed"
+	BankAccountStatusRemoved             = "removed"
+)
+
+// Bank account verification methods
+const (
+	VerificationMethodInstant       = "instant"
+	VerificationMethodMicroDeposits = "micro_deposits"
+)
+
+// BankAccount is an external bank account linked as a wallet funding source
+type BankAccount struct {
+	ID                   uint       `gorm:"primarykey" json:"id"`
+	UserID               uint       `gorm:"not null;index" json:"user_id"`
+	Provider             string     `gorm:"not null" json:"provider"`
+	ProviderAccountID    string     `gorm:"not null;index" json:"-"`
+	InstitutionName      string     `json:"institution_name"`
+	AccountMask          string     `gorm:"type:varchar(4)" json:"account_mask"`
+	AccountType          string     `json:"account_type"`
+	HolderName           string     `json:"holder_name"`
+	Currency             string     `gorm:"default:'USD'" json:"currency"`
+	Status               string     `gorm:"not null;default:'pending_verification'" json:"status"`
+	VerificationMethod   string     `json:"verification_method"`
+	VerificationAttempts int        `gorm:"default:0" json:"verification_attempts"`
+	IsDefault            bool       `gorm:"default:false" json:"is_default"`
+	VerifiedAt           *time.Time `json:"verified_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Bulk operation actions
+const (
+	BulkActionLockWallets   = "lock_wallets"
+	BulkActionSuspendUsers  = "suspend_users"
+	BulkActionExpireQRCodes = "expire_qr_codes"
+)
+
+// Bulk operation statuses
+const (
+	BulkStatusPending   = "pending" // Waiting for the job to pick it up
+	BulkStatusRunning   = "running"
+	BulkStatusCompleted = "completed"
+)
+
+// Bulk operation item statuses
+const (
+	BulkItemPending   = "pending"
+	BulkItemSucceeded = "succeeded"
+	BulkItemFailed    = "failed"
+	BulkItemSkipped   = "skipped" // Already in the state the action puts it in
+)
+
+// BulkOperation applies one admin action to many users or QR codes in the
+// background. Targets are fixed when it is created, one item each.
+type BulkOperation struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Action      string `gorm:"size:30;not null" json:"action"`
+	Status      string `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	RequestedBy uint   `gorm:"not null;index" json:"requested_by"`
+	Reason      string `gorm:"not null" json:"reason"`
+	Source      string `gorm:"size:10;not null" json:"source"` // csv or filter
+	// Params holds the action's settings, such as a wallet lock's scope,
+	// and Filter the query targets were picked with
+	Params    JSON `gorm:"type:jsonb" json:"params,omitempty"`
+	Filter    JSON `gorm:"type:jsonb" json:"filter,omitempty"`
+	Total     int  `gorm:"not null" json:"total"`
+	Processed int  `gorm:"default:0" json:"processed"`
+	Succeeded int  `gorm:"default:0" json:"succeeded"`
+	Failed    int  `gorm:"default:0" json:"failed"`
+	Skipped   int  `gorm:"default:0" json:"skipped"`
+	// LeaseUntil is when a running operation's instance is presumed gone
+	// and another may pick it up
+	LeaseUntil  *time.Time `json:"-"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BulkOperationItem is one target of a bulk operation and what happened to it
+type BulkOperationItem struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	OperationID uint       `gorm:"not null;uniqueIndex:idx_bulk_operation_target" json:"operation_id"`
+	TargetID    uint       `gorm:"not null;uniqueIndex:idx_bulk_operation_target" json:"target_id"`
+	Status      string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Error       string     `json:"error,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Card top-up statuses
+const (
+	CardTopUpRequiresAction = "requires_action" // Waiting on the cardholder's 3-D Secure challenge
+	CardTopUpSucceeded      = "succeeded"
+	CardTopUpFailed         = "failed"
+)
+
+// Reasons a card top-up is stepped up to 3-D Secure
+const (
+	StepUpAmount   = "amount"   // Above the configured threshold
+	StepUpNewCard  = "new_card" // Card verified only recently
+	StepUpVelocity = "velocity" // Many card top-ups in a short time
+)
+
+// CardTopUp is a top-up charged to a linked card. One needing 3-D Secure
+// is left pending until the processor's webhook reports the outcome, and
+// only then is the wallet credited.
+type CardTopUp struct {
+	ID            uint    `gorm:"primarykey" json:"id"`
+	UserID        uint    `gorm:"not null;index" json:"user_id"`
+	CardID        uint    `gorm:"not null" json:"card_id"`
+	TransactionID uint    `gorm:"not null" json:"transaction_id"` // The top_up transaction
+	ChargeID      string  `gorm:"not null;uniqueIndex" json:"charge_id"`
+	Amount        float64 `gorm:"not null" json:"amount"`
+	Status        string  `gorm:"size:20;not null;index" json:"status"`
+	StepUpReason  string  `gorm:"size:20" json:"step_up_reason,omitempty"`
+	// ClientSecret and NextActionURL let the client run the challenge; both
+	// are cleared once the top-up completes
+	ClientSecret  string     `json:"client_secret,omitempty"`
+	NextActionURL string     `json:"next_action_url,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Card vault access actions
+const (
+	VaultActionStore      = "store"
+	VaultActionDetokenize = "detokenize"
+	VaultActionDelete     = "delete"
+	VaultActionImport     = "import"
+)
+
+// VaultedCard is a card's processor token, encrypted at rest. The rest of
+// the codebase only sees the opaque Reference.
+type VaultedCard struct {
+	ID         uint   `gorm:"primarykey"`
+	Reference  string `gorm:"size:40;uniqueIndex;not null"`
+	UserID     uint   `gorm:"not null;index"`
+	Ciphertext string `gorm:"not null"`
+	Brand      string
+	LastFour   string `gorm:"size:4"`
+	CreatedAt  time.Time
+}
+
+// VaultAccess logs each time the vault stores, reveals or deletes a token
+type VaultAccess struct {
+	ID        uint   `gorm:"primarykey"`
+	Reference string `gorm:"size:40;not null;index"`
+	UserID    uint   `gorm:"not null"`
+	Action    string `gorm:"size:20;not null"`
+	Purpose   string `gorm:"size:50;not null"`
+	CreatedAt time.Time
+}
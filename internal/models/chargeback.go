@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Chargeback statuses. A chargeback opens with the disputed amount reserved
+// on the merchant's wallet, optionally moves to represented once the
+// merchant contests it, and is settled as won (reserve released) or lost
+// (reserve captured and paid to the customer).
+const (
+	ChargebackStatusOpen        = "open"
+	ChargebackStatusRepresented = "represented"
+	ChargebackStatusWon         = "won"
+	ChargebackStatusLost        = "lost"
+)
+
+// Chargeback is a forced reversal of a disputed payment. The fee is charged
+// when it opens and is kept whatever the outcome.
+type Chargeback struct {
+	ID                  uint       `gorm:"primarykey" json:"id"`
+	DisputeID           uint       `gorm:"not null;uniqueIndex" json:"dispute_id"`
+	TransactionID       uint       `gorm:"not null;index" json:"transaction_id"`
+	MerchantUserID      uint       `gorm:"not null;index" json:"merchant_user_id"`
+	CustomerID          uint       `gorm:"not null;index" json:"customer_id"`
+	Amount              float64    `gorm:"not null" json:"amount"`
+	Fee                 float64    `gorm:"not null;default:0" json:"fee"`
+	Currency            string     `gorm:"default:'USD'" json:"currency"`
+	ReasonCode          string     `gorm:"size:20" json:"reason_code,omitempty"`
+	Status              string     `gorm:"size:20;not null;default:'open';index" json:"status"`
+	ReserveHoldID       *uint      `json:"reserve_hold_id,omitempty"`
+	FeeTransactionID    *uint      `json:"fee_transaction_id,omitempty"`
+	SettleTransactionID *uint      `json:"settle_transaction_id,omitempty"`
+	RepresentmentDueAt  time.Time  `gorm:"index" json:"representment_due_at"`
+	RepresentmentNote   string     `json:"representment_note,omitempty"`
+	RepresentedAt       *time.Time `json:"represented_at,omitempty"`
+	OpenedBy            uint       `gorm:"not null" json:"opened_by"`
+	ResolutionNote      string     `json:"resolution_note,omitempty"`
+	ResolvedBy          *uint      `json:"resolved_by,omitempty"` // Nil when lost by missing the deadline
+	ResolvedAt          *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// IsActive reports whether the chargeback still holds a reserve
+func (c *Chargeback) IsActive() bool {
+	return c.Status == ChargebackStatusOpen || c.Status == ChargebackStatusRepresented
+}
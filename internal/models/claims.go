@@ -17,6 +17,9 @@ type UserClaims struct {
 	TokenType    string   `json:"token_type"`
 	Permissions  []string `json:"permissions"`
 	TokenVersion int      `json:"token_version"`
+	// SessionID names the sign-in the token belongs to; tokens issued
+	// before sessions have none
+	SessionID string `json:"sid,omitempty"`
 }
 
 // HasPermission checks if the user has a specific permission
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Compliance report types
+const (
+	ComplianceReportCTR = "ctr" // Large transaction report
+	ComplianceReportSAR = "sar" // Suspicious activity report
+)
+
+// Why a compliance report was drafted
+const (
+	ComplianceReasonLargeTransaction = "large_transaction"
+	ComplianceReasonStructuring      = "structuring"
+)
+
+// Compliance report statuses. Drafts are reviewed by a compliance officer,
+// then filed with the regulator or dismissed; both are final.
+const (
+	ComplianceReportDraft     = "draft"
+	ComplianceReportInReview  = "in_review"
+	ComplianceReportFiled     = "filed"
+	ComplianceReportDismissed = "dismissed"
+)
+
+// ComplianceReport is a regulatory report drafted from flagged
+// transactions. The subject's details are copied when it's drafted, so
+// the report shows them as they were.
+type ComplianceReport struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	Type             string    `gorm:"size:10;not null;index" json:"type"`
+	Reason           string    `gorm:"size:30;not null" json:"reason"`
+	Status           string    `gorm:"size:20;not null;default:'draft';index" json:"status"`
+	SubjectUserID    uint      `gorm:"not null;index" json:"subject_user_id"`
+	SubjectName      string    `json:"subject_name"`
+	SubjectEmail     string    `json:"subject_email"`
+	SubjectPhone     string    `json:"subject_phone"`
+	SubjectCountry   string    `gorm:"size:2" json:"subject_country,omitempty"`
+	SubjectKYCStatus string    `gorm:"size:20" json:"subject_kyc_status"`
+	TotalAmount      float64   `gorm:"type:decimal(20,2);not null" json:"total_amount"`
+	Currency         string    `gorm:"size:3;not null" json:"currency"`
+	TransactionCount int       `gorm:"not null" json:"transaction_count"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	// Narrative is drafted from what was flagged; officers rewrite it
+	// before filing
+	Narrative       string     `gorm:"type:text" json:"narrative"`
+	AssignedTo      *uint      `gorm:"index" json:"assigned_to,omitempty"`
+	FilingReference string     `json:"filing_reference,omitempty"` // The regulator's reference once filed
+	ReviewNote      string     `json:"review_note,omitempty"`
+	ReviewedBy      *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	Transactions []ComplianceReportTransaction `gorm:"foreignKey:ReportID" json:"transactions,omitempty"`
+}
+
+// IsFinal reports whether the report was filed or dismissed
+func (r *ComplianceReport) IsFinal() bool {
+	return r.Status == ComplianceReportFiled || r.Status == ComplianceReportDismissed
+}
+
+// ComplianceReportTransaction is a transaction a report covers. A
+// transaction is in at most one report of each type, so monitoring never
+// reports it twice.
+type ComplianceReportTransaction struct {
+	ID            uint      `gorm:"primarykey" json:"-"`
+	ReportID      uint      `gorm:"not null;index" json:"-"`
+	ReportType    string    `gorm:"size:10;not null;uniqueIndex:idx_compliance_report_transaction" json:"-"`
+	TransactionID uint      `gorm:"not null;uniqueIndex:idx_compliance_report_transaction" json:"transaction_id"`
+	Type          string    `json:"type"`
+	Amount        float64   `gorm:"type:decimal(20,2);not null" json:"amount"`
+	Currency      string    `gorm:"size:3" json:"currency"`
+	SenderID      uint      `json:"sender_id"`
+	ReceiverID    uint      `json:"receiver_id"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
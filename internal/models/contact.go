@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Ways a contact can be added
+const (
+	ContactViaPhone  = "phone"
+	ContactViaEmail  = "email"
+	ContactViaQRCode = "qr_code"
+	ContactViaUserID = "user_id"
+)
+
+// Contact is a saved P2P recipient in a user's beneficiary directory
+type Contact struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	UserID        uint       `gorm:"not null;uniqueIndex:idx_contact_user_pair" json:"-"`
+	ContactUserID uint       `gorm:"not null;uniqueIndex:idx_contact_user_pair;index" json:"contact_user_id"`
+	Name          string     `gorm:"not null" json:"name"` // The recipient's name when they were added
+	Nickname      string     `json:"nickname,omitempty"`
+	AddedVia      string     `gorm:"size:10;not null" json:"added_via"`
+	Favorite      bool       `gorm:"default:false;index" json:"favorite"`
+	PaymentCount  int        `gorm:"default:0" json:"payment_count"`
+	LastPaidAt    *time.Time `json:"last_paid_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -1,32 +1,58 @@
 package models
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // CreditCard represents a stored credit card
 type CreditCard struct {
-	ID          uint   `gorm:"primarykey"`
-	UserID      uint   `gorm:"not null;index"`
-	CardNumber  string `gorm:"not null"`
+	ID     uint `gorm:"primarykey"`
+	UserID uint `gorm:"not null;index"`
+	// VaultRef points at the card's token in the card vault
+	VaultRef    string `gorm:"default:''" json:"-"`
 	CardType    string `gorm:"not null"`
 	ExpiryMonth string `gorm:"not null"`
 	ExpiryYear  string `gorm:"not null"`
 	LastFour    string `gorm:"not null"`
 	IsDefault   bool   `gorm:"default:false"`
 	Status      string `gorm:"default:'active'"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+
+	// Billing details, as the card's issuer has them
+	BillingName       string `gorm:"default:''"`
+	BillingAddress    string `gorm:"default:''"`
+	BillingPostalCode string `gorm:"default:''"`
+	BillingCountry    string `gorm:"size:2;default:''"`
+
+	// Ownership is verified with a small charge the user reads off their
+	// statement; the charge is refunded either way
+	VerifiedAt           *time.Time
+	VerificationAttempts int     `gorm:"default:0"`
+	VerificationChargeID string  `gorm:"default:''" json:"-"`
+	VerificationAmount   float64 `gorm:"default:0" json:"-"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
-// VisaCardToken represents the card tokenization result
-type VisaCardToken struct {
-	Token    string `json:"token"`
-	Expiry   string `json:"expiry"`
-	CardType string `json:"card_type"`
+// CreditCardStatusVerificationFailed marks a card whose verification
+// attempts ran out; it can't be verified again and must be relinked
+const CreditCardStatusVerificationFailed = "verification_failed"
+
+// IsVerified reports whether the user has proved they own the card
+func (c *CreditCard) IsVerified() bool {
+	return c.VerifiedAt != nil
 }
 
-// CreateCardInput represents the input for creating a new card
-type CreateCardInput struct {
-	CardNumber  string `json:"card_number"`
-	ExpiryMonth string `json:"expiry_month"`
-	ExpiryYear  string `json:"expiry_year"`
+// IsExpired reports whether the card's expiry month has passed at now
+func (c *CreditCard) IsExpired(now time.Time) bool {
+	month, err := strconv.Atoi(c.ExpiryMonth)
+	if err != nil {
+		return true
+	}
+	year, err := strconv.Atoi(c.ExpiryYear)
+	if err != nil {
+		return true
+	}
+	return year < now.Year() || (year == now.Year() && month < int(now.Month()))
 }
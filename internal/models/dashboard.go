@@ -9,6 +9,8 @@ type DashboardStats struct {
 	AverageTransactionAmount float64    `json:"average_transaction_amount"`
 	LastTransactionDate      *time.Time `json:"last_transaction_date"`
 	CurrentBalance           float64    `json:"current_balance"`
+	AvailableBalance         float64    `json:"available_balance"`
+	HeldBalance              float64    `json:"held_balance"`
 	PendingTransactions      int        `json:"pending_transactions"`
 }
 
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Daily breakdown scopes. Each splits a user's or merchant's day by a
+// different key.
+const (
+	BreakdownUserSpending   = "user_spending"   // Money sent, by transaction category
+	BreakdownUserIncome     = "user_income"     // Money received, by income source
+	BreakdownUserType       = "user_type"       // All activity, by transaction type
+	BreakdownMerchantMethod = "merchant_method" // Payments received, by payment method
+)
+
+// UserDailyStats is the projected activity of one user on one UTC day
+type UserDailyStats struct {
+	ID                uint      `gorm:"primarykey" json:"-"`
+	UserID            uint      `gorm:"not null;uniqueIndex:idx_user_daily_stats" json:"user_id"`
+	Day               time.Time `gorm:"type:date;not null;uniqueIndex:idx_user_daily_stats" json:"day"`
+	SentCount         int64     `gorm:"not null;default:0" json:"sent_count"`
+	SentVolume        float64   `gorm:"not null;default:0" json:"sent_volume"`
+	ReceivedCount     int64     `gorm:"not null;default:0" json:"received_count"`
+	ReceivedVolume    float64   `gorm:"not null;default:0" json:"received_volume"`
+	LastTransactionAt time.Time `json:"last_transaction_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// MerchantDailyStats is the projected payment volume a merchant received on
+// one UTC day
+type MerchantDailyStats struct {
+	ID         uint      `gorm:"primarykey" json:"-"`
+	MerchantID uint      `gorm:"not null;uniqueIndex:idx_merchant_daily_stats" json:"merchant_id"` // Merchant's user ID
+	Day        time.Time `gorm:"type:date;not null;uniqueIndex:idx_merchant_daily_stats" json:"day"`
+	Count      int64     `gorm:"not null;default:0" json:"count"`
+	Volume     float64   `gorm:"not null;default:0" json:"volume"`
+	Fees       float64   `gorm:"not null;default:0" json:"fees"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DailyBreakdown splits a user's or merchant's day by category, type or
+// payment method, depending on its scope
+type DailyBreakdown struct {
+	ID      uint      `gorm:"primarykey" json:"-"`
+	Scope   string    `gorm:"size:20;not null;uniqueIndex:idx_daily_breakdown" json:"scope"`
+	OwnerID uint      `gorm:"not null;uniqueIndex:idx_daily_breakdown" json:"owner_id"` // User ID, or the merchant's user ID
+	Day     time.Time `gorm:"type:date;not null;uniqueIndex:idx_daily_breakdown" json:"day"`
+	Key     string    `gorm:"size:50;not null;uniqueIndex:idx_daily_breakdown" json:"key"`
+	Count   int64     `gorm:"not null;default:0" json:"count"`
+	Volume  float64   `gorm:"not null;default:0" json:"volume"`
+}
+
+// ProjectedTransaction marks a transaction as counted in the dashboard
+// projections so replays never count it twice
+type ProjectedTransaction struct {
+	TransactionID uint      `gorm:"primarykey"`
+	ProjectedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// ProjectionCursor records how far a projection has read the transaction
+// stream, as the update time of the last transaction it saw
+type ProjectionCursor struct {
+	Name      string    `gorm:"primarykey;size:50"`
+	Position  time.Time `gorm:"not null"`
+	UpdatedAt time.Time
+}
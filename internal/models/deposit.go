@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Deposit lifecycle statuses
+const (
+	DepositStatusInitiated = "initiated"
+	DepositStatusPending   = "pending"
+	DepositStatusSettled   = "settled"
+	DepositStatusReturned  = "returned"
+	DepositStatusFailed    = "failed"
+)
+
+// Deposit tracks an ACH top-up from initiation until it settles or the bank
+// returns it. Returns can arrive after settlement, once the funds were spent.
+type Deposit struct {
+	ID                    uint       `gorm:"primarykey" json:"id"`
+	UserID                uint       `gorm:"not null;index" json:"user_id"`
+	BankAccountID         uint       `gorm:"not null;index" json:"bank_account_id"`
+	TransactionID         uint       `gorm:"not null" json:"transaction_id"` // The top_up transaction
+	ProviderTransferID    string     `gorm:"not null;uniqueIndex" json:"provider_transfer_id"`
+	Amount                float64    `gorm:"not null" json:"amount"`
+	Currency              string     `gorm:"default:'USD'" json:"currency"`
+	Status                string     `gorm:"not null;default:'initiated';index" json:"status"`
+	Credited              bool       `gorm:"default:false" json:"credited"` // Wallet has been credited
+	ReturnCode            string     `json:"return_code,omitempty"`
+	ReturnReason          string     `json:"return_reason,omitempty"`
+	ReversalTransactionID *uint      `json:"reversal_transaction_id,omitempty"`
+	FeeTransactionID      *uint      `json:"fee_transaction_id,omitempty"`
+	SettledAt             *time.Time `json:"settled_at,omitempty"`
+	ReturnedAt            *time.Time `json:"returned_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// DepositEvent records each provider status update applied to a deposit
+type DepositEvent struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	DepositID  uint      `gorm:"not null;index" json:"deposit_id"`
+	EventID    string    `gorm:"not null;uniqueIndex" json:"event_id"` // Provider's event ID, for idempotency
+	Status     string    `gorm:"not null" json:"status"`
+	ReturnCode string    `json:"return_code,omitempty"`
+	Applied    bool      `json:"applied"` // False when the event was stale or out of order
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -6,14 +6,102 @@ import (
 	"gorm.io/gorm"
 )
 
+// Where a dispute came from. Escrow disputes are settled through the escrow
+// service, never by refunding the underlying transaction.
+const (
+	DisputeSourcePayment = "payment"
+	DisputeSourceEscrow  = "escrow"
+)
+
+// Dispute statuses. A dispute moves open -> evidence_requested ->
+// under_review -> resolved; admins may skip straight to under_review.
+// Disputes filed before the workflow existed start out as pending, which is
+// treated the same as open.
+const (
+	DisputeStatusPending           = "pending"
+	DisputeStatusOpen              = "open"
+	DisputeStatusEvidenceRequested = "evidence_requested"
+	DisputeStatusUnderReview       = "under_review"
+	DisputeStatusResolved          = "resolved"
+	DisputeStatusChargedBack       = "charged_back"
+)
+
+// Dispute resolutions
+const (
+	DisputeResolutionCustomer = "customer" // Decided for the customer, with a full or partial refund
+	DisputeResolutionMerchant = "merchant" // Decided for the merchant, nothing refunded
+)
+
+// Dispute categories, picked by the customer when filing. Disputes filed
+// before categories existed are other.
+const (
+	DisputeCategoryUnauthorized = "unauthorized" // The customer didn't make the payment
+	DisputeCategoryNotReceived  = "not_received" // Paid for goods or services never received
+	DisputeCategoryDuplicate    = "duplicate"    // Charged more than once for the same purchase
+	DisputeCategoryOther        = "other"
+)
+
+// DisputeCategories lists the categories a dispute can be filed under
+var DisputeCategories = []string{
+	DisputeCategoryUnauthorized,
+	DisputeCategoryNotReceived,
+	DisputeCategoryDuplicate,
+	DisputeCategoryOther,
+}
+
 type Dispute struct {
 	gorm.Model
-	TransactionID uint   `gorm:"not null"`
-	MerchantID    uint   `gorm:"not null"`
-	UserID        uint   `gorm:"not null"`
-	Reason        string `gorm:"not null"`
-	Status        string `gorm:"default:'pending'"`
-	Refunded      bool   `gorm:"default:false"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	TransactionID  uint   `gorm:"not null"`
+	MerchantID     uint   `gorm:"not null"`
+	MerchantUserID uint   `gorm:"index"` // The counterparty who answers the dispute
+	UserID         uint   `gorm:"not null;index"`
+	Reason         string `gorm:"not null"`
+	Category       string `gorm:"size:30;default:'other'"`
+	// DisputedAmount is the part of the transaction the customer disputes;
+	// zero on disputes filed before partial disputes, meaning all of it
+	DisputedAmount float64 `gorm:"type:decimal(20,2);default:0"`
+	// Attachments reference what backs the dispute up, such as order
+	// numbers or links to files kept elsewhere
+	Attachments      []string `gorm:"type:jsonb;serializer:json"`
+	Status           string   `gorm:"default:'open';index"`
+	Refunded         bool     `gorm:"default:false"`
+	RefundAmount     float64
+	RefundTxID       *uint
+	Source           string `gorm:"size:20;default:'payment'"`
+	ResponseDueAt    *time.Time
+	MerchantResponse string
+	RespondedAt      *time.Time
+	Resolution       string `gorm:"size:20"`
+	ResolutionNote   string
+	ResolvedBy       *uint
+	ResolvedAt       *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// IsOpen reports whether the dispute is still waiting on a decision
+func (d *Dispute) IsOpen() bool {
+	switch d.Status {
+	case DisputeStatusPending, DisputeStatusOpen, DisputeStatusEvidenceRequested, DisputeStatusUnderReview:
+		return true
+	}
+	return false
+}
+
+// AmountInDispute is how much of a transaction of transactionAmount the
+// dispute is about
+func (d *Dispute) AmountInDispute(transactionAmount float64) float64 {
+	if d.DisputedAmount > 0 {
+		return d.DisputedAmount
+	}
+	return transactionAmount
+}
+
+// CounterpartyID is the user who answers the dispute. Older disputes only
+// recorded MerchantID.
+func (d *Dispute) CounterpartyID() uint {
+	if d.MerchantUserID != 0 {
+		return d.MerchantUserID
+	}
+	return d.MerchantID
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Which side of a dispute submitted evidence
+const (
+	EvidencePartyCustomer = "customer"
+	EvidencePartyMerchant = "merchant"
+	EvidencePartyAdmin    = "admin"
+)
+
+// DisputeEvidence is a file attached to a dispute. The content lives in
+// file storage under StorageKey.
+type DisputeEvidence struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	DisputeID    uint      `gorm:"not null;index" json:"dispute_id"`
+	ChargebackID *uint     `gorm:"index" json:"chargeback_id,omitempty"` // Set on chargeback representment evidence
+	UploadedBy   uint      `gorm:"not null" json:"uploaded_by"`
+	Party        string    `gorm:"size:10;not null" json:"party"`
+	FileName     string    `gorm:"not null" json:"file_name"`
+	ContentType  string    `gorm:"not null" json:"content_type"`
+	Size         int64     `gorm:"not null" json:"size"`
+	StorageKey   string    `gorm:"not null" json:"-"`
+	Description  string    `json:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
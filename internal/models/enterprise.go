@@ -79,3 +79,134 @@ type EnterpriseAPIKey struct {
 	LastUsed     time.Time
 	Status       string
 }
+
+// Enterprise member roles
+const (
+	EnterpriseRoleOwner   = "owner"   // Created the organization; manages everything
+	EnterpriseRoleAdmin   = "admin"   // Manages members, wallets and spending policies
+	EnterpriseRoleFinance = "finance" // Funds wallets, pays and reads statements
+	EnterpriseRoleMember  = "member"  // Pays from the organization's wallets within their policy
+)
+
+// IsValidEnterpriseRole reports whether role is an organization member role
+func IsValidEnterpriseRole(role string) bool {
+	switch role {
+	case EnterpriseRoleOwner, EnterpriseRoleAdmin, EnterpriseRoleFinance, EnterpriseRoleMember:
+		return true
+	}
+	return false
+}
+
+// Enterprise payment statuses
+const (
+	EnterprisePaymentPendingApproval = "pending_approval"
+	EnterprisePaymentCompleted       = "completed"
+	EnterprisePaymentRejected        = "rejected"
+	EnterprisePaymentCancelled       = "cancelled"
+)
+
+// Enterprise payment audit actions
+const (
+	EnterprisePaymentEventApprovalRequired = "approval_required"
+	EnterprisePaymentEventApproved         = "approved"
+	EnterprisePaymentEventRejected         = "rejected"
+	EnterprisePaymentEventCancelled        = "cancelled"
+	EnterprisePaymentEventExecuted         = "executed"
+)
+
+// EnterpriseMember grants a user a role in an organization. The limits are
+// the member's spending policy across all of its wallets; 0 means no limit.
+type EnterpriseMember struct {
+	ID                  uint      `gorm:"primarykey" json:"id"`
+	EnterpriseID        uint      `gorm:"not null;uniqueIndex:idx_enterprise_member" json:"enterprise_id"`
+	UserID              uint      `gorm:"not null;uniqueIndex:idx_enterprise_member;index" json:"user_id"`
+	Role                string    `gorm:"size:20;not null" json:"role"`
+	Title               string    `json:"title,omitempty"`
+	PerTransactionLimit float64   `gorm:"default:0" json:"per_transaction_limit"`
+	DailyLimit          float64   `gorm:"default:0" json:"daily_limit"`
+	MonthlyLimit        float64   `gorm:"default:0" json:"monthly_limit"`
+	AddedBy             uint      `json:"added_by"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// EnterpriseWallet holds an organization's money, kept apart from its
+// members' personal wallets. An organization can split its funds across
+// several, such as one per department.
+type EnterpriseWallet struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	EnterpriseID uint      `gorm:"not null;index" json:"enterprise_id"`
+	Name         string    `gorm:"not null" json:"name"`
+	Balance      float64   `gorm:"not null;default:0" json:"balance"`
+	Currency     string    `gorm:"default:'USD'" json:"currency"`
+	Status       string    `gorm:"default:'active'" json:"status"`
+	CreatedBy    uint      `gorm:"not null" json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// EnterpriseFunding moves money from a member's personal wallet into an
+// organization wallet
+type EnterpriseFunding struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	EnterpriseID  uint      `gorm:"not null;index:,composite:enterprise_created" json:"enterprise_id"`
+	WalletID      uint      `gorm:"not null;index" json:"wallet_id"`
+	FundedBy      uint      `gorm:"not null" json:"funded_by"`
+	Amount        float64   `gorm:"not null" json:"amount"`
+	TransactionID uint      `json:"transaction_id"`
+	CreatedAt     time.Time `gorm:"index:,composite:enterprise_created" json:"created_at"`
+}
+
+// EnterprisePayment is a payment a member made out of an organization wallet
+type EnterprisePayment struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	EnterpriseID  uint      `gorm:"not null;index:,composite:enterprise_created" json:"enterprise_id"`
+	WalletID      uint      `gorm:"not null;index" json:"wallet_id"`
+	RequestedBy   uint      `gorm:"not null;index" json:"requested_by"`
+	RecipientID   uint      `gorm:"not null" json:"recipient_id"`
+	Amount        float64   `gorm:"not null" json:"amount"`
+	Category      string    `gorm:"size:50" json:"category,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Status        string    `gorm:"size:20;not null;index" json:"status"`
+	TransactionID *uint     `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `gorm:"index:,composite:enterprise_created" json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Set when an approval rule held the payment. ApproverRole is copied
+	// from the rule so later rule changes don't move queued payments.
+	RuleID       *uint      `json:"rule_id,omitempty"`
+	ApproverRole string     `gorm:"size:20" json:"approver_role,omitempty"`
+	ReviewedBy   *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote   string     `json:"review_note,omitempty"`
+}
+
+// EnterpriseApprovalRule holds matching payments for approval. A payment
+// matches when it comes from the rule's wallet (any wallet when unset), is
+// in its category (any category when empty) and is at least MinAmount.
+// Owners' payments are never held.
+type EnterpriseApprovalRule struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	EnterpriseID uint      `gorm:"not null;index" json:"enterprise_id"`
+	Name         string    `gorm:"not null" json:"name"`
+	WalletID     *uint     `json:"wallet_id,omitempty"`
+	Category     string    `gorm:"size:50" json:"category,omitempty"`
+	MinAmount    float64   `gorm:"not null;default:0" json:"min_amount"`
+	ApproverRole string    `gorm:"size:20;not null" json:"approver_role"`
+	Active       bool      `gorm:"not null;default:true" json:"active"`
+	CreatedBy    uint      `gorm:"not null" json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// EnterprisePaymentEvent is one step in an organization payment's audit
+// trail: who did what, and when
+type EnterprisePaymentEvent struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	EnterpriseID uint      `gorm:"not null;index" json:"enterprise_id"`
+	PaymentID    uint      `gorm:"not null;index" json:"payment_id"`
+	ActorID      uint      `gorm:"not null" json:"actor_id"`
+	Action       string    `gorm:"size:30;not null" json:"action"`
+	Note         string    `json:"note,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Escrow statuses
+const (
+	EscrowStatusFunded   = "funded"
+	EscrowStatusDisputed = "disputed"
+	EscrowStatusReleased = "released"
+	EscrowStatusRefunded = "refunded"
+)
+
+// Escrow holds a buyer's payment in the escrow system account until the
+// buyer confirms delivery, the auto-release date passes, or a dispute over
+// it is resolved.
+type Escrow struct {
+	ID                   uint       `gorm:"primarykey" json:"id"`
+	BuyerID              uint       `gorm:"not null;index" json:"buyer_id"`
+	SellerID             uint       `gorm:"not null;index" json:"seller_id"`
+	Amount               float64    `gorm:"not null" json:"amount"`
+	Currency             string     `gorm:"default:'USD'" json:"currency"`
+	Description          string     `gorm:"not null" json:"description"`
+	Status               string     `gorm:"size:20;not null;default:'funded';index" json:"status"`
+	AutoReleaseAt        time.Time  `gorm:"not null;index" json:"auto_release_at"`
+	FundingTransactionID *uint      `json:"funding_transaction_id,omitempty"`
+	SettleTransactionID  *uint      `json:"settle_transaction_id,omitempty"` // Release to the seller or refund to the buyer
+	SettledReason        string     `gorm:"size:30" json:"settled_reason,omitempty"`
+	SettledAt            *time.Time `json:"settled_at,omitempty"`
+	DisputeID            *uint      `json:"dispute_id,omitempty"`
+	DisputedBy           *uint      `json:"disputed_by,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Export destinations
+const (
+	ExportDestinationEmail   = "email"
+	ExportDestinationStorage = "storage"
+	ExportDestinationWebhook = "webhook"
+)
+
+// Export schedule statuses
+const (
+	ExportScheduleStatusActive = "active"
+	ExportScheduleStatusPaused = "paused"
+)
+
+// Export run statuses
+const (
+	ExportRunStatusSucceeded = "succeeded"
+	ExportRunStatusFailed    = "failed"
+)
+
+// ExportSchedule is a user's recurring weekly or monthly transaction history
+// export
+type ExportSchedule struct {
+	ID                  uint       `gorm:"primarykey" json:"id"`
+	UserID              uint       `gorm:"not null;index" json:"user_id"`
+	Frequency           string     `gorm:"not null;default:'monthly'" json:"frequency"`
+	DayOfMonth          int        `gorm:"not null;default:1" json:"day_of_month"`
+	DayOfWeek           int        `gorm:"not null;default:1" json:"day_of_week"` // ISO weekday, 1 is Monday
+	Format              string     `gorm:"not null;default:'csv'" json:"format"`
+	Destination         string     `gorm:"not null" json:"destination"`
+	Email               string     `json:"email,omitempty"`
+	StorageConnector    string     `json:"storage_connector,omitempty"`
+	StoragePath         string     `json:"storage_path,omitempty"`
+	WebhookURL          string     `json:"webhook_url,omitempty"`
+	WebhookSecret       string     `json:"webhook_secret,omitempty"` // Signs webhook deliveries; only ever shown to the owner
+	Status              string     `gorm:"not null;default:'active'" json:"status"`
+	NextRunAt           time.Time  `gorm:"index" json:"next_run_at"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+	LastRunStatus       string     `json:"last_run_status,omitempty"`
+	ConsecutiveFailures int        `gorm:"default:0" json:"consecutive_failures"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// ExportRun records one execution of an export schedule
+type ExportRun struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	ScheduleID  uint      `gorm:"not null;index" json:"schedule_id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Status      string    `gorm:"not null" json:"status"`
+	RowCount    int       `json:"row_count"`
+	Location    string    `json:"location,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Feature flags
+const (
+	// FeatureDiagnostics exposes the admin diagnostics endpoints that
+	// decode tokens and show token versions and cache stats
+	FeatureDiagnostics = "diagnostics"
+	// FeatureGraphQL exposes the GraphQL gateway for client apps
+	FeatureGraphQL = "graphql"
+	// FeatureGoogleLogin and FeatureAppleLogin let users sign in with
+	// their Google or Apple account, when the provider is configured
+	FeatureGoogleLogin = "social_login_google"
+	FeatureAppleLogin  = "social_login_apple"
+)
+
+// FeatureFlagOverride turns a feature flag on or off at runtime, in place
+// of its default for the environment and the configuration
+type FeatureFlagOverride struct {
+	Key       string    `gorm:"primarykey;size:50" json:"key"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	Reason    string    `json:"reason"`
+	UpdatedBy uint      `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "math"
+
 type UserType string
 
 const (
@@ -39,3 +41,33 @@ var FeeStructures = map[UserType]FeeStructure{
 		MinimumBalance:       1000,
 	},
 }
+
+// Merchant processing fee plans
+const (
+	FeePlanStandard   = "standard"
+	FeePlanPlus       = "plus"
+	FeePlanEnterprise = "enterprise"
+)
+
+// ProcessingFee is what a merchant's customer pays on each payment: a
+// share of the amount plus a fixed fee
+type ProcessingFee struct {
+	Plan     string  `json:"plan"`
+	Rate     float64 `json:"rate"` // Fraction of the amount, 0.029 for 2.9%
+	FixedFee float64 `json:"fixed_fee"`
+	// Negotiated is set when the rate is the merchant's own rather than
+	// the plan's
+	Negotiated bool `json:"negotiated"`
+}
+
+// FeePlans are the processing fees of each plan tier
+var FeePlans = map[string]ProcessingFee{
+	FeePlanStandard:   {Plan: FeePlanStandard, Rate: 0.029, FixedFee: 0.30},
+	FeePlanPlus:       {Plan: FeePlanPlus, Rate: 0.024, FixedFee: 0.25},
+	FeePlanEnterprise: {Plan: FeePlanEnterprise, Rate: 0.019, FixedFee: 0.20},
+}
+
+// Calculate returns the fee on amount, rounded to the cent
+func (f ProcessingFee) Calculate(amount float64) float64 {
+	return math.Round((amount*f.Rate+f.FixedFee)*100) / 100
+}
@@ -0,0 +1,108 @@
+package models
+
+import "testing"
+
+func TestMerchantEffectiveFee(t *testing.T) {
+	tests := []struct {
+		name           string
+		merchant       Merchant
+		amount         float64
+		wantPlan       string
+		wantRate       float64
+		wantNegotiated bool
+		wantFee        float64
+	}{
+		{
+			name:     "standard plan",
+			merchant: Merchant{FeePlan: FeePlanStandard},
+			amount:   100,
+			wantPlan: FeePlanStandard,
+			wantRate: 0.029,
+			wantFee:  3.20,
+		},
+		{
+			name:     "plus plan",
+			merchant: Merchant{FeePlan: FeePlanPlus},
+			amount:   100,
+			wantPlan: FeePlanPlus,
+			wantRate: 0.024,
+			wantFee:  2.65,
+		},
+		{
+			name:     "enterprise plan",
+			merchant: Merchant{FeePlan: FeePlanEnterprise},
+			amount:   100,
+			wantPlan: FeePlanEnterprise,
+			wantRate: 0.019,
+			wantFee:  2.10,
+		},
+		{
+			name:     "unknown plan falls back to standard",
+			merchant: Merchant{FeePlan: "legacy"},
+			amount:   100,
+			wantPlan: FeePlanStandard,
+			wantRate: 0.029,
+			wantFee:  3.20,
+		},
+		{
+			name:     "no plan falls back to standard",
+			merchant: Merchant{},
+			amount:   10,
+			wantPlan: FeePlanStandard,
+			wantRate: 0.029,
+			wantFee:  0.59,
+		},
+		{
+			name:           "negotiated rate keeps the plan's fixed fee",
+			merchant:       Merchant{FeePlan: FeePlanPlus, ProcessingFeeRate: 0.015},
+			amount:         200,
+			wantPlan:       FeePlanPlus,
+			wantRate:       0.015,
+			wantNegotiated: true,
+			wantFee:        3.25,
+		},
+		{
+			name:     "rounded to the cent",
+			merchant: Merchant{FeePlan: FeePlanStandard},
+			amount:   12.34,
+			wantPlan: FeePlanStandard,
+			wantRate: 0.029,
+			wantFee:  0.66,
+		},
+		{
+			name:     "fixed fee alone on a zero amount",
+			merchant: Merchant{FeePlan: FeePlanEnterprise},
+			amount:   0,
+			wantPlan: FeePlanEnterprise,
+			wantRate: 0.019,
+			wantFee:  0.20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := tt.merchant.EffectiveFee()
+			if fee.Plan != tt.wantPlan {
+				t.Errorf("plan = %q, want %q", fee.Plan, tt.wantPlan)
+			}
+			if fee.Rate != tt.wantRate {
+				t.Errorf("rate = %v, want %v", fee.Rate, tt.wantRate)
+			}
+			if fee.Negotiated != tt.wantNegotiated {
+				t.Errorf("negotiated = %v, want %v", fee.Negotiated, tt.wantNegotiated)
+			}
+			if got := fee.Calculate(tt.amount); got != tt.wantFee {
+				t.Errorf("Calculate(%v) = %v, want %v", tt.amount, got, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestEffectiveFeeLeavesPlansUnchanged(t *testing.T) {
+	merchant := Merchant{FeePlan: FeePlanStandard, ProcessingFeeRate: 0.01}
+	merchant.EffectiveFee()
+
+	if got := FeePlans[FeePlanStandard]; got.Rate != 0.029 || got.Negotiated {
+		t.Errorf("standard plan changed to %+v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+)
+
+// MerchantFraudRules are the fraud controls a merchant manages for payments
+// into their account. Zero values leave a control switched off.
+type MerchantFraudRules struct {
+	ID                         uint        `gorm:"primarykey" json:"id"`
+	MerchantID                 uint        `gorm:"not null;uniqueIndex" json:"merchant_id"` // Merchant's user ID
+	MaxChargeAmount            float64     `gorm:"default:0" json:"max_charge_amount"`
+	MaxChargesPerCustomerDaily int         `gorm:"default:0" json:"max_charges_per_customer_daily"`
+	BlockedCountries           CountryList `gorm:"type:text" json:"blocked_countries"`
+	CreatedAt                  time.Time   `json:"created_at"`
+	UpdatedAt                  time.Time   `json:"updated_at"`
+}
+
+// CountryList is a set of ISO 3166-1 alpha-2 codes stored as comma separated text
+type CountryList []string
+
+// Value implements the driver.Valuer interface
+func (l CountryList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (l *CountryList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	}
+	*l = nil
+	for _, code := range strings.Split(raw, ",") {
+		if code != "" {
+			*l = append(*l, code)
+		}
+	}
+	return nil
+}
+
+// Contains reports whether the list holds the given country code
+func (l CountryList) Contains(code string) bool {
+	for _, c := range l {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCountryCode upper-cases a country code and reports whether it
+// looks like an ISO 3166-1 alpha-2 code
+func NormalizeCountryCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return code, false
+	}
+	return code, true
+}
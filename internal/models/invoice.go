@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Invoice statuses
+const (
+	InvoiceStatusDraft   = "draft"
+	InvoiceStatusSent    = "sent"
+	InvoiceStatusPaid    = "paid"
+	InvoiceStatusOverdue = "overdue"
+	InvoiceStatusVoid    = "void"
+)
+
+// Invoice is a merchant's bill to a customer, payable from an Orus wallet
+type Invoice struct {
+	ID             uint              `gorm:"primarykey" json:"id"`
+	MerchantID     uint              `gorm:"not null;index" json:"merchant_id"` // Merchant's user ID
+	Number         string            `gorm:"not null;uniqueIndex" json:"number"`
+	PublicCode     string            `gorm:"not null;uniqueIndex" json:"public_code"`
+	CustomerName   string            `json:"customer_name"`
+	CustomerEmail  string            `gorm:"not null" json:"customer_email"`
+	Currency       string            `gorm:"default:'USD'" json:"currency"`
+	Status         string            `gorm:"not null;default:'draft';index" json:"status"`
+	Subtotal       float64           `json:"subtotal"`
+	TaxRate        float64           `json:"tax_rate"` // Percentage, e.g. 8.25
+	TaxAmount      float64           `json:"tax_amount"`
+	Total          float64           `json:"total"`
+	DueDate        time.Time         `gorm:"index" json:"due_date"`
+	Notes          string            `json:"notes,omitempty"`
+	SentAt         *time.Time        `json:"sent_at,omitempty"`
+	PaidAt         *time.Time        `json:"paid_at,omitempty"`
+	PaidBy         *uint             `json:"paid_by,omitempty"`
+	TransactionID  *uint             `json:"transaction_id,omitempty"`
+	LastReminderAt *time.Time        `json:"last_reminder_at,omitempty"`
+	ReminderCount  int               `gorm:"default:0" json:"reminder_count"`
+	LineItems      []InvoiceLineItem `gorm:"constraint:OnDelete:CASCADE" json:"line_items"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// IsPayable reports whether the invoice can be paid
+func (i *Invoice) IsPayable() bool {
+	return i.Status == InvoiceStatusSent || i.Status == InvoiceStatusOverdue
+}
+
+// InvoiceLineItem is a single billed item on an invoice
+type InvoiceLineItem struct {
+	ID          uint    `gorm:"primarykey" json:"id"`
+	InvoiceID   uint    `gorm:"not null;index" json:"-"`
+	Description string  `gorm:"not null" json:"description"`
+	Quantity    float64 `gorm:"not null" json:"quantity"`
+	UnitPrice   float64 `gorm:"not null" json:"unit_price"`
+	Amount      float64 `gorm:"not null" json:"amount"`
+}
+
+// InvoiceCounter tracks the last invoice number issued per merchant
+type InvoiceCounter struct {
+	MerchantID uint `gorm:"primarykey;autoIncrement:false"`
+	LastNumber int  `gorm:"not null;default:0"`
+}
@@ -51,3 +51,23 @@ func (j *JSON) UnmarshalJSON(data []byte) error {
 	}
 	return json.Unmarshal(data, &j.data)
 }
+
+// Map returns the data as a JSON object, or nil if it is empty or not an object
+func (j JSON) Map() map[string]interface{} {
+	switch data := j.data.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return data
+	}
+
+	raw, err := j.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Login event statuses
+const (
+	LoginStatusSucceeded  = "succeeded"
+	LoginStatusChallenged = "challenged" // Awaiting the one-time code
+	LoginStatusReported   = "reported"   // The user said it wasn't them
+)
+
+// LoginEvent is one sign-in to an account, with where and what it came
+// from. Logins from a country or device the user hasn't signed in from
+// before are challenged and alerted.
+type LoginEvent struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	UserID     uint   `gorm:"not null;index:idx_login_events_user,priority:1" json:"user_id"`
+	IP         string `gorm:"size:50" json:"ip"`
+	Country    string `gorm:"size:2" json:"country,omitempty"`
+	DeviceID   string `gorm:"size:64;index" json:"device_id"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	NewCountry bool   `json:"new_country"`
+	NewDevice  bool   `json:"new_device"`
+	Status     string `gorm:"size:20;not null" json:"status"`
+	// ReportTokenHash is the SHA-256 of the token in the alert's "this
+	// wasn't me" link
+	ReportTokenHash string     `gorm:"size:64;index" json:"-"`
+	ReportedAt      *time.Time `json:"reported_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"index:idx_login_events_user,priority:2" json:"created_at"`
+}
+
+// IsSuspicious reports whether the login came from somewhere new
+func (e *LoginEvent) IsSuspicious() bool {
+	return e.NewCountry || e.NewDevice
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Loyalty program statuses. Paused programs stop earning but points can
+// still be redeemed.
+const (
+	LoyaltyProgramActive = "active"
+	LoyaltyProgramPaused = "paused"
+)
+
+// Loyalty ledger entry kinds
+const (
+	LoyaltyEntryEarn     = "earn"     // Points accrued on a payment
+	LoyaltyEntryRedeem   = "redeem"   // Points spent as a discount
+	LoyaltyEntryReversal = "reversal" // Points handed back after a failed payment
+)
+
+// LoyaltyProgram is a merchant's points scheme. Customers earn EarnRate
+// percent of each payment as points, which are worth 1.00 off a future
+// payment at the merchant for every BurnRate points.
+type LoyaltyProgram struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	MerchantID       uint      `gorm:"not null;uniqueIndex" json:"merchant_id"` // Merchant's user ID
+	Name             string    `gorm:"size:100;not null" json:"name"`
+	EarnRate         float64   `gorm:"not null" json:"earn_rate"`
+	BurnRate         int       `gorm:"not null" json:"burn_rate"`
+	MinRedeemPoints  int64     `gorm:"default:0" json:"min_redeem_points"`
+	MaxRedeemPercent float64   `gorm:"not null;default:100" json:"max_redeem_percent"` // Largest share of a payment points can cover
+	Status           string    `gorm:"size:20;not null;default:'active'" json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// LoyaltyAccount is a customer's points balance with one merchant's program
+type LoyaltyAccount struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ProgramID  uint      `gorm:"not null;uniqueIndex:idx_loyalty_account" json:"program_id"`
+	CustomerID uint      `gorm:"not null;uniqueIndex:idx_loyalty_account;index" json:"customer_id"`
+	MerchantID uint      `gorm:"not null;index" json:"merchant_id"` // Merchant's user ID
+	Balance    int64     `gorm:"not null;default:0" json:"balance"`
+	Earned     int64     `gorm:"not null;default:0" json:"earned"`   // Lifetime points earned
+	Redeemed   int64     `gorm:"not null;default:0" json:"redeemed"` // Lifetime points redeemed
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// LoyaltyEntry is one posting to the points sub-ledger. Points are positive
+// for credits and negative for debits.
+type LoyaltyEntry struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	AccountID     uint      `gorm:"not null;index" json:"account_id"`
+	Kind          string    `gorm:"size:10;not null;uniqueIndex:idx_loyalty_entry_transaction" json:"kind"`
+	Points        int64     `gorm:"not null" json:"points"`
+	BalanceAfter  int64     `gorm:"not null" json:"balance_after"`
+	Value         float64   `gorm:"default:0" json:"value"` // Payment amount earned on, or discount given
+	TransactionID *uint     `gorm:"uniqueIndex:idx_loyalty_entry_transaction" json:"transaction_id,omitempty"`
+	Description   string    `json:"description"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
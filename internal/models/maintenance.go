@@ -0,0 +1,42 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// MaintenanceModeID is the ID of the single maintenance row
+const MaintenanceModeID = 1
+
+// MaintenanceMode pauses payment-mutating requests across the platform
+// while it's enabled; reads keep working. There is one row, kept when
+// maintenance ends as the record of who last changed it.
+type MaintenanceMode struct {
+	ID      uint   `gorm:"primarykey" json:"-"`
+	Enabled bool   `gorm:"not null" json:"enabled"`
+	Message string `json:"message"`
+	// RetryAfter is how many seconds clients are told to wait before
+	// retrying, unless EndsAt says otherwise
+	RetryAfter int        `gorm:"not null" json:"retry_after"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+	// AllowedUserIDs may keep making changes during maintenance, to
+	// check the platform before reopening it
+	AllowedUserIDs []uint    `gorm:"type:jsonb;serializer:json" json:"allowed_user_ids"`
+	UpdatedBy      uint      `json:"updated_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RetryAfterAt returns the seconds until clients should retry: until
+// EndsAt while it's ahead, otherwise RetryAfter
+func (m *MaintenanceMode) RetryAfterAt(now time.Time) int {
+	if m.EndsAt != nil && m.EndsAt.After(now) {
+		return int(m.EndsAt.Sub(now).Round(time.Second) / time.Second)
+	}
+	return m.RetryAfter
+}
+
+// Allows reports whether the user may make changes during maintenance
+func (m *MaintenanceMode) Allows(userID uint) bool {
+	return slices.Contains(m.AllowedUserIDs, userID)
+}
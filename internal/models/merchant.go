@@ -7,25 +7,77 @@ import (
 )
 
 type Merchant struct {
-	ID                      uint   `gorm:"primarykey"`
-	UserID                  uint   `gorm:"uniqueIndex;not null"`
-	BusinessName            string `gorm:"not null"`
-	BusinessType            string `gorm:"not null"`
-	BusinessAddress         string
-	RiskScore               int     `gorm:"default:0"`
-	ComplianceLevel         string  `gorm:"default:'pending'"`
-	Status                  string  `gorm:"default:'pending'"`
+	ID              uint   `gorm:"primarykey"`
+	UserID          uint   `gorm:"uniqueIndex;not null"`
+	BusinessName    string `gorm:"not null"`
+	BusinessType    string `gorm:"not null"`
+	BusinessAddress string
+	RiskScore       int    `gorm:"default:0"`
+	ComplianceLevel string `gorm:"default:'pending'"`
+	Status          string `gorm:"default:'pending'"`
+	// FeePlan is the merchant's pricing tier, one of the FeePlan
+	// constants; a non-zero ProcessingFeeRate is a negotiated rate that
+	// replaces the plan's
+	FeePlan                 string  `gorm:"size:20;default:'standard'"`
 	ProcessingFeeRate       float64 `gorm:"default:0"`
 	DailyTransactionLimit   float64
 	MonthlyTransactionLimit float64
 	MinTransactionAmount    float64
 	MaxTransactionAmount    float64
 	WebhookURL              string
+	WebhookSecret           string `json:"-"` // Signs webhook deliveries
 	MonthlyVolume           float64
 	Metadata                JSON `gorm:"type:jsonb"`
 	CreatedAt               time.Time
 	UpdatedAt               time.Time
-	APIKey                  string `gorm:"column:api_key"`
+	APIKey                  string `gorm:"column:api_key;index"`
+	// SandboxAPIKey authenticates the merchant's test integration; requests
+	// made with it only reach sandbox data
+	SandboxAPIKey string `gorm:"column:sandbox_api_key;index"`
+	// RequireMTLS refuses API requests without one of the merchant's
+	// registered client certificates
+	RequireMTLS bool `gorm:"column:require_mtls;default:false"`
+	// RiskStatus is set by risk monitoring. While it isn't normal,
+	// RiskRestoreLimit holds the MaxTransactionAmount to give back once an
+	// admin clears the merchant.
+	RiskStatus       string `gorm:"size:20;default:'normal';index"`
+	RiskRestoreLimit *float64
+	RiskAssessedAt   *time.Time
+	// RiskClearedAt stops monitoring from restricting the merchant again
+	// straight after a review, on the same history
+	RiskClearedAt *time.Time
+}
+
+// EffectiveFee is the processing fee the merchant's payments are charged:
+// their plan's, at their negotiated rate if they have one
+func (m *Merchant) EffectiveFee() ProcessingFee {
+	fee, ok := FeePlans[m.FeePlan]
+	if !ok {
+		fee = FeePlans[FeePlanStandard]
+	}
+	if m.ProcessingFeeRate > 0 {
+		fee.Rate = m.ProcessingFeeRate
+		fee.Negotiated = true
+	}
+	return fee
+}
+
+// ComplianceLevelFor maps a 0-100 risk score to the merchant's compliance level
+func ComplianceLevelFor(riskScore int) string {
+	switch {
+	case riskScore < 30:
+		return "low_risk"
+	case riskScore < 70:
+		return "medium_risk"
+	default:
+		return "high_risk"
+	}
+}
+
+// IsRiskRestricted reports whether risk monitoring has lowered the
+// merchant's limit
+func (m *Merchant) IsRiskRestricted() bool {
+	return m.RiskStatus == MerchantRiskTightened || m.RiskStatus == MerchantRiskReview
 }
 
 type MerchantBankAccount struct {
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// API key modes an access rule applies to
+const (
+	APIKeyModeLive    = "live"
+	APIKeyModeSandbox = "sandbox"
+)
+
+// Reasons a merchant API request was rejected
+const (
+	APIRejectionIPNotAllowed         = "ip_not_allowed"
+	APIRejectionClientCertMissing    = "client_cert_missing"
+	APIRejectionClientCertNotAllowed = "client_cert_not_allowed"
+)
+
+// MerchantIPRange is a source range a merchant's API key may be used from.
+// A key with no ranges may be used from anywhere.
+type MerchantIPRange struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	MerchantID uint      `gorm:"not null;index" json:"merchant_id"`
+	CIDR       string    `gorm:"column:cidr;size:50;not null" json:"cidr"`
+	KeyMode    string    `gorm:"size:10;not null" json:"key_mode"`
+	Label      string    `gorm:"size:100" json:"label,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MerchantClientCert is a client certificate accepted when the merchant
+// requires mutual TLS, known by its SHA-256 fingerprint
+type MerchantClientCert struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	MerchantID  uint      `gorm:"not null;uniqueIndex:idx_merchant_client_cert" json:"merchant_id"`
+	Fingerprint string    `gorm:"size:64;not null;uniqueIndex:idx_merchant_client_cert" json:"fingerprint"`
+	Subject     string    `json:"subject"`
+	NotAfter    time.Time `json:"not_after"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MerchantAPIRejection records a merchant API request refused by the
+// merchant's IP allowlist or mutual TLS requirement
+type MerchantAPIRejection struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	MerchantID  uint      `gorm:"not null;index:idx_merchant_api_rejections_recent,priority:1" json:"merchant_id"`
+	KeyMode     string    `gorm:"size:10;not null" json:"key_mode"`
+	Reason      string    `gorm:"size:30;not null" json:"reason"`
+	IP          string    `gorm:"size:50" json:"ip"`
+	Method      string    `gorm:"size:10" json:"method"`
+	Path        string    `json:"path"`
+	Fingerprint string    `gorm:"size:64" json:"fingerprint,omitempty"`
+	CreatedAt   time.Time `gorm:"index:idx_merchant_api_rejections_recent,priority:2" json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Merchant reserve sources: who set a merchant's reserve
+const (
+	ReserveSourceDefault = "default"
+	ReserveSourceAdmin   = "admin"
+	ReserveSourceRisk    = "risk" // Raised by risk monitoring while the merchant is restricted
+)
+
+// Reserve entry statuses
+const (
+	ReserveEntryHeld     = "held"
+	ReserveEntryReleased = "released"
+)
+
+// MerchantReserve is a merchant's rolling reserve: the share of each
+// payment they receive that is withheld, and for how many days. Merchants
+// without one use the configured default.
+type MerchantReserve struct {
+	MerchantUserID uint    `gorm:"primarykey" json:"merchant_user_id"`
+	Percent        float64 `gorm:"not null;default:0" json:"percent"` // Fraction withheld, 0.1 for 10%
+	HoldDays       int     `gorm:"not null;default:0" json:"hold_days"`
+	Source         string  `gorm:"size:10;not null" json:"source"`
+	Reason         string  `json:"reason,omitempty"`
+	// The Restore fields keep the reserve risk monitoring replaced, given
+	// back once an admin clears the merchant
+	RestorePercent *float64  `json:"-"`
+	RestoreDays    *int      `json:"-"`
+	RestoreSource  string    `gorm:"size:10" json:"-"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ReserveEntry is the part of one payment withheld in a merchant's
+// reserve. It is held on their wallet until ReleaseAt.
+type ReserveEntry struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	MerchantUserID uint       `gorm:"not null;index" json:"merchant_user_id"`
+	TransactionID  uint       `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	HoldID         uint       `gorm:"not null" json:"hold_id"`
+	Amount         float64    `gorm:"not null" json:"amount"`
+	Status         string     `gorm:"size:10;not null;default:'held';index" json:"status"`
+	ReleaseAt      time.Time  `gorm:"not null;index" json:"release_at"`
+	ReleasedAt     *time.Time `json:"released_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Merchant risk statuses
+const (
+	MerchantRiskNormal    = "normal"
+	MerchantRiskTightened = "tightened" // Limit lowered automatically
+	MerchantRiskReview    = "review"    // Limit lowered until an admin clears the merchant
+)
+
+// Actions a merchant risk assessment can take
+const (
+	MerchantRiskActionNone      = "none"
+	MerchantRiskActionTightened = "tightened"
+	MerchantRiskActionReview    = "review"
+	MerchantRiskActionCleared   = "cleared" // An admin restored the merchant's limit
+)
+
+// MerchantRiskAssessment is one scoring of a merchant's recent activity and
+// what, if anything, it changed. Together they are the merchant's risk
+// history.
+type MerchantRiskAssessment struct {
+	ID             uint    `gorm:"primarykey" json:"id"`
+	MerchantUserID uint    `gorm:"not null;index" json:"merchant_user_id"`
+	Score          int     `json:"score"`
+	ChargeCount    int64   `json:"charge_count"`
+	ChargeVolume   float64 `json:"charge_volume"`
+	// ChargebackRate and RefundRate are shares of the charges in the
+	// window; VolumeSpike compares the last day's volume to the daily
+	// average before it
+	ChargebackRate float64   `json:"chargeback_rate"`
+	RefundRate     float64   `json:"refund_rate"`
+	VolumeSpike    float64   `json:"volume_spike"`
+	Reasons        string    `json:"reasons,omitempty"`
+	Action         string    `gorm:"size:20;not null" json:"action"`
+	Status         string    `gorm:"size:20;not null" json:"status"` // The merchant's risk status afterwards
+	LimitBefore    float64   `json:"limit_before"`
+	LimitAfter     float64   `json:"limit_after"`
+	Note           string    `json:"note,omitempty"`
+	ReviewedBy     *uint     `json:"reviewed_by,omitempty"` // The admin who cleared the merchant
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Merchant staff statuses
+const (
+	StaffStatusInvited  = "invited"
+	StaffStatusActive   = "active"
+	StaffStatusDeclined = "declined"
+	StaffStatusRevoked  = "revoked"
+)
+
+// Staff scopes, checked on each operator action
+const (
+	StaffScopeCharge  = "charge"
+	StaffScopeRefund  = "refund"
+	StaffScopeReports = "reports"
+)
+
+// MerchantStaff lets another user operate a merchant's point of sale. Each
+// operator has their own PIN, entered at the till, and every charge or
+// refund they make is attributed to them.
+type MerchantStaff struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	MerchantID     uint       `gorm:"not null;uniqueIndex:idx_merchant_staff_user" json:"merchant_id"`
+	MerchantUserID uint       `gorm:"not null;index" json:"merchant_user_id"`
+	UserID         uint       `gorm:"not null;uniqueIndex:idx_merchant_staff_user;index" json:"user_id"`
+	DisplayName    string     `gorm:"size:50" json:"display_name"`
+	CanCharge      bool       `gorm:"not null;default:true" json:"can_charge"`
+	CanRefund      bool       `gorm:"not null;default:false" json:"can_refund"`
+	CanViewReports bool       `gorm:"not null;default:false" json:"can_view_reports"`
+	Status         string     `gorm:"size:20;not null;default:'invited';index" json:"status"`
+	PINHash        string     `json:"-"`
+	PINSetAt       *time.Time `json:"pin_set_at,omitempty"`
+	FailedPINs     int        `gorm:"not null;default:0" json:"-"`
+	PINLockedUntil *time.Time `json:"pin_locked_until,omitempty"`
+	InvitedBy      uint       `gorm:"not null" json:"invited_by"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// HasScope reports whether the staff member may perform the action
+func (s *MerchantStaff) HasScope(scope string) bool {
+	switch scope {
+	case StaffScopeCharge:
+		return s.CanCharge
+	case StaffScopeRefund:
+		return s.CanRefund
+	case StaffScopeReports:
+		return s.CanViewReports
+	}
+	return false
+}
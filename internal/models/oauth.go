@@ -0,0 +1,137 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scopes third-party apps can be granted
+const (
+	OAuthScopeBalanceRead      = "balance:read"
+	OAuthScopeTransactionsRead = "transactions:read"
+	// OAuthScopePaymentsInitiate lets an app request payments, which the
+	// user approves in the wallet before any money moves
+	OAuthScopePaymentsInitiate = "payments:initiate"
+)
+
+// OAuthScopes describes each scope on the consent screen
+var OAuthScopes = map[string]string{
+	OAuthScopeBalanceRead:      "See your wallet balance",
+	OAuthScopeTransactionsRead: "See your transaction history",
+	OAuthScopePaymentsInitiate: "Request payments from your wallet, which you approve each time",
+}
+
+// OAuth client statuses
+const (
+	OAuthClientActive   = "active"
+	OAuthClientDisabled = "disabled"
+)
+
+// OAuthClient is a third-party app users can authorize. It authenticates
+// to the token endpoint with its client ID and secret.
+type OAuthClient struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	ClientID string `gorm:"size:64;not null;uniqueIndex" json:"client_id"`
+	// SecretHash is the SHA-256 of the secret, shown once at registration
+	SecretHash   string   `gorm:"size:64;not null" json:"-"`
+	OwnerID      uint     `gorm:"not null;index" json:"owner_id"`
+	Name         string   `gorm:"size:100;not null" json:"name"`
+	RedirectURIs []string `gorm:"type:jsonb;serializer:json" json:"redirect_uris"`
+	// Scopes are the most the app can ask users for
+	Scopes    []string  `gorm:"type:jsonb;serializer:json" json:"scopes"`
+	Status    string    `gorm:"size:20;not null" json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName keeps the OAuth tables named oauth_*, not GORM's o_auth_*
+func (OAuthClient) TableName() string { return "oauth_clients" }
+
+// AllowsRedirect reports whether uri is one of the app's redirect URIs
+func (c *OAuthClient) AllowsRedirect(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// OAuthAuthorizationCode is the one-time code a user's consent sends back
+// to the app, exchanged for tokens at the token endpoint
+type OAuthAuthorizationCode struct {
+	ID          uint   `gorm:"primarykey"`
+	CodeHash    string `gorm:"size:64;not null;uniqueIndex"`
+	ClientID    string `gorm:"size:64;not null"`
+	UserID      uint   `gorm:"not null"`
+	RedirectURI string `gorm:"not null"`
+	Scope       string `gorm:"not null"`
+	// CodeChallenge is the PKCE S256 challenge, when the app sent one
+	CodeChallenge string `gorm:"size:64"`
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+func (OAuthAuthorizationCode) TableName() string { return "oauth_authorization_codes" }
+
+// OAuthToken is an access token and its refresh token, issued to an app
+// for a user. Both are opaque and stored as SHA-256 hashes.
+type OAuthToken struct {
+	ID               uint       `gorm:"primarykey" json:"id"`
+	ClientID         string     `gorm:"size:64;not null;index:idx_oauth_tokens_grant,priority:2" json:"client_id"`
+	UserID           uint       `gorm:"not null;index:idx_oauth_tokens_grant,priority:1" json:"user_id"`
+	Scope            string     `gorm:"not null" json:"scope"`
+	AccessHash       string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	RefreshHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	AccessExpiresAt  time.Time  `json:"access_expires_at"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func (OAuthToken) TableName() string { return "oauth_tokens" }
+
+// HasScope reports whether the token was granted scope
+func (t *OAuthToken) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(t.Scope), scope)
+}
+
+// OAuthGrant is a user's consent to an app, listed as a connected app
+// until the user disconnects it
+type OAuthGrant struct {
+	ID        uint         `gorm:"primarykey" json:"id"`
+	UserID    uint         `gorm:"not null;uniqueIndex:idx_oauth_grant" json:"user_id"`
+	ClientID  string       `gorm:"size:64;not null;uniqueIndex:idx_oauth_grant" json:"client_id"`
+	Scope     string       `gorm:"not null" json:"scope"`
+	Client    *OAuthClient `gorm:"foreignKey:ClientID;references:ClientID" json:"client,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func (OAuthGrant) TableName() string { return "oauth_grants" }
+
+// OAuth payment request statuses
+const (
+	OAuthPaymentPending  = "pending"
+	OAuthPaymentApproved = "approved"
+	OAuthPaymentDeclined = "declined"
+	OAuthPaymentExpired  = "expired"
+)
+
+// OAuthPaymentRequest is a payment an app asked for on a user's behalf.
+// Nothing moves until the user approves it in the wallet, when it is sent
+// as a transfer.
+type OAuthPaymentRequest struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	ClientID      string     `gorm:"size:64;not null" json:"client_id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	RecipientID   uint       `gorm:"not null" json:"recipient_id"`
+	Amount        float64    `gorm:"not null" json:"amount"`
+	Description   string     `json:"description,omitempty"`
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	TransactionID *uint      `json:"transaction_id,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	// ClientName is shown to the user deciding
+	ClientName string `gorm:"-" json:"client_name,omitempty"`
+}
+
+func (OAuthPaymentRequest) TableName() string { return "oauth_payment_requests" }
@@ -13,15 +13,17 @@ type PaymentRequest struct {
 	Amount      float64 `json:"amount" validate:"required,gt=0"`
 	RecipientID uint    `json:"recipient_id" validate:"required"`
 	Description string  `json:"description"`
-	PaymentType string  `json:"payment_type" validate:"required,oneof=wallet card qr"`
+	PaymentType string  `json:"payment_type" validate:"required,oneof=wallet card qr merchant_payment"`
 }
 
 // QRPaymentRequest represents a QR code payment request
 type QRPaymentRequest struct {
-	QRCode      string         `json:"qr_code"`
-	Amount      float64        `json:"amount"`
-	Description string         `json:"description"`
+	QRCode      string         `json:"qr_code" validate:"required"`
+	Amount      float64        `json:"amount" validate:"gt=0,lte=1000000"`
+	Description string         `json:"description" validate:"max=255"`
 	Metadata    map[string]any `json:"metadata"`
+	// Loyalty points the payer spends at the merchant for a discount
+	LoyaltyPoints int64 `json:"loyalty_points" validate:"gte=0"`
 }
 
 const (
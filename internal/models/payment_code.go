@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Payment code statuses
+const (
+	PaymentCodeStatusActive   = "active"
+	PaymentCodeStatusRedeemed = "redeemed"
+)
+
+// PaymentCode is a numeric one-time code a customer reads out or types in
+// at a till instead of showing a QR code. Only a keyed hash of the code is
+// stored; the customer sees the code once, when it is generated.
+type PaymentCode struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	UserID         uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash       string     `gorm:"size:64;not null;index" json:"-"`
+	Amount         float64    `gorm:"not null" json:"amount"`
+	Status         string     `gorm:"size:20;not null;default:'active';index" json:"status"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	MerchantUserID *uint      `gorm:"index" json:"merchant_user_id,omitempty"`
+	TransactionID  *uint      `json:"transaction_id,omitempty"`
+	RedeemedAt     *time.Time `json:"redeemed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
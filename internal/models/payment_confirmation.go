@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Payment confirmation channels
+const (
+	ConfirmationChannelEmail = "email"
+	ConfirmationChannelPush  = "push"
+)
+
+// Payment confirmation roles: which side of the payment the user was on
+const (
+	ConfirmationRolePayer = "payer"
+	ConfirmationRolePayee = "payee"
+)
+
+// Payment confirmation statuses
+const (
+	ConfirmationPending = "pending"
+	ConfirmationSent    = "sent"
+	// ConfirmationDigested means the confirmation went out in a digest
+	// with the user's other pending ones
+	ConfirmationDigested = "digested"
+)
+
+// ConfirmationSettings is how a user wants to hear about their completed
+// payments. Users without settings get confirmations by email.
+type ConfirmationSettings struct {
+	UserID    uint      `gorm:"primarykey" json:"-"`
+	Channel   string    `gorm:"size:10;not null;default:'email'" json:"channel"`
+	OptOut    bool      `gorm:"not null;default:false" json:"opt_out"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PaymentConfirmation is a confirmation owed to one party of a completed
+// payment. They are queued rather than sent straight away so a busy
+// merchant's can be rolled into one digest.
+type PaymentConfirmation struct {
+	ID            uint   `gorm:"primarykey"`
+	TransactionID uint   `gorm:"not null;uniqueIndex:idx_payment_confirmations_tx_user"`
+	UserID        uint   `gorm:"not null;uniqueIndex:idx_payment_confirmations_tx_user"`
+	Role          string `gorm:"size:10;not null"`
+	Channel       string `gorm:"size:10;not null"`
+	Status        string `gorm:"size:20;not null;default:'pending';index"`
+	SentAt        *time.Time
+	CreatedAt     time.Time
+	Transaction   *Transaction `gorm:"foreignKey:TransactionID"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Payment intent statuses
+const (
+	PaymentIntentStatusOpen = "open"
+	PaymentIntentStatusPaid = "paid"
+)
+
+// PaymentIntent is a request for a set amount, shared as a signed link or
+// written to an NFC tag. Opening it pre-fills the payment in the app; it
+// can be paid once, before it expires.
+type PaymentIntent struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	RecipientID   uint       `gorm:"not null;index" json:"recipient_id"`
+	Amount        float64    `gorm:"not null" json:"amount"`
+	Currency      string     `gorm:"size:3;not null;default:'USD'" json:"currency"`
+	Description   string     `json:"description,omitempty"`
+	Reference     string     `gorm:"size:64" json:"reference,omitempty"`
+	Status        string     `gorm:"size:20;not null;default:'open';index" json:"status"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	PayerID       *uint      `gorm:"index" json:"payer_id,omitempty"`
+	TransactionID *uint      `json:"transaction_id,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Payment link statuses
+const (
+	PaymentLinkStatusActive   = "active"
+	PaymentLinkStatusDisabled = "disabled"
+)
+
+// Checkout session statuses
+const (
+	CheckoutSessionStatusOpen       = "open"
+	CheckoutSessionStatusProcessing = "processing" // Claimed by a payer while the payment goes through
+	CheckoutSessionStatusCompleted  = "completed"
+	CheckoutSessionStatusExpired    = "expired"
+)
+
+// PaymentLink is a shareable link a merchant uses to collect a fixed amount
+type PaymentLink struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	MerchantID  uint       `gorm:"not null;index" json:"merchant_id"` // Merchant's user ID
+	Code        string     `gorm:"not null;uniqueIndex" json:"code"`
+	Amount      float64    `gorm:"not null" json:"amount"`
+	Currency    string     `gorm:"default:'USD'" json:"currency"`
+	Description string     `json:"description"`
+	Status      string     `gorm:"not null;default:'active'" json:"status"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	MaxUses     int        `gorm:"default:-1" json:"max_uses"` // -1 means unlimited
+	UseCount    int        `gorm:"default:0" json:"use_count"`
+	ViewCount   int        `gorm:"default:0" json:"view_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsUsable reports whether the link can still accept payments
+func (l *PaymentLink) IsUsable(now time.Time) bool {
+	if l.Status != PaymentLinkStatusActive {
+		return false
+	}
+	if l.ExpiresAt != nil && !now.Before(*l.ExpiresAt) {
+		return false
+	}
+	return l.MaxUses == -1 || l.UseCount < l.MaxUses
+}
+
+// Where a checkout session was opened from
+const (
+	CheckoutSourcePaymentLink = "payment_link"
+	CheckoutSourceAPI         = "api" // Created by the merchant's server for an online order
+)
+
+// CheckoutSession is a payer's attempt to pay a payment link, or an order a
+// merchant opened through the API for any payer to complete
+type CheckoutSession struct {
+	ID              uint       `gorm:"primarykey" json:"-"`
+	SessionID       string     `gorm:"not null;uniqueIndex" json:"id"`
+	Source          string     `gorm:"size:20;not null;default:'payment_link';index" json:"source"`
+	PaymentLinkID   *uint      `gorm:"index" json:"payment_link_id,omitempty"`
+	MerchantID      uint       `gorm:"not null;index" json:"merchant_id"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"` // Payer; 0 until an API session is paid
+	Amount          float64    `gorm:"not null" json:"amount"`
+	Currency        string     `gorm:"default:'USD'" json:"currency"`
+	Description     string     `json:"description"`
+	ClientReference string     `gorm:"size:100;index" json:"client_reference,omitempty"`
+	Metadata        JSON       `gorm:"type:jsonb" json:"metadata"`
+	SuccessURL      string     `json:"success_url,omitempty"`
+	CancelURL       string     `json:"cancel_url,omitempty"`
+	Status          string     `gorm:"not null;default:'open'" json:"status"`
+	TransactionID   *uint      `json:"transaction_id,omitempty"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PaymentPIN is a user's optional numeric PIN, separate from their password,
+// entered for large transfers and card removal. Wrong PINs in a row lock
+// it for a while.
+type PaymentPIN struct {
+	UserID         uint       `gorm:"primarykey" json:"-"`
+	PINHash        string     `gorm:"not null" json:"-"`
+	FailedAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	SetAt          time.Time  `json:"set_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsLocked reports whether wrong PINs have locked the PIN at now
+func (p *PaymentPIN) IsLocked(now time.Time) bool {
+	return p.LockedUntil != nil && now.Before(*p.LockedUntil)
+}
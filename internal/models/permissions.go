@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 // Permission constants
 const (
 	// Wallet permissions
@@ -32,11 +34,55 @@ const (
 	// User management permissions
 	PermissionUserRead  = "user:read"
 	PermissionUserWrite = "user:write"
+
+	// Treasury permissions. Moving funds between system accounts is
+	// reserved for super-admins.
+	PermissionTreasuryRead  = "treasury:read"
+	PermissionTreasuryWrite = "treasury:write"
+
+	// Compliance permissions, for the officers reviewing and filing
+	// regulatory reports
+	PermissionComplianceRead  = "compliance:read"
+	PermissionComplianceWrite = "compliance:write"
 )
 
-// GetDefaultPermissions returns default permissions based on role
+// PermissionDefinitions lists every permission roles can grant
+var PermissionDefinitions = []Permission{
+	{Name: PermissionWalletRead, Description: "View wallets and balances"},
+	{Name: PermissionWalletWrite, Description: "Top up, withdraw and move wallet funds"},
+	{Name: PermissionTransactionRead, Description: "View transactions"},
+	{Name: PermissionTransactionWrite, Description: "Create transactions"},
+	{Name: PermissionCreditCardWrite, Description: "Manage credit cards"},
+	{Name: PermissionChangePassword, Description: "Change their own password"},
+	{Name: PermissionMerchantCreate, Description: "Create a merchant profile"},
+	{Name: PermissionMerchantRead, Description: "View merchant profiles"},
+	{Name: PermissionMerchantWrite, Description: "Manage merchant profiles"},
+	{Name: PermissionMerchantTransaction, Description: "Take merchant payments"},
+	{Name: PermissionPaymentWrite, Description: "Make payments"},
+	{Name: PermissionReadAdmin, Description: "Use the read-only admin API"},
+	{Name: PermissionWriteAdmin, Description: "Make changes through the admin API"},
+	{Name: PermissionUserRead, Description: "View user accounts"},
+	{Name: PermissionUserWrite, Description: "Manage user accounts"},
+	{Name: PermissionTreasuryRead, Description: "View system accounts"},
+	{Name: PermissionTreasuryWrite, Description: "Move funds between system accounts"},
+	{Name: PermissionComplianceRead, Description: "View regulatory report drafts"},
+	{Name: PermissionComplianceWrite, Description: "Review, file and dismiss regulatory reports"},
+}
+
+// SystemRoles are the built-in roles, seeded with GetDefaultPermissions
+var SystemRoles = []string{"super_admin", "admin", "user", "regular", "merchant"}
+
+// IsSystemRole reports whether role is built in
+func IsSystemRole(role string) bool {
+	return slices.Contains(SystemRoles, role)
+}
+
+// GetDefaultPermissions returns the permissions of a built-in role. Custom
+// roles live in the database; see the rbac service.
 func GetDefaultPermissions(role string) []string {
 	switch role {
+	case "super_admin":
+		return append(GetDefaultPermissions("admin"), PermissionTreasuryWrite)
 	case "admin":
 		return []string{
 			PermissionReadAdmin,
@@ -53,6 +99,9 @@ func GetDefaultPermissions(role string) []string {
 			PermissionMerchantWrite,
 			PermissionMerchantCreate,
 			PermissionPaymentWrite,
+			PermissionTreasuryRead,
+			PermissionComplianceRead,
+			PermissionComplianceWrite,
 		}
 	case "regular", "user":
 		return []string{
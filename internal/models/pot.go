@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Pot statuses
+const (
+	PotStatusActive = "active"
+	PotStatusClosed = "closed"
+)
+
+// Pot entry kinds
+const (
+	PotEntryDeposit    = "deposit"
+	PotEntryWithdrawal = "withdrawal"
+	PotEntryRoundUp    = "round_up"
+	PotEntrySweep      = "sweep"
+)
+
+// Sweep frequencies
+const (
+	SweepDaily   = "daily"
+	SweepWeekly  = "weekly"
+	SweepMonthly = "monthly"
+)
+
+// Pot is a savings sub-account set aside from a user's main wallet balance.
+// Money in a pot is not spendable until it is moved back.
+type Pot struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	UserID         uint       `gorm:"not null;index" json:"user_id"`
+	WalletID       uint       `gorm:"not null;index" json:"wallet_id"`
+	Name           string     `gorm:"not null" json:"name"`
+	Balance        float64    `gorm:"not null;default:0" json:"balance"`
+	Currency       string     `gorm:"default:'USD'" json:"currency"`
+	TargetAmount   float64    `gorm:"default:0" json:"target_amount"` // 0 means no goal
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`         // No withdrawals before this date
+	Status         string     `gorm:"not null;default:'active';index" json:"status"`
+	RoundUpEnabled bool       `gorm:"default:false;index" json:"round_up_enabled"`
+	RoundUpTo      float64    `gorm:"default:1" json:"round_up_to"`  // Round card and QR payments up to a multiple of this
+	RoundUpAfterID uint       `gorm:"default:0" json:"-"`            // Last transaction considered for round-ups
+	SweepAmount    float64    `gorm:"default:0" json:"sweep_amount"` // 0 disables scheduled sweeps
+	SweepFrequency string     `gorm:"size:10" json:"sweep_frequency,omitempty"`
+	NextSweepAt    *time.Time `gorm:"index" json:"next_sweep_at,omitempty"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsLocked reports whether withdrawals are blocked at the given time
+func (p *Pot) IsLocked(now time.Time) bool {
+	return p.LockedUntil != nil && now.Before(*p.LockedUntil)
+}
+
+// PotEntry is one movement of money into or out of a pot. Amount is positive
+// into the pot and negative out of it.
+type PotEntry struct {
+	ID                  uint      `gorm:"primarykey" json:"id"`
+	PotID               uint      `gorm:"not null;index;uniqueIndex:idx_pot_entry_source" json:"pot_id"`
+	Kind                string    `gorm:"size:20;not null" json:"kind"`
+	Amount              float64   `gorm:"not null" json:"amount"`
+	BalanceAfter        float64   `gorm:"not null" json:"balance_after"`
+	TransactionID       uint      `gorm:"not null" json:"transaction_id"`                                // The pot_transfer ledger transaction
+	SourceTransactionID *uint     `gorm:"uniqueIndex:idx_pot_entry_source" json:"source_transaction_id"` // The payment a round-up came from
+	CreatedAt           time.Time `json:"created_at"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Promotion types
+const (
+	PromotionTypeCashback         = "cashback"          // Percentage of the payment back
+	PromotionTypeFixedBonus       = "fixed_bonus"       // Flat bonus per qualifying payment
+	PromotionTypeFirstTransaction = "first_transaction" // Flat bonus on the customer's first payment
+)
+
+// Who pays for a promotion's rewards
+const (
+	PromotionFundedByPlatform = "platform" // Debited from the promotions expense account
+	PromotionFundedByMerchant = "merchant" // Debited from the merchant's wallet
+)
+
+// Promotion statuses. A promotion becomes exhausted once its budget is spent.
+const (
+	PromotionStatusActive    = "active"
+	PromotionStatusPaused    = "paused"
+	PromotionStatusExhausted = "exhausted"
+	PromotionStatusEnded     = "ended"
+)
+
+// Promotion is an admin-defined campaign that rewards qualifying merchant
+// payments. Promotions without a code apply to every customer; coded ones
+// only to customers who redeemed the code.
+type Promotion struct {
+	ID               uint       `gorm:"primarykey" json:"id"`
+	Name             string     `gorm:"size:100;not null" json:"name"`
+	Description      string     `json:"description,omitempty"`
+	Code             *string    `gorm:"size:32;uniqueIndex" json:"code,omitempty"`
+	Type             string     `gorm:"size:20;not null" json:"type"`
+	Rate             float64    `gorm:"default:0" json:"rate,omitempty"`       // Cashback percentage
+	Amount           float64    `gorm:"default:0" json:"amount,omitempty"`     // Fixed bonus amount
+	MaxReward        float64    `gorm:"default:0" json:"max_reward,omitempty"` // Cashback cap per payment, 0 for none
+	MinSpend         float64    `gorm:"default:0" json:"min_spend"`
+	MerchantID       *uint      `gorm:"index" json:"merchant_id,omitempty"` // Merchant's user ID; nil for any merchant
+	MerchantCategory string     `gorm:"size:50" json:"merchant_category,omitempty"`
+	FundedBy         string     `gorm:"size:10;not null" json:"funded_by"`
+	Budget           float64    `gorm:"not null" json:"budget"`
+	Spent            float64    `gorm:"not null;default:0" json:"spent"`
+	MaxPerUser       int        `gorm:"default:0" json:"max_per_user"` // 0 for no limit
+	StartsAt         time.Time  `json:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+	Status           string     `gorm:"size:20;not null;default:'active';index" json:"status"`
+	CreatedBy        uint       `gorm:"not null" json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// PromotionEnrollment records a customer redeeming a promotion's code
+type PromotionEnrollment struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	PromotionID uint      `gorm:"not null;uniqueIndex:idx_promotion_enrollment" json:"promotion_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_promotion_enrollment;index" json:"user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PromotionReward is one reward credited for a qualifying payment. The
+// credit itself is a separate promotion transaction in the customer's history.
+type PromotionReward struct {
+	ID                   uint      `gorm:"primarykey" json:"id"`
+	PromotionID          uint      `gorm:"not null;uniqueIndex:idx_promotion_reward_payment" json:"promotion_id"`
+	UserID               uint      `gorm:"not null;index" json:"user_id"`
+	PaymentTransactionID uint      `gorm:"not null;uniqueIndex:idx_promotion_reward_payment" json:"payment_transaction_id"`
+	RewardTransactionID  uint      `gorm:"not null" json:"reward_transaction_id"`
+	Amount               float64   `gorm:"not null" json:"amount"`
+	FundedBy             string    `gorm:"size:10;not null" json:"funded_by"`
+	CreatedAt            time.Time `gorm:"index" json:"created_at"`
+}
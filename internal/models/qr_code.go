@@ -7,10 +7,18 @@ import (
 )
 
 const (
-	QRTypeStatic      = "static"  // For receiving payments
-	QRTypeDynamic     = "dynamic" // For receiving specific amount
-	QRTypePayment     = "payment" // For making payments (user shows this)
-	QRTypePaymentCode = "payment" // Alias for payment type
+	QRTypeStatic      = "static"          // For receiving payments
+	QRTypeDynamic     = "dynamic"         // For receiving specific amount
+	QRTypePayment     = "payment"         // For making payments (user shows this)
+	QRTypePaymentCode = "payment"         // Alias for payment type
+	QRTypePoster      = "merchant_poster" // Printed static code at a merchant's till or location
+)
+
+// QR code statuses
+const (
+	QRStatusActive  = "active"
+	QRStatusRevoked = "revoked" // Withdrawn by its owner, e.g. after a leak
+	QRStatusExpired = "expired" // Passed its expiry date or used up
 )
 
 type QRCode struct {
@@ -30,8 +38,32 @@ type QRCode struct {
 	MonthlyLimit     *float64
 	AllowedCustomers []uint `gorm:"type:integer[]"`
 	Metadata         JSON   `gorm:"type:jsonb"`
+
+	// Usage of the code, counted as payments complete
+	TotalAmount float64 `gorm:"not null;default:0"`
+	LastUsedAt  *time.Time
+
+	RevokedAt    *time.Time
+	RevokeReason string
+	// ReplacedByID is the code regenerated in place of this one
+	ReplacedByID *uint
+
+	// Merchant posters: where the code is displayed, amounts printed on it
+	// for payers to pick, and a reference stamped on its payments
+	Label         string
+	Location      string
+	TerminalID    *uint     `gorm:"index"`
+	PresetAmounts []float64 `gorm:"type:jsonb;serializer:json"`
+	Reference     string
 }
 
+// QR transaction statuses
+const (
+	QRTransactionPending   = "pending"
+	QRTransactionCompleted = "completed"
+)
+
+// QRTransaction is a payment made with a QR code. CustomerID is the payer.
 type QRTransaction struct {
 	gorm.Model
 	QRCodeID      uint `gorm:"index"`
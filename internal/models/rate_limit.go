@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Rate limit endpoint classes. Payments are the strictest and also count
+// toward writes.
+const (
+	RateLimitReads    = "reads"
+	RateLimitWrites   = "writes"
+	RateLimitPayments = "payments"
+)
+
+// RateLimitClasses lists every endpoint class
+var RateLimitClasses = []string{RateLimitReads, RateLimitWrites, RateLimitPayments}
+
+// Who a rate limit applies to
+const (
+	RateLimitSubjectUser     = "user"     // Authenticated by a user token
+	RateLimitSubjectMerchant = "merchant" // Authenticated by a merchant API key
+)
+
+// RateLimitOverride replaces the configured budget of one endpoint class for
+// one user or merchant
+type RateLimitOverride struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	SubjectType string    `gorm:"size:10;not null;uniqueIndex:idx_rate_limit_overrides_subject_class" json:"subject_type"`
+	SubjectID   uint      `gorm:"not null;uniqueIndex:idx_rate_limit_overrides_subject_class" json:"subject_id"` // User ID, or merchant ID for API keys
+	Class       string    `gorm:"size:10;not null;uniqueIndex:idx_rate_limit_overrides_subject_class" json:"class"`
+	Limit       int       `gorm:"not null" json:"limit"` // Requests per window
+	Reason      string    `json:"reason"`
+	UpdatedBy   uint      `json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Receipt is the itemized record of a merchant payment, issued once per transaction
+type Receipt struct {
+	ID               uint          `gorm:"primarykey" json:"id"`
+	TransactionID    uint          `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	Number           string        `gorm:"not null;uniqueIndex" json:"number"`
+	MerchantID       uint          `gorm:"not null;index" json:"merchant_id"` // Merchant's user ID
+	CustomerID       uint          `gorm:"not null;index" json:"customer_id"`
+	MerchantName     string        `json:"merchant_name"`
+	MerchantAddress  string        `json:"merchant_address,omitempty"`
+	MerchantCategory string        `json:"merchant_category,omitempty"`
+	Currency         string        `gorm:"default:'USD'" json:"currency"`
+	Subtotal         float64       `json:"subtotal"`
+	TaxAmount        float64       `json:"tax_amount"`
+	Tip              float64       `json:"tip"`
+	Fee              float64       `json:"fee"`
+	Total            float64       `json:"total"`
+	PaymentMethod    string        `json:"payment_method"`
+	Items            []ReceiptItem `gorm:"constraint:OnDelete:CASCADE" json:"items"`
+	EmailedTo        string        `json:"emailed_to,omitempty"`
+	EmailedAt        *time.Time    `json:"emailed_at,omitempty"`
+	IssuedAt         time.Time     `json:"issued_at"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// ReceiptItem is a single purchased item on a receipt
+type ReceiptItem struct {
+	ID          uint    `gorm:"primarykey" json:"id"`
+	ReceiptID   uint    `gorm:"not null;index" json:"-"`
+	Description string  `gorm:"not null" json:"description"`
+	Quantity    float64 `gorm:"not null" json:"quantity"`
+	UnitPrice   float64 `gorm:"not null" json:"unit_price"`
+	Amount      float64 `gorm:"not null" json:"amount"`
+}
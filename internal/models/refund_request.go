@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// RefundPolicy is how a merchant handles the refunds customers ask for
+type RefundPolicy struct {
+	MerchantUserID uint `gorm:"primarykey" json:"-"`
+	// Enabled lets customers ask for refunds themselves
+	Enabled bool `gorm:"not null;default:true" json:"enabled"`
+	// WindowDays is how many days after a charge customers can ask
+	WindowDays int `gorm:"not null;default:30" json:"window_days"`
+	// AutoApproveLimit is the amount up to which refunds asked for within
+	// the window are paid without the merchant; zero leaves every refund
+	// to the merchant
+	AutoApproveLimit float64   `gorm:"not null;default:0" json:"auto_approve_limit"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// DefaultRefundPolicy is the policy of merchants that never set one
+func DefaultRefundPolicy(merchantUserID uint) *RefundPolicy {
+	return &RefundPolicy{MerchantUserID: merchantUserID, Enabled: true, WindowDays: 30}
+}
+
+// Open reports whether customers can still ask for a refund of a charge
+// made at chargedAt
+func (p *RefundPolicy) Open(chargedAt, now time.Time) bool {
+	return p.Enabled && now.Before(chargedAt.AddDate(0, 0, p.WindowDays))
+}
+
+// AutoApproves reports whether a refund of amount is paid without the
+// merchant
+func (p *RefundPolicy) AutoApproves(amount float64) bool {
+	return amount <= p.AutoApproveLimit
+}
+
+// Refund request statuses
+const (
+	RefundRequestPending  = "pending"  // Waiting for the merchant
+	RefundRequestApproved = "approved" // Approved, the refund being paid
+	RefundRequestRefunded = "refunded"
+	RefundRequestRejected = "rejected"
+	RefundRequestCanceled = "canceled" // Withdrawn by the customer
+)
+
+// RefundRequest is a customer asking a merchant to refund all or part of a
+// charge. Requests the merchant's policy allows are refunded straight away;
+// the rest wait for the merchant to approve or reject them.
+type RefundRequest struct {
+	ID             uint    `gorm:"primarykey" json:"id"`
+	TransactionID  uint    `gorm:"not null;index" json:"transaction_id"` // The charge
+	CustomerID     uint    `gorm:"not null;index" json:"customer_id"`
+	MerchantUserID uint    `gorm:"not null;index" json:"merchant_user_id"`
+	MerchantName   string  `json:"merchant_name"`
+	Amount         float64 `gorm:"not null" json:"amount"`
+	Currency       string  `json:"currency"`
+	Reason         string  `json:"reason,omitempty"`
+	Status         string  `gorm:"size:20;not null;index" json:"status"`
+	AutoApproved   bool    `gorm:"not null;default:false" json:"auto_approved"`
+	// DecidedBy is the merchant user who approved or rejected the request,
+	// unset when the policy approved it
+	DecidedBy           *uint      `json:"decided_by,omitempty"`
+	DecisionNote        string     `json:"decision_note,omitempty"`
+	DecidedAt           *time.Time `json:"decided_at,omitempty"`
+	RefundTransactionID *uint      `json:"refund_transaction_id,omitempty"`
+	RefundedAt          *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Role is a named set of permissions a user can hold. System roles are the
+// built-in ones; their permissions come from GetDefaultPermissions and
+// can't be edited. Admins create custom roles such as "support_agent".
+type Role struct {
+	ID          uint         `gorm:"primarykey" json:"id"`
+	Name        string       `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description string       `json:"description"`
+	System      bool         `gorm:"not null;default:false" json:"system"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// PermissionNames returns the names of the role's permissions
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Permission is something a role allows, such as "wallet:read"
+type Permission struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Name        string `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description string `json:"description"`
+}
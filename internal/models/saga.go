@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Saga statuses
+const (
+	SagaRunning      = "running"
+	SagaCompleted    = "completed"
+	SagaCompensating = "compensating" // A step failed; the ones before it are being undone
+	SagaCompensated  = "compensated"
+	// SagaRequiresAttention means compensation kept failing and someone
+	// has to put the money back by hand
+	SagaRequiresAttention = "requires_attention"
+)
+
+// Saga step statuses
+const (
+	SagaStepPending     = "pending"
+	SagaStepCompleted   = "completed"
+	SagaStepFailed      = "failed"
+	SagaStepCompensated = "compensated"
+)
+
+// TransactionStatusRequiresAttention marks a transaction whose saga could
+// not undo the steps it had already taken
+const TransactionStatusRequiresAttention = "requires_attention"
+
+// Saga records a multi-step payment, such as debiting a customer and
+// crediting a merchant, so that when a step fails the steps before it
+// are undone even if the instance running it goes away.
+type Saga struct {
+	ID            uint   `gorm:"primarykey" json:"id"`
+	Name          string `gorm:"size:50;not null" json:"name"`
+	TransactionID *uint  `gorm:"index" json:"transaction_id,omitempty"`
+	Status        string `gorm:"size:20;not null;default:'running';index" json:"status"`
+	Error         string `json:"error,omitempty"` // Why the failed step failed
+	// Attempts counts the runs of compensation; NextAttemptAt is when the
+	// job tries again
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LeaseUntil    *time.Time `json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Steps         []SagaStep `gorm:"foreignKey:SagaID" json:"steps,omitempty"`
+}
+
+// SagaStep is one step of a saga. Compensation names the registered
+// handler that undoes it, called with Params; it is empty when the step
+// has nothing to undo.
+type SagaStep struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	SagaID        uint       `gorm:"not null;index" json:"-"`
+	Position      int        `gorm:"not null" json:"position"`
+	Name          string     `gorm:"size:50;not null" json:"name"`
+	Status        string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Compensation  string     `gorm:"size:50" json:"compensation,omitempty"`
+	Params        JSON       `gorm:"type:jsonb" json:"params,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CompensatedAt *time.Time `json:"compensated_at,omitempty"`
+}
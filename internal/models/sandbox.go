@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Sandbox charge sources
+const (
+	SandboxSourceWallet = "wallet" // A sandbox customer's simulated balance
+	SandboxSourceCard   = "card"   // The simulated card network
+	SandboxSourceBank   = "bank"   // The simulated bank provider
+)
+
+// Sandbox charge and refund statuses
+const (
+	SandboxStatusPending           = "pending"
+	SandboxStatusSucceeded         = "succeeded"
+	SandboxStatusFailed            = "failed"
+	SandboxStatusPartiallyRefunded = "partially_refunded"
+	SandboxStatusRefunded          = "refunded"
+)
+
+// SandboxCustomer is a test payer with a simulated wallet balance. Sandbox
+// customers belong to one merchant and never touch real wallets.
+type SandboxCustomer struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	MerchantID uint      `gorm:"not null;index" json:"merchant_id"`
+	Name       string    `gorm:"not null" json:"name"`
+	Email      string    `json:"email,omitempty"`
+	Balance    float64   `gorm:"not null;default:0" json:"balance"`
+	Currency   string    `gorm:"default:'USD'" json:"currency"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SandboxCharge is a payment a merchant's test integration took with its
+// sandbox API key
+type SandboxCharge struct {
+	ID                uint      `gorm:"primarykey" json:"-"`
+	Reference         string    `gorm:"size:40;not null;uniqueIndex" json:"id"`
+	MerchantID        uint      `gorm:"not null;index:,composite:merchant_created" json:"merchant_id"`
+	CustomerID        *uint     `json:"customer_id,omitempty"`
+	Amount            float64   `gorm:"not null" json:"amount"`
+	RefundedAmount    float64   `gorm:"not null;default:0" json:"refunded_amount"`
+	Currency          string    `gorm:"default:'USD'" json:"currency"`
+	Source            string    `gorm:"size:10;not null" json:"source"`
+	CardLast4         string    `gorm:"size:4" json:"card_last4,omitempty"`
+	Status            string    `gorm:"size:20;not null;index" json:"status"`
+	FailureCode       string    `gorm:"size:40" json:"failure_code,omitempty"`
+	FailureMessage    string    `json:"failure_message,omitempty"`
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Metadata          JSON      `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedAt         time.Time `gorm:"index:,composite:merchant_created" json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SandboxRefund returns part or all of a sandbox charge
+type SandboxRefund struct {
+	ID          uint      `gorm:"primarykey" json:"-"`
+	Reference   string    `gorm:"size:40;not null;uniqueIndex" json:"id"`
+	MerchantID  uint      `gorm:"not null;index" json:"merchant_id"`
+	ChargeID    uint      `gorm:"not null;index" json:"-"`
+	Charge      string    `gorm:"-" json:"charge"`
+	Amount      float64   `gorm:"not null" json:"amount"`
+	Status      string    `gorm:"size:20;not null" json:"status"`
+	FailureCode string    `gorm:"size:40" json:"failure_code,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
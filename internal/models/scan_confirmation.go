@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ScanConfirmationSettings is whether the user approves the charges
+// merchants take by scanning their payment code QR, above an amount that
+// goes through without asking
+type ScanConfirmationSettings struct {
+	UserID  uint `gorm:"primarykey" json:"-"`
+	Enabled bool `gorm:"not null;default:false" json:"enabled"`
+	// AutoApproveLimit is the amount up to which charges complete without
+	// asking; zero asks for every charge
+	AutoApproveLimit float64   `gorm:"not null;default:0" json:"auto_approve_limit"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Requires reports whether a charge of amount waits for the user
+func (s *ScanConfirmationSettings) Requires(amount float64) bool {
+	return s.Enabled && amount > s.AutoApproveLimit
+}
+
+// Scan confirmation statuses
+const (
+	ScanConfirmationPending  = "pending"
+	ScanConfirmationApproved = "approved"
+	ScanConfirmationDeclined = "declined"
+	ScanConfirmationExpired  = "expired"
+	ScanConfirmationFailed   = "failed" // Approved, but the charge couldn't complete
+)
+
+// ScanConfirmation is a merchant's charge of a scanned payment code waiting
+// for the payer to approve or decline it before ExpiresAt. No money moves
+// until it is approved, when the charge is taken as scanned.
+type ScanConfirmation struct {
+	ID             uint    `gorm:"primarykey" json:"id"`
+	UserID         uint    `gorm:"not null;index" json:"user_id"`
+	MerchantUserID uint    `gorm:"not null;index" json:"merchant_user_id"`
+	MerchantName   string  `json:"merchant_name"`
+	Amount         float64 `gorm:"not null" json:"amount"`
+	Description    string  `json:"description,omitempty"`
+	// Charge is the merchant's charge request, taken once approved
+	Charge        JSON       `gorm:"type:jsonb" json:"-"`
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	ExpiresAt     time.Time  `gorm:"not null;index" json:"expires_at"`
+	TransactionID *uint      `json:"transaction_id,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
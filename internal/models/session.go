@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Session is one sign-in on one device. Its tokens carry its ID, so it
+// can be ended alone; bumping the user's token version ends them all.
+type Session struct {
+	ID        string `gorm:"primarykey;size:32" json:"id"`
+	UserID    uint   `gorm:"not null;index" json:"user_id"`
+	IP        string `gorm:"size:50" json:"ip"`
+	Country   string `gorm:"size:2" json:"country,omitempty"`
+	DeviceID  string `gorm:"size:64" json:"device_id"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// TokenVersion is the user's token version when the session began;
+	// once the user's moves on, the session is over
+	TokenVersion int        `gorm:"not null" json:"-"`
+	LastUsedAt   time.Time  `json:"last_used_at"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Current marks the session the listing was requested from
+	Current bool `gorm:"-" json:"current"`
+}
+
+// IsActive reports whether the session can still be used by a user whose
+// token version is tokenVersion
+func (s *Session) IsActive(tokenVersion int, now time.Time) bool {
+	return s.RevokedAt == nil && s.TokenVersion == tokenVersion && now.Before(s.ExpiresAt)
+}
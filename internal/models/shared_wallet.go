@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Shared wallet member roles
+const (
+	WalletRoleOwner   = "owner"   // Manages members, approves payments, spends without limits
+	WalletRoleSpender = "spender" // Adds funds and pays within their limits
+	WalletRoleViewer  = "viewer"  // Sees the balance and activity only
+)
+
+// Shared wallet payment statuses
+const (
+	SharedPaymentPendingApproval = "pending_approval"
+	SharedPaymentCompleted       = "completed"
+	SharedPaymentRejected        = "rejected"
+)
+
+// IsValidWalletRole reports whether role is a shared wallet member role
+func IsValidWalletRole(role string) bool {
+	return role == WalletRoleOwner || role == WalletRoleSpender || role == WalletRoleViewer
+}
+
+// SharedWallet is a wallet owned jointly by several users, such as a family
+// or a team. Its balance is kept apart from the members' personal wallets.
+type SharedWallet struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	Name              string    `gorm:"not null" json:"name"`
+	Balance           float64   `gorm:"not null;default:0" json:"balance"`
+	Currency          string    `gorm:"default:'USD'" json:"currency"`
+	Status            string    `gorm:"default:'active'" json:"status"`
+	ApprovalThreshold float64   `gorm:"default:0" json:"approval_threshold"` // Non-owner payments above this need an owner; 0 disables
+	CreatedBy         uint      `gorm:"not null" json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// WalletMember grants a user a role on a shared wallet
+type WalletMember struct {
+	ID                  uint      `gorm:"primarykey" json:"id"`
+	SharedWalletID      uint      `gorm:"not null;uniqueIndex:idx_wallet_member" json:"shared_wallet_id"`
+	UserID              uint      `gorm:"not null;uniqueIndex:idx_wallet_member;index" json:"user_id"`
+	Role                string    `gorm:"size:20;not null" json:"role"`
+	PerTransactionLimit float64   `gorm:"default:0" json:"per_transaction_limit"` // 0 means no limit
+	DailyLimit          float64   `gorm:"default:0" json:"daily_limit"`           // 0 means no limit
+	AddedBy             uint      `json:"added_by"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// SharedWalletPayment is a payment out of a shared wallet, held for owner
+// approval when it is over the wallet's approval threshold
+type SharedWalletPayment struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	SharedWalletID uint       `gorm:"not null;index" json:"shared_wallet_id"`
+	RequestedBy    uint       `gorm:"not null;index" json:"requested_by"`
+	RecipientID    uint       `gorm:"not null" json:"recipient_id"`
+	Amount         float64    `gorm:"not null" json:"amount"`
+	Description    string     `json:"description"`
+	Status         string     `gorm:"size:20;not null;index" json:"status"`
+	ReviewedBy     *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	TransactionID  *uint      `json:"transaction_id,omitempty"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
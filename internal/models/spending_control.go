@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Spending control scopes
+const (
+	SpendingScopeCategory = "category" // Payments to merchants of one category
+	SpendingScopeForeign  = "foreign"  // Payments to merchants in another country
+)
+
+// Spending control actions
+const (
+	SpendingActionBlock = "block"
+	SpendingActionCap   = "cap"
+)
+
+// Spending cap periods
+const (
+	SpendingPeriodDaily   = "daily"
+	SpendingPeriodMonthly = "monthly"
+)
+
+// Merchant categories a spending control can target. They are matched
+// against the merchant's business type.
+const (
+	MerchantCategoryGambling = "gambling"
+	MerchantCategoryCrypto   = "crypto"
+	MerchantCategoryAdult    = "adult"
+	MerchantCategoryAlcohol  = "alcohol"
+	MerchantCategoryTobacco  = "tobacco"
+)
+
+// ControlledCategories are the merchant categories spending can be blocked
+// or capped at
+var ControlledCategories = map[string]bool{
+	MerchantCategoryGambling: true,
+	MerchantCategoryCrypto:   true,
+	MerchantCategoryAdult:    true,
+	MerchantCategoryAlcohol:  true,
+	MerchantCategoryTobacco:  true,
+}
+
+// SpendingControl blocks or caps a user's payments to a merchant category
+// or to foreign merchants. Users set their own; an organization's owners
+// and admins set them for its members, with EnterpriseID recording which
+// organization so the member can't lift them.
+type SpendingControl struct {
+	ID           uint   `gorm:"primarykey" json:"id"`
+	UserID       uint   `gorm:"not null;uniqueIndex:idx_spending_control" json:"user_id"`
+	EnterpriseID uint   `gorm:"not null;default:0;uniqueIndex:idx_spending_control" json:"enterprise_id,omitempty"`
+	Scope        string `gorm:"size:20;not null;uniqueIndex:idx_spending_control" json:"scope"`
+	// Category is one of ControlledCategories for the category scope and
+	// empty for foreign
+	Category  string    `gorm:"size:30;not null;default:'';uniqueIndex:idx_spending_control" json:"category,omitempty"`
+	Action    string    `gorm:"size:10;not null" json:"action"`
+	CapAmount float64   `gorm:"default:0" json:"cap_amount,omitempty"`
+	Period    string    `gorm:"size:10" json:"period,omitempty"`
+	SetBy     uint      `json:"set_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsManaged reports whether an organization set the control for its member
+func (c *SpendingControl) IsManaged() bool {
+	return c.EnterpriseID != 0
+}
+
+// MerchantCountry is the merchant's country from their profile address,
+// falling back to the country on their account
+func MerchantCountry(merchant *Merchant, user *User) string {
+	if address, ok := merchant.Metadata.Map()["address"].(map[string]interface{}); ok {
+		if country, ok := address["country"].(string); ok {
+			if code, ok := NormalizeCountryCode(country); ok {
+				return code
+			}
+		}
+	}
+	if user != nil {
+		if code, ok := NormalizeCountryCode(user.Country); ok {
+			return code
+		}
+	}
+	return ""
+}
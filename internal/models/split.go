@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Split statuses
+const (
+	SplitStatusOpen          = "open"
+	SplitStatusPartiallyPaid = "partially_paid"
+	SplitStatusCompleted     = "completed"
+	SplitStatusCancelled     = "cancelled"
+)
+
+// Split share statuses
+const (
+	SplitShareStatusPending = "pending"
+	SplitShareStatusPaid    = "paid"
+)
+
+// Split divides a bill between an organizer and other users. Each participant
+// pays their share straight into the organizer's wallet.
+type Split struct {
+	ID              uint         `gorm:"primarykey" json:"id"`
+	OrganizerID     uint         `gorm:"not null;index" json:"organizer_id"`
+	TransactionID   *uint        `gorm:"uniqueIndex" json:"transaction_id,omitempty"` // The bill being split, if it was paid through Orus
+	Title           string       `gorm:"not null" json:"title"`
+	Currency        string       `gorm:"default:'USD'" json:"currency"`
+	TotalAmount     float64      `gorm:"not null" json:"total_amount"`
+	OrganizerShare  float64      `gorm:"not null;default:0" json:"organizer_share"`
+	CollectedAmount float64      `gorm:"not null;default:0" json:"collected_amount"`
+	ShareCount      int          `gorm:"not null" json:"share_count"`
+	PaidCount       int          `gorm:"not null;default:0" json:"paid_count"`
+	Status          string       `gorm:"not null;default:'open';index" json:"status"`
+	CompletedAt     *time.Time   `json:"completed_at,omitempty"`
+	Shares          []SplitShare `gorm:"constraint:OnDelete:CASCADE" json:"shares"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// OutstandingAmount is what participants still owe the organizer
+func (s *Split) OutstandingAmount() float64 {
+	return s.TotalAmount - s.OrganizerShare - s.CollectedAmount
+}
+
+// IsPayable reports whether participants can still pay into the split
+func (s *Split) IsPayable() bool {
+	return s.Status == SplitStatusOpen || s.Status == SplitStatusPartiallyPaid
+}
+
+// SplitShare is one participant's part of a split
+type SplitShare struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	SplitID        uint       `gorm:"not null;uniqueIndex:idx_split_share_user" json:"split_id"`
+	UserID         uint       `gorm:"not null;uniqueIndex:idx_split_share_user;index" json:"user_id"`
+	Amount         float64    `gorm:"not null" json:"amount"`
+	Status         string     `gorm:"not null;default:'pending';index" json:"status"`
+	TransactionID  *uint      `json:"transaction_id,omitempty"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	LastReminderAt *time.Time `json:"last_reminder_at,omitempty"`
+	ReminderCount  int        `gorm:"default:0" json:"reminder_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
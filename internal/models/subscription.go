@@ -0,0 +1,133 @@
+package models
+
+import "time"
+
+// Billing intervals for subscription plans
+const (
+	SubscriptionIntervalDay   = "day"
+	SubscriptionIntervalWeek  = "week"
+	SubscriptionIntervalMonth = "month"
+	SubscriptionIntervalYear  = "year"
+)
+
+// Subscription plan statuses. Archived plans keep billing existing
+// subscribers but accept no new ones.
+const (
+	SubscriptionPlanStatusActive   = "active"
+	SubscriptionPlanStatusArchived = "archived"
+)
+
+// Subscription statuses. A subscription whose payment fails goes past_due
+// and is retried; it is canceled once the retries run out.
+const (
+	SubscriptionStatusIncomplete = "incomplete" // Waiting on the first payment
+	SubscriptionStatusTrialing   = "trialing"
+	SubscriptionStatusActive     = "active"
+	SubscriptionStatusPastDue    = "past_due"
+	SubscriptionStatusPaused     = "paused"
+	SubscriptionStatusCanceled   = "canceled"
+)
+
+// Subscription charge outcomes
+const (
+	SubscriptionChargeSucceeded = "succeeded"
+	SubscriptionChargeFailed    = "failed"
+)
+
+// SubscriptionPlan is a recurring price a merchant offers. Pricing can't
+// change once created, since customers consented to it.
+type SubscriptionPlan struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	MerchantID    uint      `gorm:"not null;index" json:"merchant_id"` // Merchant's user ID
+	Name          string    `gorm:"size:100;not null" json:"name"`
+	Description   string    `json:"description,omitempty"`
+	Amount        float64   `gorm:"not null" json:"amount"`
+	Currency      string    `gorm:"default:'USD'" json:"currency"`
+	Interval      string    `gorm:"size:10;not null" json:"interval"`
+	IntervalCount int       `gorm:"not null;default:1" json:"interval_count"`
+	TrialDays     int       `gorm:"default:0" json:"trial_days"`
+	Status        string    `gorm:"size:20;not null;default:'active';index" json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Subscription is a customer's standing authorization to be charged for a
+// plan. The price and interval are copied from the plan at signup.
+type Subscription struct {
+	ID                 uint       `gorm:"primarykey" json:"id"`
+	PlanID             uint       `gorm:"not null;index" json:"plan_id"`
+	MerchantID         uint       `gorm:"not null;index" json:"merchant_id"` // Merchant's user ID
+	CustomerID         uint       `gorm:"not null;index" json:"customer_id"`
+	Amount             float64    `gorm:"not null" json:"amount"`
+	Currency           string     `gorm:"default:'USD'" json:"currency"`
+	Interval           string     `gorm:"size:10;not null" json:"interval"`
+	IntervalCount      int        `gorm:"not null;default:1" json:"interval_count"`
+	Status             string     `gorm:"size:20;not null;index" json:"status"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
+	NextBillingAt      *time.Time `gorm:"index" json:"next_billing_at,omitempty"` // Nil while paused or once canceled
+	FailedAttempts     int        `gorm:"default:0" json:"failed_attempts"`
+	LastChargeAt       *time.Time `json:"last_charge_at,omitempty"`
+	PausedAt           *time.Time `json:"paused_at,omitempty"`
+	CanceledAt         *time.Time `json:"canceled_at,omitempty"`
+	CanceledBy         *uint      `json:"canceled_by,omitempty"`
+	CancelReason       string     `json:"cancel_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// IsBillable reports whether the subscription is still being charged
+func (s *Subscription) IsBillable() bool {
+	switch s.Status {
+	case SubscriptionStatusTrialing, SubscriptionStatusActive, SubscriptionStatusPastDue:
+		return true
+	}
+	return false
+}
+
+// SubscriptionConsent records the customer's authorization of recurring
+// charges: exactly what they agreed to, when, and from where
+type SubscriptionConsent struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	SubscriptionID uint       `gorm:"not null;uniqueIndex" json:"subscription_id"`
+	CustomerID     uint       `gorm:"not null;index" json:"customer_id"`
+	MerchantID     uint       `gorm:"not null" json:"merchant_id"`
+	PlanID         uint       `gorm:"not null" json:"plan_id"`
+	Amount         float64    `gorm:"not null" json:"amount"`
+	Currency       string     `json:"currency"`
+	Interval       string     `gorm:"size:10" json:"interval"`
+	IntervalCount  int        `json:"interval_count"`
+	Text           string     `gorm:"not null" json:"text"`
+	IPAddress      string     `gorm:"size:45" json:"ip_address"`
+	UserAgent      string     `json:"user_agent"`
+	AcceptedAt     time.Time  `json:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+}
+
+// SubscriptionCharge is one attempt to bill a subscription period
+type SubscriptionCharge struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	SubscriptionID uint      `gorm:"not null;index" json:"subscription_id"`
+	Amount         float64   `gorm:"not null" json:"amount"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	Attempt        int       `gorm:"not null" json:"attempt"`
+	Status         string    `gorm:"size:20;not null" json:"status"`
+	TransactionID  *uint     `json:"transaction_id,omitempty"`
+	FailureReason  string    `json:"failure_reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AddInterval advances t by count billing intervals
+func AddInterval(t time.Time, interval string, count int) time.Time {
+	switch interval {
+	case SubscriptionIntervalDay:
+		return t.AddDate(0, 0, count)
+	case SubscriptionIntervalWeek:
+		return t.AddDate(0, 0, 7*count)
+	case SubscriptionIntervalYear:
+		return t.AddDate(count, 0, 0)
+	default:
+		return t.AddDate(0, count, 0)
+	}
+}
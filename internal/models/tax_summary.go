@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Tax summary report statuses
+const (
+	TaxSummaryPending   = "pending" // Waiting for the job to pick it up
+	TaxSummaryRunning   = "running"
+	TaxSummaryCompleted = "completed"
+	TaxSummaryFailed    = "failed"
+)
+
+// TaxSummaryReport is a tax year summary generated in the background for
+// an account with too many transactions to summarize during a request.
+// The finished file is kept for download.
+type TaxSummaryReport struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	UserID   uint   `gorm:"not null;index" json:"-"`
+	Year     int    `gorm:"not null" json:"year"`
+	Format   string `gorm:"size:10;not null" json:"format"`
+	Language string `gorm:"size:10" json:"-"` // Language the file is written in
+	Status   string `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	FileName string `json:"file_name,omitempty"`
+	Content  []byte `json:"-"`
+	Error    string `json:"error,omitempty"`
+	// LeaseUntil is when a running report's instance is presumed gone and
+	// another may pick it up
+	LeaseUntil  *time.Time `json:"-"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
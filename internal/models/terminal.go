@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Terminal statuses
+const (
+	TerminalStatusPending     = "pending" // Registered, waiting for the device to pair
+	TerminalStatusActive      = "active"
+	TerminalStatusDeactivated = "deactivated"
+)
+
+// Terminal is a merchant's point-of-sale device. A device pairs once with
+// a short-lived code and then authenticates with its own key and secret.
+type Terminal struct {
+	ID                 uint       `gorm:"primarykey" json:"id"`
+	MerchantID         uint       `gorm:"not null;index" json:"merchant_id"`
+	MerchantUserID     uint       `gorm:"not null;index" json:"merchant_user_id"`
+	Name               string     `gorm:"size:50;not null" json:"name"`
+	Status             string     `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	PairingCodeHash    string     `gorm:"size:64;index" json:"-"`
+	PairingExpiresAt   *time.Time `json:"pairing_expires_at,omitempty"`
+	CredentialKey      *string    `gorm:"size:40;uniqueIndex" json:"credential_key,omitempty"`
+	SecretHash         string     `gorm:"size:64" json:"-"`
+	DeviceModel        string     `json:"device_model,omitempty"`
+	DeviceSerial       string     `json:"device_serial,omitempty"`
+	PairedAt           *time.Time `json:"paired_at,omitempty"`
+	LastSeenAt         *time.Time `json:"last_seen_at,omitempty"`
+	DeactivatedAt      *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy      *uint      `json:"deactivated_by,omitempty"`
+	DeactivationReason string     `json:"deactivation_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"orus/internal/validation/metadata"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Transaction types
@@ -15,14 +18,28 @@ const (
 	TransactionTypeP2PTransfer    = "P2P_TRANSFER"
 	TransactionTypeTransfer       = "transfer"
 	TransactionTypeQRCode         = "QR_PAYMENT"
+	TransactionTypeCardPayment    = "card_payment"
+	TransactionTypePotTransfer    = "pot_transfer"
+	TransactionTypeEscrow         = "escrow"
+	TransactionTypeChargeback     = "chargeback"
+	TransactionTypePromotion      = "promotion"
+	TransactionTypeReversal       = "reversal"
 )
 
+// TransactionStatusExpired marks a transaction left pending past the
+// configured TTL and expired by the cleanup job
+const TransactionStatusExpired = "expired"
+
+// TransactionStatusAwaitingConfirmation marks a large transfer held until
+// the sender approves it on a trusted device
+const TransactionStatusAwaitingConfirmation = "awaiting_confirmation"
+
 // Consolidated Transaction model
 type Transaction struct {
 	ID               uint    `gorm:"primarykey"`
 	Type             string  `gorm:"not null"`
-	SenderID         uint    `gorm:"not null"`
-	ReceiverID       uint    `gorm:"not null"`
+	SenderID         uint    `gorm:"not null;index:,composite:sender_created"`
+	ReceiverID       uint    `gorm:"not null;index:,composite:receiver_created"`
 	Amount           float64 `gorm:"not null"`
 	Description      string
 	Status           string  `gorm:"not null;default:'pending'"`
@@ -36,11 +53,73 @@ type Transaction struct {
 	MerchantID       *uint   // Optional merchant reference
 	MerchantName     string  // Merchant business name
 	MerchantCategory string  // Merchant business type
+	OperatorID       *uint   `gorm:"index"` // Merchant staff member who took the payment
+	TerminalID       *uint   `gorm:"index"` // Point-of-sale terminal the payment was taken on
 	CardID           *uint   // Optional card reference
+	VirtualCardID    *uint   `gorm:"index"` // Optional issued virtual card reference
 	QRCodeID         *string // Optional QR code reference
 	Category         string  `gorm:"type:varchar(50)"`
-	ProcessedAt      time.Time
-	UpdatedAt        time.Time
+	// CategorySource says what chose Category, one of the CategorySource
+	// constants; empty on transactions from before categorization
+	CategorySource string `gorm:"type:varchar(10);default:''"`
+	// Conversions between currencies record the exact rate applied, the
+	// provider it came from and what was credited in the other currency;
+	// the fields stay empty on transactions in a single currency
+	FXRate            float64 `gorm:"type:decimal(20,8);default:0"`
+	FXProvider        string  `gorm:"type:varchar(20);default:''"`
+	ConvertedAmount   float64 `gorm:"default:0"`
+	ConvertedCurrency string  `gorm:"type:varchar(3);default:''"`
+	ProcessedAt       time.Time
+	// CreatedAt is the partition key of the transactions table
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index:,composite:sender_created;index:,composite:receiver_created"`
+	UpdatedAt time.Time
+}
+
+// BeforeCreate validates metadata against the schema for the transaction type
+// and stamps the schema version so consumers can rely on its structure.
+func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
+	validated, err := metadata.Validate(t.Type, t.Metadata.Map())
+	if err != nil {
+		return err
+	}
+	t.Metadata = NewJSON(validated)
+	return nil
+}
+
+// AfterCreate books platform fees to the fee revenue system account within
+// the same database transaction, so a fee is never taken from a customer
+// without being recorded as platform revenue.
+func (t *Transaction) AfterCreate(tx *gorm.DB) error {
+	if t.Status != "completed" {
+		return nil
+	}
+	fee := t.Fee
+	if t.Type == "fee" {
+		fee = t.Amount
+	}
+	if fee <= 0 {
+		return nil
+	}
+	return PostSystemEntry(tx.Session(&gorm.Session{NewDB: true}), SystemAccountFeeRevenue, &SystemLedgerEntry{
+		Kind:          SystemEntryFee,
+		Amount:        fee,
+		TransactionID: &t.ID,
+		Description:   t.Description,
+	})
+}
+
+// IsConversion reports whether the transaction moved money between currencies
+func (t *Transaction) IsConversion() bool {
+	return t.ConvertedCurrency != ""
+}
+
+// CreditedAmount is what the receiving side got: Amount, or the converted
+// amount on conversions
+func (t *Transaction) CreditedAmount() float64 {
+	if t.IsConversion() {
+		return t.ConvertedAmount
+	}
+	return t.Amount
 }
 
 type Location struct {
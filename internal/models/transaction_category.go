@@ -0,0 +1,105 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Spending categories transactions are sorted into
+const (
+	CategoryGroceries     = "Groceries"
+	CategoryDining        = "Dining"
+	CategoryTransport     = "Transport"
+	CategoryTravel        = "Travel"
+	CategoryShopping      = "Shopping"
+	CategoryBills         = "Bills & Utilities"
+	CategoryEntertainment = "Entertainment"
+	CategoryHealth        = "Health"
+	CategoryEducation     = "Education"
+	CategoryTransfers     = "Transfers"
+	CategoryCash          = "Cash & Withdrawals"
+	CategoryOther         = "Other"
+
+	// CategoryUncategorized is what dashboards show for a transaction
+	// without a category
+	CategoryUncategorized = "Uncategorized"
+)
+
+// TransactionCategories lists the categories users can choose from
+var TransactionCategories = []string{
+	CategoryGroceries,
+	CategoryDining,
+	CategoryTransport,
+	CategoryTravel,
+	CategoryShopping,
+	CategoryBills,
+	CategoryEntertainment,
+	CategoryHealth,
+	CategoryEducation,
+	CategoryTransfers,
+	CategoryCash,
+	CategoryOther,
+}
+
+// What chose a transaction's category
+const (
+	CategorySourceUser    = "user"    // The sender re-categorized it
+	CategorySourceRule    = "rule"    // One of the sender's rules
+	CategorySourceHistory = "history" // What the sender chose for the same payee before
+	CategorySourceMCC     = "mcc"     // The card network's merchant category code
+	CategorySourceKeyword = "keyword" // The merchant's business type, name or description
+	CategorySourceType    = "type"    // The transaction type
+	CategorySourceSystem  = "system"  // Not spending; the label it was created with stands
+)
+
+// CategoryRule files a user's future payments to a payee under a category.
+// It matches payments to ReceiverID when set, and otherwise payments whose
+// merchant name or description contains Pattern.
+type CategoryRule struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	ReceiverID uint      `gorm:"default:0" json:"receiver_id,omitempty"`
+	Pattern    string    `gorm:"size:100" json:"pattern,omitempty"` // Lowercase
+	Category   string    `gorm:"size:50;not null" json:"category"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Matches reports whether the rule applies to the transaction
+func (r *CategoryRule) Matches(tx *Transaction) bool {
+	if r.ReceiverID != 0 {
+		return tx.ReceiverID == r.ReceiverID
+	}
+	if r.Pattern == "" {
+		return false
+	}
+	payee := strings.ToLower(tx.MerchantName + " " + tx.Description)
+	return strings.Contains(payee, r.Pattern)
+}
+
+// IsValidCategory reports whether users can file transactions under category
+func IsValidCategory(category string) bool {
+	return slices.Contains(TransactionCategories, category)
+}
+
+// CountsAsSpending reports whether the transaction shows in its sender's
+// spending breakdown: not a top-up, refund or move between their own
+// balances
+func (t *Transaction) CountsAsSpending() bool {
+	if t.SenderID == 0 || t.SenderID == t.ReceiverID {
+		return false
+	}
+	if strings.EqualFold(t.Type, "top_up") || t.Type == TransactionTypeTopup {
+		return false
+	}
+	return t.Type != TransactionTypeRefund
+}
+
+// SpendingCategory is the key the transaction is counted under in its
+// sender's spending breakdown
+func (t *Transaction) SpendingCategory() string {
+	if t.Category == "" {
+		return CategoryUncategorized
+	}
+	return t.Category
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Transaction reversal actors
+const (
+	ReversalActorAdmin    = "admin"
+	ReversalActorMerchant = "merchant"
+)
+
+// TransactionReversal records who reversed a completed transaction and
+// why, linking it to the compensating transaction that undid it. A
+// transaction can only be reversed once.
+type TransactionReversal struct {
+	ID                    uint      `gorm:"primarykey" json:"id"`
+	OriginalTransactionID uint      `gorm:"not null;uniqueIndex" json:"original_transaction_id"`
+	ReversalTransactionID uint      `gorm:"not null;index" json:"reversal_transaction_id"`
+	Amount                float64   `gorm:"not null" json:"amount"`
+	FeeReturned           float64   `gorm:"not null;default:0" json:"fee_returned"`
+	Reason                string    `gorm:"not null" json:"reason"`
+	ReversedBy            uint      `gorm:"not null;index" json:"reversed_by"`
+	Actor                 string    `gorm:"size:20;not null" json:"actor"`
+	OperatorID            *uint     `json:"operator_id,omitempty"` // Merchant staff member who reversed it
+	CreatedAt             time.Time `json:"created_at"`
+}
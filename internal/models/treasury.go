@@ -0,0 +1,171 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// System account codes. These are the platform's own ledger accounts, kept
+// apart from customer wallets so platform money is never counted as theirs.
+const (
+	SystemAccountFeeRevenue        = "fee_revenue"
+	SystemAccountFXSpread          = "fx_spread"
+	SystemAccountPromotionsExpense = "promotions_expense"
+	SystemAccountEscheatment       = "escheatment"
+	SystemAccountEscrow            = "escrow"
+)
+
+// System ledger entry kinds
+const (
+	SystemEntryFee         = "fee"
+	SystemEntryTransferIn  = "transfer_in"
+	SystemEntryTransferOut = "transfer_out"
+	SystemEntryEscrowIn    = "escrow_in"
+	SystemEntryEscrowOut   = "escrow_out"
+	SystemEntryPromotion   = "promotion"
+)
+
+// Treasury transfer statuses
+const (
+	TreasuryTransferPending  = "pending"
+	TreasuryTransferExecuted = "executed"
+	TreasuryTransferRejected = "rejected"
+)
+
+// SystemAccountDefinition describes one of the fixed system accounts
+type SystemAccountDefinition struct {
+	Code string
+	Name string
+	Type string // revenue, expense or liability
+	// Custodial accounts hold customer money on their way between wallets.
+	// They count towards customer funds and treasury transfers can't touch them.
+	Custodial bool
+}
+
+// SystemAccountDefinitions lists every system account the platform keeps
+var SystemAccountDefinitions = []SystemAccountDefinition{
+	{Code: SystemAccountFeeRevenue, Name: "Fee revenue", Type: "revenue"},
+	{Code: SystemAccountFXSpread, Name: "FX spread", Type: "revenue"},
+	{Code: SystemAccountPromotionsExpense, Name: "Promotions expense", Type: "expense"},
+	{Code: SystemAccountEscheatment, Name: "Escheatment", Type: "liability"},
+	{Code: SystemAccountEscrow, Name: "Escrow holdings", Type: "liability", Custodial: true},
+}
+
+// IsSystemAccountCode reports whether code names a known system account
+func IsSystemAccountCode(code string) bool {
+	for _, def := range SystemAccountDefinitions {
+		if def.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCustodialAccount reports whether code names a custodial system account
+func IsCustodialAccount(code string) bool {
+	for _, def := range SystemAccountDefinitions {
+		if def.Code == code {
+			return def.Custodial
+		}
+	}
+	return false
+}
+
+// SystemAccount is an internal platform ledger account
+type SystemAccount struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Code      string    `gorm:"size:50;not null;uniqueIndex" json:"code"`
+	Name      string    `gorm:"not null" json:"name"`
+	Type      string    `gorm:"size:20;not null" json:"type"`
+	Balance   float64   `gorm:"not null;default:0" json:"balance"`
+	Currency  string    `gorm:"default:'USD'" json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SystemLedgerEntry is one posting to a system account. Amount is positive
+// for credits and negative for debits.
+type SystemLedgerEntry struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	AccountID     uint      `gorm:"not null;index" json:"account_id"`
+	Kind          string    `gorm:"size:20;not null;index" json:"kind"`
+	Amount        float64   `gorm:"not null" json:"amount"`
+	BalanceAfter  float64   `gorm:"not null" json:"balance_after"`
+	TransactionID *uint     `gorm:"index" json:"transaction_id,omitempty"` // Customer transaction that earned it
+	TransferID    *uint     `gorm:"index" json:"transfer_id,omitempty"`    // Treasury transfer that moved it
+	Description   string    `json:"description"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// TreasuryTransfer moves funds between system accounts. It is requested by
+// one super-admin and only executed once a different super-admin approves it.
+type TreasuryTransfer struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	FromAccountCode string     `gorm:"size:50;not null" json:"from_account"`
+	ToAccountCode   string     `gorm:"size:50;not null" json:"to_account"`
+	Amount          float64    `gorm:"not null" json:"amount"`
+	Reason          string     `gorm:"not null" json:"reason"`
+	Status          string     `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	RequestedBy     uint       `gorm:"not null" json:"requested_by"`
+	ReviewedBy      *uint      `json:"reviewed_by,omitempty"`
+	ReviewNote      string     `json:"review_note,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// PostSystemEntry adjusts a system account's balance and records the entry
+// using db, so the posting commits or rolls back with the caller's
+// transaction. The account row stays locked until then.
+func PostSystemEntry(db *gorm.DB, code string, entry *SystemLedgerEntry) error {
+	account, err := lockSystemAccount(db, code)
+	if err != nil {
+		return err
+	}
+
+	entry.Amount = math.Round(entry.Amount*100) / 100
+	account.Balance = math.Round((account.Balance+entry.Amount)*100) / 100
+	if err := db.Model(account).Update("balance", account.Balance).Error; err != nil {
+		return fmt.Errorf("failed to update system account %s: %w", code, err)
+	}
+
+	entry.AccountID = account.ID
+	entry.BalanceAfter = account.Balance
+	if err := db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record system ledger entry: %w", err)
+	}
+	return nil
+}
+
+// lockSystemAccount loads the account for update, creating it on first use
+func lockSystemAccount(db *gorm.DB, code string) (*SystemAccount, error) {
+	var def *SystemAccountDefinition
+	for i := range SystemAccountDefinitions {
+		if SystemAccountDefinitions[i].Code == code {
+			def = &SystemAccountDefinitions[i]
+		}
+	}
+	if def == nil {
+		return nil, fmt.Errorf("unknown system account %q", code)
+	}
+
+	var account SystemAccount
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).
+			Create(&SystemAccount{Code: def.Code, Name: def.Name, Type: def.Type}).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to create system account %s: %w", code, err)
+		}
+		err = db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&account).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock system account %s: %w", code, err)
+	}
+	return &account, nil
+}
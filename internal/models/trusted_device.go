@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// TrustedDevice is a phone or browser the user approves large transfers
+// on. It is told apart by the device ID it logged in with.
+type TrustedDevice struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	UserID   uint   `gorm:"not null;uniqueIndex:idx_trusted_device" json:"user_id"`
+	DeviceID string `gorm:"size:64;not null;uniqueIndex:idx_trusted_device" json:"device_id"`
+	Name     string `gorm:"size:100" json:"name"`
+	// PushToken addresses approval requests to the device
+	PushToken  string     `json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Transfer confirmation statuses
+const (
+	TransferConfirmationPending  = "pending"
+	TransferConfirmationApproved = "approved"
+	TransferConfirmationDenied   = "denied"
+	TransferConfirmationExpired  = "expired"
+	TransferConfirmationFailed   = "failed" // Approved, but the transfer couldn't complete
+)
+
+// TransferConfirmation is a large transfer waiting for its sender to
+// approve or deny it on a trusted device before ExpiresAt. The transfer
+// stays awaiting confirmation until then.
+type TransferConfirmation struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	TransactionID uint      `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	Status        string    `gorm:"size:20;not null;index" json:"status"`
+	ExpiresAt     time.Time `gorm:"not null;index" json:"expires_at"`
+	// DeviceID is the trusted device that approved or denied the transfer
+	DeviceID      string       `gorm:"size:64" json:"device_id,omitempty"`
+	DecidedAt     *time.Time   `json:"decided_at,omitempty"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	Transaction   *Transaction `gorm:"foreignKey:TransactionID" json:"transaction,omitempty"`
+}
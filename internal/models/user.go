@@ -6,18 +6,30 @@ import (
 	"gorm.io/gorm"
 )
 
+// User statuses
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended" // Blocked by an admin from logging in and paying
+)
+
 type User struct {
 	gorm.Model
 	Email                 string  `gorm:"uniqueIndex;not null"` // Unique index on Email
 	Password              string  `gorm:"not null"`
 	Name                  string  `gorm:"not null"`
-	Phone                 string  `gorm:"uniqueIndex;not null"` // Unique index on Phone
+	Phone                 string  `gorm:"uniqueIndex;not null"` // Unique when set; empty for social sign-ups
+	Handle                *string `gorm:"uniqueIndex;size:30"`  // Lowercase payment @handle, without the @
+	HandleDiscoverable    bool    `gorm:"default:true"`         // Others can find the user by handle
+	PhoneDiscoverable     bool    `gorm:"default:true"`         // Others can find the user by phone number
+	Country               string  `gorm:"size:2"`               // ISO 3166-1 alpha-2, optional
 	UserType              string  `gorm:"default:'regular'"`
 	Role                  string  `gorm:"default:'user'"`
 	WalletID              *uint   `gorm:"unique;default:null"` // Make it a pointer to allow NULL
 	Wallet                *Wallet `gorm:"foreignKey:WalletID"`
 	Status                string  `gorm:"default:'active'"`
-	KYCStatus             string  `gorm:"default:'pending'"`
+	SuspendedAt           *time.Time
+	SuspensionReason      string
+	KYCStatus             string `gorm:"default:'pending'"`
 	LastLoginAt           time.Time
 	LastLoginIP           string
 	TwoFactorEnabled      bool `gorm:"default:false"`
@@ -25,7 +37,6 @@ type User struct {
 	AccountLockoutUntil   *time.Time
 	TokenVersion          int       `gorm:"default:1"`
 	MerchantProfileStatus string    `gorm:"default:'not_applicable'"`
-	Balance               float64   `gorm:"default:0"`
 	LastActiveAt          time.Time `gorm:"index"`
 }
 
@@ -36,11 +47,18 @@ type CreateUserInput struct {
 	Phone    string `json:"phone"`
 	Password string `json:"password"`
 	Role     string `json:"role"`
+	Country  string `json:"country"`
+}
+
+// IsSuspended reports whether an admin has suspended the account
+func (u *User) IsSuspended() bool {
+	return u.Status == UserStatusSuspended
 }
 
 // UpdateUserInput represents the data needed to update a user
 type UpdateUserInput struct {
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
-	Email string `json:"email"`
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	Country string `json:"country"`
 }
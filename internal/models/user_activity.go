@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// User activity actions
+const (
+	UserActivityLogin           = "login"
+	UserActivityLoginBlocked    = "login_blocked" // A suspended user tried to log in
+	UserActivityLogout          = "logout"
+	UserActivityProfileUpdated  = "profile_updated"
+	UserActivityRoleChanged     = "role_changed"
+	UserActivitySuspended       = "suspended"
+	UserActivityUnsuspended     = "unsuspended"
+	UserActivitySessionsRevoked = "sessions_revoked"
+	UserActivityOverdraftSet    = "overdraft_set"
+	UserActivityLoginChallenged = "login_challenged" // A login from a new country or device was sent a code
+	UserActivityLockedByUser    = "locked_by_user"   // The user reported a login that wasn't them
+)
+
+// UserActivity records an account event: the user's own sign-ins and the
+// changes admins made to the account. ActorID is the admin who acted, or
+// nil when the user did.
+type UserActivity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index:,composite:user_created" json:"user_id"`
+	ActorID   *uint     `json:"actor_id,omitempty"`
+	Action    string    `gorm:"size:30;not null" json:"action"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `gorm:"index:,composite:user_created" json:"created_at"`
+}
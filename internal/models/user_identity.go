@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Social login providers
+const (
+	IdentityProviderGoogle = "google"
+	IdentityProviderApple  = "apple"
+)
+
+// UserIdentity links a user to their account at a social login provider,
+// which is told apart by the provider's subject identifier. Emails can
+// change at the provider; the subject never does.
+type UserIdentity struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	UserID   uint   `gorm:"not null;index" json:"user_id"`
+	Provider string `gorm:"size:20;not null;uniqueIndex:idx_user_identity" json:"provider"`
+	Subject  string `gorm:"size:255;not null;uniqueIndex:idx_user_identity" json:"-"`
+	// Email is the address the provider last vouched for
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Virtual card types
+const (
+	VirtualCardTypeSingleUse  = "single_use"
+	VirtualCardTypeReloadable = "reloadable"
+)
+
+// Virtual card statuses
+const (
+	VirtualCardStatusActive = "active"
+	VirtualCardStatusFrozen = "frozen"
+	VirtualCardStatusClosed = "closed"
+)
+
+// Spend limit intervals for virtual cards
+const (
+	SpendLimitPerTransaction = "per_transaction"
+	SpendLimitDaily          = "daily"
+	SpendLimitMonthly        = "monthly"
+	SpendLimitLifetime       = "lifetime"
+)
+
+// VirtualCard is an issued card number that spends from the owner's wallet.
+// The full card number and CVV are only returned at issuance and never stored.
+type VirtualCard struct {
+	ID                 uint       `gorm:"primarykey" json:"id"`
+	UserID             uint       `gorm:"not null;index" json:"user_id"`
+	WalletID           uint       `gorm:"not null;index" json:"wallet_id"`
+	Provider           string     `gorm:"not null" json:"provider"`
+	ProviderCardID     string     `gorm:"not null;uniqueIndex" json:"-"`
+	Type               string     `gorm:"not null" json:"type"`
+	Status             string     `gorm:"not null;default:'active'" json:"status"`
+	Nickname           string     `json:"nickname"`
+	Brand              string     `json:"brand"`
+	LastFour           string     `gorm:"type:varchar(4)" json:"last_four"`
+	ExpiryMonth        int        `json:"expiry_month"`
+	ExpiryYear         int        `json:"expiry_year"`
+	Currency           string     `gorm:"default:'USD'" json:"currency"`
+	SpendLimit         float64    `gorm:"default:0" json:"spend_limit"` // 0 means no card-level limit
+	SpendLimitInterval string     `json:"spend_limit_interval,omitempty"`
+	FrozenAt           *time.Time `json:"frozen_at,omitempty"`
+	ClosedAt           *time.Time `json:"closed_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// IsExpired reports whether the card is past its expiry month
+func (c *VirtualCard) IsExpired(now time.Time) bool {
+	expiry := time.Date(c.ExpiryYear, time.Month(c.ExpiryMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.Before(expiry)
+}
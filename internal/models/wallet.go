@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,8 +14,48 @@ type Wallet struct {
 	Currency     string  `gorm:"default:'USD'"`
 	Status       string  `gorm:"default:'active'"`
 	StatusReason string  `gorm:"default:''"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// LockScope and LockedUntil describe the active lock of a locked
+	// wallet; see WalletLock
+	LockScope   string `gorm:"size:10;default:''"`
+	LockedUntil *time.Time
+	// OverdraftLimit is how far below zero the balance may go; the default
+	// of 0 allows no overdraft
+	OverdraftLimit float64 `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	// Version is bumped by the database on every balance or status change;
+	// cached copies with a lower version are stale
+	Version int64 `gorm:"not null;default:0"`
+}
+
+// Overdrawn reports whether the balance is below what the wallet's
+// overdraft limit allows
+func (w *Wallet) Overdrawn() bool {
+	return math.Round((w.Balance+w.OverdraftLimit)*100) < 0
+}
+
+// LockBlocks reports whether the wallet's status stops money moving in
+// direction at now. A lock past its expiry blocks nothing, even before the
+// expiry job lifts it; locks from before scopes existed block everything.
+func (w *Wallet) LockBlocks(direction string, now time.Time) bool {
+	switch w.Status {
+	case "active":
+		return false
+	case "locked":
+		if w.LockedUntil != nil && !now.Before(*w.LockedUntil) {
+			return false
+		}
+		switch w.LockScope {
+		case WalletLockDebits:
+			return direction == WalletDirectionDebit
+		case WalletLockCredits:
+			return direction == WalletDirectionCredit
+		default:
+			return true
+		}
+	default:
+		return true
+	}
 }
 
 func (w *Wallet) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Wallet hold types
+const (
+	HoldTypeAuthorization     = "authorization"
+	HoldTypePendingWithdrawal = "pending_withdrawal"
+	HoldTypePendingDebit      = "pending_debit"
+	HoldTypeEscrow            = "escrow"
+	HoldTypeChargebackReserve = "chargeback_reserve"
+	HoldTypeRollingReserve    = "rolling_reserve"
+)
+
+// Wallet hold statuses
+const (
+	HoldStatusActive   = "active"
+	HoldStatusReleased = "released"
+	HoldStatusCaptured = "captured"
+)
+
+// WalletHold reserves part of a wallet balance so it can't be spent
+// while a withdrawal, escrow or other pending debit is in flight.
+type WalletHold struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	WalletID   uint       `gorm:"not null;index" json:"wallet_id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Amount     float64    `gorm:"not null" json:"amount"`
+	Type       string     `gorm:"not null" json:"type"`
+	Status     string     `gorm:"not null;default:'active';index" json:"status"`
+	Reason     string     `json:"reason"`
+	Reference  string     `gorm:"index" json:"reference"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsActive reports whether the hold still reserves funds at the given time.
+func (h *WalletHold) IsActive(now time.Time) bool {
+	if h.Status != HoldStatusActive {
+		return false
+	}
+	return h.ExpiresAt == nil || h.ExpiresAt.After(now)
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Wallet lock scopes
+const (
+	WalletLockDebits  = "debits"  // Money can come in but not go out
+	WalletLockCredits = "credits" // Money can go out but not come in
+	WalletLockFull    = "full"
+)
+
+// Wallet lock reasons
+const (
+	WalletLockReasonFraud         = "fraud"
+	WalletLockReasonLegal         = "legal"
+	WalletLockReasonUserRequested = "user_requested"
+)
+
+// Directions money moves through a wallet, as checked against its lock
+const (
+	WalletDirectionDebit  = "debit"
+	WalletDirectionCredit = "credit"
+)
+
+// Wallet lock events
+const (
+	WalletLockEventLocked   = "locked"
+	WalletLockEventReleased = "released"
+	WalletLockEventExpired  = "expired"
+	WalletLockEventRefused  = "refused" // An operation the lock blocked
+)
+
+// WalletLock is a lock placed on a wallet. The wallet carries the active
+// lock's scope and expiry so every operation can check it without a join;
+// these rows keep who locked it, why and when it was lifted.
+type WalletLock struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	WalletID   uint       `gorm:"not null;index" json:"wallet_id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Scope      string     `gorm:"size:10;not null" json:"scope"`
+	Reason     string     `gorm:"size:20;not null" json:"reason"`
+	Note       string     `json:"note,omitempty"`
+	LockedBy   *uint      `json:"locked_by,omitempty"` // Admin who placed it; nil when the user did
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	ReleasedBy *uint      `json:"released_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the lock has not been lifted
+func (l *WalletLock) IsActive() bool {
+	return l.ReleasedAt == nil
+}
+
+// WalletLockEvent audits a wallet's locks: each lock placed and lifted,
+// and each operation a lock refused
+type WalletLockEvent struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	WalletID  uint      `gorm:"not null;index:,composite:wallet_created" json:"wallet_id"`
+	LockID    *uint     `json:"lock_id,omitempty"`
+	Event     string    `gorm:"size:20;not null" json:"event"`
+	Operation string    `gorm:"size:30" json:"operation,omitempty"`
+	Direction string    `gorm:"size:10" json:"direction,omitempty"`
+	Amount    float64   `json:"amount,omitempty"`
+	ActorID   *uint     `json:"actor_id,omitempty"`
+	CreatedAt time.Time `gorm:"index:,composite:wallet_created" json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusFailed    = "failed" // Gave up after the last retry
+)
+
+// WebhookDelivery is an event queued for a merchant's webhook endpoint.
+// Payload is the exact body that is signed and sent.
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	EventID        string     `gorm:"size:40;not null;uniqueIndex" json:"event_id"`
+	MerchantUserID uint       `gorm:"not null;index" json:"merchant_user_id"`
+	Event          string     `gorm:"size:50;not null" json:"event"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	Sandbox        bool       `gorm:"not null;default:false" json:"sandbox"` // Sent for sandbox activity
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
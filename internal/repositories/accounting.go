@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrAccountingExportBusy = errors.New("this day's journal is already being exported")
+
+// accountingFundingTypes are the transactions moving money between the
+// banks or card processors and customer wallets
+var (
+	accountingFundingIn  = []string{"top_up", models.TransactionTypeTopup}
+	accountingFundingOut = []string{"withdrawal", models.TransactionTypeWithdrawal}
+)
+
+// AccountingLedgerEntry is a system ledger posting with the account it
+// was posted to
+type AccountingLedgerEntry struct {
+	ID            uint
+	AccountCode   string
+	Currency      string
+	Kind          string
+	Amount        float64
+	TransactionID *uint
+	TransferID    *uint
+	Description   string
+	CreatedAt     time.Time
+}
+
+// AccountingRepository reads the ledger the accounting journals are built
+// from and records the exports
+type AccountingRepository interface {
+	// GetLedgerEntries returns the system ledger postings in [from, to)
+	GetLedgerEntries(from, to time.Time) ([]AccountingLedgerEntry, error)
+	// GetFundingTransactions returns the completed top-ups and withdrawals
+	// created in [from, to)
+	GetFundingTransactions(from, to time.Time) (topUps, withdrawals []models.Transaction, err error)
+
+	// Claim starts exporting the day in format. Without force it only
+	// claims a day not yet exported, or whose last attempt failed fewer
+	// than maxAttempts times; with force it re-exports a finished day.
+	// Attempts running since before staleBefore are taken over either way.
+	Claim(date time.Time, format string, requestedBy *uint, force bool, maxAttempts int, staleBefore time.Time) (*models.AccountingExport, error)
+	Finish(export *models.AccountingExport) error
+	List(format string, limit, offset int) ([]models.AccountingExport, int64, error)
+}
+
+type accountingRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountingRepository(db *gorm.DB) AccountingRepository {
+	return &accountingRepository{db: db}
+}
+
+func (r *accountingRepository) GetLedgerEntries(from, to time.Time) ([]AccountingLedgerEntry, error) {
+	var entries []AccountingLedgerEntry
+	err := r.db.Table("system_ledger_entries AS e").
+		Select("e.id, a.code AS account_code, a.currency, e.kind, e.amount, e.transaction_id, e.transfer_id, e.description, e.created_at").
+		Joins("JOIN system_accounts a ON a.id = e.account_id").
+		Where("e.created_at >= ? AND e.created_at < ?", from, to).
+		Order("e.created_at, e.id").
+		Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *accountingRepository) GetFundingTransactions(from, to time.Time) ([]models.Transaction, []models.Transaction, error) {
+	find := func(types []string) ([]models.Transaction, error) {
+		var transactions []models.Transaction
+		err := r.db.Where("created_at >= ? AND created_at < ?", from, to).
+			Where("status = ? AND type IN ?", "completed", types).
+			Order("created_at, id").
+			Find(&transactions).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding transactions: %w", err)
+		}
+		return transactions, nil
+	}
+
+	topUps, err := find(accountingFundingIn)
+	if err != nil {
+		return nil, nil, err
+	}
+	withdrawals, err := find(accountingFundingOut)
+	if err != nil {
+		return nil, nil, err
+	}
+	return topUps, withdrawals, nil
+}
+
+func (r *accountingRepository) Claim(date time.Time, format string, requestedBy *uint, force bool, maxAttempts int, staleBefore time.Time) (*models.AccountingExport, error) {
+	claimable := `(accounting_exports.status = @failed AND accounting_exports.attempts < @max_attempts)`
+	if force {
+		claimable = `accounting_exports.status <> @running`
+	}
+	var exports []models.AccountingExport
+	err := r.db.Raw(`INSERT INTO accounting_exports (date, format, status, attempts, requested_by, created_at, updated_at)
+		VALUES (@date, @format, @running, 1, @requested_by, @now, @now)
+		ON CONFLICT (date, format) DO UPDATE
+		SET status = @running, attempts = accounting_exports.attempts + 1, requested_by = @requested_by,
+			error = '', updated_at = @now
+		WHERE `+claimable+`
+			OR (accounting_exports.status = @running AND accounting_exports.updated_at < @stale)
+		RETURNING *`,
+		map[string]interface{}{
+			"date":         date.Format(time.DateOnly),
+			"format":       format,
+			"running":      models.AccountingExportRunning,
+			"failed":       models.AccountingExportFailed,
+			"requested_by": requestedBy,
+			"max_attempts": maxAttempts,
+			"stale":        staleBefore,
+			"now":          time.Now(),
+		}).Scan(&exports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim accounting export: %w", err)
+	}
+	if len(exports) == 0 {
+		return nil, ErrAccountingExportBusy
+	}
+	return &exports[0], nil
+}
+
+func (r *accountingRepository) Finish(export *models.AccountingExport) error {
+	err := r.db.Model(export).Select("status", "entries", "debits", "credits", "file_name", "location", "error", "completed_at").
+		Updates(export).Error
+	if err != nil {
+		return fmt.Errorf("failed to finish accounting export: %w", err)
+	}
+	return nil
+}
+
+func (r *accountingRepository) List(format string, limit, offset int) ([]models.AccountingExport, int64, error) {
+	query := r.db.Model(&models.AccountingExport{})
+	if format != "" {
+		query = query.Where("format = ?", format)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count accounting exports: %w", err)
+	}
+
+	var exports []models.AccountingExport
+	if err := query.Order("date DESC, format").Limit(limit).Offset(offset).Find(&exports).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list accounting exports: %w", err)
+	}
+	return exports, total, nil
+}
@@ -0,0 +1,281 @@
+package repositories
+
+import (
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// occurredAt is when a transaction happened. It is the partition key, so
+// bounding it lets Postgres skip the months outside the range.
+const occurredAt = "created_at"
+
+// AnalyticsFilter selects the completed transactions an analytics query
+// covers
+type AnalyticsFilter struct {
+	UserID uint
+	// Merchant limits the query to payments the user received, with the
+	// payers as counterparties. Otherwise both directions count.
+	Merchant    bool
+	From, To    time.Time // From inclusive, To exclusive
+	Granularity string    // Period length: day, week or month
+	Timezone    string    // IANA zone periods are cut in
+}
+
+// AnalyticsBucket aggregates the transactions of one period. Period is the
+// period's start as wall-clock time in the filter's timezone.
+type AnalyticsBucket struct {
+	Period         time.Time
+	Count          int64
+	Volume         float64
+	Counterparties int64
+}
+
+// QRCodeBucket aggregates the payments made through one QR code
+type QRCodeBucket struct {
+	QRCodeID   uint
+	Type       string
+	Label      string
+	Location   string
+	TerminalID *uint
+	Count      int64
+	Volume     float64
+}
+
+// AnalyticsRepository runs parameterized aggregates over transactions
+type AnalyticsRepository interface {
+	// Series returns one bucket per period that had transactions, in order
+	Series(filter AnalyticsFilter) ([]AnalyticsBucket, error)
+	// Totals aggregates the whole range, counting each counterparty once
+	Totals(filter AnalyticsFilter) (*AnalyticsBucket, error)
+	// CountBy counts transactions by payment method for merchants and by
+	// type for everyone else
+	CountBy(filter AnalyticsFilter) (map[string]int64, error)
+	// ByQRCode aggregates the payments made by scanning a QR code, per
+	// code, largest volume first
+	ByQRCode(filter AnalyticsFilter) ([]QRCodeBucket, error)
+
+	// ListCustomers returns the merchant's customers who paid in the range
+	ListCustomers(filter CustomerFilter, limit, offset int) ([]CustomerStats, int64, error)
+	SummarizeCustomers(filter CustomerFilter) (*CustomerSummary, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) Series(filter AnalyticsFilter) ([]AnalyticsBucket, error) {
+	period := fmt.Sprintf("date_trunc(?, %s AT TIME ZONE ?)", occurredAt)
+	rows, err := r.scope(filter).
+		Select(fmt.Sprintf("%s AS period, COUNT(*), COALESCE(SUM(amount), 0), COUNT(DISTINCT %s)",
+			period, counterparty(filter)),
+			filter.Granularity, filter.Timezone).
+		Group("period").
+		Order("period").
+		Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analytics series: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []AnalyticsBucket
+	for rows.Next() {
+		var b AnalyticsBucket
+		if err := rows.Scan(&b.Period, &b.Count, &b.Volume, &b.Counterparties); err != nil {
+			return nil, fmt.Errorf("failed to read analytics series: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+func (r *analyticsRepository) Totals(filter AnalyticsFilter) (*AnalyticsBucket, error) {
+	var b AnalyticsBucket
+	err := r.scope(filter).
+		Select(fmt.Sprintf("COUNT(*), COALESCE(SUM(amount), 0), COUNT(DISTINCT %s)", counterparty(filter))).
+		Row().Scan(&b.Count, &b.Volume, &b.Counterparties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analytics totals: %w", err)
+	}
+	return &b, nil
+}
+
+func (r *analyticsRepository) CountBy(filter AnalyticsFilter) (map[string]int64, error) {
+	column := "type"
+	if filter.Merchant {
+		column = "COALESCE(NULLIF(payment_method, ''), 'unknown')"
+	}
+	var results []struct {
+		Key   string
+		Count int64
+	}
+	err := r.scope(filter).
+		Select(column + " AS key, COUNT(*) AS count").
+		Group("key").
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analytics breakdown: %w", err)
+	}
+
+	counts := make(map[string]int64, len(results))
+	for _, result := range results {
+		counts[result.Key] = result.Count
+	}
+	return counts, nil
+}
+
+func (r *analyticsRepository) ByQRCode(filter AnalyticsFilter) ([]QRCodeBucket, error) {
+	// Aggregate first so the join can't make the shared column names ambiguous
+	totals := r.scope(filter).
+		Select("qr_code_id, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS volume").
+		Where("qr_code_id IS NOT NULL").
+		Group("qr_code_id")
+
+	var buckets []QRCodeBucket
+	err := r.db.Scopes(Replica(filter.UserID)).Table("(?) AS t", totals).
+		Select("qr_codes.id AS qr_code_id, qr_codes.type, qr_codes.label, qr_codes.location, qr_codes.terminal_id, t.count, t.volume").
+		Joins("JOIN qr_codes ON qr_codes.code = t.qr_code_id").
+		Order("t.volume DESC").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analytics by QR code: %w", err)
+	}
+	return buckets, nil
+}
+
+func (r *analyticsRepository) scope(filter AnalyticsFilter) *gorm.DB {
+	query := r.db.Scopes(Replica(filter.UserID)).Model(&models.Transaction{}).
+		Where("status = ?", "completed").
+		Where(occurredAt+" >= ? AND "+occurredAt+" < ?", filter.From, filter.To)
+	if filter.Merchant {
+		return query.Where("receiver_id = ? AND sender_id <> receiver_id", filter.UserID)
+	}
+	return query.Where("sender_id = ? OR receiver_id = ?", filter.UserID, filter.UserID)
+}
+
+// counterparty is the SQL for the other side of a transaction in the
+// filter's view. Top-ups and moves between a user's own balances have none.
+func counterparty(filter AnalyticsFilter) string {
+	if filter.Merchant {
+		return "sender_id"
+	}
+	return fmt.Sprintf("NULLIF(NULLIF(CASE WHEN sender_id = %[1]d THEN receiver_id ELSE sender_id END, 0), %[1]d)", filter.UserID)
+}
+
+// CustomerFilter selects a merchant's customers who paid in a date range
+type CustomerFilter struct {
+	MerchantID uint      // Merchant's user ID
+	From, To   time.Time // From inclusive, To exclusive
+	SortBy     string    // volume, payments or last_payment_at; all descending
+}
+
+// CustomerStats is what one customer spent with a merchant, in the range
+// and over all time
+type CustomerStats struct {
+	CustomerID       uint
+	Name             string
+	FirstPaymentAt   time.Time
+	LastPaymentAt    time.Time
+	Payments         int64
+	Volume           float64
+	LifetimePayments int64
+	LifetimeVolume   float64
+}
+
+// CustomerSummary aggregates a merchant's customers over a range, compared
+// with the equally long period just before it
+type CustomerSummary struct {
+	Customers         int64
+	NewCustomers      int64 // First ever payment fell in the range
+	RepeatCustomers   int64 // Paid more than once in the range
+	Payments          int64
+	Volume            float64
+	PreviousCustomers int64 // Paid in the previous period
+	LapsedCustomers   int64 // Paid in the previous period but not in the range
+}
+
+// customerPayments lists a merchant's completed incoming payments, leaving
+// out moves between the merchant's own balances
+const customerPayments = `WITH payments AS (
+	SELECT sender_id, amount, ` + occurredAt + ` AS at
+	FROM transactions
+	WHERE receiver_id = @merchant AND status = 'completed' AND sender_id <> receiver_id
+)`
+
+var customerSorts = map[string]string{
+	"volume":          "volume DESC",
+	"payments":        "payments DESC",
+	"last_payment_at": "last_payment_at DESC",
+}
+
+func (r *analyticsRepository) ListCustomers(filter CustomerFilter, limit, offset int) ([]CustomerStats, int64, error) {
+	order, ok := customerSorts[filter.SortBy]
+	if !ok {
+		order = customerSorts["volume"]
+	}
+	customers := customerPayments + `, customers AS (
+		SELECT sender_id AS customer_id,
+			MIN(at) AS first_payment_at, MAX(at) AS last_payment_at,
+			COUNT(*) FILTER (WHERE at >= @from AND at < @to) AS payments,
+			COALESCE(SUM(amount) FILTER (WHERE at >= @from AND at < @to), 0) AS volume,
+			COUNT(*) AS lifetime_payments, COALESCE(SUM(amount), 0) AS lifetime_volume
+		FROM payments
+		GROUP BY sender_id
+	)`
+	args := map[string]interface{}{
+		"merchant": filter.MerchantID, "from": filter.From, "to": filter.To,
+		"limit": limit, "offset": offset,
+	}
+
+	var total int64
+	err := r.db.Raw(customers+` SELECT COUNT(*) FROM customers WHERE payments > 0`, args).Row().Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count customers: %w", err)
+	}
+
+	var stats []CustomerStats
+	err = r.db.Raw(customers+` SELECT c.*, COALESCE(u.name, '') AS name
+		FROM customers c LEFT JOIN users u ON u.id = c.customer_id
+		WHERE c.payments > 0
+		ORDER BY `+order+`, c.customer_id
+		LIMIT @limit OFFSET @offset`, args).Scan(&stats).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+	}
+	return stats, total, nil
+}
+
+func (r *analyticsRepository) SummarizeCustomers(filter CustomerFilter) (*CustomerSummary, error) {
+	previous := filter.From.Add(-filter.To.Sub(filter.From))
+	var s CustomerSummary
+	err := r.db.Raw(customerPayments+`, customers AS (
+		SELECT sender_id, MIN(at) AS first_at,
+			COUNT(*) FILTER (WHERE at >= @from AND at < @to) AS payments,
+			COALESCE(SUM(amount) FILTER (WHERE at >= @from AND at < @to), 0) AS volume,
+			COUNT(*) FILTER (WHERE at >= @previous AND at < @from) AS previous_payments
+		FROM payments
+		GROUP BY sender_id
+	)
+	SELECT
+		COUNT(*) FILTER (WHERE payments > 0),
+		COUNT(*) FILTER (WHERE payments > 0 AND first_at >= @from),
+		COUNT(*) FILTER (WHERE payments > 1),
+		COALESCE(SUM(payments), 0),
+		COALESCE(SUM(volume), 0),
+		COUNT(*) FILTER (WHERE previous_payments > 0),
+		COUNT(*) FILTER (WHERE previous_payments > 0 AND payments = 0)
+	FROM customers`,
+		map[string]interface{}{"merchant": filter.MerchantID, "from": filter.From, "to": filter.To, "previous": previous}).
+		Row().Scan(&s.Customers, &s.NewCustomers, &s.RepeatCustomers, &s.Payments, &s.Volume,
+		&s.PreviousCustomers, &s.LapsedCustomers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize customers: %w", err)
+	}
+	return &s, nil
+}
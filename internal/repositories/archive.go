@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"fmt"
+	"orus/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// archivableStatuses are the settled states a transaction can be archived
+// in. Anything still in flight stays hot whatever its age.
+var archivableStatuses = []string{"completed", "failed", "refunded", "reversed", "cancelled", "declined", models.TransactionStatusExpired}
+
+// TransactionArchiveRepository moves old transactions between the hot
+// table and the archive
+type TransactionArchiveRepository interface {
+	// Archive moves up to limit settled transactions that happened before
+	// cutoff into the archive and returns how many it moved. Completed
+	// transactions wait until the dashboard projections have counted them.
+	Archive(cutoff time.Time, limit int) (int64, error)
+	// GetUserHistory pages through a user's transactions across the hot
+	// table and the archive
+	GetUserHistory(userID uint, limit, offset int) ([]models.Transaction, int64, error)
+}
+
+type transactionArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionArchiveRepository(db *gorm.DB) TransactionArchiveRepository {
+	return &transactionArchiveRepository{db: db}
+}
+
+func (r *transactionArchiveRepository) Archive(cutoff time.Time, limit int) (int64, error) {
+	columns, err := transactionColumns(r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	// Delete and insert in one statement so a row is never in both tables
+	// or neither
+	result := r.db.Exec(`WITH moved AS (
+		DELETE FROM transactions WHERE id IN (
+			SELECT id FROM transactions
+			WHERE `+occurredAt+` < @cutoff AND status IN @statuses
+				AND (status <> 'completed' OR id IN (SELECT transaction_id FROM projected_transactions))
+			ORDER BY id
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	)
+	INSERT INTO archived_transactions (`+columns+`, archived_at)
+	SELECT `+columns+`, NOW() FROM moved`,
+		map[string]interface{}{"cutoff": cutoff, "statuses": archivableStatuses, "limit": limit})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to archive transactions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *transactionArchiveRepository) GetUserHistory(userID uint, limit, offset int) ([]models.Transaction, int64, error) {
+	columns, err := transactionColumns(r.db)
+	if err != nil {
+		return nil, 0, err
+	}
+	args := map[string]interface{}{"user": userID, "limit": limit, "offset": offset}
+
+	var total int64
+	err = r.db.Raw(`SELECT
+		(SELECT COUNT(*) FROM transactions WHERE sender_id = @user OR receiver_id = @user) +
+		(SELECT COUNT(*) FROM archived_transactions WHERE sender_id = @user OR receiver_id = @user)`, args).
+		Row().Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count transaction history: %w", err)
+	}
+
+	var transactions []models.Transaction
+	err = r.db.Raw(`SELECT `+columns+` FROM transactions WHERE sender_id = @user OR receiver_id = @user
+		UNION ALL
+		SELECT `+columns+` FROM archived_transactions WHERE sender_id = @user OR receiver_id = @user
+		ORDER BY transaction_id DESC
+		LIMIT @limit OFFSET @offset`, args).
+		Scan(&transactions).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	return transactions, total, nil
+}
+
+// transactionColumns lists the transaction table's columns, quoted, in the
+// order both tables share
+func transactionColumns(db *gorm.DB) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&models.Transaction{}); err != nil {
+		return "", fmt.Errorf("failed to read transaction schema: %w", err)
+	}
+	quoted := make([]string, len(stmt.Schema.DBNames))
+	for i, name := range stmt.Schema.DBNames {
+		quoted[i] = `"` + name + `"`
+	}
+	return strings.Join(quoted, ", "), nil
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAutoTopUpRuleNotFound = errors.New("auto top-up rule not found")
+	ErrAutoTopUpAlreadyRun   = errors.New("payment already evaluated for auto top-up")
+)
+
+// autoTopUpCredits are the transaction types a user sends that add to
+// their balance rather than take from it
+var autoTopUpCredits = []string{"top_up", models.TransactionTypeTopup}
+
+// AutoTopUpRepository stores users' auto top-up rules and the top-ups
+// they set off
+type AutoTopUpRepository interface {
+	GetRule(userID uint) (*models.AutoTopUpRule, error)
+	// SaveRule creates the user's rule or replaces their existing one
+	SaveRule(rule *models.AutoTopUpRule) error
+	DeleteRule(userID uint) error
+	// RecordOutcome saves the rule's failure count, last failure and
+	// whether it is still enabled
+	RecordOutcome(rule *models.AutoTopUpRule) error
+
+	// GetDueRules returns enabled rules whose user has completed a payment
+	// since the rule's checkpoint
+	GetDueRules(limit int) ([]models.AutoTopUpRule, error)
+	// LatestPaymentID returns the user's latest completed payment after
+	// afterID, or 0 if there is none
+	LatestPaymentID(userID, afterID uint) (uint, error)
+	SetCheckpoint(ruleID, transactionID uint) error
+	LatestTransactionID() (uint, error)
+
+	// ClaimRun records a run before the card is charged, returning
+	// ErrAutoTopUpAlreadyRun if the payment already set one off
+	ClaimRun(run *models.AutoTopUpRun) error
+	UpdateRun(run *models.AutoTopUpRun) error
+	// SumRunsSince totals the user's auto top-ups since the time, leaving
+	// out failed ones
+	SumRunsSince(userID uint, since time.Time) (float64, error)
+	ListRuns(userID uint, limit, offset int) ([]models.AutoTopUpRun, int64, error)
+}
+
+type autoTopUpRepository struct {
+	db *gorm.DB
+}
+
+func NewAutoTopUpRepository(db *gorm.DB) AutoTopUpRepository {
+	return &autoTopUpRepository{db: db}
+}
+
+func (r *autoTopUpRepository) GetRule(userID uint) (*models.AutoTopUpRule, error) {
+	var rule models.AutoTopUpRule
+	if err := r.db.Where("user_id = ?", userID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAutoTopUpRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get auto top-up rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *autoTopUpRepository) SaveRule(rule *models.AutoTopUpRule) error {
+	if err := r.db.Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to save auto top-up rule: %w", err)
+	}
+	return nil
+}
+
+func (r *autoTopUpRepository) DeleteRule(userID uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.AutoTopUpRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete auto top-up rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAutoTopUpRuleNotFound
+	}
+	return nil
+}
+
+func (r *autoTopUpRepository) RecordOutcome(rule *models.AutoTopUpRule) error {
+	err := r.db.Model(&models.AutoTopUpRule{}).Where("id = ?", rule.ID).Updates(map[string]interface{}{
+		"consecutive_failures": rule.ConsecutiveFailures,
+		"last_failure":         rule.LastFailure,
+		"enabled":              rule.Enabled,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record auto top-up outcome: %w", err)
+	}
+	return nil
+}
+
+func (r *autoTopUpRepository) GetDueRules(limit int) ([]models.AutoTopUpRule, error) {
+	var rules []models.AutoTopUpRule
+	err := r.db.Where("enabled = ?", true).
+		Where(`EXISTS (SELECT 1 FROM transactions
+			WHERE transactions.sender_id = auto_top_up_rules.user_id
+				AND transactions.id > auto_top_up_rules.checked_after_id
+				AND transactions.status = ? AND transactions.type NOT IN ?)`, "completed", autoTopUpCredits).
+		Order("id").Limit(limit).Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due auto top-up rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *autoTopUpRepository) LatestPaymentID(userID, afterID uint) (uint, error) {
+	var id uint
+	err := r.db.Model(&models.Transaction{}).
+		Where("sender_id = ? AND id > ? AND status = ? AND type NOT IN ?", userID, afterID, "completed", autoTopUpCredits).
+		Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest payment: %w", err)
+	}
+	return id, nil
+}
+
+func (r *autoTopUpRepository) SetCheckpoint(ruleID, transactionID uint) error {
+	err := r.db.Model(&models.AutoTopUpRule{}).
+		Where("id = ? AND checked_after_id < ?", ruleID, transactionID).
+		Update("checked_after_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to update auto top-up checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *autoTopUpRepository) LatestTransactionID() (uint, error) {
+	var id uint
+	if err := r.db.Model(&models.Transaction{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to get latest transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (r *autoTopUpRepository) ClaimRun(run *models.AutoTopUpRun) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	if result.Error != nil {
+		return fmt.Errorf("failed to claim auto top-up: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAutoTopUpAlreadyRun
+	}
+	return nil
+}
+
+func (r *autoTopUpRepository) UpdateRun(run *models.AutoTopUpRun) error {
+	if err := r.db.Save(run).Error; err != nil {
+		return fmt.Errorf("failed to update auto top-up: %w", err)
+	}
+	return nil
+}
+
+func (r *autoTopUpRepository) SumRunsSince(userID uint, since time.Time) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.AutoTopUpRun{}).
+		Where("user_id = ? AND created_at >= ? AND status <> ?", userID, since, models.AutoTopUpFailed).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum auto top-ups: %w", err)
+	}
+	return total, nil
+}
+
+func (r *autoTopUpRepository) ListRuns(userID uint, limit, offset int) ([]models.AutoTopUpRun, int64, error) {
+	var runs []models.AutoTopUpRun
+	var total int64
+	query := r.db.Model(&models.AutoTopUpRun{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count auto top-ups: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list auto top-ups: %w", err)
+	}
+	return runs, total, nil
+}
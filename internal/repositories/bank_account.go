@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrBankAccountNotFound = errors.New("bank account not found")
+
+// BankAccountRepository persists linked external bank accounts
+type BankAccountRepository interface {
+	Create(account *models.BankAccount) error
+	GetByID(id uint) (*models.BankAccount, error)
+	GetByIDAndUserID(id, userID uint) (*models.BankAccount, error)
+	GetByUserID(userID uint) ([]models.BankAccount, error)
+	Update(account *models.BankAccount) error
+}
+
+type bankAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewBankAccountRepository(db *gorm.DB) BankAccountRepository {
+	return &bankAccountRepository{db: db}
+}
+
+func (r *bankAccountRepository) Create(account *models.BankAccount) error {
+	if err := r.db.Create(account).Error; err != nil {
+		return fmt.Errorf("failed to create bank account: %w", err)
+	}
+	return nil
+}
+
+func (r *bankAccountRepository) GetByID(id uint) (*models.BankAccount, error) {
+	var account models.BankAccount
+	if err := r.db.First(&account, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	return &account, nil
+}
+
+func (r *bankAccountRepository) GetByIDAndUserID(id, userID uint) (*models.BankAccount, error) {
+	var account models.BankAccount
+	err := r.db.Where("id = ? AND user_id = ? AND status <> ?", id, userID, models.BankAccountStatusRemoved).
+		First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	return &account, nil
+}
+
+func (r *bankAccountRepository) GetByUserID(userID uint) ([]models.BankAccount, error) {
+	var accounts []models.BankAccount
+	err := r.db.Where("user_id = ? AND status <> ?", userID, models.BankAccountStatusRemoved).
+		Order("created_at DESC").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func (r *bankAccountRepository) Update(account *models.BankAccount) error {
+	if err := r.db.Save(account).Error; err != nil {
+		return fmt.Errorf("failed to update bank account: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrBulkOperationNotFound = errors.New("bulk operation not found")
+
+// BulkUserFilter picks users for a bulk operation; empty fields match everyone
+type BulkUserFilter struct {
+	Role          string
+	Status        string
+	Country       string
+	KYCStatus     string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// BulkQRFilter picks active QR codes for a bulk operation; empty fields
+// match every active code
+type BulkQRFilter struct {
+	UserID        uint
+	Type          string
+	CreatedBefore *time.Time
+}
+
+// BulkOperationRepository stores bulk admin operations and their items
+type BulkOperationRepository interface {
+	// Create saves the operation with a pending item for each target
+	Create(op *models.BulkOperation, targetIDs []uint) error
+	GetByID(id uint) (*models.BulkOperation, error)
+	List(limit, offset int) ([]models.BulkOperation, int64, error)
+	// Claim takes the oldest pending operation, or a running one whose
+	// lease has run out, for this instance until leaseUntil. It returns
+	// nil when there is none.
+	Claim(now, leaseUntil time.Time) (*models.BulkOperation, error)
+	ExtendLease(id uint, leaseUntil time.Time) error
+	Complete(id uint, now time.Time) error
+
+	// PendingItems returns up to limit of the operation's unprocessed items
+	PendingItems(operationID uint, limit int) ([]models.BulkOperationItem, error)
+	// RecordItem saves the item's outcome and adds it to the operation's counts
+	RecordItem(item *models.BulkOperationItem) error
+	// ListItems pages through the operation's items, optionally only those
+	// in one status
+	ListItems(operationID uint, status string, limit, offset int) ([]models.BulkOperationItem, int64, error)
+	// AllItems returns every item of the operation, for its report
+	AllItems(operationID uint) ([]models.BulkOperationItem, error)
+
+	// FindUserIDs and FindQRCodeIDs return up to limit targets matching the filter
+	FindUserIDs(filter BulkUserFilter, limit int) ([]uint, error)
+	FindQRCodeIDs(filter BulkQRFilter, limit int) ([]uint, error)
+}
+
+type bulkOperationRepository struct {
+	db *gorm.DB
+}
+
+func NewBulkOperationRepository(db *gorm.DB) BulkOperationRepository {
+	return &bulkOperationRepository{db: db}
+}
+
+// bulkItemBatch caps how many items are inserted per statement
+const bulkItemBatch = 1000
+
+func (r *bulkOperationRepository) Create(op *models.BulkOperation, targetIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(op).Error; err != nil {
+			return fmt.Errorf("failed to create bulk operation: %w", err)
+		}
+		items := make([]models.BulkOperationItem, len(targetIDs))
+		for i, id := range targetIDs {
+			items[i] = models.BulkOperationItem{OperationID: op.ID, TargetID: id, Status: models.BulkItemPending}
+		}
+		if err := tx.CreateInBatches(items, bulkItemBatch).Error; err != nil {
+			return fmt.Errorf("failed to create bulk operation items: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *bulkOperationRepository) GetByID(id uint) (*models.BulkOperation, error) {
+	var op models.BulkOperation
+	if err := r.db.First(&op, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBulkOperationNotFound
+		}
+		return nil, fmt.Errorf("failed to get bulk operation: %w", err)
+	}
+	return &op, nil
+}
+
+func (r *bulkOperationRepository) List(limit, offset int) ([]models.BulkOperation, int64, error) {
+	var ops []models.BulkOperation
+	var total int64
+	query := r.db.Model(&models.BulkOperation{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count bulk operations: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&ops).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list bulk operations: %w", err)
+	}
+	return ops, total, nil
+}
+
+func (r *bulkOperationRepository) Claim(now, leaseUntil time.Time) (*models.BulkOperation, error) {
+	var ops []models.BulkOperation
+	err := r.db.Raw(`UPDATE bulk_operations
+		SET status = @running, lease_until = @lease, started_at = COALESCE(started_at, @now), updated_at = @now
+		WHERE id = (
+			SELECT id FROM bulk_operations
+			WHERE status = @pending OR (status = @running AND lease_until < @now)
+			ORDER BY id LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		map[string]interface{}{
+			"running": models.BulkStatusRunning,
+			"pending": models.BulkStatusPending,
+			"lease":   leaseUntil,
+			"now":     now,
+		}).Scan(&ops).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim bulk operation: %w", err)
+	}
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return &ops[0], nil
+}
+
+func (r *bulkOperationRepository) ExtendLease(id uint, leaseUntil time.Time) error {
+	if err := r.db.Model(&models.BulkOperation{}).Where("id = ?", id).Update("lease_until", leaseUntil).Error; err != nil {
+		return fmt.Errorf("failed to extend bulk operation lease: %w", err)
+	}
+	return nil
+}
+
+func (r *bulkOperationRepository) Complete(id uint, now time.Time) error {
+	err := r.db.Model(&models.BulkOperation{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       models.BulkStatusCompleted,
+		"completed_at": now,
+		"lease_until":  nil,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete bulk operation: %w", err)
+	}
+	return nil
+}
+
+func (r *bulkOperationRepository) PendingItems(operationID uint, limit int) ([]models.BulkOperationItem, error) {
+	var items []models.BulkOperationItem
+	err := r.db.Where("operation_id = ? AND status = ?", operationID, models.BulkItemPending).
+		Order("id").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk operation items: %w", err)
+	}
+	return items, nil
+}
+
+func (r *bulkOperationRepository) RecordItem(item *models.BulkOperationItem) error {
+	counter := map[string]string{
+		models.BulkItemSucceeded: "succeeded",
+		models.BulkItemFailed:    "failed",
+		models.BulkItemSkipped:   "skipped",
+	}[item.Status]
+	if counter == "" {
+		return fmt.Errorf("invalid bulk operation item status %q", item.Status)
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Only a pending item counts, so an item recorded twice after a
+		// lease ran out isn't counted twice
+		result := tx.Model(&models.BulkOperationItem{}).
+			Where("id = ? AND status = ?", item.ID, models.BulkItemPending).
+			Updates(map[string]interface{}{
+				"status":       item.Status,
+				"error":        item.Error,
+				"processed_at": item.ProcessedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to record bulk operation item: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		err := tx.Model(&models.BulkOperation{}).Where("id = ?", item.OperationID).Updates(map[string]interface{}{
+			"processed": gorm.Expr("processed + 1"),
+			counter:     gorm.Expr(counter + " + 1"),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update bulk operation progress: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *bulkOperationRepository) ListItems(operationID uint, status string, limit, offset int) ([]models.BulkOperationItem, int64, error) {
+	var items []models.BulkOperationItem
+	var total int64
+	query := r.db.Model(&models.BulkOperationItem{}).Where("operation_id = ?", operationID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count bulk operation items: %w", err)
+	}
+	if err := query.Order("id").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list bulk operation items: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *bulkOperationRepository) AllItems(operationID uint) ([]models.BulkOperationItem, error) {
+	var items []models.BulkOperationItem
+	if err := r.db.Where("operation_id = ?", operationID).Order("id").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bulk operation items: %w", err)
+	}
+	return items, nil
+}
+
+func (r *bulkOperationRepository) FindUserIDs(filter BulkUserFilter, limit int) ([]uint, error) {
+	query := r.db.Model(&models.User{})
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Country != "" {
+		query = query.Where("country = ?", filter.Country)
+	}
+	if filter.KYCStatus != "" {
+		query = query.Where("kyc_status = ?", filter.KYCStatus)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	var ids []uint
+	if err := query.Order("id").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *bulkOperationRepository) FindQRCodeIDs(filter BulkQRFilter, limit int) ([]uint, error) {
+	query := r.db.Model(&models.QRCode{}).Where("status = ?", models.QRStatusActive)
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	var ids []uint
+	if err := query.Order("id").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to find QR codes: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"orus/internal/resilience"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// breakerHook fails Redis commands fast while Redis is down, so callers
+// fall back to the database at once instead of each waiting out the
+// timeouts. Replies from Redis, misses and errors included, count as
+// healthy; only connection and timeout failures trip the breaker.
+type breakerHook struct {
+	breaker *resilience.Breaker
+}
+
+func newBreakerHook() *breakerHook {
+	return &breakerHook{
+		breaker: resilience.NewBreaker("redis", resilience.BreakerConfig{
+			FailureThreshold: resilience.DefaultBreakerConfig.FailureThreshold,
+			Cooldown:         resilience.DefaultBreakerConfig.Cooldown,
+			IsFailure:        isRedisOutage,
+		}),
+	}
+}
+
+func (h *breakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *breakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err := next(ctx, cmd)
+		h.breaker.Record(err)
+		return err
+	}
+}
+
+func (h *breakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err := next(ctx, cmds)
+		h.breaker.Record(err)
+		return err
+	}
+}
+
+func isRedisOutage(err error) bool {
+	var reply redis.Error
+	return !errors.As(err, &reply) && !errors.Is(err, context.Canceled)
+}
+
+// Breaker returns the circuit breaker guarding Redis
+func (s *CacheService) Breaker() *resilience.Breaker {
+	return s.breaker.breaker
+}
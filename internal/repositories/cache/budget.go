@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrBudgetUnseeded is returned by Reserve when a budget's counter is
+// missing, as at the start of a period or after Redis lost its data. Seed
+// it from the source of truth and reserve again.
+var ErrBudgetUnseeded = errors.New("budget counter is not seeded")
+
+// Budget is a counter capped at Limit. Its key should name the period it
+// covers; TTL only has to outlast that period.
+type Budget struct {
+	Key   string
+	Limit int64
+	TTL   time.Duration
+}
+
+// reserveBudgets adds ARGV[1] to every counter in KEYS unless that takes
+// one past its limit, ARGV[2..n+1]. It returns -1 if a counter is missing,
+// the 1-based index of the first counter that would go over, or 0 once
+// the amount is added to all of them.
+var reserveBudgets = redis.NewScript(`
+local amount = tonumber(ARGV[1])
+for i, key in ipairs(KEYS) do
+	local spent = redis.call('GET', key)
+	if not spent then
+		return -1
+	end
+	if tonumber(spent) + amount > tonumber(ARGV[i + 1]) then
+		return i
+	end
+end
+for i, key in ipairs(KEYS) do
+	redis.call('INCRBY', key, amount)
+end
+return 0
+`)
+
+// Reserve adds amount to every budget if none of them would go over its
+// limit. It returns -1 once the amount is reserved, or the index of the
+// first budget that would have gone over.
+func (s *CacheService) Reserve(ctx context.Context, amount int64, budgets ...Budget) (int, error) {
+	if len(budgets) == 0 {
+		return -1, nil
+	}
+	keys := make([]string, len(budgets))
+	args := make([]interface{}, 0, len(budgets)+1)
+	args = append(args, amount)
+	for i, b := range budgets {
+		keys[i] = s.key(b.Key)
+		args = append(args, b.Limit)
+	}
+
+	res, err := reserveBudgets.Run(ctx, s.client, keys, args...).Int()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case res < 0:
+		return 0, ErrBudgetUnseeded
+	case res > 0:
+		return res - 1, nil
+	}
+	return -1, nil
+}
+
+// Release gives back an amount reserved on budgets
+func (s *CacheService) Release(ctx context.Context, amount int64, budgets ...Budget) error {
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, b := range budgets {
+			pipe.DecrBy(ctx, s.key(b.Key), amount)
+		}
+		return nil
+	})
+	return err
+}
+
+// SeedBudget sets a missing budget counter to spent. A counter seeded
+// meanwhile by someone else is left alone.
+func (s *CacheService) SeedBudget(ctx context.Context, b Budget, spent int64) error {
+	return s.client.SetNX(ctx, s.key(b.Key), spent, b.TTL).Err()
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hitWindow counts a hit in a fixed window, starting the window on its
+// first hit, and returns the count and the milliseconds until it resets
+var hitWindow = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// Hit counts one hit on key within a fixed window. It returns the hits so
+// far in the current window and how long until the window resets.
+func (s *CacheService) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	res, err := hitWindow.Run(ctx, s.client, []string{s.key(key)}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// unhitWindow takes a hit back, leaving a window that has reset alone
+var unhitWindow = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('DECR', KEYS[1])
+end
+return 0
+`)
+
+// Unhit takes back a hit Hit counted in the current window
+func (s *CacheService) Unhit(ctx context.Context, key string) error {
+	return unhitWindow.Run(ctx, s.client, []string{s.key(key)}).Err()
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// scanBatchSize bounds how many keys are fetched and deleted per round trip
+const scanBatchSize = 500
+
+// TagKey returns the set key holding all cache keys attached to a tag
+func TagKey(tag string) string {
+	return "tag:" + tag
+}
+
+// UserTag groups every cache entry derived from a single user
+func UserTag(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// RoleTag groups cache entries of every user holding a role
+func RoleTag(role string) string {
+	return "role:" + role
+}
+
+// TagKeys attaches cache keys to a tag so they can be invalidated together
+func (s *CacheService) TagKeys(ctx context.Context, tag string, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	// Members are stored namespaced so invalidation can delete them as is
+	members := make([]interface{}, len(keys))
+	for i, key := range keys {
+		members[i] = s.key(key)
+	}
+
+	tagKey := s.key(TagKey(tag))
+	pipe := s.client.TxPipeline()
+	pipe.SAdd(ctx, tagKey, members...)
+	// The tag outlives its longest-lived member, jitter included
+	pipe.Expire(ctx, tagKey, s.ttl+time.Duration(s.jitter*float64(s.ttl)))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// InvalidateTag deletes every key attached to a tag along with the tag itself
+func (s *CacheService) InvalidateTag(ctx context.Context, tag string) (int64, error) {
+	tagKey := s.key(TagKey(tag))
+	var deleted int64
+	var cursor uint64
+
+	for {
+		keys, next, err := s.client.SScan(ctx, tagKey, cursor, "", scanBatchSize).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan tag %s: %w", tag, err)
+		}
+		if len(keys) > 0 {
+			n, err := s.client.Del(ctx, keys...).Result()
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete keys for tag %s: %w", tag, err)
+			}
+			deleted += n
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	if err := s.client.Del(ctx, tagKey).Err(); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// InvalidatePrefix deletes every key starting with prefix using SCAN,
+// so large keyspaces are walked incrementally instead of blocking Redis
+func (s *CacheService) InvalidatePrefix(ctx context.Context, prefix string) (int64, error) {
+	if prefix == "" {
+		return 0, fmt.Errorf("refusing to invalidate an empty prefix")
+	}
+
+	var deleted int64
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, s.key(prefix)+"*", scanBatchSize).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan prefix %s: %w", prefix, err)
+		}
+		if len(keys) > 0 {
+			n, err := s.client.Del(ctx, keys...).Result()
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete keys for prefix %s: %w", prefix, err)
+			}
+			deleted += n
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrInvalidationQueueFull is returned when the invalidation backlog is saturated
+var ErrInvalidationQueueFull = errors.New("cache invalidation queue is full")
+
+// InvalidationJob describes a set of cache entries to drop
+type InvalidationJob struct {
+	Tags     []string
+	Prefixes []string
+	Keys     []string
+	Reason   string
+}
+
+// UserInvalidationJob builds a job dropping everything cached for a user
+func UserInvalidationJob(userID uint, reason string) InvalidationJob {
+	return InvalidationJob{
+		Tags: []string{UserTag(userID)},
+		Prefixes: []string{
+			fmt.Sprintf("wallet:user:%d", userID),
+			fmt.Sprintf("tx_history:%d:", userID),
+		},
+		Keys:   []string{fmt.Sprintf("user:id:%d", userID), SessionKey(userID)},
+		Reason: reason,
+	}
+}
+
+// RoleInvalidationJob builds a job dropping cached entries of every user with a role
+func RoleInvalidationJob(role, reason string) InvalidationJob {
+	return InvalidationJob{
+		Tags:   []string{RoleTag(role)},
+		Reason: reason,
+	}
+}
+
+// Invalidator fans invalidation jobs out to background workers so admin
+// operations don't block on walking large parts of the keyspace
+type Invalidator struct {
+	cache   *CacheService
+	jobs    chan InvalidationJob
+	workers int
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewInvalidator creates an invalidator with the given number of workers
+func NewInvalidator(cache *CacheService, workers int) *Invalidator {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Invalidator{
+		cache:   cache,
+		jobs:    make(chan InvalidationJob, 1000),
+		workers: workers,
+	}
+}
+
+// Start launches the workers; they exit when ctx is cancelled or Stop is called
+func (i *Invalidator) Start(ctx context.Context) {
+	for w := 0; w < i.workers; w++ {
+		i.wg.Add(1)
+		go func() {
+			defer i.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job, ok := <-i.jobs:
+					if !ok {
+						return
+					}
+					i.run(ctx, job)
+				}
+			}
+		}()
+	}
+}
+
+// Stop drains queued jobs and waits for the workers to finish
+func (i *Invalidator) Stop() {
+	i.once.Do(func() {
+		close(i.jobs)
+	})
+	i.wg.Wait()
+}
+
+// Enqueue schedules a job without blocking the caller
+func (i *Invalidator) Enqueue(job InvalidationJob) error {
+	select {
+	case i.jobs <- job:
+		return nil
+	default:
+		log.Printf("Cache invalidation queue full, dropping job: %s", job.Reason)
+		return ErrInvalidationQueueFull
+	}
+}
+
+func (i *Invalidator) run(ctx context.Context, job InvalidationJob) {
+	start := time.Now()
+	var deleted int64
+
+	for _, tag := range job.Tags {
+		n, err := i.cache.InvalidateTag(ctx, tag)
+		if err != nil {
+			log.Printf("Cache invalidation failed for tag %s: %v", tag, err)
+		}
+		deleted += n
+	}
+
+	for _, prefix := range job.Prefixes {
+		n, err := i.cache.InvalidatePrefix(ctx, prefix)
+		if err != nil {
+			log.Printf("Cache invalidation failed for prefix %s: %v", prefix, err)
+		}
+		deleted += n
+	}
+
+	if len(job.Keys) > 0 {
+		if err := i.cache.Delete(ctx, job.Keys...); err != nil {
+			log.Printf("Cache invalidation failed for keys %v: %v", job.Keys, err)
+		} else {
+			deleted += int64(len(job.Keys))
+		}
+	}
+
+	log.Printf("Cache INVALIDATE job (%s): %d keys in %s", job.Reason, deleted, time.Since(start))
+}
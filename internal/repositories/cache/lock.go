@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redsync/redsync/v4"
+	"github.com/go-redsync/redsync/v4/redis/goredis/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockBusy is returned when a lock is still held by someone else once
+// the caller has waited as long as it will
+var ErrLockBusy = errors.New("resource is busy, try again")
+
+// lockRetryDelay is how long Lock sleeps between attempts on a held lock
+const lockRetryDelay = 25 * time.Millisecond
+
+// Lock is a distributed lock held in Redis
+type Lock struct {
+	mutex *redsync.Mutex
+	name  string
+}
+
+// LockStats counts lock acquisitions since start-up
+type LockStats struct {
+	// Acquired counts locks taken
+	Acquired uint64
+	// Busy counts callers that gave up waiting for a held lock
+	Busy uint64
+	// Unavailable counts callers that went ahead unlocked because Redis was down
+	Unavailable uint64
+	// Wait is the total time spent acquiring locks
+	Wait time.Duration
+}
+
+type lockCounters struct {
+	acquired    atomic.Uint64
+	busy        atomic.Uint64
+	unavailable atomic.Uint64
+	wait        atomic.Int64
+}
+
+// Lock takes the lock called name, shared by every instance, waiting up to
+// wait for its holder to release it. The lock expires after ttl in case
+// the holder dies. It returns ErrLockBusy if the lock is still held after
+// wait, or ctx's error if ctx ends first.
+//
+// When Redis is unavailable the lock is skipped and a no-op Lock returned:
+// the lock keeps instances from racing, but the database still serialises
+// the writes themselves, so an outage shouldn't stop every payment.
+func (s *CacheService) Lock(ctx context.Context, name string, ttl, wait time.Duration) (*Lock, error) {
+	start := time.Now()
+	defer func() { s.locks.wait.Add(int64(time.Since(start))) }()
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	// One try per call, so a held lock can be told apart from Redis failing
+	mutex := s.locker.NewMutex(s.key("lock:"+name), redsync.WithExpiry(ttl), redsync.WithTries(1))
+	for {
+		err := mutex.LockContext(waitCtx)
+		if err == nil {
+			s.locks.acquired.Add(1)
+			return &Lock{mutex: mutex, name: name}, nil
+		}
+
+		var taken *redsync.ErrTaken
+		held := errors.As(err, &taken) || errors.Is(err, redsync.ErrFailed)
+		if !held && waitCtx.Err() == nil {
+			s.locks.unavailable.Add(1)
+			log.Printf("Lock %s unavailable, continuing without it: %v", name, err)
+			return &Lock{name: name}, nil
+		}
+
+		if waitCtx.Err() != nil {
+			return nil, s.lockAbandoned(ctx)
+		}
+		select {
+		case <-waitCtx.Done():
+			return nil, s.lockAbandoned(ctx)
+		case <-time.After(lockRetryDelay):
+		}
+	}
+}
+
+// lockAbandoned is the error for a caller that stopped waiting on a lock:
+// ctx's own error if it ended, else ErrLockBusy
+func (s *CacheService) lockAbandoned(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.locks.busy.Add(1)
+	return ErrLockBusy
+}
+
+// Unlock releases the lock. It is safe to call on a lock skipped because
+// Redis was down, and runs even after the caller's context has ended.
+func (l *Lock) Unlock(ctx context.Context) {
+	if l == nil || l.mutex == nil {
+		return
+	}
+	if _, err := l.mutex.UnlockContext(context.WithoutCancel(ctx)); err != nil {
+		log.Printf("Failed to release lock %s, it expires on its own: %v", l.name, err)
+	}
+}
+
+// LockStats returns the lock counters
+func (s *CacheService) LockStats() LockStats {
+	return LockStats{
+		Acquired:    s.locks.acquired.Load(),
+		Busy:        s.locks.busy.Load(),
+		Unavailable: s.locks.unavailable.Load(),
+		Wait:        time.Duration(s.locks.wait.Load()),
+	}
+}
+
+func newLocker(client *redis.Client) *redsync.Redsync {
+	return redsync.New(goredis.NewPool(client))
+}
@@ -5,22 +5,73 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"math/rand"
 	"orus/internal/models"
 	"time"
 
+	"github.com/go-redsync/redsync/v4"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
+// Options configures a CacheService
+type Options struct {
+	// Namespace prefixes every key the service reads or writes
+	Namespace string
+	// TTL is used by Set
+	TTL time.Duration
+	// TTLJitter stretches cache TTLs by a random fraction up to this value
+	TTLJitter float64
+}
+
+// CacheService stores JSON values in Redis. Callers pass bare keys; the
+// service adds its namespace, so nothing outside it sees prefixed keys.
 type CacheService struct {
-	client *redis.Client
-	ttl    time.Duration
+	client    *redis.Client
+	namespace string
+	ttl       time.Duration
+	jitter    float64
+	loads     singleflight.Group
+	breaker   *breakerHook
+	locker    *redsync.Redsync
+	locks     lockCounters
 }
 
-func NewCacheService(client *redis.Client, defaultTTL time.Duration) *CacheService {
+// NewCacheService wraps client, guarding it with a circuit breaker
+func NewCacheService(client *redis.Client, opts Options) *CacheService {
+	breaker := newBreakerHook()
+	client.AddHook(breaker)
 	return &CacheService{
-		client: client,
-		ttl:    defaultTTL,
+		client:    client,
+		namespace: opts.Namespace,
+		ttl:       opts.TTL,
+		jitter:    opts.TTLJitter,
+		breaker:   breaker,
+		locker:    newLocker(client),
+	}
+}
+
+// key adds the namespace to a bare key
+func (s *CacheService) key(key string) string {
+	return s.namespace + key
+}
+
+func (s *CacheService) keys(keys []string) []string {
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = s.key(key)
+	}
+	return namespaced
+}
+
+// jittered stretches ttl by a random fraction so entries cached at the
+// same moment expire spread out rather than all at once
+func (s *CacheService) jittered(ttl time.Duration) time.Duration {
+	if s.jitter <= 0 || ttl <= 0 {
+		return ttl
 	}
+	return ttl + time.Duration(rand.Float64()*s.jitter*float64(ttl))
 }
 
 // Base operations
@@ -28,16 +79,23 @@ func (s *CacheService) Set(ctx context.Context, key string, value interface{}) e
 	return s.SetWithTTL(ctx, key, value, s.ttl)
 }
 
+// SetWithTTL caches a value for about ttl; the TTL is jittered
 func (s *CacheService) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return s.SetExact(ctx, key, value, s.jittered(ttl))
+}
+
+// SetExact stores a value that must expire exactly after ttl, such as a
+// one-time code, rather than a cached copy of data held elsewhere
+func (s *CacheService) SetExact(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache value: %w", err)
 	}
-	return s.client.Set(ctx, key, data, ttl).Err()
+	return s.client.Set(ctx, s.key(key), data, ttl).Err()
 }
 
 func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
-	data, err := s.client.Get(ctx, key).Bytes()
+	data, err := s.client.Get(ctx, s.key(key)).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			return false, nil
@@ -51,8 +109,64 @@ func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) (b
 	return true, nil
 }
 
+// Take is Get that deletes the key in the same command, so of callers
+// racing for a value only one finds it
+func (s *CacheService) Take(ctx context.Context, key string, dest interface{}) (bool, error) {
+	data, err := s.client.GetDel(ctx, s.key(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to take cache value: %w", err)
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, fmt.Errorf("failed to unmarshal cache value: %w", err)
+	}
+	return true, nil
+}
+
 func (s *CacheService) Delete(ctx context.Context, keys ...string) error {
-	return s.client.Del(ctx, keys...).Err()
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, s.keys(keys)...).Err()
+}
+
+// Coalesce runs load once per key at a time: callers that miss the cache
+// for the same key together share the first caller's result instead of
+// each hitting the database. Callers must not modify the shared result.
+func (s *CacheService) Coalesce(key string, load func() (interface{}, error)) (interface{}, error) {
+	value, err, _ := s.loads.Do(key, load)
+	return value, err
+}
+
+// Load reads key into dest. On a miss load runs once across concurrent
+// callers, its result is cached for ttl and decoded into dest. Errors from
+// load are returned as is and not cached.
+func (s *CacheService) Load(ctx context.Context, key string, ttl time.Duration, dest interface{}, load func() (interface{}, error)) error {
+	if found, err := s.Get(ctx, key, dest); err == nil && found {
+		return nil
+	}
+
+	data, err := s.Coalesce(key, func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cache value: %w", err)
+		}
+		if err := s.client.Set(ctx, s.key(key), data, s.jittered(ttl)).Err(); err != nil {
+			log.Printf("Failed to cache %s: %v", key, err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data.([]byte), dest)
 }
 
 // Key generation
@@ -79,6 +193,14 @@ func (s *CacheService) CacheUser(ctx context.Context, user *models.User) error {
 			return err
 		}
 	}
+
+	// Tag entries so role or permission changes can drop them in bulk
+	if err := s.TagKeys(ctx, UserTag(user.ID), keys...); err != nil {
+		return err
+	}
+	if user.Role != "" {
+		return s.TagKeys(ctx, RoleTag(user.Role), keys...)
+	}
 	return nil
 }
 
@@ -95,15 +217,24 @@ func (s *CacheService) GetUser(ctx context.Context, key string) (*models.User, e
 }
 
 // Wallet caching
+
+// WalletTTL bounds how long a cached wallet is served after a change made
+// outside the wallet service, which only drops the entry
+const WalletTTL = time.Minute
+
+// CacheWallet writes a wallet through to the cache. A copy older than the
+// cached one is ignored, so concurrent writers always leave the newest.
 func (s *CacheService) CacheWallet(ctx context.Context, wallet *models.Wallet) error {
 	key := s.GenerateKey("wallet", "user", wallet.UserID)
-	return s.Set(ctx, key, wallet)
+	_, err := s.SetVersioned(ctx, key, wallet.Version, wallet, WalletTTL)
+	return err
 }
 
+// GetWallet returns the cached wallet, or nil if none is cached
 func (s *CacheService) GetWallet(ctx context.Context, userID uint) (*models.Wallet, error) {
 	key := s.GenerateKey("wallet", "user", userID)
 	var wallet models.Wallet
-	found, err := s.Get(ctx, key, &wallet)
+	_, found, err := s.GetVersioned(ctx, key, &wallet)
 	if err != nil || !found {
 		return nil, err
 	}
@@ -112,6 +243,11 @@ func (s *CacheService) GetWallet(ctx context.Context, userID uint) (*models.Wall
 
 // Invalidation patterns
 func (s *CacheService) InvalidateUser(ctx context.Context, userID uint) error {
+	// The session state is dropped even when the user itself isn't cached
+	if err := s.InvalidateSessions(ctx, userID); err != nil {
+		return err
+	}
+
 	user, err := s.GetUser(ctx, s.GenerateKey("user", "id", userID))
 	if err != nil {
 		return err
@@ -132,11 +268,6 @@ func (s *CacheService) InvalidateWallet(ctx context.Context, userID uint) error
 	return s.Delete(ctx, s.GenerateKey("wallet", "user", userID))
 }
 
-// FlushAll flushes all keys from the cache
-func (s *CacheService) FlushAll(ctx context.Context) error {
-	return s.client.FlushAll(ctx).Err()
-}
-
 // Close closes the Redis client connection
 func (s *CacheService) Close() error {
 	return s.client.Close()
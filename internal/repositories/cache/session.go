@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SessionTTL bounds how long a session state is served from the cache.
+// Every change to it drops the entry; the TTL only covers a load racing
+// such a change.
+const SessionTTL = 5 * time.Minute
+
+// SessionState is what authenticating a request needs to know about a user
+type SessionState struct {
+	TokenVersion int  `json:"token_version"`
+	Suspended    bool `json:"suspended"`
+}
+
+// SessionKey returns the cache key of a user's session state
+func SessionKey(userID uint) string {
+	return fmt.Sprintf("session:user:%d", userID)
+}
+
+// LoadSession reads a user's session state, calling load on a miss and
+// caching its result
+func (s *CacheService) LoadSession(ctx context.Context, userID uint, load func() (*SessionState, error)) (*SessionState, error) {
+	var state SessionState
+	err := s.Load(ctx, SessionKey(userID), SessionTTL, &state, func() (interface{}, error) {
+		return load()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// InvalidateSessions drops the cached session state of the given users
+func (s *CacheService) InvalidateSessions(ctx context.Context, userIDs ...uint) error {
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = SessionKey(userID)
+	}
+	return s.Delete(ctx, keys...)
+}
+
+// SessionActiveKey returns the cache key of whether a sign-in session is
+// still open
+func SessionActiveKey(sessionID string) string {
+	return "session:id:" + sessionID
+}
+
+// LoadSessionActive reads whether a sign-in session is still open, calling
+// load on a miss and caching its result
+func (s *CacheService) LoadSessionActive(ctx context.Context, sessionID string, load func() (bool, error)) (bool, error) {
+	var active bool
+	err := s.Load(ctx, SessionActiveKey(sessionID), SessionTTL, &active, func() (interface{}, error) {
+		return load()
+	})
+	return active, err
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setIfNewer stores a versioned value as a hash of its version and data.
+// It leaves the entry alone when it already holds a newer version, so a
+// slow writer can't replace fresh data with what it read earlier.
+var setIfNewer = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'string' then
+	redis.call('DEL', KEYS[1])
+end
+local current = redis.call('HGET', KEYS[1], 'v')
+if current and tonumber(current) > tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'v', ARGV[1], 'd', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// SetVersioned caches value under key unless the entry already holds a
+// newer version. It reports whether the value was stored.
+func (s *CacheService) SetVersioned(ctx context.Context, key string, version int64, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+	stored, err := setIfNewer.Run(ctx, s.client, []string{s.key(key)},
+		version, data, s.jittered(ttl).Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to set versioned cache value: %w", err)
+	}
+	return stored == 1, nil
+}
+
+// GetVersioned reads a value stored by SetVersioned into dest and returns
+// its version
+func (s *CacheService) GetVersioned(ctx context.Context, key string, dest interface{}) (int64, bool, error) {
+	values, err := s.client.HMGet(ctx, s.key(key), "v", "d").Result()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get versioned cache value: %w", err)
+	}
+	rawVersion, ok := values[0].(string)
+	if !ok {
+		return 0, false, nil
+	}
+	data, ok := values[1].(string)
+	if !ok {
+		return 0, false, nil
+	}
+	version, err := strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid cached version %q: %w", rawVersion, err)
+	}
+	if err := json.Unmarshal([]byte(data), dest); err != nil {
+		return 0, false, fmt.Errorf("failed to unmarshal cache value: %w", err)
+	}
+	return version, true, nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCardTopUpNotFound = errors.New("card top-up not found")
+
+// CardTopUpRepository stores top-ups charged to linked cards
+type CardTopUpRepository interface {
+	Create(ctx context.Context, topUp *models.CardTopUp) error
+	Update(ctx context.Context, topUp *models.CardTopUp) error
+	GetByIDAndUserID(ctx context.Context, id, userID uint) (*models.CardTopUp, error)
+	// GetByChargeIDForUpdate locks the top-up row for the rest of the transaction
+	GetByChargeIDForUpdate(ctx context.Context, chargeID string) (*models.CardTopUp, error)
+	// CountSince counts the user's card top-ups created after since,
+	// whatever became of them
+	CountSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+	UpdateTransactionStatus(ctx context.Context, transactionID uint, status string) error
+
+	// ExecuteInTransaction runs fn with top-up and wallet repositories sharing one database transaction
+	ExecuteInTransaction(ctx context.Context, fn func(CardTopUpRepository, WalletRepository) error) error
+}
+
+type cardTopUpRepository struct {
+	db *gorm.DB
+}
+
+func NewCardTopUpRepository(db *gorm.DB) CardTopUpRepository {
+	return &cardTopUpRepository{db: db}
+}
+
+func (r *cardTopUpRepository) Create(ctx context.Context, topUp *models.CardTopUp) error {
+	if err := r.db.WithContext(ctx).Create(topUp).Error; err != nil {
+		return fmt.Errorf("failed to create card top-up: %w", err)
+	}
+	return nil
+}
+
+func (r *cardTopUpRepository) Update(ctx context.Context, topUp *models.CardTopUp) error {
+	if err := r.db.WithContext(ctx).Save(topUp).Error; err != nil {
+		return fmt.Errorf("failed to update card top-up: %w", err)
+	}
+	return nil
+}
+
+func (r *cardTopUpRepository) GetByIDAndUserID(ctx context.Context, id, userID uint) (*models.CardTopUp, error) {
+	var topUp models.CardTopUp
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&topUp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCardTopUpNotFound
+		}
+		return nil, fmt.Errorf("failed to get card top-up: %w", err)
+	}
+	return &topUp, nil
+}
+
+func (r *cardTopUpRepository) GetByChargeIDForUpdate(ctx context.Context, chargeID string) (*models.CardTopUp, error) {
+	var topUp models.CardTopUp
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("charge_id = ?", chargeID).
+		First(&topUp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCardTopUpNotFound
+		}
+		return nil, fmt.Errorf("failed to get card top-up: %w", err)
+	}
+	return &topUp, nil
+}
+
+func (r *cardTopUpRepository) CountSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.CardTopUp{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count card top-ups: %w", err)
+	}
+	return count, nil
+}
+
+func (r *cardTopUpRepository) UpdateTransactionStatus(ctx context.Context, transactionID uint, status string) error {
+	err := r.db.WithContext(ctx).Model(&models.Transaction{}).Where("id = ?", transactionID).
+		Updates(map[string]interface{}{"status": status, "processed_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update top-up transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *cardTopUpRepository) ExecuteInTransaction(ctx context.Context, fn func(CardTopUpRepository, WalletRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&cardTopUpRepository{db: tx}, NewWalletRepository(tx))
+	})
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrVaultedCardNotFound = errors.New("card reference not found")
+
+// LegacyCard is a linked card whose processor token was stored in plain
+// text on the card, before the vault
+type LegacyCard struct {
+	ID         uint
+	UserID     uint
+	CardNumber string
+}
+
+// CardVaultRepository stores encrypted card tokens and their access log.
+// Only the vault uses it.
+type CardVaultRepository interface {
+	Create(ctx context.Context, card *models.VaultedCard) error
+	GetByReference(ctx context.Context, ref string) (*models.VaultedCard, error)
+	Delete(ctx context.Context, ref string) error
+	RecordAccess(ctx context.Context, access *models.VaultAccess) error
+
+	// GetLegacyCards returns cards still holding a plain-text token
+	GetLegacyCards(ctx context.Context, limit int) ([]LegacyCard, error)
+	// AttachLegacyCard points the card at its vaulted token and clears the
+	// plain-text copy
+	AttachLegacyCard(ctx context.Context, cardID uint, ref string) error
+}
+
+type cardVaultRepository struct {
+	db *gorm.DB
+}
+
+func NewCardVaultRepository(db *gorm.DB) CardVaultRepository {
+	return &cardVaultRepository{db: db}
+}
+
+func (r *cardVaultRepository) Create(ctx context.Context, card *models.VaultedCard) error {
+	if err := r.db.WithContext(ctx).Create(card).Error; err != nil {
+		return fmt.Errorf("failed to vault card: %w", err)
+	}
+	return nil
+}
+
+func (r *cardVaultRepository) GetByReference(ctx context.Context, ref string) (*models.VaultedCard, error) {
+	var card models.VaultedCard
+	if err := r.db.WithContext(ctx).Where("reference = ?", ref).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVaultedCardNotFound
+		}
+		return nil, fmt.Errorf("failed to get vaulted card: %w", err)
+	}
+	return &card, nil
+}
+
+func (r *cardVaultRepository) Delete(ctx context.Context, ref string) error {
+	result := r.db.WithContext(ctx).Where("reference = ?", ref).Delete(&models.VaultedCard{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete vaulted card: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrVaultedCardNotFound
+	}
+	return nil
+}
+
+func (r *cardVaultRepository) RecordAccess(ctx context.Context, access *models.VaultAccess) error {
+	if err := r.db.WithContext(ctx).Create(access).Error; err != nil {
+		return fmt.Errorf("failed to log vault access: %w", err)
+	}
+	return nil
+}
+
+func (r *cardVaultRepository) GetLegacyCards(ctx context.Context, limit int) ([]LegacyCard, error) {
+	var cards []LegacyCard
+	err := r.db.WithContext(ctx).Table("credit_cards").
+		Select("id, user_id, card_number").
+		Where("vault_ref = '' AND card_number IS NOT NULL AND card_number <> ''").
+		Order("id").
+		Limit(limit).
+		Scan(&cards).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards to vault: %w", err)
+	}
+	return cards, nil
+}
+
+func (r *cardVaultRepository) AttachLegacyCard(ctx context.Context, cardID uint, ref string) error {
+	err := r.db.WithContext(ctx).Table("credit_cards").
+		Where("id = ?", cardID).
+		Updates(map[string]interface{}{"vault_ref": ref, "card_number": nil}).Error
+	if err != nil {
+		return fmt.Errorf("failed to attach vaulted card: %w", err)
+	}
+	return nil
+}
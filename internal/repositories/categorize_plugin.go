@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const categorizePluginName = "orus:categorize"
+
+// TransactionCategorizer sets the spending category of a transaction
+type TransactionCategorizer interface {
+	Categorize(ctx context.Context, tx *models.Transaction)
+}
+
+// CategorizePlugin categorizes transactions as they are created, whichever
+// service creates them. One already categorized is left as it is.
+type CategorizePlugin struct {
+	categorizer TransactionCategorizer
+}
+
+// NewCategorizePlugin wraps the categorizer as a GORM plugin; register it
+// with db.Use
+func NewCategorizePlugin(c TransactionCategorizer) *CategorizePlugin {
+	return &CategorizePlugin{categorizer: c}
+}
+
+func (p *CategorizePlugin) Name() string { return categorizePluginName }
+
+func (p *CategorizePlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register(categorizePluginName, p.categorize)
+}
+
+func (p *CategorizePlugin) categorize(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	var transactions []*models.Transaction
+	switch dest := db.Statement.Dest.(type) {
+	case *models.Transaction:
+		transactions = append(transactions, dest)
+	case []models.Transaction:
+		for i := range dest {
+			transactions = append(transactions, &dest[i])
+		}
+	case []*models.Transaction:
+		transactions = dest
+	default:
+		return
+	}
+
+	for _, tx := range transactions {
+		if tx != nil && tx.CategorySource == "" {
+			p.categorizer.Categorize(db.Statement.Context, tx)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrChargebackNotFound   = errors.New("chargeback not found")
+	ErrChargebackNotActive  = errors.New("chargeback has already been settled")
+	ErrDisputeNotChargeable = errors.New("dispute cannot be charged back")
+)
+
+// ChargebackSettlement closes a chargeback. A lost chargeback pays the
+// reserved amount out to the customer as Transaction.
+type ChargebackSettlement struct {
+	ChargebackID uint
+	Status       string // ChargebackStatusWon or ChargebackStatusLost
+	ResolvedBy   *uint
+	Note         string
+	Transaction  *models.Transaction // Only used when lost
+}
+
+// ChargebackRepository persists chargebacks and moves their reserve and
+// fee through the merchant's wallet
+type ChargebackRepository interface {
+	// Open creates the chargeback, reserves its amount on the merchant's
+	// wallet, takes the fee and closes the dispute to the regular workflow,
+	// all in one database transaction
+	Open(chargeback *models.Chargeback, fee *models.Transaction) error
+	// Represent records the merchant's contest of an open chargeback. It
+	// returns false if the chargeback wasn't open.
+	Represent(id uint, note string, now time.Time) (bool, error)
+	Settle(settlement ChargebackSettlement) (*models.Chargeback, error)
+	GetByID(id uint) (*models.Chargeback, error)
+	GetByDisputeID(disputeID uint) (*models.Chargeback, error)
+	// List returns chargebacks against a merchant, or all of them when
+	// merchantUserID is 0
+	List(merchantUserID uint, status string, limit, offset int) ([]models.Chargeback, int64, error)
+	// GetOverdue returns open chargebacks whose representment deadline has passed
+	GetOverdue(now time.Time, limit int) ([]models.Chargeback, error)
+}
+
+type chargebackRepository struct {
+	db *gorm.DB
+}
+
+func NewChargebackRepository(db *gorm.DB) ChargebackRepository {
+	return &chargebackRepository{db: db}
+}
+
+func (r *chargebackRepository) Open(chargeback *models.Chargeback, fee *models.Transaction) error {
+	return r.db.Transaction(func(db *gorm.DB) error {
+		var dispute models.Dispute
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dispute, chargeback.DisputeID).Error; err != nil {
+			return fmt.Errorf("failed to lock dispute: %w", err)
+		}
+		if !dispute.IsOpen() || dispute.Source == models.DisputeSourceEscrow {
+			return ErrDisputeNotChargeable
+		}
+
+		// Locked so debits already checking the merchant's holds finish
+		// before the reserve is placed
+		wallets := NewWalletRepository(db)
+		wallet, err := wallets.LockByUserID(chargeback.MerchantUserID)
+		if err != nil {
+			return fmt.Errorf("failed to get merchant wallet: %w", err)
+		}
+
+		chargeback.Status = models.ChargebackStatusOpen
+		if err := db.Create(chargeback).Error; err != nil {
+			return fmt.Errorf("failed to create chargeback: %w", err)
+		}
+
+		// The reserve may exceed what the merchant has available; their
+		// available balance goes negative until the chargeback settles
+		hold := &models.WalletHold{
+			WalletID:  wallet.ID,
+			UserID:    chargeback.MerchantUserID,
+			Amount:    chargeback.Amount,
+			Type:      models.HoldTypeChargebackReserve,
+			Status:    models.HoldStatusActive,
+			Reason:    fmt.Sprintf("Chargeback #%d reserve", chargeback.ID),
+			Reference: fmt.Sprintf("chargeback:%d", chargeback.ID),
+		}
+		if err := db.Create(hold).Error; err != nil {
+			return fmt.Errorf("failed to reserve chargeback funds: %w", err)
+		}
+		chargeback.ReserveHoldID = &hold.ID
+		updates := map[string]interface{}{"reserve_hold_id": hold.ID}
+
+		// Like an NSF fee, the chargeback fee is taken even if it pushes the
+		// balance negative
+		if fee != nil && chargeback.Fee > 0 {
+			if _, err := wallets.ForceAdjustBalance(chargeback.MerchantUserID, -chargeback.Fee); err != nil {
+				return fmt.Errorf("failed to debit chargeback fee: %w", err)
+			}
+			fee.Metadata = models.NewJSON(map[string]interface{}{
+				"fee_type":      "chargeback",
+				"chargeback_id": chargeback.ID,
+				"dispute_id":    dispute.ID,
+			})
+			// The fee transaction books itself to fee revenue on create
+			if err := db.Create(fee).Error; err != nil {
+				return fmt.Errorf("failed to record chargeback fee: %w", err)
+			}
+			chargeback.FeeTransactionID = &fee.ID
+			updates["fee_transaction_id"] = fee.ID
+		}
+
+		if err := db.Model(chargeback).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to link chargeback reserve: %w", err)
+		}
+		if err := db.Model(&dispute).Update("status", models.DisputeStatusChargedBack).Error; err != nil {
+			return fmt.Errorf("failed to update dispute: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *chargebackRepository) Represent(id uint, note string, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Chargeback{}).
+		Where("id = ? AND status = ?", id, models.ChargebackStatusOpen).
+		Updates(map[string]interface{}{
+			"status":             models.ChargebackStatusRepresented,
+			"representment_note": note,
+			"represented_at":     now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update chargeback: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *chargebackRepository) Settle(settlement ChargebackSettlement) (*models.Chargeback, error) {
+	var chargeback models.Chargeback
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&chargeback, settlement.ChargebackID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrChargebackNotFound
+			}
+			return fmt.Errorf("failed to lock chargeback: %w", err)
+		}
+		if !chargeback.IsActive() {
+			return ErrChargebackNotActive
+		}
+
+		now := time.Now()
+		holdStatus := models.HoldStatusReleased
+		chargebackUpdates := map[string]interface{}{
+			"status":          settlement.Status,
+			"resolution_note": settlement.Note,
+			"resolved_by":     settlement.ResolvedBy,
+			"resolved_at":     now,
+		}
+		disputeUpdates := map[string]interface{}{
+			"resolution":      models.DisputeResolutionMerchant,
+			"resolution_note": settlement.Note,
+			"resolved_by":     settlement.ResolvedBy,
+			"resolved_at":     now,
+		}
+
+		if settlement.Status == models.ChargebackStatusLost {
+			tx, err := r.payOut(db, &chargeback, settlement.Transaction)
+			if err != nil {
+				return err
+			}
+			holdStatus = models.HoldStatusCaptured
+			chargebackUpdates["settle_transaction_id"] = tx.ID
+			disputeUpdates["resolution"] = models.DisputeResolutionCustomer
+			disputeUpdates["refunded"] = true
+			disputeUpdates["refund_amount"] = chargeback.Amount
+			disputeUpdates["refund_tx_id"] = tx.ID
+		}
+
+		if chargeback.ReserveHoldID != nil {
+			if err := db.Model(&models.WalletHold{}).
+				Where("id = ? AND status = ?", *chargeback.ReserveHoldID, models.HoldStatusActive).
+				Updates(map[string]interface{}{"status": holdStatus, "released_at": now}).Error; err != nil {
+				return fmt.Errorf("failed to close chargeback reserve: %w", err)
+			}
+		}
+		if err := db.Model(&chargeback).Updates(chargebackUpdates).Error; err != nil {
+			return fmt.Errorf("failed to settle chargeback: %w", err)
+		}
+		if err := db.Model(&models.Dispute{}).Where("id = ?", chargeback.DisputeID).Updates(disputeUpdates).Error; err != nil {
+			return fmt.Errorf("failed to update dispute: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &chargeback, nil
+}
+
+// payOut moves the reserved amount from the merchant to the customer. The
+// merchant's balance may go negative if they spent the funds before the
+// reserve was placed.
+func (r *chargebackRepository) payOut(db *gorm.DB, chargeback *models.Chargeback, tx *models.Transaction) (*models.Transaction, error) {
+	_, _, err := NewWalletRepository(db).ForceTransferFunds(chargeback.MerchantUserID, chargeback.CustomerID, chargeback.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pay out chargeback: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"chargeback_id":           chargeback.ID,
+		"dispute_id":              chargeback.DisputeID,
+		"original_transaction_id": chargeback.TransactionID,
+	}
+	if chargeback.ReasonCode != "" {
+		metadata["reason_code"] = chargeback.ReasonCode
+	}
+	tx.Metadata = models.NewJSON(metadata)
+	if err := db.Create(tx).Error; err != nil {
+		return nil, fmt.Errorf("failed to record chargeback: %w", err)
+	}
+
+	if err := db.Model(&models.Transaction{}).Where("id = ?", chargeback.TransactionID).
+		Update("status", "chargeback").Error; err != nil {
+		return nil, fmt.Errorf("failed to mark transaction charged back: %w", err)
+	}
+	return tx, nil
+}
+
+func (r *chargebackRepository) GetByID(id uint) (*models.Chargeback, error) {
+	var chargeback models.Chargeback
+	if err := r.db.First(&chargeback, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChargebackNotFound
+		}
+		return nil, fmt.Errorf("failed to get chargeback: %w", err)
+	}
+	return &chargeback, nil
+}
+
+func (r *chargebackRepository) GetByDisputeID(disputeID uint) (*models.Chargeback, error) {
+	var chargeback models.Chargeback
+	if err := r.db.Where("dispute_id = ?", disputeID).First(&chargeback).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChargebackNotFound
+		}
+		return nil, fmt.Errorf("failed to get chargeback: %w", err)
+	}
+	return &chargeback, nil
+}
+
+func (r *chargebackRepository) List(merchantUserID uint, status string, limit, offset int) ([]models.Chargeback, int64, error) {
+	var chargebacks []models.Chargeback
+	var total int64
+
+	query := r.db.Model(&models.Chargeback{})
+	if merchantUserID != 0 {
+		query = query.Where("merchant_user_id = ?", merchantUserID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count chargebacks: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&chargebacks).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get chargebacks: %w", err)
+	}
+	return chargebacks, total, nil
+}
+
+func (r *chargebackRepository) GetOverdue(now time.Time, limit int) ([]models.Chargeback, error) {
+	var chargebacks []models.Chargeback
+	err := r.db.Where("status = ? AND representment_due_at < ?", models.ChargebackStatusOpen, now).
+		Order("representment_due_at").Limit(limit).Find(&chargebacks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue chargebacks: %w", err)
+	}
+	return chargebacks, nil
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrComplianceReportNotFound       = errors.New("compliance report not found")
+	ErrComplianceReportClosed         = errors.New("compliance report was already filed or dismissed")
+	ErrComplianceTransactionsReported = errors.New("transactions are already in a report of this type")
+)
+
+// complianceExcludedTypes move money within a user's own funds or undo
+// earlier transactions, so monitoring leaves them out
+var complianceExcludedTypes = []string{
+	models.TransactionTypePotTransfer,
+	models.TransactionTypePromotion,
+	models.TransactionTypeReversal,
+	models.TransactionTypeRefund,
+	models.TransactionTypeChargeback,
+	"fee",
+}
+
+// StructuringCandidate is a user whose transactions below the reporting
+// threshold add up to a possible structuring pattern
+type StructuringCandidate struct {
+	UserID   uint
+	Currency string
+	Count    int
+	Total    float64
+}
+
+// ComplianceReportFilter narrows the reports listed; zero values match all
+type ComplianceReportFilter struct {
+	Type          string
+	Status        string
+	SubjectUserID uint
+	AssignedTo    uint
+}
+
+// ComplianceRepository finds transactions to report and persists the
+// report drafts
+type ComplianceRepository interface {
+	// FindLargeTransactions returns completed transactions of at least
+	// threshold created since, that no large transaction report covers
+	FindLargeTransactions(since time.Time, threshold float64, limit int) ([]models.Transaction, error)
+	// FindStructuring returns the senders whose completed transactions
+	// between floor and threshold since, that no suspicious activity
+	// report covers, number at least minCount and add up to minTotal
+	FindStructuring(since time.Time, floor, threshold float64, minCount int, minTotal float64) ([]StructuringCandidate, error)
+	// GetStructuringTransactions returns the transactions FindStructuring
+	// counted for the candidate
+	GetStructuringTransactions(candidate StructuringCandidate, since time.Time, floor, threshold float64) ([]models.Transaction, error)
+
+	// Create saves the report with its transactions. It fails with
+	// ErrComplianceTransactionsReported, saving nothing, if a report of
+	// the type covers any of them already.
+	Create(report *models.ComplianceReport) error
+	GetByID(id uint) (*models.ComplianceReport, error)
+	List(filter ComplianceReportFilter, limit, offset int) ([]models.ComplianceReport, int64, error)
+	// Update saves the fields unless the report was filed or dismissed
+	// in the meantime
+	Update(report *models.ComplianceReport, fields ...string) error
+}
+
+type complianceRepository struct {
+	db *gorm.DB
+}
+
+func NewComplianceRepository(db *gorm.DB) ComplianceRepository {
+	return &complianceRepository{db: db}
+}
+
+// monitored selects completed transactions monitoring looks at, created
+// since and not yet in a report of reportType
+func (r *complianceRepository) monitored(since time.Time, reportType string) *gorm.DB {
+	return r.db.Model(&models.Transaction{}).
+		Where("transactions.created_at >= ? AND transactions.status = ?", since, "completed").
+		Where("transactions.type NOT IN ?", complianceExcludedTypes).
+		Where(`NOT EXISTS (SELECT 1 FROM compliance_report_transactions c
+			WHERE c.report_type = ? AND c.transaction_id = transactions.id)`, reportType)
+}
+
+func (r *complianceRepository) FindLargeTransactions(since time.Time, threshold float64, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.monitored(since, models.ComplianceReportCTR).
+		Where("transactions.amount >= ?", threshold).
+		Order("transactions.id").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find large transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *complianceRepository) FindStructuring(since time.Time, floor, threshold float64, minCount int, minTotal float64) ([]StructuringCandidate, error) {
+	var candidates []StructuringCandidate
+	err := r.monitored(since, models.ComplianceReportSAR).
+		Select("transactions.sender_id AS user_id, transactions.currency, COUNT(*) AS count, SUM(transactions.amount) AS total").
+		Where("transactions.sender_id <> 0 AND transactions.amount >= ? AND transactions.amount < ?", floor, threshold).
+		Group("transactions.sender_id, transactions.currency").
+		Having("COUNT(*) >= ? AND SUM(transactions.amount) >= ?", minCount, minTotal).
+		Order("total DESC").
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find structuring patterns: %w", err)
+	}
+	return candidates, nil
+}
+
+func (r *complianceRepository) GetStructuringTransactions(candidate StructuringCandidate, since time.Time, floor, threshold float64) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.monitored(since, models.ComplianceReportSAR).
+		Where("transactions.sender_id = ? AND transactions.currency = ?", candidate.UserID, candidate.Currency).
+		Where("transactions.amount >= ? AND transactions.amount < ?", floor, threshold).
+		Order("transactions.created_at, transactions.id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get structuring transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *complianceRepository) Create(report *models.ComplianceReport) error {
+	transactions := report.Transactions
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Transactions").Create(report).Error; err != nil {
+			return fmt.Errorf("failed to create compliance report: %w", err)
+		}
+		for i := range transactions {
+			transactions[i].ReportID = report.ID
+			transactions[i].ReportType = report.Type
+		}
+		// Another run may have reported some of them in the meantime
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&transactions)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record compliance report transactions: %w", result.Error)
+		}
+		if result.RowsAffected != int64(len(transactions)) {
+			return ErrComplianceTransactionsReported
+		}
+		return nil
+	})
+}
+
+func (r *complianceRepository) GetByID(id uint) (*models.ComplianceReport, error) {
+	var report models.ComplianceReport
+	err := r.db.Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("occurred_at, transaction_id")
+	}).First(&report, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrComplianceReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance report: %w", err)
+	}
+	return &report, nil
+}
+
+func (r *complianceRepository) List(filter ComplianceReportFilter, limit, offset int) ([]models.ComplianceReport, int64, error) {
+	query := r.db.Model(&models.ComplianceReport{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.SubjectUserID != 0 {
+		query = query.Where("subject_user_id = ?", filter.SubjectUserID)
+	}
+	if filter.AssignedTo != 0 {
+		query = query.Where("assigned_to = ?", filter.AssignedTo)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count compliance reports: %w", err)
+	}
+
+	var reports []models.ComplianceReport
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&reports).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list compliance reports: %w", err)
+	}
+	return reports, total, nil
+}
+
+func (r *complianceRepository) Update(report *models.ComplianceReport, fields ...string) error {
+	result := r.db.Model(report).
+		Where("status NOT IN ?", []string{models.ComplianceReportFiled, models.ComplianceReportDismissed}).
+		Select(fields).
+		Updates(report)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update compliance report: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrComplianceReportClosed
+	}
+	return nil
+}
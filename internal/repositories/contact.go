@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrContactNotFound = errors.New("contact not found")
+	ErrContactExists   = errors.New("contact already saved")
+)
+
+// ContactFilter narrows a contact listing
+type ContactFilter struct {
+	FavoritesOnly bool
+	Search        string // Matches name or nickname
+}
+
+// ContactRepository persists users' saved P2P recipients
+type ContactRepository interface {
+	Create(contact *models.Contact) error
+	GetByID(id, userID uint) (*models.Contact, error)
+	GetByContactUserID(userID, contactUserID uint) (*models.Contact, error)
+	// List returns favorites first, then the most paid recipients
+	List(userID uint, filter ContactFilter, limit, offset int) ([]models.Contact, int64, error)
+	Update(contact *models.Contact) error
+	Delete(id, userID uint) error
+	// RecordPayment bumps the payment stats of the sender's contact for the
+	// receiver, if they have one
+	RecordPayment(userID, contactUserID uint, paidAt time.Time) error
+	// HasPaid reports whether the sender has completed a transfer to the receiver before
+	HasPaid(senderID, receiverID uint) (bool, error)
+}
+
+type contactRepository struct {
+	db *gorm.DB
+}
+
+func NewContactRepository(db *gorm.DB) ContactRepository {
+	return &contactRepository{db: db}
+}
+
+func (r *contactRepository) Create(contact *models.Contact) error {
+	result := r.db.Where("user_id = ? AND contact_user_id = ?", contact.UserID, contact.ContactUserID).
+		FirstOrCreate(contact)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create contact: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrContactExists
+	}
+	return nil
+}
+
+func (r *contactRepository) GetByID(id, userID uint) (*models.Contact, error) {
+	return r.first(r.db.Where("id = ? AND user_id = ?", id, userID))
+}
+
+func (r *contactRepository) GetByContactUserID(userID, contactUserID uint) (*models.Contact, error) {
+	return r.first(r.db.Where("user_id = ? AND contact_user_id = ?", userID, contactUserID))
+}
+
+func (r *contactRepository) first(query *gorm.DB) (*models.Contact, error) {
+	var contact models.Contact
+	if err := query.First(&contact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+	return &contact, nil
+}
+
+func (r *contactRepository) List(userID uint, filter ContactFilter, limit, offset int) ([]models.Contact, int64, error) {
+	var contacts []models.Contact
+	var total int64
+
+	query := r.db.Model(&models.Contact{}).Where("user_id = ?", userID)
+	if filter.FavoritesOnly {
+		query = query.Where("favorite = ?", true)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		like := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(nickname) LIKE ?", like, like)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count contacts: %w", err)
+	}
+	err := query.Order("favorite DESC, payment_count DESC, last_paid_at DESC NULLS LAST, name").
+		Limit(limit).Offset(offset).Find(&contacts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get contacts: %w", err)
+	}
+	return contacts, total, nil
+}
+
+func (r *contactRepository) Update(contact *models.Contact) error {
+	if err := r.db.Model(contact).Select("nickname", "favorite").Updates(contact).Error; err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+	return nil
+}
+
+func (r *contactRepository) Delete(id, userID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Contact{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete contact: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+func (r *contactRepository) RecordPayment(userID, contactUserID uint, paidAt time.Time) error {
+	err := r.db.Model(&models.Contact{}).
+		Where("user_id = ? AND contact_user_id = ?", userID, contactUserID).
+		Updates(map[string]interface{}{
+			"payment_count": gorm.Expr("payment_count + 1"),
+			"last_paid_at":  paidAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+	return nil
+}
+
+func (r *contactRepository) HasPaid(senderID, receiverID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Transaction{}).
+		Where("sender_id = ? AND receiver_id = ? AND status = ? AND type IN ?", senderID, receiverID, "completed",
+			[]string{models.TransactionTypeP2PTransfer, models.TransactionTypeTransfer}).
+		Limit(1).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check payment history: %w", err)
+	}
+	return count > 0, nil
+}
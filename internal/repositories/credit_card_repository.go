@@ -27,4 +27,7 @@ type CreditCardRepository interface {
 
 	// New method
 	GetByIDAndUserID(cardID uint, userID uint) (*models.CreditCard, error)
+
+	// List pages through every card, for admins
+	List(limit, offset int) ([]models.CreditCard, int64, error)
 }
@@ -124,3 +124,16 @@ func (r *creditCardRepository) GetByIDAndUserID(cardID uint, userID uint) (*mode
 	}
 	return &card, nil
 }
+
+func (r *creditCardRepository) List(limit, offset int) ([]models.CreditCard, int64, error) {
+	var cards []models.CreditCard
+	var total int64
+
+	if err := r.db.Model(&models.CreditCard{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count cards: %w", err)
+	}
+	if err := r.db.Limit(limit).Offset(offset).Find(&cards).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list cards: %w", err)
+	}
+	return cards, total, nil
+}
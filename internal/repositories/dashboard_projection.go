@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProjectionUpdate is everything one transaction adds to the dashboard
+// projections. Counts and volumes are increments, not totals.
+type ProjectionUpdate struct {
+	TransactionID uint
+	Users         []models.UserDailyStats
+	Merchants     []models.MerchantDailyStats
+	Breakdowns    []models.DailyBreakdown
+}
+
+// DashboardProjectionRepository maintains the daily aggregates the
+// dashboards read instead of scanning transactions. Day ranges are
+// inclusive; a zero bound leaves that side open.
+type DashboardProjectionRepository interface {
+	// GetCursor returns the projection's position, or the zero time if it
+	// hasn't run yet
+	GetCursor(name string) (time.Time, error)
+	SaveCursor(name string, position time.Time) error
+	// ListCompletedSince returns completed transactions updated after
+	// (after, afterID), ordered by update time and ID
+	ListCompletedSince(after time.Time, afterID uint, limit int) ([]models.Transaction, error)
+	// Apply adds the update to the aggregates in one database transaction.
+	// It returns false if the transaction had already been projected.
+	Apply(update ProjectionUpdate) (bool, error)
+
+	// GetUserTotals sums a user's days; LastTransactionAt is the latest
+	GetUserTotals(userID uint, from, to time.Time) (*models.UserDailyStats, error)
+	GetMerchantTotals(merchantID uint, from, to time.Time) (*models.MerchantDailyStats, error)
+	// GetBreakdown sums an owner's breakdown over the days, one row per key
+	GetBreakdown(scope string, ownerID uint, from, to time.Time) ([]models.DailyBreakdown, error)
+}
+
+type dashboardProjectionRepository struct {
+	db *gorm.DB
+}
+
+func NewDashboardProjectionRepository(db *gorm.DB) DashboardProjectionRepository {
+	return &dashboardProjectionRepository{db: db}
+}
+
+func (r *dashboardProjectionRepository) GetCursor(name string) (time.Time, error) {
+	var cursor models.ProjectionCursor
+	if err := r.db.Where("name = ?", name).First(&cursor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get projection cursor: %w", err)
+	}
+	return cursor.Position, nil
+}
+
+func (r *dashboardProjectionRepository) SaveCursor(name string, position time.Time) error {
+	err := r.db.Save(&models.ProjectionCursor{Name: name, Position: position}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save projection cursor: %w", err)
+	}
+	return nil
+}
+
+func (r *dashboardProjectionRepository) ListCompletedSince(after time.Time, afterID uint, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Where("status = ?", "completed").
+		Where("(updated_at > ? OR (updated_at = ? AND id > ?))", after, after, afterID).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions to project: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *dashboardProjectionRepository) Apply(update ProjectionUpdate) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.ProjectedTransaction{TransactionID: update.TransactionID})
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark transaction projected: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		for i := range update.Users {
+			err := db.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"sent_count":          gorm.Expr("user_daily_stats.sent_count + excluded.sent_count"),
+					"sent_volume":         gorm.Expr("ROUND((user_daily_stats.sent_volume + excluded.sent_volume)::numeric, 2)"),
+					"received_count":      gorm.Expr("user_daily_stats.received_count + excluded.received_count"),
+					"received_volume":     gorm.Expr("ROUND((user_daily_stats.received_volume + excluded.received_volume)::numeric, 2)"),
+					"last_transaction_at": gorm.Expr("GREATEST(user_daily_stats.last_transaction_at, excluded.last_transaction_at)"),
+					"updated_at":          gorm.Expr("excluded.updated_at"),
+				}),
+			}).Create(&update.Users[i]).Error
+			if err != nil {
+				return fmt.Errorf("failed to project user stats: %w", err)
+			}
+		}
+
+		for i := range update.Merchants {
+			err := db.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "merchant_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"count":      gorm.Expr("merchant_daily_stats.count + excluded.count"),
+					"volume":     gorm.Expr("ROUND((merchant_daily_stats.volume + excluded.volume)::numeric, 2)"),
+					"fees":       gorm.Expr("ROUND((merchant_daily_stats.fees + excluded.fees)::numeric, 2)"),
+					"updated_at": gorm.Expr("excluded.updated_at"),
+				}),
+			}).Create(&update.Merchants[i]).Error
+			if err != nil {
+				return fmt.Errorf("failed to project merchant stats: %w", err)
+			}
+		}
+
+		for i := range update.Breakdowns {
+			err := db.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "scope"}, {Name: "owner_id"}, {Name: "day"}, {Name: "key"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"count":  gorm.Expr("daily_breakdowns.count + excluded.count"),
+					"volume": gorm.Expr("ROUND((daily_breakdowns.volume + excluded.volume)::numeric, 2)"),
+				}),
+			}).Create(&update.Breakdowns[i]).Error
+			if err != nil {
+				return fmt.Errorf("failed to project breakdown: %w", err)
+			}
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+func (r *dashboardProjectionRepository) GetUserTotals(userID uint, from, to time.Time) (*models.UserDailyStats, error) {
+	totals := models.UserDailyStats{UserID: userID}
+	var last *time.Time
+	err := dayRange(r.replica().Model(&models.UserDailyStats{}), from, to).
+		Where("user_id = ?", userID).
+		Select(`COALESCE(SUM(sent_count), 0), COALESCE(SUM(sent_volume), 0),
+			COALESCE(SUM(received_count), 0), COALESCE(SUM(received_volume), 0),
+			MAX(last_transaction_at)`).
+		Row().Scan(&totals.SentCount, &totals.SentVolume, &totals.ReceivedCount, &totals.ReceivedVolume, &last)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user totals: %w", err)
+	}
+	if last != nil {
+		totals.LastTransactionAt = *last
+	}
+	return &totals, nil
+}
+
+func (r *dashboardProjectionRepository) GetMerchantTotals(merchantID uint, from, to time.Time) (*models.MerchantDailyStats, error) {
+	totals := models.MerchantDailyStats{MerchantID: merchantID}
+	err := dayRange(r.replica().Model(&models.MerchantDailyStats{}), from, to).
+		Where("merchant_id = ?", merchantID).
+		Select("COALESCE(SUM(count), 0), COALESCE(SUM(volume), 0), COALESCE(SUM(fees), 0)").
+		Row().Scan(&totals.Count, &totals.Volume, &totals.Fees)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant totals: %w", err)
+	}
+	return &totals, nil
+}
+
+func (r *dashboardProjectionRepository) GetBreakdown(scope string, ownerID uint, from, to time.Time) ([]models.DailyBreakdown, error) {
+	var rows []models.DailyBreakdown
+	err := dayRange(r.replica().Model(&models.DailyBreakdown{}), from, to).
+		Where("scope = ? AND owner_id = ?", scope, ownerID).
+		Select("scope, owner_id, key, SUM(count) AS count, SUM(volume) AS volume").
+		Group("scope, owner_id, key").
+		Order("volume DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get breakdown: %w", err)
+	}
+	return rows, nil
+}
+
+// replica reads the projections from the read replica. They trail the
+// ledger anyway, so a user's own recent writes don't pin them to the
+// primary.
+func (r *dashboardProjectionRepository) replica() *gorm.DB {
+	return r.db.Scopes(Replica(0))
+}
+
+// dayRange limits a projection query to the days between from and to
+func dayRange(db *gorm.DB, from, to time.Time) *gorm.DB {
+	if !from.IsZero() {
+		db = db.Where("day >= ?", from.Format("2006-01-02"))
+	}
+	if !to.IsZero() {
+		db = db.Where("day <= ?", to.Format("2006-01-02"))
+	}
+	return db
+}
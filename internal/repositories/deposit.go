@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDepositNotFound      = errors.New("deposit not found")
+	ErrDepositEventNotFound = errors.New("deposit event not found")
+)
+
+// DepositRepository persists ACH deposits and the provider events applied to them
+type DepositRepository interface {
+	Create(deposit *models.Deposit) error
+	GetByIDAndUserID(id, userID uint) (*models.Deposit, error)
+	GetByUserID(userID uint, limit, offset int) ([]models.Deposit, int64, error)
+	// GetByProviderTransferIDForUpdate locks the deposit row for the rest of the transaction
+	GetByProviderTransferIDForUpdate(transferID string) (*models.Deposit, error)
+	Update(deposit *models.Deposit) error
+	UpdateTransactionStatus(transactionID uint, status string) error
+
+	CreateEvent(event *models.DepositEvent) error
+	GetEventByEventID(eventID string) (*models.DepositEvent, error)
+	GetEvents(depositID uint) ([]models.DepositEvent, error)
+
+	// ExecuteInTransaction runs fn with deposit and wallet repositories sharing one database transaction
+	ExecuteInTransaction(fn func(DepositRepository, WalletRepository) error) error
+}
+
+type depositRepository struct {
+	db *gorm.DB
+}
+
+func NewDepositRepository(db *gorm.DB) DepositRepository {
+	return &depositRepository{db: db}
+}
+
+func (r *depositRepository) Create(deposit *models.Deposit) error {
+	if err := r.db.Create(deposit).Error; err != nil {
+		return fmt.Errorf("failed to create deposit: %w", err)
+	}
+	return nil
+}
+
+func (r *depositRepository) GetByIDAndUserID(id, userID uint) (*models.Deposit, error) {
+	var deposit models.Deposit
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&deposit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDepositNotFound
+		}
+		return nil, fmt.Errorf("failed to get deposit: %w", err)
+	}
+	return &deposit, nil
+}
+
+func (r *depositRepository) GetByUserID(userID uint, limit, offset int) ([]models.Deposit, int64, error) {
+	var deposits []models.Deposit
+	var total int64
+
+	query := r.db.Model(&models.Deposit{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count deposits: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deposits).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get deposits: %w", err)
+	}
+	return deposits, total, nil
+}
+
+func (r *depositRepository) GetByProviderTransferIDForUpdate(transferID string) (*models.Deposit, error) {
+	var deposit models.Deposit
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("provider_transfer_id = ?", transferID).
+		First(&deposit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDepositNotFound
+		}
+		return nil, fmt.Errorf("failed to get deposit: %w", err)
+	}
+	return &deposit, nil
+}
+
+func (r *depositRepository) Update(deposit *models.Deposit) error {
+	if err := r.db.Save(deposit).Error; err != nil {
+		return fmt.Errorf("failed to update deposit: %w", err)
+	}
+	return nil
+}
+
+func (r *depositRepository) UpdateTransactionStatus(transactionID uint, status string) error {
+	err := r.db.Model(&models.Transaction{}).Where("id = ?", transactionID).
+		Updates(map[string]interface{}{"status": status, "processed_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update deposit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *depositRepository) CreateEvent(event *models.DepositEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record deposit event: %w", err)
+	}
+	return nil
+}
+
+func (r *depositRepository) GetEventByEventID(eventID string) (*models.DepositEvent, error) {
+	var event models.DepositEvent
+	if err := r.db.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDepositEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get deposit event: %w", err)
+	}
+	return &event, nil
+}
+
+func (r *depositRepository) GetEvents(depositID uint) ([]models.DepositEvent, error) {
+	var events []models.DepositEvent
+	if err := r.db.Where("deposit_id = ?", depositID).Order("created_at ASC").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get deposit events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *depositRepository) ExecuteInTransaction(fn func(DepositRepository, WalletRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&depositRepository{db: tx}, NewWalletRepository(tx))
+	})
+}
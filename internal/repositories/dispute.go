@@ -1,18 +1,37 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
 	"orus/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var ErrEvidenceNotFound = errors.New("dispute evidence not found")
+
 type DisputeRepository interface {
 	Create(dispute *models.Dispute) error
 	FindByID(id uint) (*models.Dispute, error)
 	FindByMerchantID(merchantID uint) ([]models.Dispute, error)
+	// ListByUserID pages through the disputes the user filed, newest
+	// first, optionally only those in status
+	ListByUserID(userID uint, status string, limit, offset int) ([]models.Dispute, int64, error)
 	ExistsByTransactionID(transactionID uint) (bool, error)
 	IsRefunded(disputeID uint) (bool, error)
 	Update(dispute *models.Dispute) error
+
+	// Transition moves a dispute to a new status with updates, but only if it
+	// is currently in one of from. It returns false if it wasn't.
+	Transition(id uint, from []string, updates map[string]interface{}) (bool, error)
+	List(status string, limit, offset int) ([]models.Dispute, int64, error)
+	// FindOverdueResponses returns disputes whose evidence deadline has passed
+	FindOverdueResponses(now time.Time, limit int) ([]models.Dispute, error)
+
+	AddEvidence(evidence *models.DisputeEvidence) error
+	ListEvidence(disputeID uint) ([]models.DisputeEvidence, error)
+	GetEvidence(disputeID, evidenceID uint) (*models.DisputeEvidence, error)
 }
 
 type disputeRepository struct {
@@ -39,6 +58,23 @@ func (r *disputeRepository) FindByMerchantID(merchantID uint) ([]models.Dispute,
 	return disputes, err
 }
 
+func (r *disputeRepository) ListByUserID(userID uint, status string, limit, offset int) ([]models.Dispute, int64, error) {
+	var disputes []models.Dispute
+	var total int64
+
+	query := r.db.Model(&models.Dispute{}).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count disputes: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&disputes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get disputes: %w", err)
+	}
+	return disputes, total, nil
+}
+
 func (r *disputeRepository) ExistsByTransactionID(transactionID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Dispute{}).Where("transaction_id = ?", transactionID).Count(&count).Error
@@ -60,3 +96,64 @@ func (r *disputeRepository) IsRefunded(disputeID uint) (bool, error) {
 func (r *disputeRepository) Update(dispute *models.Dispute) error {
 	return r.db.Save(dispute).Error
 }
+
+func (r *disputeRepository) Transition(id uint, from []string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.Dispute{}).Where("id = ? AND status IN ?", id, from).Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update dispute: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *disputeRepository) List(status string, limit, offset int) ([]models.Dispute, int64, error) {
+	var disputes []models.Dispute
+	var total int64
+
+	query := r.db.Model(&models.Dispute{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count disputes: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&disputes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get disputes: %w", err)
+	}
+	return disputes, total, nil
+}
+
+func (r *disputeRepository) FindOverdueResponses(now time.Time, limit int) ([]models.Dispute, error) {
+	var disputes []models.Dispute
+	err := r.db.Where("status = ? AND response_due_at < ?", models.DisputeStatusEvidenceRequested, now).
+		Order("response_due_at").Limit(limit).Find(&disputes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue disputes: %w", err)
+	}
+	return disputes, nil
+}
+
+func (r *disputeRepository) AddEvidence(evidence *models.DisputeEvidence) error {
+	if err := r.db.Create(evidence).Error; err != nil {
+		return fmt.Errorf("failed to save dispute evidence: %w", err)
+	}
+	return nil
+}
+
+func (r *disputeRepository) ListEvidence(disputeID uint) ([]models.DisputeEvidence, error) {
+	var evidence []models.DisputeEvidence
+	if err := r.db.Where("dispute_id = ?", disputeID).Order("id").Find(&evidence).Error; err != nil {
+		return nil, fmt.Errorf("failed to get dispute evidence: %w", err)
+	}
+	return evidence, nil
+}
+
+func (r *disputeRepository) GetEvidence(disputeID, evidenceID uint) (*models.DisputeEvidence, error) {
+	var evidence models.DisputeEvidence
+	if err := r.db.Where("id = ? AND dispute_id = ?", evidenceID, disputeID).First(&evidence).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEvidenceNotFound
+		}
+		return nil, fmt.Errorf("failed to get dispute evidence: %w", err)
+	}
+	return &evidence, nil
+}
@@ -0,0 +1,633 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEnterpriseNotFound          = errors.New("organization not found")
+	ErrEnterpriseMemberNotFound    = errors.New("organization member not found")
+	ErrEnterpriseWalletNotFound    = errors.New("organization wallet not found")
+	ErrInsufficientEnterpriseFunds = errors.New("insufficient funds in organization wallet")
+	ErrEnterprisePaymentNotFound   = errors.New("organization payment not found")
+	ErrEnterprisePaymentNotOpen    = errors.New("organization payment is not pending approval")
+	ErrApprovalRuleNotFound        = errors.New("approval rule not found")
+)
+
+// EnterprisePaymentFilter narrows a listing of organization payments.
+// Zero fields don't filter.
+type EnterprisePaymentFilter struct {
+	WalletID    uint
+	RequestedBy uint
+	Status      string
+	From        time.Time
+	To          time.Time
+	// ApproverRoles keeps payments waiting on one of these roles
+	ApproverRoles []string
+}
+
+// EnterpriseReview is an approver's decision on a held payment
+type EnterpriseReview struct {
+	ReviewerID uint
+	Note       string
+}
+
+// EnterpriseWalletTotals sums one organization wallet's activity
+type EnterpriseWalletTotals struct {
+	WalletID uint    `json:"wallet_id"`
+	Funded   float64 `json:"funded"`
+	Spent    float64 `json:"spent"`
+	Payments int64   `json:"payments"`
+}
+
+// EnterpriseMemberTotals sums one member's spending
+type EnterpriseMemberTotals struct {
+	UserID   uint    `json:"user_id"`
+	Spent    float64 `json:"spent"`
+	Payments int64   `json:"payments"`
+}
+
+// EnterpriseCategoryTotals sums spending in one category
+type EnterpriseCategoryTotals struct {
+	Category string  `json:"category"`
+	Spent    float64 `json:"spent"`
+	Payments int64   `json:"payments"`
+}
+
+// EnterpriseRepository persists organizations, their members and wallets,
+// and the money moving in and out of those wallets
+type EnterpriseRepository interface {
+	// Create saves the organization together with its owner
+	Create(enterprise *models.Enterprise, owner *models.EnterpriseMember) error
+	GetByID(id uint) (*models.Enterprise, error)
+	// GetByUserID returns the organizations the user is a member of
+	GetByUserID(userID uint) ([]models.Enterprise, error)
+	RegistrationNoTaken(registrationNo string, excludeID uint) (bool, error)
+	Update(enterprise *models.Enterprise) error
+
+	GetMember(enterpriseID, userID uint) (*models.EnterpriseMember, error)
+	ListMembers(enterpriseID uint) ([]models.EnterpriseMember, error)
+	AddMember(member *models.EnterpriseMember) error
+	UpdateMember(member *models.EnterpriseMember) error
+	RemoveMember(enterpriseID, userID uint) error
+	CountMembers(enterpriseID uint, role string) (int64, error)
+	// SumMemberSpending totals a member's payments since the given time,
+	// counting those still awaiting approval
+	SumMemberSpending(enterpriseID, userID uint, since time.Time) (float64, error)
+
+	CreateWallet(wallet *models.EnterpriseWallet) error
+	GetWallet(enterpriseID, walletID uint) (*models.EnterpriseWallet, error)
+	ListWallets(enterpriseID uint) ([]models.EnterpriseWallet, error)
+	UpdateWallet(wallet *models.EnterpriseWallet) error
+
+	// Fund moves money from the member's personal wallet into the
+	// organization wallet
+	Fund(funding *models.EnterpriseFunding, tx *models.Transaction) error
+	// ExecutePayment pays the recipient out of the organization wallet and
+	// saves the payment as completed. A payment that was held for approval
+	// needs the review that releases it.
+	ExecutePayment(payment *models.EnterprisePayment, tx *models.Transaction, review *EnterpriseReview) error
+	// HoldPayment saves a payment as pending approval
+	HoldPayment(payment *models.EnterprisePayment, note string) error
+	// ClosePayment rejects or cancels a payment pending approval
+	ClosePayment(enterpriseID, paymentID, actorID uint, status, note string) (*models.EnterprisePayment, error)
+	GetPayment(enterpriseID, paymentID uint) (*models.EnterprisePayment, error)
+	ListPayments(enterpriseID uint, filter EnterprisePaymentFilter, limit, offset int) ([]models.EnterprisePayment, int64, error)
+	ListPaymentEvents(enterpriseID, paymentID uint) ([]models.EnterprisePaymentEvent, error)
+
+	CreateRule(rule *models.EnterpriseApprovalRule) error
+	GetRule(enterpriseID, ruleID uint) (*models.EnterpriseApprovalRule, error)
+	ListRules(enterpriseID uint, activeOnly bool) ([]models.EnterpriseApprovalRule, error)
+	UpdateRule(rule *models.EnterpriseApprovalRule) error
+	DeleteRule(enterpriseID, ruleID uint) error
+
+	// Statement totals sum an organization's completed activity in [from, to)
+	GetWalletTotals(enterpriseID uint, from, to time.Time) ([]EnterpriseWalletTotals, error)
+	GetMemberTotals(enterpriseID uint, from, to time.Time) ([]EnterpriseMemberTotals, error)
+	GetCategoryTotals(enterpriseID uint, from, to time.Time) ([]EnterpriseCategoryTotals, error)
+}
+
+type enterpriseRepository struct {
+	db *gorm.DB
+}
+
+func NewEnterpriseRepository(db *gorm.DB) EnterpriseRepository {
+	return &enterpriseRepository{db: db}
+}
+
+// enterpriseOmit leaves out the legacy profile's associations and its
+// array column, which the organization API doesn't manage
+var enterpriseOmit = []string{clause.Associations, "IPWhitelist"}
+
+func (r *enterpriseRepository) Create(enterprise *models.Enterprise, owner *models.EnterpriseMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(enterpriseOmit...).Create(enterprise).Error; err != nil {
+			return fmt.Errorf("failed to create organization: %w", err)
+		}
+		owner.EnterpriseID = enterprise.ID
+		if err := tx.Create(owner).Error; err != nil {
+			return fmt.Errorf("failed to add organization owner: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *enterpriseRepository) GetByID(id uint) (*models.Enterprise, error) {
+	var enterprise models.Enterprise
+	if err := r.db.Omit(enterpriseOmit...).First(&enterprise, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnterpriseNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &enterprise, nil
+}
+
+func (r *enterpriseRepository) GetByUserID(userID uint) ([]models.Enterprise, error) {
+	var enterprises []models.Enterprise
+	err := r.db.Omit(enterpriseOmit...).
+		Joins("JOIN enterprise_members ON enterprise_members.enterprise_id = enterprises.id").
+		Where("enterprise_members.user_id = ?", userID).
+		Order("enterprises.id").
+		Find(&enterprises).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organizations: %w", err)
+	}
+	return enterprises, nil
+}
+
+func (r *enterpriseRepository) RegistrationNoTaken(registrationNo string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Enterprise{}).
+		Where("company_registration_no = ? AND id <> ?", registrationNo, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check registration number: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *enterpriseRepository) Update(enterprise *models.Enterprise) error {
+	if err := r.db.Omit(enterpriseOmit...).Save(enterprise).Error; err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) GetMember(enterpriseID, userID uint) (*models.EnterpriseMember, error) {
+	var member models.EnterpriseMember
+	if err := r.db.Where("enterprise_id = ? AND user_id = ?", enterpriseID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnterpriseMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	return &member, nil
+}
+
+func (r *enterpriseRepository) ListMembers(enterpriseID uint) ([]models.EnterpriseMember, error) {
+	var members []models.EnterpriseMember
+	if err := r.db.Where("enterprise_id = ?", enterpriseID).Order("id").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	return members, nil
+}
+
+func (r *enterpriseRepository) AddMember(member *models.EnterpriseMember) error {
+	if err := r.db.Create(member).Error; err != nil {
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) UpdateMember(member *models.EnterpriseMember) error {
+	if err := r.db.Save(member).Error; err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) RemoveMember(enterpriseID, userID uint) error {
+	result := r.db.Where("enterprise_id = ? AND user_id = ?", enterpriseID, userID).Delete(&models.EnterpriseMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove organization member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrEnterpriseMemberNotFound
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) CountMembers(enterpriseID uint, role string) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.EnterpriseMember{}).Where("enterprise_id = ?", enterpriseID)
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count organization members: %w", err)
+	}
+	return count, nil
+}
+
+func (r *enterpriseRepository) SumMemberSpending(enterpriseID, userID uint, since time.Time) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.EnterprisePayment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("enterprise_id = ? AND requested_by = ? AND created_at >= ? AND status IN ?",
+			enterpriseID, userID, since, []string{models.EnterprisePaymentCompleted, models.EnterprisePaymentPendingApproval}).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum member spending: %w", err)
+	}
+	return total, nil
+}
+
+func (r *enterpriseRepository) CreateWallet(wallet *models.EnterpriseWallet) error {
+	if err := r.db.Create(wallet).Error; err != nil {
+		return fmt.Errorf("failed to create organization wallet: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) GetWallet(enterpriseID, walletID uint) (*models.EnterpriseWallet, error) {
+	var wallet models.EnterpriseWallet
+	if err := r.db.Where("id = ? AND enterprise_id = ?", walletID, enterpriseID).First(&wallet).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnterpriseWalletNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+func (r *enterpriseRepository) ListWallets(enterpriseID uint) ([]models.EnterpriseWallet, error) {
+	var wallets []models.EnterpriseWallet
+	if err := r.db.Where("enterprise_id = ?", enterpriseID).Order("id").Find(&wallets).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organization wallets: %w", err)
+	}
+	return wallets, nil
+}
+
+func (r *enterpriseRepository) UpdateWallet(wallet *models.EnterpriseWallet) error {
+	// Balance only moves through Fund and ExecutePayment
+	if err := r.db.Omit("balance").Save(wallet).Error; err != nil {
+		return fmt.Errorf("failed to update organization wallet: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) Fund(funding *models.EnterpriseFunding, transaction *models.Transaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Personal wallets are locked before organization ones, as in ExecutePayment
+		if _, err := NewWalletRepository(tx).AdjustBalance(funding.FundedBy, -funding.Amount); err != nil {
+			if debitRefused(err) {
+				return ErrInsufficientPersonalFunds
+			}
+			return err
+		}
+		wallet, err := lockEnterpriseWallet(tx, funding.EnterpriseID, funding.WalletID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(wallet).Update("balance", math.Round((wallet.Balance+funding.Amount)*100)/100).Error; err != nil {
+			return fmt.Errorf("failed to credit organization wallet: %w", err)
+		}
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to record funding: %w", err)
+		}
+		funding.TransactionID = transaction.ID
+		if err := tx.Create(funding).Error; err != nil {
+			return fmt.Errorf("failed to save funding: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *enterpriseRepository) ExecutePayment(payment *models.EnterprisePayment, transaction *models.Transaction, review *EnterpriseReview) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		actorID := payment.RequestedBy
+		if payment.ID == 0 {
+			payment.Status = models.EnterprisePaymentCompleted
+			if err := tx.Create(payment).Error; err != nil {
+				return fmt.Errorf("failed to create organization payment: %w", err)
+			}
+		} else {
+			if review == nil {
+				return ErrEnterprisePaymentNotOpen
+			}
+			result := tx.Model(&models.EnterprisePayment{}).
+				Where("id = ? AND status = ?", payment.ID, models.EnterprisePaymentPendingApproval).
+				Updates(map[string]interface{}{
+					"status":      models.EnterprisePaymentCompleted,
+					"reviewed_by": review.ReviewerID,
+					"reviewed_at": now,
+					"review_note": review.Note,
+				})
+			if result.Error != nil {
+				return fmt.Errorf("failed to approve organization payment: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return ErrEnterprisePaymentNotOpen
+			}
+			payment.Status = models.EnterprisePaymentCompleted
+			payment.ReviewedBy = &review.ReviewerID
+			payment.ReviewedAt = &now
+			payment.ReviewNote = review.Note
+			actorID = review.ReviewerID
+			if err := recordEnterprisePaymentEvent(tx, payment, actorID, models.EnterprisePaymentEventApproved, review.Note); err != nil {
+				return err
+			}
+		}
+
+		// Personal wallets are locked before organization ones, as in Fund
+		if _, err := NewWalletRepository(tx).AdjustBalance(payment.RecipientID, payment.Amount); err != nil {
+			return fmt.Errorf("failed to credit recipient wallet: %w", err)
+		}
+		wallet, err := lockEnterpriseWallet(tx, payment.EnterpriseID, payment.WalletID)
+		if err != nil {
+			return err
+		}
+		if wallet.Balance < payment.Amount {
+			return ErrInsufficientEnterpriseFunds
+		}
+
+		if err := tx.Model(wallet).Update("balance", math.Round((wallet.Balance-payment.Amount)*100)/100).Error; err != nil {
+			return fmt.Errorf("failed to debit organization wallet: %w", err)
+		}
+
+		transaction.Metadata = models.NewJSON(map[string]interface{}{
+			"enterprise_id":         payment.EnterpriseID,
+			"enterprise_wallet_id":  payment.WalletID,
+			"enterprise_payment_id": payment.ID,
+			"direction":             "out",
+		})
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to record organization payment: %w", err)
+		}
+		payment.TransactionID = &transaction.ID
+		if err := tx.Model(payment).Update("transaction_id", transaction.ID).Error; err != nil {
+			return fmt.Errorf("failed to link organization payment: %w", err)
+		}
+		return recordEnterprisePaymentEvent(tx, payment, actorID, models.EnterprisePaymentEventExecuted, transaction.TransactionID)
+	})
+}
+
+func (r *enterpriseRepository) HoldPayment(payment *models.EnterprisePayment, note string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		payment.Status = models.EnterprisePaymentPendingApproval
+		if err := tx.Create(payment).Error; err != nil {
+			return fmt.Errorf("failed to create organization payment: %w", err)
+		}
+		return recordEnterprisePaymentEvent(tx, payment, payment.RequestedBy, models.EnterprisePaymentEventApprovalRequired, note)
+	})
+}
+
+func (r *enterpriseRepository) ClosePayment(enterpriseID, paymentID, actorID uint, status, note string) (*models.EnterprisePayment, error) {
+	updates := map[string]interface{}{"status": status}
+	action := models.EnterprisePaymentEventCancelled
+	if status == models.EnterprisePaymentRejected {
+		updates["reviewed_by"] = actorID
+		updates["reviewed_at"] = time.Now()
+		updates["review_note"] = note
+		action = models.EnterprisePaymentEventRejected
+	}
+
+	var payment *models.EnterprisePayment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.EnterprisePayment{}).
+			Where("id = ? AND enterprise_id = ? AND status = ?", paymentID, enterpriseID, models.EnterprisePaymentPendingApproval).
+			Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to close organization payment: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			if _, err := getEnterprisePayment(tx, enterpriseID, paymentID); err != nil {
+				return err
+			}
+			return ErrEnterprisePaymentNotOpen
+		}
+		var err error
+		if payment, err = getEnterprisePayment(tx, enterpriseID, paymentID); err != nil {
+			return err
+		}
+		return recordEnterprisePaymentEvent(tx, payment, actorID, action, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *enterpriseRepository) GetPayment(enterpriseID, paymentID uint) (*models.EnterprisePayment, error) {
+	return getEnterprisePayment(r.db, enterpriseID, paymentID)
+}
+
+func (r *enterpriseRepository) ListPayments(enterpriseID uint, filter EnterprisePaymentFilter, limit, offset int) ([]models.EnterprisePayment, int64, error) {
+	var payments []models.EnterprisePayment
+	var total int64
+
+	query := r.db.Model(&models.EnterprisePayment{}).Where("enterprise_id = ?", enterpriseID)
+	if filter.WalletID != 0 {
+		query = query.Where("wallet_id = ?", filter.WalletID)
+	}
+	if filter.RequestedBy != 0 {
+		query = query.Where("requested_by = ?", filter.RequestedBy)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if len(filter.ApproverRoles) > 0 {
+		query = query.Where("approver_role IN ?", filter.ApproverRoles)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count organization payments: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&payments).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get organization payments: %w", err)
+	}
+	return payments, total, nil
+}
+
+func (r *enterpriseRepository) ListPaymentEvents(enterpriseID, paymentID uint) ([]models.EnterprisePaymentEvent, error) {
+	var events []models.EnterprisePaymentEvent
+	err := r.db.Where("enterprise_id = ? AND payment_id = ?", enterpriseID, paymentID).
+		Order("id").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization payment events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *enterpriseRepository) CreateRule(rule *models.EnterpriseApprovalRule) error {
+	if err := r.db.Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create approval rule: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) GetRule(enterpriseID, ruleID uint) (*models.EnterpriseApprovalRule, error) {
+	var rule models.EnterpriseApprovalRule
+	if err := r.db.Where("id = ? AND enterprise_id = ?", ruleID, enterpriseID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApprovalRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get approval rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *enterpriseRepository) ListRules(enterpriseID uint, activeOnly bool) ([]models.EnterpriseApprovalRule, error) {
+	var rules []models.EnterpriseApprovalRule
+	query := r.db.Where("enterprise_id = ?", enterpriseID)
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Order("id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list approval rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *enterpriseRepository) UpdateRule(rule *models.EnterpriseApprovalRule) error {
+	if err := r.db.Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to update approval rule: %w", err)
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) DeleteRule(enterpriseID, ruleID uint) error {
+	result := r.db.Where("id = ? AND enterprise_id = ?", ruleID, enterpriseID).Delete(&models.EnterpriseApprovalRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete approval rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrApprovalRuleNotFound
+	}
+	return nil
+}
+
+func (r *enterpriseRepository) GetWalletTotals(enterpriseID uint, from, to time.Time) ([]EnterpriseWalletTotals, error) {
+	var spent []EnterpriseWalletTotals
+	err := r.db.Model(&models.EnterprisePayment{}).
+		Select("wallet_id, COALESCE(SUM(amount), 0) AS spent, COUNT(*) AS payments").
+		Where("enterprise_id = ? AND status = ? AND created_at >= ? AND created_at < ?",
+			enterpriseID, models.EnterprisePaymentCompleted, from, to).
+		Group("wallet_id").
+		Scan(&spent).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to total organization payments: %w", err)
+	}
+
+	var funded []EnterpriseWalletTotals
+	err = r.db.Model(&models.EnterpriseFunding{}).
+		Select("wallet_id, COALESCE(SUM(amount), 0) AS funded").
+		Where("enterprise_id = ? AND created_at >= ? AND created_at < ?", enterpriseID, from, to).
+		Group("wallet_id").
+		Scan(&funded).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to total organization fundings: %w", err)
+	}
+
+	byWallet := make(map[uint]*EnterpriseWalletTotals, len(spent)+len(funded))
+	totals := make([]EnterpriseWalletTotals, 0, len(spent)+len(funded))
+	for _, t := range spent {
+		totals = append(totals, t)
+	}
+	for i := range totals {
+		byWallet[totals[i].WalletID] = &totals[i]
+	}
+	for _, t := range funded {
+		if existing, ok := byWallet[t.WalletID]; ok {
+			existing.Funded = t.Funded
+			continue
+		}
+		totals = append(totals, t)
+	}
+	return totals, nil
+}
+
+func (r *enterpriseRepository) GetMemberTotals(enterpriseID uint, from, to time.Time) ([]EnterpriseMemberTotals, error) {
+	var totals []EnterpriseMemberTotals
+	err := r.db.Model(&models.EnterprisePayment{}).
+		Select("requested_by AS user_id, COALESCE(SUM(amount), 0) AS spent, COUNT(*) AS payments").
+		Where("enterprise_id = ? AND status = ? AND created_at >= ? AND created_at < ?",
+			enterpriseID, models.EnterprisePaymentCompleted, from, to).
+		Group("requested_by").
+		Order("spent DESC").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to total member spending: %w", err)
+	}
+	return totals, nil
+}
+
+func (r *enterpriseRepository) GetCategoryTotals(enterpriseID uint, from, to time.Time) ([]EnterpriseCategoryTotals, error) {
+	var totals []EnterpriseCategoryTotals
+	err := r.db.Model(&models.EnterprisePayment{}).
+		Select("COALESCE(category, '') AS category, COALESCE(SUM(amount), 0) AS spent, COUNT(*) AS payments").
+		Where("enterprise_id = ? AND status = ? AND created_at >= ? AND created_at < ?",
+			enterpriseID, models.EnterprisePaymentCompleted, from, to).
+		Group("COALESCE(category, '')").
+		Order("spent DESC").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to total category spending: %w", err)
+	}
+	return totals, nil
+}
+
+func lockEnterpriseWallet(tx *gorm.DB, enterpriseID, walletID uint) (*models.EnterpriseWallet, error) {
+	var wallet models.EnterpriseWallet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND enterprise_id = ?", walletID, enterpriseID).
+		First(&wallet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnterpriseWalletNotFound
+		}
+		return nil, fmt.Errorf("failed to lock organization wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+func getEnterprisePayment(db *gorm.DB, enterpriseID, paymentID uint) (*models.EnterprisePayment, error) {
+	var payment models.EnterprisePayment
+	if err := db.Where("id = ? AND enterprise_id = ?", paymentID, enterpriseID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnterprisePaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization payment: %w", err)
+	}
+	return &payment, nil
+}
+
+func recordEnterprisePaymentEvent(tx *gorm.DB, payment *models.EnterprisePayment, actorID uint, action, note string) error {
+	event := &models.EnterprisePaymentEvent{
+		EnterpriseID: payment.EnterpriseID,
+		PaymentID:    payment.ID,
+		ActorID:      actorID,
+		Action:       action,
+		Note:         note,
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record organization payment event: %w", err)
+	}
+	return nil
+}
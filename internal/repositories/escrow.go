@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEscrowNotFound         = errors.New("escrow not found")
+	ErrEscrowNotOpen          = errors.New("escrow has already been settled")
+	ErrInsufficientBuyerFunds = errors.New("insufficient funds for escrow")
+	ErrEscrowAlreadyDisputed  = errors.New("escrow is already disputed")
+	ErrEscrowStatusNotAllowed = errors.New("escrow is not in a state that allows this")
+)
+
+// EscrowSettlement pays an escrow out to the seller or back to the buyer
+type EscrowSettlement struct {
+	EscrowID uint
+	// From lists the statuses the escrow may be in; anything else fails
+	// with ErrEscrowStatusNotAllowed
+	From        []string
+	Status      string // EscrowStatusReleased or EscrowStatusRefunded
+	Reason      string
+	Transaction *models.Transaction
+}
+
+// EscrowRepository persists escrow payments and moves their funds through
+// the escrow system account
+type EscrowRepository interface {
+	// Fund debits the buyer's wallet into the escrow account and creates the escrow
+	Fund(escrow *models.Escrow, tx *models.Transaction) error
+	// Settle credits the escrowed amount to the seller or buyer
+	Settle(settlement EscrowSettlement) (*models.Escrow, error)
+	MarkDisputed(id, userID, disputeID uint) error
+	GetByID(id uint) (*models.Escrow, error)
+	List(userID uint, role, status string, limit, offset int) ([]models.Escrow, int64, error)
+	GetDueForRelease(now time.Time, limit int) ([]models.Escrow, error)
+}
+
+type escrowRepository struct {
+	db *gorm.DB
+}
+
+func NewEscrowRepository(db *gorm.DB) EscrowRepository {
+	return &escrowRepository{db: db}
+}
+
+func (r *escrowRepository) Fund(escrow *models.Escrow, tx *models.Transaction) error {
+	return r.db.Transaction(func(db *gorm.DB) error {
+		if _, err := NewWalletRepository(db).AdjustBalance(escrow.BuyerID, -escrow.Amount); err != nil {
+			if debitRefused(err) {
+				return ErrInsufficientBuyerFunds
+			}
+			return fmt.Errorf("failed to debit buyer wallet: %w", err)
+		}
+
+		escrow.Status = models.EscrowStatusFunded
+		if err := db.Create(escrow).Error; err != nil {
+			return fmt.Errorf("failed to create escrow: %w", err)
+		}
+
+		tx.Metadata = models.NewJSON(map[string]interface{}{
+			"escrow_id": escrow.ID,
+			"direction": "fund",
+		})
+		if err := db.Create(tx).Error; err != nil {
+			return fmt.Errorf("failed to record escrow funding: %w", err)
+		}
+		if err := models.PostSystemEntry(db, models.SystemAccountEscrow, &models.SystemLedgerEntry{
+			Kind:          models.SystemEntryEscrowIn,
+			Amount:        escrow.Amount,
+			TransactionID: &tx.ID,
+			Description:   fmt.Sprintf("Escrow #%d funded", escrow.ID),
+		}); err != nil {
+			return err
+		}
+
+		escrow.FundingTransactionID = &tx.ID
+		if err := db.Model(escrow).Update("funding_transaction_id", tx.ID).Error; err != nil {
+			return fmt.Errorf("failed to link escrow funding: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *escrowRepository) Settle(settlement EscrowSettlement) (*models.Escrow, error) {
+	var escrow models.Escrow
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&escrow, settlement.EscrowID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEscrowNotFound
+			}
+			return fmt.Errorf("failed to lock escrow: %w", err)
+		}
+		allowed := false
+		for _, status := range settlement.From {
+			allowed = allowed || escrow.Status == status
+		}
+		if !allowed {
+			if escrow.Status == models.EscrowStatusReleased || escrow.Status == models.EscrowStatusRefunded {
+				return ErrEscrowNotOpen
+			}
+			return ErrEscrowStatusNotAllowed
+		}
+
+		recipientID := escrow.SellerID
+		direction := "release"
+		if settlement.Status == models.EscrowStatusRefunded {
+			recipientID = escrow.BuyerID
+			direction = "refund"
+		}
+
+		if _, err := NewWalletRepository(db).AdjustBalance(recipientID, escrow.Amount); err != nil {
+			return fmt.Errorf("failed to credit recipient wallet: %w", err)
+		}
+
+		tx := settlement.Transaction
+		tx.Metadata = models.NewJSON(map[string]interface{}{
+			"escrow_id": escrow.ID,
+			"direction": direction,
+			"reason":    settlement.Reason,
+		})
+		if err := db.Create(tx).Error; err != nil {
+			return fmt.Errorf("failed to record escrow settlement: %w", err)
+		}
+		if err := models.PostSystemEntry(db, models.SystemAccountEscrow, &models.SystemLedgerEntry{
+			Kind:          models.SystemEntryEscrowOut,
+			Amount:        -escrow.Amount,
+			TransactionID: &tx.ID,
+			Description:   fmt.Sprintf("Escrow #%d %s", escrow.ID, settlement.Status),
+		}); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		escrow.Status = settlement.Status
+		escrow.SettleTransactionID = &tx.ID
+		escrow.SettledReason = settlement.Reason
+		escrow.SettledAt = &now
+		if err := db.Model(&escrow).Updates(map[string]interface{}{
+			"status":                escrow.Status,
+			"settle_transaction_id": tx.ID,
+			"settled_reason":        escrow.SettledReason,
+			"settled_at":            now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to settle escrow: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &escrow, nil
+}
+
+func (r *escrowRepository) MarkDisputed(id, userID, disputeID uint) error {
+	result := r.db.Model(&models.Escrow{}).
+		Where("id = ? AND status = ?", id, models.EscrowStatusFunded).
+		Updates(map[string]interface{}{
+			"status":      models.EscrowStatusDisputed,
+			"dispute_id":  disputeID,
+			"disputed_by": userID,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark escrow disputed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		escrow, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if escrow.Status == models.EscrowStatusDisputed {
+			return ErrEscrowAlreadyDisputed
+		}
+		return ErrEscrowNotOpen
+	}
+	return nil
+}
+
+func (r *escrowRepository) GetByID(id uint) (*models.Escrow, error) {
+	var escrow models.Escrow
+	if err := r.db.First(&escrow, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEscrowNotFound
+		}
+		return nil, fmt.Errorf("failed to get escrow: %w", err)
+	}
+	return &escrow, nil
+}
+
+func (r *escrowRepository) List(userID uint, role, status string, limit, offset int) ([]models.Escrow, int64, error) {
+	var escrows []models.Escrow
+	var total int64
+
+	query := r.db.Model(&models.Escrow{})
+	switch role {
+	case "buyer":
+		query = query.Where("buyer_id = ?", userID)
+	case "seller":
+		query = query.Where("seller_id = ?", userID)
+	default:
+		query = query.Where("buyer_id = ? OR seller_id = ?", userID, userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count escrows: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&escrows).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get escrows: %w", err)
+	}
+	return escrows, total, nil
+}
+
+func (r *escrowRepository) GetDueForRelease(now time.Time, limit int) ([]models.Escrow, error) {
+	var escrows []models.Escrow
+	err := r.db.Where("status = ? AND auto_release_at <= ?", models.EscrowStatusFunded, now).
+		Order("auto_release_at").Limit(limit).Find(&escrows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escrows due for release: %w", err)
+	}
+	return escrows, nil
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrExportScheduleNotFound = errors.New("export schedule not found")
+
+// ExportScheduleRepository persists scheduled transaction exports and their runs
+type ExportScheduleRepository interface {
+	Create(schedule *models.ExportSchedule) error
+	GetByIDAndUserID(id, userID uint) (*models.ExportSchedule, error)
+	GetByUserID(userID uint) ([]models.ExportSchedule, error)
+	Update(schedule *models.ExportSchedule) error
+	Delete(id, userID uint) error
+	GetDue(now time.Time, limit int) ([]models.ExportSchedule, error)
+	ClaimRun(schedule *models.ExportSchedule, nextRunAt time.Time) (bool, error)
+
+	CreateRun(run *models.ExportRun) error
+	GetRuns(scheduleID uint, limit, offset int) ([]models.ExportRun, int64, error)
+	GetUserTransactionsBetween(userID uint, start, end time.Time) ([]models.Transaction, error)
+}
+
+type exportScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewExportScheduleRepository(db *gorm.DB) ExportScheduleRepository {
+	return &exportScheduleRepository{db: db}
+}
+
+func (r *exportScheduleRepository) Create(schedule *models.ExportSchedule) error {
+	if err := r.db.Create(schedule).Error; err != nil {
+		return fmt.Errorf("failed to create export schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *exportScheduleRepository) GetByIDAndUserID(id, userID uint) (*models.ExportSchedule, error) {
+	var schedule models.ExportSchedule
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get export schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+func (r *exportScheduleRepository) GetByUserID(userID uint) ([]models.ExportSchedule, error) {
+	var schedules []models.ExportSchedule
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to get export schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+func (r *exportScheduleRepository) Update(schedule *models.ExportSchedule) error {
+	if err := r.db.Save(schedule).Error; err != nil {
+		return fmt.Errorf("failed to update export schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *exportScheduleRepository) Delete(id, userID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.ExportSchedule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete export schedule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrExportScheduleNotFound
+	}
+	return nil
+}
+
+func (r *exportScheduleRepository) GetDue(now time.Time, limit int) ([]models.ExportSchedule, error) {
+	var schedules []models.ExportSchedule
+	err := r.db.Where("status = ? AND next_run_at <= ?", models.ExportScheduleStatusActive, now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due export schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// ClaimRun advances next_run_at only if no other worker has already done so,
+// making the caller the single executor of this run
+func (r *exportScheduleRepository) ClaimRun(schedule *models.ExportSchedule, nextRunAt time.Time) (bool, error) {
+	result := r.db.Model(&models.ExportSchedule{}).
+		Where("id = ? AND next_run_at = ?", schedule.ID, schedule.NextRunAt).
+		Update("next_run_at", nextRunAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim export run: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	schedule.NextRunAt = nextRunAt
+	return true, nil
+}
+
+func (r *exportScheduleRepository) CreateRun(run *models.ExportRun) error {
+	if err := r.db.Create(run).Error; err != nil {
+		return fmt.Errorf("failed to record export run: %w", err)
+	}
+	return nil
+}
+
+func (r *exportScheduleRepository) GetRuns(scheduleID uint, limit, offset int) ([]models.ExportRun, int64, error) {
+	var runs []models.ExportRun
+	var total int64
+
+	query := r.db.Model(&models.ExportRun{}).Where("schedule_id = ?", scheduleID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count export runs: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get export runs: %w", err)
+	}
+	return runs, total, nil
+}
+
+func (r *exportScheduleRepository) GetUserTransactionsBetween(userID uint, start, end time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Where("(sender_id = ? OR receiver_id = ?) AND updated_at >= ? AND updated_at < ?",
+		userID, userID, start, end).
+		Order("updated_at ASC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions for export: %w", err)
+	}
+	return transactions, nil
+}
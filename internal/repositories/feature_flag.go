@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrFeatureFlagOverrideNotFound = errors.New("feature flag override not found")
+
+// FeatureFlagRepository persists admin overrides of the feature flags
+type FeatureFlagRepository interface {
+	List() ([]models.FeatureFlagOverride, error)
+	// Save creates the flag's override or replaces its existing one
+	Save(override *models.FeatureFlagOverride) error
+	Delete(key string) error
+}
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+func (r *featureFlagRepository) List() ([]models.FeatureFlagOverride, error) {
+	var overrides []models.FeatureFlagOverride
+	if err := r.db.Order("key").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list feature flag overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (r *featureFlagRepository) Save(override *models.FeatureFlagOverride) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "reason", "updated_by", "updated_at"}),
+	}).Create(override).Error
+	if err != nil {
+		return fmt.Errorf("failed to save feature flag override: %w", err)
+	}
+	return nil
+}
+
+func (r *featureFlagRepository) Delete(key string) error {
+	result := r.db.Where("key = ?", key).Delete(&models.FeatureFlagOverride{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete feature flag override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrFeatureFlagOverrideNotFound
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrFraudRulesNotFound = errors.New("fraud rules not found")
+
+// FraudRuleRepository persists merchant self-service fraud rules
+type FraudRuleRepository interface {
+	GetByMerchantID(merchantID uint) (*models.MerchantFraudRules, error)
+	Save(rules *models.MerchantFraudRules) error
+	// CountCustomerCharges counts a customer's completed payments to a merchant since the given time
+	CountCustomerCharges(merchantID, customerID uint, since time.Time) (int64, error)
+}
+
+type fraudRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewFraudRuleRepository(db *gorm.DB) FraudRuleRepository {
+	return &fraudRuleRepository{db: db}
+}
+
+func (r *fraudRuleRepository) GetByMerchantID(merchantID uint) (*models.MerchantFraudRules, error) {
+	var rules models.MerchantFraudRules
+	if err := r.db.Where("merchant_id = ?", merchantID).First(&rules).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFraudRulesNotFound
+		}
+		return nil, fmt.Errorf("failed to get fraud rules: %w", err)
+	}
+	return &rules, nil
+}
+
+func (r *fraudRuleRepository) Save(rules *models.MerchantFraudRules) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "merchant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"max_charge_amount", "max_charges_per_customer_daily", "blocked_countries", "updated_at",
+		}),
+	}).Create(rules).Error
+	if err != nil {
+		return fmt.Errorf("failed to save fraud rules: %w", err)
+	}
+	return nil
+}
+
+func (r *fraudRuleRepository) CountCustomerCharges(merchantID, customerID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Transaction{}).
+		Where("receiver_id = ? AND sender_id = ? AND status = ? AND created_at >= ?",
+			merchantID, customerID, "completed", since).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count customer charges: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvestigationTransactionNotFound = errors.New("transaction not found")
+
+// InvestigationRepository gathers the records linked to a transaction for
+// admins investigating it. Transactions are looked up in both the hot
+// table and the archive.
+type InvestigationRepository interface {
+	// GetTransaction finds the transaction and reports whether it has
+	// been archived
+	GetTransaction(id uint) (*models.Transaction, bool, error)
+	// GetTransactions finds the transactions with the given IDs
+	GetTransactions(ids []uint) ([]models.Transaction, error)
+	// GetLinked returns the transactions that point back to this one:
+	// refunds referencing it, and reversals, chargebacks and dispute
+	// refunds naming it as their original
+	GetLinked(transactionID uint) ([]models.Transaction, error)
+	// GetReversals returns reversal records the transaction is the
+	// original or the compensating transaction of
+	GetReversals(transactionID uint) ([]models.TransactionReversal, error)
+	GetDisputes(transactionID uint) ([]models.Dispute, error)
+	GetChargebacks(transactionID uint) ([]models.Chargeback, error)
+	// GetLedgerEntries returns the system ledger entries the transaction booked
+	GetLedgerEntries(transactionID uint) ([]models.SystemLedgerEntry, error)
+	// GetQRCode finds the QR code by its code, or nil if it is gone
+	GetQRCode(code string) (*models.QRCode, error)
+	// GetLoginBefore returns the user's last completed login at or before
+	// the time, including ones they later reported, or nil if there is none
+	GetLoginBefore(userID uint, at time.Time) (*models.LoginEvent, error)
+	// GetRecentLogins returns the user's latest logins, newest first
+	GetRecentLogins(userID uint, limit int) ([]models.LoginEvent, error)
+}
+
+type investigationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvestigationRepository(db *gorm.DB) InvestigationRepository {
+	return &investigationRepository{db: db}
+}
+
+func (r *investigationRepository) GetTransaction(id uint) (*models.Transaction, bool, error) {
+	var transaction models.Transaction
+	err := r.db.First(&transaction, id).Error
+	if err == nil {
+		return &transaction, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var archived models.ArchivedTransaction
+	err = r.db.First(&archived, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, ErrInvestigationTransactionNotFound
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get archived transaction: %w", err)
+	}
+	return &archived.Transaction, true, nil
+}
+
+func (r *investigationRepository) GetTransactions(ids []uint) ([]models.Transaction, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.findBoth("id IN @ids", map[string]interface{}{"ids": ids})
+}
+
+func (r *investigationRepository) GetLinked(transactionID uint) ([]models.Transaction, error) {
+	id := strconv.FormatUint(uint64(transactionID), 10)
+	return r.findBoth(`id <> @id AND ((type = @refund AND reference = @ref) OR metadata->>'original_transaction_id' = @ref)`,
+		map[string]interface{}{"id": transactionID, "ref": id, "refund": models.TransactionTypeRefund})
+}
+
+// findBoth reads matching transactions from the hot table and the archive,
+// oldest first
+func (r *investigationRepository) findBoth(where string, args map[string]interface{}) ([]models.Transaction, error) {
+	columns, err := transactionColumns(r.db)
+	if err != nil {
+		return nil, err
+	}
+	var transactions []models.Transaction
+	err = r.db.Raw(`SELECT `+columns+` FROM transactions WHERE `+where+`
+		UNION ALL
+		SELECT `+columns+` FROM archived_transactions WHERE `+where+`
+		ORDER BY id`, args).
+		Scan(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *investigationRepository) GetReversals(transactionID uint) ([]models.TransactionReversal, error) {
+	var reversals []models.TransactionReversal
+	err := r.db.Where("original_transaction_id = ? OR reversal_transaction_id = ?", transactionID, transactionID).
+		Order("id").Find(&reversals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reversals: %w", err)
+	}
+	return reversals, nil
+}
+
+func (r *investigationRepository) GetDisputes(transactionID uint) ([]models.Dispute, error) {
+	var disputes []models.Dispute
+	if err := r.db.Where("transaction_id = ?", transactionID).Order("id").Find(&disputes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get disputes: %w", err)
+	}
+	return disputes, nil
+}
+
+func (r *investigationRepository) GetChargebacks(transactionID uint) ([]models.Chargeback, error) {
+	var chargebacks []models.Chargeback
+	if err := r.db.Where("transaction_id = ?", transactionID).Order("id").Find(&chargebacks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get chargebacks: %w", err)
+	}
+	return chargebacks, nil
+}
+
+func (r *investigationRepository) GetLedgerEntries(transactionID uint) ([]models.SystemLedgerEntry, error) {
+	var entries []models.SystemLedgerEntry
+	if err := r.db.Where("transaction_id = ?", transactionID).Order("id").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *investigationRepository) GetQRCode(code string) (*models.QRCode, error) {
+	var qr models.QRCode
+	err := r.db.Unscoped().Where("code = ?", code).First(&qr).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get QR code: %w", err)
+	}
+	return &qr, nil
+}
+
+func (r *investigationRepository) GetLoginBefore(userID uint, at time.Time) (*models.LoginEvent, error) {
+	var event models.LoginEvent
+	err := r.db.Where("user_id = ? AND status IN ? AND created_at <= ?", userID,
+		[]string{models.LoginStatusSucceeded, models.LoginStatusReported}, at).
+		Order("created_at DESC").First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login: %w", err)
+	}
+	return &event, nil
+}
+
+func (r *investigationRepository) GetRecentLogins(userID uint, limit int) ([]models.LoginEvent, error) {
+	var events []models.LoginEvent
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get logins: %w", err)
+	}
+	return events, nil
+}
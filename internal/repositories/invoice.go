@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvoiceNotFound = errors.New("invoice not found")
+
+// InvoiceRepository persists merchant invoices and their line items
+type InvoiceRepository interface {
+	// Create assigns the merchant's next invoice number and saves the invoice
+	Create(invoice *models.Invoice) error
+	GetByIDAndMerchantID(id, merchantID uint) (*models.Invoice, error)
+	GetByPublicCode(code string) (*models.Invoice, error)
+	GetByMerchantID(merchantID uint, status string, limit, offset int) ([]models.Invoice, int64, error)
+	Update(invoice *models.Invoice) error
+	ReplaceLineItems(invoice *models.Invoice) error
+	Delete(invoice *models.Invoice) error
+	TransitionStatus(id uint, from []string, to string) (bool, error)
+	MarkOverdue(now time.Time) (int64, error)
+	GetReminderCandidates(dueBefore time.Time, limit int) ([]models.Invoice, error)
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+func (r *invoiceRepository) Create(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		counter := models.InvoiceCounter{MerchantID: invoice.MerchantID, LastNumber: 1}
+		err := tx.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "merchant_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"last_number": gorm.Expr("invoice_counters.last_number + 1")}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "last_number"}}},
+		).Create(&counter).Error
+		if err != nil {
+			return fmt.Errorf("failed to allocate invoice number: %w", err)
+		}
+
+		invoice.Number = fmt.Sprintf("INV-%d-%06d", invoice.MerchantID, counter.LastNumber)
+		if err := tx.Create(invoice).Error; err != nil {
+			return fmt.Errorf("failed to create invoice: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *invoiceRepository) GetByIDAndMerchantID(id, merchantID uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := r.db.Preload("LineItems").Where("id = ? AND merchant_id = ?", id, merchantID).First(&invoice).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) GetByPublicCode(code string) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := r.db.Preload("LineItems").Where("public_code = ?", code).First(&invoice).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) GetByMerchantID(merchantID uint, status string, limit, offset int) ([]models.Invoice, int64, error) {
+	var invoices []models.Invoice
+	var total int64
+
+	query := r.db.Model(&models.Invoice{}).Where("merchant_id = ?", merchantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	err := query.Preload("LineItems").Order("created_at DESC").Limit(limit).Offset(offset).Find(&invoices).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get invoices: %w", err)
+	}
+	return invoices, total, nil
+}
+
+func (r *invoiceRepository) Update(invoice *models.Invoice) error {
+	if err := r.db.Omit("LineItems").Save(invoice).Error; err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+	return nil
+}
+
+func (r *invoiceRepository) ReplaceLineItems(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.InvoiceLineItem{}).Error; err != nil {
+			return fmt.Errorf("failed to clear line items: %w", err)
+		}
+		for i := range invoice.LineItems {
+			invoice.LineItems[i].ID = 0
+			invoice.LineItems[i].InvoiceID = invoice.ID
+		}
+		if len(invoice.LineItems) > 0 {
+			if err := tx.Create(&invoice.LineItems).Error; err != nil {
+				return fmt.Errorf("failed to save line items: %w", err)
+			}
+		}
+		if err := tx.Omit("LineItems").Save(invoice).Error; err != nil {
+			return fmt.Errorf("failed to update invoice: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *invoiceRepository) Delete(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.InvoiceLineItem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete line items: %w", err)
+		}
+		if err := tx.Delete(invoice).Error; err != nil {
+			return fmt.Errorf("failed to delete invoice: %w", err)
+		}
+		return nil
+	})
+}
+
+// TransitionStatus changes the status only if it is currently one of from,
+// so concurrent actions on the same invoice can't both succeed
+func (r *invoiceRepository) TransitionStatus(id uint, from []string, to string) (bool, error) {
+	result := r.db.Model(&models.Invoice{}).
+		Where("id = ? AND status IN ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update invoice status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkOverdue moves sent invoices past their due date to overdue
+func (r *invoiceRepository) MarkOverdue(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Invoice{}).
+		Where("status = ? AND due_date < ?", models.InvoiceStatusSent, now).
+		Update("status", models.InvoiceStatusOverdue)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark invoices overdue: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetReminderCandidates returns unpaid invoices due before the given time
+func (r *invoiceRepository) GetReminderCandidates(dueBefore time.Time, limit int) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	err := r.db.Where("status IN ? AND due_date < ?",
+		[]string{models.InvoiceStatusSent, models.InvoiceStatusOverdue}, dueBefore).
+		Order("due_date ASC").
+		Limit(limit).
+		Find(&invoices).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices for reminders: %w", err)
+	}
+	return invoices, nil
+}
@@ -1,13 +1,32 @@
 package repositories
 
-import "orus/internal/models"
+import (
+	"orus/internal/models"
 
-func CreateKYC(kyc *models.KYCVerification) error {
-	return DB.Create(kyc).Error
+	"gorm.io/gorm"
+)
+
+type KYCRepository interface {
+	Create(kyc *models.KYCVerification) error
+	GetByUserID(userID uint) (*models.KYCVerification, error)
+}
+
+type kycRepository struct {
+	db *gorm.DB
+}
+
+func NewKYCRepository(db *gorm.DB) KYCRepository {
+	return &kycRepository{db: db}
+}
+
+func (r *kycRepository) Create(kyc *models.KYCVerification) error {
+	return r.db.Create(kyc).Error
 }
 
-func GetKYCByDocumentID(docID string) (*models.KYCVerification, error) {
+func (r *kycRepository) GetByUserID(userID uint) (*models.KYCVerification, error) {
 	var kyc models.KYCVerification
-	err := DB.Where("document_id = ?", docID).First(&kyc).Error
-	return &kyc, err
+	if err := r.db.Where("user_id = ?", userID).First(&kyc).Error; err != nil {
+		return nil, err
+	}
+	return &kyc, nil
 }
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrLoginEventNotFound = errors.New("login not found")
+
+// LoginEventRepository persists users' login history
+type LoginEventRepository interface {
+	Create(ctx context.Context, event *models.LoginEvent) error
+	Update(ctx context.Context, event *models.LoginEvent) error
+	GetByID(ctx context.Context, id uint) (*models.LoginEvent, error)
+	GetByReportTokenHash(ctx context.Context, hash string) (*models.LoginEvent, error)
+	// CountSucceeded counts the user's completed logins
+	CountSucceeded(ctx context.Context, userID uint) (int64, error)
+	// KnownDevice reports whether the user completed a login from the device
+	KnownDevice(ctx context.Context, userID uint, deviceID string) (bool, error)
+	// KnownCountry reports whether the user completed a login from the country
+	KnownCountry(ctx context.Context, userID uint, country string) (bool, error)
+	// ListByUser returns the user's logins, newest first
+	ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.LoginEvent, int64, error)
+}
+
+type loginEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginEventRepository(db *gorm.DB) LoginEventRepository {
+	return &loginEventRepository{db: db}
+}
+
+func (r *loginEventRepository) Create(ctx context.Context, event *models.LoginEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+func (r *loginEventRepository) Update(ctx context.Context, event *models.LoginEvent) error {
+	if err := r.db.WithContext(ctx).Save(event).Error; err != nil {
+		return fmt.Errorf("failed to update login: %w", err)
+	}
+	return nil
+}
+
+func (r *loginEventRepository) GetByID(ctx context.Context, id uint) (*models.LoginEvent, error) {
+	var event models.LoginEvent
+	if err := r.db.WithContext(ctx).First(&event, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLoginEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get login: %w", err)
+	}
+	return &event, nil
+}
+
+func (r *loginEventRepository) GetByReportTokenHash(ctx context.Context, hash string) (*models.LoginEvent, error) {
+	var event models.LoginEvent
+	err := r.db.WithContext(ctx).Where("report_token_hash = ?", hash).First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLoginEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get login: %w", err)
+	}
+	return &event, nil
+}
+
+func (r *loginEventRepository) CountSucceeded(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.LoginEvent{}).
+		Where("user_id = ? AND status = ?", userID, models.LoginStatusSucceeded).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count logins: %w", err)
+	}
+	return count, nil
+}
+
+func (r *loginEventRepository) KnownDevice(ctx context.Context, userID uint, deviceID string) (bool, error) {
+	return r.exists(ctx, "user_id = ? AND status = ? AND device_id = ?", userID, models.LoginStatusSucceeded, deviceID)
+}
+
+func (r *loginEventRepository) KnownCountry(ctx context.Context, userID uint, country string) (bool, error) {
+	return r.exists(ctx, "user_id = ? AND status = ? AND country = ?", userID, models.LoginStatusSucceeded, country)
+}
+
+func (r *loginEventRepository) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.LoginEvent{}).
+		Where(query, args...).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to look up login history: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *loginEventRepository) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.LoginEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.LoginEvent{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count logins: %w", err)
+	}
+
+	var events []models.LoginEvent
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list logins: %w", err)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrLoyaltyProgramNotFound = errors.New("loyalty program not found")
+	ErrLoyaltyAccountNotFound = errors.New("loyalty account not found")
+	ErrInsufficientPoints     = errors.New("insufficient loyalty points")
+	ErrLoyaltyAlreadyPosted   = errors.New("loyalty points already posted for this transaction")
+)
+
+// LoyaltyPosting moves points in or out of a customer's account
+type LoyaltyPosting struct {
+	Program    *models.LoyaltyProgram
+	CustomerID uint
+	Entry      *models.LoyaltyEntry // Kind, Points, Value, TransactionID and Description
+}
+
+// LoyaltyRepository persists merchant loyalty programs and the customers'
+// points sub-ledger
+type LoyaltyRepository interface {
+	GetProgram(merchantID uint) (*models.LoyaltyProgram, error)
+	SaveProgram(program *models.LoyaltyProgram) error
+
+	GetAccount(programID, customerID uint) (*models.LoyaltyAccount, error)
+	GetAccountByID(id uint) (*models.LoyaltyAccount, error)
+	// ListAccounts returns a customer's accounts across merchants
+	ListAccounts(customerID uint) ([]models.LoyaltyAccount, error)
+	ListMembers(programID uint, limit, offset int) ([]models.LoyaltyAccount, int64, error)
+	ListEntries(accountID uint, limit, offset int) ([]models.LoyaltyEntry, int64, error)
+
+	// Post applies the entry to the customer's balance, opening their
+	// account on first use. A debit beyond the balance fails with
+	// ErrInsufficientPoints.
+	Post(posting LoyaltyPosting) (*models.LoyaltyAccount, error)
+	// LinkEntry attaches the payment a redemption was spent on
+	LinkEntry(entryID, transactionID uint) error
+}
+
+type loyaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyRepository(db *gorm.DB) LoyaltyRepository {
+	return &loyaltyRepository{db: db}
+}
+
+func (r *loyaltyRepository) GetProgram(merchantID uint) (*models.LoyaltyProgram, error) {
+	var program models.LoyaltyProgram
+	if err := r.db.Where("merchant_id = ?", merchantID).First(&program).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLoyaltyProgramNotFound
+		}
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+	return &program, nil
+}
+
+func (r *loyaltyRepository) SaveProgram(program *models.LoyaltyProgram) error {
+	if err := r.db.Save(program).Error; err != nil {
+		return fmt.Errorf("failed to save loyalty program: %w", err)
+	}
+	return nil
+}
+
+func (r *loyaltyRepository) GetAccount(programID, customerID uint) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	if err := r.db.Where("program_id = ? AND customer_id = ?", programID, customerID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLoyaltyAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get loyalty account: %w", err)
+	}
+	return &account, nil
+}
+
+func (r *loyaltyRepository) GetAccountByID(id uint) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	if err := r.db.First(&account, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLoyaltyAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get loyalty account: %w", err)
+	}
+	return &account, nil
+}
+
+func (r *loyaltyRepository) ListAccounts(customerID uint) ([]models.LoyaltyAccount, error) {
+	var accounts []models.LoyaltyAccount
+	if err := r.db.Where("customer_id = ?", customerID).Order("balance DESC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get loyalty accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func (r *loyaltyRepository) ListMembers(programID uint, limit, offset int) ([]models.LoyaltyAccount, int64, error) {
+	var accounts []models.LoyaltyAccount
+	var total int64
+
+	query := r.db.Model(&models.LoyaltyAccount{}).Where("program_id = ?", programID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count loyalty members: %w", err)
+	}
+	if err := query.Order("earned DESC").Limit(limit).Offset(offset).Find(&accounts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get loyalty members: %w", err)
+	}
+	return accounts, total, nil
+}
+
+func (r *loyaltyRepository) ListEntries(accountID uint, limit, offset int) ([]models.LoyaltyEntry, int64, error) {
+	var entries []models.LoyaltyEntry
+	var total int64
+
+	query := r.db.Model(&models.LoyaltyEntry{}).Where("account_id = ?", accountID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count loyalty entries: %w", err)
+	}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get loyalty entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+func (r *loyaltyRepository) Post(posting LoyaltyPosting) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoyaltyAccount{
+			ProgramID:  posting.Program.ID,
+			CustomerID: posting.CustomerID,
+			MerchantID: posting.Program.MerchantID,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to open loyalty account: %w", err)
+		}
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("program_id = ? AND customer_id = ?", posting.Program.ID, posting.CustomerID).
+			First(&account).Error; err != nil {
+			return fmt.Errorf("failed to lock loyalty account: %w", err)
+		}
+
+		entry := posting.Entry
+		if account.Balance+entry.Points < 0 {
+			return ErrInsufficientPoints
+		}
+		account.Balance += entry.Points
+		switch entry.Kind {
+		case models.LoyaltyEntryEarn:
+			account.Earned += entry.Points
+		case models.LoyaltyEntryRedeem, models.LoyaltyEntryReversal:
+			// Reversals hand back points a redemption took
+			account.Redeemed -= entry.Points
+		}
+
+		entry.AccountID = account.ID
+		entry.BalanceAfter = account.Balance
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record loyalty entry: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrLoyaltyAlreadyPosted
+		}
+		if err := db.Model(&account).Updates(map[string]interface{}{
+			"balance":  account.Balance,
+			"earned":   account.Earned,
+			"redeemed": account.Redeemed,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update loyalty balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *loyaltyRepository) LinkEntry(entryID, transactionID uint) error {
+	err := r.db.Model(&models.LoyaltyEntry{}).Where("id = ?", entryID).
+		Update("transaction_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to link loyalty entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaintenanceRepository persists the platform's maintenance switch
+type MaintenanceRepository interface {
+	// Get returns the maintenance state; before it was ever set, that's
+	// maintenance off
+	Get() (*models.MaintenanceMode, error)
+	// Save replaces the maintenance state
+	Save(mode *models.MaintenanceMode) error
+}
+
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+func (r *maintenanceRepository) Get() (*models.MaintenanceMode, error) {
+	var mode models.MaintenanceMode
+	err := r.db.First(&mode, models.MaintenanceModeID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.MaintenanceMode{ID: models.MaintenanceModeID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance mode: %w", err)
+	}
+	return &mode, nil
+}
+
+func (r *maintenanceRepository) Save(mode *models.MaintenanceMode) error {
+	mode.ID = models.MaintenanceModeID
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "message", "retry_after", "ends_at", "allowed_user_ids", "updated_by", "updated_at"}),
+	}).Create(mode).Error
+	if err != nil {
+		return fmt.Errorf("failed to save maintenance mode: %w", err)
+	}
+	return nil
+}
@@ -10,85 +10,114 @@ import (
 	"gorm.io/gorm"
 )
 
+// SandboxAPIKeyPrefix starts every sandbox API key, so integrators and the
+// API key middleware can tell them from live keys at a glance
+const SandboxAPIKeyPrefix = "sk_test_"
+
 type MerchantRepository interface {
 	GetByID(id uint) (*models.Merchant, error)
 	GetByUserID(userID uint) (*models.Merchant, error)
+	// GetByUserIDs finds the merchant profiles of the given users; users
+	// without one are skipped
+	GetByUserIDs(userIDs []uint) ([]models.Merchant, error)
 	Create(merchant *models.Merchant) error
 	Update(merchant *models.Merchant) error
 	UpdateAPIKey(userID uint, apiKey string) error
+	// UpdateProfile writes the merchant's non-zero fields
+	UpdateProfile(merchant *models.Merchant) error
+	// GetByAPIKey finds the merchant a server-to-server API key belongs to
+	GetByAPIKey(apiKey string) (*models.Merchant, error)
+	// GenerateAPIKey replaces the merchant's API key with a new random one
+	GenerateAPIKey(userID uint) (string, error)
+	// GetBySandboxAPIKey finds the merchant a sandbox API key belongs to
+	GetBySandboxAPIKey(apiKey string) (*models.Merchant, error)
+	// GenerateSandboxAPIKey replaces the merchant's sandbox API key with a
+	// new random one carrying SandboxAPIKeyPrefix
+	GenerateSandboxAPIKey(userID uint) (string, error)
+	// SetWebhookURL sets where merchant webhooks are delivered and returns
+	// the secret they are signed with, creating one on first use
+	SetWebhookURL(userID uint, webhookURL string) (string, error)
+	// SetFeePlan sets the merchant's fee plan and negotiated rate, zero for
+	// none
+	SetFeePlan(id uint, plan string, rate float64) error
 }
 
 type merchantRepository struct {
 	db *gorm.DB
 }
 
-func GetMerchantByUserID(userID uint) (*models.Merchant, error) {
+func NewMerchantRepository(db *gorm.DB) MerchantRepository {
+	return &merchantRepository{
+		db: db,
+	}
+}
+
+func (r *merchantRepository) GetByID(id uint) (*models.Merchant, error) {
 	var merchant models.Merchant
-	if err := DB.Where("user_id = ?", userID).First(&merchant).Error; err != nil {
+	if err := r.db.First(&merchant, id).Error; err != nil {
 		return nil, err
 	}
 	return &merchant, nil
 }
 
-func CreateMerchant(merchant *models.Merchant) error {
-	tx := DB.Begin()
-	if tx.Error != nil {
-		return tx.Error
+func (r *merchantRepository) GetByUserID(userID uint) (*models.Merchant, error) {
+	var merchant models.Merchant
+	if err := r.db.Where("user_id = ?", userID).First(&merchant).Error; err != nil {
+		return nil, err
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	return &merchant, nil
+}
 
-	// Clean up any existing records
-	if err := tx.Unscoped().Where("user_id = ?", merchant.UserID).Delete(&models.Merchant{}).Error; err != nil {
-		tx.Rollback()
-		return err
+func (r *merchantRepository) GetByUserIDs(userIDs []uint) ([]models.Merchant, error) {
+	var merchants []models.Merchant
+	if len(userIDs) == 0 {
+		return merchants, nil
 	}
-
-	// Reset the sequence if needed
-	if err := tx.Exec("ALTER SEQUENCE merchants_id_seq RESTART WITH 1").Error; err != nil {
-		tx.Rollback()
-		return err
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&merchants).Error; err != nil {
+		return nil, err
 	}
+	return merchants, nil
+}
 
-	// Create the new merchant (let DB handle ID auto-increment)
-	if err := tx.Create(merchant).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+func (r *merchantRepository) Create(merchant *models.Merchant) error {
+	return r.db.Create(merchant).Error
+}
 
-	// Update user's status
-	if err := tx.Model(&models.User{}).
-		Where("id = ?", merchant.UserID).
-		Update("merchant_profile_status", "completed").Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+func (r *merchantRepository) Update(merchant *models.Merchant) error {
+	return r.db.Save(merchant).Error
+}
 
-	return tx.Commit().Error
+func (r *merchantRepository) UpdateAPIKey(userID uint, apiKey string) error {
+	return r.db.Model(&models.Merchant{}).
+		Where("user_id = ?", userID).
+		Update("api_key", apiKey).Error
 }
 
-func UpdateMerchant(merchant *models.Merchant) error {
+func (r *merchantRepository) UpdateProfile(merchant *models.Merchant) error {
 	// Ensure we're updating an existing record by using the ID
 	if merchant.ID == 0 {
 		return errors.New("cannot update merchant with ID 0")
 	}
-	return DB.Model(&models.Merchant{}).Where("id = ?", merchant.ID).Updates(merchant).Error
+	return r.db.Model(&models.Merchant{}).Where("id = ?", merchant.ID).Updates(merchant).Error
 }
 
-func GenerateMerchantAPIKey(merchantID uint) (string, error) {
-	// Generate random bytes for API key
+func (r *merchantRepository) GetByAPIKey(apiKey string) (*models.Merchant, error) {
+	var merchant models.Merchant
+	if err := r.db.Where("api_key = ? AND api_key <> ''", apiKey).First(&merchant).Error; err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *merchantRepository) GenerateAPIKey(userID uint) (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
 	apiKey := hex.EncodeToString(bytes)
 
-	// Update merchant with new API key
-	result := DB.Model(&models.Merchant{}).
-		Where("user_id = ?", merchantID).
+	result := r.db.Model(&models.Merchant{}).
+		Where("user_id = ?", userID).
 		Update("api_key", apiKey)
 	if result.Error != nil {
 		return "", result.Error
@@ -96,61 +125,67 @@ func GenerateMerchantAPIKey(merchantID uint) (string, error) {
 	if result.RowsAffected == 0 {
 		return "", fmt.Errorf("merchant not found")
 	}
-
 	return apiKey, nil
 }
 
-func SetMerchantWebhookURL(merchantID uint, webhookURL string) error {
-	result := DB.Model(&models.Merchant{}).
-		Where("user_id = ?", merchantID).
-		Update("webhook_url", webhookURL)
+func (r *merchantRepository) GetBySandboxAPIKey(apiKey string) (*models.Merchant, error) {
+	var merchant models.Merchant
+	if err := r.db.Where("sandbox_api_key = ? AND sandbox_api_key <> ''", apiKey).First(&merchant).Error; err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *merchantRepository) GenerateSandboxAPIKey(userID uint) (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	apiKey := SandboxAPIKeyPrefix + hex.EncodeToString(bytes)
+
+	result := r.db.Model(&models.Merchant{}).
+		Where("user_id = ?", userID).
+		Update("sandbox_api_key", apiKey)
 	if result.Error != nil {
-		return result.Error
+		return "", result.Error
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("merchant not found")
+		return "", fmt.Errorf("merchant not found")
 	}
-	return nil
+	return apiKey, nil
 }
 
-func GetMerchantStaticQR(userID uint) (*models.QRCode, error) {
-	var qr models.QRCode
-	err := DB.Where("user_id = ? AND user_type = ? AND type = ?",
-		userID, "merchant", models.QRTypeStatic).First(&qr).Error
+func (r *merchantRepository) SetWebhookURL(userID uint, webhookURL string) (string, error) {
+	merchant, err := r.GetByUserID(userID)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("merchant not found")
+		}
+		return "", err
 	}
-	return &qr, nil
-}
 
-func NewMerchantRepository(db *gorm.DB) MerchantRepository {
-	return &merchantRepository{
-		db: db,
+	updates := map[string]interface{}{"webhook_url": webhookURL}
+	if merchant.WebhookSecret == "" {
+		bytes := make([]byte, 32)
+		if _, err := rand.Read(bytes); err != nil {
+			return "", err
+		}
+		merchant.WebhookSecret = "whsec_" + hex.EncodeToString(bytes)
+		updates["webhook_secret"] = merchant.WebhookSecret
 	}
+	if err := r.db.Model(&models.Merchant{}).Where("id = ?", merchant.ID).Updates(updates).Error; err != nil {
+		return "", err
+	}
+	return merchant.WebhookSecret, nil
 }
 
-func (r *merchantRepository) GetByID(id uint) (*models.Merchant, error) {
-	var merchant models.Merchant
-	err := r.db.First(&merchant, id).Error
-	return &merchant, err
-}
-
-func (r *merchantRepository) GetByUserID(userID uint) (*models.Merchant, error) {
-	var merchant models.Merchant
-	err := r.db.Where("user_id = ?", userID).First(&merchant).Error
-	return &merchant, err
-}
-
-func (r *merchantRepository) Create(merchant *models.Merchant) error {
-	return r.db.Create(merchant).Error
-}
-
-func (r *merchantRepository) Update(merchant *models.Merchant) error {
-	return r.db.Save(merchant).Error
-}
-
-func (r *merchantRepository) UpdateAPIKey(userID uint, apiKey string) error {
-	return r.db.Model(&models.Merchant{}).
-		Where("user_id = ?", userID).
-		Update("api_key", apiKey).Error
+func (r *merchantRepository) SetFeePlan(id uint, plan string, rate float64) error {
+	err := r.db.Model(&models.Merchant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"fee_plan":            plan,
+		"processing_fee_rate": rate,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to set merchant fee plan: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIPRangeNotFound    = errors.New("IP range not found")
+	ErrClientCertNotFound = errors.New("client certificate not found")
+)
+
+// MerchantAPIAccessRepository persists the IP allowlists, client
+// certificates and rejected requests of the merchant API
+type MerchantAPIAccessRepository interface {
+	ListIPRanges(ctx context.Context, merchantID uint) ([]models.MerchantIPRange, error)
+	CreateIPRange(ctx context.Context, ipRange *models.MerchantIPRange) error
+	DeleteIPRange(ctx context.Context, merchantID, id uint) error
+
+	ListClientCerts(ctx context.Context, merchantID uint) ([]models.MerchantClientCert, error)
+	CreateClientCert(ctx context.Context, cert *models.MerchantClientCert) error
+	DeleteClientCert(ctx context.Context, merchantID, id uint) error
+	// HasClientCert reports whether the merchant registered the
+	// certificate with the given fingerprint
+	HasClientCert(ctx context.Context, merchantID uint, fingerprint string) (bool, error)
+	SetRequireMTLS(ctx context.Context, merchantID uint, require bool) error
+
+	RecordRejection(ctx context.Context, rejection *models.MerchantAPIRejection) error
+	// ListRejections returns the merchant's rejected requests, newest first
+	ListRejections(ctx context.Context, merchantID uint, limit, offset int) ([]models.MerchantAPIRejection, int64, error)
+}
+
+type merchantAPIAccessRepository struct {
+	db *gorm.DB
+}
+
+func NewMerchantAPIAccessRepository(db *gorm.DB) MerchantAPIAccessRepository {
+	return &merchantAPIAccessRepository{db: db}
+}
+
+func (r *merchantAPIAccessRepository) ListIPRanges(ctx context.Context, merchantID uint) ([]models.MerchantIPRange, error) {
+	var ranges []models.MerchantIPRange
+	err := r.db.WithContext(ctx).
+		Where("merchant_id = ?", merchantID).
+		Order("created_at ASC").
+		Find(&ranges).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IP ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+func (r *merchantAPIAccessRepository) CreateIPRange(ctx context.Context, ipRange *models.MerchantIPRange) error {
+	if err := r.db.WithContext(ctx).Create(ipRange).Error; err != nil {
+		return fmt.Errorf("failed to create IP range: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantAPIAccessRepository) DeleteIPRange(ctx context.Context, merchantID, id uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND merchant_id = ?", id, merchantID).
+		Delete(&models.MerchantIPRange{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete IP range: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrIPRangeNotFound
+	}
+	return nil
+}
+
+func (r *merchantAPIAccessRepository) ListClientCerts(ctx context.Context, merchantID uint) ([]models.MerchantClientCert, error) {
+	var certs []models.MerchantClientCert
+	err := r.db.WithContext(ctx).
+		Where("merchant_id = ?", merchantID).
+		Order("created_at ASC").
+		Find(&certs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client certificates: %w", err)
+	}
+	return certs, nil
+}
+
+func (r *merchantAPIAccessRepository) CreateClientCert(ctx context.Context, cert *models.MerchantClientCert) error {
+	if err := r.db.WithContext(ctx).Create(cert).Error; err != nil {
+		return fmt.Errorf("failed to create client certificate: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantAPIAccessRepository) DeleteClientCert(ctx context.Context, merchantID, id uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND merchant_id = ?", id, merchantID).
+		Delete(&models.MerchantClientCert{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete client certificate: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrClientCertNotFound
+	}
+	return nil
+}
+
+func (r *merchantAPIAccessRepository) HasClientCert(ctx context.Context, merchantID uint, fingerprint string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.MerchantClientCert{}).
+		Where("merchant_id = ? AND fingerprint = ?", merchantID, fingerprint).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to look up client certificate: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *merchantAPIAccessRepository) SetRequireMTLS(ctx context.Context, merchantID uint, require bool) error {
+	err := r.db.WithContext(ctx).Model(&models.Merchant{}).
+		Where("id = ?", merchantID).
+		Update("require_mtls", require).Error
+	if err != nil {
+		return fmt.Errorf("failed to update mutual TLS requirement: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantAPIAccessRepository) RecordRejection(ctx context.Context, rejection *models.MerchantAPIRejection) error {
+	if err := r.db.WithContext(ctx).Create(rejection).Error; err != nil {
+		return fmt.Errorf("failed to record API rejection: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantAPIAccessRepository) ListRejections(ctx context.Context, merchantID uint, limit, offset int) ([]models.MerchantAPIRejection, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.MerchantAPIRejection{}).
+		Where("merchant_id = ?", merchantID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count API rejections: %w", err)
+	}
+
+	var rejections []models.MerchantAPIRejection
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&rejections).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list API rejections: %w", err)
+	}
+	return rejections, total, nil
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrMerchantRiskAlreadyAssessed = errors.New("merchant already assessed")
+
+// merchantRiskNonCharges are transaction types a merchant receives that
+// aren't payments for what they sell
+var merchantRiskNonCharges = []string{
+	models.TransactionTypeRefund,
+	models.TransactionTypeChargeback,
+	models.TransactionTypeReversal,
+	models.TransactionTypeTopup,
+	"top_up",
+}
+
+// MerchantRiskMetrics is a merchant's activity over a monitoring window
+type MerchantRiskMetrics struct {
+	ChargeCount  int64
+	ChargeVolume float64
+	// RecentVolume is the part of ChargeVolume received since the recent
+	// cut-off
+	RecentVolume     float64
+	RefundCount      int64
+	RefundVolume     float64
+	ChargebackCount  int64
+	ChargebackVolume float64
+}
+
+// MerchantRiskRepository stores merchants' risk status and the assessments
+// that set it
+type MerchantRiskRepository interface {
+	// GetDue returns active merchants not assessed since the time
+	GetDue(assessedBefore time.Time, limit int) ([]models.Merchant, error)
+	// MarkAssessed claims the merchant for assessment, returning
+	// ErrMerchantRiskAlreadyAssessed if another run got there first
+	MarkAssessed(merchantID uint, assessedBefore, now time.Time) error
+	// GetMetrics totals the merchant's charges, refunds and chargebacks
+	// since the time. Chargebacks the merchant won are left out.
+	GetMetrics(merchantUserID uint, since, recentSince time.Time) (*MerchantRiskMetrics, error)
+	// SaveAssessment writes the merchant's score, risk status and limit
+	// along with the assessment that changed them
+	SaveAssessment(merchant *models.Merchant, assessment *models.MerchantRiskAssessment) error
+	ListAssessments(merchantUserID uint, limit, offset int) ([]models.MerchantRiskAssessment, int64, error)
+	// ListRestricted returns merchants with the risk status, or with any
+	// status other than normal when it is empty
+	ListRestricted(status string, limit, offset int) ([]models.Merchant, int64, error)
+}
+
+type merchantRiskRepository struct {
+	db *gorm.DB
+}
+
+func NewMerchantRiskRepository(db *gorm.DB) MerchantRiskRepository {
+	return &merchantRiskRepository{db: db}
+}
+
+func (r *merchantRiskRepository) GetDue(assessedBefore time.Time, limit int) ([]models.Merchant, error) {
+	var merchants []models.Merchant
+	err := r.db.Where("status = ?", "active").
+		Where("risk_assessed_at IS NULL OR risk_assessed_at < ?", assessedBefore).
+		Order("risk_assessed_at NULLS FIRST, id").Limit(limit).Find(&merchants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchants due for risk assessment: %w", err)
+	}
+	return merchants, nil
+}
+
+func (r *merchantRiskRepository) MarkAssessed(merchantID uint, assessedBefore, now time.Time) error {
+	result := r.db.Model(&models.Merchant{}).
+		Where("id = ? AND (risk_assessed_at IS NULL OR risk_assessed_at < ?)", merchantID, assessedBefore).
+		Update("risk_assessed_at", now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to claim merchant risk assessment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrMerchantRiskAlreadyAssessed
+	}
+	return nil
+}
+
+func (r *merchantRiskRepository) GetMetrics(merchantUserID uint, since, recentSince time.Time) (*MerchantRiskMetrics, error) {
+	var m MerchantRiskMetrics
+	err := r.db.Model(&models.Transaction{}).
+		Where("status = ? AND created_at >= ? AND (receiver_id = ? OR sender_id = ?) AND sender_id <> receiver_id",
+			"completed", since, merchantUserID, merchantUserID).
+		Select(`COUNT(*) FILTER (WHERE receiver_id = @merchant AND type NOT IN @other),
+			COALESCE(SUM(amount) FILTER (WHERE receiver_id = @merchant AND type NOT IN @other), 0),
+			COALESCE(SUM(amount) FILTER (WHERE receiver_id = @merchant AND type NOT IN @other AND created_at >= @recent), 0),
+			COUNT(*) FILTER (WHERE sender_id = @merchant AND type = @refund),
+			COALESCE(SUM(amount) FILTER (WHERE sender_id = @merchant AND type = @refund), 0)`,
+			map[string]interface{}{
+				"merchant": merchantUserID,
+				"other":    merchantRiskNonCharges,
+				"refund":   models.TransactionTypeRefund,
+				"recent":   recentSince,
+			}).
+		Row().Scan(&m.ChargeCount, &m.ChargeVolume, &m.RecentVolume, &m.RefundCount, &m.RefundVolume)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant activity: %w", err)
+	}
+
+	err = r.db.Model(&models.Chargeback{}).
+		Where("merchant_user_id = ? AND created_at >= ? AND status <> ?", merchantUserID, since, models.ChargebackStatusWon).
+		Select("COUNT(*), COALESCE(SUM(amount), 0)").
+		Row().Scan(&m.ChargebackCount, &m.ChargebackVolume)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant chargebacks: %w", err)
+	}
+	return &m, nil
+}
+
+func (r *merchantRiskRepository) SaveAssessment(merchant *models.Merchant, assessment *models.MerchantRiskAssessment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Merchant{}).Where("id = ?", merchant.ID).Updates(map[string]interface{}{
+			"risk_score":             merchant.RiskScore,
+			"compliance_level":       merchant.ComplianceLevel,
+			"risk_status":            merchant.RiskStatus,
+			"risk_restore_limit":     merchant.RiskRestoreLimit,
+			"risk_assessed_at":       merchant.RiskAssessedAt,
+			"risk_cleared_at":        merchant.RiskClearedAt,
+			"max_transaction_amount": merchant.MaxTransactionAmount,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update merchant risk: %w", err)
+		}
+		if err := tx.Create(assessment).Error; err != nil {
+			return fmt.Errorf("failed to record merchant risk assessment: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *merchantRiskRepository) ListAssessments(merchantUserID uint, limit, offset int) ([]models.MerchantRiskAssessment, int64, error) {
+	var assessments []models.MerchantRiskAssessment
+	var total int64
+	query := r.db.Model(&models.MerchantRiskAssessment{}).Where("merchant_user_id = ?", merchantUserID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count merchant risk assessments: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&assessments).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list merchant risk assessments: %w", err)
+	}
+	return assessments, total, nil
+}
+
+func (r *merchantRiskRepository) ListRestricted(status string, limit, offset int) ([]models.Merchant, int64, error) {
+	var merchants []models.Merchant
+	var total int64
+	query := r.db.Model(&models.Merchant{})
+	if status != "" {
+		query = query.Where("risk_status = ?", status)
+	} else {
+		query = query.Where("risk_status IN ?", []string{models.MerchantRiskTightened, models.MerchantRiskReview})
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count restricted merchants: %w", err)
+	}
+	if err := query.Order("risk_score DESC, id").Limit(limit).Offset(offset).Find(&merchants).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list restricted merchants: %w", err)
+	}
+	return merchants, total, nil
+}
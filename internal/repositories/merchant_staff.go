@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrStaffNotFound = errors.New("staff member not found")
+
+// ShiftTotals summarizes one operator's charges and refunds over a period.
+// OperatorID is nil for activity by the merchant themselves.
+type ShiftTotals struct {
+	OperatorID  *uint      `json:"operator_id"`
+	Charges     int64      `json:"charges"`
+	ChargeTotal float64    `json:"charge_total"`
+	Refunds     int64      `json:"refunds"`
+	RefundTotal float64    `json:"refund_total"`
+	FirstAt     *time.Time `json:"first_at"`
+	LastAt      *time.Time `json:"last_at"`
+}
+
+// MerchantStaffRepository persists merchant staff and reports on their activity
+type MerchantStaffRepository interface {
+	Create(staff *models.MerchantStaff) error
+	GetByID(id uint) (*models.MerchantStaff, error)
+	GetByMerchantAndUser(merchantID, userID uint) (*models.MerchantStaff, error)
+	ListByMerchant(merchantUserID uint) ([]models.MerchantStaff, error)
+	ListByUser(userID uint) ([]models.MerchantStaff, error)
+	Update(staff *models.MerchantStaff) error
+	// SetPINState records failed PIN attempts and any lockout
+	SetPINState(id uint, failed int, lockedUntil *time.Time) error
+	// ShiftTotals groups a merchant's completed charges and refunds in
+	// [from, to) by the operator who made them
+	ShiftTotals(merchantUserID uint, from, to time.Time) ([]ShiftTotals, error)
+}
+
+type merchantStaffRepository struct {
+	db *gorm.DB
+}
+
+func NewMerchantStaffRepository(db *gorm.DB) MerchantStaffRepository {
+	return &merchantStaffRepository{db: db}
+}
+
+func (r *merchantStaffRepository) Create(staff *models.MerchantStaff) error {
+	if err := r.db.Create(staff).Error; err != nil {
+		return fmt.Errorf("failed to create staff member: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantStaffRepository) GetByID(id uint) (*models.MerchantStaff, error) {
+	var staff models.MerchantStaff
+	if err := r.db.First(&staff, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStaffNotFound
+		}
+		return nil, fmt.Errorf("failed to get staff member: %w", err)
+	}
+	return &staff, nil
+}
+
+func (r *merchantStaffRepository) GetByMerchantAndUser(merchantID, userID uint) (*models.MerchantStaff, error) {
+	var staff models.MerchantStaff
+	if err := r.db.Where("merchant_id = ? AND user_id = ?", merchantID, userID).First(&staff).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStaffNotFound
+		}
+		return nil, fmt.Errorf("failed to get staff member: %w", err)
+	}
+	return &staff, nil
+}
+
+func (r *merchantStaffRepository) ListByMerchant(merchantUserID uint) ([]models.MerchantStaff, error) {
+	var staff []models.MerchantStaff
+	if err := r.db.Where("merchant_user_id = ?", merchantUserID).Order("id").Find(&staff).Error; err != nil {
+		return nil, fmt.Errorf("failed to list staff: %w", err)
+	}
+	return staff, nil
+}
+
+func (r *merchantStaffRepository) ListByUser(userID uint) ([]models.MerchantStaff, error) {
+	var staff []models.MerchantStaff
+	if err := r.db.Where("user_id = ? AND status IN ?", userID, []string{models.StaffStatusInvited, models.StaffStatusActive}).
+		Order("id").Find(&staff).Error; err != nil {
+		return nil, fmt.Errorf("failed to list staff memberships: %w", err)
+	}
+	return staff, nil
+}
+
+func (r *merchantStaffRepository) Update(staff *models.MerchantStaff) error {
+	if err := r.db.Save(staff).Error; err != nil {
+		return fmt.Errorf("failed to update staff member: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantStaffRepository) SetPINState(id uint, failed int, lockedUntil *time.Time) error {
+	err := r.db.Model(&models.MerchantStaff{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_pins":      failed,
+		"pin_locked_until": lockedUntil,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update staff PIN state: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantStaffRepository) ShiftTotals(merchantUserID uint, from, to time.Time) ([]ShiftTotals, error) {
+	var totals []ShiftTotals
+	err := r.db.Model(&models.Transaction{}).
+		Select(`operator_id,
+			COUNT(*) FILTER (WHERE receiver_id = @merchant) AS charges,
+			COALESCE(SUM(amount) FILTER (WHERE receiver_id = @merchant), 0) AS charge_total,
+			COUNT(*) FILTER (WHERE sender_id = @merchant) AS refunds,
+			COALESCE(SUM(amount) FILTER (WHERE sender_id = @merchant), 0) AS refund_total,
+			MIN(processed_at) AS first_at,
+			MAX(processed_at) AS last_at`, map[string]interface{}{"merchant": merchantUserID}).
+		Where("status = ? AND processed_at >= ? AND processed_at < ?", "completed", from, to).
+		Where("(receiver_id = ? AND merchant_id IS NOT NULL) OR (sender_id = ? AND type = ?)",
+			merchantUserID, merchantUserID, models.TransactionTypeRefund).
+		Group("operator_id").
+		Order("operator_id NULLS FIRST").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shift totals: %w", err)
+	}
+	return totals, nil
+}
@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthClientNotFound         = errors.New("oauth client not found")
+	ErrOAuthCodeNotFound           = errors.New("oauth authorization code not found")
+	ErrOAuthTokenNotFound          = errors.New("oauth token not found")
+	ErrOAuthGrantNotFound          = errors.New("oauth grant not found")
+	ErrOAuthPaymentRequestNotFound = errors.New("oauth payment request not found")
+)
+
+// OAuthRepository persists third-party apps and what users have let them do
+type OAuthRepository interface {
+	CreateClient(ctx context.Context, client *models.OAuthClient) error
+	GetClient(ctx context.Context, clientID string) (*models.OAuthClient, error)
+	ListClientsByOwner(ctx context.Context, ownerID uint) ([]models.OAuthClient, error)
+	DeleteClient(ctx context.Context, ownerID uint, clientID string) error
+
+	CreateCode(ctx context.Context, code *models.OAuthAuthorizationCode) error
+	GetCode(ctx context.Context, codeHash string) (*models.OAuthAuthorizationCode, error)
+	// UseCode marks the code used, reporting false when it already was
+	UseCode(ctx context.Context, id uint, at time.Time) (bool, error)
+
+	CreateToken(ctx context.Context, token *models.OAuthToken) error
+	GetTokenByAccess(ctx context.Context, accessHash string) (*models.OAuthToken, error)
+	GetTokenByRefresh(ctx context.Context, refreshHash string) (*models.OAuthToken, error)
+	RevokeToken(ctx context.Context, id uint, at time.Time) error
+	// RevokeGrantTokens revokes every token the app holds for the user
+	RevokeGrantTokens(ctx context.Context, userID uint, clientID string, at time.Time) error
+
+	SaveGrant(ctx context.Context, grant *models.OAuthGrant) error
+	GetGrant(ctx context.Context, userID uint, clientID string) (*models.OAuthGrant, error)
+	ListGrants(ctx context.Context, userID uint) ([]models.OAuthGrant, error)
+	DeleteGrant(ctx context.Context, userID uint, clientID string) error
+
+	CreatePaymentRequest(ctx context.Context, req *models.OAuthPaymentRequest) error
+	GetPaymentRequest(ctx context.Context, id uint) (*models.OAuthPaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, userID uint, status string) ([]models.OAuthPaymentRequest, error)
+	// DecidePaymentRequest moves a pending request to status, reporting
+	// false when it was no longer pending
+	DecidePaymentRequest(ctx context.Context, id uint, status string, at time.Time) (bool, error)
+	// ReopenPaymentRequest puts an approved request whose payment failed
+	// back to pending
+	ReopenPaymentRequest(ctx context.Context, id uint) error
+	SetPaymentRequestTransaction(ctx context.Context, id, transactionID uint) error
+	// ExpirePaymentRequests expires the user's pending requests that are
+	// past their deadline
+	ExpirePaymentRequests(ctx context.Context, userID uint, now time.Time) error
+}
+
+type oauthRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthRepository(db *gorm.DB) OAuthRepository {
+	return &oauthRepository{db: db}
+}
+
+func (r *oauthRepository) CreateClient(ctx context.Context, client *models.OAuthClient) error {
+	if err := r.db.WithContext(ctx).Create(client).Error; err != nil {
+		return fmt.Errorf("failed to create oauth client: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) GetClient(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	if err := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthClientNotFound
+		}
+		return nil, fmt.Errorf("failed to get oauth client: %w", err)
+	}
+	return &client, nil
+}
+
+func (r *oauthRepository) ListClientsByOwner(ctx context.Context, ownerID uint) ([]models.OAuthClient, error) {
+	var clients []models.OAuthClient
+	if err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("id").Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
+	}
+	return clients, nil
+}
+
+func (r *oauthRepository) DeleteClient(ctx context.Context, ownerID uint, clientID string) error {
+	res := r.db.WithContext(ctx).Where("owner_id = ? AND client_id = ?", ownerID, clientID).Delete(&models.OAuthClient{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete oauth client: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+func (r *oauthRepository) CreateCode(ctx context.Context, code *models.OAuthAuthorizationCode) error {
+	if err := r.db.WithContext(ctx).Create(code).Error; err != nil {
+		return fmt.Errorf("failed to create oauth authorization code: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) GetCode(ctx context.Context, codeHash string) (*models.OAuthAuthorizationCode, error) {
+	var code models.OAuthAuthorizationCode
+	if err := r.db.WithContext(ctx).Where("code_hash = ?", codeHash).First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get oauth authorization code: %w", err)
+	}
+	return &code, nil
+}
+
+func (r *oauthRepository) UseCode(ctx context.Context, id uint, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.OAuthAuthorizationCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to use oauth authorization code: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *oauthRepository) CreateToken(ctx context.Context, token *models.OAuthToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create oauth token: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) GetTokenByAccess(ctx context.Context, accessHash string) (*models.OAuthToken, error) {
+	return r.getToken(ctx, "access_hash = ?", accessHash)
+}
+
+func (r *oauthRepository) GetTokenByRefresh(ctx context.Context, refreshHash string) (*models.OAuthToken, error) {
+	return r.getToken(ctx, "refresh_hash = ?", refreshHash)
+}
+
+func (r *oauthRepository) getToken(ctx context.Context, query string, hash string) (*models.OAuthToken, error) {
+	var token models.OAuthToken
+	if err := r.db.WithContext(ctx).Where(query, hash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get oauth token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *oauthRepository) RevokeToken(ctx context.Context, id uint, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.OAuthToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke oauth token: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) RevokeGrantTokens(ctx context.Context, userID uint, clientID string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.OAuthToken{}).
+		Where("user_id = ? AND client_id = ? AND revoked_at IS NULL", userID, clientID).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke oauth tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) SaveGrant(ctx context.Context, grant *models.OAuthGrant) error {
+	if err := r.db.WithContext(ctx).Save(grant).Error; err != nil {
+		return fmt.Errorf("failed to save oauth grant: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) GetGrant(ctx context.Context, userID uint, clientID string) (*models.OAuthGrant, error) {
+	var grant models.OAuthGrant
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).First(&grant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthGrantNotFound
+		}
+		return nil, fmt.Errorf("failed to get oauth grant: %w", err)
+	}
+	return &grant, nil
+}
+
+func (r *oauthRepository) ListGrants(ctx context.Context, userID uint) ([]models.OAuthGrant, error) {
+	var grants []models.OAuthGrant
+	err := r.db.WithContext(ctx).Preload("Client").Where("user_id = ?", userID).Order("id").Find(&grants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth grants: %w", err)
+	}
+	return grants, nil
+}
+
+func (r *oauthRepository) DeleteGrant(ctx context.Context, userID uint, clientID string) error {
+	res := r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&models.OAuthGrant{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete oauth grant: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrOAuthGrantNotFound
+	}
+	return nil
+}
+
+func (r *oauthRepository) CreatePaymentRequest(ctx context.Context, req *models.OAuthPaymentRequest) error {
+	if err := r.db.WithContext(ctx).Create(req).Error; err != nil {
+		return fmt.Errorf("failed to create oauth payment request: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) GetPaymentRequest(ctx context.Context, id uint) (*models.OAuthPaymentRequest, error) {
+	var req models.OAuthPaymentRequest
+	if err := r.db.WithContext(ctx).First(&req, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get oauth payment request: %w", err)
+	}
+	return &req, nil
+}
+
+func (r *oauthRepository) ListPaymentRequests(ctx context.Context, userID uint, status string) ([]models.OAuthPaymentRequest, error) {
+	q := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var reqs []models.OAuthPaymentRequest
+	if err := q.Order("id DESC").Find(&reqs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list oauth payment requests: %w", err)
+	}
+	return reqs, nil
+}
+
+func (r *oauthRepository) DecidePaymentRequest(ctx context.Context, id uint, status string, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.OAuthPaymentRequest{}).
+		Where("id = ? AND status = ?", id, models.OAuthPaymentPending).
+		Updates(map[string]interface{}{"status": status, "decided_at": at})
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to decide oauth payment request: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *oauthRepository) ReopenPaymentRequest(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&models.OAuthPaymentRequest{}).
+		Where("id = ? AND status = ?", id, models.OAuthPaymentApproved).
+		Updates(map[string]interface{}{"status": models.OAuthPaymentPending, "decided_at": nil}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reopen oauth payment request: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) SetPaymentRequestTransaction(ctx context.Context, id, transactionID uint) error {
+	err := r.db.WithContext(ctx).Model(&models.OAuthPaymentRequest{}).
+		Where("id = ?", id).
+		Update("transaction_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to record oauth payment request transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRepository) ExpirePaymentRequests(ctx context.Context, userID uint, now time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.OAuthPaymentRequest{}).
+		Where("user_id = ? AND status = ? AND expires_at < ?", userID, models.OAuthPaymentPending, now).
+		Updates(map[string]interface{}{"status": models.OAuthPaymentExpired, "decided_at": now}).Error
+	if err != nil {
+		return fmt.Errorf("failed to expire oauth payment requests: %w", err)
+	}
+	return nil
+}
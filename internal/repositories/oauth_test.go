@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"orus/internal/models"
+	"orus/migrations"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// migratedDB returns a database in a fresh schema with every migration
+// applied, dropped when the test ends. The test is skipped unless
+// TEST_DATABASE_URL points at a Postgres it may create schemas in.
+func migratedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	name := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA "` + name + `"`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA "` + name + `" CASCADE`) })
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, name)), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	provider, err := migrations.NewProvider(sqlDB)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+// withSearchPath points every connection made with dsn, a URL or
+// key=value string, at schema
+func withSearchPath(dsn, schema string) string {
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		q := u.Query()
+		q.Set("search_path", schema)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return dsn + " search_path=" + schema
+}
+
+func TestOAuthTablesMatchMigrations(t *testing.T) {
+	var ddl strings.Builder
+	err := fs.WalkDir(migrations.FS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(migrations.FS, path)
+		ddl.Write(content)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to read migrations: %v", err)
+	}
+
+	for _, model := range []interface{}{
+		&models.OAuthClient{},
+		&models.OAuthAuthorizationCode{},
+		&models.OAuthToken{},
+		&models.OAuthGrant{},
+		&models.OAuthPaymentRequest{},
+	} {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("failed to parse %T: %v", model, err)
+		}
+		if !strings.Contains(ddl.String(), `CREATE TABLE IF NOT EXISTS "`+s.Table+`"`) {
+			t.Errorf("%T maps to %q, which no migration creates", model, s.Table)
+		}
+	}
+}
+
+func TestOAuthRepository(t *testing.T) {
+	db := migratedDB(t)
+	repo := NewOAuthRepository(db)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Microsecond)
+
+	client := &models.OAuthClient{
+		ClientID:     "app-1",
+		SecretHash:   strings.Repeat("a", 64),
+		OwnerID:      1,
+		Name:         "Budgeting app",
+		RedirectURIs: []string{"https://app.example/callback"},
+		Scopes:       []string{models.OAuthScopeBalanceRead, models.OAuthScopePaymentsInitiate},
+		Status:       models.OAuthClientActive,
+	}
+	if err := repo.CreateClient(ctx, client); err != nil {
+		t.Fatalf("CreateClient() error = %v", err)
+	}
+	got, err := repo.GetClient(ctx, "app-1")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if got.Name != client.Name || !got.AllowsRedirect("https://app.example/callback") || len(got.Scopes) != 2 {
+		t.Errorf("GetClient() = %+v, want %+v", got, client)
+	}
+	if _, err := repo.GetClient(ctx, "missing"); !errors.Is(err, ErrOAuthClientNotFound) {
+		t.Errorf("GetClient(missing) error = %v, want %v", err, ErrOAuthClientNotFound)
+	}
+	if owned, err := repo.ListClientsByOwner(ctx, 1); err != nil || len(owned) != 1 {
+		t.Errorf("ListClientsByOwner() = %d clients, %v, want 1", len(owned), err)
+	}
+
+	code := &models.OAuthAuthorizationCode{
+		CodeHash:    strings.Repeat("b", 64),
+		ClientID:    "app-1",
+		UserID:      2,
+		RedirectURI: "https://app.example/callback",
+		Scope:       models.OAuthScopeBalanceRead,
+		ExpiresAt:   now.Add(time.Minute),
+	}
+	if err := repo.CreateCode(ctx, code); err != nil {
+		t.Fatalf("CreateCode() error = %v", err)
+	}
+	if got, err := repo.GetCode(ctx, code.CodeHash); err != nil || got.ID != code.ID {
+		t.Fatalf("GetCode() = %+v, %v", got, err)
+	}
+	if used, err := repo.UseCode(ctx, code.ID, now); err != nil || !used {
+		t.Errorf("UseCode() = %v, %v, want true", used, err)
+	}
+	if used, err := repo.UseCode(ctx, code.ID, now); err != nil || used {
+		t.Errorf("second UseCode() = %v, %v, want false", used, err)
+	}
+
+	token := &models.OAuthToken{
+		ClientID:         "app-1",
+		UserID:           2,
+		Scope:            models.OAuthScopeBalanceRead,
+		AccessHash:       strings.Repeat("c", 64),
+		RefreshHash:      strings.Repeat("d", 64),
+		AccessExpiresAt:  now.Add(time.Hour),
+		RefreshExpiresAt: now.Add(24 * time.Hour),
+	}
+	if err := repo.CreateToken(ctx, token); err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if got, err := repo.GetTokenByAccess(ctx, token.AccessHash); err != nil || got.ID != token.ID {
+		t.Errorf("GetTokenByAccess() = %+v, %v", got, err)
+	}
+	if err := repo.RevokeGrantTokens(ctx, 2, "app-1", now); err != nil {
+		t.Fatalf("RevokeGrantTokens() error = %v", err)
+	}
+	if got, err := repo.GetTokenByRefresh(ctx, token.RefreshHash); err != nil || got.RevokedAt == nil {
+		t.Errorf("GetTokenByRefresh() = %+v, %v, want revoked", got, err)
+	}
+
+	if err := repo.SaveGrant(ctx, &models.OAuthGrant{UserID: 2, ClientID: "app-1", Scope: models.OAuthScopeBalanceRead}); err != nil {
+		t.Fatalf("SaveGrant() error = %v", err)
+	}
+	grants, err := repo.ListGrants(ctx, 2)
+	if err != nil || len(grants) != 1 || grants[0].Client == nil || grants[0].Client.Name != client.Name {
+		t.Errorf("ListGrants() = %+v, %v, want the grant with its client", grants, err)
+	}
+	if err := repo.DeleteGrant(ctx, 2, "app-1"); err != nil {
+		t.Errorf("DeleteGrant() error = %v", err)
+	}
+	if _, err := repo.GetGrant(ctx, 2, "app-1"); !errors.Is(err, ErrOAuthGrantNotFound) {
+		t.Errorf("GetGrant() after delete error = %v, want %v", err, ErrOAuthGrantNotFound)
+	}
+
+	req := &models.OAuthPaymentRequest{
+		ClientID:    "app-1",
+		UserID:      2,
+		RecipientID: 3,
+		Amount:      12.5,
+		Status:      models.OAuthPaymentPending,
+		ExpiresAt:   now.Add(-time.Minute),
+	}
+	if err := repo.CreatePaymentRequest(ctx, req); err != nil {
+		t.Fatalf("CreatePaymentRequest() error = %v", err)
+	}
+	if pending, err := repo.ListPaymentRequests(ctx, 2, models.OAuthPaymentPending); err != nil || len(pending) != 1 {
+		t.Errorf("ListPaymentRequests() = %d requests, %v, want 1", len(pending), err)
+	}
+	if err := repo.ExpirePaymentRequests(ctx, 2, now); err != nil {
+		t.Fatalf("ExpirePaymentRequests() error = %v", err)
+	}
+	if decided, err := repo.DecidePaymentRequest(ctx, req.ID, models.OAuthPaymentApproved, now); err != nil || decided {
+		t.Errorf("DecidePaymentRequest() on an expired request = %v, %v, want false", decided, err)
+	}
+	if got, err := repo.GetPaymentRequest(ctx, req.ID); err != nil || got.Status != models.OAuthPaymentExpired {
+		t.Errorf("GetPaymentRequest() = %+v, %v, want expired", got, err)
+	}
+
+	if err := repo.DeleteClient(ctx, 1, "app-1"); err != nil {
+		t.Errorf("DeleteClient() error = %v", err)
+	}
+	if err := repo.DeleteClient(ctx, 1, "app-1"); !errors.Is(err, ErrOAuthClientNotFound) {
+		t.Errorf("second DeleteClient() error = %v, want %v", err, ErrOAuthClientNotFound)
+	}
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PartitionMonthsAhead is how many future months always have a partition
+// ready, so inserts never fall into the default partition
+const PartitionMonthsAhead = 3
+
+// PartitionManager creates the monthly partitions of the transactions
+// table, which the migrations range partition by created_at. Queries that
+// bound created_at only scan the months they need.
+type PartitionManager interface {
+	// EnsurePartitions creates the monthly partitions from the current month
+	// up to monthsAhead months ahead and returns how many it created
+	EnsurePartitions(monthsAhead int) (int, error)
+}
+
+type partitionManager struct {
+	db *gorm.DB
+}
+
+func NewPartitionManager(db *gorm.DB) PartitionManager {
+	return &partitionManager{db: db}
+}
+
+func (m *partitionManager) EnsurePartitions(monthsAhead int) (int, error) {
+	created := 0
+	current := monthStart(time.Now().UTC())
+	for i := 0; i <= monthsAhead; i++ {
+		ok, err := createPartition(m.db, current.AddDate(0, i, 0))
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// createPartition creates the partition holding the month starting at
+// month, returning false if it already exists
+func createPartition(db *gorm.DB, month time.Time) (bool, error) {
+	name := fmt.Sprintf("transactions_%04d_%02d", month.Year(), int(month.Month()))
+	var exists bool
+	if err := db.Raw("SELECT to_regclass(?) IS NOT NULL", name).Row().Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check partition %s: %w", name, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	err := db.Exec(fmt.Sprintf(`CREATE TABLE %s PARTITION OF transactions FOR VALUES FROM ('%s') TO ('%s')`,
+		name, month.Format(time.RFC3339), month.AddDate(0, 1, 0).Format(time.RFC3339))).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to create partition %s: %w", name, err)
+	}
+	return true, nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrPaymentCodeNotFound = errors.New("payment code not found")
+
+// PaymentCodeRepository persists one-time payment codes by their hash
+type PaymentCodeRepository interface {
+	Create(ctx context.Context, code *models.PaymentCode) error
+	// ActiveExists reports whether an unexpired active code has the hash,
+	// so a new code never shadows one still outstanding
+	ActiveExists(ctx context.Context, codeHash string, now time.Time) (bool, error)
+	// Redeem marks the unexpired active code with the hash redeemed by the
+	// merchant. Only one caller can redeem a code; the others get
+	// ErrPaymentCodeNotFound.
+	Redeem(ctx context.Context, codeHash string, merchantUserID uint, now time.Time) (*models.PaymentCode, error)
+	// Reopen makes a redeemed code active again after its payment failed
+	Reopen(ctx context.Context, id uint) error
+	LinkTransaction(ctx context.Context, id, transactionID uint) error
+}
+
+type paymentCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentCodeRepository(db *gorm.DB) PaymentCodeRepository {
+	return &paymentCodeRepository{db: db}
+}
+
+func (r *paymentCodeRepository) Create(ctx context.Context, code *models.PaymentCode) error {
+	if err := r.db.WithContext(ctx).Create(code).Error; err != nil {
+		return fmt.Errorf("failed to create payment code: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentCodeRepository) ActiveExists(ctx context.Context, codeHash string, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PaymentCode{}).
+		Where("code_hash = ? AND status = ? AND expires_at > ?", codeHash, models.PaymentCodeStatusActive, now).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to look up payment code: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *paymentCodeRepository) Redeem(ctx context.Context, codeHash string, merchantUserID uint, now time.Time) (*models.PaymentCode, error) {
+	var code models.PaymentCode
+	err := r.db.WithContext(ctx).
+		Where("code_hash = ? AND status = ? AND expires_at > ?", codeHash, models.PaymentCodeStatusActive, now).
+		First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to look up payment code: %w", err)
+	}
+
+	// Conditional on the status, so two tills entering the same code can't
+	// both redeem it
+	res := r.db.WithContext(ctx).Model(&models.PaymentCode{}).
+		Where("id = ? AND status = ?", code.ID, models.PaymentCodeStatusActive).
+		Updates(map[string]interface{}{
+			"status":           models.PaymentCodeStatusRedeemed,
+			"merchant_user_id": merchantUserID,
+			"redeemed_at":      now,
+		})
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to redeem payment code: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrPaymentCodeNotFound
+	}
+	code.Status = models.PaymentCodeStatusRedeemed
+	code.MerchantUserID = &merchantUserID
+	code.RedeemedAt = &now
+	return &code, nil
+}
+
+func (r *paymentCodeRepository) Reopen(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&models.PaymentCode{}).
+		Where("id = ? AND status = ? AND transaction_id IS NULL", id, models.PaymentCodeStatusRedeemed).
+		Updates(map[string]interface{}{
+			"status":           models.PaymentCodeStatusActive,
+			"merchant_user_id": nil,
+			"redeemed_at":      nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reopen payment code: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentCodeRepository) LinkTransaction(ctx context.Context, id, transactionID uint) error {
+	err := r.db.WithContext(ctx).Model(&models.PaymentCode{}).
+		Where("id = ?", id).
+		Update("transaction_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to link payment code to transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentConfirmationRepository stores users' confirmation settings and
+// the queue of confirmations owed for completed payments
+type PaymentConfirmationRepository interface {
+	// GetSettings returns the user's settings, or the defaults if they
+	// never changed them
+	GetSettings(userID uint) (*models.ConfirmationSettings, error)
+	SaveSettings(settings *models.ConfirmationSettings) error
+	// GetSettingsFor returns the saved settings of the users, keyed by
+	// user; users missing from the map use the defaults
+	GetSettingsFor(userIDs []uint) (map[uint]models.ConfirmationSettings, error)
+
+	// Queue adds the confirmations, skipping those already queued for the
+	// same transaction and user, and returns how many it added
+	Queue(confirmations []models.PaymentConfirmation) (int64, error)
+	// Pending returns up to limit pending confirmations with their
+	// transactions, grouped by user and oldest first within a user
+	Pending(limit int) ([]models.PaymentConfirmation, error)
+	// MarkSent moves the pending confirmations to status
+	MarkSent(ids []uint, status string, at time.Time) error
+}
+
+type paymentConfirmationRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentConfirmationRepository(db *gorm.DB) PaymentConfirmationRepository {
+	return &paymentConfirmationRepository{db: db}
+}
+
+func defaultConfirmationSettings(userID uint) *models.ConfirmationSettings {
+	return &models.ConfirmationSettings{UserID: userID, Channel: models.ConfirmationChannelEmail}
+}
+
+func (r *paymentConfirmationRepository) GetSettings(userID uint) (*models.ConfirmationSettings, error) {
+	var settings models.ConfirmationSettings
+	if err := r.db.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultConfirmationSettings(userID), nil
+		}
+		return nil, fmt.Errorf("failed to get confirmation settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *paymentConfirmationRepository) SaveSettings(settings *models.ConfirmationSettings) error {
+	if err := r.db.Save(settings).Error; err != nil {
+		return fmt.Errorf("failed to save confirmation settings: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentConfirmationRepository) GetSettingsFor(userIDs []uint) (map[uint]models.ConfirmationSettings, error) {
+	settings := make(map[uint]models.ConfirmationSettings)
+	if len(userIDs) == 0 {
+		return settings, nil
+	}
+	var rows []models.ConfirmationSettings
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get confirmation settings: %w", err)
+	}
+	for _, row := range rows {
+		settings[row.UserID] = row
+	}
+	return settings, nil
+}
+
+func (r *paymentConfirmationRepository) Queue(confirmations []models.PaymentConfirmation) (int64, error) {
+	if len(confirmations) == 0 {
+		return 0, nil
+	}
+	result := r.db.Omit("Transaction").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&confirmations)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to queue payment confirmations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *paymentConfirmationRepository) Pending(limit int) ([]models.PaymentConfirmation, error) {
+	var confirmations []models.PaymentConfirmation
+	err := r.db.Preload("Transaction").
+		Where("status = ?", models.ConfirmationPending).
+		Order("user_id ASC, id ASC").
+		Limit(limit).
+		Find(&confirmations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending payment confirmations: %w", err)
+	}
+	return confirmations, nil
+}
+
+func (r *paymentConfirmationRepository) MarkSent(ids []uint, status string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := r.db.Model(&models.PaymentConfirmation{}).
+		Where("id IN ? AND status = ?", ids, models.ConfirmationPending).
+		Updates(map[string]interface{}{"status": status, "sent_at": at}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark payment confirmations sent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrPaymentIntentNotFound = errors.New("payment intent not found")
+
+// PaymentIntentRepository persists payment intents
+type PaymentIntentRepository interface {
+	Create(ctx context.Context, intent *models.PaymentIntent) error
+	GetByID(ctx context.Context, id uint) (*models.PaymentIntent, error)
+	// MarkPaid moves an open intent to paid by the payer. Only one caller
+	// can pay an intent; the others get false.
+	MarkPaid(ctx context.Context, intent *models.PaymentIntent, payerID uint, now time.Time) (bool, error)
+	// Reopen makes a paid intent open again after its payment failed
+	Reopen(ctx context.Context, id uint) error
+	LinkTransaction(ctx context.Context, id, transactionID uint) error
+}
+
+type paymentIntentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentIntentRepository(db *gorm.DB) PaymentIntentRepository {
+	return &paymentIntentRepository{db: db}
+}
+
+func (r *paymentIntentRepository) Create(ctx context.Context, intent *models.PaymentIntent) error {
+	if err := r.db.WithContext(ctx).Create(intent).Error; err != nil {
+		return fmt.Errorf("failed to create payment intent: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentIntentRepository) GetByID(ctx context.Context, id uint) (*models.PaymentIntent, error) {
+	var intent models.PaymentIntent
+	if err := r.db.WithContext(ctx).First(&intent, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentIntentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment intent: %w", err)
+	}
+	return &intent, nil
+}
+
+func (r *paymentIntentRepository) MarkPaid(ctx context.Context, intent *models.PaymentIntent, payerID uint, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.PaymentIntent{}).
+		Where("id = ? AND status = ?", intent.ID, models.PaymentIntentStatusOpen).
+		Updates(map[string]interface{}{
+			"status":   models.PaymentIntentStatusPaid,
+			"payer_id": payerID,
+			"paid_at":  now,
+		})
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to mark payment intent paid: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	intent.Status = models.PaymentIntentStatusPaid
+	intent.PayerID = &payerID
+	intent.PaidAt = &now
+	return true, nil
+}
+
+func (r *paymentIntentRepository) Reopen(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&models.PaymentIntent{}).
+		Where("id = ? AND status = ? AND transaction_id IS NULL", id, models.PaymentIntentStatusPaid).
+		Updates(map[string]interface{}{
+			"status":   models.PaymentIntentStatusOpen,
+			"payer_id": nil,
+			"paid_at":  nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reopen payment intent: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentIntentRepository) LinkTransaction(ctx context.Context, id, transactionID uint) error {
+	err := r.db.WithContext(ctx).Model(&models.PaymentIntent{}).
+		Where("id = ?", id).
+		Update("transaction_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to link payment intent to transaction: %w", err)
+	}
+	return nil
+}
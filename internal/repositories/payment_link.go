@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentLinkNotFound     = errors.New("payment link not found")
+	ErrPaymentLinkExhausted    = errors.New("payment link has reached its maximum uses")
+	ErrCheckoutSessionNotFound = errors.New("checkout session not found")
+)
+
+// PaymentLinkRepository persists merchant payment links and checkout sessions
+type PaymentLinkRepository interface {
+	Create(link *models.PaymentLink) error
+	GetByID(id uint) (*models.PaymentLink, error)
+	GetByCode(code string) (*models.PaymentLink, error)
+	GetByIDAndMerchantID(id, merchantID uint) (*models.PaymentLink, error)
+	GetByMerchantID(merchantID uint, limit, offset int) ([]models.PaymentLink, int64, error)
+	Update(link *models.PaymentLink) error
+	IncrementViews(id uint) error
+	ReserveUse(id uint) error
+	ReleaseUse(id uint) error
+	// GetMerchantTotals sums views, uses and revenue across the merchant's
+	// links
+	GetMerchantTotals(merchantID uint) (*PaymentLinkTotals, error)
+
+	CreateSession(session *models.CheckoutSession) error
+	GetSession(sessionID string) (*models.CheckoutSession, error)
+	UpdateSession(session *models.CheckoutSession) error
+	// TransitionSession applies updates only if the session is in status
+	// from. It returns false if it wasn't.
+	TransitionSession(id uint, from string, updates map[string]interface{}) (bool, error)
+	// ListSessions returns a merchant's checkout sessions from one source,
+	// newest first, optionally filtered by status
+	ListSessions(merchantID uint, source, status string, limit, offset int) ([]models.CheckoutSession, int64, error)
+}
+
+// PaymentLinkTotals is how a merchant's payment links are doing
+type PaymentLinkTotals struct {
+	Links       int64
+	ActiveLinks int64
+	Views       int64
+	Uses        int64
+	Revenue     float64
+}
+
+type paymentLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentLinkRepository(db *gorm.DB) PaymentLinkRepository {
+	return &paymentLinkRepository{db: db}
+}
+
+func (r *paymentLinkRepository) Create(link *models.PaymentLink) error {
+	if err := r.db.Create(link).Error; err != nil {
+		return fmt.Errorf("failed to create payment link: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentLinkRepository) GetByID(id uint) (*models.PaymentLink, error) {
+	var link models.PaymentLink
+	if err := r.db.First(&link, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *paymentLinkRepository) GetByCode(code string) (*models.PaymentLink, error) {
+	var link models.PaymentLink
+	if err := r.db.Where("code = ?", code).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *paymentLinkRepository) GetByIDAndMerchantID(id, merchantID uint) (*models.PaymentLink, error) {
+	var link models.PaymentLink
+	if err := r.db.Where("id = ? AND merchant_id = ?", id, merchantID).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *paymentLinkRepository) GetByMerchantID(merchantID uint, limit, offset int) ([]models.PaymentLink, int64, error) {
+	var links []models.PaymentLink
+	var total int64
+
+	query := r.db.Model(&models.PaymentLink{}).Where("merchant_id = ?", merchantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count payment links: %w", err)
+	}
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&links).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get payment links: %w", err)
+	}
+	return links, total, nil
+}
+
+func (r *paymentLinkRepository) Update(link *models.PaymentLink) error {
+	if err := r.db.Save(link).Error; err != nil {
+		return fmt.Errorf("failed to update payment link: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentLinkRepository) IncrementViews(id uint) error {
+	err := r.db.Model(&models.PaymentLink{}).Where("id = ?", id).
+		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+	if err != nil {
+		return fmt.Errorf("failed to record payment link view: %w", err)
+	}
+	return nil
+}
+
+// ReserveUse atomically claims one use so concurrent payers can't exceed max uses
+func (r *paymentLinkRepository) ReserveUse(id uint) error {
+	result := r.db.Model(&models.PaymentLink{}).
+		Where("id = ? AND (max_uses = -1 OR use_count < max_uses)", id).
+		UpdateColumn("use_count", gorm.Expr("use_count + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to reserve payment link use: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrPaymentLinkExhausted
+	}
+	return nil
+}
+
+// ReleaseUse gives back a reserved use after a failed payment
+func (r *paymentLinkRepository) ReleaseUse(id uint) error {
+	err := r.db.Model(&models.PaymentLink{}).Where("id = ? AND use_count > 0", id).
+		UpdateColumn("use_count", gorm.Expr("use_count - 1")).Error
+	if err != nil {
+		return fmt.Errorf("failed to release payment link use: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentLinkRepository) CreateSession(session *models.CheckoutSession) error {
+	if err := r.db.Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create checkout session: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentLinkRepository) GetSession(sessionID string) (*models.CheckoutSession, error) {
+	var session models.CheckoutSession
+	if err := r.db.Where("session_id = ?", sessionID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCheckoutSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get checkout session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *paymentLinkRepository) UpdateSession(session *models.CheckoutSession) error {
+	if err := r.db.Save(session).Error; err != nil {
+		return fmt.Errorf("failed to update checkout session: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentLinkRepository) TransitionSession(id uint, from string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.CheckoutSession{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update checkout session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *paymentLinkRepository) ListSessions(merchantID uint, source, status string, limit, offset int) ([]models.CheckoutSession, int64, error) {
+	var sessions []models.CheckoutSession
+	var total int64
+
+	query := r.db.Model(&models.CheckoutSession{}).Where("merchant_id = ? AND source = ?", merchantID, source)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count checkout sessions: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&sessions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get checkout sessions: %w", err)
+	}
+	return sessions, total, nil
+}
+
+func (r *paymentLinkRepository) GetMerchantTotals(merchantID uint) (*PaymentLinkTotals, error) {
+	var totals PaymentLinkTotals
+	err := r.db.Model(&models.PaymentLink{}).
+		Where("merchant_id = ?", merchantID).
+		Select(`COUNT(*), COUNT(*) FILTER (WHERE status = ?),
+			COALESCE(SUM(view_count), 0), COALESCE(SUM(use_count), 0), COALESCE(SUM(use_count * amount), 0)`,
+			models.PaymentLinkStatusActive).
+		Row().Scan(&totals.Links, &totals.ActiveLinks, &totals.Views, &totals.Uses, &totals.Revenue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment link totals: %w", err)
+	}
+	return &totals, nil
+}
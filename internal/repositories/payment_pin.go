@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrPaymentPINNotFound = errors.New("payment PIN not set")
+
+// PaymentPINRepository persists users' payment PINs and their lockout state
+type PaymentPINRepository interface {
+	Get(userID uint) (*models.PaymentPIN, error)
+	// Save sets the user's PIN, replacing any they had and clearing its
+	// lockout
+	Save(pin *models.PaymentPIN) error
+	// RecordFailure counts a wrong PIN in one statement, so parallel
+	// guesses can't lose count, locking the PIN until lockUntil on the
+	// maxAttempts-th in a row. It reports whether the PIN is locked, which
+	// it already is if another guess locked it first.
+	RecordFailure(userID uint, maxAttempts int, lockUntil, now time.Time) (bool, error)
+	// ClearFailures resets the count after a right PIN. It reports false,
+	// clearing nothing, if another guess locked the PIN meanwhile.
+	ClearFailures(userID uint, now time.Time) (bool, error)
+}
+
+type paymentPINRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentPINRepository(db *gorm.DB) PaymentPINRepository {
+	return &paymentPINRepository{db: db}
+}
+
+func (r *paymentPINRepository) Get(userID uint) (*models.PaymentPIN, error) {
+	var pin models.PaymentPIN
+	if err := r.db.Where("user_id = ?", userID).First(&pin).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentPINNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment PIN: %w", err)
+	}
+	return &pin, nil
+}
+
+func (r *paymentPINRepository) Save(pin *models.PaymentPIN) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"pin_hash", "failed_attempts", "locked_until", "set_at", "updated_at",
+		}),
+	}).Create(pin).Error
+	if err != nil {
+		return fmt.Errorf("failed to save payment PIN: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentPINRepository) RecordFailure(userID uint, maxAttempts int, lockUntil, now time.Time) (bool, error) {
+	var counted []struct{ FailedAttempts int }
+	err := r.db.Raw(`UPDATE payment_pins SET
+			failed_attempts = CASE WHEN failed_attempts + 1 >= @max THEN 0 ELSE failed_attempts + 1 END,
+			locked_until = CASE WHEN failed_attempts + 1 >= @max THEN @until ELSE NULL END,
+			updated_at = @now
+		WHERE user_id = @user AND (locked_until IS NULL OR locked_until <= @now)
+		RETURNING failed_attempts`,
+		map[string]interface{}{"max": maxAttempts, "until": lockUntil, "now": now, "user": userID}).
+		Scan(&counted).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to record wrong payment PIN: %w", err)
+	}
+	// No row means the PIN was locked first; a count back at zero means
+	// this guess locked it
+	return len(counted) == 0 || counted[0].FailedAttempts == 0, nil
+}
+
+func (r *paymentPINRepository) ClearFailures(userID uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.PaymentPIN{}).
+		Where("user_id = ? AND (locked_until IS NULL OR locked_until <= ?)", userID, now).
+		Updates(map[string]interface{}{"failed_attempts": 0, "locked_until": nil})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to clear payment PIN attempts: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTransactionNotPending = errors.New("transaction is no longer pending")
+
+// PendingTransactionRepository finds the transactions left pending and
+// expires them
+type PendingTransactionRepository interface {
+	// FindStale returns up to limit transactions pending since before
+	// cutoff, oldest first
+	FindStale(cutoff time.Time, limit int) ([]models.Transaction, error)
+	// List pages through the transactions pending since before cutoff,
+	// oldest first
+	List(cutoff time.Time, limit, offset int) ([]models.Transaction, int64, error)
+	// Expire marks the transaction expired and releases the active holds
+	// placed for it, those referencing its transaction ID, returning how
+	// many it released. It returns ErrTransactionNotPending when the
+	// transaction was resolved in the meantime.
+	Expire(tx *models.Transaction, now time.Time) (int64, error)
+}
+
+type pendingTransactionRepository struct {
+	db *gorm.DB
+}
+
+func NewPendingTransactionRepository(db *gorm.DB) PendingTransactionRepository {
+	return &pendingTransactionRepository{db: db}
+}
+
+func (r *pendingTransactionRepository) stale(cutoff time.Time) *gorm.DB {
+	return r.db.Model(&models.Transaction{}).Where("status = ? AND created_at < ?", "pending", cutoff)
+}
+
+func (r *pendingTransactionRepository) FindStale(cutoff time.Time, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	if err := r.stale(cutoff).Order("created_at").Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to find stale pending transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *pendingTransactionRepository) List(cutoff time.Time, limit, offset int) ([]models.Transaction, int64, error) {
+	var total int64
+	if err := r.stale(cutoff).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending transactions: %w", err)
+	}
+	var transactions []models.Transaction
+	if err := r.stale(cutoff).Order("created_at").Limit(limit).Offset(offset).Find(&transactions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+	return transactions, total, nil
+}
+
+func (r *pendingTransactionRepository) Expire(tx *models.Transaction, now time.Time) (int64, error) {
+	var released int64
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		res := db.Model(&models.Transaction{}).
+			Where("id = ? AND status = ?", tx.ID, "pending").
+			Updates(map[string]interface{}{"status": models.TransactionStatusExpired, "updated_at": now})
+		if res.Error != nil {
+			return fmt.Errorf("failed to expire transaction: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrTransactionNotPending
+		}
+		tx.Status = models.TransactionStatusExpired
+
+		if tx.TransactionID == "" {
+			return nil
+		}
+		res = db.Model(&models.WalletHold{}).
+			Where("reference = ? AND status = ?", tx.TransactionID, models.HoldStatusActive).
+			Updates(map[string]interface{}{"status": models.HoldStatusReleased, "released_at": now})
+		if res.Error != nil {
+			return fmt.Errorf("failed to release holds: %w", res.Error)
+		}
+		released = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return released, nil
+}
@@ -0,0 +1,269 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPotNotFound          = errors.New("pot not found")
+	ErrInsufficientPotFunds = errors.New("insufficient funds in pot")
+	ErrPotNotActive         = errors.New("pot is not active")
+	ErrRoundUpAlreadyTaken  = errors.New("payment already rounded up")
+)
+
+// PotMove moves money between a user's main balance and one of their pots.
+// A positive Amount moves into the pot, a negative one back to the wallet.
+type PotMove struct {
+	PotID               uint
+	UserID              uint
+	Amount              float64
+	Kind                string
+	Transaction         *models.Transaction
+	SourceTransactionID *uint
+	// Close marks the pot closed in the same database transaction
+	Close bool
+}
+
+// PotRepository persists savings pots and the ledger of moves into and out of them
+type PotRepository interface {
+	Create(pot *models.Pot) error
+	GetByIDAndUserID(id, userID uint) (*models.Pot, error)
+	GetByUserID(userID uint, includeClosed bool) ([]models.Pot, error)
+	// UpdateSettings saves everything except the balance and status
+	UpdateSettings(pot *models.Pot) error
+	GetEntries(potID uint, limit, offset int) ([]models.PotEntry, int64, error)
+
+	// Move applies a PotMove to the wallet and pot balances and records it in
+	// the transaction ledger and the pot's entries, all in one database transaction
+	Move(move PotMove) (*models.PotEntry, error)
+
+	GetRoundUpPots(limit int) ([]models.Pot, error)
+	// GetRoundUpCandidates returns the user's completed payments of the given
+	// types after afterID, oldest first
+	GetRoundUpCandidates(userID, afterID uint, types []string, limit int) ([]models.Transaction, error)
+	SetRoundUpCheckpoint(potID, transactionID uint) error
+	LatestTransactionID() (uint, error)
+	// HasRoundUpPot reports whether the user has another active pot collecting round-ups
+	HasRoundUpPot(userID, exceptPotID uint) (bool, error)
+
+	GetDueSweeps(now time.Time, limit int) ([]models.Pot, error)
+	SetNextSweep(potID uint, next *time.Time) error
+}
+
+type potRepository struct {
+	db *gorm.DB
+}
+
+func NewPotRepository(db *gorm.DB) PotRepository {
+	return &potRepository{db: db}
+}
+
+func (r *potRepository) Create(pot *models.Pot) error {
+	if err := r.db.Create(pot).Error; err != nil {
+		return fmt.Errorf("failed to create pot: %w", err)
+	}
+	return nil
+}
+
+func (r *potRepository) GetByIDAndUserID(id, userID uint) (*models.Pot, error) {
+	var pot models.Pot
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&pot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPotNotFound
+		}
+		return nil, fmt.Errorf("failed to get pot: %w", err)
+	}
+	return &pot, nil
+}
+
+func (r *potRepository) GetByUserID(userID uint, includeClosed bool) ([]models.Pot, error) {
+	var pots []models.Pot
+	query := r.db.Where("user_id = ?", userID)
+	if !includeClosed {
+		query = query.Where("status = ?", models.PotStatusActive)
+	}
+	if err := query.Order("created_at").Find(&pots).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pots: %w", err)
+	}
+	return pots, nil
+}
+
+func (r *potRepository) UpdateSettings(pot *models.Pot) error {
+	err := r.db.Model(pot).Select(
+		"name", "target_amount", "locked_until",
+		"round_up_enabled", "round_up_to", "round_up_after_id",
+		"sweep_amount", "sweep_frequency", "next_sweep_at",
+	).Updates(pot).Error
+	if err != nil {
+		return fmt.Errorf("failed to update pot: %w", err)
+	}
+	return nil
+}
+
+func (r *potRepository) GetEntries(potID uint, limit, offset int) ([]models.PotEntry, int64, error) {
+	var entries []models.PotEntry
+	var total int64
+
+	query := r.db.Model(&models.PotEntry{}).Where("pot_id = ?", potID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count pot entries: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get pot entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+func (r *potRepository) Move(move PotMove) (*models.PotEntry, error) {
+	var entry *models.PotEntry
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Wallets are locked before pots, whichever way the money moves
+		wallets := NewWalletRepository(tx)
+		if _, err := wallets.LockByUserID(move.UserID); err != nil {
+			return err
+		}
+		var pot models.Pot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", move.PotID, move.UserID).
+			First(&pot).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPotNotFound
+			}
+			return fmt.Errorf("failed to lock pot: %w", err)
+		}
+		if pot.Status != models.PotStatusActive {
+			return ErrPotNotActive
+		}
+
+		if move.SourceTransactionID != nil {
+			var taken int64
+			if err := tx.Model(&models.PotEntry{}).
+				Where("pot_id = ? AND source_transaction_id = ?", pot.ID, *move.SourceTransactionID).
+				Count(&taken).Error; err != nil {
+				return fmt.Errorf("failed to check round-up: %w", err)
+			}
+			if taken > 0 {
+				return ErrRoundUpAlreadyTaken
+			}
+		}
+
+		amount := math.Round(move.Amount*100) / 100
+		if amount < 0 && pot.Balance < -amount {
+			return ErrInsufficientPotFunds
+		}
+		if amount != 0 {
+			if _, err := wallets.AdjustBalance(move.UserID, -amount); err != nil {
+				if debitRefused(err) {
+					return ErrInsufficientPersonalFunds
+				}
+				return err
+			}
+		}
+		pot.Balance = math.Round((pot.Balance+amount)*100) / 100
+		potUpdates := map[string]interface{}{"balance": pot.Balance}
+		if move.Close {
+			potUpdates["status"] = models.PotStatusClosed
+			potUpdates["closed_at"] = time.Now()
+		}
+		if err := tx.Model(&pot).Updates(potUpdates).Error; err != nil {
+			return fmt.Errorf("failed to update pot: %w", err)
+		}
+		if amount == 0 {
+			// Closing an empty pot moves nothing, so there is nothing to record
+			return nil
+		}
+
+		if err := tx.Create(move.Transaction).Error; err != nil {
+			return fmt.Errorf("failed to record pot transfer: %w", err)
+		}
+		entry = &models.PotEntry{
+			PotID:               pot.ID,
+			Kind:                move.Kind,
+			Amount:              amount,
+			BalanceAfter:        pot.Balance,
+			TransactionID:       move.Transaction.ID,
+			SourceTransactionID: move.SourceTransactionID,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to record pot entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *potRepository) GetRoundUpPots(limit int) ([]models.Pot, error) {
+	var pots []models.Pot
+	err := r.db.Where("round_up_enabled = ? AND status = ?", true, models.PotStatusActive).
+		Order("id").Limit(limit).Find(&pots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round-up pots: %w", err)
+	}
+	return pots, nil
+}
+
+func (r *potRepository) GetRoundUpCandidates(userID, afterID uint, types []string, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Where("sender_id = ? AND id > ? AND status = ? AND type IN ?", userID, afterID, "completed", types).
+		Order("id").Limit(limit).Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments for round-up: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *potRepository) SetRoundUpCheckpoint(potID, transactionID uint) error {
+	err := r.db.Model(&models.Pot{}).
+		Where("id = ? AND round_up_after_id < ?", potID, transactionID).
+		Update("round_up_after_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to update round-up checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *potRepository) LatestTransactionID() (uint, error) {
+	var id uint
+	if err := r.db.Model(&models.Transaction{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to get latest transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (r *potRepository) HasRoundUpPot(userID, exceptPotID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Pot{}).
+		Where("user_id = ? AND id <> ? AND round_up_enabled = ? AND status = ?", userID, exceptPotID, true, models.PotStatusActive).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check round-up pots: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *potRepository) GetDueSweeps(now time.Time, limit int) ([]models.Pot, error) {
+	var pots []models.Pot
+	err := r.db.Where("status = ? AND sweep_amount > 0 AND next_sweep_at <= ?", models.PotStatusActive, now).
+		Order("next_sweep_at").Limit(limit).Find(&pots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due sweeps: %w", err)
+	}
+	return pots, nil
+}
+
+func (r *potRepository) SetNextSweep(potID uint, next *time.Time) error {
+	if err := r.db.Model(&models.Pot{}).Where("id = ?", potID).Update("next_sweep_at", next).Error; err != nil {
+		return fmt.Errorf("failed to schedule next sweep: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPromotionNotFound        = errors.New("promotion not found")
+	ErrPromotionNotActive       = errors.New("promotion is not active")
+	ErrPromotionLimitReached    = errors.New("promotion limit reached for this customer")
+	ErrPromotionAlreadyRewarded = errors.New("payment has already been rewarded by this promotion")
+	ErrInsufficientFunderFunds  = errors.New("insufficient funds to pay the promotion reward")
+)
+
+// PromotionGrant credits one promotion reward for a qualifying payment
+type PromotionGrant struct {
+	Promotion *models.Promotion
+	Payment   *models.Transaction
+	Amount    float64
+}
+
+// PromotionRepository persists promotions, code redemptions and the
+// rewards paid out under them
+type PromotionRepository interface {
+	Create(promotion *models.Promotion) error
+	GetByID(id uint) (*models.Promotion, error)
+	GetByCode(code string) (*models.Promotion, error)
+	List(status string, limit, offset int) ([]models.Promotion, int64, error)
+	// Transition applies updates only if the promotion is in one of from.
+	// It returns false if it wasn't.
+	Transition(id uint, from []string, updates map[string]interface{}) (bool, error)
+
+	// Enroll records a customer redeeming a promotion code. It returns
+	// false if they already had.
+	Enroll(enrollment *models.PromotionEnrollment) (bool, error)
+	// ListAvailable returns the running promotions open to the customer:
+	// those without a code and those whose code they redeemed
+	ListAvailable(userID uint, now time.Time) ([]models.Promotion, error)
+
+	// CountPayments returns the customer's completed payments of the given
+	// types other than excludeID, optionally only those to one merchant
+	CountPayments(userID, merchantUserID uint, types []string, excludeID uint) (int64, error)
+	// Grant pays a reward out of the promotion's budget and its funder,
+	// crediting the customer's wallet with a promotion transaction. The
+	// reward is trimmed to whatever budget is left.
+	Grant(grant PromotionGrant) (*models.PromotionReward, error)
+	ListRewards(promotionID, userID uint, limit, offset int) ([]models.PromotionReward, int64, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) Create(promotion *models.Promotion) error {
+	if err := r.db.Create(promotion).Error; err != nil {
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return nil
+}
+
+func (r *promotionRepository) GetByID(id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) GetByCode(code string) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.Where("code = ?", code).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) List(status string, limit, offset int) ([]models.Promotion, int64, error) {
+	var promotions []models.Promotion
+	var total int64
+
+	query := r.db.Model(&models.Promotion{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count promotions: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&promotions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get promotions: %w", err)
+	}
+	return promotions, total, nil
+}
+
+func (r *promotionRepository) Transition(id uint, from []string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.Promotion{}).Where("id = ? AND status IN ?", id, from).Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update promotion: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *promotionRepository) Enroll(enrollment *models.PromotionEnrollment) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(enrollment)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to redeem promotion code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *promotionRepository) ListAvailable(userID uint, now time.Time) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	err := r.db.
+		Where("status = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", models.PromotionStatusActive, now, now).
+		Where("code IS NULL OR id IN (?)",
+			r.db.Model(&models.PromotionEnrollment{}).Select("promotion_id").Where("user_id = ?", userID)).
+		Order("id").Find(&promotions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get available promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+func (r *promotionRepository) CountPayments(userID, merchantUserID uint, types []string, excludeID uint) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.Transaction{}).
+		Where("sender_id = ? AND LOWER(type) IN ? AND status = ? AND id <> ?", userID, types, "completed", excludeID)
+	if merchantUserID != 0 {
+		query = query.Where("receiver_id = ?", merchantUserID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count payments: %w", err)
+	}
+	return count, nil
+}
+
+func (r *promotionRepository) Grant(grant PromotionGrant) (*models.PromotionReward, error) {
+	var reward *models.PromotionReward
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		var promotion models.Promotion
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, grant.Promotion.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPromotionNotFound
+			}
+			return fmt.Errorf("failed to lock promotion: %w", err)
+		}
+		if promotion.Status != models.PromotionStatusActive {
+			return ErrPromotionNotActive
+		}
+
+		customerID := grant.Payment.SenderID
+		if promotion.MaxPerUser > 0 {
+			var count int64
+			if err := db.Model(&models.PromotionReward{}).
+				Where("promotion_id = ? AND user_id = ?", promotion.ID, customerID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to count promotion rewards: %w", err)
+			}
+			if count >= int64(promotion.MaxPerUser) {
+				return ErrPromotionLimitReached
+			}
+		}
+
+		amount := math.Min(grant.Amount, promotion.Budget-promotion.Spent)
+		amount = math.Round(amount*100) / 100
+		if amount <= 0 {
+			return ErrPromotionNotActive
+		}
+
+		// A merchant pays its own rewards; the platform's come from the
+		// promotions expense account
+		funderID := uint(0)
+		if promotion.FundedBy == models.PromotionFundedByMerchant {
+			funderID = *promotion.MerchantID
+		}
+		wallets := NewWalletRepository(db)
+		if funderID != 0 {
+			if _, _, err := wallets.TransferFunds(funderID, customerID, amount, 0); err != nil {
+				if debitRefused(err) {
+					return ErrInsufficientFunderFunds
+				}
+				return fmt.Errorf("failed to pay promotion reward: %w", err)
+			}
+		} else if _, err := wallets.AdjustBalance(customerID, amount); err != nil {
+			return fmt.Errorf("failed to credit customer wallet: %w", err)
+		}
+
+		now := time.Now()
+		tx := &models.Transaction{
+			Type:          models.TransactionTypePromotion,
+			SenderID:      funderID,
+			ReceiverID:    customerID,
+			Amount:        amount,
+			Currency:      grant.Payment.Currency,
+			Status:        "completed",
+			Description:   promotion.Name,
+			TransactionID: fmt.Sprintf("PRM-%d-%d", promotion.ID, now.UnixNano()),
+			Reference:     grant.Payment.TransactionID,
+			PaymentType:   "promotion",
+			PaymentMethod: promotion.FundedBy,
+			MerchantID:    promotion.MerchantID,
+			Category:      "Rewards",
+			ProcessedAt:   now,
+			Metadata: models.NewJSON(map[string]interface{}{
+				"promotion_id":           promotion.ID,
+				"promotion_type":         promotion.Type,
+				"payment_transaction_id": grant.Payment.ID,
+				"funded_by":              promotion.FundedBy,
+			}),
+		}
+		if err := db.Create(tx).Error; err != nil {
+			return fmt.Errorf("failed to record promotion reward: %w", err)
+		}
+		if funderID == 0 {
+			if err := models.PostSystemEntry(db, models.SystemAccountPromotionsExpense, &models.SystemLedgerEntry{
+				Kind:          models.SystemEntryPromotion,
+				Amount:        -amount,
+				TransactionID: &tx.ID,
+				Description:   fmt.Sprintf("Promotion #%d reward", promotion.ID),
+			}); err != nil {
+				return err
+			}
+		}
+
+		reward = &models.PromotionReward{
+			PromotionID:          promotion.ID,
+			UserID:               customerID,
+			PaymentTransactionID: grant.Payment.ID,
+			RewardTransactionID:  tx.ID,
+			Amount:               amount,
+			FundedBy:             promotion.FundedBy,
+		}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(reward)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record promotion reward: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrPromotionAlreadyRewarded
+		}
+
+		spent := math.Round((promotion.Spent+amount)*100) / 100
+		updates := map[string]interface{}{"spent": spent}
+		if spent >= promotion.Budget {
+			updates["status"] = models.PromotionStatusExhausted
+		}
+		if err := db.Model(&promotion).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update promotion budget: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reward, nil
+}
+
+func (r *promotionRepository) ListRewards(promotionID, userID uint, limit, offset int) ([]models.PromotionReward, int64, error) {
+	var rewards []models.PromotionReward
+	var total int64
+
+	query := r.db.Model(&models.PromotionReward{})
+	if promotionID != 0 {
+		query = query.Where("promotion_id = ?", promotionID)
+	}
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count promotion rewards: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&rewards).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get promotion rewards: %w", err)
+	}
+	return rewards, total, nil
+}
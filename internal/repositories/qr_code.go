@@ -2,16 +2,53 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"orus/internal/models"
-	"orus/internal/utils"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+var ErrQRCodeNotFound = errors.New("QR code not found")
+
+// QRUsage sums the payments made with a QR code
+type QRUsage struct {
+	Scans        int64   `json:"scans"`
+	Volume       float64 `json:"volume"`
+	UniquePayers int64   `json:"unique_payers"`
+}
+
 type QRCodeRepository interface {
-	GetQRCodesByUserID(ctx context.Context, userID uint) ([]*models.QRCode, error)
-	// Add other QR code related methods as needed
+	// GetQRCodesByUserID returns the user's codes, newest first. A non-empty
+	// status keeps only codes in that status.
+	GetQRCodesByUserID(ctx context.Context, userID uint, status string) ([]*models.QRCode, error)
+	GetByCode(code string) (*models.QRCode, error)
+	// GetActiveByCode finds an active code, returning ErrQRCodeNotFound if
+	// there is none
+	GetActiveByCode(ctx context.Context, code string) (*models.QRCode, error)
+	Create(ctx context.Context, qr *models.QRCode) error
+	GetByIDAndUserID(ctx context.Context, id, userID uint) (*models.QRCode, error)
+	GetByID(ctx context.Context, id uint) (*models.QRCode, error)
+
+	// Revoke marks an active code revoked. It returns ErrQRCodeNotFound if
+	// the code is no longer active.
+	Revoke(ctx context.Context, qr *models.QRCode, reason string) error
+	// Expire marks an active code expired. It returns ErrQRCodeNotFound if
+	// the code is no longer active.
+	Expire(ctx context.Context, qr *models.QRCode) error
+	// Replace revokes old and creates replacement in one database
+	// transaction, linking the two
+	Replace(ctx context.Context, old, replacement *models.QRCode, reason string) error
+	// RecordUse saves a completed payment made with a code and adds it to
+	// the code's running totals
+	RecordUse(ctx context.Context, use *models.QRTransaction) error
+	// Usage sums the completed payments made with a code since a time; a
+	// zero since covers all of them
+	Usage(ctx context.Context, qrID uint, since time.Time) (*QRUsage, error)
+	// ExpireStale marks active dynamic codes expired once they are past
+	// their expiry date or have no uses left, returning how many it marked
+	ExpireStale(ctx context.Context, now time.Time) (int64, error)
 }
 
 type qrCodeRepository struct {
@@ -22,115 +59,154 @@ func NewQRCodeRepository(db *gorm.DB) QRCodeRepository {
 	return &qrCodeRepository{db: db}
 }
 
-func (r *qrCodeRepository) GetQRCodesByUserID(ctx context.Context, userID uint) ([]*models.QRCode, error) {
+func (r *qrCodeRepository) GetQRCodesByUserID(ctx context.Context, userID uint, status string) ([]*models.QRCode, error) {
 	var qrCodes []*models.QRCode
-	err := r.db.Where("user_id = ?", userID).Find(&qrCodes).Error
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("id DESC").Find(&qrCodes).Error
 	return qrCodes, err
 }
 
-func CreateQRCode(qr *models.QRCode) (*models.QRCode, error) {
-	if err := DB.Create(qr).Error; err != nil {
+func (r *qrCodeRepository) GetByCode(code string) (*models.QRCode, error) {
+	var qr models.QRCode
+	if err := r.db.Where("code = ?", code).First(&qr).Error; err != nil {
 		return nil, err
 	}
-	return qr, nil
+	return &qr, nil
 }
 
-func GetQRCodeByCode(code string) (*models.QRCode, error) {
+func (r *qrCodeRepository) GetActiveByCode(ctx context.Context, code string) (*models.QRCode, error) {
 	var qr models.QRCode
-	if err := DB.Where("code = ?", code).First(&qr).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Where("code = ? AND status = ?", code, models.QRStatusActive).First(&qr).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQRCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get QR code: %w", err)
 	}
 	return &qr, nil
 }
 
-func GetQRCodeByCodeForUpdate(code string) (*models.QRCode, error) {
+func (r *qrCodeRepository) Create(ctx context.Context, qr *models.QRCode) error {
+	if err := r.db.WithContext(ctx).Create(qr).Error; err != nil {
+		return fmt.Errorf("failed to create QR code: %w", err)
+	}
+	return nil
+}
+
+func (r *qrCodeRepository) GetByIDAndUserID(ctx context.Context, id, userID uint) (*models.QRCode, error) {
 	var qr models.QRCode
-	if err := DB.Set("gorm:for_update", true).Where("code = ?", code).First(&qr).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&qr).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQRCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get QR code: %w", err)
 	}
 	return &qr, nil
 }
 
-func GetQRCodeDailyTotal(ctx context.Context, qrID uint) (float64, error) {
-	var total float64
-	today := time.Now().Truncate(24 * time.Hour)
-	tomorrow := today.Add(24 * time.Hour)
-
-	err := DB.WithContext(ctx).Model(&models.QRTransaction{}).
-		Where("qr_code_id = ? AND created_at >= ? AND created_at < ?", qrID, today, tomorrow).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&total).Error
-
-	return total, err
+func (r *qrCodeRepository) GetByID(ctx context.Context, id uint) (*models.QRCode, error) {
+	var qr models.QRCode
+	if err := r.db.WithContext(ctx).First(&qr, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQRCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get QR code: %w", err)
+	}
+	return &qr, nil
 }
 
-func GetQRCodeMonthlyTotal(ctx context.Context, qrID uint) (float64, error) {
-	var total float64
-	startOfMonth := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -time.Now().UTC().Day()+1)
-	startOfNextMonth := startOfMonth.AddDate(0, 1, 0)
-
-	err := DB.WithContext(ctx).Model(&models.QRTransaction{}).
-		Where("qr_code_id = ? AND created_at >= ? AND created_at < ?", qrID, startOfMonth, startOfNextMonth).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&total).Error
+func (r *qrCodeRepository) Revoke(ctx context.Context, qr *models.QRCode, reason string) error {
+	return revokeQRCode(r.db.WithContext(ctx), qr, reason)
+}
 
-	return total, err
+func (r *qrCodeRepository) Expire(ctx context.Context, qr *models.QRCode) error {
+	res := r.db.WithContext(ctx).Model(&models.QRCode{}).
+		Where("id = ? AND status = ?", qr.ID, models.QRStatusActive).
+		Update("status", models.QRStatusExpired)
+	if res.Error != nil {
+		return fmt.Errorf("failed to expire QR code: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrQRCodeNotFound
+	}
+	qr.Status = models.QRStatusExpired
+	return nil
 }
 
-func GetUserStaticQR(userID uint) (*models.QRCode, error) {
-	var qr models.QRCode
-	err := DB.Where("user_id = ? AND user_type = ? AND type = ?",
-		userID, "user", models.QRTypeStatic).First(&qr).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// If no static QR exists, create one
-			qr = models.QRCode{
-				Code:     utils.MustGenerateSecureCode(),
-				UserID:   userID,
-				UserType: "user",
-				Type:     models.QRTypeStatic,
-				Status:   "active",
-				MaxUses:  -1, // Unlimited uses
-			}
-
-			// Set default limits
-			dailyLimit := float64(1000)
-			monthlyLimit := float64(5000)
-			qr.DailyLimit = &dailyLimit
-			qr.MonthlyLimit = &monthlyLimit
-
-			if err := DB.Create(&qr).Error; err != nil {
-				return nil, err
-			}
-			return &qr, nil
+func (r *qrCodeRepository) Replace(ctx context.Context, old, replacement *models.QRCode, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(replacement).Error; err != nil {
+			return fmt.Errorf("failed to create QR code: %w", err)
 		}
-		return nil, err
+		old.ReplacedByID = &replacement.ID
+		return revokeQRCode(tx, old, reason)
+	})
+}
+
+// revokeQRCode revokes qr only while it is still active, so a code used up
+// or revoked concurrently isn't revoked twice
+func revokeQRCode(db *gorm.DB, qr *models.QRCode, reason string) error {
+	now := time.Now()
+	res := db.Model(&models.QRCode{}).
+		Where("id = ? AND status = ?", qr.ID, models.QRStatusActive).
+		Updates(map[string]interface{}{
+			"status":         models.QRStatusRevoked,
+			"revoked_at":     now,
+			"revoke_reason":  reason,
+			"replaced_by_id": qr.ReplacedByID,
+		})
+	if res.Error != nil {
+		return fmt.Errorf("failed to revoke QR code: %w", res.Error)
 	}
-	return &qr, nil
+	if res.RowsAffected == 0 {
+		return ErrQRCodeNotFound
+	}
+	qr.Status = models.QRStatusRevoked
+	qr.RevokedAt = &now
+	qr.RevokeReason = reason
+	return nil
 }
 
-func GetUserPaymentQR(userID uint) (*models.QRCode, error) {
-	var qr models.QRCode
-	err := DB.Where("user_id = ? AND type = ?", userID, models.QRTypePayment).First(&qr).Error
-
-	if err == gorm.ErrRecordNotFound {
-		// Create new payment QR if not found
-		qr = models.QRCode{
-			Code:     utils.MustGenerateSecureCode(),
-			UserID:   userID,
-			UserType: "user",
-			Type:     models.QRTypePayment,
-			Status:   "active",
-			MaxUses:  -1, // Never expires
+func (r *qrCodeRepository) RecordUse(ctx context.Context, use *models.QRTransaction) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(use).Error; err != nil {
+			return err
 		}
+		return tx.Model(&models.QRCode{}).Where("id = ?", use.QRCodeID).Updates(map[string]interface{}{
+			"usage_count":  gorm.Expr("usage_count + 1"),
+			"total_amount": gorm.Expr("total_amount + ?", use.Amount),
+			"last_used_at": use.CompletedAt,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record QR code use: %w", err)
+	}
+	return nil
+}
 
-		if err := DB.Create(&qr).Error; err != nil {
-			return nil, err
-		}
-		return &qr, nil
-	} else if err != nil {
-		return nil, err
+func (r *qrCodeRepository) Usage(ctx context.Context, qrID uint, since time.Time) (*QRUsage, error) {
+	var usage QRUsage
+	query := r.db.WithContext(ctx).Model(&models.QRTransaction{}).
+		Select("COUNT(*) AS scans, COALESCE(SUM(amount), 0) AS volume, COUNT(DISTINCT customer_id) AS unique_payers").
+		Where("qr_code_id = ? AND status = ?", qrID, models.QRTransactionCompleted)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	if err := query.Scan(&usage).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum QR code usage: %w", err)
 	}
+	return &usage, nil
+}
 
-	return &qr, nil
+func (r *qrCodeRepository) ExpireStale(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&models.QRCode{}).
+		Where("type = ? AND status = ?", models.QRTypeDynamic, models.QRStatusActive).
+		Where("(expires_at < ? OR (max_uses > 0 AND usage_count >= max_uses))", now).
+		Update("status", models.QRStatusExpired)
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to expire QR codes: %w", res.Error)
+	}
+	return res.RowsAffected, nil
 }
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRateLimitOverrideNotFound = errors.New("rate limit override not found")
+
+// RateLimitOverrideRepository persists admin overrides of the rate limits
+type RateLimitOverrideRepository interface {
+	// ListBySubject returns the overrides of one user or merchant
+	ListBySubject(subjectType string, subjectID uint) ([]models.RateLimitOverride, error)
+	List(limit, offset int) ([]models.RateLimitOverride, int64, error)
+	GetByID(id uint) (*models.RateLimitOverride, error)
+	// Save creates the override or replaces the subject's existing one for the class
+	Save(override *models.RateLimitOverride) error
+	Delete(id uint) error
+}
+
+type rateLimitOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewRateLimitOverrideRepository(db *gorm.DB) RateLimitOverrideRepository {
+	return &rateLimitOverrideRepository{db: db}
+}
+
+func (r *rateLimitOverrideRepository) ListBySubject(subjectType string, subjectID uint) ([]models.RateLimitOverride, error) {
+	var overrides []models.RateLimitOverride
+	err := r.db.Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).Find(&overrides).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate limit overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (r *rateLimitOverrideRepository) List(limit, offset int) ([]models.RateLimitOverride, int64, error) {
+	var overrides []models.RateLimitOverride
+	var total int64
+	if err := r.db.Model(&models.RateLimitOverride{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count rate limit overrides: %w", err)
+	}
+	err := r.db.Order("subject_type, subject_id, class").Limit(limit).Offset(offset).Find(&overrides).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list rate limit overrides: %w", err)
+	}
+	return overrides, total, nil
+}
+
+func (r *rateLimitOverrideRepository) GetByID(id uint) (*models.RateLimitOverride, error) {
+	var override models.RateLimitOverride
+	if err := r.db.First(&override, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRateLimitOverrideNotFound
+		}
+		return nil, fmt.Errorf("failed to get rate limit override: %w", err)
+	}
+	return &override, nil
+}
+
+func (r *rateLimitOverrideRepository) Save(override *models.RateLimitOverride) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subject_type"}, {Name: "subject_id"}, {Name: "class"}},
+		DoUpdates: clause.AssignmentColumns([]string{"limit", "reason", "updated_by", "updated_at"}),
+	}).Create(override).Error
+	if err != nil {
+		return fmt.Errorf("failed to save rate limit override: %w", err)
+	}
+	return nil
+}
+
+func (r *rateLimitOverrideRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.RateLimitOverride{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete rate limit override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRateLimitOverrideNotFound
+	}
+	return nil
+}
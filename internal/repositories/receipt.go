@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// ReceiptRepository persists transaction receipts and their items
+type ReceiptRepository interface {
+	Create(receipt *models.Receipt) error
+	GetByTransactionID(transactionID uint) (*models.Receipt, error)
+	Update(receipt *models.Receipt) error
+}
+
+type receiptRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptRepository(db *gorm.DB) ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+func (r *receiptRepository) Create(receipt *models.Receipt) error {
+	if err := r.db.Create(receipt).Error; err != nil {
+		return fmt.Errorf("failed to create receipt: %w", err)
+	}
+	return nil
+}
+
+func (r *receiptRepository) GetByTransactionID(transactionID uint) (*models.Receipt, error) {
+	var receipt models.Receipt
+	err := r.db.Preload("Items").Where("transaction_id = ?", transactionID).First(&receipt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReceiptNotFound
+		}
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	return &receipt, nil
+}
+
+func (r *receiptRepository) Update(receipt *models.Receipt) error {
+	if err := r.db.Omit("Items").Save(receipt).Error; err != nil {
+		return fmt.Errorf("failed to update receipt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRefundRequestNotFound = errors.New("refund request not found")
+	// ErrRefundRequestNotPending is returned when a request was decided or
+	// withdrawn first
+	ErrRefundRequestNotPending = errors.New("refund request is no longer pending")
+)
+
+// RefundRequestRepository persists merchants' refund policies and the
+// refunds customers ask them for
+type RefundRequestRepository interface {
+	// GetPolicy returns the merchant's policy, the default if they never
+	// set one
+	GetPolicy(merchantUserID uint) (*models.RefundPolicy, error)
+	SavePolicy(policy *models.RefundPolicy) error
+
+	Create(req *models.RefundRequest) error
+	Get(id uint) (*models.RefundRequest, error)
+	// HasOpen reports whether a refund of the charge is already pending or
+	// being paid
+	HasOpen(transactionID uint) (bool, error)
+	// ListByCustomer pages through the customer's requests, newest first
+	ListByCustomer(customerID uint, limit, offset int) ([]models.RefundRequest, int64, error)
+	// ListByMerchant pages through the requests made of the merchant,
+	// newest first, optionally only those with status
+	ListByMerchant(merchantUserID uint, status string, limit, offset int) ([]models.RefundRequest, int64, error)
+	// Decide ends a pending request with status, recording who decided it
+	// and why; decidedBy is nil when the policy approved it
+	Decide(id uint, status string, decidedBy *uint, note string, at time.Time) error
+	// Reopen returns an approved request whose refund couldn't be paid to
+	// pending
+	Reopen(id uint) error
+	// Complete links an approved request to the refund that paid it
+	Complete(id, refundTransactionID uint, at time.Time) error
+}
+
+type refundRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRequestRepository(db *gorm.DB) RefundRequestRepository {
+	return &refundRequestRepository{db: db}
+}
+
+func (r *refundRequestRepository) GetPolicy(merchantUserID uint) (*models.RefundPolicy, error) {
+	var policy models.RefundPolicy
+	err := r.db.Where("merchant_user_id = ?", merchantUserID).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultRefundPolicy(merchantUserID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refund policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (r *refundRequestRepository) SavePolicy(policy *models.RefundPolicy) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "merchant_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "window_days", "auto_approve_limit", "updated_at"}),
+	}).Create(policy).Error
+	if err != nil {
+		return fmt.Errorf("failed to save refund policy: %w", err)
+	}
+	return nil
+}
+
+func (r *refundRequestRepository) Create(req *models.RefundRequest) error {
+	if err := r.db.Create(req).Error; err != nil {
+		return fmt.Errorf("failed to create refund request: %w", err)
+	}
+	return nil
+}
+
+func (r *refundRequestRepository) Get(id uint) (*models.RefundRequest, error) {
+	var req models.RefundRequest
+	if err := r.db.First(&req, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefundRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get refund request: %w", err)
+	}
+	return &req, nil
+}
+
+func (r *refundRequestRepository) HasOpen(transactionID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RefundRequest{}).
+		Where("transaction_id = ? AND status IN ?", transactionID, []string{models.RefundRequestPending, models.RefundRequestApproved}).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check open refund requests: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *refundRequestRepository) ListByCustomer(customerID uint, limit, offset int) ([]models.RefundRequest, int64, error) {
+	return r.list(r.db.Where("customer_id = ?", customerID), limit, offset)
+}
+
+func (r *refundRequestRepository) ListByMerchant(merchantUserID uint, status string, limit, offset int) ([]models.RefundRequest, int64, error) {
+	query := r.db.Where("merchant_user_id = ?", merchantUserID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return r.list(query, limit, offset)
+}
+
+func (r *refundRequestRepository) list(query *gorm.DB, limit, offset int) ([]models.RefundRequest, int64, error) {
+	var reqs []models.RefundRequest
+	var total int64
+	query = query.Model(&models.RefundRequest{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count refund requests: %w", err)
+	}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&reqs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list refund requests: %w", err)
+	}
+	return reqs, total, nil
+}
+
+func (r *refundRequestRepository) Decide(id uint, status string, decidedBy *uint, note string, at time.Time) error {
+	result := r.db.Model(&models.RefundRequest{}).
+		Where("id = ? AND status = ?", id, models.RefundRequestPending).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"auto_approved": status == models.RefundRequestApproved && decidedBy == nil,
+			"decided_by":    decidedBy,
+			"decision_note": note,
+			"decided_at":    at,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to decide refund request: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRefundRequestNotPending
+	}
+	return nil
+}
+
+func (r *refundRequestRepository) Reopen(id uint) error {
+	err := r.db.Model(&models.RefundRequest{}).
+		Where("id = ? AND status = ?", id, models.RefundRequestApproved).
+		Updates(map[string]interface{}{
+			"status":        models.RefundRequestPending,
+			"auto_approved": false,
+			"decided_by":    nil,
+			"decision_note": "",
+			"decided_at":    nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reopen refund request: %w", err)
+	}
+	return nil
+}
+
+func (r *refundRequestRepository) Complete(id, refundTransactionID uint, at time.Time) error {
+	err := r.db.Model(&models.RefundRequest{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":                models.RefundRequestRefunded,
+		"refund_transaction_id": refundTransactionID,
+		"refunded_at":           at,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete refund request: %w", err)
+	}
+	return nil
+}
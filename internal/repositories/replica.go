@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"orus/internal/config"
+	"orus/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	replicaPluginName = "orus:read_replica"
+	// replicaSetting marks a statement as allowed on the replica
+	replicaSetting = "orus:replica"
+	// primarySetting pins a statement to the primary whatever else says
+	primarySetting = "orus:primary"
+
+	// lagCheckInterval bounds how often the replica's lag is measured
+	lagCheckInterval = time.Second
+)
+
+// ReadReplica routes reads that opt in with Replica to a read replica.
+// Everything else, writes and reads inside transactions included, stays on
+// the primary. A read also stays on the primary while the replica lags
+// more than the configured maximum, or when its user wrote a transaction
+// recently enough that the replica may not have it yet.
+type ReadReplica struct {
+	replica *sql.DB
+	maxLag  time.Duration
+
+	lag       atomic.Int64 // Last measured lag in nanoseconds, -1 if unknown
+	checkedAt atomic.Int64 // When lag was last measured, in Unix nanoseconds
+	checking  atomic.Bool
+
+	mu     sync.Mutex
+	writes map[uint]time.Time // User IDs with recent transactions
+}
+
+// ConnectReplica opens the configured read replica and routes reads that
+// opt in to it. Without a replica DSN it does nothing and every query
+// keeps using the primary.
+func ConnectReplica(cfg config.DatabaseConfig, primary *gorm.DB) error {
+	if cfg.ReplicaDSN == "" {
+		return nil
+	}
+
+	replica, err := gorm.Open(postgres.Open(cfg.ReplicaDSN), &gorm.Config{Logger: primary.Logger})
+	if err != nil {
+		return fmt.Errorf("failed to connect to read replica: %w", err)
+	}
+	sqlDB, err := replica.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get read replica instance: %w", err)
+	}
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	plugin := &ReadReplica{
+		replica: sqlDB,
+		maxLag:  cfg.ReplicaMaxLag,
+		writes:  make(map[uint]time.Time),
+	}
+	plugin.lag.Store(-1)
+	if err := primary.Use(plugin); err != nil {
+		return fmt.Errorf("failed to register read replica: %w", err)
+	}
+
+	log.Println("✅ PostgreSQL read replica connected")
+	return nil
+}
+
+func (p *ReadReplica) Name() string {
+	return replicaPluginName
+}
+
+func (p *ReadReplica) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register(replicaPluginName, p.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register(replicaPluginName, p.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register(replicaPluginName, p.recordWrite); err != nil {
+		return err
+	}
+	return db.Callback().Update().After("gorm:update").Register(replicaPluginName, p.recordWrite)
+}
+
+// Replica lets a read run on the replica. userID names whose data it is,
+// so a user who just paid sees the payment; pass 0 for reads that aren't
+// about one user, such as admin listings.
+func Replica(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Set(replicaSetting, userID)
+	}
+}
+
+// Primary pins a query to the primary, overriding Replica
+func Primary(db *gorm.DB) *gorm.DB {
+	return db.Set(primarySetting, true)
+}
+
+// ReplicaLag returns the replica's last measured lag, or false without a
+// replica or before the first measurement
+func ReplicaLag(db *gorm.DB) (time.Duration, bool) {
+	plugin, ok := db.Config.Plugins[replicaPluginName].(*ReadReplica)
+	if !ok {
+		return 0, false
+	}
+	lag := plugin.lag.Load()
+	return time.Duration(lag), lag >= 0
+}
+
+func (p *ReadReplica) route(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if _, pinned := db.Get(primarySetting); pinned {
+		return
+	}
+	value, ok := db.Get(replicaSetting)
+	if !ok {
+		return
+	}
+	// Reads inside a transaction must see its writes
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	if !p.fresh() || p.wroteRecently(value.(uint)) {
+		return
+	}
+	db.Statement.ConnPool = p.replica
+}
+
+// fresh reports whether the replica is known to lag less than the maximum.
+// A stale measurement is refreshed in the background, so no query waits
+// on it.
+func (p *ReadReplica) fresh() bool {
+	if time.Since(time.Unix(0, p.checkedAt.Load())) > lagCheckInterval && p.checking.CompareAndSwap(false, true) {
+		go p.measureLag()
+	}
+	lag := p.lag.Load()
+	return lag >= 0 && time.Duration(lag) <= p.maxLag
+}
+
+func (p *ReadReplica) measureLag() {
+	defer p.checking.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.maxLag)
+	defer cancel()
+
+	// An idle primary sends nothing to replay, so a replica that has
+	// replayed everything it received counts as caught up
+	var seconds float64
+	err := p.replica.QueryRowContext(ctx, `SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`).Scan(&seconds)
+	if err != nil {
+		log.Printf("Failed to measure read replica lag: %v", err)
+		p.lag.Store(-1)
+	} else {
+		p.lag.Store(int64(seconds * float64(time.Second)))
+	}
+	p.checkedAt.Store(time.Now().UnixNano())
+}
+
+// recordWrite notes the users of transactions written on the primary
+func (p *ReadReplica) recordWrite(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	var userIDs []uint
+	switch dest := db.Statement.Dest.(type) {
+	case *models.Transaction:
+		userIDs = append(userIDs, dest.SenderID, dest.ReceiverID)
+	case []models.Transaction:
+		for _, tx := range dest {
+			userIDs = append(userIDs, tx.SenderID, tx.ReceiverID)
+		}
+	case []*models.Transaction:
+		for _, tx := range dest {
+			userIDs = append(userIDs, tx.SenderID, tx.ReceiverID)
+		}
+	default:
+		return
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, userID := range userIDs {
+		if userID != 0 {
+			p.writes[userID] = now
+		}
+	}
+	// Forget writes the replica has certainly caught up with
+	if len(p.writes) > 10000 {
+		for userID, at := range p.writes {
+			if now.Sub(at) > p.maxLag {
+				delete(p.writes, userID)
+			}
+		}
+	}
+}
+
+// wroteRecently reports whether the user's latest transaction may not
+// have reached the replica yet
+func (p *ReadReplica) wroteRecently(userID uint) bool {
+	if userID == 0 {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	at, ok := p.writes[userID]
+	return ok && time.Since(at) <= p.maxLag
+}
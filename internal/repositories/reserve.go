@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReserveRelease is how much of a merchant's reserve is released on a day
+type ReserveRelease struct {
+	Day    time.Time `json:"day"`
+	Amount float64   `json:"amount"`
+	Count  int64     `json:"count"`
+}
+
+// ReserveRepository stores merchants' rolling reserves and the funds
+// withheld in them
+type ReserveRepository interface {
+	// GetReserve returns the merchant's own reserve, or nil if they use
+	// the default
+	GetReserve(merchantUserID uint) (*models.MerchantReserve, error)
+	// GetReservesFor returns the reserves of those of the merchants with
+	// their own, keyed by merchant
+	GetReservesFor(merchantUserIDs []uint) (map[uint]models.MerchantReserve, error)
+	SaveReserve(reserve *models.MerchantReserve) error
+	// DeleteReserve puts the merchant back on the default reserve
+	DeleteReserve(merchantUserID uint) error
+
+	// Withhold records the entry and holds its amount on the merchant's
+	// wallet until it is released. It returns false if the transaction
+	// was already withheld from.
+	Withhold(entry *models.ReserveEntry) (bool, error)
+	// ReleaseDue releases up to limit entries due by now, along with
+	// their holds, returning how many it released
+	ReleaseDue(now time.Time, limit int) (int64, error)
+
+	// GetHeld returns the total the merchant has held in reserve
+	GetHeld(merchantUserID uint) (float64, error)
+	// GetSchedule returns what is still held by the day it is released
+	GetSchedule(merchantUserID uint) ([]ReserveRelease, error)
+	// ListEntries pages through the merchant's entries, latest first
+	ListEntries(merchantUserID uint, limit, offset int) ([]models.ReserveEntry, int64, error)
+}
+
+type reserveRepository struct {
+	db *gorm.DB
+}
+
+func NewReserveRepository(db *gorm.DB) ReserveRepository {
+	return &reserveRepository{db: db}
+}
+
+func (r *reserveRepository) GetReserve(merchantUserID uint) (*models.MerchantReserve, error) {
+	var reserve models.MerchantReserve
+	if err := r.db.Where("merchant_user_id = ?", merchantUserID).First(&reserve).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get merchant reserve: %w", err)
+	}
+	return &reserve, nil
+}
+
+func (r *reserveRepository) GetReservesFor(merchantUserIDs []uint) (map[uint]models.MerchantReserve, error) {
+	reserves := make(map[uint]models.MerchantReserve)
+	if len(merchantUserIDs) == 0 {
+		return reserves, nil
+	}
+	var rows []models.MerchantReserve
+	if err := r.db.Where("merchant_user_id IN ?", merchantUserIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get merchant reserves: %w", err)
+	}
+	for _, row := range rows {
+		reserves[row.MerchantUserID] = row
+	}
+	return reserves, nil
+}
+
+func (r *reserveRepository) SaveReserve(reserve *models.MerchantReserve) error {
+	if err := r.db.Save(reserve).Error; err != nil {
+		return fmt.Errorf("failed to save merchant reserve: %w", err)
+	}
+	return nil
+}
+
+func (r *reserveRepository) DeleteReserve(merchantUserID uint) error {
+	if err := r.db.Where("merchant_user_id = ?", merchantUserID).Delete(&models.MerchantReserve{}).Error; err != nil {
+		return fmt.Errorf("failed to delete merchant reserve: %w", err)
+	}
+	return nil
+}
+
+func (r *reserveRepository) Withhold(entry *models.ReserveEntry) (bool, error) {
+	withheld := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var wallet models.Wallet
+		if err := tx.Where("user_id = ?", entry.MerchantUserID).First(&wallet).Error; err != nil {
+			return fmt.Errorf("failed to get merchant wallet: %w", err)
+		}
+
+		entry.Status = models.ReserveEntryHeld
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record reserve entry: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// Like a chargeback reserve, the hold may exceed what the merchant
+		// still has available if they spent the payment in the meantime
+		releaseAt := entry.ReleaseAt
+		hold := &models.WalletHold{
+			WalletID:  wallet.ID,
+			UserID:    entry.MerchantUserID,
+			Amount:    entry.Amount,
+			Type:      models.HoldTypeRollingReserve,
+			Status:    models.HoldStatusActive,
+			Reason:    fmt.Sprintf("Rolling reserve on transaction %d", entry.TransactionID),
+			Reference: fmt.Sprintf("reserve:%d", entry.ID),
+			ExpiresAt: &releaseAt,
+		}
+		if err := tx.Create(hold).Error; err != nil {
+			return fmt.Errorf("failed to hold reserve: %w", err)
+		}
+		entry.HoldID = hold.ID
+		if err := tx.Model(entry).Update("hold_id", hold.ID).Error; err != nil {
+			return fmt.Errorf("failed to link reserve hold: %w", err)
+		}
+		withheld = true
+		return nil
+	})
+	return withheld, err
+}
+
+func (r *reserveRepository) ReleaseDue(now time.Time, limit int) (int64, error) {
+	var released int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var entries []models.ReserveEntry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND release_at <= ?", models.ReserveEntryHeld, now).
+			Order("release_at ASC").
+			Limit(limit).
+			Find(&entries).Error
+		if err != nil {
+			return fmt.Errorf("failed to find reserve entries due: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(entries))
+		holdIDs := make([]uint, len(entries))
+		for i, entry := range entries {
+			ids[i], holdIDs[i] = entry.ID, entry.HoldID
+		}
+
+		err = tx.Model(&models.WalletHold{}).
+			Where("id IN ? AND status = ?", holdIDs, models.HoldStatusActive).
+			Updates(map[string]interface{}{"status": models.HoldStatusReleased, "released_at": now}).Error
+		if err != nil {
+			return fmt.Errorf("failed to release reserve holds: %w", err)
+		}
+		result := tx.Model(&models.ReserveEntry{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": models.ReserveEntryReleased, "released_at": now})
+		if result.Error != nil {
+			return fmt.Errorf("failed to release reserve entries: %w", result.Error)
+		}
+		released = result.RowsAffected
+		return nil
+	})
+	return released, err
+}
+
+func (r *reserveRepository) held(merchantUserID uint) *gorm.DB {
+	return r.db.Model(&models.ReserveEntry{}).
+		Where("merchant_user_id = ? AND status = ?", merchantUserID, models.ReserveEntryHeld)
+}
+
+func (r *reserveRepository) GetHeld(merchantUserID uint) (float64, error) {
+	var held float64
+	if err := r.held(merchantUserID).Select("COALESCE(SUM(amount), 0)").Row().Scan(&held); err != nil {
+		return 0, fmt.Errorf("failed to get held reserve: %w", err)
+	}
+	return held, nil
+}
+
+func (r *reserveRepository) GetSchedule(merchantUserID uint) ([]ReserveRelease, error) {
+	var releases []ReserveRelease
+	err := r.held(merchantUserID).
+		Select("date_trunc('day', release_at) AS day, SUM(amount) AS amount, COUNT(*) AS count").
+		Group("day").
+		Order("day ASC").
+		Scan(&releases).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserve release schedule: %w", err)
+	}
+	return releases, nil
+}
+
+func (r *reserveRepository) ListEntries(merchantUserID uint, limit, offset int) ([]models.ReserveEntry, int64, error) {
+	query := r.db.Model(&models.ReserveEntry{}).Where("merchant_user_id = ?", merchantUserID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count reserve entries: %w", err)
+	}
+	var entries []models.ReserveEntry
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list reserve entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrUnknownPermission = errors.New("unknown permission")
+)
+
+// RoleRepository persists roles and the permissions they grant
+type RoleRepository interface {
+	// EnsureDefaults creates the known permissions and built-in roles, and
+	// resets the built-in roles' permissions to their definitions
+	EnsureDefaults() error
+
+	List() ([]models.Role, error)
+	GetByName(name string) (*models.Role, error)
+	// Create saves a role granting the named permissions
+	Create(role *models.Role, permissions []string) error
+	// Update saves the role's description and replaces its permissions
+	Update(role *models.Role, permissions []string) error
+	Delete(id uint) error
+
+	ListPermissions() ([]models.Permission, error)
+	// CountUsers counts the users holding a role
+	CountUsers(name string) (int64, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) EnsureDefaults() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, def := range models.PermissionDefinitions {
+			permission := def
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"description"}),
+			}).Create(&permission).Error
+			if err != nil {
+				return fmt.Errorf("failed to create permission %s: %w", def.Name, err)
+			}
+		}
+
+		for _, name := range models.SystemRoles {
+			role := models.Role{Name: name, System: true}
+			err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+				Create(&role).Error
+			if err != nil {
+				return fmt.Errorf("failed to create role %s: %w", name, err)
+			}
+			if err := tx.Where("name = ?", name).First(&role).Error; err != nil {
+				return fmt.Errorf("failed to get role %s: %w", name, err)
+			}
+			if err := replacePermissions(tx, &role, models.GetDefaultPermissions(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *roleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Preload("Permissions").Order("system DESC, name").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(role *models.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Create(role).Error; err != nil {
+			return fmt.Errorf("failed to create role: %w", err)
+		}
+		return replacePermissions(tx, role, permissions)
+	})
+}
+
+func (r *roleRepository) Update(role *models.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+		return replacePermissions(tx, role, permissions)
+	})
+}
+
+func (r *roleRepository) Delete(id uint) error {
+	result := r.db.Select(clause.Associations).Delete(&models.Role{ID: id})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+func (r *roleRepository) ListPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.Order("name").Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+func (r *roleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.User{}).Where("role = ?", name).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count role users: %w", err)
+	}
+	return count, nil
+}
+
+// replacePermissions sets the role's permissions to the named ones
+func replacePermissions(tx *gorm.DB, role *models.Role, names []string) error {
+	var permissions []models.Permission
+	if len(names) > 0 {
+		if err := tx.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+			return fmt.Errorf("failed to get permissions: %w", err)
+		}
+		if len(permissions) != len(names) {
+			return ErrUnknownPermission
+		}
+	}
+	if err := tx.Model(role).Association("Permissions").Replace(permissions); err != nil {
+		return fmt.Errorf("failed to set role permissions: %w", err)
+	}
+	role.Permissions = permissions
+	return nil
+}
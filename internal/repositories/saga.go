@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSagaNotFound = errors.New("saga not found")
+
+// SagaRepository stores sagas and the outcome of their steps
+type SagaRepository interface {
+	// Create saves the saga with its steps pending
+	Create(saga *models.Saga) error
+	GetByID(id uint) (*models.Saga, error)
+	// List pages through the sagas, newest first, optionally only those in
+	// one status
+	List(status string, limit, offset int) ([]models.Saga, int64, error)
+	// SaveStep records a step's status, error and timestamps
+	SaveStep(step *models.SagaStep) error
+	// Save records the saga's status, error and attempts and releases it
+	Save(saga *models.Saga) error
+
+	// Claim takes a compensating saga that is due, or a running one not
+	// touched since staleBefore whose instance is presumed gone, for this
+	// instance until leaseUntil. It returns nil when there is none.
+	Claim(now, staleBefore, leaseUntil time.Time) (*models.Saga, error)
+
+	// SetTransactionStatus records how the saga's transaction ended
+	SetTransactionStatus(transactionID uint, status string) error
+}
+
+type sagaRepository struct {
+	db *gorm.DB
+}
+
+func NewSagaRepository(db *gorm.DB) SagaRepository {
+	return &sagaRepository{db: db}
+}
+
+func (r *sagaRepository) Create(saga *models.Saga) error {
+	if err := r.db.Create(saga).Error; err != nil {
+		return fmt.Errorf("failed to create saga: %w", err)
+	}
+	return nil
+}
+
+func (r *sagaRepository) GetByID(id uint) (*models.Saga, error) {
+	var saga models.Saga
+	err := r.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).First(&saga, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSagaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+	return &saga, nil
+}
+
+func (r *sagaRepository) List(status string, limit, offset int) ([]models.Saga, int64, error) {
+	query := r.db.Model(&models.Saga{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count sagas: %w", err)
+	}
+	var sagas []models.Saga
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&sagas).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list sagas: %w", err)
+	}
+	return sagas, total, nil
+}
+
+func (r *sagaRepository) SaveStep(step *models.SagaStep) error {
+	err := r.db.Model(&models.SagaStep{}).Where("id = ?", step.ID).Updates(map[string]interface{}{
+		"status":         step.Status,
+		"error":          step.Error,
+		"completed_at":   step.CompletedAt,
+		"compensated_at": step.CompensatedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save saga step: %w", err)
+	}
+	return nil
+}
+
+func (r *sagaRepository) Save(saga *models.Saga) error {
+	saga.LeaseUntil = nil
+	err := r.db.Model(&models.Saga{}).Where("id = ?", saga.ID).Updates(map[string]interface{}{
+		"status":          saga.Status,
+		"error":           saga.Error,
+		"attempts":        saga.Attempts,
+		"next_attempt_at": saga.NextAttemptAt,
+		"lease_until":     nil,
+		"updated_at":      time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save saga: %w", err)
+	}
+	return nil
+}
+
+func (r *sagaRepository) Claim(now, staleBefore, leaseUntil time.Time) (*models.Saga, error) {
+	var sagas []models.Saga
+	err := r.db.Raw(`UPDATE sagas
+		SET lease_until = @lease, updated_at = @now
+		WHERE id = (
+			SELECT id FROM sagas
+			WHERE ((status = @compensating AND next_attempt_at <= @now)
+					OR (status = @running AND updated_at < @stale))
+				AND (lease_until IS NULL OR lease_until < @now)
+			ORDER BY id LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		map[string]interface{}{
+			"compensating": models.SagaCompensating,
+			"running":      models.SagaRunning,
+			"stale":        staleBefore,
+			"lease":        leaseUntil,
+			"now":          now,
+		}).Scan(&sagas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim saga: %w", err)
+	}
+	if len(sagas) == 0 {
+		return nil, nil
+	}
+
+	saga := &sagas[0]
+	if err := r.db.Where("saga_id = ?", saga.ID).Order("position").Find(&saga.Steps).Error; err != nil {
+		return nil, fmt.Errorf("failed to load saga steps: %w", err)
+	}
+	return saga, nil
+}
+
+func (r *sagaRepository) SetTransactionStatus(transactionID uint, status string) error {
+	err := r.db.Model(&models.Transaction{}).Where("id = ?", transactionID).Update("status", status).Error
+	if err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSandboxCustomerNotFound  = errors.New("sandbox customer not found")
+	ErrSandboxChargeNotFound    = errors.New("sandbox charge not found")
+	ErrSandboxInsufficientFunds = errors.New("insufficient funds in sandbox customer balance")
+	ErrSandboxRefundExceeds     = errors.New("refund exceeds the amount left on the charge")
+	ErrSandboxChargeNotSettled  = errors.New("sandbox charge is not pending")
+)
+
+// SandboxBalance sums a merchant's sandbox charges
+type SandboxBalance struct {
+	Available float64 `json:"available"` // Succeeded charges less refunds
+	Pending   float64 `json:"pending"`   // Charges waiting on the bank
+	Refunded  float64 `json:"refunded"`
+	Charges   int64   `json:"charges"`
+}
+
+// SandboxRepository persists a merchant's test customers, charges and
+// refunds. Nothing here touches real wallets or transactions.
+type SandboxRepository interface {
+	CreateCustomer(customer *models.SandboxCustomer) error
+	GetCustomer(merchantID, customerID uint) (*models.SandboxCustomer, error)
+	ListCustomers(merchantID uint, limit, offset int) ([]models.SandboxCustomer, int64, error)
+	// FundCustomer adds simulated money to a customer's balance
+	FundCustomer(merchantID, customerID uint, amount float64) (*models.SandboxCustomer, error)
+
+	// CreateCharge saves a charge; a succeeded wallet charge also debits
+	// the customer's balance
+	CreateCharge(charge *models.SandboxCharge) error
+	GetCharge(merchantID uint, reference string) (*models.SandboxCharge, error)
+	ListCharges(merchantID uint, status string, limit, offset int) ([]models.SandboxCharge, int64, error)
+	// SettleCharge resolves a pending charge as succeeded or failed
+	SettleCharge(charge *models.SandboxCharge, status, failureCode, failureMessage string) error
+
+	// Refund saves a refund and, when it succeeds, credits wallet charges
+	// back to the customer and updates the charge's refunded amount
+	Refund(charge *models.SandboxCharge, refund *models.SandboxRefund) error
+	ListRefunds(merchantID uint, chargeID uint) ([]models.SandboxRefund, error)
+
+	GetBalance(merchantID uint) (*SandboxBalance, error)
+	// Reset deletes all of the merchant's sandbox data
+	Reset(merchantID uint) error
+}
+
+type sandboxRepository struct {
+	db *gorm.DB
+}
+
+func NewSandboxRepository(db *gorm.DB) SandboxRepository {
+	return &sandboxRepository{db: db}
+}
+
+func (r *sandboxRepository) CreateCustomer(customer *models.SandboxCustomer) error {
+	if err := r.db.Create(customer).Error; err != nil {
+		return fmt.Errorf("failed to create sandbox customer: %w", err)
+	}
+	return nil
+}
+
+func (r *sandboxRepository) GetCustomer(merchantID, customerID uint) (*models.SandboxCustomer, error) {
+	var customer models.SandboxCustomer
+	if err := r.db.Where("id = ? AND merchant_id = ?", customerID, merchantID).First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSandboxCustomerNotFound
+		}
+		return nil, fmt.Errorf("failed to get sandbox customer: %w", err)
+	}
+	return &customer, nil
+}
+
+func (r *sandboxRepository) ListCustomers(merchantID uint, limit, offset int) ([]models.SandboxCustomer, int64, error) {
+	var customers []models.SandboxCustomer
+	var total int64
+
+	query := r.db.Model(&models.SandboxCustomer{}).Where("merchant_id = ?", merchantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count sandbox customers: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&customers).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get sandbox customers: %w", err)
+	}
+	return customers, total, nil
+}
+
+func (r *sandboxRepository) FundCustomer(merchantID, customerID uint, amount float64) (*models.SandboxCustomer, error) {
+	var customer *models.SandboxCustomer
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		customer, err = adjustSandboxBalance(tx, merchantID, customerID, amount)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (r *sandboxRepository) CreateCharge(charge *models.SandboxCharge) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if charge.Source == models.SandboxSourceWallet && charge.Status == models.SandboxStatusSucceeded {
+			if _, err := adjustSandboxBalance(tx, charge.MerchantID, *charge.CustomerID, -charge.Amount); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(charge).Error; err != nil {
+			return fmt.Errorf("failed to create sandbox charge: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sandboxRepository) GetCharge(merchantID uint, reference string) (*models.SandboxCharge, error) {
+	var charge models.SandboxCharge
+	if err := r.db.Where("reference = ? AND merchant_id = ?", reference, merchantID).First(&charge).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSandboxChargeNotFound
+		}
+		return nil, fmt.Errorf("failed to get sandbox charge: %w", err)
+	}
+	return &charge, nil
+}
+
+func (r *sandboxRepository) ListCharges(merchantID uint, status string, limit, offset int) ([]models.SandboxCharge, int64, error) {
+	var charges []models.SandboxCharge
+	var total int64
+
+	query := r.db.Model(&models.SandboxCharge{}).Where("merchant_id = ?", merchantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count sandbox charges: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&charges).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get sandbox charges: %w", err)
+	}
+	return charges, total, nil
+}
+
+func (r *sandboxRepository) SettleCharge(charge *models.SandboxCharge, status, failureCode, failureMessage string) error {
+	result := r.db.Model(&models.SandboxCharge{}).
+		Where("id = ? AND status = ?", charge.ID, models.SandboxStatusPending).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"failure_code":    failureCode,
+			"failure_message": failureMessage,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to settle sandbox charge: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSandboxChargeNotSettled
+	}
+	charge.Status = status
+	charge.FailureCode = failureCode
+	charge.FailureMessage = failureMessage
+	return nil
+}
+
+func (r *sandboxRepository) Refund(charge *models.SandboxCharge, refund *models.SandboxRefund) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.SandboxCharge
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, charge.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock sandbox charge: %w", err)
+		}
+		if refund.Status == models.SandboxStatusSucceeded {
+			remaining := math.Round((locked.Amount-locked.RefundedAmount)*100) / 100
+			if refund.Amount > remaining {
+				return ErrSandboxRefundExceeds
+			}
+			if locked.Source == models.SandboxSourceWallet && locked.CustomerID != nil {
+				if _, err := adjustSandboxBalance(tx, locked.MerchantID, *locked.CustomerID, refund.Amount); err != nil {
+					return err
+				}
+			}
+
+			locked.RefundedAmount = math.Round((locked.RefundedAmount+refund.Amount)*100) / 100
+			locked.Status = models.SandboxStatusPartiallyRefunded
+			if locked.RefundedAmount >= locked.Amount {
+				locked.Status = models.SandboxStatusRefunded
+			}
+			err := tx.Model(&locked).Updates(map[string]interface{}{
+				"refunded_amount": locked.RefundedAmount,
+				"status":          locked.Status,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update sandbox charge: %w", err)
+			}
+		}
+
+		refund.ChargeID = locked.ID
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to create sandbox refund: %w", err)
+		}
+		*charge = locked
+		return nil
+	})
+}
+
+func (r *sandboxRepository) ListRefunds(merchantID uint, chargeID uint) ([]models.SandboxRefund, error) {
+	var refunds []models.SandboxRefund
+	if err := r.db.Where("merchant_id = ? AND charge_id = ?", merchantID, chargeID).Order("id").Find(&refunds).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sandbox refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+func (r *sandboxRepository) GetBalance(merchantID uint) (*SandboxBalance, error) {
+	var balance SandboxBalance
+	err := r.db.Model(&models.SandboxCharge{}).
+		Select(`COALESCE(SUM(CASE WHEN status IN ? THEN amount - refunded_amount ELSE 0 END), 0) AS available,
+			COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0) AS pending,
+			COALESCE(SUM(refunded_amount), 0) AS refunded,
+			COUNT(*) AS charges`,
+			[]string{models.SandboxStatusSucceeded, models.SandboxStatusPartiallyRefunded, models.SandboxStatusRefunded},
+			models.SandboxStatusPending).
+		Where("merchant_id = ?", merchantID).
+		Scan(&balance).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sandbox balance: %w", err)
+	}
+	return &balance, nil
+}
+
+func (r *sandboxRepository) Reset(merchantID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.SandboxRefund{}, &models.SandboxCharge{}, &models.SandboxCustomer{}} {
+			if err := tx.Where("merchant_id = ?", merchantID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to reset sandbox data: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// adjustSandboxBalance adds delta to the customer's simulated balance with
+// the row locked, refusing a debit past zero with
+// ErrSandboxInsufficientFunds. Sandbox balances only move through here.
+func adjustSandboxBalance(tx *gorm.DB, merchantID, customerID uint, delta float64) (*models.SandboxCustomer, error) {
+	customer, err := lockSandboxCustomer(tx, merchantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	balance := math.Round((customer.Balance+delta)*100) / 100
+	if delta < 0 && balance < 0 {
+		return nil, ErrSandboxInsufficientFunds
+	}
+	if err := tx.Model(customer).Update("balance", balance).Error; err != nil {
+		return nil, fmt.Errorf("failed to update sandbox customer balance: %w", err)
+	}
+	customer.Balance = balance
+	return customer, nil
+}
+
+func lockSandboxCustomer(tx *gorm.DB, merchantID, customerID uint) (*models.SandboxCustomer, error) {
+	var customer models.SandboxCustomer
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND merchant_id = ?", customerID, merchantID).
+		First(&customer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSandboxCustomerNotFound
+		}
+		return nil, fmt.Errorf("failed to lock sandbox customer: %w", err)
+	}
+	return &customer, nil
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrScanConfirmationNotFound = errors.New("scan confirmation not found")
+	// ErrScanConfirmationNotPending is returned when a confirmation was
+	// decided or expired first
+	ErrScanConfirmationNotPending = errors.New("scan confirmation is no longer pending")
+)
+
+// ScanConfirmationRepository persists payers' scan confirmation settings
+// and the merchant charges waiting for their approval
+type ScanConfirmationRepository interface {
+	// GetSettings returns the user's settings, off if they never set any
+	GetSettings(userID uint) (*models.ScanConfirmationSettings, error)
+	SaveSettings(settings *models.ScanConfirmationSettings) error
+
+	Create(confirmation *models.ScanConfirmation) error
+	Get(id uint) (*models.ScanConfirmation, error)
+	// ListPending returns the user's charges still waiting, soonest to
+	// expire first
+	ListPending(userID uint, now time.Time) ([]models.ScanConfirmation, error)
+	// Close ends a pending confirmation with status
+	Close(id uint, status string, at time.Time) error
+	// Complete links an approved confirmation to the charge it took
+	Complete(id, transactionID uint) error
+	// Fail records why an approved charge couldn't complete
+	Fail(id uint, reason string) error
+	// FindExpired returns pending confirmations past their expiry
+	FindExpired(now time.Time, limit int) ([]models.ScanConfirmation, error)
+}
+
+type scanConfirmationRepository struct {
+	db *gorm.DB
+}
+
+func NewScanConfirmationRepository(db *gorm.DB) ScanConfirmationRepository {
+	return &scanConfirmationRepository{db: db}
+}
+
+func (r *scanConfirmationRepository) GetSettings(userID uint) (*models.ScanConfirmationSettings, error) {
+	var settings models.ScanConfirmationSettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.ScanConfirmationSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan confirmation settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *scanConfirmationRepository) SaveSettings(settings *models.ScanConfirmationSettings) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "auto_approve_limit", "updated_at"}),
+	}).Create(settings).Error
+	if err != nil {
+		return fmt.Errorf("failed to save scan confirmation settings: %w", err)
+	}
+	return nil
+}
+
+func (r *scanConfirmationRepository) Create(confirmation *models.ScanConfirmation) error {
+	if err := r.db.Create(confirmation).Error; err != nil {
+		return fmt.Errorf("failed to create scan confirmation: %w", err)
+	}
+	return nil
+}
+
+func (r *scanConfirmationRepository) Get(id uint) (*models.ScanConfirmation, error) {
+	var confirmation models.ScanConfirmation
+	if err := r.db.First(&confirmation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScanConfirmationNotFound
+		}
+		return nil, fmt.Errorf("failed to get scan confirmation: %w", err)
+	}
+	return &confirmation, nil
+}
+
+func (r *scanConfirmationRepository) ListPending(userID uint, now time.Time) ([]models.ScanConfirmation, error) {
+	var confirmations []models.ScanConfirmation
+	err := r.db.Where("user_id = ? AND status = ? AND expires_at > ?", userID, models.ScanConfirmationPending, now).
+		Order("expires_at ASC").
+		Find(&confirmations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan confirmations: %w", err)
+	}
+	return confirmations, nil
+}
+
+func (r *scanConfirmationRepository) Close(id uint, status string, at time.Time) error {
+	result := r.db.Model(&models.ScanConfirmation{}).
+		Where("id = ? AND status = ?", id, models.ScanConfirmationPending).
+		Updates(map[string]interface{}{"status": status, "decided_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("failed to close scan confirmation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrScanConfirmationNotPending
+	}
+	return nil
+}
+
+func (r *scanConfirmationRepository) Complete(id, transactionID uint) error {
+	err := r.db.Model(&models.ScanConfirmation{}).Where("id = ?", id).Update("transaction_id", transactionID).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete scan confirmation: %w", err)
+	}
+	return nil
+}
+
+func (r *scanConfirmationRepository) Fail(id uint, reason string) error {
+	err := r.db.Model(&models.ScanConfirmation{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":         models.ScanConfirmationFailed,
+		"failure_reason": reason,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record scan charge failure: %w", err)
+	}
+	return nil
+}
+
+func (r *scanConfirmationRepository) FindExpired(now time.Time, limit int) ([]models.ScanConfirmation, error) {
+	var confirmations []models.ScanConfirmation
+	err := r.db.Where("status = ? AND expires_at <= ?", models.ScanConfirmationPending, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&confirmations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired scan confirmations: %w", err)
+	}
+	return confirmations, nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionRepository persists users' sign-in sessions
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	Get(ctx context.Context, id string) (*models.Session, error)
+	// ListActive returns the user's sessions that are neither revoked nor
+	// expired and began at tokenVersion, most recently used first
+	ListActive(ctx context.Context, userID uint, tokenVersion int, now time.Time) ([]models.Session, error)
+	// Touch records the session was used, extending it to expiresAt
+	Touch(ctx context.Context, id string, at, expiresAt time.Time) error
+	// Revoke ends one of the user's sessions. Ending all of them is done
+	// by bumping the user's token version.
+	Revoke(ctx context.Context, userID uint, id string, at time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Get(ctx context.Context, id string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActive(ctx context.Context, userID uint, tokenVersion int, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND token_version = ? AND revoked_at IS NULL AND expires_at > ?", userID, tokenVersion, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id string, at, expiresAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "expires_at": expiresAt}).Error
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, userID uint, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
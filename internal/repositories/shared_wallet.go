@@ -0,0 +1,325 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSharedWalletNotFound        = errors.New("shared wallet not found")
+	ErrWalletMemberNotFound        = errors.New("wallet member not found")
+	ErrSharedWalletPaymentNotFound = errors.New("shared wallet payment not found")
+	ErrSharedWalletPaymentNotOpen  = errors.New("shared wallet payment is not pending approval")
+	ErrInsufficientSharedFunds     = errors.New("insufficient funds in shared wallet")
+	ErrInsufficientPersonalFunds   = errors.New("insufficient funds in personal wallet")
+)
+
+// SharedWalletRepository persists shared wallets, their members and payments
+type SharedWalletRepository interface {
+	// Create saves the wallet together with its first owner
+	Create(wallet *models.SharedWallet, owner *models.WalletMember) error
+	GetByID(id uint) (*models.SharedWallet, error)
+	GetByUserID(userID uint) ([]models.SharedWallet, error)
+	Update(wallet *models.SharedWallet) error
+
+	GetMember(walletID, userID uint) (*models.WalletMember, error)
+	ListMembers(walletID uint) ([]models.WalletMember, error)
+	AddMember(member *models.WalletMember) error
+	UpdateMember(member *models.WalletMember) error
+	RemoveMember(walletID, userID uint) error
+	CountOwners(walletID uint) (int64, error)
+	// SumMemberSpending totals a member's completed and pending payments since the given time
+	SumMemberSpending(walletID, userID uint, since time.Time) (float64, error)
+
+	// Contribute moves funds from the member's personal wallet into the shared
+	// wallet, which is credited tx's converted amount when currencies differ
+	Contribute(walletID, userID uint, amount float64, tx *models.Transaction) error
+
+	CreatePayment(payment *models.SharedWalletPayment) error
+	GetPayment(walletID, paymentID uint) (*models.SharedWalletPayment, error)
+	ListPayments(walletID uint, status string, limit, offset int) ([]models.SharedWalletPayment, int64, error)
+	// ExecutePayment pays the recipient from the shared wallet. A new payment is
+	// saved as completed; an existing one must still be pending approval and is
+	// marked approved by reviewerID. The recipient is credited tx's converted
+	// amount when currencies differ.
+	ExecutePayment(payment *models.SharedWalletPayment, tx *models.Transaction, reviewerID *uint) error
+	RejectPayment(walletID, paymentID, reviewerID uint) (*models.SharedWalletPayment, error)
+}
+
+type sharedWalletRepository struct {
+	db *gorm.DB
+}
+
+func NewSharedWalletRepository(db *gorm.DB) SharedWalletRepository {
+	return &sharedWalletRepository{db: db}
+}
+
+func (r *sharedWalletRepository) Create(wallet *models.SharedWallet, owner *models.WalletMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(wallet).Error; err != nil {
+			return fmt.Errorf("failed to create shared wallet: %w", err)
+		}
+		owner.SharedWalletID = wallet.ID
+		if err := tx.Create(owner).Error; err != nil {
+			return fmt.Errorf("failed to add wallet owner: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sharedWalletRepository) GetByID(id uint) (*models.SharedWallet, error) {
+	var wallet models.SharedWallet
+	if err := r.db.First(&wallet, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSharedWalletNotFound
+		}
+		return nil, fmt.Errorf("failed to get shared wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+func (r *sharedWalletRepository) GetByUserID(userID uint) ([]models.SharedWallet, error) {
+	var wallets []models.SharedWallet
+	err := r.db.Joins("JOIN wallet_members ON wallet_members.shared_wallet_id = shared_wallets.id").
+		Where("wallet_members.user_id = ?", userID).
+		Order("shared_wallets.id").
+		Find(&wallets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared wallets: %w", err)
+	}
+	return wallets, nil
+}
+
+func (r *sharedWalletRepository) Update(wallet *models.SharedWallet) error {
+	// Never write the balance from a possibly stale copy
+	if err := r.db.Model(wallet).Select("name", "approval_threshold", "status").Updates(wallet).Error; err != nil {
+		return fmt.Errorf("failed to update shared wallet: %w", err)
+	}
+	return nil
+}
+
+func (r *sharedWalletRepository) GetMember(walletID, userID uint) (*models.WalletMember, error) {
+	var member models.WalletMember
+	if err := r.db.Where("shared_wallet_id = ? AND user_id = ?", walletID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWalletMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to get wallet member: %w", err)
+	}
+	return &member, nil
+}
+
+func (r *sharedWalletRepository) ListMembers(walletID uint) ([]models.WalletMember, error) {
+	var members []models.WalletMember
+	if err := r.db.Where("shared_wallet_id = ?", walletID).Order("id").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to get wallet members: %w", err)
+	}
+	return members, nil
+}
+
+func (r *sharedWalletRepository) AddMember(member *models.WalletMember) error {
+	if err := r.db.Create(member).Error; err != nil {
+		return fmt.Errorf("failed to add wallet member: %w", err)
+	}
+	return nil
+}
+
+func (r *sharedWalletRepository) UpdateMember(member *models.WalletMember) error {
+	if err := r.db.Save(member).Error; err != nil {
+		return fmt.Errorf("failed to update wallet member: %w", err)
+	}
+	return nil
+}
+
+func (r *sharedWalletRepository) RemoveMember(walletID, userID uint) error {
+	result := r.db.Where("shared_wallet_id = ? AND user_id = ?", walletID, userID).Delete(&models.WalletMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove wallet member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWalletMemberNotFound
+	}
+	return nil
+}
+
+func (r *sharedWalletRepository) CountOwners(walletID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.WalletMember{}).
+		Where("shared_wallet_id = ? AND role = ?", walletID, models.WalletRoleOwner).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count wallet owners: %w", err)
+	}
+	return count, nil
+}
+
+func (r *sharedWalletRepository) SumMemberSpending(walletID, userID uint, since time.Time) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.SharedWalletPayment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("shared_wallet_id = ? AND requested_by = ? AND created_at >= ? AND status IN ?", walletID, userID, since,
+			[]string{models.SharedPaymentPendingApproval, models.SharedPaymentCompleted}).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum member spending: %w", err)
+	}
+	return total, nil
+}
+
+func (r *sharedWalletRepository) Contribute(walletID, userID uint, amount float64, transaction *models.Transaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := NewWalletRepository(tx).AdjustBalance(userID, -amount); err != nil {
+			if debitRefused(err) {
+				return ErrInsufficientPersonalFunds
+			}
+			return err
+		}
+		shared, err := lockSharedWallet(tx, walletID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(shared).Update("balance", math.Round((shared.Balance+transaction.CreditedAmount())*100)/100).Error; err != nil {
+			return fmt.Errorf("failed to credit shared wallet: %w", err)
+		}
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to record contribution: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sharedWalletRepository) CreatePayment(payment *models.SharedWalletPayment) error {
+	if err := r.db.Create(payment).Error; err != nil {
+		return fmt.Errorf("failed to create shared wallet payment: %w", err)
+	}
+	return nil
+}
+
+func (r *sharedWalletRepository) GetPayment(walletID, paymentID uint) (*models.SharedWalletPayment, error) {
+	return getSharedWalletPayment(r.db, walletID, paymentID)
+}
+
+func (r *sharedWalletRepository) ListPayments(walletID uint, status string, limit, offset int) ([]models.SharedWalletPayment, int64, error) {
+	var payments []models.SharedWalletPayment
+	var total int64
+
+	query := r.db.Model(&models.SharedWalletPayment{}).Where("shared_wallet_id = ?", walletID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count shared wallet payments: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&payments).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get shared wallet payments: %w", err)
+	}
+	return payments, total, nil
+}
+
+func (r *sharedWalletRepository) ExecutePayment(payment *models.SharedWalletPayment, transaction *models.Transaction, reviewerID *uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if payment.ID == 0 {
+			payment.Status = models.SharedPaymentCompleted
+			if err := tx.Create(payment).Error; err != nil {
+				return fmt.Errorf("failed to create shared wallet payment: %w", err)
+			}
+		} else {
+			result := tx.Model(&models.SharedWalletPayment{}).
+				Where("id = ? AND status = ?", payment.ID, models.SharedPaymentPendingApproval).
+				Updates(map[string]interface{}{
+					"status":      models.SharedPaymentCompleted,
+					"reviewed_by": reviewerID,
+					"reviewed_at": now,
+				})
+			if result.Error != nil {
+				return fmt.Errorf("failed to approve shared wallet payment: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return ErrSharedWalletPaymentNotOpen
+			}
+			payment.Status = models.SharedPaymentCompleted
+			payment.ReviewedBy = reviewerID
+			payment.ReviewedAt = &now
+		}
+
+		// Personal wallets are locked before shared ones, as in Contribute
+		if _, err := NewWalletRepository(tx).AdjustBalance(payment.RecipientID, transaction.CreditedAmount()); err != nil {
+			return fmt.Errorf("failed to credit recipient wallet: %w", err)
+		}
+		shared, err := lockSharedWallet(tx, payment.SharedWalletID)
+		if err != nil {
+			return err
+		}
+		if shared.Balance < payment.Amount {
+			return ErrInsufficientSharedFunds
+		}
+
+		if err := tx.Model(shared).Update("balance", math.Round((shared.Balance-payment.Amount)*100)/100).Error; err != nil {
+			return fmt.Errorf("failed to debit shared wallet: %w", err)
+		}
+
+		transaction.Metadata = models.NewJSON(map[string]interface{}{
+			"shared_wallet_id":         payment.SharedWalletID,
+			"shared_wallet_payment_id": payment.ID,
+			"direction":                "out",
+		})
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to record shared wallet payment: %w", err)
+		}
+		payment.TransactionID = &transaction.ID
+		if err := tx.Model(payment).Update("transaction_id", transaction.ID).Error; err != nil {
+			return fmt.Errorf("failed to link shared wallet payment: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sharedWalletRepository) RejectPayment(walletID, paymentID, reviewerID uint) (*models.SharedWalletPayment, error) {
+	result := r.db.Model(&models.SharedWalletPayment{}).
+		Where("id = ? AND shared_wallet_id = ? AND status = ?", paymentID, walletID, models.SharedPaymentPendingApproval).
+		Updates(map[string]interface{}{
+			"status":      models.SharedPaymentRejected,
+			"reviewed_by": reviewerID,
+			"reviewed_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to reject shared wallet payment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := getSharedWalletPayment(r.db, walletID, paymentID); err != nil {
+			return nil, err
+		}
+		return nil, ErrSharedWalletPaymentNotOpen
+	}
+	return getSharedWalletPayment(r.db, walletID, paymentID)
+}
+
+func lockSharedWallet(tx *gorm.DB, id uint) (*models.SharedWallet, error) {
+	var wallet models.SharedWallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&wallet, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSharedWalletNotFound
+		}
+		return nil, fmt.Errorf("failed to lock shared wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+func getSharedWalletPayment(db *gorm.DB, walletID, paymentID uint) (*models.SharedWalletPayment, error) {
+	var payment models.SharedWalletPayment
+	if err := db.Where("id = ? AND shared_wallet_id = ?", paymentID, walletID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSharedWalletPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get shared wallet payment: %w", err)
+	}
+	return &payment, nil
+}
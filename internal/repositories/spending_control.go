@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrSpendingControlNotFound = errors.New("spending control not found")
+
+// SpendingControlRepository persists the spending controls on users'
+// payments to merchants and sums the spending they cap
+type SpendingControlRepository interface {
+	// ListForUser returns every control on the user, their own and those
+	// their organizations set
+	ListForUser(userID uint) ([]models.SpendingControl, error)
+	// ListSetBy returns the controls the organization set on its member
+	ListSetBy(enterpriseID, userID uint) ([]models.SpendingControl, error)
+	Get(id uint) (*models.SpendingControl, error)
+	// Save creates the control or replaces the one with the same user,
+	// organization, scope and category
+	Save(control *models.SpendingControl) error
+	Delete(id uint) error
+
+	// SumCategorySpend totals the user's completed payments since the
+	// given time to merchants whose business type is category
+	SumCategorySpend(userID uint, category string, since time.Time) (float64, error)
+	// SumForeignSpend totals the user's completed payments since the given
+	// time to merchants known to be outside country
+	SumForeignSpend(userID uint, country string, since time.Time) (float64, error)
+}
+
+type spendingControlRepository struct {
+	db *gorm.DB
+}
+
+func NewSpendingControlRepository(db *gorm.DB) SpendingControlRepository {
+	return &spendingControlRepository{db: db}
+}
+
+func (r *spendingControlRepository) ListForUser(userID uint) ([]models.SpendingControl, error) {
+	var controls []models.SpendingControl
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&controls).Error; err != nil {
+		return nil, fmt.Errorf("failed to list spending controls: %w", err)
+	}
+	return controls, nil
+}
+
+func (r *spendingControlRepository) ListSetBy(enterpriseID, userID uint) ([]models.SpendingControl, error) {
+	var controls []models.SpendingControl
+	err := r.db.Where("enterprise_id = ? AND user_id = ?", enterpriseID, userID).
+		Order("id").
+		Find(&controls).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spending controls: %w", err)
+	}
+	return controls, nil
+}
+
+func (r *spendingControlRepository) Get(id uint) (*models.SpendingControl, error) {
+	var control models.SpendingControl
+	if err := r.db.First(&control, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSpendingControlNotFound
+		}
+		return nil, fmt.Errorf("failed to get spending control: %w", err)
+	}
+	return &control, nil
+}
+
+func (r *spendingControlRepository) Save(control *models.SpendingControl) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "enterprise_id"}, {Name: "scope"}, {Name: "category"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"action", "cap_amount", "period", "set_by", "updated_at",
+		}),
+	}).Create(control).Error
+	if err != nil {
+		return fmt.Errorf("failed to save spending control: %w", err)
+	}
+	return nil
+}
+
+func (r *spendingControlRepository) Delete(id uint) error {
+	if err := r.db.Delete(&models.SpendingControl{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete spending control: %w", err)
+	}
+	return nil
+}
+
+func (r *spendingControlRepository) SumCategorySpend(userID uint, category string, since time.Time) (float64, error) {
+	var total float64
+	err := r.merchantSpend(userID, since).
+		Where("LOWER(TRIM(merchants.business_type)) = ?", category).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum category spending: %w", err)
+	}
+	return total, nil
+}
+
+func (r *spendingControlRepository) SumForeignSpend(userID uint, country string, since time.Time) (float64, error) {
+	var total float64
+	err := r.merchantSpend(userID, since).
+		Joins("JOIN users ON users.id = merchants.user_id").
+		Where(`COALESCE(NULLIF(UPPER(merchants.metadata->'address'->>'country'), ''), NULLIF(UPPER(users.country), '')) <> ?`, country).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum foreign spending: %w", err)
+	}
+	return total, nil
+}
+
+// merchantSpend sums the user's completed payments to merchants since the
+// given time, leaving out money merchants send back
+func (r *spendingControlRepository) merchantSpend(userID uint, since time.Time) *gorm.DB {
+	return r.db.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(transactions.amount), 0)").
+		Joins("JOIN merchants ON merchants.user_id = transactions.receiver_id").
+		Where("transactions.sender_id = ? AND transactions.status = ? AND transactions.created_at >= ?",
+			userID, "completed", since).
+		Where("transactions.type NOT IN ?", []string{
+			models.TransactionTypeRefund,
+			models.TransactionTypeChargeback,
+			models.TransactionTypeReversal,
+		})
+}
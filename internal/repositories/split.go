@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSplitNotFound = errors.New("split not found")
+
+// SplitRepository persists split bills and their participant shares
+type SplitRepository interface {
+	Create(split *models.Split) error
+	GetByID(id uint) (*models.Split, error)
+	GetByTransactionID(transactionID uint) (*models.Split, error)
+	// GetByUserID lists splits the user organized or has a share in
+	GetByUserID(userID uint, role string, limit, offset int) ([]models.Split, int64, error)
+
+	// ClaimShare marks a pending share paid while its split is still payable,
+	// so a share can't be paid twice or into a cancelled split
+	ClaimShare(splitID, shareID uint) (bool, error)
+	// ReleaseShare returns a claimed share to pending after a failed payment
+	ReleaseShare(shareID uint) error
+	// RecordPayment links the payment to the share and advances the split's totals and status
+	RecordPayment(splitID, shareID, transactionID uint, amount float64, paidAt time.Time) error
+	// Cancel closes a split nobody has paid into yet
+	Cancel(splitID uint) (bool, error)
+
+	UpdateShare(share *models.SplitShare) error
+	// GetReminderCandidates returns unpaid shares on payable splits not reminded since before
+	GetReminderCandidates(before time.Time, maxReminders, limit int) ([]models.SplitShare, error)
+}
+
+// Split list roles
+const (
+	SplitRoleOrganizer   = "organizer"
+	SplitRoleParticipant = "participant"
+)
+
+type splitRepository struct {
+	db *gorm.DB
+}
+
+func NewSplitRepository(db *gorm.DB) SplitRepository {
+	return &splitRepository{db: db}
+}
+
+func (r *splitRepository) Create(split *models.Split) error {
+	if err := r.db.Create(split).Error; err != nil {
+		return fmt.Errorf("failed to create split: %w", err)
+	}
+	return nil
+}
+
+func (r *splitRepository) GetByID(id uint) (*models.Split, error) {
+	var split models.Split
+	if err := r.db.Preload("Shares", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&split, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSplitNotFound
+		}
+		return nil, fmt.Errorf("failed to get split: %w", err)
+	}
+	return &split, nil
+}
+
+func (r *splitRepository) GetByTransactionID(transactionID uint) (*models.Split, error) {
+	var split models.Split
+	if err := r.db.Where("transaction_id = ?", transactionID).First(&split).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSplitNotFound
+		}
+		return nil, fmt.Errorf("failed to get split: %w", err)
+	}
+	return &split, nil
+}
+
+func (r *splitRepository) GetByUserID(userID uint, role string, limit, offset int) ([]models.Split, int64, error) {
+	var splits []models.Split
+	var total int64
+
+	participating := r.db.Model(&models.SplitShare{}).Select("split_id").Where("user_id = ?", userID)
+	query := r.db.Model(&models.Split{})
+	switch role {
+	case SplitRoleOrganizer:
+		query = query.Where("organizer_id = ?", userID)
+	case SplitRoleParticipant:
+		query = query.Where("id IN (?)", participating)
+	default:
+		query = query.Where("organizer_id = ? OR id IN (?)", userID, participating)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count splits: %w", err)
+	}
+	err := query.Preload("Shares", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("created_at DESC").Limit(limit).Offset(offset).Find(&splits).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get splits: %w", err)
+	}
+	return splits, total, nil
+}
+
+func (r *splitRepository) ClaimShare(splitID, shareID uint) (bool, error) {
+	payable := r.db.Model(&models.Split{}).Select("1").
+		Where("id = ? AND status IN ?", splitID, []string{models.SplitStatusOpen, models.SplitStatusPartiallyPaid})
+	result := r.db.Model(&models.SplitShare{}).
+		Where("id = ? AND split_id = ? AND status = ? AND EXISTS (?)", shareID, splitID, models.SplitShareStatusPending, payable).
+		Update("status", models.SplitShareStatusPaid)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim split share: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *splitRepository) ReleaseShare(shareID uint) error {
+	err := r.db.Model(&models.SplitShare{}).
+		Where("id = ? AND status = ? AND transaction_id IS NULL", shareID, models.SplitShareStatusPaid).
+		Update("status", models.SplitShareStatusPending).Error
+	if err != nil {
+		return fmt.Errorf("failed to release split share: %w", err)
+	}
+	return nil
+}
+
+func (r *splitRepository) RecordPayment(splitID, shareID, transactionID uint, amount float64, paidAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.SplitShare{}).Where("id = ?", shareID).
+			Updates(map[string]interface{}{"transaction_id": transactionID, "paid_at": paidAt}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update split share: %w", err)
+		}
+
+		// Column references on the right read the pre-update row
+		err = tx.Model(&models.Split{}).Where("id = ?", splitID).Updates(map[string]interface{}{
+			"collected_amount": gorm.Expr("ROUND(CAST(collected_amount + ? AS numeric), 2)", amount),
+			"paid_count":       gorm.Expr("paid_count + 1"),
+			"status": gorm.Expr("CASE WHEN paid_count + 1 >= share_count THEN ? ELSE ? END",
+				models.SplitStatusCompleted, models.SplitStatusPartiallyPaid),
+			"completed_at": gorm.Expr("CASE WHEN paid_count + 1 >= share_count THEN CAST(? AS timestamptz) ELSE NULL END", paidAt),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update split totals: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *splitRepository) Cancel(splitID uint) (bool, error) {
+	claimed := r.db.Model(&models.SplitShare{}).Select("1").
+		Where("split_id = ? AND status <> ?", splitID, models.SplitShareStatusPending)
+	result := r.db.Model(&models.Split{}).
+		Where("id = ? AND status = ? AND NOT EXISTS (?)", splitID, models.SplitStatusOpen, claimed).
+		Update("status", models.SplitStatusCancelled)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel split: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *splitRepository) UpdateShare(share *models.SplitShare) error {
+	if err := r.db.Save(share).Error; err != nil {
+		return fmt.Errorf("failed to update split share: %w", err)
+	}
+	return nil
+}
+
+func (r *splitRepository) GetReminderCandidates(before time.Time, maxReminders, limit int) ([]models.SplitShare, error) {
+	var shares []models.SplitShare
+	err := r.db.Model(&models.SplitShare{}).
+		Joins("JOIN splits ON splits.id = split_shares.split_id").
+		Where("split_shares.status = ? AND splits.status IN ?", models.SplitShareStatusPending,
+			[]string{models.SplitStatusOpen, models.SplitStatusPartiallyPaid}).
+		Where("split_shares.reminder_count < ?", maxReminders).
+		Where("COALESCE(split_shares.last_reminder_at, split_shares.created_at) < ?", before).
+		Order("split_shares.id").
+		Limit(limit).
+		Find(&shares).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get split shares for reminders: %w", err)
+	}
+	return shares, nil
+}
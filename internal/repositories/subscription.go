@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSubscriptionPlanNotFound = errors.New("subscription plan not found")
+	ErrSubscriptionNotFound     = errors.New("subscription not found")
+)
+
+// SubscriptionFilter narrows a subscription listing. Zero values match
+// everything.
+type SubscriptionFilter struct {
+	MerchantID uint
+	CustomerID uint
+	PlanID     uint
+	Status     string
+}
+
+// SubscriptionRepository persists subscription plans, subscriptions, their
+// consent records and billing attempts
+type SubscriptionRepository interface {
+	CreatePlan(plan *models.SubscriptionPlan) error
+	GetPlan(id uint) (*models.SubscriptionPlan, error)
+	ListPlans(merchantID uint, status string, limit, offset int) ([]models.SubscriptionPlan, int64, error)
+	UpdatePlan(plan *models.SubscriptionPlan) error
+
+	// Create saves a subscription together with the customer's consent
+	Create(subscription *models.Subscription, consent *models.SubscriptionConsent) error
+	GetByID(id uint) (*models.Subscription, error)
+	List(filter SubscriptionFilter, limit, offset int) ([]models.Subscription, int64, error)
+	// HasLive reports whether the customer already has a subscription to
+	// the plan that hasn't been canceled
+	HasLive(customerID, planID uint) (bool, error)
+	Update(subscription *models.Subscription) error
+	// Transition applies updates only if the subscription is in one of
+	// from. It returns false if it wasn't.
+	Transition(id uint, from []string, updates map[string]interface{}) (bool, error)
+
+	// GetDue returns billable subscriptions whose next charge is due
+	GetDue(now time.Time, limit int) ([]models.Subscription, error)
+	// ClaimBilling pushes a due subscription's next billing time out to
+	// leaseUntil so other workers skip it while it is charged. It returns
+	// false if another worker got there first.
+	ClaimBilling(subscription *models.Subscription, leaseUntil time.Time) (bool, error)
+
+	CreateCharge(charge *models.SubscriptionCharge) error
+	ListCharges(subscriptionID uint, limit int) ([]models.SubscriptionCharge, error)
+	GetConsent(subscriptionID uint) (*models.SubscriptionConsent, error)
+	RevokeConsent(subscriptionID uint, now time.Time) error
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (r *subscriptionRepository) CreatePlan(plan *models.SubscriptionPlan) error {
+	if err := r.db.Create(plan).Error; err != nil {
+		return fmt.Errorf("failed to create subscription plan: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) GetPlan(id uint) (*models.SubscriptionPlan, error) {
+	var plan models.SubscriptionPlan
+	if err := r.db.First(&plan, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionPlanNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription plan: %w", err)
+	}
+	return &plan, nil
+}
+
+func (r *subscriptionRepository) ListPlans(merchantID uint, status string, limit, offset int) ([]models.SubscriptionPlan, int64, error) {
+	var plans []models.SubscriptionPlan
+	var total int64
+
+	query := r.db.Model(&models.SubscriptionPlan{}).Where("merchant_id = ?", merchantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count subscription plans: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&plans).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get subscription plans: %w", err)
+	}
+	return plans, total, nil
+}
+
+func (r *subscriptionRepository) UpdatePlan(plan *models.SubscriptionPlan) error {
+	if err := r.db.Save(plan).Error; err != nil {
+		return fmt.Errorf("failed to update subscription plan: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) Create(subscription *models.Subscription, consent *models.SubscriptionConsent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(subscription).Error; err != nil {
+			return err
+		}
+		consent.SubscriptionID = subscription.ID
+		return tx.Create(consent).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) GetByID(id uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+func (r *subscriptionRepository) List(filter SubscriptionFilter, limit, offset int) ([]models.Subscription, int64, error) {
+	var subscriptions []models.Subscription
+	var total int64
+
+	query := r.db.Model(&models.Subscription{})
+	if filter.MerchantID != 0 {
+		query = query.Where("merchant_id = ?", filter.MerchantID)
+	}
+	if filter.CustomerID != 0 {
+		query = query.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.PlanID != 0 {
+		query = query.Where("plan_id = ?", filter.PlanID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&subscriptions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	return subscriptions, total, nil
+}
+
+func (r *subscriptionRepository) HasLive(customerID, planID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Subscription{}).
+		Where("customer_id = ? AND plan_id = ? AND status <> ?", customerID, planID, models.SubscriptionStatusCanceled).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check subscriptions: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *subscriptionRepository) Update(subscription *models.Subscription) error {
+	if err := r.db.Save(subscription).Error; err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) Transition(id uint, from []string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.Subscription{}).Where("id = ? AND status IN ?", id, from).Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update subscription: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *subscriptionRepository) GetDue(now time.Time, limit int) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := r.db.Where("status IN ? AND next_billing_at <= ?", []string{
+		models.SubscriptionStatusTrialing,
+		models.SubscriptionStatusActive,
+		models.SubscriptionStatusPastDue,
+	}, now).Order("next_billing_at").Limit(limit).Find(&subscriptions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (r *subscriptionRepository) ClaimBilling(subscription *models.Subscription, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&models.Subscription{}).
+		Where("id = ? AND next_billing_at = ?", subscription.ID, subscription.NextBillingAt).
+		Update("next_billing_at", leaseUntil)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim subscription billing: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	subscription.NextBillingAt = &leaseUntil
+	return true, nil
+}
+
+func (r *subscriptionRepository) CreateCharge(charge *models.SubscriptionCharge) error {
+	if err := r.db.Create(charge).Error; err != nil {
+		return fmt.Errorf("failed to record subscription charge: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) ListCharges(subscriptionID uint, limit int) ([]models.SubscriptionCharge, error) {
+	var charges []models.SubscriptionCharge
+	if err := r.db.Where("subscription_id = ?", subscriptionID).Order("id DESC").Limit(limit).Find(&charges).Error; err != nil {
+		return nil, fmt.Errorf("failed to get subscription charges: %w", err)
+	}
+	return charges, nil
+}
+
+func (r *subscriptionRepository) GetConsent(subscriptionID uint) (*models.SubscriptionConsent, error) {
+	var consent models.SubscriptionConsent
+	if err := r.db.Where("subscription_id = ?", subscriptionID).First(&consent).Error; err != nil {
+		return nil, fmt.Errorf("failed to get subscription consent: %w", err)
+	}
+	return &consent, nil
+}
+
+func (r *subscriptionRepository) RevokeConsent(subscriptionID uint, now time.Time) error {
+	err := r.db.Model(&models.SubscriptionConsent{}).
+		Where("subscription_id = ? AND revoked_at IS NULL", subscriptionID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke subscription consent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTaxSummaryReportNotFound = errors.New("tax summary report not found")
+
+// What a tax summary row adds up
+const (
+	TaxIncome          = "income"           // Money received other than as a merchant
+	TaxMerchantRevenue = "merchant_revenue" // Payments received as a merchant
+	TaxRefundsReceived = "refunds_received"
+	TaxFeesPaid        = "fees_paid"
+)
+
+// taxNotIncome are transaction types that move the user's own money or
+// undo earlier transactions, so receiving them isn't income
+var taxNotIncome = []string{
+	models.TransactionTypeTopup,
+	"top_up",
+	models.TransactionTypeWithdrawal,
+	models.TransactionTypeRefund,
+	models.TransactionTypePotTransfer,
+	models.TransactionTypeReversal,
+	models.TransactionTypeChargeback,
+	"fee",
+}
+
+// TaxSummaryRow adds up one kind of amount for a month, category and
+// currency. Month is 1 to 12, in UTC.
+type TaxSummaryRow struct {
+	Month    int
+	Kind     string
+	Category string
+	Currency string
+	Count    int64
+	Amount   float64
+}
+
+// TaxSummaryRepository aggregates a user's transactions for tax summaries
+// and tracks the summaries generated in the background
+type TaxSummaryRepository interface {
+	// CountTransactions counts the user's completed transactions created
+	// in [from, to)
+	CountTransactions(userID uint, from, to time.Time) (int64, error)
+	// Summarize adds up the user's completed transactions created in
+	// [from, to) by month, kind, category and currency. Amounts received
+	// are in the currency they were credited in.
+	Summarize(userID uint, from, to time.Time) ([]TaxSummaryRow, error)
+
+	CreateReport(report *models.TaxSummaryReport) error
+	// GetReport returns the user's report, with its file
+	GetReport(userID, id uint) (*models.TaxSummaryReport, error)
+	// FindUnfinished returns the user's pending or running report for the
+	// year and format, or nil
+	FindUnfinished(userID uint, year int, format string) (*models.TaxSummaryReport, error)
+	// Claim marks the oldest pending report, or a running one whose lease
+	// ran out, running until leaseUntil and returns it; nil when there is
+	// none
+	Claim(now, leaseUntil time.Time) (*models.TaxSummaryReport, error)
+	// Finish saves the report's status, file and error and releases it
+	Finish(report *models.TaxSummaryReport) error
+}
+
+type taxSummaryRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxSummaryRepository(db *gorm.DB) TaxSummaryRepository {
+	return &taxSummaryRepository{db: db}
+}
+
+func (r *taxSummaryRepository) CountTransactions(userID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Transaction{}).
+		Where("(sender_id = ? OR receiver_id = ?) AND status = ?", userID, userID, "completed").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+	return count, nil
+}
+
+func (r *taxSummaryRepository) Summarize(userID uint, from, to time.Time) ([]TaxSummaryRow, error) {
+	var rows []TaxSummaryRow
+	err := r.db.Raw(`WITH t AS (
+			SELECT * FROM transactions
+			WHERE (sender_id = @user OR receiver_id = @user) AND sender_id <> receiver_id
+				AND status = 'completed' AND created_at >= @from AND created_at < @to
+		), received AS (
+			SELECT created_at, type, merchant_id, category,
+				CASE WHEN converted_currency <> '' THEN converted_currency ELSE currency END AS currency,
+				CASE WHEN converted_currency <> '' THEN converted_amount ELSE amount END AS amount
+			FROM t WHERE receiver_id = @user
+		), amounts AS (
+			SELECT created_at, category, currency, amount, @merchant AS kind
+				FROM received WHERE merchant_id IS NOT NULL AND type NOT IN @excluded
+			UNION ALL
+			SELECT created_at, category, currency, amount, @income
+				FROM received WHERE merchant_id IS NULL AND type NOT IN @excluded
+			UNION ALL
+			SELECT created_at, category, currency, amount, @refunds
+				FROM received WHERE type = @refund
+			UNION ALL
+			SELECT created_at, category, currency, CASE WHEN type = 'fee' THEN amount ELSE fee END, @fees
+				FROM t WHERE sender_id = @user AND (type = 'fee' OR fee > 0)
+		)
+		SELECT EXTRACT(MONTH FROM created_at AT TIME ZONE 'UTC')::int AS month, kind,
+			COALESCE(NULLIF(category, ''), 'uncategorized') AS category, currency,
+			COUNT(*) AS count, SUM(amount) AS amount
+		FROM amounts
+		GROUP BY 1, 2, 3, 4
+		ORDER BY 1, 2, 3, 4`,
+		map[string]interface{}{
+			"user":     userID,
+			"from":     from,
+			"to":       to,
+			"excluded": taxNotIncome,
+			"refund":   models.TransactionTypeRefund,
+			"merchant": TaxMerchantRevenue,
+			"income":   TaxIncome,
+			"refunds":  TaxRefundsReceived,
+			"fees":     TaxFeesPaid,
+		}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *taxSummaryRepository) CreateReport(report *models.TaxSummaryReport) error {
+	if err := r.db.Create(report).Error; err != nil {
+		return fmt.Errorf("failed to create tax summary report: %w", err)
+	}
+	return nil
+}
+
+func (r *taxSummaryRepository) GetReport(userID, id uint) (*models.TaxSummaryReport, error) {
+	var report models.TaxSummaryReport
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaxSummaryReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get tax summary report: %w", err)
+	}
+	return &report, nil
+}
+
+func (r *taxSummaryRepository) FindUnfinished(userID uint, year int, format string) (*models.TaxSummaryReport, error) {
+	var reports []models.TaxSummaryReport
+	err := r.db.Omit("Content").
+		Where("user_id = ? AND year = ? AND format = ? AND status IN ?", userID, year, format,
+			[]string{models.TaxSummaryPending, models.TaxSummaryRunning}).
+		Order("id DESC").Limit(1).Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tax summary report: %w", err)
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	return &reports[0], nil
+}
+
+func (r *taxSummaryRepository) Claim(now, leaseUntil time.Time) (*models.TaxSummaryReport, error) {
+	var reports []models.TaxSummaryReport
+	err := r.db.Raw(`UPDATE tax_summary_reports
+		SET status = @running, lease_until = @lease, updated_at = @now
+		WHERE id = (
+			SELECT id FROM tax_summary_reports
+			WHERE status = @pending OR (status = @running AND lease_until < @now)
+			ORDER BY id LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		map[string]interface{}{
+			"running": models.TaxSummaryRunning,
+			"pending": models.TaxSummaryPending,
+			"lease":   leaseUntil,
+			"now":     now,
+		}).Scan(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim tax summary report: %w", err)
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	return &reports[0], nil
+}
+
+func (r *taxSummaryRepository) Finish(report *models.TaxSummaryReport) error {
+	err := r.db.Model(report).Updates(map[string]interface{}{
+		"status":       report.Status,
+		"file_name":    report.FileName,
+		"content":      report.Content,
+		"error":        report.Error,
+		"completed_at": report.CompletedAt,
+		"lease_until":  nil,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to finish tax summary report: %w", err)
+	}
+	return nil
+}
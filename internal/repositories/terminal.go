@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTerminalNotFound = errors.New("terminal not found")
+
+// TerminalRepository persists point-of-sale terminals
+type TerminalRepository interface {
+	Create(terminal *models.Terminal) error
+	GetByID(id uint) (*models.Terminal, error)
+	// GetByPairingCode finds a pending terminal by the hash of its pairing code
+	GetByPairingCode(codeHash string) (*models.Terminal, error)
+	GetByCredentialKey(key string) (*models.Terminal, error)
+	// List returns a merchant's terminals, or every terminal when
+	// merchantUserID is 0
+	List(merchantUserID uint, status string, limit, offset int) ([]models.Terminal, int64, error)
+	// CountActive counts a merchant's terminals that haven't been deactivated
+	CountActive(merchantUserID uint) (int64, error)
+	// Pair activates a pending terminal with its credentials. It returns
+	// false if the terminal was no longer pending on that pairing code.
+	Pair(id uint, codeHash string, updates map[string]interface{}) (bool, error)
+	// ResetPairing revokes a terminal's credentials and puts it back to
+	// pending on a new pairing code. It returns false if it was deactivated.
+	ResetPairing(id uint, codeHash string, expiresAt time.Time) (bool, error)
+	// Deactivate permanently disables a terminal. It returns false if it
+	// was already deactivated.
+	Deactivate(id, by uint, reason string, now time.Time) (bool, error)
+	Touch(id uint, seenAt time.Time) error
+	ListTransactions(terminalID uint, limit, offset int) ([]models.Transaction, int64, error)
+}
+
+type terminalRepository struct {
+	db *gorm.DB
+}
+
+func NewTerminalRepository(db *gorm.DB) TerminalRepository {
+	return &terminalRepository{db: db}
+}
+
+func (r *terminalRepository) Create(terminal *models.Terminal) error {
+	if err := r.db.Create(terminal).Error; err != nil {
+		return fmt.Errorf("failed to create terminal: %w", err)
+	}
+	return nil
+}
+
+func (r *terminalRepository) GetByID(id uint) (*models.Terminal, error) {
+	return r.first(r.db.Where("id = ?", id))
+}
+
+func (r *terminalRepository) GetByPairingCode(codeHash string) (*models.Terminal, error) {
+	return r.first(r.db.Where("pairing_code_hash = ? AND status = ?", codeHash, models.TerminalStatusPending))
+}
+
+func (r *terminalRepository) GetByCredentialKey(key string) (*models.Terminal, error) {
+	return r.first(r.db.Where("credential_key = ?", key))
+}
+
+func (r *terminalRepository) first(query *gorm.DB) (*models.Terminal, error) {
+	var terminal models.Terminal
+	if err := query.First(&terminal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTerminalNotFound
+		}
+		return nil, fmt.Errorf("failed to get terminal: %w", err)
+	}
+	return &terminal, nil
+}
+
+func (r *terminalRepository) List(merchantUserID uint, status string, limit, offset int) ([]models.Terminal, int64, error) {
+	var terminals []models.Terminal
+	var total int64
+
+	query := r.db.Model(&models.Terminal{})
+	if merchantUserID != 0 {
+		query = query.Where("merchant_user_id = ?", merchantUserID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count terminals: %w", err)
+	}
+	if err := query.Order("id").Limit(limit).Offset(offset).Find(&terminals).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list terminals: %w", err)
+	}
+	return terminals, total, nil
+}
+
+func (r *terminalRepository) CountActive(merchantUserID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Terminal{}).
+		Where("merchant_user_id = ? AND status <> ?", merchantUserID, models.TerminalStatusDeactivated).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count terminals: %w", err)
+	}
+	return count, nil
+}
+
+func (r *terminalRepository) Pair(id uint, codeHash string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.Terminal{}).
+		Where("id = ? AND status = ? AND pairing_code_hash = ?", id, models.TerminalStatusPending, codeHash).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to pair terminal: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *terminalRepository) ResetPairing(id uint, codeHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Terminal{}).
+		Where("id = ? AND status <> ?", id, models.TerminalStatusDeactivated).
+		Updates(map[string]interface{}{
+			"status":             models.TerminalStatusPending,
+			"pairing_code_hash":  codeHash,
+			"pairing_expires_at": expiresAt,
+			"credential_key":     nil,
+			"secret_hash":        "",
+			"paired_at":          nil,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reset terminal pairing: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *terminalRepository) Deactivate(id, by uint, reason string, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Terminal{}).
+		Where("id = ? AND status <> ?", id, models.TerminalStatusDeactivated).
+		Updates(map[string]interface{}{
+			"status":              models.TerminalStatusDeactivated,
+			"pairing_code_hash":   "",
+			"pairing_expires_at":  nil,
+			"deactivated_at":      now,
+			"deactivated_by":      by,
+			"deactivation_reason": reason,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to deactivate terminal: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *terminalRepository) Touch(id uint, seenAt time.Time) error {
+	if err := r.db.Model(&models.Terminal{}).Where("id = ?", id).UpdateColumn("last_seen_at", seenAt).Error; err != nil {
+		return fmt.Errorf("failed to update terminal: %w", err)
+	}
+	return nil
+}
+
+func (r *terminalRepository) ListTransactions(terminalID uint, limit, offset int) ([]models.Transaction, int64, error) {
+	var transactions []models.Transaction
+	var total int64
+
+	query := r.db.Model(&models.Transaction{}).Where("terminal_id = ?", terminalID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count terminal transactions: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&transactions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get terminal transactions: %w", err)
+	}
+	return transactions, total, nil
+}
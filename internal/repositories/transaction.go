@@ -4,21 +4,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"orus/internal/models"
+	"strconv"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionNotAwaitingConfirmation is returned when a held transfer
+	// was completed or cancelled first
+	ErrTransactionNotAwaitingConfirmation = errors.New("transaction is no longer awaiting confirmation")
 )
 
 // Add these methods to your existing TransactionRepository interface
 type TransactionRepository interface {
+	// WithContext returns the repository with every query bound to ctx, so
+	// they're cancelled with it
+	WithContext(ctx context.Context) TransactionRepository
+	// ExecuteInTransaction runs fn with transaction and wallet repositories sharing one database transaction
+	ExecuteInTransaction(fn func(TransactionRepository, WalletRepository) error) error
+
 	// ... existing methods ...
 	CreateTransaction(tx *models.Transaction) error
 	// Dashboard-specific methods
 	GetTransactionStats(userID uint) (count int, volume float64, err error)
 	GetLastTransaction(userID uint) (*models.Transaction, error)
-	GetRecentMerchants(userID uint, limit int) ([]string, error)
+	GetRecentMerchants(userID uint, since time.Time, limit int) ([]string, error)
+	// GetRecentReceived returns up to limit of the completed payments a
+	// user received since a time, newest first
+	GetRecentReceived(userID uint, since time.Time, limit int) ([]models.Transaction, error)
 	GetSpendingByCategory(userID uint, since time.Time) (map[string]float64, error)
 	GetIncomeByCategory(userID uint, since time.Time) (map[string]float64, error)
 	GetUniqueCustomerCount(merchantID uint) (int, error)
@@ -29,151 +46,238 @@ type TransactionRepository interface {
 	FindByID(id uint) (*models.Transaction, error)
 	Update(transaction *models.Transaction) error
 	GetDailyTransactionTotal(ctx context.Context, userID uint, start, end time.Time, txType string, total *float64) error
+	// GetUserTransactions pages through the transactions a user sent or received
+	GetUserTransactions(userID uint, limit, offset int) ([]models.Transaction, int64, error)
+	// GetUserTransactionsBefore returns up to limit of the transactions a
+	// user sent or received before before, newest first
+	GetUserTransactionsBefore(userID uint, before time.Time, limit int) ([]models.Transaction, error)
+	// List pages through every transaction, for admins
+	List(limit, offset int) ([]models.Transaction, int64, error)
+	// GetMerchantCharge finds a completed charge the merchant received by
+	// its ID or transaction reference
+	GetMerchantCharge(merchantID uint, id string) (*models.Transaction, error)
+	// GetRefundedAmount sums the completed refunds of a merchant's charge
+	GetRefundedAmount(merchantID, chargeID uint) (float64, error)
+	// GetPendingMerchantTotals sums the payments a merchant has yet to
+	// receive and the refunds it has yet to pay out
+	GetPendingMerchantTotals(merchantID uint) (*PendingMerchantTotals, error)
+
+	// CompleteHeld completes a transfer awaiting confirmation, returning
+	// ErrTransactionNotAwaitingConfirmation once it was completed or
+	// cancelled
+	CompleteHeld(id uint, processedAt time.Time) error
+	// GetForUpdate loads the transaction and locks it until the
+	// surrounding database transaction ends. A non-zero merchantID limits
+	// it to payments that merchant received.
+	GetForUpdate(id, merchantID uint) (*models.Transaction, error)
+	// HasCompletedRefunds reports whether any refund of the charge went
+	// through
+	HasCompletedRefunds(chargeID uint) (bool, error)
+	UpdateStatus(id uint, status string) error
+	CreateReversal(reversal *models.TransactionReversal) error
+	// PostSystemEntry posts entry to a system account, committing or
+	// rolling back with the repository's database transaction
+	PostSystemEntry(code string, entry *models.SystemLedgerEntry) error
 }
 
-func CreateTransaction(tx *models.Transaction) error {
-	return DB.Create(tx).Error
+// PendingMerchantTotals is what is still in flight for a merchant
+type PendingMerchantTotals struct {
+	IncomingCount  int64
+	IncomingAmount float64
+	// IncomingFees sums the fees already worked out on incoming payments;
+	// UnpricedCount and UnpricedAmount count and sum the incoming payments
+	// with no fee recorded yet
+	IncomingFees   float64
+	UnpricedCount  int64
+	UnpricedAmount float64
+	RefundCount    int64
+	RefundAmount   float64
 }
 
-func GetTransactionByQRCode(qrCodeID string) (*models.Transaction, error) {
+// transactionRepository struct
+
+// FindByID retrieves a transaction by its ID
+func (r *transactionRepository) FindByID(id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := DB.Where("qr_code_id = ?", qrCodeID).First(&transaction).Error
-	return &transaction, err
+	err := r.db.First(&transaction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
 }
 
-func UpdateTransaction(tx *models.Transaction) error {
-	return DB.Save(tx).Error
+func (r *transactionRepository) Create(transaction *models.Transaction) error {
+	return r.db.Create(transaction).Error
 }
 
-// Get all transactions for admin with pagination
-func GetTransactions(limit, offset int) ([]models.Transaction, int64, error) {
-	var transactions []models.Transaction
-	var total int64
-
-	DB.Model(&models.Transaction{}).Count(&total)
-	result := DB.Limit(limit).Offset(offset).Find(&transactions)
+func (r *transactionRepository) Update(transaction *models.Transaction) error {
+	return r.db.Save(transaction).Error
+}
 
-	return transactions, total, result.Error
+func (r *transactionRepository) GetDailyTransactionTotal(ctx context.Context, userID uint, start, end time.Time, txType string, total *float64) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("sender_id = ? AND type = ? AND created_at BETWEEN ? AND ?", userID, txType, start, end).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(total).Error
+	if err != nil {
+		return fmt.Errorf("failed to get daily transaction total: %w", err)
+	}
+	return nil
 }
 
-// Get transactions for a specific user with pagination
-func GetUserTransactions(userID uint, limit int, offset int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetUserTransactions(userID uint, limit, offset int) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
-	result := DB.Where("sender_id = ? OR receiver_id = ?", userID, userID).
-		Limit(limit).Offset(offset).
-		Order("transaction_id DESC"). // Changed from "created_at DESC"
-		Find(&transactions)
-	return transactions, result.Error
-}
-
-func ProcessTransaction(senderID uint, receiverID uint, amount float64, qrCodeID string) error {
-	return DB.Transaction(func(tx *gorm.DB) error {
-		// Validate amount
-		if amount <= 0 {
-			return errors.New("amount must be greater than zero")
-		}
-
-		// Prevent self-transfer
-		if senderID == receiverID {
-			return errors.New("cannot send money to yourself")
-		}
-
-		// Get sender's wallet
-		var senderWallet models.Wallet
-		err := tx.Where("user_id = ?", senderID).First(&senderWallet).Error
-		if err != nil {
-			return fmt.Errorf("failed to fetch sender's wallet: %v", err)
-		}
-
-		// Check sufficient balance
-		if senderWallet.Balance < amount {
-			return fmt.Errorf("insufficient funds: available %.2f, requested %.2f", senderWallet.Balance, amount)
-		}
-
-		// Get receiver's wallet
-		var receiverWallet models.Wallet
-		err = tx.Where("user_id = ?", receiverID).First(&receiverWallet).Error
-		if err != nil {
-			return fmt.Errorf("failed to fetch receiver's wallet: %v", err)
-		}
+	var total int64
 
-		// Create transaction record
-		transaction := &models.Transaction{
-			SenderID:   senderID,
-			ReceiverID: receiverID,
-			Amount:     amount,
-			Status:     "pending",
-			QRCodeID:   &qrCodeID,
-			Type:       "TRANSFER",
-		}
+	query := r.db.Scopes(Replica(userID)).Model(&models.Transaction{}).Where("sender_id = ? OR receiver_id = ?", userID, userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count user transactions: %w", err)
+	}
+	if err := query.Order("transaction_id DESC").Limit(limit).Offset(offset).Find(&transactions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get user transactions: %w", err)
+	}
+	return transactions, total, nil
+}
 
-		if err := tx.Create(transaction).Error; err != nil {
-			return fmt.Errorf("failed to create transaction: %v", err)
-		}
+func (r *transactionRepository) GetUserTransactionsBefore(userID uint, before time.Time, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Scopes(Replica(userID)).Where("(sender_id = ? OR receiver_id = ?) AND created_at < ?", userID, userID, before).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user transactions: %w", err)
+	}
+	return transactions, nil
+}
 
-		// Update balances
-		senderWallet.Balance -= amount
-		if err := tx.Model(senderWallet).Update("balance", senderWallet.Balance).Error; err != nil {
-			return fmt.Errorf("failed to update sender's wallet: %v", err)
-		}
+func (r *transactionRepository) List(limit, offset int) ([]models.Transaction, int64, error) {
+	var transactions []models.Transaction
+	var total int64
 
-		receiverWallet.Balance += amount
-		if err := tx.Model(receiverWallet).Update("balance", receiverWallet.Balance).Error; err != nil {
-			return fmt.Errorf("failed to update receiver's wallet: %v", err)
-		}
+	if err := r.db.Scopes(Replica(0)).Model(&models.Transaction{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+	if err := r.db.Scopes(Replica(0)).Limit(limit).Offset(offset).Find(&transactions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	return transactions, total, nil
+}
 
-		// Mark transaction as completed
-		if err := tx.Model(transaction).Update("status", "completed").Error; err != nil {
-			log.Printf("Warning: Failed to update transaction status: %v", err)
-		}
+func (r *transactionRepository) GetMerchantCharge(merchantID uint, id string) (*models.Transaction, error) {
+	var charge models.Transaction
+	query := r.db.Where("receiver_id = ? AND merchant_id IS NOT NULL AND status = ?", merchantID, "completed")
+	if n, err := strconv.ParseUint(id, 10, 32); err == nil {
+		query = query.Where("id = ?", n)
+	} else {
+		query = query.Where("transaction_id = ?", id)
+	}
+	if err := query.First(&charge).Error; err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
 
-		// Invalidate wallet caches
-		InvalidateWalletCache(senderID)
-		InvalidateWalletCache(receiverID)
+func (r *transactionRepository) GetRefundedAmount(merchantID, chargeID uint) (float64, error) {
+	var refunded float64
+	err := r.db.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("type = ? AND sender_id = ? AND reference = ? AND status = ?",
+			models.TransactionTypeRefund, merchantID, strconv.FormatUint(uint64(chargeID), 10), "completed").
+		Scan(&refunded).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get refunded amount: %w", err)
+	}
+	return refunded, nil
+}
 
-		return nil
-	})
+func (r *transactionRepository) GetPendingMerchantTotals(merchantID uint) (*PendingMerchantTotals, error) {
+	var totals PendingMerchantTotals
+	err := r.db.Model(&models.Transaction{}).
+		Where("status = ? AND (receiver_id = ? OR sender_id = ?) AND sender_id <> receiver_id", "pending", merchantID, merchantID).
+		Select(`COUNT(*) FILTER (WHERE receiver_id = @merchant AND type <> @refund),
+			COALESCE(SUM(amount) FILTER (WHERE receiver_id = @merchant AND type <> @refund), 0),
+			COALESCE(SUM(fee) FILTER (WHERE receiver_id = @merchant AND type <> @refund), 0),
+			COUNT(*) FILTER (WHERE receiver_id = @merchant AND type <> @refund AND COALESCE(fee, 0) = 0),
+			COALESCE(SUM(amount) FILTER (WHERE receiver_id = @merchant AND type <> @refund AND COALESCE(fee, 0) = 0), 0),
+			COUNT(*) FILTER (WHERE sender_id = @merchant AND type = @refund),
+			COALESCE(SUM(amount) FILTER (WHERE sender_id = @merchant AND type = @refund), 0)`,
+			map[string]interface{}{"merchant": merchantID, "refund": models.TransactionTypeRefund}).
+		Row().Scan(&totals.IncomingCount, &totals.IncomingAmount, &totals.IncomingFees,
+		&totals.UnpricedCount, &totals.UnpricedAmount, &totals.RefundCount, &totals.RefundAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending merchant totals: %w", err)
+	}
+	return &totals, nil
 }
 
-func InvalidateWalletCache(userID uint) {
-	key := CacheService.GenerateKey("wallet", "user", userID)
-	CacheService.Delete(context.Background(), key)
+func (r *transactionRepository) CompleteHeld(id uint, processedAt time.Time) error {
+	result := r.db.Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", id, models.TransactionStatusAwaitingConfirmation).
+		Updates(map[string]interface{}{"status": "completed", "processed_at": processedAt})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransactionNotAwaitingConfirmation
+	}
+	return nil
 }
 
-// transactionRepository struct
+func (r *transactionRepository) GetForUpdate(id, merchantID uint) (*models.Transaction, error) {
+	var tx models.Transaction
+	query := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id)
+	if merchantID != 0 {
+		query = query.Where("receiver_id = ? AND merchant_id IS NOT NULL", merchantID)
+	}
+	if err := query.First(&tx).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &tx, nil
+}
 
-// FindByID retrieves a transaction by its ID
-func (r *transactionRepository) FindByID(id uint) (*models.Transaction, error) {
-	var transaction models.Transaction
-	err := r.db.First(&transaction, id).Error
+func (r *transactionRepository) HasCompletedRefunds(chargeID uint) (bool, error) {
+	var refunds int64
+	err := r.db.Model(&models.Transaction{}).
+		Where("type = ? AND reference = ? AND status = ?",
+			models.TransactionTypeRefund, strconv.FormatUint(uint64(chargeID), 10), "completed").
+		Count(&refunds).Error
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("failed to check refunds: %w", err)
 	}
-	return &transaction, nil
+	return refunds > 0, nil
 }
 
-func (r *transactionRepository) Create(transaction *models.Transaction) error {
-	return r.db.Create(transaction).Error
+func (r *transactionRepository) UpdateStatus(id uint, status string) error {
+	if err := r.db.Model(&models.Transaction{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	return nil
 }
 
-func (r *transactionRepository) Update(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+func (r *transactionRepository) CreateReversal(reversal *models.TransactionReversal) error {
+	if err := r.db.Create(reversal).Error; err != nil {
+		return fmt.Errorf("failed to record reversal: %w", err)
+	}
+	return nil
 }
 
-func (r *transactionRepository) GetDailyTransactionTotal(ctx context.Context, userID uint, start, end time.Time, txType string, total *float64) error {
-	key := CacheService.GenerateKey("transaction", "daily", map[string]interface{}{
-		"user_id": userID,
-		"start":   start.Format(time.RFC3339),
-		"end":     end.Format(time.RFC3339),
-		"type":    txType,
-	})
+func (r *transactionRepository) PostSystemEntry(code string, entry *models.SystemLedgerEntry) error {
+	return models.PostSystemEntry(r.db.Session(&gorm.Session{NewDB: true}), code, entry)
+}
 
-	found, _ := CacheService.Get(ctx, key, total)
-	if found {
-		return nil
+func (r *transactionRepository) GetRecentReceived(userID uint, since time.Time, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Where("receiver_id = ? AND status = ? AND created_at >= ?", userID, "completed", since).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent transactions: %w", err)
 	}
-
-	// If cache miss, proceed to database...
-	CacheService.SetWithTTL(ctx, key, *total, 5*time.Minute)
-	return nil
+	return transactions, nil
 }
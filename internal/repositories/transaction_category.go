@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCategoryRuleNotFound = errors.New("category rule not found")
+
+// TransactionCategoryRepository stores users' categorization rules and
+// changes the categories of existing transactions
+type TransactionCategoryRepository interface {
+	ListRules(userID uint) ([]models.CategoryRule, error)
+	CountRules(userID uint) (int64, error)
+	CreateRule(rule *models.CategoryRule) error
+	DeleteRule(userID, id uint) error
+
+	// LastChosenCategory returns the category of the sender's latest
+	// payment to the receiver that they or their rules categorized, or ""
+	LastChosenCategory(senderID, receiverID uint) (string, error)
+	// ListUncategorized returns transactions created before categorization,
+	// in ID order after afterID
+	ListUncategorized(afterID uint, limit int) ([]models.Transaction, error)
+	// SetCategory changes a transaction's category. If the dashboards
+	// already count it, its sender's spending moves to the new category
+	// in the same database transaction.
+	SetCategory(tx *models.Transaction, category, source string) error
+}
+
+type transactionCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionCategoryRepository(db *gorm.DB) TransactionCategoryRepository {
+	return &transactionCategoryRepository{db: db}
+}
+
+func (r *transactionCategoryRepository) ListRules(userID uint) ([]models.CategoryRule, error) {
+	var rules []models.CategoryRule
+	if err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list category rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *transactionCategoryRepository) CountRules(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.CategoryRule{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count category rules: %w", err)
+	}
+	return count, nil
+}
+
+func (r *transactionCategoryRepository) CreateRule(rule *models.CategoryRule) error {
+	if err := r.db.Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create category rule: %w", err)
+	}
+	return nil
+}
+
+func (r *transactionCategoryRepository) DeleteRule(userID, id uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.CategoryRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete category rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrCategoryRuleNotFound
+	}
+	return nil
+}
+
+func (r *transactionCategoryRepository) LastChosenCategory(senderID, receiverID uint) (string, error) {
+	var categories []string
+	err := r.db.Model(&models.Transaction{}).
+		Where("sender_id = ? AND receiver_id = ?", senderID, receiverID).
+		Where("category_source IN ?", []string{models.CategorySourceUser, models.CategorySourceRule}).
+		Order("created_at DESC").
+		Limit(1).
+		Pluck("category", &categories).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to get last chosen category: %w", err)
+	}
+	if len(categories) == 0 {
+		return "", nil
+	}
+	return categories[0], nil
+}
+
+func (r *transactionCategoryRepository) ListUncategorized(afterID uint, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Where("category_source = '' AND id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uncategorized transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (r *transactionCategoryRepository) SetCategory(tx *models.Transaction, category, source string) error {
+	return r.db.Transaction(func(db *gorm.DB) error {
+		// UpdateColumns leaves updated_at alone, so the change doesn't send
+		// the transaction back through the dashboard projection
+		err := db.Model(&models.Transaction{}).
+			Where("id = ? AND created_at = ?", tx.ID, tx.CreatedAt).
+			UpdateColumns(map[string]interface{}{"category": category, "category_source": source}).Error
+		if err != nil {
+			return fmt.Errorf("failed to set transaction category: %w", err)
+		}
+
+		from := tx.SpendingCategory()
+		tx.Category, tx.CategorySource = category, source
+		if from == tx.SpendingCategory() || !tx.CountsAsSpending() {
+			return nil
+		}
+		var projected int64
+		err = db.Model(&models.ProjectedTransaction{}).Where("transaction_id = ?", tx.ID).Count(&projected).Error
+		if err != nil {
+			return fmt.Errorf("failed to check transaction projection: %w", err)
+		}
+		if projected == 0 {
+			return nil
+		}
+		return moveSpending(db, tx, from)
+	})
+}
+
+// moveSpending moves a projected transaction's amount from one key of its
+// sender's spending breakdown to the one for its current category
+func moveSpending(db *gorm.DB, tx *models.Transaction, from string) error {
+	at := tx.CreatedAt.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	err := db.Model(&models.DailyBreakdown{}).
+		Where("scope = ? AND owner_id = ? AND day = ? AND key = ?", models.BreakdownUserSpending, tx.SenderID, day, from).
+		UpdateColumns(map[string]interface{}{
+			"count":  gorm.Expr("GREATEST(count - 1, 0)"),
+			"volume": gorm.Expr("ROUND((volume - ?)::numeric, 2)", tx.Amount),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to move spending breakdown: %w", err)
+	}
+	err = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}, {Name: "owner_id"}, {Name: "day"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":  gorm.Expr("daily_breakdowns.count + excluded.count"),
+			"volume": gorm.Expr("ROUND((daily_breakdowns.volume + excluded.volume)::numeric, 2)"),
+		}),
+	}).Create(&models.DailyBreakdown{
+		Scope:   models.BreakdownUserSpending,
+		OwnerID: tx.SenderID,
+		Day:     day,
+		Key:     tx.SpendingCategory(),
+		Count:   1,
+		Volume:  tx.Amount,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to move spending breakdown: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
 	"orus/internal/models"
 	"time"
@@ -35,10 +36,10 @@ func (r *transactionRepository) GetLastTransaction(userID uint) (*models.Transac
 	return &tx, nil
 }
 
-func (r *transactionRepository) GetRecentMerchants(userID uint, limit int) ([]string, error) {
+func (r *transactionRepository) GetRecentMerchants(userID uint, since time.Time, limit int) ([]string, error) {
 	var merchants []string
 	err := r.db.Model(&models.Transaction{}).
-		Where("sender_id = ?", userID).
+		Where("sender_id = ? AND created_at >= ?", userID, since).
 		Select("DISTINCT merchant_name").
 		Where("merchant_name != ''").
 		Limit(limit).
@@ -234,7 +235,7 @@ func (r *transactionRepository) GetMerchantTransactions(merchantID uint, limit,
 	var total int64
 
 	// Get total count
-	if err := r.db.Model(&models.Transaction{}).
+	if err := r.db.Scopes(Replica(merchantID)).Model(&models.Transaction{}).
 		Where("receiver_id = ? AND type IN (?, ?, ?)",
 			merchantID,
 			"merchant_scan",
@@ -245,7 +246,7 @@ func (r *transactionRepository) GetMerchantTransactions(merchantID uint, limit,
 	}
 
 	// Get paginated transactions with merchant details
-	err := r.db.Where("receiver_id = ? AND type IN (?, ?, ?)",
+	err := r.db.Scopes(Replica(merchantID)).Where("receiver_id = ? AND type IN (?, ?, ?)",
 		merchantID,
 		"merchant_scan",
 		"merchant_direct",
@@ -298,20 +299,12 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 	}
 }
 
-func UpdateTransactionCategories() error {
-	// Set default categories based on transaction types
-	return DB.Exec(`
-		UPDATE transactions 
-		SET category = CASE
-			WHEN type = 'TOPUP' THEN 'Deposit'
-			WHEN type = 'WITHDRAWAL' THEN 'Withdrawal'
-			WHEN type = 'P2P_TRANSFER' THEN 'Transfer'
-			WHEN type = 'QR_PAYMENT' THEN 'Payment'
-			WHEN type = 'merchant_direct' THEN 'Shopping'
-			WHEN type = 'merchant_scan' THEN 'Shopping'
-			WHEN type = 'refund' THEN 'Refund'
-			ELSE 'Other'
-		END
-		WHERE category IS NULL OR category = ''
-	`).Error
+func (r *transactionRepository) WithContext(ctx context.Context) TransactionRepository {
+	return &transactionRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *transactionRepository) ExecuteInTransaction(fn func(TransactionRepository, WalletRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&transactionRepository{db: tx}, NewWalletRepository(tx))
+	})
 }
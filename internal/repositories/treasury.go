@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSystemAccountNotFound    = errors.New("system account not found")
+	ErrTreasuryTransferNotFound = errors.New("treasury transfer not found")
+	ErrTreasuryTransferNotOpen  = errors.New("treasury transfer is not pending")
+	ErrInsufficientSystemFunds  = errors.New("insufficient funds in system account")
+)
+
+// SystemAccountTotals summarizes postings to one system account over a period
+type SystemAccountTotals struct {
+	AccountID uint    `json:"account_id"`
+	Credits   float64 `json:"credits"`
+	Debits    float64 `json:"debits"`
+	Entries   int64   `json:"entries"`
+}
+
+// TreasuryRepository persists system accounts, their ledger and the
+// transfers between them
+type TreasuryRepository interface {
+	EnsureAccounts() error
+	ListAccounts() ([]models.SystemAccount, error)
+	GetAccountByCode(code string) (*models.SystemAccount, error)
+	GetEntries(accountID uint, limit, offset int) ([]models.SystemLedgerEntry, int64, error)
+	GetPeriodTotals(from, to time.Time) ([]SystemAccountTotals, error)
+	// GetCustomerFunds returns the total held in customer wallets
+	GetCustomerFunds() (float64, error)
+
+	CreateTransfer(transfer *models.TreasuryTransfer) error
+	GetTransfer(id uint) (*models.TreasuryTransfer, error)
+	ListTransfers(status string, limit, offset int) ([]models.TreasuryTransfer, int64, error)
+	// ExecuteTransfer approves a pending transfer and posts both legs atomically
+	ExecuteTransfer(id, reviewerID uint, note string) (*models.TreasuryTransfer, error)
+	RejectTransfer(id, reviewerID uint, note string) (*models.TreasuryTransfer, error)
+}
+
+type treasuryRepository struct {
+	db *gorm.DB
+}
+
+func NewTreasuryRepository(db *gorm.DB) TreasuryRepository {
+	return &treasuryRepository{db: db}
+}
+
+func (r *treasuryRepository) EnsureAccounts() error {
+	for _, def := range models.SystemAccountDefinitions {
+		account := models.SystemAccount{Code: def.Code, Name: def.Name, Type: def.Type}
+		err := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).
+			Create(&account).Error
+		if err != nil {
+			return fmt.Errorf("failed to create system account %s: %w", def.Code, err)
+		}
+	}
+	return nil
+}
+
+func (r *treasuryRepository) ListAccounts() ([]models.SystemAccount, error) {
+	var accounts []models.SystemAccount
+	if err := r.db.Order("id").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list system accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func (r *treasuryRepository) GetAccountByCode(code string) (*models.SystemAccount, error) {
+	var account models.SystemAccount
+	if err := r.db.Where("code = ?", code).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSystemAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get system account: %w", err)
+	}
+	return &account, nil
+}
+
+func (r *treasuryRepository) GetEntries(accountID uint, limit, offset int) ([]models.SystemLedgerEntry, int64, error) {
+	var entries []models.SystemLedgerEntry
+	var total int64
+
+	query := r.db.Model(&models.SystemLedgerEntry{}).Where("account_id = ?", accountID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count system ledger entries: %w", err)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get system ledger entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+func (r *treasuryRepository) GetPeriodTotals(from, to time.Time) ([]SystemAccountTotals, error) {
+	var totals []SystemAccountTotals
+	err := r.db.Model(&models.SystemLedgerEntry{}).
+		Select(`account_id,
+			COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) AS credits,
+			COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0) AS debits,
+			COUNT(*) AS entries`).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("account_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system account totals: %w", err)
+	}
+	return totals, nil
+}
+
+func (r *treasuryRepository) GetCustomerFunds() (float64, error) {
+	var total float64
+	if err := r.db.Model(&models.Wallet{}).Select("COALESCE(SUM(balance), 0)").Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum wallet balances: %w", err)
+	}
+	return total, nil
+}
+
+func (r *treasuryRepository) CreateTransfer(transfer *models.TreasuryTransfer) error {
+	if err := r.db.Create(transfer).Error; err != nil {
+		return fmt.Errorf("failed to create treasury transfer: %w", err)
+	}
+	return nil
+}
+
+func (r *treasuryRepository) GetTransfer(id uint) (*models.TreasuryTransfer, error) {
+	return getTreasuryTransfer(r.db, id)
+}
+
+func (r *treasuryRepository) ListTransfers(status string, limit, offset int) ([]models.TreasuryTransfer, int64, error) {
+	var transfers []models.TreasuryTransfer
+	var total int64
+
+	query := r.db.Model(&models.TreasuryTransfer{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count treasury transfers: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&transfers).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get treasury transfers: %w", err)
+	}
+	return transfers, total, nil
+}
+
+func (r *treasuryRepository) ExecuteTransfer(id, reviewerID uint, note string) (*models.TreasuryTransfer, error) {
+	var transfer *models.TreasuryTransfer
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		t, err := r.review(tx, id, reviewerID, note, models.TreasuryTransferExecuted)
+		if err != nil {
+			return err
+		}
+		transfer = t
+
+		// Lock both accounts in a fixed order so opposite transfers can't deadlock
+		first, second := t.FromAccountCode, t.ToAccountCode
+		if second < first {
+			first, second = second, first
+		}
+		var locked []models.SystemAccount
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code IN ?", []string{first, second}).
+			Order("code").
+			Find(&locked).Error; err != nil {
+			return fmt.Errorf("failed to lock system accounts: %w", err)
+		}
+		for _, account := range locked {
+			if account.Code == t.FromAccountCode && account.Balance < t.Amount {
+				return ErrInsufficientSystemFunds
+			}
+		}
+
+		description := fmt.Sprintf("Treasury transfer #%d: %s", t.ID, t.Reason)
+		if err := models.PostSystemEntry(tx, t.FromAccountCode, &models.SystemLedgerEntry{
+			Kind:        models.SystemEntryTransferOut,
+			Amount:      -t.Amount,
+			TransferID:  &t.ID,
+			Description: description,
+		}); err != nil {
+			return err
+		}
+		return models.PostSystemEntry(tx, t.ToAccountCode, &models.SystemLedgerEntry{
+			Kind:        models.SystemEntryTransferIn,
+			Amount:      t.Amount,
+			TransferID:  &t.ID,
+			Description: description,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (r *treasuryRepository) RejectTransfer(id, reviewerID uint, note string) (*models.TreasuryTransfer, error) {
+	return r.review(r.db, id, reviewerID, note, models.TreasuryTransferRejected)
+}
+
+// review closes a pending transfer. The status condition makes sure two
+// reviewers acting at once can't both succeed.
+func (r *treasuryRepository) review(db *gorm.DB, id, reviewerID uint, note, status string) (*models.TreasuryTransfer, error) {
+	now := time.Now()
+	result := db.Model(&models.TreasuryTransfer{}).
+		Where("id = ? AND status = ?", id, models.TreasuryTransferPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"review_note": note,
+			"reviewed_at": now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update treasury transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := getTreasuryTransfer(db, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrTreasuryTransferNotOpen
+	}
+	return getTreasuryTransfer(db, id)
+}
+
+func getTreasuryTransfer(db *gorm.DB, id uint) (*models.TreasuryTransfer, error) {
+	var transfer models.TreasuryTransfer
+	if err := db.First(&transfer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTreasuryTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to get treasury transfer: %w", err)
+	}
+	return &transfer, nil
+}
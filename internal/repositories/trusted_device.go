@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTrustedDeviceNotFound        = errors.New("trusted device not found")
+	ErrTransferConfirmationNotFound = errors.New("transfer confirmation not found")
+	// ErrConfirmationNotPending is returned when a confirmation was
+	// decided or expired first
+	ErrConfirmationNotPending = errors.New("transfer confirmation is no longer pending")
+)
+
+// TrustedDeviceRepository persists users' trusted devices and the large
+// transfers waiting to be approved on them
+type TrustedDeviceRepository interface {
+	ListDevices(userID uint) ([]models.TrustedDevice, error)
+	GetDevice(userID uint, deviceID string) (*models.TrustedDevice, error)
+	// SaveDevice registers the device, or renames it and replaces its push
+	// token if it already is
+	SaveDevice(device *models.TrustedDevice) error
+	DeleteDevice(userID, id uint) error
+	TouchDevice(id uint, at time.Time) error
+
+	// Hold creates the transfer, awaiting confirmation, and its
+	// confirmation in one database transaction
+	Hold(tx *models.Transaction, confirmation *models.TransferConfirmation) error
+	// GetConfirmation returns the confirmation with its transfer
+	GetConfirmation(id uint) (*models.TransferConfirmation, error)
+	// ListPending returns the user's confirmations still waiting, with
+	// their transfers, soonest to expire first
+	ListPending(userID uint, now time.Time) ([]models.TransferConfirmation, error)
+	// Approve marks a pending confirmation approved from the device. The
+	// transfer is left for the caller to complete.
+	Approve(id uint, deviceID string, at time.Time) error
+	// Close ends a pending confirmation with status, setting its transfer's
+	// status to txStatus
+	Close(id uint, status, txStatus, deviceID string, at time.Time) error
+	// Fail records why an approved transfer couldn't complete
+	Fail(id uint, reason string) error
+	// FindExpired returns pending confirmations past their expiry
+	FindExpired(now time.Time, limit int) ([]models.TransferConfirmation, error)
+}
+
+type trustedDeviceRepository struct {
+	db *gorm.DB
+}
+
+func NewTrustedDeviceRepository(db *gorm.DB) TrustedDeviceRepository {
+	return &trustedDeviceRepository{db: db}
+}
+
+func (r *trustedDeviceRepository) ListDevices(userID uint) ([]models.TrustedDevice, error) {
+	var devices []models.TrustedDevice
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to list trusted devices: %w", err)
+	}
+	return devices, nil
+}
+
+func (r *trustedDeviceRepository) GetDevice(userID uint, deviceID string) (*models.TrustedDevice, error) {
+	var device models.TrustedDevice
+	if err := r.db.Where("user_id = ? AND device_id = ?", userID, deviceID).First(&device).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTrustedDeviceNotFound
+		}
+		return nil, fmt.Errorf("failed to get trusted device: %w", err)
+	}
+	return &device, nil
+}
+
+func (r *trustedDeviceRepository) SaveDevice(device *models.TrustedDevice) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "device_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "push_token"}),
+	}).Create(device).Error
+	if err != nil {
+		return fmt.Errorf("failed to save trusted device: %w", err)
+	}
+	return nil
+}
+
+func (r *trustedDeviceRepository) DeleteDevice(userID, id uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.TrustedDevice{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete trusted device: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTrustedDeviceNotFound
+	}
+	return nil
+}
+
+func (r *trustedDeviceRepository) TouchDevice(id uint, at time.Time) error {
+	err := r.db.Model(&models.TrustedDevice{}).Where("id = ?", id).Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to update trusted device: %w", err)
+	}
+	return nil
+}
+
+func (r *trustedDeviceRepository) Hold(tx *models.Transaction, confirmation *models.TransferConfirmation) error {
+	return r.db.Transaction(func(dbTx *gorm.DB) error {
+		if err := dbTx.Create(tx).Error; err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+		confirmation.TransactionID = tx.ID
+		if err := dbTx.Create(confirmation).Error; err != nil {
+			return fmt.Errorf("failed to create transfer confirmation: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *trustedDeviceRepository) GetConfirmation(id uint) (*models.TransferConfirmation, error) {
+	var confirmation models.TransferConfirmation
+	if err := r.db.Preload("Transaction").First(&confirmation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransferConfirmationNotFound
+		}
+		return nil, fmt.Errorf("failed to get transfer confirmation: %w", err)
+	}
+	return &confirmation, nil
+}
+
+func (r *trustedDeviceRepository) ListPending(userID uint, now time.Time) ([]models.TransferConfirmation, error) {
+	var confirmations []models.TransferConfirmation
+	err := r.db.Preload("Transaction").
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, models.TransferConfirmationPending, now).
+		Order("expires_at ASC").
+		Find(&confirmations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer confirmations: %w", err)
+	}
+	return confirmations, nil
+}
+
+func (r *trustedDeviceRepository) Approve(id uint, deviceID string, at time.Time) error {
+	result := r.db.Model(&models.TransferConfirmation{}).
+		Where("id = ? AND status = ?", id, models.TransferConfirmationPending).
+		Updates(map[string]interface{}{
+			"status":     models.TransferConfirmationApproved,
+			"device_id":  deviceID,
+			"decided_at": at,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to approve transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrConfirmationNotPending
+	}
+	return nil
+}
+
+func (r *trustedDeviceRepository) Close(id uint, status, txStatus, deviceID string, at time.Time) error {
+	return r.db.Transaction(func(dbTx *gorm.DB) error {
+		var confirmation models.TransferConfirmation
+		err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", id, models.TransferConfirmationPending).
+			First(&confirmation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrConfirmationNotPending
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock transfer confirmation: %w", err)
+		}
+
+		updates := map[string]interface{}{"status": status, "decided_at": at}
+		if deviceID != "" {
+			updates["device_id"] = deviceID
+		}
+		if err := dbTx.Model(&confirmation).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to close transfer confirmation: %w", err)
+		}
+		err = dbTx.Model(&models.Transaction{}).
+			Where("id = ? AND status = ?", confirmation.TransactionID, models.TransactionStatusAwaitingConfirmation).
+			Update("status", txStatus).Error
+		if err != nil {
+			return fmt.Errorf("failed to update transfer: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *trustedDeviceRepository) Fail(id uint, reason string) error {
+	return r.db.Transaction(func(dbTx *gorm.DB) error {
+		var confirmation models.TransferConfirmation
+		if err := dbTx.First(&confirmation, id).Error; err != nil {
+			return fmt.Errorf("failed to get transfer confirmation: %w", err)
+		}
+		err := dbTx.Model(&confirmation).Updates(map[string]interface{}{
+			"status":         models.TransferConfirmationFailed,
+			"failure_reason": reason,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to record transfer failure: %w", err)
+		}
+		err = dbTx.Model(&models.Transaction{}).
+			Where("id = ? AND status = ?", confirmation.TransactionID, models.TransactionStatusAwaitingConfirmation).
+			Update("status", "failed").Error
+		if err != nil {
+			return fmt.Errorf("failed to update transfer: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *trustedDeviceRepository) FindExpired(now time.Time, limit int) ([]models.TransferConfirmation, error) {
+	var confirmations []models.TransferConfirmation
+	err := r.db.Where("status = ? AND expires_at <= ?", models.TransferConfirmationPending, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&confirmations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired transfer confirmations: %w", err)
+	}
+	return confirmations, nil
+}
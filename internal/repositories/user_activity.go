@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserActivityRepository persists the account events shown on the admin
+// activity timeline
+type UserActivityRepository interface {
+	Record(activity *models.UserActivity) error
+	// ListByUser returns up to limit of the user's events from before
+	// before, newest first
+	ListByUser(userID uint, before time.Time, limit int) ([]models.UserActivity, error)
+}
+
+type userActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserActivityRepository(db *gorm.DB) UserActivityRepository {
+	return &userActivityRepository{db: db}
+}
+
+func (r *userActivityRepository) Record(activity *models.UserActivity) error {
+	if err := r.db.Create(activity).Error; err != nil {
+		return fmt.Errorf("failed to record user activity: %w", err)
+	}
+	return nil
+}
+
+func (r *userActivityRepository) ListByUser(userID uint, before time.Time, limit int) ([]models.UserActivity, error) {
+	var activities []models.UserActivity
+	err := r.db.Where("user_id = ? AND created_at < ?", userID, before).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&activities).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user activity: %w", err)
+	}
+	return activities, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrUserIdentityNotFound = errors.New("user identity not found")
+
+// UserIdentityRepository persists the social login accounts users sign in
+// with
+type UserIdentityRepository interface {
+	Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.UserIdentity, error)
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	// Touch records a sign-in with the identity and the email the
+	// provider vouched for
+	Touch(ctx context.Context, id uint, email string, at time.Time) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserIdentityNotFound
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID uint) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+	return identities, nil
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	if err := r.db.WithContext(ctx).Create(identity).Error; err != nil {
+		return fmt.Errorf("failed to link user identity: %w", err)
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) Touch(ctx context.Context, id uint, email string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.UserIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"email": email, "last_login_at": at}).Error
+	if err != nil {
+		return fmt.Errorf("failed to touch user identity: %w", err)
+	}
+	return nil
+}
@@ -3,12 +3,14 @@ package repositories
 import (
 	"errors"
 	"orus/internal/models"
+	"time"
 )
 
 var (
 	ErrUserNotFound      = errors.New("user not found")
 	ErrEmailTaken        = errors.New("email already taken")
 	ErrPhoneTaken        = errors.New("phone number already taken")
+	ErrHandleTaken       = errors.New("handle already taken")
 	ErrInvalidUserData   = errors.New("invalid user data")
 	ErrDatabaseOperation = errors.New("database operation failed")
 )
@@ -21,12 +23,25 @@ type UserRepository interface {
 	// GetByID retrieves a user by their ID
 	GetByID(id uint) (*models.User, error)
 
+	// GetByIDs retrieves the users with the given IDs, in no particular
+	// order; IDs without a user are skipped
+	GetByIDs(ids []uint) ([]*models.User, error)
+
 	// GetByEmail retrieves a user by their email address
 	GetByEmail(email string) (*models.User, error)
 
 	// GetByPhone retrieves a user by their phone number
 	GetByPhone(phone string) (*models.User, error)
 
+	// GetByHandle retrieves a user by their payment handle
+	GetByHandle(handle string) (*models.User, error)
+
+	// SetHandle claims handle for the user, or releases theirs when nil
+	SetHandle(userID uint, handle *string) error
+
+	// UpdateDiscoverability controls whether others can find the user by handle or phone
+	UpdateDiscoverability(userID uint, byHandle, byPhone bool) error
+
 	// Update updates an existing user's information
 	Update(user *models.User) error
 
@@ -36,6 +51,10 @@ type UserRepository interface {
 	// IncrementTokenVersion increments the user's token version
 	IncrementTokenVersion(userID uint) error
 
+	// IncrementRoleTokenVersions increments the token version of every user
+	// holding role. Callers invalidate the role's cache entries.
+	IncrementRoleTokenVersions(role string) error
+
 	// List retrieves users with pagination
 	List(offset, limit int) ([]*models.User, int64, error)
 
@@ -45,8 +64,9 @@ type UserRepository interface {
 	// UpdateStatus updates the user's status
 	UpdateStatus(userID uint, status string) error
 
-	GetBalance(userID uint) (float64, error)
-	UpdateBalance(userID uint, newBalance float64) error
+	// SetSuspension suspends the user, or reinstates them when suspendedAt
+	// is nil. Either way it bumps the token version so existing sessions end.
+	SetSuspension(userID uint, suspendedAt *time.Time, reason string) error
 }
 
 // Implementation will be in user_repository_impl.go
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"orus/internal/repositories/cache"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -45,25 +46,44 @@ func (r *userRepository) GetByID(id uint) (*models.User, error) {
 	}
 
 	log.Printf("Cache miss for user ID: %d, querying database", id)
-	// Cache miss - proceed to database
-	var user models.User
-	if err := r.db.First(&user, id).Error; err != nil {
-		log.Printf("Database error for user ID %d: %v", id, err)
-		if err == gorm.ErrRecordNotFound {
-			return nil, ErrUserNotFound
+	// Cache miss - concurrent misses for the same user share one query
+	loaded, err := r.cache.Coalesce(key, func() (interface{}, error) {
+		var user models.User
+		if err := r.db.First(&user, id).Error; err != nil {
+			log.Printf("Database error for user ID %d: %v", id, err)
+			if err == gorm.ErrRecordNotFound {
+				return nil, ErrUserNotFound
+			}
+			return nil, err
 		}
-		return nil, err
-	}
 
-	log.Printf("Found user in database: ID=%d, Email=%s", user.ID, user.Email)
-	// Cache the result
-	if err := r.cache.CacheUser(context.Background(), &user); err != nil {
-		log.Printf("Failed to cache user: %v", err)
+		log.Printf("Found user in database: ID=%d, Email=%s", user.ID, user.Email)
+		// Cache the result
+		if err := r.cache.CacheUser(context.Background(), &user); err != nil {
+			log.Printf("Failed to cache user: %v", err)
+		}
+		return &user, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	// Callers may modify the user, so each gets its own copy
+	user := *loaded.(*models.User)
 	return &user, nil
 }
 
+func (r *userRepository) GetByIDs(ids []uint) ([]*models.User, error) {
+	var users []*models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	var user models.User
 	result := r.db.Where("email = ?", email).First(&user)
@@ -77,6 +97,10 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 }
 
 func (r *userRepository) GetByPhone(phone string) (*models.User, error) {
+	// Users who signed up with a social login have no phone number
+	if phone == "" {
+		return nil, ErrUserNotFound
+	}
 	var user models.User
 	result := r.db.Where("phone = ?", phone).First(&user)
 	if result.Error != nil {
@@ -88,6 +112,56 @@ func (r *userRepository) GetByPhone(phone string) (*models.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) GetByHandle(handle string) (*models.User, error) {
+	var user models.User
+	result := r.db.Where("handle = ?", handle).First(&user)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrDatabaseOperation
+	}
+	return &user, nil
+}
+
+func (r *userRepository) SetHandle(userID uint, handle *string) error {
+	if handle != nil {
+		if owner, err := r.GetByHandle(*handle); err == nil && owner.ID != userID {
+			return ErrHandleTaken
+		}
+	}
+
+	if err := r.db.Model(&models.User{}).Where("id = ?", userID).Update("handle", handle).Error; err != nil {
+		// Lost a race for the same handle to the unique index
+		if handle != nil {
+			if owner, lookupErr := r.GetByHandle(*handle); lookupErr == nil && owner.ID != userID {
+				return ErrHandleTaken
+			}
+		}
+		return ErrDatabaseOperation
+	}
+
+	if err := r.cache.InvalidateUser(context.Background(), userID); err != nil {
+		log.Printf("Warning: Failed to invalidate user cache: %v", err)
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateDiscoverability(userID uint, byHandle, byPhone bool) error {
+	err := r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"handle_discoverable": byHandle,
+		"phone_discoverable":  byPhone,
+	}).Error
+	if err != nil {
+		return ErrDatabaseOperation
+	}
+
+	if err := r.cache.InvalidateUser(context.Background(), userID); err != nil {
+		log.Printf("Warning: Failed to invalidate user cache: %v", err)
+	}
+	return nil
+}
+
 func (r *userRepository) Update(user *models.User) error {
 	result := r.db.Save(user)
 	if result.Error != nil {
@@ -111,6 +185,10 @@ func (r *userRepository) Delete(id uint) error {
 	if result.RowsAffected == 0 {
 		return ErrUserNotFound
 	}
+
+	if err := r.cache.InvalidateUser(context.Background(), id); err != nil {
+		log.Printf("Warning: Failed to invalidate user cache: %v", err)
+	}
 	return nil
 }
 
@@ -123,7 +201,7 @@ func (r *userRepository) IncrementTokenVersion(userID uint) error {
 
 	// Add debug logging
 	log.Printf("Invalidating cache for user ID: %d", userID)
-	if err := InvalidateUserCache(userID); err != nil {
+	if err := r.cache.InvalidateUser(context.Background(), userID); err != nil {
 		log.Printf("Cache invalidation error: %v", err)
 	}
 
@@ -131,17 +209,40 @@ func (r *userRepository) IncrementTokenVersion(userID uint) error {
 	return nil
 }
 
+func (r *userRepository) IncrementRoleTokenVersions(role string) error {
+	var userIDs []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("role = ?", role).Pluck("id", &userIDs).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("role = ?", role).
+			UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+	})
+	if err != nil {
+		return ErrDatabaseOperation
+	}
+
+	// Drop the sessions in batches so a large role doesn't make one huge DEL
+	for start := 0; start < len(userIDs); start += 500 {
+		end := min(start+500, len(userIDs))
+		if err := r.cache.InvalidateSessions(context.Background(), userIDs[start:end]...); err != nil {
+			log.Printf("Warning: Failed to invalidate sessions of role %s: %v", role, err)
+		}
+	}
+	return nil
+}
+
 func (r *userRepository) List(offset, limit int) ([]*models.User, int64, error) {
 	var users []*models.User
 	var total int64
 
 	// Get total count
-	if err := r.db.Model(&models.User{}).Count(&total).Error; err != nil {
+	if err := r.db.Scopes(Replica(0)).Model(&models.User{}).Count(&total).Error; err != nil {
 		return nil, 0, ErrDatabaseOperation
 	}
 
 	// Get users with pagination
-	result := r.db.Offset(offset).Limit(limit).Find(&users)
+	result := r.db.Scopes(Replica(0)).Offset(offset).Limit(limit).Find(&users)
 	if result.Error != nil {
 		return nil, 0, ErrDatabaseOperation
 	}
@@ -159,6 +260,10 @@ func (r *userRepository) UpdatePassword(userID uint, hashedPassword string) erro
 	if result.RowsAffected == 0 {
 		return ErrUserNotFound
 	}
+
+	if err := r.cache.InvalidateUser(context.Background(), userID); err != nil {
+		log.Printf("Warning: Failed to invalidate user cache: %v", err)
+	}
 	return nil
 }
 
@@ -172,5 +277,36 @@ func (r *userRepository) UpdateStatus(userID uint, status string) error {
 	if result.RowsAffected == 0 {
 		return ErrUserNotFound
 	}
+
+	if err := r.cache.InvalidateUser(context.Background(), userID); err != nil {
+		log.Printf("Warning: Failed to invalidate user cache: %v", err)
+	}
+	return nil
+}
+
+func (r *userRepository) SetSuspension(userID uint, suspendedAt *time.Time, reason string) error {
+	status := models.UserStatusActive
+	if suspendedAt != nil {
+		status = models.UserStatusSuspended
+	}
+
+	result := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"status":            status,
+			"suspended_at":      suspendedAt,
+			"suspension_reason": reason,
+			"token_version":     gorm.Expr("token_version + 1"),
+		})
+	if result.Error != nil {
+		return ErrDatabaseOperation
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	if err := r.cache.InvalidateUser(context.Background(), userID); err != nil {
+		log.Printf("Warning: Failed to invalidate user cache: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVirtualCardNotFound     = errors.New("virtual card not found")
+	ErrCardTransactionNotFound = errors.New("card transaction not found")
+)
+
+// VirtualCardRepository persists issued virtual cards and their spend
+type VirtualCardRepository interface {
+	Create(card *models.VirtualCard) error
+	GetByIDAndUserID(id, userID uint) (*models.VirtualCard, error)
+	GetByProviderCardID(providerCardID string) (*models.VirtualCard, error)
+	GetByUserID(userID uint) ([]models.VirtualCard, error)
+	Update(card *models.VirtualCard) error
+	GetTransactions(cardID uint, limit, offset int) ([]models.Transaction, int64, error)
+	GetTransactionByReference(cardID uint, reference string) (*models.Transaction, error)
+	SumSpent(cardID uint, since time.Time) (float64, error)
+}
+
+type virtualCardRepository struct {
+	db *gorm.DB
+}
+
+func NewVirtualCardRepository(db *gorm.DB) VirtualCardRepository {
+	return &virtualCardRepository{db: db}
+}
+
+func (r *virtualCardRepository) Create(card *models.VirtualCard) error {
+	if err := r.db.Create(card).Error; err != nil {
+		return fmt.Errorf("failed to create virtual card: %w", err)
+	}
+	return nil
+}
+
+func (r *virtualCardRepository) GetByIDAndUserID(id, userID uint) (*models.VirtualCard, error) {
+	var card models.VirtualCard
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVirtualCardNotFound
+		}
+		return nil, fmt.Errorf("failed to get virtual card: %w", err)
+	}
+	return &card, nil
+}
+
+func (r *virtualCardRepository) GetByProviderCardID(providerCardID string) (*models.VirtualCard, error) {
+	var card models.VirtualCard
+	if err := r.db.Where("provider_card_id = ?", providerCardID).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVirtualCardNotFound
+		}
+		return nil, fmt.Errorf("failed to get virtual card: %w", err)
+	}
+	return &card, nil
+}
+
+func (r *virtualCardRepository) GetByUserID(userID uint) ([]models.VirtualCard, error) {
+	var cards []models.VirtualCard
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&cards).Error; err != nil {
+		return nil, fmt.Errorf("failed to get virtual cards: %w", err)
+	}
+	return cards, nil
+}
+
+func (r *virtualCardRepository) Update(card *models.VirtualCard) error {
+	if err := r.db.Save(card).Error; err != nil {
+		return fmt.Errorf("failed to update virtual card: %w", err)
+	}
+	return nil
+}
+
+func (r *virtualCardRepository) GetTransactions(cardID uint, limit, offset int) ([]models.Transaction, int64, error) {
+	var transactions []models.Transaction
+	var total int64
+
+	query := r.db.Model(&models.Transaction{}).Where("virtual_card_id = ?", cardID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count card transactions: %w", err)
+	}
+
+	err := query.Order("processed_at DESC").Limit(limit).Offset(offset).Find(&transactions).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get card transactions: %w", err)
+	}
+	return transactions, total, nil
+}
+
+func (r *virtualCardRepository) GetTransactionByReference(cardID uint, reference string) (*models.Transaction, error) {
+	var tx models.Transaction
+	err := r.db.Where("virtual_card_id = ? AND reference = ?", cardID, reference).First(&tx).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCardTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get card transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+func (r *virtualCardRepository) SumSpent(cardID uint, since time.Time) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.Transaction{}).
+		Where("virtual_card_id = ? AND status = ? AND created_at >= ?", cardID, "completed", since).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum card spend: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrWalletLockNotFound = errors.New("wallet has no active lock")
+
+// WalletLockRepository places and lifts wallet locks, keeping the wallet's
+// status in step with its active lock, and records the audit trail
+type WalletLockRepository interface {
+	// Lock locks the wallet, lifting any lock it already has
+	Lock(ctx context.Context, lock *models.WalletLock) error
+	// Release lifts the wallet's active lock, recording event as why. It
+	// returns ErrWalletLockNotFound if the wallet isn't locked.
+	Release(ctx context.Context, walletID uint, releasedBy *uint, event string) (*models.WalletLock, error)
+	// Expire lifts the lock if it is still active and past its expiry, so a
+	// lock placed in its stead is left alone
+	Expire(ctx context.Context, lockID uint, now time.Time) (*models.WalletLock, error)
+	GetActive(ctx context.Context, walletID uint) (*models.WalletLock, error)
+	// GetExpired returns active locks past their expiry, oldest first
+	GetExpired(ctx context.Context, now time.Time, limit int) ([]models.WalletLock, error)
+
+	// RecordEvent adds to the audit trail outside any transaction of the
+	// caller's, so a refusal is kept when the caller rolls back
+	RecordEvent(ctx context.Context, event *models.WalletLockEvent) error
+	ListEvents(ctx context.Context, walletID uint, limit, offset int) ([]models.WalletLockEvent, int64, error)
+}
+
+type walletLockRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletLockRepository(db *gorm.DB) WalletLockRepository {
+	return &walletLockRepository{db: db}
+}
+
+func (r *walletLockRepository) Lock(ctx context.Context, lock *models.WalletLock) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		previous, err := releaseWalletLock(tx, lock.LockedBy, now, "wallet_id = ?", lock.WalletID)
+		if err != nil && !errors.Is(err, ErrWalletLockNotFound) {
+			return err
+		}
+		if previous != nil {
+			if err := tx.Create(lockEvent(previous, models.WalletLockEventReleased, lock.LockedBy)).Error; err != nil {
+				return fmt.Errorf("failed to record wallet lock event: %w", err)
+			}
+		}
+
+		if err := tx.Create(lock).Error; err != nil {
+			return fmt.Errorf("failed to create wallet lock: %w", err)
+		}
+		res := tx.Model(&models.Wallet{}).Where("id = ?", lock.WalletID).Updates(map[string]interface{}{
+			"status":        "locked",
+			"status_reason": lock.Reason,
+			"lock_scope":    lock.Scope,
+			"locked_until":  lock.ExpiresAt,
+		})
+		if res.Error != nil {
+			return fmt.Errorf("failed to lock wallet: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrWalletNotFound
+		}
+		if err := tx.Create(lockEvent(lock, models.WalletLockEventLocked, lock.LockedBy)).Error; err != nil {
+			return fmt.Errorf("failed to record wallet lock event: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *walletLockRepository) Release(ctx context.Context, walletID uint, releasedBy *uint, event string) (*models.WalletLock, error) {
+	return r.release(ctx, releasedBy, event, time.Now(), "wallet_id = ?", walletID)
+}
+
+func (r *walletLockRepository) Expire(ctx context.Context, lockID uint, now time.Time) (*models.WalletLock, error) {
+	return r.release(ctx, nil, models.WalletLockEventExpired, now, "id = ? AND expires_at <= ?", lockID, now)
+}
+
+// release lifts the active lock matching cond and reactivates its wallet
+func (r *walletLockRepository) release(ctx context.Context, releasedBy *uint, event string, now time.Time, cond string, args ...interface{}) (*models.WalletLock, error) {
+	var lock *models.WalletLock
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if lock, err = releaseWalletLock(tx, releasedBy, now, cond, args...); err != nil {
+			return err
+		}
+		err = tx.Model(&models.Wallet{}).Where("id = ?", lock.WalletID).Updates(map[string]interface{}{
+			"status":        "active",
+			"status_reason": "",
+			"lock_scope":    "",
+			"locked_until":  nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to unlock wallet: %w", err)
+		}
+		if err := tx.Create(lockEvent(lock, event, releasedBy)).Error; err != nil {
+			return fmt.Errorf("failed to record wallet lock event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// releaseWalletLock marks the active lock matching cond released, leaving
+// the wallet itself to the caller
+func releaseWalletLock(db *gorm.DB, releasedBy *uint, now time.Time, cond string, args ...interface{}) (*models.WalletLock, error) {
+	var lock models.WalletLock
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(cond, args...).
+		Where("released_at IS NULL").
+		First(&lock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWalletLockNotFound
+		}
+		return nil, fmt.Errorf("failed to get wallet lock: %w", err)
+	}
+
+	err = db.Model(&lock).Updates(map[string]interface{}{
+		"released_at": now,
+		"released_by": releasedBy,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to release wallet lock: %w", err)
+	}
+	lock.ReleasedAt = &now
+	lock.ReleasedBy = releasedBy
+	return &lock, nil
+}
+
+func lockEvent(lock *models.WalletLock, event string, actorID *uint) *models.WalletLockEvent {
+	return &models.WalletLockEvent{
+		WalletID: lock.WalletID,
+		LockID:   &lock.ID,
+		Event:    event,
+		ActorID:  actorID,
+	}
+}
+
+func (r *walletLockRepository) GetActive(ctx context.Context, walletID uint) (*models.WalletLock, error) {
+	var lock models.WalletLock
+	err := r.db.WithContext(ctx).Where("wallet_id = ? AND released_at IS NULL", walletID).First(&lock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWalletLockNotFound
+		}
+		return nil, fmt.Errorf("failed to get wallet lock: %w", err)
+	}
+	return &lock, nil
+}
+
+func (r *walletLockRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]models.WalletLock, error) {
+	var locks []models.WalletLock
+	err := r.db.WithContext(ctx).
+		Where("released_at IS NULL AND expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&locks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired wallet locks: %w", err)
+	}
+	return locks, nil
+}
+
+func (r *walletLockRepository) RecordEvent(ctx context.Context, event *models.WalletLockEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record wallet lock event: %w", err)
+	}
+	return nil
+}
+
+func (r *walletLockRepository) ListEvents(ctx context.Context, walletID uint, limit, offset int) ([]models.WalletLockEvent, int64, error) {
+	var events []models.WalletLockEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.WalletLockEvent{}).Where("wallet_id = ?", walletID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count wallet lock events: %w", err)
+	}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list wallet lock events: %w", err)
+	}
+	return events, total, nil
+}
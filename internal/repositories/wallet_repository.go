@@ -13,17 +13,56 @@ var (
 	ErrDuplicateWallet    = errors.New("wallet already exists")
 	ErrTransactionFailed  = errors.New("transaction failed")
 	ErrInvalidTransaction = errors.New("invalid transaction")
+	ErrHoldNotFound       = errors.New("wallet hold not found")
+	ErrOverdraftExceeded  = errors.New("balance would exceed the wallet's overdraft limit")
+	// ErrInsufficientBalance refuses a debit the overdraft limit allows but
+	// which would spend funds on hold
+	ErrInsufficientBalance = errors.New("insufficient available balance")
 )
 
 // WalletRepository defines the interface for wallet-related database operations
 type WalletRepository interface {
+	// WithContext returns the repository with every query bound to ctx, so
+	// they're cancelled with it
+	WithContext(ctx context.Context) WalletRepository
+
 	// Core wallet operations
 	Create(wallet *models.Wallet) error
 	GetByID(id uint) (*models.Wallet, error)
 	GetByUserID(userID uint) (*models.Wallet, error)
+	// Update saves the wallet except its balance, which only moves through
+	// TransferFunds and AdjustBalance and their forced variants
 	Update(wallet *models.Wallet) error
 	Delete(id uint) error
 
+	// TransferFunds moves amount from one user's wallet to the other's in
+	// one database transaction, both rows locked in user order so
+	// concurrent transfers can't deadlock. The sender may go below zero
+	// only as far as their overdraft limit, else ErrOverdraftExceeded, and
+	// may not spend funds on hold, else ErrInsufficientBalance. It returns
+	// both wallets after the move; call it within ExecuteInTransaction to
+	// record the move atomically with it. The sender also pays fee, which
+	// the recipient doesn't get; record it on the transaction so it is
+	// booked as platform revenue.
+	TransferFunds(fromUserID, toUserID uint, amount, fee float64) (from, to *models.Wallet, err error)
+	// ForceTransferFunds is TransferFunds without the balance checks, for
+	// debits the sender can't refuse such as lost chargebacks
+	ForceTransferFunds(fromUserID, toUserID uint, amount float64) (from, to *models.Wallet, err error)
+	// LockByIDs reads the wallets with ids locked for update, in user order
+	// like TransferFunds so the two can't deadlock, and returns them by ID.
+	// Call it within ExecuteInTransaction; the locks last until it commits.
+	LockByIDs(ids ...uint) (map[uint]*models.Wallet, error)
+	// LockByUserID reads the user's wallet locked for update, for checks
+	// such as the wallet's lock that must hold until its balance moves
+	LockByUserID(userID uint) (*models.Wallet, error)
+	// AdjustBalance adds delta to the user's balance with the row locked,
+	// writing only the balance, and returns the wallet after. A debit is
+	// checked like TransferFunds checks the sender's.
+	AdjustBalance(userID uint, delta float64) (*models.Wallet, error)
+	// ForceAdjustBalance is AdjustBalance without the balance checks, for
+	// debits the user can't refuse such as returned deposits
+	ForceAdjustBalance(userID uint, delta float64) (*models.Wallet, error)
+
 	// Transaction operations
 	CreateTransaction(tx *models.Transaction) error
 	GetTransactionByID(id uint) (*models.Transaction, error)
@@ -31,6 +70,12 @@ type WalletRepository interface {
 	GetDailyTransactionTotal(ctx context.Context, userID uint, start, end time.Time, txType string, total *float64) error
 	GetMonthlyTransactionTotal(ctx context.Context, userID uint, start, end time.Time, txType string, total *float64) error
 
+	// Hold operations
+	CreateHold(hold *models.WalletHold) error
+	GetHoldByID(id uint) (*models.WalletHold, error)
+	GetActiveHolds(walletID uint) ([]models.WalletHold, error)
+	UpdateHold(hold *models.WalletHold) error
+
 	// Batch operations
 	ExecuteInTransaction(fn func(WalletRepository) error) error
 	BulkCreate(wallets []*models.Wallet) error
@@ -39,6 +84,15 @@ type WalletRepository interface {
 	// Status operations
 	UpdateStatus(walletID uint, status string) error
 	GetWalletsByStatus(status string) ([]*models.Wallet, error)
+	List(limit, offset int) ([]models.Wallet, int64, error)
+
+	// Overdraft operations
+	SetOverdraftLimit(walletID uint, limit float64) error
+	// GetNegative returns the wallets with a negative balance, most
+	// overdrawn first, and how many there are
+	GetNegative(limit, offset int) ([]models.Wallet, int64, error)
+	// GetNegativeTotal sums how far below zero the wallets are
+	GetNegativeTotal() (float64, error)
 
 	// Analytics and reporting
 	GetTotalBalance() (float64, error)
@@ -2,11 +2,14 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"orus/internal/models"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type walletRepository struct {
@@ -19,6 +22,10 @@ func NewWalletRepository(db *gorm.DB) WalletRepository {
 	}
 }
 
+func (r *walletRepository) WithContext(ctx context.Context) WalletRepository {
+	return &walletRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *walletRepository) Create(wallet *models.Wallet) error {
 	result := r.db.Create(wallet)
 	if result.Error != nil {
@@ -50,7 +57,10 @@ func (r *walletRepository) GetByUserID(userID uint) (*models.Wallet, error) {
 }
 
 func (r *walletRepository) Update(wallet *models.Wallet) error {
-	result := r.db.Save(wallet)
+	// The balance only moves under a row lock, the limit only through
+	// SetOverdraftLimit, and the status and lock through UpdateStatus and
+	// the lock repository, so saving a stale copy can't undo any of them
+	result := r.db.Omit("balance", "overdraft_limit", "status", "status_reason", "lock_scope", "locked_until").Save(wallet)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update wallet: %w", result.Error)
 	}
@@ -68,6 +78,153 @@ func (r *walletRepository) Delete(id uint) error {
 	return nil
 }
 
+func (r *walletRepository) TransferFunds(fromUserID, toUserID uint, amount, fee float64) (*models.Wallet, *models.Wallet, error) {
+	return r.transfer(fromUserID, toUserID, amount, fee, true)
+}
+
+func (r *walletRepository) ForceTransferFunds(fromUserID, toUserID uint, amount float64) (*models.Wallet, *models.Wallet, error) {
+	return r.transfer(fromUserID, toUserID, amount, 0, false)
+}
+
+func (r *walletRepository) transfer(fromUserID, toUserID uint, amount, fee float64, checked bool) (*models.Wallet, *models.Wallet, error) {
+	if amount <= 0 || fee < 0 || fromUserID == toUserID {
+		return nil, nil, ErrInvalidTransaction
+	}
+
+	var from, to *models.Wallet
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var wallets []models.Wallet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id IN ?", []uint{fromUserID, toUserID}).
+			Order("user_id").
+			Find(&wallets).Error
+		if err != nil {
+			return fmt.Errorf("failed to lock wallets: %w", err)
+		}
+		for i := range wallets {
+			switch wallets[i].UserID {
+			case fromUserID:
+				from = &wallets[i]
+			case toUserID:
+				to = &wallets[i]
+			}
+		}
+		if from == nil || to == nil {
+			return ErrWalletNotFound
+		}
+
+		from.Balance = math.Round((from.Balance-amount-fee)*100) / 100
+		if checked {
+			if err := checkDebit(tx, from); err != nil {
+				return err
+			}
+		}
+		to.Balance = math.Round((to.Balance+amount)*100) / 100
+
+		// Only the balances are written, so nothing else on the rows is
+		// touched
+		for _, wallet := range []*models.Wallet{from, to} {
+			if err := tx.Model(wallet).Update("balance", wallet.Balance).Error; err != nil {
+				return fmt.Errorf("failed to update wallet: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+func (r *walletRepository) LockByIDs(ids ...uint) (map[uint]*models.Wallet, error) {
+	var wallets []models.Wallet
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("user_id").
+		Find(&wallets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock wallets: %w", err)
+	}
+	locked := make(map[uint]*models.Wallet, len(wallets))
+	for i := range wallets {
+		locked[wallets[i].ID] = &wallets[i]
+	}
+	return locked, nil
+}
+
+func (r *walletRepository) LockByUserID(userID uint) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&wallet).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("failed to lock wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+func (r *walletRepository) AdjustBalance(userID uint, delta float64) (*models.Wallet, error) {
+	return r.adjust(userID, delta, delta < 0)
+}
+
+func (r *walletRepository) ForceAdjustBalance(userID uint, delta float64) (*models.Wallet, error) {
+	return r.adjust(userID, delta, false)
+}
+
+func (r *walletRepository) adjust(userID uint, delta float64, checked bool) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		locked, err := (&walletRepository{db: tx}).LockByUserID(userID)
+		if err != nil {
+			return err
+		}
+		wallet = *locked
+		wallet.Balance = math.Round((wallet.Balance+delta)*100) / 100
+		if checked {
+			if err := checkDebit(tx, &wallet); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&wallet).Update("balance", wallet.Balance).Error; err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// debitRefused reports whether err is TransferFunds or AdjustBalance
+// refusing a debit the balance doesn't cover
+func debitRefused(err error) bool {
+	return errors.Is(err, ErrOverdraftExceeded) || errors.Is(err, ErrInsufficientBalance)
+}
+
+// checkDebit refuses a debit that leaves the locked wallet past its
+// overdraft limit, or spending funds its active holds reserve. Holds are
+// summed under the wallet's lock, which placing one takes too.
+func checkDebit(tx *gorm.DB, wallet *models.Wallet) error {
+	if wallet.Overdrawn() {
+		return ErrOverdraftExceeded
+	}
+	var held float64
+	err := tx.Model(&models.WalletHold{}).
+		Where("wallet_id = ? AND status = ? AND (expires_at IS NULL OR expires_at > ?)",
+			wallet.ID, models.HoldStatusActive, time.Now()).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&held).Error
+	if err != nil {
+		return fmt.Errorf("failed to sum wallet holds: %w", err)
+	}
+	if math.Round((wallet.Balance-held+wallet.OverdraftLimit)*100) < 0 {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
 func (r *walletRepository) CreateTransaction(tx *models.Transaction) error {
 	result := r.db.Create(tx)
 	if result.Error != nil {
@@ -124,6 +281,44 @@ func (r *walletRepository) GetMonthlyTransactionTotal(ctx context.Context, userI
 	return nil
 }
 
+func (r *walletRepository) CreateHold(hold *models.WalletHold) error {
+	if err := r.db.Create(hold).Error; err != nil {
+		return fmt.Errorf("failed to create wallet hold: %w", err)
+	}
+	return nil
+}
+
+func (r *walletRepository) GetHoldByID(id uint) (*models.WalletHold, error) {
+	var hold models.WalletHold
+	if err := r.db.First(&hold, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("failed to get wallet hold: %w", err)
+	}
+	return &hold, nil
+}
+
+func (r *walletRepository) GetActiveHolds(walletID uint) ([]models.WalletHold, error) {
+	var holds []models.WalletHold
+	err := r.db.
+		Where("wallet_id = ? AND status = ? AND (expires_at IS NULL OR expires_at > ?)",
+			walletID, models.HoldStatusActive, time.Now()).
+		Order("created_at ASC").
+		Find(&holds).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet holds: %w", err)
+	}
+	return holds, nil
+}
+
+func (r *walletRepository) UpdateHold(hold *models.WalletHold) error {
+	if err := r.db.Save(hold).Error; err != nil {
+		return fmt.Errorf("failed to update wallet hold: %w", err)
+	}
+	return nil
+}
+
 func (r *walletRepository) ExecuteInTransaction(fn func(WalletRepository) error) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		txRepo := &walletRepository{db: tx}
@@ -203,3 +398,50 @@ func (r *walletRepository) GetTransactionStats(start, end time.Time) (*Transacti
 	}
 	return &stats, nil
 }
+
+func (r *walletRepository) List(limit, offset int) ([]models.Wallet, int64, error) {
+	var wallets []models.Wallet
+	var total int64
+
+	if err := r.db.Model(&models.Wallet{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count wallets: %w", err)
+	}
+	if err := r.db.Limit(limit).Offset(offset).Find(&wallets).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	return wallets, total, nil
+}
+
+func (r *walletRepository) SetOverdraftLimit(walletID uint, limit float64) error {
+	result := r.db.Model(&models.Wallet{}).Where("id = ?", walletID).Update("overdraft_limit", limit)
+	if result.Error != nil {
+		return fmt.Errorf("failed to set overdraft limit: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWalletNotFound
+	}
+	return nil
+}
+
+func (r *walletRepository) GetNegative(limit, offset int) ([]models.Wallet, int64, error) {
+	var wallets []models.Wallet
+	var total int64
+
+	query := r.db.Model(&models.Wallet{}).Where("balance < 0")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count negative wallets: %w", err)
+	}
+	if err := query.Order("balance ASC, id ASC").Limit(limit).Offset(offset).Find(&wallets).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list negative wallets: %w", err)
+	}
+	return wallets, total, nil
+}
+
+func (r *walletRepository) GetNegativeTotal() (float64, error) {
+	var total float64
+	err := r.db.Model(&models.Wallet{}).Where("balance < 0").Select("COALESCE(SUM(-balance), 0)").Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum negative balances: %w", err)
+	}
+	return total, nil
+}
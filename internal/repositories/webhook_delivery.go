@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+// WebhookDeliveryRepository persists the outbound merchant webhook queue
+type WebhookDeliveryRepository interface {
+	Create(delivery *models.WebhookDelivery) error
+	// GetDue returns pending deliveries whose next attempt is due
+	GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// Claim pushes a due delivery's next attempt out to leaseUntil so other
+	// workers skip it while it is being sent. It returns false if another
+	// worker got there first.
+	Claim(delivery *models.WebhookDelivery, leaseUntil time.Time) (bool, error)
+	Update(delivery *models.WebhookDelivery) error
+	// GetByEventID returns one of the merchant's deliveries
+	GetByEventID(merchantUserID uint, eventID string) (*models.WebhookDelivery, error)
+	// ListByMerchant returns the merchant's deliveries, newest first,
+	// optionally by event type and status
+	ListByMerchant(merchantUserID uint, event, status string, limit, offset int) ([]models.WebhookDelivery, int64, error)
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Create(delivery *models.WebhookDelivery) error {
+	if err := r.db.Create(delivery).Error; err != nil {
+		return fmt.Errorf("failed to queue webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", models.WebhookStatusPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhooks: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) Claim(delivery *models.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, models.WebhookStatusPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+func (r *webhookDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	if err := r.db.Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) GetByEventID(merchantUserID uint, eventID string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Where("event_id = ? AND merchant_user_id = ?", eventID, merchantUserID).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &delivery, nil
+}
+
+func (r *webhookDeliveryRepository) ListByMerchant(merchantUserID uint, event, status string, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&models.WebhookDelivery{}).Where("merchant_user_id = ?", merchantUserID)
+	if event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhooks: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return deliveries, total, nil
+}
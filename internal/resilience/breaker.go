@@ -0,0 +1,198 @@
+// Package resilience keeps an unhealthy dependency, such as Redis or a
+// bank provider, from stalling the requests that use it. Circuit breakers
+// fail calls fast while the dependency is down and bounded retries ride
+// out brief blips.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling a dependency whose breaker
+// is open
+var ErrCircuitOpen = errors.New("dependency is unavailable, try again later")
+
+// State is where a breaker is in its cycle
+type State int
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = iota
+	// StateOpen fails every call until the cooldown ends
+	StateOpen
+	// StateHalfOpen lets one probe call through to test the dependency
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig tunes a breaker
+type BreakerConfig struct {
+	// FailureThreshold consecutive failures open the breaker
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before probing
+	Cooldown time.Duration
+	// IsFailure reports whether an error means the dependency is unhealthy.
+	// Nil counts every error except permanent ones and cancellations.
+	IsFailure func(error) bool
+}
+
+// DefaultBreakerConfig suits most network dependencies
+var DefaultBreakerConfig = BreakerConfig{
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+// Breaker is a circuit breaker guarding one dependency
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	trips    uint64
+	rejected uint64
+}
+
+// NewBreaker creates a closed breaker
+func NewBreaker(name string, cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultBreakerConfig.FailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultBreakerConfig.Cooldown
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isFailure
+	}
+	return &Breaker{name: name, cfg: cfg}
+}
+
+// Name identifies the dependency the breaker guards
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow reports whether a call may go ahead, returning ErrCircuitOpen if
+// not. Every allowed call must be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cfg.Cooldown {
+			b.rejected++
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		// Only one probe at a time; everyone else waits for its verdict
+		if b.probing {
+			b.rejected++
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record reports the outcome of an allowed call
+func (b *Breaker) Record(err error) {
+	failed := err != nil && b.cfg.IsFailure(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.probing = false
+		// A cancelled probe says nothing about the dependency; the next
+		// call probes again
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if failed {
+			b.open()
+		} else {
+			b.state = StateClosed
+			b.failures = 0
+		}
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == StateClosed && b.failures >= b.cfg.FailureThreshold {
+		b.open()
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.trips++
+}
+
+// Do runs fn if the breaker allows it and records the outcome
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	b.Record(err)
+	return err
+}
+
+// BreakerStats is a snapshot of a breaker for metrics
+type BreakerStats struct {
+	Name     string
+	State    State
+	Failures int
+	Trips    uint64
+	Rejected uint64
+}
+
+// Stats returns the breaker's current state and counters
+func (b *Breaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == StateOpen && time.Since(b.openedAt) >= b.cfg.Cooldown {
+		state = StateHalfOpen
+	}
+	return BreakerStats{
+		Name:     b.name,
+		State:    state,
+		Failures: b.failures,
+		Trips:    b.trips,
+		Rejected: b.rejected,
+	}
+}
+
+func isFailure(err error) bool {
+	return !IsPermanent(err) && !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,54 @@
+package resilience
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry collects the application's breakers so their state can be
+// reported in one place
+type Registry struct {
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{breakers: make(map[string]*Breaker)}
+}
+
+// Breaker returns the named breaker, creating it with cfg the first time
+func (r *Registry) Breaker(name string, cfg BreakerConfig) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	b := NewBreaker(name, cfg)
+	r.breakers[name] = b
+	return b
+}
+
+// Register adds a breaker created elsewhere
+func (r *Registry) Register(b *Breaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakers[b.Name()] = b
+}
+
+// Stats returns a snapshot of every breaker, sorted by name
+func (r *Registry) Stats() []BreakerStats {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	stats := make([]BreakerStats, len(breakers))
+	for i, b := range breakers {
+		stats[i] = b.Stats()
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
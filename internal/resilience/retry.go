@@ -0,0 +1,85 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Policy bounds how a call is retried
+type Policy struct {
+	// Attempts is the total number of tries, the first included
+	Attempts int
+	// BaseDelay is the wait before the first retry; it doubles each time
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between tries
+	MaxDelay time.Duration
+}
+
+// DefaultPolicy retries twice within about half a second, short enough to
+// stay inside a payment request
+var DefaultPolicy = Policy{
+	Attempts:  3,
+	BaseDelay: 100 * time.Millisecond,
+	MaxDelay:  400 * time.Millisecond,
+}
+
+// permanentError marks an error retrying can't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one retrying can't fix, such as a declined
+// transfer. It is neither retried nor counted against a breaker.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Retry calls fn until it succeeds, fails permanently, the breaker opens
+// or the policy's attempts run out, waiting with jittered exponential
+// backoff in between. Only retry calls that are safe to repeat.
+func Retry(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	attempts := max(policy.Attempts, 1)
+	delay := policy.BaseDelay
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt >= attempts || IsPermanent(err) || errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
+
+		// Full jitter keeps clients that failed together from retrying together
+		wait := time.Duration(rand.Int63n(int64(delay) + 1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, policy.MaxDelay)
+	}
+}
+
+// Call runs fn through the breaker, retrying per policy. Each try is
+// recorded, so a dependency that keeps failing opens the breaker and
+// stops the remaining tries.
+func Call(ctx context.Context, b *Breaker, policy Policy, fn func(ctx context.Context) error) error {
+	return Retry(ctx, policy, func(ctx context.Context) error {
+		return b.Do(ctx, fn)
+	})
+}
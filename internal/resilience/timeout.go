@@ -0,0 +1,53 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is an operation that ran past its deadline. It unwraps to
+// context.DeadlineExceeded.
+type TimeoutError struct {
+	Op      string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Timeout <= 0 {
+		return e.Op + " timed out"
+	}
+	return fmt.Sprintf("%s timed out after %s", e.Op, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// WithDeadline bounds ctx by timeout for the operation op. The returned
+// finish releases the deadline and, when the operation failed because it
+// passed, replaces its error with a *TimeoutError:
+//
+//	ctx, finish := resilience.WithDeadline(ctx, "wallet debit", timeout)
+//	defer finish(&err)
+//
+// A non-positive timeout leaves ctx's own deadline, if any, in charge.
+// Nested operations keep the innermost TimeoutError.
+func WithDeadline(ctx context.Context, op string, timeout time.Duration) (context.Context, func(*error)) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func(err *error) {
+		defer cancel()
+		if *err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		var timeoutErr *TimeoutError
+		if errors.As(*err, &timeoutErr) {
+			return
+		}
+		*err = &TimeoutError{Op: op, Timeout: timeout}
+	}
+}
@@ -4,123 +4,142 @@
 package routes
 
 import (
-	"orus/internal/config"
+	"orus/internal/container"
 	"orus/internal/handlers"
 	"orus/internal/middleware"
 	"orus/internal/models"
-	"orus/internal/repositories"
-	services "orus/internal/services"
-	"orus/internal/services/auth"
+	"orus/internal/services/accounting"
+	"orus/internal/services/apiaccess"
+	"orus/internal/services/autotopup"
+	"orus/internal/services/bulk"
+	"orus/internal/services/category"
+	"orus/internal/services/compliance"
 	creditcard "orus/internal/services/credit-card"
-	"orus/internal/services/dashboard"
-	"orus/internal/services/dispute"
-	"orus/internal/services/merchant"
-	"orus/internal/services/notification"
-	"orus/internal/services/payment"
-	qr "orus/internal/services/qr_code"
-	"orus/internal/services/transaction"
-	"orus/internal/services/transfer"
-	"orus/internal/services/user"
-	"orus/internal/services/wallet"
+	"orus/internal/services/deviceconfirm"
+	disputesvc "orus/internal/services/dispute"
+	"orus/internal/services/featureflag"
+	"orus/internal/services/maintenance"
+	merchantsvc "orus/internal/services/merchant"
+	"orus/internal/services/merchantrisk"
+	"orus/internal/services/oauth"
+	"orus/internal/services/paymentcode"
+	"orus/internal/services/paymentintent"
+	"orus/internal/services/paymentpin"
+	qrsvc "orus/internal/services/qr_code"
+	"orus/internal/services/refundrequest"
+	"orus/internal/services/reserve"
+	"orus/internal/services/scanconfirm"
+	"orus/internal/services/spendcontrol"
+	"orus/internal/validation/metadata"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
-var walletService wallet.Service
-
 // SetupRoutes configures all application routes.
 // It groups routes by functionality and applies appropriate middleware.
-func SetupRoutes(app *fiber.App, db *gorm.DB) {
-	// Initialize repositories
-	walletRepo := repositories.NewWalletRepository(repositories.DB)
-	userRepo := repositories.NewUserRepository(repositories.DB, repositories.CacheService)
-	cardRepo := repositories.NewCreditCardRepository(repositories.DB)
-	qrRepo := repositories.NewQRCodeRepository(repositories.DB)
-
-	// Initialize auth service and handler
-	jwtSecret := config.GetEnv("JWT_SECRET", "orus")
-	refreshSecret := config.GetEnv("REFRESH_SECRET", "your-refresh-secret")
-	authService := auth.NewService(userRepo, jwtSecret, refreshSecret, repositories.CacheService)
-	authHandler := handlers.NewAuthHandler(authService, refreshSecret)
-
-	// Initialize services in correct order
-	cardService := creditcard.NewService(cardRepo)
-	userService := user.NewService(userRepo)
-	walletService = wallet.NewService(
-		walletRepo,
-		repositories.CacheService,
-		cardService,
-		wallet.WalletConfig{},
-		&wallet.NoopMetricsCollector{},
-	)
-
-	transactionService := transaction.NewService(
-		repositories.DB,
-		walletService,
-		walletService,
-		repositories.CacheService,
-	)
+func SetupRoutes(app *fiber.App, c *container.Container) {
+	cfg := c.Config
+	h := c.Handlers
+	metadata.SetMode(metadata.Mode(cfg.Metadata.SchemaMode))
 
-	qrService := qr.NewService(
-		repositories.DB,
-		qrRepo,
-		repositories.CacheService,
-		transactionService,
-		walletService,
-	)
-
-	paymentService := payment.NewService(walletService, transactionService, qrService)
-
-	notificationService := notification.NewService()
-	transferService := transfer.NewService(walletService, notificationService)
-	transferHandler := handlers.NewTransferHandler(transferService)
-
-	// Initialize dashboard service and handler
-	dashboardService := dashboard.NewService(
-		repositories.NewTransactionRepository(db),
-		repositories.NewWalletRepository(db),
-		repositories.NewMerchantRepository(db),
-		userRepo,
-		db,
-	)
-	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
-
-	// Initialize dispute service and handler
-	disputeService := dispute.NewService(
-		repositories.NewDisputeRepository(db),
-		repositories.NewTransactionRepository(db),
-		repositories.NewUserRepository(repositories.DB, repositories.CacheService),
-		db,
-	)
-	disputeHandler := handlers.NewDisputeHandler(disputeService)
-
-	kycService := services.NewKYCService()
-	kycHandler := handlers.NewKYCHandler(kycService)
-
-	// Initialize handlers
-	paymentHandler := handlers.NewPaymentHandler(qrService, paymentService)
-	merchantHandler := handlers.NewMerchantHandler(
-		merchant.NewService(qrService, transactionService, walletService),
-		qrService,
-		repositories.NewTransactionRepository(db),
-	)
-	// enterpriseHandler := handlers.NewEnterpriseHandler()
-	userHandler := handlers.NewUserHandler(userService, walletService, qrService)
-	cardHandler := handlers.NewCreditCardHandler(cardRepo)
+	// Messages and documents follow the client's Accept-Language
+	app.Use(middleware.Language())
 
 	// Public routes
 	api := app.Group("/api")
 
 	// Public endpoints (no auth required)
-	api.Post("/login", authHandler.LoginUser)       // This becomes /api/login
-	api.Post("/register", userHandler.RegisterUser) // This becomes /api/register
-	api.Post("/refresh", authHandler.RefreshToken)  // This becomes /api/refresh
-	api.Post("/verify-otp", authHandler.VerifyOTP)
+	api.Post("/login", h.Auth.LoginUser)       // This becomes /api/login
+	api.Post("/register", h.User.RegisterUser) // This becomes /api/register
+	api.Post("/refresh", h.Auth.RefreshToken)  // This becomes /api/refresh
+	api.Post("/verify-otp", h.Auth.VerifyOTP)
+	// Sign in with Google and Apple; the callbacks are the redirect URIs
+	api.Get("/login/providers", h.SocialLogin.ListProviders)
+	api.Get("/login/:provider", h.SocialLogin.Begin)
+	api.Get("/login/:provider/callback", h.SocialLogin.Callback)
+	api.Post("/login/:provider/callback", h.SocialLogin.Callback)
+	// "This wasn't me" links in new login alerts
+	api.Get("/login/report/:token", h.Auth.GetReportedLogin)
+	api.Post("/login/report/:token", h.Auth.ReportLogin)
+
+	// Error code catalog for client developers
+	api.Get("/errors", handlers.ListErrorCodes)
+
+	// Maintenance announcement for client apps to show
+	api.Get("/maintenance", h.Maintenance.GetStatus)
+
+	// Hosted checkout page data for payment links
+	api.Get("/pay/:code", h.Checkout.GetHostedLink)
+	// Payment intents are viewable by anyone holding their link; the app
+	// previews them here too
+	api.Get("/pay/intents/:token", h.Intent.GetIntent)
+	api.Get("/checkout/hosted/:id", h.Checkout.GetHostedSession)
+
+	// Authenticated users and merchant API keys each get a request budget
+	// per window; payment routes count against a stricter one as well
+	rateLimiter := middleware.NewRateLimiter(c.Services.RateLimits, cfg.RateLimit.Enabled)
+	payments := rateLimiter.Limit(models.RateLimitPayments)
+
+	// During maintenance, authenticated writes answer 503 with Retry-After
+	maintenanceMode := middleware.Maintenance(c.Services.Maintenance)
+
+	// Server-to-server merchant API, authenticated by the merchant's API key
+	// and limited to the merchant's allowed IPs and client certificates.
+	// Sandbox keys only reach the sandbox endpoints.
+	v1 := api.Group("/v1",
+		middleware.MerchantAPIKey(c.Repositories.Merchants),
+		rateLimiter.Handler,
+		middleware.MerchantAPIAccess(c.Services.APIAccess, cfg.Security.ClientCertHeader),
+		maintenanceMode,
+	)
+	setupSandboxRoutes(v1, h.Sandbox, payments)
+	orders := v1.Group("/checkout/sessions", middleware.LiveModeOnly())
+	orders.Post("/", h.Checkout.CreateOrderSession)
+	orders.Get("/", h.Checkout.ListOrderSessions)
+	orders.Get("/:id", h.Checkout.GetOrderSession)
+	orders.Post("/:id/expire", h.Checkout.ExpireOrderSession)
+
+	// Invoices are viewable by anyone holding their link
+	api.Get("/invoices/public/:code", h.Invoice.GetPublicInvoice)
+	api.Get("/invoices/public/:code/pdf", h.Invoice.GetPublicInvoicePDF)
+
+	// Card issuer callbacks authenticate with a shared secret instead of a user token
+	api.Post("/issuing/authorizations", middleware.WebhookSecret("X-Issuer-Secret", cfg.Issuing.WebhookSecret), h.VirtualCard.Authorize)
+
+	// Bank provider deposit lifecycle callbacks
+	api.Post("/funding/webhooks/deposits", middleware.WebhookSecret("X-Bank-Secret", cfg.Funding.WebhookSecret), h.Funding.HandleDepositWebhook)
+
+	// Card processor callbacks completing top-ups held for 3-D Secure
+	api.Post("/cards/webhooks/payments", middleware.WebhookSecret("X-Card-Processor-Secret", cfg.Cards.WebhookSecret), h.Wallet.HandleCardPaymentWebhook)
+
+	// Point-of-sale devices pair with a one-time code, then authenticate
+	// with their own credentials instead of a user token. Pairing is
+	// registered first so the terminal auth below doesn't apply to it.
+	api.Post("/terminal/pair", h.Terminal.PairTerminal)
+	device := api.Group("/terminal", middleware.TerminalAuth(c.Services.Terminals), maintenanceMode)
+	device.Get("/", h.Terminal.CurrentTerminal)
+	device.Post("/charge", h.Terminal.TerminalCharge)
+	device.Get("/charge-confirmations/:id", h.Terminal.TerminalChargeConfirmation)
+	device.Post("/refund", h.Terminal.TerminalRefund)
+
+	// Third-party apps authenticate to the OAuth endpoints with their
+	// client credentials, and call the connect API with the access token
+	// a user granted them, limited to its scopes
+	api.Post("/oauth/token", h.OAuth.Token)
+	api.Post("/oauth/introspect", h.OAuth.Introspect)
+	api.Post("/oauth/revoke", h.OAuth.Revoke)
+	connect := api.Group("/connect", middleware.OAuthToken(c.Services.OAuth), rateLimiter.Handler, maintenanceMode)
+	connect.Get("/balance", middleware.OAuthScope(models.OAuthScopeBalanceRead), h.OAuth.ConnectBalance)
+	connect.Get("/transactions", middleware.OAuthScope(models.OAuthScopeTransactionsRead), h.OAuth.ConnectTransactions)
+	connect.Post("/payments",
+		middleware.OAuthScope(models.OAuthScopePaymentsInitiate),
+		payments,
+		middleware.Validate[oauth.PaymentRequest](),
+		h.OAuth.ConnectRequestPayment,
+	)
 
-	// Debug endpoints (public)
-	api.Get("/debug/token-version/:id", authHandler.GetTokenVersion)
-	api.Get("/debug/token", authHandler.DebugToken)
+	// Public keys verifying access tokens, for services that accept them
+	app.Get("/.well-known/jwks.json", h.Auth.JWKS)
 
 	// Also add a root welcome route
 	app.Get("/", func(c *fiber.Ctx) error {
@@ -132,113 +151,306 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 	})
 
 	// Create middleware instance
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	authMiddleware := middleware.NewAuthMiddleware(c.Services.Auth, c.Services.JWTKeys)
 
 	// Protected routes with auth middleware
-	protected := api.Use(authMiddleware.Handler) // Auth middleware starts here
+	protected := api.Use(authMiddleware.Handler, rateLimiter.Handler, maintenanceMode) // Auth middleware starts here
 
 	// Setup different route groups
-	setupUserRoutes(protected, paymentHandler, userHandler, cardHandler, authHandler, qrService, kycHandler, transferHandler)
-	setupMerchantRoutes(protected, merchantHandler, paymentHandler)
-	// setupEnterpriseRoutes(protected, enterpriseHandler)
-	setupAdminRoutes(app, authMiddleware)
-	setupDisputeRoutes(protected, disputeHandler)
+	setupUserRoutes(protected, h, payments)
+	setupFundingRoutes(protected, h.Funding, payments)
+	setupVirtualCardRoutes(protected, h.VirtualCard)
+	setupMerchantRoutes(protected, h.Merchant, h.Payment, h.Checkout, h.QR, payments)
+	setupCheckoutRoutes(protected, h.Checkout, payments)
+	setupSettingsRoutes(protected, h.Export)
+	setupInvoiceRoutes(protected, h.Invoice, payments)
+	setupFraudRoutes(protected, h.Fraud)
+	setupMerchantSecurityRoutes(protected, h.Security)
+	setupReceiptRoutes(protected, h.Receipt)
+	setupReportRoutes(protected, h.TaxSummary)
+	setupCategoryRoutes(protected, h.Category)
+	setupSplitRoutes(protected, h.Split, payments)
+	setupSharedWalletRoutes(protected, h.SharedWallet, payments)
+	setupPotRoutes(protected, h.Pot)
+	setupAutoTopUpRoutes(protected, h.AutoTopUp)
+	setupFXRoutes(protected, h.FX)
+	setupContactRoutes(protected, h.Contact)
+	setupHandleRoutes(protected, h.Handle)
+	setupConfirmationRoutes(protected, h.Confirmation)
+	setupSpendingControlRoutes(protected, h.SpendingControl)
+	setupDeviceConfirmationRoutes(protected, h.DeviceConfirmation)
+	setupScanConfirmationRoutes(protected, h.ScanConfirmation)
+	setupRefundRequestRoutes(protected, h.RefundRequest)
+	setupPaymentPINRoutes(protected, h.PaymentPIN)
+	setupOAuthRoutes(protected, h.OAuth, payments)
+	setupEscrowRoutes(protected, h.Escrow, payments)
+	setupStaffRoutes(protected, h.Staff, payments)
+	setupTerminalRoutes(protected, h.Terminal)
+	setupReserveRoutes(protected, h.Reserve)
+	setupSubscriptionRoutes(protected, h.Subscription)
+	setupPromotionRoutes(protected, h.Promotion)
+	setupLoyaltyRoutes(protected, h.Loyalty)
+	setupMerchantInsightRoutes(protected, h.Dashboard)
+	setupWebhookRoutes(protected, h.Webhook)
+	setupEnterpriseRoutes(protected, h.Enterprise, payments)
+	setupAdminRoutes(app, authMiddleware, h, c.Services.Features)
+	setupDisputeRoutes(protected, h.Dispute)
+
+	// GraphQL gateway for client apps
+	graphql := middleware.Feature(c.Services.Features, models.FeatureGraphQL)
+	protected.Get("/graphql", graphql, h.GraphQL.Query)
+	protected.Post("/graphql", graphql, h.GraphQL.Query)
 
 	// Add dashboard routes
-	addDashboardRoutes(app, dashboardHandler, authMiddleware.Handler)
-
-	// Add debug endpoint for protected routes
-	protected.Get("/debug/claims", func(c *fiber.Ctx) error {
-		claims, ok := c.Locals("claims").(*models.UserClaims)
-		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "No claims found",
-			})
-		}
-
-		return c.JSON(fiber.Map{
-			"user_id":       claims.UserID,
-			"email":         claims.Email,
-			"role":          claims.Role,
-			"permissions":   claims.Permissions,
-			"token_version": claims.TokenVersion,
-		})
-	})
-
-	// Add temporary cache stats route
-	protected.Get("/test/cache-stats", handlers.CacheStats)
+	addDashboardRoutes(app, h.Dashboard, authMiddleware.Handler)
 }
 
-func setupUserRoutes(router fiber.Router, paymentHandler *handlers.PaymentHandler, userHandler *handlers.UserHandler, cardHandler *handlers.CreditCardHandler, authHandler *handlers.AuthHandler, qrService qr.Service, kycHandler *handlers.KYCHandler, transferHandler *handlers.TransferHandler) {
-	// Initialize wallet handler
-	walletHandler := handlers.NewWalletHandler(walletService)
-
+func setupUserRoutes(router fiber.Router, h *container.Handlers, paymentsLimit fiber.Handler) {
 	// Wallet routes
 	wallet := router.Group("/wallet")
-	wallet.Get("/", middleware.HasPermission(models.PermissionWalletRead), walletHandler.GetWallet)
-	wallet.Post("/topup", middleware.HasPermission(models.PermissionWalletWrite), walletHandler.TopUpWallet)
-	wallet.Post("/withdraw", middleware.HasPermission(models.PermissionWalletWrite), walletHandler.WithdrawToCard)
+	wallet.Get("/", middleware.HasPermission(models.PermissionWalletRead), h.Wallet.GetWallet)
+	wallet.Get("/balance", middleware.HasPermission(models.PermissionWalletRead), h.Wallet.GetBalance)
+	wallet.Post("/topup", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, middleware.Validate[handlers.CardTransferRequest](), h.Wallet.TopUpWallet)
+	wallet.Get("/topups/:id", middleware.HasPermission(models.PermissionWalletRead), h.Wallet.GetTopUp)
+	wallet.Post("/withdraw", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, middleware.Validate[handlers.CardTransferRequest](), h.Wallet.WithdrawToCard)
+	wallet.Get("/lock", middleware.HasPermission(models.PermissionWalletRead), h.Wallet.GetWalletLock)
+	wallet.Post("/lock", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[handlers.WalletLockRequest](), h.Wallet.LockOwnWallet)
+	wallet.Delete("/lock", middleware.HasPermission(models.PermissionWalletWrite), h.Wallet.UnlockOwnWallet)
 
 	// Transaction routes
-	router.Get("/transactions", userHandler.GetUserTransactions) //✅
+	router.Get("/transactions", h.User.GetUserTransactions) //✅
+	router.Get("/transactions/metadata-schemas", handlers.GetMetadataSchemas)
 
 	// User account routes
-	router.Post("/credit-card", cardHandler.LinkCard)         // Add credit card route
-	router.Get("/credit-card", cardHandler.GetCards)          // Get user's cards
-	router.Delete("/credit-card/:id", cardHandler.DeleteCard) // Delete a card
-	router.Post("/change-password", authHandler.ChangePassword)
-	router.Post("/logout", authHandler.LogoutUser)
+	router.Post("/credit-card", h.CreditCard.LinkCard)         // Add credit card route
+	router.Get("/credit-card", h.CreditCard.GetCards)          // Get user's cards
+	router.Delete("/credit-card/:id", h.CreditCard.DeleteCard) // Delete a card
+	router.Patch("/credit-card/:id", middleware.Validate[creditcard.UpdateCardInput](), h.CreditCard.UpdateCard)
+	router.Put("/credit-card/:id/default", h.CreditCard.SetDefaultCard)
+	router.Post("/credit-card/:id/verify", h.CreditCard.StartCardVerification)
+	router.Post("/credit-card/:id/verify/confirm", h.CreditCard.ConfirmCardVerification)
+	router.Post("/change-password", h.Auth.ChangePassword)
+	router.Post("/logout", h.Auth.LogoutUser)
+	router.Post("/logout-all", h.Auth.LogoutAll)
+	router.Get("/sessions", h.Auth.ListSessions)
+	router.Delete("/sessions/:id", h.Auth.RevokeSession)
+	router.Get("/logins", h.Auth.ListLogins)
+	router.Get("/profile/identities", h.SocialLogin.ListIdentities)
+	router.Post("/profile/identities/:provider", h.SocialLogin.BeginLink)
 
 	// Payment routes
-	payments := router.Group("/payment")
-	payments.Post("/scan", paymentHandler.ProcessQRPayment) // For users scanning QRs
-	payments.Post("/send", paymentHandler.SendMoney)        //✅
-	payments.Post("/p2p", transferHandler.Transfer)
+	payments := router.Group("/payment", paymentsLimit)
+	payments.Post("/scan", middleware.Validate[models.QRPaymentRequest](), h.Payment.ProcessQRPayment) // For users scanning QRs
+	payments.Post("/send", middleware.Validate[handlers.SendMoneyRequest](), h.Payment.SendMoney)      //✅
+	payments.Post("/p2p", h.Transfer.Transfer)
+	payments.Post("/codes", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[paymentcode.GenerateRequest](), h.PaymentCode.GenerateCode)
+	payments.Post("/intents", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[paymentintent.CreateRequest](), h.Intent.CreateIntent)
+	payments.Post("/intents/confirm", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[paymentintent.ConfirmRequest](), h.Intent.ConfirmIntent)
 
 	// QR code routes
-	qrHandler := handlers.NewQRHandler(qrService)
-	router.Get("/qr-codes", middleware.HasPermission(models.PermissionWalletRead), qrHandler.GetUserQRCodes)
+	router.Get("/qr-codes", middleware.HasPermission(models.PermissionWalletRead), h.QR.GetUserQRCodes)
+	router.Get("/qr-codes/:id/stats", middleware.HasPermission(models.PermissionWalletRead), h.QR.GetQRCodeStats)
+	router.Post("/qr-codes/:id/revoke", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[handlers.RevokeQRRequest](), h.QR.RevokeQRCode)
+	router.Post("/qr-codes/:id/regenerate", middleware.HasPermission(models.PermissionWalletWrite), h.QR.RegenerateQRCode)
 
 	// KYC routes
 	kyc := router.Group("/kyc")
-	kyc.Post("/", kycHandler.SubmitKYC)
-	kyc.Get("/", kycHandler.GetStatus)
+	kyc.Post("/", h.KYC.SubmitKYC)
+	kyc.Get("/", h.KYC.GetStatus)
 }
 
-func setupMerchantRoutes(router fiber.Router, h *handlers.MerchantHandler, paymentHandler *handlers.PaymentHandler) {
+func setupMerchantRoutes(router fiber.Router, h *handlers.MerchantHandler, paymentHandler *handlers.PaymentHandler, checkoutHandler *handlers.CheckoutHandler, qrHandler *handlers.QRHandler, paymentsLimit fiber.Handler) {
 	merchant := router.Group("/merchant", middleware.HasPermission(models.PermissionMerchantRead))
 
 	// Profile Management
-	merchant.Post("/", h.CreateMerchant)
+	merchant.Post("/", middleware.Validate[handlers.CreateMerchantRequest](), h.CreateMerchant)
 	merchant.Get("/profile", h.GetMerchantProfile)
-	merchant.Put("/profile", h.UpdateMerchantProfile)
+	merchant.Put("/profile", middleware.Validate[handlers.UpdateMerchantProfileRequest](), h.UpdateMerchantProfile)
 
 	// Payment Processing
-	payments := merchant.Group("/payments")
-	payments.Post("/receive", paymentHandler.ProcessQRPayment) // For merchants receiving payments (scanning customer QRs)
-	payments.Post("/charge", h.ProcessDirectCharge)            // For direct charges without QR
+	payments := merchant.Group("/payments", paymentsLimit)
+	payments.Post("/receive", middleware.Validate[models.QRPaymentRequest](), paymentHandler.ProcessQRPayment) // For merchants receiving payments (scanning customer QRs)
+	payments.Post("/charge", middleware.Validate[merchantsvc.ChargeInput](), h.ProcessDirectCharge)            // For direct charges without QR
+	payments.Post("/refund", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[merchantsvc.RefundInput](), h.RefundCharge)
+	merchant.Get("/payments/confirmations/:id", h.GetChargeConfirmation) // Charges held for the customer's approval
+	payments.Post("/reverse", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[merchantsvc.ReverseInput](), h.ReverseCharge)
 
 	// Integration Settings
 	merchant.Post("/:merchantId/apikey", middleware.HasPermission(models.PermissionMerchantWrite), h.GenerateAPIKey)
-	merchant.Post("/:merchantId/webhook", middleware.HasPermission(models.PermissionMerchantWrite), h.SetWebhookURL)
+	merchant.Post("/:merchantId/apikey/sandbox", middleware.HasPermission(models.PermissionMerchantWrite), h.GenerateSandboxAPIKey)
+	merchant.Post("/:merchantId/webhook", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[handlers.WebhookURLRequest](), h.SetWebhookURL)
 
 	// Transactions
 	merchant.Get("/transactions", h.GetMerchantTransactions)
+
+	// Payment links
+	links := merchant.Group("/payment-links")
+	links.Post("/", middleware.HasPermission(models.PermissionMerchantWrite), checkoutHandler.CreatePaymentLink)
+	links.Get("/", checkoutHandler.GetPaymentLinks)
+	links.Get("/:id", checkoutHandler.GetPaymentLink)
+	links.Post("/:id/disable", middleware.HasPermission(models.PermissionMerchantWrite), checkoutHandler.DisablePaymentLink)
+
+	// QR posters for tills and locations
+	posters := merchant.Group("/qr-posters")
+	posters.Post("/", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[qrsvc.PosterRequest](), qrHandler.CreatePoster)
+	posters.Get("/", qrHandler.ListPosters)
+	posters.Get("/:id/pdf", qrHandler.GetPosterPDF)
 }
 
-func setupAdminRoutes(app *fiber.App, authMiddleware *middleware.AuthMiddleware) {
+func setupAdminRoutes(app *fiber.App, authMiddleware *middleware.AuthMiddleware, h *container.Handlers, features featureflag.Service) {
 	// Use the existing auth middleware instance
 	admin := app.Group("/api/admin", authMiddleware.Handler, middleware.AdminAuthMiddleware)
 
-	admin.Get("/transactions", middleware.HasPermission(models.PermissionReadAdmin), handlers.GetAllTransactions)
-	admin.Get("/users", middleware.HasPermission(models.PermissionReadAdmin), handlers.GetUsersPaginated)
-	admin.Delete("/users/:id", middleware.HasPermission(models.PermissionWriteAdmin), handlers.DeleteUser)
-	admin.Get("/wallets", middleware.HasPermission(models.PermissionWriteAdmin), handlers.GetAllWallets)
-	admin.Get("/credit-cards", middleware.HasPermission(models.PermissionWriteAdmin), handlers.GetAllCreditCards)
+	admin.Get("/transactions", middleware.HasPermission(models.PermissionReadAdmin), h.Admin.GetAllTransactions)
+	admin.Post("/transactions/:id/reverse", middleware.HasPermission(models.PermissionWriteAdmin), h.Transaction.ReverseTransaction)
+	admin.Get("/transactions/:id/graph", middleware.HasPermission(models.PermissionReadAdmin), h.Investigation.GetGraph)
+	admin.Get("/transactions/pending", middleware.HasPermission(models.PermissionReadAdmin), h.PendingTransaction.ListPending) // Stuck pending, before they expire
+	admin.Get("/users", middleware.HasPermission(models.PermissionReadAdmin), h.Admin.GetUsersPaginated)
+	admin.Delete("/users/:id", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.DeleteUser)
+	admin.Patch("/users/:id", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.UpdateUser)
+	admin.Put("/users/:id/role", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.UpdateUserRole)
+	admin.Post("/users/:id/suspend", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.SuspendUser)
+	admin.Post("/users/:id/unsuspend", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.UnsuspendUser)
+	admin.Post("/users/:id/revoke-sessions", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.RevokeUserSessions)
+	admin.Get("/users/:id/timeline", middleware.HasPermission(models.PermissionReadAdmin), h.Admin.GetUserTimeline)
+	admin.Get("/wallets", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.GetAllWallets)
+	admin.Get("/wallets/negative", middleware.HasPermission(models.PermissionReadAdmin), h.Overdraft.GetNegativeWallets)
+	admin.Put("/users/:id/overdraft", middleware.HasPermission(models.PermissionWriteAdmin), h.Overdraft.SetOverdraftLimit)
+	admin.Post("/users/:id/wallet/lock", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[handlers.WalletLockRequest](), h.Wallet.LockUserWallet)
+	admin.Delete("/users/:id/wallet/lock", middleware.HasPermission(models.PermissionWriteAdmin), h.Wallet.UnlockUserWallet)
+	admin.Get("/users/:id/wallet/lock-events", middleware.HasPermission(models.PermissionReadAdmin), h.Wallet.GetWalletLockEvents)
+	admin.Get("/credit-cards", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.GetAllCreditCards)
+
+	// Roles: built-in ones are read-only, custom ones grant a chosen
+	// subset of permissions
+	roles := admin.Group("/roles")
+	roles.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Role.ListRoles)
+	roles.Get("/:name", middleware.HasPermission(models.PermissionReadAdmin), h.Role.GetRole)
+	roles.Post("/", middleware.HasPermission(models.PermissionWriteAdmin), h.Role.CreateRole)
+	roles.Put("/:name", middleware.HasPermission(models.PermissionWriteAdmin), h.Role.UpdateRole)
+	roles.Delete("/:name", middleware.HasPermission(models.PermissionWriteAdmin), h.Role.DeleteRole)
+	admin.Get("/permissions", middleware.HasPermission(models.PermissionReadAdmin), h.Role.ListPermissions)
 
 	// Add cache stats endpoint to admin routes
-	admin.Get("/cache-stats", handlers.CacheStats)
-
+	admin.Get("/cache-stats", h.Health.CacheStats)
+	admin.Post("/cache/invalidate", middleware.HasPermission(models.PermissionWriteAdmin), h.Admin.InvalidateCache)
+
+	// Feature flags: defaults and configuration, overridden at runtime
+	flags := admin.Group("/feature-flags")
+	flags.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.FeatureFlag.ListFlags)
+	flags.Put("/:key", middleware.HasPermission(models.PermissionWriteAdmin), h.FeatureFlag.SetOverride)
+	flags.Delete("/:key", middleware.HasPermission(models.PermissionWriteAdmin), h.FeatureFlag.DeleteOverride)
+
+	// Maintenance: pauses payment-mutating endpoints platform-wide; admin
+	// routes and the allowed users keep working
+	maint := admin.Group("/maintenance")
+	maint.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Maintenance.GetMaintenance)
+	maint.Put("/", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[maintenance.EnableRequest](), h.Maintenance.EnableMaintenance)
+	maint.Delete("/", middleware.HasPermission(models.PermissionWriteAdmin), h.Maintenance.DisableMaintenance)
+
+	// Diagnostics for debugging sessions, only while the flag is on
+	diagnostics := admin.Group("/diagnostics", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Feature(features, models.FeatureDiagnostics))
+	diagnostics.Post("/token", h.Auth.DebugToken)
+	diagnostics.Get("/token-version/:id", h.Auth.GetTokenVersion)
+	diagnostics.Get("/claims", h.Auth.DebugClaims)
+	diagnostics.Get("/cache-stats", h.Health.CacheStats)
+
+	// Rate limits: defaults come from the configuration, overrides raise or
+	// lower them for one user or merchant
+	rateLimits := admin.Group("/rate-limits")
+	rateLimits.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.RateLimit.GetSettings)
+	rateLimits.Get("/overrides", middleware.HasPermission(models.PermissionReadAdmin), h.RateLimit.ListOverrides)
+	rateLimits.Put("/overrides", middleware.HasPermission(models.PermissionWriteAdmin), h.RateLimit.SetOverride)
+	rateLimits.Delete("/overrides/:id", middleware.HasPermission(models.PermissionWriteAdmin), h.RateLimit.DeleteOverride)
+
+	// Treasury: any admin can report, only super-admins move funds and a
+	// transfer needs a second super-admin to approve it
+	treasury := admin.Group("/treasury")
+	treasury.Get("/report", middleware.HasPermission(models.PermissionTreasuryRead), h.Treasury.GetReport)
+	treasury.Get("/accounts/:code/entries", middleware.HasPermission(models.PermissionTreasuryRead), h.Treasury.GetEntries)
+	treasury.Get("/transfers", middleware.HasPermission(models.PermissionTreasuryRead), h.Treasury.ListTransfers)
+	treasury.Post("/transfers", middleware.SuperAdminMiddleware, h.Treasury.RequestTransfer)
+	treasury.Post("/transfers/:id/approve", middleware.SuperAdminMiddleware, h.Treasury.ApproveTransfer)
+	treasury.Post("/transfers/:id/reject", middleware.SuperAdminMiddleware, h.Treasury.RejectTransfer)
+
+	// Accounting: daily journals of the ledger for the accounting system
+	books := admin.Group("/accounting")
+	books.Get("/accounts", middleware.HasPermission(models.PermissionTreasuryRead), h.Accounting.GetAccounts)
+	books.Get("/journals/:date", middleware.HasPermission(models.PermissionTreasuryRead), h.Accounting.GetJournal)
+	books.Get("/exports", middleware.HasPermission(models.PermissionTreasuryRead), h.Accounting.ListExports)
+	books.Post("/exports", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[accounting.ExportRequest](), h.Accounting.Export)
+
+	// Compliance: regulatory reports drafted for compliance officers
+	reports := admin.Group("/compliance/reports")
+	reports.Get("/", middleware.HasPermission(models.PermissionComplianceRead), h.Compliance.ListReports)
+	reports.Get("/:id", middleware.HasPermission(models.PermissionComplianceRead), h.Compliance.GetReport)
+	reports.Get("/:id/export", middleware.HasPermission(models.PermissionComplianceRead), h.Compliance.ExportReport)
+	reports.Post("/:id/review", middleware.HasPermission(models.PermissionComplianceWrite), h.Compliance.StartReview)
+	reports.Put("/:id/narrative", middleware.HasPermission(models.PermissionComplianceWrite), middleware.Validate[compliance.NarrativeRequest](), h.Compliance.UpdateNarrative)
+	reports.Post("/:id/file", middleware.HasPermission(models.PermissionComplianceWrite), middleware.Validate[compliance.FileRequest](), h.Compliance.FileReport)
+	reports.Post("/:id/dismiss", middleware.HasPermission(models.PermissionComplianceWrite), middleware.Validate[compliance.DismissRequest](), h.Compliance.DismissReport)
+
+	// Sagas: multi-step payments, retried by hand once their compensation
+	// needs attention
+	sagas := admin.Group("/sagas")
+	sagas.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Saga.ListSagas)
+	sagas.Get("/:id", middleware.HasPermission(models.PermissionReadAdmin), h.Saga.GetSaga)
+	sagas.Post("/:id/retry", middleware.HasPermission(models.PermissionWriteAdmin), h.Saga.RetrySaga)
+
+	admin.Post("/escrows/:id/resolve", middleware.HasPermission(models.PermissionWriteAdmin), h.Escrow.ResolveEscrow)
+
+	// Dispute arbitration
+	disputes := admin.Group("/disputes")
+	disputes.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Dispute.ListAllDisputes)
+	disputes.Get("/:id", middleware.HasPermission(models.PermissionReadAdmin), h.Dispute.AdminGetDispute)
+	disputes.Get("/:id/evidence/:evidenceId", middleware.HasPermission(models.PermissionReadAdmin), h.Dispute.AdminDownloadEvidence)
+	disputes.Post("/:id/evidence", middleware.HasPermission(models.PermissionWriteAdmin), h.Dispute.AdminUploadEvidence)
+	disputes.Post("/:id/request-evidence", middleware.HasPermission(models.PermissionWriteAdmin), h.Dispute.RequestEvidence)
+	disputes.Post("/:id/review", middleware.HasPermission(models.PermissionWriteAdmin), h.Dispute.StartReview)
+	disputes.Post("/:id/resolve", middleware.HasPermission(models.PermissionWriteAdmin), h.Dispute.ResolveDispute)
+	disputes.Post("/:id/chargeback", middleware.HasPermission(models.PermissionWriteAdmin), h.Dispute.OpenChargeback)
+
+	chargebacks := admin.Group("/chargebacks")
+	chargebacks.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Dispute.ListAllChargebacks)
+	chargebacks.Get("/:id", middleware.HasPermission(models.PermissionReadAdmin), h.Dispute.AdminGetChargeback)
+	chargebacks.Post("/:id/settle", middleware.HasPermission(models.PermissionWriteAdmin), h.Dispute.SettleChargeback)
+
+	// Bulk actions from an uploaded CSV or a filter, run in the background
+	bulkOps := admin.Group("/bulk-operations")
+	bulkOps.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Bulk.ListOperations)
+	bulkOps.Get("/:id", middleware.HasPermission(models.PermissionReadAdmin), h.Bulk.GetOperation)
+	bulkOps.Get("/:id/items", middleware.HasPermission(models.PermissionReadAdmin), h.Bulk.ListItems)
+	bulkOps.Get("/:id/report", middleware.HasPermission(models.PermissionReadAdmin), h.Bulk.GetReport)
+	bulkOps.Post("/lock-wallets", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[bulk.Request](), h.Bulk.LockWallets)
+	bulkOps.Post("/suspend-users", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[bulk.Request](), h.Bulk.SuspendUsers)
+	bulkOps.Post("/expire-qr-codes", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[bulk.Request](), h.Bulk.ExpireQRCodes)
+
+	// Merchant risk monitoring: restricted merchants, risk history and
+	// manual review
+	merchantRisk := admin.Group("/merchants")
+	merchantRisk.Get("/risk/restricted", middleware.HasPermission(models.PermissionReadAdmin), h.MerchantRisk.ListRestricted)
+	merchantRisk.Get("/:id/risk", middleware.HasPermission(models.PermissionReadAdmin), h.MerchantRisk.GetRisk)
+	merchantRisk.Get("/:id/risk/history", middleware.HasPermission(models.PermissionReadAdmin), h.MerchantRisk.GetHistory)
+	merchantRisk.Post("/:id/risk/assess", middleware.HasPermission(models.PermissionWriteAdmin), h.MerchantRisk.Assess)
+	merchantRisk.Post("/:id/risk/clear", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[merchantrisk.ClearRequest](), h.MerchantRisk.Clear)
+	merchantRisk.Get("/:id/reserve", middleware.HasPermission(models.PermissionReadAdmin), h.Reserve.AdminGetReserve)
+	merchantRisk.Put("/:id/reserve", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[reserve.SetRequest](), h.Reserve.SetReserve)
+	merchantRisk.Put("/:id/fee-plan", middleware.HasPermission(models.PermissionWriteAdmin), middleware.Validate[merchantsvc.FeePlanInput](), h.Merchant.SetFeePlan) // Plan tier and negotiated rate
+
+	// Remote deactivation of lost or compromised terminals
+	terminals := admin.Group("/terminals")
+	terminals.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Terminal.AdminListTerminals)
+	terminals.Post("/:id/deactivate", middleware.HasPermission(models.PermissionWriteAdmin), h.Terminal.AdminDeactivateTerminal)
+
+	// Promotional campaigns and their payouts
+	promotions := admin.Group("/promotions")
+	promotions.Get("/", middleware.HasPermission(models.PermissionReadAdmin), h.Promotion.ListPromotions)
+	promotions.Get("/:id", middleware.HasPermission(models.PermissionReadAdmin), h.Promotion.GetPromotion)
+	promotions.Get("/:id/rewards", middleware.HasPermission(models.PermissionReadAdmin), h.Promotion.ListPromotionRewards)
+	promotions.Post("/", middleware.HasPermission(models.PermissionWriteAdmin), h.Promotion.CreatePromotion)
+	promotions.Post("/:id/pause", middleware.HasPermission(models.PermissionWriteAdmin), h.Promotion.PausePromotion)
+	promotions.Post("/:id/resume", middleware.HasPermission(models.PermissionWriteAdmin), h.Promotion.ResumePromotion)
+	promotions.Post("/:id/end", middleware.HasPermission(models.PermissionWriteAdmin), h.Promotion.EndPromotion)
 }
 
 func addDashboardRoutes(app *fiber.App, handler *handlers.DashboardHandler, authMiddleware fiber.Handler) {
@@ -256,8 +468,456 @@ func addDashboardRoutes(app *fiber.App, handler *handlers.DashboardHandler, auth
 func setupDisputeRoutes(router fiber.Router, disputeHandler *handlers.DisputeHandler) {
 	dispute := router.Group("/disputes")
 
-	dispute.Post("/", disputeHandler.FileDispute)                                                                       // Endpoint to file a dispute
+	dispute.Post("/", middleware.Validate[disputesvc.FileRequest](), disputeHandler.FileDispute)                        // Endpoint to file a dispute
+	dispute.Get("/mine", disputeHandler.ListMyDisputes)                                                                 // Disputes the caller filed, to track their status
 	dispute.Get("/", disputeHandler.GetDisputes)                                                                        // Endpoint to get all disputes for a merchant
 	dispute.Get("/merchant", disputeHandler.GetMerchantDisputes)                                                        // New endpoint to get merchant disputes
 	dispute.Post("/:id/refund", middleware.HasPermission(models.PermissionMerchantWrite), disputeHandler.RefundDispute) // New endpoint for processing refunds
+	dispute.Get("/:id", disputeHandler.GetDispute)
+	dispute.Post("/:id/evidence", disputeHandler.UploadEvidence)
+	dispute.Get("/:id/evidence/:evidenceId", disputeHandler.DownloadEvidence)
+	dispute.Post("/:id/respond", disputeHandler.RespondDispute)
+
+	// Chargebacks against the merchant; representment files are uploaded
+	// as evidence on the dispute
+	chargebacks := router.Group("/chargebacks")
+	chargebacks.Get("/", middleware.HasPermission(models.PermissionMerchantRead), disputeHandler.ListChargebacks)
+	chargebacks.Get("/:id", disputeHandler.GetChargeback)
+	chargebacks.Post("/:id/represent", middleware.HasPermission(models.PermissionMerchantWrite), disputeHandler.RepresentChargeback)
+}
+
+func setupFundingRoutes(router fiber.Router, h *handlers.FundingHandler, paymentsLimit fiber.Handler) {
+	banks := router.Group("/funding-sources/banks")
+
+	banks.Get("/", middleware.HasPermission(models.PermissionWalletRead), h.GetAccounts)
+	banks.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.LinkAccount)
+	banks.Post("/:id/verify", middleware.HasPermission(models.PermissionWalletWrite), h.VerifyAccount)
+	banks.Delete("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.RemoveAccount)
+	banks.Post("/:id/topup", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.TopUp)
+	banks.Post("/:id/withdraw", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.Withdraw)
+
+	deposits := router.Group("/funding-sources/deposits")
+	deposits.Get("/", middleware.HasPermission(models.PermissionWalletRead), h.GetDeposits)
+	deposits.Get("/:id", middleware.HasPermission(models.PermissionWalletRead), h.GetDeposit)
+}
+
+func setupVirtualCardRoutes(router fiber.Router, h *handlers.VirtualCardHandler) {
+	cards := router.Group("/virtual-cards")
+
+	cards.Get("/", middleware.HasPermission(models.PermissionWalletRead), h.GetCards)
+	cards.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.IssueCard)
+	cards.Get("/:id", middleware.HasPermission(models.PermissionWalletRead), h.GetCard)
+	cards.Get("/:id/transactions", middleware.HasPermission(models.PermissionWalletRead), h.GetCardTransactions)
+	cards.Put("/:id/limits", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateSpendLimit)
+	cards.Post("/:id/freeze", middleware.HasPermission(models.PermissionWalletWrite), h.FreezeCard)
+	cards.Post("/:id/unfreeze", middleware.HasPermission(models.PermissionWalletWrite), h.UnfreezeCard)
+	cards.Delete("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.CloseCard)
+}
+
+func setupCheckoutRoutes(router fiber.Router, h *handlers.CheckoutHandler, paymentsLimit fiber.Handler) {
+	router.Post("/pay/:code/checkout", middleware.HasPermission(models.PermissionWalletWrite), h.OpenCheckout)
+
+	sessions := router.Group("/checkout/sessions")
+	sessions.Get("/:id", middleware.HasPermission(models.PermissionWalletRead), h.GetCheckoutSession)
+	sessions.Post("/:id/complete", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.CompleteCheckout)
+}
+
+func setupSettingsRoutes(router fiber.Router, exportHandler *handlers.ExportHandler) {
+	// Statement exports live under the wallet; /settings/exports is kept for
+	// existing clients
+	for _, prefix := range []string{"/wallet/exports", "/settings/exports"} {
+		exports := router.Group(prefix, middleware.HasPermission(models.PermissionWalletRead))
+
+		exports.Get("/", exportHandler.GetSchedules)
+		exports.Post("/", middleware.HasPermission(models.PermissionWalletWrite), exportHandler.CreateSchedule)
+		exports.Put("/:id", middleware.HasPermission(models.PermissionWalletWrite), exportHandler.UpdateSchedule)
+		exports.Delete("/:id", middleware.HasPermission(models.PermissionWalletWrite), exportHandler.DeleteSchedule)
+		exports.Post("/:id/run", middleware.HasPermission(models.PermissionWalletWrite), exportHandler.RunSchedule)
+		exports.Get("/:id/runs", exportHandler.GetRuns)
+	}
+}
+
+func setupInvoiceRoutes(router fiber.Router, h *handlers.InvoiceHandler, paymentsLimit fiber.Handler) {
+	invoices := router.Group("/merchant/invoices", middleware.HasPermission(models.PermissionMerchantRead))
+
+	invoices.Post("/", middleware.HasPermission(models.PermissionMerchantWrite), h.CreateInvoice)
+	invoices.Get("/", h.GetInvoices)
+	invoices.Get("/:id", h.GetInvoice)
+	invoices.Put("/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.UpdateInvoice)
+	invoices.Delete("/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.DeleteInvoice)
+	invoices.Post("/:id/send", middleware.HasPermission(models.PermissionMerchantWrite), h.SendInvoice)
+	invoices.Post("/:id/void", middleware.HasPermission(models.PermissionMerchantWrite), h.VoidInvoice)
+	invoices.Get("/:id/pdf", h.GetInvoicePDF)
+
+	router.Post("/invoices/public/:code/pay", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.PayInvoice)
+}
+
+func setupFraudRoutes(router fiber.Router, h *handlers.FraudHandler) {
+	rules := router.Group("/merchant/fraud-rules", middleware.HasPermission(models.PermissionMerchantRead))
+
+	rules.Get("/", h.GetRules)
+	rules.Put("/", middleware.HasPermission(models.PermissionMerchantWrite), h.UpdateRules)
+}
+
+func setupMerchantSecurityRoutes(router fiber.Router, h *handlers.MerchantSecurityHandler) {
+	security := router.Group("/merchant/security", middleware.HasPermission(models.PermissionMerchantRead))
+
+	security.Get("/", h.GetSettings)
+	security.Get("/rejections", h.ListRejections)
+	security.Post("/ip-ranges", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[apiaccess.AddIPRangeInput](), h.AddIPRange)
+	security.Delete("/ip-ranges/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.RemoveIPRange)
+	security.Post("/certificates", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[apiaccess.AddCertificateInput](), h.AddCertificate)
+	security.Delete("/certificates/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.RemoveCertificate)
+	security.Put("/mtls", middleware.HasPermission(models.PermissionMerchantWrite), h.SetMTLS)
+}
+
+func setupReceiptRoutes(router fiber.Router, h *handlers.ReceiptHandler) {
+	router.Get("/transactions/:id/receipt", middleware.HasPermission(models.PermissionWalletRead), h.GetReceipt)
+	router.Post("/transactions/:id/receipt/email", middleware.HasPermission(models.PermissionWalletRead), h.EmailReceipt)
+}
+
+func setupReportRoutes(router fiber.Router, h *handlers.TaxSummaryHandler) {
+	taxSummary := router.Group("/reports/tax-summary", middleware.HasPermission(models.PermissionWalletRead))
+	taxSummary.Get("/", h.GetTaxSummary)
+	taxSummary.Get("/:id", h.GetTaxSummaryReport)
+	taxSummary.Get("/:id/download", h.DownloadTaxSummaryReport)
+}
+
+func setupCategoryRoutes(router fiber.Router, h *handlers.CategoryHandler) {
+	router.Get("/transactions/categories", h.ListCategories)
+	router.Put("/transactions/:id/category", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[category.RecategorizeRequest](), h.Recategorize)
+
+	rules := router.Group("/transactions/category-rules", middleware.HasPermission(models.PermissionWalletRead))
+	rules.Get("/", h.ListRules)
+	rules.Post("/", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[category.RuleRequest](), h.CreateRule)
+	rules.Delete("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.DeleteRule)
+}
+
+func setupSplitRoutes(router fiber.Router, h *handlers.SplitHandler, paymentsLimit fiber.Handler) {
+	splits := router.Group("/splits", middleware.HasPermission(models.PermissionWalletRead))
+
+	splits.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.CreateSplit)
+	splits.Get("/", h.GetSplits)
+	splits.Get("/:id", h.GetSplit)
+	splits.Post("/:id/pay", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.PaySplit)
+	splits.Post("/:id/remind", middleware.HasPermission(models.PermissionWalletWrite), h.RemindSplit)
+	splits.Post("/:id/cancel", middleware.HasPermission(models.PermissionWalletWrite), h.CancelSplit)
+}
+
+func setupSharedWalletRoutes(router fiber.Router, h *handlers.SharedWalletHandler, paymentsLimit fiber.Handler) {
+	shared := router.Group("/shared-wallets", middleware.HasPermission(models.PermissionWalletRead))
+
+	shared.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.CreateSharedWallet)
+	shared.Get("/", h.GetSharedWallets)
+	shared.Get("/:id", h.GetSharedWallet)
+	shared.Put("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateSharedWallet)
+
+	shared.Post("/:id/members", middleware.HasPermission(models.PermissionWalletWrite), h.AddMember)
+	shared.Put("/:id/members/:userId", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateMember)
+	shared.Delete("/:id/members/:userId", middleware.HasPermission(models.PermissionWalletWrite), h.RemoveMember)
+
+	shared.Post("/:id/contribute", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.Contribute)
+	shared.Post("/:id/payments", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.Pay)
+	shared.Get("/:id/payments", h.GetPayments)
+	shared.Post("/:id/payments/:paymentId/approve", middleware.HasPermission(models.PermissionWalletWrite), h.ApprovePayment)
+	shared.Post("/:id/payments/:paymentId/reject", middleware.HasPermission(models.PermissionWalletWrite), h.RejectPayment)
+}
+
+func setupEnterpriseRoutes(router fiber.Router, h *handlers.EnterpriseHandler, paymentsLimit fiber.Handler) {
+	orgs := router.Group("/enterprise/organizations", middleware.HasPermission(models.PermissionWalletRead))
+
+	orgs.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.CreateOrganization)
+	orgs.Get("/", h.GetOrganizations)
+	orgs.Get("/:id", h.GetOrganization)
+	orgs.Put("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateOrganization)
+
+	orgs.Get("/:id/members", h.GetMembers)
+	orgs.Post("/:id/members", middleware.HasPermission(models.PermissionWalletWrite), h.AddMember)
+	orgs.Put("/:id/members/:userId", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateMember)
+	orgs.Delete("/:id/members/:userId", middleware.HasPermission(models.PermissionWalletWrite), h.RemoveMember)
+
+	orgs.Get("/:id/wallets", h.GetWallets)
+	orgs.Post("/:id/wallets", middleware.HasPermission(models.PermissionWalletWrite), h.CreateWallet)
+	orgs.Put("/:id/wallets/:walletId", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateWallet)
+	orgs.Post("/:id/wallets/:walletId/fund", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.FundWallet)
+	orgs.Post("/:id/wallets/:walletId/payments", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.Pay)
+
+	orgs.Get("/:id/payments", h.GetPayments)
+	orgs.Get("/:id/payments/:paymentId/history", h.GetPaymentHistory)
+	orgs.Post("/:id/payments/:paymentId/approve", middleware.HasPermission(models.PermissionWalletWrite), h.ApprovePayment)
+	orgs.Post("/:id/payments/:paymentId/reject", middleware.HasPermission(models.PermissionWalletWrite), h.RejectPayment)
+	orgs.Post("/:id/payments/:paymentId/cancel", middleware.HasPermission(models.PermissionWalletWrite), h.CancelPayment)
+	orgs.Get("/:id/approvals", h.GetApprovals)
+
+	orgs.Get("/:id/approval-rules", h.GetRules)
+	orgs.Post("/:id/approval-rules", middleware.HasPermission(models.PermissionWalletWrite), h.CreateRule)
+	orgs.Put("/:id/approval-rules/:ruleId", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateRule)
+	orgs.Delete("/:id/approval-rules/:ruleId", middleware.HasPermission(models.PermissionWalletWrite), h.DeleteRule)
+
+	orgs.Get("/:id/statement", h.GetStatement)
+}
+
+func setupPotRoutes(router fiber.Router, h *handlers.PotHandler) {
+	pots := router.Group("/pots", middleware.HasPermission(models.PermissionWalletRead))
+
+	pots.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.CreatePot)
+	pots.Get("/", h.GetPots)
+	pots.Get("/:id", h.GetPot)
+	pots.Put("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.UpdatePot)
+	pots.Post("/:id/deposit", middleware.HasPermission(models.PermissionWalletWrite), h.Deposit)
+	pots.Post("/:id/withdraw", middleware.HasPermission(models.PermissionWalletWrite), h.Withdraw)
+	pots.Post("/:id/close", middleware.HasPermission(models.PermissionWalletWrite), h.ClosePot)
+	pots.Get("/:id/entries", h.GetPotEntries)
+}
+
+func setupAutoTopUpRoutes(router fiber.Router, h *handlers.AutoTopUpHandler) {
+	autoTopUp := router.Group("/wallet/auto-top-up", middleware.HasPermission(models.PermissionWalletRead))
+
+	autoTopUp.Get("/", h.GetRule)
+	autoTopUp.Put("/", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[autotopup.RuleRequest](), h.SaveRule)
+	autoTopUp.Delete("/", middleware.HasPermission(models.PermissionWalletWrite), h.DeleteRule)
+	autoTopUp.Get("/runs", h.ListRuns)
+}
+
+func setupFXRoutes(router fiber.Router, h *handlers.FXHandler) {
+	router.Get("/fx/rates", middleware.HasPermission(models.PermissionWalletRead), h.GetRates)
+}
+
+func setupContactRoutes(router fiber.Router, h *handlers.ContactHandler) {
+	contacts := router.Group("/contacts", middleware.HasPermission(models.PermissionWalletRead))
+
+	contacts.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.AddContact)
+	contacts.Get("/", h.GetContacts)
+	contacts.Get("/:id", h.GetContact)
+	contacts.Put("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.UpdateContact)
+	contacts.Delete("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.RemoveContact)
+	contacts.Post("/:id/favorite", middleware.HasPermission(models.PermissionWalletWrite), h.FavoriteContact)
+	contacts.Delete("/:id/favorite", middleware.HasPermission(models.PermissionWalletWrite), h.UnfavoriteContact)
+}
+
+func setupHandleRoutes(router fiber.Router, h *handlers.HandleHandler) {
+	router.Get("/resolve/:handle", middleware.HasPermission(models.PermissionWalletRead), h.Resolve)
+
+	profile := router.Group("/profile")
+	profile.Get("/handle", h.GetHandle)
+	profile.Put("/handle", h.ClaimHandle)
+	profile.Delete("/handle", h.ReleaseHandle)
+	profile.Put("/privacy", h.UpdatePrivacy)
+}
+
+func setupConfirmationRoutes(router fiber.Router, h *handlers.ConfirmationHandler) {
+	router.Get("/profile/payment-confirmations", h.GetSettings)
+	router.Put("/profile/payment-confirmations", h.UpdateSettings)
+}
+
+func setupSpendingControlRoutes(router fiber.Router, h *handlers.SpendingControlHandler) {
+	controls := router.Group("/spending-controls", middleware.HasPermission(models.PermissionWalletRead))
+	controls.Get("/", h.ListControls)
+	controls.Put("/", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[spendcontrol.ControlRequest](), h.SetControl)
+	controls.Delete("/:id", middleware.HasPermission(models.PermissionWalletWrite), h.DeleteControl)
+
+	// Controls organization owners and admins put on their members
+	members := router.Group("/enterprise/organizations/:id/members/:userId/spending-controls", middleware.HasPermission(models.PermissionWalletRead))
+	members.Get("/", h.ListMemberControls)
+	members.Put("/", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[spendcontrol.ControlRequest](), h.SetMemberControl)
+	members.Delete("/:controlId", middleware.HasPermission(models.PermissionWalletWrite), h.DeleteMemberControl)
+}
+
+func setupDeviceConfirmationRoutes(router fiber.Router, h *handlers.DeviceConfirmationHandler) {
+	devices := router.Group("/profile/trusted-devices")
+	devices.Get("/", h.ListDevices)
+	devices.Post("/", middleware.Validate[deviceconfirm.DeviceRequest](), h.RegisterDevice)
+	devices.Delete("/:id", h.RemoveDevice)
+
+	// Approved or denied from a trusted device, named by its X-Device-ID
+	confirmations := router.Group("/transfer-confirmations", middleware.HasPermission(models.PermissionWalletRead))
+	confirmations.Get("/", h.ListPending)
+	confirmations.Post("/:id/approve", middleware.HasPermission(models.PermissionWalletWrite), h.Approve)
+	confirmations.Post("/:id/deny", middleware.HasPermission(models.PermissionWalletWrite), h.Deny)
+}
+
+func setupScanConfirmationRoutes(router fiber.Router, h *handlers.ScanConfirmationHandler) {
+	router.Get("/profile/charge-confirmations", h.GetSettings)
+	router.Put("/profile/charge-confirmations", middleware.Validate[scanconfirm.SettingsRequest](), h.UpdateSettings)
+
+	// Merchant scans of the user's payment code held for their approval
+	confirmations := router.Group("/charge-confirmations", middleware.HasPermission(models.PermissionWalletRead))
+	confirmations.Get("/", h.ListPending)
+	confirmations.Post("/:id/approve", middleware.HasPermission(models.PermissionWalletWrite), h.Approve)
+	confirmations.Post("/:id/decline", middleware.HasPermission(models.PermissionWalletWrite), h.Decline)
+}
+
+func setupRefundRequestRoutes(router fiber.Router, h *handlers.RefundRequestHandler) {
+	// Customers ask for refunds of their charges
+	router.Post("/transactions/:id/refund-requests", middleware.HasPermission(models.PermissionWalletWrite), middleware.Validate[refundrequest.CreateRequest](), h.Request)
+	requests := router.Group("/refund-requests", middleware.HasPermission(models.PermissionWalletRead))
+	requests.Get("/", h.List)
+	requests.Post("/:id/cancel", middleware.HasPermission(models.PermissionWalletWrite), h.Cancel)
+
+	// Merchants set their policy and decide the requests it leaves to them
+	merchant := router.Group("/merchant", middleware.HasPermission(models.PermissionMerchantRead))
+	merchant.Get("/refund-policy", h.GetPolicy)
+	merchant.Put("/refund-policy", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[refundrequest.PolicyRequest](), h.UpdatePolicy)
+	merchant.Get("/refund-requests", h.ListForMerchant)
+	merchant.Post("/refund-requests/:id/approve", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[refundrequest.DecisionRequest](), h.Approve)
+	merchant.Post("/refund-requests/:id/reject", middleware.HasPermission(models.PermissionMerchantWrite), middleware.Validate[refundrequest.DecisionRequest](), h.Reject)
+}
+
+func setupPaymentPINRoutes(router fiber.Router, h *handlers.PaymentPINHandler) {
+	// Sent as X-Payment-PIN on large transfers and card removal
+	pin := router.Group("/profile/payment-pin")
+	pin.Get("/", h.GetStatus)
+	pin.Post("/", middleware.Validate[paymentpin.SetRequest](), h.SetPIN)
+	pin.Put("/", middleware.Validate[paymentpin.ChangeRequest](), h.ChangePIN)
+	pin.Post("/reset", middleware.Validate[paymentpin.ResetRequest](), h.ResetPIN)
+}
+
+func setupOAuthRoutes(router fiber.Router, h *handlers.OAuthHandler, paymentsLimit fiber.Handler) {
+	// Apps the user registered as a developer
+	clients := router.Group("/oauth/clients")
+	clients.Post("/", middleware.Validate[oauth.RegisterClientRequest](), h.RegisterClient)
+	clients.Get("/", h.ListClients)
+	clients.Delete("/:clientId", h.DeleteClient)
+
+	// The consent screen, and the user's decision on it
+	router.Get("/oauth/authorize", h.Consent)
+	router.Post("/oauth/authorize", h.Authorize)
+
+	// Apps the user authorized
+	router.Get("/oauth/connections", h.ListConnections)
+	router.Delete("/oauth/connections/:clientId", h.Disconnect)
+
+	// Payments apps asked for, approved like a transfer
+	requests := router.Group("/oauth/payment-requests", middleware.HasPermission(models.PermissionWalletRead))
+	requests.Get("/", h.ListPaymentRequests)
+	requests.Post("/:id/approve", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.ApprovePaymentRequest)
+	requests.Post("/:id/decline", middleware.HasPermission(models.PermissionWalletWrite), h.DeclinePaymentRequest)
+}
+
+func setupEscrowRoutes(router fiber.Router, h *handlers.EscrowHandler, paymentsLimit fiber.Handler) {
+	escrows := router.Group("/escrows", middleware.HasPermission(models.PermissionWalletRead))
+
+	escrows.Post("/", middleware.HasPermission(models.PermissionWalletWrite), paymentsLimit, h.CreateEscrow)
+	escrows.Get("/", h.GetEscrows)
+	escrows.Get("/:id", h.GetEscrow)
+	escrows.Post("/:id/confirm", middleware.HasPermission(models.PermissionWalletWrite), h.ConfirmEscrow)
+	escrows.Post("/:id/refund", middleware.HasPermission(models.PermissionWalletWrite), h.RefundEscrow)
+	escrows.Post("/:id/dispute", middleware.HasPermission(models.PermissionWalletWrite), h.DisputeEscrow)
+}
+
+func setupStaffRoutes(router fiber.Router, h *handlers.StaffHandler, paymentsLimit fiber.Handler) {
+	// Merchant side
+	team := router.Group("/merchant/staff", middleware.HasPermission(models.PermissionMerchantRead))
+	team.Get("/", h.ListStaff)
+	team.Get("/shifts", h.GetShiftReport)
+	team.Post("/", middleware.HasPermission(models.PermissionMerchantWrite), h.InviteStaff)
+	team.Put("/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.UpdateStaff)
+	team.Delete("/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.RevokeStaff)
+
+	// Staff side; access comes from the membership, not the user's role
+	memberships := router.Group("/staff/memberships")
+	memberships.Get("/", h.ListMemberships)
+	memberships.Post("/:id/accept", h.AcceptInvite)
+	memberships.Post("/:id/decline", h.DeclineInvite)
+	memberships.Put("/:id/pin", h.SetPIN)
+	memberships.Post("/:id/charge", paymentsLimit, h.StaffCharge)
+	memberships.Get("/:id/charge-confirmations/:confirmationId", h.StaffChargeConfirmation)
+	memberships.Post("/:id/refund", paymentsLimit, h.StaffRefund)
+	memberships.Get("/:id/shifts", h.StaffShiftReport)
+}
+
+func setupTerminalRoutes(router fiber.Router, h *handlers.TerminalHandler) {
+	terminals := router.Group("/merchant/terminals", middleware.HasPermission(models.PermissionMerchantRead))
+	terminals.Get("/", h.ListTerminals)
+	terminals.Get("/:id", h.GetTerminal)
+	terminals.Get("/:id/transactions", h.GetTerminalTransactions)
+	terminals.Post("/", middleware.HasPermission(models.PermissionMerchantWrite), h.RegisterTerminal)
+	terminals.Post("/:id/pairing-code", middleware.HasPermission(models.PermissionMerchantWrite), h.NewPairingCode)
+	terminals.Post("/:id/deactivate", middleware.HasPermission(models.PermissionMerchantWrite), h.DeactivateTerminal)
+}
+
+func setupReserveRoutes(router fiber.Router, h *handlers.ReserveHandler) {
+	reserves := router.Group("/merchant/reserve", middleware.HasPermission(models.PermissionMerchantRead))
+	reserves.Get("/", h.GetReserve)
+	reserves.Get("/entries", h.ListEntries)
+}
+
+func setupSubscriptionRoutes(router fiber.Router, h *handlers.SubscriptionHandler) {
+	// Merchant side
+	plans := router.Group("/merchant/subscription-plans", middleware.HasPermission(models.PermissionMerchantRead))
+	plans.Get("/", h.ListPlans)
+	plans.Get("/:id", h.GetPlan)
+	plans.Post("/", middleware.HasPermission(models.PermissionMerchantWrite), h.CreatePlan)
+	plans.Put("/:id", middleware.HasPermission(models.PermissionMerchantWrite), h.UpdatePlan)
+	plans.Post("/:id/archive", middleware.HasPermission(models.PermissionMerchantWrite), h.ArchivePlan)
+
+	subscribers := router.Group("/merchant/subscriptions", middleware.HasPermission(models.PermissionMerchantRead))
+	subscribers.Get("/", h.ListSubscribers)
+	subscribers.Get("/:id", h.GetSubscriber)
+	subscribers.Post("/:id/cancel", middleware.HasPermission(models.PermissionMerchantWrite), h.CancelSubscriber)
+
+	// Customer side
+	router.Get("/subscription-plans/:id", middleware.HasPermission(models.PermissionWalletRead), h.ViewPlan)
+	subscriptions := router.Group("/subscriptions")
+	subscriptions.Get("/", middleware.HasPermission(models.PermissionWalletRead), h.ListSubscriptions)
+	subscriptions.Get("/:id", middleware.HasPermission(models.PermissionWalletRead), h.GetSubscription)
+	subscriptions.Post("/", middleware.HasPermission(models.PermissionWalletWrite), h.Subscribe)
+	subscriptions.Post("/:id/cancel", middleware.HasPermission(models.PermissionWalletWrite), h.CancelSubscription)
+	subscriptions.Post("/:id/pause", middleware.HasPermission(models.PermissionWalletWrite), h.PauseSubscription)
+	subscriptions.Post("/:id/resume", middleware.HasPermission(models.PermissionWalletWrite), h.ResumeSubscription)
+}
+
+func setupPromotionRoutes(router fiber.Router, h *handlers.PromotionHandler) {
+	promotions := router.Group("/promotions")
+	promotions.Get("/", middleware.HasPermission(models.PermissionWalletRead), h.GetOffers)
+	promotions.Get("/rewards", middleware.HasPermission(models.PermissionWalletRead), h.GetRewards)
+	promotions.Post("/redeem", middleware.HasPermission(models.PermissionWalletWrite), h.RedeemCode)
+}
+
+func setupLoyaltyRoutes(router fiber.Router, h *handlers.LoyaltyHandler) {
+	// Merchant side
+	program := router.Group("/merchant/loyalty", middleware.HasPermission(models.PermissionMerchantRead))
+	program.Get("/", h.GetProgram)
+	program.Put("/", middleware.HasPermission(models.PermissionMerchantWrite), h.SaveProgram)
+	program.Get("/members", h.ListMembers)
+
+	// Customer side
+	points := router.Group("/loyalty", middleware.HasPermission(models.PermissionWalletRead))
+	points.Get("/", h.ListAccounts)
+	points.Get("/:merchantId", h.GetAccount)
+	points.Get("/:merchantId/entries", h.ListEntries)
+	points.Get("/:merchantId/quote", h.QuoteRedemption)
+}
+
+func setupMerchantInsightRoutes(router fiber.Router, h *handlers.DashboardHandler) {
+	router.Get("/merchant/customers", middleware.HasPermission(models.PermissionMerchantRead), h.GetCustomerInsights)
+	router.Get("/merchant/earnings/forecast", middleware.HasPermission(models.PermissionMerchantRead), h.GetEarningsForecast)
+}
+
+func setupWebhookRoutes(router fiber.Router, h *handlers.WebhookHandler) {
+	webhooks := router.Group("/merchant/webhooks", middleware.HasPermission(models.PermissionMerchantRead))
+	webhooks.Get("/events", h.ListTestEvents)
+	webhooks.Post("/test", middleware.HasPermission(models.PermissionMerchantWrite), h.SendTest)
+	webhooks.Get("/deliveries", h.ListDeliveries)
+	webhooks.Post("/deliveries/:eventId/replay", middleware.HasPermission(models.PermissionMerchantWrite), h.Replay)
+}
+
+func setupSandboxRoutes(router fiber.Router, h *handlers.SandboxHandler, paymentsLimit fiber.Handler) {
+	sandbox := router.Group("/sandbox", middleware.SandboxModeOnly())
+	sandbox.Get("/scenarios", h.GetScenarios)
+	sandbox.Get("/balance", h.GetBalance)
+	sandbox.Delete("/data", h.Reset)
+
+	customers := sandbox.Group("/customers")
+	customers.Post("/", h.CreateCustomer)
+	customers.Get("/", h.ListCustomers)
+	customers.Get("/:id", h.GetCustomer)
+	customers.Post("/:id/fund", h.FundCustomer)
+
+	charges := sandbox.Group("/charges")
+	charges.Post("/", paymentsLimit, h.CreateCharge)
+	charges.Get("/", h.ListCharges)
+	charges.Get("/:id", h.GetCharge)
+	charges.Post("/:id/settle", h.SettleCharge)
+	charges.Post("/:id/refund", paymentsLimit, h.Refund)
 }
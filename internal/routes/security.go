@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"orus/internal/config"
+	"orus/internal/middleware"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// checkoutPaths are the public checkout pages; merchants call and embed
+// them from their own sites, so they get the checkout security policy
+var checkoutPaths = []string{
+	"/api/pay/",
+	"/api/checkout/hosted/",
+	"/api/invoices/public/",
+}
+
+// SetupSecurity applies the configured CORS and security headers. Register
+// it before the routes it covers.
+func SetupSecurity(app *fiber.App, cfg config.SecurityConfig) {
+	app.Use(middleware.Security(middleware.SecurityPolicy{
+		AllowedOrigins:        cfg.AllowedOrigins,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		FrameOptions:          "DENY",
+	}, checkoutPaths...))
+
+	// The app calls the checkout pages too, so its origins stay allowed
+	origins := slices.Clone(cfg.Checkout.AllowedOrigins)
+	if !slices.Contains(origins, "*") {
+		origins = append(origins, cfg.AllowedOrigins...)
+	}
+	ancestors := "'none'"
+	if len(cfg.Checkout.FrameAncestors) > 0 {
+		ancestors = strings.Join(cfg.Checkout.FrameAncestors, " ")
+	}
+	checkout := middleware.Security(middleware.SecurityPolicy{
+		AllowedOrigins:        origins,
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors " + ancestors,
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+	})
+	for _, path := range checkoutPaths {
+		app.Use(path, checkout)
+	}
+}
@@ -0,0 +1,32 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"orus/internal/utils/storage"
+	"strings"
+)
+
+// S3Delivery uploads journals to a bucket on an S3-compatible service
+type S3Delivery struct {
+	store  storage.Storage
+	bucket string
+	prefix string
+}
+
+// NewS3Delivery uploads journals under prefix in the bucket cfg names
+func NewS3Delivery(cfg storage.Config, prefix string) (*S3Delivery, error) {
+	store, err := storage.NewS3(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &S3Delivery{store: store, bucket: cfg.Bucket, prefix: strings.TrimLeft(prefix, "/")}, nil
+}
+
+func (d *S3Delivery) Deliver(ctx context.Context, file *File) (string, error) {
+	key := d.prefix + file.Name
+	if err := d.store.Put(ctx, key, file.Content, file.ContentType); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("s3://%s/%s", d.bucket, key), nil
+}
@@ -0,0 +1,12 @@
+package accounting
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownFormat  = errors.New("format must be csv, ofx, quickbooks or xero")
+	ErrUnknownAccount = errors.New("unknown internal account")
+	ErrInvalidDate    = errors.New("date must be YYYY-MM-DD")
+	ErrDayNotOver     = errors.New("only days that are over can be exported")
+	ErrDeliveryFailed = errors.New("journal delivery failed")
+)
@@ -0,0 +1,248 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"orus/internal/models"
+	"strconv"
+	"time"
+)
+
+// render writes the journal in format
+func render(journal *Journal, format string) (*File, error) {
+	day := journal.Date.Format(time.DateOnly)
+	var (
+		file = &File{ContentType: "text/csv"}
+		err  error
+	)
+	switch format {
+	case models.AccountingFormatCSV:
+		file.Name = "journal-" + day + ".csv"
+		file.Content, err = writeCSV(journal)
+	case models.AccountingFormatQuickBooks:
+		file.Name = "journal-" + day + "-quickbooks.csv"
+		file.Content, err = writeQuickBooks(journal)
+	case models.AccountingFormatXero:
+		file.Name = "journal-" + day + "-xero.csv"
+		file.Content, err = writeXero(journal)
+	case models.AccountingFormatOFX:
+		file.Name = "journal-" + day + ".ofx"
+		file.ContentType = "application/x-ofx"
+		file.Content, err = writeOFX(journal)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// writeCSV lists every line with both the internal and the chart's account
+func writeCSV(journal *Journal) ([]byte, error) {
+	return writeRecords(
+		[]string{"entry", "date", "account", "account_code", "account_name", "debit", "credit", "currency", "description", "source"},
+		journal,
+		func(entry Entry, line Line) []string {
+			return []string{
+				entry.Number,
+				entry.Time.UTC().Format(time.RFC3339),
+				line.Account.Internal,
+				line.Account.Code,
+				line.Account.Name,
+				amount(line.Debit),
+				amount(line.Credit),
+				entry.Currency,
+				entry.Description,
+				entry.Source,
+			}
+		})
+}
+
+// writeQuickBooks follows the QuickBooks Online journal entry import, which
+// groups lines into entries by journal number and matches accounts by name
+func writeQuickBooks(journal *Journal) ([]byte, error) {
+	return writeRecords(
+		[]string{"JournalNo", "JournalDate", "Currency", "Memo", "Account", "Debits", "Credits", "Description"},
+		journal,
+		func(entry Entry, line Line) []string {
+			return []string{
+				entry.Number,
+				entry.Time.UTC().Format("01/02/2006"),
+				entry.Currency,
+				entry.Source,
+				line.Account.Name,
+				blankZero(line.Debit),
+				blankZero(line.Credit),
+				entry.Description,
+			}
+		})
+}
+
+// writeXero follows the Xero manual journal import, which groups lines into
+// journals by narration and date, matches accounts by code and takes
+// debits as positive amounts and credits as negative ones
+func writeXero(journal *Journal) ([]byte, error) {
+	return writeRecords(
+		[]string{"*Narration", "*Date", "Description", "*AccountCode", "*TaxRate", "*Amount"},
+		journal,
+		func(entry Entry, line Line) []string {
+			return []string{
+				entry.Number + " " + entry.Description,
+				entry.Time.UTC().Format("02/01/2006"),
+				entry.Source,
+				line.Account.Code,
+				"Tax Exempt",
+				amount(line.Debit - line.Credit),
+			}
+		})
+}
+
+func writeRecords(header []string, journal *Journal, record func(Entry, Line) []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, entry := range journal.Entries {
+		for _, line := range entry.Lines {
+			if err := w.Write(record(entry, line)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// OFX 2.2 has no journal, so the journal becomes one statement per account
+// and currency, crediting and debiting it as a bank statement would
+type ofxDocument struct {
+	XMLName xml.Name      `xml:"OFX"`
+	SignOn  ofxSignOn     `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    []ofxResponse `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	Server   string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxResponse struct {
+	TrnUID    string       `xml:"TRNUID"`
+	Status    ofxStatus    `xml:"STATUS"`
+	Currency  string       `xml:"STMTRS>CURDEF"`
+	BankID    string       `xml:"STMTRS>BANKACCTFROM>BANKID"`
+	AccountID string       `xml:"STMTRS>BANKACCTFROM>ACCTID"`
+	Type      string       `xml:"STMTRS>BANKACCTFROM>ACCTTYPE"`
+	Start     string       `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+	End       string       `xml:"STMTRS>BANKTRANLIST>DTEND"`
+	Lines     []ofxLine    `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+	Balance   ofxLedgerBal `xml:"STMTRS>LEDGERBAL"`
+}
+
+type ofxLine struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FITID  string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+	Memo   string `xml:"MEMO"`
+}
+
+// ofxLedgerBal is the day's net movement; the journal doesn't know the
+// accounts' balances in the accounting system
+type ofxLedgerBal struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+const ofxTime = "20060102150405"
+
+func writeOFX(journal *Journal) ([]byte, error) {
+	start := journal.Date.UTC()
+	end := start.AddDate(0, 0, 1)
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc := ofxDocument{SignOn: ofxSignOn{Status: ok, Server: time.Now().UTC().Format(ofxTime), Language: "ENG"}}
+
+	type statementKey struct{ account, currency string }
+	statements := make(map[statementKey]int)
+	nets := make(map[statementKey]float64)
+	for _, entry := range journal.Entries {
+		for i, line := range entry.Lines {
+			key := statementKey{line.Account.Code, entry.Currency}
+			n, found := statements[key]
+			if !found {
+				n = len(doc.Bank)
+				statements[key] = n
+				doc.Bank = append(doc.Bank, ofxResponse{
+					TrnUID:    strconv.Itoa(n + 1),
+					Status:    ok,
+					Currency:  entry.Currency,
+					BankID:    "ORUS",
+					AccountID: line.Account.Code,
+					Type:      "CHECKING",
+					Start:     start.Format(ofxTime),
+					End:       end.Format(ofxTime),
+				})
+			}
+
+			net := line.Credit - line.Debit
+			nets[key] += net
+			trnType := "CREDIT"
+			if net < 0 {
+				trnType = "DEBIT"
+			}
+			doc.Bank[n].Lines = append(doc.Bank[n].Lines, ofxLine{
+				Type:   trnType,
+				Posted: entry.Time.UTC().Format(ofxTime),
+				Amount: amount(net),
+				FITID:  entry.Number + "-" + strconv.Itoa(i+1),
+				Name:   truncate(entry.Description, 32),
+				Memo:   entry.Source,
+			})
+		}
+	}
+	for key, n := range statements {
+		doc.Bank[n].Balance = ofxLedgerBal{Amount: amount(round(nets[key])), AsOf: end.Format(ofxTime)}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func blankZero(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return amount(v)
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
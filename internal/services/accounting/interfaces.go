@@ -0,0 +1,32 @@
+package accounting
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// Deliverer sends journal files on to the accounting system
+type Deliverer interface {
+	// Deliver uploads the file and returns where it was stored
+	Deliver(ctx context.Context, file *File) (string, error)
+}
+
+// Service builds daily double-entry journals from the ledger, in the
+// formats accounting systems import, and delivers them every day
+type Service interface {
+	// Accounts returns the chart of accounts the journals post to
+	Accounts() []Account
+	// Journal builds the journal of a UTC day
+	Journal(ctx context.Context, date time.Time) (*Journal, error)
+	// Render builds the journal of a UTC day in format, for download
+	Render(ctx context.Context, date time.Time, format string) (*File, error)
+
+	// Export renders and delivers a day's journal again, whatever became
+	// of its earlier exports
+	Export(ctx context.Context, actorID uint, req ExportRequest) (*models.AccountingExport, error)
+	// ExportDue exports yesterday's journal in every configured format not
+	// exported yet and returns how many it exported
+	ExportDue(ctx context.Context) (int, error)
+	ListExports(ctx context.Context, format string, limit, offset int) ([]models.AccountingExport, int64, error)
+}
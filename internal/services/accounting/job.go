@@ -0,0 +1,24 @@
+package accounting
+
+import (
+	"context"
+	"log"
+)
+
+// Job exports yesterday's accounting journals from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the accounting service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "accounting-exports" }
+
+func (j *Job) Run(ctx context.Context) error {
+	exported, err := j.service.ExportDue(ctx)
+	if exported > 0 {
+		log.Printf("Exported %d accounting journals", exported)
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package accounting
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// chart returns every internal account, named as the mapping says
+func chart(mappings map[string]AccountMapping) ([]Account, error) {
+	accounts := make([]Account, 0, len(models.SystemAccountDefinitions)+2)
+	for _, def := range models.SystemAccountDefinitions {
+		accounts = append(accounts, Account{Internal: def.Code, Code: def.Code, Name: def.Name, Type: def.Type})
+	}
+	accounts = append(accounts,
+		Account{Internal: models.AccountingAccountCustomerFunds, Code: models.AccountingAccountCustomerFunds, Name: "Customer funds", Type: "liability"},
+		Account{Internal: models.AccountingAccountFundingClearing, Code: models.AccountingAccountFundingClearing, Name: "Funding clearing", Type: "asset"},
+	)
+
+	for internal, mapping := range mappings {
+		i := slices.IndexFunc(accounts, func(a Account) bool { return a.Internal == internal })
+		if i < 0 {
+			return nil, fmt.Errorf("%w %q", ErrUnknownAccount, internal)
+		}
+		if mapping.Code != "" {
+			accounts[i].Code = mapping.Code
+		}
+		if mapping.Name != "" {
+			accounts[i].Name = mapping.Name
+		}
+	}
+	return accounts, nil
+}
+
+// buildJournal turns a day's ledger postings and funding transactions
+// into balanced entries. System account postings are credits when
+// positive and debits when negative; their other side is customer funds,
+// except for treasury transfers, whose two postings form one entry.
+// Top-ups and withdrawals move money between funding clearing and
+// customer funds. Payments between wallets stay within customer funds
+// and aren't journaled; their fees are, as the postings to fee revenue.
+func (s *service) buildJournal(date time.Time, ledger []repositories.AccountingLedgerEntry, topUps, withdrawals []models.Transaction) *Journal {
+	var entries []Entry
+	transfers := make(map[uint]int)
+	for _, posting := range ledger {
+		amount := round(posting.Amount)
+		if amount == 0 {
+			continue
+		}
+		line := s.line(posting.AccountCode, amount)
+
+		if posting.TransferID != nil {
+			if i, ok := transfers[*posting.TransferID]; ok {
+				entries[i].Lines = append(entries[i].Lines, line)
+				continue
+			}
+			transfers[*posting.TransferID] = len(entries)
+			entries = append(entries, Entry{
+				Time:        posting.CreatedAt,
+				Description: posting.Description,
+				Source:      "transfer:" + strconv.FormatUint(uint64(*posting.TransferID), 10),
+				Currency:    posting.Currency,
+				Lines:       []Line{line},
+			})
+			continue
+		}
+
+		entries = append(entries, Entry{
+			Time:        posting.CreatedAt,
+			Description: posting.Description,
+			Source:      "ledger:" + strconv.FormatUint(uint64(posting.ID), 10),
+			Currency:    posting.Currency,
+			Lines:       []Line{line, s.line(models.AccountingAccountCustomerFunds, -amount)},
+		})
+	}
+
+	funding := func(tx models.Transaction, description string, in bool) {
+		amount := round(tx.Amount)
+		if amount == 0 {
+			return
+		}
+		if !in {
+			amount = -amount
+		}
+		entries = append(entries, Entry{
+			Time:        tx.CreatedAt,
+			Description: fmt.Sprintf("%s %s", description, reference(tx)),
+			Source:      "transaction:" + strconv.FormatUint(uint64(tx.ID), 10),
+			Currency:    tx.Currency,
+			Lines: []Line{
+				s.line(models.AccountingAccountFundingClearing, -amount),
+				s.line(models.AccountingAccountCustomerFunds, amount),
+			},
+		})
+	}
+	for _, tx := range topUps {
+		funding(tx, "Top-up", true)
+	}
+	for _, tx := range withdrawals {
+		funding(tx, "Withdrawal", false)
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int { return a.Time.Compare(b.Time) })
+	journal := &Journal{Date: date, Entries: entries}
+	for i := range journal.Entries {
+		journal.Entries[i].Number = fmt.Sprintf("%s-%04d", date.Format("20060102"), i+1)
+		for _, line := range journal.Entries[i].Lines {
+			journal.Debits += line.Debit
+			journal.Credits += line.Credit
+		}
+	}
+	journal.Debits, journal.Credits = round(journal.Debits), round(journal.Credits)
+	return journal
+}
+
+// line posts amount to the internal account: a credit when positive, a
+// debit when negative
+func (s *service) line(internal string, amount float64) Line {
+	account, ok := s.accounts[internal]
+	if !ok {
+		// A system account added after the chart was built
+		account = Account{Internal: internal, Code: internal, Name: internal}
+	}
+	if amount < 0 {
+		return Line{Account: account, Debit: -amount}
+	}
+	return Line{Account: account, Credit: amount}
+}
+
+// reference names a transaction by its external reference when it has one
+func reference(tx models.Transaction) string {
+	return cmp.Or(tx.TransactionID, "#"+strconv.FormatUint(uint64(tx.ID), 10))
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,191 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxAttempts bounds how often the job retries a day's failed export;
+// admins can still export it again after that
+const maxAttempts = 5
+
+// staleAfter is how long an export may run before another instance
+// assumes it died and takes it over
+const staleAfter = 30 * time.Minute
+
+type service struct {
+	repo      repositories.AccountingRepository
+	deliverer Deliverer
+	formats   []string
+	chart     []Account
+	accounts  map[string]Account
+}
+
+// NewService creates the accounting export service. Formats and account
+// mappings in the configuration must exist, so a typo fails at startup
+// instead of leaving the journals wrong. A nil deliverer keeps journals
+// for download only.
+func NewService(repo repositories.AccountingRepository, deliverer Deliverer, cfg Config) (Service, error) {
+	for _, format := range cfg.Formats {
+		if !slices.Contains(Formats, format) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+		}
+	}
+	accounts, err := chart(cfg.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &service{
+		repo:      repo,
+		deliverer: deliverer,
+		formats:   slices.Compact(slices.Clone(cfg.Formats)),
+		chart:     accounts,
+		accounts:  make(map[string]Account, len(accounts)),
+	}
+	for _, account := range accounts {
+		s.accounts[account.Internal] = account
+	}
+	return s, nil
+}
+
+func (s *service) Accounts() []Account {
+	return slices.Clone(s.chart)
+}
+
+func (s *service) Journal(ctx context.Context, date time.Time) (*Journal, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	next := day.AddDate(0, 0, 1)
+
+	ledger, err := s.repo.GetLedgerEntries(day, next)
+	if err != nil {
+		return nil, err
+	}
+	topUps, withdrawals, err := s.repo.GetFundingTransactions(day, next)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildJournal(day, ledger, topUps, withdrawals), nil
+}
+
+func (s *service) Render(ctx context.Context, date time.Time, format string) (*File, error) {
+	if !slices.Contains(Formats, format) {
+		return nil, ErrUnknownFormat
+	}
+	journal, err := s.Journal(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return render(journal, format)
+}
+
+func (s *service) Export(ctx context.Context, actorID uint, req ExportRequest) (*models.AccountingExport, error) {
+	date, err := ParseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if !slices.Contains(Formats, format) {
+		return nil, ErrUnknownFormat
+	}
+
+	export, err := s.repo.Claim(date, format, &actorID, true, maxAttempts, time.Now().Add(-staleAfter))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d re-exporting the %s accounting journal of %s", actorID, format, req.Date)
+	if err := s.run(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *service) ExportDue(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+
+	exported := 0
+	var failed error
+	for _, format := range s.formats {
+		export, err := s.repo.Claim(yesterday, format, nil, false, maxAttempts, now.Add(-staleAfter))
+		if errors.Is(err, repositories.ErrAccountingExportBusy) {
+			// Exported already, running elsewhere or out of attempts
+			continue
+		}
+		if err != nil {
+			return exported, err
+		}
+		if err := s.run(ctx, export); err != nil {
+			log.Printf("Failed to export the %s accounting journal of %s: %v", format, yesterday.Format(time.DateOnly), err)
+			failed = err
+			continue
+		}
+		exported++
+	}
+	return exported, failed
+}
+
+// run renders and delivers a claimed export and records how it went
+func (s *service) run(ctx context.Context, export *models.AccountingExport) error {
+	err := s.deliver(ctx, export)
+	if err != nil {
+		export.Status = models.AccountingExportFailed
+		export.Error = err.Error()
+	} else {
+		now := time.Now()
+		export.Status = models.AccountingExportCompleted
+		export.CompletedAt = &now
+	}
+	if finishErr := s.repo.Finish(export); finishErr != nil {
+		return errors.Join(err, finishErr)
+	}
+	return err
+}
+
+func (s *service) deliver(ctx context.Context, export *models.AccountingExport) error {
+	journal, err := s.Journal(ctx, export.Date)
+	if err != nil {
+		return err
+	}
+	file, err := render(journal, export.Format)
+	if err != nil {
+		return err
+	}
+	export.Entries = len(journal.Entries)
+	export.Debits = journal.Debits
+	export.Credits = journal.Credits
+	export.FileName = file.Name
+
+	if s.deliverer == nil {
+		return nil
+	}
+	location, err := s.deliverer.Deliver(ctx, file)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	export.Location = location
+	return nil
+}
+
+func (s *service) ListExports(ctx context.Context, format string, limit, offset int) ([]models.AccountingExport, int64, error) {
+	return s.repo.List(format, limit, offset)
+}
+
+// ParseDate reads a YYYY-MM-DD day that's already over in UTC
+func ParseDate(value string) (time.Time, error) {
+	date, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	if !date.AddDate(0, 0, 1).After(time.Now().UTC()) {
+		return date, nil
+	}
+	return time.Time{}, ErrDayNotOver
+}
@@ -0,0 +1,278 @@
+package accounting
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPConfig is the server journals are uploaded to
+type SFTPConfig struct {
+	Host           string
+	Port           int
+	User           string
+	Password       string
+	PrivateKeyFile string
+	// HostKey is the server's public key in authorized_keys format
+	HostKey string
+	Dir     string
+}
+
+// SFTPDelivery uploads journals to a directory on an SFTP server. Each
+// file is written under a temporary name and renamed once complete, so
+// the accounting system never picks up half a journal.
+type SFTPDelivery struct {
+	addr   string
+	dir    string
+	config *ssh.ClientConfig
+}
+
+// NewSFTPDelivery checks the keys in cfg and returns the delivery; it
+// doesn't connect until the first journal
+func NewSFTPDelivery(cfg SFTPConfig) (*SFTPDelivery, error) {
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sftp host key: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if cfg.PrivateKeyFile != "" {
+		pem, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sftp private key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sftp private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("sftp delivery needs a private key or password")
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 22
+	}
+	return &SFTPDelivery{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		dir:  cfg.Dir,
+		config: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+			Timeout:         30 * time.Second,
+		},
+	}, nil
+}
+
+func (d *SFTPDelivery) Deliver(ctx context.Context, file *File) (string, error) {
+	dialer := net.Dialer{Timeout: d.config.Timeout}
+	raw, err := dialer.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to sftp server: %w", err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(raw, d.addr, d.config)
+	if err != nil {
+		raw.Close()
+		return "", fmt.Errorf("sftp handshake failed: %w", err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	defer stop()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("failed to open sftp session: %w", err)
+	}
+	defer session.Close()
+	w, err := session.StdinPipe()
+	if err != nil {
+		return "", err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return "", fmt.Errorf("sftp subsystem unavailable: %w", err)
+	}
+
+	conn := &sftpConn{w: w, r: r}
+	target := path.Join(d.dir, file.Name)
+	partial := target + ".part"
+	if err := conn.init(); err != nil {
+		return "", err
+	}
+	if err := conn.upload(partial, file.Content); err != nil {
+		return "", err
+	}
+	// SFTP v3 servers won't rename over an existing file; a re-exported
+	// journal replaces the earlier one
+	_ = conn.status(conn.request(sshFxpRemove, appendString(nil, []byte(target))))
+	if err := conn.status(conn.request(sshFxpRename, appendString(appendString(nil, []byte(partial)), []byte(target)))); err != nil {
+		return "", err
+	}
+	return "sftp://" + d.addr + target, nil
+}
+
+// The parts of SFTP version 3 needed to upload a file
+const (
+	sshFxpInit    = 1
+	sshFxpVersion = 2
+	sshFxpOpen    = 3
+	sshFxpClose   = 4
+	sshFxpWrite   = 6
+	sshFxpRemove  = 13
+	sshFxpRename  = 18
+	sshFxpStatus  = 101
+	sshFxpHandle  = 102
+
+	sshFxfWrite = 0x02
+	sshFxfCreat = 0x08
+	sshFxfTrunc = 0x10
+
+	sshFxOK = 0
+
+	sftpVersion   = 3
+	sftpChunkSize = 32 << 10
+	sftpMaxPacket = 256 << 10
+)
+
+var errSFTPReply = errors.New("sftp: unexpected reply from server")
+
+// sftpConn speaks SFTP over an SSH session, one request at a time
+type sftpConn struct {
+	w  io.Writer
+	r  io.Reader
+	id uint32
+}
+
+func (c *sftpConn) init() error {
+	if err := c.send(sshFxpInit, binary.BigEndian.AppendUint32(nil, sftpVersion)); err != nil {
+		return err
+	}
+	typ, _, err := c.recv()
+	if err != nil {
+		return err
+	}
+	if typ != sshFxpVersion {
+		return errSFTPReply
+	}
+	return nil
+}
+
+// upload creates or truncates name and writes content to it
+func (c *sftpConn) upload(name string, content []byte) error {
+	open := appendString(nil, []byte(name))
+	open = binary.BigEndian.AppendUint32(open, sshFxfWrite|sshFxfCreat|sshFxfTrunc)
+	open = binary.BigEndian.AppendUint32(open, 0) // No attributes
+	typ, reply, err := c.request(sshFxpOpen, open)
+	if err != nil {
+		return err
+	}
+	if typ == sshFxpStatus {
+		return c.status(typ, reply, nil)
+	}
+	handle, _, ok := readString(reply)
+	if typ != sshFxpHandle || !ok {
+		return errSFTPReply
+	}
+
+	for offset := 0; offset < len(content); offset += sftpChunkSize {
+		chunk := content[offset:min(offset+sftpChunkSize, len(content))]
+		write := appendString(nil, handle)
+		write = binary.BigEndian.AppendUint64(write, uint64(offset))
+		write = appendString(write, chunk)
+		if err := c.status(c.request(sshFxpWrite, write)); err != nil {
+			return err
+		}
+	}
+	return c.status(c.request(sshFxpClose, appendString(nil, handle)))
+}
+
+// request sends a request under the next ID and returns the reply after
+// its ID
+func (c *sftpConn) request(typ byte, body []byte) (byte, []byte, error) {
+	c.id++
+	payload := binary.BigEndian.AppendUint32(nil, c.id)
+	if err := c.send(typ, append(payload, body...)); err != nil {
+		return 0, nil, err
+	}
+	replyType, reply, err := c.recv()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(reply) < 4 || binary.BigEndian.Uint32(reply) != c.id {
+		return 0, nil, errSFTPReply
+	}
+	return replyType, reply[4:], nil
+}
+
+// status turns a status reply into an error unless it reports success
+func (c *sftpConn) status(typ byte, reply []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	if typ != sshFxpStatus || len(reply) < 4 {
+		return errSFTPReply
+	}
+	if code := binary.BigEndian.Uint32(reply); code != sshFxOK {
+		msg, _, _ := readString(reply[4:])
+		return fmt.Errorf("sftp: %s (status %d)", msg, code)
+	}
+	return nil
+}
+
+func (c *sftpConn) send(typ byte, payload []byte) error {
+	packet := binary.BigEndian.AppendUint32(make([]byte, 0, 5+len(payload)), uint32(1+len(payload)))
+	packet = append(packet, typ)
+	_, err := c.w.Write(append(packet, payload...))
+	return err
+}
+
+func (c *sftpConn) recv() (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return 0, nil, fmt.Errorf("sftp: %w", err)
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, errSFTPReply
+	}
+	payload := make([]byte, length-1)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, fmt.Errorf("sftp: %w", err)
+	}
+	return header[4], payload, nil
+}
+
+func appendString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func readString(b []byte) ([]byte, []byte, bool) {
+	if len(b) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(b)
+	if uint32(len(b)-4) < n {
+		return nil, nil, false
+	}
+	return b[4 : 4+n], b[4+n:], true
+}
@@ -0,0 +1,75 @@
+package accounting
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// Formats lists every journal format
+var Formats = []string{
+	models.AccountingFormatCSV,
+	models.AccountingFormatOFX,
+	models.AccountingFormatQuickBooks,
+	models.AccountingFormatXero,
+}
+
+// Config picks the formats exported each day and maps the internal
+// accounts to the accounting system's chart of accounts
+type Config struct {
+	Formats  []string
+	Accounts map[string]AccountMapping
+}
+
+// AccountMapping is an account in the accounting system's chart
+type AccountMapping struct {
+	Code string
+	Name string
+}
+
+// Account is an internal account and what it's called in the chart
+type Account struct {
+	Internal string `json:"internal"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Type     string `json:"type"` // asset, liability, revenue or expense
+}
+
+// Journal is a day's postings, every entry balanced
+type Journal struct {
+	Date    time.Time `json:"date"`
+	Entries []Entry   `json:"entries"`
+	Debits  float64   `json:"debits"`
+	Credits float64   `json:"credits"`
+}
+
+// Entry is one balanced journal entry
+type Entry struct {
+	Number      string    `json:"number"`
+	Time        time.Time `json:"time"`
+	Description string    `json:"description"`
+	// Source is what the entry was built from: ledger:<entry ID>,
+	// transfer:<treasury transfer ID> or transaction:<transaction ID>
+	Source   string `json:"source"`
+	Currency string `json:"currency"`
+	Lines    []Line `json:"lines"`
+}
+
+// Line debits or credits one account
+type Line struct {
+	Account Account `json:"account"`
+	Debit   float64 `json:"debit"`
+	Credit  float64 `json:"credit"`
+}
+
+// File is a journal rendered in one format
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// ExportRequest renders and delivers a day's journal again
+type ExportRequest struct {
+	Date   string `json:"date" validate:"required"` // YYYY-MM-DD
+	Format string `json:"format" validate:"required"`
+}
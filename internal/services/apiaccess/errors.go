@@ -0,0 +1,19 @@
+package apiaccess
+
+import "errors"
+
+// Service errors
+var (
+	ErrIPNotAllowed         = errors.New("requests from this IP address are not allowed for this API key")
+	ErrClientCertRequired   = errors.New("a client certificate is required for this API key")
+	ErrClientCertNotAllowed = errors.New("client certificate is not registered for this merchant")
+	ErrInvalidCIDR          = errors.New("IP range must be an IP address or CIDR block")
+	ErrInvalidKeyMode       = errors.New("key mode must be live or sandbox")
+	ErrTooManyIPRanges      = errors.New("too many IP ranges")
+	ErrInvalidCertificate   = errors.New("certificate must be a PEM encoded X.509 certificate")
+	ErrCertificateExpired   = errors.New("certificate has expired")
+	ErrCertificateExists    = errors.New("certificate is already registered")
+	ErrNoClientCertificates = errors.New("register a client certificate before requiring mutual TLS")
+	ErrLastCertificate      = errors.New("turn off mutual TLS before removing the last client certificate")
+	ErrNotMerchant          = errors.New("merchant profile not found")
+)
@@ -0,0 +1,24 @@
+package apiaccess
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages the IP allowlists and client certificates restricting
+// where a merchant's API keys may be used from, and enforces them
+type Service interface {
+	// Check admits or refuses a merchant API request. Refusals are logged
+	// and recorded for the merchant to review.
+	Check(ctx context.Context, merchant *models.Merchant, req Request) error
+
+	GetSettings(ctx context.Context, userID uint) (*Settings, error)
+	AddIPRange(ctx context.Context, userID uint, input AddIPRangeInput) (*models.MerchantIPRange, error)
+	RemoveIPRange(ctx context.Context, userID, id uint) error
+	AddCertificate(ctx context.Context, userID uint, input AddCertificateInput) (*models.MerchantClientCert, error)
+	RemoveCertificate(ctx context.Context, userID, id uint) error
+	// SetRequireMTLS turns the mutual TLS requirement on or off. It can
+	// only be turned on once a certificate is registered.
+	SetRequireMTLS(ctx context.Context, userID uint, require bool) (*Settings, error)
+	ListRejections(ctx context.Context, userID uint, limit, offset int) ([]models.MerchantAPIRejection, int64, error)
+}
@@ -0,0 +1,308 @@
+package apiaccess
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"log"
+	"net/netip"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+// MaxIPRanges caps how many ranges a merchant can allow per key mode
+const MaxIPRanges = 50
+
+// recentRejections is how many rejected requests the settings show
+const recentRejections = 10
+
+type service struct {
+	repo      repositories.MerchantAPIAccessRepository
+	merchants repositories.MerchantRepository
+}
+
+// NewService creates a new merchant API access service
+func NewService(repo repositories.MerchantAPIAccessRepository, merchants repositories.MerchantRepository) Service {
+	return &service{
+		repo:      repo,
+		merchants: merchants,
+	}
+}
+
+func (s *service) Check(ctx context.Context, merchant *models.Merchant, req Request) error {
+	mode := models.APIKeyModeLive
+	if req.Sandbox {
+		mode = models.APIKeyModeSandbox
+	}
+
+	allowed, err := s.ipAllowed(ctx, merchant.ID, mode, req.IP)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		s.reject(ctx, merchant, mode, models.APIRejectionIPNotAllowed, req)
+		return ErrIPNotAllowed
+	}
+
+	if !merchant.RequireMTLS {
+		return nil
+	}
+	if req.Fingerprint == "" {
+		s.reject(ctx, merchant, mode, models.APIRejectionClientCertMissing, req)
+		return ErrClientCertRequired
+	}
+	registered, err := s.repo.HasClientCert(ctx, merchant.ID, req.Fingerprint)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		s.reject(ctx, merchant, mode, models.APIRejectionClientCertNotAllowed, req)
+		return ErrClientCertNotAllowed
+	}
+	return nil
+}
+
+// ipAllowed reports whether ip falls in one of the merchant's ranges for
+// the key mode. A mode without ranges allows every address.
+func (s *service) ipAllowed(ctx context.Context, merchantID uint, mode, ip string) (bool, error) {
+	ranges, err := s.repo.ListIPRanges(ctx, merchantID)
+	if err != nil {
+		return false, err
+	}
+
+	addr, addrErr := netip.ParseAddr(ip)
+	restricted := false
+	for _, r := range ranges {
+		if r.KeyMode != mode {
+			continue
+		}
+		restricted = true
+		prefix, err := netip.ParsePrefix(r.CIDR)
+		if err != nil || addrErr != nil {
+			continue
+		}
+		if prefix.Contains(addr.Unmap()) {
+			return true, nil
+		}
+	}
+	return !restricted, nil
+}
+
+func (s *service) reject(ctx context.Context, merchant *models.Merchant, mode, reason string, req Request) {
+	log.Printf("Merchant API request rejected: merchant=%d mode=%s reason=%s ip=%s %s %s",
+		merchant.ID, mode, reason, req.IP, req.Method, req.Path)
+
+	err := s.repo.RecordRejection(context.WithoutCancel(ctx), &models.MerchantAPIRejection{
+		MerchantID:  merchant.ID,
+		KeyMode:     mode,
+		Reason:      reason,
+		IP:          req.IP,
+		Method:      req.Method,
+		Path:        req.Path,
+		Fingerprint: req.Fingerprint,
+	})
+	if err != nil {
+		log.Printf("Failed to record API rejection for merchant %d: %v", merchant.ID, err)
+	}
+}
+
+func (s *service) GetSettings(ctx context.Context, userID uint) (*Settings, error) {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+	return s.settings(ctx, merchant)
+}
+
+func (s *service) settings(ctx context.Context, merchant *models.Merchant) (*Settings, error) {
+	ranges, err := s.repo.ListIPRanges(ctx, merchant.ID)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := s.repo.ListClientCerts(ctx, merchant.ID)
+	if err != nil {
+		return nil, err
+	}
+	rejections, _, err := s.repo.ListRejections(ctx, merchant.ID, recentRejections, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Settings{
+		RequireMTLS:      merchant.RequireMTLS,
+		IPRanges:         ranges,
+		Certificates:     certs,
+		RecentRejections: rejections,
+	}, nil
+}
+
+// AddIPRange allows a key to be used from an address or CIDR block. The
+// first range added for a mode restricts that key to its ranges.
+func (s *service) AddIPRange(ctx context.Context, userID uint, input AddIPRangeInput) (*models.MerchantIPRange, error) {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+	if input.KeyMode != models.APIKeyModeLive && input.KeyMode != models.APIKeyModeSandbox {
+		return nil, ErrInvalidKeyMode
+	}
+	prefix, err := parseRange(input.CIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := s.repo.ListIPRanges(ctx, merchant.ID)
+	if err != nil {
+		return nil, err
+	}
+	count := 0
+	for _, r := range ranges {
+		if r.KeyMode == input.KeyMode {
+			count++
+		}
+	}
+	if count >= MaxIPRanges {
+		return nil, ErrTooManyIPRanges
+	}
+
+	ipRange := &models.MerchantIPRange{
+		MerchantID: merchant.ID,
+		CIDR:       prefix.String(),
+		KeyMode:    input.KeyMode,
+		Label:      strings.TrimSpace(input.Label),
+	}
+	if err := s.repo.CreateIPRange(ctx, ipRange); err != nil {
+		return nil, err
+	}
+	return ipRange, nil
+}
+
+// parseRange accepts a CIDR block or a single address, returning the
+// masked block
+func parseRange(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if addr, err := netip.ParseAddr(value); err == nil {
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, ErrInvalidCIDR
+	}
+	return prefix.Masked(), nil
+}
+
+func (s *service) RemoveIPRange(ctx context.Context, userID, id uint) error {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return ErrNotMerchant
+	}
+	return s.repo.DeleteIPRange(ctx, merchant.ID, id)
+}
+
+// AddCertificate registers a client certificate. Only its fingerprint,
+// subject and expiry are kept.
+func (s *service) AddCertificate(ctx context.Context, userID uint, input AddCertificateInput) (*models.MerchantClientCert, error) {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	block, _ := pem.Decode([]byte(input.Certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, ErrInvalidCertificate
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidCertificate
+	}
+	if time.Now().After(parsed.NotAfter) {
+		return nil, ErrCertificateExpired
+	}
+
+	fingerprint := Fingerprint(parsed.Raw)
+	exists, err := s.repo.HasClientCert(ctx, merchant.ID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrCertificateExists
+	}
+
+	cert := &models.MerchantClientCert{
+		MerchantID:  merchant.ID,
+		Fingerprint: fingerprint,
+		Subject:     parsed.Subject.String(),
+		NotAfter:    parsed.NotAfter,
+	}
+	if err := s.repo.CreateClientCert(ctx, cert); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// RemoveCertificate deletes a client certificate, refusing to remove the
+// last one while mutual TLS is required so the merchant can't lock out
+// their own integration
+func (s *service) RemoveCertificate(ctx context.Context, userID, id uint) error {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return ErrNotMerchant
+	}
+	if merchant.RequireMTLS {
+		certs, err := s.repo.ListClientCerts(ctx, merchant.ID)
+		if err != nil {
+			return err
+		}
+		if len(certs) == 1 && certs[0].ID == id {
+			return ErrLastCertificate
+		}
+	}
+	return s.repo.DeleteClientCert(ctx, merchant.ID, id)
+}
+
+func (s *service) SetRequireMTLS(ctx context.Context, userID uint, require bool) (*Settings, error) {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+	if require {
+		certs, err := s.repo.ListClientCerts(ctx, merchant.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(certs) == 0 {
+			return nil, ErrNoClientCertificates
+		}
+	}
+	if err := s.repo.SetRequireMTLS(ctx, merchant.ID, require); err != nil {
+		return nil, err
+	}
+	merchant.RequireMTLS = require
+	return s.settings(ctx, merchant)
+}
+
+func (s *service) ListRejections(ctx context.Context, userID uint, limit, offset int) ([]models.MerchantAPIRejection, int64, error) {
+	merchant, err := s.merchants.GetByUserID(userID)
+	if err != nil {
+		return nil, 0, ErrNotMerchant
+	}
+	return s.repo.ListRejections(ctx, merchant.ID, limit, offset)
+}
+
+// Fingerprint is the SHA-256 fingerprint of a DER encoded certificate as
+// lowercase hex, the form certificates are registered and matched in
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint turns a fingerprint as forwarded by a TLS
+// terminating proxy, in any case and optionally colon separated, into the
+// form Fingerprint returns
+func NormalizeFingerprint(value string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), ":", ""))
+}
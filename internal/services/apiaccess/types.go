@@ -0,0 +1,41 @@
+package apiaccess
+
+import "orus/internal/models"
+
+// Request is what the access rules are checked against
+type Request struct {
+	IP     string
+	Method string
+	Path   string
+	// Sandbox is set for requests made with the sandbox API key
+	Sandbox bool
+	// Fingerprint is the SHA-256 fingerprint of the client certificate,
+	// lowercase hex, or empty when none was presented
+	Fingerprint string
+}
+
+// Settings is the merchant security page: the access rules and the
+// requests they recently refused
+type Settings struct {
+	RequireMTLS      bool                          `json:"require_mtls"`
+	IPRanges         []models.MerchantIPRange      `json:"ip_ranges"`
+	Certificates     []models.MerchantClientCert   `json:"certificates"`
+	RecentRejections []models.MerchantAPIRejection `json:"recent_rejections"`
+}
+
+// AddIPRangeInput allows a key to be used from an address or CIDR block
+type AddIPRangeInput struct {
+	CIDR    string `json:"cidr" validate:"required"`
+	KeyMode string `json:"key_mode" validate:"required,oneof=live sandbox"`
+	Label   string `json:"label" validate:"max=100"`
+}
+
+// AddCertificateInput registers a client certificate for mutual TLS
+type AddCertificateInput struct {
+	Certificate string `json:"certificate" validate:"required"`
+}
+
+// SetMTLSInput turns the mutual TLS requirement on or off
+type SetMTLSInput struct {
+	Require bool `json:"require"`
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted for signing tokens
+const minRSABits = 2048
+
+var (
+	// ErrUnknownSigningKey is returned for a token whose key ID isn't in
+	// the key set, say one signed with a key since removed
+	ErrUnknownSigningKey = errors.New("token signed with an unknown key")
+	// ErrUnexpectedSigningMethod is returned for a token signed with an
+	// algorithm other than its key's
+	ErrUnexpectedSigningMethod = errors.New("unexpected token signing method")
+)
+
+// signingKey is one asymmetric key of the set
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// KeySet signs access tokens and verifies them. With a shared secret it
+// signs with HS256; with a key directory it signs with the active RS256 or
+// EdDSA key, naming it in the token's kid header, and verifies with
+// whichever key the token names. Rotating adds a key and makes it active
+// while the old one keeps verifying the tokens it signed, until it is
+// removed once they have expired.
+type KeySet struct {
+	secret []byte
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// NewSecretKeySet signs and verifies tokens with HS256 under secret
+func NewSecretKeySet(secret string) *KeySet {
+	return &KeySet{secret: []byte(secret)}
+}
+
+// LoadKeySet reads the PEM private keys in dir, RSA or Ed25519 each named
+// <key id>.pem, and signs with the one named activeKID
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{keys: make(map[string]*signingKey)}
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".pem")
+		key, err := loadSigningKey(id, path)
+		if err != nil {
+			return nil, err
+		}
+		set.keys[id] = key
+	}
+	if len(set.keys) == 0 {
+		return nil, fmt.Errorf("no signing keys in %s", dir)
+	}
+
+	active, ok := set.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found in %s", activeKID, dir)
+	}
+	set.active = active
+	return set, nil
+}
+
+func loadSigningKey(id, path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", id)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("failed to parse signing key %s: %w", id, err)
+		}
+		private = rsaKey
+	}
+
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("signing key %s is %d bits; RSA keys need at least %d", id, k.N.BitLen(), minRSABits)
+		}
+		return &signingKey{id: id, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &signingKey{id: id, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	default:
+		return nil, fmt.Errorf("signing key %s must be RSA or Ed25519, not %T", id, private)
+	}
+}
+
+// Sign signs claims with the active key
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if k.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.id
+	return token.SignedString(k.active.private)
+}
+
+// Keyfunc finds the key verifying token, refusing any algorithm other than
+// the key's so a public key can't be passed off as an HMAC secret
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if k.active == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrUnexpectedSigningMethod
+		}
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnexpectedSigningMethod
+	}
+	return key.public, nil
+}
+
+// JWK is a public key as published in the JWKS
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is the JSON Web Key Set other services verify access tokens with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the set's public keys. It is empty with a shared secret,
+// which can't be published.
+func (k *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range k.keys {
+		jwk := JWK{KeyID: key.id, Use: "sig", Algorithm: key.method.Alg()}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
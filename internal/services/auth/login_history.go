@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"orus/internal/models"
+)
+
+// reportLinkTTL is how long the "this wasn't me" link of a login alert works
+const reportLinkTTL = 30 * 24 * time.Hour
+
+// pendingLoginTTL matches the lifetime of the one-time code
+const pendingLoginTTL = 5 * time.Minute
+
+// lockReason is recorded as the suspension reason of an account its holder
+// locked from a login alert
+const lockReason = "locked by the account holder after a login they didn't recognise"
+
+var (
+	// ErrStepUpRequired is returned for a login from a new country or
+	// device, which is completed with the one-time code like MFA
+	ErrStepUpRequired = errors.New("login from a new country or device must be verified")
+	// ErrReportLinkInvalid is returned for an unknown or expired "this
+	// wasn't me" link
+	ErrReportLinkInvalid = errors.New("login report link is invalid or has expired")
+)
+
+// LoginContext is where a login came from
+type LoginContext struct {
+	IP        string
+	Country   string // ISO 3166-1 alpha-2 from the edge, empty when unknown
+	DeviceID  string
+	UserAgent string
+}
+
+// Notifier alerts users to logins from somewhere new and sends them the
+// one-time codes that complete challenged logins
+type Notifier interface {
+	SendNewLoginAlert(ctx context.Context, user *models.User, event *models.LoginEvent, reportURL string) error
+	SendLoginCode(ctx context.Context, user *models.User, code string, ttl time.Duration) error
+}
+
+// newLoginEvent records where a login came from and whether the user has
+// signed in from that country and device before. A user's first login has
+// nothing to compare against and is never new.
+func (s *service) newLoginEvent(ctx context.Context, user *models.User, login LoginContext) (*models.LoginEvent, error) {
+	event := &models.LoginEvent{
+		UserID:    user.ID,
+		IP:        login.IP,
+		DeviceID:  login.DeviceID,
+		UserAgent: login.UserAgent,
+	}
+	if code, ok := models.NormalizeCountryCode(login.Country); ok {
+		event.Country = code
+	}
+
+	previous, err := s.loginEvents.CountSucceeded(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if previous == 0 {
+		return event, nil
+	}
+
+	knownDevice := false
+	if event.DeviceID != "" {
+		if knownDevice, err = s.loginEvents.KnownDevice(ctx, user.ID, event.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+	event.NewDevice = !knownDevice
+
+	if event.Country != "" {
+		knownCountry, err := s.loginEvents.KnownCountry(ctx, user.ID, event.Country)
+		if err != nil {
+			return nil, err
+		}
+		event.NewCountry = !knownCountry
+	}
+	return event, nil
+}
+
+// challengeLogin holds a login for the one-time code, alerting the user
+// when it came from somewhere new
+func (s *service) challengeLogin(ctx context.Context, user *models.User, event *models.LoginEvent) error {
+	var reportToken string
+	if event.IsSuspicious() {
+		token, err := newReportToken()
+		if err != nil {
+			return err
+		}
+		reportToken = token
+		event.ReportTokenHash = hashReportToken(token)
+	}
+
+	event.Status = models.LoginStatusChallenged
+	if err := s.loginEvents.Create(ctx, event); err != nil {
+		return err
+	}
+	if err := s.generateOTP(ctx, user); err != nil {
+		return err
+	}
+	if err := s.cache.SetExact(ctx, pendingLoginKey(user.ID), event.ID, pendingLoginTTL); err != nil {
+		return err
+	}
+
+	if event.IsSuspicious() {
+		s.recordActivity(user.ID, models.UserActivityLoginChallenged)
+		reportURL := fmt.Sprintf("%s/api/login/report/%s", s.baseURL, reportToken)
+		if err := s.notifier.SendNewLoginAlert(ctx, user, event, reportURL); err != nil {
+			log.Printf("Failed to send new login alert to user %d: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+// completeChallengedLogin marks the login held for the code as succeeded,
+// trusting its country and device from now on. It returns the login, or
+// nil when it can't be found.
+func (s *service) completeChallengedLogin(ctx context.Context, userID uint) *models.LoginEvent {
+	key := pendingLoginKey(userID)
+	var eventID uint
+	found, err := s.cache.Get(ctx, key, &eventID)
+	if err != nil || !found {
+		return nil
+	}
+	_ = s.cache.Delete(ctx, key)
+
+	event, err := s.loginEvents.GetByID(ctx, eventID)
+	if err != nil {
+		log.Printf("Failed to load login %d of user %d: %v", eventID, userID, err)
+		return nil
+	}
+	if event.UserID != userID || event.Status != models.LoginStatusChallenged {
+		return nil
+	}
+	event.Status = models.LoginStatusSucceeded
+	if err := s.loginEvents.Update(ctx, event); err != nil {
+		log.Printf("Failed to complete login %d of user %d: %v", eventID, userID, err)
+	}
+	return event
+}
+
+// recordLogin adds a login that needed no challenge to the history.
+// Failing to record it doesn't fail the login.
+func (s *service) recordLogin(ctx context.Context, event *models.LoginEvent) {
+	event.Status = models.LoginStatusSucceeded
+	if err := s.loginEvents.Create(ctx, event); err != nil {
+		log.Printf("Failed to record login of user %d: %v", event.UserID, err)
+	}
+}
+
+func (s *service) GetReportedLogin(ctx context.Context, token string) (*models.LoginEvent, error) {
+	event, err := s.loginEvents.GetByReportTokenHash(ctx, hashReportToken(token))
+	if err != nil || time.Since(event.CreatedAt) > reportLinkTTL {
+		return nil, ErrReportLinkInvalid
+	}
+	return event, nil
+}
+
+// ReportLogin locks the account of a login its holder didn't recognise.
+// Locking suspends the account, which ends every session; an admin
+// reinstates it once the holder has been verified. Reporting twice is a
+// no-op.
+func (s *service) ReportLogin(ctx context.Context, token string) error {
+	event, err := s.GetReportedLogin(ctx, token)
+	if err != nil {
+		return err
+	}
+	if event.Status == models.LoginStatusReported {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(event.UserID)
+	if err != nil {
+		return err
+	}
+	if !user.IsSuspended() {
+		now := time.Now()
+		if err := s.userRepo.SetSuspension(user.ID, &now, lockReason); err != nil {
+			return err
+		}
+	}
+	_ = s.cache.Delete(ctx, otpKey(user.ID), otpAttemptsKey(user.ID), pendingLoginKey(user.ID))
+
+	now := time.Now()
+	event.Status = models.LoginStatusReported
+	event.ReportedAt = &now
+	if err := s.loginEvents.Update(ctx, event); err != nil {
+		return err
+	}
+
+	log.Printf("User %d reported login %d from %s (%s) and locked their account", user.ID, event.ID, event.IP, event.Country)
+	s.recordActivity(user.ID, models.UserActivityLockedByUser)
+	return nil
+}
+
+func (s *service) ListLogins(ctx context.Context, userID uint, limit, offset int) ([]models.LoginEvent, int64, error) {
+	return s.loginEvents.ListByUser(ctx, userID, limit, offset)
+}
+
+func pendingLoginKey(userID uint) string {
+	return fmt.Sprintf("login_pending:%d", userID)
+}
+
+func newReportToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashReportToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
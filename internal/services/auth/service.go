@@ -4,41 +4,85 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"time"
 
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"orus/internal/repositories/cache"
+	"orus/internal/services/password"
+	"orus/internal/services/rbac"
 	"orus/internal/validation"
 
 	"log"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
-// ErrMFARequired indicates that multi-factor authentication is needed
-var ErrMFARequired = errors.New("mfa_required")
+var (
+	// ErrMFARequired indicates that multi-factor authentication is needed
+	ErrMFARequired = errors.New("mfa_required")
+	// ErrInvalidCredentials is returned for an unknown user or wrong password
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountSuspended is returned when an admin has suspended the user
+	ErrAccountSuspended = errors.New("account is suspended")
+	// ErrTooManyOTPAttempts is returned once a one-time code has been
+	// guessed at MaxOTPAttempts times; the login has to start over
+	ErrTooManyOTPAttempts = errors.New("too many attempts, log in again for a new code")
+)
+
+const (
+	// otpTTL is how long a one-time code can be used
+	otpTTL = 5 * time.Minute
+	// MaxOTPAttempts is how many times a one-time code can be tried before
+	// it is thrown away
+	MaxOTPAttempts = 5
+)
 
 // Service defines the interface for authentication operations.
 // It provides methods for user authentication, token management,
 // and session handling.
 type Service interface {
-	// Login authenticates a user and returns access and refresh tokens
-	Login(email, phone, password string) (*models.User, string, string, error)
+	// Login authenticates a user and returns access and refresh tokens.
+	// A login needing the one-time code returns ErrMFARequired, or
+	// ErrStepUpRequired when it came from a new country or device.
+	Login(email, phone, password string, login LoginContext) (*models.User, string, string, error)
+
+	// LoginExternal signs in a user a social login provider vouched for,
+	// with the same challenges as a password login
+	LoginExternal(user *models.User, login LoginContext) (*models.User, string, string, error)
 
 	// RefreshTokens generates new access and refresh tokens
 	RefreshTokens(refreshToken string) (string, string, error)
 
-	// Logout invalidates a user's current session
-	Logout(userID uint) error
+	// Logout ends the session the user is signed in with. Tokens without
+	// a session can only be ended with all the others.
+	Logout(userID uint, sessionID string) error
+
+	// LogoutAll ends every session of the user
+	LogoutAll(userID uint) error
+
+	// ListSessions returns the user's open sessions, marking currentID
+	ListSessions(ctx context.Context, userID uint, currentID string) ([]models.Session, error)
+
+	// RevokeSession ends one of the user's sessions
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+
+	// SessionActive reports whether a session of the user is still open,
+	// from the cache when possible
+	SessionActive(userID uint, sessionID string) (bool, error)
 
 	// GetUserTokenVersion returns the current token version for a user
 	GetUserTokenVersion(userID uint) (int, error)
 
+	// SessionState returns what authenticating a request needs to know
+	// about a user, from the cache when possible
+	SessionState(userID uint) (*cache.SessionState, error)
+
 	// ChangePassword updates a user's password after validating the old password
 	// Returns error if old password is invalid or new password doesn't meet requirements
 	ChangePassword(userID uint, oldPassword, newPassword string) error
@@ -46,30 +90,71 @@ type Service interface {
 	// GetUserByID retrieves a user by their ID
 	GetUserByID(userID uint) (*models.User, error)
 
-	// GenerateTokens creates new access and refresh tokens for a user
-	GenerateTokens(user *models.User) (string, string, error)
-
 	// VerifyOTP completes login when MFA is enabled
 	VerifyOTP(userID uint, code string) (*models.User, string, string, error)
+
+	// GetPermissions returns the permissions a role grants
+	GetPermissions(role string) ([]string, error)
+
+	// ListLogins returns the user's login history, newest first
+	ListLogins(ctx context.Context, userID uint, limit, offset int) ([]models.LoginEvent, int64, error)
+
+	// GetReportedLogin returns the login a "this wasn't me" link names
+	GetReportedLogin(ctx context.Context, token string) (*models.LoginEvent, error)
+
+	// ReportLogin locks the account of a login named by a "this wasn't me"
+	// link, ending every session
+	ReportLogin(ctx context.Context, token string) error
 }
 
 type service struct {
 	userRepo      repositories.UserRepository
-	jwtSecret     string
+	activityRepo  repositories.UserActivityRepository
+	loginEvents   repositories.LoginEventRepository
+	sessions      repositories.SessionRepository
+	rbac          rbac.Service
+	keys          *KeySet
 	refreshSecret string
+	passwords     *password.Hasher
 	cache         *cache.CacheService
+	notifier      Notifier
+	baseURL       string
 }
 
-func NewService(userRepo repositories.UserRepository, jwtSecret, refreshSecret string, cacheSvc *cache.CacheService) Service {
+// NewService creates the auth service. Access tokens are signed by keys;
+// refresh tokens, only ever read back by this service, stay HS256 under
+// refreshSecret. Login alerts link back to baseURL.
+func NewService(
+	userRepo repositories.UserRepository,
+	activityRepo repositories.UserActivityRepository,
+	loginEvents repositories.LoginEventRepository,
+	sessions repositories.SessionRepository,
+	rbacSvc rbac.Service,
+	keys *KeySet,
+	refreshSecret string,
+	passwords *password.Hasher,
+	cacheSvc *cache.CacheService,
+	notifier Notifier,
+	baseURL string,
+) Service {
 	return &service{
 		userRepo:      userRepo,
-		jwtSecret:     jwtSecret,
+		activityRepo:  activityRepo,
+		loginEvents:   loginEvents,
+		sessions:      sessions,
+		rbac:          rbacSvc,
+		keys:          keys,
 		refreshSecret: refreshSecret,
+		passwords:     passwords,
 		cache:         cacheSvc,
+		notifier:      notifier,
+		baseURL:       baseURL,
 	}
 }
 
-func (s *service) Login(email, phone, password string) (*models.User, string, string, error) {
+func (s *service) Login(email, phone, password string, login LoginContext) (*models.User, string, string, error) {
+	ctx := context.Background()
+
 	// Get user by email or phone
 	var user *models.User
 	var err error
@@ -85,47 +170,59 @@ func (s *service) Login(email, phone, password string) (*models.User, string, st
 	}
 
 	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, "", "", errors.New("invalid credentials")
+	ok, rehash := s.passwords.Verify(user.Password, password)
+	if !ok {
+		return nil, "", "", ErrInvalidCredentials
 	}
-
-	// If MFA is enabled, generate OTP and return special error
-	if user.TwoFactorEnabled {
-		if _, err := s.generateOTP(user.ID); err != nil {
-			return nil, "", "", err
-		}
-		return user, "", "", ErrMFARequired
+	if rehash {
+		s.upgradePassword(user.ID, password)
 	}
+	return s.completeLogin(ctx, user, login)
+}
 
-	// After successful login
-	log.Printf("Initial token version: %d", user.TokenVersion)
-	log.Printf("User ID before login: %d", user.ID)
+func (s *service) LoginExternal(user *models.User, login LoginContext) (*models.User, string, string, error) {
+	return s.completeLogin(context.Background(), user, login)
+}
 
-	if err := s.userRepo.IncrementTokenVersion(user.ID); err != nil {
-		return nil, "", "", err
+// completeLogin signs in a user whose identity has been established,
+// challenging the login when MFA is on or it came from somewhere new
+func (s *service) completeLogin(ctx context.Context, user *models.User, login LoginContext) (*models.User, string, string, error) {
+	if user.IsSuspended() {
+		s.recordActivity(user.ID, models.UserActivityLoginBlocked)
+		return nil, "", "", ErrAccountSuspended
 	}
 
-	// Verify the increment
-	updatedUser, err := s.userRepo.GetByID(user.ID)
+	event, err := s.newLoginEvent(ctx, user, login)
 	if err != nil {
 		return nil, "", "", err
 	}
-	log.Printf("New token version: %d", updatedUser.TokenVersion)
-	log.Printf("User ID after increment: %d", updatedUser.ID)
 
-	// Generate new tokens
-	accessToken, err := s.generateAccessToken(updatedUser)
+	// Logins from somewhere new, and every login with MFA enabled, are
+	// completed with a one-time code
+	if user.TwoFactorEnabled || event.IsSuspicious() {
+		if err := s.challengeLogin(ctx, user, event); err != nil {
+			return nil, "", "", err
+		}
+		if !user.TwoFactorEnabled {
+			return user, "", "", ErrStepUpRequired
+		}
+		return user, "", "", ErrMFARequired
+	}
+
+	// Each login is a session of its own, leaving the user's sessions on
+	// other devices open
+	session, err := s.startSession(ctx, user, event)
 	if err != nil {
 		return nil, "", "", err
 	}
-	log.Printf("Generated token with version: %d for user ID: %d", updatedUser.TokenVersion, updatedUser.ID)
-
-	refreshToken, err := s.generateRefreshToken(updatedUser)
+	accessToken, refreshToken, err := s.generateTokens(user, session.ID)
 	if err != nil {
 		return nil, "", "", err
 	}
+	s.recordLogin(ctx, event)
+	s.recordActivity(user.ID, models.UserActivityLogin)
 
-	return updatedUser, accessToken, refreshToken, nil
+	return user, accessToken, refreshToken, nil
 }
 
 func (s *service) RefreshTokens(refreshToken string) (string, string, error) {
@@ -151,12 +248,34 @@ func (s *service) RefreshTokens(refreshToken string) (string, string, error) {
 	if claims.TokenVersion != user.TokenVersion {
 		return "", "", errors.New("token version mismatch")
 	}
+	if user.IsSuspended() {
+		return "", "", ErrAccountSuspended
+	}
 
-	return s.generateTokens(user)
+	sessionID, err := s.refreshSession(context.Background(), user, claims.SessionID)
+	if err != nil {
+		return "", "", err
+	}
+	return s.generateTokens(user, sessionID)
 }
 
-func (s *service) Logout(userID uint) error {
-	return s.userRepo.IncrementTokenVersion(userID)
+func (s *service) Logout(userID uint, sessionID string) error {
+	if sessionID == "" {
+		return s.LogoutAll(userID)
+	}
+	if err := s.RevokeSession(context.Background(), userID, sessionID); err != nil {
+		return err
+	}
+	s.recordActivity(userID, models.UserActivityLogout)
+	return nil
+}
+
+func (s *service) LogoutAll(userID uint) error {
+	if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+		return err
+	}
+	s.recordActivity(userID, models.UserActivityLogout)
+	return nil
 }
 
 func (s *service) ChangePassword(userID uint, oldPassword, newPassword string) error {
@@ -165,7 +284,7 @@ func (s *service) ChangePassword(userID uint, oldPassword, newPassword string) e
 		return errors.New("failed to get user")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+	if ok, _ := s.passwords.Verify(user.Password, oldPassword); !ok {
 		return errors.New("invalid old password")
 	}
 
@@ -173,12 +292,12 @@ func (s *service) ChangePassword(userID uint, oldPassword, newPassword string) e
 		return errors.New("password must be at least 8 characters and contain special characters")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwords.Hash(newPassword)
 	if err != nil {
 		return errors.New("failed to hash password")
 	}
 
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	user.TokenVersion++ // Invalidate existing tokens
 
 	if err := s.userRepo.Update(user); err != nil {
@@ -188,15 +307,28 @@ func (s *service) ChangePassword(userID uint, oldPassword, newPassword string) e
 	return nil
 }
 
-func (s *service) generateTokens(user *models.User) (string, string, error) {
-	// Create access token with JWT_SECRET
-	accessToken, err := s.generateAccessToken(user)
+// upgradePassword replaces a bcrypt hash, or one made with older argon2id
+// parameters, once the password is known. A failure only delays the
+// upgrade to the next login.
+func (s *service) upgradePassword(userID uint, password string) {
+	hash, err := s.passwords.Hash(password)
+	if err == nil {
+		err = s.userRepo.UpdatePassword(userID, hash)
+	}
+	if err != nil {
+		log.Printf("Failed to upgrade password hash for user %d: %v", userID, err)
+	}
+}
+
+func (s *service) generateTokens(user *models.User, sessionID string) (string, string, error) {
+	// Create access token with the active signing key
+	accessToken, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return "", "", err
 	}
 
 	// Create refresh token with REFRESH_SECRET
-	refreshToken, err := s.generateRefreshToken(user)
+	refreshToken, err := s.generateRefreshToken(user, sessionID)
 	if err != nil {
 		return "", "", err
 	}
@@ -204,88 +336,145 @@ func (s *service) generateTokens(user *models.User) (string, string, error) {
 	return accessToken, refreshToken, nil
 }
 
-func (s *service) generateAccessToken(user *models.User) (string, error) {
+func (s *service) generateAccessToken(user *models.User, sessionID string) (string, error) {
+	// Claims carry the role's permissions as they are now; changing them
+	// ends the role's sessions
+	permissions, err := s.rbac.Permissions(context.Background(), user.Role)
+	if err != nil {
+		return "", fmt.Errorf("failed to get permissions of role %s: %w", user.Role, err)
+	}
+
 	claims := &models.UserClaims{
 		UserID:       user.ID,
 		Email:        user.Email,
 		Role:         user.Role,
-		Permissions:  models.GetDefaultPermissions(user.Role),
+		Permissions:  permissions,
 		TokenType:    "access",
 		TokenVersion: user.TokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return s.keys.Sign(claims)
 }
 
-func (s *service) generateRefreshToken(user *models.User) (string, error) {
+func (s *service) generateRefreshToken(user *models.User, sessionID string) (string, error) {
 	claims := &models.UserClaims{
 		UserID:       user.ID,
 		TokenType:    "refresh",
 		TokenVersion: user.TokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenTTL)),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.refreshSecret))
 }
 
-func (s *service) GetUserByID(userID uint) (*models.User, error) {
-	return s.userRepo.GetByID(userID)
+func (s *service) GetPermissions(role string) ([]string, error) {
+	return s.rbac.Permissions(context.Background(), role)
 }
 
-func (s *service) GenerateTokens(user *models.User) (string, string, error) {
-	// Reuse the existing generateTokens method
-	return s.generateTokens(user)
+func (s *service) GetUserByID(userID uint) (*models.User, error) {
+	return s.userRepo.GetByID(userID)
 }
 
 func (s *service) GetUserTokenVersion(userID uint) (int, error) {
-	log.Printf("Getting token version for user ID: %d", userID)
-	user, err := s.GetUserByID(userID)
+	state, err := s.SessionState(userID)
 	if err != nil {
 		log.Printf("Error getting token version for user %d: %v", userID, err)
 		return 0, err
 	}
-	log.Printf("Retrieved token version %d for user %d", user.TokenVersion, userID)
-	return user.TokenVersion, nil
+	return state.TokenVersion, nil
+}
+
+func (s *service) SessionState(userID uint) (*cache.SessionState, error) {
+	return s.cache.LoadSession(context.Background(), userID, func() (*cache.SessionState, error) {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		return &cache.SessionState{
+			TokenVersion: user.TokenVersion,
+			Suspended:    user.IsSuspended(),
+		}, nil
+	})
 }
 
-// generateOTP creates a 6 digit code and stores it in cache
-func (s *service) generateOTP(userID uint) (string, error) {
-	code := fmt.Sprintf("%06d", rand.Intn(1000000))
-	key := fmt.Sprintf("otp:%d", userID)
-	if err := s.cache.SetWithTTL(context.Background(), key, code, 5*time.Minute); err != nil {
-		return "", err
+// generateOTP creates a random 6 digit code, stores it in cache with a
+// fresh attempt count and sends it to the user
+func (s *service) generateOTP(ctx context.Context, user *models.User) error {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return fmt.Errorf("failed to generate one-time code: %w", err)
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	if err := s.cache.SetExact(ctx, otpKey(user.ID), code, otpTTL); err != nil {
+		return err
 	}
-	log.Printf("OTP for user %d: %s", userID, code)
-	return code, nil
+	if err := s.cache.Delete(ctx, otpAttemptsKey(user.ID)); err != nil {
+		return err
+	}
+	return s.notifier.SendLoginCode(ctx, user, code, otpTTL)
 }
 
-// VerifyOTP checks the code and returns tokens if valid
+// VerifyOTP checks the code and returns tokens if valid. Every try counts
+// against the code, which is thrown away after MaxOTPAttempts.
 func (s *service) VerifyOTP(userID uint, code string) (*models.User, string, string, error) {
-	key := fmt.Sprintf("otp:%d", userID)
+	ctx := context.Background()
+	key := otpKey(userID)
+	attempts, _, err := s.cache.Hit(ctx, otpAttemptsKey(userID), otpTTL)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if attempts > MaxOTPAttempts {
+		_ = s.cache.Delete(ctx, key, otpAttemptsKey(userID))
+		return nil, "", "", ErrTooManyOTPAttempts
+	}
+
 	var stored string
-	found, err := s.cache.Get(context.Background(), key, &stored)
-	if err != nil || !found || stored != code {
+	found, err := s.cache.Get(ctx, key, &stored)
+	if err != nil || !found || subtle.ConstantTimeCompare([]byte(stored), []byte(code)) != 1 {
 		return nil, "", "", errors.New("invalid otp")
 	}
-	_ = s.cache.Delete(context.Background(), key)
+	_ = s.cache.Delete(ctx, key, otpAttemptsKey(userID))
 
-	if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
-		return nil, "", "", err
-	}
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, "", "", err
 	}
+	if user.IsSuspended() {
+		return nil, "", "", ErrAccountSuspended
+	}
 
-	access, refresh, err := s.generateTokens(user)
+	event := s.completeChallengedLogin(ctx, user.ID)
+	session, err := s.startSession(ctx, user, event)
 	if err != nil {
 		return nil, "", "", err
 	}
+	access, refresh, err := s.generateTokens(user, session.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	s.recordActivity(user.ID, models.UserActivityLogin)
 
 	return user, access, refresh, nil
 }
+
+func otpKey(userID uint) string {
+	return fmt.Sprintf("otp:%d", userID)
+}
+
+func otpAttemptsKey(userID uint) string {
+	return fmt.Sprintf("otp_attempts:%d", userID)
+}
+
+// recordActivity adds a sign-in event to the user's activity timeline.
+// Failing to record it doesn't fail the sign-in.
+func (s *service) recordActivity(userID uint, action string) {
+	if err := s.activityRepo.Record(&models.UserActivity{UserID: userID, Action: action}); err != nil {
+		log.Printf("Failed to record %s activity for user %d: %v", action, userID, err)
+	}
+}
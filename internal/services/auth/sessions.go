@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+)
+
+const (
+	accessTokenTTL = 24 * time.Hour
+	// refreshTokenTTL is also how long a session lasts unused; every
+	// refresh extends it
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// startSession opens a session for a completed login. event says where it
+// came from, and is nil when that isn't known.
+func (s *service) startSession(ctx context.Context, user *models.User, event *models.LoginEvent) (*models.Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		ID:           id,
+		UserID:       user.ID,
+		TokenVersion: user.TokenVersion,
+		LastUsedAt:   now,
+		ExpiresAt:    now.Add(refreshTokenTTL),
+	}
+	if event != nil {
+		session.IP = event.IP
+		session.Country = event.Country
+		session.DeviceID = event.DeviceID
+		session.UserAgent = event.UserAgent
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// refreshSession checks the session a refresh token names is still open
+// and extends it. Refresh tokens from before sessions are moved into a
+// new one.
+func (s *service) refreshSession(ctx context.Context, user *models.User, sessionID string) (string, error) {
+	if sessionID == "" {
+		session, err := s.startSession(ctx, user, nil)
+		if err != nil {
+			return "", err
+		}
+		return session.ID, nil
+	}
+
+	session, err := s.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if session.UserID != user.ID || !session.IsActive(user.TokenVersion, now) {
+		return "", errors.New("session has ended")
+	}
+	if err := s.sessions.Touch(ctx, session.ID, now, now.Add(refreshTokenTTL)); err != nil {
+		log.Printf("Failed to extend session %s of user %d: %v", session.ID, user.ID, err)
+	}
+	return session.ID, nil
+}
+
+func (s *service) SessionActive(userID uint, sessionID string) (bool, error) {
+	ctx := context.Background()
+	return s.cache.LoadSessionActive(ctx, sessionID, func() (bool, error) {
+		session, err := s.sessions.Get(ctx, sessionID)
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return session.UserID == userID && session.RevokedAt == nil && time.Now().Before(session.ExpiresAt), nil
+	})
+}
+
+func (s *service) ListSessions(ctx context.Context, userID uint, currentID string) ([]models.Session, error) {
+	state, err := s.SessionState(userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessions.ListActive(ctx, userID, state.TokenVersion, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+func (s *service) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	if err := s.sessions.Revoke(ctx, userID, sessionID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.cache.Delete(ctx, cache.SessionActiveKey(sessionID)); err != nil {
+		log.Printf("Failed to drop cached session %s: %v", sessionID, err)
+	}
+	return nil
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
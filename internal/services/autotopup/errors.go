@@ -0,0 +1,12 @@
+package autotopup
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidAmount    = errors.New("top-up amount must be greater than zero")
+	ErrInvalidThreshold = errors.New("threshold cannot be negative")
+	ErrInvalidCap       = errors.New("caps cannot be negative or below the top-up amount")
+	ErrCardNotFound     = errors.New("card not found")
+	ErrNoDefaultCard    = errors.New("set a default card or choose one for auto top-up")
+)
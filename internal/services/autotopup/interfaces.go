@@ -0,0 +1,36 @@
+package autotopup
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/wallet"
+)
+
+// WalletService is the part of the wallet service auto top-ups rely on.
+// Top-ups go through the same flow, limits and 3-D Secure rules as ones
+// the user starts.
+type WalletService interface {
+	GetBalanceDetails(ctx context.Context, userID uint) (*wallet.BalanceDetails, error)
+	TopUp(ctx context.Context, userID uint, cardID uint, amount float64) (*models.CardTopUp, error)
+}
+
+// CardService finds the card an auto top-up charges
+type CardService interface {
+	GetUserCards(userID uint) ([]models.CreditCard, error)
+	GetByIDAndUserID(cardID uint, userID uint) (*models.CreditCard, error)
+}
+
+// Service tops wallets up from a card when payments take the balance below
+// the user's threshold
+type Service interface {
+	GetRule(ctx context.Context, userID uint) (*models.AutoTopUpRule, error)
+	// SaveRule sets up the user's auto top-up or replaces their existing
+	// one. Only payments from now on set it off.
+	SaveRule(ctx context.Context, userID uint, req RuleRequest) (*models.AutoTopUpRule, error)
+	DeleteRule(ctx context.Context, userID uint) error
+	ListRuns(ctx context.Context, userID uint, limit, offset int) ([]models.AutoTopUpRun, int64, error)
+
+	// ProcessPayments evaluates the rules of users who have paid since they
+	// were last checked, and returns how many top-ups it started
+	ProcessPayments(ctx context.Context) (int, error)
+}
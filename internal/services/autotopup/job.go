@@ -0,0 +1,25 @@
+package autotopup
+
+import (
+	"context"
+	"log"
+)
+
+// Job tops up the wallets of users whose payments took them below their
+// auto top-up threshold
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the auto top-up service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "auto-top-up" }
+
+func (j *Job) Run(ctx context.Context) error {
+	started, err := j.service.ProcessPayments(ctx)
+	if started > 0 {
+		log.Printf("Started %d auto top-ups", started)
+	}
+	return err
+}
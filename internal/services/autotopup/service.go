@@ -0,0 +1,240 @@
+package autotopup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"time"
+)
+
+type service struct {
+	repo    repositories.AutoTopUpRepository
+	wallets WalletService
+	cards   CardService
+	cache   *cache.CacheService
+}
+
+// NewService creates the auto top-up service
+func NewService(repo repositories.AutoTopUpRepository, wallets WalletService, cards CardService, cacheSvc *cache.CacheService) Service {
+	return &service{
+		repo:    repo,
+		wallets: wallets,
+		cards:   cards,
+		cache:   cacheSvc,
+	}
+}
+
+func (s *service) GetRule(ctx context.Context, userID uint) (*models.AutoTopUpRule, error) {
+	return s.repo.GetRule(userID)
+}
+
+func (s *service) SaveRule(ctx context.Context, userID uint, req RuleRequest) (*models.AutoTopUpRule, error) {
+	switch {
+	case req.Amount <= 0:
+		return nil, ErrInvalidAmount
+	case req.Threshold < 0:
+		return nil, ErrInvalidThreshold
+	case req.DailyCap < 0 || req.MonthlyCap < 0,
+		req.DailyCap > 0 && req.DailyCap < req.Amount,
+		req.MonthlyCap > 0 && req.MonthlyCap < math.Max(req.Amount, req.DailyCap):
+		return nil, ErrInvalidCap
+	}
+	if _, err := s.card(userID, req.CardID); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.GetRule(userID)
+	if errors.Is(err, repositories.ErrAutoTopUpRuleNotFound) {
+		rule = &models.AutoTopUpRule{UserID: userID}
+	} else if err != nil {
+		return nil, err
+	}
+	latest, err := s.repo.LatestTransactionID()
+	if err != nil {
+		return nil, err
+	}
+
+	rule.CardID = req.CardID
+	rule.Threshold = round2(req.Threshold)
+	rule.Amount = round2(req.Amount)
+	rule.DailyCap = round2(req.DailyCap)
+	rule.MonthlyCap = round2(req.MonthlyCap)
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.CheckedAfterID = latest
+	rule.ConsecutiveFailures = 0
+	rule.LastFailure = ""
+	if err := s.repo.SaveRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, userID uint) error {
+	return s.repo.DeleteRule(userID)
+}
+
+func (s *service) ListRuns(ctx context.Context, userID uint, limit, offset int) ([]models.AutoTopUpRun, int64, error) {
+	return s.repo.ListRuns(userID, limit, offset)
+}
+
+// card returns the card the rule charges: the one chosen, or else the
+// user's default card
+func (s *service) card(userID, cardID uint) (*models.CreditCard, error) {
+	if cardID != 0 {
+		card, err := s.cards.GetByIDAndUserID(cardID, userID)
+		if err != nil {
+			return nil, ErrCardNotFound
+		}
+		return card, nil
+	}
+	cards, err := s.cards.GetUserCards(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cards {
+		if cards[i].IsDefault {
+			return &cards[i], nil
+		}
+	}
+	return nil, ErrNoDefaultCard
+}
+
+func (s *service) ProcessPayments(ctx context.Context) (int, error) {
+	rules, err := s.repo.GetDueRules(batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	started := 0
+	for i := range rules {
+		if err := ctx.Err(); err != nil {
+			return started, err
+		}
+		rule := &rules[i]
+
+		// Only the latest payment matters: the balance it left is the
+		// balance now
+		paymentID, err := s.repo.LatestPaymentID(rule.UserID, rule.CheckedAfterID)
+		if err != nil {
+			return started, err
+		}
+		if paymentID == 0 {
+			continue
+		}
+		ran, err := s.evaluate(ctx, rule, paymentID)
+		if errors.Is(err, cache.ErrLockBusy) {
+			// Another instance is on it
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to evaluate auto top-up rule %d: %v", rule.ID, err)
+			continue
+		}
+		if ran {
+			started++
+		}
+		if err := s.repo.SetCheckpoint(rule.ID, paymentID); err != nil {
+			return started, err
+		}
+	}
+	return started, nil
+}
+
+// evaluate tops the user's wallet up if their balance is below the rule's
+// threshold and the caps allow it. It reports whether it charged the card.
+func (s *service) evaluate(ctx context.Context, rule *models.AutoTopUpRule, paymentID uint) (bool, error) {
+	lock, err := s.cache.Lock(ctx, fmt.Sprintf("auto_top_up:%d", rule.UserID), evaluationLock, evaluationWait)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock(ctx)
+
+	balance, err := s.wallets.GetBalanceDetails(ctx, rule.UserID)
+	if err != nil {
+		return false, err
+	}
+	spendable := round2(balance.Total - balance.Held)
+	if spendable >= rule.Threshold {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	if rule.DailyCap > 0 {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if over, err := s.overCap(rule, day, rule.DailyCap); err != nil || over {
+			return false, err
+		}
+	}
+	if rule.MonthlyCap > 0 {
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if over, err := s.overCap(rule, month, rule.MonthlyCap); err != nil || over {
+			return false, err
+		}
+	}
+
+	run := &models.AutoTopUpRun{
+		RuleID:               rule.ID,
+		UserID:               rule.UserID,
+		TriggerTransactionID: paymentID,
+		Balance:              spendable,
+		Amount:               rule.Amount,
+		Status:               models.AutoTopUpPending,
+	}
+	if err := s.repo.ClaimRun(run); err != nil {
+		if errors.Is(err, repositories.ErrAutoTopUpAlreadyRun) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var topUp *models.CardTopUp
+	card, chargeErr := s.card(rule.UserID, rule.CardID)
+	if chargeErr == nil {
+		run.CardID = card.ID
+		topUp, chargeErr = s.wallets.TopUp(ctx, rule.UserID, card.ID, rule.Amount)
+	}
+	if chargeErr != nil {
+		run.Status = models.AutoTopUpFailed
+		run.FailureReason = chargeErr.Error()
+		rule.ConsecutiveFailures++
+		rule.LastFailure = chargeErr.Error()
+		if rule.ConsecutiveFailures >= MaxConsecutiveFailures {
+			rule.Enabled = false
+			log.Printf("Turned off auto top-up rule %d after %d failures", rule.ID, rule.ConsecutiveFailures)
+		}
+	} else {
+		run.CardTopUpID = &topUp.ID
+		run.Status = models.AutoTopUpSucceeded
+		if topUp.Status == models.CardTopUpRequiresAction {
+			run.Status = models.AutoTopUpRequiresAction
+		}
+		rule.ConsecutiveFailures = 0
+		rule.LastFailure = ""
+	}
+	if err := s.repo.UpdateRun(run); err != nil {
+		log.Printf("Failed to record auto top-up %d: %v", run.ID, err)
+	}
+	if err := s.repo.RecordOutcome(rule); err != nil {
+		log.Printf("Failed to record outcome of auto top-up rule %d: %v", rule.ID, err)
+	}
+	return chargeErr == nil, nil
+}
+
+// overCap reports whether another top-up would take the user's auto
+// top-ups since the time past the cap
+func (s *service) overCap(rule *models.AutoTopUpRule, since time.Time, limit float64) (bool, error) {
+	total, err := s.repo.SumRunsSince(rule.UserID, since)
+	if err != nil {
+		return false, err
+	}
+	return round2(total+rule.Amount) > limit, nil
+}
+
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
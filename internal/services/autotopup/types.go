@@ -0,0 +1,28 @@
+package autotopup
+
+import "time"
+
+const (
+	// MaxConsecutiveFailures turns a rule off after this many failed charges
+	// in a row, so a declined card isn't retried after every payment
+	MaxConsecutiveFailures = 3
+
+	batchSize = 100
+
+	// evaluationLock bounds how long one instance holds a user's
+	// evaluation; another waits up to evaluationWait before leaving it
+	evaluationLock = 30 * time.Second
+	evaluationWait = time.Second
+)
+
+// RuleRequest sets up an auto top-up: when the spendable balance drops
+// below Threshold, charge Amount to the card. CardID 0 charges the default
+// card. Caps of 0 mean no cap.
+type RuleRequest struct {
+	CardID     uint    `json:"card_id"`
+	Threshold  float64 `json:"threshold" validate:"gte=0"`
+	Amount     float64 `json:"amount" validate:"gt=0"`
+	DailyCap   float64 `json:"daily_cap" validate:"gte=0"`
+	MonthlyCap float64 `json:"monthly_cap" validate:"gte=0"`
+	Enabled    *bool   `json:"enabled"` // Defaults to true
+}
@@ -0,0 +1,13 @@
+package bulk
+
+import "errors"
+
+// Service errors
+var (
+	ErrSourceRequired = errors.New("upload a CSV file of IDs or give a filter, not both")
+	ErrEmptyFilter    = errors.New("filter must narrow the targets by at least one field")
+	ErrInvalidCSV     = errors.New("CSV must have one numeric ID per row")
+	ErrFileTooLarge   = errors.New("CSV file is too large")
+	ErrNoTargets      = errors.New("no targets to apply the operation to")
+	ErrTooManyTargets = errors.New("too many targets for one bulk operation")
+)
@@ -0,0 +1,41 @@
+package bulk
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/wallet"
+)
+
+// WalletLocker locks wallets the same way a single admin lock does
+type WalletLocker interface {
+	LockWallet(ctx context.Context, actorID *uint, userID uint, req wallet.LockRequest) (*models.WalletLock, error)
+}
+
+// UserSuspender suspends users the same way a single admin suspension does
+type UserSuspender interface {
+	Suspend(ctx context.Context, actorID, userID uint, reason string) (*models.User, error)
+}
+
+// QRExpirer expires QR codes on an admin's behalf
+type QRExpirer interface {
+	ExpireQRCode(ctx context.Context, qrID uint) (*models.QRCode, error)
+}
+
+// Service runs admin actions against many users or QR codes in the
+// background, recording what happened to each
+type Service interface {
+	// Create validates the request, fixes its targets and queues the
+	// operation for the job
+	Create(ctx context.Context, adminID uint, action string, req Request) (*Progress, error)
+	Get(ctx context.Context, id uint) (*Progress, error)
+	List(ctx context.Context, limit, offset int) ([]Progress, int64, error)
+	// ListItems pages through the operation's per-target results,
+	// optionally only those in one status
+	ListItems(ctx context.Context, id uint, status string, limit, offset int) ([]models.BulkOperationItem, int64, error)
+	// Report renders every per-target result of the operation as CSV
+	Report(ctx context.Context, id uint) (*models.BulkOperation, []byte, error)
+
+	// RunPending works through queued operations and returns how many
+	// targets it processed
+	RunPending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,24 @@
+package bulk
+
+import (
+	"context"
+	"log"
+)
+
+// Job works through queued bulk admin operations
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the bulk operation service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "bulk-operations" }
+
+func (j *Job) Run(ctx context.Context) error {
+	processed, err := j.service.RunPending(ctx)
+	if processed > 0 {
+		log.Printf("Processed %d bulk operation targets", processed)
+	}
+	return err
+}
@@ -0,0 +1,332 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	qr "orus/internal/services/qr_code"
+	"orus/internal/services/useradmin"
+	"orus/internal/services/wallet"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type service struct {
+	repo    repositories.BulkOperationRepository
+	wallets WalletLocker
+	users   UserSuspender
+	qrCodes QRExpirer
+}
+
+// NewService creates the bulk operation service
+func NewService(repo repositories.BulkOperationRepository, wallets WalletLocker, users UserSuspender, qrCodes QRExpirer) Service {
+	return &service{
+		repo:    repo,
+		wallets: wallets,
+		users:   users,
+		qrCodes: qrCodes,
+	}
+}
+
+func (s *service) Create(ctx context.Context, adminID uint, action string, req Request) (*Progress, error) {
+	op := &models.BulkOperation{
+		Action:      action,
+		Status:      models.BulkStatusPending,
+		RequestedBy: adminID,
+		Reason:      strings.TrimSpace(req.Reason),
+	}
+	if op.Reason == "" {
+		return nil, useradmin.ErrReasonRequired
+	}
+	if action == models.BulkActionLockWallets {
+		params, err := lockSettings(req)
+		if err != nil {
+			return nil, err
+		}
+		op.Params = models.NewJSON(params)
+	}
+
+	var targets []uint
+	var err error
+	switch {
+	case len(req.CSV) > 0 && req.Filter == nil:
+		op.Source = SourceCSV
+		targets, err = parseCSV(req.CSV)
+	case len(req.CSV) == 0 && req.Filter != nil:
+		op.Source = SourceFilter
+		op.Filter = models.NewJSON(req.Filter)
+		targets, err = s.find(action, req.Filter)
+	default:
+		return nil, ErrSourceRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	if len(targets) > MaxTargets {
+		return nil, ErrTooManyTargets
+	}
+
+	op.Total = len(targets)
+	if err := s.repo.Create(op, targets); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d queued bulk %s of %d targets: %s", adminID, action, op.Total, op.Reason)
+	return newProgress(op), nil
+}
+
+// lockSettings checks the wallet lock settings up front, so a bad scope
+// fails the request instead of every item
+func lockSettings(req Request) (*lockParams, error) {
+	switch {
+	case req.Scope == "":
+		return nil, wallet.ErrInvalidLockScope
+	case req.LockReason != models.WalletLockReasonFraud && req.LockReason != models.WalletLockReasonLegal:
+		return nil, wallet.ErrInvalidLockReason
+	case req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()):
+		return nil, wallet.ErrInvalidLockExpiry
+	}
+	return &lockParams{
+		Scope:     req.Scope,
+		Reason:    req.LockReason,
+		Note:      strings.TrimSpace(req.Note),
+		ExpiresAt: req.ExpiresAt,
+	}, nil
+}
+
+// parseCSV reads one ID from the first column of each row. A first row
+// that isn't a number is taken as a header; duplicates are dropped.
+func parseCSV(content []byte) ([]uint, error) {
+	if len(content) > MaxCSVSize {
+		return nil, ErrFileTooLarge
+	}
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	seen := make(map[uint]bool)
+	var ids []uint
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		field := strings.TrimSpace(record[0])
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(field, 10, 32)
+		if err != nil || id == 0 {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidCSV, line)
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+		if len(ids) > MaxTargets {
+			return nil, ErrTooManyTargets
+		}
+	}
+}
+
+// find returns the targets matching the filter, one over MaxTargets at
+// most so too broad a filter can be told apart
+func (s *service) find(action string, f *Filter) ([]uint, error) {
+	if action == models.BulkActionExpireQRCodes {
+		if f.UserID == 0 && f.Type == "" && f.CreatedBefore == nil {
+			return nil, ErrEmptyFilter
+		}
+		return s.repo.FindQRCodeIDs(repositories.BulkQRFilter{
+			UserID:        f.UserID,
+			Type:          f.Type,
+			CreatedBefore: f.CreatedBefore,
+		}, MaxTargets+1)
+	}
+
+	if f.Role == "" && f.Status == "" && f.Country == "" && f.KYCStatus == "" &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil {
+		return nil, ErrEmptyFilter
+	}
+	return s.repo.FindUserIDs(repositories.BulkUserFilter{
+		Role:          f.Role,
+		Status:        f.Status,
+		Country:       f.Country,
+		KYCStatus:     f.KYCStatus,
+		CreatedAfter:  f.CreatedAfter,
+		CreatedBefore: f.CreatedBefore,
+	}, MaxTargets+1)
+}
+
+func (s *service) Get(ctx context.Context, id uint) (*Progress, error) {
+	op, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return newProgress(op), nil
+}
+
+func (s *service) List(ctx context.Context, limit, offset int) ([]Progress, int64, error) {
+	ops, total, err := s.repo.List(limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	progress := make([]Progress, len(ops))
+	for i := range ops {
+		progress[i] = *newProgress(&ops[i])
+	}
+	return progress, total, nil
+}
+
+func (s *service) ListItems(ctx context.Context, id uint, status string, limit, offset int) ([]models.BulkOperationItem, int64, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListItems(id, status, limit, offset)
+}
+
+func (s *service) Report(ctx context.Context, id uint) (*models.BulkOperation, []byte, error) {
+	op, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := s.repo.AllItems(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"target_id", "status", "error", "processed_at"}); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range items {
+		processedAt := ""
+		if item.ProcessedAt != nil {
+			processedAt = item.ProcessedAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{strconv.FormatUint(uint64(item.TargetID), 10), item.Status, item.Error, processedAt}
+		if err := w.Write(record); err != nil {
+			return nil, nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, nil, err
+	}
+	return op, buf.Bytes(), nil
+}
+
+func (s *service) RunPending(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		now := time.Now()
+		op, err := s.repo.Claim(now, now.Add(lease))
+		if err != nil || op == nil {
+			return processed, err
+		}
+		n, err := s.run(ctx, op)
+		processed += n
+		if err != nil {
+			return processed, err
+		}
+	}
+}
+
+// run applies the operation to its pending items until none are left. If
+// the run is cut short the lease runs out and a later run resumes it.
+func (s *service) run(ctx context.Context, op *models.BulkOperation) (int, error) {
+	processed := 0
+	for {
+		items, err := s.repo.PendingItems(op.ID, batchSize)
+		if err != nil {
+			return processed, err
+		}
+		if len(items) == 0 {
+			log.Printf("Bulk operation %d finished", op.ID)
+			return processed, s.repo.Complete(op.ID, time.Now())
+		}
+
+		for i := range items {
+			if err := ctx.Err(); err != nil {
+				return processed, err
+			}
+			item := &items[i]
+			err := s.apply(ctx, op, item.TargetID)
+			if err != nil && ctx.Err() != nil {
+				// Cut short rather than failed; leave it for the next run
+				return processed, ctx.Err()
+			}
+
+			now := time.Now()
+			item.ProcessedAt = &now
+			switch {
+			case err == nil:
+				item.Status = models.BulkItemSucceeded
+			case errors.Is(err, useradmin.ErrAlreadySuspended), errors.Is(err, qr.ErrQRInactive):
+				item.Status = models.BulkItemSkipped
+				item.Error = err.Error()
+			default:
+				item.Status = models.BulkItemFailed
+				item.Error = err.Error()
+			}
+			if err := s.repo.RecordItem(item); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		if err := s.repo.ExtendLease(op.ID, time.Now().Add(lease)); err != nil {
+			return processed, err
+		}
+	}
+}
+
+// apply runs the operation's action against one target through the same
+// service call a single admin action uses
+func (s *service) apply(ctx context.Context, op *models.BulkOperation, targetID uint) error {
+	switch op.Action {
+	case models.BulkActionLockWallets:
+		var params lockParams
+		raw, err := json.Marshal(op.Params)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return fmt.Errorf("invalid lock settings: %w", err)
+		}
+		note := params.Note
+		if note == "" {
+			note = op.Reason
+		}
+		_, err = s.wallets.LockWallet(ctx, &op.RequestedBy, targetID, wallet.LockRequest{
+			Scope:     params.Scope,
+			Reason:    params.Reason,
+			Note:      note,
+			ExpiresAt: params.ExpiresAt,
+		})
+		return err
+	case models.BulkActionSuspendUsers:
+		_, err := s.users.Suspend(ctx, op.RequestedBy, targetID, op.Reason)
+		return err
+	case models.BulkActionExpireQRCodes:
+		_, err := s.qrCodes.ExpireQRCode(ctx, targetID)
+		return err
+	default:
+		return fmt.Errorf("unknown bulk action %q", op.Action)
+	}
+}
@@ -0,0 +1,79 @@
+package bulk
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+const (
+	// MaxTargets caps the users or QR codes one operation can act on
+	MaxTargets = 10000
+	// MaxCSVSize caps an uploaded CSV file
+	MaxCSVSize = 1 << 20
+
+	batchSize = 100
+	// lease is how long a running operation is held by one instance
+	// without progress before another may resume it
+	lease = 5 * time.Minute
+)
+
+// Target sources
+const (
+	SourceCSV    = "csv"
+	SourceFilter = "filter"
+)
+
+// Request starts a bulk operation. Targets come from the uploaded CSV
+// file, one ID per row with an optional header, or from Filter.
+type Request struct {
+	Reason string `json:"reason" form:"reason" validate:"required,max=500"`
+
+	// Wallet lock settings
+	Scope      string     `json:"scope" form:"scope" validate:"omitempty,oneof=debits credits full"`
+	LockReason string     `json:"lock_reason" form:"lock_reason" validate:"omitempty,oneof=fraud legal"`
+	Note       string     `json:"note" form:"note" validate:"max=500"`
+	ExpiresAt  *time.Time `json:"expires_at" form:"-"`
+
+	Filter *Filter `json:"filter" form:"-"`
+	CSV    []byte  `json:"-" form:"-"`
+}
+
+// Filter picks the targets of a bulk operation. Wallet locks and
+// suspensions read the user fields, QR code expiry the QR code ones.
+type Filter struct {
+	// Users
+	Role         string     `json:"role,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	Country      string     `json:"country,omitempty"`
+	KYCStatus    string     `json:"kyc_status,omitempty"`
+	CreatedAfter *time.Time `json:"created_after,omitempty"`
+
+	// QR codes, only active ones
+	UserID uint   `json:"user_id,omitempty"`
+	Type   string `json:"type,omitempty"`
+
+	// Both
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+}
+
+// lockParams are the wallet lock settings stored on an operation
+type lockParams struct {
+	Scope     string     `json:"scope"`
+	Reason    string     `json:"reason"`
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Progress is a bulk operation with how far along it is
+type Progress struct {
+	*models.BulkOperation
+	Percent float64 `json:"percent"`
+}
+
+func newProgress(op *models.BulkOperation) *Progress {
+	percent := 100.0
+	if op.Total > 0 {
+		percent = float64(op.Processed*10000/op.Total) / 100
+	}
+	return &Progress{BulkOperation: op, Percent: percent}
+}
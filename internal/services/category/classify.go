@@ -0,0 +1,121 @@
+package category
+
+import (
+	"orus/internal/models"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mccRange files the merchant category codes From to To under Category
+type mccRange struct {
+	From, To int
+	Category string
+}
+
+// mccRanges maps card network merchant category codes to categories.
+// Narrower ranges come first so they win over the broad ones around them.
+var mccRanges = []mccRange{
+	{5411, 5411, models.CategoryGroceries},
+	{5422, 5499, models.CategoryGroceries},
+	{5811, 5814, models.CategoryDining},
+	{5912, 5912, models.CategoryHealth},
+	{5541, 5542, models.CategoryTransport},
+	{6010, 6011, models.CategoryCash},
+	{3000, 3999, models.CategoryTravel}, // Airlines, car rental and hotels
+	{4511, 4511, models.CategoryTravel},
+	{4722, 4722, models.CategoryTravel},
+	{7011, 7011, models.CategoryTravel},
+	{4000, 4799, models.CategoryTransport},
+	{4800, 4999, models.CategoryBills},
+	{5000, 5999, models.CategoryShopping},
+	{7800, 7999, models.CategoryEntertainment},
+	{8011, 8099, models.CategoryHealth},
+	{8211, 8299, models.CategoryEducation},
+}
+
+// fromMCC returns the category of a four-digit merchant category code,
+// or "" if the value isn't one or isn't mapped
+func fromMCC(value string) string {
+	if len(value) != 4 {
+		return ""
+	}
+	mcc, err := strconv.Atoi(value)
+	if err != nil {
+		return ""
+	}
+	for _, r := range mccRanges {
+		if mcc >= r.From && mcc <= r.To {
+			return r.Category
+		}
+	}
+	return ""
+}
+
+// keywords maps whole words in a merchant's business type, name or a
+// payment's description to categories
+var keywords = map[string]string{
+	"grocery": models.CategoryGroceries, "groceries": models.CategoryGroceries,
+	"supermarket": models.CategoryGroceries, "market": models.CategoryGroceries,
+	"bakery": models.CategoryGroceries, "butcher": models.CategoryGroceries,
+
+	"restaurant": models.CategoryDining, "restaurants": models.CategoryDining,
+	"cafe": models.CategoryDining, "coffee": models.CategoryDining,
+	"bar": models.CategoryDining, "pizza": models.CategoryDining,
+	"food": models.CategoryDining, "takeaway": models.CategoryDining,
+
+	"taxi": models.CategoryTransport, "uber": models.CategoryTransport,
+	"bus": models.CategoryTransport, "train": models.CategoryTransport,
+	"fuel": models.CategoryTransport, "petrol": models.CategoryTransport,
+	"parking": models.CategoryTransport, "transport": models.CategoryTransport,
+
+	"hotel": models.CategoryTravel, "airline": models.CategoryTravel,
+	"flight": models.CategoryTravel, "travel": models.CategoryTravel,
+
+	"retail": models.CategoryShopping, "shop": models.CategoryShopping,
+	"store": models.CategoryShopping, "clothing": models.CategoryShopping,
+	"electronics": models.CategoryShopping, "ecommerce": models.CategoryShopping,
+
+	"electricity": models.CategoryBills, "water": models.CategoryBills,
+	"internet": models.CategoryBills, "telecom": models.CategoryBills,
+	"utilities": models.CategoryBills, "rent": models.CategoryBills,
+	"insurance": models.CategoryBills,
+
+	"cinema": models.CategoryEntertainment, "music": models.CategoryEntertainment,
+	"games": models.CategoryEntertainment, "streaming": models.CategoryEntertainment,
+	"entertainment": models.CategoryEntertainment,
+
+	"pharmacy": models.CategoryHealth, "clinic": models.CategoryHealth,
+	"hospital": models.CategoryHealth, "doctor": models.CategoryHealth,
+	"health": models.CategoryHealth,
+
+	"school": models.CategoryEducation, "tuition": models.CategoryEducation,
+	"university": models.CategoryEducation, "education": models.CategoryEducation,
+}
+
+// fromKeywords returns the category of the first text with a known word
+func fromKeywords(texts ...string) string {
+	for _, text := range texts {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		for _, word := range words {
+			if category, ok := keywords[word]; ok {
+				return category
+			}
+		}
+	}
+	return ""
+}
+
+// fromType returns the category a transaction's type implies, or ""
+func fromType(txType string) string {
+	switch txType {
+	case models.TransactionTypeP2PTransfer, models.TransactionTypeTransfer,
+		models.TransactionTypePotTransfer:
+		return models.CategoryTransfers
+	case models.TransactionTypeWithdrawal:
+		return models.CategoryCash
+	}
+	return ""
+}
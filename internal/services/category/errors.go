@@ -0,0 +1,13 @@
+package category
+
+import "errors"
+
+// Service errors
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrInvalidCategory     = errors.New("unknown transaction category")
+	ErrNotSpending         = errors.New("only payments you sent can be categorized")
+	ErrInvalidRule         = errors.New("rule needs a payee or a pattern")
+	ErrTooManyRules        = errors.New("too many category rules")
+	ErrNoPayee             = errors.New("transaction has no payee to remember")
+)
@@ -0,0 +1,27 @@
+package category
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service files transactions under the spending categories the dashboards
+// break down by. New transactions are categorized as they are created, from
+// the sender's rules and past choices, the merchant's category code and
+// keywords in the payee's name.
+type Service interface {
+	// Categorize sets the transaction's Category and CategorySource. It
+	// never fails; a signal that can't be read is skipped.
+	Categorize(ctx context.Context, tx *models.Transaction)
+	// Recategorize files one of the user's payments under another
+	// category, and with Remember their future payments to the same payee
+	Recategorize(ctx context.Context, userID, txID uint, req RecategorizeRequest) (*models.Transaction, error)
+
+	ListRules(ctx context.Context, userID uint) ([]models.CategoryRule, error)
+	CreateRule(ctx context.Context, userID uint, req RuleRequest) (*models.CategoryRule, error)
+	DeleteRule(ctx context.Context, userID, id uint) error
+
+	// Backfill categorizes transactions created before categorization and
+	// returns how many it changed
+	Backfill(ctx context.Context) (int, error)
+}
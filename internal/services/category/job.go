@@ -0,0 +1,24 @@
+package category
+
+import (
+	"context"
+	"log"
+)
+
+// Job categorizes transactions created before categorization existed
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the categorization service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "category-backfill" }
+
+func (j *Job) Run(ctx context.Context) error {
+	changed, err := j.service.Backfill(ctx)
+	if changed > 0 {
+		log.Printf("Categorized %d older transactions", changed)
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// rulesTTL bounds how long a user's rules are cached; changes drop the
+// entry, so it only matters if that fails
+const rulesTTL = 10 * time.Minute
+
+// backfillBatch is how many old transactions are categorized at a time
+const backfillBatch = 500
+
+type service struct {
+	repo   repositories.TransactionCategoryRepository
+	txRepo repositories.TransactionRepository
+	cache  *cache.CacheService
+}
+
+// NewService creates the transaction categorization service
+func NewService(
+	repo repositories.TransactionCategoryRepository,
+	txRepo repositories.TransactionRepository,
+	cacheSvc *cache.CacheService,
+) Service {
+	return &service{repo: repo, txRepo: txRepo, cache: cacheSvc}
+}
+
+func (s *service) Categorize(ctx context.Context, tx *models.Transaction) {
+	tx.Category, tx.CategorySource = s.classify(ctx, tx)
+}
+
+// classify picks a transaction's category from the strongest signal there
+// is: the sender's own choices first, then what the payee is
+func (s *service) classify(ctx context.Context, tx *models.Transaction) (string, string) {
+	if !tx.CountsAsSpending() {
+		return tx.Category, models.CategorySourceSystem
+	}
+
+	rules, err := s.rules(ctx, tx.SenderID)
+	if err != nil {
+		log.Printf("Failed to load category rules of user %d: %v", tx.SenderID, err)
+	}
+	for i := range rules {
+		if rules[i].Matches(tx) {
+			return rules[i].Category, models.CategorySourceRule
+		}
+	}
+
+	if tx.ReceiverID != 0 {
+		category, err := s.repo.LastChosenCategory(tx.SenderID, tx.ReceiverID)
+		if err != nil {
+			log.Printf("Failed to look up category history of user %d: %v", tx.SenderID, err)
+		}
+		if category != "" {
+			return category, models.CategorySourceHistory
+		}
+	}
+
+	if category := fromMCC(tx.MerchantCategory); category != "" {
+		return category, models.CategorySourceMCC
+	}
+	if category := fromKeywords(tx.MerchantCategory, tx.MerchantName, tx.Description); category != "" {
+		return category, models.CategorySourceKeyword
+	}
+	if category := fromType(tx.Type); category != "" {
+		return category, models.CategorySourceType
+	}
+	return models.CategoryOther, models.CategorySourceType
+}
+
+func (s *service) Recategorize(ctx context.Context, userID, txID uint, req RecategorizeRequest) (*models.Transaction, error) {
+	if !models.IsValidCategory(req.Category) {
+		return nil, ErrInvalidCategory
+	}
+	tx, err := s.txRepo.FindByID(txID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	if tx.SenderID != userID {
+		return nil, ErrTransactionNotFound
+	}
+	if !tx.CountsAsSpending() {
+		return nil, ErrNotSpending
+	}
+
+	var rule *models.CategoryRule
+	if req.Remember {
+		rule = &models.CategoryRule{UserID: userID, ReceiverID: tx.ReceiverID, Category: req.Category}
+		if tx.ReceiverID == 0 {
+			rule.Pattern = strings.ToLower(strings.TrimSpace(tx.MerchantName))
+			if rule.Pattern == "" {
+				return nil, ErrNoPayee
+			}
+		}
+	}
+
+	if err := s.repo.SetCategory(tx, req.Category, models.CategorySourceUser); err != nil {
+		return nil, err
+	}
+	if rule != nil {
+		if err := s.saveRule(ctx, rule); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+func (s *service) ListRules(ctx context.Context, userID uint) ([]models.CategoryRule, error) {
+	return s.repo.ListRules(userID)
+}
+
+func (s *service) CreateRule(ctx context.Context, userID uint, req RuleRequest) (*models.CategoryRule, error) {
+	if !models.IsValidCategory(req.Category) {
+		return nil, ErrInvalidCategory
+	}
+	rule := &models.CategoryRule{
+		UserID:     userID,
+		ReceiverID: req.ReceiverID,
+		Pattern:    strings.ToLower(strings.TrimSpace(req.Pattern)),
+		Category:   req.Category,
+	}
+	if rule.ReceiverID == 0 && rule.Pattern == "" {
+		return nil, ErrInvalidRule
+	}
+	if err := s.saveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// saveRule stores a new rule, enforcing the per-user limit
+func (s *service) saveRule(ctx context.Context, rule *models.CategoryRule) error {
+	count, err := s.repo.CountRules(rule.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= MaxRules {
+		return ErrTooManyRules
+	}
+	if err := s.repo.CreateRule(rule); err != nil {
+		return err
+	}
+	s.dropRules(ctx, rule.UserID)
+	return nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, userID, id uint) error {
+	if err := s.repo.DeleteRule(userID, id); err != nil {
+		return err
+	}
+	s.dropRules(ctx, userID)
+	return nil
+}
+
+// rules returns the user's rules, newest first so they win over older ones
+func (s *service) rules(ctx context.Context, userID uint) ([]models.CategoryRule, error) {
+	var rules []models.CategoryRule
+	err := s.cache.Load(ctx, rulesKey(userID), rulesTTL, &rules, func() (interface{}, error) {
+		return s.repo.ListRules(userID)
+	})
+	return rules, err
+}
+
+// dropRules makes the user's next payment read their rules afresh
+func (s *service) dropRules(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, rulesKey(userID)); err != nil {
+		log.Printf("Failed to drop cached category rules of user %d: %v", userID, err)
+	}
+}
+
+func rulesKey(userID uint) string {
+	return fmt.Sprintf("category_rules:%d", userID)
+}
+
+func (s *service) Backfill(ctx context.Context) (int, error) {
+	var afterID uint
+	changed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		batch, err := s.repo.ListUncategorized(afterID, backfillBatch)
+		if err != nil {
+			return changed, err
+		}
+		for i := range batch {
+			tx := &batch[i]
+			afterID = tx.ID
+			category, source := s.classify(ctx, tx)
+			if err := s.repo.SetCategory(tx, category, source); err != nil {
+				return changed, err
+			}
+			changed++
+		}
+		if len(batch) < backfillBatch {
+			return changed, nil
+		}
+	}
+}
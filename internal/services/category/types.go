@@ -0,0 +1,20 @@
+package category
+
+// MaxRules bounds how many category rules a user can have
+const MaxRules = 100
+
+// RecategorizeRequest files a payment under another category
+type RecategorizeRequest struct {
+	Category string `json:"category" validate:"required"`
+	// Remember files future payments to the same payee under the category
+	Remember bool `json:"remember"`
+}
+
+// RuleRequest creates a category rule. A rule for a payee matches their
+// payments; one with a pattern matches payments whose merchant name or
+// description contains it.
+type RuleRequest struct {
+	ReceiverID uint   `json:"receiver_id"`
+	Pattern    string `json:"pattern" validate:"max=100"`
+	Category   string `json:"category" validate:"required"`
+}
@@ -0,0 +1,20 @@
+package checkout
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidAmount     = errors.New("invalid amount")
+	ErrInvalidExpiry     = errors.New("expiry must be in the future")
+	ErrInvalidMaxUses    = errors.New("max uses must be -1 (unlimited) or a positive number")
+	ErrNotMerchant       = errors.New("merchant profile not found")
+	ErrLinkUnavailable   = errors.New("payment link is no longer available")
+	ErrSelfPayment       = errors.New("merchants cannot pay their own payment links")
+	ErrSessionExpired    = errors.New("checkout session has expired")
+	ErrSessionNotOpen    = errors.New("checkout session is not open")
+	ErrSessionNotAllowed = errors.New("checkout session belongs to another user")
+	ErrInvalidSessionTTL = errors.New("expires_in_minutes must be between 5 and 1440")
+	ErrInvalidReturnURL  = errors.New("return URLs must be absolute http or https URLs")
+	ErrInvalidMetadata   = errors.New("metadata is limited to 20 keys")
+	ErrInvalidReference  = errors.New("client reference is limited to 100 characters")
+)
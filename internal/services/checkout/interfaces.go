@@ -0,0 +1,50 @@
+package checkout
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/loyalty"
+)
+
+// TransactionService defines the payment processing used to settle checkouts
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
+
+// WebhookService notifies merchants about checkout events
+type WebhookService interface {
+	Enqueue(ctx context.Context, merchantUserID uint, event string, data interface{}) error
+}
+
+// LoyaltyService spends a payer's loyalty points as a checkout discount
+type LoyaltyService interface {
+	Reserve(ctx context.Context, customerID, merchantID uint, amount float64, points int64) (*loyalty.Redemption, error)
+	Confirm(ctx context.Context, redemption *loyalty.Redemption, transactionID uint) error
+	Release(ctx context.Context, redemption *loyalty.Redemption) error
+}
+
+// Service manages merchant payment links and the checkout sessions opened
+// from them or through the merchant API
+type Service interface {
+	CreateLink(ctx context.Context, merchantID uint, req CreateLinkRequest) (*models.PaymentLink, error)
+	ListLinks(ctx context.Context, merchantID uint, limit, offset int) ([]models.PaymentLink, int64, error)
+	GetLink(ctx context.Context, merchantID, linkID uint) (*LinkDetails, error)
+	DisableLink(ctx context.Context, merchantID, linkID uint) (*models.PaymentLink, error)
+
+	// GetHostedLink returns what the hosted checkout page shows and records a view
+	GetHostedLink(ctx context.Context, code string) (*HostedLink, error)
+	OpenSession(ctx context.Context, userID uint, code string) (*models.CheckoutSession, error)
+	GetSession(ctx context.Context, userID uint, sessionID string) (*models.CheckoutSession, error)
+	// CompleteSession pays the session, taking off the discount for any
+	// loyalty points the payer spends at the merchant
+	CompleteSession(ctx context.Context, userID uint, sessionID string, loyaltyPoints int64) (*CheckoutResult, error)
+
+	// Merchant API: orders opened by the merchant's server for any payer
+	CreateOrderSession(ctx context.Context, merchant *models.Merchant, req CreateSessionRequest) (*OrderSession, error)
+	GetOrderSession(ctx context.Context, merchantID uint, sessionID string) (*OrderSession, error)
+	ListOrderSessions(ctx context.Context, merchantID uint, status string, limit, offset int) ([]OrderSession, int64, error)
+	ExpireOrderSession(ctx context.Context, merchantID uint, sessionID string) (*OrderSession, error)
+	// GetHostedSession returns what the hosted checkout page shows for an
+	// order session
+	GetHostedSession(ctx context.Context, sessionID string) (*HostedSession, error)
+}
@@ -0,0 +1,188 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOrderTTL is how long an order session stays payable unless
+	// the merchant asks otherwise
+	DefaultOrderTTL = time.Hour
+	MinOrderTTL     = 5 * time.Minute
+	MaxOrderTTL     = 24 * time.Hour
+
+	maxMetadataKeys       = 20
+	maxClientReferenceLen = 100
+)
+
+// CreateOrderSession opens a checkout any payer can complete from the
+// hosted page or by scanning its QR code
+func (s *service) CreateOrderSession(ctx context.Context, merchant *models.Merchant, req CreateSessionRequest) (*OrderSession, error) {
+	if req.Amount <= 0 || req.Amount > MaxLinkAmount {
+		return nil, ErrInvalidAmount
+	}
+	ttl := DefaultOrderTTL
+	if req.ExpiresInMinutes != 0 {
+		ttl = time.Duration(req.ExpiresInMinutes) * time.Minute
+		if ttl < MinOrderTTL || ttl > MaxOrderTTL {
+			return nil, ErrInvalidSessionTTL
+		}
+	}
+	reference := strings.TrimSpace(req.ClientReference)
+	if len(reference) > maxClientReferenceLen {
+		return nil, ErrInvalidReference
+	}
+	if len(req.Metadata) > maxMetadataKeys {
+		return nil, ErrInvalidMetadata
+	}
+	for _, u := range []string{req.SuccessURL, req.CancelURL} {
+		if u != "" && !isReturnURL(u) {
+			return nil, ErrInvalidReturnURL
+		}
+	}
+
+	sessionID, err := utils.GenerateUniqueID(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	session := &models.CheckoutSession{
+		SessionID:       "cs_" + sessionID,
+		Source:          models.CheckoutSourceAPI,
+		MerchantID:      merchant.UserID,
+		Amount:          math.Round(req.Amount*100) / 100,
+		Currency:        "USD",
+		Description:     strings.TrimSpace(req.Description),
+		ClientReference: reference,
+		SuccessURL:      req.SuccessURL,
+		CancelURL:       req.CancelURL,
+		Status:          models.CheckoutSessionStatusOpen,
+		ExpiresAt:       time.Now().Add(ttl),
+	}
+	if len(req.Metadata) > 0 {
+		session.Metadata = models.NewJSON(req.Metadata)
+	}
+	if err := s.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+	return s.orderSession(session), nil
+}
+
+func (s *service) GetOrderSession(ctx context.Context, merchantID uint, sessionID string) (*OrderSession, error) {
+	session, err := s.getOrderSession(merchantID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	s.expireIfDue(session)
+	return s.orderSession(session), nil
+}
+
+func (s *service) ListOrderSessions(ctx context.Context, merchantID uint, status string, limit, offset int) ([]OrderSession, int64, error) {
+	sessions, total, err := s.repo.ListSessions(merchantID, models.CheckoutSourceAPI, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	orders := make([]OrderSession, len(sessions))
+	for i := range sessions {
+		orders[i] = *s.orderSession(&sessions[i])
+	}
+	return orders, total, nil
+}
+
+// ExpireOrderSession closes an open order session, e.g. when the shopper
+// abandons the order
+func (s *service) ExpireOrderSession(ctx context.Context, merchantID uint, sessionID string) (*OrderSession, error) {
+	session, err := s.getOrderSession(merchantID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	expired, err := s.repo.TransitionSession(session.ID, models.CheckoutSessionStatusOpen, map[string]interface{}{
+		"status": models.CheckoutSessionStatusExpired,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !expired {
+		return nil, ErrSessionNotOpen
+	}
+	session.Status = models.CheckoutSessionStatusExpired
+	return s.orderSession(session), nil
+}
+
+func (s *service) GetHostedSession(ctx context.Context, sessionID string) (*HostedSession, error) {
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Source != models.CheckoutSourceAPI {
+		return nil, repositories.ErrCheckoutSessionNotFound
+	}
+	s.expireIfDue(session)
+
+	merchantName := ""
+	if merchant, err := s.merchants.GetByUserID(session.MerchantID); err == nil {
+		merchantName = merchant.BusinessName
+	}
+
+	return &HostedSession{
+		ID:           session.SessionID,
+		Amount:       session.Amount,
+		Currency:     session.Currency,
+		Description:  session.Description,
+		MerchantName: merchantName,
+		Status:       session.Status,
+		ExpiresAt:    session.ExpiresAt,
+		CancelURL:    session.CancelURL,
+	}, nil
+}
+
+// getOrderSession loads one of the merchant's API sessions. Other
+// merchants' sessions are reported as not found.
+func (s *service) getOrderSession(merchantID uint, sessionID string) (*models.CheckoutSession, error) {
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Source != models.CheckoutSourceAPI || session.MerchantID != merchantID {
+		return nil, repositories.ErrCheckoutSessionNotFound
+	}
+	return session, nil
+}
+
+// expireIfDue records that an open session has run out of time, so its
+// status is accurate when polled
+func (s *service) expireIfDue(session *models.CheckoutSession) {
+	if session.Status != models.CheckoutSessionStatusOpen || time.Now().Before(session.ExpiresAt) {
+		return
+	}
+	expired, err := s.repo.TransitionSession(session.ID, models.CheckoutSessionStatusOpen, map[string]interface{}{
+		"status": models.CheckoutSessionStatusExpired,
+	})
+	if err != nil {
+		log.Printf("Failed to expire checkout session %s: %v", session.SessionID, err)
+		return
+	}
+	if expired {
+		session.Status = models.CheckoutSessionStatusExpired
+	}
+}
+
+func (s *service) orderSession(session *models.CheckoutSession) *OrderSession {
+	hostedURL := fmt.Sprintf("%s/api/checkout/hosted/%s", s.baseURL, session.SessionID)
+	// The QR code encodes the hosted page too, so any camera app can open it
+	return &OrderSession{CheckoutSession: session, RedirectURL: hostedURL, QRPayload: hostedURL}
+}
+
+func isReturnURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
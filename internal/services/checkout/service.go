@@ -0,0 +1,367 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/loyalty"
+	"orus/internal/services/webhook"
+	"orus/internal/utils"
+	"time"
+)
+
+const (
+	// SessionTTL is how long a payer has to complete a checkout
+	SessionTTL = 30 * time.Minute
+	// MaxLinkAmount is the largest amount a payment link can request
+	MaxLinkAmount = 10000.0
+)
+
+type service struct {
+	repo           repositories.PaymentLinkRepository
+	merchants      repositories.MerchantRepository
+	transactionSvc TransactionService
+	webhooks       WebhookService
+	loyalty        LoyaltyService
+	baseURL        string
+}
+
+// NewService creates a new payment link and checkout service.
+// baseURL is the public origin that hosted checkout links are served from.
+func NewService(
+	repo repositories.PaymentLinkRepository,
+	merchants repositories.MerchantRepository,
+	transactionSvc TransactionService,
+	webhooks WebhookService,
+	loyalty LoyaltyService,
+	baseURL string,
+) Service {
+	return &service{
+		repo:           repo,
+		merchants:      merchants,
+		transactionSvc: transactionSvc,
+		webhooks:       webhooks,
+		loyalty:        loyalty,
+		baseURL:        baseURL,
+	}
+}
+
+// CreateLink creates a shareable link for a fixed amount
+func (s *service) CreateLink(ctx context.Context, merchantID uint, req CreateLinkRequest) (*models.PaymentLink, error) {
+	if req.Amount <= 0 || req.Amount > MaxLinkAmount {
+		return nil, ErrInvalidAmount
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+	maxUses := -1
+	if req.MaxUses != nil {
+		maxUses = *req.MaxUses
+	}
+	if maxUses == 0 || maxUses < -1 {
+		return nil, ErrInvalidMaxUses
+	}
+
+	if _, err := s.merchants.GetByUserID(merchantID); err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	code, err := utils.GenerateUniqueID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate link code: %w", err)
+	}
+
+	link := &models.PaymentLink{
+		MerchantID:  merchantID,
+		Code:        code,
+		Amount:      math.Round(req.Amount*100) / 100,
+		Currency:    "USD",
+		Description: req.Description,
+		Status:      models.PaymentLinkStatusActive,
+		ExpiresAt:   req.ExpiresAt,
+		MaxUses:     maxUses,
+	}
+	if err := s.repo.Create(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *service) ListLinks(ctx context.Context, merchantID uint, limit, offset int) ([]models.PaymentLink, int64, error) {
+	return s.repo.GetByMerchantID(merchantID, limit, offset)
+}
+
+// GetLink returns a merchant's link with its view and conversion analytics
+func (s *service) GetLink(ctx context.Context, merchantID, linkID uint) (*LinkDetails, error) {
+	link, err := s.repo.GetByIDAndMerchantID(linkID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := LinkStats{
+		Views:       link.ViewCount,
+		Conversions: link.UseCount,
+		Revenue:     math.Round(float64(link.UseCount)*link.Amount*100) / 100,
+	}
+	if link.ViewCount > 0 {
+		stats.ConversionRate = math.Round(float64(link.UseCount)/float64(link.ViewCount)*10000) / 100
+	}
+
+	return &LinkDetails{
+		PaymentLink: link,
+		URL:         fmt.Sprintf("%s/api/pay/%s", s.baseURL, link.Code),
+		Stats:       stats,
+	}, nil
+}
+
+// DisableLink stops a link from accepting further payments
+func (s *service) DisableLink(ctx context.Context, merchantID, linkID uint) (*models.PaymentLink, error) {
+	link, err := s.repo.GetByIDAndMerchantID(linkID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	link.Status = models.PaymentLinkStatusDisabled
+	if err := s.repo.Update(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *service) GetHostedLink(ctx context.Context, code string) (*HostedLink, error) {
+	link, err := s.repo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	merchantName := ""
+	if merchant, err := s.merchants.GetByUserID(link.MerchantID); err == nil {
+		merchantName = merchant.BusinessName
+	}
+
+	if err := s.repo.IncrementViews(link.ID); err != nil {
+		log.Printf("Failed to record view for payment link %d: %v", link.ID, err)
+	}
+
+	return &HostedLink{
+		Code:         link.Code,
+		Amount:       link.Amount,
+		Currency:     link.Currency,
+		Description:  link.Description,
+		MerchantName: merchantName,
+		ExpiresAt:    link.ExpiresAt,
+		Available:    link.IsUsable(time.Now()),
+	}, nil
+}
+
+// OpenSession starts a checkout for the payer at the link's amount
+func (s *service) OpenSession(ctx context.Context, userID uint, code string) (*models.CheckoutSession, error) {
+	link, err := s.repo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if !link.IsUsable(time.Now()) {
+		return nil, ErrLinkUnavailable
+	}
+	if link.MerchantID == userID {
+		return nil, ErrSelfPayment
+	}
+
+	sessionID, err := utils.GenerateUniqueID(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	session := &models.CheckoutSession{
+		SessionID:     "cs_" + sessionID,
+		Source:        models.CheckoutSourcePaymentLink,
+		PaymentLinkID: &link.ID,
+		MerchantID:    link.MerchantID,
+		UserID:        userID,
+		Amount:        link.Amount,
+		Currency:      link.Currency,
+		Description:   link.Description,
+		Status:        models.CheckoutSessionStatusOpen,
+		ExpiresAt:     time.Now().Add(SessionTTL),
+	}
+	if err := s.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *service) GetSession(ctx context.Context, userID uint, sessionID string) (*models.CheckoutSession, error) {
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	// Order sessions from the merchant API are open to any payer until paid
+	if session.UserID != userID && !(session.Source == models.CheckoutSourceAPI && session.UserID == 0) {
+		return nil, ErrSessionNotAllowed
+	}
+	return session, nil
+}
+
+// CompleteSession pays the merchant from the payer's wallet and closes the session
+func (s *service) CompleteSession(ctx context.Context, userID uint, sessionID string, loyaltyPoints int64) (*CheckoutResult, error) {
+	session, err := s.GetSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.CheckoutSessionStatusOpen {
+		return nil, ErrSessionNotOpen
+	}
+
+	now := time.Now()
+	if !now.Before(session.ExpiresAt) {
+		session.Status = models.CheckoutSessionStatusExpired
+		if err := s.repo.UpdateSession(session); err != nil {
+			log.Printf("Failed to expire checkout session %s: %v", session.SessionID, err)
+		}
+		return nil, ErrSessionExpired
+	}
+	if session.MerchantID == userID {
+		return nil, ErrSelfPayment
+	}
+
+	var link *models.PaymentLink
+	if session.PaymentLinkID != nil {
+		link, err = s.repo.GetByID(*session.PaymentLinkID)
+		if err != nil {
+			return nil, err
+		}
+		if link.Status != models.PaymentLinkStatusActive || (link.ExpiresAt != nil && !now.Before(*link.ExpiresAt)) {
+			return nil, ErrLinkUnavailable
+		}
+	}
+
+	merchant, err := s.merchants.GetByUserID(session.MerchantID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	// Claim the session so concurrent attempts can't pay it twice
+	claimed, err := s.repo.TransitionSession(session.ID, models.CheckoutSessionStatusOpen, map[string]interface{}{
+		"status":  models.CheckoutSessionStatusProcessing,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrSessionNotOpen
+	}
+
+	// Claim a use before moving money so max uses holds under concurrent checkouts
+	if link != nil {
+		if err := s.repo.ReserveUse(link.ID); err != nil {
+			s.reopen(session)
+			if errors.Is(err, repositories.ErrPaymentLinkExhausted) {
+				return nil, ErrLinkUnavailable
+			}
+			return nil, err
+		}
+	}
+
+	// Points are spent before the payment and handed back if it fails
+	amount := session.Amount
+	metadata := map[string]interface{}{"checkout_session_id": session.SessionID}
+	var redemption *loyalty.Redemption
+	if loyaltyPoints > 0 {
+		redemption, err = s.loyalty.Reserve(ctx, userID, session.MerchantID, session.Amount, loyaltyPoints)
+		if err != nil {
+			s.releaseUse(link)
+			s.reopen(session)
+			return nil, err
+		}
+		amount = redemption.NetAmount
+		metadata["loyalty_points"] = redemption.Points
+		metadata["loyalty_discount"] = redemption.Discount
+		metadata["gross_amount"] = session.Amount
+	}
+
+	txID := fmt.Sprintf("CHK-%d-%d", session.ID, now.UnixNano())
+	paymentType := "checkout"
+	if link != nil {
+		metadata["payment_link_id"] = link.ID
+		txID = fmt.Sprintf("PLK-%d-%d", link.ID, now.UnixNano())
+		paymentType = "payment_link"
+	}
+	if session.ClientReference != "" {
+		metadata["client_reference"] = session.ClientReference
+	}
+
+	tx, err := s.transactionSvc.ProcessTransaction(ctx, &models.Transaction{
+		Type:             "merchant_payment",
+		SenderID:         userID,
+		ReceiverID:       session.MerchantID,
+		Amount:           amount,
+		Currency:         session.Currency,
+		Description:      session.Description,
+		Status:           "pending",
+		TransactionID:    txID,
+		Reference:        session.SessionID,
+		PaymentType:      paymentType,
+		PaymentMethod:    "wallet",
+		MerchantID:       &merchant.ID,
+		MerchantName:     merchant.BusinessName,
+		MerchantCategory: merchant.BusinessType,
+		Category:         "Sale",
+		Metadata:         models.NewJSON(metadata),
+	})
+	if err != nil {
+		if redemption != nil {
+			if releaseErr := s.loyalty.Release(ctx, redemption); releaseErr != nil {
+				log.Printf("Failed to return %d loyalty points to user %d: %v", redemption.Points, userID, releaseErr)
+			}
+		}
+		s.releaseUse(link)
+		s.reopen(session)
+		return nil, err
+	}
+	if redemption != nil {
+		if err := s.loyalty.Confirm(ctx, redemption, tx.ID); err != nil {
+			log.Printf("Failed to link loyalty redemption to transaction %d: %v", tx.ID, err)
+		}
+	}
+
+	completedAt := time.Now()
+	session.Status = models.CheckoutSessionStatusCompleted
+	session.UserID = userID
+	session.TransactionID = &tx.ID
+	session.CompletedAt = &completedAt
+	if err := s.repo.UpdateSession(session); err != nil {
+		// The payment went through; don't report failure to the payer
+		log.Printf("Failed to mark checkout session %s completed: %v", session.SessionID, err)
+	}
+
+	if err := s.webhooks.Enqueue(ctx, session.MerchantID, webhook.EventCheckoutSessionCompleted, session); err != nil {
+		log.Printf("Failed to queue webhook for checkout session %s: %v", session.SessionID, err)
+	}
+
+	return &CheckoutResult{Session: session, Transaction: tx}, nil
+}
+
+// releaseUse hands back a payment link use claimed for a failed checkout
+func (s *service) releaseUse(link *models.PaymentLink) {
+	if link == nil {
+		return
+	}
+	if err := s.repo.ReleaseUse(link.ID); err != nil {
+		log.Printf("Failed to release use of payment link %d: %v", link.ID, err)
+	}
+}
+
+// reopen hands a claimed session back after its payment failed
+func (s *service) reopen(session *models.CheckoutSession) {
+	_, err := s.repo.TransitionSession(session.ID, models.CheckoutSessionStatusProcessing, map[string]interface{}{
+		"status":  models.CheckoutSessionStatusOpen,
+		"user_id": session.UserID,
+	})
+	if err != nil {
+		log.Printf("Failed to reopen checkout session %s: %v", session.SessionID, err)
+	}
+}
@@ -0,0 +1,79 @@
+package checkout
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// CreateLinkRequest is a merchant's request for a new payment link
+type CreateLinkRequest struct {
+	Amount      float64    `json:"amount"`
+	Description string     `json:"description"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	MaxUses     *int       `json:"max_uses"`
+}
+
+// LinkStats are the conversion analytics for a payment link
+type LinkStats struct {
+	Views          int     `json:"views"`
+	Conversions    int     `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+	Revenue        float64 `json:"revenue"`
+}
+
+// LinkDetails is a payment link with its analytics
+type LinkDetails struct {
+	*models.PaymentLink
+	URL   string    `json:"url"`
+	Stats LinkStats `json:"stats"`
+}
+
+// HostedLink is the public view of a payment link for the hosted checkout page
+type HostedLink struct {
+	Code         string     `json:"code"`
+	Amount       float64    `json:"amount"`
+	Currency     string     `json:"currency"`
+	Description  string     `json:"description"`
+	MerchantName string     `json:"merchant_name"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	Available    bool       `json:"available"`
+}
+
+// CheckoutResult is a completed checkout and its wallet transaction
+type CheckoutResult struct {
+	Session     *models.CheckoutSession `json:"session"`
+	Transaction *models.Transaction     `json:"transaction"`
+}
+
+// CreateSessionRequest is a merchant server's request to open a checkout
+// for an online order. ClientReference is the merchant's own order ID.
+type CreateSessionRequest struct {
+	Amount           float64                `json:"amount"`
+	Description      string                 `json:"description"`
+	ClientReference  string                 `json:"client_reference"`
+	Metadata         map[string]interface{} `json:"metadata"`
+	SuccessURL       string                 `json:"success_url"`
+	CancelURL        string                 `json:"cancel_url"`
+	ExpiresInMinutes int                    `json:"expires_in_minutes"`
+}
+
+// OrderSession is an API checkout session with the ways to send the payer
+// to it: a hosted page to redirect to, or a QR code to show
+type OrderSession struct {
+	*models.CheckoutSession
+	RedirectURL string `json:"redirect_url"`
+	QRPayload   string `json:"qr_payload"`
+}
+
+// HostedSession is the public view of an order session for the hosted
+// checkout page
+type HostedSession struct {
+	ID           string    `json:"id"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	Description  string    `json:"description"`
+	MerchantName string    `json:"merchant_name"`
+	Status       string    `json:"status"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CancelURL    string    `json:"cancel_url,omitempty"`
+}
@@ -0,0 +1,11 @@
+package compliance
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownType       = errors.New("type must be ctr or sar")
+	ErrUnknownStatus     = errors.New("status must be draft, in_review, filed or dismissed")
+	ErrReferenceRequired = errors.New("a filing reference is required to file a report")
+	ErrNoteRequired      = errors.New("a note is required to dismiss a report")
+)
@@ -0,0 +1,25 @@
+package compliance
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service flags transactions regulators want reported, drafts large
+// transaction (CTR) and suspicious activity (SAR) reports from them, and
+// tracks the drafts through review to filing
+type Service interface {
+	// MonitorDue drafts reports for the transactions flagged since the
+	// last run and returns how many it drafted
+	MonitorDue(ctx context.Context) (int, error)
+
+	List(ctx context.Context, filter ListFilter, limit, offset int) ([]models.ComplianceReport, int64, error)
+	Get(ctx context.Context, id uint) (*models.ComplianceReport, error)
+	// StartReview assigns the report to the officer and marks it in review
+	StartReview(ctx context.Context, officerID, id uint) (*models.ComplianceReport, error)
+	UpdateNarrative(ctx context.Context, officerID, id uint, req NarrativeRequest) (*models.ComplianceReport, error)
+	File(ctx context.Context, officerID, id uint, req FileRequest) (*models.ComplianceReport, error)
+	Dismiss(ctx context.Context, officerID, id uint, req DismissRequest) (*models.ComplianceReport, error)
+	// Export returns the report as CSV, a row per transaction, for filing
+	Export(ctx context.Context, id uint) ([]byte, error)
+}
@@ -0,0 +1,24 @@
+package compliance
+
+import (
+	"context"
+	"log"
+)
+
+// Job drafts compliance reports from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the compliance service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "compliance-monitoring" }
+
+func (j *Job) Run(ctx context.Context) error {
+	drafted, err := j.service.MonitorDue(ctx)
+	if drafted > 0 {
+		log.Printf("Drafted %d compliance reports", drafted)
+	}
+	return err
+}
@@ -0,0 +1,304 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var reportTypes = []string{models.ComplianceReportCTR, models.ComplianceReportSAR}
+
+var reportStatuses = []string{
+	models.ComplianceReportDraft,
+	models.ComplianceReportInReview,
+	models.ComplianceReportFiled,
+	models.ComplianceReportDismissed,
+}
+
+type service struct {
+	repo     repositories.ComplianceRepository
+	userRepo repositories.UserRepository
+	cfg      Config
+}
+
+// NewService creates the compliance reporting service
+func NewService(repo repositories.ComplianceRepository, userRepo repositories.UserRepository, cfg Config) Service {
+	return &service{repo: repo, userRepo: userRepo, cfg: cfg}
+}
+
+func (s *service) MonitorDue(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+
+	large, err := s.draftLargeTransactions(now.Add(-lookback))
+	if err != nil {
+		return large, err
+	}
+	structuring, err := s.draftStructuring(now)
+	return large + structuring, err
+}
+
+// draftLargeTransactions drafts a CTR for every transaction at or above
+// the threshold since, that isn't reported yet
+func (s *service) draftLargeTransactions(since time.Time) (int, error) {
+	transactions, err := s.repo.FindLargeTransactions(since, s.cfg.LargeTransactionThreshold, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	drafted := 0
+	for _, tx := range transactions {
+		subjectID := tx.SenderID
+		if subjectID == 0 {
+			subjectID = tx.ReceiverID // Top-ups have no sender
+		}
+		report := s.draft(models.ComplianceReportCTR, models.ComplianceReasonLargeTransaction, subjectID, tx.Currency, []models.Transaction{tx})
+		report.Narrative = fmt.Sprintf(
+			"%s transaction %d of %.2f %s on %s is at or above the large transaction threshold of %.2f %s.",
+			tx.Type, tx.ID, tx.Amount, tx.Currency, tx.CreatedAt.UTC().Format(time.RFC3339),
+			s.cfg.LargeTransactionThreshold, tx.Currency,
+		)
+		if err := s.create(report); err != nil {
+			return drafted, err
+		}
+		drafted++
+	}
+	return drafted, nil
+}
+
+// draftStructuring drafts a SAR for every user whose transactions below
+// the threshold within the structuring window add up to a pattern
+func (s *service) draftStructuring(now time.Time) (int, error) {
+	since := now.Add(-s.cfg.StructuringWindow)
+	floor, threshold := s.cfg.StructuringFloor, s.cfg.LargeTransactionThreshold
+
+	candidates, err := s.repo.FindStructuring(since, floor, threshold, s.cfg.StructuringMinCount, s.cfg.StructuringMinTotal)
+	if err != nil {
+		return 0, err
+	}
+
+	drafted := 0
+	for _, candidate := range candidates {
+		transactions, err := s.repo.GetStructuringTransactions(candidate, since, floor, threshold)
+		if err != nil {
+			return drafted, err
+		}
+		if len(transactions) == 0 {
+			continue
+		}
+
+		report := s.draft(models.ComplianceReportSAR, models.ComplianceReasonStructuring, candidate.UserID, candidate.Currency, transactions)
+		report.Narrative = fmt.Sprintf(
+			"User %d made %d transactions between %s and %s totalling %.2f %s. Each was between %.2f and %.2f %s, "+
+				"just below the large transaction threshold, which may indicate structuring to avoid reporting.",
+			candidate.UserID, report.TransactionCount,
+			report.PeriodStart.Format(time.RFC3339), report.PeriodEnd.Format(time.RFC3339),
+			report.TotalAmount, candidate.Currency, floor, threshold, candidate.Currency,
+		)
+		if err := s.create(report); err != nil {
+			return drafted, err
+		}
+		drafted++
+	}
+	return drafted, nil
+}
+
+// draft builds a report on the transactions, with the subject's details
+// as they are now
+func (s *service) draft(reportType, reason string, subjectID uint, currency string, transactions []models.Transaction) *models.ComplianceReport {
+	report := &models.ComplianceReport{
+		Type:             reportType,
+		Reason:           reason,
+		Status:           models.ComplianceReportDraft,
+		SubjectUserID:    subjectID,
+		Currency:         currency,
+		TransactionCount: len(transactions),
+		PeriodStart:      transactions[0].CreatedAt.UTC(),
+		PeriodEnd:        transactions[0].CreatedAt.UTC(),
+	}
+	if user, err := s.userRepo.GetByID(subjectID); err == nil {
+		report.SubjectName = user.Name
+		report.SubjectEmail = user.Email
+		report.SubjectPhone = user.Phone
+		report.SubjectCountry = user.Country
+		report.SubjectKYCStatus = user.KYCStatus
+	}
+
+	for _, tx := range transactions {
+		report.TotalAmount += tx.Amount
+		occurred := tx.CreatedAt.UTC()
+		if occurred.Before(report.PeriodStart) {
+			report.PeriodStart = occurred
+		}
+		if occurred.After(report.PeriodEnd) {
+			report.PeriodEnd = occurred
+		}
+		report.Transactions = append(report.Transactions, models.ComplianceReportTransaction{
+			TransactionID: tx.ID,
+			Type:          tx.Type,
+			Amount:        tx.Amount,
+			Currency:      tx.Currency,
+			SenderID:      tx.SenderID,
+			ReceiverID:    tx.ReceiverID,
+			OccurredAt:    occurred,
+		})
+	}
+	return report
+}
+
+// create saves the draft, skipping it if another run reported its
+// transactions first
+func (s *service) create(report *models.ComplianceReport) error {
+	err := s.repo.Create(report)
+	if errors.Is(err, repositories.ErrComplianceTransactionsReported) {
+		return nil
+	}
+	return err
+}
+
+func (s *service) List(ctx context.Context, filter ListFilter, limit, offset int) ([]models.ComplianceReport, int64, error) {
+	if filter.Type != "" && !slices.Contains(reportTypes, filter.Type) {
+		return nil, 0, ErrUnknownType
+	}
+	if filter.Status != "" && !slices.Contains(reportStatuses, filter.Status) {
+		return nil, 0, ErrUnknownStatus
+	}
+	return s.repo.List(repositories.ComplianceReportFilter(filter), limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, id uint) (*models.ComplianceReport, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *service) StartReview(ctx context.Context, officerID, id uint) (*models.ComplianceReport, error) {
+	report, err := s.open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Status = models.ComplianceReportInReview
+	report.AssignedTo = &officerID
+	if err := s.repo.Update(report, "Status", "AssignedTo"); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *service) UpdateNarrative(ctx context.Context, officerID, id uint, req NarrativeRequest) (*models.ComplianceReport, error) {
+	report, err := s.open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Narrative = req.Narrative
+	fields := []string{"Narrative"}
+	if report.AssignedTo == nil {
+		report.AssignedTo = &officerID
+		fields = append(fields, "AssignedTo")
+	}
+	if err := s.repo.Update(report, fields...); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *service) File(ctx context.Context, officerID, id uint, req FileRequest) (*models.ComplianceReport, error) {
+	if req.Reference == "" {
+		return nil, ErrReferenceRequired
+	}
+	report, err := s.open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Status = models.ComplianceReportFiled
+	report.FilingReference = req.Reference
+	report.ReviewNote = req.Note
+	if err := s.review(report, officerID, "FilingReference"); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *service) Dismiss(ctx context.Context, officerID, id uint, req DismissRequest) (*models.ComplianceReport, error) {
+	if req.Note == "" {
+		return nil, ErrNoteRequired
+	}
+	report, err := s.open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Status = models.ComplianceReportDismissed
+	report.ReviewNote = req.Note
+	if err := s.review(report, officerID); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// open returns the report unless it was filed or dismissed already
+func (s *service) open(id uint) (*models.ComplianceReport, error) {
+	report, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if report.IsFinal() {
+		return nil, repositories.ErrComplianceReportClosed
+	}
+	return report, nil
+}
+
+// review closes the report as the officer with its new status and note
+func (s *service) review(report *models.ComplianceReport, officerID uint, fields ...string) error {
+	now := time.Now()
+	report.ReviewedBy = &officerID
+	report.ReviewedAt = &now
+	if report.AssignedTo == nil {
+		report.AssignedTo = &officerID
+	}
+	fields = append(fields, "Status", "ReviewNote", "ReviewedBy", "ReviewedAt", "AssignedTo")
+	return s.repo.Update(report, fields...)
+}
+
+func (s *service) Export(ctx context.Context, id uint) ([]byte, error) {
+	report, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"report_id", "type", "reason", "status", "filing_reference",
+		"subject_user_id", "subject_name", "subject_email", "subject_phone", "subject_country", "subject_kyc_status",
+		"total_amount", "currency", "transaction_count", "period_start", "period_end", "narrative",
+		"transaction_id", "transaction_type", "amount", "transaction_currency", "sender_id", "receiver_id", "occurred_at",
+	})
+	head := []string{
+		strconv.FormatUint(uint64(report.ID), 10), report.Type, report.Reason, report.Status, report.FilingReference,
+		strconv.FormatUint(uint64(report.SubjectUserID), 10), report.SubjectName, report.SubjectEmail,
+		report.SubjectPhone, report.SubjectCountry, report.SubjectKYCStatus,
+		strconv.FormatFloat(report.TotalAmount, 'f', 2, 64), report.Currency, strconv.Itoa(report.TransactionCount),
+		report.PeriodStart.UTC().Format(time.RFC3339), report.PeriodEnd.UTC().Format(time.RFC3339), report.Narrative,
+	}
+	for _, tx := range report.Transactions {
+		w.Write(append(slices.Clone(head),
+			strconv.FormatUint(uint64(tx.TransactionID), 10), tx.Type,
+			strconv.FormatFloat(tx.Amount, 'f', 2, 64), tx.Currency,
+			strconv.FormatUint(uint64(tx.SenderID), 10), strconv.FormatUint(uint64(tx.ReceiverID), 10),
+			tx.OccurredAt.UTC().Format(time.RFC3339),
+		))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write compliance report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,44 @@
+package compliance
+
+import "time"
+
+// lookback is how far back each monitoring run looks for large
+// transactions. Runs overlap, and transactions already reported are
+// skipped, so a missed run doesn't leave any unreported.
+const lookback = 7 * 24 * time.Hour
+
+// batchSize bounds the large transactions drafted per run
+const batchSize = 500
+
+// Config sets when transactions are drafted into reports
+type Config struct {
+	LargeTransactionThreshold float64
+	StructuringWindow         time.Duration
+	StructuringMinCount       int
+	StructuringMinTotal       float64
+	StructuringFloor          float64
+}
+
+// ListFilter narrows the reports listed; zero values match all
+type ListFilter struct {
+	Type          string
+	Status        string
+	SubjectUserID uint
+	AssignedTo    uint
+}
+
+// NarrativeRequest replaces a report's narrative
+type NarrativeRequest struct {
+	Narrative string `json:"narrative" validate:"required,max=10000"`
+}
+
+// FileRequest records that a report was filed with the regulator
+type FileRequest struct {
+	Reference string `json:"reference" validate:"required,max=100"`
+	Note      string `json:"note" validate:"max=1000"`
+}
+
+// DismissRequest closes a report without filing it
+type DismissRequest struct {
+	Note string `json:"note" validate:"required,max=1000"`
+}
@@ -0,0 +1,6 @@
+package confirmation
+
+import "errors"
+
+// Service errors
+var ErrInvalidChannel = errors.New("channel must be email or push")
@@ -0,0 +1,35 @@
+package confirmation
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// Stream is the feed of completed transactions and the cursor into it
+type Stream interface {
+	GetCursor(name string) (time.Time, error)
+	SaveCursor(name string, position time.Time) error
+	ListCompletedSince(after time.Time, afterID uint, limit int) ([]models.Transaction, error)
+}
+
+// Notifier delivers confirmations by email or push
+type Notifier interface {
+	SendPaymentConfirmation(ctx context.Context, userID uint, channel, role string, tx *models.Transaction, counterparty, receiptURL string) error
+	SendPaymentDigest(ctx context.Context, userID uint, channel string, transactions []*models.Transaction, historyURL string) error
+}
+
+// Service confirms completed payments to both payer and payee
+type Service interface {
+	// GetSettings returns how the user hears about their payments
+	GetSettings(ctx context.Context, userID uint) (*models.ConfirmationSettings, error)
+	UpdateSettings(ctx context.Context, userID uint, req SettingsRequest) (*models.ConfirmationSettings, error)
+
+	// QueueCompleted follows the completed transactions and queues a
+	// confirmation for each party who hasn't opted out, returning how
+	// many it queued
+	QueueCompleted(ctx context.Context) (int, error)
+	// SendPending sends the queued confirmations, as one digest for users
+	// with many waiting, and returns how many messages it sent
+	SendPending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,31 @@
+package confirmation
+
+import (
+	"context"
+	"log"
+)
+
+// Job queues and sends payment confirmations from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the confirmation service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "payment-confirmations" }
+
+func (j *Job) Run(ctx context.Context) error {
+	queued, err := j.service.QueueCompleted(ctx)
+	if queued > 0 {
+		log.Printf("Queued %d payment confirmations", queued)
+	}
+	if err != nil {
+		return err
+	}
+	sent, err := j.service.SendPending(ctx)
+	if sent > 0 {
+		log.Printf("Sent %d payment confirmation messages", sent)
+	}
+	return err
+}
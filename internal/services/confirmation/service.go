@@ -0,0 +1,272 @@
+package confirmation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+type service struct {
+	repo     repositories.PaymentConfirmationRepository
+	stream   Stream
+	users    repositories.UserRepository
+	notifier Notifier
+	config   Config
+}
+
+// NewService creates the payment confirmation service
+func NewService(repo repositories.PaymentConfirmationRepository, stream Stream, users repositories.UserRepository, notifier Notifier, config Config) Service {
+	return &service{repo: repo, stream: stream, users: users, notifier: notifier, config: config}
+}
+
+func (s *service) GetSettings(ctx context.Context, userID uint) (*models.ConfirmationSettings, error) {
+	return s.repo.GetSettings(userID)
+}
+
+func (s *service) UpdateSettings(ctx context.Context, userID uint, req SettingsRequest) (*models.ConfirmationSettings, error) {
+	settings, err := s.repo.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Channel != nil {
+		switch *req.Channel {
+		case models.ConfirmationChannelEmail, models.ConfirmationChannelPush:
+			settings.Channel = *req.Channel
+		default:
+			return nil, ErrInvalidChannel
+		}
+	}
+	if req.OptOut != nil {
+		settings.OptOut = *req.OptOut
+	}
+	if err := s.repo.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *service) QueueCompleted(ctx context.Context) (int, error) {
+	position, err := s.stream.GetCursor(cursorName)
+	if err != nil {
+		return 0, err
+	}
+	// Confirmations start from when the feature is first run rather than
+	// going back over every payment ever made
+	if position.IsZero() {
+		return 0, s.stream.SaveCursor(cursorName, time.Now())
+	}
+
+	after := position.Add(-streamOverlap)
+	var afterID uint
+	queued := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return queued, err
+		}
+		batch, err := s.stream.ListCompletedSince(after, afterID, streamBatch)
+		if err != nil {
+			return queued, err
+		}
+		confirmations, err := s.confirmationsFor(batch)
+		if err != nil {
+			return queued, err
+		}
+		added, err := s.repo.Queue(confirmations)
+		if err != nil {
+			return queued, err
+		}
+		queued += int(added)
+		if len(batch) > 0 {
+			last := batch[len(batch)-1]
+			after, afterID = last.UpdatedAt, last.ID
+		}
+
+		// Save progress per batch so a long catch-up resumes where it stopped
+		if after.After(position) {
+			if err := s.stream.SaveCursor(cursorName, after); err != nil {
+				return queued, err
+			}
+			position = after
+		}
+		if len(batch) < streamBatch {
+			return queued, nil
+		}
+	}
+}
+
+// confirmationsFor works out who should hear about the transactions: the
+// payer and the payee, unless they opted out. Transactions without two
+// distinct parties, such as top-ups, aren't confirmed.
+func (s *service) confirmationsFor(transactions []models.Transaction) ([]models.PaymentConfirmation, error) {
+	var userIDs []uint
+	for _, tx := range transactions {
+		if tx.SenderID != 0 && tx.ReceiverID != 0 && tx.SenderID != tx.ReceiverID {
+			userIDs = append(userIDs, tx.SenderID, tx.ReceiverID)
+		}
+	}
+	settings, err := s.repo.GetSettingsFor(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmations []models.PaymentConfirmation
+	add := func(tx *models.Transaction, userID uint, role string) {
+		channel := models.ConfirmationChannelEmail
+		if setting, ok := settings[userID]; ok {
+			if setting.OptOut {
+				return
+			}
+			channel = setting.Channel
+		}
+		confirmations = append(confirmations, models.PaymentConfirmation{
+			TransactionID: tx.ID,
+			UserID:        userID,
+			Role:          role,
+			Channel:       channel,
+			Status:        models.ConfirmationPending,
+		})
+	}
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.SenderID == 0 || tx.ReceiverID == 0 || tx.SenderID == tx.ReceiverID {
+			continue
+		}
+		add(tx, tx.SenderID, models.ConfirmationRolePayer)
+		add(tx, tx.ReceiverID, models.ConfirmationRolePayee)
+	}
+	return confirmations, nil
+}
+
+func (s *service) SendPending(ctx context.Context) (int, error) {
+	pending, err := s.repo.Pending(sendBatch)
+	if err != nil {
+		return 0, err
+	}
+	users, err := s.counterparties(pending)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	// Pending comes grouped by user
+	for start := 0; start < len(pending); {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		end := start
+		for end < len(pending) && pending[end].UserID == pending[start].UserID {
+			end++
+		}
+		group := pending[start:end]
+		start = end
+
+		if len(group) >= s.config.DigestThreshold {
+			ok, err := s.sendDigest(ctx, group)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+			continue
+		}
+		for i := range group {
+			ok, err := s.send(ctx, &group[i], users)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+	return sent, nil
+}
+
+// send delivers a single confirmation. A delivery failure leaves it
+// pending for the next run; only storage errors are returned.
+func (s *service) send(ctx context.Context, confirmation *models.PaymentConfirmation, users map[uint]*models.User) (bool, error) {
+	tx := confirmation.Transaction
+	if tx == nil {
+		// The transaction was archived before it could be confirmed
+		return false, s.repo.MarkSent([]uint{confirmation.ID}, models.ConfirmationSent, time.Now())
+	}
+	receiptURL := fmt.Sprintf("%s/api/transactions/%d/receipt", s.config.BaseURL, tx.ID)
+	err := s.notifier.SendPaymentConfirmation(ctx, confirmation.UserID, confirmation.Channel, confirmation.Role,
+		tx, counterparty(confirmation, users), receiptURL)
+	if err != nil {
+		log.Printf("Failed to confirm transaction %d to user %d: %v", tx.ID, confirmation.UserID, err)
+		return false, nil
+	}
+	return true, s.repo.MarkSent([]uint{confirmation.ID}, models.ConfirmationSent, time.Now())
+}
+
+// sendDigest rolls one user's waiting confirmations into one message
+func (s *service) sendDigest(ctx context.Context, group []models.PaymentConfirmation) (bool, error) {
+	ids := make([]uint, 0, len(group))
+	transactions := make([]*models.Transaction, 0, len(group))
+	for i := range group {
+		ids = append(ids, group[i].ID)
+		if group[i].Transaction != nil {
+			transactions = append(transactions, group[i].Transaction)
+		}
+	}
+	// The latest confirmation was queued with the user's current channel
+	last := group[len(group)-1]
+	historyURL := fmt.Sprintf("%s/api/transactions", s.config.BaseURL)
+	if err := s.notifier.SendPaymentDigest(ctx, last.UserID, last.Channel, transactions, historyURL); err != nil {
+		log.Printf("Failed to send payment digest to user %d: %v", last.UserID, err)
+		return false, nil
+	}
+	return true, s.repo.MarkSent(ids, models.ConfirmationDigested, time.Now())
+}
+
+// counterparties loads the users on the other side of the confirmations'
+// transactions, so messages can say who paid or was paid
+func (s *service) counterparties(pending []models.PaymentConfirmation) (map[uint]*models.User, error) {
+	var ids []uint
+	seen := make(map[uint]bool)
+	for i := range pending {
+		tx := pending[i].Transaction
+		if tx == nil {
+			continue
+		}
+		id := counterpartyID(&pending[i])
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	users, err := s.users.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	return byID, nil
+}
+
+func counterpartyID(confirmation *models.PaymentConfirmation) uint {
+	if confirmation.Role == models.ConfirmationRolePayer {
+		return confirmation.Transaction.ReceiverID
+	}
+	return confirmation.Transaction.SenderID
+}
+
+// counterparty names the other side of the payment: the merchant a payer
+// paid, or the other user
+func counterparty(confirmation *models.PaymentConfirmation, users map[uint]*models.User) string {
+	if confirmation.Role == models.ConfirmationRolePayer && confirmation.Transaction.MerchantName != "" {
+		return confirmation.Transaction.MerchantName
+	}
+	if user := users[counterpartyID(confirmation)]; user != nil {
+		return user.Name
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package confirmation
+
+import "time"
+
+// Config sets where receipts are linked and when confirmations are batched
+type Config struct {
+	// BaseURL is the public address receipt links point at
+	BaseURL string
+	// DigestThreshold is how many confirmations a user must have waiting
+	// for them to get one digest instead
+	DigestThreshold int
+}
+
+// SettingsRequest changes how a user hears about their payments; fields
+// left out are kept
+type SettingsRequest struct {
+	Channel *string `json:"channel"`
+	OptOut  *bool   `json:"opt_out"`
+}
+
+const (
+	// cursorName is the confirmations' position in the transaction stream
+	cursorName = "payment_confirmations"
+	// streamBatch is how many completed transactions are read at a time
+	streamBatch = 500
+	// streamOverlap re-reads the last stretch of the stream on every run,
+	// so payments that committed after a later one was read aren't
+	// missed. Ones already queued are skipped.
+	streamOverlap = 2 * time.Minute
+	// sendBatch bounds the confirmations sent per job run
+	sendBatch = 1000
+)
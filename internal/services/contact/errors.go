@@ -0,0 +1,15 @@
+package contact
+
+import "errors"
+
+// Service errors
+var (
+	ErrContactNotFound   = errors.New("contact not found")
+	ErrContactExists     = errors.New("contact already saved")
+	ErrRecipientNotFound = errors.New("no user found for that recipient")
+	ErrInvalidRecipient  = errors.New("give exactly one of phone, email, qr_code or user_id")
+	ErrSelfContact       = errors.New("you cannot add yourself as a contact")
+	ErrNicknameTooLong   = errors.New("nickname is too long")
+	ErrNotBeneficiary    = errors.New("add the recipient to your contacts before paying them for the first time")
+	ErrCoolingOff        = errors.New("new beneficiaries can't be paid until the cooling-off period ends")
+)
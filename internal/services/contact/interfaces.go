@@ -0,0 +1,21 @@
+package contact
+
+import "context"
+
+// Service manages a user's directory of saved P2P recipients and enforces
+// the cooling-off period before a new beneficiary can be paid
+type Service interface {
+	Add(ctx context.Context, userID uint, req AddRequest) (*ContactView, error)
+	List(ctx context.Context, userID uint, favoritesOnly bool, search string, limit, offset int) ([]ContactView, int64, error)
+	Get(ctx context.Context, userID, contactID uint) (*ContactView, error)
+	Update(ctx context.Context, userID, contactID uint, req UpdateRequest) (*ContactView, error)
+	SetFavorite(ctx context.Context, userID, contactID uint, favorite bool) (*ContactView, error)
+	Remove(ctx context.Context, userID, contactID uint) error
+
+	// CheckBeneficiary returns ErrCoolingOff or ErrNotBeneficiary when the
+	// sender may not pay the receiver yet. It always passes when cooling-off
+	// is disabled or the sender has paid the receiver before.
+	CheckBeneficiary(ctx context.Context, senderID, receiverID uint) error
+	// RecordPayment updates the sender's contact stats after a transfer
+	RecordPayment(ctx context.Context, senderID, receiverID uint)
+}
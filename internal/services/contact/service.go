@@ -0,0 +1,253 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxNicknameLength caps the nickname a user can give a contact
+const MaxNicknameLength = 50
+
+type service struct {
+	repo    repositories.ContactRepository
+	users   repositories.UserRepository
+	qrCodes repositories.QRCodeRepository
+	// coolingOff is how long a new beneficiary must wait before their
+	// first payment; zero turns the check off
+	coolingOff time.Duration
+}
+
+// NewService creates a new contacts service
+func NewService(repo repositories.ContactRepository, users repositories.UserRepository, qrCodes repositories.QRCodeRepository, coolingOff time.Duration) Service {
+	return &service{
+		repo:       repo,
+		users:      users,
+		qrCodes:    qrCodes,
+		coolingOff: coolingOff,
+	}
+}
+
+func (s *service) Add(ctx context.Context, userID uint, req AddRequest) (*ContactView, error) {
+	nickname := strings.TrimSpace(req.Nickname)
+	if len(nickname) > MaxNicknameLength {
+		return nil, ErrNicknameTooLong
+	}
+
+	recipient, via, err := s.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == userID {
+		return nil, ErrSelfContact
+	}
+
+	contact := &models.Contact{
+		UserID:        userID,
+		ContactUserID: recipient.ID,
+		Name:          recipient.Name,
+		Nickname:      nickname,
+		AddedVia:      via,
+		Favorite:      req.Favorite,
+	}
+	if err := s.repo.Create(contact); err != nil {
+		if errors.Is(err, repositories.ErrContactExists) {
+			return nil, ErrContactExists
+		}
+		return nil, err
+	}
+	return s.view(contact)
+}
+
+func (s *service) List(ctx context.Context, userID uint, favoritesOnly bool, search string, limit, offset int) ([]ContactView, int64, error) {
+	contacts, total, err := s.repo.List(userID, repositories.ContactFilter{
+		FavoritesOnly: favoritesOnly,
+		Search:        search,
+	}, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	views := make([]ContactView, len(contacts))
+	for i := range contacts {
+		view, err := s.view(&contacts[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		views[i] = *view
+	}
+	return views, total, nil
+}
+
+func (s *service) Get(ctx context.Context, userID, contactID uint) (*ContactView, error) {
+	contact, err := s.get(userID, contactID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(contact)
+}
+
+func (s *service) Update(ctx context.Context, userID, contactID uint, req UpdateRequest) (*ContactView, error) {
+	contact, err := s.get(userID, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Nickname != nil {
+		nickname := strings.TrimSpace(*req.Nickname)
+		if len(nickname) > MaxNicknameLength {
+			return nil, ErrNicknameTooLong
+		}
+		contact.Nickname = nickname
+	}
+	if req.Favorite != nil {
+		contact.Favorite = *req.Favorite
+	}
+
+	if err := s.repo.Update(contact); err != nil {
+		return nil, err
+	}
+	return s.view(contact)
+}
+
+func (s *service) SetFavorite(ctx context.Context, userID, contactID uint, favorite bool) (*ContactView, error) {
+	return s.Update(ctx, userID, contactID, UpdateRequest{Favorite: &favorite})
+}
+
+func (s *service) Remove(ctx context.Context, userID, contactID uint) error {
+	if err := s.repo.Delete(contactID, userID); err != nil {
+		if errors.Is(err, repositories.ErrContactNotFound) {
+			return ErrContactNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) CheckBeneficiary(ctx context.Context, senderID, receiverID uint) error {
+	if s.coolingOff <= 0 {
+		return nil
+	}
+
+	paid, err := s.repo.HasPaid(senderID, receiverID)
+	if err != nil {
+		return err
+	}
+	if paid {
+		return nil
+	}
+
+	contact, err := s.repo.GetByContactUserID(senderID, receiverID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrContactNotFound) {
+			return ErrNotBeneficiary
+		}
+		return err
+	}
+	if time.Now().Before(contact.CreatedAt.Add(s.coolingOff)) {
+		return ErrCoolingOff
+	}
+	return nil
+}
+
+func (s *service) RecordPayment(ctx context.Context, senderID, receiverID uint) {
+	if err := s.repo.RecordPayment(senderID, receiverID, time.Now()); err != nil {
+		log.Printf("Failed to update contact stats for user %d: %v", senderID, err)
+	}
+}
+
+// resolve finds the user a request points at and how they were identified
+func (s *service) resolve(req AddRequest) (*models.User, string, error) {
+	phone := strings.TrimSpace(req.Phone)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	code := strings.TrimSpace(req.QRCode)
+
+	given := 0
+	for _, set := range []bool{phone != "", email != "", code != "", req.UserID != 0} {
+		if set {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, "", ErrInvalidRecipient
+	}
+
+	var (
+		user *models.User
+		via  string
+		err  error
+	)
+	switch {
+	case phone != "":
+		user, err = s.users.GetByPhone(phone)
+		via = models.ContactViaPhone
+	case email != "":
+		user, err = s.users.GetByEmail(email)
+		via = models.ContactViaEmail
+	case code != "":
+		via = models.ContactViaQRCode
+		qr, qrErr := s.qrCodes.GetByCode(code)
+		if qrErr != nil {
+			if errors.Is(qrErr, gorm.ErrRecordNotFound) {
+				return nil, "", ErrRecipientNotFound
+			}
+			return nil, "", qrErr
+		}
+		// Payment codes identify a payer, not someone to send money to
+		if qr.Type == models.QRTypePayment || qr.Status != "active" {
+			return nil, "", ErrRecipientNotFound
+		}
+		user, err = s.users.GetByID(qr.UserID)
+	default:
+		user, err = s.users.GetByID(req.UserID)
+		via = models.ContactViaUserID
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, "", ErrRecipientNotFound
+		}
+		return nil, "", err
+	}
+	if user.Status != "active" {
+		return nil, "", ErrRecipientNotFound
+	}
+	return user, via, nil
+}
+
+func (s *service) get(userID, contactID uint) (*models.Contact, error) {
+	contact, err := s.repo.GetByID(contactID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrContactNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, err
+	}
+	return contact, nil
+}
+
+// view adds when a still-cooling-off contact becomes payable
+func (s *service) view(contact *models.Contact) (*ContactView, error) {
+	view := &ContactView{Contact: contact}
+	if s.coolingOff <= 0 || contact.PaymentCount > 0 {
+		return view, nil
+	}
+
+	payableFrom := contact.CreatedAt.Add(s.coolingOff)
+	if !time.Now().Before(payableFrom) {
+		return view, nil
+	}
+	paid, err := s.repo.HasPaid(contact.UserID, contact.ContactUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !paid {
+		view.PayableFrom = &payableFrom
+	}
+	return view, nil
+}
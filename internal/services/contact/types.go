@@ -0,0 +1,30 @@
+package contact
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// AddRequest saves a recipient found by exactly one of phone, email, the
+// code of one of their receiving QR codes, or user ID
+type AddRequest struct {
+	Phone    string `json:"phone"`
+	Email    string `json:"email"`
+	QRCode   string `json:"qr_code"`
+	UserID   uint   `json:"user_id"`
+	Nickname string `json:"nickname"`
+	Favorite bool   `json:"favorite"`
+}
+
+// UpdateRequest changes a contact's nickname or favorite flag
+type UpdateRequest struct {
+	Nickname *string `json:"nickname"`
+	Favorite *bool   `json:"favorite"`
+}
+
+// ContactView is a contact with when it can first be paid
+type ContactView struct {
+	*models.Contact
+	// PayableFrom is set while a new beneficiary is still cooling off
+	PayableFrom *time.Time `json:"payable_from,omitempty"`
+}
@@ -0,0 +1,18 @@
+package creditcard
+
+import "errors"
+
+// Service errors
+var (
+	ErrCardNotOwned           = errors.New("card does not belong to user")
+	ErrCardNotActive          = errors.New("card is not active")
+	ErrCardExpired            = errors.New("card has expired")
+	ErrCardNotVerified        = errors.New("card must be verified before it can fund the wallet")
+	ErrAlreadyVerified        = errors.New("card is already verified")
+	ErrVerificationNotStarted = errors.New("card verification has not been started")
+	ErrVerificationFailed     = errors.New("verification amount does not match")
+	ErrTooManyAttempts        = errors.New("too many verification attempts")
+	ErrInvalidExpiry          = errors.New("invalid expiry date")
+	ErrInvalidBillingCountry  = errors.New("billing country must be a two-letter code")
+	ErrChargeDeclined         = errors.New("card was declined")
+)
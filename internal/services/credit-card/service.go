@@ -1,31 +1,43 @@
 package creditcard
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"log"
+	"math"
 	"orus/internal/models"
 	"orus/internal/repositories"
+	"orus/internal/services/vault"
+	"strings"
+	"time"
 )
 
+// MaxVerificationAttempts bounds how many amounts a user may report for
+// one verification charge
+const MaxVerificationAttempts = 3
+
 type serviceImpl struct {
-	tokenizer Tokenizer
 	repo      repositories.CreditCardRepository
+	vault     vault.Service
+	processor Processor
+	pins      PINService
 }
 
-func NewService(repo repositories.CreditCardRepository) Service {
+func NewService(repo repositories.CreditCardRepository, vault vault.Service, processor Processor, pins PINService) Service {
 	return &serviceImpl{
-		tokenizer: NewTokenizer(),
 		repo:      repo,
+		vault:     vault,
+		processor: processor,
+		pins:      pins,
 	}
 }
 
-func (s *serviceImpl) LinkCard(userID uint, input CreateCardInput) (*models.CreditCard, error) {
+func (s *serviceImpl) LinkCard(ctx context.Context, userID uint, input CreateCardInput) (*models.CreditCard, error) {
 	if err := s.validateCardInput(input); err != nil {
 		return nil, err
 	}
 
-	tokenizedCard, err := s.tokenizer.TokenizeCard(input)
+	vaulted, err := s.vault.Store(ctx, userID, input.CardNumber)
 	if err != nil {
 		log.Println("Tokenization failed:", err)
 		return nil, fmt.Errorf("card tokenization failed: %w", err)
@@ -33,14 +45,16 @@ func (s *serviceImpl) LinkCard(userID uint, input CreateCardInput) (*models.Cred
 
 	cardRecord := &models.CreditCard{
 		UserID:      userID,
-		CardNumber:  tokenizedCard.Token,
-		CardType:    tokenizedCard.CardType,
+		VaultRef:    vaulted.Reference,
+		CardType:    vaulted.Brand,
 		ExpiryMonth: input.ExpiryMonth,
 		ExpiryYear:  input.ExpiryYear,
+		LastFour:    vaulted.LastFour,
 		Status:      "active",
 	}
 
-	if err := repositories.CreateCreditCard(cardRecord); err != nil {
+	if err := s.repo.Create(cardRecord); err != nil {
+		s.forget(ctx, vaulted.Reference, "link_failed")
 		return nil, fmt.Errorf("failed to save card: %w", err)
 	}
 
@@ -48,24 +62,49 @@ func (s *serviceImpl) LinkCard(userID uint, input CreateCardInput) (*models.Cred
 }
 
 func (s *serviceImpl) GetUserCards(userID uint) ([]models.CreditCard, error) {
-	return repositories.GetCreditCardsByUserID(userID)
+	cards, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.CreditCard, len(cards))
+	for i, card := range cards {
+		result[i] = *card
+	}
+	return result, nil
 }
 
-func (s *serviceImpl) DeleteCard(userID uint, cardID uint) error {
-	card, err := repositories.GetCreditCardByID(cardID)
+func (s *serviceImpl) DeleteCard(ctx context.Context, userID uint, cardID uint) error {
+	card, err := s.repo.GetByID(cardID)
 	if err != nil {
 		return err
 	}
 
 	if card.UserID != userID {
-		return errors.New("card does not belong to user")
+		return ErrCardNotOwned
+	}
+	if err := s.pins.Require(ctx, userID); err != nil {
+		return err
 	}
 
-	return repositories.DeleteCreditCard(cardID)
+	if err := s.repo.Delete(cardID); err != nil {
+		return err
+	}
+	s.forget(ctx, card.VaultRef, "card_deleted")
+	return nil
+}
+
+// forget removes a token no card refers to any more from the vault
+func (s *serviceImpl) forget(ctx context.Context, ref, purpose string) {
+	if ref == "" {
+		return
+	}
+	if err := s.vault.Delete(context.WithoutCancel(ctx), ref, purpose); err != nil {
+		log.Printf("Failed to delete vaulted card %s: %v", ref, err)
+	}
 }
 
 func (s *serviceImpl) GetByID(cardID uint) (*models.CreditCard, error) {
-	return repositories.GetCreditCardByID(cardID)
+	return s.repo.GetByID(cardID)
 }
 
 func (s *serviceImpl) GetByIDAndUserID(cardID uint, userID uint) (*models.CreditCard, error) {
@@ -75,3 +114,183 @@ func (s *serviceImpl) GetByIDAndUserID(cardID uint, userID uint) (*models.Credit
 	}
 	return card, nil
 }
+
+func (s *serviceImpl) SetDefault(userID, cardID uint) (*models.CreditCard, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Status != "active" {
+		return nil, ErrCardNotActive
+	}
+	if card.IsExpired(time.Now()) {
+		return nil, ErrCardExpired
+	}
+
+	if err := s.repo.SetDefault(card.ID, true); err != nil {
+		return nil, fmt.Errorf("failed to set default card: %w", err)
+	}
+	card.IsDefault = true
+	return card, nil
+}
+
+// UpdateCard changes the card's expiry, say once the issuer renews it, and
+// its billing details. The card stays verified: the number is the same.
+func (s *serviceImpl) UpdateCard(userID, cardID uint, input UpdateCardInput) (*models.CreditCard, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ExpiryMonth != nil || input.ExpiryYear != nil {
+		month, year := card.ExpiryMonth, card.ExpiryYear
+		if input.ExpiryMonth != nil {
+			month = strings.TrimSpace(*input.ExpiryMonth)
+		}
+		if input.ExpiryYear != nil {
+			year = strings.TrimSpace(*input.ExpiryYear)
+		}
+		if err := validateExpiry(month, year); err != nil {
+			return nil, err
+		}
+		card.ExpiryMonth, card.ExpiryYear = month, year
+	}
+
+	if input.BillingName != nil {
+		card.BillingName = strings.TrimSpace(*input.BillingName)
+	}
+	if input.BillingAddress != nil {
+		card.BillingAddress = strings.TrimSpace(*input.BillingAddress)
+	}
+	if input.BillingPostalCode != nil {
+		card.BillingPostalCode = strings.TrimSpace(*input.BillingPostalCode)
+	}
+	if input.BillingCountry != nil {
+		country := strings.ToUpper(strings.TrimSpace(*input.BillingCountry))
+		if err := validateBillingCountry(country); err != nil {
+			return nil, err
+		}
+		card.BillingCountry = country
+	}
+
+	if err := s.repo.Update(card); err != nil {
+		return nil, fmt.Errorf("failed to update card: %w", err)
+	}
+	return card, nil
+}
+
+// StartVerification charges the card a small amount for the user to report
+// back. Asking again while a charge is outstanding returns the card as is.
+func (s *serviceImpl) StartVerification(ctx context.Context, userID, cardID uint) (*models.CreditCard, error) {
+	card, err := s.verifiableCard(userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.VerificationChargeID != "" {
+		return card, nil
+	}
+	if card.IsExpired(time.Now()) {
+		return nil, ErrCardExpired
+	}
+
+	token, err := s.vault.Token(ctx, card.VaultRef, "card_verification")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read card from the vault: %w", err)
+	}
+	charge, err := s.processor.ChargeVerification(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge card for verification: %w", err)
+	}
+
+	card.VerificationChargeID = charge.ID
+	card.VerificationAmount = charge.Amount
+	if err := s.repo.Update(card); err != nil {
+		// The charge can't be matched to the card any more, so give it back
+		s.refundVerification(ctx, card.ID, charge.ID)
+		return nil, fmt.Errorf("failed to save card verification: %w", err)
+	}
+	return card, nil
+}
+
+// ConfirmVerification verifies the card if amount is what was charged. The
+// charge is refunded once the card is verified or the attempts run out.
+func (s *serviceImpl) ConfirmVerification(ctx context.Context, userID, cardID uint, amount float64) (*models.CreditCard, error) {
+	card, err := s.verifiableCard(userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.VerificationChargeID == "" {
+		return nil, ErrVerificationNotStarted
+	}
+
+	card.VerificationAttempts++
+	ok := math.Round(amount*100) == math.Round(card.VerificationAmount*100)
+	chargeID := card.VerificationChargeID
+	if ok {
+		now := time.Now()
+		card.VerifiedAt = &now
+	} else if card.VerificationAttempts >= MaxVerificationAttempts {
+		card.Status = models.CreditCardStatusVerificationFailed
+	}
+	settled := ok || card.Status == models.CreditCardStatusVerificationFailed
+	if settled {
+		card.VerificationChargeID = ""
+		card.VerificationAmount = 0
+	}
+
+	if err := s.repo.Update(card); err != nil {
+		return nil, fmt.Errorf("failed to update card verification: %w", err)
+	}
+	if settled {
+		s.refundVerification(ctx, card.ID, chargeID)
+	}
+
+	if !ok {
+		if card.Status == models.CreditCardStatusVerificationFailed {
+			return nil, ErrTooManyAttempts
+		}
+		return nil, ErrVerificationFailed
+	}
+	return card, nil
+}
+
+func (s *serviceImpl) verifiableCard(userID, cardID uint) (*models.CreditCard, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case card.IsVerified():
+		return nil, ErrAlreadyVerified
+	case card.Status == models.CreditCardStatusVerificationFailed:
+		return nil, ErrTooManyAttempts
+	case card.Status != "active":
+		return nil, ErrCardNotActive
+	}
+	return card, nil
+}
+
+func (s *serviceImpl) ChargeTopUp(ctx context.Context, card *models.CreditCard, amount float64, reference string, authenticate bool) (*Charge, error) {
+	token, err := s.vault.Token(ctx, card.VaultRef, "top_up")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read card from the vault: %w", err)
+	}
+	charge, err := s.processor.ChargeTopUp(ctx, token, amount, reference, authenticate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge card: %w", err)
+	}
+	if charge.Status == ChargeFailed {
+		return nil, ErrChargeDeclined
+	}
+	return charge, nil
+}
+
+func (s *serviceImpl) RefundCharge(ctx context.Context, chargeID string) error {
+	return s.processor.Refund(ctx, chargeID)
+}
+
+func (s *serviceImpl) refundVerification(ctx context.Context, cardID uint, chargeID string) {
+	if err := s.processor.Refund(context.WithoutCancel(ctx), chargeID); err != nil {
+		log.Printf("CRITICAL: failed to refund verification charge %s on card %d: %v", chargeID, cardID, err)
+	}
+}
@@ -1,6 +1,7 @@
 package creditcard
 
 import (
+	"context"
 	"orus/internal/models"
 )
 
@@ -11,19 +12,42 @@ type CreateCardInput struct {
 	ExpiryYear  string `json:"expiry_year"`
 }
 
-// TokenizedCard represents a tokenized credit card
-type TokenizedCard struct {
-	Token    string
-	CardType string
-	LastFour string
-	IssuedBy string
+// UpdateCardInput changes a card's expiry and billing details. Fields left
+// nil keep their value.
+type UpdateCardInput struct {
+	ExpiryMonth       *string `json:"expiry_month"`
+	ExpiryYear        *string `json:"expiry_year"`
+	BillingName       *string `json:"billing_name" validate:"omitempty,max=100"`
+	BillingAddress    *string `json:"billing_address" validate:"omitempty,max=255"`
+	BillingPostalCode *string `json:"billing_postal_code" validate:"omitempty,max=20"`
+	BillingCountry    *string `json:"billing_country"`
+}
+
+// PINService checks the user's payment PIN before a card is removed
+type PINService interface {
+	Require(ctx context.Context, userID uint) error
 }
 
 // Service defines the interface for credit card operations
 type Service interface {
-	LinkCard(userID uint, input CreateCardInput) (*models.CreditCard, error)
+	LinkCard(ctx context.Context, userID uint, input CreateCardInput) (*models.CreditCard, error)
 	GetUserCards(userID uint) ([]models.CreditCard, error)
-	DeleteCard(userID uint, cardID uint) error
+	DeleteCard(ctx context.Context, userID uint, cardID uint) error
 	GetByID(cardID uint) (*models.CreditCard, error)
 	GetByIDAndUserID(cardID uint, userID uint) (*models.CreditCard, error)
+
+	// SetDefault makes the card the one used when none is picked
+	SetDefault(userID, cardID uint) (*models.CreditCard, error)
+	UpdateCard(userID, cardID uint, input UpdateCardInput) (*models.CreditCard, error)
+
+	// StartVerification makes a small refundable charge on the card;
+	// ConfirmVerification verifies the card once the user reports it
+	StartVerification(ctx context.Context, userID, cardID uint) (*models.CreditCard, error)
+	ConfirmVerification(ctx context.Context, userID, cardID uint, amount float64) (*models.CreditCard, error)
+
+	// ChargeTopUp charges a wallet top-up to the card, asking for 3-D
+	// Secure when authenticate is set. A declined charge is ErrChargeDeclined.
+	ChargeTopUp(ctx context.Context, card *models.CreditCard, amount float64, reference string, authenticate bool) (*Charge, error)
+	// RefundCharge returns a charge the wallet couldn't be credited for
+	RefundCharge(ctx context.Context, chargeID string) error
 }
@@ -3,31 +3,47 @@ package creditcard
 import (
 	"errors"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 func (s *serviceImpl) validateCardInput(card CreateCardInput) error {
 	if card.CardNumber == "" {
 		return errors.New("card number is required")
 	}
-	if card.ExpiryMonth == "" || card.ExpiryYear == "" {
+	return validateExpiry(card.ExpiryMonth, card.ExpiryYear)
+}
+
+func validateExpiry(expiryMonth, expiryYear string) error {
+	if expiryMonth == "" || expiryYear == "" {
 		return errors.New("expiry date is required")
 	}
 
-	month, err := strconv.Atoi(card.ExpiryMonth)
+	month, err := strconv.Atoi(expiryMonth)
 	if err != nil || month < 1 || month > 12 {
-		return errors.New("invalid expiry month")
+		return ErrInvalidExpiry
 	}
 
-	year, err := strconv.Atoi(card.ExpiryYear)
+	year, err := strconv.Atoi(expiryYear)
 	if err != nil {
-		return errors.New("invalid expiry year")
+		return ErrInvalidExpiry
 	}
 
 	now := time.Now()
 	if year < now.Year() || (year == now.Year() && month < int(now.Month())) {
-		return errors.New("card has expired")
+		return ErrCardExpired
 	}
 
 	return nil
 }
+
+func validateBillingCountry(country string) error {
+	if country == "" {
+		return nil
+	}
+	if len(country) != 2 || strings.IndexFunc(country, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+		return ErrInvalidBillingCountry
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package dashboard
+
+import (
+	"context"
+	"math"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+// Analytics granularities
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week" // Weeks start on Monday
+	GranularityMonth = "month"
+)
+
+// Analytics metrics
+const (
+	MetricVolume          = "volume"
+	MetricCount           = "count"
+	MetricAverage         = "average"
+	MetricUniqueCustomers = "unique_customers"
+)
+
+const (
+	// MaxAnalyticsPeriods caps how many periods one report can hold
+	MaxAnalyticsPeriods = 366
+
+	dateLayout = "2006-01-02"
+)
+
+// AllMetrics are reported when a query doesn't pick any
+var AllMetrics = []string{MetricVolume, MetricCount, MetricAverage, MetricUniqueCustomers}
+
+// AnalyticsQuery selects what an analytics report covers. Zero values take
+// the defaults.
+type AnalyticsQuery struct {
+	StartDate   string   // YYYY-MM-DD; defaults to a month before EndDate
+	EndDate     string   // YYYY-MM-DD, inclusive; defaults to today
+	Granularity string   // Defaults to day
+	Metrics     []string // Defaults to AllMetrics
+	Timezone    string   // IANA name the dates and periods are in; defaults to UTC
+}
+
+// AnalyticsReport is a time series of a user's completed transactions.
+// Merchants get the payments they received; everyone else gets both
+// directions. Every period in the range is listed, empty ones with zero
+// values, and only the requested metrics are set.
+type AnalyticsReport struct {
+	Scope       string           `json:"scope"` // "merchant" or "user"
+	Granularity string           `json:"granularity"`
+	Timezone    string           `json:"timezone"`
+	StartDate   string           `json:"start_date"`
+	EndDate     string           `json:"end_date"`
+	Metrics     []string         `json:"metrics"`
+	Series      []AnalyticsPoint `json:"series"`
+	Totals      AnalyticsValues  `json:"totals"`
+	BreakdownBy string           `json:"breakdown_by"` // "payment_method" or "type"
+	Breakdown   map[string]int64 `json:"breakdown"`    // Transaction count per key
+	ByQRCode    []QRCodeTotals   `json:"by_qr_code,omitempty"`
+}
+
+// QRCodeTotals is what a merchant took in through one QR code, such as a
+// poster at a till or location. Merchant reports list every code that took
+// payments in the range.
+type QRCodeTotals struct {
+	QRCodeID   uint    `json:"qr_code_id"`
+	Type       string  `json:"type"`
+	Label      string  `json:"label,omitempty"`
+	Location   string  `json:"location,omitempty"`
+	TerminalID *uint   `json:"terminal_id,omitempty"`
+	Count      int64   `json:"count"`
+	Volume     float64 `json:"volume"`
+}
+
+// AnalyticsPoint holds the metrics of one period
+type AnalyticsPoint struct {
+	Period string `json:"period"` // First day of the period
+	AnalyticsValues
+}
+
+// AnalyticsValues holds the requested metrics. For users who aren't
+// merchants, unique customers counts everyone they sent to or received from.
+type AnalyticsValues struct {
+	Volume          *float64 `json:"volume,omitempty"`
+	Count           *int64   `json:"count,omitempty"`
+	Average         *float64 `json:"average,omitempty"`
+	UniqueCustomers *int64   `json:"unique_customers,omitempty"`
+}
+
+func (s *service) GetTransactionAnalytics(ctx context.Context, userID uint, query AnalyticsQuery) (*AnalyticsReport, error) {
+	granularity := strings.ToLower(strings.TrimSpace(query.Granularity))
+	if granularity == "" {
+		granularity = GranularityDay
+	}
+	if granularity != GranularityDay && granularity != GranularityWeek && granularity != GranularityMonth {
+		return nil, ErrInvalidGranularity
+	}
+	metrics, err := parseMetrics(query.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezone(query.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseRange(query.StartDate, query.EndDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lay out the periods first so an oversized range never hits the database
+	var periods []time.Time
+	for p := periodStart(start, granularity); !p.After(end); p = nextPeriod(p, granularity) {
+		if len(periods) == MaxAnalyticsPeriods {
+			return nil, ErrRangeTooLong
+		}
+		periods = append(periods, p)
+	}
+
+	_, err = s.merchantRepo.GetByUserID(userID)
+	merchant := err == nil
+	filter := repositories.AnalyticsFilter{
+		UserID:      userID,
+		Merchant:    merchant,
+		From:        localMidnight(start, loc),
+		To:          localMidnight(end.AddDate(0, 0, 1), loc),
+		Granularity: granularity,
+		Timezone:    loc.String(),
+	}
+
+	buckets, err := s.analytics.Series(filter)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.analytics.Totals(filter)
+	if err != nil {
+		return nil, err
+	}
+	breakdown, err := s.analytics.CountBy(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	byPeriod := make(map[string]repositories.AnalyticsBucket, len(buckets))
+	for _, b := range buckets {
+		byPeriod[b.Period.Format(dateLayout)] = b
+	}
+	series := make([]AnalyticsPoint, 0, len(periods))
+	for _, p := range periods {
+		key := p.Format(dateLayout)
+		series = append(series, AnalyticsPoint{Period: key, AnalyticsValues: values(byPeriod[key], metrics)})
+	}
+
+	report := &AnalyticsReport{
+		Scope:       "user",
+		Granularity: granularity,
+		Timezone:    loc.String(),
+		StartDate:   start.Format(dateLayout),
+		EndDate:     end.Format(dateLayout),
+		Metrics:     metrics,
+		Series:      series,
+		Totals:      values(*totals, metrics),
+		BreakdownBy: "type",
+		Breakdown:   breakdown,
+	}
+	if merchant {
+		report.Scope = "merchant"
+		report.BreakdownBy = "payment_method"
+		byQR, err := s.analytics.ByQRCode(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range byQR {
+			report.ByQRCode = append(report.ByQRCode, QRCodeTotals{
+				QRCodeID:   b.QRCodeID,
+				Type:       b.Type,
+				Label:      b.Label,
+				Location:   b.Location,
+				TerminalID: b.TerminalID,
+				Count:      b.Count,
+				Volume:     math.Round(b.Volume*100) / 100,
+			})
+		}
+	}
+	return report, nil
+}
+
+func parseMetrics(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return AllMetrics, nil
+	}
+	metrics := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, m := range requested {
+		m = strings.ToLower(strings.TrimSpace(m))
+		switch m {
+		case "":
+			continue
+		case MetricVolume, MetricCount, MetricAverage, MetricUniqueCustomers:
+		default:
+			return nil, ErrInvalidMetric
+		}
+		if !seen[m] {
+			seen[m] = true
+			metrics = append(metrics, m)
+		}
+	}
+	if len(metrics) == 0 {
+		return AllMetrics, nil
+	}
+	return metrics, nil
+}
+
+func parseTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	// "Local" means the server's zone, which the database doesn't know
+	if name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// parseRange returns the first and last day of the range as calendar
+// dates, at midnight UTC. Today is taken in loc; localMidnight turns the
+// dates into instants there.
+func parseRange(startDate, endDate string, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if endDate != "" {
+		parsed, err := time.Parse(dateLayout, endDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDate
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, -1, 0)
+	if startDate != "" {
+		parsed, err := time.Parse(dateLayout, startDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDate
+		}
+		start = parsed
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return start, end, nil
+}
+
+// localMidnight returns when day starts in loc. Where clocks go forward at
+// midnight the day has no 00:00, and starts when they change.
+func localMidnight(day time.Time, loc *time.Location) time.Time {
+	t := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	if t.Day() != day.Day() {
+		_, t = t.ZoneBounds()
+	}
+	return t
+}
+
+// periodStart returns the start of the period day falls in, matching
+// Postgres date_trunc
+func periodStart(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+func nextPeriod(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// values picks the requested metrics out of a bucket
+func values(b repositories.AnalyticsBucket, metrics []string) AnalyticsValues {
+	var v AnalyticsValues
+	for _, m := range metrics {
+		switch m {
+		case MetricVolume:
+			volume := math.Round(b.Volume*100) / 100
+			v.Volume = &volume
+		case MetricCount:
+			count := b.Count
+			v.Count = &count
+		case MetricAverage:
+			avg := average(b.Volume, b.Count)
+			v.Average = &avg
+		case MetricUniqueCustomers:
+			customers := b.Counterparties
+			v.UniqueCustomers = &customers
+		}
+	}
+	return v
+}
@@ -0,0 +1,387 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type fakeAnalytics struct {
+	repositories.AnalyticsRepository
+	series  []repositories.AnalyticsBucket
+	totals  repositories.AnalyticsBucket
+	counts  map[string]int64
+	byQR    []repositories.QRCodeBucket
+	filters []repositories.AnalyticsFilter
+}
+
+func (f *fakeAnalytics) Series(filter repositories.AnalyticsFilter) ([]repositories.AnalyticsBucket, error) {
+	f.filters = append(f.filters, filter)
+	return f.series, nil
+}
+
+func (f *fakeAnalytics) Totals(filter repositories.AnalyticsFilter) (*repositories.AnalyticsBucket, error) {
+	totals := f.totals
+	return &totals, nil
+}
+
+func (f *fakeAnalytics) CountBy(filter repositories.AnalyticsFilter) (map[string]int64, error) {
+	return f.counts, nil
+}
+
+func (f *fakeAnalytics) ByQRCode(filter repositories.AnalyticsFilter) ([]repositories.QRCodeBucket, error) {
+	return f.byQR, nil
+}
+
+type fakeMerchants struct {
+	repositories.MerchantRepository
+	merchant bool
+	plan     string
+	rate     float64
+}
+
+func (f *fakeMerchants) GetByUserID(userID uint) (*models.Merchant, error) {
+	if !f.merchant {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.Merchant{UserID: userID, FeePlan: f.plan, ProcessingFeeRate: f.rate}, nil
+}
+
+func newAnalyticsService(analytics *fakeAnalytics, merchant bool) Service {
+	return NewService(nil, analytics, nil, nil, &fakeMerchants{merchant: merchant}, nil)
+}
+
+// day is a calendar date as Postgres returns date_trunc of a local time
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// keys returns the sorted keys of a decoded JSON object
+func keys(t *testing.T, v interface{}) []string {
+	t.Helper()
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		t.Fatalf("%v is not a JSON object", v)
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestAnalyticsResponseShape(t *testing.T) {
+	granularities := []struct {
+		name      string
+		periods   int
+		first     string
+		last      string
+		hitPeriod string
+	}{
+		// 2024-01-01 is a Monday, so weeks line up with the range
+		{GranularityDay, 91, "2024-01-01", "2024-03-31", "2024-02-14"},
+		{GranularityWeek, 13, "2024-01-01", "2024-03-25", "2024-02-12"},
+		{GranularityMonth, 3, "2024-01-01", "2024-03-01", "2024-02-01"},
+	}
+	metricSets := [][]string{
+		{MetricVolume},
+		{MetricCount},
+		{MetricAverage},
+		{MetricUniqueCustomers},
+		{MetricCount, MetricVolume},
+		nil, // All of them
+	}
+
+	for _, g := range granularities {
+		for _, metrics := range metricSets {
+			for _, merchant := range []bool{false, true} {
+				want := metrics
+				if want == nil {
+					want = AllMetrics
+				}
+				scope := "user"
+				if merchant {
+					scope = "merchant"
+				}
+				name := g.name + "/" + strings.Join(want, "+") + "/" + scope
+
+				t.Run(name, func(t *testing.T) {
+					hit, _ := time.Parse(dateLayout, g.hitPeriod)
+					analytics := &fakeAnalytics{
+						series: []repositories.AnalyticsBucket{{Period: hit, Count: 4, Volume: 100.5, Counterparties: 3}},
+						totals: repositories.AnalyticsBucket{Count: 4, Volume: 100.5, Counterparties: 3},
+						counts: map[string]int64{"wallet": 4},
+						byQR:   []repositories.QRCodeBucket{{QRCodeID: 9, Type: "poster", Label: "Till 1", Count: 4, Volume: 100.5}},
+					}
+					report, err := newAnalyticsService(analytics, merchant).GetTransactionAnalytics(context.Background(), 1, AnalyticsQuery{
+						StartDate:   "2024-01-01",
+						EndDate:     "2024-03-31",
+						Granularity: strings.ToUpper(g.name),
+						Metrics:     metrics,
+					})
+					if err != nil {
+						t.Fatalf("GetTransactionAnalytics() error = %v", err)
+					}
+
+					raw, err := json.Marshal(report)
+					if err != nil {
+						t.Fatalf("failed to marshal report: %v", err)
+					}
+					var decoded map[string]interface{}
+					if err := json.Unmarshal(raw, &decoded); err != nil {
+						t.Fatalf("failed to unmarshal report: %v", err)
+					}
+
+					wantTop := []string{"breakdown", "breakdown_by", "end_date", "granularity", "metrics", "scope", "series", "start_date", "timezone", "totals"}
+					wantBreakdownBy := "type"
+					if merchant {
+						wantTop = append(wantTop, "by_qr_code")
+						wantBreakdownBy = "payment_method"
+					}
+					sort.Strings(wantTop)
+					if got := keys(t, decoded); strings.Join(got, ",") != strings.Join(wantTop, ",") {
+						t.Errorf("report keys = %v, want %v", got, wantTop)
+					}
+					if decoded["scope"] != scope || decoded["breakdown_by"] != wantBreakdownBy {
+						t.Errorf("scope %v broken down by %v, want %s by %s", decoded["scope"], decoded["breakdown_by"], scope, wantBreakdownBy)
+					}
+					if decoded["granularity"] != g.name || decoded["timezone"] != "UTC" {
+						t.Errorf("granularity %v in %v, want %s in UTC", decoded["granularity"], decoded["timezone"], g.name)
+					}
+					if decoded["start_date"] != "2024-01-01" || decoded["end_date"] != "2024-03-31" {
+						t.Errorf("range = %v to %v, want 2024-01-01 to 2024-03-31", decoded["start_date"], decoded["end_date"])
+					}
+
+					wantValues := append([]string(nil), want...)
+					sort.Strings(wantValues)
+					if got := keys(t, decoded["totals"]); strings.Join(got, ",") != strings.Join(wantValues, ",") {
+						t.Errorf("totals keys = %v, want %v", got, wantValues)
+					}
+
+					series := decoded["series"].([]interface{})
+					if len(series) != g.periods {
+						t.Fatalf("%d periods, want %d", len(series), g.periods)
+					}
+					wantPoint := append([]string{"period"}, wantValues...)
+					sort.Strings(wantPoint)
+					for i, point := range series {
+						if got := keys(t, point); strings.Join(got, ",") != strings.Join(wantPoint, ",") {
+							t.Fatalf("series[%d] keys = %v, want %v", i, got, wantPoint)
+						}
+					}
+					if first := series[0].(map[string]interface{})["period"]; first != g.first {
+						t.Errorf("first period = %v, want %s", first, g.first)
+					}
+					if last := series[len(series)-1].(map[string]interface{})["period"]; last != g.last {
+						t.Errorf("last period = %v, want %s", last, g.last)
+					}
+
+					// Periods without transactions are listed with zero values
+					for _, p := range report.Series {
+						wantCount, wantVolume, wantAverage, wantCustomers := int64(0), 0.0, 0.0, int64(0)
+						if p.Period == g.hitPeriod {
+							wantCount, wantVolume, wantAverage, wantCustomers = 4, 100.5, 25.13, 3
+						}
+						if p.Count != nil && *p.Count != wantCount {
+							t.Errorf("%s count = %d, want %d", p.Period, *p.Count, wantCount)
+						}
+						if p.Volume != nil && *p.Volume != wantVolume {
+							t.Errorf("%s volume = %v, want %v", p.Period, *p.Volume, wantVolume)
+						}
+						if p.Average != nil && *p.Average != wantAverage {
+							t.Errorf("%s average = %v, want %v", p.Period, *p.Average, wantAverage)
+						}
+						if p.UniqueCustomers != nil && *p.UniqueCustomers != wantCustomers {
+							t.Errorf("%s unique customers = %d, want %d", p.Period, *p.UniqueCustomers, wantCustomers)
+						}
+					}
+
+					filter := analytics.filters[0]
+					if filter.Merchant != merchant || filter.Granularity != g.name || filter.Timezone != "UTC" {
+						t.Errorf("filter = %+v, want merchant %v by %s in UTC", filter, merchant, g.name)
+					}
+					if !filter.From.Equal(day(2024, 1, 1)) || !filter.To.Equal(day(2024, 4, 1)) {
+						t.Errorf("filter covers %v to %v, want 2024-01-01 to 2024-04-01", filter.From, filter.To)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestAnalyticsDSTBoundaries(t *testing.T) {
+	tests := []struct {
+		name        string
+		timezone    string
+		start, end  string
+		granularity string
+		// Buckets as Postgres labels them, by local calendar date
+		buckets     []time.Time
+		wantPeriods []string
+		wantCounts  []int64
+		// The range as local midnights, in UTC
+		wantFrom, wantTo string
+	}{
+		{
+			name:        "clocks go forward at 02:00",
+			timezone:    "America/New_York",
+			start:       "2024-03-09",
+			end:         "2024-03-11",
+			granularity: GranularityDay,
+			buckets:     []time.Time{day(2024, 3, 10)},
+			wantPeriods: []string{"2024-03-09", "2024-03-10", "2024-03-11"},
+			wantCounts:  []int64{0, 1, 0},
+			wantFrom:    "2024-03-09T05:00:00Z",
+			wantTo:      "2024-03-12T04:00:00Z",
+		},
+		{
+			name:        "clocks go back at 02:00",
+			timezone:    "America/New_York",
+			start:       "2024-11-02",
+			end:         "2024-11-04",
+			granularity: GranularityDay,
+			buckets:     []time.Time{day(2024, 11, 3), day(2024, 11, 4)},
+			wantPeriods: []string{"2024-11-02", "2024-11-03", "2024-11-04"},
+			wantCounts:  []int64{0, 1, 1},
+			wantFrom:    "2024-11-02T04:00:00Z",
+			wantTo:      "2024-11-05T05:00:00Z",
+		},
+		{
+			name:        "clocks go forward at midnight",
+			timezone:    "America/Santiago",
+			start:       "2024-09-07",
+			end:         "2024-09-10",
+			granularity: GranularityDay,
+			buckets:     []time.Time{day(2024, 9, 8), day(2024, 9, 9)},
+			wantPeriods: []string{"2024-09-07", "2024-09-08", "2024-09-09", "2024-09-10"},
+			wantCounts:  []int64{0, 1, 1, 0},
+			wantFrom:    "2024-09-07T04:00:00Z",
+			wantTo:      "2024-09-11T03:00:00Z",
+		},
+		{
+			name:        "range starting on a day with no midnight",
+			timezone:    "America/Santiago",
+			start:       "2024-09-08",
+			end:         "2024-09-08",
+			granularity: GranularityDay,
+			buckets:     []time.Time{day(2024, 9, 8)},
+			wantPeriods: []string{"2024-09-08"},
+			wantCounts:  []int64{1},
+			wantFrom:    "2024-09-08T04:00:00Z",
+			wantTo:      "2024-09-09T03:00:00Z",
+		},
+		{
+			name:        "week spanning clocks going back",
+			timezone:    "Europe/London",
+			start:       "2024-10-21",
+			end:         "2024-11-03",
+			granularity: GranularityWeek,
+			buckets:     []time.Time{day(2024, 10, 21)},
+			wantPeriods: []string{"2024-10-21", "2024-10-28"},
+			wantCounts:  []int64{1, 0},
+			wantFrom:    "2024-10-20T23:00:00Z",
+			wantTo:      "2024-11-04T00:00:00Z",
+		},
+		{
+			name:        "month spanning clocks going forward",
+			timezone:    "Europe/Paris",
+			start:       "2024-03-15",
+			end:         "2024-04-10",
+			granularity: GranularityMonth,
+			buckets:     []time.Time{day(2024, 3, 1), day(2024, 4, 1)},
+			wantPeriods: []string{"2024-03-01", "2024-04-01"},
+			wantCounts:  []int64{1, 1},
+			wantFrom:    "2024-03-14T23:00:00Z",
+			wantTo:      "2024-04-10T22:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analytics := &fakeAnalytics{}
+			for _, b := range tt.buckets {
+				analytics.series = append(analytics.series, repositories.AnalyticsBucket{Period: b, Count: 1, Volume: 10})
+			}
+			report, err := newAnalyticsService(analytics, false).GetTransactionAnalytics(context.Background(), 1, AnalyticsQuery{
+				StartDate:   tt.start,
+				EndDate:     tt.end,
+				Granularity: tt.granularity,
+				Metrics:     []string{MetricCount},
+				Timezone:    tt.timezone,
+			})
+			if err != nil {
+				t.Fatalf("GetTransactionAnalytics() error = %v", err)
+			}
+
+			if report.Timezone != tt.timezone || report.StartDate != tt.start || report.EndDate != tt.end {
+				t.Errorf("report covers %s to %s in %s, want %s to %s in %s",
+					report.StartDate, report.EndDate, report.Timezone, tt.start, tt.end, tt.timezone)
+			}
+			var periods []string
+			var counts []int64
+			for _, p := range report.Series {
+				periods = append(periods, p.Period)
+				counts = append(counts, *p.Count)
+			}
+			if strings.Join(periods, ",") != strings.Join(tt.wantPeriods, ",") {
+				t.Errorf("periods = %v, want %v", periods, tt.wantPeriods)
+			}
+			for i := range counts {
+				if i < len(tt.wantCounts) && counts[i] != tt.wantCounts[i] {
+					t.Errorf("counts = %v, want %v", counts, tt.wantCounts)
+					break
+				}
+			}
+
+			filter := analytics.filters[0]
+			if filter.Timezone != tt.timezone {
+				t.Errorf("filter timezone = %q, want %q", filter.Timezone, tt.timezone)
+			}
+			if got := filter.From.UTC().Format(time.RFC3339); got != tt.wantFrom {
+				t.Errorf("filter from = %s, want %s", got, tt.wantFrom)
+			}
+			if got := filter.To.UTC().Format(time.RFC3339); got != tt.wantTo {
+				t.Errorf("filter to = %s, want %s", got, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestAnalyticsRejectsBadQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query AnalyticsQuery
+		want  error
+	}{
+		{"unknown granularity", AnalyticsQuery{Granularity: "hour"}, ErrInvalidGranularity},
+		{"unknown metric", AnalyticsQuery{Metrics: []string{"count", "margin"}}, ErrInvalidMetric},
+		{"unknown timezone", AnalyticsQuery{Timezone: "Mars/Olympus"}, ErrInvalidTimezone},
+		{"server timezone", AnalyticsQuery{Timezone: "Local"}, ErrInvalidTimezone},
+		{"malformed date", AnalyticsQuery{StartDate: "01/02/2024", EndDate: "2024-02-01"}, ErrInvalidDate},
+		{"start after end", AnalyticsQuery{StartDate: "2024-03-01", EndDate: "2024-02-01"}, ErrInvalidDateRange},
+		{"too many periods", AnalyticsQuery{StartDate: "2023-01-01", EndDate: "2024-01-31"}, ErrRangeTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analytics := &fakeAnalytics{}
+			_, err := newAnalyticsService(analytics, false).GetTransactionAnalytics(context.Background(), 1, tt.query)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetTransactionAnalytics() error = %v, want %v", err, tt.want)
+			}
+			if len(analytics.filters) != 0 {
+				t.Errorf("rejected query reached the database")
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"math"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Customer churn risks
+const (
+	ChurnRiskLow    = "low"
+	ChurnRiskMedium = "medium"
+	ChurnRiskHigh   = "high"
+)
+
+// Customer sort orders
+const (
+	CustomerSortVolume   = "volume"
+	CustomerSortPayments = "payments"
+	CustomerSortRecent   = "recent"
+)
+
+// churnBaselineDays is the payment gap assumed for customers who have only
+// paid once, and the shortest one used for anybody
+const churnBaselineDays = 30
+
+// CustomerQuery selects the customers a merchant insights report covers
+type CustomerQuery struct {
+	StartDate string // YYYY-MM-DD; defaults to a month before EndDate
+	EndDate   string // YYYY-MM-DD, inclusive; defaults to today
+	Timezone  string // IANA name the dates are in; defaults to UTC
+	Sort      string // volume, payments or recent; defaults to volume
+}
+
+// CustomerInsights is one page of a merchant's customers with a summary of
+// all of them
+type CustomerInsights struct {
+	Summary   CustomerSummary
+	Customers []CustomerView
+	Total     int64
+}
+
+// CustomerSummary describes a merchant's customer base over the range.
+// Retention compares it with the equally long period just before.
+type CustomerSummary struct {
+	StartDate          string  `json:"start_date"`
+	EndDate            string  `json:"end_date"`
+	Customers          int64   `json:"customers"`
+	NewCustomers       int64   `json:"new_customers"`
+	ReturningCustomers int64   `json:"returning_customers"`
+	RepeatRate         float64 `json:"repeat_rate"` // Percent who paid more than once
+	Payments           int64   `json:"payments"`
+	Volume             float64 `json:"volume"`
+	AverageBasket      float64 `json:"average_basket"`
+	PreviousCustomers  int64   `json:"previous_period_customers"`
+	LapsedCustomers    int64   `json:"lapsed_customers"` // Paid in the previous period, not this one
+	RetentionRate      float64 `json:"retention_rate"`   // Percent of previous customers who came back
+}
+
+// CustomerView is what one customer spent with the merchant
+type CustomerView struct {
+	CustomerID           uint      `json:"customer_id"`
+	Name                 string    `json:"name"`
+	Status               string    `json:"status"` // "new" or "returning"
+	Payments             int64     `json:"payments"`
+	Volume               float64   `json:"volume"`
+	AverageBasket        float64   `json:"average_basket"`
+	FirstPaymentAt       time.Time `json:"first_payment_at"`
+	LastPaymentAt        time.Time `json:"last_payment_at"`
+	LifetimePayments     int64     `json:"lifetime_payments"`
+	LifetimeVolume       float64   `json:"lifetime_volume"`
+	DaysSinceLastPayment int       `json:"days_since_last_payment"`
+	ChurnRisk            string    `json:"churn_risk"`
+}
+
+func (s *service) GetCustomerInsights(ctx context.Context, merchantID uint, query CustomerQuery, limit, offset int) (*CustomerInsights, error) {
+	if _, err := s.merchantRepo.GetByUserID(merchantID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotMerchant
+		}
+		return nil, err
+	}
+
+	sortBy := "volume"
+	switch strings.ToLower(strings.TrimSpace(query.Sort)) {
+	case "", CustomerSortVolume:
+	case CustomerSortPayments:
+		sortBy = "payments"
+	case CustomerSortRecent:
+		sortBy = "last_payment_at"
+	default:
+		return nil, ErrInvalidSort
+	}
+	loc, err := parseTimezone(query.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseRange(query.StartDate, query.EndDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repositories.CustomerFilter{
+		MerchantID: merchantID,
+		From:       localMidnight(start, loc),
+		To:         localMidnight(end.AddDate(0, 0, 1), loc),
+		SortBy:     sortBy,
+	}
+	summary, err := s.analytics.SummarizeCustomers(filter)
+	if err != nil {
+		return nil, err
+	}
+	stats, total, err := s.analytics.ListCustomers(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	customers := make([]CustomerView, 0, len(stats))
+	for _, c := range stats {
+		days, risk := churnRisk(c, now)
+		status := "returning"
+		if !c.FirstPaymentAt.Before(filter.From) {
+			status = "new"
+		}
+		customers = append(customers, CustomerView{
+			CustomerID:           c.CustomerID,
+			Name:                 c.Name,
+			Status:               status,
+			Payments:             c.Payments,
+			Volume:               math.Round(c.Volume*100) / 100,
+			AverageBasket:        average(c.Volume, c.Payments),
+			FirstPaymentAt:       c.FirstPaymentAt,
+			LastPaymentAt:        c.LastPaymentAt,
+			LifetimePayments:     c.LifetimePayments,
+			LifetimeVolume:       math.Round(c.LifetimeVolume*100) / 100,
+			DaysSinceLastPayment: days,
+			ChurnRisk:            risk,
+		})
+	}
+
+	return &CustomerInsights{
+		Summary: CustomerSummary{
+			StartDate:          start.Format(dateLayout),
+			EndDate:            end.Format(dateLayout),
+			Customers:          summary.Customers,
+			NewCustomers:       summary.NewCustomers,
+			ReturningCustomers: summary.Customers - summary.NewCustomers,
+			RepeatRate:         percent(summary.RepeatCustomers, summary.Customers),
+			Payments:           summary.Payments,
+			Volume:             math.Round(summary.Volume*100) / 100,
+			AverageBasket:      average(summary.Volume, summary.Payments),
+			PreviousCustomers:  summary.PreviousCustomers,
+			LapsedCustomers:    summary.LapsedCustomers,
+			RetentionRate:      percent(summary.PreviousCustomers-summary.LapsedCustomers, summary.PreviousCustomers),
+		},
+		Customers: customers,
+		Total:     total,
+	}, nil
+}
+
+// churnRisk compares the time since a customer last paid with how often
+// they usually pay: overdue by twice their usual gap is medium risk, by
+// four times high
+func churnRisk(c repositories.CustomerStats, now time.Time) (int, string) {
+	days := int(now.Sub(c.LastPaymentAt).Hours() / 24)
+	gap := float64(churnBaselineDays)
+	if c.LifetimePayments > 1 {
+		usual := c.LastPaymentAt.Sub(c.FirstPaymentAt).Hours() / 24 / float64(c.LifetimePayments-1)
+		gap = math.Max(usual, churnBaselineDays)
+	}
+	switch {
+	case float64(days) > 4*gap:
+		return days, ChurnRiskHigh
+	case float64(days) > 2*gap:
+		return days, ChurnRiskMedium
+	default:
+		return days, ChurnRiskLow
+	}
+}
+
+func percent(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
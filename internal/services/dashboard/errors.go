@@ -0,0 +1,15 @@
+package dashboard
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidGranularity = errors.New("granularity must be day, week or month")
+	ErrInvalidMetric      = errors.New("metrics must be volume, count, average or unique_customers")
+	ErrInvalidTimezone    = errors.New("unknown timezone")
+	ErrInvalidDate        = errors.New("dates must be in YYYY-MM-DD format")
+	ErrInvalidDateRange   = errors.New("start date must not be after end date")
+	ErrRangeTooLong       = errors.New("date range has too many periods for the granularity")
+	ErrInvalidSort        = errors.New("sort must be volume, payments or recent")
+	ErrNotMerchant        = errors.New("merchant profile not found")
+)
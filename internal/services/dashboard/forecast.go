@@ -0,0 +1,80 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EarningsForecast projects what a merchant's spendable balance comes to
+// once the payments and refunds in flight settle. Funds held in reserve,
+// such as against chargebacks, don't count towards it until released.
+type EarningsForecast struct {
+	AvailableBalance float64      `json:"available_balance"` // Balance less reserves
+	PendingSales     ForecastItem `json:"pending_sales"`
+	UpcomingFees     float64      `json:"upcoming_fees"` // Fees the pending sales will incur
+	PendingRefunds   ForecastItem `json:"pending_refunds"`
+	Reserves         float64      `json:"reserves"`
+	// ReservesByType splits the reserves by hold type, e.g. chargeback_reserve
+	ReservesByType map[string]float64 `json:"reserves_by_type"`
+	NextSettlement float64            `json:"next_settlement"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+}
+
+// ForecastItem counts and sums the transactions of one kind in flight
+type ForecastItem struct {
+	Count  int64   `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+func (s *service) GetEarningsForecast(ctx context.Context, merchantID uint) (*EarningsForecast, error) {
+	merchant, err := s.merchantRepo.GetByUserID(merchantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotMerchant
+		}
+		return nil, err
+	}
+
+	wallet, err := s.walletRepo.GetByUserID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := s.walletRepo.GetActiveHolds(wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	forecast := EarningsForecast{
+		ReservesByType: make(map[string]float64),
+		GeneratedAt:    time.Now(),
+	}
+	for _, hold := range holds {
+		forecast.Reserves += hold.Amount
+		forecast.ReservesByType[hold.Type] = round2(forecast.ReservesByType[hold.Type] + hold.Amount)
+	}
+	forecast.Reserves = round2(forecast.Reserves)
+	forecast.AvailableBalance = round2(wallet.Balance - forecast.Reserves)
+
+	pending, err := s.transactionRepo.GetPendingMerchantTotals(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	forecast.PendingSales = ForecastItem{Count: pending.IncomingCount, Amount: round2(pending.IncomingAmount)}
+	forecast.PendingRefunds = ForecastItem{Count: pending.RefundCount, Amount: round2(pending.RefundAmount)}
+	// Sales not priced yet are charged the merchant's effective rate and
+	// its fixed fee each
+	fee := merchant.EffectiveFee()
+	forecast.UpcomingFees = round2(pending.IncomingFees + pending.UnpricedAmount*fee.Rate +
+		float64(pending.UnpricedCount)*fee.FixedFee)
+
+	forecast.NextSettlement = round2(forecast.AvailableBalance + forecast.PendingSales.Amount -
+		forecast.UpcomingFees - forecast.PendingRefunds.Amount)
+	return &forecast, nil
+}
+
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,102 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+)
+
+type fakeForecastWallets struct {
+	repositories.WalletRepository
+	wallet *models.Wallet
+	holds  []models.WalletHold
+}
+
+func (f *fakeForecastWallets) GetByUserID(userID uint) (*models.Wallet, error) {
+	return f.wallet, nil
+}
+
+func (f *fakeForecastWallets) GetActiveHolds(walletID uint) ([]models.WalletHold, error) {
+	return f.holds, nil
+}
+
+type fakePendingTotals struct {
+	repositories.TransactionRepository
+	totals repositories.PendingMerchantTotals
+}
+
+func (f *fakePendingTotals) GetPendingMerchantTotals(merchantID uint) (*repositories.PendingMerchantTotals, error) {
+	totals := f.totals
+	return &totals, nil
+}
+
+func TestEarningsForecast(t *testing.T) {
+	wallets := &fakeForecastWallets{
+		wallet: &models.Wallet{ID: 3, UserID: 7, Balance: 1000},
+		holds:  []models.WalletHold{{Type: models.HoldTypeChargebackReserve, Amount: 50}},
+	}
+	// One sale of 100 is priced at the standard 3.20; two, of 200 in all,
+	// are not priced yet
+	pending := repositories.PendingMerchantTotals{
+		IncomingCount:  3,
+		IncomingAmount: 300,
+		IncomingFees:   3.20,
+		UnpricedCount:  2,
+		UnpricedAmount: 200,
+		RefundCount:    1,
+		RefundAmount:   20,
+	}
+	priced := pending
+	priced.UnpricedCount, priced.UnpricedAmount = 0, 0
+
+	tests := []struct {
+		name           string
+		merchant       *fakeMerchants
+		pending        repositories.PendingMerchantTotals
+		wantFees       float64
+		wantSettlement float64
+	}{
+		{
+			name:           "standard plan charges its rate and fixed fee on each unpriced sale",
+			merchant:       &fakeMerchants{merchant: true, plan: models.FeePlanStandard},
+			pending:        pending,
+			wantFees:       9.60, // 3.20 + 200 × 2.9% + 2 × 0.30
+			wantSettlement: 1220.40,
+		},
+		{
+			name:           "negotiated rate keeps the plan's fixed fee",
+			merchant:       &fakeMerchants{merchant: true, plan: models.FeePlanPlus, rate: 0.02},
+			pending:        pending,
+			wantFees:       7.70, // 3.20 + 200 × 2% + 2 × 0.25
+			wantSettlement: 1222.30,
+		},
+		{
+			name:           "priced sales only",
+			merchant:       &fakeMerchants{merchant: true, plan: models.FeePlanEnterprise},
+			pending:        priced,
+			wantFees:       3.20,
+			wantSettlement: 1226.80,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(nil, nil, &fakePendingTotals{totals: tt.pending}, wallets, tt.merchant, nil)
+
+			forecast, err := svc.GetEarningsForecast(context.Background(), 7)
+			if err != nil {
+				t.Fatalf("GetEarningsForecast() error = %v", err)
+			}
+			if forecast.UpcomingFees != tt.wantFees {
+				t.Errorf("UpcomingFees = %v, want %v", forecast.UpcomingFees, tt.wantFees)
+			}
+			if forecast.AvailableBalance != 950 || forecast.Reserves != 50 {
+				t.Errorf("AvailableBalance, Reserves = %v, %v, want 950, 50", forecast.AvailableBalance, forecast.Reserves)
+			}
+			if forecast.NextSettlement != tt.wantSettlement {
+				t.Errorf("NextSettlement = %v, want %v", forecast.NextSettlement, tt.wantSettlement)
+			}
+		})
+	}
+}
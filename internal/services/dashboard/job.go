@@ -0,0 +1,24 @@
+package dashboard
+
+import (
+	"context"
+	"log"
+)
+
+// Job keeps the dashboard aggregates up to date with new transactions
+type Job struct {
+	projector Projector
+}
+
+// NewJob wraps the projector as a scheduled job
+func NewJob(p Projector) *Job { return &Job{projector: p} }
+
+func (j *Job) Name() string { return "dashboard-projection" }
+
+func (j *Job) Run(ctx context.Context) error {
+	projected, err := j.projector.Project(ctx)
+	if projected > 0 {
+		log.Printf("Projected %d transactions into dashboard stats", projected)
+	}
+	return err
+}
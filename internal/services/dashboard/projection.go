@@ -0,0 +1,201 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	projectionName = "dashboard"
+	// projectionBatch is how many transactions are read at a time
+	projectionBatch = 500
+	// projectionOverlap re-reads the last stretch of the stream on every
+	// run, so transactions that committed after a later one was read
+	// aren't missed. Ones already projected are skipped.
+	projectionOverlap = 2 * time.Minute
+)
+
+// Projector folds completed transactions into the daily per-user and
+// per-merchant aggregates the dashboards read
+type Projector interface {
+	// Project catches the aggregates up with the transaction stream and
+	// returns how many transactions it added
+	Project(ctx context.Context) (int, error)
+}
+
+type projector struct {
+	repo         repositories.DashboardProjectionRepository
+	merchantRepo repositories.MerchantRepository
+}
+
+// NewProjector creates the dashboard projection worker
+func NewProjector(repo repositories.DashboardProjectionRepository, merchantRepo repositories.MerchantRepository) Projector {
+	return &projector{repo: repo, merchantRepo: merchantRepo}
+}
+
+func (p *projector) Project(ctx context.Context) (int, error) {
+	position, err := p.repo.GetCursor(projectionName)
+	if err != nil {
+		return 0, err
+	}
+
+	after := position
+	if !after.IsZero() {
+		after = after.Add(-projectionOverlap)
+	}
+	var afterID uint
+	merchants := make(map[uint]bool)
+	projected := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return projected, err
+		}
+		batch, err := p.repo.ListCompletedSince(after, afterID, projectionBatch)
+		if err != nil {
+			return projected, err
+		}
+		for i := range batch {
+			tx := &batch[i]
+			update, err := p.update(tx, merchants)
+			if err != nil {
+				return projected, err
+			}
+			applied, err := p.repo.Apply(update)
+			if err != nil {
+				return projected, err
+			}
+			if applied {
+				projected++
+			}
+			after, afterID = tx.UpdatedAt, tx.ID
+		}
+
+		// Save progress per batch so a long backfill resumes where it stopped
+		if after.After(position) {
+			if err := p.repo.SaveCursor(projectionName, after); err != nil {
+				return projected, err
+			}
+			position = after
+		}
+		if len(batch) < projectionBatch {
+			return projected, nil
+		}
+	}
+}
+
+// update works out what a completed transaction adds to the aggregates of
+// the users and merchant involved
+func (p *projector) update(tx *models.Transaction, merchants map[uint]bool) (repositories.ProjectionUpdate, error) {
+	at := tx.CreatedAt.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	update := repositories.ProjectionUpdate{TransactionID: tx.ID}
+	breakdown := func(scope string, ownerID uint, key string) {
+		update.Breakdowns = append(update.Breakdowns, models.DailyBreakdown{
+			Scope:   scope,
+			OwnerID: ownerID,
+			Day:     day,
+			Key:     key,
+			Count:   1,
+			Volume:  tx.Amount,
+		})
+	}
+
+	// Top-ups have no receiver; the money comes in to the sender
+	if isTopUp(tx.Type) {
+		update.Users = append(update.Users, models.UserDailyStats{
+			UserID:            tx.SenderID,
+			Day:               day,
+			ReceivedCount:     1,
+			ReceivedVolume:    tx.Amount,
+			LastTransactionAt: at,
+		})
+		breakdown(models.BreakdownUserIncome, tx.SenderID, "Top Up")
+		breakdown(models.BreakdownUserType, tx.SenderID, tx.Type)
+		return update, nil
+	}
+
+	internal := tx.SenderID == tx.ReceiverID
+	if tx.SenderID != 0 {
+		update.Users = append(update.Users, models.UserDailyStats{
+			UserID:            tx.SenderID,
+			Day:               day,
+			SentCount:         1,
+			SentVolume:        tx.Amount,
+			LastTransactionAt: at,
+		})
+		breakdown(models.BreakdownUserType, tx.SenderID, tx.Type)
+		if tx.CountsAsSpending() {
+			breakdown(models.BreakdownUserSpending, tx.SenderID, tx.SpendingCategory())
+		}
+	}
+
+	if tx.ReceiverID == 0 || internal {
+		return update, nil
+	}
+	update.Users = append(update.Users, models.UserDailyStats{
+		UserID:            tx.ReceiverID,
+		Day:               day,
+		ReceivedCount:     1,
+		ReceivedVolume:    tx.Amount,
+		LastTransactionAt: at,
+	})
+	breakdown(models.BreakdownUserType, tx.ReceiverID, tx.Type)
+	breakdown(models.BreakdownUserIncome, tx.ReceiverID, incomeSource(tx.Type))
+
+	merchant, err := p.isMerchant(tx.ReceiverID, merchants)
+	if err != nil {
+		return update, err
+	}
+	if merchant {
+		update.Merchants = append(update.Merchants, models.MerchantDailyStats{
+			MerchantID: tx.ReceiverID,
+			Day:        day,
+			Count:      1,
+			Volume:     tx.Amount,
+			Fees:       tx.Fee,
+		})
+		method := tx.PaymentMethod
+		if method == "" {
+			method = "unknown"
+		}
+		breakdown(models.BreakdownMerchantMethod, tx.ReceiverID, method)
+	}
+	return update, nil
+}
+
+// isMerchant reports whether the user has a merchant account, remembering
+// the answer for the rest of the run
+func (p *projector) isMerchant(userID uint, merchants map[uint]bool) (bool, error) {
+	if known, ok := merchants[userID]; ok {
+		return known, nil
+	}
+	_, err := p.merchantRepo.GetByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	merchants[userID] = err == nil
+	return err == nil, nil
+}
+
+func isTopUp(txType string) bool {
+	return strings.EqualFold(txType, "top_up") || txType == models.TransactionTypeTopup
+}
+
+func incomeSource(txType string) string {
+	switch txType {
+	case models.TransactionTypeRefund:
+		return "Refund"
+	case models.TransactionTypeP2PTransfer, models.TransactionTypeTransfer:
+		return "Received Transfer"
+	default:
+		return "Other Income"
+	}
+}
@@ -3,25 +3,39 @@ package dashboard
 import (
 	"context"
 	"fmt"
+	"math"
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"time"
-
-	"gorm.io/gorm"
 )
 
+// recentMonths bounds the "recent" lists so they only read the latest
+// transaction partitions
+const recentMonths = 3
+
 type Service interface {
 	GetUserDashboard(ctx context.Context, userID uint) (*models.UserDashboardStats, error)
 	GetMerchantDashboard(ctx context.Context, merchantID uint) (*MerchantDashboard, error)
-	GetTransactionAnalytics(ctx context.Context, userID uint, startDate, endDate time.Time) (map[string]interface{}, error)
+	GetTransactionAnalytics(ctx context.Context, userID uint, query AnalyticsQuery) (*AnalyticsReport, error)
+	// GetCustomerInsights summarizes who pays the merchant and lists a page
+	// of those customers
+	GetCustomerInsights(ctx context.Context, merchantID uint, query CustomerQuery, limit, offset int) (*CustomerInsights, error)
+	// GetEarningsForecast projects the merchant's next settlement from the
+	// payments, fees, reserves and refunds still in flight
+	GetEarningsForecast(ctx context.Context, merchantID uint) (*EarningsForecast, error)
 }
 
+// service reads the dashboards from the daily aggregates the projector
+// maintains, so figures trail new transactions by up to one projection run.
+// Days are UTC. Analytics reports, which can be cut in any timezone, query
+// transactions directly.
 type service struct {
+	projections     repositories.DashboardProjectionRepository
+	analytics       repositories.AnalyticsRepository
 	transactionRepo repositories.TransactionRepository
 	walletRepo      repositories.WalletRepository
 	merchantRepo    repositories.MerchantRepository
-	userRepo        repositories.UserRepository
-	db              *gorm.DB
+	paymentLinks    repositories.PaymentLinkRepository
 }
 
 type MerchantDashboard struct {
@@ -32,21 +46,34 @@ type MerchantDashboard struct {
 	MonthlyTransactions int64                `json:"monthly_transactions"`
 	MonthlyAmount       float64              `json:"monthly_amount"`
 	RecentTransactions  []models.Transaction `json:"recent_transactions"`
+	PaymentLinks        PaymentLinkStats     `json:"payment_links"`
+}
+
+// PaymentLinkStats summarizes views and conversions across a merchant's payment links
+type PaymentLinkStats struct {
+	TotalLinks     int64   `json:"total_links"`
+	ActiveLinks    int64   `json:"active_links"`
+	Views          int64   `json:"views"`
+	Conversions    int64   `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+	Revenue        float64 `json:"revenue"`
 }
 
 func NewService(
+	projections repositories.DashboardProjectionRepository,
+	analytics repositories.AnalyticsRepository,
 	transactionRepo repositories.TransactionRepository,
 	walletRepo repositories.WalletRepository,
 	merchantRepo repositories.MerchantRepository,
-	userRepo repositories.UserRepository,
-	db *gorm.DB,
+	paymentLinks repositories.PaymentLinkRepository,
 ) Service {
 	return &service{
+		projections:     projections,
+		analytics:       analytics,
 		transactionRepo: transactionRepo,
 		walletRepo:      walletRepo,
 		merchantRepo:    merchantRepo,
-		userRepo:        userRepo,
-		db:              db,
+		paymentLinks:    paymentLinks,
 	}
 }
 
@@ -58,22 +85,34 @@ func (s *service) GetUserDashboard(ctx context.Context, userID uint) (*models.Us
 	}
 
 	// Get recent transactions
-	recentMerchants, err := s.transactionRepo.GetRecentMerchants(userID, 5)
+	recentMerchants, err := s.transactionRepo.GetRecentMerchants(userID, today().AddDate(0, -recentMonths, 0), 5)
 	if err != nil {
 		return nil, err
 	}
 
+	since := today().AddDate(0, -1, 0)
+
 	// Get spending by category
-	spendingByCategory, err := s.transactionRepo.GetSpendingByCategory(userID, time.Now().AddDate(0, -1, 0))
+	spending, err := s.projections.GetBreakdown(models.BreakdownUserSpending, userID, since, time.Time{})
 	if err != nil {
 		return nil, err
 	}
+	spendingByCategory := make(map[string]float64, len(spending))
+	var monthlySpending float64
+	for _, row := range spending {
+		spendingByCategory[row.Key] = row.Volume
+		monthlySpending += row.Volume
+	}
 
 	// Get income by category
-	incomeByCategory, err := s.transactionRepo.GetIncomeByCategory(userID, time.Now().AddDate(0, -1, 0))
+	income, err := s.projections.GetBreakdown(models.BreakdownUserIncome, userID, since, time.Time{})
 	if err != nil {
 		return nil, err
 	}
+	incomeByCategory := make(map[string]float64, len(income))
+	for _, row := range income {
+		incomeByCategory[row.Key] = row.Volume
+	}
 
 	return &models.UserDashboardStats{
 		DashboardStats:     *stats,
@@ -81,53 +120,58 @@ func (s *service) GetUserDashboard(ctx context.Context, userID uint) (*models.Us
 		RecentMerchants:    recentMerchants,
 		SpendingByCategory: spendingByCategory,
 		IncomeByCategory:   incomeByCategory,
-		MonthlySpending:    stats.TotalVolume,
+		MonthlySpending:    math.Round(monthlySpending*100) / 100,
 	}, nil
 }
 
 func (s *service) GetMerchantDashboard(ctx context.Context, merchantID uint) (*MerchantDashboard, error) {
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	startOfDay := today()
+	startOfMonth := time.Date(startOfDay.Year(), startOfDay.Month(), 1, 0, 0, 0, 0, time.UTC)
 
 	var dashboard MerchantDashboard
 
 	// Get total stats
-	err := s.db.Model(&models.Transaction{}).
-		Where("receiver_id = ? AND status = ?", merchantID, "completed").
-		Select("COUNT(*) as total_transactions, COALESCE(SUM(amount), 0) as total_amount").
-		Row().Scan(&dashboard.TotalTransactions, &dashboard.TotalAmount)
+	total, err := s.projections.GetMerchantTotals(merchantID, time.Time{}, time.Time{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total stats: %w", err)
 	}
+	dashboard.TotalTransactions, dashboard.TotalAmount = total.Count, total.Volume
 
 	// Get daily stats
-	err = s.db.Model(&models.Transaction{}).
-		Where("receiver_id = ? AND status = ? AND updated_at >= ?",
-			merchantID, "completed", startOfDay).
-		Select("COUNT(*) as daily_transactions, COALESCE(SUM(amount), 0) as daily_amount").
-		Row().Scan(&dashboard.DailyTransactions, &dashboard.DailyAmount)
+	daily, err := s.projections.GetMerchantTotals(merchantID, startOfDay, time.Time{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get daily stats: %w", err)
 	}
+	dashboard.DailyTransactions, dashboard.DailyAmount = daily.Count, daily.Volume
 
 	// Get monthly stats
-	err = s.db.Model(&models.Transaction{}).
-		Where("receiver_id = ? AND status = ? AND updated_at >= ?",
-			merchantID, "completed", startOfMonth).
-		Select("COUNT(*) as monthly_transactions, COALESCE(SUM(amount), 0) as monthly_amount").
-		Row().Scan(&dashboard.MonthlyTransactions, &dashboard.MonthlyAmount)
+	monthly, err := s.projections.GetMerchantTotals(merchantID, startOfMonth, time.Time{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get monthly stats: %w", err)
 	}
+	dashboard.MonthlyTransactions, dashboard.MonthlyAmount = monthly.Count, monthly.Volume
 
 	// Get recent transactions
-	err = s.db.Where("receiver_id = ? AND status = ?", merchantID, "completed").
-		Order("updated_at DESC").
-		Limit(10).
-		Find(&dashboard.RecentTransactions).Error
+	dashboard.RecentTransactions, err = s.transactionRepo.GetRecentReceived(merchantID, today().AddDate(0, -recentMonths, 0), 10)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get payment link analytics
+	links, err := s.paymentLinks.GetMerchantTotals(merchantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent transactions: %w", err)
+		return nil, err
+	}
+	dashboard.PaymentLinks = PaymentLinkStats{
+		TotalLinks:  links.Links,
+		ActiveLinks: links.ActiveLinks,
+		Views:       links.Views,
+		Conversions: links.Uses,
+		Revenue:     links.Revenue,
+	}
+	if dashboard.PaymentLinks.Views > 0 {
+		rate := float64(dashboard.PaymentLinks.Conversions) / float64(dashboard.PaymentLinks.Views) * 100
+		dashboard.PaymentLinks.ConversionRate = math.Round(rate*100) / 100
 	}
 
 	return &dashboard, nil
@@ -135,175 +179,56 @@ func (s *service) GetMerchantDashboard(ctx context.Context, merchantID uint) (*M
 
 func (s *service) getBasicStats(_ context.Context, userID uint) (*models.DashboardStats, error) {
 	// Get transaction count and volume
-	count, volume, err := s.transactionRepo.GetTransactionStats(userID)
+	totals, err := s.projections.GetUserTotals(userID, time.Time{}, time.Time{})
 	if err != nil {
 		return nil, err
 	}
+	count := totals.SentCount + totals.ReceivedCount
+	volume := math.Round((totals.SentVolume+totals.ReceivedVolume)*100) / 100
 
-	// Get last transaction date
-	lastTx, err := s.transactionRepo.GetLastTransaction(userID)
+	// Get wallet balance
+	wallet, err := s.walletRepo.GetByUserID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get wallet balance
-	wallet, err := s.walletRepo.GetByUserID(userID)
+	// Funds reserved by holds are part of the balance but not spendable
+	holds, err := s.walletRepo.GetActiveHolds(wallet.ID)
 	if err != nil {
 		return nil, err
 	}
+	var held float64
+	for _, hold := range holds {
+		held += hold.Amount
+	}
+
+	var lastTransaction *time.Time
+	if !totals.LastTransactionAt.IsZero() {
+		lastTransaction = &totals.LastTransactionAt
+	}
 
 	return &models.DashboardStats{
-		TotalTransactions:        count,
+		TotalTransactions:        int(count),
 		TotalVolume:              volume,
-		AverageTransactionAmount: volume / float64(count),
-		LastTransactionDate:      &lastTx.ProcessedAt,
+		AverageTransactionAmount: average(volume, count),
+		LastTransactionDate:      lastTransaction,
 		CurrentBalance:           wallet.Balance,
+		AvailableBalance:         wallet.Balance - held,
+		HeldBalance:              held,
 		PendingTransactions:      0, // TODO: Implement pending transactions count
 	}, nil
 }
 
-func (s *service) GetTransactionAnalytics(ctx context.Context, userID uint, startDate, endDate time.Time) (map[string]interface{}, error) {
-	// Check if user is a merchant
-	merchant, err := s.merchantRepo.GetByUserID(userID)
-	if err == nil && merchant != nil {
-		// This is a merchant, get merchant-specific analytics
-		fmt.Printf("Getting merchant analytics for userID %d (merchantID %d)\n", userID, merchant.ID)
-		return s.getMerchantAnalytics(merchant.ID, startDate, endDate)
-	}
-
-	// Regular user analytics
-	volumeOverTime, err := s.transactionRepo.GetVolumeOverTime(userID, startDate, endDate)
-	if err != nil {
-		return nil, err
-	}
-
-	countByType, err := s.transactionRepo.GetTransactionCountByType(userID, startDate, endDate)
-	if err != nil {
-		return nil, err
-	}
-
-	return map[string]interface{}{
-		"volume_over_time": volumeOverTime,
-		"count_by_type":    countByType,
-	}, nil
+// today returns the start of the current UTC day, the unit the projections
+// are kept in
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 }
 
-func (s *service) getMerchantAnalytics(merchantID uint, startDate, endDate time.Time) (map[string]interface{}, error) {
-	fmt.Printf("Querying transactions for merchantID %d between %v and %v\n", merchantID, startDate, endDate)
-
-	// Debug query parameters
-	fmt.Printf("Query parameters - MerchantID: %d, Status: completed, Start: %v, End: %v\n",
-		merchantID, startDate, endDate)
-
-	// First, let's check if we can find any transactions at all for this merchant
-	var totalTx int64
-	err := s.db.Model(&models.Transaction{}).
-		Where("merchant_id = ?", merchantID).
-		Count(&totalTx).Error
-	if err != nil {
-		return nil, fmt.Errorf("failed to count total transactions: %w", err)
-	}
-	fmt.Printf("Total transactions found for merchant (without filters): %d\n", totalTx)
-
-	// Get daily volumes with more detailed logging
-	volumeOverTime := make(map[string]float64)
-	var dailyStats []struct {
-		Date   string
-		Count  int
-		Volume float64
-	}
-
-	err = s.db.Model(&models.Transaction{}).
-		Select("DATE(processed_at)::text as date, COUNT(*) as count, COALESCE(SUM(amount), 0) as volume").
-		Where("merchant_id = ? AND status = ? AND processed_at >= ? AND processed_at <= ?",
-			merchantID, "completed", startDate, endDate).
-		Group("DATE(processed_at)").
-		Order("date").
-		Find(&dailyStats).Error
-
-	if err != nil {
-		fmt.Printf("Error getting daily volumes: %v\n", err)
-		return nil, fmt.Errorf("failed to get daily volumes: %w", err)
-	}
-
-	for _, stat := range dailyStats {
-		volumeOverTime[stat.Date] = stat.Volume
-		fmt.Printf("Daily stat - Date: %s, Count: %d, Volume: %.2f\n",
-			stat.Date, stat.Count, stat.Volume)
+func average(volume float64, count int64) float64 {
+	if count == 0 {
+		return 0
 	}
-
-	// Get payment method counts with debugging
-	countByType := make(map[string]int64)
-	var methodStats []struct {
-		Method string
-		Count  int64
-	}
-
-	err = s.db.Model(&models.Transaction{}).
-		Select("COALESCE(payment_method, 'unknown') as method, COUNT(*) as count").
-		Where("merchant_id = ? AND status = ? AND processed_at >= ? AND processed_at <= ?",
-			merchantID, "completed", startDate, endDate).
-		Group("payment_method").
-		Find(&methodStats).Error
-
-	if err != nil {
-		fmt.Printf("Error getting payment method counts: %v\n", err)
-		return nil, fmt.Errorf("failed to get payment method counts: %w", err)
-	}
-
-	for _, stat := range methodStats {
-		countByType[stat.Method] = stat.Count
-		fmt.Printf("Payment method stat - Method: %s, Count: %d\n",
-			stat.Method, stat.Count)
-	}
-
-	// Get summary stats with more detailed query
-	var summary struct {
-		TotalVolume        float64
-		AverageTransaction float64
-		TotalCount         int64
-	}
-
-	err = s.db.Model(&models.Transaction{}).
-		Where("merchant_id = ? AND status = ? AND processed_at >= ? AND processed_at <= ?",
-			merchantID, "completed", startDate, endDate).
-		Select(`
-			COALESCE(SUM(amount), 0) as total_volume,
-			CASE 
-				WHEN COUNT(*) > 0 THEN COALESCE(SUM(amount), 0) / COUNT(*)
-				ELSE 0
-			END as average_transaction,
-			COUNT(*) as total_count
-		`).
-		Scan(&summary).Error
-
-	if err != nil {
-		fmt.Printf("Error getting summary stats: %v\n", err)
-		return nil, fmt.Errorf("failed to get summary stats: %w", err)
-	}
-
-	// Debug summary results
-	fmt.Printf("Summary stats for merchant %d:\n", merchantID)
-	fmt.Printf("- Total volume: %.2f\n", summary.TotalVolume)
-	fmt.Printf("- Average transaction: %.2f\n", summary.AverageTransaction)
-	fmt.Printf("- Total count: %d\n", summary.TotalCount)
-	fmt.Printf("- Volume over time entries: %d\n", len(volumeOverTime))
-	fmt.Printf("- Payment methods found: %d\n", len(countByType))
-
-	// Check if we have any data
-	if summary.TotalCount == 0 {
-		fmt.Printf("WARNING: No transactions found for merchant %d in date range\n", merchantID)
-		// Double check the date range
-		fmt.Printf("Date range check - Start: %v, End: %v\n", startDate, endDate)
-	}
-
-	return map[string]interface{}{
-		"volume_over_time": volumeOverTime,
-		"count_by_type":    countByType,
-		"summary": map[string]interface{}{
-			"total_volume":        summary.TotalVolume,
-			"average_transaction": summary.AverageTransaction,
-			"total_count":         summary.TotalCount,
-		},
-	}, nil
+	return math.Round(volume/float64(count)*100) / 100
 }
@@ -0,0 +1,15 @@
+package deviceconfirm
+
+import "errors"
+
+// Service errors
+var (
+	ErrTrustedDeviceRequired = errors.New("transfers of this size must be approved on a trusted device; register one first")
+	ErrDeviceIDRequired      = errors.New("the X-Device-ID header is required")
+	ErrUnknownDevice         = errors.New("sign in on this device before trusting it")
+	ErrDeviceNotTrusted      = errors.New("transfers can only be approved or denied on one of your trusted devices")
+	ErrDeviceNotFound        = errors.New("trusted device not found")
+	ErrConfirmationNotFound  = errors.New("transfer confirmation not found")
+	ErrConfirmationClosed    = errors.New("transfer is no longer awaiting confirmation")
+	ErrConfirmationExpired   = errors.New("the time to approve this transfer has passed")
+)
@@ -0,0 +1,42 @@
+package deviceconfirm
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// TransactionService completes approved transfers
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
+
+// Notifier pushes approval requests to trusted devices
+type Notifier interface {
+	SendTransferApprovalRequest(ctx context.Context, device *models.TrustedDevice, tx *models.Transaction, expiresAt time.Time) error
+}
+
+// Service holds large transfers until their sender approves them on a
+// trusted device
+type Service interface {
+	ListDevices(ctx context.Context, userID uint) ([]models.TrustedDevice, error)
+	// RegisterDevice trusts the device the user is signed in on; it must
+	// have completed a login
+	RegisterDevice(ctx context.Context, userID uint, deviceID string, req DeviceRequest) (*models.TrustedDevice, error)
+	RemoveDevice(ctx context.Context, userID, id uint) error
+
+	// Hold records a transfer at or above the threshold as awaiting
+	// confirmation instead of processing it, and asks the sender's trusted
+	// devices to approve it. It reports whether the transfer was held.
+	Hold(ctx context.Context, tx *models.Transaction) (bool, error)
+	// ListPending returns the user's transfers waiting for approval
+	ListPending(ctx context.Context, userID uint) ([]models.TransferConfirmation, error)
+	// Approve completes a held transfer from one of the user's trusted
+	// devices
+	Approve(ctx context.Context, userID uint, deviceID string, id uint) (*models.TransferConfirmation, error)
+	// Deny cancels a held transfer from one of the user's trusted devices
+	Deny(ctx context.Context, userID uint, deviceID string, id uint) (*models.TransferConfirmation, error)
+	// ExpireDue cancels the transfers not approved in time and returns how
+	// many it cancelled
+	ExpireDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,24 @@
+package deviceconfirm
+
+import (
+	"context"
+	"log"
+)
+
+// Job cancels large transfers not approved in time from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the device confirmation service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "transfer-confirmations" }
+
+func (j *Job) Run(ctx context.Context) error {
+	expired, err := j.service.ExpireDue(ctx)
+	if expired > 0 {
+		log.Printf("Expired %d transfers awaiting device confirmation", expired)
+	}
+	return err
+}
@@ -0,0 +1,244 @@
+package deviceconfirm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+type service struct {
+	repo         repositories.TrustedDeviceRepository
+	logins       repositories.LoginEventRepository
+	transactions TransactionService
+	notifier     Notifier
+	config       Config
+}
+
+// NewService creates the trusted device confirmation service
+func NewService(
+	repo repositories.TrustedDeviceRepository,
+	logins repositories.LoginEventRepository,
+	transactions TransactionService,
+	notifier Notifier,
+	config Config,
+) Service {
+	return &service{
+		repo:         repo,
+		logins:       logins,
+		transactions: transactions,
+		notifier:     notifier,
+		config:       config,
+	}
+}
+
+func (s *service) ListDevices(ctx context.Context, userID uint) ([]models.TrustedDevice, error) {
+	return s.repo.ListDevices(userID)
+}
+
+func (s *service) RegisterDevice(ctx context.Context, userID uint, deviceID string, req DeviceRequest) (*models.TrustedDevice, error) {
+	if deviceID == "" {
+		return nil, ErrDeviceIDRequired
+	}
+	// A device is only as trusted as the login it went through, which was
+	// challenged if the device was new
+	known, err := s.logins.KnownDevice(ctx, userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !known {
+		return nil, ErrUnknownDevice
+	}
+
+	device := &models.TrustedDevice{
+		UserID:    userID,
+		DeviceID:  deviceID,
+		Name:      req.Name,
+		PushToken: req.PushToken,
+	}
+	if err := s.repo.SaveDevice(device); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDevice(userID, deviceID)
+}
+
+func (s *service) RemoveDevice(ctx context.Context, userID, id uint) error {
+	err := s.repo.DeleteDevice(userID, id)
+	if errors.Is(err, repositories.ErrTrustedDeviceNotFound) {
+		return ErrDeviceNotFound
+	}
+	return err
+}
+
+func (s *service) Hold(ctx context.Context, tx *models.Transaction) (bool, error) {
+	if s.config.Threshold <= 0 || tx.Amount < s.config.Threshold {
+		return false, nil
+	}
+	devices, err := s.repo.ListDevices(tx.SenderID)
+	if err != nil {
+		return false, err
+	}
+	if len(devices) == 0 {
+		return false, ErrTrustedDeviceRequired
+	}
+
+	tx.Status = models.TransactionStatusAwaitingConfirmation
+	confirmation := &models.TransferConfirmation{
+		UserID:    tx.SenderID,
+		Status:    models.TransferConfirmationPending,
+		ExpiresAt: time.Now().Add(s.config.Window),
+	}
+	if err := s.repo.Hold(tx, confirmation); err != nil {
+		return false, err
+	}
+
+	for i := range devices {
+		if err := s.notifier.SendTransferApprovalRequest(ctx, &devices[i], tx, confirmation.ExpiresAt); err != nil {
+			log.Printf("Failed to push approval of transfer %d to device %d: %v", tx.ID, devices[i].ID, err)
+		}
+	}
+	return true, nil
+}
+
+func (s *service) ListPending(ctx context.Context, userID uint) ([]models.TransferConfirmation, error) {
+	return s.repo.ListPending(userID, time.Now())
+}
+
+func (s *service) Approve(ctx context.Context, userID uint, deviceID string, id uint) (*models.TransferConfirmation, error) {
+	device, err := s.trusted(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	confirmation, err := s.pending(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.repo.Approve(id, deviceID, now); err != nil {
+		if errors.Is(err, repositories.ErrConfirmationNotPending) {
+			return nil, ErrConfirmationClosed
+		}
+		return nil, err
+	}
+	s.touch(device, now)
+
+	// Checks run again as the funds move, so a transfer the sender can no
+	// longer afford or a limit now refuses fails here
+	if _, err := s.transactions.ProcessTransaction(ctx, confirmation.Transaction); err != nil {
+		if failErr := s.repo.Fail(id, err.Error()); failErr != nil {
+			log.Printf("Failed to record failure of transfer confirmation %d: %v", id, failErr)
+		}
+		return nil, err
+	}
+
+	confirmation.Status = models.TransferConfirmationApproved
+	confirmation.DeviceID = deviceID
+	confirmation.DecidedAt = &now
+	return confirmation, nil
+}
+
+func (s *service) Deny(ctx context.Context, userID uint, deviceID string, id uint) (*models.TransferConfirmation, error) {
+	device, err := s.trusted(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	confirmation, err := s.pending(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.repo.Close(id, models.TransferConfirmationDenied, declinedStatus, deviceID, now); err != nil {
+		if errors.Is(err, repositories.ErrConfirmationNotPending) {
+			return nil, ErrConfirmationClosed
+		}
+		return nil, err
+	}
+	s.touch(device, now)
+
+	confirmation.Status = models.TransferConfirmationDenied
+	confirmation.DeviceID = deviceID
+	confirmation.DecidedAt = &now
+	confirmation.Transaction.Status = declinedStatus
+	return confirmation, nil
+}
+
+func (s *service) ExpireDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	confirmations, err := s.repo.FindExpired(now, expiryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, confirmation := range confirmations {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		err := s.expire(confirmation.ID, now)
+		if errors.Is(err, repositories.ErrConfirmationNotPending) {
+			continue // Decided meanwhile
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// pending loads the user's confirmation still waiting for a decision. One
+// past its window is expired on the spot, in case the job hasn't yet.
+func (s *service) pending(userID, id uint) (*models.TransferConfirmation, error) {
+	confirmation, err := s.repo.GetConfirmation(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTransferConfirmationNotFound) {
+			return nil, ErrConfirmationNotFound
+		}
+		return nil, err
+	}
+	if confirmation.UserID != userID || confirmation.Transaction == nil {
+		return nil, ErrConfirmationNotFound
+	}
+	if confirmation.Status != models.TransferConfirmationPending {
+		return nil, ErrConfirmationClosed
+	}
+
+	now := time.Now()
+	if !now.Before(confirmation.ExpiresAt) {
+		if err := s.expire(id, now); err != nil && !errors.Is(err, repositories.ErrConfirmationNotPending) {
+			return nil, err
+		}
+		return nil, ErrConfirmationExpired
+	}
+	return confirmation, nil
+}
+
+// trusted requires the request to come from one of the user's trusted
+// devices
+func (s *service) trusted(userID uint, deviceID string) (*models.TrustedDevice, error) {
+	if deviceID == "" {
+		return nil, ErrDeviceIDRequired
+	}
+	device, err := s.repo.GetDevice(userID, deviceID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTrustedDeviceNotFound) {
+			return nil, ErrDeviceNotTrusted
+		}
+		return nil, err
+	}
+	return device, nil
+}
+
+func (s *service) expire(id uint, now time.Time) error {
+	return s.repo.Close(id, models.TransferConfirmationExpired, models.TransactionStatusExpired, "", now)
+}
+
+func (s *service) touch(device *models.TrustedDevice, at time.Time) {
+	if err := s.repo.TouchDevice(device.ID, at); err != nil {
+		log.Printf("Failed to update trusted device %d: %v", device.ID, err)
+	}
+}
@@ -0,0 +1,23 @@
+package deviceconfirm
+
+import "time"
+
+// Config decides which transfers wait for device approval and for how long
+type Config struct {
+	// Threshold is the amount from which a transfer must be approved;
+	// zero turns approval off
+	Threshold float64
+	Window    time.Duration
+}
+
+// DeviceRequest registers the device the request is made from as trusted
+type DeviceRequest struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	PushToken string `json:"push_token" validate:"max=512"`
+}
+
+// expiryBatch is how many lapsed confirmations are expired at a time
+const expiryBatch = 200
+
+// declinedStatus is what a denied transfer is left as
+const declinedStatus = "declined"
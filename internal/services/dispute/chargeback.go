@@ -0,0 +1,248 @@
+package dispute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+// OpenChargeback charges a disputed payment back to the merchant. The amount
+// is reserved on their wallet until the chargeback settles, and the
+// chargeback fee is taken straight away.
+func (s *Service) OpenChargeback(ctx context.Context, adminID, disputeID uint, req OpenChargebackRequest) (*models.Chargeback, error) {
+	dispute, err := s.get(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Source == models.DisputeSourceEscrow {
+		return nil, ErrEscrowDispute
+	}
+	if !dispute.IsOpen() {
+		return nil, ErrDisputeClosed
+	}
+	merchantID := dispute.CounterpartyID()
+	if merchantID == 0 {
+		return nil, ErrNoCounterparty
+	}
+
+	tx, err := s.transactionRepo.FindByID(dispute.TransactionID)
+	if err != nil {
+		return nil, errors.New("transaction not found")
+	}
+	amount := dispute.AmountInDispute(tx.Amount)
+	if req.Amount != nil {
+		amount = math.Round(*req.Amount*100) / 100
+	}
+	if amount <= 0 || amount > tx.Amount {
+		return nil, ErrInvalidChargebackAmount
+	}
+
+	now := time.Now()
+	chargeback := &models.Chargeback{
+		DisputeID:          dispute.ID,
+		TransactionID:      tx.ID,
+		MerchantUserID:     merchantID,
+		CustomerID:         dispute.UserID,
+		Amount:             amount,
+		Fee:                math.Round(s.config.ChargebackFee*100) / 100,
+		Currency:           tx.Currency,
+		ReasonCode:         strings.TrimSpace(req.ReasonCode),
+		RepresentmentDueAt: now.Add(s.config.RepresentmentWindow),
+		OpenedBy:           adminID,
+	}
+
+	var fee *models.Transaction
+	if chargeback.Fee > 0 {
+		fee = &models.Transaction{
+			Type:          "fee",
+			SenderID:      merchantID,
+			Amount:        chargeback.Fee,
+			Currency:      tx.Currency,
+			Status:        "completed",
+			TransactionID: fmt.Sprintf("CBK-FEE-%d-%d", dispute.ID, now.UnixNano()),
+			Reference:     fmt.Sprintf("%d", tx.ID),
+			PaymentType:   "chargeback_fee",
+			Category:      "Fee",
+			Description:   fmt.Sprintf("Chargeback fee for dispute #%d", dispute.ID),
+			ProcessedAt:   now,
+		}
+	}
+
+	if err := s.chargebacks.Open(chargeback, fee); err != nil {
+		if errors.Is(err, repositories.ErrDisputeNotChargeable) {
+			return nil, ErrDisputeClosed
+		}
+		return nil, err
+	}
+	s.invalidate(ctx, merchantID)
+	return chargeback, nil
+}
+
+// GetChargeback returns a chargeback and its representment evidence to the
+// merchant, the customer or an admin
+func (s *Service) GetChargeback(userID uint, isAdmin bool, chargebackID uint) (*ChargebackDetail, error) {
+	chargeback, err := s.chargeback(chargebackID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && chargeback.MerchantUserID != userID && chargeback.CustomerID != userID {
+		return nil, ErrChargebackNotFound
+	}
+
+	all, err := s.repo.ListEvidence(chargeback.DisputeID)
+	if err != nil {
+		return nil, err
+	}
+	evidence := make([]models.DisputeEvidence, 0, len(all))
+	for _, e := range all {
+		if e.ChargebackID != nil && *e.ChargebackID == chargeback.ID {
+			evidence = append(evidence, e)
+		}
+	}
+	return &ChargebackDetail{Chargeback: chargeback, Evidence: evidence}, nil
+}
+
+// ListChargebacks returns chargebacks against a merchant, or every
+// chargeback when merchantUserID is 0
+func (s *Service) ListChargebacks(merchantUserID uint, status string, limit, offset int) ([]models.Chargeback, int64, error) {
+	return s.chargebacks.List(merchantUserID, status, limit, offset)
+}
+
+// Represent records the merchant's contest of a chargeback. Supporting
+// files are uploaded as evidence on the dispute.
+func (s *Service) Represent(userID, chargebackID uint, note string) (*models.Chargeback, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, ErrRepresentmentRequired
+	}
+	chargeback, err := s.chargeback(chargebackID)
+	if err != nil {
+		return nil, err
+	}
+	if chargeback.MerchantUserID != userID {
+		if chargeback.CustomerID == userID {
+			return nil, ErrNotCounterparty
+		}
+		return nil, ErrChargebackNotFound
+	}
+	if !chargeback.IsActive() {
+		return nil, ErrChargebackClosed
+	}
+	if chargeback.Status == models.ChargebackStatusRepresented {
+		return nil, ErrAlreadyRepresented
+	}
+	now := time.Now()
+	if now.After(chargeback.RepresentmentDueAt) {
+		return nil, ErrRepresentmentClosed
+	}
+
+	ok, err := s.chargebacks.Represent(chargeback.ID, note, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Settled or represented since we looked
+		return nil, ErrChargebackClosed
+	}
+	return s.chargeback(chargeback.ID)
+}
+
+// SettleChargeback records an admin's decision. A won chargeback releases
+// the reserve back to the merchant; a lost one pays it to the customer.
+func (s *Service) SettleChargeback(ctx context.Context, adminID, chargebackID uint, req SettleChargebackRequest) (*models.Chargeback, error) {
+	if req.Outcome != models.ChargebackStatusWon && req.Outcome != models.ChargebackStatusLost {
+		return nil, ErrInvalidChargebackOutcome
+	}
+	return s.settle(ctx, chargebackID, req.Outcome, &adminID, strings.TrimSpace(req.Note))
+}
+
+// ProcessOverdueChargebacks settles chargebacks the merchant didn't contest
+// before the representment deadline against them
+func (s *Service) ProcessOverdueChargebacks(ctx context.Context) (int, error) {
+	overdue, err := s.chargebacks.GetOverdue(time.Now(), overdueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, chargeback := range overdue {
+		if err := ctx.Err(); err != nil {
+			return settled, err
+		}
+		_, err := s.settle(ctx, chargeback.ID, models.ChargebackStatusLost, nil, "Representment deadline passed")
+		if errors.Is(err, ErrChargebackClosed) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to settle overdue chargeback %d: %v", chargeback.ID, err)
+			continue
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+func (s *Service) settle(ctx context.Context, chargebackID uint, outcome string, resolvedBy *uint, note string) (*models.Chargeback, error) {
+	chargeback, err := s.chargeback(chargebackID)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := repositories.ChargebackSettlement{
+		ChargebackID: chargeback.ID,
+		Status:       outcome,
+		ResolvedBy:   resolvedBy,
+		Note:         note,
+	}
+	if outcome == models.ChargebackStatusLost {
+		now := time.Now()
+		settlement.Transaction = &models.Transaction{
+			Type:          models.TransactionTypeChargeback,
+			SenderID:      chargeback.MerchantUserID,
+			ReceiverID:    chargeback.CustomerID,
+			Amount:        chargeback.Amount,
+			Currency:      chargeback.Currency,
+			Status:        "completed",
+			TransactionID: fmt.Sprintf("CBK-%d-%d", chargeback.ID, now.UnixNano()),
+			Reference:     fmt.Sprintf("%d", chargeback.TransactionID),
+			PaymentType:   "chargeback",
+			Category:      "Refund",
+			Description:   fmt.Sprintf("Chargeback #%d", chargeback.ID),
+			ProcessedAt:   now,
+		}
+	}
+
+	settled, err := s.chargebacks.Settle(settlement)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrChargebackNotFound):
+			return nil, ErrChargebackNotFound
+		case errors.Is(err, repositories.ErrChargebackNotActive):
+			return nil, ErrChargebackClosed
+		}
+		return nil, err
+	}
+
+	s.invalidate(ctx, chargeback.MerchantUserID)
+	if outcome == models.ChargebackStatusLost {
+		s.invalidate(ctx, chargeback.CustomerID)
+	}
+	return settled, nil
+}
+
+func (s *Service) chargeback(id uint) (*models.Chargeback, error) {
+	chargeback, err := s.chargebacks.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrChargebackNotFound) {
+			return nil, ErrChargebackNotFound
+		}
+		return nil, err
+	}
+	return chargeback, nil
+}
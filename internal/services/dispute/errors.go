@@ -0,0 +1,33 @@
+package dispute
+
+import "errors"
+
+// Service errors
+var (
+	ErrDisputeNotFound    = errors.New("dispute not found")
+	ErrEvidenceNotFound   = errors.New("dispute evidence not found")
+	ErrNotCounterparty    = errors.New("only the merchant can respond to this dispute")
+	ErrDisputeClosed      = errors.New("dispute has already been resolved")
+	ErrInvalidTransition  = errors.New("dispute is not in a state that allows this")
+	ErrEmptyFile          = errors.New("evidence file is empty")
+	ErrFileTooLarge       = errors.New("evidence file is too large")
+	ErrFileType           = errors.New("evidence must be a PDF, PNG or JPEG file")
+	ErrTooMuchEvidence    = errors.New("evidence limit reached for this dispute")
+	ErrResponseRequired   = errors.New("response is required")
+	ErrInvalidDeadline    = errors.New("response deadline is out of range")
+	ErrInvalidOutcome     = errors.New("outcome must be customer or merchant")
+	ErrInvalidRefund      = errors.New("refund amount must be positive and no more than the transaction amount")
+	ErrNoCounterparty     = errors.New("dispute has no counterparty to refund from")
+	ErrEscrowDispute      = errors.New("escrow disputes are settled through the escrow")
+	ErrInvalidCategory    = errors.New("category must be unauthorized, not_received, duplicate or other")
+	ErrInvalidAmount      = errors.New("disputed amount must be positive and no more than the transaction amount")
+	ErrTooManyAttachments = errors.New("a dispute can reference at most 10 attachments")
+
+	ErrChargebackNotFound       = errors.New("chargeback not found")
+	ErrChargebackClosed         = errors.New("chargeback has already been settled")
+	ErrAlreadyRepresented       = errors.New("chargeback has already been represented")
+	ErrRepresentmentClosed      = errors.New("representment deadline has passed")
+	ErrRepresentmentRequired    = errors.New("representment note is required")
+	ErrInvalidChargebackAmount  = errors.New("chargeback amount must be positive and no more than the transaction amount")
+	ErrInvalidChargebackOutcome = errors.New("outcome must be won or lost")
+)
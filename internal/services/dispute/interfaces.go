@@ -0,0 +1,12 @@
+package dispute
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// TransactionService moves refund money from the merchant's wallet to the
+// customer's when a dispute is decided for the customer
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
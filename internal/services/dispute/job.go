@@ -0,0 +1,34 @@
+package dispute
+
+import (
+	"context"
+	"log"
+)
+
+// Job enforces dispute deadlines: disputes whose merchant missed the
+// evidence deadline go under review, and chargebacks that weren't contested
+// in time are settled against the merchant
+type Job struct {
+	service *Service
+}
+
+// NewJob wraps the dispute service as a scheduled job
+func NewJob(s *Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "dispute-deadlines" }
+
+func (j *Job) Run(ctx context.Context) error {
+	moved, err := j.service.ProcessOverdueResponses(ctx)
+	if moved > 0 {
+		log.Printf("Moved %d disputes with missed response deadlines to review", moved)
+	}
+	if err != nil {
+		return err
+	}
+
+	settled, err := j.service.ProcessOverdueChargebacks(ctx)
+	if settled > 0 {
+		log.Printf("Settled %d uncontested chargebacks", settled)
+	}
+	return err
+}
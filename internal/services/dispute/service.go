@@ -1,25 +1,61 @@
 package dispute
 
 import (
+	"context"
 	"errors"
+	"log"
+	"math"
 	"orus/internal/models"
 	"orus/internal/repositories"
-
-	"gorm.io/gorm"
+	"orus/internal/repositories/cache"
+	"orus/internal/utils/storage"
+	"slices"
+	"time"
 )
 
 type Service struct {
 	repo            repositories.DisputeRepository
 	transactionRepo repositories.TransactionRepository
-	userRepo        repositories.UserRepository
-	db              *gorm.DB
+	chargebacks     repositories.ChargebackRepository
+	files           storage.Storage
+	payments        TransactionService
+	cache           *cache.CacheService
+	config          Config
 }
 
-func NewService(repo repositories.DisputeRepository, transactionRepo repositories.TransactionRepository, userRepo repositories.UserRepository, db *gorm.DB) *Service {
-	return &Service{repo: repo, transactionRepo: transactionRepo, userRepo: userRepo, db: db}
+func NewService(
+	repo repositories.DisputeRepository,
+	transactionRepo repositories.TransactionRepository,
+	chargebacks repositories.ChargebackRepository,
+	files storage.Storage,
+	payments TransactionService,
+	cache *cache.CacheService,
+	config Config,
+) *Service {
+	return &Service{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		chargebacks:     chargebacks,
+		files:           files,
+		payments:        payments,
+		cache:           cache,
+		config:          config,
+	}
 }
 
-func (s *Service) FileDispute(transactionID, userID uint, reason string) (*models.Dispute, error) {
+func (s *Service) FileDispute(userID uint, req FileRequest) (*models.Dispute, error) {
+	transactionID := req.TransactionID
+	category := req.Category
+	if category == "" {
+		category = models.DisputeCategoryOther
+	}
+	if !slices.Contains(models.DisputeCategories, category) {
+		return nil, ErrInvalidCategory
+	}
+	if len(req.Attachments) > MaxAttachments {
+		return nil, ErrTooManyAttachments
+	}
+
 	// Retrieve the transaction to check user involvement
 	transaction, err := s.transactionRepo.FindByID(transactionID)
 	if err != nil {
@@ -36,6 +72,16 @@ func (s *Service) FileDispute(transactionID, userID uint, reason string) (*model
 		return nil, errors.New("transaction is not associated with a merchant")
 	}
 
+	// A partial dispute covers part of the payment, such as one item of
+	// an order
+	amount := transaction.Amount
+	if req.Amount != nil {
+		amount = math.Round(*req.Amount*100) / 100
+	}
+	if amount <= 0 || amount > transaction.Amount {
+		return nil, ErrInvalidAmount
+	}
+
 	// Check if a dispute already exists for this transaction
 	exists, err := s.repo.ExistsByTransactionID(transactionID)
 	if err != nil {
@@ -53,12 +99,23 @@ func (s *Service) FileDispute(transactionID, userID uint, reason string) (*model
 		return nil, errors.New("a dispute has already been filed for this transaction")
 	}
 
+	// The counterparty is whichever side of the transaction didn't file
+	counterparty := transaction.ReceiverID
+	if transaction.ReceiverID == userID {
+		counterparty = transaction.SenderID
+	}
+
 	// Create the dispute
 	dispute := &models.Dispute{
-		TransactionID: transactionID,
-		MerchantID:    *transaction.MerchantID,
-		UserID:        userID,
-		Reason:        reason,
+		TransactionID:  transactionID,
+		MerchantID:     *transaction.MerchantID,
+		MerchantUserID: counterparty,
+		UserID:         userID,
+		Reason:         req.Reason,
+		Category:       category,
+		DisputedAmount: amount,
+		Attachments:    req.Attachments,
+		Status:         models.DisputeStatusOpen,
 	}
 
 	if err := s.repo.Create(dispute); err != nil {
@@ -67,139 +124,96 @@ func (s *Service) FileDispute(transactionID, userID uint, reason string) (*model
 	return dispute, nil
 }
 
-func (s *Service) GetDisputes(merchantID uint) ([]models.Dispute, error) {
-	return s.repo.FindByMerchantID(merchantID)
-}
-
-func (s *Service) GetMerchantDisputes(merchantID uint) ([]models.Dispute, error) {
-	return s.repo.FindByMerchantID(merchantID)
+// OpenEscrowDispute files a dispute over an escrow payment raised by either
+// party. MerchantID is the seller so it shows up in their dispute list.
+func (s *Service) OpenEscrowDispute(fundingTransactionID, raisedBy, sellerID uint, reason string) (*models.Dispute, error) {
+	dispute := &models.Dispute{
+		TransactionID:  fundingTransactionID,
+		MerchantID:     sellerID,
+		MerchantUserID: sellerID,
+		UserID:         raisedBy,
+		Reason:         reason,
+		Status:         models.DisputeStatusOpen,
+		Source:         models.DisputeSourceEscrow,
+	}
+	if err := s.repo.Create(dispute); err != nil {
+		return nil, err
+	}
+	return dispute, nil
 }
 
-func (s *Service) ProcessRefund(disputeID uint) error {
-	// Check if the dispute exists
+// CloseEscrowDispute records how the escrow behind a dispute was settled
+func (s *Service) CloseEscrowDispute(disputeID uint, refunded bool) error {
 	dispute, err := s.repo.FindByID(disputeID)
 	if err != nil {
 		return errors.New("dispute not found")
 	}
+	now := time.Now()
+	dispute.Status = models.DisputeStatusResolved
+	dispute.Refunded = refunded
+	dispute.ResolvedAt = &now
+	return s.repo.Update(dispute)
+}
 
-	// Check if the dispute is already refunded
-	if dispute.Refunded {
-		return errors.New("dispute has already been refunded")
-	}
-
-	// Retrieve the transaction associated with the dispute
-	transaction, err := s.transactionRepo.FindByID(dispute.TransactionID)
-	if err != nil {
-		return errors.New("transaction not found")
-	}
-
-	// Determine the roles
-	var senderID, receiverID uint
-	if transaction.SenderID == dispute.UserID {
-		senderID = transaction.SenderID     // Customer
-		receiverID = transaction.ReceiverID // Merchant
-	} else {
-		senderID = transaction.ReceiverID // Customer
-		receiverID = transaction.SenderID // Merchant
-	}
-
-	// Start a transaction
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		// Credit to the customer
-		if err := s.updateUserBalance(receiverID, transaction.Amount); err != nil {
-			return err
-		}
-
-		// Deduct from the merchant
-		if err := s.updateUserBalance(senderID, -transaction.Amount); err != nil {
-			return err
-		}
-
-		// Update the dispute to mark it as refunded
-		dispute.Refunded = true
-		if err := s.repo.Update(dispute); err != nil {
-			return err
-		}
-
-		// Record the refund transaction (optional)
-		refundTransaction := &models.Transaction{
-			SenderID:   senderID,
-			ReceiverID: receiverID,
-			Amount:     transaction.Amount,
-			Status:     "completed", // or "refunded"
-			Type:       "REFUND",    // Indicate this is a refund transaction
-		}
-		if err := s.transactionRepo.CreateTransaction(refundTransaction); err != nil {
-			return err
-		}
+// ListMine pages through the disputes the user filed, newest first,
+// optionally only those in status
+func (s *Service) ListMine(userID uint, status string, limit, offset int) ([]models.Dispute, int64, error) {
+	return s.repo.ListByUserID(userID, status, limit, offset)
+}
 
-		return nil
-	})
+func (s *Service) GetDisputes(merchantID uint) ([]models.Dispute, error) {
+	return s.repo.FindByMerchantID(merchantID)
+}
 
-	return err
+func (s *Service) GetMerchantDisputes(merchantID uint) ([]models.Dispute, error) {
+	return s.repo.FindByMerchantID(merchantID)
 }
 
-func (s *Service) ProcessChargeback(disputeID uint) error {
-	// Check if the dispute exists
-	dispute, err := s.repo.FindByID(disputeID)
+// ProcessRefund lets the merchant settle an open dispute by refunding
+// what the customer disputed, without waiting for arbitration
+func (s *Service) ProcessRefund(ctx context.Context, merchantUserID, disputeID uint) (*models.Dispute, error) {
+	dispute, err := s.get(disputeID)
 	if err != nil {
-		return errors.New("dispute not found")
+		return nil, err
 	}
-
-	// Check if the dispute is already processed
-	if dispute.Status != "pending" {
-		return errors.New("dispute cannot be charged back")
+	if dispute.Source == models.DisputeSourceEscrow {
+		return nil, ErrEscrowDispute
+	}
+	if dispute.CounterpartyID() != merchantUserID {
+		return nil, ErrNotCounterparty
+	}
+	if !dispute.IsOpen() {
+		return nil, ErrDisputeClosed
 	}
 
-	// Retrieve the transaction associated with the dispute
-	transaction, err := s.transactionRepo.FindByID(dispute.TransactionID)
+	tx, err := s.transactionRepo.FindByID(dispute.TransactionID)
 	if err != nil {
-		return errors.New("transaction not found")
+		return nil, errors.New("transaction not found")
 	}
+	amount := dispute.AmountInDispute(tx.Amount)
 
-	// Start a transaction
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		// Update the transaction status to chargeback
-		transaction.Status = "chargeback"
-		if err := s.transactionRepo.Update(transaction); err != nil {
-			return err
-		}
-
-		// Adjust the balances
-		if err := s.updateUserBalance(transaction.ReceiverID, -transaction.Amount); err != nil {
-			return err
-		}
-		if err := s.updateUserBalance(transaction.SenderID, transaction.Amount); err != nil {
-			return err
-		}
-
-		// Update the dispute status
-		dispute.Status = "charged_back"
-		if err := s.repo.Update(dispute); err != nil {
-			return err
-		}
-
-		return nil
+	// Claim the dispute before moving money so it can't be refunded twice
+	now := time.Now()
+	claimed, err := s.transition(disputeID, []string{dispute.Status}, map[string]interface{}{
+		"status":          models.DisputeStatusResolved,
+		"resolution":      models.DisputeResolutionCustomer,
+		"resolution_note": "Refunded by the merchant",
+		"resolved_at":     now,
 	})
-
-	return err
-}
-
-// Example method to update user balance
-func (s *Service) updateUserBalance(userID uint, amount float64) error {
-	// Retrieve the current balance
-	currentBalance, err := s.userRepo.GetBalance(userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Calculate the new balance
-	newBalance := currentBalance + amount
+	return s.refund(ctx, claimed, amount, now, map[string]interface{}{
+		"status":          dispute.Status,
+		"resolution":      "",
+		"resolution_note": "",
+		"resolved_at":     nil,
+	})
+}
 
-	// Update the user's balance in the database
-	if err := s.userRepo.UpdateBalance(userID, newBalance); err != nil {
-		return err
+func (s *Service) invalidate(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, s.cache.GenerateKey("wallet", "user", userID)); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
 	}
-
-	return nil
 }
@@ -0,0 +1,72 @@
+package dispute
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// Config holds the dispute and chargeback deadlines and fees
+type Config struct {
+	// ResponseWindow is how long a merchant gets to answer an evidence request
+	ResponseWindow time.Duration
+	// RepresentmentWindow is how long a merchant gets to contest a
+	// chargeback before it is settled against them
+	RepresentmentWindow time.Duration
+	// ChargebackFee is charged to the merchant when a chargeback opens
+	ChargebackFee float64
+}
+
+// MaxAttachments bounds the attachment references filed with a dispute
+const MaxAttachments = 10
+
+// FileRequest files a dispute over a merchant payment. Category defaults
+// to other and Amount to the full transaction amount.
+type FileRequest struct {
+	TransactionID uint     `json:"transaction_id" validate:"required"`
+	Reason        string   `json:"reason" validate:"max=1000"`
+	Category      string   `json:"category" validate:"omitempty,oneof=unauthorized not_received duplicate other"`
+	Amount        *float64 `json:"amount" validate:"omitempty,gt=0"`
+	Attachments   []string `json:"attachments" validate:"max=10,dive,required,max=500"`
+}
+
+// EvidenceUpload is a file submitted as dispute evidence
+type EvidenceUpload struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+	Description string
+}
+
+// Detail is a dispute with its evidence
+type Detail struct {
+	Dispute    *models.Dispute          `json:"dispute"`
+	Evidence   []models.DisputeEvidence `json:"evidence"`
+	Chargeback *models.Chargeback       `json:"chargeback,omitempty"`
+}
+
+// ChargebackDetail is a chargeback with its representment evidence
+type ChargebackDetail struct {
+	Chargeback *models.Chargeback       `json:"chargeback"`
+	Evidence   []models.DisputeEvidence `json:"evidence"`
+}
+
+// OpenChargebackRequest records a chargeback against a disputed payment.
+// Amount defaults to the disputed amount.
+type OpenChargebackRequest struct {
+	Amount     *float64 `json:"amount"`
+	ReasonCode string   `json:"reason_code"`
+}
+
+// SettleChargebackRequest is an admin's decision on a chargeback
+type SettleChargebackRequest struct {
+	Outcome string `json:"outcome"` // won or lost, from the merchant's side
+	Note    string `json:"note"`
+}
+
+// ResolveRequest is an admin's arbitration decision. RefundAmount defaults
+// to the disputed amount when deciding for the customer.
+type ResolveRequest struct {
+	Outcome      string   `json:"outcome"`
+	RefundAmount *float64 `json:"refund_amount"`
+	Note         string   `json:"note"`
+}
@@ -0,0 +1,418 @@
+package dispute
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxEvidenceSize caps a single evidence file
+	MaxEvidenceSize = 10 << 20
+	// MaxEvidenceFiles caps how many files can be attached to one dispute
+	MaxEvidenceFiles = 20
+	// MaxResponseDays caps the deadline an admin can give a merchant
+	MaxResponseDays = 30
+
+	overdueBatchSize = 100
+)
+
+// evidenceTypes maps accepted evidence content types to file extensions
+var evidenceTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+}
+
+// Get returns a dispute with its evidence to one of its parties, or to an admin
+func (s *Service) Get(userID uint, isAdmin bool, disputeID uint) (*Detail, error) {
+	dispute, err := s.authorize(userID, isAdmin, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	evidence, err := s.repo.ListEvidence(dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &Detail{Dispute: dispute, Evidence: evidence}
+	if dispute.Status == models.DisputeStatusChargedBack {
+		chargeback, err := s.chargebacks.GetByDisputeID(dispute.ID)
+		if err != nil && !errors.Is(err, repositories.ErrChargebackNotFound) {
+			return nil, err
+		}
+		detail.Chargeback = chargeback
+	}
+	return detail, nil
+}
+
+// List returns disputes for admin review, newest first
+func (s *Service) List(status string, limit, offset int) ([]models.Dispute, int64, error) {
+	return s.repo.List(status, limit, offset)
+}
+
+// AddEvidence stores a file and attaches it to the dispute. Either party
+// and admins can add evidence until the dispute is resolved. Once charged
+// back, only the merchant and admins can add evidence, as representment,
+// until the chargeback settles.
+func (s *Service) AddEvidence(ctx context.Context, userID uint, isAdmin bool, disputeID uint, upload EvidenceUpload) (*models.DisputeEvidence, error) {
+	dispute, err := s.authorize(userID, isAdmin, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	party := partyOf(dispute, userID)
+
+	var chargebackID *uint
+	if !dispute.IsOpen() {
+		chargeback, err := s.representable(dispute, party)
+		if err != nil {
+			return nil, err
+		}
+		chargebackID = &chargeback.ID
+	}
+
+	if len(upload.Content) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if len(upload.Content) > MaxEvidenceSize {
+		return nil, ErrFileTooLarge
+	}
+	// Trust the bytes, not the client's Content-Type header
+	contentType := http.DetectContentType(upload.Content)
+	ext, ok := evidenceTypes[contentType]
+	if !ok {
+		return nil, ErrFileType
+	}
+
+	existing, err := s.repo.ListEvidence(dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxEvidenceFiles {
+		return nil, ErrTooMuchEvidence
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate evidence key: %w", err)
+	}
+	key := fmt.Sprintf("disputes/%d/%d-%s%s", dispute.ID, time.Now().Unix(), hex.EncodeToString(suffix), ext)
+	if err := s.files.Put(ctx, key, upload.Content, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store evidence: %w", err)
+	}
+
+	evidence := &models.DisputeEvidence{
+		DisputeID:    dispute.ID,
+		ChargebackID: chargebackID,
+		UploadedBy:   userID,
+		Party:        party,
+		FileName:     cleanFileName(upload.FileName, ext),
+		ContentType:  contentType,
+		Size:         int64(len(upload.Content)),
+		StorageKey:   key,
+		Description:  strings.TrimSpace(upload.Description),
+	}
+	if err := s.repo.AddEvidence(evidence); err != nil {
+		if delErr := s.files.Delete(ctx, key); delErr != nil {
+			log.Printf("Failed to remove orphaned evidence %s: %v", key, delErr)
+		}
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// GetEvidenceFile returns an evidence record and its content
+func (s *Service) GetEvidenceFile(ctx context.Context, userID uint, isAdmin bool, disputeID, evidenceID uint) (*models.DisputeEvidence, []byte, error) {
+	if _, err := s.authorize(userID, isAdmin, disputeID); err != nil {
+		return nil, nil, err
+	}
+	evidence, err := s.repo.GetEvidence(disputeID, evidenceID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrEvidenceNotFound) {
+			return nil, nil, ErrEvidenceNotFound
+		}
+		return nil, nil, err
+	}
+	content, err := s.files.Get(ctx, evidence.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read evidence: %w", err)
+	}
+	return evidence, content, nil
+}
+
+// Respond records the merchant's answer and puts the dispute under review
+func (s *Service) Respond(userID, disputeID uint, response string) (*models.Dispute, error) {
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return nil, ErrResponseRequired
+	}
+	dispute, err := s.authorize(userID, false, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.CounterpartyID() != userID {
+		return nil, ErrNotCounterparty
+	}
+
+	return s.transition(disputeID, []string{
+		models.DisputeStatusPending,
+		models.DisputeStatusOpen,
+		models.DisputeStatusEvidenceRequested,
+	}, map[string]interface{}{
+		"status":            models.DisputeStatusUnderReview,
+		"merchant_response": response,
+		"responded_at":      time.Now(),
+	})
+}
+
+// RequestEvidence asks the merchant to answer within days
+func (s *Service) RequestEvidence(adminID, disputeID uint, days int) (*models.Dispute, error) {
+	window := s.config.ResponseWindow
+	if days != 0 {
+		if days < 1 || days > MaxResponseDays {
+			return nil, ErrInvalidDeadline
+		}
+		window = time.Duration(days) * 24 * time.Hour
+	}
+
+	dispute, err := s.transition(disputeID, []string{
+		models.DisputeStatusPending,
+		models.DisputeStatusOpen,
+	}, map[string]interface{}{
+		"status":          models.DisputeStatusEvidenceRequested,
+		"response_due_at": time.Now().Add(window),
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d requested evidence on dispute %d, due %s", adminID, disputeID, dispute.ResponseDueAt.Format(time.RFC3339))
+	return dispute, nil
+}
+
+// StartReview moves a dispute under review without waiting for the merchant
+func (s *Service) StartReview(adminID, disputeID uint) (*models.Dispute, error) {
+	return s.transition(disputeID, []string{
+		models.DisputeStatusPending,
+		models.DisputeStatusOpen,
+		models.DisputeStatusEvidenceRequested,
+	}, map[string]interface{}{
+		"status": models.DisputeStatusUnderReview,
+	})
+}
+
+// Resolve records an admin's decision on a dispute under review. Deciding
+// for the customer refunds them, fully or partially, from the merchant.
+func (s *Service) Resolve(ctx context.Context, adminID, disputeID uint, req ResolveRequest) (*models.Dispute, error) {
+	dispute, err := s.get(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Source == models.DisputeSourceEscrow {
+		return nil, ErrEscrowDispute
+	}
+
+	var refund float64
+	switch req.Outcome {
+	case models.DisputeResolutionMerchant:
+	case models.DisputeResolutionCustomer:
+		tx, err := s.transactionRepo.FindByID(dispute.TransactionID)
+		if err != nil {
+			return nil, errors.New("transaction not found")
+		}
+		refund = dispute.AmountInDispute(tx.Amount)
+		if req.RefundAmount != nil {
+			refund = math.Round(*req.RefundAmount*100) / 100
+		}
+		if refund <= 0 || refund > tx.Amount {
+			return nil, ErrInvalidRefund
+		}
+		if dispute.CounterpartyID() == 0 {
+			return nil, ErrNoCounterparty
+		}
+	default:
+		return nil, ErrInvalidOutcome
+	}
+
+	// Claim the dispute before moving money so two admins can't both refund
+	now := time.Now()
+	claimed, err := s.transition(disputeID, []string{models.DisputeStatusUnderReview}, map[string]interface{}{
+		"status":          models.DisputeStatusResolved,
+		"resolution":      req.Outcome,
+		"resolution_note": strings.TrimSpace(req.Note),
+		"resolved_by":     adminID,
+		"resolved_at":     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if refund == 0 {
+		return claimed, nil
+	}
+
+	return s.refund(ctx, dispute, refund, now, map[string]interface{}{
+		"status":          models.DisputeStatusUnderReview,
+		"resolution":      "",
+		"resolution_note": "",
+		"resolved_by":     nil,
+		"resolved_at":     nil,
+	})
+}
+
+// refund pays amount back from the merchant's wallet to the customer's as
+// a refund transaction, through the locked wallet and ledger path every
+// payment takes, and records it on the dispute. The dispute must already
+// be claimed as resolved; if the refund fails, reopen puts it back so the
+// decision can be retried.
+func (s *Service) refund(ctx context.Context, dispute *models.Dispute, amount float64, now time.Time, reopen map[string]interface{}) (*models.Dispute, error) {
+	refundTx, err := s.payments.ProcessTransaction(ctx, &models.Transaction{
+		Type:          models.TransactionTypeRefund,
+		SenderID:      dispute.CounterpartyID(),
+		ReceiverID:    dispute.UserID,
+		Amount:        amount,
+		Description:   fmt.Sprintf("Refund for dispute #%d", dispute.ID),
+		TransactionID: fmt.Sprintf("DSP-REF-%d-%d", dispute.ID, now.UnixNano()),
+		Reference:     fmt.Sprintf("%d", dispute.TransactionID),
+		Category:      "Refund",
+		Metadata: models.NewJSON(map[string]interface{}{
+			"original_transaction_id": dispute.TransactionID,
+			"dispute_id":              dispute.ID,
+			"reason":                  "dispute",
+		}),
+	})
+	if err != nil {
+		if _, revertErr := s.repo.Transition(dispute.ID, []string{models.DisputeStatusResolved}, reopen); revertErr != nil {
+			log.Printf("Failed to reopen dispute %d after refund error: %v", dispute.ID, revertErr)
+		}
+		return nil, fmt.Errorf("failed to refund dispute: %w", err)
+	}
+
+	return s.transition(dispute.ID, []string{models.DisputeStatusResolved}, map[string]interface{}{
+		"refunded":      true,
+		"refund_amount": amount,
+		"refund_tx_id":  refundTx.ID,
+	})
+}
+
+// ProcessOverdueResponses moves disputes whose merchant missed the evidence
+// deadline under review, so an admin can decide without their response
+func (s *Service) ProcessOverdueResponses(ctx context.Context) (int, error) {
+	overdue, err := s.repo.FindOverdueResponses(time.Now(), overdueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, dispute := range overdue {
+		if err := ctx.Err(); err != nil {
+			return moved, err
+		}
+		ok, err := s.repo.Transition(dispute.ID, []string{models.DisputeStatusEvidenceRequested}, map[string]interface{}{
+			"status": models.DisputeStatusUnderReview,
+		})
+		if err != nil {
+			return moved, err
+		}
+		if ok {
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// representable returns the chargeback that evidence on a closed dispute
+// would count towards as representment
+func (s *Service) representable(dispute *models.Dispute, party string) (*models.Chargeback, error) {
+	if dispute.Status != models.DisputeStatusChargedBack || party == models.EvidencePartyCustomer {
+		return nil, ErrDisputeClosed
+	}
+	chargeback, err := s.chargebacks.GetByDisputeID(dispute.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrChargebackNotFound) {
+			return nil, ErrDisputeClosed
+		}
+		return nil, err
+	}
+	if !chargeback.IsActive() {
+		return nil, ErrChargebackClosed
+	}
+	return chargeback, nil
+}
+
+// transition applies a status change and returns the updated dispute
+func (s *Service) transition(disputeID uint, from []string, updates map[string]interface{}) (*models.Dispute, error) {
+	ok, err := s.repo.Transition(disputeID, from, updates)
+	if err != nil {
+		return nil, err
+	}
+	dispute, err := s.get(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if !dispute.IsOpen() {
+			return nil, ErrDisputeClosed
+		}
+		return nil, ErrInvalidTransition
+	}
+	return dispute, nil
+}
+
+// authorize loads a dispute for one of its parties or an admin. Anyone else
+// is told it doesn't exist.
+func (s *Service) authorize(userID uint, isAdmin bool, disputeID uint) (*models.Dispute, error) {
+	dispute, err := s.get(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && dispute.UserID != userID && dispute.CounterpartyID() != userID {
+		return nil, ErrDisputeNotFound
+	}
+	return dispute, nil
+}
+
+func (s *Service) get(disputeID uint) (*models.Dispute, error) {
+	dispute, err := s.repo.FindByID(disputeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func partyOf(dispute *models.Dispute, userID uint) string {
+	switch {
+	case dispute.UserID == userID:
+		return models.EvidencePartyCustomer
+	case dispute.CounterpartyID() == userID:
+		return models.EvidencePartyMerchant
+	default:
+		return models.EvidencePartyAdmin
+	}
+}
+
+// cleanFileName keeps just the base name of an upload, with the extension
+// matching its detected type
+func cleanFileName(name, ext string) string {
+	base := filepath.Base(strings.TrimSpace(name))
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if base == "" || base == "." || base == "/" {
+		base = "evidence"
+	}
+	if len(base) > 100 {
+		base = base[:100]
+	}
+	return base + ext
+}
@@ -0,0 +1,292 @@
+package enterprise
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+)
+
+// approverRank orders the roles rules can require; when several rules
+// match, the most senior approver wins
+var approverRank = map[string]int{
+	models.EnterpriseRoleFinance: 1,
+	models.EnterpriseRoleAdmin:   2,
+	models.EnterpriseRoleOwner:   3,
+}
+
+func (s *service) ListRules(ctx context.Context, userID, orgID uint) ([]models.EnterpriseApprovalRule, error) {
+	if _, _, err := s.authorize(userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRules(orgID, false)
+}
+
+func (s *service) CreateRule(ctx context.Context, userID, orgID uint, req RuleRequest) (*models.EnterpriseApprovalRule, error) {
+	if _, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin); err != nil {
+		return nil, err
+	}
+	rule := &models.EnterpriseApprovalRule{EnterpriseID: orgID, Active: true, CreatedBy: userID}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if rule.Name == "" {
+		return nil, ErrRuleNameRequired
+	}
+	if rule.ApproverRole == "" {
+		return nil, ErrInvalidApproverRole
+	}
+	if err := s.repo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *service) UpdateRule(ctx context.Context, userID, orgID, ruleID uint, req RuleRequest) (*models.EnterpriseApprovalRule, error) {
+	if _, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin); err != nil {
+		return nil, err
+	}
+	rule, err := s.repo.GetRule(orgID, ruleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrApprovalRuleNotFound) {
+			return nil, ErrRuleNotFound
+		}
+		return nil, err
+	}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, userID, orgID, ruleID uint) error {
+	if _, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin); err != nil {
+		return err
+	}
+	// Payments the rule already held stay queued for their approver role
+	if err := s.repo.DeleteRule(orgID, ruleID); err != nil {
+		if errors.Is(err, repositories.ErrApprovalRuleNotFound) {
+			return ErrRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) ListApprovals(ctx context.Context, userID, orgID uint, limit, offset int) ([]models.EnterprisePayment, int64, error) {
+	_, member, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin, models.EnterpriseRoleFinance)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := repositories.EnterprisePaymentFilter{Status: models.EnterprisePaymentPendingApproval}
+	if member.Role != models.EnterpriseRoleOwner {
+		filter.ApproverRoles = []string{member.Role}
+	}
+	return s.repo.ListPayments(orgID, filter, limit, offset)
+}
+
+func (s *service) ApprovePayment(ctx context.Context, userID, orgID, paymentID uint, note string) (*models.EnterprisePayment, error) {
+	_, member, err := s.authorize(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.reviewable(member, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.wallet(orgID, payment.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Status != WalletActive {
+		return nil, ErrWalletInactive
+	}
+
+	review := &repositories.EnterpriseReview{ReviewerID: userID, Note: strings.TrimSpace(note)}
+	if err := s.execute(ctx, w, payment, review); err != nil {
+		return nil, err
+	}
+	s.notifyRequester(ctx, payment)
+	return payment, nil
+}
+
+func (s *service) RejectPayment(ctx context.Context, userID, orgID, paymentID uint, note string) (*models.EnterprisePayment, error) {
+	_, member, err := s.authorize(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.reviewable(member, paymentID); err != nil {
+		return nil, err
+	}
+	payment, err := s.close(orgID, paymentID, userID, models.EnterprisePaymentRejected, strings.TrimSpace(note))
+	if err != nil {
+		return nil, err
+	}
+	s.notifyRequester(ctx, payment)
+	return payment, nil
+}
+
+func (s *service) CancelPayment(ctx context.Context, userID, orgID, paymentID uint) (*models.EnterprisePayment, error) {
+	if _, _, err := s.authorize(userID, orgID); err != nil {
+		return nil, err
+	}
+	payment, err := s.payment(orgID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.RequestedBy != userID {
+		return nil, ErrNotRequester
+	}
+	return s.close(orgID, paymentID, userID, models.EnterprisePaymentCancelled, "")
+}
+
+func (s *service) PaymentHistory(ctx context.Context, userID, orgID, paymentID uint) ([]models.EnterprisePaymentEvent, error) {
+	_, member, err := s.authorize(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.payment(orgID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	// Plain members only see their own payments, as in ListPayments
+	if member.Role == models.EnterpriseRoleMember && payment.RequestedBy != userID {
+		return nil, ErrPaymentNotFound
+	}
+	return s.repo.ListPaymentEvents(orgID, paymentID)
+}
+
+// matchRule returns the active rule that holds the payment, or nil if none
+// does
+func (s *service) matchRule(payment *models.EnterprisePayment) (*models.EnterpriseApprovalRule, error) {
+	rules, err := s.repo.ListRules(payment.EnterpriseID, true)
+	if err != nil {
+		return nil, err
+	}
+	var match *models.EnterpriseApprovalRule
+	for i := range rules {
+		rule := &rules[i]
+		if rule.WalletID != nil && *rule.WalletID != payment.WalletID {
+			continue
+		}
+		if rule.Category != "" && rule.Category != payment.Category {
+			continue
+		}
+		if payment.Amount < rule.MinAmount {
+			continue
+		}
+		if match == nil || approverRank[rule.ApproverRole] > approverRank[match.ApproverRole] {
+			match = rule
+		}
+	}
+	return match, nil
+}
+
+// reviewable loads a held payment the member may approve or reject
+func (s *service) reviewable(member *models.EnterpriseMember, paymentID uint) (*models.EnterprisePayment, error) {
+	payment, err := s.payment(member.EnterpriseID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != models.EnterpriseRoleOwner && member.Role != payment.ApproverRole {
+		return nil, ErrRoleForbidden
+	}
+	if payment.RequestedBy == member.UserID {
+		return nil, ErrSelfReview
+	}
+	if payment.Status != models.EnterprisePaymentPendingApproval {
+		return nil, ErrPaymentNotPending
+	}
+	return payment, nil
+}
+
+func (s *service) close(orgID, paymentID, actorID uint, status, note string) (*models.EnterprisePayment, error) {
+	payment, err := s.repo.ClosePayment(orgID, paymentID, actorID, status, note)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrEnterprisePaymentNotFound):
+			return nil, ErrPaymentNotFound
+		case errors.Is(err, repositories.ErrEnterprisePaymentNotOpen):
+			return nil, ErrPaymentNotPending
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *service) payment(orgID, paymentID uint) (*models.EnterprisePayment, error) {
+	payment, err := s.repo.GetPayment(orgID, paymentID)
+	if errors.Is(err, repositories.ErrEnterprisePaymentNotFound) {
+		return nil, ErrPaymentNotFound
+	}
+	return payment, err
+}
+
+// notifyApprovers tells everyone who can review a held payment about it.
+// Owners can review every payment.
+func (s *service) notifyApprovers(ctx context.Context, payment *models.EnterprisePayment) {
+	members, err := s.repo.ListMembers(payment.EnterpriseID)
+	if err != nil {
+		log.Printf("Failed to list approvers for organization %d payment %d: %v", payment.EnterpriseID, payment.ID, err)
+		return
+	}
+	for _, m := range members {
+		if m.UserID == payment.RequestedBy {
+			continue
+		}
+		if m.Role != models.EnterpriseRoleOwner && m.Role != payment.ApproverRole {
+			continue
+		}
+		if err := s.notifier.SendEnterpriseApprovalRequest(ctx, m.UserID, payment); err != nil {
+			log.Printf("Failed to notify user %d of organization payment %d: %v", m.UserID, payment.ID, err)
+		}
+	}
+}
+
+func (s *service) notifyRequester(ctx context.Context, payment *models.EnterprisePayment) {
+	if err := s.notifier.SendEnterprisePaymentReviewed(ctx, payment.RequestedBy, payment); err != nil {
+		log.Printf("Failed to notify user %d of organization payment %d: %v", payment.RequestedBy, payment.ID, err)
+	}
+}
+
+// applyRuleRequest validates and sets the fields of req that are present
+func (s *service) applyRuleRequest(rule *models.EnterpriseApprovalRule, req RuleRequest) error {
+	if name := strings.TrimSpace(req.Name); name != "" {
+		rule.Name = name
+	}
+	if req.ApproverRole != "" {
+		if _, ok := approverRank[req.ApproverRole]; !ok {
+			return ErrInvalidApproverRole
+		}
+		rule.ApproverRole = req.ApproverRole
+	}
+	if req.WalletID != nil {
+		if *req.WalletID == 0 {
+			rule.WalletID = nil
+		} else {
+			if _, err := s.wallet(rule.EnterpriseID, *req.WalletID); err != nil {
+				return err
+			}
+			walletID := *req.WalletID
+			rule.WalletID = &walletID
+		}
+	}
+	if req.Category != nil {
+		rule.Category = strings.ToLower(strings.TrimSpace(*req.Category))
+	}
+	if req.MinAmount != nil {
+		if *req.MinAmount < 0 {
+			return ErrInvalidLimit
+		}
+		rule.MinAmount = round2(*req.MinAmount)
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return nil
+}
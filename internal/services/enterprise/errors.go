@@ -0,0 +1,40 @@
+package enterprise
+
+import "errors"
+
+// Service errors
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrRoleForbidden         = errors.New("your role in this organization does not allow this")
+	ErrInvalidRole           = errors.New("role must be owner, admin, finance or member")
+	ErrNameRequired          = errors.New("organization name is required")
+	ErrRegistrationRequired  = errors.New("company registration number is required")
+	ErrRegistrationTaken     = errors.New("company registration number is already registered")
+	ErrAlreadyCreated        = errors.New("you have already created an organization")
+	ErrInvalidBillingCycle   = errors.New("billing cycle must be monthly or yearly")
+	ErrInvalidLimit          = errors.New("limits cannot be negative")
+	ErrMemberNotFound        = errors.New("member not found")
+	ErrMemberExists          = errors.New("user is already a member")
+	ErrTooManyMembers        = errors.New("organization has reached its member limit")
+	ErrLastOwner             = errors.New("an organization must keep at least one owner")
+	ErrOwnerRequired         = errors.New("only an owner can grant or change the owner role")
+	ErrWalletNotFound        = errors.New("organization wallet not found")
+	ErrWalletNameRequired    = errors.New("wallet name is required")
+	ErrWalletInactive        = errors.New("organization wallet is not active")
+	ErrInvalidAmount         = errors.New("amount must be greater than zero")
+	ErrInsufficientFunds     = errors.New("insufficient funds in organization wallet")
+	ErrRecipientNotFound     = errors.New("recipient not found")
+	ErrTransactionLimit      = errors.New("payment exceeds your per-transaction limit")
+	ErrDailyLimit            = errors.New("payment exceeds your daily spending limit")
+	ErrMonthlyLimit          = errors.New("payment exceeds your monthly spending limit")
+	ErrInvalidStatementMonth = errors.New("month must be formatted YYYY-MM")
+
+	// Payment approvals
+	ErrRuleNotFound        = errors.New("approval rule not found")
+	ErrRuleNameRequired    = errors.New("approval rule name is required")
+	ErrInvalidApproverRole = errors.New("approver role must be owner, admin or finance")
+	ErrPaymentNotFound     = errors.New("payment not found")
+	ErrPaymentNotPending   = errors.New("payment is not pending approval")
+	ErrSelfReview          = errors.New("you cannot review your own payment")
+	ErrNotRequester        = errors.New("only the member who requested the payment can cancel it")
+)
@@ -0,0 +1,69 @@
+package enterprise
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Notifier tells approvers about held payments and requesters about the
+// decision
+type Notifier interface {
+	SendEnterpriseApprovalRequest(ctx context.Context, userID uint, payment *models.EnterprisePayment) error
+	SendEnterprisePaymentReviewed(ctx context.Context, userID uint, payment *models.EnterprisePayment) error
+}
+
+// Service manages enterprise organizations: their members and roles, the
+// wallets they pay from, members' spending policies and monthly statements.
+// Callers who aren't members of an organization get ErrOrganizationNotFound.
+type Service interface {
+	// CreateOrganization registers an organization owned by the user
+	CreateOrganization(ctx context.Context, userID uint, req OrganizationRequest) (*OrganizationView, error)
+	ListOrganizations(ctx context.Context, userID uint) ([]Organization, error)
+	GetOrganization(ctx context.Context, userID, orgID uint) (*OrganizationView, error)
+	UpdateOrganization(ctx context.Context, userID, orgID uint, req OrganizationRequest) (*OrganizationView, error)
+
+	// Members; owners and admins manage them, only owners manage owners
+	ListMembers(ctx context.Context, userID, orgID uint) ([]models.EnterpriseMember, error)
+	AddMember(ctx context.Context, userID, orgID uint, req MemberRequest) (*models.EnterpriseMember, error)
+	UpdateMember(ctx context.Context, userID, orgID, memberID uint, req MemberRequest) (*models.EnterpriseMember, error)
+	// RemoveMember takes a user out of the organization; members can remove
+	// themselves
+	RemoveMember(ctx context.Context, userID, orgID, memberID uint) error
+
+	// Wallets
+	CreateWallet(ctx context.Context, userID, orgID uint, req WalletRequest) (*models.EnterpriseWallet, error)
+	ListWallets(ctx context.Context, userID, orgID uint) ([]models.EnterpriseWallet, error)
+	UpdateWallet(ctx context.Context, userID, orgID, walletID uint, req WalletRequest) (*models.EnterpriseWallet, error)
+	// FundWallet moves money from the caller's personal wallet into an
+	// organization wallet
+	FundWallet(ctx context.Context, userID, orgID, walletID uint, amount float64) (*models.EnterpriseWallet, error)
+
+	// Pay sends money out of an organization wallet within the caller's
+	// spending policy. Payments matching an approval rule are held as
+	// pending approval instead.
+	Pay(ctx context.Context, userID, orgID, walletID uint, req PaymentRequest) (*models.EnterprisePayment, error)
+	// ListPayments lists the organization's payments; plain members only
+	// see their own
+	ListPayments(ctx context.Context, userID, orgID uint, filter PaymentFilter, limit, offset int) ([]models.EnterprisePayment, int64, error)
+	// PaymentHistory returns a payment's audit trail
+	PaymentHistory(ctx context.Context, userID, orgID, paymentID uint) ([]models.EnterprisePaymentEvent, error)
+
+	// Approval rules; owners and admins manage them
+	ListRules(ctx context.Context, userID, orgID uint) ([]models.EnterpriseApprovalRule, error)
+	CreateRule(ctx context.Context, userID, orgID uint, req RuleRequest) (*models.EnterpriseApprovalRule, error)
+	UpdateRule(ctx context.Context, userID, orgID, ruleID uint, req RuleRequest) (*models.EnterpriseApprovalRule, error)
+	DeleteRule(ctx context.Context, userID, orgID, ruleID uint) error
+
+	// ListApprovals lists the held payments the caller can review
+	ListApprovals(ctx context.Context, userID, orgID uint, limit, offset int) ([]models.EnterprisePayment, int64, error)
+	// ApprovePayment executes a held payment. Owners and members with the
+	// payment's approver role can review it, but never their own.
+	ApprovePayment(ctx context.Context, userID, orgID, paymentID uint, note string) (*models.EnterprisePayment, error)
+	RejectPayment(ctx context.Context, userID, orgID, paymentID uint, note string) (*models.EnterprisePayment, error)
+	// CancelPayment withdraws the caller's own held payment
+	CancelPayment(ctx context.Context, userID, orgID, paymentID uint) (*models.EnterprisePayment, error)
+
+	// Statement consolidates the organization's activity for a month given
+	// as YYYY-MM; empty means the current month
+	Statement(ctx context.Context, userID, orgID uint, month string) (*Statement, error)
+}
@@ -0,0 +1,754 @@
+package enterprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/wallet"
+	"strings"
+	"time"
+)
+
+type service struct {
+	repo     repositories.EnterpriseRepository
+	users    repositories.UserRepository
+	wallets  wallet.Service
+	notifier Notifier
+	cache    *cache.CacheService
+}
+
+// NewService creates the enterprise organization service. Personal wallet
+// balances are checked through wallets so holds are respected.
+func NewService(
+	repo repositories.EnterpriseRepository,
+	users repositories.UserRepository,
+	wallets wallet.Service,
+	notifier Notifier,
+	cache *cache.CacheService,
+) Service {
+	return &service{
+		repo:     repo,
+		users:    users,
+		wallets:  wallets,
+		notifier: notifier,
+		cache:    cache,
+	}
+}
+
+func (s *service) CreateOrganization(ctx context.Context, userID uint, req OrganizationRequest) (*OrganizationView, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	registrationNo := strings.TrimSpace(req.RegistrationNo)
+	if registrationNo == "" {
+		return nil, ErrRegistrationRequired
+	}
+	billingCycle, err := billingCycle(req.BillingCycle)
+	if err != nil {
+		return nil, err
+	}
+	if taken, err := s.repo.RegistrationNoTaken(registrationNo, 0); err != nil {
+		return nil, err
+	} else if taken {
+		return nil, ErrRegistrationTaken
+	}
+	// The enterprise profile is unique per creator
+	memberOf, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range memberOf {
+		if e.UserID == userID {
+			return nil, ErrAlreadyCreated
+		}
+	}
+
+	enterprise := &models.Enterprise{
+		UserID:                userID,
+		CompanyName:           name,
+		CompanyRegistrationNo: registrationNo,
+		IndustryType:          strings.TrimSpace(req.IndustryType),
+		BillingCycle:          billingCycle,
+		ContractStartDate:     time.Now(),
+		VerificationStatus:    "pending",
+	}
+	owner := &models.EnterpriseMember{UserID: userID, Role: models.EnterpriseRoleOwner, AddedBy: userID}
+	if err := s.repo.Create(enterprise, owner); err != nil {
+		if taken, _ := s.repo.RegistrationNoTaken(registrationNo, 0); taken {
+			return nil, ErrRegistrationTaken
+		}
+		return nil, err
+	}
+	return &OrganizationView{
+		Organization: toOrganization(enterprise),
+		Role:         owner.Role,
+		Members:      []models.EnterpriseMember{*owner},
+		Wallets:      []models.EnterpriseWallet{},
+	}, nil
+}
+
+func (s *service) ListOrganizations(ctx context.Context, userID uint) ([]Organization, error) {
+	enterprises, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	organizations := make([]Organization, 0, len(enterprises))
+	for i := range enterprises {
+		organizations = append(organizations, toOrganization(&enterprises[i]))
+	}
+	return organizations, nil
+}
+
+func (s *service) GetOrganization(ctx context.Context, userID, orgID uint) (*OrganizationView, error) {
+	enterprise, member, err := s.authorize(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(enterprise, member)
+}
+
+func (s *service) UpdateOrganization(ctx context.Context, userID, orgID uint, req OrganizationRequest) (*OrganizationView, error) {
+	enterprise, member, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		enterprise.CompanyName = name
+	}
+	if registrationNo := strings.TrimSpace(req.RegistrationNo); registrationNo != "" && registrationNo != enterprise.CompanyRegistrationNo {
+		if taken, err := s.repo.RegistrationNoTaken(registrationNo, enterprise.ID); err != nil {
+			return nil, err
+		} else if taken {
+			return nil, ErrRegistrationTaken
+		}
+		enterprise.CompanyRegistrationNo = registrationNo
+	}
+	if industry := strings.TrimSpace(req.IndustryType); industry != "" {
+		enterprise.IndustryType = industry
+	}
+	if req.BillingCycle != "" {
+		if enterprise.BillingCycle, err = billingCycle(req.BillingCycle); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Update(enterprise); err != nil {
+		return nil, err
+	}
+	return s.view(enterprise, member)
+}
+
+func (s *service) ListMembers(ctx context.Context, userID, orgID uint) ([]models.EnterpriseMember, error) {
+	if _, _, err := s.authorize(userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(orgID)
+}
+
+func (s *service) AddMember(ctx context.Context, userID, orgID uint, req MemberRequest) (*models.EnterpriseMember, error) {
+	_, actor, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(req.UserID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetMember(orgID, user.ID); err == nil {
+		return nil, ErrMemberExists
+	} else if !errors.Is(err, repositories.ErrEnterpriseMemberNotFound) {
+		return nil, err
+	}
+	count, err := s.repo.CountMembers(orgID, "")
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxMembers {
+		return nil, ErrTooManyMembers
+	}
+
+	if req.Role == "" {
+		req.Role = models.EnterpriseRoleMember
+	}
+	member := &models.EnterpriseMember{EnterpriseID: orgID, UserID: user.ID, AddedBy: userID}
+	if err := applyMemberRequest(actor, member, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddMember(member); err != nil {
+		if _, getErr := s.repo.GetMember(orgID, user.ID); getErr == nil {
+			return nil, ErrMemberExists
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *service) UpdateMember(ctx context.Context, userID, orgID, memberID uint, req MemberRequest) (*models.EnterpriseMember, error) {
+	_, actor, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.member(orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == models.EnterpriseRoleOwner && actor.Role != models.EnterpriseRoleOwner {
+		return nil, ErrOwnerRequired
+	}
+
+	demoting := member.Role == models.EnterpriseRoleOwner && req.Role != "" && req.Role != models.EnterpriseRoleOwner
+	if err := applyMemberRequest(actor, member, req); err != nil {
+		return nil, err
+	}
+	if demoting {
+		if err := s.keepAnOwner(orgID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.UpdateMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, userID, orgID, memberID uint) error {
+	var actor *models.EnterpriseMember
+	var err error
+	if userID == memberID {
+		_, actor, err = s.authorize(userID, orgID)
+	} else {
+		_, actor, err = s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin)
+	}
+	if err != nil {
+		return err
+	}
+
+	member, err := s.member(orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.EnterpriseRoleOwner {
+		if actor.Role != models.EnterpriseRoleOwner {
+			return ErrOwnerRequired
+		}
+		if err := s.keepAnOwner(orgID); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.RemoveMember(orgID, memberID); err != nil {
+		if errors.Is(err, repositories.ErrEnterpriseMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) CreateWallet(ctx context.Context, userID, orgID uint, req WalletRequest) (*models.EnterpriseWallet, error) {
+	if _, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrWalletNameRequired
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if len(currency) != 3 {
+		return nil, wallet.ErrInvalidCurrency
+	}
+
+	w := &models.EnterpriseWallet{
+		EnterpriseID: orgID,
+		Name:         name,
+		Currency:     currency,
+		Status:       WalletActive,
+		CreatedBy:    userID,
+	}
+	if err := s.repo.CreateWallet(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *service) ListWallets(ctx context.Context, userID, orgID uint) ([]models.EnterpriseWallet, error) {
+	if _, _, err := s.authorize(userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWallets(orgID)
+}
+
+func (s *service) UpdateWallet(ctx context.Context, userID, orgID, walletID uint, req WalletRequest) (*models.EnterpriseWallet, error) {
+	if _, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin); err != nil {
+		return nil, err
+	}
+	w, err := s.wallet(orgID, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		w.Name = name
+	}
+	switch req.Status {
+	case "":
+	case WalletActive, WalletFrozen:
+		w.Status = req.Status
+	default:
+		return nil, wallet.ErrInvalidOperation
+	}
+	if err := s.repo.UpdateWallet(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *service) FundWallet(ctx context.Context, userID, orgID, walletID uint, amount float64) (*models.EnterpriseWallet, error) {
+	if _, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin, models.EnterpriseRoleFinance); err != nil {
+		return nil, err
+	}
+	w, err := s.wallet(orgID, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Status != WalletActive {
+		return nil, ErrWalletInactive
+	}
+	amount = round2(amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if err := s.wallets.ValidateBalance(ctx, userID, amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.repo.Fund(&models.EnterpriseFunding{
+		EnterpriseID: orgID,
+		WalletID:     walletID,
+		FundedBy:     userID,
+		Amount:       amount,
+	}, &models.Transaction{
+		Type:          models.TransactionTypeTransfer,
+		SenderID:      userID,
+		Amount:        amount,
+		Currency:      w.Currency,
+		Status:        "completed",
+		Description:   fmt.Sprintf("Added to %s", w.Name),
+		TransactionID: fmt.Sprintf("ENT-IN-%d-%d-%d", walletID, userID, now.UnixNano()),
+		PaymentType:   PaymentType,
+		PaymentMethod: "wallet",
+		ProcessedAt:   now,
+		Metadata: models.NewJSON(map[string]interface{}{
+			"enterprise_id":        orgID,
+			"enterprise_wallet_id": walletID,
+			"direction":            "in",
+		}),
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrInsufficientPersonalFunds) {
+			return nil, wallet.ErrInsufficientBalance
+		}
+		if errors.Is(err, repositories.ErrEnterpriseWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, err
+	}
+	s.invalidate(ctx, userID)
+
+	return s.repo.GetWallet(orgID, walletID)
+}
+
+// Pay sends money out of an organization wallet. Every member can pay;
+// all but owners are held to their spending policy and the approval rules.
+func (s *service) Pay(ctx context.Context, userID, orgID, walletID uint, req PaymentRequest) (*models.EnterprisePayment, error) {
+	_, member, err := s.authorize(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.wallet(orgID, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Status != WalletActive {
+		return nil, ErrWalletInactive
+	}
+	amount := round2(req.Amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if _, err := s.users.GetByID(req.RecipientID); err != nil {
+		return nil, ErrRecipientNotFound
+	}
+	if member.Role != models.EnterpriseRoleOwner {
+		if err := s.checkPolicy(member, amount); err != nil {
+			return nil, err
+		}
+	}
+	if w.Balance < amount {
+		return nil, ErrInsufficientFunds
+	}
+
+	payment := &models.EnterprisePayment{
+		EnterpriseID: orgID,
+		WalletID:     walletID,
+		RequestedBy:  userID,
+		RecipientID:  req.RecipientID,
+		Amount:       amount,
+		Category:     strings.ToLower(strings.TrimSpace(req.Category)),
+		Description:  strings.TrimSpace(req.Description),
+	}
+
+	if member.Role != models.EnterpriseRoleOwner {
+		rule, err := s.matchRule(payment)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			payment.RuleID = &rule.ID
+			payment.ApproverRole = rule.ApproverRole
+			if err := s.repo.HoldPayment(payment, rule.Name); err != nil {
+				return nil, err
+			}
+			log.Printf("Organization %d payment %d of %.2f awaits %s approval (rule %d)", orgID, payment.ID, amount, rule.ApproverRole, rule.ID)
+			s.notifyApprovers(ctx, payment)
+			return payment, nil
+		}
+	}
+
+	if err := s.execute(ctx, w, payment, nil); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *service) ListPayments(ctx context.Context, userID, orgID uint, filter PaymentFilter, limit, offset int) ([]models.EnterprisePayment, int64, error) {
+	_, member, err := s.authorize(userID, orgID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if member.Role == models.EnterpriseRoleMember {
+		filter.MemberID = userID
+	}
+	return s.repo.ListPayments(orgID, repositories.EnterprisePaymentFilter{
+		WalletID:    filter.WalletID,
+		RequestedBy: filter.MemberID,
+		Status:      filter.Status,
+	}, limit, offset)
+}
+
+func (s *service) Statement(ctx context.Context, userID, orgID uint, month string) (*Statement, error) {
+	enterprise, _, err := s.authorize(userID, orgID, models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin, models.EnterpriseRoleFinance)
+	if err != nil {
+		return nil, err
+	}
+
+	var from time.Time
+	if month == "" {
+		now := time.Now().UTC()
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else if from, err = time.Parse("2006-01", month); err != nil {
+		return nil, ErrInvalidStatementMonth
+	}
+	to := from.AddDate(0, 1, 0)
+
+	walletTotals, err := s.repo.GetWalletTotals(orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	memberTotals, err := s.repo.GetMemberTotals(orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	categoryTotals, err := s.repo.GetCategoryTotals(orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := s.repo.ListWallets(orgID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.CountMembers(orgID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{
+		EnterpriseID: orgID,
+		Month:        from.Format("2006-01"),
+		From:         from,
+		To:           to,
+		Wallets:      make([]WalletStatement, 0, len(wallets)),
+		Members:      memberTotals,
+		Categories:   categoryTotals,
+	}
+	// Every wallet appears, including ones with no activity in the month
+	byWallet := make(map[uint]int, len(wallets))
+	for _, w := range wallets {
+		byWallet[w.ID] = len(statement.Wallets)
+		statement.Wallets = append(statement.Wallets, WalletStatement{
+			EnterpriseWalletTotals: repositories.EnterpriseWalletTotals{WalletID: w.ID},
+			Name:                   w.Name,
+			Balance:                w.Balance,
+		})
+	}
+	for _, t := range walletTotals {
+		if i, ok := byWallet[t.WalletID]; ok {
+			statement.Wallets[i].EnterpriseWalletTotals = t
+		}
+		statement.Funded += t.Funded
+		statement.Spent += t.Spent
+		statement.Payments += t.Payments
+	}
+	statement.Funded = round2(statement.Funded)
+	statement.Spent = round2(statement.Spent)
+
+	fees := models.FeeStructures[models.UserTypeEnterprise]
+	statement.Billing = Billing{
+		Plan:            string(models.UserTypeEnterprise),
+		BillingCycle:    enterprise.BillingCycle,
+		SubscriptionFee: fees.MonthlyFee,
+		TransactionFee:  fees.TransactionFee,
+		Members:         members,
+	}
+	if enterprise.BillingCycle == "yearly" {
+		statement.Billing.SubscriptionFee = round2(fees.MonthlyFee * 12)
+	}
+	return statement, nil
+}
+
+func (s *service) execute(ctx context.Context, w *models.EnterpriseWallet, payment *models.EnterprisePayment, review *repositories.EnterpriseReview) error {
+	description := payment.Description
+	if description == "" {
+		description = fmt.Sprintf("Payment from %s", w.Name)
+	}
+	now := time.Now()
+	tx := &models.Transaction{
+		Type:          models.TransactionTypeTransfer,
+		SenderID:      payment.RequestedBy,
+		ReceiverID:    payment.RecipientID,
+		Amount:        payment.Amount,
+		Currency:      w.Currency,
+		Status:        "completed",
+		Description:   description,
+		TransactionID: fmt.Sprintf("ENT-OUT-%d-%d", w.ID, now.UnixNano()),
+		PaymentType:   PaymentType,
+		PaymentMethod: PaymentType,
+		ProcessedAt:   now,
+	}
+
+	if err := s.repo.ExecutePayment(payment, tx, review); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInsufficientEnterpriseFunds):
+			return ErrInsufficientFunds
+		case errors.Is(err, repositories.ErrEnterpriseWalletNotFound):
+			return ErrWalletNotFound
+		case errors.Is(err, repositories.ErrEnterprisePaymentNotOpen):
+			return ErrPaymentNotPending
+		}
+		return err
+	}
+	s.invalidate(ctx, payment.RecipientID)
+	log.Printf("Organization %d member %d paid %.2f to user %d from wallet %d",
+		payment.EnterpriseID, payment.RequestedBy, payment.Amount, payment.RecipientID, payment.WalletID)
+	return nil
+}
+
+// checkPolicy holds a member to their per-transaction, daily and monthly
+// limits; days and months are UTC
+func (s *service) checkPolicy(member *models.EnterpriseMember, amount float64) error {
+	if member.PerTransactionLimit > 0 && amount > member.PerTransactionLimit {
+		return ErrTransactionLimit
+	}
+	now := time.Now().UTC()
+	if member.DailyLimit > 0 {
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		spent, err := s.repo.SumMemberSpending(member.EnterpriseID, member.UserID, startOfDay)
+		if err != nil {
+			return err
+		}
+		if spent+amount > member.DailyLimit {
+			return ErrDailyLimit
+		}
+	}
+	if member.MonthlyLimit > 0 {
+		startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		spent, err := s.repo.SumMemberSpending(member.EnterpriseID, member.UserID, startOfMonth)
+		if err != nil {
+			return err
+		}
+		if spent+amount > member.MonthlyLimit {
+			return ErrMonthlyLimit
+		}
+	}
+	return nil
+}
+
+// authorize loads the organization and the caller's membership, requiring
+// one of roles when any are given. Non-members get not found so
+// organizations aren't leaked.
+func (s *service) authorize(userID, orgID uint, roles ...string) (*models.Enterprise, *models.EnterpriseMember, error) {
+	member, err := s.repo.GetMember(orgID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrEnterpriseMemberNotFound) {
+			return nil, nil, ErrOrganizationNotFound
+		}
+		return nil, nil, err
+	}
+	enterprise, err := s.repo.GetByID(orgID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrEnterpriseNotFound) {
+			return nil, nil, ErrOrganizationNotFound
+		}
+		return nil, nil, err
+	}
+
+	if len(roles) == 0 {
+		return enterprise, member, nil
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return enterprise, member, nil
+		}
+	}
+	return nil, nil, ErrRoleForbidden
+}
+
+func (s *service) view(enterprise *models.Enterprise, member *models.EnterpriseMember) (*OrganizationView, error) {
+	members, err := s.repo.ListMembers(enterprise.ID)
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := s.repo.ListWallets(enterprise.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &OrganizationView{
+		Organization: toOrganization(enterprise),
+		Role:         member.Role,
+		Members:      members,
+		Wallets:      wallets,
+	}, nil
+}
+
+func (s *service) member(orgID, userID uint) (*models.EnterpriseMember, error) {
+	member, err := s.repo.GetMember(orgID, userID)
+	if errors.Is(err, repositories.ErrEnterpriseMemberNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	return member, err
+}
+
+func (s *service) wallet(orgID, walletID uint) (*models.EnterpriseWallet, error) {
+	w, err := s.repo.GetWallet(orgID, walletID)
+	if errors.Is(err, repositories.ErrEnterpriseWalletNotFound) {
+		return nil, ErrWalletNotFound
+	}
+	return w, err
+}
+
+func (s *service) keepAnOwner(orgID uint) error {
+	owners, err := s.repo.CountMembers(orgID, models.EnterpriseRoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func (s *service) resolveUser(userID uint, email string) (*models.User, error) {
+	var user *models.User
+	var err error
+	if userID != 0 {
+		user, err = s.users.GetByID(userID)
+	} else if email = strings.TrimSpace(email); email != "" {
+		user, err = s.users.GetByEmail(email)
+	} else {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *service) invalidate(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, s.cache.GenerateKey("wallet", "user", userID)); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
+
+// applyMemberRequest sets a member's role and policy. Only owners can make
+// someone an owner.
+func applyMemberRequest(actor, member *models.EnterpriseMember, req MemberRequest) error {
+	if req.Role != "" {
+		if !models.IsValidEnterpriseRole(req.Role) {
+			return ErrInvalidRole
+		}
+		if req.Role == models.EnterpriseRoleOwner && actor.Role != models.EnterpriseRoleOwner {
+			return ErrOwnerRequired
+		}
+		member.Role = req.Role
+	}
+	if req.Title != nil {
+		member.Title = strings.TrimSpace(*req.Title)
+	}
+	for _, limit := range []struct {
+		value *float64
+		field *float64
+	}{
+		{req.PerTransactionLimit, &member.PerTransactionLimit},
+		{req.DailyLimit, &member.DailyLimit},
+		{req.MonthlyLimit, &member.MonthlyLimit},
+	} {
+		if limit.value == nil {
+			continue
+		}
+		if *limit.value < 0 {
+			return ErrInvalidLimit
+		}
+		*limit.field = round2(*limit.value)
+	}
+	return nil
+}
+
+func billingCycle(cycle string) (string, error) {
+	switch cycle = strings.ToLower(strings.TrimSpace(cycle)); cycle {
+	case "":
+		return "monthly", nil
+	case "monthly", "yearly":
+		return cycle, nil
+	}
+	return "", ErrInvalidBillingCycle
+}
+
+func toOrganization(e *models.Enterprise) Organization {
+	return Organization{
+		ID:                 e.ID,
+		Name:               e.CompanyName,
+		RegistrationNo:     e.CompanyRegistrationNo,
+		IndustryType:       e.IndustryType,
+		BillingCycle:       e.BillingCycle,
+		VerificationStatus: e.VerificationStatus,
+		CreatedBy:          e.UserID,
+		CreatedAt:          e.CreatedAt,
+	}
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,132 @@
+package enterprise
+
+import (
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+const (
+	// MaxMembers caps the size of an organization
+	MaxMembers = 500
+
+	// DefaultCurrency is used for organization wallets created without one
+	DefaultCurrency = "USD"
+
+	// PaymentType tags the transactions that move money in and out of
+	// organization wallets
+	PaymentType = "enterprise_wallet"
+)
+
+// Wallet statuses
+const (
+	WalletActive = "active"
+	WalletFrozen = "frozen"
+)
+
+// OrganizationRequest creates or updates an organization. On update, empty
+// fields are left unchanged.
+type OrganizationRequest struct {
+	Name           string `json:"name"`
+	RegistrationNo string `json:"registration_no"`
+	IndustryType   string `json:"industry_type"`
+	BillingCycle   string `json:"billing_cycle"` // monthly or yearly; defaults to monthly
+}
+
+// Organization is the API view of an enterprise. The underlying profile
+// embeds its creator's user record, so it is never returned as is.
+type Organization struct {
+	ID                 uint      `json:"id"`
+	Name               string    `json:"name"`
+	RegistrationNo     string    `json:"registration_no"`
+	IndustryType       string    `json:"industry_type,omitempty"`
+	BillingCycle       string    `json:"billing_cycle"`
+	VerificationStatus string    `json:"verification_status"`
+	CreatedBy          uint      `json:"created_by"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// OrganizationView is an organization as one of its members sees it
+type OrganizationView struct {
+	Organization
+	Role    string                    `json:"role"` // The caller's role
+	Members []models.EnterpriseMember `json:"members"`
+	Wallets []models.EnterpriseWallet `json:"wallets"`
+}
+
+// MemberRequest adds a member or changes their role and spending policy.
+// Users are found by ID or email; nil limits are left unchanged.
+type MemberRequest struct {
+	UserID              uint     `json:"user_id"`
+	Email               string   `json:"email"`
+	Role                string   `json:"role"`
+	Title               *string  `json:"title"`
+	PerTransactionLimit *float64 `json:"per_transaction_limit"`
+	DailyLimit          *float64 `json:"daily_limit"`
+	MonthlyLimit        *float64 `json:"monthly_limit"`
+}
+
+// WalletRequest creates or updates an organization wallet
+type WalletRequest struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+	Status   string `json:"status"` // active or frozen; only on update
+}
+
+// PaymentRequest pays a user out of an organization wallet
+type PaymentRequest struct {
+	RecipientID uint    `json:"recipient_id"`
+	Amount      float64 `json:"amount"`
+	Category    string  `json:"category"`
+	Description string  `json:"description"`
+}
+
+// RuleRequest creates or updates an approval rule. On update, nil fields
+// are left unchanged; a WalletID of 0 makes the rule apply to all wallets.
+type RuleRequest struct {
+	Name         string   `json:"name"`
+	WalletID     *uint    `json:"wallet_id"`
+	Category     *string  `json:"category"`
+	MinAmount    *float64 `json:"min_amount"`
+	ApproverRole string   `json:"approver_role"`
+	Active       *bool    `json:"active"`
+}
+
+// PaymentFilter narrows a listing of organization payments
+type PaymentFilter struct {
+	WalletID uint
+	MemberID uint
+	Status   string
+}
+
+// WalletStatement is one wallet's activity over a statement period
+type WalletStatement struct {
+	repositories.EnterpriseWalletTotals
+	Name    string  `json:"name"`
+	Balance float64 `json:"balance"` // Balance when the statement was produced
+}
+
+// Billing is what the organization owes for its plan over a period
+type Billing struct {
+	Plan            string  `json:"plan"`
+	BillingCycle    string  `json:"billing_cycle"`
+	SubscriptionFee float64 `json:"subscription_fee"`
+	TransactionFee  float64 `json:"transaction_fee_percent"`
+	Members         int64   `json:"members"`
+}
+
+// Statement consolidates an organization's activity for a calendar month
+// (UTC) across all of its wallets and members
+type Statement struct {
+	EnterpriseID uint                                    `json:"enterprise_id"`
+	Month        string                                  `json:"month"`
+	From         time.Time                               `json:"from"`
+	To           time.Time                               `json:"to"`
+	Funded       float64                                 `json:"funded"`
+	Spent        float64                                 `json:"spent"`
+	Payments     int64                                   `json:"payments"`
+	Wallets      []WalletStatement                       `json:"wallets"`
+	Members      []repositories.EnterpriseMemberTotals   `json:"members"`
+	Categories   []repositories.EnterpriseCategoryTotals `json:"categories"`
+	Billing      Billing                                 `json:"billing"`
+}
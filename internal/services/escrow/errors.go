@@ -0,0 +1,21 @@
+package escrow
+
+import "errors"
+
+// Service errors
+var (
+	ErrEscrowNotFound      = errors.New("escrow not found")
+	ErrSellerNotFound      = errors.New("seller not found")
+	ErrSelfEscrow          = errors.New("you cannot open an escrow with yourself")
+	ErrInvalidAmount       = errors.New("amount must be greater than zero")
+	ErrDescriptionRequired = errors.New("description is required")
+	ErrInvalidReleaseDays  = errors.New("release period is out of range")
+	ErrNotBuyer            = errors.New("only the buyer can do this")
+	ErrNotSeller           = errors.New("only the seller can do this")
+	ErrNotOpen             = errors.New("escrow has already been settled")
+	ErrDisputed            = errors.New("escrow is under dispute")
+	ErrNotDisputed         = errors.New("escrow is not disputed")
+	ErrAlreadyDisputed     = errors.New("escrow is already disputed")
+	ErrReasonRequired      = errors.New("reason is required")
+	ErrInvalidOutcome      = errors.New("outcome must be release or refund")
+)
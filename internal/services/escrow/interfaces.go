@@ -0,0 +1,40 @@
+package escrow
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// WalletService checks the buyer can fund an escrow, respecting holds
+type WalletService interface {
+	ValidateBalance(ctx context.Context, userID uint, amount float64) error
+}
+
+// DisputeService takes over escrows that the buyer or seller object to
+type DisputeService interface {
+	OpenEscrowDispute(fundingTransactionID, raisedBy, sellerID uint, reason string) (*models.Dispute, error)
+	CloseEscrowDispute(disputeID uint, refunded bool) error
+}
+
+// Notifier tells buyers and sellers about changes to their escrows
+type Notifier interface {
+	SendEscrowUpdate(ctx context.Context, userID uint, escrow *models.Escrow, event string) error
+}
+
+// Service manages escrow payments between a buyer and a seller
+type Service interface {
+	Create(ctx context.Context, buyerID uint, req CreateRequest) (*models.Escrow, error)
+	List(ctx context.Context, userID uint, role, status string, limit, offset int) ([]models.Escrow, int64, error)
+	Get(ctx context.Context, userID, escrowID uint) (*models.Escrow, error)
+	// Confirm is the buyer releasing the funds to the seller
+	Confirm(ctx context.Context, buyerID, escrowID uint) (*models.Escrow, error)
+	// Refund is the seller giving the funds back to the buyer
+	Refund(ctx context.Context, sellerID, escrowID uint) (*models.Escrow, error)
+	// Dispute stops auto-release and hands the escrow to the dispute service
+	Dispute(ctx context.Context, userID, escrowID uint, reason string) (*models.Escrow, error)
+	// Resolve settles a disputed escrow in favour of the seller or the buyer
+	Resolve(ctx context.Context, adminID, escrowID uint, outcome string) (*models.Escrow, error)
+
+	// ProcessAutoReleases releases funded escrows whose auto-release date has passed
+	ProcessAutoReleases(ctx context.Context) (int, error)
+}
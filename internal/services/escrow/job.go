@@ -0,0 +1,24 @@
+package escrow
+
+import (
+	"context"
+	"log"
+)
+
+// Job releases escrows whose auto-release date has passed
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the escrow service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "escrow-auto-release" }
+
+func (j *Job) Run(ctx context.Context) error {
+	released, err := j.service.ProcessAutoReleases(ctx)
+	if released > 0 {
+		log.Printf("Auto-released %d escrows", released)
+	}
+	return err
+}
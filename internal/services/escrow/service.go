@@ -0,0 +1,342 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/wallet"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxReleaseDays caps how long a buyer can keep funds in escrow
+	MaxReleaseDays = 90
+
+	releaseBatchSize = 100
+)
+
+type service struct {
+	repo     repositories.EscrowRepository
+	users    repositories.UserRepository
+	wallets  WalletService
+	disputes DisputeService
+	notifier Notifier
+	cache    *cache.CacheService
+	// releaseAfterDays is the auto-release period when the buyer doesn't pick one
+	releaseAfterDays int
+}
+
+// NewService creates a new escrow service
+func NewService(
+	repo repositories.EscrowRepository,
+	users repositories.UserRepository,
+	wallets WalletService,
+	disputes DisputeService,
+	notifier Notifier,
+	cache *cache.CacheService,
+	releaseAfterDays int,
+) Service {
+	return &service{
+		repo:             repo,
+		users:            users,
+		wallets:          wallets,
+		disputes:         disputes,
+		notifier:         notifier,
+		cache:            cache,
+		releaseAfterDays: releaseAfterDays,
+	}
+}
+
+func (s *service) Create(ctx context.Context, buyerID uint, req CreateRequest) (*models.Escrow, error) {
+	amount := round2(req.Amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, ErrDescriptionRequired
+	}
+	if req.SellerID == buyerID {
+		return nil, ErrSelfEscrow
+	}
+	days := req.ReleaseAfterDays
+	if days == 0 {
+		days = s.releaseAfterDays
+	}
+	if days < 1 || days > MaxReleaseDays {
+		return nil, ErrInvalidReleaseDays
+	}
+
+	seller, err := s.users.GetByID(req.SellerID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrSellerNotFound
+		}
+		return nil, err
+	}
+	if seller.Status != "active" {
+		return nil, ErrSellerNotFound
+	}
+
+	if err := s.wallets.ValidateBalance(ctx, buyerID, amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	escrow := &models.Escrow{
+		BuyerID:       buyerID,
+		SellerID:      seller.ID,
+		Amount:        amount,
+		Currency:      "USD",
+		Description:   description,
+		AutoReleaseAt: now.AddDate(0, 0, days),
+	}
+	tx := &models.Transaction{
+		Type:          models.TransactionTypeEscrow,
+		SenderID:      buyerID,
+		Amount:        amount,
+		Currency:      escrow.Currency,
+		Status:        "completed",
+		Description:   fmt.Sprintf("Escrow payment: %s", description),
+		TransactionID: fmt.Sprintf("ESC-IN-%d-%d", buyerID, now.UnixNano()),
+		PaymentType:   "escrow",
+		PaymentMethod: "wallet",
+		ProcessedAt:   now,
+	}
+	if err := s.repo.Fund(escrow, tx); err != nil {
+		return nil, mapRepoError(err)
+	}
+
+	s.invalidate(ctx, buyerID)
+	s.notify(ctx, escrow.SellerID, escrow, "funded")
+	return escrow, nil
+}
+
+func (s *service) List(ctx context.Context, userID uint, role, status string, limit, offset int) ([]models.Escrow, int64, error) {
+	return s.repo.List(userID, role, status, limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, userID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := s.repo.GetByID(escrowID)
+	if err != nil {
+		return nil, mapRepoError(err)
+	}
+	// Other users can't tell an escrow exists
+	if escrow.BuyerID != userID && escrow.SellerID != userID {
+		return nil, ErrEscrowNotFound
+	}
+	return escrow, nil
+}
+
+func (s *service) Confirm(ctx context.Context, buyerID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := s.Get(ctx, buyerID, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.BuyerID != buyerID {
+		return nil, ErrNotBuyer
+	}
+	return s.settle(ctx, escrow, models.EscrowStatusReleased, "buyer_confirmed", models.EscrowStatusFunded)
+}
+
+func (s *service) Refund(ctx context.Context, sellerID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := s.Get(ctx, sellerID, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.SellerID != sellerID {
+		return nil, ErrNotSeller
+	}
+
+	// A seller can also settle a dispute by giving the money back
+	settled, err := s.settle(ctx, escrow, models.EscrowStatusRefunded, "seller_refund",
+		models.EscrowStatusFunded, models.EscrowStatusDisputed)
+	if err != nil {
+		return nil, err
+	}
+	s.closeDispute(settled, true)
+	return settled, nil
+}
+
+func (s *service) Dispute(ctx context.Context, userID, escrowID uint, reason string) (*models.Escrow, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	escrow, err := s.Get(ctx, userID, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	switch escrow.Status {
+	case models.EscrowStatusDisputed:
+		return nil, ErrAlreadyDisputed
+	case models.EscrowStatusFunded:
+	default:
+		return nil, ErrNotOpen
+	}
+
+	dispute, err := s.disputes.OpenEscrowDispute(*escrow.FundingTransactionID, userID, escrow.SellerID, reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkDisputed(escrow.ID, userID, dispute.ID); err != nil {
+		// The escrow settled or was disputed while the dispute was being
+		// filed; the orphaned dispute is closed so nobody acts on it
+		if closeErr := s.disputes.CloseEscrowDispute(dispute.ID, false); closeErr != nil {
+			log.Printf("Failed to close dispute %d for escrow %d: %v", dispute.ID, escrow.ID, closeErr)
+		}
+		return nil, mapRepoError(err)
+	}
+
+	escrow, err = s.repo.GetByID(escrow.ID)
+	if err != nil {
+		return nil, err
+	}
+	counterparty := escrow.SellerID
+	if userID == escrow.SellerID {
+		counterparty = escrow.BuyerID
+	}
+	s.notify(ctx, counterparty, escrow, "disputed")
+	return escrow, nil
+}
+
+func (s *service) Resolve(ctx context.Context, adminID, escrowID uint, outcome string) (*models.Escrow, error) {
+	status, reason := models.EscrowStatusReleased, "dispute_release"
+	switch outcome {
+	case OutcomeRelease:
+	case OutcomeRefund:
+		status, reason = models.EscrowStatusRefunded, "dispute_refund"
+	default:
+		return nil, ErrInvalidOutcome
+	}
+
+	escrow, err := s.repo.GetByID(escrowID)
+	if err != nil {
+		return nil, mapRepoError(err)
+	}
+	settled, err := s.settle(ctx, escrow, status, reason, models.EscrowStatusDisputed)
+	if err != nil {
+		if errors.Is(err, errStatusNotAllowed) {
+			return nil, ErrNotDisputed
+		}
+		return nil, err
+	}
+	s.closeDispute(settled, status == models.EscrowStatusRefunded)
+
+	log.Printf("Escrow %d dispute resolved by admin %d: %s", escrowID, adminID, outcome)
+	return settled, nil
+}
+
+func (s *service) ProcessAutoReleases(ctx context.Context) (int, error) {
+	due, err := s.repo.GetDueForRelease(time.Now(), releaseBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return released, err
+		}
+		// A dispute or confirmation may have got there first; that's fine
+		if _, err := s.settle(ctx, &due[i], models.EscrowStatusReleased, "auto_release", models.EscrowStatusFunded); err != nil {
+			if !errors.Is(err, ErrNotOpen) && !errors.Is(err, errStatusNotAllowed) {
+				log.Printf("Failed to auto-release escrow %d: %v", due[i].ID, err)
+			}
+			continue
+		}
+		released++
+	}
+	return released, nil
+}
+
+// errStatusNotAllowed is returned by settle when the escrow is open but in
+// a status the caller didn't allow
+var errStatusNotAllowed = errors.New("escrow status does not allow settlement")
+
+// settle pays the escrow out and tells both parties
+func (s *service) settle(ctx context.Context, escrow *models.Escrow, status, reason string, from ...string) (*models.Escrow, error) {
+	recipientID, senderID, verb := escrow.SellerID, escrow.BuyerID, "released"
+	if status == models.EscrowStatusRefunded {
+		recipientID, senderID, verb = escrow.BuyerID, escrow.SellerID, "refunded"
+	}
+
+	now := time.Now()
+	settled, err := s.repo.Settle(repositories.EscrowSettlement{
+		EscrowID: escrow.ID,
+		From:     from,
+		Status:   status,
+		Reason:   reason,
+		Transaction: &models.Transaction{
+			Type:          models.TransactionTypeEscrow,
+			ReceiverID:    recipientID,
+			Amount:        escrow.Amount,
+			Currency:      escrow.Currency,
+			Status:        "completed",
+			Description:   fmt.Sprintf("Escrow %s: %s", verb, escrow.Description),
+			TransactionID: fmt.Sprintf("ESC-OUT-%d-%d", escrow.ID, now.UnixNano()),
+			PaymentType:   "escrow",
+			PaymentMethod: "escrow",
+			ProcessedAt:   now,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrEscrowStatusNotAllowed) {
+			if escrow.Status == models.EscrowStatusDisputed {
+				return nil, ErrDisputed
+			}
+			return nil, errStatusNotAllowed
+		}
+		return nil, mapRepoError(err)
+	}
+
+	s.invalidate(ctx, recipientID)
+	s.notify(ctx, recipientID, settled, verb)
+	s.notify(ctx, senderID, settled, verb)
+	return settled, nil
+}
+
+func (s *service) closeDispute(escrow *models.Escrow, refunded bool) {
+	if escrow.DisputeID == nil {
+		return
+	}
+	if err := s.disputes.CloseEscrowDispute(*escrow.DisputeID, refunded); err != nil {
+		log.Printf("Failed to close dispute %d for escrow %d: %v", *escrow.DisputeID, escrow.ID, err)
+	}
+}
+
+func (s *service) notify(ctx context.Context, userID uint, escrow *models.Escrow, event string) {
+	if err := s.notifier.SendEscrowUpdate(ctx, userID, escrow, event); err != nil {
+		log.Printf("Failed to notify user %d about escrow %d: %v", userID, escrow.ID, err)
+	}
+}
+
+func (s *service) invalidate(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, s.cache.GenerateKey("wallet", "user", userID)); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
+
+func mapRepoError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrEscrowNotFound):
+		return ErrEscrowNotFound
+	case errors.Is(err, repositories.ErrEscrowNotOpen):
+		return ErrNotOpen
+	case errors.Is(err, repositories.ErrEscrowAlreadyDisputed):
+		return ErrAlreadyDisputed
+	case errors.Is(err, repositories.ErrInsufficientBuyerFunds):
+		return wallet.ErrInsufficientBalance
+	}
+	return err
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,26 @@
+package escrow
+
+// Outcomes for resolving a disputed escrow
+const (
+	OutcomeRelease = "release"
+	OutcomeRefund  = "refund"
+)
+
+// CreateRequest funds an escrow for a seller
+type CreateRequest struct {
+	SellerID    uint    `json:"seller_id"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+	// ReleaseAfterDays overrides the default auto-release period
+	ReleaseAfterDays int `json:"release_after_days"`
+}
+
+// DisputeRequest is the body for objecting to an escrow
+type DisputeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ResolveRequest is the body for settling a disputed escrow
+type ResolveRequest struct {
+	Outcome string `json:"outcome"`
+}
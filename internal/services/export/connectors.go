@@ -0,0 +1,127 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"orus/internal/services/webhook"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// LogMailer records export emails in the log instead of sending them.
+// It stands in until an email provider is configured.
+type LogMailer struct{}
+
+// NewLogMailer creates a mailer that logs outgoing messages
+func NewLogMailer() *LogMailer { return &LogMailer{} }
+
+func (m *LogMailer) SendAttachment(ctx context.Context, to, subject, body string, attachment Attachment) error {
+	log.Printf("Email to %s: %s (%s, %d bytes)", to, subject, attachment.FileName, len(attachment.Content))
+	return nil
+}
+
+// LocalStorage writes exports under a base directory on local disk
+type LocalStorage struct {
+	baseDir string
+}
+
+// NewLocalStorage creates a connector rooted at baseDir
+func NewLocalStorage(baseDir string) *LocalStorage {
+	return &LocalStorage{baseDir: baseDir}
+}
+
+func (s *LocalStorage) Name() string { return "local" }
+
+func (s *LocalStorage) Upload(ctx context.Context, path string, content []byte) (string, error) {
+	// Keep user-supplied paths inside the base directory
+	clean := filepath.Clean("/" + path)
+	target := filepath.Join(s.baseDir, clean)
+	if !strings.HasPrefix(target, filepath.Clean(s.baseDir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid storage path: %s", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	if err := os.WriteFile(target, content, 0o640); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+	return "local://" + clean, nil
+}
+
+// HTTPPoster POSTs export files to webhook URLs, signed the same way as
+// merchant webhooks so receivers can reuse their verification code
+type HTTPPoster struct {
+	client *http.Client
+}
+
+// NewHTTPPoster creates a webhook poster. Merchants choose the URLs, so it
+// only connects to public addresses, checked once the host is resolved so
+// DNS can't point it back inside, and doesn't follow redirects.
+func NewHTTPPoster() *HTTPPoster {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would connect on the poster's behalf, past the check
+	transport.Proxy = nil
+	return &HTTPPoster{client: &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// publicOnly refuses to connect to loopback, private, link-local (which
+// holds cloud metadata endpoints) and other non-public addresses
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is carrier-grade NAT space, private to the provider
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func (p *HTTPPoster) Post(ctx context.Context, url, secret string, attachment Attachment) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(attachment.Content))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", attachment.ContentType)
+	req.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	req.Header.Set("X-Orus-Event", "statement.exported")
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, time.Now(), attachment.Content))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return nil
+}
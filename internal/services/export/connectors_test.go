@@ -0,0 +1,57 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPPosterRefusesPrivateAddresses(t *testing.T) {
+	var received bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	err := NewHTTPPoster().Post(context.Background(), server.URL, "whsec_test", Attachment{Content: []byte("a,b")})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Post() to %s error = %v, want %v", server.URL, err, ErrBlockedAddress)
+	}
+	if received {
+		t.Error("Post() reached a loopback endpoint")
+	}
+}
+
+func TestPublicOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:443", false},
+		{"10.0.0.5:443", false},
+		{"172.16.0.1:443", false},
+		{"192.168.1.1:443", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:443", false},
+		{"0.0.0.0:443", false},
+		{"[::1]:443", false},
+		{"[fd00:ec2::254]:80", false},
+		{"[fe80::1]:443", false},
+		{"[::ffff:127.0.0.1]:443", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := publicOnly("tcp", tt.address, nil)
+			if tt.allowed && err != nil {
+				t.Errorf("publicOnly() error = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrBlockedAddress) {
+				t.Errorf("publicOnly() error = %v, want %v", err, ErrBlockedAddress)
+			}
+		})
+	}
+}
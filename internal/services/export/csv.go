@@ -0,0 +1,60 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"orus/internal/models"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"date", "transaction_id", "type", "direction", "description", "category",
+	"merchant", "amount", "fee", "currency", "status", "reference",
+}
+
+// writeCSV renders transactions from the point of view of userID
+func writeCSV(userID uint, transactions []models.Transaction) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	for _, tx := range transactions {
+		direction := "debit"
+		if tx.ReceiverID == userID && tx.SenderID != userID {
+			direction = "credit"
+		}
+
+		date := tx.ProcessedAt
+		if date.IsZero() {
+			date = tx.UpdatedAt
+		}
+
+		record := []string{
+			date.UTC().Format(time.RFC3339),
+			tx.TransactionID,
+			tx.Type,
+			direction,
+			tx.Description,
+			tx.Category,
+			tx.MerchantName,
+			strconv.FormatFloat(tx.Amount, 'f', 2, 64),
+			strconv.FormatFloat(tx.Fee, 'f', 2, 64),
+			tx.Currency,
+			tx.Status,
+			tx.Reference,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,17 @@
+package export
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidDayOfMonth   = errors.New("day of month must be between 1 and 28")
+	ErrInvalidDayOfWeek    = errors.New("day of week must be between 1 (Monday) and 7 (Sunday)")
+	ErrInvalidFrequency    = errors.New("frequency must be weekly or monthly")
+	ErrInvalidDestination  = errors.New("destination must be email, storage or webhook")
+	ErrInvalidStatus       = errors.New("status must be active or paused")
+	ErrUnknownConnector    = errors.New("unknown storage connector")
+	ErrStoragePathRequired = errors.New("storage path is required for storage exports")
+	ErrInvalidWebhookURL   = errors.New("webhook URL must be an https URL")
+	ErrBlockedAddress      = errors.New("webhook URL resolves to a private address")
+	ErrTooManySchedules    = errors.New("export schedule limit reached")
+)
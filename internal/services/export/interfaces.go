@@ -0,0 +1,47 @@
+package export
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Mailer delivers export files by email
+type Mailer interface {
+	SendAttachment(ctx context.Context, to, subject, body string, attachment Attachment) error
+}
+
+// StorageConnector uploads export files to a storage backend
+type StorageConnector interface {
+	// Name identifies the connector on export schedules
+	Name() string
+
+	// Upload stores the content at path and returns where it was stored
+	Upload(ctx context.Context, path string, content []byte) (string, error)
+}
+
+// WebhookPoster delivers export files to a user's webhook endpoint
+type WebhookPoster interface {
+	// Post sends the attachment to url, signed with secret
+	Post(ctx context.Context, url, secret string, attachment Attachment) error
+}
+
+// Notifier tells users when a scheduled export fails
+type Notifier interface {
+	SendExportFailedNotification(ctx context.Context, userID uint, schedule *models.ExportSchedule, reason string) error
+}
+
+// Service manages scheduled transaction exports
+type Service interface {
+	CreateSchedule(ctx context.Context, userID uint, req ScheduleRequest) (*models.ExportSchedule, error)
+	ListSchedules(ctx context.Context, userID uint) ([]models.ExportSchedule, error)
+	UpdateSchedule(ctx context.Context, userID, scheduleID uint, req ScheduleRequest) (*models.ExportSchedule, error)
+	DeleteSchedule(ctx context.Context, userID, scheduleID uint) error
+	GetRuns(ctx context.Context, userID, scheduleID uint, limit, offset int) ([]models.ExportRun, int64, error)
+
+	// RunNow exports the previous week or month immediately without moving
+	// the schedule
+	RunNow(ctx context.Context, userID, scheduleID uint) (*models.ExportRun, error)
+
+	// RunDue executes every schedule whose next run has passed and returns how many ran
+	RunDue(ctx context.Context) (int, error)
+}
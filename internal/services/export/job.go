@@ -0,0 +1,24 @@
+package export
+
+import (
+	"context"
+	"log"
+)
+
+// Job runs due export schedules from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the export service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "transaction-exports" }
+
+func (j *Job) Run(ctx context.Context) error {
+	ran, err := j.service.RunDue(ctx)
+	if ran > 0 {
+		log.Printf("Ran %d scheduled transaction exports", ran)
+	}
+	return err
+}
@@ -0,0 +1,397 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"path"
+	"time"
+)
+
+const (
+	// MaxSchedulesPerUser bounds how many export schedules a user can keep
+	MaxSchedulesPerUser = 5
+	// MaxConsecutiveFailures pauses a schedule after repeated failures
+	MaxConsecutiveFailures = 3
+	// runHour is the UTC hour scheduled exports run at
+	runHour = 6
+	// dueBatchSize bounds how many schedules one job pass picks up
+	dueBatchSize = 100
+)
+
+type service struct {
+	repo       repositories.ExportScheduleRepository
+	users      repositories.UserRepository
+	mailer     Mailer
+	poster     WebhookPoster
+	connectors map[string]StorageConnector
+	notifier   Notifier
+}
+
+// NewService creates a new export scheduling service
+func NewService(
+	repo repositories.ExportScheduleRepository,
+	users repositories.UserRepository,
+	mailer Mailer,
+	poster WebhookPoster,
+	notifier Notifier,
+	connectors ...StorageConnector,
+) Service {
+	byName := make(map[string]StorageConnector, len(connectors))
+	for _, c := range connectors {
+		byName[c.Name()] = c
+	}
+	return &service{
+		repo:       repo,
+		users:      users,
+		mailer:     mailer,
+		poster:     poster,
+		connectors: byName,
+		notifier:   notifier,
+	}
+}
+
+func (s *service) CreateSchedule(ctx context.Context, userID uint, req ScheduleRequest) (*models.ExportSchedule, error) {
+	existing, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxSchedulesPerUser {
+		return nil, ErrTooManySchedules
+	}
+
+	schedule := &models.ExportSchedule{
+		UserID:    userID,
+		Frequency: FrequencyMonthly,
+		Format:    FormatCSV,
+		Status:    models.ExportScheduleStatusActive,
+	}
+	if err := s.apply(schedule, req); err != nil {
+		return nil, err
+	}
+	schedule.NextRunAt = nextRun(time.Now(), schedule)
+
+	if err := s.repo.Create(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *service) ListSchedules(ctx context.Context, userID uint) ([]models.ExportSchedule, error) {
+	return s.repo.GetByUserID(userID)
+}
+
+func (s *service) UpdateSchedule(ctx context.Context, userID, scheduleID uint, req ScheduleRequest) (*models.ExportSchedule, error) {
+	schedule, err := s.repo.GetByIDAndUserID(scheduleID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	previousRun := nextRun(now, schedule)
+	previousStatus := schedule.Status
+	if err := s.apply(schedule, req); err != nil {
+		return nil, err
+	}
+
+	// Reschedule when the frequency or day changes or a paused schedule is
+	// resumed
+	resumed := previousStatus != models.ExportScheduleStatusActive && schedule.Status == models.ExportScheduleStatusActive
+	if next := nextRun(now, schedule); !next.Equal(previousRun) || resumed {
+		schedule.NextRunAt = next
+	}
+	if resumed {
+		schedule.ConsecutiveFailures = 0
+	}
+
+	if err := s.repo.Update(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *service) DeleteSchedule(ctx context.Context, userID, scheduleID uint) error {
+	return s.repo.Delete(scheduleID, userID)
+}
+
+func (s *service) GetRuns(ctx context.Context, userID, scheduleID uint, limit, offset int) ([]models.ExportRun, int64, error) {
+	schedule, err := s.repo.GetByIDAndUserID(scheduleID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetRuns(schedule.ID, limit, offset)
+}
+
+func (s *service) RunNow(ctx context.Context, userID, scheduleID uint) (*models.ExportRun, error) {
+	schedule, err := s.repo.GetByIDAndUserID(scheduleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, schedule, time.Now()), nil
+}
+
+func (s *service) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	schedules, err := s.repo.GetDue(now, dueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	for i := range schedules {
+		if ctx.Err() != nil {
+			return ran, ctx.Err()
+		}
+
+		schedule := &schedules[i]
+		claimed, err := s.repo.ClaimRun(schedule, nextRun(now, schedule))
+		if err != nil {
+			log.Printf("Failed to claim export schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Another instance picked it up
+		}
+
+		s.execute(ctx, schedule, now)
+		ran++
+	}
+	return ran, nil
+}
+
+// execute exports the calendar week or month before now and records the
+// outcome on the schedule
+func (s *service) execute(ctx context.Context, schedule *models.ExportSchedule, now time.Time) *models.ExportRun {
+	start, end := period(now, schedule.Frequency)
+
+	run := &models.ExportRun{
+		ScheduleID:  schedule.ID,
+		UserID:      schedule.UserID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+
+	location, rows, err := s.export(ctx, schedule, start, end)
+	run.RowCount = rows
+	if err != nil {
+		run.Status = models.ExportRunStatusFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = models.ExportRunStatusSucceeded
+		run.Location = location
+	}
+
+	if err := s.repo.CreateRun(run); err != nil {
+		log.Printf("Failed to record export run for schedule %d: %v", schedule.ID, err)
+	}
+
+	ranAt := time.Now()
+	schedule.LastRunAt = &ranAt
+	schedule.LastRunStatus = run.Status
+	if run.Status == models.ExportRunStatusFailed {
+		schedule.ConsecutiveFailures++
+		if schedule.ConsecutiveFailures >= MaxConsecutiveFailures {
+			schedule.Status = models.ExportScheduleStatusPaused
+		}
+		s.notifyFailure(ctx, schedule, run.Error)
+	} else {
+		schedule.ConsecutiveFailures = 0
+	}
+
+	if err := s.repo.Update(schedule); err != nil {
+		log.Printf("Failed to update export schedule %d: %v", schedule.ID, err)
+	}
+	return run
+}
+
+func (s *service) export(ctx context.Context, schedule *models.ExportSchedule, start, end time.Time) (string, int, error) {
+	transactions, err := s.repo.GetUserTransactionsBetween(schedule.UserID, start, end)
+	if err != nil {
+		return "", 0, err
+	}
+
+	content, err := writeCSV(schedule.UserID, transactions)
+	if err != nil {
+		return "", len(transactions), fmt.Errorf("failed to render export: %w", err)
+	}
+
+	fileName := fmt.Sprintf("transactions-%s.csv", start.Format("2006-01"))
+	label := start.Format("January 2006")
+	if schedule.Frequency == FrequencyWeekly {
+		fileName = fmt.Sprintf("transactions-%s.csv", start.Format("2006-01-02"))
+		label = "the week of " + start.Format("January 2, 2006")
+	}
+	attachment := Attachment{FileName: fileName, ContentType: "text/csv", Content: content}
+
+	switch schedule.Destination {
+	case models.ExportDestinationEmail:
+		err := s.mailer.SendAttachment(ctx, schedule.Email,
+			fmt.Sprintf("Your Orus transactions for %s", label),
+			fmt.Sprintf("Attached are your %d transactions for %s.", len(transactions), label),
+			attachment,
+		)
+		if err != nil {
+			return "", len(transactions), fmt.Errorf("failed to email export: %w", err)
+		}
+		return "mailto:" + schedule.Email, len(transactions), nil
+
+	case models.ExportDestinationStorage:
+		connector, ok := s.connectors[schedule.StorageConnector]
+		if !ok {
+			return "", len(transactions), ErrUnknownConnector
+		}
+		// Scope every user's exports to their own prefix
+		target := path.Join("users", fmt.Sprint(schedule.UserID), schedule.StoragePath, fileName)
+		location, err := connector.Upload(ctx, target, content)
+		if err != nil {
+			return "", len(transactions), fmt.Errorf("failed to upload export: %w", err)
+		}
+		return location, len(transactions), nil
+
+	case models.ExportDestinationWebhook:
+		if err := s.poster.Post(ctx, schedule.WebhookURL, schedule.WebhookSecret, attachment); err != nil {
+			return "", len(transactions), fmt.Errorf("failed to deliver export: %w", err)
+		}
+		return schedule.WebhookURL, len(transactions), nil
+
+	default:
+		return "", len(transactions), ErrInvalidDestination
+	}
+}
+
+func (s *service) notifyFailure(ctx context.Context, schedule *models.ExportSchedule, reason string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendExportFailedNotification(ctx, schedule.UserID, schedule, reason); err != nil {
+		log.Printf("Failed to notify user %d of export failure: %v", schedule.UserID, err)
+	}
+}
+
+// apply validates a request and copies it onto the schedule
+func (s *service) apply(schedule *models.ExportSchedule, req ScheduleRequest) error {
+	switch req.Frequency {
+	case "":
+	case FrequencyWeekly, FrequencyMonthly:
+		schedule.Frequency = req.Frequency
+	default:
+		return ErrInvalidFrequency
+	}
+	if req.DayOfMonth == 0 {
+		req.DayOfMonth = 1
+	}
+	if req.DayOfMonth < 1 || req.DayOfMonth > 28 {
+		return ErrInvalidDayOfMonth
+	}
+	if req.DayOfWeek == 0 {
+		req.DayOfWeek = 1
+	}
+	if req.DayOfWeek < 1 || req.DayOfWeek > 7 {
+		return ErrInvalidDayOfWeek
+	}
+
+	switch req.Status {
+	case "":
+	case models.ExportScheduleStatusActive, models.ExportScheduleStatusPaused:
+		schedule.Status = req.Status
+	default:
+		return ErrInvalidStatus
+	}
+
+	switch req.Destination {
+	case models.ExportDestinationEmail:
+		if req.Email == "" {
+			user, err := s.users.GetByID(schedule.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to get user email: %w", err)
+			}
+			req.Email = user.Email
+		}
+		schedule.Email = req.Email
+		schedule.StorageConnector = ""
+		schedule.StoragePath = ""
+		schedule.WebhookURL = ""
+	case models.ExportDestinationStorage:
+		if _, ok := s.connectors[req.StorageConnector]; !ok {
+			return ErrUnknownConnector
+		}
+		if req.StoragePath == "" {
+			return ErrStoragePathRequired
+		}
+		schedule.StorageConnector = req.StorageConnector
+		schedule.StoragePath = req.StoragePath
+		schedule.Email = ""
+		schedule.WebhookURL = ""
+	case models.ExportDestinationWebhook:
+		u, err := url.Parse(req.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return ErrInvalidWebhookURL
+		}
+		// Keep the secret across URL changes so receivers don't have to
+		// rotate it
+		if schedule.WebhookSecret == "" {
+			secret := make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return fmt.Errorf("failed to generate webhook secret: %w", err)
+			}
+			schedule.WebhookSecret = "whsec_" + hex.EncodeToString(secret)
+		}
+		schedule.WebhookURL = req.WebhookURL
+		schedule.Email = ""
+		schedule.StorageConnector = ""
+		schedule.StoragePath = ""
+	default:
+		return ErrInvalidDestination
+	}
+
+	schedule.DayOfMonth = req.DayOfMonth
+	schedule.DayOfWeek = req.DayOfWeek
+	schedule.Destination = req.Destination
+	return nil
+}
+
+// nextRun returns the schedule's next run day at runHour UTC strictly after
+// now
+func nextRun(now time.Time, schedule *models.ExportSchedule) time.Time {
+	now = now.UTC()
+	if schedule.Frequency == FrequencyWeekly {
+		candidate := time.Date(now.Year(), now.Month(), now.Day(), runHour, 0, 0, 0, time.UTC)
+		candidate = candidate.AddDate(0, 0, (schedule.DayOfWeek-isoWeekday(candidate)+7)%7)
+		if !candidate.After(now) {
+			candidate = candidate.AddDate(0, 0, 7)
+		}
+		return candidate
+	}
+
+	candidate := time.Date(now.Year(), now.Month(), schedule.DayOfMonth, runHour, 0, 0, 0, time.UTC)
+	if !candidate.After(now) {
+		candidate = candidate.AddDate(0, 1, 0)
+	}
+	return candidate
+}
+
+// period returns the last full calendar week (Monday to Sunday) or month
+// before now
+func period(now time.Time, frequency string) (time.Time, time.Time) {
+	now = now.UTC()
+	if frequency == FrequencyWeekly {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		end := today.AddDate(0, 0, 1-isoWeekday(today))
+		return end.AddDate(0, 0, -7), end
+	}
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return end.AddDate(0, -1, 0), end
+}
+
+// isoWeekday numbers the days of the week from Monday (1) to Sunday (7)
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
@@ -0,0 +1,28 @@
+package export
+
+// Supported schedule settings
+const (
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FormatCSV        = "csv"
+)
+
+// ScheduleRequest creates or updates an export schedule
+type ScheduleRequest struct {
+	Frequency        string `json:"frequency"`    // weekly or monthly
+	DayOfMonth       int    `json:"day_of_month"` // Monthly run day, 1 to 28
+	DayOfWeek        int    `json:"day_of_week"`  // Weekly run day, 1 (Monday) to 7
+	Destination      string `json:"destination"`
+	Email            string `json:"email"`
+	StorageConnector string `json:"storage_connector"`
+	StoragePath      string `json:"storage_path"`
+	WebhookURL       string `json:"webhook_url"`
+	Status           string `json:"status"`
+}
+
+// Attachment is a file sent with an export email
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
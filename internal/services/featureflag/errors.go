@@ -0,0 +1,8 @@
+package featureflag
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownFlag = errors.New("unknown feature flag")
+)
@@ -0,0 +1,21 @@
+package featureflag
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service decides which optional features are on. A flag starts from its
+// default for the environment, the configuration can turn it on or off,
+// and an admin override in the database takes precedence over both.
+type Service interface {
+	// Enabled reports whether the flag is on. Failing to read the
+	// overrides falls back to the configured state.
+	Enabled(ctx context.Context, key string) bool
+
+	List(ctx context.Context) ([]FlagState, error)
+	// SetOverride turns the flag on or off, regardless of the configuration
+	SetOverride(ctx context.Context, actorID uint, key string, req OverrideRequest) (*models.FeatureFlagOverride, error)
+	// DeleteOverride returns the flag to its configured state
+	DeleteOverride(ctx context.Context, key string) error
+}
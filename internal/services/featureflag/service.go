@@ -0,0 +1,171 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"slices"
+	"strings"
+	"time"
+)
+
+// overridesKey caches every flag override together; there are few
+const overridesKey = "feature_flags:overrides"
+
+// overridesTTL bounds how long overrides are cached; changes drop the
+// entry, so it only matters if that fails
+const overridesTTL = time.Minute
+
+type service struct {
+	repo       repositories.FeatureFlagRepository
+	cache      *cache.CacheService
+	configured map[string]configured
+}
+
+// configured is a flag's state before overrides
+type configured struct {
+	enabled bool
+	source  string
+}
+
+// NewService creates the feature flag service. Flags named in the
+// configuration must exist, so a typo fails at startup instead of leaving
+// a feature in the wrong state.
+func NewService(repo repositories.FeatureFlagRepository, cacheSvc *cache.CacheService, cfg Config) (Service, error) {
+	states := make(map[string]configured, len(Flags))
+	for _, flag := range Flags {
+		states[flag.Key] = configured{
+			enabled: flag.Environments == nil || slices.Contains(flag.Environments, cfg.Env),
+			source:  SourceDefault,
+		}
+	}
+	configure := func(keys []string, enabled bool) error {
+		for _, key := range keys {
+			key = strings.ToLower(strings.TrimSpace(key))
+			if _, ok := states[key]; !ok {
+				return fmt.Errorf("%w %q", ErrUnknownFlag, key)
+			}
+			states[key] = configured{enabled: enabled, source: SourceConfig}
+		}
+		return nil
+	}
+	if err := configure(cfg.Enabled, true); err != nil {
+		return nil, err
+	}
+	if err := configure(cfg.Disabled, false); err != nil {
+		return nil, err
+	}
+
+	return &service{
+		repo:       repo,
+		cache:      cacheSvc,
+		configured: states,
+	}, nil
+}
+
+func (s *service) Enabled(ctx context.Context, key string) bool {
+	state, ok := s.configured[key]
+	if !ok {
+		return false
+	}
+	overrides, err := s.overrides(ctx)
+	if err != nil {
+		log.Printf("Failed to load feature flag overrides: %v", err)
+	}
+	if enabled, ok := overrides[key]; ok {
+		return enabled
+	}
+	return state.enabled
+}
+
+func (s *service) overrides(ctx context.Context) (map[string]bool, error) {
+	var overrides map[string]bool
+	err := s.cache.Load(ctx, overridesKey, overridesTTL, &overrides, func() (interface{}, error) {
+		rows, err := s.repo.List()
+		if err != nil {
+			return nil, err
+		}
+		byKey := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			byKey[row.Key] = row.Enabled
+		}
+		return byKey, nil
+	})
+	return overrides, err
+}
+
+func (s *service) List(ctx context.Context) ([]FlagState, error) {
+	rows, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]models.FeatureFlagOverride, len(rows))
+	for _, row := range rows {
+		overrides[row.Key] = row
+	}
+
+	states := make([]FlagState, 0, len(Flags))
+	for _, flag := range Flags {
+		state := FlagState{
+			Key:         flag.Key,
+			Description: flag.Description,
+			Enabled:     s.configured[flag.Key].enabled,
+			Source:      s.configured[flag.Key].source,
+			Configured:  s.configured[flag.Key].enabled,
+		}
+		if override, ok := overrides[flag.Key]; ok {
+			state.Enabled = override.Enabled
+			state.Source = SourceOverride
+			state.Override = &override
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func (s *service) SetOverride(ctx context.Context, actorID uint, key string, req OverrideRequest) (*models.FeatureFlagOverride, error) {
+	if _, ok := s.configured[key]; !ok {
+		return nil, ErrUnknownFlag
+	}
+
+	override := &models.FeatureFlagOverride{
+		Key:       key,
+		Enabled:   req.Enabled,
+		Reason:    strings.TrimSpace(req.Reason),
+		UpdatedBy: actorID,
+	}
+	if err := s.repo.Save(override); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d turned feature flag %s %s", actorID, key, onOff(req.Enabled))
+	s.dropOverrides(ctx)
+	return override, nil
+}
+
+func (s *service) DeleteOverride(ctx context.Context, key string) error {
+	if _, ok := s.configured[key]; !ok {
+		return ErrUnknownFlag
+	}
+	if err := s.repo.Delete(key); err != nil {
+		return err
+	}
+	s.dropOverrides(ctx)
+	return nil
+}
+
+// dropOverrides makes the next check read the overrides afresh
+func (s *service) dropOverrides(ctx context.Context) {
+	if err := s.cache.Delete(ctx, overridesKey); err != nil {
+		log.Printf("Failed to drop cached feature flag overrides: %v", err)
+	}
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
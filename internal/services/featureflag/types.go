@@ -0,0 +1,63 @@
+package featureflag
+
+import "orus/internal/models"
+
+// Flag is a feature that can be switched on or off
+type Flag struct {
+	Key         string
+	Description string
+	// Environments the flag is on in by default; nil means all of them
+	Environments []string
+}
+
+// Flags lists every feature flag
+var Flags = []Flag{
+	{
+		Key:          models.FeatureDiagnostics,
+		Description:  "Admin diagnostics endpoints: token decoding, token versions and cache stats",
+		Environments: []string{"development", "test"},
+	},
+	{
+		Key:         models.FeatureGraphQL,
+		Description: "GraphQL gateway for client apps",
+	},
+	{
+		Key:         models.FeatureGoogleLogin,
+		Description: "Sign in with Google, when GOOGLE_CLIENT_ID is set",
+	},
+	{
+		Key:         models.FeatureAppleLogin,
+		Description: "Sign in with Apple, when APPLE_CLIENT_ID is set",
+	},
+}
+
+// Config turns flags on or off for this deployment, over their defaults
+type Config struct {
+	Env      string
+	Enabled  []string
+	Disabled []string
+}
+
+// OverrideRequest turns a flag on or off at runtime
+type OverrideRequest struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+// Where a flag's state comes from
+const (
+	SourceDefault  = "default"
+	SourceConfig   = "config"
+	SourceOverride = "override"
+)
+
+// FlagState shows admins a flag's state and what decided it
+type FlagState struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Source      string `json:"source"`
+	// Configured is the state without an override
+	Configured bool                        `json:"configured"`
+	Override   *models.FeatureFlagOverride `json:"override,omitempty"`
+}
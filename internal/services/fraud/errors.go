@@ -0,0 +1,34 @@
+package fraud
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Service errors
+var (
+	ErrDeclined         = errors.New("payment declined by fraud rules")
+	ErrAmountExceeded   = errors.New("charge amount exceeds the allowed maximum")
+	ErrVelocityExceeded = errors.New("customer has reached the daily charge limit")
+	ErrCountryBlocked   = errors.New("payments from the customer's country are blocked")
+	ErrInvalidAmount    = errors.New("max charge amount cannot be negative")
+	ErrInvalidVelocity  = errors.New("max charges per customer per day cannot be negative")
+	ErrInvalidCountry   = errors.New("blocked countries must be 2-letter ISO codes")
+	ErrTooManyCountries = errors.New("too many blocked countries")
+	ErrNotMerchant      = errors.New("merchant profile not found")
+)
+
+// Violation is a payment declined by a fraud rule. It matches ErrDeclined and
+// the specific rule error with errors.Is.
+type Violation struct {
+	Source string // SourceMerchant or SourcePlatform
+	Rule   error
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s rule", v.Rule.Error(), v.Source)
+}
+
+func (v *Violation) Unwrap() error { return v.Rule }
+
+func (v *Violation) Is(target error) bool { return target == ErrDeclined }
@@ -0,0 +1,16 @@
+package fraud
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages merchant fraud rules and screens payments against them
+type Service interface {
+	GetRules(ctx context.Context, merchantID uint) (*RulesView, error)
+	UpdateRules(ctx context.Context, merchantID uint, req UpdateRulesRequest) (*RulesView, error)
+
+	// Evaluate checks a payment against the receiving merchant's rules and
+	// then the platform rules, returning a *Violation if either declines it
+	Evaluate(ctx context.Context, tx *models.Transaction) error
+}
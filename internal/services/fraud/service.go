@@ -0,0 +1,241 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxBlockedCountries caps how many countries a merchant can block
+const MaxBlockedCountries = 50
+
+type service struct {
+	repo      repositories.FraudRuleRepository
+	users     repositories.UserRepository
+	merchants repositories.MerchantRepository
+	platform  PlatformRules
+}
+
+// NewService creates a new fraud rules service enforcing the given platform
+// rules alongside each merchant's own
+func NewService(repo repositories.FraudRuleRepository, users repositories.UserRepository, merchants repositories.MerchantRepository, platform PlatformRules) Service {
+	countries := make([]string, 0, len(platform.BlockedCountries))
+	for _, c := range platform.BlockedCountries {
+		if code, ok := models.NormalizeCountryCode(c); ok {
+			countries = append(countries, code)
+		}
+	}
+	platform.BlockedCountries = countries
+
+	return &service{
+		repo:      repo,
+		users:     users,
+		merchants: merchants,
+		platform:  platform,
+	}
+}
+
+func (s *service) GetRules(ctx context.Context, merchantID uint) (*RulesView, error) {
+	merchant, err := s.merchants.GetByUserID(merchantID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+	rules, err := s.merchantRules(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(merchant, rules), nil
+}
+
+// UpdateRules changes a merchant's rules. Merchants can set any value, but
+// a rule looser than the platform's has no effect until the platform relaxes.
+func (s *service) UpdateRules(ctx context.Context, merchantID uint, req UpdateRulesRequest) (*RulesView, error) {
+	merchant, err := s.merchants.GetByUserID(merchantID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+	rules, err := s.merchantRules(merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MaxChargeAmount != nil {
+		if *req.MaxChargeAmount < 0 {
+			return nil, ErrInvalidAmount
+		}
+		rules.MaxChargeAmount = math.Round(*req.MaxChargeAmount*100) / 100
+	}
+	if req.MaxChargesPerCustomerDaily != nil {
+		if *req.MaxChargesPerCustomerDaily < 0 {
+			return nil, ErrInvalidVelocity
+		}
+		rules.MaxChargesPerCustomerDaily = *req.MaxChargesPerCustomerDaily
+	}
+	if req.BlockedCountries != nil {
+		countries, err := normalizeCountries(*req.BlockedCountries)
+		if err != nil {
+			return nil, err
+		}
+		rules.BlockedCountries = countries
+	}
+
+	if err := s.repo.Save(rules); err != nil {
+		return nil, err
+	}
+	return s.view(merchant, rules), nil
+}
+
+// Evaluate screens payments into a merchant's wallet. Merchant rules run
+// first so a merchant sees their own rule as the reason when both would
+// decline; platform rules always run after, so the stricter limit wins.
+func (s *service) Evaluate(ctx context.Context, tx *models.Transaction) error {
+	merchant, err := s.merchants.GetByUserID(tx.ReceiverID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Not a merchant payment
+		}
+		return fmt.Errorf("failed to get merchant: %w", err)
+	}
+
+	rules, err := s.merchantRules(merchant.UserID)
+	if err != nil {
+		return err
+	}
+	platform := s.platformFor(merchant)
+
+	c := &check{service: s, tx: tx, merchantID: merchant.UserID}
+	if err := c.apply(SourceMerchant, rules.MaxChargeAmount, rules.MaxChargesPerCustomerDaily, rules.BlockedCountries); err != nil {
+		return err
+	}
+	return c.apply(SourcePlatform, platform.MaxChargeAmount, platform.MaxChargesPerCustomerDaily, platform.BlockedCountries)
+}
+
+// check caches payer lookups across the merchant and platform passes
+type check struct {
+	service    *service
+	tx         *models.Transaction
+	merchantID uint
+
+	charges      *int64
+	country      string
+	countryKnown bool
+}
+
+func (c *check) apply(source string, maxAmount float64, maxDaily int, blocked []string) error {
+	if maxAmount > 0 && c.tx.Amount > maxAmount {
+		return &Violation{Source: source, Rule: ErrAmountExceeded}
+	}
+
+	if maxDaily > 0 {
+		if c.charges == nil {
+			now := time.Now().UTC()
+			startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			count, err := c.service.repo.CountCustomerCharges(c.merchantID, c.tx.SenderID, startOfDay)
+			if err != nil {
+				return err
+			}
+			c.charges = &count
+		}
+		if *c.charges >= int64(maxDaily) {
+			return &Violation{Source: source, Rule: ErrVelocityExceeded}
+		}
+	}
+
+	if len(blocked) > 0 {
+		if !c.countryKnown {
+			user, err := c.service.users.GetByID(c.tx.SenderID)
+			if err != nil {
+				return fmt.Errorf("failed to get payer: %w", err)
+			}
+			c.country = user.Country
+			c.countryKnown = true
+		}
+		// Payers without a country on file can't be matched against a block list
+		if c.country != "" && models.CountryList(blocked).Contains(c.country) {
+			return &Violation{Source: source, Rule: ErrCountryBlocked}
+		}
+	}
+	return nil
+}
+
+func (s *service) merchantRules(merchantID uint) (*models.MerchantFraudRules, error) {
+	rules, err := s.repo.GetByMerchantID(merchantID)
+	if errors.Is(err, repositories.ErrFraudRulesNotFound) {
+		return &models.MerchantFraudRules{MerchantID: merchantID}, nil
+	}
+	return rules, err
+}
+
+// platformFor folds the merchant's platform-assigned transaction limit into
+// the global platform rules
+func (s *service) platformFor(merchant *models.Merchant) PlatformRules {
+	platform := s.platform
+	platform.MaxChargeAmount = stricterAmount(platform.MaxChargeAmount, merchant.MaxTransactionAmount)
+	return platform
+}
+
+func (s *service) view(merchant *models.Merchant, rules *models.MerchantFraudRules) *RulesView {
+	platform := s.platformFor(merchant)
+
+	blocked := make(map[string]bool)
+	for _, c := range rules.BlockedCountries {
+		blocked[c] = true
+	}
+	for _, c := range platform.BlockedCountries {
+		blocked[c] = true
+	}
+	countries := make([]string, 0, len(blocked))
+	for c := range blocked {
+		countries = append(countries, c)
+	}
+	sort.Strings(countries)
+
+	return &RulesView{
+		Merchant: rules,
+		Platform: platform,
+		Effective: EffectiveRules{
+			MaxChargeAmount:            stricterAmount(rules.MaxChargeAmount, platform.MaxChargeAmount),
+			MaxChargesPerCustomerDaily: int(stricterAmount(float64(rules.MaxChargesPerCustomerDaily), float64(platform.MaxChargesPerCustomerDaily))),
+			BlockedCountries:           countries,
+		},
+	}
+}
+
+// stricterAmount returns the lower of two limits, treating zero as no limit
+func stricterAmount(a, b float64) float64 {
+	switch {
+	case a <= 0:
+		return b
+	case b <= 0:
+		return a
+	default:
+		return math.Min(a, b)
+	}
+}
+
+func normalizeCountries(codes []string) (models.CountryList, error) {
+	seen := make(map[string]bool)
+	var countries models.CountryList
+	for _, c := range codes {
+		code, ok := models.NormalizeCountryCode(c)
+		if !ok {
+			return nil, ErrInvalidCountry
+		}
+		if !seen[code] {
+			seen[code] = true
+			countries = append(countries, code)
+		}
+	}
+	if len(countries) > MaxBlockedCountries {
+		return nil, ErrTooManyCountries
+	}
+	sort.Strings(countries)
+	return countries, nil
+}
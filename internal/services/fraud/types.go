@@ -0,0 +1,39 @@
+package fraud
+
+import "orus/internal/models"
+
+// Rule sources
+const (
+	SourceMerchant = "merchant"
+	SourcePlatform = "platform"
+)
+
+// PlatformRules are the fraud limits applied to every merchant.
+// Zero values leave a control switched off.
+type PlatformRules struct {
+	MaxChargeAmount            float64  `json:"max_charge_amount"`
+	MaxChargesPerCustomerDaily int      `json:"max_charges_per_customer_daily"`
+	BlockedCountries           []string `json:"blocked_countries"`
+}
+
+// EffectiveRules are the limits actually enforced for a merchant: the
+// stricter of the merchant and platform value for each control
+type EffectiveRules struct {
+	MaxChargeAmount            float64  `json:"max_charge_amount"`
+	MaxChargesPerCustomerDaily int      `json:"max_charges_per_customer_daily"`
+	BlockedCountries           []string `json:"blocked_countries"`
+}
+
+// RulesView shows a merchant their own rules next to what is enforced
+type RulesView struct {
+	Merchant  *models.MerchantFraudRules `json:"merchant"`
+	Platform  PlatformRules              `json:"platform"`
+	Effective EffectiveRules             `json:"effective"`
+}
+
+// UpdateRulesRequest changes the provided controls; omitted fields are kept
+type UpdateRulesRequest struct {
+	MaxChargeAmount            *float64  `json:"max_charge_amount"`
+	MaxChargesPerCustomerDaily *int      `json:"max_charges_per_customer_daily"`
+	BlockedCountries           *[]string `json:"blocked_countries"`
+}
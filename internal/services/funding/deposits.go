@@ -0,0 +1,220 @@
+package funding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+func (s *service) ListDeposits(ctx context.Context, userID uint, limit, offset int) ([]models.Deposit, int64, error) {
+	return s.depositRepo.GetByUserID(userID, limit, offset)
+}
+
+func (s *service) GetDeposit(ctx context.Context, userID, depositID uint) (*DepositDetails, error) {
+	deposit, err := s.depositRepo.GetByIDAndUserID(depositID, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.depositRepo.GetEvents(deposit.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &DepositDetails{Deposit: deposit, Events: events}, nil
+}
+
+// HandleDepositEvent applies a provider status update to a deposit. Events
+// are idempotent by event ID; stale or out-of-order events are recorded but
+// leave the deposit unchanged.
+func (s *service) HandleDepositEvent(ctx context.Context, event DepositEvent) (*models.Deposit, error) {
+	if event.EventID == "" || event.TransferID == "" {
+		return nil, ErrInvalidDepositEvent
+	}
+	switch event.Status {
+	case TransferStatusPending, TransferStatusSettled, TransferStatusFailed, TransferStatusReturned:
+	default:
+		return nil, ErrInvalidDepositEvent
+	}
+
+	var deposit *models.Deposit
+	var applied bool
+	err := s.depositRepo.ExecuteInTransaction(func(deposits repositories.DepositRepository, repo repositories.WalletRepository) error {
+		if _, err := deposits.GetEventByEventID(event.EventID); err == nil {
+			return ErrDuplicateDepositEvent
+		} else if !errors.Is(err, repositories.ErrDepositEventNotFound) {
+			return err
+		}
+
+		// Lock the deposit so concurrent events for it apply one at a time
+		d, err := deposits.GetByProviderTransferIDForUpdate(event.TransferID)
+		if err != nil {
+			return err
+		}
+		deposit = d
+
+		applied, err = s.applyDepositEvent(deposits, repo, d, event)
+		if err != nil {
+			return err
+		}
+
+		return deposits.CreateEvent(&models.DepositEvent{
+			DepositID:  d.ID,
+			EventID:    event.EventID,
+			Status:     event.Status,
+			ReturnCode: event.ReturnCode,
+			Applied:    applied,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if applied {
+		s.invalidateWallet(ctx, deposit.UserID)
+		s.notifyDeposit(ctx, deposit, deposit.Status)
+	}
+	return deposit, nil
+}
+
+// applyDepositEvent moves the deposit through its lifecycle and reports
+// whether the event changed anything
+func (s *service) applyDepositEvent(
+	deposits repositories.DepositRepository,
+	repo repositories.WalletRepository,
+	deposit *models.Deposit,
+	event DepositEvent,
+) (bool, error) {
+	inFlight := deposit.Status == models.DepositStatusInitiated || deposit.Status == models.DepositStatusPending
+	now := time.Now()
+
+	switch event.Status {
+	case TransferStatusPending:
+		if deposit.Status != models.DepositStatusInitiated {
+			return false, nil
+		}
+		deposit.Status = models.DepositStatusPending
+
+	case TransferStatusSettled:
+		if !inFlight {
+			return false, nil
+		}
+		if _, err := repo.AdjustBalance(deposit.UserID, deposit.Amount); err != nil {
+			return false, err
+		}
+		if err := deposits.UpdateTransactionStatus(deposit.TransactionID, "completed"); err != nil {
+			return false, err
+		}
+		deposit.Status = models.DepositStatusSettled
+		deposit.Credited = true
+		deposit.SettledAt = &now
+
+	case TransferStatusFailed:
+		if !inFlight {
+			return false, nil
+		}
+		if err := deposits.UpdateTransactionStatus(deposit.TransactionID, "failed"); err != nil {
+			return false, err
+		}
+		deposit.Status = models.DepositStatusFailed
+
+	case TransferStatusReturned:
+		if deposit.Status == models.DepositStatusReturned || deposit.Status == models.DepositStatusFailed {
+			return false, nil
+		}
+		deposit.ReturnCode = event.ReturnCode
+		deposit.ReturnReason = event.ReturnReason
+		deposit.ReturnedAt = &now
+
+		if deposit.Credited {
+			if err := s.reverseDeposit(deposits, repo, deposit, now); err != nil {
+				return false, err
+			}
+		} else if err := deposits.UpdateTransactionStatus(deposit.TransactionID, "failed"); err != nil {
+			return false, err
+		}
+		deposit.Status = models.DepositStatusReturned
+	}
+
+	return true, deposits.Update(deposit)
+}
+
+// reverseDeposit debits a returned deposit back out of the wallet. If the
+// funds were already spent the balance goes negative, to be repaid by later
+// top-ups, and an NSF fee is recorded on top.
+func (s *service) reverseDeposit(
+	deposits repositories.DepositRepository,
+	repo repositories.WalletRepository,
+	deposit *models.Deposit,
+	now time.Time,
+) error {
+	// The return is the bank's to make, so it may overdraw the wallet
+	wallet, err := repo.ForceAdjustBalance(deposit.UserID, -deposit.Amount)
+	if err != nil {
+		return err
+	}
+	spent := wallet.Balance < 0
+
+	reversal := &models.Transaction{
+		Type:          "debit",
+		SenderID:      deposit.UserID,
+		Amount:        deposit.Amount,
+		Currency:      deposit.Currency,
+		Status:        "completed",
+		TransactionID: fmt.Sprintf("ACH-RET-%d-%d", deposit.ID, now.UnixNano()),
+		Reference:     deposit.ProviderTransferID,
+		PaymentType:   "ach_return",
+		PaymentMethod: "ach",
+		Category:      "Reversal",
+		Description:   "Returned bank deposit",
+		ProcessedAt:   now,
+		Metadata: models.NewJSON(map[string]interface{}{
+			"reason":      "ach_return",
+			"source":      "bank_deposit",
+			"deposit_id":  deposit.ID,
+			"return_code": deposit.ReturnCode,
+		}),
+	}
+	if err := repo.CreateTransaction(reversal); err != nil {
+		return err
+	}
+	deposit.ReversalTransactionID = &reversal.ID
+
+	if spent && s.nsfFee > 0 {
+		if _, err := repo.ForceAdjustBalance(deposit.UserID, -s.nsfFee); err != nil {
+			return err
+		}
+		fee := &models.Transaction{
+			Type:          "fee",
+			SenderID:      deposit.UserID,
+			Amount:        s.nsfFee,
+			Currency:      deposit.Currency,
+			Status:        "completed",
+			TransactionID: fmt.Sprintf("NSF-%d-%d", deposit.ID, now.UnixNano()),
+			Reference:     deposit.ProviderTransferID,
+			PaymentType:   "nsf_fee",
+			Category:      "Fee",
+			Description:   "Returned deposit fee",
+			ProcessedAt:   now,
+			Metadata: models.NewJSON(map[string]interface{}{
+				"fee_type":    "nsf",
+				"deposit_id":  deposit.ID,
+				"return_code": deposit.ReturnCode,
+			}),
+		}
+		if err := repo.CreateTransaction(fee); err != nil {
+			return err
+		}
+		deposit.FeeTransactionID = &fee.ID
+	}
+
+	return deposits.UpdateTransactionStatus(deposit.TransactionID, "reversed")
+}
+
+func (s *service) notifyDeposit(ctx context.Context, deposit *models.Deposit, status string) {
+	if err := s.notifier.SendDepositNotification(ctx, deposit.UserID, deposit, status); err != nil {
+		log.Printf("Failed to notify user %d about deposit %d: %v", deposit.UserID, deposit.ID, err)
+	}
+}
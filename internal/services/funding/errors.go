@@ -0,0 +1,17 @@
+package funding
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidAmount         = errors.New("invalid amount")
+	ErrInvalidAccountDetails = errors.New("invalid bank account details")
+	ErrAccountNotVerified    = errors.New("bank account is not verified")
+	ErrAlreadyVerified       = errors.New("bank account is already verified")
+	ErrVerificationFailed    = errors.New("micro-deposit amounts do not match")
+	ErrTooManyAttempts       = errors.New("too many verification attempts")
+	ErrInvalidPublicToken    = errors.New("invalid public token")
+	ErrTransferFailed        = errors.New("bank transfer failed")
+	ErrInvalidDepositEvent   = errors.New("invalid deposit event")
+	ErrDuplicateDepositEvent = errors.New("deposit event already processed")
+)
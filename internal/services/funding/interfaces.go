@@ -0,0 +1,59 @@
+package funding
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Provider is a Plaid-style bank data and ACH provider
+type Provider interface {
+	// Name identifies the provider on stored accounts
+	Name() string
+
+	// ExchangePublicToken completes instant auth and returns the verified account
+	ExchangePublicToken(ctx context.Context, publicToken string) (*ProviderAccount, error)
+
+	// CreateAccount registers account and routing numbers for micro-deposit verification
+	CreateAccount(ctx context.Context, details AccountDetails) (*ProviderAccount, error)
+
+	// SendMicroDeposits sends two small deposits to the account
+	SendMicroDeposits(ctx context.Context, providerAccountID string) error
+
+	// VerifyMicroDeposits checks the amounts reported by the user
+	VerifyMicroDeposits(ctx context.Context, providerAccountID string, amounts [2]float64) (bool, error)
+
+	// Debit pulls funds from the bank account over ACH
+	Debit(ctx context.Context, providerAccountID string, amount float64, reference string) (*Transfer, error)
+
+	// Credit pushes funds to the bank account over ACH
+	Credit(ctx context.Context, providerAccountID string, amount float64, reference string) (*Transfer, error)
+}
+
+// WalletService defines the wallet operations used by the funding service
+type WalletService interface {
+	ValidateBalance(ctx context.Context, userID uint, amount float64) error
+	EnforceLock(ctx context.Context, wallet *models.Wallet, operation, direction string, amount float64) error
+}
+
+// Notifier tells users about their deposit's progress
+type Notifier interface {
+	SendDepositNotification(ctx context.Context, userID uint, deposit *models.Deposit, status string) error
+}
+
+// Service manages linked bank accounts and ACH movements
+type Service interface {
+	LinkInstant(ctx context.Context, userID uint, publicToken string) (*models.BankAccount, error)
+	LinkManual(ctx context.Context, userID uint, details AccountDetails) (*models.BankAccount, error)
+	VerifyMicroDeposits(ctx context.Context, userID, accountID uint, amounts [2]float64) (*models.BankAccount, error)
+	ListAccounts(ctx context.Context, userID uint) ([]models.BankAccount, error)
+	RemoveAccount(ctx context.Context, userID, accountID uint) error
+	TopUp(ctx context.Context, userID, accountID uint, amount float64) (*models.Transaction, error)
+	Withdraw(ctx context.Context, userID, accountID uint, amount float64) (*models.Transaction, error)
+
+	ListDeposits(ctx context.Context, userID uint, limit, offset int) ([]models.Deposit, int64, error)
+	GetDeposit(ctx context.Context, userID, depositID uint) (*DepositDetails, error)
+
+	// HandleDepositEvent applies a provider webhook to a deposit, crediting
+	// the wallet on settlement and reversing the credit on return
+	HandleDepositEvent(ctx context.Context, event DepositEvent) (*models.Deposit, error)
+}
@@ -0,0 +1,90 @@
+package funding
+
+import (
+	"context"
+	"errors"
+	"orus/internal/resilience"
+)
+
+// resilientProvider guards a provider with a circuit breaker and retries
+// the calls that are safe to repeat: reads, and ACH transfers, which the
+// provider deduplicates by reference
+type resilientProvider struct {
+	Provider
+	breaker *resilience.Breaker
+	policy  resilience.Policy
+}
+
+// WithResilience wraps p so that a provider outage fails calls fast
+// instead of stalling the requests waiting on it
+func WithResilience(p Provider, breaker *resilience.Breaker, policy resilience.Policy) Provider {
+	return &resilientProvider{Provider: p, breaker: breaker, policy: policy}
+}
+
+// ProviderBreakerConfig doesn't count the provider rejecting a request,
+// such as a bad public token, as the provider being down
+var ProviderBreakerConfig = resilience.BreakerConfig{
+	FailureThreshold: resilience.DefaultBreakerConfig.FailureThreshold,
+	Cooldown:         resilience.DefaultBreakerConfig.Cooldown,
+	IsFailure: func(err error) bool {
+		return !errors.Is(err, ErrInvalidPublicToken) &&
+			!resilience.IsPermanent(err) &&
+			!errors.Is(err, context.Canceled)
+	},
+}
+
+func (p *resilientProvider) ExchangePublicToken(ctx context.Context, publicToken string) (*ProviderAccount, error) {
+	var account *ProviderAccount
+	err := p.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		account, err = p.Provider.ExchangePublicToken(ctx, publicToken)
+		return err
+	})
+	return account, err
+}
+
+func (p *resilientProvider) CreateAccount(ctx context.Context, details AccountDetails) (*ProviderAccount, error) {
+	var account *ProviderAccount
+	err := p.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		account, err = p.Provider.CreateAccount(ctx, details)
+		return err
+	})
+	return account, err
+}
+
+func (p *resilientProvider) SendMicroDeposits(ctx context.Context, providerAccountID string) error {
+	return p.breaker.Do(ctx, func(ctx context.Context) error {
+		return p.Provider.SendMicroDeposits(ctx, providerAccountID)
+	})
+}
+
+func (p *resilientProvider) VerifyMicroDeposits(ctx context.Context, providerAccountID string, amounts [2]float64) (bool, error) {
+	var ok bool
+	err := resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		var err error
+		ok, err = p.Provider.VerifyMicroDeposits(ctx, providerAccountID, amounts)
+		return err
+	})
+	return ok, err
+}
+
+func (p *resilientProvider) Debit(ctx context.Context, providerAccountID string, amount float64, reference string) (*Transfer, error) {
+	var transfer *Transfer
+	err := resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		var err error
+		transfer, err = p.Provider.Debit(ctx, providerAccountID, amount, reference)
+		return err
+	})
+	return transfer, err
+}
+
+func (p *resilientProvider) Credit(ctx context.Context, providerAccountID string, amount float64, reference string) (*Transfer, error) {
+	var transfer *Transfer
+	err := resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		var err error
+		transfer, err = p.Provider.Credit(ctx, providerAccountID, amount, reference)
+		return err
+	})
+	return transfer, err
+}
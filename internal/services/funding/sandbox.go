@@ -0,0 +1,137 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sandbox micro-deposit amounts, fixed so integrators can verify accounts in tests
+const (
+	SandboxMicroDepositOne = 0.32
+	SandboxMicroDepositTwo = 0.45
+)
+
+// SandboxPublicTokenPrefix marks public tokens accepted by the sandbox provider
+const SandboxPublicTokenPrefix = "public-sandbox-"
+
+// SandboxProvider simulates a bank data provider in memory.
+// Instant auth accepts any token with SandboxPublicTokenPrefix, micro-deposits
+// are always SandboxMicroDepositOne and SandboxMicroDepositTwo, and ACH
+// transfers settle immediately unless the amount ends in .13 (simulated
+// failure) or .17 (left pending for a webhook to settle or return).
+type SandboxProvider struct {
+	mu       sync.Mutex
+	accounts map[string]*ProviderAccount
+	deposits map[string][2]float64
+}
+
+// NewSandboxProvider creates an in-memory sandbox provider
+func NewSandboxProvider() *SandboxProvider {
+	return &SandboxProvider{
+		accounts: make(map[string]*ProviderAccount),
+		deposits: make(map[string][2]float64),
+	}
+}
+
+func (p *SandboxProvider) Name() string { return "sandbox" }
+
+func (p *SandboxProvider) ExchangePublicToken(ctx context.Context, publicToken string) (*ProviderAccount, error) {
+	if !strings.HasPrefix(publicToken, SandboxPublicTokenPrefix) {
+		return nil, ErrInvalidPublicToken
+	}
+
+	account := &ProviderAccount{
+		ID:              fmt.Sprintf("acct_sandbox_%d", time.Now().UnixNano()),
+		InstitutionName: "Sandbox Bank",
+		Mask:            "0000",
+		AccountType:     "checking",
+		HolderName:      "Sandbox User",
+		Verified:        true,
+	}
+
+	p.mu.Lock()
+	p.accounts[account.ID] = account
+	p.mu.Unlock()
+	return account, nil
+}
+
+func (p *SandboxProvider) CreateAccount(ctx context.Context, details AccountDetails) (*ProviderAccount, error) {
+	account := &ProviderAccount{
+		ID:              fmt.Sprintf("acct_sandbox_%d", time.Now().UnixNano()),
+		InstitutionName: "Sandbox Bank",
+		Mask:            details.AccountNumber[len(details.AccountNumber)-4:],
+		AccountType:     details.AccountType,
+		HolderName:      details.HolderName,
+	}
+
+	p.mu.Lock()
+	p.accounts[account.ID] = account
+	p.mu.Unlock()
+	return account, nil
+}
+
+func (p *SandboxProvider) SendMicroDeposits(ctx context.Context, providerAccountID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.accounts[providerAccountID]; !ok {
+		return fmt.Errorf("sandbox account %s not found", providerAccountID)
+	}
+	p.deposits[providerAccountID] = [2]float64{SandboxMicroDepositOne, SandboxMicroDepositTwo}
+	return nil
+}
+
+func (p *SandboxProvider) VerifyMicroDeposits(ctx context.Context, providerAccountID string, amounts [2]float64) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The sandbox is stateless across restarts, so fall back to the fixed amounts
+	expected, ok := p.deposits[providerAccountID]
+	if !ok {
+		expected = [2]float64{SandboxMicroDepositOne, SandboxMicroDepositTwo}
+	}
+
+	match := (amounts[0] == expected[0] && amounts[1] == expected[1]) ||
+		(amounts[0] == expected[1] && amounts[1] == expected[0])
+	if match {
+		if account, ok := p.accounts[providerAccountID]; ok {
+			account.Verified = true
+		}
+		delete(p.deposits, providerAccountID)
+	}
+	return match, nil
+}
+
+func (p *SandboxProvider) Debit(ctx context.Context, providerAccountID string, amount float64, reference string) (*Transfer, error) {
+	return p.transfer("debit", amount, reference), nil
+}
+
+func (p *SandboxProvider) Credit(ctx context.Context, providerAccountID string, amount float64, reference string) (*Transfer, error) {
+	return p.transfer("credit", amount, reference), nil
+}
+
+func (p *SandboxProvider) transfer(direction string, amount float64, reference string) *Transfer {
+	status := TransferStatusSettled
+	switch int64(amount*100+0.5) % 100 {
+	case 13:
+		status = TransferStatusFailed
+	case 17:
+		status = TransferStatusPending
+	}
+	return &Transfer{
+		ID:     fmt.Sprintf("ach_sandbox_%s_%s", direction, reference),
+		Status: status,
+	}
+}
+
+// NewProvider returns the provider configured by name
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case "", "sandbox":
+		return NewSandboxProvider(), nil
+	default:
+		return nil, fmt.Errorf("unsupported bank provider: %s", name)
+	}
+}
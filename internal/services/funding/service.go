@@ -0,0 +1,379 @@
+package funding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
+	"time"
+)
+
+const (
+	// MaxVerificationAttempts bounds micro-deposit guesses per account
+	MaxVerificationAttempts = 3
+	// MaxACHAmount is the largest single ACH top-up or withdrawal
+	MaxACHAmount = 25000.0
+	// DefaultNSFFee is charged when a returned deposit had already been spent
+	DefaultNSFFee = 15.0
+)
+
+type service struct {
+	repo        repositories.BankAccountRepository
+	depositRepo repositories.DepositRepository
+	walletRepo  repositories.WalletRepository
+	walletSvc   WalletService
+	provider    Provider
+	notifier    Notifier
+	cache       *cache.CacheService
+	nsfFee      float64
+}
+
+// NewService creates a new funding source service. nsfFee is charged when a
+// returned deposit had already been spent from the wallet.
+func NewService(
+	repo repositories.BankAccountRepository,
+	depositRepo repositories.DepositRepository,
+	walletRepo repositories.WalletRepository,
+	walletSvc WalletService,
+	provider Provider,
+	notifier Notifier,
+	cache *cache.CacheService,
+	nsfFee float64,
+) Service {
+	return &service{
+		repo:        repo,
+		depositRepo: depositRepo,
+		walletRepo:  walletRepo,
+		walletSvc:   walletSvc,
+		provider:    provider,
+		notifier:    notifier,
+		cache:       cache,
+		nsfFee:      nsfFee,
+	}
+}
+
+// LinkInstant links and verifies an account in one step using a provider public token
+func (s *service) LinkInstant(ctx context.Context, userID uint, publicToken string) (*models.BankAccount, error) {
+	providerAccount, err := s.provider.ExchangePublicToken(ctx, publicToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	account := &models.BankAccount{
+		UserID:             userID,
+		Provider:           s.provider.Name(),
+		ProviderAccountID:  providerAccount.ID,
+		InstitutionName:    providerAccount.InstitutionName,
+		AccountMask:        providerAccount.Mask,
+		AccountType:        providerAccount.AccountType,
+		HolderName:         providerAccount.HolderName,
+		Status:             models.BankAccountStatusVerified,
+		VerificationMethod: models.VerificationMethodInstant,
+		VerifiedAt:         &now,
+	}
+	if err := s.repo.Create(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// LinkManual registers raw account details and starts micro-deposit verification
+func (s *service) LinkManual(ctx context.Context, userID uint, details AccountDetails) (*models.BankAccount, error) {
+	if len(details.RoutingNumber) != 9 || len(details.AccountNumber) < 4 || details.HolderName == "" {
+		return nil, ErrInvalidAccountDetails
+	}
+	if details.AccountType == "" {
+		details.AccountType = "checking"
+	}
+
+	providerAccount, err := s.provider.CreateAccount(ctx, details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register bank account: %w", err)
+	}
+
+	if err := s.provider.SendMicroDeposits(ctx, providerAccount.ID); err != nil {
+		return nil, fmt.Errorf("failed to send micro-deposits: %w", err)
+	}
+
+	account := &models.BankAccount{
+		UserID:             userID,
+		Provider:           s.provider.Name(),
+		ProviderAccountID:  providerAccount.ID,
+		InstitutionName:    providerAccount.InstitutionName,
+		AccountMask:        providerAccount.Mask,
+		AccountType:        providerAccount.AccountType,
+		HolderName:         providerAccount.HolderName,
+		Status:             models.BankAccountStatusPendingVerification,
+		VerificationMethod: models.VerificationMethodMicroDeposits,
+	}
+	if err := s.repo.Create(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// VerifyMicroDeposits confirms account ownership with the two deposited amounts
+func (s *service) VerifyMicroDeposits(ctx context.Context, userID, accountID uint, amounts [2]float64) (*models.BankAccount, error) {
+	account, err := s.repo.GetByIDAndUserID(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch account.Status {
+	case models.BankAccountStatusVerified:
+		return nil, ErrAlreadyVerified
+	case models.BankAccountStatusFailed:
+		return nil, ErrTooManyAttempts
+	}
+
+	ok, err := s.provider.VerifyMicroDeposits(ctx, account.ProviderAccountID, amounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify micro-deposits: %w", err)
+	}
+
+	account.VerificationAttempts++
+	if ok {
+		now := time.Now()
+		account.Status = models.BankAccountStatusVerified
+		account.VerifiedAt = &now
+	} else if account.VerificationAttempts >= MaxVerificationAttempts {
+		account.Status = models.BankAccountStatusFailed
+	}
+
+	if err := s.repo.Update(account); err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrVerificationFailed
+	}
+	return account, nil
+}
+
+func (s *service) ListAccounts(ctx context.Context, userID uint) ([]models.BankAccount, error) {
+	return s.repo.GetByUserID(userID)
+}
+
+// RemoveAccount unlinks an account; history stays attached to the record
+func (s *service) RemoveAccount(ctx context.Context, userID, accountID uint) error {
+	account, err := s.repo.GetByIDAndUserID(accountID, userID)
+	if err != nil {
+		return err
+	}
+	account.Status = models.BankAccountStatusRemoved
+	account.IsDefault = false
+	return s.repo.Update(account)
+}
+
+// TopUp pulls funds from a verified bank account into the wallet.
+// The wallet is credited once the provider reports the ACH debit as settled;
+// pending transfers are recorded as deposits and settled by provider webhooks.
+func (s *service) TopUp(ctx context.Context, userID, accountID uint, amount float64) (*models.Transaction, error) {
+	account, err := s.getVerifiedAccount(userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 || amount > MaxACHAmount {
+		return nil, ErrInvalidAmount
+	}
+	amount = math.Round(amount*100) / 100
+
+	reference := fmt.Sprintf("ACH-IN-%d-%d", userID, time.Now().UnixNano())
+	transfer, err := s.provider.Debit(ctx, account.ProviderAccountID, amount, reference)
+	if err != nil {
+		if errors.Is(err, resilience.ErrCircuitOpen) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrTransferFailed, err)
+	}
+
+	tx := &models.Transaction{
+		Type:          "top_up",
+		SenderID:      userID,
+		ReceiverID:    0, // No receiver for top-ups
+		Amount:        amount,
+		TransactionID: reference,
+		Reference:     transfer.ID,
+		PaymentType:   "bank_topup",
+		PaymentMethod: "ach",
+		Category:      "Top Up",
+		Description:   fmt.Sprintf("Top up from %s account ending in %s", account.InstitutionName, account.AccountMask),
+		Metadata:      s.transferMetadata(account, transfer),
+	}
+
+	deposit := &models.Deposit{
+		UserID:             userID,
+		BankAccountID:      account.ID,
+		ProviderTransferID: transfer.ID,
+		Amount:             amount,
+		Currency:           account.Currency,
+	}
+
+	err = s.depositRepo.ExecuteInTransaction(func(deposits repositories.DepositRepository, repo repositories.WalletRepository) error {
+		switch transfer.Status {
+		case TransferStatusFailed:
+			tx.Status = "failed"
+			deposit.Status = models.DepositStatusFailed
+		case TransferStatusPending:
+			tx.Status = "pending"
+			deposit.Status = models.DepositStatusPending
+		default:
+			if _, err := repo.AdjustBalance(userID, amount); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			tx.Status = "completed"
+			tx.ProcessedAt = now
+			deposit.Status = models.DepositStatusSettled
+			deposit.Credited = true
+			deposit.SettledAt = &now
+		}
+
+		if err := repo.CreateTransaction(tx); err != nil {
+			return err
+		}
+		deposit.TransactionID = tx.ID
+		return deposits.Create(deposit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateWallet(ctx, userID)
+	s.notifyDeposit(ctx, deposit, models.DepositStatusInitiated)
+	s.notifyDeposit(ctx, deposit, deposit.Status)
+
+	if transfer.Status == TransferStatusFailed {
+		return tx, ErrTransferFailed
+	}
+	return tx, nil
+}
+
+// Withdraw debits the wallet and pushes funds to a verified bank account.
+// If the provider rejects the transfer the wallet is refunded.
+func (s *service) Withdraw(ctx context.Context, userID, accountID uint, amount float64) (*models.Transaction, error) {
+	account, err := s.getVerifiedAccount(userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 || amount > MaxACHAmount {
+		return nil, ErrInvalidAmount
+	}
+	amount = math.Round(amount*100) / 100
+
+	if err := s.walletSvc.ValidateBalance(ctx, userID, amount); err != nil {
+		return nil, err
+	}
+
+	reference := fmt.Sprintf("ACH-OUT-%d-%d", userID, time.Now().UnixNano())
+	tx := &models.Transaction{
+		Type:          "withdrawal",
+		SenderID:      userID,
+		ReceiverID:    0,
+		Amount:        amount,
+		Status:        "pending",
+		TransactionID: reference,
+		PaymentType:   "bank_withdrawal",
+		PaymentMethod: "ach",
+		Category:      "Withdrawal",
+		Description:   fmt.Sprintf("Withdrawal to %s account ending in %s", account.InstitutionName, account.AccountMask),
+	}
+
+	// Debit first so the funds can't be spent while the ACH credit is in flight
+	err = s.walletRepo.ExecuteInTransaction(func(repo repositories.WalletRepository) error {
+		return s.adjustBalance(ctx, repo, userID, -amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateWallet(ctx, userID)
+
+	transfer, err := s.provider.Credit(ctx, account.ProviderAccountID, amount, reference)
+	if err != nil || transfer.Status == TransferStatusFailed {
+		if transfer == nil {
+			transfer = &Transfer{Status: TransferStatusFailed}
+		}
+		tx.Status = "failed"
+		tx.Metadata = s.transferMetadata(account, transfer)
+
+		refundErr := s.walletRepo.ExecuteInTransaction(func(repo repositories.WalletRepository) error {
+			if err := s.adjustBalance(ctx, repo, userID, amount); err != nil {
+				return err
+			}
+			return repo.CreateTransaction(tx)
+		})
+		if refundErr != nil {
+			log.Printf("CRITICAL: failed to refund wallet for user %d after failed ACH withdrawal %s: %v",
+				userID, reference, refundErr)
+		}
+		s.invalidateWallet(ctx, userID)
+		if errors.Is(err, resilience.ErrCircuitOpen) {
+			return tx, err
+		}
+		return tx, ErrTransferFailed
+	}
+
+	tx.Reference = transfer.ID
+	tx.Metadata = s.transferMetadata(account, transfer)
+	if transfer.Status == TransferStatusSettled {
+		tx.Status = "completed"
+		tx.ProcessedAt = time.Now()
+	}
+	if err := s.walletRepo.CreateTransaction(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *service) getVerifiedAccount(userID, accountID uint) (*models.BankAccount, error) {
+	account, err := s.repo.GetByIDAndUserID(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != models.BankAccountStatusVerified {
+		return nil, ErrAccountNotVerified
+	}
+	return account, nil
+}
+
+// adjustBalance moves the wallet's balance by delta. Only debits are held
+// to the wallet's lock, checked with the row locked so it can't change
+// before the debit: a credit here returns a failed withdrawal's funds.
+func (s *service) adjustBalance(ctx context.Context, repo repositories.WalletRepository, userID uint, delta float64) error {
+	if delta < 0 {
+		wallet, err := repo.LockByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if err := s.walletSvc.EnforceLock(ctx, wallet, "bank_withdrawal", models.WalletDirectionDebit, -delta); err != nil {
+			return err
+		}
+	}
+	_, err := repo.AdjustBalance(userID, delta)
+	return err
+}
+
+func (s *service) transferMetadata(account *models.BankAccount, transfer *Transfer) models.JSON {
+	return models.NewJSON(map[string]interface{}{
+		"bank_account_id":      account.ID,
+		"account_mask":         account.AccountMask,
+		"institution_name":     account.InstitutionName,
+		"provider":             account.Provider,
+		"provider_transfer_id": transfer.ID,
+		"provider_status":      transfer.Status,
+	})
+}
+
+func (s *service) invalidateWallet(ctx context.Context, userID uint) {
+	key := s.cache.GenerateKey("wallet", "user", userID)
+	if err := s.cache.Delete(ctx, key); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,50 @@
+package funding
+
+import "orus/internal/models"
+
+// AccountDetails are the raw bank details used for manual linking
+type AccountDetails struct {
+	HolderName    string `json:"holder_name"`
+	RoutingNumber string `json:"routing_number"`
+	AccountNumber string `json:"account_number"`
+	AccountType   string `json:"account_type"`
+}
+
+// ProviderAccount is the provider's view of a linked account
+type ProviderAccount struct {
+	ID              string
+	InstitutionName string
+	Mask            string
+	AccountType     string
+	HolderName      string
+	Verified        bool
+}
+
+// Transfer statuses reported by providers
+const (
+	TransferStatusPending  = "pending"
+	TransferStatusSettled  = "settled"
+	TransferStatusFailed   = "failed"
+	TransferStatusReturned = "returned"
+)
+
+// Transfer is an ACH movement initiated with the provider
+type Transfer struct {
+	ID     string
+	Status string
+}
+
+// DepositEvent is a provider status update for an ACH deposit
+type DepositEvent struct {
+	EventID      string `json:"event_id"`
+	TransferID   string `json:"transfer_id"`
+	Status       string `json:"status"`
+	ReturnCode   string `json:"return_code"`
+	ReturnReason string `json:"return_reason"`
+}
+
+// DepositDetails is a deposit with its provider event history
+type DepositDetails struct {
+	*models.Deposit
+	Events []models.DepositEvent `json:"events"`
+}
@@ -0,0 +1,71 @@
+package fxrates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ECBDailyURL is the European Central Bank's daily reference rates feed
+const ECBDailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECBProvider reads the European Central Bank's euro reference rates,
+// published once each working day around 16:00 CET
+type ECBProvider struct {
+	client *http.Client
+	url    string
+}
+
+// ecbEnvelope is the feed's document, with the day's rates nested in
+// Cube elements
+type ecbEnvelope struct {
+	Cube struct {
+		Day struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func (p *ECBProvider) Name() string { return "ecb" }
+
+func (p *ECBProvider) Latest(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ECB request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ECB rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ECB rates returned status %d", resp.StatusCode)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse ECB rates: %w", err)
+	}
+	asOf, err := time.Parse("2006-01-02", envelope.Cube.Day.Time)
+	if err != nil || len(envelope.Cube.Day.Rates) == 0 {
+		return nil, ErrInvalidRates
+	}
+
+	rates := map[string]float64{"EUR": 1}
+	for _, r := range envelope.Cube.Day.Rates {
+		rates[r.Currency] = r.Rate
+	}
+	return &Rates{
+		Base:      "EUR",
+		Rates:     rates,
+		Provider:  p.Name(),
+		AsOf:      asOf,
+		FetchedAt: time.Now().UTC(),
+	}, nil
+}
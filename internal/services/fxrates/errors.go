@@ -0,0 +1,10 @@
+package fxrates
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnsupportedCurrency = errors.New("no exchange rate for currency")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrInvalidRates        = errors.New("provider returned invalid exchange rates")
+)
@@ -0,0 +1,79 @@
+package fxrates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// FixerLatestURL is Fixer's latest rates endpoint
+const FixerLatestURL = "https://data.fixer.io/api/latest"
+
+// FixerProvider reads rates from Fixer, which plans without base
+// switching publish against the euro
+type FixerProvider struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+// fixerResponse is Fixer's reply; failures come back with a 200 status,
+// Success false and the reason in Error
+type fixerResponse struct {
+	Success   bool               `json:"success"`
+	Timestamp int64              `json:"timestamp"`
+	Base      string             `json:"base"`
+	Rates     map[string]float64 `json:"rates"`
+	Error     *struct {
+		Code int    `json:"code"`
+		Type string `json:"type"`
+	} `json:"error"`
+}
+
+func (p *FixerProvider) Name() string { return "fixer" }
+
+func (p *FixerProvider) Latest(ctx context.Context) (*Rates, error) {
+	endpoint := p.url + "?" + url.Values{"access_key": {p.apiKey}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Fixer request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The URL carries the API key, so it is left out of the error
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to fetch Fixer rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fixer rates returned status %d", resp.StatusCode)
+	}
+
+	var body fixerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse Fixer rates: %w", err)
+	}
+	if !body.Success {
+		if body.Error != nil {
+			return nil, fmt.Errorf("Fixer rates failed: %d %s", body.Error.Code, body.Error.Type)
+		}
+		return nil, ErrInvalidRates
+	}
+	if body.Base == "" || len(body.Rates) == 0 {
+		return nil, ErrInvalidRates
+	}
+
+	body.Rates[body.Base] = 1
+	return &Rates{
+		Base:      body.Base,
+		Rates:     body.Rates,
+		Provider:  p.Name(),
+		AsOf:      time.Unix(body.Timestamp, 0).UTC(),
+		FetchedAt: time.Now().UTC(),
+	}, nil
+}
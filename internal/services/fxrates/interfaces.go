@@ -0,0 +1,28 @@
+package fxrates
+
+import "context"
+
+// Provider publishes reference exchange rates, such as the ECB's daily
+// rates or Fixer's
+type Provider interface {
+	// Name identifies the provider on conversion transactions
+	Name() string
+
+	// Latest returns the provider's current rates against its own base
+	Latest(ctx context.Context) (*Rates, error)
+}
+
+// Service quotes exchange rates and converts amounts between currencies.
+// Rates are cached, so a quote and a conversion made soon after it use
+// the same rate.
+type Service interface {
+	// Rates returns the rates from base to each of symbols, or to every
+	// currency the provider publishes when symbols is empty
+	Rates(ctx context.Context, base string, symbols []string) (*Rates, error)
+
+	// Quote returns the rate converting from into to
+	Quote(ctx context.Context, from, to string) (*Quote, error)
+
+	// Convert converts amount of from into to at the current rate
+	Convert(ctx context.Context, amount float64, from, to string) (*Conversion, error)
+}
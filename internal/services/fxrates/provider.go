@@ -0,0 +1,65 @@
+package fxrates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// NewProvider returns the rate provider named in the configuration.
+// Fixer needs an API key; the ECB's reference rates are public.
+func NewProvider(name, apiKey string) (Provider, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	switch name {
+	case "", "sandbox":
+		return NewSandboxProvider(), nil
+	case "ecb":
+		return &ECBProvider{client: client, url: ECBDailyURL}, nil
+	case "fixer":
+		if apiKey == "" {
+			return nil, fmt.Errorf("fixer needs an API key")
+		}
+		return &FixerProvider{client: client, url: FixerLatestURL, apiKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate provider: %s", name)
+	}
+}
+
+// sandboxRates are fixed so integrators get the same conversions in tests
+var sandboxRates = map[string]float64{
+	"USD": 1,
+	"EUR": 0.92,
+	"GBP": 0.79,
+	"CAD": 1.36,
+	"JPY": 151.5,
+	"CHF": 0.9,
+	"XOF": 603.5,
+	"NGN": 1480,
+	"KES": 130,
+}
+
+// SandboxProvider serves fixed rates against the US dollar
+type SandboxProvider struct{}
+
+// NewSandboxProvider creates the sandbox rate provider
+func NewSandboxProvider() *SandboxProvider {
+	return &SandboxProvider{}
+}
+
+func (p *SandboxProvider) Name() string { return "sandbox" }
+
+func (p *SandboxProvider) Latest(ctx context.Context) (*Rates, error) {
+	rates := make(map[string]float64, len(sandboxRates))
+	for currency, rate := range sandboxRates {
+		rates[currency] = rate
+	}
+	now := time.Now().UTC()
+	return &Rates{
+		Base:      "USD",
+		Rates:     rates,
+		Provider:  p.Name(),
+		AsOf:      now.Truncate(24 * time.Hour),
+		FetchedAt: now,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package fxrates
+
+import (
+	"context"
+	"orus/internal/resilience"
+)
+
+// resilientProvider guards a provider with a circuit breaker and retries
+// reads, which are always safe to repeat
+type resilientProvider struct {
+	Provider
+	breaker *resilience.Breaker
+	policy  resilience.Policy
+}
+
+// WithResilience wraps p so that a provider outage fails quotes fast
+// instead of stalling the requests waiting on them
+func WithResilience(p Provider, breaker *resilience.Breaker, policy resilience.Policy) Provider {
+	return &resilientProvider{Provider: p, breaker: breaker, policy: policy}
+}
+
+func (p *resilientProvider) Latest(ctx context.Context) (*Rates, error) {
+	var rates *Rates
+	err := resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		var err error
+		rates, err = p.Provider.Latest(ctx)
+		return err
+	})
+	return rates, err
+}
@@ -0,0 +1,153 @@
+package fxrates
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"orus/internal/repositories/cache"
+	"strings"
+	"time"
+)
+
+type service struct {
+	provider Provider
+	cache    *cache.CacheService
+	ttl      time.Duration
+}
+
+// NewService creates the exchange rate service. The provider's rates are
+// cached for about ttl, so it is called at most that often.
+func NewService(provider Provider, cache *cache.CacheService, ttl time.Duration) Service {
+	return &service{
+		provider: provider,
+		cache:    cache,
+		ttl:      ttl,
+	}
+}
+
+func ratesKey(provider string) string {
+	return fmt.Sprintf("fx:rates:%s", provider)
+}
+
+// latest returns the provider's rates, from the cache while they last
+func (s *service) latest(ctx context.Context) (*Rates, error) {
+	var rates Rates
+	err := s.cache.Load(ctx, ratesKey(s.provider.Name()), s.ttl, &rates, func() (interface{}, error) {
+		latest, err := s.provider.Latest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(latest.Rates) == 0 {
+			return nil, ErrInvalidRates
+		}
+		return latest, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rates.ExpiresAt = rates.FetchedAt.Add(s.ttl)
+	return &rates, nil
+}
+
+// rate converts one unit of from into to using the provider's table,
+// going through its base when neither currency is it
+func rate(table *Rates, from, to string) (float64, error) {
+	fromRate, ok := table.Rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, ErrUnsupportedCurrency
+	}
+	toRate, ok := table.Rates[to]
+	if !ok || toRate <= 0 {
+		return 0, ErrUnsupportedCurrency
+	}
+	return roundRate(toRate / fromRate), nil
+}
+
+func (s *service) Rates(ctx context.Context, base string, symbols []string) (*Rates, error) {
+	base = normalize(base)
+	if base == "" {
+		base = DefaultBase
+	}
+	table, err := s.latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := table.Rates[base]; !ok {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	if len(symbols) == 0 {
+		for currency := range table.Rates {
+			symbols = append(symbols, currency)
+		}
+	}
+	rates := make(map[string]float64, len(symbols))
+	for _, symbol := range symbols {
+		symbol = normalize(symbol)
+		if symbol == "" || symbol == base {
+			continue
+		}
+		r, err := rate(table, base, symbol)
+		if err != nil {
+			return nil, err
+		}
+		rates[symbol] = r
+	}
+	return &Rates{
+		Base:      base,
+		Rates:     rates,
+		Provider:  table.Provider,
+		AsOf:      table.AsOf,
+		FetchedAt: table.FetchedAt,
+		ExpiresAt: table.ExpiresAt,
+	}, nil
+}
+
+func (s *service) Quote(ctx context.Context, from, to string) (*Quote, error) {
+	from, to = normalize(from), normalize(to)
+	table, err := s.latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := rate(table, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{
+		From:     from,
+		To:       to,
+		Rate:     r,
+		Provider: table.Provider,
+		AsOf:     table.AsOf,
+	}, nil
+}
+
+func (s *service) Convert(ctx context.Context, amount float64, from, to string) (*Conversion, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	quote, err := s.Quote(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// The rate is applied as recorded, so the transaction shows exactly
+	// how the converted amount was reached
+	converted := math.Round(amount*quote.Rate*100) / 100
+	if converted <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return &Conversion{
+		Quote:     *quote,
+		Amount:    amount,
+		Converted: converted,
+	}, nil
+}
+
+func normalize(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+func roundRate(r float64) float64 {
+	scale := math.Pow10(rateDecimals)
+	return math.Round(r*scale) / scale
+}
@@ -0,0 +1,52 @@
+package fxrates
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// DefaultBase is the base of rates requested without one
+const DefaultBase = "USD"
+
+// rateDecimals is the precision rates are applied and recorded at
+const rateDecimals = 8
+
+// Rates holds how much of each currency one unit of Base buys
+type Rates struct {
+	Base     string             `json:"base"`
+	Rates    map[string]float64 `json:"rates"`
+	Provider string             `json:"provider"`
+	// AsOf is when the provider published the rates; FetchedAt is when
+	// they were read from it
+	AsOf      time.Time `json:"as_of"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// ExpiresAt is when the rates are next read from the provider, until
+	// which quotes stay the same
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Quote is the rate converting one unit of From into To
+type Quote struct {
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Rate     float64   `json:"rate"`
+	Provider string    `json:"provider"`
+	AsOf     time.Time `json:"as_of"`
+}
+
+// Conversion is an amount converted at a quote's rate, which conversion
+// transactions record
+type Conversion struct {
+	Quote
+	Amount    float64 `json:"amount"`
+	Converted float64 `json:"converted"`
+}
+
+// Record stamps the conversion on the transaction moving the money: its
+// rate, provider and the amount credited in the other currency
+func (c *Conversion) Record(tx *models.Transaction) {
+	tx.FXRate = c.Rate
+	tx.FXProvider = c.Provider
+	tx.ConvertedAmount = c.Converted
+	tx.ConvertedCurrency = c.To
+}
@@ -0,0 +1,13 @@
+package handle
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidHandle     = errors.New("handles are 3-20 characters of lowercase letters, digits, dots or underscores, starting with a letter")
+	ErrReservedHandle    = errors.New("handle is reserved")
+	ErrHandleTaken       = errors.New("handle already taken")
+	ErrNoHandle          = errors.New("you have not claimed a handle")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrInvalidIdentifier = errors.New("enter an @handle or phone number")
+)
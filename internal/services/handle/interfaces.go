@@ -0,0 +1,16 @@
+package handle
+
+import "context"
+
+// Service manages payment @handles and who can find a user by handle or
+// phone number
+type Service interface {
+	Claim(ctx context.Context, userID uint, handle string) (*Settings, error)
+	Release(ctx context.Context, userID uint) (*Settings, error)
+	GetSettings(ctx context.Context, userID uint) (*Settings, error)
+	UpdatePrivacy(ctx context.Context, userID uint, req PrivacyRequest) (*Settings, error)
+
+	// Resolve finds who to pay from an @handle or phone number. Users who
+	// hide themselves are reported as not found, the same as unknown ones.
+	Resolve(ctx context.Context, requesterID uint, identifier string) (*Recipient, error)
+}
@@ -0,0 +1,161 @@
+package handle
+
+import (
+	"context"
+	"errors"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_.]{2,19}$`)
+
+// reservedHandles can't be claimed so nobody can pose as the platform
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "api": true, "help": true,
+	"orus": true, "oruspay": true, "root": true, "security": true,
+	"support": true, "system": true, "treasury": true,
+}
+
+type service struct {
+	users repositories.UserRepository
+}
+
+// NewService creates a new handle service
+func NewService(users repositories.UserRepository) Service {
+	return &service{users: users}
+}
+
+func (s *service) Claim(ctx context.Context, userID uint, handle string) (*Settings, error) {
+	handle = Normalize(handle)
+	if !handlePattern.MatchString(handle) || strings.Contains(handle, "..") {
+		return nil, ErrInvalidHandle
+	}
+	if reservedHandles[handle] {
+		return nil, ErrReservedHandle
+	}
+
+	if err := s.users.SetHandle(userID, &handle); err != nil {
+		if errors.Is(err, repositories.ErrHandleTaken) {
+			return nil, ErrHandleTaken
+		}
+		return nil, err
+	}
+	return s.GetSettings(ctx, userID)
+}
+
+func (s *service) Release(ctx context.Context, userID uint) (*Settings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.Handle == nil {
+		return nil, ErrNoHandle
+	}
+	if err := s.users.SetHandle(userID, nil); err != nil {
+		return nil, err
+	}
+	settings.Handle = nil
+	return settings, nil
+}
+
+func (s *service) GetSettings(ctx context.Context, userID uint) (*Settings, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Settings{
+		Handle:             user.Handle,
+		HandleDiscoverable: user.HandleDiscoverable,
+		PhoneDiscoverable:  user.PhoneDiscoverable,
+	}, nil
+}
+
+func (s *service) UpdatePrivacy(ctx context.Context, userID uint, req PrivacyRequest) (*Settings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.HandleDiscoverable != nil {
+		settings.HandleDiscoverable = *req.HandleDiscoverable
+	}
+	if req.PhoneDiscoverable != nil {
+		settings.PhoneDiscoverable = *req.PhoneDiscoverable
+	}
+
+	if err := s.users.UpdateDiscoverability(userID, settings.HandleDiscoverable, settings.PhoneDiscoverable); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *service) Resolve(ctx context.Context, requesterID uint, identifier string) (*Recipient, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return nil, ErrInvalidIdentifier
+	}
+
+	var (
+		user *models.User
+		err  error
+	)
+	byPhone := isPhoneNumber(identifier)
+	if byPhone {
+		user, err = s.users.GetByPhone(strings.NewReplacer(" ", "", "-", "").Replace(identifier))
+	} else {
+		user, err = s.users.GetByHandle(Normalize(identifier))
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+
+	if user.ID != requesterID {
+		if user.Status != "active" ||
+			(byPhone && !user.PhoneDiscoverable) ||
+			(!byPhone && !user.HandleDiscoverable) {
+			return nil, ErrRecipientNotFound
+		}
+	}
+
+	recipient := &Recipient{UserID: user.ID, DisplayName: displayName(user.Name)}
+	if user.Handle != nil && user.HandleDiscoverable {
+		recipient.Handle = *user.Handle
+	}
+	return recipient, nil
+}
+
+// Normalize lowercases a handle and strips a leading @
+func Normalize(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// isPhoneNumber treats anything made only of digits, spaces, dashes and an
+// optional leading + as a phone number
+func isPhoneNumber(s string) bool {
+	digits := 0
+	for i, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case r == '+' && i == 0, r == ' ', r == '-':
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// displayName shortens a full name to the first name and last initial
+func displayName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) < 2 {
+		return name
+	}
+	last := []rune(parts[len(parts)-1])
+	return parts[0] + " " + string(last[0]) + "."
+}
@@ -0,0 +1,22 @@
+package handle
+
+// Settings is a user's handle and discoverability
+type Settings struct {
+	Handle             *string `json:"handle"`
+	HandleDiscoverable bool    `json:"handle_discoverable"`
+	PhoneDiscoverable  bool    `json:"phone_discoverable"`
+}
+
+// PrivacyRequest changes discoverability; unset fields are left alone
+type PrivacyRequest struct {
+	HandleDiscoverable *bool `json:"handle_discoverable"`
+	PhoneDiscoverable  *bool `json:"phone_discoverable"`
+}
+
+// Recipient is what a payer learns about a resolved user: enough to confirm
+// they have the right person, but not their contact details
+type Recipient struct {
+	UserID      uint   `json:"user_id"`
+	Handle      string `json:"handle,omitempty"`
+	DisplayName string `json:"display_name"`
+}
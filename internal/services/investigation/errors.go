@@ -0,0 +1,6 @@
+package investigation
+
+import "errors"
+
+// Service errors
+var ErrTransactionNotFound = errors.New("transaction not found")
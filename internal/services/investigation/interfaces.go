@@ -0,0 +1,11 @@
+package investigation
+
+import "context"
+
+// Service assembles everything linked to a transaction for fraud analysts
+type Service interface {
+	// Graph returns the transaction with its refunds, reversals, disputes,
+	// ledger entries, QR code, the login it was made from, risk signals and
+	// both parties' recent activity
+	Graph(ctx context.Context, transactionID uint) (*Graph, error)
+}
@@ -0,0 +1,256 @@
+package investigation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/merchantrisk"
+	"strconv"
+	"time"
+)
+
+type service struct {
+	repo         repositories.InvestigationRepository
+	users        repositories.UserRepository
+	transactions repositories.TransactionRepository
+	activity     repositories.UserActivityRepository
+	risk         merchantrisk.Service
+}
+
+// NewService creates the transaction investigation service
+func NewService(
+	repo repositories.InvestigationRepository,
+	users repositories.UserRepository,
+	transactions repositories.TransactionRepository,
+	activity repositories.UserActivityRepository,
+	risk merchantrisk.Service,
+) Service {
+	return &service{
+		repo:         repo,
+		users:        users,
+		transactions: transactions,
+		activity:     activity,
+		risk:         risk,
+	}
+}
+
+func (s *service) Graph(ctx context.Context, transactionID uint) (*Graph, error) {
+	tx, archived, err := s.repo.GetTransaction(transactionID)
+	if errors.Is(err, repositories.ErrInvestigationTransactionNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	graph := &Graph{Transaction: tx, Archived: archived}
+
+	if graph.Reversals, err = s.repo.GetReversals(tx.ID); err != nil {
+		return nil, err
+	}
+	if graph.Linked, err = s.linked(tx, graph.Reversals); err != nil {
+		return nil, err
+	}
+	if graph.Disputes, err = s.repo.GetDisputes(tx.ID); err != nil {
+		return nil, err
+	}
+	if graph.Chargebacks, err = s.repo.GetChargebacks(tx.ID); err != nil {
+		return nil, err
+	}
+	if graph.LedgerEntries, err = s.repo.GetLedgerEntries(tx.ID); err != nil {
+		return nil, err
+	}
+	if tx.QRCodeID != nil && *tx.QRCodeID != "" {
+		if graph.QRCode, err = s.repo.GetQRCode(*tx.QRCodeID); err != nil {
+			return nil, err
+		}
+	}
+	if tx.SenderID != 0 {
+		if graph.Login, err = s.repo.GetLoginBefore(tx.SenderID, tx.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	users, err := s.users.GetByIDs([]uint{tx.SenderID, tx.ReceiverID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parties: %w", err)
+	}
+	for _, user := range users {
+		party, err := s.party(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		// A user paying themselves, such as a top-up, is both parties
+		if user.ID == tx.SenderID {
+			graph.Sender = party
+		}
+		if user.ID == tx.ReceiverID {
+			graph.Receiver = party
+		}
+	}
+
+	graph.Signals = signals(graph)
+	return graph, nil
+}
+
+// linked returns the transaction this one refunds, reverses or charges
+// back, then the transactions that do so to it
+func (s *service) linked(tx *models.Transaction, reversals []models.TransactionReversal) ([]Linked, error) {
+	seen := map[uint]bool{tx.ID: true}
+	var originalIDs []uint
+	addOriginal := func(id uint) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			originalIDs = append(originalIDs, id)
+		}
+	}
+	switch tx.Type {
+	case models.TransactionTypeRefund, models.TransactionTypeReversal, models.TransactionTypeChargeback:
+		if id, err := strconv.ParseUint(tx.Reference, 10, 32); err == nil {
+			addOriginal(uint(id))
+		}
+	}
+	if v, ok := tx.Metadata.Map()["original_transaction_id"]; ok {
+		if id, err := strconv.ParseUint(fmt.Sprint(v), 10, 32); err == nil {
+			addOriginal(uint(id))
+		}
+	}
+	for _, r := range reversals {
+		if r.ReversalTransactionID == tx.ID {
+			addOriginal(r.OriginalTransactionID)
+		}
+	}
+
+	originals, err := s.repo.GetTransactions(originalIDs)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := s.repo.GetLinked(tx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make([]Linked, 0, len(originals)+len(dependents))
+	for _, t := range originals {
+		linked = append(linked, Linked{Relation: RelationOriginal, Transaction: t})
+	}
+	for _, t := range dependents {
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		linked = append(linked, Linked{Relation: relation(t.Type), Transaction: t})
+	}
+	return linked, nil
+}
+
+func relation(transactionType string) string {
+	switch transactionType {
+	case models.TransactionTypeRefund:
+		return RelationRefund
+	case models.TransactionTypeReversal:
+		return RelationReversal
+	case models.TransactionTypeChargeback:
+		return RelationChargeback
+	default:
+		return RelationRelated
+	}
+}
+
+func (s *service) party(ctx context.Context, user *models.User) (*Party, error) {
+	party := &Party{
+		UserID:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Country:   user.Country,
+		Role:      user.Role,
+		Status:    user.Status,
+		KYCStatus: user.KYCStatus,
+		CreatedAt: user.CreatedAt,
+	}
+
+	profile, err := s.risk.GetProfile(ctx, user.ID)
+	switch {
+	case err == nil:
+		party.MerchantRisk = profile
+	case !errors.Is(err, merchantrisk.ErrMerchantNotFound):
+		return nil, err
+	}
+
+	now := time.Now()
+	if party.RecentTransactions, err = s.transactions.GetUserTransactionsBefore(user.ID, now, recentLimit); err != nil {
+		return nil, err
+	}
+	if party.RecentLogins, err = s.repo.GetRecentLogins(user.ID, recentLimit); err != nil {
+		return nil, err
+	}
+	if party.RecentActivity, err = s.activity.ListByUser(user.ID, now, recentLimit); err != nil {
+		return nil, err
+	}
+	return party, nil
+}
+
+// signals lists what an analyst should look at first
+func signals(g *Graph) []string {
+	tx := g.Transaction
+	signals := []string{}
+
+	if tx.Status == "reversed" {
+		signals = append(signals, "transaction was reversed")
+	}
+	var refunded float64
+	for _, l := range g.Linked {
+		if l.Relation == RelationRefund && l.Transaction.Status == "completed" {
+			refunded += l.Transaction.Amount
+		}
+	}
+	switch {
+	case refunded > 0 && refunded >= tx.Amount:
+		signals = append(signals, "refunded in full")
+	case refunded > 0:
+		signals = append(signals, "partly refunded")
+	}
+	if len(g.Disputes) > 0 {
+		signals = append(signals, "disputed by the payer")
+	}
+	if len(g.Chargebacks) > 0 {
+		signals = append(signals, "charged back")
+	}
+
+	if g.Login != nil {
+		if g.Login.Status == models.LoginStatusReported {
+			signals = append(signals, "made after a login the payer reported as not them")
+		}
+		if g.Login.NewDevice {
+			signals = append(signals, "made after a login from a new device")
+		}
+		if g.Login.NewCountry {
+			signals = append(signals, "made after a login from a new country")
+		}
+	}
+
+	if p := g.Sender; p != nil {
+		if tx.CreatedAt.Sub(p.CreatedAt) < newAccountAge {
+			signals = append(signals, "payer account was under a week old")
+		}
+		if p.Status == models.UserStatusSuspended {
+			signals = append(signals, "payer is suspended")
+		}
+	}
+	if p := g.Receiver; p != nil {
+		if p.Status == models.UserStatusSuspended {
+			signals = append(signals, "receiver is suspended")
+		}
+		if p.MerchantRisk != nil {
+			switch p.MerchantRisk.Status {
+			case models.MerchantRiskReview:
+				signals = append(signals, "merchant is awaiting risk review")
+			case models.MerchantRiskTightened:
+				signals = append(signals, "merchant limits tightened on risk")
+			}
+		}
+	}
+	return signals
+}
@@ -0,0 +1,70 @@
+package investigation
+
+import (
+	"orus/internal/models"
+	"orus/internal/services/merchantrisk"
+	"time"
+)
+
+const (
+	// recentLimit caps each party's recent transactions, logins and
+	// account events
+	recentLimit = 10
+
+	// newAccountAge flags payers whose account is younger than this
+	newAccountAge = 7 * 24 * time.Hour
+)
+
+// How a linked transaction relates to the one investigated
+const (
+	RelationOriginal   = "original" // The transaction this one refunds, reverses or charges back
+	RelationRefund     = "refund"
+	RelationReversal   = "reversal"
+	RelationChargeback = "chargeback"
+	RelationRelated    = "related"
+)
+
+// Graph is the linked view of a transaction
+type Graph struct {
+	Transaction *models.Transaction          `json:"transaction"`
+	Archived    bool                         `json:"archived"`
+	Linked      []Linked                     `json:"linked"`
+	Reversals   []models.TransactionReversal `json:"reversals"`
+	Disputes    []models.Dispute             `json:"disputes"`
+	Chargebacks []models.Chargeback          `json:"chargebacks"`
+	// LedgerEntries are the platform ledger postings, such as fees, the
+	// transaction booked
+	LedgerEntries []models.SystemLedgerEntry `json:"ledger_entries"`
+	QRCode        *models.QRCode             `json:"qr_code,omitempty"`
+	// Login is the payer's last login before the transaction: the device
+	// and IP it was most likely made from
+	Login *models.LoginEvent `json:"login,omitempty"`
+	// Signals lists what stands out about the transaction
+	Signals  []string `json:"signals"`
+	Sender   *Party   `json:"sender,omitempty"`
+	Receiver *Party   `json:"receiver,omitempty"`
+}
+
+// Linked is a transaction connected to the one investigated
+type Linked struct {
+	Relation    string             `json:"relation"`
+	Transaction models.Transaction `json:"transaction"`
+}
+
+// Party is one side of the transaction and what they have been doing lately
+type Party struct {
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Country   string    `json:"country,omitempty"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	KYCStatus string    `json:"kyc_status"`
+	CreatedAt time.Time `json:"created_at"`
+	// MerchantRisk is the party's merchant risk standing, when they are one
+	MerchantRisk       *merchantrisk.Profile `json:"merchant_risk,omitempty"`
+	RecentTransactions []models.Transaction  `json:"recent_transactions"`
+	RecentLogins       []models.LoginEvent   `json:"recent_logins"`
+	RecentActivity     []models.UserActivity `json:"recent_activity"`
+}
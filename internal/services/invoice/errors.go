@@ -0,0 +1,18 @@
+package invoice
+
+import "errors"
+
+// Service errors
+var (
+	ErrNoLineItems     = errors.New("invoice must have at least one line item")
+	ErrInvalidLineItem = errors.New("line items need a description, positive quantity and non-negative price")
+	ErrInvalidTaxRate  = errors.New("tax rate must be between 0 and 100")
+	ErrInvalidEmail    = errors.New("a valid customer email is required")
+	ErrInvalidDueDate  = errors.New("due date must be in the future")
+	ErrInvalidTotal    = errors.New("invoice total must be greater than zero")
+	ErrNotMerchant     = errors.New("merchant profile not found")
+	ErrNotEditable     = errors.New("only draft invoices can be changed")
+	ErrNotPayable      = errors.New("invoice is not payable")
+	ErrCannotVoid      = errors.New("paid or void invoices cannot be voided")
+	ErrSelfPayment     = errors.New("merchants cannot pay their own invoices")
+)
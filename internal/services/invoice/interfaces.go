@@ -0,0 +1,41 @@
+package invoice
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/receipt"
+)
+
+// TransactionService defines the payment processing used to settle invoices
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
+
+// ReceiptService issues itemized receipts for paid invoices
+type ReceiptService interface {
+	Issue(ctx context.Context, tx *models.Transaction, details receipt.Details) (*models.Receipt, error)
+}
+
+// Notifier emails invoices, reminders and receipts to customers
+type Notifier interface {
+	SendInvoiceEmail(ctx context.Context, to string, invoice *models.Invoice, kind, payURL string) error
+}
+
+// Service manages merchant invoices and their payment by customers
+type Service interface {
+	CreateInvoice(ctx context.Context, merchantID uint, req InvoiceRequest) (*models.Invoice, error)
+	ListInvoices(ctx context.Context, merchantID uint, status string, limit, offset int) ([]models.Invoice, int64, error)
+	GetInvoice(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, error)
+	UpdateInvoice(ctx context.Context, merchantID, invoiceID uint, req InvoiceRequest) (*models.Invoice, error)
+	DeleteInvoice(ctx context.Context, merchantID, invoiceID uint) error
+	SendInvoice(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, error)
+	VoidInvoice(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, error)
+	RenderPDF(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, []byte, error)
+
+	GetPublicInvoice(ctx context.Context, code string) (*PublicInvoice, error)
+	RenderPublicPDF(ctx context.Context, code string) (*models.Invoice, []byte, error)
+	PayInvoice(ctx context.Context, userID uint, code string) (*PaymentResult, error)
+
+	// ProcessDue marks overdue invoices and sends due reminders
+	ProcessDue(ctx context.Context) (overdue int64, reminders int, err error)
+}
@@ -0,0 +1,24 @@
+package invoice
+
+import (
+	"context"
+	"log"
+)
+
+// Job marks overdue invoices and sends payment reminders
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the invoice service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "invoice-reminders" }
+
+func (j *Job) Run(ctx context.Context) error {
+	overdue, reminders, err := j.service.ProcessDue(ctx)
+	if overdue > 0 || reminders > 0 {
+		log.Printf("Marked %d invoices overdue and sent %d reminders", overdue, reminders)
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package invoice
+
+import (
+	"fmt"
+	"orus/internal/i18n"
+	"orus/internal/models"
+	"orus/internal/utils/pdf"
+	"strings"
+)
+
+// renderPDF lays the invoice out as a printable PDF in lang
+func renderPDF(inv *models.Invoice, merchantName, lang string) []byte {
+	return pdf.Render(invoiceLines(inv, merchantName, lang))
+}
+
+func invoiceLines(inv *models.Invoice, merchantName, lang string) []string {
+	t := func(key string, args ...interface{}) string { return i18n.T(lang, key, args...) }
+	decimals := i18n.CurrencyDecimals(inv.Currency)
+	money := func(amount float64) string { return i18n.FormatNumber(lang, amount, decimals) }
+	label := func(key string) string { return fmt.Sprintf("%-10s", t(key)+":") }
+
+	rule := strings.Repeat("-", pdf.LineWidth)
+	lines := []string{
+		t("doc.invoice") + " " + inv.Number,
+		"",
+		label("doc.from") + merchantName,
+		label("doc.bill_to") + strings.TrimSpace(inv.CustomerName+" <"+inv.CustomerEmail+">"),
+		label("doc.issued") + i18n.FormatDate(lang, inv.CreatedAt),
+		label("doc.due") + i18n.FormatDate(lang, inv.DueDate),
+		label("doc.status") + strings.ToUpper(inv.Status),
+		"",
+		rule,
+		fmt.Sprintf("%-44s %8s %11s %12s", t("doc.description"), t("doc.qty"), t("doc.unit_price"), t("doc.amount")),
+		rule,
+	}
+
+	for _, item := range inv.LineItems {
+		desc := item.Description
+		if len(desc) > 44 {
+			desc = desc[:41] + "..."
+		}
+		lines = append(lines, fmt.Sprintf("%-44s %8s %11s %12s",
+			desc, pdf.FormatNumber(item.Quantity), money(item.UnitPrice), money(item.Amount)))
+	}
+
+	lines = append(lines,
+		rule,
+		fmt.Sprintf("%65s %12s", t("doc.subtotal"), money(inv.Subtotal)),
+		fmt.Sprintf("%65s %12s", t("doc.tax_rate", pdf.FormatNumber(inv.TaxRate)), money(inv.TaxAmount)),
+		fmt.Sprintf("%65s %12s", t("doc.total")+" "+inv.Currency, money(inv.Total)),
+	)
+
+	if inv.Notes != "" {
+		lines = append(lines, "", t("doc.notes"))
+		lines = append(lines, pdf.Wrap(inv.Notes, pdf.LineWidth)...)
+	}
+	return lines
+}
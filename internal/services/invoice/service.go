@@ -0,0 +1,403 @@
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/mail"
+	"orus/internal/i18n"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/receipt"
+	"orus/internal/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// ReminderWindow is how far ahead of the due date reminders start
+	ReminderWindow = 3 * 24 * time.Hour
+	// ReminderInterval is the minimum time between reminders for one invoice
+	ReminderInterval = 3 * 24 * time.Hour
+	// MaxReminders caps how many reminders a customer receives per invoice
+	MaxReminders = 5
+
+	reminderBatchSize = 100
+)
+
+type service struct {
+	repo           repositories.InvoiceRepository
+	merchants      repositories.MerchantRepository
+	transactionSvc TransactionService
+	receiptSvc     ReceiptService
+	notifier       Notifier
+	baseURL        string
+}
+
+// NewService creates a new invoicing service.
+// baseURL is the public origin that invoice pay links are served from.
+func NewService(
+	repo repositories.InvoiceRepository,
+	merchants repositories.MerchantRepository,
+	transactionSvc TransactionService,
+	receiptSvc ReceiptService,
+	notifier Notifier,
+	baseURL string,
+) Service {
+	return &service{
+		repo:           repo,
+		merchants:      merchants,
+		transactionSvc: transactionSvc,
+		receiptSvc:     receiptSvc,
+		notifier:       notifier,
+		baseURL:        baseURL,
+	}
+}
+
+// CreateInvoice saves a draft invoice with totals computed from its line items
+func (s *service) CreateInvoice(ctx context.Context, merchantID uint, req InvoiceRequest) (*models.Invoice, error) {
+	if _, err := s.merchants.GetByUserID(merchantID); err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	code, err := utils.GenerateUniqueID(12)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invoice code: %w", err)
+	}
+
+	invoice := &models.Invoice{
+		MerchantID: merchantID,
+		PublicCode: code,
+		Currency:   "USD",
+		Status:     models.InvoiceStatusDraft,
+	}
+	if err := applyRequest(invoice, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *service) ListInvoices(ctx context.Context, merchantID uint, status string, limit, offset int) ([]models.Invoice, int64, error) {
+	return s.repo.GetByMerchantID(merchantID, status, limit, offset)
+}
+
+func (s *service) GetInvoice(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, error) {
+	return s.repo.GetByIDAndMerchantID(invoiceID, merchantID)
+}
+
+// UpdateInvoice replaces the contents of a draft invoice
+func (s *service) UpdateInvoice(ctx context.Context, merchantID, invoiceID uint, req InvoiceRequest) (*models.Invoice, error) {
+	invoice, err := s.repo.GetByIDAndMerchantID(invoiceID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != models.InvoiceStatusDraft {
+		return nil, ErrNotEditable
+	}
+	if err := applyRequest(invoice, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceLineItems(invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *service) DeleteInvoice(ctx context.Context, merchantID, invoiceID uint) error {
+	invoice, err := s.repo.GetByIDAndMerchantID(invoiceID, merchantID)
+	if err != nil {
+		return err
+	}
+	if invoice.Status != models.InvoiceStatusDraft {
+		return ErrNotEditable
+	}
+	return s.repo.Delete(invoice)
+}
+
+// SendInvoice issues a draft invoice and emails it to the customer
+func (s *service) SendInvoice(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, error) {
+	invoice, err := s.repo.GetByIDAndMerchantID(invoiceID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != models.InvoiceStatusDraft {
+		return nil, ErrNotEditable
+	}
+	if !invoice.DueDate.After(time.Now()) {
+		return nil, ErrInvalidDueDate
+	}
+
+	now := time.Now()
+	invoice.Status = models.InvoiceStatusSent
+	invoice.SentAt = &now
+	if err := s.repo.Update(invoice); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.SendInvoiceEmail(ctx, invoice.CustomerEmail, invoice, EmailIssued, s.payURL(invoice)); err != nil {
+		log.Printf("Failed to email invoice %s: %v", invoice.Number, err)
+	}
+	return invoice, nil
+}
+
+// VoidInvoice cancels an unpaid invoice so it can no longer be paid
+func (s *service) VoidInvoice(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, error) {
+	invoice, err := s.repo.GetByIDAndMerchantID(invoiceID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.TransitionStatus(invoice.ID, []string{
+		models.InvoiceStatusDraft, models.InvoiceStatusSent, models.InvoiceStatusOverdue,
+	}, models.InvoiceStatusVoid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCannotVoid
+	}
+	invoice.Status = models.InvoiceStatusVoid
+	return invoice, nil
+}
+
+func (s *service) RenderPDF(ctx context.Context, merchantID, invoiceID uint) (*models.Invoice, []byte, error) {
+	invoice, err := s.repo.GetByIDAndMerchantID(invoiceID, merchantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invoice, renderPDF(invoice, s.merchantName(invoice.MerchantID), i18n.FromContext(ctx)), nil
+}
+
+// GetPublicInvoice returns an issued invoice by its pay link code
+func (s *service) GetPublicInvoice(ctx context.Context, code string) (*PublicInvoice, error) {
+	invoice, err := s.publicInvoice(code)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicInvoice{
+		Invoice:      invoice,
+		MerchantName: s.merchantName(invoice.MerchantID),
+		PayURL:       s.payURL(invoice),
+		PDFURL:       s.payURL(invoice) + "/pdf",
+	}, nil
+}
+
+func (s *service) RenderPublicPDF(ctx context.Context, code string) (*models.Invoice, []byte, error) {
+	invoice, err := s.publicInvoice(code)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invoice, renderPDF(invoice, s.merchantName(invoice.MerchantID), i18n.FromContext(ctx)), nil
+}
+
+// PayInvoice settles an invoice in full from the payer's wallet
+func (s *service) PayInvoice(ctx context.Context, userID uint, code string) (*PaymentResult, error) {
+	invoice, err := s.publicInvoice(code)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.IsPayable() {
+		return nil, ErrNotPayable
+	}
+	if invoice.MerchantID == userID {
+		return nil, ErrSelfPayment
+	}
+
+	merchant, err := s.merchants.GetByUserID(invoice.MerchantID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	// Claim the invoice before moving money so it can't be paid twice
+	previousStatus := invoice.Status
+	ok, err := s.repo.TransitionStatus(invoice.ID, []string{previousStatus}, models.InvoiceStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotPayable
+	}
+
+	now := time.Now()
+	tx, err := s.transactionSvc.ProcessTransaction(ctx, &models.Transaction{
+		Type:             "merchant_payment",
+		SenderID:         userID,
+		ReceiverID:       invoice.MerchantID,
+		Amount:           invoice.Total,
+		Currency:         invoice.Currency,
+		Description:      fmt.Sprintf("Invoice %s", invoice.Number),
+		Status:           "pending",
+		TransactionID:    fmt.Sprintf("INV-%d-%d", invoice.ID, now.UnixNano()),
+		Reference:        invoice.Number,
+		PaymentType:      "invoice",
+		PaymentMethod:    "wallet",
+		MerchantID:       &merchant.ID,
+		MerchantName:     merchant.BusinessName,
+		MerchantCategory: merchant.BusinessType,
+		Category:         "Sale",
+		Metadata: models.NewJSON(map[string]interface{}{
+			"invoice_id":     invoice.ID,
+			"invoice_number": invoice.Number,
+		}),
+	})
+	if err != nil {
+		if _, revertErr := s.repo.TransitionStatus(invoice.ID, []string{models.InvoiceStatusPaid}, previousStatus); revertErr != nil {
+			log.Printf("Failed to release invoice %s after failed payment: %v", invoice.Number, revertErr)
+		}
+		return nil, err
+	}
+
+	paidAt := time.Now()
+	invoice.Status = models.InvoiceStatusPaid
+	invoice.PaidAt = &paidAt
+	invoice.PaidBy = &userID
+	invoice.TransactionID = &tx.ID
+	if err := s.repo.Update(invoice); err != nil {
+		// The payment went through; don't report failure to the payer
+		log.Printf("Failed to record payment of invoice %s: %v", invoice.Number, err)
+	}
+
+	items := make([]receipt.Item, len(invoice.LineItems))
+	for i, item := range invoice.LineItems {
+		items[i] = receipt.Item{Description: item.Description, Quantity: item.Quantity, UnitPrice: item.UnitPrice}
+	}
+	if _, err := s.receiptSvc.Issue(ctx, tx, receipt.Details{Items: items, TaxAmount: invoice.TaxAmount}); err != nil {
+		log.Printf("Failed to issue receipt for invoice %s: %v", invoice.Number, err)
+	}
+
+	if err := s.notifier.SendInvoiceEmail(ctx, invoice.CustomerEmail, invoice, EmailPaid, s.payURL(invoice)); err != nil {
+		log.Printf("Failed to email receipt for invoice %s: %v", invoice.Number, err)
+	}
+
+	return &PaymentResult{Invoice: invoice, Transaction: tx}, nil
+}
+
+// ProcessDue marks invoices past their due date as overdue and reminds
+// customers of invoices that are due soon or already overdue
+func (s *service) ProcessDue(ctx context.Context) (int64, int, error) {
+	now := time.Now()
+	overdue, err := s.repo.MarkOverdue(now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	candidates, err := s.repo.GetReminderCandidates(now.Add(ReminderWindow), reminderBatchSize)
+	if err != nil {
+		return overdue, 0, err
+	}
+
+	sent := 0
+	for i := range candidates {
+		if ctx.Err() != nil {
+			return overdue, sent, ctx.Err()
+		}
+		invoice := &candidates[i]
+		if invoice.ReminderCount >= MaxReminders {
+			continue
+		}
+		if invoice.LastReminderAt != nil && now.Sub(*invoice.LastReminderAt) < ReminderInterval {
+			continue
+		}
+
+		kind := EmailReminder
+		if invoice.Status == models.InvoiceStatusOverdue {
+			kind = EmailOverdue
+		}
+		if err := s.notifier.SendInvoiceEmail(ctx, invoice.CustomerEmail, invoice, kind, s.payURL(invoice)); err != nil {
+			log.Printf("Failed to send reminder for invoice %s: %v", invoice.Number, err)
+			continue
+		}
+
+		invoice.LastReminderAt = &now
+		invoice.ReminderCount++
+		if err := s.repo.Update(invoice); err != nil {
+			log.Printf("Failed to record reminder for invoice %s: %v", invoice.Number, err)
+			continue
+		}
+		sent++
+	}
+	return overdue, sent, nil
+}
+
+// publicInvoice hides drafts from anyone holding the code
+func (s *service) publicInvoice(code string) (*models.Invoice, error) {
+	invoice, err := s.repo.GetByPublicCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status == models.InvoiceStatusDraft {
+		return nil, repositories.ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+func (s *service) payURL(invoice *models.Invoice) string {
+	return fmt.Sprintf("%s/api/invoices/public/%s", s.baseURL, invoice.PublicCode)
+}
+
+func (s *service) merchantName(merchantID uint) string {
+	if merchant, err := s.merchants.GetByUserID(merchantID); err == nil {
+		return merchant.BusinessName
+	}
+	return ""
+}
+
+// applyRequest validates the request and recomputes the invoice totals
+func applyRequest(invoice *models.Invoice, req InvoiceRequest) error {
+	email := strings.TrimSpace(req.CustomerEmail)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return ErrInvalidEmail
+	}
+	if !req.DueDate.After(time.Now()) {
+		return ErrInvalidDueDate
+	}
+	if req.TaxRate < 0 || req.TaxRate > 100 {
+		return ErrInvalidTaxRate
+	}
+	if len(req.LineItems) == 0 {
+		return ErrNoLineItems
+	}
+
+	items := make([]models.InvoiceLineItem, 0, len(req.LineItems))
+	subtotal := 0.0
+	for _, item := range req.LineItems {
+		description := strings.TrimSpace(item.Description)
+		if description == "" || item.Quantity <= 0 || item.UnitPrice < 0 {
+			return ErrInvalidLineItem
+		}
+		amount := round2(item.Quantity * item.UnitPrice)
+		subtotal += amount
+		items = append(items, models.InvoiceLineItem{
+			Description: description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      amount,
+		})
+	}
+
+	subtotal = round2(subtotal)
+	taxAmount := round2(subtotal * req.TaxRate / 100)
+	total := round2(subtotal + taxAmount)
+	if total <= 0 {
+		return ErrInvalidTotal
+	}
+
+	invoice.CustomerName = strings.TrimSpace(req.CustomerName)
+	invoice.CustomerEmail = email
+	invoice.DueDate = req.DueDate
+	invoice.TaxRate = req.TaxRate
+	invoice.Notes = req.Notes
+	invoice.LineItems = items
+	invoice.Subtotal = subtotal
+	invoice.TaxAmount = taxAmount
+	invoice.Total = total
+	return nil
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,45 @@
+package invoice
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// Invoice email kinds
+const (
+	EmailIssued   = "issued"
+	EmailReminder = "reminder"
+	EmailOverdue  = "overdue"
+	EmailPaid     = "paid"
+)
+
+// LineItemRequest is a billed item on an invoice request
+type LineItemRequest struct {
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// InvoiceRequest creates or replaces a draft invoice
+type InvoiceRequest struct {
+	CustomerName  string            `json:"customer_name"`
+	CustomerEmail string            `json:"customer_email"`
+	DueDate       time.Time         `json:"due_date"`
+	TaxRate       float64           `json:"tax_rate"`
+	Notes         string            `json:"notes"`
+	LineItems     []LineItemRequest `json:"line_items"`
+}
+
+// PublicInvoice is what a customer sees at the invoice's pay URL
+type PublicInvoice struct {
+	*models.Invoice
+	MerchantName string `json:"merchant_name"`
+	PayURL       string `json:"pay_url"`
+	PDFURL       string `json:"pdf_url"`
+}
+
+// PaymentResult is a paid invoice and its wallet transaction
+type PaymentResult struct {
+	Invoice     *models.Invoice     `json:"invoice"`
+	Transaction *models.Transaction `json:"transaction"`
+}
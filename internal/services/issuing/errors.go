@@ -0,0 +1,14 @@
+package issuing
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidCardType    = errors.New("invalid card type")
+	ErrInvalidSpendLimit  = errors.New("invalid spend limit")
+	ErrCardClosed         = errors.New("virtual card is closed")
+	ErrCardAlreadyFrozen  = errors.New("virtual card is already frozen")
+	ErrCardNotFrozen      = errors.New("virtual card is not frozen")
+	ErrWalletNotActive    = errors.New("wallet is not active")
+	ErrTooManyActiveCards = errors.New("active virtual card limit reached")
+)
@@ -0,0 +1,45 @@
+package issuing
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Provider is a card issuer that creates card numbers on a card network
+type Provider interface {
+	// Name identifies the provider on stored cards
+	Name() string
+
+	// IssueCard creates a new card number and returns its sensitive details
+	IssueCard(ctx context.Context, req ProviderIssueRequest) (*ProviderCard, error)
+
+	// FreezeCard blocks authorizations at the network
+	FreezeCard(ctx context.Context, providerCardID string) error
+
+	// UnfreezeCard re-enables authorizations at the network
+	UnfreezeCard(ctx context.Context, providerCardID string) error
+
+	// CloseCard permanently cancels the card number
+	CloseCard(ctx context.Context, providerCardID string) error
+}
+
+// WalletService defines the wallet operations used by the issuing service
+type WalletService interface {
+	ValidateBalance(ctx context.Context, userID uint, amount float64) error
+	EnforceLock(ctx context.Context, wallet *models.Wallet, operation, direction string, amount float64) error
+}
+
+// Service issues virtual cards and authorizes their spend against the wallet
+type Service interface {
+	IssueCard(ctx context.Context, userID uint, req IssueCardRequest) (*IssuedCard, error)
+	ListCards(ctx context.Context, userID uint) ([]models.VirtualCard, error)
+	GetCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error)
+	FreezeCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error)
+	UnfreezeCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error)
+	CloseCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error)
+	UpdateSpendLimit(ctx context.Context, userID, cardID uint, req SpendLimitRequest) (*models.VirtualCard, error)
+	GetTransactions(ctx context.Context, userID, cardID uint, limit, offset int) ([]models.Transaction, int64, error)
+
+	// Authorize handles a real-time authorization request from the issuer
+	Authorize(ctx context.Context, req AuthorizationRequest) (*AuthorizationDecision, error)
+}
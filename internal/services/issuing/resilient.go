@@ -0,0 +1,49 @@
+package issuing
+
+import (
+	"context"
+	"orus/internal/resilience"
+)
+
+// resilientProvider guards an issuer with a circuit breaker. Freezing,
+// unfreezing and closing cards are retried as repeating them is harmless;
+// issuing is not, as a retry could create a second card.
+type resilientProvider struct {
+	Provider
+	breaker *resilience.Breaker
+	policy  resilience.Policy
+}
+
+// WithResilience wraps p so that an issuer outage fails calls fast
+// instead of stalling the requests waiting on it
+func WithResilience(p Provider, breaker *resilience.Breaker, policy resilience.Policy) Provider {
+	return &resilientProvider{Provider: p, breaker: breaker, policy: policy}
+}
+
+func (p *resilientProvider) IssueCard(ctx context.Context, req ProviderIssueRequest) (*ProviderCard, error) {
+	var card *ProviderCard
+	err := p.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		card, err = p.Provider.IssueCard(ctx, req)
+		return err
+	})
+	return card, err
+}
+
+func (p *resilientProvider) FreezeCard(ctx context.Context, providerCardID string) error {
+	return resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		return p.Provider.FreezeCard(ctx, providerCardID)
+	})
+}
+
+func (p *resilientProvider) UnfreezeCard(ctx context.Context, providerCardID string) error {
+	return resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		return p.Provider.UnfreezeCard(ctx, providerCardID)
+	})
+}
+
+func (p *resilientProvider) CloseCard(ctx context.Context, providerCardID string) error {
+	return resilience.Call(ctx, p.breaker, p.policy, func(ctx context.Context) error {
+		return p.Provider.CloseCard(ctx, providerCardID)
+	})
+}
@@ -0,0 +1,416 @@
+package issuing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/wallet"
+	"time"
+)
+
+// MaxActiveCards bounds the number of open virtual cards per user
+const MaxActiveCards = 10
+
+type service struct {
+	repo       repositories.VirtualCardRepository
+	walletRepo repositories.WalletRepository
+	walletSvc  WalletService
+	provider   Provider
+	cache      *cache.CacheService
+}
+
+// NewService creates a new virtual card issuing service
+func NewService(
+	repo repositories.VirtualCardRepository,
+	walletRepo repositories.WalletRepository,
+	walletSvc WalletService,
+	provider Provider,
+	cache *cache.CacheService,
+) Service {
+	return &service{
+		repo:       repo,
+		walletRepo: walletRepo,
+		walletSvc:  walletSvc,
+		provider:   provider,
+		cache:      cache,
+	}
+}
+
+// IssueCard creates a virtual card backed by the user's wallet
+func (s *service) IssueCard(ctx context.Context, userID uint, req IssueCardRequest) (*IssuedCard, error) {
+	if req.Type == "" {
+		req.Type = models.VirtualCardTypeReloadable
+	}
+	if req.Type != models.VirtualCardTypeReloadable && req.Type != models.VirtualCardTypeSingleUse {
+		return nil, ErrInvalidCardType
+	}
+	if err := validateSpendLimit(req.SpendLimit, req.SpendLimitInterval); err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.walletRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.Status != "active" {
+		return nil, ErrWalletNotActive
+	}
+
+	cards, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	open := 0
+	for _, card := range cards {
+		if card.Status != models.VirtualCardStatusClosed {
+			open++
+		}
+	}
+	if open >= MaxActiveCards {
+		return nil, ErrTooManyActiveCards
+	}
+
+	providerCard, err := s.provider.IssueCard(ctx, ProviderIssueRequest{
+		UserID:    userID,
+		Type:      req.Type,
+		Currency:  wallet.Currency,
+		Reference: fmt.Sprintf("VC-%d-%d", userID, time.Now().UnixNano()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue card: %w", err)
+	}
+
+	card := &models.VirtualCard{
+		UserID:             userID,
+		WalletID:           wallet.ID,
+		Provider:           s.provider.Name(),
+		ProviderCardID:     providerCard.ID,
+		Type:               req.Type,
+		Status:             models.VirtualCardStatusActive,
+		Nickname:           req.Nickname,
+		Brand:              providerCard.Brand,
+		LastFour:           providerCard.Number[len(providerCard.Number)-4:],
+		ExpiryMonth:        providerCard.ExpiryMonth,
+		ExpiryYear:         providerCard.ExpiryYear,
+		Currency:           wallet.Currency,
+		SpendLimit:         req.SpendLimit,
+		SpendLimitInterval: limitInterval(req.SpendLimit, req.SpendLimitInterval),
+	}
+	if err := s.repo.Create(card); err != nil {
+		// Don't leave a live card number at the issuer that we can't account for
+		if closeErr := s.provider.CloseCard(ctx, providerCard.ID); closeErr != nil {
+			log.Printf("Failed to close orphaned card %s: %v", providerCard.ID, closeErr)
+		}
+		return nil, err
+	}
+
+	return &IssuedCard{
+		Card: card,
+		Details: CardDetails{
+			Number:      providerCard.Number,
+			CVV:         providerCard.CVV,
+			ExpiryMonth: providerCard.ExpiryMonth,
+			ExpiryYear:  providerCard.ExpiryYear,
+		},
+	}, nil
+}
+
+func (s *service) ListCards(ctx context.Context, userID uint) ([]models.VirtualCard, error) {
+	return s.repo.GetByUserID(userID)
+}
+
+func (s *service) GetCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error) {
+	return s.repo.GetByIDAndUserID(cardID, userID)
+}
+
+// FreezeCard temporarily blocks all authorizations on the card
+func (s *service) FreezeCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch card.Status {
+	case models.VirtualCardStatusClosed:
+		return nil, ErrCardClosed
+	case models.VirtualCardStatusFrozen:
+		return nil, ErrCardAlreadyFrozen
+	}
+
+	if err := s.provider.FreezeCard(ctx, card.ProviderCardID); err != nil {
+		return nil, fmt.Errorf("failed to freeze card: %w", err)
+	}
+
+	now := time.Now()
+	card.Status = models.VirtualCardStatusFrozen
+	card.FrozenAt = &now
+	if err := s.repo.Update(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// UnfreezeCard re-enables a frozen card
+func (s *service) UnfreezeCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch card.Status {
+	case models.VirtualCardStatusClosed:
+		return nil, ErrCardClosed
+	case models.VirtualCardStatusActive:
+		return nil, ErrCardNotFrozen
+	}
+
+	if err := s.provider.UnfreezeCard(ctx, card.ProviderCardID); err != nil {
+		return nil, fmt.Errorf("failed to unfreeze card: %w", err)
+	}
+
+	card.Status = models.VirtualCardStatusActive
+	card.FrozenAt = nil
+	if err := s.repo.Update(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// CloseCard permanently cancels the card; its transaction feed is kept
+func (s *service) CloseCard(ctx context.Context, userID, cardID uint) (*models.VirtualCard, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Status == models.VirtualCardStatusClosed {
+		return nil, ErrCardClosed
+	}
+
+	if err := s.closeCard(ctx, card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// UpdateSpendLimit changes the card-level spend limit
+func (s *service) UpdateSpendLimit(ctx context.Context, userID, cardID uint, req SpendLimitRequest) (*models.VirtualCard, error) {
+	if err := validateSpendLimit(req.SpendLimit, req.SpendLimitInterval); err != nil {
+		return nil, err
+	}
+
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Status == models.VirtualCardStatusClosed {
+		return nil, ErrCardClosed
+	}
+
+	card.SpendLimit = req.SpendLimit
+	card.SpendLimitInterval = limitInterval(req.SpendLimit, req.SpendLimitInterval)
+	if err := s.repo.Update(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// GetTransactions returns the wallet debits made with the card
+func (s *service) GetTransactions(ctx context.Context, userID, cardID uint, limit, offset int) ([]models.Transaction, int64, error) {
+	card, err := s.repo.GetByIDAndUserID(cardID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetTransactions(card.ID, limit, offset)
+}
+
+// Authorize approves or declines a card spend and debits the wallet on approval.
+// Repeated requests with the same authorization ID return the original outcome.
+func (s *service) Authorize(ctx context.Context, req AuthorizationRequest) (*AuthorizationDecision, error) {
+	card, err := s.repo.GetByProviderCardID(req.ProviderCardID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrVirtualCardNotFound) {
+			return decline(DeclineCardNotFound), nil
+		}
+		return nil, err
+	}
+
+	if req.AuthorizationID != "" {
+		existing, err := s.repo.GetTransactionByReference(card.ID, req.AuthorizationID)
+		if err == nil {
+			return &AuthorizationDecision{Approved: true, Transaction: existing}, nil
+		}
+		if !errors.Is(err, repositories.ErrCardTransactionNotFound) {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	amount := math.Round(req.Amount*100) / 100
+	switch {
+	case card.Status != models.VirtualCardStatusActive:
+		return decline(DeclineCardInactive), nil
+	case card.IsExpired(now):
+		return decline(DeclineCardExpired), nil
+	case amount <= 0:
+		return decline(DeclineInvalidAmount), nil
+	case req.Currency != "" && req.Currency != card.Currency:
+		return decline(DeclineCurrencyMismatch), nil
+	}
+
+	withinLimit, err := s.withinSpendLimit(card, amount, now)
+	if err != nil {
+		return nil, err
+	}
+	if !withinLimit {
+		return decline(DeclineSpendLimitExceeded), nil
+	}
+
+	if err := s.walletSvc.ValidateBalance(ctx, card.UserID, amount); err != nil {
+		if errors.Is(err, wallet.ErrWalletLocked) || errors.Is(err, wallet.ErrDebitsLocked) {
+			return decline(DeclineWalletUnavailable), nil
+		}
+		return decline(DeclineInsufficientFunds), nil
+	}
+
+	cardID := card.ID
+	tx := &models.Transaction{
+		Type:             models.TransactionTypeCardPayment,
+		SenderID:         card.UserID,
+		ReceiverID:       0, // External merchant on the card network
+		Amount:           amount,
+		Currency:         card.Currency,
+		TransactionID:    fmt.Sprintf("VCP-%d-%d", card.ID, now.UnixNano()),
+		Reference:        req.AuthorizationID,
+		PaymentType:      "virtual_card",
+		PaymentMethod:    "card",
+		MerchantName:     req.MerchantName,
+		MerchantCategory: req.MerchantCategory,
+		VirtualCardID:    &cardID,
+		Category:         "Card Payment",
+		Description:      fmt.Sprintf("Card payment at %s with card ending in %s", merchantLabel(req.MerchantName), card.LastFour),
+		Metadata: models.NewJSON(map[string]interface{}{
+			"virtual_card_id":  card.ID,
+			"card_last_four":   card.LastFour,
+			"card_type":        card.Type,
+			"provider":         card.Provider,
+			"authorization_id": req.AuthorizationID,
+		}),
+	}
+
+	// The wallet is checked and debited under its row lock, against the
+	// balance its holds leave available, with the payment recorded in the
+	// same transaction
+	declineReason := ""
+	err = s.walletRepo.ExecuteInTransaction(func(repo repositories.WalletRepository) error {
+		wallet, err := repo.LockByUserID(card.UserID)
+		if err != nil {
+			return err
+		}
+		if err := s.walletSvc.EnforceLock(ctx, wallet, "card_authorization", models.WalletDirectionDebit, amount); err != nil {
+			declineReason = DeclineWalletUnavailable
+			return ErrWalletNotActive
+		}
+		if _, err := repo.AdjustBalance(card.UserID, -amount); err != nil {
+			if errors.Is(err, repositories.ErrOverdraftExceeded) || errors.Is(err, repositories.ErrInsufficientBalance) {
+				declineReason = DeclineInsufficientFunds
+			}
+			return err
+		}
+
+		tx.Status = "completed"
+		tx.ProcessedAt = now
+		return repo.CreateTransaction(tx)
+	})
+	if err != nil {
+		if declineReason != "" {
+			return decline(declineReason), nil
+		}
+		return nil, err
+	}
+
+	s.invalidateWallet(ctx, card.UserID)
+
+	if card.Type == models.VirtualCardTypeSingleUse {
+		if err := s.closeCard(ctx, card); err != nil {
+			log.Printf("Failed to close single-use card %d after authorization: %v", card.ID, err)
+		}
+	}
+
+	return &AuthorizationDecision{Approved: true, Transaction: tx}, nil
+}
+
+func (s *service) closeCard(ctx context.Context, card *models.VirtualCard) error {
+	if err := s.provider.CloseCard(ctx, card.ProviderCardID); err != nil {
+		return fmt.Errorf("failed to close card: %w", err)
+	}
+
+	now := time.Now()
+	card.Status = models.VirtualCardStatusClosed
+	card.ClosedAt = &now
+	return s.repo.Update(card)
+}
+
+func (s *service) withinSpendLimit(card *models.VirtualCard, amount float64, now time.Time) (bool, error) {
+	if card.SpendLimit <= 0 {
+		return true, nil
+	}
+
+	var since time.Time
+	switch card.SpendLimitInterval {
+	case models.SpendLimitPerTransaction:
+		return amount <= card.SpendLimit, nil
+	case models.SpendLimitDaily:
+		since = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	case models.SpendLimitMonthly:
+		since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case models.SpendLimitLifetime:
+		since = card.CreatedAt
+	}
+
+	spent, err := s.repo.SumSpent(card.ID, since)
+	if err != nil {
+		return false, err
+	}
+	return spent+amount <= card.SpendLimit, nil
+}
+
+func (s *service) invalidateWallet(ctx context.Context, userID uint) {
+	key := s.cache.GenerateKey("wallet", "user", userID)
+	if err := s.cache.Delete(ctx, key); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
+
+func validateSpendLimit(limit float64, interval string) error {
+	if limit < 0 {
+		return ErrInvalidSpendLimit
+	}
+	switch interval {
+	case "", models.SpendLimitPerTransaction, models.SpendLimitDaily,
+		models.SpendLimitMonthly, models.SpendLimitLifetime:
+		return nil
+	default:
+		return ErrInvalidSpendLimit
+	}
+}
+
+// limitInterval defaults the interval to monthly when a limit is set without one
+func limitInterval(limit float64, interval string) string {
+	if limit <= 0 {
+		return ""
+	}
+	if interval == "" {
+		return models.SpendLimitMonthly
+	}
+	return interval
+}
+
+func merchantLabel(name string) string {
+	if name == "" {
+		return "card merchant"
+	}
+	return name
+}
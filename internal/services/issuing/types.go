@@ -0,0 +1,82 @@
+package issuing
+
+import "orus/internal/models"
+
+// IssueCardRequest is the user's request for a new virtual card
+type IssueCardRequest struct {
+	Type               string  `json:"type"`
+	Nickname           string  `json:"nickname"`
+	SpendLimit         float64 `json:"spend_limit"`
+	SpendLimitInterval string  `json:"spend_limit_interval"`
+}
+
+// SpendLimitRequest updates a card's spend limit; a zero limit removes it
+type SpendLimitRequest struct {
+	SpendLimit         float64 `json:"spend_limit"`
+	SpendLimitInterval string  `json:"spend_limit_interval"`
+}
+
+// ProviderIssueRequest is sent to the issuer when creating a card
+type ProviderIssueRequest struct {
+	UserID    uint
+	Type      string
+	Currency  string
+	Reference string
+}
+
+// ProviderCard is the issuer's response to a card creation
+type ProviderCard struct {
+	ID          string
+	Brand       string
+	Number      string
+	CVV         string
+	ExpiryMonth int
+	ExpiryYear  int
+}
+
+// CardDetails are the sensitive card details, shown once at issuance
+type CardDetails struct {
+	Number      string `json:"number"`
+	CVV         string `json:"cvv"`
+	ExpiryMonth int    `json:"expiry_month"`
+	ExpiryYear  int    `json:"expiry_year"`
+}
+
+// IssuedCard is the result of issuing a card
+type IssuedCard struct {
+	Card    *models.VirtualCard `json:"card"`
+	Details CardDetails         `json:"details"`
+}
+
+// AuthorizationRequest is a real-time spend request forwarded by the issuer
+type AuthorizationRequest struct {
+	ProviderCardID   string  `json:"card_id"`
+	AuthorizationID  string  `json:"authorization_id"`
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	MerchantName     string  `json:"merchant_name"`
+	MerchantCategory string  `json:"merchant_category"`
+}
+
+// Authorization decline reasons
+const (
+	DeclineCardNotFound       = "card_not_found"
+	DeclineCardInactive       = "card_inactive"
+	DeclineCardExpired        = "card_expired"
+	DeclineInvalidAmount      = "invalid_amount"
+	DeclineCurrencyMismatch   = "currency_mismatch"
+	DeclineSpendLimitExceeded = "spend_limit_exceeded"
+	DeclineInsufficientFunds  = "insufficient_funds"
+	DeclineWalletUnavailable  = "wallet_unavailable"
+)
+
+// AuthorizationDecision is returned to the issuer
+type AuthorizationDecision struct {
+	Approved      bool                `json:"approved"`
+	DeclineReason string              `json:"decline_reason,omitempty"`
+	Transaction   *models.Transaction `json:"transaction,omitempty"`
+}
+
+func decline(reason string) *AuthorizationDecision {
+	return &AuthorizationDecision{Approved: false, DeclineReason: reason}
+}
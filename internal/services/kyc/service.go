@@ -0,0 +1,37 @@
+package kyc
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/repositories"
+)
+
+// Service defines verification operations.
+type Service interface {
+	SubmitKYC(ctx context.Context, userID uint, documentID, scanURL string) (*models.KYCVerification, error)
+	GetStatus(ctx context.Context, userID uint) (*models.KYCVerification, error)
+}
+
+type service struct {
+	repo repositories.KYCRepository
+}
+
+// NewService creates a new KYC service.
+func NewService(repo repositories.KYCRepository) Service { return &service{repo: repo} }
+
+func (s *service) SubmitKYC(ctx context.Context, userID uint, documentID, scanURL string) (*models.KYCVerification, error) {
+	kyc := &models.KYCVerification{
+		UserID:     userID,
+		DocumentID: documentID,
+		ScanURL:    scanURL,
+		Status:     "pending",
+	}
+	if err := s.repo.Create(kyc); err != nil {
+		return nil, err
+	}
+	return kyc, nil
+}
+
+func (s *service) GetStatus(ctx context.Context, userID uint) (*models.KYCVerification, error) {
+	return s.repo.GetByUserID(userID)
+}
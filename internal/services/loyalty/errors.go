@@ -0,0 +1,22 @@
+package loyalty
+
+import "errors"
+
+// Service errors
+var (
+	ErrNotMerchant        = errors.New("merchant profile not found")
+	ErrProgramNotFound    = errors.New("merchant has no loyalty program")
+	ErrAccountNotFound    = errors.New("no loyalty points with this merchant")
+	ErrNameRequired       = errors.New("program name is required")
+	ErrInvalidEarnRate    = errors.New("earn rate must be above 0 and at most 100 percent")
+	ErrInvalidBurnRate    = errors.New("burn rate must be between 1 and 10000 points per unit of currency")
+	ErrInvalidRedeemLimit = errors.New("max redeem percent must be above 0 and at most 100")
+	ErrInvalidMinimum     = errors.New("minimum redeemable points cannot be negative")
+	ErrInvalidStatus      = errors.New("status must be active or paused")
+	ErrInvalidPoints      = errors.New("points must be greater than zero")
+	ErrInvalidAmount      = errors.New("invalid amount")
+	ErrBelowMinimum       = errors.New("not enough points to meet the program's minimum redemption")
+	ErrInsufficientPoints = errors.New("insufficient loyalty points")
+	ErrDiscountTooSmall   = errors.New("points are worth less than the smallest discount")
+	ErrSelfRedemption     = errors.New("merchants cannot redeem points at their own program")
+)
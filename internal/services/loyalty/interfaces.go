@@ -0,0 +1,32 @@
+package loyalty
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages merchant loyalty programs and customers' points
+type Service interface {
+	// Merchant side
+	GetProgram(ctx context.Context, merchantID uint) (*models.LoyaltyProgram, error)
+	// SaveProgram creates the merchant's program or replaces its settings
+	SaveProgram(ctx context.Context, merchantID uint, req ProgramRequest) (*models.LoyaltyProgram, error)
+	ListMembers(ctx context.Context, merchantID uint, limit, offset int) ([]models.LoyaltyAccount, int64, error)
+
+	// Customer side
+	ListAccounts(ctx context.Context, customerID uint) ([]AccountView, error)
+	GetAccount(ctx context.Context, customerID, merchantID uint) (*AccountView, error)
+	ListEntries(ctx context.Context, customerID, merchantID uint, limit, offset int) ([]models.LoyaltyEntry, int64, error)
+	// Quote works out the discount points would give on a payment without
+	// spending them
+	Quote(ctx context.Context, customerID, merchantID uint, amount float64, points int64) (*Quote, error)
+
+	// Accrue credits the points a completed merchant payment earns. It
+	// returns nil if the merchant has no active program.
+	Accrue(ctx context.Context, tx *models.Transaction) (*models.LoyaltyEntry, error)
+	// Reserve spends points ahead of a payment to the merchant. The caller
+	// charges the discounted amount, then confirms or releases the redemption.
+	Reserve(ctx context.Context, customerID, merchantID uint, amount float64, points int64) (*Redemption, error)
+	Confirm(ctx context.Context, redemption *Redemption, transactionID uint) error
+	Release(ctx context.Context, redemption *Redemption) error
+}
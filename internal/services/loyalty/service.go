@@ -0,0 +1,331 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+)
+
+const (
+	// MaxBurnRate caps how many points a unit of currency can cost
+	MaxBurnRate = 10000
+)
+
+type service struct {
+	repo      repositories.LoyaltyRepository
+	merchants repositories.MerchantRepository
+}
+
+// NewService creates a new loyalty points service
+func NewService(repo repositories.LoyaltyRepository, merchants repositories.MerchantRepository) Service {
+	return &service{repo: repo, merchants: merchants}
+}
+
+func (s *service) GetProgram(ctx context.Context, merchantID uint) (*models.LoyaltyProgram, error) {
+	if _, err := s.merchants.GetByUserID(merchantID); err != nil {
+		return nil, ErrNotMerchant
+	}
+	return s.program(merchantID)
+}
+
+func (s *service) SaveProgram(ctx context.Context, merchantID uint, req ProgramRequest) (*models.LoyaltyProgram, error) {
+	if _, err := s.merchants.GetByUserID(merchantID); err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if req.EarnRate <= 0 || req.EarnRate > 100 {
+		return nil, ErrInvalidEarnRate
+	}
+	if req.BurnRate < 1 || req.BurnRate > MaxBurnRate {
+		return nil, ErrInvalidBurnRate
+	}
+	if req.MinRedeemPoints < 0 {
+		return nil, ErrInvalidMinimum
+	}
+	if req.MaxRedeemPercent == 0 {
+		req.MaxRedeemPercent = 100
+	}
+	if req.MaxRedeemPercent < 0 || req.MaxRedeemPercent > 100 {
+		return nil, ErrInvalidRedeemLimit
+	}
+	if req.Status == "" {
+		req.Status = models.LoyaltyProgramActive
+	}
+	if req.Status != models.LoyaltyProgramActive && req.Status != models.LoyaltyProgramPaused {
+		return nil, ErrInvalidStatus
+	}
+
+	program, err := s.repo.GetProgram(merchantID)
+	if errors.Is(err, repositories.ErrLoyaltyProgramNotFound) {
+		program = &models.LoyaltyProgram{MerchantID: merchantID}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Changing the rates only affects points earned and spent from now on
+	program.Name = name
+	program.EarnRate = req.EarnRate
+	program.BurnRate = req.BurnRate
+	program.MinRedeemPoints = req.MinRedeemPoints
+	program.MaxRedeemPercent = req.MaxRedeemPercent
+	program.Status = req.Status
+	if err := s.repo.SaveProgram(program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+func (s *service) ListMembers(ctx context.Context, merchantID uint, limit, offset int) ([]models.LoyaltyAccount, int64, error) {
+	program, err := s.GetProgram(ctx, merchantID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListMembers(program.ID, limit, offset)
+}
+
+func (s *service) ListAccounts(ctx context.Context, customerID uint) ([]AccountView, error) {
+	accounts, err := s.repo.ListAccounts(customerID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]AccountView, 0, len(accounts))
+	for i := range accounts {
+		program, err := s.program(accounts[i].MerchantID)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, s.view(&accounts[i], program))
+	}
+	return views, nil
+}
+
+func (s *service) GetAccount(ctx context.Context, customerID, merchantID uint) (*AccountView, error) {
+	program, account, err := s.account(customerID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	v := s.view(account, program)
+	return &v, nil
+}
+
+func (s *service) ListEntries(ctx context.Context, customerID, merchantID uint, limit, offset int) ([]models.LoyaltyEntry, int64, error) {
+	_, account, err := s.account(customerID, merchantID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListEntries(account.ID, limit, offset)
+}
+
+func (s *service) Quote(ctx context.Context, customerID, merchantID uint, amount float64, points int64) (*Quote, error) {
+	if customerID == merchantID {
+		return nil, ErrSelfRedemption
+	}
+	program, account, err := s.account(customerID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := quote(program, amount, points)
+	if err != nil {
+		return nil, err
+	}
+	if account.Balance < quote.Points {
+		return nil, ErrInsufficientPoints
+	}
+	quote.Balance = account.Balance
+	return quote, nil
+}
+
+func (s *service) Accrue(ctx context.Context, tx *models.Transaction) (*models.LoyaltyEntry, error) {
+	if tx.Status != "completed" || !isPayment(tx.Type) || tx.SenderID == tx.ReceiverID {
+		return nil, nil
+	}
+	program, err := s.repo.GetProgram(tx.ReceiverID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoyaltyProgramNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if program.Status != models.LoyaltyProgramActive {
+		return nil, nil
+	}
+
+	points := earned(program, tx.Amount)
+	if points <= 0 {
+		return nil, nil
+	}
+	entry := &models.LoyaltyEntry{
+		Kind:          models.LoyaltyEntryEarn,
+		Points:        points,
+		Value:         tx.Amount,
+		TransactionID: &tx.ID,
+		Description:   fmt.Sprintf("Earned at %s", program.Name),
+	}
+	if _, err := s.repo.Post(repositories.LoyaltyPosting{Program: program, CustomerID: tx.SenderID, Entry: entry}); err != nil {
+		if errors.Is(err, repositories.ErrLoyaltyAlreadyPosted) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *service) Reserve(ctx context.Context, customerID, merchantID uint, amount float64, points int64) (*Redemption, error) {
+	if customerID == merchantID {
+		return nil, ErrSelfRedemption
+	}
+	program, _, err := s.account(customerID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := quote(program, amount, points)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.LoyaltyEntry{
+		Kind:        models.LoyaltyEntryRedeem,
+		Points:      -quote.Points,
+		Value:       quote.Discount,
+		Description: fmt.Sprintf("Redeemed at %s", program.Name),
+	}
+	if _, err := s.repo.Post(repositories.LoyaltyPosting{Program: program, CustomerID: customerID, Entry: entry}); err != nil {
+		if errors.Is(err, repositories.ErrInsufficientPoints) {
+			return nil, ErrInsufficientPoints
+		}
+		return nil, err
+	}
+	return &Redemption{
+		EntryID:    entry.ID,
+		ProgramID:  program.ID,
+		CustomerID: customerID,
+		MerchantID: merchantID,
+		Points:     quote.Points,
+		Discount:   quote.Discount,
+		NetAmount:  quote.NetAmount,
+	}, nil
+}
+
+func (s *service) Confirm(ctx context.Context, redemption *Redemption, transactionID uint) error {
+	return s.repo.LinkEntry(redemption.EntryID, transactionID)
+}
+
+func (s *service) Release(ctx context.Context, redemption *Redemption) error {
+	program, err := s.program(redemption.MerchantID)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.Post(repositories.LoyaltyPosting{
+		Program:    program,
+		CustomerID: redemption.CustomerID,
+		Entry: &models.LoyaltyEntry{
+			Kind:        models.LoyaltyEntryReversal,
+			Points:      redemption.Points,
+			Value:       redemption.Discount,
+			Description: "Payment failed, points returned",
+		},
+	})
+	return err
+}
+
+func (s *service) program(merchantID uint) (*models.LoyaltyProgram, error) {
+	program, err := s.repo.GetProgram(merchantID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoyaltyProgramNotFound) {
+			return nil, ErrProgramNotFound
+		}
+		return nil, err
+	}
+	return program, nil
+}
+
+// account loads the merchant's program and the customer's account with it
+func (s *service) account(customerID, merchantID uint) (*models.LoyaltyProgram, *models.LoyaltyAccount, error) {
+	program, err := s.program(merchantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	account, err := s.repo.GetAccount(program.ID, customerID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoyaltyAccountNotFound) {
+			return nil, nil, ErrAccountNotFound
+		}
+		return nil, nil, err
+	}
+	return program, account, nil
+}
+
+// earned returns the points a payment accrues: EarnRate percent of the
+// amount, at BurnRate points per unit of currency
+func earned(program *models.LoyaltyProgram, amount float64) int64 {
+	return int64(math.Floor(amount*program.EarnRate/100*float64(program.BurnRate) + 1e-9))
+}
+
+// quote works out the discount for spending up to points on a payment.
+// The discount is capped by the program's limit and always leaves at least
+// one cent to pay, spending only the points needed to reach it.
+func quote(program *models.LoyaltyProgram, amount float64, points int64) (*Quote, error) {
+	if points <= 0 {
+		return nil, ErrInvalidPoints
+	}
+	amountCents := int64(math.Round(amount * 100))
+	if amount <= 0 || amountCents < 2 {
+		return nil, ErrInvalidAmount
+	}
+	if points < program.MinRedeemPoints {
+		return nil, ErrBelowMinimum
+	}
+
+	burn := int64(program.BurnRate)
+	cents := points * 100 / burn
+	maxCents := int64(math.Floor(float64(amountCents)*program.MaxRedeemPercent/100 + 1e-9))
+	if maxCents > amountCents-1 {
+		maxCents = amountCents - 1
+	}
+	if cents > maxCents {
+		cents = maxCents
+		points = (cents*burn + 99) / 100
+	}
+	if cents <= 0 {
+		return nil, ErrDiscountTooSmall
+	}
+
+	return &Quote{
+		MerchantID: program.MerchantID,
+		Amount:     float64(amountCents) / 100,
+		Points:     points,
+		Discount:   float64(cents) / 100,
+		NetAmount:  float64(amountCents-cents) / 100,
+	}, nil
+}
+
+func (s *service) view(account *models.LoyaltyAccount, program *models.LoyaltyProgram) AccountView {
+	v := AccountView{
+		Account:     account,
+		ProgramName: program.Name,
+		Value:       float64(account.Balance*100/int64(program.BurnRate)) / 100,
+		Status:      program.Status,
+	}
+	if merchant, err := s.merchants.GetByUserID(program.MerchantID); err == nil {
+		v.MerchantName = merchant.BusinessName
+	}
+	return v
+}
+
+func isPayment(txType string) bool {
+	txType = strings.ToLower(txType)
+	for _, t := range paymentTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package loyalty
+
+import "orus/internal/models"
+
+// paymentTypes are the transaction types that pay a merchant and earn
+// points, lower-cased
+var paymentTypes = []string{
+	"merchant_payment",
+	models.TransactionTypeMerchantScan,
+	models.TransactionTypeQRPayment,
+}
+
+// ProgramRequest sets a merchant's program. MaxRedeemPercent defaults to 100.
+type ProgramRequest struct {
+	Name             string  `json:"name"`
+	EarnRate         float64 `json:"earn_rate"` // Percent of each payment accrued as points
+	BurnRate         int     `json:"burn_rate"` // Points per 1.00 of discount
+	MinRedeemPoints  int64   `json:"min_redeem_points"`
+	MaxRedeemPercent float64 `json:"max_redeem_percent"`
+	Status           string  `json:"status"` // active or paused; defaults to active
+}
+
+// AccountView is a customer's balance with one merchant and what it is worth
+type AccountView struct {
+	Account      *models.LoyaltyAccount `json:"account"`
+	ProgramName  string                 `json:"program_name"`
+	MerchantName string                 `json:"merchant_name,omitempty"`
+	Value        float64                `json:"value"` // Discount the balance is worth
+	Status       string                 `json:"status"`
+}
+
+// Quote is the discount a number of points would give on a payment
+type Quote struct {
+	MerchantID uint    `json:"merchant_id"`
+	Amount     float64 `json:"amount"`     // Payment before the discount
+	Points     int64   `json:"points"`     // Points that would be spent
+	Discount   float64 `json:"discount"`   // Amount the points take off
+	NetAmount  float64 `json:"net_amount"` // What is left to pay from the wallet
+	Balance    int64   `json:"balance"`    // Points before redeeming
+}
+
+// Redemption is points spent ahead of a payment
+type Redemption struct {
+	EntryID    uint
+	ProgramID  uint
+	CustomerID uint
+	MerchantID uint
+	Points     int64
+	Discount   float64
+	NetAmount  float64
+}
@@ -0,0 +1,10 @@
+package maintenance
+
+import "errors"
+
+// Service errors
+var (
+	ErrNotEnabled        = errors.New("maintenance mode is not enabled")
+	ErrInvalidRetryAfter = errors.New("retry_after must be between 0 and 86400 seconds")
+	ErrEndsInPast        = errors.New("ends_at must be in the future")
+)
@@ -0,0 +1,22 @@
+package maintenance
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service switches the platform in and out of maintenance. While it's on,
+// payment-mutating endpoints answer 503 with the announcement; reads,
+// the admin console and the allowed users carry on.
+type Service interface {
+	// Current returns the maintenance state as last cached. Failing to
+	// read it counts as no maintenance, so a store outage doesn't stop
+	// payments as well.
+	Current(ctx context.Context) *models.MaintenanceMode
+
+	Get(ctx context.Context) (*models.MaintenanceMode, error)
+	// Enable starts maintenance, or replaces the announcement and
+	// allowlist of the one under way
+	Enable(ctx context.Context, actorID uint, req EnableRequest) (*models.MaintenanceMode, error)
+	Disable(ctx context.Context, actorID uint) (*models.MaintenanceMode, error)
+}
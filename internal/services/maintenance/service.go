@@ -0,0 +1,101 @@
+package maintenance
+
+import (
+	"context"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"slices"
+	"strings"
+	"time"
+)
+
+// stateKey caches the maintenance state, read on every mutating request
+const stateKey = "maintenance:state"
+
+// stateTTL bounds how long the state is cached; changes drop the entry,
+// so it only matters if that fails
+const stateTTL = time.Minute
+
+type service struct {
+	repo  repositories.MaintenanceRepository
+	cache *cache.CacheService
+}
+
+// NewService creates the maintenance service
+func NewService(repo repositories.MaintenanceRepository, cacheSvc *cache.CacheService) Service {
+	return &service{repo: repo, cache: cacheSvc}
+}
+
+func (s *service) Current(ctx context.Context) *models.MaintenanceMode {
+	var mode models.MaintenanceMode
+	err := s.cache.Load(ctx, stateKey, stateTTL, &mode, func() (interface{}, error) {
+		return s.repo.Get()
+	})
+	if err != nil {
+		log.Printf("Failed to load maintenance mode: %v", err)
+		return &models.MaintenanceMode{}
+	}
+	return &mode
+}
+
+func (s *service) Get(ctx context.Context) (*models.MaintenanceMode, error) {
+	return s.repo.Get()
+}
+
+func (s *service) Enable(ctx context.Context, actorID uint, req EnableRequest) (*models.MaintenanceMode, error) {
+	if req.RetryAfter < 0 || req.RetryAfter > MaxRetryAfter {
+		return nil, ErrInvalidRetryAfter
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(time.Now()) {
+		return nil, ErrEndsInPast
+	}
+
+	mode := &models.MaintenanceMode{
+		Enabled:        true,
+		Message:        strings.TrimSpace(req.Message),
+		RetryAfter:     req.RetryAfter,
+		EndsAt:         req.EndsAt,
+		AllowedUserIDs: slices.Compact(slices.Sorted(slices.Values(req.AllowedUserIDs))),
+		UpdatedBy:      actorID,
+	}
+	if mode.Message == "" {
+		mode.Message = DefaultMessage
+	}
+	if mode.RetryAfter == 0 {
+		mode.RetryAfter = DefaultRetryAfter
+	}
+	if err := s.repo.Save(mode); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d enabled maintenance mode (%d users allowed)", actorID, len(mode.AllowedUserIDs))
+	s.dropState(ctx)
+	return mode, nil
+}
+
+func (s *service) Disable(ctx context.Context, actorID uint) (*models.MaintenanceMode, error) {
+	mode, err := s.repo.Get()
+	if err != nil {
+		return nil, err
+	}
+	if !mode.Enabled {
+		return nil, ErrNotEnabled
+	}
+
+	mode.Enabled = false
+	mode.UpdatedBy = actorID
+	if err := s.repo.Save(mode); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d disabled maintenance mode", actorID)
+	s.dropState(ctx)
+	return mode, nil
+}
+
+// dropState makes the next request read the maintenance state afresh
+func (s *service) dropState(ctx context.Context) {
+	if err := s.cache.Delete(ctx, stateKey); err != nil {
+		log.Printf("Failed to drop cached maintenance mode: %v", err)
+	}
+}
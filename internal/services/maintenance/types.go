@@ -0,0 +1,46 @@
+package maintenance
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// DefaultMessage is announced when maintenance starts without a message
+const DefaultMessage = "Payments are paused for scheduled maintenance. Please try again shortly."
+
+// DefaultRetryAfter is the seconds clients wait when neither retry_after
+// nor ends_at is given
+const DefaultRetryAfter = 300
+
+// MaxRetryAfter bounds retry_after to a day
+const MaxRetryAfter = 86400
+
+// EnableRequest starts maintenance
+type EnableRequest struct {
+	Message string `json:"message" validate:"max=500"`
+	// RetryAfter is in seconds; zero uses DefaultRetryAfter
+	RetryAfter     int        `json:"retry_after"`
+	EndsAt         *time.Time `json:"ends_at"`
+	AllowedUserIDs []uint     `json:"allowed_user_ids"`
+}
+
+// Status is what clients see of maintenance, without the allowlist
+type Status struct {
+	Enabled    bool       `json:"enabled"`
+	Message    string     `json:"message,omitempty"`
+	RetryAfter int        `json:"retry_after,omitempty"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+}
+
+// StatusOf returns what clients see of the maintenance state
+func StatusOf(mode *models.MaintenanceMode, now time.Time) Status {
+	if !mode.Enabled {
+		return Status{}
+	}
+	return Status{
+		Enabled:    true,
+		Message:    mode.Message,
+		RetryAfter: mode.RetryAfterAt(now),
+		EndsAt:     mode.EndsAt,
+	}
+}
@@ -6,4 +6,11 @@ var (
 	ErrMerchantInactive = errors.New("merchant is not active")
 	ErrInvalidAmount    = errors.New("invalid transaction amount")
 	ErrLimitExceeded    = errors.New("transaction limit exceeded")
+
+	ErrChargeNotFound      = errors.New("charge not found")
+	ErrRefundExceedsCharge = errors.New("refund exceeds the amount left to refund on this charge")
+
+	ErrMerchantNotFound = errors.New("merchant profile not found")
+	ErrUnknownFeePlan   = errors.New("fee plan must be standard, plus or enterprise")
+	ErrInvalidFeeRate   = errors.New("negotiated rate must be at least 0 and below 1")
 )
@@ -2,44 +2,153 @@ package merchant
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"orus/internal/models"
 	"orus/internal/repositories"
-	"orus/internal/services"
+	"orus/internal/services/paymentcode"
 	"orus/internal/services/qr_code"
+	"orus/internal/services/receipt"
+	"orus/internal/services/saga"
 	"orus/internal/services/transaction"
 	"orus/internal/services/wallet"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// OperatorAuthorizer verifies a staff operator's PIN and scope
+type OperatorAuthorizer interface {
+	AuthorizeOperator(merchantUserID, staffID uint, pin, scope string) (*models.MerchantStaff, error)
+}
+
+// PaymentCodeRedeemer claims customers' numeric one-time payment codes for
+// a charge, then confirms or releases them once the charge settles
+type PaymentCodeRedeemer interface {
+	Redeem(ctx context.Context, code string, merchantUserID uint, amount float64) (*models.PaymentCode, error)
+	Confirm(ctx context.Context, code *models.PaymentCode, transactionID uint) error
+	Release(ctx context.Context, code *models.PaymentCode) error
+}
+
+// PayerConfirmation holds charges of scanned payment code QRs for payers
+// who approve them first, and records how approved ones went
+type PayerConfirmation interface {
+	Hold(ctx context.Context, confirmation *models.ScanConfirmation) (bool, error)
+	GetForMerchant(ctx context.Context, merchantUserID, id uint) (*models.ScanConfirmation, error)
+	Claim(ctx context.Context, userID, id uint) (*models.ScanConfirmation, error)
+	Complete(ctx context.Context, id, transactionID uint) error
+	Fail(ctx context.Context, id uint, reason string) error
+}
+
+// TerminalVerifier checks that a point-of-sale terminal belongs to the
+// merchant and may take payments
+type TerminalVerifier interface {
+	ActiveTerminal(merchantUserID, terminalID uint) (*models.Terminal, error)
+}
+
 type Service struct {
+	merchants          repositories.MerchantRepository
+	qrCodes            repositories.QRCodeRepository
+	transactions       repositories.TransactionRepository
 	qrService          qr_code.Service
 	transactionService transaction.Service
 	walletService      wallet.Service
-	feeCalculator      *services.FeeCalculator
+	receiptService     receipt.Service
+	operators          OperatorAuthorizer
+	terminals          TerminalVerifier
+	paymentCodes       PaymentCodeRedeemer
+	payerConfirmation  PayerConfirmation
+	sagas              saga.Service
 }
 
+// Saga compensations undoing the steps of merchant payments
+const (
+	compensateDebit  = "merchant.refund_debit"   // Credits a debited wallet back
+	compensateCredit = "merchant.reverse_credit" // Debits a credited wallet back
+	compensateRedeem = "merchant.release_payment_code"
+)
+
 func NewService(
+	merchants repositories.MerchantRepository,
+	qrCodes repositories.QRCodeRepository,
+	transactions repositories.TransactionRepository,
 	qrSvc qr_code.Service,
 	txSvc transaction.Service,
 	walletSvc wallet.Service,
+	receiptSvc receipt.Service,
+	operators OperatorAuthorizer,
+	terminals TerminalVerifier,
+	paymentCodes PaymentCodeRedeemer,
+	payerConfirmation PayerConfirmation,
+	sagas saga.Service,
 ) *Service {
-	return &Service{
+	s := &Service{
+		merchants:          merchants,
+		qrCodes:            qrCodes,
+		transactions:       transactions,
 		qrService:          qrSvc,
 		transactionService: txSvc,
 		walletService:      walletSvc,
-		feeCalculator:      services.NewFeeCalculator(),
+		receiptService:     receiptSvc,
+		operators:          operators,
+		terminals:          terminals,
+		paymentCodes:       paymentCodes,
+		payerConfirmation:  payerConfirmation,
+		sagas:              sagas,
 	}
+	s.registerCompensations()
+	return s
+}
+
+// registerCompensations lets the saga service undo the steps of merchant
+// payments, on this instance or from the job on another
+func (s *Service) registerCompensations() {
+	s.sagas.Register(compensateDebit, func(ctx context.Context, params map[string]interface{}) error {
+		walletID, amount, err := walletParams(params)
+		if err != nil {
+			return err
+		}
+		return s.walletService.Refund(ctx, walletID, amount)
+	})
+	s.sagas.Register(compensateCredit, func(ctx context.Context, params map[string]interface{}) error {
+		walletID, amount, err := walletParams(params)
+		if err != nil {
+			return err
+		}
+		return s.walletService.Debit(ctx, walletID, amount)
+	})
+	s.sagas.Register(compensateRedeem, func(ctx context.Context, params map[string]interface{}) error {
+		codeID, err := saga.Uint(params, "payment_code_id")
+		if err != nil {
+			return err
+		}
+		return s.paymentCodes.Release(ctx, &models.PaymentCode{ID: codeID})
+	})
+}
+
+// walletParams reads the wallet and amount a wallet step recorded
+func walletParams(params map[string]interface{}) (uint, float64, error) {
+	walletID, err := saga.Uint(params, "wallet_id")
+	if err != nil {
+		return 0, 0, err
+	}
+	amount, err := saga.Float(params, "amount")
+	if err != nil {
+		return 0, 0, err
+	}
+	return walletID, amount, nil
 }
 
 func (s *Service) CreateMerchant(merchant *models.Merchant) (*models.Merchant, error) {
 	log.Printf("Creating new merchant for user ID: %d", merchant.UserID)
 
 	// Check for existing merchant
-	existingMerchant, err := repositories.GetMerchantByUserID(merchant.UserID)
+	existingMerchant, err := s.merchants.GetByUserID(merchant.UserID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
@@ -49,7 +158,7 @@ func (s *Service) CreateMerchant(merchant *models.Merchant) (*models.Merchant, e
 
 	// Set defaults
 	merchant.RiskScore = int(calculateInitialRiskScore(merchant))
-	merchant.ComplianceLevel = determineComplianceLevel(float64(merchant.RiskScore))
+	merchant.ComplianceLevel = models.ComplianceLevelFor(merchant.RiskScore)
 	merchant.DailyTransactionLimit = DefaultDailyLimit
 	merchant.MonthlyTransactionLimit = DefaultMonthlyLimit
 	merchant.MinTransactionAmount = DefaultMinAmount
@@ -57,26 +166,48 @@ func (s *Service) CreateMerchant(merchant *models.Merchant) (*models.Merchant, e
 	merchant.Status = "active"
 
 	// Create merchant profile without QR codes
-	if err := repositories.DB.Create(merchant).Error; err != nil {
+	if err := s.merchants.Create(merchant); err != nil {
 		return nil, err
 	}
 	return merchant, nil
 }
 
-func (s *Service) ProcessDirectCharge(merchantID uint, input ChargeInput) (*models.Transaction, error) {
-	// Validate the payment code
-	var qrCode models.QRCode
-	if err := repositories.DB.Where("code = ? AND status = ?", input.PaymentCode, "active").First(&qrCode).Error; err != nil {
-		return nil, fmt.Errorf("invalid payment code: %w", err)
+// ProcessDirectCharge charges the customer whose payment code the merchant
+// scanned or was read. A payer who approves scans of their payment code QR
+// gets the charge held instead, returned as its confirmation.
+func (s *Service) ProcessDirectCharge(merchantID uint, input ChargeInput) (*models.Transaction, *models.ScanConfirmation, error) {
+	terminal, err := s.terminal(merchantID, input.TerminalID)
+	if err != nil {
+		return nil, nil, err
 	}
+	operator, err := s.operator(merchantID, input.OperatorID, input.OperatorPIN, models.StaffScopeCharge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A short all-digit code is a one-time code the customer read out;
+	// anything else is the code of their payment code QR
+	oneTime := paymentcode.IsCode(input.PaymentCode)
 
-	// Verify this is a payment code QR, not a receive QR
-	if qrCode.Type != string(qr_code.TypePaymentCode) {
-		return nil, fmt.Errorf("invalid QR type: merchant can only scan payment codes")
+	// Validate the payment code
+	var qrCode *models.QRCode
+	if !oneTime {
+		qrCode, err = s.qrCodes.GetByCode(input.PaymentCode)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid payment code: %w", err)
+		}
+		if qrCode.Status != "active" {
+			return nil, nil, fmt.Errorf("invalid payment code: %w", gorm.ErrRecordNotFound)
+		}
+
+		// Verify this is a payment code QR, not a receive QR
+		if qrCode.Type != string(qr_code.TypePaymentCode) {
+			return nil, nil, fmt.Errorf("invalid QR type: merchant can only scan payment codes")
+		}
 	}
 
 	// Get merchant details
-	merchant, err := repositories.GetMerchantByUserID(merchantID)
+	merchant, err := s.merchants.GetByUserID(merchantID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Create default merchant profile
@@ -93,14 +224,176 @@ func (s *Service) ProcessDirectCharge(merchantID uint, input ChargeInput) (*mode
 				MaxTransactionAmount:    DefaultMaxAmount,
 			}
 
-			if err := repositories.DB.Create(merchant).Error; err != nil {
-				return nil, fmt.Errorf("failed to create merchant profile: %w", err)
+			if err := s.merchants.Create(merchant); err != nil {
+				return nil, nil, fmt.Errorf("failed to create merchant profile: %w", err)
 			}
 		} else {
-			return nil, fmt.Errorf("merchant not found: %w", err)
+			return nil, nil, fmt.Errorf("merchant not found: %w", err)
 		}
 	}
 
+	// Reject bad itemization before any money moves
+	receiptDetails := receipt.Details{
+		Items:     input.Items,
+		TaxAmount: input.TaxAmount,
+		Tip:       input.Tip,
+		EmailTo:   input.ReceiptEmail,
+	}
+	if err := s.receiptService.Validate(input.Amount, receiptDetails); err != nil {
+		return nil, nil, err
+	}
+
+	// Payers can ask to approve scans of their payment code QR before any
+	// money moves. One-time codes aren't held: the payer generated the code
+	// for this payment, up to an amount they chose.
+	if !oneTime {
+		held, err := s.holdForPayer(qrCode, merchant, input)
+		if err != nil || held != nil {
+			return nil, held, err
+		}
+	}
+
+	tx, err := s.charge(qrCode, merchant, operator, terminal, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, nil, nil
+}
+
+// charge takes a validated charge from the payment code's owner, then
+// attributes it to the merchant's operator and terminal and issues its
+// receipt
+func (s *Service) charge(qrCode *models.QRCode, merchant *models.Merchant, operator *models.MerchantStaff, terminal *models.Terminal, input ChargeInput) (*models.Transaction, error) {
+	var (
+		tx  *models.Transaction
+		err error
+	)
+	if qrCode == nil {
+		tx, err = s.redeemPaymentCode(context.Background(), merchant, input)
+	} else {
+		tx, err = s.chargePaymentCodeQR(qrCode, merchant, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Enrich transaction with merchant details
+	tx.MerchantID = &merchant.ID
+	tx.MerchantName = merchant.BusinessName
+	tx.MerchantCategory = merchant.BusinessType
+	if operator != nil {
+		tx.OperatorID = &operator.ID
+	}
+	if terminal != nil {
+		tx.TerminalID = &terminal.ID
+	}
+
+	// Update the transaction record
+	if err := s.transactions.Update(tx); err != nil {
+		return nil, fmt.Errorf("failed to update transaction with merchant details: %w", err)
+	}
+
+	receiptDetails := receipt.Details{
+		Items:     input.Items,
+		TaxAmount: input.TaxAmount,
+		Tip:       input.Tip,
+		EmailTo:   input.ReceiptEmail,
+	}
+	if _, err := s.receiptService.Issue(context.Background(), tx, receiptDetails); err != nil {
+		// The charge went through; the receipt can be issued again on request
+		log.Printf("Failed to issue receipt for transaction %d: %v", tx.ID, err)
+	}
+
+	return tx, nil
+}
+
+// holdForPayer holds the charge for the payer to approve if they asked to,
+// returning nil when it can go ahead. The operator's PIN was checked here
+// and isn't kept.
+func (s *Service) holdForPayer(qrCode *models.QRCode, merchant *models.Merchant, input ChargeInput) (*models.ScanConfirmation, error) {
+	// Don't prompt the payer for a charge their balance would refuse
+	if err := s.walletService.ValidateBalance(context.Background(), qrCode.UserID, input.Amount); err != nil {
+		return nil, fmt.Errorf("insufficient balance: %w", err)
+	}
+
+	input.OperatorPIN = ""
+	confirmation := &models.ScanConfirmation{
+		UserID:         qrCode.UserID,
+		MerchantUserID: merchant.UserID,
+		MerchantName:   merchant.BusinessName,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		Charge:         models.NewJSON(input),
+	}
+	held, err := s.payerConfirmation.Hold(context.Background(), confirmation)
+	if err != nil || !held {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+// ApproveScanCharge takes a held charge the payer approved. It runs through
+// the same checks as when it was scanned, so a charge the payer can no
+// longer afford, or whose code or terminal was revoked since, fails here.
+func (s *Service) ApproveScanCharge(ctx context.Context, payerID, id uint) (*models.Transaction, error) {
+	confirmation, err := s.payerConfirmation.Claim(ctx, payerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.chargeApproved(confirmation)
+	if err != nil {
+		if failErr := s.payerConfirmation.Fail(ctx, id, err.Error()); failErr != nil {
+			log.Printf("Failed to record failure of scan confirmation %d: %v", id, failErr)
+		}
+		return nil, err
+	}
+	if err := s.payerConfirmation.Complete(ctx, id, tx.ID); err != nil {
+		log.Printf("Failed to link scan confirmation %d to transaction %d: %v", id, tx.ID, err)
+	}
+	return tx, nil
+}
+
+// GetScanCharge returns a charge held for the payer's approval, for the
+// point of sale that took it to follow
+func (s *Service) GetScanCharge(ctx context.Context, merchantID, id uint) (*models.ScanConfirmation, error) {
+	return s.payerConfirmation.GetForMerchant(ctx, merchantID, id)
+}
+
+func (s *Service) chargeApproved(confirmation *models.ScanConfirmation) (*models.Transaction, error) {
+	raw, err := confirmation.Charge.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read held charge: %w", err)
+	}
+	var input ChargeInput
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return nil, fmt.Errorf("failed to read held charge: %w", err)
+	}
+
+	qrCode, err := s.qrCodes.GetByCode(input.PaymentCode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment code: %w", err)
+	}
+	if qrCode.Status != "active" || qrCode.UserID != confirmation.UserID {
+		return nil, fmt.Errorf("invalid payment code: %w", gorm.ErrRecordNotFound)
+	}
+	merchant, err := s.merchants.GetByUserID(confirmation.MerchantUserID)
+	if err != nil {
+		return nil, fmt.Errorf("merchant not found: %w", err)
+	}
+	terminal, err := s.terminal(merchant.UserID, input.TerminalID)
+	if err != nil {
+		return nil, err
+	}
+	var operator *models.MerchantStaff
+	if input.OperatorID != 0 {
+		operator = &models.MerchantStaff{ID: input.OperatorID}
+	}
+	return s.charge(qrCode, merchant, operator, terminal, input)
+}
+
+// chargePaymentCodeQR charges the owner of a scanned payment code QR
+func (s *Service) chargePaymentCodeQR(qrCode *models.QRCode, merchant *models.Merchant, input ChargeInput) (*models.Transaction, error) {
 	// Get customer ID from QR code
 	customerID := qrCode.UserID
 
@@ -112,38 +405,200 @@ func (s *Service) ProcessDirectCharge(merchantID uint, input ChargeInput) (*mode
 	metadata := map[string]interface{}{
 		"scanner_role":      "merchant",
 		"payment_code":      input.PaymentCode,
-		"merchant_id":       merchantID,
+		"merchant_id":       merchant.UserID,
 		"merchant_name":     merchant.BusinessName,
 		"merchant_category": merchant.BusinessType,
 		"payment_type":      "merchant_scan",
 		"device_type":       "pos",
 	}
 
-	tx, err := s.qrService.ProcessQRPayment(
+	return s.qrService.ProcessQRPayment(
 		context.Background(),
 		input.PaymentCode,
 		input.Amount,
-		merchantID,
+		merchant.UserID,
 		input.Description,
 		metadata,
 	)
+}
+
+// redeemPaymentCode charges the owner of a one-time payment code. The code
+// is claimed first so it can't pay twice, and handed back by the saga if
+// the charge fails.
+func (s *Service) redeemPaymentCode(ctx context.Context, merchant *models.Merchant, input ChargeInput) (*models.Transaction, error) {
+	code, err := s.paymentCodes.Redeem(ctx, input.PaymentCode, merchant.UserID, input.Amount)
 	if err != nil {
 		return nil, err
 	}
 
-	// Enrich transaction with merchant details
-	tx.MerchantID = &merchant.ID
-	tx.MerchantName = merchant.BusinessName
-	tx.MerchantCategory = merchant.BusinessType
-
-	// Update the transaction record
-	if err := repositories.DB.Save(tx).Error; err != nil {
-		return nil, fmt.Errorf("failed to update transaction with merchant details: %w", err)
+	now := time.Now()
+	charge := &models.Transaction{
+		Type:             "merchant_payment",
+		SenderID:         code.UserID,
+		ReceiverID:       merchant.UserID,
+		Amount:           input.Amount,
+		Fee:              merchant.EffectiveFee().Calculate(input.Amount),
+		Description:      input.Description,
+		Status:           "pending",
+		TransactionID:    fmt.Sprintf("PCD-%d-%d", code.ID, now.UnixNano()),
+		Reference:        fmt.Sprintf("PCD-%d", code.ID),
+		PaymentType:      "payment_code",
+		PaymentMethod:    "wallet",
+		MerchantID:       &merchant.ID,
+		MerchantName:     merchant.BusinessName,
+		MerchantCategory: merchant.BusinessType,
+		Category:         "Sale",
+		Metadata: models.NewJSON(map[string]interface{}{
+			"payment_code_id": code.ID,
+		}),
 	}
 
+	var tx *models.Transaction
+	err = s.sagas.Execute(ctx, saga.Definition{
+		Name: "payment_code_charge",
+		Steps: []saga.Step{
+			{
+				// Redeem already claimed the code; the step records how
+				// to hand it back
+				Name:         "redeem_code",
+				Run:          func(context.Context) error { return nil },
+				Compensation: compensateRedeem,
+				Params:       map[string]interface{}{"payment_code_id": code.ID},
+			},
+			{
+				Name: "charge_customer",
+				Run: func(ctx context.Context) (err error) {
+					tx, err = s.transactionService.ProcessTransaction(ctx, charge)
+					return err
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.paymentCodes.Confirm(ctx, code, tx.ID); err != nil {
+		log.Printf("Failed to link payment code %d to transaction %d: %v", code.ID, tx.ID, err)
+	}
 	return tx, nil
 }
 
+// RefundCharge returns all or part of a completed charge to the customer
+// from the merchant's wallet
+func (s *Service) RefundCharge(ctx context.Context, merchantID uint, input RefundInput) (*models.Transaction, error) {
+	terminal, err := s.terminal(merchantID, input.TerminalID)
+	if err != nil {
+		return nil, err
+	}
+	operator, err := s.operator(merchantID, input.OperatorID, input.OperatorPIN, models.StaffScopeRefund)
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.transactions.GetMerchantCharge(merchantID, input.TransactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChargeNotFound
+		}
+		return nil, fmt.Errorf("failed to get charge: %w", err)
+	}
+
+	refunded, err := s.transactions.GetRefundedAmount(merchantID, charge.ID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := math.Round((charge.Amount-refunded)*100) / 100
+
+	amount := math.Round(input.Amount*100) / 100
+	if input.Amount == 0 {
+		amount = remaining
+	}
+	if amount <= 0 {
+		if remaining <= 0 {
+			return nil, ErrRefundExceedsCharge
+		}
+		return nil, ErrInvalidAmount
+	}
+	if amount > remaining {
+		return nil, ErrRefundExceedsCharge
+	}
+
+	description := "Refund"
+	if reason := strings.TrimSpace(input.Reason); reason != "" {
+		description = "Refund: " + reason
+	}
+	refund := &models.Transaction{
+		Type:             models.TransactionTypeRefund,
+		SenderID:         merchantID,
+		ReceiverID:       charge.SenderID,
+		Amount:           amount,
+		Currency:         charge.Currency,
+		Description:      description,
+		TransactionID:    fmt.Sprintf("REF-%d-%d", charge.ID, time.Now().UnixNano()),
+		Reference:        strconv.FormatUint(uint64(charge.ID), 10),
+		MerchantID:       charge.MerchantID,
+		MerchantName:     charge.MerchantName,
+		MerchantCategory: charge.MerchantCategory,
+		Category:         "Refund",
+		Metadata: models.NewJSON(map[string]interface{}{
+			"original_transaction_id": charge.ID,
+			"reason":                  strings.TrimSpace(input.Reason),
+		}),
+	}
+	if operator != nil {
+		refund.OperatorID = &operator.ID
+	}
+	if terminal != nil {
+		refund.TerminalID = &terminal.ID
+	}
+	return s.transactionService.ProcessTransaction(ctx, refund)
+}
+
+// ReverseCharge undoes a charge taken in error, returning the amount to the
+// customer. Operators need the refund scope.
+func (s *Service) ReverseCharge(ctx context.Context, merchantID uint, input ReverseInput) (*models.TransactionReversal, error) {
+	operator, err := s.operator(merchantID, input.OperatorID, input.OperatorPIN, models.StaffScopeRefund)
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.transactions.GetMerchantCharge(merchantID, input.TransactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChargeNotFound
+		}
+		return nil, fmt.Errorf("failed to get charge: %w", err)
+	}
+
+	req := transaction.ReversalRequest{
+		TransactionID:  charge.ID,
+		Reason:         input.Reason,
+		ReversedBy:     merchantID,
+		Actor:          models.ReversalActorMerchant,
+		MerchantUserID: merchantID,
+	}
+	if operator != nil {
+		req.OperatorID = &operator.ID
+	}
+	return s.transactionService.Reverse(ctx, req)
+}
+
+// operator authorizes the staff member acting at the till, if any
+func (s *Service) operator(merchantID, operatorID uint, pin, scope string) (*models.MerchantStaff, error) {
+	if operatorID == 0 {
+		return nil, nil
+	}
+	return s.operators.AuthorizeOperator(merchantID, operatorID, pin, scope)
+}
+
+// terminal checks the point-of-sale terminal the payment is taken on, if any
+func (s *Service) terminal(merchantID, terminalID uint) (*models.Terminal, error) {
+	if terminalID == 0 {
+		return nil, nil
+	}
+	return s.terminals.ActiveTerminal(merchantID, terminalID)
+}
+
 // Move all merchant service methods here
 
 func calculateInitialRiskScore(merchant *models.Merchant) float64 {
@@ -160,21 +615,10 @@ func calculateInitialRiskScore(merchant *models.Merchant) float64 {
 	return score
 }
 
-func determineComplianceLevel(riskScore float64) string {
-	switch {
-	case riskScore < 30:
-		return "low_risk"
-	case riskScore < 70:
-		return "medium_risk"
-	default:
-		return "high_risk"
-	}
-}
-
 func (s *Service) processTransaction(tx *models.Transaction) (*models.Transaction, error) {
 	ctx := context.Background()
 
-	merchant, err := repositories.GetMerchantByUserID(tx.ReceiverID)
+	merchant, err := s.merchants.GetByUserID(tx.ReceiverID)
 	if err != nil {
 		return nil, err
 	}
@@ -185,25 +629,53 @@ func (s *Service) processTransaction(tx *models.Transaction) (*models.Transactio
 	tx.MerchantCategory = merchant.BusinessType
 	tx.PaymentMethod = "WALLET"
 
-	// Calculate fee
-	fee := s.feeCalculator.CalculateFee(tx.Amount)
+	// The customer pays the merchant's processing fee on top
+	fee := merchant.EffectiveFee().Calculate(tx.Amount)
 	tx.Fee = fee
 
-	// Process the actual transaction
-	err = repositories.DB.Transaction(func(db *gorm.DB) error {
-		if err := s.walletService.Debit(ctx, tx.SenderID, tx.Amount+fee); err != nil {
-			return err
-		}
+	// The wallet steps take wallet IDs, not the users' IDs the transaction
+	// carries
+	customer, err := s.walletService.GetWallet(ctx, tx.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	merchantWallet, err := s.walletService.GetWallet(ctx, tx.ReceiverID)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := s.walletService.Credit(ctx, tx.ReceiverID, tx.Amount); err != nil {
-			_ = s.walletService.Credit(ctx, tx.SenderID, tx.Amount+fee)
-			return err
-		}
+	tx.Status = "pending"
+	if err := s.transactions.CreateTransaction(tx); err != nil {
+		return nil, err
+	}
 
-		tx.Status = "completed"
-		return db.Save(tx).Error
+	// Debit the customer, credit the merchant and complete the
+	// transaction; whatever went through is undone if a later step fails
+	err = s.sagas.Execute(ctx, saga.Definition{
+		Name:          "merchant_payment",
+		TransactionID: &tx.ID,
+		Steps: []saga.Step{
+			{
+				Name:         "debit_customer",
+				Run:          func(ctx context.Context) error { return s.walletService.Debit(ctx, customer.ID, tx.Amount+fee) },
+				Compensation: compensateDebit,
+				Params:       map[string]interface{}{"wallet_id": customer.ID, "amount": tx.Amount + fee},
+			},
+			{
+				Name:         "credit_merchant",
+				Run:          func(ctx context.Context) error { return s.walletService.Credit(ctx, merchantWallet.ID, tx.Amount) },
+				Compensation: compensateCredit,
+				Params:       map[string]interface{}{"wallet_id": merchantWallet.ID, "amount": tx.Amount},
+			},
+			{
+				Name: "complete_transaction",
+				Run: func(context.Context) error {
+					tx.Status = "completed"
+					return s.transactions.Update(tx)
+				},
+			},
+		},
 	})
-
 	if err != nil {
 		return nil, err
 	}
@@ -212,11 +684,16 @@ func (s *Service) processTransaction(tx *models.Transaction) (*models.Transactio
 }
 
 func (s *Service) GetMerchant(ctx context.Context, userID uint) (*models.Merchant, error) {
-	return repositories.GetMerchantByUserID(userID)
+	return s.merchants.GetByUserID(userID)
+}
+
+// SaveMerchant writes every field of the merchant's profile
+func (s *Service) SaveMerchant(merchant *models.Merchant) error {
+	return s.merchants.Update(merchant)
 }
 
 func (s *Service) UpdateMerchantProfile(merchantID uint, input UpdateMerchantInput) error {
-	merchant, err := repositories.GetMerchantByUserID(merchantID)
+	merchant, err := s.merchants.GetByUserID(merchantID)
 	if err != nil {
 		return err
 	}
@@ -224,10 +701,33 @@ func (s *Service) UpdateMerchantProfile(merchantID uint, input UpdateMerchantInp
 	merchant.BusinessName = input.BusinessName
 	merchant.BusinessType = input.BusinessType
 	merchant.BusinessAddress = input.BusinessAddress
-	merchant.ProcessingFeeRate = input.ProcessingFee
 	merchant.WebhookURL = input.WebhookURL
 
-	return repositories.UpdateMerchant(merchant)
+	return s.merchants.UpdateProfile(merchant)
+}
+
+// SetFeePlan moves the merchant to a fee plan at their negotiated rate,
+// if any, returning the updated profile
+func (s *Service) SetFeePlan(ctx context.Context, merchantUserID uint, input FeePlanInput) (*models.Merchant, error) {
+	if _, ok := models.FeePlans[input.Plan]; !ok {
+		return nil, ErrUnknownFeePlan
+	}
+	if input.Rate < 0 || input.Rate >= 1 {
+		return nil, ErrInvalidFeeRate
+	}
+	merchant, err := s.merchants.GetByUserID(merchantUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMerchantNotFound
+		}
+		return nil, err
+	}
+	if err := s.merchants.SetFeePlan(merchant.ID, input.Plan, input.Rate); err != nil {
+		return nil, err
+	}
+	merchant.FeePlan = input.Plan
+	merchant.ProcessingFeeRate = input.Rate
+	return merchant, nil
 }
 
 func (s *Service) ProcessQRPayment(ctx context.Context, merchantID uint, input QRPaymentInput) (*models.Transaction, error) {
@@ -244,9 +744,17 @@ func (s *Service) ProcessQRPayment(ctx context.Context, merchantID uint, input Q
 }
 
 func (s *Service) GenerateAPIKey(merchantID uint) (string, error) {
-	return repositories.GenerateMerchantAPIKey(merchantID)
+	return s.merchants.GenerateAPIKey(merchantID)
+}
+
+// GenerateSandboxAPIKey replaces the merchant's sandbox API key. Sandbox
+// keys only reach the /v1/sandbox endpoints.
+func (s *Service) GenerateSandboxAPIKey(merchantID uint) (string, error) {
+	return s.merchants.GenerateSandboxAPIKey(merchantID)
 }
 
-func (s *Service) SetWebhookURL(merchantID uint, webhookURL string) error {
-	return repositories.SetMerchantWebhookURL(merchantID, webhookURL)
+// SetWebhookURL sets the merchant's webhook endpoint and returns the secret
+// deliveries are signed with
+func (s *Service) SetWebhookURL(merchantID uint, webhookURL string) (string, error) {
+	return s.merchants.SetWebhookURL(merchantID, webhookURL)
 }
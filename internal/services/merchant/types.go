@@ -1,19 +1,43 @@
 package merchant
 
+import "orus/internal/services/receipt"
+
 // Input types for merchant operations
 type UpdateMerchantInput struct {
-	BusinessName    string  `json:"business_name"`
-	BusinessType    string  `json:"business_type"`
-	BusinessAddress string  `json:"business_address"`
-	ProcessingFee   float64 `json:"processing_fee"`
-	WebhookURL      string  `json:"webhook_url"`
+	BusinessName    string `json:"business_name"`
+	BusinessType    string `json:"business_type"`
+	BusinessAddress string `json:"business_address"`
+	WebhookURL      string `json:"webhook_url"`
+}
+
+// FeePlanInput moves a merchant to a fee plan and sets their negotiated
+// rate, or clears it with zero
+type FeePlanInput struct {
+	Plan string `json:"plan" validate:"required"`
+	// Rate is the fraction of each payment charged, 0.021 for 2.1%
+	Rate float64 `json:"rate" validate:"gte=0,lt=1"`
 }
 
 type ChargeInput struct {
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
+	Amount      float64 `json:"amount" validate:"gt=0"`
+	Description string  `json:"description" validate:"max=255"`
 	PaymentType string  `json:"payment_type"`
-	PaymentCode string  `json:"payment_code"`
+	// PaymentCode is the customer's payment code QR, or the 6 to 8 digit
+	// one-time code they generated instead
+	PaymentCode string `json:"payment_code" validate:"required"`
+
+	// Optional receipt itemization; items must add up to amount less tax and tip
+	Items        []receipt.Item `json:"items"`
+	TaxAmount    float64        `json:"tax_amount" validate:"gte=0"`
+	Tip          float64        `json:"tip" validate:"gte=0"`
+	ReceiptEmail string         `json:"receipt_email" validate:"omitempty,email"`
+
+	// Set when a staff operator takes the payment; their PIN is required
+	OperatorID  uint   `json:"operator_id"`
+	OperatorPIN string `json:"operator_pin"`
+
+	// Point-of-sale terminal the payment is taken on
+	TerminalID uint `json:"terminal_id"`
 }
 
 type QRPaymentInput struct {
@@ -23,8 +47,24 @@ type QRPaymentInput struct {
 	Metadata    map[string]interface{} `json:"metadata"`
 }
 
+// RefundInput refunds all or part of a charge. TransactionID is the charge's
+// ID or external reference; Amount defaults to what is left to refund.
 type RefundInput struct {
-	TransactionID string  `json:"transaction_id"`
-	Amount        float64 `json:"amount"`
-	Reason        string  `json:"reason"`
+	TransactionID string  `json:"transaction_id" validate:"required"`
+	Amount        float64 `json:"amount" validate:"gte=0"`
+	Reason        string  `json:"reason" validate:"max=255"`
+
+	OperatorID  uint   `json:"operator_id"`
+	OperatorPIN string `json:"operator_pin"`
+	TerminalID  uint   `json:"terminal_id"`
+}
+
+// ReverseInput reverses a charge taken in error. Unlike a refund it undoes
+// the whole charge, and is refused once any of it has been refunded.
+type ReverseInput struct {
+	TransactionID string `json:"transaction_id" validate:"required"`
+	Reason        string `json:"reason" validate:"required,max=255"`
+
+	OperatorID  uint   `json:"operator_id"`
+	OperatorPIN string `json:"operator_pin"`
 }
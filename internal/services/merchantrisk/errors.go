@@ -0,0 +1,11 @@
+package merchantrisk
+
+import "errors"
+
+// Service errors
+var (
+	ErrMerchantNotFound = errors.New("merchant profile not found")
+	ErrNotRestricted    = errors.New("merchant has no risk restrictions to clear")
+	ErrInvalidStatus    = errors.New("risk status must be tightened or review")
+	ErrBusy             = errors.New("merchant is being assessed, try again")
+)
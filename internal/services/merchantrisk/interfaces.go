@@ -0,0 +1,30 @@
+package merchantrisk
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Reserves raises a merchant's rolling reserve while they are restricted
+type Reserves interface {
+	ApplyRisk(ctx context.Context, merchantUserID uint, status string) error
+}
+
+// Service scores merchants on their chargebacks, refunds and volume, and
+// lowers the transaction limit of those that look risky
+type Service interface {
+	// AssessDue re-scores merchants not assessed in the last hour and
+	// returns how many it restricted
+	AssessDue(ctx context.Context) (int, error)
+	// Assess re-scores one merchant now
+	Assess(ctx context.Context, merchantUserID uint) (*models.MerchantRiskAssessment, error)
+
+	GetProfile(ctx context.Context, merchantUserID uint) (*Profile, error)
+	ListHistory(ctx context.Context, merchantUserID uint, limit, offset int) ([]models.MerchantRiskAssessment, int64, error)
+	// ListRestricted returns merchants with the risk status, or all
+	// tightened and under review ones when it is empty
+	ListRestricted(ctx context.Context, status string, limit, offset int) ([]Profile, int64, error)
+	// Clear restores the limit a restricted merchant had and records the
+	// admin's review
+	Clear(ctx context.Context, merchantUserID, adminID uint, note string) (*Profile, error)
+}
@@ -0,0 +1,24 @@
+package merchantrisk
+
+import (
+	"context"
+	"log"
+)
+
+// Job re-scores merchants and restricts the ones crossing risk thresholds
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the merchant risk service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "merchant-risk" }
+
+func (j *Job) Run(ctx context.Context) error {
+	restricted, err := j.service.AssessDue(ctx)
+	if restricted > 0 {
+		log.Printf("Restricted %d merchants on risk", restricted)
+	}
+	return err
+}
@@ -0,0 +1,326 @@
+package merchantrisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type service struct {
+	repo      repositories.MerchantRiskRepository
+	merchants repositories.MerchantRepository
+	reserves  Reserves
+	cache     *cache.CacheService
+}
+
+// NewService creates the merchant risk service
+func NewService(repo repositories.MerchantRiskRepository, merchants repositories.MerchantRepository, reserves Reserves, cacheSvc *cache.CacheService) Service {
+	return &service{
+		repo:      repo,
+		merchants: merchants,
+		reserves:  reserves,
+		cache:     cacheSvc,
+	}
+}
+
+func (s *service) AssessDue(ctx context.Context) (int, error) {
+	restricted := 0
+	for {
+		now := time.Now().UTC()
+		due := now.Add(-assessInterval)
+		merchants, err := s.repo.GetDue(due, batchSize)
+		if err != nil {
+			return restricted, err
+		}
+		for i := range merchants {
+			if err := ctx.Err(); err != nil {
+				return restricted, err
+			}
+			merchant := &merchants[i]
+
+			err := s.repo.MarkAssessed(merchant.ID, due, now)
+			if errors.Is(err, repositories.ErrMerchantRiskAlreadyAssessed) {
+				continue
+			}
+			if err != nil {
+				return restricted, err
+			}
+			assessment, err := s.assessLocked(ctx, merchant.UserID)
+			if errors.Is(err, ErrBusy) {
+				// Another instance is on it
+				continue
+			}
+			if err != nil {
+				log.Printf("Failed to assess risk of merchant %d: %v", merchant.UserID, err)
+				continue
+			}
+			if assessment.Action != models.MerchantRiskActionNone {
+				restricted++
+			}
+		}
+		if len(merchants) < batchSize {
+			return restricted, nil
+		}
+	}
+}
+
+func (s *service) Assess(ctx context.Context, merchantUserID uint) (*models.MerchantRiskAssessment, error) {
+	if _, err := s.merchant(merchantUserID); err != nil {
+		return nil, err
+	}
+	return s.assessLocked(ctx, merchantUserID)
+}
+
+func (s *service) GetProfile(ctx context.Context, merchantUserID uint) (*Profile, error) {
+	merchant, err := s.merchant(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	return newProfile(merchant), nil
+}
+
+func (s *service) ListHistory(ctx context.Context, merchantUserID uint, limit, offset int) ([]models.MerchantRiskAssessment, int64, error) {
+	if _, err := s.merchant(merchantUserID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListAssessments(merchantUserID, limit, offset)
+}
+
+func (s *service) ListRestricted(ctx context.Context, status string, limit, offset int) ([]Profile, int64, error) {
+	if status != "" && status != models.MerchantRiskTightened && status != models.MerchantRiskReview {
+		return nil, 0, ErrInvalidStatus
+	}
+	merchants, total, err := s.repo.ListRestricted(status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	profiles := make([]Profile, len(merchants))
+	for i := range merchants {
+		profiles[i] = *newProfile(&merchants[i])
+	}
+	return profiles, total, nil
+}
+
+func (s *service) Clear(ctx context.Context, merchantUserID, adminID uint, note string) (*Profile, error) {
+	unlock, err := s.lock(ctx, merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	merchant, err := s.merchant(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !merchant.IsRiskRestricted() {
+		return nil, ErrNotRestricted
+	}
+
+	now := time.Now().UTC()
+	assessment := &models.MerchantRiskAssessment{
+		MerchantUserID: merchant.UserID,
+		Score:          merchant.RiskScore,
+		Action:         models.MerchantRiskActionCleared,
+		Status:         models.MerchantRiskNormal,
+		LimitBefore:    merchant.MaxTransactionAmount,
+		Note:           note,
+		ReviewedBy:     &adminID,
+	}
+	if merchant.RiskRestoreLimit != nil {
+		merchant.MaxTransactionAmount = *merchant.RiskRestoreLimit
+	}
+	assessment.LimitAfter = merchant.MaxTransactionAmount
+	merchant.RiskStatus = models.MerchantRiskNormal
+	merchant.RiskRestoreLimit = nil
+	merchant.RiskClearedAt = &now
+	if err := s.repo.SaveAssessment(merchant, assessment); err != nil {
+		return nil, err
+	}
+	s.applyReserve(ctx, merchant)
+	return newProfile(merchant), nil
+}
+
+func (s *service) merchant(merchantUserID uint) (*models.Merchant, error) {
+	merchant, err := s.merchants.GetByUserID(merchantUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMerchantNotFound
+	}
+	return merchant, err
+}
+
+// lock keeps the job and admins from changing a merchant's limit at the
+// same time
+func (s *service) lock(ctx context.Context, merchantUserID uint) (func(), error) {
+	lock, err := s.cache.Lock(ctx, fmt.Sprintf("merchant_risk:%d", merchantUserID), assessmentLock, assessmentWait)
+	if errors.Is(err, cache.ErrLockBusy) {
+		return nil, ErrBusy
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func() { lock.Unlock(ctx) }, nil
+}
+
+// assessLocked re-reads the merchant under their lock and assesses them
+func (s *service) assessLocked(ctx context.Context, merchantUserID uint) (*models.MerchantRiskAssessment, error) {
+	unlock, err := s.lock(ctx, merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	merchant, err := s.merchant(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	assessment, err := s.assess(merchant, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if assessment.Action != models.MerchantRiskActionNone {
+		s.applyReserve(ctx, merchant)
+	}
+	return assessment, nil
+}
+
+// applyReserve sets the merchant's rolling reserve for their new risk
+// status. The status is saved either way; a failure is only logged.
+func (s *service) applyReserve(ctx context.Context, merchant *models.Merchant) {
+	if err := s.reserves.ApplyRisk(ctx, merchant.UserID, merchant.RiskStatus); err != nil {
+		log.Printf("Failed to set reserve of merchant %d for risk status %s: %v", merchant.UserID, merchant.RiskStatus, err)
+	}
+}
+
+// assess scores the merchant's last Window of activity and restricts
+// them when it crosses a threshold. Monitoring only ever restricts: lifting
+// a restriction takes an admin's review.
+func (s *service) assess(merchant *models.Merchant, now time.Time) (*models.MerchantRiskAssessment, error) {
+	metrics, err := s.repo.GetMetrics(merchant.UserID, now.Add(-Window), now.Add(-recentWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	status := merchant.RiskStatus
+	if status == "" {
+		status = models.MerchantRiskNormal
+	}
+	assessment := &models.MerchantRiskAssessment{
+		MerchantUserID: merchant.UserID,
+		ChargeCount:    metrics.ChargeCount,
+		ChargeVolume:   round2(metrics.ChargeVolume),
+		Action:         models.MerchantRiskActionNone,
+		LimitBefore:    merchant.MaxTransactionAmount,
+	}
+	if metrics.ChargeCount >= MinCharges {
+		charges := float64(metrics.ChargeCount)
+		assessment.ChargebackRate = round4(float64(metrics.ChargebackCount) / charges)
+		assessment.RefundRate = round4(float64(metrics.RefundCount) / charges)
+
+		// The daily average of the volume before the recent window
+		prior := (metrics.ChargeVolume - metrics.RecentVolume) / ((Window - recentWindow).Hours() / 24)
+		if prior > 0 {
+			assessment.VolumeSpike = round2(metrics.RecentVolume / prior)
+		}
+	}
+
+	var reasons []string
+	warning, critical := false, false
+	check := func(name string, value, warn, crit float64, format func(float64) string) {
+		switch {
+		case value >= crit:
+			critical = true
+			reasons = append(reasons, fmt.Sprintf("%s %s at or over %s", name, format(value), format(crit)))
+		case value >= warn:
+			warning = true
+			reasons = append(reasons, fmt.Sprintf("%s %s at or over %s", name, format(value), format(warn)))
+		}
+	}
+	check("chargeback rate", assessment.ChargebackRate, ChargebackRateWarning, ChargebackRateCritical, percent)
+	check("refund rate", assessment.RefundRate, RefundRateWarning, RefundRateCritical, percent)
+	check("volume", assessment.VolumeSpike, VolumeSpikeWarning, VolumeSpikeCritical, multiple)
+	assessment.Reasons = strings.Join(reasons, "; ")
+
+	assessment.Score = score(assessment)
+	merchant.RiskScore = assessment.Score
+	merchant.ComplianceLevel = models.ComplianceLevelFor(assessment.Score)
+
+	graced := merchant.RiskClearedAt != nil && now.Sub(*merchant.RiskClearedAt) < ClearedGracePeriod
+	switch {
+	case graced && (warning || critical):
+		assessment.Note = "cleared by review recently, not restricted"
+	case critical && status != models.MerchantRiskReview:
+		restrict(merchant, reviewLimit(merchant.MaxTransactionAmount))
+		status = models.MerchantRiskReview
+		assessment.Action = models.MerchantRiskActionReview
+	case warning && status == models.MerchantRiskNormal:
+		restrict(merchant, tightenedLimit(merchant.MaxTransactionAmount))
+		status = models.MerchantRiskTightened
+		assessment.Action = models.MerchantRiskActionTightened
+	}
+	merchant.RiskStatus = status
+	merchant.RiskAssessedAt = &now
+	assessment.Status = status
+	assessment.LimitAfter = merchant.MaxTransactionAmount
+
+	if err := s.repo.SaveAssessment(merchant, assessment); err != nil {
+		return nil, err
+	}
+	if assessment.Action != models.MerchantRiskActionNone {
+		log.Printf("Merchant %d risk %s: %s", merchant.UserID, assessment.Action, assessment.Reasons)
+	}
+	return assessment, nil
+}
+
+// restrict lowers the merchant's limit, remembering the one to restore
+// the first time
+func restrict(merchant *models.Merchant, limit float64) {
+	if merchant.RiskRestoreLimit == nil {
+		previous := merchant.MaxTransactionAmount
+		merchant.RiskRestoreLimit = &previous
+	}
+	merchant.MaxTransactionAmount = limit
+}
+
+// tightenedLimit halves the merchant's limit, or sets TightenedLimit when
+// they have none. It never raises the limit.
+func tightenedLimit(current float64) float64 {
+	if current <= 0 {
+		return TightenedLimit
+	}
+	return round2(math.Min(current, math.Max(current/2, MinTightenedLimit)))
+}
+
+// reviewLimit lowers the merchant's limit to ReviewLimit
+func reviewLimit(current float64) float64 {
+	if current <= 0 {
+		return ReviewLimit
+	}
+	return math.Min(current, ReviewLimit)
+}
+
+// score weighs each signal by how close it is to its critical threshold:
+// chargebacks count most, then refunds, then volume spikes
+func score(a *models.MerchantRiskAssessment) int {
+	part := func(value, critical, weight float64) float64 {
+		return math.Min(value/critical, 1) * weight
+	}
+	total := part(a.ChargebackRate, ChargebackRateCritical, 50) +
+		part(a.RefundRate, RefundRateCritical, 30) +
+		part(a.VolumeSpike, VolumeSpikeCritical, 20)
+	return int(math.Round(total))
+}
+
+func percent(v float64) string  { return fmt.Sprintf("%.2f%%", v*100) }
+func multiple(v float64) string { return fmt.Sprintf("%.1fx", v) }
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+func round4(v float64) float64 { return math.Round(v*10000) / 10000 }
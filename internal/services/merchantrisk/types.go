@@ -0,0 +1,85 @@
+package merchantrisk
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+const (
+	// Window is how far back chargeback and refund rates are measured
+	Window = 30 * 24 * time.Hour
+	// recentWindow is the latest volume compared to the daily average
+	// before it
+	recentWindow = 24 * time.Hour
+
+	// MinCharges is how many charges the window needs before chargeback
+	// and refund rates count; a handful of charges says little
+	MinCharges = 20
+
+	// Chargeback rate, refund rate and volume spike thresholds. Crossing a
+	// warning one tightens the merchant's limit; crossing a critical one
+	// sends them to manual review.
+	ChargebackRateWarning  = 0.005
+	ChargebackRateCritical = 0.01
+	RefundRateWarning      = 0.10
+	RefundRateCritical     = 0.20
+	VolumeSpikeWarning     = 3.0
+	VolumeSpikeCritical    = 5.0
+
+	// TightenedLimit caps the transactions of a merchant without a limit
+	// once tightened; a merchant with one has it halved, down to
+	// MinTightenedLimit
+	TightenedLimit    = 1000.0
+	MinTightenedLimit = 100.0
+	// ReviewLimit caps the transactions of a merchant awaiting review
+	ReviewLimit = 50.0
+
+	// ClearedGracePeriod keeps monitoring from restricting a merchant an
+	// admin just cleared again on the same history
+	ClearedGracePeriod = 7 * 24 * time.Hour
+
+	// assessInterval is how often each merchant is re-scored
+	assessInterval = 55 * time.Minute
+	batchSize      = 100
+
+	// assessmentLock bounds how long one instance holds a merchant's
+	// assessment; another waits up to assessmentWait before leaving it
+	assessmentLock = 30 * time.Second
+	assessmentWait = time.Second
+)
+
+// Profile is a merchant's current risk standing
+type Profile struct {
+	MerchantUserID       uint       `json:"merchant_user_id"`
+	BusinessName         string     `json:"business_name"`
+	Score                int        `json:"score"`
+	ComplianceLevel      string     `json:"compliance_level"`
+	Status               string     `json:"status"`
+	MaxTransactionAmount float64    `json:"max_transaction_amount"`
+	RestoreLimit         *float64   `json:"restore_limit,omitempty"` // Given back when cleared
+	AssessedAt           *time.Time `json:"assessed_at,omitempty"`
+	ClearedAt            *time.Time `json:"cleared_at,omitempty"`
+}
+
+func newProfile(m *models.Merchant) *Profile {
+	status := m.RiskStatus
+	if status == "" {
+		status = models.MerchantRiskNormal
+	}
+	return &Profile{
+		MerchantUserID:       m.UserID,
+		BusinessName:         m.BusinessName,
+		Score:                m.RiskScore,
+		ComplianceLevel:      m.ComplianceLevel,
+		Status:               status,
+		MaxTransactionAmount: m.MaxTransactionAmount,
+		RestoreLimit:         m.RiskRestoreLimit,
+		AssessedAt:           m.RiskAssessedAt,
+		ClearedAt:            m.RiskClearedAt,
+	}
+}
+
+// ClearRequest restores a restricted merchant's limit after review
+type ClearRequest struct {
+	Note string `json:"note" validate:"required,max=500"`
+}
@@ -3,17 +3,247 @@ package notification
 import (
 	"context"
 	"log"
+	"orus/internal/i18n"
 	"orus/internal/models"
+	"strings"
+	"time"
 )
 
-// Service is a minimal notification service implementation.
+// Service is a minimal notification service implementation. Messages are
+// written in the language of the request that set them off.
 type Service struct{}
 
 // NewService creates a new notification service.
 func NewService() *Service { return &Service{} }
 
+// eventMessage uses the template for event under prefix, or the generic
+// one taking the event as its last argument
+func eventMessage(lang, prefix, event string, args ...interface{}) string {
+	if key := prefix + "." + event; i18n.Has(key) {
+		return i18n.T(lang, key, args...)
+	}
+	return i18n.T(lang, prefix, append(args, event)...)
+}
+
 // SendTransferNotification logs a transfer notification.
 func (s *Service) SendTransferNotification(ctx context.Context, userID uint, tx *models.Transaction) error {
-	log.Printf("Notify user %d of transfer %s", userID, tx.TransactionID)
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d: %s", userID,
+		i18n.T(lang, "notify.transfer", tx.TransactionID, i18n.FormatAmount(lang, tx.Amount, tx.Currency)))
+	return nil
+}
+
+// SendExportFailedNotification logs a failed scheduled export notification.
+func (s *Service) SendExportFailedNotification(ctx context.Context, userID uint, schedule *models.ExportSchedule, reason string) error {
+	log.Printf("Notify user %d that export schedule %d failed (%d in a row, status %s): %s",
+		userID, schedule.ID, schedule.ConsecutiveFailures, schedule.Status,
+		i18n.T(i18n.FromContext(ctx), "notify.export_failed", reason))
+	return nil
+}
+
+// SendInvoiceEmail logs an invoice email to a customer.
+func (s *Service) SendInvoiceEmail(ctx context.Context, to string, invoice *models.Invoice, kind, payURL string) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Email %s invoice to %s: %s", kind, to, eventMessage(lang, "email.invoice", kind,
+		invoice.Number, i18n.FormatAmount(lang, invoice.Total, invoice.Currency), i18n.FormatDate(lang, invoice.DueDate), payURL))
+	return nil
+}
+
+// SendDepositNotification logs a bank deposit status notification.
+func (s *Service) SendDepositNotification(ctx context.Context, userID uint, deposit *models.Deposit, status string) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d of deposit %d: %s", userID, deposit.ID,
+		eventMessage(lang, "notify.deposit", status, i18n.FormatAmount(lang, deposit.Amount, deposit.Currency)))
+	return nil
+}
+
+// SendReceiptEmail logs a receipt email to a customer.
+func (s *Service) SendReceiptEmail(ctx context.Context, to string, receipt *models.Receipt, pdf []byte) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Email receipt (%d byte PDF) to %s: %s", len(pdf), to,
+		i18n.T(lang, "email.receipt", receipt.Number, i18n.FormatAmount(lang, receipt.Total, receipt.Currency), receipt.MerchantName))
+	return nil
+}
+
+// SendSplitRequest logs a request, or reminder, to pay a split share.
+func (s *Service) SendSplitRequest(ctx context.Context, userID uint, split *models.Split, share *models.SplitShare, reminder bool) error {
+	lang := i18n.FromContext(ctx)
+	key := "notify.split.request"
+	if reminder {
+		key = "notify.split.reminder"
+	}
+	log.Printf("Notify user %d of split %d from user %d: %s", userID, split.ID, split.OrganizerID,
+		i18n.T(lang, key, i18n.FormatAmount(lang, share.Amount, split.Currency), split.Title))
+	return nil
+}
+
+// SendSplitPaid logs that a participant paid their split share.
+func (s *Service) SendSplitPaid(ctx context.Context, userID uint, split *models.Split, share *models.SplitShare) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d that user %d paid split %d: %s", userID, share.UserID, split.ID,
+		i18n.T(lang, "notify.split.paid", i18n.FormatAmount(lang, share.Amount, split.Currency), split.Title, split.PaidCount, split.ShareCount))
+	return nil
+}
+
+// SendEscrowUpdate logs a change to an escrow payment the user is party to.
+func (s *Service) SendEscrowUpdate(ctx context.Context, userID uint, escrow *models.Escrow, event string) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d of escrow %d (buyer %d, seller %d): %s", userID, escrow.ID, escrow.BuyerID, escrow.SellerID,
+		eventMessage(lang, "notify.escrow", event, i18n.FormatAmount(lang, escrow.Amount, escrow.Currency)))
+	return nil
+}
+
+// SendSubscriptionEmail logs a subscription billing email to a customer.
+func (s *Service) SendSubscriptionEmail(ctx context.Context, to string, subscription *models.Subscription, plan *models.SubscriptionPlan, kind string) error {
+	lang := i18n.FromContext(ctx)
+	amount := i18n.FormatAmount(lang, subscription.Amount, subscription.Currency)
+	next := "-"
+	if subscription.NextBillingAt != nil {
+		next = i18n.FormatDate(lang, *subscription.NextBillingAt)
+	}
+
+	message := kind
+	if key := "email.subscription." + kind; i18n.Has(key) {
+		message = i18n.T(lang, key, amount, plan.Name, next)
+	}
+	log.Printf("Email %s for subscription %d (%d failed attempts) to %s: %s",
+		kind, subscription.ID, subscription.FailedAttempts, to, message)
+	return nil
+}
+
+// SendEnterpriseApprovalRequest logs a request to review an organization payment.
+func (s *Service) SendEnterpriseApprovalRequest(ctx context.Context, userID uint, payment *models.EnterprisePayment) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d: organization %d payment %d by user %d needs %s approval: %s",
+		userID, payment.EnterpriseID, payment.ID, payment.RequestedBy, payment.ApproverRole,
+		i18n.T(lang, "notify.enterprise.approval", i18n.FormatNumber(lang, payment.Amount, 2)))
+	return nil
+}
+
+// SendEnterprisePaymentReviewed logs an approver's decision on an organization payment.
+func (s *Service) SendEnterprisePaymentReviewed(ctx context.Context, userID uint, payment *models.EnterprisePayment) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d: organization %d payment %d: %s", userID, payment.EnterpriseID, payment.ID,
+		eventMessage(lang, "notify.enterprise", payment.Status, i18n.FormatNumber(lang, payment.Amount, 2)))
+	return nil
+}
+
+// SendWalletLockNotification logs a lock being placed on, or lifted from, the user's wallet.
+func (s *Service) SendWalletLockNotification(ctx context.Context, userID uint, lock *models.WalletLock, event string) error {
+	lang := i18n.FromContext(ctx)
+	until := i18n.T(lang, "notify.wallet_lock.indefinite")
+	if lock.ExpiresAt != nil {
+		until = i18n.T(lang, "notify.wallet_lock.until", i18n.FormatDateTime(lang, *lock.ExpiresAt))
+	}
+	message := event
+	if key := "notify.wallet_lock." + event; i18n.Has(key) {
+		message = i18n.T(lang, key)
+		if event == models.WalletLockEventLocked {
+			message = i18n.T(lang, key, until)
+		}
+	}
+	log.Printf("Notify user %d: wallet lock %d (%s, %s) %s: %s", userID, lock.ID, lock.Scope, lock.Reason, event, message)
+	return nil
+}
+
+// SendNewLoginAlert logs an email alerting the user to a login from a new country or device.
+func (s *Service) SendNewLoginAlert(ctx context.Context, user *models.User, event *models.LoginEvent, reportURL string) error {
+	lang := i18n.FromContext(ctx)
+	country := event.Country
+	if country == "" {
+		country = i18n.T(lang, "email.unknown_country")
+	}
+	log.Printf("Email %s (device %s, %s): %s", user.Email, event.DeviceID, event.UserAgent,
+		i18n.T(lang, "email.new_login", event.IP, country, i18n.FormatDateTime(lang, event.CreatedAt), reportURL))
+	return nil
+}
+
+// SendLoginCode logs an email with the one-time code that completes the
+// user's login.
+func (s *Service) SendLoginCode(ctx context.Context, user *models.User, code string, ttl time.Duration) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Email %s: %s", user.Email, i18n.T(lang, "email.login_code", code, int(ttl.Minutes())))
+	return nil
+}
+
+// SendPaymentConfirmation logs a confirmation of a completed payment to the
+// payer or the payee, by email or push.
+func (s *Service) SendPaymentConfirmation(ctx context.Context, userID uint, channel, role string, tx *models.Transaction, counterparty, receiptURL string) error {
+	lang := i18n.FromContext(ctx)
+	if counterparty == "" {
+		counterparty = i18n.T(lang, "notify.payment.someone")
+	}
+	log.Printf("Notify user %d by %s: %s", userID, channel, i18n.T(lang, "notify.payment."+role,
+		i18n.FormatAmount(lang, tx.Amount, tx.Currency), counterparty, i18n.FormatAmount(lang, tx.Fee, tx.Currency), receiptURL))
+	return nil
+}
+
+// SendPaymentDigest logs one message confirming many completed payments,
+// with their total in each currency.
+func (s *Service) SendPaymentDigest(ctx context.Context, userID uint, channel string, transactions []*models.Transaction, historyURL string) error {
+	lang := i18n.FromContext(ctx)
+	var currencies []string
+	totals := make(map[string]float64)
+	for _, tx := range transactions {
+		if _, ok := totals[tx.Currency]; !ok {
+			currencies = append(currencies, tx.Currency)
+		}
+		totals[tx.Currency] += tx.Amount
+	}
+	amounts := make([]string, len(currencies))
+	for i, currency := range currencies {
+		amounts[i] = i18n.FormatAmount(lang, totals[currency], currency)
+	}
+	log.Printf("Notify user %d by %s: %s", userID, channel,
+		i18n.T(lang, "notify.payment.digest", len(transactions), strings.Join(amounts, ", "), historyURL))
+	return nil
+}
+
+// SendTransferApprovalRequest logs a push asking the user to approve or deny
+// a large transfer on one of their trusted devices.
+func (s *Service) SendTransferApprovalRequest(ctx context.Context, device *models.TrustedDevice, tx *models.Transaction, expiresAt time.Time) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Push to user %d on device %d (%s): %s", device.UserID, device.ID, device.Name, i18n.T(lang, "notify.transfer.approval",
+		i18n.FormatAmount(lang, tx.Amount, tx.Currency), tx.ReceiverID, i18n.FormatDateTime(lang, expiresAt)))
+	return nil
+}
+
+// SendOAuthPaymentRequest logs a push asking the user to approve or decline
+// a payment a connected app requested.
+func (s *Service) SendOAuthPaymentRequest(ctx context.Context, userID uint, req *models.OAuthPaymentRequest) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Notify user %d: %s", userID, i18n.T(lang, "notify.oauth.payment_request",
+		req.ClientName, i18n.FormatNumber(lang, req.Amount, 2), req.RecipientID, i18n.FormatDateTime(lang, req.ExpiresAt)))
+	return nil
+}
+
+// SendScanChargeApprovalRequest logs a push asking the user to approve or
+// decline a charge a merchant took by scanning their payment code.
+func (s *Service) SendScanChargeApprovalRequest(ctx context.Context, confirmation *models.ScanConfirmation) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Push to user %d: charge confirmation %d: %s", confirmation.UserID, confirmation.ID, i18n.T(lang, "notify.scan.approval",
+		confirmation.MerchantName, i18n.FormatNumber(lang, confirmation.Amount, 2), i18n.FormatDateTime(lang, confirmation.ExpiresAt)))
+	return nil
+}
+
+// SendRefundRequested logs a push asking the merchant to approve or reject
+// a customer's refund request.
+func (s *Service) SendRefundRequested(ctx context.Context, req *models.RefundRequest) error {
+	lang := i18n.FromContext(ctx)
+	log.Printf("Push to merchant %d: %s", req.MerchantUserID, i18n.T(lang, "notify.refund.requested",
+		i18n.FormatNumber(lang, req.Amount, 2), req.Reason, req.ID))
+	return nil
+}
+
+// SendRefundRequestDecided logs a push telling the customer whether their
+// refund was paid or rejected.
+func (s *Service) SendRefundRequestDecided(ctx context.Context, req *models.RefundRequest) error {
+	lang := i18n.FromContext(ctx)
+	amount := i18n.FormatNumber(lang, req.Amount, 2)
+	message := i18n.T(lang, "notify.refund.refunded", req.MerchantName, amount)
+	if req.Status == models.RefundRequestRejected {
+		message = i18n.T(lang, "notify.refund.rejected", req.MerchantName, amount, req.DecisionNote)
+	}
+	log.Printf("Push to user %d: %s", req.CustomerID, message)
 	return nil
 }
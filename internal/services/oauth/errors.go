@@ -0,0 +1,60 @@
+package oauth
+
+import "errors"
+
+var (
+	// ErrClientNotFound is returned for an app that isn't registered, or
+	// isn't the caller's to manage
+	ErrClientNotFound = errors.New("app not found")
+	// ErrInvalidClient is returned when an app's registration is invalid
+	ErrInvalidClient = errors.New("app registration is invalid")
+	// ErrInvalidAuthorization is returned for an authorization request
+	// that can't be shown or redirected back: an unknown or disabled app,
+	// or a redirect URI the app didn't register
+	ErrInvalidAuthorization = errors.New("authorization request is invalid")
+	// ErrInvalidToken is returned for an access token that is unknown,
+	// expired or revoked
+	ErrInvalidToken = errors.New("access token is invalid or has expired")
+	// ErrInsufficientScope is returned when the token wasn't granted the
+	// scope an endpoint needs
+	ErrInsufficientScope = errors.New("access token lacks the required scope")
+	// ErrConnectionNotFound is returned when the user hasn't authorized
+	// the app
+	ErrConnectionNotFound = errors.New("app is not connected")
+	// ErrPaymentRequestNotFound is returned for a payment request that
+	// isn't the user's
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	// ErrPaymentRequestDecided is returned for a payment request that has
+	// already been approved, declined or has expired
+	ErrPaymentRequestDecided = errors.New("payment request is no longer pending")
+	// ErrInvalidPaymentRequest is returned for a payment request without
+	// a recipient or a positive amount
+	ErrInvalidPaymentRequest = errors.New("payment request is invalid")
+)
+
+// Error is an error from the token, introspection or revocation endpoints,
+// rendered in the form RFC 6749 section 5.2 gives
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	Status      int    `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+func invalidRequest(description string) *Error {
+	return &Error{Code: "invalid_request", Description: description, Status: 400}
+}
+
+func invalidClient() *Error {
+	return &Error{Code: "invalid_client", Description: "client authentication failed", Status: 401}
+}
+
+func invalidGrant(description string) *Error {
+	return &Error{Code: "invalid_grant", Description: description, Status: 400}
+}
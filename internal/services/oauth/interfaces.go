@@ -0,0 +1,60 @@
+package oauth
+
+import (
+	"context"
+
+	"orus/internal/models"
+)
+
+// Service lets users authorize third-party apps to read their balance and
+// transactions and to request payments, through the OAuth 2.0
+// authorization code flow
+type Service interface {
+	// RegisterClient registers an app owned by the user
+	RegisterClient(ctx context.Context, ownerID uint, req RegisterClientRequest) (*RegisteredClient, error)
+	ListClients(ctx context.Context, ownerID uint) ([]models.OAuthClient, error)
+	// DeleteClient removes the user's app. Its tokens stop working.
+	DeleteClient(ctx context.Context, ownerID uint, clientID string) error
+
+	// Consent checks an authorization request and returns what the
+	// consent screen shows
+	Consent(ctx context.Context, userID uint, req AuthorizeRequest) (*Consent, error)
+	// Authorize records the user's decision, returning the redirect back
+	// to the app with an authorization code, or with access_denied
+	Authorize(ctx context.Context, userID uint, decision ConsentDecision) (*Redirect, error)
+
+	// Token exchanges an authorization code or a refresh token
+	Token(ctx context.Context, req TokenRequest) (*TokenResponse, error)
+	// Introspect describes a token the calling app holds
+	Introspect(ctx context.Context, creds ClientCredentials, token string) (*Introspection, error)
+	// Revoke revokes an access or refresh token the calling app holds,
+	// along with its pair
+	Revoke(ctx context.Context, creds ClientCredentials, token string) error
+
+	// Authenticate returns the live token an access token stands for
+	Authenticate(ctx context.Context, accessToken string) (*models.OAuthToken, error)
+
+	// ListConnections returns the apps the user authorized
+	ListConnections(ctx context.Context, userID uint) ([]models.OAuthGrant, error)
+	// Disconnect withdraws the user's consent and revokes the app's tokens
+	Disconnect(ctx context.Context, userID uint, clientID string) error
+
+	// RequestPayment records an app's payment request, which waits for
+	// the user's approval
+	RequestPayment(ctx context.Context, token *models.OAuthToken, req PaymentRequest) (*models.OAuthPaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, userID uint, status string) ([]models.OAuthPaymentRequest, error)
+	// ApprovePaymentRequest sends the payment as a transfer from the
+	// user. ctx carries the user's role and payment PIN like any transfer.
+	ApprovePaymentRequest(ctx context.Context, userID, id uint) (*models.OAuthPaymentRequest, error)
+	DeclinePaymentRequest(ctx context.Context, userID, id uint) (*models.OAuthPaymentRequest, error)
+}
+
+// Transfers sends approved payment requests
+type Transfers interface {
+	Transfer(ctx context.Context, senderID, receiverID uint, amount float64, description string) (*models.Transaction, error)
+}
+
+// Notifier asks users to approve the payments apps request
+type Notifier interface {
+	SendOAuthPaymentRequest(ctx context.Context, userID uint, req *models.OAuthPaymentRequest) error
+}
@@ -0,0 +1,647 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+)
+
+type service struct {
+	repo      repositories.OAuthRepository
+	users     repositories.UserRepository
+	transfers Transfers
+	notifier  Notifier
+}
+
+// NewService creates the OAuth service
+func NewService(repo repositories.OAuthRepository, users repositories.UserRepository, transfers Transfers, notifier Notifier) Service {
+	return &service{repo: repo, users: users, transfers: transfers, notifier: notifier}
+}
+
+func (s *service) RegisterClient(ctx context.Context, ownerID uint, req RegisterClientRequest) (*RegisteredClient, error) {
+	for _, scope := range req.Scopes {
+		if _, ok := models.OAuthScopes[scope]; !ok {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidClient, scope)
+		}
+	}
+	for _, uri := range req.RedirectURIs {
+		if !validRedirect(uri) {
+			return nil, fmt.Errorf("%w: redirect URI %q must use https", ErrInvalidClient, uri)
+		}
+	}
+
+	id, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	secret = secretPrefix + secret
+
+	client := &models.OAuthClient{
+		ClientID:     id,
+		SecretHash:   hash(secret),
+		OwnerID:      ownerID,
+		Name:         strings.TrimSpace(req.Name),
+		RedirectURIs: req.RedirectURIs,
+		Scopes:       slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+		Status:       models.OAuthClientActive,
+	}
+	if err := s.repo.CreateClient(ctx, client); err != nil {
+		return nil, err
+	}
+	return &RegisteredClient{OAuthClient: client, ClientSecret: secret}, nil
+}
+
+// validRedirect allows https redirect URIs, and http ones to the loopback
+// address for apps in development
+func validRedirect(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.Fragment != "" {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1"
+	}
+	return false
+}
+
+func (s *service) ListClients(ctx context.Context, ownerID uint) ([]models.OAuthClient, error) {
+	return s.repo.ListClientsByOwner(ctx, ownerID)
+}
+
+func (s *service) DeleteClient(ctx context.Context, ownerID uint, clientID string) error {
+	if err := s.repo.DeleteClient(ctx, ownerID, clientID); err != nil {
+		if errors.Is(err, repositories.ErrOAuthClientNotFound) {
+			return ErrClientNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) Consent(ctx context.Context, userID uint, req AuthorizeRequest) (*Consent, error) {
+	client, scopes, err := s.checkAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	consent := &Consent{ClientID: client.ClientID, ClientName: client.Name}
+	for _, scope := range scopes {
+		consent.Scopes = append(consent.Scopes, Scope{Name: scope, Description: models.OAuthScopes[scope]})
+	}
+	grant, err := s.repo.GetGrant(ctx, userID, client.ClientID)
+	if err != nil && !errors.Is(err, repositories.ErrOAuthGrantNotFound) {
+		return nil, err
+	}
+	if grant != nil {
+		granted := strings.Fields(grant.Scope)
+		consent.AlreadyGranted = !slices.ContainsFunc(scopes, func(scope string) bool {
+			return !slices.Contains(granted, scope)
+		})
+	}
+	return consent, nil
+}
+
+func (s *service) Authorize(ctx context.Context, userID uint, decision ConsentDecision) (*Redirect, error) {
+	client, scopes, err := s.checkAuthorization(ctx, decision.AuthorizeRequest)
+	if err != nil {
+		// Requests the app got wrong go back to it. Requests that can't
+		// be trusted to redirect fail here.
+		var oauthErr *Error
+		if errors.As(err, &oauthErr) {
+			return redirect(decision.RedirectURI, url.Values{
+				"error":             {oauthErr.Code},
+				"error_description": {oauthErr.Description},
+			}, decision.State), nil
+		}
+		return nil, err
+	}
+	if !decision.Approve {
+		return redirect(decision.RedirectURI, url.Values{"error": {"access_denied"}}, decision.State), nil
+	}
+
+	scope := strings.Join(scopes, " ")
+	grant, err := s.repo.GetGrant(ctx, userID, client.ClientID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrOAuthGrantNotFound) {
+			return nil, err
+		}
+		grant = &models.OAuthGrant{UserID: userID, ClientID: client.ClientID}
+	}
+	grant.Scope = mergeScopes(grant.Scope, scope)
+	if err := s.repo.SaveGrant(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	code, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.CreateCode(ctx, &models.OAuthAuthorizationCode{
+		CodeHash:      hash(code),
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   decision.RedirectURI,
+		Scope:         scope,
+		CodeChallenge: decision.CodeChallenge,
+		ExpiresAt:     time.Now().Add(codeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redirect(decision.RedirectURI, url.Values{"code": {code}}, decision.State), nil
+}
+
+// checkAuthorization returns the app and scopes an authorization request
+// is for. An unknown app or unregistered redirect URI is
+// ErrInvalidAuthorization; anything else wrong is an *Error to send back
+// to the app.
+func (s *service) checkAuthorization(ctx context.Context, req AuthorizeRequest) (*models.OAuthClient, []string, error) {
+	client, err := s.repo.GetClient(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthClientNotFound) {
+			return nil, nil, ErrInvalidAuthorization
+		}
+		return nil, nil, err
+	}
+	if client.Status != models.OAuthClientActive || !client.AllowsRedirect(req.RedirectURI) {
+		return nil, nil, ErrInvalidAuthorization
+	}
+
+	if req.ResponseType != "code" {
+		return nil, nil, &Error{Code: "unsupported_response_type", Description: "only the code response type is supported", Status: 400}
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		return nil, nil, invalidRequest("code_challenge_method must be S256")
+	}
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		return nil, nil, &Error{Code: "invalid_scope", Description: "scope is required", Status: 400}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			return nil, nil, &Error{Code: "invalid_scope", Description: fmt.Sprintf("scope %q is not available to this app", scope), Status: 400}
+		}
+	}
+	slices.Sort(scopes)
+	return client, slices.Compact(scopes), nil
+}
+
+func (s *service) Token(ctx context.Context, req TokenRequest) (*TokenResponse, error) {
+	client, err := s.authenticateClient(ctx, ClientCredentials{ClientID: req.ClientID, ClientSecret: req.ClientSecret})
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case "authorization_code":
+		return s.exchangeCode(ctx, client, req)
+	case "refresh_token":
+		return s.refresh(ctx, client, req.RefreshToken)
+	default:
+		return nil, &Error{Code: "unsupported_grant_type", Status: 400}
+	}
+}
+
+func (s *service) exchangeCode(ctx context.Context, client *models.OAuthClient, req TokenRequest) (*TokenResponse, error) {
+	if req.Code == "" {
+		return nil, invalidRequest("code is required")
+	}
+	code, err := s.repo.GetCode(ctx, hash(req.Code))
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthCodeNotFound) {
+			return nil, invalidGrant("authorization code is invalid")
+		}
+		return nil, err
+	}
+	if code.ClientID != client.ClientID {
+		return nil, invalidGrant("authorization code is invalid")
+	}
+
+	now := time.Now()
+	if code.UsedAt != nil {
+		// A code used twice may have been intercepted, so the tokens
+		// issued for it can't be trusted either
+		if err := s.repo.RevokeGrantTokens(ctx, code.UserID, code.ClientID, now); err != nil {
+			log.Printf("failed to revoke tokens after reuse of oauth code %d: %v", code.ID, err)
+		}
+		return nil, invalidGrant("authorization code has already been used")
+	}
+	if now.After(code.ExpiresAt) {
+		return nil, invalidGrant("authorization code has expired")
+	}
+	if req.RedirectURI != code.RedirectURI {
+		return nil, invalidGrant("redirect_uri does not match the authorization request")
+	}
+	if code.CodeChallenge != "" {
+		sum := sha256.Sum256([]byte(req.CodeVerifier))
+		challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+		if subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+			return nil, invalidGrant("code_verifier does not match the code challenge")
+		}
+	}
+
+	used, err := s.repo.UseCode(ctx, code.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, invalidGrant("authorization code has already been used")
+	}
+	return s.issue(ctx, client.ClientID, code.UserID, code.Scope)
+}
+
+func (s *service) refresh(ctx context.Context, client *models.OAuthClient, refreshToken string) (*TokenResponse, error) {
+	if refreshToken == "" {
+		return nil, invalidRequest("refresh_token is required")
+	}
+	token, err := s.repo.GetTokenByRefresh(ctx, hash(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthTokenNotFound) {
+			return nil, invalidGrant("refresh token is invalid")
+		}
+		return nil, err
+	}
+	if token.ClientID != client.ClientID {
+		return nil, invalidGrant("refresh token is invalid")
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil {
+		// Refresh tokens rotate, so a revoked one coming back means it
+		// leaked. End the app's access until the user authorizes again.
+		if err := s.repo.RevokeGrantTokens(ctx, token.UserID, token.ClientID, now); err != nil {
+			log.Printf("failed to revoke tokens after reuse of oauth refresh token %d: %v", token.ID, err)
+		}
+		return nil, invalidGrant("refresh token has been revoked")
+	}
+	if now.After(token.RefreshExpiresAt) {
+		return nil, invalidGrant("refresh token has expired")
+	}
+
+	if err := s.repo.RevokeToken(ctx, token.ID, now); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, token.ClientID, token.UserID, token.Scope)
+}
+
+func (s *service) issue(ctx context.Context, clientID string, userID uint, scope string) (*TokenResponse, error) {
+	access, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	access, refresh = accessPrefix+access, refreshPrefix+refresh
+
+	now := time.Now()
+	err = s.repo.CreateToken(ctx, &models.OAuthToken{
+		ClientID:         clientID,
+		UserID:           userID,
+		Scope:            scope,
+		AccessHash:       hash(access),
+		RefreshHash:      hash(refresh),
+		AccessExpiresAt:  now.Add(accessTTL),
+		RefreshExpiresAt: now.Add(refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTTL.Seconds()),
+		RefreshToken: refresh,
+		Scope:        scope,
+	}, nil
+}
+
+func (s *service) Introspect(ctx context.Context, creds ClientCredentials, token string) (*Introspection, error) {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	stored, tokenType, err := s.lookup(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	// Apps only learn about their own tokens
+	if stored == nil || stored.ClientID != client.ClientID || stored.RevokedAt != nil {
+		return &Introspection{}, nil
+	}
+
+	expiresAt := stored.AccessExpiresAt
+	if tokenType == "refresh_token" {
+		expiresAt = stored.RefreshExpiresAt
+	}
+	if time.Now().After(expiresAt) {
+		return &Introspection{}, nil
+	}
+	return &Introspection{
+		Active:    true,
+		Scope:     stored.Scope,
+		ClientID:  stored.ClientID,
+		Subject:   strconv.FormatUint(uint64(stored.UserID), 10),
+		TokenType: tokenType,
+		ExpiresAt: expiresAt.Unix(),
+		IssuedAt:  stored.CreatedAt.Unix(),
+	}, nil
+}
+
+func (s *service) Revoke(ctx context.Context, creds ClientCredentials, token string) error {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return err
+	}
+	stored, _, err := s.lookup(ctx, token)
+	if err != nil {
+		return err
+	}
+	// Unknown tokens and other apps' tokens succeed quietly, per RFC 7009
+	if stored == nil || stored.ClientID != client.ClientID || stored.RevokedAt != nil {
+		return nil
+	}
+	return s.repo.RevokeToken(ctx, stored.ID, time.Now())
+}
+
+// lookup finds the token an access or refresh token string stands for,
+// returning nil for one that isn't known
+func (s *service) lookup(ctx context.Context, token string) (*models.OAuthToken, string, error) {
+	var (
+		stored    *models.OAuthToken
+		tokenType string
+		err       error
+	)
+	switch {
+	case strings.HasPrefix(token, accessPrefix):
+		stored, err = s.repo.GetTokenByAccess(ctx, hash(token))
+		tokenType = "access_token"
+	case strings.HasPrefix(token, refreshPrefix):
+		stored, err = s.repo.GetTokenByRefresh(ctx, hash(token))
+		tokenType = "refresh_token"
+	default:
+		return nil, "", nil
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthTokenNotFound) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	return stored, tokenType, nil
+}
+
+// authenticateClient checks an app's credentials
+func (s *service) authenticateClient(ctx context.Context, creds ClientCredentials) (*models.OAuthClient, error) {
+	if creds.ClientID == "" || creds.ClientSecret == "" {
+		return nil, invalidClient()
+	}
+	client, err := s.repo.GetClient(ctx, creds.ClientID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthClientNotFound) {
+			return nil, invalidClient()
+		}
+		return nil, err
+	}
+	if client.Status != models.OAuthClientActive ||
+		subtle.ConstantTimeCompare([]byte(hash(creds.ClientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, invalidClient()
+	}
+	return client, nil
+}
+
+func (s *service) Authenticate(ctx context.Context, accessToken string) (*models.OAuthToken, error) {
+	if !strings.HasPrefix(accessToken, accessPrefix) {
+		return nil, ErrInvalidToken
+	}
+	token, err := s.repo.GetTokenByAccess(ctx, hash(accessToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthTokenNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if token.RevokedAt != nil || time.Now().After(token.AccessExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	// A suspended user's apps lose access along with the user
+	user, err := s.users.GetByID(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, ErrInvalidToken
+	}
+	return token, nil
+}
+
+func (s *service) ListConnections(ctx context.Context, userID uint) ([]models.OAuthGrant, error) {
+	return s.repo.ListGrants(ctx, userID)
+}
+
+func (s *service) Disconnect(ctx context.Context, userID uint, clientID string) error {
+	if err := s.repo.DeleteGrant(ctx, userID, clientID); err != nil {
+		if errors.Is(err, repositories.ErrOAuthGrantNotFound) {
+			return ErrConnectionNotFound
+		}
+		return err
+	}
+	return s.repo.RevokeGrantTokens(ctx, userID, clientID, time.Now())
+}
+
+func (s *service) RequestPayment(ctx context.Context, token *models.OAuthToken, req PaymentRequest) (*models.OAuthPaymentRequest, error) {
+	if !token.HasScope(models.OAuthScopePaymentsInitiate) {
+		return nil, ErrInsufficientScope
+	}
+	if req.RecipientID == 0 || req.RecipientID == token.UserID || req.Amount <= 0 {
+		return nil, ErrInvalidPaymentRequest
+	}
+	client, err := s.repo.GetClient(ctx, token.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &models.OAuthPaymentRequest{
+		ClientID:    token.ClientID,
+		UserID:      token.UserID,
+		RecipientID: req.RecipientID,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Status:      models.OAuthPaymentPending,
+		ExpiresAt:   time.Now().Add(paymentRequestTTL),
+		ClientName:  client.Name,
+	}
+	if err := s.repo.CreatePaymentRequest(ctx, payment); err != nil {
+		return nil, err
+	}
+	if err := s.notifier.SendOAuthPaymentRequest(ctx, payment.UserID, payment); err != nil {
+		log.Printf("failed to notify user %d of oauth payment request %d: %v", payment.UserID, payment.ID, err)
+	}
+	return payment, nil
+}
+
+func (s *service) ListPaymentRequests(ctx context.Context, userID uint, status string) ([]models.OAuthPaymentRequest, error) {
+	if err := s.repo.ExpirePaymentRequests(ctx, userID, time.Now()); err != nil {
+		return nil, err
+	}
+	reqs, err := s.repo.ListPaymentRequests(ctx, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for i := range reqs {
+		name, ok := names[reqs[i].ClientID]
+		if !ok {
+			if client, err := s.repo.GetClient(ctx, reqs[i].ClientID); err == nil {
+				name = client.Name
+			}
+			names[reqs[i].ClientID] = name
+		}
+		reqs[i].ClientName = name
+	}
+	return reqs, nil
+}
+
+func (s *service) ApprovePaymentRequest(ctx context.Context, userID, id uint) (*models.OAuthPaymentRequest, error) {
+	payment, err := s.pendingPaymentRequest(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim the request first so approving it twice can't pay twice
+	now := time.Now()
+	claimed, err := s.repo.DecidePaymentRequest(ctx, payment.ID, models.OAuthPaymentApproved, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrPaymentRequestDecided
+	}
+
+	description := payment.Description
+	if description == "" {
+		if client, err := s.repo.GetClient(ctx, payment.ClientID); err == nil {
+			description = "Payment requested by " + client.Name
+		}
+	}
+	tx, err := s.transfers.Transfer(ctx, payment.UserID, payment.RecipientID, payment.Amount, description)
+	if err != nil {
+		// Leave it for the user to try again, e.g. with their PIN
+		if reopenErr := s.repo.ReopenPaymentRequest(ctx, payment.ID); reopenErr != nil {
+			log.Printf("failed to reopen oauth payment request %d: %v", payment.ID, reopenErr)
+		}
+		return nil, err
+	}
+	if err := s.repo.SetPaymentRequestTransaction(ctx, payment.ID, tx.ID); err != nil {
+		log.Printf("failed to record transaction %d for oauth payment request %d: %v", tx.ID, payment.ID, err)
+	}
+
+	payment.Status = models.OAuthPaymentApproved
+	payment.DecidedAt = &now
+	payment.TransactionID = &tx.ID
+	return payment, nil
+}
+
+func (s *service) DeclinePaymentRequest(ctx context.Context, userID, id uint) (*models.OAuthPaymentRequest, error) {
+	payment, err := s.pendingPaymentRequest(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	declined, err := s.repo.DecidePaymentRequest(ctx, payment.ID, models.OAuthPaymentDeclined, now)
+	if err != nil {
+		return nil, err
+	}
+	if !declined {
+		return nil, ErrPaymentRequestDecided
+	}
+	payment.Status = models.OAuthPaymentDeclined
+	payment.DecidedAt = &now
+	return payment, nil
+}
+
+// pendingPaymentRequest returns the user's payment request if it still
+// awaits a decision, expiring it when it's past its deadline
+func (s *service) pendingPaymentRequest(ctx context.Context, userID, id uint) (*models.OAuthPaymentRequest, error) {
+	payment, err := s.repo.GetPaymentRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOAuthPaymentRequestNotFound) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	if payment.UserID != userID {
+		return nil, ErrPaymentRequestNotFound
+	}
+	if payment.Status != models.OAuthPaymentPending {
+		return nil, ErrPaymentRequestDecided
+	}
+	if now := time.Now(); now.After(payment.ExpiresAt) {
+		if _, err := s.repo.DecidePaymentRequest(ctx, payment.ID, models.OAuthPaymentExpired, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrPaymentRequestDecided
+	}
+	return payment, nil
+}
+
+// redirect adds params and the app's state to the redirect URI
+func redirect(uri string, params url.Values, state string) *Redirect {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return &Redirect{RedirectURL: uri}
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	u.RawQuery = query.Encode()
+	return &Redirect{RedirectURL: u.String()}
+}
+
+// mergeScopes adds the scopes in b to those in a
+func mergeScopes(a, b string) string {
+	scopes := append(strings.Fields(a), strings.Fields(b)...)
+	slices.Sort(scopes)
+	return strings.Join(slices.Compact(scopes), " ")
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
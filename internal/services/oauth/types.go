@@ -0,0 +1,119 @@
+package oauth
+
+import (
+	"time"
+
+	"orus/internal/models"
+)
+
+const (
+	codeTTL    = 10 * time.Minute
+	accessTTL  = time.Hour
+	refreshTTL = 30 * 24 * time.Hour
+	// paymentRequestTTL is how long the user has to approve an app's
+	// payment request
+	paymentRequestTTL = 15 * time.Minute
+
+	accessPrefix  = "orus_at_"
+	refreshPrefix = "orus_rt_"
+	secretPrefix  = "orus_cs_"
+)
+
+// RegisterClientRequest registers an app
+type RegisterClientRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,dive,url"`
+	Scopes       []string `json:"scopes" validate:"required,min=1"`
+}
+
+// RegisteredClient is a newly registered app with its secret, which is not
+// shown again
+type RegisteredClient struct {
+	*models.OAuthClient
+	ClientSecret string `json:"client_secret"`
+}
+
+// AuthorizeRequest is the app's request for a user's consent, from the
+// query string of the link the app sent the user to
+type AuthorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type"`
+	ClientID            string `json:"client_id" query:"client_id"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri"`
+	Scope               string `json:"scope" query:"scope"`
+	State               string `json:"state" query:"state"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method"`
+}
+
+// ConsentDecision is the user's answer on the consent screen
+type ConsentDecision struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// Scope is a scope shown on the consent screen
+type Scope struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Consent is what the consent screen shows the user
+type Consent struct {
+	ClientID   string  `json:"client_id"`
+	ClientName string  `json:"client_name"`
+	Scopes     []Scope `json:"scopes"`
+	// AlreadyGranted is set when the user authorized the app for these
+	// scopes before
+	AlreadyGranted bool `json:"already_granted"`
+}
+
+// Redirect is where to send the user after the consent screen
+type Redirect struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+// TokenRequest is a request to the token endpoint, form encoded
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// TokenResponse is the token endpoint's answer, per RFC 6749 section 5.1
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// Introspection is the introspection endpoint's answer, per RFC 7662.
+// Only Active is set for a token that isn't.
+type Introspection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+// ClientCredentials authenticate an app at the token, introspection and
+// revocation endpoints
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// PaymentRequest is an app's request for a payment from the user
+type PaymentRequest struct {
+	RecipientID uint    `json:"recipient_id" validate:"required"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	Description string  `json:"description" validate:"max=255"`
+}
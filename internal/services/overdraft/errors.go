@@ -0,0 +1,8 @@
+package overdraft
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidLimit = errors.New("overdraft limit must be between zero and the configured maximum")
+)
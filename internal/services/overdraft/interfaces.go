@@ -0,0 +1,27 @@
+package overdraft
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// PotService is the part of the pot service recovery sweeps rely on
+type PotService interface {
+	CoverShortfall(ctx context.Context, userID uint, amount float64) (float64, error)
+}
+
+// Service applies wallet overdraft policy. Wallets may not go below zero
+// unless an admin gives them an overdraft limit; the wallet repository
+// enforces it on every update.
+type Service interface {
+	// SetLimit lets the user's wallet go as far as limit below zero; 0
+	// disallows overdrafts again
+	SetLimit(ctx context.Context, actorID, userID uint, limit float64) (*models.Wallet, error)
+
+	// Report lists the wallets with a negative balance, most overdrawn first
+	Report(ctx context.Context, limit, offset int) (*Report, error)
+
+	// Recover covers negative balances from their owners' savings pots,
+	// returning how many wallets it brought back to zero
+	Recover(ctx context.Context) (int, error)
+}
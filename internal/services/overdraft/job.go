@@ -0,0 +1,24 @@
+package overdraft
+
+import (
+	"context"
+	"log"
+)
+
+// Job covers negative wallet balances from savings pots
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the overdraft service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "overdraft-recovery" }
+
+func (j *Job) Run(ctx context.Context) error {
+	recovered, err := j.service.Recover(ctx)
+	if recovered > 0 {
+		log.Printf("Recovered %d negative wallet balances from pots", recovered)
+	}
+	return err
+}
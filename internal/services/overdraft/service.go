@@ -0,0 +1,129 @@
+package overdraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+)
+
+// sweepBatch bounds how many negative wallets one recovery run looks at
+const sweepBatch = 100
+
+type service struct {
+	wallets  repositories.WalletRepository
+	activity repositories.UserActivityRepository
+	pots     PotService
+	cache    *cache.CacheService
+	maxLimit float64
+}
+
+// NewService creates a new overdraft service. Admins may give a wallet a
+// limit of at most maxLimit.
+func NewService(
+	wallets repositories.WalletRepository,
+	activity repositories.UserActivityRepository,
+	pots PotService,
+	cache *cache.CacheService,
+	maxLimit float64,
+) Service {
+	return &service{
+		wallets:  wallets,
+		activity: activity,
+		pots:     pots,
+		cache:    cache,
+		maxLimit: maxLimit,
+	}
+}
+
+func (s *service) SetLimit(ctx context.Context, actorID, userID uint, limit float64) (*models.Wallet, error) {
+	limit = math.Round(limit*100) / 100
+	if limit < 0 || limit > s.maxLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	wallets := s.wallets.WithContext(ctx)
+	wallet, err := wallets.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := wallets.SetOverdraftLimit(wallet.ID, limit); err != nil {
+		return nil, err
+	}
+	wallet.OverdraftLimit = limit
+
+	activity := &models.UserActivity{
+		UserID:  userID,
+		ActorID: &actorID,
+		Action:  models.UserActivityOverdraftSet,
+		Details: fmt.Sprintf("overdraft limit set to %.2f", limit),
+	}
+	if err := s.activity.Record(activity); err != nil {
+		log.Printf("Failed to record overdraft activity for user %d: %v", userID, err)
+	}
+	if err := s.cache.InvalidateWallet(context.WithoutCancel(ctx), userID); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+	return wallet, nil
+}
+
+func (s *service) Report(ctx context.Context, limit, offset int) (*Report, error) {
+	wallets := s.wallets.WithContext(ctx)
+	negative, count, err := wallets.GetNegative(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	deficit, err := wallets.GetNegativeTotal()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Wallets: make([]NegativeWallet, 0, len(negative)),
+		Count:   count,
+		Deficit: math.Round(deficit*100) / 100,
+	}
+	for i := range negative {
+		w := &negative[i]
+		report.Wallets = append(report.Wallets, NegativeWallet{
+			WalletID:       w.ID,
+			UserID:         w.UserID,
+			Currency:       w.Currency,
+			Balance:        w.Balance,
+			OverdraftLimit: w.OverdraftLimit,
+			BeyondLimit:    w.Overdrawn(),
+		})
+	}
+	return report, nil
+}
+
+// Recover moves money from each negative wallet's owner's pots to bring it
+// back to zero. Wallets still short are left for top-ups to repay and
+// stay on the report.
+func (s *service) Recover(ctx context.Context) (int, error) {
+	negative, _, err := s.wallets.WithContext(ctx).GetNegative(sweepBatch, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for i := range negative {
+		if err := ctx.Err(); err != nil {
+			return recovered, err
+		}
+		w := &negative[i]
+
+		shortfall := math.Round(-w.Balance*100) / 100
+		covered, err := s.pots.CoverShortfall(ctx, w.UserID, shortfall)
+		if err != nil {
+			log.Printf("Failed to cover negative balance of wallet %d from pots: %v", w.ID, err)
+		}
+		if covered >= shortfall {
+			recovered++
+		}
+	}
+	return recovered, nil
+}
@@ -0,0 +1,20 @@
+package overdraft
+
+// NegativeWallet is a wallet below zero in the overdraft report
+type NegativeWallet struct {
+	WalletID       uint    `json:"wallet_id"`
+	UserID         uint    `json:"user_id"`
+	Currency       string  `json:"currency"`
+	Balance        float64 `json:"balance"`
+	OverdraftLimit float64 `json:"overdraft_limit"`
+	// BeyondLimit marks balances past the wallet's limit, left by debits
+	// the user couldn't refuse such as returned deposits
+	BeyondLimit bool `json:"beyond_limit"`
+}
+
+// Report is a page of negative wallets with totals across all of them
+type Report struct {
+	Wallets []NegativeWallet `json:"wallets"`
+	Count   int64            `json:"count"`
+	Deficit float64          `json:"total_deficit"` // How far below zero they are altogether
+}
@@ -0,0 +1,112 @@
+// Package password hashes and verifies user passwords. New hashes are
+// argon2id in the PHC string format, keyed by a pepper held outside the
+// database; bcrypt hashes from before argon2id still verify, and Verify
+// reports when a hash should be replaced so logins upgrade it.
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	saltLength = 16
+	keyLength  = 32
+)
+
+// Params are the argon2id cost parameters; Memory is in KiB
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Hasher hashes passwords with argon2id under its params and pepper
+type Hasher struct {
+	params Params
+	pepper []byte
+}
+
+// NewHasher creates a hasher. Every argon2id hash depends on pepper, so
+// it must stay the same for as long as the hashes are kept.
+func NewHasher(params Params, pepper string) *Hasher {
+	return &Hasher{params: params, pepper: []byte(pepper)}
+}
+
+// Hash returns the argon2id hash of password
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := h.derive(password, salt, h.params)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify reports whether password matches hash, and whether hash should
+// be replaced by a fresh one: it is bcrypt, or argon2id with parameters
+// other than the hasher's.
+func (h *Hasher) Verify(hash, password string) (ok, rehash bool) {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil, true
+	}
+
+	params, salt, key, err := decode(hash)
+	if err != nil {
+		return false, false
+	}
+	if subtle.ConstantTimeCompare(h.derive(password, salt, params), key) != 1 {
+		return false, false
+	}
+	return true, params != h.params
+}
+
+// derive runs argon2id over the password keyed by the pepper, so the
+// hash can't be brute forced without it
+func (h *Hasher) derive(password string, salt []byte, params Params) []byte {
+	input := []byte(password)
+	if len(h.pepper) > 0 {
+		mac := hmac.New(sha256.New, h.pepper)
+		mac.Write(input)
+		input = mac.Sum(nil)
+	}
+	return argon2.IDKey(input, salt, params.Iterations, params.Memory, params.Parallelism, keyLength)
+}
+
+// decode parses a $argon2id$v=19$m=..,t=..,p=..$salt$key hash
+func decode(hash string) (Params, []byte, []byte, error) {
+	var params Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("malformed argon2id parameters: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	return params, salt, key, nil
+}
@@ -33,3 +33,20 @@ type TransactionService interface {
 type QRService interface {
 	ValidateQRCode(ctx context.Context, code string, amount float64) (uint, error)
 }
+
+// BeneficiaryService guards first P2P payments to new recipients
+type BeneficiaryService interface {
+	CheckBeneficiary(ctx context.Context, senderID, receiverID uint) error
+	RecordPayment(ctx context.Context, senderID, receiverID uint)
+}
+
+// DeviceConfirmation holds large transfers until the sender approves them
+// on a trusted device
+type DeviceConfirmation interface {
+	Hold(ctx context.Context, tx *models.Transaction) (bool, error)
+}
+
+// PINService checks the sender's payment PIN on large transfers
+type PINService interface {
+	RequireForTransfer(ctx context.Context, userID uint, amount float64) error
+}
@@ -10,21 +10,33 @@ import (
 )
 
 type service struct {
+	merchants          repositories.MerchantRepository
 	walletService      WalletService
 	transactionService TransactionService
 	qrService          QRService
+	beneficiaries      BeneficiaryService
+	confirmations      DeviceConfirmation
+	pins               PINService
 }
 
 // NewService creates a new payment service
 func NewService(
+	merchants repositories.MerchantRepository,
 	walletSvc WalletService,
 	txSvc TransactionService,
 	qrSvc QRService,
+	beneficiaries BeneficiaryService,
+	confirmations DeviceConfirmation,
+	pins PINService,
 ) Service {
 	return &service{
+		merchants:          merchants,
 		walletService:      walletSvc,
 		transactionService: txSvc,
 		qrService:          qrSvc,
+		beneficiaries:      beneficiaries,
+		confirmations:      confirmations,
+		pins:               pins,
 	}
 }
 
@@ -46,6 +58,13 @@ func (s *service) SendMoney(
 		return nil, errors.New("amount must be greater than zero")
 	}
 
+	if err := s.beneficiaries.CheckBeneficiary(ctx, senderID, receiverID); err != nil {
+		return nil, err
+	}
+	if err := s.pins.RequireForTransfer(ctx, senderID, amount); err != nil {
+		return nil, err
+	}
+
 	// Create transaction with unique ID
 	tx := &models.Transaction{
 		Type:          "transfer",
@@ -57,6 +76,16 @@ func (s *service) SendMoney(
 		TransactionID: fmt.Sprintf("TRF-%d-%d-%d", senderID, receiverID, time.Now().UnixNano()),
 	}
 
+	// Large transfers wait for the sender to approve them on a trusted
+	// device, and are returned awaiting confirmation
+	held, err := s.confirmations.Hold(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		return tx, nil
+	}
+
 	// Process the transaction
 	fmt.Printf("Processing transaction - Type: transfer, From: %d, To: %d, Amount: %.2f\n",
 		senderID, receiverID, amount)
@@ -67,6 +96,7 @@ func (s *service) SendMoney(
 	}
 
 	fmt.Printf("Transaction successful - ID: %s\n", transaction.TransactionID)
+	s.beneficiaries.RecordPayment(ctx, senderID, receiverID)
 	return transaction, nil
 }
 
@@ -113,7 +143,7 @@ func (s *service) ProcessMerchantPayment(
 	}
 
 	// Get merchant details
-	merchant, err := repositories.GetMerchantByUserID(merchantID)
+	merchant, err := s.merchants.GetByUserID(merchantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merchant details: %w", err)
 	}
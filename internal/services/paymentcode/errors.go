@@ -0,0 +1,15 @@
+package paymentcode
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidAmount      = errors.New("amount must be greater than zero")
+	ErrInvalidDigits      = errors.New("payment codes are 6 to 8 digits long")
+	ErrInvalidExpiry      = errors.New("payment codes expire after 1 to 30 minutes")
+	ErrInvalidCode        = errors.New("payment code is invalid, expired or already used")
+	ErrAmountExceedsCode  = errors.New("charge exceeds the amount the payment code was issued for")
+	ErrSelfRedemption     = errors.New("merchants cannot redeem their own payment codes")
+	ErrTooManyAttempts    = errors.New("too many invalid payment codes entered, try again later")
+	ErrCodeSpaceExhausted = errors.New("could not issue a unique payment code, try again")
+)
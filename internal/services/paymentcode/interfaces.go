@@ -0,0 +1,27 @@
+package paymentcode
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// BalanceService checks the customer can cover a code when it is issued
+type BalanceService interface {
+	ValidateBalance(ctx context.Context, userID uint, amount float64) error
+}
+
+// Service issues numeric one-time payment codes and redeems them for
+// merchants. A code authorizes one charge of up to its amount.
+type Service interface {
+	// Generate issues a code to the user. The code itself is only ever
+	// returned here; it is stored hashed.
+	Generate(ctx context.Context, userID uint, req GenerateRequest) (*IssuedCode, error)
+
+	// Redeem claims a code for a charge by the merchant before any money
+	// moves. The caller charges the code's owner, then confirms or releases
+	// the redemption.
+	Redeem(ctx context.Context, code string, merchantUserID uint, amount float64) (*models.PaymentCode, error)
+	Confirm(ctx context.Context, code *models.PaymentCode, transactionID uint) error
+	// Release makes the code usable again once its charge has failed
+	Release(ctx context.Context, code *models.PaymentCode) error
+}
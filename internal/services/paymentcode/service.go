@@ -0,0 +1,228 @@
+package paymentcode
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"time"
+)
+
+const (
+	// MinDigits and MaxDigits bound a code's length
+	MinDigits = 6
+	MaxDigits = 8
+	// DefaultTTL is how long a code lasts unless the customer asks otherwise
+	DefaultTTL = 10 * time.Minute
+	// MinTTL and MaxTTL bound a code's lifetime
+	MinTTL = time.Minute
+	MaxTTL = 30 * time.Minute
+
+	// MaxMisses is how many unknown codes a merchant may enter per
+	// MissWindow before redemptions are refused, so codes can't be guessed
+	MaxMisses  = 5
+	MissWindow = 15 * time.Minute
+
+	// issueAttempts bounds the retries for a code clashing with one still
+	// outstanding
+	issueAttempts = 5
+)
+
+type service struct {
+	repo     repositories.PaymentCodeRepository
+	balances BalanceService
+	cache    *cache.CacheService
+	secret   []byte
+}
+
+// NewService creates a new payment code service. Codes are hashed with
+// secret, which must stay the same for outstanding codes to redeem.
+func NewService(repo repositories.PaymentCodeRepository, balances BalanceService, cache *cache.CacheService, secret string) Service {
+	return &service{
+		repo:     repo,
+		balances: balances,
+		cache:    cache,
+		secret:   []byte(secret),
+	}
+}
+
+// IsCode reports whether s has the shape of a one-time payment code rather
+// than a QR payment code
+func IsCode(s string) bool {
+	if len(s) < MinDigits || len(s) > MaxDigits {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *service) Generate(ctx context.Context, userID uint, req GenerateRequest) (*IssuedCode, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	digits := req.Digits
+	if digits == 0 {
+		digits = MinDigits
+	}
+	if digits < MinDigits || digits > MaxDigits {
+		return nil, ErrInvalidDigits
+	}
+	ttl := DefaultTTL
+	if req.ExpiresIn != 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if ttl < MinTTL || ttl > MaxTTL {
+		return nil, ErrInvalidExpiry
+	}
+
+	amount := math.Round(req.Amount*100) / 100
+	if err := s.balances.ValidateBalance(ctx, userID, amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for attempt := 0; attempt < issueAttempts; attempt++ {
+		code, err := newCode(digits)
+		if err != nil {
+			return nil, err
+		}
+		codeHash := s.hash(code)
+		// Redemption finds a code by its hash alone, so it must be unique
+		// among the codes that can still be redeemed
+		taken, err := s.repo.ActiveExists(ctx, codeHash, now)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			continue
+		}
+
+		paymentCode := &models.PaymentCode{
+			UserID:    userID,
+			CodeHash:  codeHash,
+			Amount:    amount,
+			Status:    models.PaymentCodeStatusActive,
+			ExpiresAt: now.Add(ttl),
+		}
+		if err := s.repo.Create(ctx, paymentCode); err != nil {
+			return nil, err
+		}
+		return &IssuedCode{PaymentCode: paymentCode, Code: code}, nil
+	}
+	return nil, ErrCodeSpaceExhausted
+}
+
+func (s *service) Redeem(ctx context.Context, code string, merchantUserID uint, amount float64) (*models.PaymentCode, error) {
+	if !IsCode(code) {
+		return nil, ErrInvalidCode
+	}
+	// Every attempt is counted as a miss before the lookup, so guesses made
+	// in parallel can't all pass the check before any is counted; the
+	// attempts that find their code are taken back off
+	counted, allowed := s.countAttempt(ctx, merchantUserID)
+	if !allowed {
+		return nil, ErrTooManyAttempts
+	}
+
+	paymentCode, err := s.repo.Redeem(ctx, s.hash(code), merchantUserID, time.Now())
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentCodeNotFound) {
+			return nil, ErrInvalidCode
+		}
+		s.uncountAttempt(ctx, merchantUserID, counted)
+		return nil, err
+	}
+	s.uncountAttempt(ctx, merchantUserID, counted)
+
+	// The code is the customer's to spend once; give it back untouched
+	// when this charge can't take it
+	var rejected error
+	switch {
+	case paymentCode.UserID == merchantUserID:
+		rejected = ErrSelfRedemption
+	case amount > paymentCode.Amount:
+		rejected = ErrAmountExceedsCode
+	}
+	if rejected != nil {
+		if err := s.Release(ctx, paymentCode); err != nil {
+			log.Printf("Failed to reopen payment code %d: %v", paymentCode.ID, err)
+		}
+		return nil, rejected
+	}
+	return paymentCode, nil
+}
+
+func (s *service) Confirm(ctx context.Context, code *models.PaymentCode, transactionID uint) error {
+	if err := s.repo.LinkTransaction(ctx, code.ID, transactionID); err != nil {
+		return err
+	}
+	code.TransactionID = &transactionID
+	return nil
+}
+
+func (s *service) Release(ctx context.Context, code *models.PaymentCode) error {
+	if err := s.repo.Reopen(ctx, code.ID); err != nil {
+		return err
+	}
+	code.Status = models.PaymentCodeStatusActive
+	code.MerchantUserID = nil
+	code.RedeemedAt = nil
+	return nil
+}
+
+// countAttempt counts a redemption attempt against the merchant's misses,
+// reporting whether it was counted and whether it is within MaxMisses.
+// Redis being down lets redemptions through rather than stopping every
+// till.
+func (s *service) countAttempt(ctx context.Context, merchantUserID uint) (counted, allowed bool) {
+	misses, _, err := s.cache.Hit(ctx, missKey(merchantUserID), MissWindow)
+	if err != nil {
+		log.Printf("Failed to count payment code attempt for merchant %d: %v", merchantUserID, err)
+		return false, true
+	}
+	return true, misses <= MaxMisses
+}
+
+// uncountAttempt takes back an attempt that wasn't a miss
+func (s *service) uncountAttempt(ctx context.Context, merchantUserID uint, counted bool) {
+	if !counted {
+		return
+	}
+	if err := s.cache.Unhit(ctx, missKey(merchantUserID)); err != nil {
+		log.Printf("Failed to uncount payment code attempt for merchant %d: %v", merchantUserID, err)
+	}
+}
+
+func missKey(merchantUserID uint) string {
+	return fmt.Sprintf("payment-code:misses:%d", merchantUserID)
+}
+
+func (s *service) hash(code string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newCode returns a uniformly random code of the given number of digits,
+// leading zeros included
+func newCode(digits int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate payment code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
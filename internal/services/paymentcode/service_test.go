@@ -0,0 +1,99 @@
+package paymentcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeCodes holds one redeemable code, counting every lookup
+type fakeCodes struct {
+	repositories.PaymentCodeRepository
+	hash    string
+	lookups atomic.Int64
+}
+
+func (f *fakeCodes) Redeem(ctx context.Context, codeHash string, merchantUserID uint, now time.Time) (*models.PaymentCode, error) {
+	f.lookups.Add(1)
+	// Long enough for the guesses to overlap
+	time.Sleep(10 * time.Millisecond)
+	if codeHash != f.hash {
+		return nil, repositories.ErrPaymentCodeNotFound
+	}
+	return &models.PaymentCode{ID: 1, UserID: 2, Amount: 50, Status: models.PaymentCodeStatusRedeemed}, nil
+}
+
+// testCache returns a cache in a namespace of its own. The test is skipped
+// unless TEST_REDIS_URL points at a Redis it may write to.
+func testCache(t *testing.T) *cache.CacheService {
+	t.Helper()
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("invalid TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+	return cache.NewCacheService(client, cache.Options{Namespace: fmt.Sprintf("test_%d", time.Now().UnixNano())})
+}
+
+func TestRedeemLimitsParallelGuesses(t *testing.T) {
+	codes := &fakeCodes{}
+	svc := NewService(codes, nil, testCache(t), "secret")
+
+	const guesses = 20
+	var wg sync.WaitGroup
+	var refused atomic.Int64
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := svc.Redeem(context.Background(), fmt.Sprintf("%06d", i), 7, 10)
+			switch {
+			case errors.Is(err, ErrTooManyAttempts):
+				refused.Add(1)
+			case !errors.Is(err, ErrInvalidCode):
+				t.Errorf("Redeem() error = %v, want %v or %v", err, ErrInvalidCode, ErrTooManyAttempts)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := codes.lookups.Load(); got != MaxMisses {
+		t.Errorf("%d guesses looked up, want %d", got, MaxMisses)
+	}
+	if got := refused.Load(); got != guesses-MaxMisses {
+		t.Errorf("%d guesses refused, want %d", got, guesses-MaxMisses)
+	}
+}
+
+func TestRedeemDoesNotCountFoundCodes(t *testing.T) {
+	s := NewService(nil, nil, testCache(t), "secret").(*service)
+	codes := &fakeCodes{hash: s.hash("123456")}
+	s.repo = codes
+
+	// A till redeeming more codes than MaxMisses in the window isn't
+	// locked out, and can still get one wrong
+	for i := 0; i < MaxMisses+2; i++ {
+		if _, err := s.Redeem(context.Background(), "123456", 7, 10); err != nil {
+			t.Fatalf("Redeem() #%d error = %v", i+1, err)
+		}
+	}
+	if _, err := s.Redeem(context.Background(), "654321", 7, 10); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Redeem(wrong code) error = %v, want %v", err, ErrInvalidCode)
+	}
+}
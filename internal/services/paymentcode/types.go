@@ -0,0 +1,19 @@
+package paymentcode
+
+import "orus/internal/models"
+
+// GenerateRequest is the body for issuing a payment code
+type GenerateRequest struct {
+	Amount float64 `json:"amount" validate:"gt=0"`
+	// Digits is the code's length, 6 by default
+	Digits int `json:"digits" validate:"omitempty,min=6,max=8"`
+	// ExpiresIn is the code's lifetime in seconds, 10 minutes by default
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=60,max=1800"`
+}
+
+// IssuedCode is a newly generated payment code together with the code the
+// customer gives the merchant
+type IssuedCode struct {
+	*models.PaymentCode
+	Code string `json:"code"`
+}
@@ -0,0 +1,14 @@
+package paymentintent
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidAmount = errors.New("amount must be greater than zero")
+	ErrInvalidExpiry = errors.New("payment intents expire after 1 minute to 7 days")
+	ErrInvalidIntent = errors.New("payment intent link is invalid")
+	ErrIntentExpired = errors.New("payment intent has expired")
+	ErrIntentPaid    = errors.New("payment intent has already been paid")
+	ErrSelfPayment   = errors.New("you cannot pay your own payment intent")
+	ErrRecipientGone = errors.New("payment intent recipient no longer exists")
+)
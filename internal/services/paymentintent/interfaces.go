@@ -0,0 +1,31 @@
+package paymentintent
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// TransactionService moves the money when an intent is paid
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
+
+// BeneficiaryService guards first payments to new recipients. A payment
+// request is as easy to fake as any other, so intents from other users go
+// through the same checks as a P2P transfer.
+type BeneficiaryService interface {
+	CheckBeneficiary(ctx context.Context, senderID, receiverID uint) error
+	RecordPayment(ctx context.Context, senderID, receiverID uint)
+}
+
+// Service issues signed payment intents and pays them. An intent's token
+// carries its recipient, amount and expiry, so the app can pre-fill the
+// payment before asking the server; the server checks the signature and
+// expiry again when the payment is confirmed.
+type Service interface {
+	Create(ctx context.Context, recipientID uint, req CreateRequest) (*IssuedIntent, error)
+	// Preview returns the intent behind a token so the payer can review it
+	Preview(ctx context.Context, token string) (*IntentView, error)
+	// Confirm pays the intent behind a token from the payer's wallet
+	Confirm(ctx context.Context, payerID uint, token string) (*models.Transaction, error)
+}
@@ -0,0 +1,226 @@
+package paymentintent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultTTL is how long an intent lasts unless its creator asks otherwise
+	DefaultTTL = 15 * time.Minute
+	// MinTTL and MaxTTL bound an intent's lifetime; printed NFC tags and
+	// links in messages need longer than a till
+	MinTTL = time.Minute
+	MaxTTL = 7 * 24 * time.Hour
+
+	// AppScheme is the URI scheme the mobile app registers
+	AppScheme = "orus"
+)
+
+type service struct {
+	repo          repositories.PaymentIntentRepository
+	users         repositories.UserRepository
+	merchants     repositories.MerchantRepository
+	transactions  TransactionService
+	beneficiaries BeneficiaryService
+	secret        []byte
+	baseURL       string
+}
+
+// NewService creates a new payment intent service. Tokens are signed with
+// secret, and links point at baseURL, the public origin the app claims
+// universal links for.
+func NewService(
+	repo repositories.PaymentIntentRepository,
+	users repositories.UserRepository,
+	merchants repositories.MerchantRepository,
+	transactions TransactionService,
+	beneficiaries BeneficiaryService,
+	secret string,
+	baseURL string,
+) Service {
+	return &service{
+		repo:          repo,
+		users:         users,
+		merchants:     merchants,
+		transactions:  transactions,
+		beneficiaries: beneficiaries,
+		secret:        []byte(secret),
+		baseURL:       baseURL,
+	}
+}
+
+func (s *service) Create(ctx context.Context, recipientID uint, req CreateRequest) (*IssuedIntent, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	ttl := DefaultTTL
+	if req.ExpiresIn != 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if ttl < MinTTL || ttl > MaxTTL {
+		return nil, ErrInvalidExpiry
+	}
+
+	intent := &models.PaymentIntent{
+		RecipientID: recipientID,
+		Amount:      math.Round(req.Amount*100) / 100,
+		Currency:    "USD",
+		Description: req.Description,
+		Reference:   req.Reference,
+		Status:      models.PaymentIntentStatusOpen,
+		// Whole seconds, as the token carries them
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	if err := s.repo.Create(ctx, intent); err != nil {
+		return nil, err
+	}
+
+	token, err := s.sign(intent)
+	if err != nil {
+		return nil, err
+	}
+	return &IssuedIntent{
+		PaymentIntent: intent,
+		Token:         token,
+		Link:          fmt.Sprintf("%s/api/pay/intents/%s", s.baseURL, token),
+		AppURI:        fmt.Sprintf("%s://pay?intent=%s", AppScheme, url.QueryEscape(token)),
+	}, nil
+}
+
+func (s *service) Preview(ctx context.Context, token string) (*IntentView, error) {
+	intent, err := s.intent(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &IntentView{PaymentIntent: intent}
+	merchant, err := s.merchants.GetByUserID(intent.RecipientID)
+	switch {
+	case err == nil:
+		view.RecipientName = merchant.BusinessName
+		view.IsMerchant = true
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user, err := s.users.GetByID(intent.RecipientID)
+		if err != nil {
+			return nil, ErrRecipientGone
+		}
+		view.RecipientName = user.Name
+	default:
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *service) Confirm(ctx context.Context, payerID uint, token string) (*models.Transaction, error) {
+	intent, err := s.intent(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != models.PaymentIntentStatusOpen {
+		return nil, ErrIntentPaid
+	}
+	if intent.RecipientID == payerID {
+		return nil, ErrSelfPayment
+	}
+
+	merchant, err := s.merchants.GetByUserID(intent.RecipientID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if merchant == nil {
+		if err := s.beneficiaries.CheckBeneficiary(ctx, payerID, intent.RecipientID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Claim the intent before moving money so it can't be paid twice
+	now := time.Now()
+	ok, err := s.repo.MarkPaid(ctx, intent, payerID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrIntentPaid
+	}
+
+	tx, err := s.transactions.ProcessTransaction(ctx, s.transaction(intent, payerID, merchant, now))
+	if err != nil {
+		if reopenErr := s.repo.Reopen(ctx, intent.ID); reopenErr != nil {
+			log.Printf("Failed to reopen payment intent %d: %v", intent.ID, reopenErr)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.LinkTransaction(ctx, intent.ID, tx.ID); err != nil {
+		log.Printf("Failed to link payment intent %d to transaction %d: %v", intent.ID, tx.ID, err)
+	}
+	if merchant == nil {
+		s.beneficiaries.RecordPayment(ctx, payerID, intent.RecipientID)
+	}
+	return tx, nil
+}
+
+// intent verifies a token and loads its intent. The stored intent must
+// match everything the token claims, so a token signed before a secret
+// leaked can't be bent onto another intent.
+func (s *service) intent(ctx context.Context, token string) (*models.PaymentIntent, error) {
+	c, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if c.expired(time.Now()) {
+		return nil, ErrIntentExpired
+	}
+
+	intent, err := s.repo.GetByID(ctx, c.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentIntentNotFound) {
+			return nil, ErrInvalidIntent
+		}
+		return nil, err
+	}
+	if claimsFor(intent) != *c {
+		return nil, ErrInvalidIntent
+	}
+	return intent, nil
+}
+
+// transaction builds the payment for an intent: a merchant payment when
+// the recipient is a merchant, otherwise a transfer
+func (s *service) transaction(intent *models.PaymentIntent, payerID uint, merchant *models.Merchant, now time.Time) *models.Transaction {
+	reference := intent.Reference
+	if reference == "" {
+		reference = fmt.Sprintf("PI-%d", intent.ID)
+	}
+	tx := &models.Transaction{
+		Type:          "transfer",
+		SenderID:      payerID,
+		ReceiverID:    intent.RecipientID,
+		Amount:        intent.Amount,
+		Currency:      intent.Currency,
+		Description:   intent.Description,
+		Status:        "pending",
+		TransactionID: fmt.Sprintf("PI-%d-%d", intent.ID, now.UnixNano()),
+		Reference:     reference,
+		PaymentType:   "payment_intent",
+		PaymentMethod: "wallet",
+	}
+	if merchant != nil {
+		tx.Type = "merchant_payment"
+		tx.MerchantID = &merchant.ID
+		tx.MerchantName = merchant.BusinessName
+		tx.MerchantCategory = merchant.BusinessType
+		tx.Category = "Sale"
+	}
+	return tx
+}
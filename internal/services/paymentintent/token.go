@@ -0,0 +1,75 @@
+package paymentintent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"orus/internal/models"
+	"strings"
+	"time"
+)
+
+// claims are what a token asserts about its intent. Keys are short to
+// keep tokens small enough for cheap NFC tags.
+type claims struct {
+	ID          uint    `json:"i"`
+	RecipientID uint    `json:"r"`
+	Amount      float64 `json:"a"`
+	Currency    string  `json:"c"`
+	ExpiresAt   int64   `json:"e"`
+}
+
+func claimsFor(intent *models.PaymentIntent) claims {
+	return claims{
+		ID:          intent.ID,
+		RecipientID: intent.RecipientID,
+		Amount:      intent.Amount,
+		Currency:    intent.Currency,
+		ExpiresAt:   intent.ExpiresAt.Unix(),
+	}
+}
+
+// sign returns the token for an intent: its claims and their signature,
+// both base64url encoded and joined by a dot
+func (s *service) sign(intent *models.PaymentIntent) (string, error) {
+	payload, err := json.Marshal(claimsFor(intent))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode payment intent: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// verify checks a token's signature and returns its claims. Expiry is left
+// to the caller.
+func (s *service) verify(token string) (*claims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidIntent
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(encoded)) {
+		return nil, ErrInvalidIntent
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidIntent
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidIntent
+	}
+	return &c, nil
+}
+
+func (c *claims) expired(now time.Time) bool {
+	return now.Unix() >= c.ExpiresAt
+}
+
+func (s *service) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
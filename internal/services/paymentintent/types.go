@@ -0,0 +1,36 @@
+package paymentintent
+
+import "orus/internal/models"
+
+// CreateRequest is the body for creating a payment intent
+type CreateRequest struct {
+	Amount      float64 `json:"amount" validate:"gt=0"`
+	Description string  `json:"description" validate:"max=255"`
+	// Reference is stamped on the payment, e.g. an order number
+	Reference string `json:"reference" validate:"max=64"`
+	// ExpiresIn is the intent's lifetime in seconds, 15 minutes by default
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=60,max=604800"`
+}
+
+// ConfirmRequest is the body for paying a payment intent
+type ConfirmRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// IssuedIntent is a new payment intent with the ways of sharing it
+type IssuedIntent struct {
+	*models.PaymentIntent
+	Token string `json:"token"`
+	// Link is a universal link opening the app, or the intent's preview
+	// without it. NFC tags carry it as a URI record.
+	Link string `json:"link"`
+	// AppURI opens the app directly
+	AppURI string `json:"app_uri"`
+}
+
+// IntentView is a payment intent as shown to the payer
+type IntentView struct {
+	*models.PaymentIntent
+	RecipientName string `json:"recipient_name"`
+	IsMerchant    bool   `json:"is_merchant"`
+}
@@ -0,0 +1,14 @@
+package paymentpin
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidPIN    = errors.New("PIN must be 4 to 6 digits")
+	ErrPINNotSet     = errors.New("you have not set a payment PIN")
+	ErrPINAlreadySet = errors.New("you already have a payment PIN; change or reset it instead")
+	ErrPINRequired   = errors.New("enter your payment PIN to continue")
+	ErrWrongPIN      = errors.New("incorrect payment PIN")
+	ErrPINLocked     = errors.New("too many incorrect payment PINs, try again later")
+	ErrWrongPassword = errors.New("incorrect password")
+)
@@ -0,0 +1,20 @@
+package paymentpin
+
+import "context"
+
+// Service manages users' payment PINs and checks them before sensitive
+// operations
+type Service interface {
+	Status(ctx context.Context, userID uint) (*Status, error)
+	Set(ctx context.Context, userID uint, req SetRequest) error
+	Change(ctx context.Context, userID uint, req ChangeRequest) error
+	// Reset replaces the PIN with the password, lifting any lockout
+	Reset(ctx context.Context, userID uint, req ResetRequest) error
+
+	// Require checks the PIN the request was made with, if the user has set
+	// one
+	Require(ctx context.Context, userID uint) error
+	// RequireForTransfer checks the PIN for transfers of at least the
+	// threshold, if the user has set one
+	RequireForTransfer(ctx context.Context, userID uint, amount float64) error
+}
@@ -0,0 +1,177 @@
+package paymentpin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/password"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type service struct {
+	repo      repositories.PaymentPINRepository
+	users     repositories.UserRepository
+	passwords *password.Hasher
+	threshold float64
+}
+
+// NewService creates the payment PIN service, asking for the PIN on
+// transfers of at least threshold
+func NewService(repo repositories.PaymentPINRepository, users repositories.UserRepository, passwords *password.Hasher, threshold float64) Service {
+	return &service{repo: repo, users: users, passwords: passwords, threshold: threshold}
+}
+
+// WithPIN attaches the PIN a request was made with to ctx
+func WithPIN(ctx context.Context, pin string) context.Context {
+	return context.WithValue(ctx, pinContextKey, pin)
+}
+
+func pinFrom(ctx context.Context) string {
+	pin, _ := ctx.Value(pinContextKey).(string)
+	return pin
+}
+
+func (s *service) Status(ctx context.Context, userID uint) (*Status, error) {
+	status := &Status{Threshold: s.threshold}
+	pin, err := s.repo.Get(userID)
+	if errors.Is(err, repositories.ErrPaymentPINNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status.Set = true
+	status.SetAt = &pin.SetAt
+	if pin.IsLocked(time.Now()) {
+		status.LockedUntil = pin.LockedUntil
+	}
+	return status, nil
+}
+
+func (s *service) Set(ctx context.Context, userID uint, req SetRequest) error {
+	if !pinPattern.MatchString(req.PIN) {
+		return ErrInvalidPIN
+	}
+	if _, err := s.repo.Get(userID); err == nil {
+		return ErrPINAlreadySet
+	} else if !errors.Is(err, repositories.ErrPaymentPINNotFound) {
+		return err
+	}
+	if err := s.checkPassword(userID, req.Password); err != nil {
+		return err
+	}
+	return s.save(userID, req.PIN)
+}
+
+func (s *service) Change(ctx context.Context, userID uint, req ChangeRequest) error {
+	if !pinPattern.MatchString(req.NewPIN) {
+		return ErrInvalidPIN
+	}
+	pin, err := s.get(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verify(pin, req.CurrentPIN); err != nil {
+		return err
+	}
+	return s.save(userID, req.NewPIN)
+}
+
+func (s *service) Reset(ctx context.Context, userID uint, req ResetRequest) error {
+	if !pinPattern.MatchString(req.NewPIN) {
+		return ErrInvalidPIN
+	}
+	if _, err := s.get(userID); err != nil {
+		return err
+	}
+	if err := s.checkPassword(userID, req.Password); err != nil {
+		return err
+	}
+	return s.save(userID, req.NewPIN)
+}
+
+func (s *service) Require(ctx context.Context, userID uint) error {
+	pin, err := s.repo.Get(userID)
+	if errors.Is(err, repositories.ErrPaymentPINNotFound) {
+		return nil // The PIN is optional
+	}
+	if err != nil {
+		return err
+	}
+	entered := pinFrom(ctx)
+	if entered == "" {
+		return ErrPINRequired
+	}
+	return s.verify(pin, entered)
+}
+
+func (s *service) RequireForTransfer(ctx context.Context, userID uint, amount float64) error {
+	if amount < s.threshold {
+		return nil
+	}
+	return s.Require(ctx, userID)
+}
+
+// verify checks entered against the PIN, locking it after MaxAttempts
+// wrong ones in a row. The count is kept in the database rather than on
+// pin, which parallel guesses all read before any of them is counted.
+func (s *service) verify(pin *models.PaymentPIN, entered string) error {
+	now := time.Now()
+	if pin.IsLocked(now) {
+		return ErrPINLocked
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(pin.PINHash), []byte(entered)); err != nil {
+		locked, err := s.repo.RecordFailure(pin.UserID, MaxAttempts, now.Add(Lockout), now)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return ErrPINLocked
+		}
+		return ErrWrongPIN
+	}
+	// A wrong guess in parallel may have locked the PIN since it was read
+	cleared, err := s.repo.ClearFailures(pin.UserID, now)
+	if err != nil {
+		return err
+	}
+	if !cleared {
+		return ErrPINLocked
+	}
+	return nil
+}
+
+func (s *service) save(userID uint, pin string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	return s.repo.Save(&models.PaymentPIN{
+		UserID:  userID,
+		PINHash: string(hash),
+		SetAt:   time.Now(),
+	})
+}
+
+func (s *service) get(userID uint) (*models.PaymentPIN, error) {
+	pin, err := s.repo.Get(userID)
+	if errors.Is(err, repositories.ErrPaymentPINNotFound) {
+		return nil, ErrPINNotSet
+	}
+	return pin, err
+}
+
+func (s *service) checkPassword(userID uint, password string) error {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if ok, _ := s.passwords.Verify(user.Password, password); !ok {
+		return ErrWrongPassword
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package paymentpin
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	// MaxAttempts wrong PINs in a row lock the PIN for Lockout
+	MaxAttempts = 5
+	Lockout     = 15 * time.Minute
+)
+
+var pinPattern = regexp.MustCompile(`^[0-9]{4,6}$`)
+
+// Status says whether the user has a PIN and whether it is locked
+type Status struct {
+	Set         bool       `json:"set"`
+	SetAt       *time.Time `json:"set_at,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	// Threshold is the transfer amount from which the PIN is asked for
+	Threshold float64 `json:"threshold"`
+}
+
+// SetRequest sets a first PIN, confirmed with the account password
+type SetRequest struct {
+	PIN      string `json:"pin" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ChangeRequest replaces the PIN with the current one
+type ChangeRequest struct {
+	CurrentPIN string `json:"current_pin" validate:"required"`
+	NewPIN     string `json:"new_pin" validate:"required"`
+}
+
+// ResetRequest replaces a forgotten or locked PIN with the account password
+type ResetRequest struct {
+	Password string `json:"password" validate:"required"`
+	NewPIN   string `json:"new_pin" validate:"required"`
+}
+
+type contextKey string
+
+// pinContextKey carries the PIN a request was made with
+const pinContextKey contextKey = "paymentPIN"
@@ -0,0 +1,6 @@
+package pendingtx
+
+import "errors"
+
+// Service errors
+var ErrInvalidAge = errors.New("older_than must be a positive duration such as 30m or 2h")
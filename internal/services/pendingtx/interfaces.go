@@ -0,0 +1,24 @@
+package pendingtx
+
+import (
+	"context"
+	"time"
+)
+
+// Webhooks tells merchants about payments to them that expired
+type Webhooks interface {
+	Enqueue(ctx context.Context, merchantUserID uint, event string, data interface{}) error
+}
+
+// Service expires transactions left pending past the TTL and shows the
+// long-pending ones to admins
+type Service interface {
+	// List pages through the transactions pending for longer than
+	// olderThan, oldest first; zero lists those pending over an hour
+	List(ctx context.Context, olderThan time.Duration, limit, offset int) ([]Item, int64, error)
+
+	// ExpireDue expires the transactions pending past the TTL, releasing
+	// their holds and telling the merchants paid, and returns how many it
+	// expired
+	ExpireDue(ctx context.Context) (int, error)
+}
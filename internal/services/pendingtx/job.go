@@ -0,0 +1,24 @@
+package pendingtx
+
+import (
+	"context"
+	"log"
+)
+
+// Job expires stale pending transactions from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the pending transaction service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "pending-transaction-expiry" }
+
+func (j *Job) Run(ctx context.Context) error {
+	expired, err := j.service.ExpireDue(ctx)
+	if expired > 0 {
+		log.Printf("Expired %d pending transactions", expired)
+	}
+	return err
+}
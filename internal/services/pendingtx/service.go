@@ -0,0 +1,82 @@
+package pendingtx
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/webhook"
+	"time"
+)
+
+type service struct {
+	repo     repositories.PendingTransactionRepository
+	webhooks Webhooks
+	config   Config
+}
+
+// NewService creates the pending transaction service
+func NewService(repo repositories.PendingTransactionRepository, webhooks Webhooks, config Config) Service {
+	return &service{repo: repo, webhooks: webhooks, config: config}
+}
+
+func (s *service) List(ctx context.Context, olderThan time.Duration, limit, offset int) ([]Item, int64, error) {
+	if olderThan < 0 {
+		return nil, 0, ErrInvalidAge
+	}
+	if olderThan == 0 {
+		olderThan = defaultAge
+	}
+
+	transactions, total, err := s.repo.List(time.Now().Add(-olderThan), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	items := make([]Item, len(transactions))
+	for i, tx := range transactions {
+		items[i] = Item{Transaction: tx, ExpiresAt: tx.CreatedAt.Add(s.config.TTL)}
+	}
+	return items, total, nil
+}
+
+func (s *service) ExpireDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	transactions, err := s.repo.FindStale(now.Add(-s.config.TTL), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range transactions {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		tx := &transactions[i]
+
+		released, err := s.repo.Expire(tx, now)
+		if errors.Is(err, repositories.ErrTransactionNotPending) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+		if released > 0 {
+			log.Printf("Released %d holds of expired transaction %d", released, tx.ID)
+		}
+		s.notify(ctx, tx)
+	}
+	return expired, nil
+}
+
+// notify tells the merchant a payment to them expired. Other transactions
+// have no one to tell beyond their own history.
+func (s *service) notify(ctx context.Context, tx *models.Transaction) {
+	if tx.MerchantID == nil || tx.ReceiverID == 0 {
+		return
+	}
+	if err := s.webhooks.Enqueue(ctx, tx.ReceiverID, webhook.EventTransactionExpired, tx); err != nil {
+		log.Printf("Failed to queue expiry webhook for transaction %d: %v", tx.ID, err)
+	}
+}
@@ -0,0 +1,26 @@
+package pendingtx
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// Config sets when pending transactions expire
+type Config struct {
+	// TTL is how long a transaction may stay pending
+	TTL time.Duration
+}
+
+// Item is a long-pending transaction in the admin view
+type Item struct {
+	models.Transaction
+	// ExpiresAt is when the cleanup job expires it
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// defaultAge is how long a transaction must have been pending to be
+// listed when the admin doesn't say
+const defaultAge = time.Hour
+
+// batchSize bounds the transactions expired per job run
+const batchSize = 200
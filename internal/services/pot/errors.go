@@ -0,0 +1,20 @@
+package pot
+
+import "errors"
+
+// Service errors
+var (
+	ErrPotNotFound          = errors.New("pot not found")
+	ErrNameRequired         = errors.New("pot name is required")
+	ErrInvalidAmount        = errors.New("amount must be greater than zero")
+	ErrInvalidTarget        = errors.New("target amount cannot be negative")
+	ErrInvalidRoundUp       = errors.New("round-up must be one of 1, 5 or 10")
+	ErrInvalidSweep         = errors.New("sweeps need a positive amount and a daily, weekly or monthly frequency")
+	ErrLockInPast           = errors.New("lock date must be in the future")
+	ErrLockShortened        = errors.New("a pot's lock can be extended but not shortened or removed")
+	ErrPotLocked            = errors.New("pot is locked until its target date")
+	ErrPotClosed            = errors.New("pot is closed")
+	ErrInsufficientPotFunds = errors.New("insufficient funds in pot")
+	ErrRoundUpPotExists     = errors.New("round-ups already go to another pot")
+	ErrTooManyPots          = errors.New("pot limit reached")
+)
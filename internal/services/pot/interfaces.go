@@ -0,0 +1,35 @@
+package pot
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// WalletService is the part of the wallet service pots rely on. Balances are
+// checked through it so funds reserved by holds can't be moved into a pot.
+type WalletService interface {
+	GetWallet(ctx context.Context, userID uint) (*models.Wallet, error)
+	ValidateBalance(ctx context.Context, userID uint, amount float64) error
+}
+
+// Service manages savings pots set aside from a user's wallet
+type Service interface {
+	Create(ctx context.Context, userID uint, req PotRequest) (*PotView, error)
+	List(ctx context.Context, userID uint, includeClosed bool) ([]PotView, error)
+	Get(ctx context.Context, userID, potID uint) (*PotView, error)
+	Update(ctx context.Context, userID, potID uint, req PotRequest) (*PotView, error)
+	// Close returns whatever is left in the pot to the main balance
+	Close(ctx context.Context, userID, potID uint) (*PotView, error)
+
+	Deposit(ctx context.Context, userID, potID uint, amount float64) (*MoveResult, error)
+	Withdraw(ctx context.Context, userID, potID uint, amount float64) (*MoveResult, error)
+	Entries(ctx context.Context, userID, potID uint, limit, offset int) ([]models.PotEntry, int64, error)
+
+	// ProcessRoundUps saves the spare change from recent card and QR payments
+	ProcessRoundUps(ctx context.Context) (int, error)
+	// ProcessSweeps runs scheduled transfers into pots that are due
+	ProcessSweeps(ctx context.Context) (int, error)
+	// CoverShortfall moves up to amount back from the user's unlocked pots
+	// to their main balance, returning how much it moved
+	CoverShortfall(ctx context.Context, userID uint, amount float64) (float64, error)
+}
@@ -0,0 +1,32 @@
+package pot
+
+import (
+	"context"
+	"log"
+)
+
+// Job saves round-ups and runs scheduled sweeps into pots
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the pot service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "pot-savings" }
+
+func (j *Job) Run(ctx context.Context) error {
+	roundUps, err := j.service.ProcessRoundUps(ctx)
+	if roundUps > 0 {
+		log.Printf("Saved %d payment round-ups into pots", roundUps)
+	}
+	if err != nil {
+		return err
+	}
+
+	sweeps, err := j.service.ProcessSweeps(ctx)
+	if sweeps > 0 {
+		log.Printf("Ran %d scheduled pot sweeps", sweeps)
+	}
+	return err
+}
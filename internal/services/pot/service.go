@@ -0,0 +1,538 @@
+package pot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/wallet"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxPotsPerUser caps how many open pots a user can have
+	MaxPotsPerUser = 10
+
+	batchSize = 100
+)
+
+// roundUpPaymentTypes are the card and QR payments spare change is saved from
+var roundUpPaymentTypes = []string{
+	models.TransactionTypeCardPayment,
+	models.TransactionTypeQRPayment,
+	models.TransactionTypeQRCode,
+	models.TransactionTypeMerchantScan,
+	models.TransactionTypeMerchantDirect,
+}
+
+type service struct {
+	repo    repositories.PotRepository
+	wallets WalletService
+	cache   *cache.CacheService
+}
+
+// NewService creates a new savings pot service
+func NewService(repo repositories.PotRepository, wallets WalletService, cache *cache.CacheService) Service {
+	return &service{
+		repo:    repo,
+		wallets: wallets,
+		cache:   cache,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uint, req PotRequest) (*PotView, error) {
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		return nil, ErrNameRequired
+	}
+
+	existing, err := s.repo.GetByUserID(userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxPotsPerUser {
+		return nil, ErrTooManyPots
+	}
+
+	w, err := s.wallets.GetWallet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pot := &models.Pot{
+		UserID:    userID,
+		WalletID:  w.ID,
+		Currency:  w.Currency,
+		Status:    models.PotStatusActive,
+		RoundUpTo: 1,
+	}
+	if err := s.apply(pot, req, time.Now()); err != nil {
+		return nil, err
+	}
+	if pot.RoundUpEnabled {
+		if err := s.startRoundUps(pot); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(pot); err != nil {
+		return nil, err
+	}
+	return newPotView(pot), nil
+}
+
+func (s *service) List(ctx context.Context, userID uint, includeClosed bool) ([]PotView, error) {
+	pots, err := s.repo.GetByUserID(userID, includeClosed)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]PotView, len(pots))
+	for i := range pots {
+		views[i] = *newPotView(&pots[i])
+	}
+	return views, nil
+}
+
+func (s *service) Get(ctx context.Context, userID, potID uint) (*PotView, error) {
+	pot, err := s.get(userID, potID)
+	if err != nil {
+		return nil, err
+	}
+	return newPotView(pot), nil
+}
+
+func (s *service) Update(ctx context.Context, userID, potID uint, req PotRequest) (*PotView, error) {
+	pot, err := s.get(userID, potID)
+	if err != nil {
+		return nil, err
+	}
+	if pot.Status != models.PotStatusActive {
+		return nil, ErrPotClosed
+	}
+
+	wasRoundingUp := pot.RoundUpEnabled
+	if err := s.apply(pot, req, time.Now()); err != nil {
+		return nil, err
+	}
+	if pot.RoundUpEnabled && !wasRoundingUp {
+		if err := s.startRoundUps(pot); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateSettings(pot); err != nil {
+		return nil, err
+	}
+	return newPotView(pot), nil
+}
+
+func (s *service) Close(ctx context.Context, userID, potID uint) (*PotView, error) {
+	pot, err := s.get(userID, potID)
+	if err != nil {
+		return nil, err
+	}
+	if pot.Status != models.PotStatusActive {
+		return nil, ErrPotClosed
+	}
+	if pot.IsLocked(time.Now()) {
+		return nil, ErrPotLocked
+	}
+
+	if _, err := s.move(ctx, pot, -pot.Balance, models.PotEntryWithdrawal, "close", nil, true); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, potID)
+}
+
+func (s *service) Deposit(ctx context.Context, userID, potID uint, amount float64) (*MoveResult, error) {
+	amount = round2(amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	pot, err := s.get(userID, potID)
+	if err != nil {
+		return nil, err
+	}
+	if pot.Status != models.PotStatusActive {
+		return nil, ErrPotClosed
+	}
+	if err := s.wallets.ValidateBalance(ctx, userID, amount); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.move(ctx, pot, amount, models.PotEntryDeposit, "manual", nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.moveResult(ctx, userID, potID, entry)
+}
+
+func (s *service) Withdraw(ctx context.Context, userID, potID uint, amount float64) (*MoveResult, error) {
+	amount = round2(amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	pot, err := s.get(userID, potID)
+	if err != nil {
+		return nil, err
+	}
+	if pot.Status != models.PotStatusActive {
+		return nil, ErrPotClosed
+	}
+	if pot.IsLocked(time.Now()) {
+		return nil, ErrPotLocked
+	}
+
+	entry, err := s.move(ctx, pot, -amount, models.PotEntryWithdrawal, "manual", nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.moveResult(ctx, userID, potID, entry)
+}
+
+func (s *service) Entries(ctx context.Context, userID, potID uint, limit, offset int) ([]models.PotEntry, int64, error) {
+	if _, err := s.get(userID, potID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetEntries(potID, limit, offset)
+}
+
+// ProcessRoundUps walks each round-up pot's new payments since its checkpoint
+// and moves the difference to the next multiple of RoundUpTo into the pot.
+// Payments are skipped, not retried, when the wallet can't cover the round-up.
+func (s *service) ProcessRoundUps(ctx context.Context) (int, error) {
+	pots, err := s.repo.GetRoundUpPots(batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	saved := 0
+potLoop:
+	for i := range pots {
+		pot := &pots[i]
+		payments, err := s.repo.GetRoundUpCandidates(pot.UserID, pot.RoundUpAfterID, roundUpPaymentTypes, batchSize)
+		if err != nil {
+			return saved, err
+		}
+
+		for _, payment := range payments {
+			if err := ctx.Err(); err != nil {
+				return saved, err
+			}
+
+			amount := roundUpAmount(payment.Amount, pot.RoundUpTo)
+			if amount > 0 && s.wallets.ValidateBalance(ctx, pot.UserID, amount) == nil {
+				paymentID := payment.ID
+				_, err := s.move(ctx, pot, amount, models.PotEntryRoundUp, "round_up", &paymentID, false)
+				switch {
+				case err == nil:
+					saved++
+				case errors.Is(err, repositories.ErrRoundUpAlreadyTaken),
+					errors.Is(err, repositories.ErrInsufficientPersonalFunds):
+				case errors.Is(err, repositories.ErrPotNotActive):
+					continue potLoop
+				default:
+					log.Printf("Failed to round up payment %d into pot %d: %v", payment.ID, pot.ID, err)
+					continue potLoop
+				}
+			}
+
+			if err := s.repo.SetRoundUpCheckpoint(pot.ID, payment.ID); err != nil {
+				return saved, err
+			}
+		}
+	}
+	return saved, nil
+}
+
+// ProcessSweeps moves each due pot's sweep amount in from the main balance,
+// never past the pot's target, and schedules the next run
+func (s *service) ProcessSweeps(ctx context.Context) (int, error) {
+	now := time.Now()
+	pots, err := s.repo.GetDueSweeps(now, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	swept := 0
+	for i := range pots {
+		if err := ctx.Err(); err != nil {
+			return swept, err
+		}
+		pot := &pots[i]
+
+		amount := pot.SweepAmount
+		if pot.TargetAmount > 0 {
+			amount = math.Min(amount, round2(pot.TargetAmount-pot.Balance))
+		}
+		if amount > 0 {
+			if err := s.wallets.ValidateBalance(ctx, pot.UserID, amount); err != nil {
+				log.Printf("Skipping sweep into pot %d: %v", pot.ID, err)
+			} else if _, err := s.move(ctx, pot, amount, models.PotEntrySweep, "sweep", nil, false); err != nil {
+				log.Printf("Failed to sweep into pot %d: %v", pot.ID, err)
+			} else {
+				swept++
+			}
+		}
+
+		next := nextSweep(*pot.NextSweepAt, pot.SweepFrequency, now)
+		if err := s.repo.SetNextSweep(pot.ID, &next); err != nil {
+			return swept, err
+		}
+	}
+	return swept, nil
+}
+
+// CoverShortfall empties the user's unlocked pots, oldest first, into their
+// main balance until amount is covered
+func (s *service) CoverShortfall(ctx context.Context, userID uint, amount float64) (float64, error) {
+	pots, err := s.repo.GetByUserID(userID, false)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var covered float64
+	for i := range pots {
+		remaining := round2(amount - covered)
+		if remaining <= 0 {
+			break
+		}
+		pot := &pots[i]
+		if pot.Balance <= 0 || pot.IsLocked(now) {
+			continue
+		}
+
+		take := math.Min(remaining, pot.Balance)
+		if _, err := s.move(ctx, pot, -take, models.PotEntryWithdrawal, "overdraft_recovery", nil, false); err != nil {
+			return covered, err
+		}
+		covered = round2(covered + take)
+	}
+	return covered, nil
+}
+
+// move records a transfer between the pot and the user's main balance as a
+// pot_transfer transaction so it shows up in the ledger like any other
+func (s *service) move(ctx context.Context, pot *models.Pot, amount float64, kind, source string, sourceTxID *uint, closePot bool) (*models.PotEntry, error) {
+	direction, description := "in", fmt.Sprintf("Moved to %s", pot.Name)
+	if amount < 0 {
+		direction, description = "out", fmt.Sprintf("Moved from %s", pot.Name)
+	}
+	metadata := map[string]interface{}{
+		"pot_id":    pot.ID,
+		"direction": direction,
+		"source":    source,
+	}
+	if sourceTxID != nil {
+		metadata["source_transaction_id"] = *sourceTxID
+	}
+
+	now := time.Now()
+	entry, err := s.repo.Move(repositories.PotMove{
+		PotID:  pot.ID,
+		UserID: pot.UserID,
+		Amount: amount,
+		Kind:   kind,
+		Transaction: &models.Transaction{
+			Type:          models.TransactionTypePotTransfer,
+			SenderID:      pot.UserID,
+			ReceiverID:    pot.UserID,
+			Amount:        math.Abs(amount),
+			Currency:      pot.Currency,
+			Status:        "completed",
+			Description:   description,
+			Category:      "Savings",
+			TransactionID: fmt.Sprintf("POT-%d-%d", pot.ID, now.UnixNano()),
+			PaymentType:   "pot",
+			PaymentMethod: "wallet",
+			ProcessedAt:   now,
+			Metadata:      models.NewJSON(metadata),
+		},
+		SourceTransactionID: sourceTxID,
+		Close:               closePot,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrPotNotFound):
+			return nil, ErrPotNotFound
+		case errors.Is(err, repositories.ErrPotNotActive):
+			return nil, ErrPotClosed
+		case errors.Is(err, repositories.ErrInsufficientPotFunds):
+			return nil, ErrInsufficientPotFunds
+		case errors.Is(err, repositories.ErrInsufficientPersonalFunds):
+			return nil, wallet.ErrInsufficientBalance
+		}
+		return nil, err
+	}
+
+	s.invalidate(ctx, pot.UserID)
+	return entry, nil
+}
+
+func (s *service) moveResult(ctx context.Context, userID, potID uint, entry *models.PotEntry) (*MoveResult, error) {
+	view, err := s.Get(ctx, userID, potID)
+	if err != nil {
+		return nil, err
+	}
+	return &MoveResult{Pot: view, Entry: entry}, nil
+}
+
+func (s *service) get(userID, potID uint) (*models.Pot, error) {
+	pot, err := s.repo.GetByIDAndUserID(potID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPotNotFound) {
+			return nil, ErrPotNotFound
+		}
+		return nil, err
+	}
+	return pot, nil
+}
+
+// apply validates req and copies the fields it sets onto pot
+func (s *service) apply(pot *models.Pot, req PotRequest, now time.Time) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return ErrNameRequired
+		}
+		pot.Name = name
+	}
+	if req.TargetAmount != nil {
+		if *req.TargetAmount < 0 {
+			return ErrInvalidTarget
+		}
+		pot.TargetAmount = round2(*req.TargetAmount)
+	}
+	if req.LockedUntil != nil {
+		if !req.LockedUntil.After(now) {
+			return ErrLockInPast
+		}
+		if pot.LockedUntil != nil && req.LockedUntil.Before(*pot.LockedUntil) {
+			return ErrLockShortened
+		}
+		lockedUntil := *req.LockedUntil
+		pot.LockedUntil = &lockedUntil
+	}
+	if req.RoundUpTo != nil {
+		switch *req.RoundUpTo {
+		case 1, 5, 10:
+			pot.RoundUpTo = *req.RoundUpTo
+		default:
+			return ErrInvalidRoundUp
+		}
+	}
+	if req.RoundUpEnabled != nil {
+		pot.RoundUpEnabled = *req.RoundUpEnabled
+	}
+
+	if req.SweepAmount != nil || req.SweepFrequency != nil || req.SweepStartAt != nil {
+		if req.SweepAmount != nil {
+			pot.SweepAmount = round2(*req.SweepAmount)
+		}
+		if req.SweepFrequency != nil {
+			pot.SweepFrequency = strings.ToLower(strings.TrimSpace(*req.SweepFrequency))
+		}
+		if pot.SweepAmount < 0 {
+			return ErrInvalidSweep
+		}
+		if pot.SweepAmount == 0 {
+			pot.SweepFrequency = ""
+			pot.NextSweepAt = nil
+			return nil
+		}
+		if !isValidFrequency(pot.SweepFrequency) {
+			return ErrInvalidSweep
+		}
+
+		next := nextSweep(now, pot.SweepFrequency, now)
+		if req.SweepStartAt != nil {
+			if !req.SweepStartAt.After(now) {
+				return ErrInvalidSweep
+			}
+			next = *req.SweepStartAt
+		}
+		pot.NextSweepAt = &next
+	}
+	return nil
+}
+
+// startRoundUps makes sure a user only rounds up into one pot, and that only
+// payments made from now on are rounded up
+func (s *service) startRoundUps(pot *models.Pot) error {
+	taken, err := s.repo.HasRoundUpPot(pot.UserID, pot.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrRoundUpPotExists
+	}
+	latest, err := s.repo.LatestTransactionID()
+	if err != nil {
+		return err
+	}
+	pot.RoundUpAfterID = latest
+	return nil
+}
+
+func (s *service) invalidate(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, s.cache.GenerateKey("wallet", "user", userID)); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
+
+func newPotView(pot *models.Pot) *PotView {
+	view := &PotView{Pot: pot, Locked: pot.IsLocked(time.Now())}
+	if pot.TargetAmount > 0 {
+		view.Progress = math.Min(100, round2(pot.Balance/pot.TargetAmount*100))
+	}
+	return view
+}
+
+func isValidFrequency(frequency string) bool {
+	switch frequency {
+	case models.SweepDaily, models.SweepWeekly, models.SweepMonthly:
+		return true
+	}
+	return false
+}
+
+// nextSweep steps from by one period until it is after now, so a backlog of
+// missed runs is collapsed into one
+func nextSweep(from time.Time, frequency string, now time.Time) time.Time {
+	next := from
+	for !next.After(now) {
+		switch frequency {
+		case models.SweepDaily:
+			next = next.AddDate(0, 0, 1)
+		case models.SweepWeekly:
+			next = next.AddDate(0, 0, 7)
+		default:
+			next = next.AddDate(0, 1, 0)
+		}
+	}
+	return next
+}
+
+// roundUpAmount is how much it takes to bring amount up to the next multiple
+// of step
+func roundUpAmount(amount, step float64) float64 {
+	cents := int64(math.Round(amount * 100))
+	stepCents := int64(math.Round(step * 100))
+	if stepCents <= 0 {
+		return 0
+	}
+	remainder := cents % stepCents
+	if remainder == 0 {
+		return 0
+	}
+	return float64(stepCents-remainder) / 100
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
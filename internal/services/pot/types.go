@@ -0,0 +1,38 @@
+package pot
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// PotRequest creates or updates a pot. On update, only fields that are set
+// are changed.
+type PotRequest struct {
+	Name           *string    `json:"name"`
+	TargetAmount   *float64   `json:"target_amount"`
+	LockedUntil    *time.Time `json:"locked_until"`
+	RoundUpEnabled *bool      `json:"round_up_enabled"`
+	RoundUpTo      *float64   `json:"round_up_to"`
+	SweepAmount    *float64   `json:"sweep_amount"`
+	SweepFrequency *string    `json:"sweep_frequency"`
+	// SweepStartAt is when the first sweep runs; defaults to one period from now
+	SweepStartAt *time.Time `json:"sweep_start_at"`
+}
+
+// PotView is a pot with its progress towards the goal
+type PotView struct {
+	*models.Pot
+	Progress float64 `json:"progress"` // Percent of the target saved, 0 without a target
+	Locked   bool    `json:"locked"`
+}
+
+// AmountRequest is the body for deposits and withdrawals
+type AmountRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// MoveResult is the pot after a deposit or withdrawal with the entry recorded
+type MoveResult struct {
+	Pot   *PotView         `json:"pot"`
+	Entry *models.PotEntry `json:"entry"`
+}
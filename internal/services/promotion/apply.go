@@ -0,0 +1,122 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+func (s *service) Apply(ctx context.Context, tx *models.Transaction) ([]models.PromotionReward, error) {
+	if tx.Status != "completed" || !isPayment(tx.Type) {
+		return nil, nil
+	}
+	merchant, err := s.merchants.GetByUserID(tx.ReceiverID)
+	if err != nil {
+		// Payments to people rather than merchants earn nothing
+		return nil, nil
+	}
+
+	now := time.Now()
+	promotions, err := s.repo.ListAvailable(tx.SenderID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var rewards []models.PromotionReward
+	for i := range promotions {
+		promotion := &promotions[i]
+		amount, err := s.reward(promotion, tx, merchant, now)
+		if err != nil {
+			return rewards, err
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		reward, err := s.repo.Grant(repositories.PromotionGrant{Promotion: promotion, Payment: tx, Amount: amount})
+		switch {
+		case err == nil:
+			rewards = append(rewards, *reward)
+			s.invalidate(ctx, tx.SenderID)
+			if promotion.FundedBy == models.PromotionFundedByMerchant {
+				s.invalidate(ctx, *promotion.MerchantID)
+			}
+		case errors.Is(err, repositories.ErrPromotionNotActive),
+			errors.Is(err, repositories.ErrPromotionLimitReached),
+			errors.Is(err, repositories.ErrPromotionAlreadyRewarded):
+			// Lost a race for the last of the budget or the customer's limit
+		case errors.Is(err, repositories.ErrInsufficientFunderFunds):
+			log.Printf("Merchant %d can't fund promotion %d for transaction %d", *promotion.MerchantID, promotion.ID, tx.ID)
+		default:
+			return rewards, err
+		}
+	}
+	return rewards, nil
+}
+
+// reward works out what a promotion pays for a payment, or 0 if the
+// payment doesn't qualify
+func (s *service) reward(p *models.Promotion, tx *models.Transaction, merchant *models.Merchant, now time.Time) (float64, error) {
+	if !running(p, now) || tx.Amount < p.MinSpend {
+		return 0, nil
+	}
+	if p.MerchantID != nil && *p.MerchantID != tx.ReceiverID {
+		return 0, nil
+	}
+	if p.MerchantCategory != "" && !strings.EqualFold(p.MerchantCategory, merchant.BusinessType) {
+		return 0, nil
+	}
+	// A merchant can't earn rewards it funds
+	if p.FundedBy == models.PromotionFundedByMerchant && tx.SenderID == *p.MerchantID {
+		return 0, nil
+	}
+
+	var amount float64
+	switch p.Type {
+	case models.PromotionTypeCashback:
+		amount = tx.Amount * p.Rate / 100
+		if p.MaxReward > 0 {
+			amount = math.Min(amount, p.MaxReward)
+		}
+	case models.PromotionTypeFixedBonus:
+		amount = p.Amount
+	case models.PromotionTypeFirstTransaction:
+		// First payment anywhere, or to the promotion's merchant
+		var merchantID uint
+		if p.MerchantID != nil {
+			merchantID = *p.MerchantID
+		}
+		previous, err := s.repo.CountPayments(tx.SenderID, merchantID, paymentTypes, tx.ID)
+		if err != nil {
+			return 0, err
+		}
+		if previous > 0 {
+			return 0, nil
+		}
+		amount = p.Amount
+	}
+
+	// A reward never exceeds the payment that earned it
+	return round2(math.Min(amount, tx.Amount)), nil
+}
+
+func (s *service) invalidate(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, s.cache.GenerateKey("wallet", "user", userID)); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
+
+func isPayment(txType string) bool {
+	txType = strings.ToLower(txType)
+	for _, t := range paymentTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package promotion
+
+import "errors"
+
+// Service errors
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrNameRequired      = errors.New("promotion name is required")
+	ErrInvalidType       = errors.New("type must be cashback, fixed_bonus or first_transaction")
+	ErrInvalidRate       = errors.New("cashback rate must be above 0 and at most 100 percent")
+	ErrInvalidAmount     = errors.New("invalid amount")
+	ErrInvalidBudget     = errors.New("budget must be greater than zero")
+	ErrInvalidLimit      = errors.New("max per user cannot be negative")
+	ErrInvalidFunding    = errors.New("funded_by must be platform or merchant")
+	ErrMerchantRequired  = errors.New("merchant-funded promotions must name the merchant")
+	ErrMerchantNotFound  = errors.New("merchant not found")
+	ErrInvalidSchedule   = errors.New("promotion must end after it starts")
+	ErrInvalidCode       = errors.New("code must be 3 to 32 letters, digits, dashes or underscores")
+	ErrCodeTaken         = errors.New("promo code is already in use")
+	ErrNotPausable       = errors.New("only active promotions can be paused")
+	ErrNotResumable      = errors.New("only paused promotions with budget left can be resumed")
+	ErrAlreadyEnded      = errors.New("promotion has already ended")
+	ErrCodeNotFound      = errors.New("promo code is not valid")
+	ErrCodeExpired       = errors.New("promo code is no longer available")
+	ErrAlreadyRedeemed   = errors.New("you have already redeemed this promo code")
+)
@@ -0,0 +1,29 @@
+package promotion
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages promotional campaigns and pays out their rewards
+type Service interface {
+	// Admin side
+	Create(ctx context.Context, adminID uint, req CreateRequest) (*models.Promotion, error)
+	List(ctx context.Context, status string, limit, offset int) ([]models.Promotion, int64, error)
+	Get(ctx context.Context, promotionID uint) (*models.Promotion, error)
+	Pause(ctx context.Context, promotionID uint) (*models.Promotion, error)
+	Resume(ctx context.Context, promotionID uint) (*models.Promotion, error)
+	End(ctx context.Context, promotionID uint) (*models.Promotion, error)
+	ListRewards(ctx context.Context, promotionID uint, limit, offset int) ([]models.PromotionReward, int64, error)
+
+	// Customer side
+	// Available returns the running promotions the customer can earn
+	Available(ctx context.Context, userID uint) ([]Offer, error)
+	// Redeem enrolls the customer in the promotion behind a code
+	Redeem(ctx context.Context, userID uint, code string) (*Offer, error)
+	Rewards(ctx context.Context, userID uint, limit, offset int) ([]models.PromotionReward, int64, error)
+
+	// Apply credits every promotion a completed merchant payment qualifies
+	// for. Payments that don't qualify for anything return no rewards.
+	Apply(ctx context.Context, tx *models.Transaction) ([]models.PromotionReward, error)
+}
@@ -0,0 +1,230 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxBudget caps how much a single promotion can pay out
+const MaxBudget = 1000000.0
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type service struct {
+	repo      repositories.PromotionRepository
+	merchants repositories.MerchantRepository
+	cache     *cache.CacheService
+}
+
+// NewService creates a new promotions service
+func NewService(repo repositories.PromotionRepository, merchants repositories.MerchantRepository, cache *cache.CacheService) Service {
+	return &service{repo: repo, merchants: merchants, cache: cache}
+}
+
+func (s *service) Create(ctx context.Context, adminID uint, req CreateRequest) (*models.Promotion, error) {
+	promotion := &models.Promotion{
+		Name:             strings.TrimSpace(req.Name),
+		Description:      strings.TrimSpace(req.Description),
+		Type:             req.Type,
+		MinSpend:         round2(req.MinSpend),
+		MerchantCategory: strings.TrimSpace(req.MerchantCategory),
+		FundedBy:         req.FundedBy,
+		Budget:           round2(req.Budget),
+		MaxPerUser:       req.MaxPerUser,
+		StartsAt:         time.Now(),
+		EndsAt:           req.EndsAt,
+		Status:           models.PromotionStatusActive,
+		CreatedBy:        adminID,
+	}
+	if promotion.Name == "" {
+		return nil, ErrNameRequired
+	}
+
+	switch req.Type {
+	case models.PromotionTypeCashback:
+		if req.Rate <= 0 || req.Rate > 100 {
+			return nil, ErrInvalidRate
+		}
+		if req.MaxReward < 0 {
+			return nil, ErrInvalidAmount
+		}
+		promotion.Rate = req.Rate
+		promotion.MaxReward = round2(req.MaxReward)
+	case models.PromotionTypeFixedBonus, models.PromotionTypeFirstTransaction:
+		if round2(req.Amount) <= 0 {
+			return nil, ErrInvalidAmount
+		}
+		promotion.Amount = round2(req.Amount)
+	default:
+		return nil, ErrInvalidType
+	}
+
+	if promotion.MinSpend < 0 {
+		return nil, ErrInvalidAmount
+	}
+	if promotion.Budget <= 0 || promotion.Budget > MaxBudget {
+		return nil, ErrInvalidBudget
+	}
+	if promotion.MaxPerUser < 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	switch req.FundedBy {
+	case models.PromotionFundedByPlatform:
+	case models.PromotionFundedByMerchant:
+		// Merchants only fund rewards on payments they receive
+		if req.MerchantID == nil {
+			return nil, ErrMerchantRequired
+		}
+	default:
+		return nil, ErrInvalidFunding
+	}
+	if req.MerchantID != nil {
+		if _, err := s.merchants.GetByUserID(*req.MerchantID); err != nil {
+			return nil, ErrMerchantNotFound
+		}
+		promotion.MerchantID = req.MerchantID
+	}
+
+	if req.StartsAt != nil {
+		promotion.StartsAt = *req.StartsAt
+	}
+	if promotion.EndsAt != nil && !promotion.EndsAt.After(promotion.StartsAt) {
+		return nil, ErrInvalidSchedule
+	}
+
+	if code := strings.TrimSpace(req.Code); code != "" {
+		code = strings.ToUpper(code)
+		if !codePattern.MatchString(code) {
+			return nil, ErrInvalidCode
+		}
+		if _, err := s.repo.GetByCode(code); err == nil {
+			return nil, ErrCodeTaken
+		} else if !errors.Is(err, repositories.ErrPromotionNotFound) {
+			return nil, err
+		}
+		promotion.Code = &code
+	}
+
+	if err := s.repo.Create(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *service) List(ctx context.Context, status string, limit, offset int) ([]models.Promotion, int64, error) {
+	return s.repo.List(status, limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, promotionID uint) (*models.Promotion, error) {
+	promotion, err := s.repo.GetByID(promotionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPromotionNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *service) Pause(ctx context.Context, promotionID uint) (*models.Promotion, error) {
+	return s.transition(ctx, promotionID, ErrNotPausable, models.PromotionStatusPaused, models.PromotionStatusActive)
+}
+
+func (s *service) Resume(ctx context.Context, promotionID uint) (*models.Promotion, error) {
+	promotion, err := s.Get(ctx, promotionID)
+	if err != nil {
+		return nil, err
+	}
+	if promotion.Spent >= promotion.Budget {
+		return nil, ErrNotResumable
+	}
+	return s.transition(ctx, promotionID, ErrNotResumable, models.PromotionStatusActive, models.PromotionStatusPaused)
+}
+
+func (s *service) End(ctx context.Context, promotionID uint) (*models.Promotion, error) {
+	return s.transition(ctx, promotionID, ErrAlreadyEnded, models.PromotionStatusEnded,
+		models.PromotionStatusActive, models.PromotionStatusPaused, models.PromotionStatusExhausted)
+}
+
+func (s *service) ListRewards(ctx context.Context, promotionID uint, limit, offset int) ([]models.PromotionReward, int64, error) {
+	if _, err := s.Get(ctx, promotionID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListRewards(promotionID, 0, limit, offset)
+}
+
+func (s *service) Available(ctx context.Context, userID uint) ([]Offer, error) {
+	promotions, err := s.repo.ListAvailable(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	offers := make([]Offer, 0, len(promotions))
+	for i := range promotions {
+		offers = append(offers, newOffer(&promotions[i]))
+	}
+	return offers, nil
+}
+
+func (s *service) Redeem(ctx context.Context, userID uint, code string) (*Offer, error) {
+	promotion, err := s.repo.GetByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if errors.Is(err, repositories.ErrPromotionNotFound) {
+			return nil, ErrCodeNotFound
+		}
+		return nil, err
+	}
+	// Codes can be redeemed ahead of a promotion's start
+	now := time.Now()
+	if promotion.Status != models.PromotionStatusActive || (promotion.EndsAt != nil && !promotion.EndsAt.After(now)) {
+		return nil, ErrCodeExpired
+	}
+
+	enrolled, err := s.repo.Enroll(&models.PromotionEnrollment{PromotionID: promotion.ID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, ErrAlreadyRedeemed
+	}
+	offer := newOffer(promotion)
+	return &offer, nil
+}
+
+func (s *service) Rewards(ctx context.Context, userID uint, limit, offset int) ([]models.PromotionReward, int64, error) {
+	return s.repo.ListRewards(0, userID, limit, offset)
+}
+
+// transition moves a promotion to status if it is in one of from,
+// returning notAllowed if it wasn't
+func (s *service) transition(ctx context.Context, promotionID uint, notAllowed error, status string, from ...string) (*models.Promotion, error) {
+	if _, err := s.Get(ctx, promotionID); err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.Transition(promotionID, from, map[string]interface{}{"status": status})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, notAllowed
+	}
+	return s.Get(ctx, promotionID)
+}
+
+// running reports whether a promotion is active and inside its schedule
+func running(p *models.Promotion, now time.Time) bool {
+	return p.Status == models.PromotionStatusActive &&
+		!p.StartsAt.After(now) &&
+		(p.EndsAt == nil || p.EndsAt.After(now))
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,67 @@
+package promotion
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// paymentTypes are the transaction types that pay a merchant and can earn
+// rewards, lower-cased
+var paymentTypes = []string{
+	"merchant_payment",
+	models.TransactionTypeMerchantScan,
+	models.TransactionTypeQRPayment,
+}
+
+// CreateRequest defines a new promotion. Rate applies to cashback, Amount
+// to fixed bonuses and first-transaction promos.
+type CreateRequest struct {
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Code             string     `json:"code"` // Optional; customers must redeem it to qualify
+	Type             string     `json:"type"`
+	Rate             float64    `json:"rate"`
+	Amount           float64    `json:"amount"`
+	MaxReward        float64    `json:"max_reward"`
+	MinSpend         float64    `json:"min_spend"`
+	MerchantID       *uint      `json:"merchant_id"` // Merchant's user ID
+	MerchantCategory string     `json:"merchant_category"`
+	FundedBy         string     `json:"funded_by"`
+	Budget           float64    `json:"budget"`
+	MaxPerUser       int        `json:"max_per_user"`
+	StartsAt         *time.Time `json:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at"`
+}
+
+// Offer is a promotion as shown to customers, without its budget
+type Offer struct {
+	ID               uint       `json:"id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description,omitempty"`
+	Type             string     `json:"type"`
+	Rate             float64    `json:"rate,omitempty"`
+	Amount           float64    `json:"amount,omitempty"`
+	MaxReward        float64    `json:"max_reward,omitempty"`
+	MinSpend         float64    `json:"min_spend"`
+	MerchantID       *uint      `json:"merchant_id,omitempty"`
+	MerchantCategory string     `json:"merchant_category,omitempty"`
+	MaxPerUser       int        `json:"max_per_user"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+}
+
+func newOffer(p *models.Promotion) Offer {
+	return Offer{
+		ID:               p.ID,
+		Name:             p.Name,
+		Description:      p.Description,
+		Type:             p.Type,
+		Rate:             p.Rate,
+		Amount:           p.Amount,
+		MaxReward:        p.MaxReward,
+		MinSpend:         p.MinSpend,
+		MerchantID:       p.MerchantID,
+		MerchantCategory: p.MerchantCategory,
+		MaxPerUser:       p.MaxPerUser,
+		EndsAt:           p.EndsAt,
+	}
+}
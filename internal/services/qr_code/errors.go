@@ -12,4 +12,10 @@ var (
 	ErrQRLimitExceeded   = errors.New("QR code usage limit exceeded")
 	ErrInvalidAmount     = errors.New("invalid amount")
 	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrInvalidQRStatus   = errors.New("status must be active, revoked or expired")
+	ErrQRNotRegenerable  = errors.New("only receive, payment and poster codes can be regenerated")
+	ErrQRDailyLimit      = errors.New("payment exceeds the QR code's daily limit")
+	ErrQRMonthlyLimit    = errors.New("payment exceeds the QR code's monthly limit")
+	ErrNotPoster         = errors.New("QR code is not a merchant poster")
+	ErrTerminalNotFound  = errors.New("terminal not found")
 )
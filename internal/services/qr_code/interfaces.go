@@ -30,8 +30,30 @@ type Service interface {
 	// New method
 	ValidateQRCode(ctx context.Context, code string, amount float64) (uint, error)
 
-	// Additional method
-	GetUserQRCodes(ctx context.Context, userID uint) ([]*models.QRCode, error)
+	// Lifecycle methods
+	// GetUserQRCodes lists the user's codes with their usage, optionally
+	// only those in one status
+	GetUserQRCodes(ctx context.Context, userID uint, status string) ([]*models.QRCode, error)
+	// RevokeQRCode stops one of the user's codes being accepted
+	RevokeQRCode(ctx context.Context, userID, qrID uint, reason string) (*models.QRCode, error)
+	// ExpireQRCode expires any user's active code, for admins
+	ExpireQRCode(ctx context.Context, qrID uint) (*models.QRCode, error)
+	// RegenerateQRCode replaces one of the user's static codes or posters
+	// with a new code of the same kind, revoking the old one
+	RegenerateQRCode(ctx context.Context, userID, qrID uint) (*models.QRCode, error)
+	// GetQRCodeStats reports one of the user's codes' usage and limits
+	GetQRCodeStats(ctx context.Context, userID, qrID uint) (*QRStats, error)
+	// Merchant posters
+	// CreatePoster makes a printable code for one of the merchant's tills
+	// or locations
+	CreatePoster(ctx context.Context, merchantUserID uint, req PosterRequest) (*models.QRCode, error)
+	// ListPosters returns the merchant's active posters
+	ListPosters(ctx context.Context, merchantUserID uint) ([]*models.QRCode, error)
+	// RenderPoster lays one of the merchant's posters out as a printable PDF
+	RenderPoster(ctx context.Context, merchantUserID, qrID uint) (*models.QRCode, []byte, error)
+
+	// ExpireStaleQRCodes expires dynamic codes past their date or out of uses
+	ExpireStaleQRCodes(ctx context.Context) (int64, error)
 }
 
 // GenerateQRRequest encapsulates parameters for QR generation
@@ -0,0 +1,24 @@
+package qr_code
+
+import (
+	"context"
+	"log"
+)
+
+// Job expires dynamic QR codes past their date or out of uses
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the QR code service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "qr-code-expiry" }
+
+func (j *Job) Run(ctx context.Context) error {
+	expired, err := j.service.ExpireStaleQRCodes(ctx)
+	if expired > 0 {
+		log.Printf("Expired %d stale QR codes", expired)
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package qr_code
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/resilience"
+	"time"
+)
+
+// regenerateReason is recorded on codes revoked by RegenerateQRCode
+const regenerateReason = "regenerated"
+
+func (s *service) RevokeQRCode(ctx context.Context, userID, qrID uint, reason string) (_ *models.QRCode, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR code revocation", s.timeout)
+	defer finish(&err)
+
+	qr, err := s.activeUserQR(ctx, userID, qrID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Revoke(ctx, qr, reason); err != nil {
+		return nil, inactiveIfGone(err)
+	}
+	return qr, nil
+}
+
+func (s *service) ExpireQRCode(ctx context.Context, qrID uint) (_ *models.QRCode, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR code expiry", s.timeout)
+	defer finish(&err)
+
+	qr, err := s.repo.GetByID(ctx, qrID)
+	if err != nil {
+		return nil, err
+	}
+	if qr.Status != models.QRStatusActive {
+		return nil, ErrQRInactive
+	}
+	if err := s.repo.Expire(ctx, qr); err != nil {
+		return nil, inactiveIfGone(err)
+	}
+	return qr, nil
+}
+
+func (s *service) RegenerateQRCode(ctx context.Context, userID, qrID uint) (_ *models.QRCode, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR code regeneration", s.timeout)
+	defer finish(&err)
+
+	old, err := s.activeUserQR(ctx, userID, qrID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var replacement *models.QRCode
+	switch QRType(old.Type) {
+	case TypeReceive:
+		replacement = newReceiveQR(user)
+	case TypePaymentCode:
+		replacement = newPaymentCodeQR(user)
+	case TypePoster:
+		replacement = newPosterQR(user, posterRequest(old))
+	default:
+		return nil, ErrQRNotRegenerable
+	}
+
+	if err := s.repo.Replace(ctx, old, replacement, regenerateReason); err != nil {
+		return nil, inactiveIfGone(err)
+	}
+	return replacement, nil
+}
+
+func (s *service) ExpireStaleQRCodes(ctx context.Context) (int64, error) {
+	return s.repo.ExpireStale(ctx, time.Now())
+}
+
+// activeUserQR loads one of the user's codes, which must still be active
+func (s *service) activeUserQR(ctx context.Context, userID, qrID uint) (*models.QRCode, error) {
+	qr, err := s.repo.GetByIDAndUserID(ctx, qrID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if qr.Status != models.QRStatusActive {
+		return nil, ErrQRInactive
+	}
+	return qr, nil
+}
+
+// inactiveIfGone reports a code that stopped being active while it was
+// being revoked as inactive rather than missing
+func inactiveIfGone(err error) error {
+	if errors.Is(err, repositories.ErrQRCodeNotFound) {
+		return ErrQRInactive
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package qr_code
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"time"
+)
+
+// QRStats reports how a QR code has been used, overall and against its
+// limits. Days and months are UTC.
+type QRStats struct {
+	QRCodeID   uint       `json:"qr_code_id"`
+	Status     string     `json:"status"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	repositories.QRUsage
+	Today     repositories.QRUsage `json:"today"`
+	ThisMonth repositories.QRUsage `json:"this_month"`
+
+	DailyLimit   *float64 `json:"daily_limit,omitempty"`
+	MonthlyLimit *float64 `json:"monthly_limit,omitempty"`
+}
+
+func (s *service) GetQRCodeStats(ctx context.Context, userID, qrID uint) (*QRStats, error) {
+	qr, err := s.repo.GetByIDAndUserID(ctx, qrID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	day, month := periodStarts(time.Now())
+	total, err := s.repo.Usage(ctx, qr.ID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	today, err := s.repo.Usage(ctx, qr.ID, day)
+	if err != nil {
+		return nil, err
+	}
+	thisMonth, err := s.repo.Usage(ctx, qr.ID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QRStats{
+		QRCodeID:     qr.ID,
+		Status:       qr.Status,
+		LastUsedAt:   qr.LastUsedAt,
+		QRUsage:      *total,
+		Today:        *today,
+		ThisMonth:    *thisMonth,
+		DailyLimit:   qr.DailyLimit,
+		MonthlyLimit: qr.MonthlyLimit,
+	}, nil
+}
+
+// spendBudget is one of a code's limits and the period it resets after
+type spendBudget struct {
+	cache.Budget
+	since time.Time
+	err   error
+}
+
+// reserveLimits counts amount against the code's daily and monthly limits
+// before the payment is made, so concurrent payments can't overshoot them
+// together. Call release if the payment then fails. Counters live in Redis
+// and are seeded from the recorded payments; with Redis down the limits
+// are checked against the database alone.
+func (s *service) reserveLimits(ctx context.Context, qr *models.QRCode, amount float64) (release func(), err error) {
+	budgets := s.spendBudgets(qr, time.Now())
+	if len(budgets) == 0 {
+		return func() {}, nil
+	}
+	cents := toCents(amount)
+	counters := make([]cache.Budget, len(budgets))
+	for i, b := range budgets {
+		counters[i] = b.Budget
+	}
+
+	over, err := s.cache.Reserve(ctx, cents, counters...)
+	if errors.Is(err, cache.ErrBudgetUnseeded) {
+		if err = s.seedBudgets(ctx, qr.ID, budgets); err == nil {
+			over, err = s.cache.Reserve(ctx, cents, counters...)
+		}
+	}
+	if err != nil {
+		log.Printf("QR limit counters unavailable for code %d, checking the database: %v", qr.ID, err)
+		return func() {}, s.checkLimits(ctx, qr.ID, budgets, cents)
+	}
+	if over >= 0 {
+		return nil, budgets[over].err
+	}
+
+	return func() {
+		if err := s.cache.Release(context.WithoutCancel(ctx), cents, counters...); err != nil {
+			log.Printf("Failed to release QR limit reservation for code %d: %v", qr.ID, err)
+		}
+	}, nil
+}
+
+func (s *service) spendBudgets(qr *models.QRCode, now time.Time) []spendBudget {
+	day, month := periodStarts(now)
+	var budgets []spendBudget
+	if qr.DailyLimit != nil && *qr.DailyLimit > 0 {
+		budgets = append(budgets, spendBudget{
+			Budget: cache.Budget{
+				Key:   fmt.Sprintf("qr:spend:%d:day:%s", qr.ID, day.Format("2006-01-02")),
+				Limit: toCents(*qr.DailyLimit),
+				TTL:   48 * time.Hour,
+			},
+			since: day,
+			err:   ErrQRDailyLimit,
+		})
+	}
+	if qr.MonthlyLimit != nil && *qr.MonthlyLimit > 0 {
+		budgets = append(budgets, spendBudget{
+			Budget: cache.Budget{
+				Key:   fmt.Sprintf("qr:spend:%d:month:%s", qr.ID, month.Format("2006-01")),
+				Limit: toCents(*qr.MonthlyLimit),
+				TTL:   32 * 24 * time.Hour,
+			},
+			since: month,
+			err:   ErrQRMonthlyLimit,
+		})
+	}
+	return budgets
+}
+
+// seedBudgets starts missing counters at what the database has recorded
+func (s *service) seedBudgets(ctx context.Context, qrID uint, budgets []spendBudget) error {
+	for _, b := range budgets {
+		usage, err := s.repo.Usage(ctx, qrID, b.since)
+		if err != nil {
+			return err
+		}
+		if err := s.cache.SeedBudget(ctx, b.Budget, toCents(usage.Volume)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLimits checks the limits against recorded payments only. Payments
+// in flight aren't seen, so it is a fallback for when Redis is down.
+func (s *service) checkLimits(ctx context.Context, qrID uint, budgets []spendBudget, cents int64) error {
+	for _, b := range budgets {
+		usage, err := s.repo.Usage(ctx, qrID, b.since)
+		if err != nil {
+			return err
+		}
+		if toCents(usage.Volume)+cents > b.Limit {
+			return b.err
+		}
+	}
+	return nil
+}
+
+// periodStarts returns the start of now's UTC day and month
+func periodStarts(now time.Time) (day, month time.Time) {
+	now = now.UTC()
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return day, month
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,161 @@
+package qr_code
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	domainQR "orus/internal/domain/qr"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/resilience"
+	"orus/internal/utils"
+	"orus/internal/utils/pdf"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PosterRequest describes a printable QR code for one of a merchant's
+// tills or locations
+type PosterRequest struct {
+	Label         string    `json:"label" validate:"required,max=40"`
+	Location      string    `json:"location" validate:"max=80"`
+	TerminalID    *uint     `json:"terminal_id,omitempty"`
+	PresetAmounts []float64 `json:"preset_amounts" validate:"max=6,dive,gt=0"`
+	Reference     string    `json:"reference" validate:"max=40"`
+}
+
+func (s *service) CreatePoster(ctx context.Context, merchantUserID uint, req PosterRequest) (_ *models.QRCode, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR poster creation", s.timeout)
+	defer finish(&err)
+
+	user, err := s.users.GetByID(merchantUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if req.TerminalID != nil {
+		terminal, err := s.terminals.GetByID(*req.TerminalID)
+		if errors.Is(err, repositories.ErrTerminalNotFound) {
+			return nil, ErrTerminalNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if terminal.MerchantUserID != merchantUserID || terminal.Status == models.TerminalStatusDeactivated {
+			return nil, ErrTerminalNotFound
+		}
+	}
+
+	qr := newPosterQR(user, req)
+	if err := s.repo.Create(ctx, qr); err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
+func (s *service) ListPosters(ctx context.Context, merchantUserID uint) ([]*models.QRCode, error) {
+	codes, err := s.repo.GetQRCodesByUserID(ctx, merchantUserID, models.QRStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	posters := make([]*models.QRCode, 0, len(codes))
+	for _, qr := range codes {
+		if qr.Type == string(TypePoster) {
+			posters = append(posters, qr)
+		}
+	}
+	return posters, nil
+}
+
+func (s *service) RenderPoster(ctx context.Context, merchantUserID, qrID uint) (*models.QRCode, []byte, error) {
+	qr, err := s.activeUserQR(ctx, merchantUserID, qrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if qr.Type != string(TypePoster) {
+		return nil, nil, ErrNotPoster
+	}
+
+	code, err := qrcode.New(qr.Code, qrcode.Medium)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return qr, pdf.Poster(posterHeading(qr), code.Bitmap(), posterCaption(qr)), nil
+}
+
+// newPosterQR builds a static code a merchant prints for a till or location.
+// Payers scan it like a receive code.
+func newPosterQR(user *models.User, req PosterRequest) *models.QRCode {
+	limits := DefaultLimits[domainQR.UserTypeMerchant]
+	return &models.QRCode{
+		UserID:        user.ID,
+		Code:          utils.MustGenerateSecureCode(),
+		Type:          string(TypePoster),
+		Status:        models.QRStatusActive,
+		MaxUses:       limits.MaxUses,
+		DailyLimit:    &limits.DailyLimit,
+		MonthlyLimit:  &limits.MonthlyLimit,
+		UserType:      user.Role,
+		Label:         strings.TrimSpace(req.Label),
+		Location:      strings.TrimSpace(req.Location),
+		TerminalID:    req.TerminalID,
+		PresetAmounts: req.PresetAmounts,
+		Reference:     strings.TrimSpace(req.Reference),
+		Metadata: models.NewJSON(map[string]interface{}{
+			"qr_type":   "poster",
+			"user_id":   user.ID,
+			"user_type": user.Role,
+			"user_role": user.Role,
+		}),
+	}
+}
+
+// posterRequest recovers the request a poster was made from, so it can be
+// regenerated as it was
+func posterRequest(qr *models.QRCode) PosterRequest {
+	return PosterRequest{
+		Label:         qr.Label,
+		Location:      qr.Location,
+		TerminalID:    qr.TerminalID,
+		PresetAmounts: qr.PresetAmounts,
+		Reference:     qr.Reference,
+	}
+}
+
+func posterHeading(qr *models.QRCode) []string {
+	heading := []string{qr.Label}
+	if qr.Location != "" {
+		heading = append(heading, qr.Location)
+	}
+	return heading
+}
+
+func posterCaption(qr *models.QRCode) []string {
+	caption := []string{"Scan to pay with Orus"}
+	if len(qr.PresetAmounts) > 0 {
+		amounts := make([]string, len(qr.PresetAmounts))
+		for i, amount := range qr.PresetAmounts {
+			amounts[i] = pdf.FormatNumber(amount)
+		}
+		caption = append(caption, "", "Amounts: "+strings.Join(amounts, "  "))
+	}
+	if qr.Reference != "" {
+		caption = append(caption, "Ref: "+qr.Reference)
+	}
+	return caption
+}
+
+// posterMetadata attributes a payment made with a poster to its location
+func posterMetadata(qr *models.QRCode, metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["qr_label"] = qr.Label
+	if qr.Location != "" {
+		metadata["qr_location"] = qr.Location
+	}
+	if qr.Reference != "" {
+		metadata["reference"] = qr.Reference
+	}
+	return metadata
+}
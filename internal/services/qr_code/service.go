@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	domainQR "orus/internal/domain/qr"
 	appErrors "orus/internal/errors"
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
 	"orus/internal/services/transaction"
 	"orus/internal/services/wallet"
 	"orus/internal/utils"
@@ -18,36 +20,58 @@ import (
 )
 
 type service struct {
-	db             *gorm.DB
 	repo           repositories.QRCodeRepository
+	users          repositories.UserRepository
+	merchants      repositories.MerchantRepository
+	terminals      repositories.TerminalRepository
 	cache          *cache.CacheService
 	transactionSvc transaction.Service
 	walletSvc      wallet.Service
+	timeout        time.Duration
 }
 
 func NewService(
-	db *gorm.DB,
 	repo repositories.QRCodeRepository,
+	users repositories.UserRepository,
+	merchants repositories.MerchantRepository,
+	terminals repositories.TerminalRepository,
 	cache *cache.CacheService,
 	txSvc transaction.Service,
 	walletSvc wallet.Service,
+	timeout time.Duration,
 ) Service {
 	return &service{
-		db:             db,
 		repo:           repo,
+		users:          users,
+		merchants:      merchants,
+		terminals:      terminals,
 		cache:          cache,
 		transactionSvc: txSvc,
 		walletSvc:      walletSvc,
+		timeout:        timeout,
 	}
 }
 
-func (s *service) GetUserReceiveQR(ctx context.Context, userID uint) (*models.QRCode, error) {
+func (s *service) GetUserReceiveQR(ctx context.Context, userID uint) (_ *models.QRCode, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR code creation", s.timeout)
+	defer finish(&err)
+
 	// Get user type first
-	user, err := repositories.GetUserByID(userID)
+	user, err := s.users.GetByID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	qr := newReceiveQR(user)
+	if err := s.repo.Create(ctx, qr); err != nil {
+		return nil, err
+	}
+
+	return qr, nil
+}
+
+// newReceiveQR builds a static code others scan to pay the user
+func newReceiveQR(user *models.User) *models.QRCode {
 	// Get limits based on user type
 	var limits QRLimits
 	if user.Role == "merchant" {
@@ -56,8 +80,8 @@ func (s *service) GetUserReceiveQR(ctx context.Context, userID uint) (*models.QR
 		limits = DefaultLimits[domainQR.UserTypeRegular]
 	}
 
-	qr := &models.QRCode{
-		UserID:       userID,
+	return &models.QRCode{
+		UserID:       user.ID,
 		Code:         utils.MustGenerateSecureCode(),
 		Type:         string(TypeReceive),
 		Status:       "active",
@@ -68,28 +92,35 @@ func (s *service) GetUserReceiveQR(ctx context.Context, userID uint) (*models.QR
 		UserType:     user.Role, // Set the user type
 		Metadata: models.NewJSON(map[string]interface{}{
 			"qr_type":   "receive",
-			"user_id":   userID,
+			"user_id":   user.ID,
 			"user_type": user.Role,
 			"user_role": user.Role,
 		}),
 	}
-
-	if err := s.db.Create(qr).Error; err != nil {
-		return nil, fmt.Errorf("failed to create QR code: %w", err)
-	}
-
-	return qr, nil
 }
 
-func (s *service) GetUserPaymentCodeQR(ctx context.Context, userID uint) (*models.QRCode, error) {
+func (s *service) GetUserPaymentCodeQR(ctx context.Context, userID uint) (_ *models.QRCode, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR code creation", s.timeout)
+	defer finish(&err)
+
 	// Get user type first
-	user, err := repositories.GetUserByID(userID)
+	user, err := s.users.GetByID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	qr := &models.QRCode{
-		UserID:    userID,
+	qr := newPaymentCodeQR(user)
+	if err := s.repo.Create(ctx, qr); err != nil {
+		return nil, err
+	}
+
+	return qr, nil
+}
+
+// newPaymentCodeQR builds a static code the user shows merchants to pay
+func newPaymentCodeQR(user *models.User) *models.QRCode {
+	return &models.QRCode{
+		UserID:    user.ID,
 		Code:      utils.MustGenerateSecureCode(),
 		Type:      string(TypePaymentCode),
 		Status:    "active",
@@ -98,23 +129,20 @@ func (s *service) GetUserPaymentCodeQR(ctx context.Context, userID uint) (*model
 		UserType:  user.Role, // Set the user type
 		Metadata: models.NewJSON(map[string]interface{}{
 			"qr_type":   "payment_code",
-			"user_id":   userID,
+			"user_id":   user.ID,
 			"user_type": user.Role,
 			"user_role": user.Role,
 		}),
 	}
-
-	if err := s.db.Create(qr).Error; err != nil {
-		return nil, fmt.Errorf("failed to create QR code: %w", err)
-	}
-
-	return qr, nil
 }
 
-func (s *service) ProcessQRPayment(ctx context.Context, code string, amount float64, scannerID uint, description string, metadata map[string]interface{}) (*models.Transaction, error) {
+func (s *service) ProcessQRPayment(ctx context.Context, code string, amount float64, scannerID uint, description string, metadata map[string]interface{}) (_ *models.Transaction, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR payment", s.timeout)
+	defer finish(&err)
+
 	// Get QR code from database
-	var qr models.QRCode
-	if err := s.db.Where("code = ? AND status = ?", code, "active").First(&qr).Error; err != nil {
+	qr, err := s.repo.GetActiveByCode(ctx, code)
+	if err != nil {
 		return nil, fmt.Errorf("invalid or expired QR code: %w", err)
 	}
 
@@ -122,6 +150,9 @@ func (s *service) ProcessQRPayment(ctx context.Context, code string, amount floa
 	if qr.ExpiresAt != nil && qr.ExpiresAt.Before(time.Now()) {
 		return nil, appErrors.ErrQRExpired
 	}
+	if qr.MaxUses > 0 && qr.UsageCount >= qr.MaxUses {
+		return nil, appErrors.ErrQRLimitExceeded
+	}
 
 	// Check scanner role and QR type validity
 	isMerchant := false
@@ -136,11 +167,14 @@ func (s *service) ProcessQRPayment(ctx context.Context, code string, amount floa
 			return nil, fmt.Errorf("merchants can only scan customer payment code QRs")
 		}
 	} else {
-		// Regular users should scan receive QR codes
-		if qr.Type != string(TypeReceive) {
+		// Regular users should scan receive QR codes or merchant posters
+		if qr.Type != string(TypeReceive) && qr.Type != string(TypePoster) {
 			return nil, fmt.Errorf("users can only scan receive QRs")
 		}
 	}
+	if qr.Type == string(TypePoster) {
+		metadata = posterMetadata(qr, metadata)
+	}
 
 	// Create transaction record
 	tx := &models.Transaction{
@@ -156,17 +190,54 @@ func (s *service) ProcessQRPayment(ctx context.Context, code string, amount floa
 		PaymentMethod: "wallet",
 		Category:      "Payment",
 		MerchantID:    getMerchantID(isMerchant, scannerID),
+		QRCodeID:      &qr.Code,
+		TerminalID:    qr.TerminalID,
 		Metadata:      models.NewJSON(metadata),
 	}
 
+	// Payments to a merchant carry the merchant's processing fee
+	merchant, err := s.merchants.GetByUserID(tx.ReceiverID)
+	switch {
+	case err == nil:
+		tx.Fee = merchant.EffectiveFee().Calculate(amount)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to look up merchant: %w", err)
+	}
+
+	release, err := s.reserveLimits(ctx, qr, amount)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use transaction service to handle the entire operation
-	return s.transactionSvc.ProcessTransaction(ctx, tx)
+	tx, err = s.transactionSvc.ProcessTransaction(ctx, tx)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// The payment is committed, so its use counts even past the deadline
+	now := time.Now()
+	use := &models.QRTransaction{
+		QRCodeID:      qr.ID,
+		TransactionID: tx.ID,
+		CustomerID:    tx.SenderID,
+		Amount:        amount,
+		Status:        models.QRTransactionCompleted,
+		CompletedAt:   &now,
+	}
+	if err := s.repo.RecordUse(context.WithoutCancel(ctx), use); err != nil {
+		log.Printf("Failed to record use of QR code %d: %v", qr.ID, err)
+	}
+	return tx, nil
 }
 
-func (s *service) ValidateQRCode(ctx context.Context, code string, amount float64) (uint, error) {
+func (s *service) ValidateQRCode(ctx context.Context, code string, amount float64) (_ uint, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "QR code validation", s.timeout)
+	defer finish(&err)
+
 	// Get QR code from database
-	var qrCode models.QRCode
-	err := s.db.Where("code = ? AND status = ?", code, "active").First(&qrCode).Error
+	qrCode, err := s.repo.GetActiveByCode(ctx, code)
 	if err != nil {
 		return 0, fmt.Errorf("invalid QR code: %w", err)
 	}
@@ -208,6 +279,11 @@ func getReceiverID(isMerchant bool, qrUserID, scannerID uint) uint {
 	return qrUserID
 }
 
-func (s *service) GetUserQRCodes(ctx context.Context, userID uint) ([]*models.QRCode, error) {
-	return s.repo.GetQRCodesByUserID(ctx, userID)
+func (s *service) GetUserQRCodes(ctx context.Context, userID uint, status string) ([]*models.QRCode, error) {
+	switch status {
+	case "", models.QRStatusActive, models.QRStatusRevoked, models.QRStatusExpired:
+	default:
+		return nil, ErrInvalidQRStatus
+	}
+	return s.repo.GetQRCodesByUserID(ctx, userID, status)
 }
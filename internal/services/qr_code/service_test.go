@@ -0,0 +1,312 @@
+package qr_code
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "orus/internal/errors"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/transaction"
+
+	"gorm.io/gorm"
+)
+
+type fakeQRCodes struct {
+	repositories.QRCodeRepository
+	codes map[string]*models.QRCode
+	uses  []*models.QRTransaction
+}
+
+func (f *fakeQRCodes) GetActiveByCode(ctx context.Context, code string) (*models.QRCode, error) {
+	qr, ok := f.codes[code]
+	if !ok {
+		return nil, repositories.ErrQRCodeNotFound
+	}
+	copied := *qr
+	return &copied, nil
+}
+
+func (f *fakeQRCodes) RecordUse(ctx context.Context, use *models.QRTransaction) error {
+	f.uses = append(f.uses, use)
+	return nil
+}
+
+type fakeMerchants struct {
+	repositories.MerchantRepository
+	byUser map[uint]*models.Merchant
+	err    error
+}
+
+func (f *fakeMerchants) GetByUserID(userID uint) (*models.Merchant, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	merchant, ok := f.byUser[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return merchant, nil
+}
+
+type fakeTransactionService struct {
+	transaction.Service
+	processed []*models.Transaction
+	err       error
+}
+
+func (f *fakeTransactionService) ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	tx.ID = 900
+	f.processed = append(f.processed, tx)
+	return tx, nil
+}
+
+func withID(id uint, qr models.QRCode) *models.QRCode {
+	qr.ID = id
+	return &qr
+}
+
+func metadataOf(t *testing.T, tx *models.Transaction) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(tx.Metadata)
+	if err != nil {
+		t.Fatalf("failed to marshal metadata: %v", err)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		t.Fatalf("failed to unmarshal metadata: %v", err)
+	}
+	return metadata
+}
+
+func TestProcessQRPayment(t *testing.T) {
+	const (
+		customerID = uint(1)
+		merchantID = uint(2)
+		friendID   = uint(3)
+	)
+	terminalID := uint(5)
+	past := time.Now().Add(-time.Hour)
+	errLookup := errors.New("database unavailable")
+	errDeclined := errors.New("declined")
+
+	codes := map[string]*models.QRCode{
+		"receive-merchant": withID(10, models.QRCode{Code: "receive-merchant", UserID: merchantID, Type: string(TypeReceive), Status: "active"}),
+		"receive-friend":   withID(11, models.QRCode{Code: "receive-friend", UserID: friendID, Type: string(TypeReceive), Status: "active"}),
+		"payment-code":     withID(12, models.QRCode{Code: "payment-code", UserID: customerID, Type: string(TypePaymentCode), Status: "active", MaxUses: -1}),
+		"poster": withID(13, models.QRCode{Code: "poster", UserID: merchantID, Type: string(TypePoster), Status: "active",
+			Label: "Table 4", Location: "Terrace", Reference: "T4", TerminalID: &terminalID}),
+		"expired":   withID(14, models.QRCode{Code: "expired", UserID: friendID, Type: string(TypeReceive), Status: "active", ExpiresAt: &past}),
+		"used-up":   withID(15, models.QRCode{Code: "used-up", UserID: friendID, Type: string(TypeReceive), Status: "active", MaxUses: 3, UsageCount: 3}),
+		"unlimited": withID(16, models.QRCode{Code: "unlimited", UserID: friendID, Type: string(TypeReceive), Status: "active", MaxUses: -1, UsageCount: 500}),
+	}
+	merchants := map[uint]*models.Merchant{
+		merchantID: {ID: 20, UserID: merchantID, FeePlan: models.FeePlanStandard},
+	}
+
+	tests := []struct {
+		name         string
+		code         string
+		scanner      uint
+		scannerRole  string
+		metadata     map[string]interface{}
+		merchantErr  error
+		processErr   error
+		wantErr      error
+		wantAnyErr   bool
+		wantType     string
+		wantSender   uint
+		wantReceiver uint
+		wantMerchant *uint
+		wantFee      float64
+		wantTerminal *uint
+		wantMetadata map[string]interface{}
+	}{
+		{
+			name:         "customer pays a merchant's receive code",
+			code:         "receive-merchant",
+			scanner:      customerID,
+			wantType:     "QR_PAYMENT",
+			wantSender:   customerID,
+			wantReceiver: merchantID,
+			wantFee:      1.75,
+		},
+		{
+			name:         "friend's receive code carries no fee",
+			code:         "receive-friend",
+			scanner:      customerID,
+			wantType:     "QR_PAYMENT",
+			wantSender:   customerID,
+			wantReceiver: friendID,
+		},
+		{
+			name:         "unlimited code ignores its usage count",
+			code:         "unlimited",
+			scanner:      customerID,
+			wantType:     "QR_PAYMENT",
+			wantSender:   customerID,
+			wantReceiver: friendID,
+		},
+		{
+			name:         "merchant scans a customer's payment code",
+			code:         "payment-code",
+			scanner:      merchantID,
+			scannerRole:  "merchant",
+			wantType:     "merchant_scan",
+			wantSender:   customerID,
+			wantReceiver: merchantID,
+			wantMerchant: &[]uint{merchantID}[0],
+			wantFee:      1.75,
+		},
+		{
+			name:         "poster payments carry the poster's label, location and reference",
+			code:         "poster",
+			scanner:      customerID,
+			metadata:     map[string]interface{}{"note": "lunch"},
+			wantType:     "QR_PAYMENT",
+			wantSender:   customerID,
+			wantReceiver: merchantID,
+			wantFee:      1.75,
+			wantTerminal: &terminalID,
+			wantMetadata: map[string]interface{}{"note": "lunch", "qr_label": "Table 4", "qr_location": "Terrace", "reference": "T4"},
+		},
+		{
+			name:         "poster without metadata still gets the poster's",
+			code:         "poster",
+			scanner:      customerID,
+			wantType:     "QR_PAYMENT",
+			wantSender:   customerID,
+			wantReceiver: merchantID,
+			wantFee:      1.75,
+			wantTerminal: &terminalID,
+			wantMetadata: map[string]interface{}{"qr_label": "Table 4", "qr_location": "Terrace", "reference": "T4"},
+		},
+		{
+			name:    "unknown code",
+			code:    "missing",
+			scanner: customerID,
+			wantErr: repositories.ErrQRCodeNotFound,
+		},
+		{
+			name:    "expired code",
+			code:    "expired",
+			scanner: customerID,
+			wantErr: appErrors.ErrQRExpired,
+		},
+		{
+			name:    "code out of uses",
+			code:    "used-up",
+			scanner: customerID,
+			wantErr: appErrors.ErrQRLimitExceeded,
+		},
+		{
+			name:        "merchant can't scan a receive code",
+			code:        "receive-friend",
+			scanner:     merchantID,
+			scannerRole: "merchant",
+			wantAnyErr:  true,
+		},
+		{
+			name:       "customer can't scan a payment code",
+			code:       "payment-code",
+			scanner:    friendID,
+			wantAnyErr: true,
+		},
+		{
+			name:        "merchant lookup fails",
+			code:        "receive-merchant",
+			scanner:     customerID,
+			merchantErr: errLookup,
+			wantErr:     errLookup,
+		},
+		{
+			name:       "payment declined",
+			code:       "receive-merchant",
+			scanner:    customerID,
+			processErr: errDeclined,
+			wantErr:    errDeclined,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qrCodes := &fakeQRCodes{codes: codes}
+			transactions := &fakeTransactionService{err: tt.processErr}
+			svc := NewService(qrCodes, nil, &fakeMerchants{byUser: merchants, err: tt.merchantErr}, nil, nil, transactions, nil, time.Second)
+
+			metadata := tt.metadata
+			if tt.scannerRole != "" {
+				if metadata == nil {
+					metadata = map[string]interface{}{}
+				}
+				metadata["scanner_role"] = tt.scannerRole
+			}
+
+			tx, err := svc.ProcessQRPayment(context.Background(), tt.code, 50, tt.scanner, "QR payment", metadata)
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil {
+					t.Fatalf("ProcessQRPayment() succeeded, want an error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ProcessQRPayment() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(qrCodes.uses) != 0 {
+					t.Errorf("failed payment recorded %d uses of the code", len(qrCodes.uses))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessQRPayment() error = %v", err)
+			}
+
+			if tx.Type != tt.wantType {
+				t.Errorf("type = %q, want %q", tx.Type, tt.wantType)
+			}
+			if tx.SenderID != tt.wantSender || tx.ReceiverID != tt.wantReceiver {
+				t.Errorf("transaction = %d -> %d, want %d -> %d", tx.SenderID, tx.ReceiverID, tt.wantSender, tt.wantReceiver)
+			}
+			if (tx.MerchantID == nil) != (tt.wantMerchant == nil) || (tx.MerchantID != nil && *tx.MerchantID != *tt.wantMerchant) {
+				t.Errorf("merchant ID = %v, want %v", tx.MerchantID, tt.wantMerchant)
+			}
+			if tx.Amount != 50 {
+				t.Errorf("amount = %v, want 50", tx.Amount)
+			}
+			if tx.Fee != tt.wantFee {
+				t.Errorf("fee = %v, want %v", tx.Fee, tt.wantFee)
+			}
+			if tx.PaymentType != "qr_scan" || tx.PaymentMethod != "wallet" {
+				t.Errorf("payment = %q by %q, want qr_scan by wallet", tx.PaymentType, tx.PaymentMethod)
+			}
+			if tx.QRCodeID == nil || *tx.QRCodeID != tt.code {
+				t.Errorf("QR code = %v, want %q", tx.QRCodeID, tt.code)
+			}
+			if (tx.TerminalID == nil) != (tt.wantTerminal == nil) || (tx.TerminalID != nil && *tx.TerminalID != *tt.wantTerminal) {
+				t.Errorf("terminal ID = %v, want %v", tx.TerminalID, tt.wantTerminal)
+			}
+			if tx.TransactionID == "" || tx.Reference == "" {
+				t.Errorf("transaction ID %q and reference %q must be set", tx.TransactionID, tx.Reference)
+			}
+
+			got := metadataOf(t, tx)
+			for key, want := range tt.wantMetadata {
+				if got[key] != want {
+					t.Errorf("metadata[%q] = %v, want %v", key, got[key], want)
+				}
+			}
+
+			if len(qrCodes.uses) != 1 {
+				t.Fatalf("recorded %d uses of the code, want 1", len(qrCodes.uses))
+			}
+			use := qrCodes.uses[0]
+			if use.QRCodeID != codes[tt.code].ID || use.TransactionID != tx.ID || use.CustomerID != tt.wantSender || use.Amount != 50 {
+				t.Errorf("use = %+v, want code %d paid by %d in transaction %d", use, codes[tt.code].ID, tt.wantSender, tx.ID)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ const (
 	TypePaymentCode QRType = "payment_code"   // For payments at merchants
 	TypeDynamic     QRType = "dynamic"
 	TypePayment     QRType = "payment"
+	TypePoster      QRType = "merchant_poster" // Merchant's printed code for a till or location
 
 	// User Types
 	UserTypeRegular  UserType = "regular"
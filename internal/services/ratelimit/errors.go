@@ -0,0 +1,10 @@
+package ratelimit
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownClass   = errors.New("class must be reads, writes or payments")
+	ErrInvalidSubject = errors.New("subject must be a user or merchant ID")
+	ErrInvalidLimit   = errors.New("limit must be greater than zero")
+)
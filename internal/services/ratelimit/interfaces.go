@@ -0,0 +1,20 @@
+package ratelimit
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service counts requests of users and merchant API keys against their
+// per-class budgets and lets admins override those budgets
+type Service interface {
+	// Allow counts a request and reports whether it fits the budget.
+	// Requests over budget are counted too, so hammering doesn't pay off.
+	Allow(ctx context.Context, subject Subject, class string) (*Decision, error)
+
+	Settings() *Settings
+	ListOverrides(ctx context.Context, limit, offset int) ([]models.RateLimitOverride, int64, error)
+	// SetOverride creates or replaces the subject's budget for the class
+	SetOverride(ctx context.Context, actorID uint, req OverrideRequest) (*models.RateLimitOverride, error)
+	DeleteOverride(ctx context.Context, id uint) error
+}
@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"slices"
+	"strings"
+	"time"
+)
+
+// overridesTTL bounds how long a subject's overrides are cached; changes
+// drop the entry, so it only matters if that fails
+const overridesTTL = 10 * time.Minute
+
+type service struct {
+	repo   repositories.RateLimitOverrideRepository
+	cache  *cache.CacheService
+	limits Limits
+}
+
+func NewService(repo repositories.RateLimitOverrideRepository, cacheSvc *cache.CacheService, limits Limits) Service {
+	return &service{
+		repo:   repo,
+		cache:  cacheSvc,
+		limits: limits,
+	}
+}
+
+func (s *service) Allow(ctx context.Context, subject Subject, class string) (*Decision, error) {
+	limit := s.limitFor(ctx, subject, class)
+
+	// Windows are aligned to the subject's first request, so clients
+	// don't all reset at the same moment
+	key := fmt.Sprintf("ratelimit:%s:%d:%s", subject.Type, subject.ID, class)
+	count, reset, err := s.cache.Hit(ctx, key, s.limits.Window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count request: %w", err)
+	}
+
+	return &Decision{
+		Allowed:   count <= int64(limit),
+		Limit:     limit,
+		Remaining: max(limit-int(count), 0),
+		Reset:     reset,
+	}, nil
+}
+
+// limitFor returns the subject's budget for the class. Failing to read the
+// overrides falls back to the default rather than rejecting the request.
+func (s *service) limitFor(ctx context.Context, subject Subject, class string) int {
+	var overrides map[string]int
+	err := s.cache.Load(ctx, overridesKey(subject.Type, subject.ID), overridesTTL, &overrides, func() (interface{}, error) {
+		rows, err := s.repo.ListBySubject(subject.Type, subject.ID)
+		if err != nil {
+			return nil, err
+		}
+		byClass := make(map[string]int, len(rows))
+		for _, row := range rows {
+			byClass[row.Class] = row.Limit
+		}
+		return byClass, nil
+	})
+	if err != nil {
+		log.Printf("Failed to load rate limit overrides of %s %d: %v", subject.Type, subject.ID, err)
+	}
+	if limit, ok := overrides[class]; ok {
+		return limit
+	}
+	return s.limits.For(class)
+}
+
+func (s *service) Settings() *Settings {
+	defaults := make(map[string]int, len(models.RateLimitClasses))
+	for _, class := range models.RateLimitClasses {
+		defaults[class] = s.limits.For(class)
+	}
+	return &Settings{
+		WindowSeconds: int64(s.limits.Window / time.Second),
+		Defaults:      defaults,
+	}
+}
+
+func (s *service) ListOverrides(ctx context.Context, limit, offset int) ([]models.RateLimitOverride, int64, error) {
+	return s.repo.List(limit, offset)
+}
+
+func (s *service) SetOverride(ctx context.Context, actorID uint, req OverrideRequest) (*models.RateLimitOverride, error) {
+	req.Class = strings.ToLower(strings.TrimSpace(req.Class))
+	if !slices.Contains(models.RateLimitClasses, req.Class) {
+		return nil, ErrUnknownClass
+	}
+	if req.SubjectID == 0 ||
+		(req.SubjectType != models.RateLimitSubjectUser && req.SubjectType != models.RateLimitSubjectMerchant) {
+		return nil, ErrInvalidSubject
+	}
+	if req.Limit <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	override := &models.RateLimitOverride{
+		SubjectType: req.SubjectType,
+		SubjectID:   req.SubjectID,
+		Class:       req.Class,
+		Limit:       req.Limit,
+		Reason:      strings.TrimSpace(req.Reason),
+		UpdatedBy:   actorID,
+	}
+	if err := s.repo.Save(override); err != nil {
+		return nil, err
+	}
+	s.dropOverrides(ctx, override)
+	return override, nil
+}
+
+func (s *service) DeleteOverride(ctx context.Context, id uint) error {
+	override, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.dropOverrides(ctx, override)
+	return nil
+}
+
+// dropOverrides makes the subject's next request read its overrides afresh
+func (s *service) dropOverrides(ctx context.Context, override *models.RateLimitOverride) {
+	if err := s.cache.Delete(ctx, overridesKey(override.SubjectType, override.SubjectID)); err != nil {
+		log.Printf("Failed to drop cached rate limit overrides of %s %d: %v", override.SubjectType, override.SubjectID, err)
+	}
+}
+
+func overridesKey(subjectType string, subjectID uint) string {
+	return fmt.Sprintf("ratelimit:overrides:%s:%d", subjectType, subjectID)
+}
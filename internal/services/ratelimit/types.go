@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// Limits are the budgets every subject gets unless an admin overrides them
+type Limits struct {
+	Window   time.Duration
+	Reads    int
+	Writes   int
+	Payments int
+}
+
+// For returns the budget of an endpoint class
+func (l Limits) For(class string) int {
+	switch class {
+	case models.RateLimitReads:
+		return l.Reads
+	case models.RateLimitPayments:
+		return l.Payments
+	default:
+		return l.Writes
+	}
+}
+
+// Subject is who a request is counted against
+type Subject struct {
+	Type string
+	ID   uint
+}
+
+// Decision is the outcome of counting one request
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the window restarts
+	Reset time.Duration
+}
+
+// OverrideRequest sets a subject's budget for an endpoint class
+type OverrideRequest struct {
+	SubjectType string `json:"subject_type"`
+	SubjectID   uint   `json:"subject_id"`
+	Class       string `json:"class"`
+	Limit       int    `json:"limit"`
+	Reason      string `json:"reason"`
+}
+
+// Settings shows admins the defaults overrides replace
+type Settings struct {
+	WindowSeconds int64          `json:"window_seconds"`
+	Defaults      map[string]int `json:"defaults"`
+}
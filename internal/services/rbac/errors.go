@@ -0,0 +1,13 @@
+package rbac
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidRoleName = errors.New("role name must be 3-50 lowercase letters, digits or underscores, starting with a letter")
+	ErrRoleExists      = errors.New("role already exists")
+	ErrSystemRole      = errors.New("built-in roles can't be changed")
+	ErrRoleInUse       = errors.New("role is assigned to users")
+	ErrNoPermissions   = errors.New("a role must grant at least one permission")
+	ErrCannotGrant     = errors.New("you can't grant a permission you don't hold")
+)
@@ -0,0 +1,28 @@
+package rbac
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages roles and resolves the permissions they grant. Tokens
+// carry the permissions of the user's role at issuance, so changing a
+// role's permissions ends its users' sessions.
+type Service interface {
+	// EnsureDefaults seeds the known permissions and built-in roles
+	EnsureDefaults(ctx context.Context) error
+
+	// Permissions returns the permissions a role grants. Results are cached.
+	Permissions(ctx context.Context, role string) ([]string, error)
+
+	ListRoles(ctx context.Context) ([]models.Role, error)
+	GetRole(ctx context.Context, name string) (*models.Role, error)
+	// CreateRole adds a custom role. The actor can only grant permissions
+	// they hold themselves.
+	CreateRole(ctx context.Context, actor *models.UserClaims, req CreateRoleRequest) (*models.Role, error)
+	UpdateRole(ctx context.Context, actor *models.UserClaims, name string, req UpdateRoleRequest) (*models.Role, error)
+	// DeleteRole removes a custom role no user holds
+	DeleteRole(ctx context.Context, actor *models.UserClaims, name string) error
+
+	ListPermissions(ctx context.Context) ([]models.Permission, error)
+}
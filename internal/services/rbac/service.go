@@ -0,0 +1,203 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,49}$`)
+
+type service struct {
+	roles       repositories.RoleRepository
+	users       repositories.UserRepository
+	cache       *cache.CacheService
+	invalidator *cache.Invalidator
+}
+
+// NewService creates a new RBAC service
+func NewService(
+	roles repositories.RoleRepository,
+	users repositories.UserRepository,
+	cacheSvc *cache.CacheService,
+	invalidator *cache.Invalidator,
+) Service {
+	return &service{
+		roles:       roles,
+		users:       users,
+		cache:       cacheSvc,
+		invalidator: invalidator,
+	}
+}
+
+func (s *service) EnsureDefaults(ctx context.Context) error {
+	if err := s.roles.EnsureDefaults(); err != nil {
+		return err
+	}
+	// Built-in roles may have been redefined by this release
+	for _, role := range models.SystemRoles {
+		s.forget(ctx, role)
+	}
+	return nil
+}
+
+func (s *service) Permissions(ctx context.Context, role string) ([]string, error) {
+	var permissions []string
+	if found, err := s.cache.Get(ctx, permissionsKey(role), &permissions); err == nil && found {
+		return permissions, nil
+	}
+
+	r, err := s.roles.GetByName(role)
+	if err != nil {
+		return nil, err
+	}
+	permissions = r.PermissionNames()
+
+	if err := s.cache.SetWithTTL(ctx, permissionsKey(role), permissions, permissionsTTL); err != nil {
+		log.Printf("Failed to cache permissions of role %s: %v", role, err)
+	}
+	return permissions, nil
+}
+
+func (s *service) ListRoles(ctx context.Context) ([]models.Role, error) {
+	return s.roles.List()
+}
+
+func (s *service) GetRole(ctx context.Context, name string) (*models.Role, error) {
+	return s.roles.GetByName(name)
+}
+
+func (s *service) CreateRole(ctx context.Context, actor *models.UserClaims, req CreateRoleRequest) (*models.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, ErrInvalidRoleName
+	}
+	permissions, err := s.grantable(actor, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.roles.GetByName(name); err == nil {
+		return nil, ErrRoleExists
+	}
+
+	role := &models.Role{Name: name, Description: strings.TrimSpace(req.Description)}
+	if err := s.roles.Create(role, permissions); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Admin %d created role %s with permissions %v", actor.UserID, name, permissions)
+	return role, nil
+}
+
+func (s *service) UpdateRole(ctx context.Context, actor *models.UserClaims, name string, req UpdateRoleRequest) (*models.Role, error) {
+	role, err := s.roles.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if role.System {
+		return nil, ErrSystemRole
+	}
+
+	if req.Description != nil {
+		role.Description = strings.TrimSpace(*req.Description)
+	}
+	permissions := role.PermissionNames()
+	changed := false
+	if req.Permissions != nil {
+		if permissions, err = s.grantable(actor, req.Permissions); err != nil {
+			return nil, err
+		}
+		current := role.PermissionNames()
+		slices.Sort(current)
+		changed = !slices.Equal(current, permissions)
+	}
+
+	if err := s.roles.Update(role, permissions); err != nil {
+		return nil, err
+	}
+	if changed {
+		s.revoke(ctx, name, fmt.Sprintf("permissions of role %s changed", name))
+	}
+
+	log.Printf("Admin %d updated role %s", actor.UserID, name)
+	return role, nil
+}
+
+func (s *service) DeleteRole(ctx context.Context, actor *models.UserClaims, name string) error {
+	role, err := s.roles.GetByName(name)
+	if err != nil {
+		return err
+	}
+	if role.System {
+		return ErrSystemRole
+	}
+	users, err := s.roles.CountUsers(name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.roles.Delete(role.ID); err != nil {
+		return err
+	}
+	s.forget(ctx, name)
+
+	log.Printf("Admin %d deleted role %s", actor.UserID, name)
+	return nil
+}
+
+func (s *service) ListPermissions(ctx context.Context) ([]models.Permission, error) {
+	return s.roles.ListPermissions()
+}
+
+// grantable validates the permissions a role is to grant, returning them
+// sorted without duplicates
+func (s *service) grantable(actor *models.UserClaims, permissions []string) ([]string, error) {
+	permissions = slices.Clone(permissions)
+	slices.Sort(permissions)
+	permissions = slices.Compact(permissions)
+	if len(permissions) == 0 {
+		return nil, ErrNoPermissions
+	}
+
+	for _, p := range permissions {
+		if !slices.ContainsFunc(models.PermissionDefinitions, func(d models.Permission) bool { return d.Name == p }) {
+			return nil, fmt.Errorf("%w: %s", repositories.ErrUnknownPermission, p)
+		}
+		if actor.Role != "super_admin" && !actor.HasPermission(p) {
+			return nil, fmt.Errorf("%w: %s", ErrCannotGrant, p)
+		}
+	}
+	return permissions, nil
+}
+
+// revoke ends the sessions of a role's users so their next tokens carry
+// its new permissions
+func (s *service) revoke(ctx context.Context, role, reason string) {
+	s.forget(ctx, role)
+	if err := s.users.IncrementRoleTokenVersions(role); err != nil {
+		log.Printf("Failed to revoke sessions of role %s: %v", role, err)
+	}
+	if err := s.invalidator.Enqueue(cache.RoleInvalidationJob(role, reason)); err != nil {
+		log.Printf("Failed to schedule cache invalidation for role %s: %v", role, err)
+	}
+}
+
+// forget drops a role's cached permissions
+func (s *service) forget(ctx context.Context, role string) {
+	if err := s.cache.Delete(ctx, permissionsKey(role)); err != nil {
+		log.Printf("Failed to clear cached permissions of role %s: %v", role, err)
+	}
+}
+
+func permissionsKey(role string) string {
+	return "rbac:role:" + role
+}
@@ -0,0 +1,19 @@
+package rbac
+
+import "time"
+
+// permissionsTTL is how long a role's permissions stay cached
+const permissionsTTL = 10 * time.Minute
+
+// CreateRoleRequest defines a custom role
+type CreateRoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest changes a custom role. Nil fields are left unchanged.
+type UpdateRoleRequest struct {
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
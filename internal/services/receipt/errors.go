@@ -0,0 +1,13 @@
+package receipt
+
+import "errors"
+
+// Service errors
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrNotMerchantPayment  = errors.New("receipts are only available for completed merchant payments")
+	ErrInvalidItem         = errors.New("items need a description, positive quantity and non-negative price")
+	ErrInvalidAmounts      = errors.New("tax and tip must be non-negative and no more than the amount")
+	ErrItemsMismatch       = errors.New("items do not add up to the amount less tax and tip")
+	ErrInvalidEmail        = errors.New("a valid email address is required")
+)
@@ -0,0 +1,28 @@
+package receipt
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Mailer emails receipts to customers
+type Mailer interface {
+	SendReceiptEmail(ctx context.Context, to string, receipt *models.Receipt, pdf []byte) error
+}
+
+// Service issues itemized receipts for merchant payments
+type Service interface {
+	// Validate checks receipt details against the amount about to be charged,
+	// so bad itemization is rejected before money moves
+	Validate(amount float64, details Details) error
+
+	// Issue stores the receipt for a completed merchant payment and emails it
+	// when details name a recipient
+	Issue(ctx context.Context, tx *models.Transaction, details Details) (*models.Receipt, error)
+
+	// GetForTransaction returns the receipt for a payment the user took part
+	// in, issuing a single-line receipt for payments made without one
+	GetForTransaction(ctx context.Context, userID, transactionID uint) (*models.Receipt, error)
+	RenderPDF(ctx context.Context, userID, transactionID uint) (*models.Receipt, []byte, error)
+	Email(ctx context.Context, userID, transactionID uint, to string) (*models.Receipt, error)
+}
@@ -0,0 +1,64 @@
+package receipt
+
+import (
+	"fmt"
+	"orus/internal/i18n"
+	"orus/internal/models"
+	"orus/internal/utils/pdf"
+	"strings"
+)
+
+// renderPDF lays the receipt out as a printable PDF in lang
+func renderPDF(r *models.Receipt, lang string) []byte {
+	return pdf.Render(receiptLines(r, lang))
+}
+
+func receiptLines(r *models.Receipt, lang string) []string {
+	t := func(key string, args ...interface{}) string { return i18n.T(lang, key, args...) }
+	decimals := i18n.CurrencyDecimals(r.Currency)
+	money := func(amount float64) string { return i18n.FormatNumber(lang, amount, decimals) }
+
+	rule := strings.Repeat("-", pdf.LineWidth)
+	lines := []string{
+		t("doc.receipt") + " " + r.Number,
+		"",
+		r.MerchantName,
+	}
+	if r.MerchantAddress != "" {
+		lines = append(lines, pdf.Wrap(r.MerchantAddress, pdf.LineWidth)...)
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("%-10s%s", t("doc.date")+":", i18n.FormatDateTime(lang, r.IssuedAt)),
+		fmt.Sprintf("%-10s%s", t("doc.payment")+":", r.PaymentMethod),
+		fmt.Sprintf("%-10s%d", t("doc.ref")+":", r.TransactionID),
+		"",
+		rule,
+		fmt.Sprintf("%-44s %8s %11s %12s", t("doc.item"), t("doc.qty"), t("doc.unit_price"), t("doc.amount")),
+		rule,
+	)
+
+	for _, item := range r.Items {
+		desc := item.Description
+		if len(desc) > 44 {
+			desc = desc[:41] + "..."
+		}
+		lines = append(lines, fmt.Sprintf("%-44s %8s %11s %12s",
+			desc, pdf.FormatNumber(item.Quantity), money(item.UnitPrice), money(item.Amount)))
+	}
+
+	lines = append(lines,
+		rule,
+		fmt.Sprintf("%65s %12s", t("doc.subtotal"), money(r.Subtotal)),
+		fmt.Sprintf("%65s %12s", t("doc.tax"), money(r.TaxAmount)),
+	)
+	if r.Tip > 0 {
+		lines = append(lines, fmt.Sprintf("%65s %12s", t("doc.tip"), money(r.Tip)))
+	}
+	lines = append(lines, fmt.Sprintf("%65s %12s", t("doc.total")+" "+r.Currency, money(r.Total)))
+	if r.Fee > 0 {
+		lines = append(lines, fmt.Sprintf("%65s %12s", t("doc.processing_fee"), money(r.Fee)))
+	}
+	lines = append(lines, "", t("doc.thanks"))
+	return lines
+}
@@ -0,0 +1,230 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/mail"
+	"orus/internal/i18n"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type service struct {
+	repo      repositories.ReceiptRepository
+	txRepo    repositories.TransactionRepository
+	users     repositories.UserRepository
+	merchants repositories.MerchantRepository
+	mailer    Mailer
+}
+
+// NewService creates a new receipt service
+func NewService(
+	repo repositories.ReceiptRepository,
+	txRepo repositories.TransactionRepository,
+	users repositories.UserRepository,
+	merchants repositories.MerchantRepository,
+	mailer Mailer,
+) Service {
+	return &service{
+		repo:      repo,
+		txRepo:    txRepo,
+		users:     users,
+		merchants: merchants,
+		mailer:    mailer,
+	}
+}
+
+func (s *service) Validate(amount float64, details Details) error {
+	_, _, err := itemize(amount, "", details)
+	return err
+}
+
+// Issue builds and stores the receipt for a merchant payment. Issuing twice
+// for the same transaction returns the existing receipt.
+func (s *service) Issue(ctx context.Context, tx *models.Transaction, details Details) (*models.Receipt, error) {
+	if !isMerchantPayment(tx) {
+		return nil, ErrNotMerchantPayment
+	}
+	if existing, err := s.repo.GetByTransactionID(tx.ID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, repositories.ErrReceiptNotFound) {
+		return nil, err
+	}
+
+	description := tx.Description
+	if description == "" {
+		description = "Payment to " + tx.MerchantName
+	}
+	items, subtotal, err := itemize(tx.Amount, description, details)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &models.Receipt{
+		TransactionID:    tx.ID,
+		Number:           fmt.Sprintf("RCT-%08d", tx.ID),
+		MerchantID:       tx.ReceiverID,
+		CustomerID:       tx.SenderID,
+		MerchantName:     tx.MerchantName,
+		MerchantCategory: tx.MerchantCategory,
+		Currency:         tx.Currency,
+		Subtotal:         subtotal,
+		TaxAmount:        round2(details.TaxAmount),
+		Tip:              round2(details.Tip),
+		Fee:              tx.Fee,
+		Total:            tx.Amount,
+		PaymentMethod:    tx.PaymentMethod,
+		Items:            items,
+		IssuedAt:         tx.ProcessedAt,
+	}
+	if merchant, err := s.merchants.GetByUserID(tx.ReceiverID); err == nil {
+		receipt.MerchantName = merchant.BusinessName
+		receipt.MerchantAddress = merchant.BusinessAddress
+		receipt.MerchantCategory = merchant.BusinessType
+	}
+	if receipt.Currency == "" {
+		receipt.Currency = "USD"
+	}
+	if receipt.PaymentMethod == "" {
+		receipt.PaymentMethod = "wallet"
+	}
+	if receipt.IssuedAt.IsZero() {
+		receipt.IssuedAt = time.Now()
+	}
+
+	if err := s.repo.Create(receipt); err != nil {
+		// Another request may have issued it first
+		if existing, getErr := s.repo.GetByTransactionID(tx.ID); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	if details.EmailTo != "" {
+		if err := s.send(ctx, receipt, details.EmailTo); err != nil {
+			log.Printf("Failed to email receipt %s: %v", receipt.Number, err)
+		}
+	}
+	return receipt, nil
+}
+
+func (s *service) GetForTransaction(ctx context.Context, userID, transactionID uint) (*models.Receipt, error) {
+	tx, err := s.txRepo.FindByID(transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	// Don't reveal other users' transactions
+	if tx.SenderID != userID && tx.ReceiverID != userID {
+		return nil, ErrTransactionNotFound
+	}
+
+	receipt, err := s.repo.GetByTransactionID(tx.ID)
+	if err == nil {
+		return receipt, nil
+	}
+	if !errors.Is(err, repositories.ErrReceiptNotFound) {
+		return nil, err
+	}
+	return s.Issue(ctx, tx, Details{})
+}
+
+func (s *service) RenderPDF(ctx context.Context, userID, transactionID uint) (*models.Receipt, []byte, error) {
+	receipt, err := s.GetForTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipt, renderPDF(receipt, i18n.FromContext(ctx)), nil
+}
+
+// Email sends the receipt to the given address, or to the user's own email
+// when none is given
+func (s *service) Email(ctx context.Context, userID, transactionID uint, to string) (*models.Receipt, error) {
+	receipt, err := s.GetForTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		user, err := s.users.GetByID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		to = user.Email
+	}
+	if err := s.send(ctx, receipt, to); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+func (s *service) send(ctx context.Context, receipt *models.Receipt, to string) error {
+	to = strings.TrimSpace(to)
+	if _, err := mail.ParseAddress(to); err != nil {
+		return ErrInvalidEmail
+	}
+	if err := s.mailer.SendReceiptEmail(ctx, to, receipt, renderPDF(receipt, i18n.FromContext(ctx))); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	receipt.EmailedTo = to
+	receipt.EmailedAt = &now
+	return s.repo.Update(receipt)
+}
+
+func isMerchantPayment(tx *models.Transaction) bool {
+	return tx.Status == "completed" && (tx.MerchantID != nil || tx.Type == "merchant_payment")
+}
+
+// itemize checks details against the charged amount and returns the receipt
+// items and subtotal. Without items the whole subtotal becomes one line.
+func itemize(amount float64, description string, details Details) ([]models.ReceiptItem, float64, error) {
+	if details.TaxAmount < 0 || details.Tip < 0 || details.TaxAmount+details.Tip > amount {
+		return nil, 0, ErrInvalidAmounts
+	}
+	subtotal := round2(amount - details.TaxAmount - details.Tip)
+
+	if len(details.Items) == 0 {
+		return []models.ReceiptItem{{
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   subtotal,
+			Amount:      subtotal,
+		}}, subtotal, nil
+	}
+
+	items := make([]models.ReceiptItem, 0, len(details.Items))
+	sum := 0.0
+	for _, item := range details.Items {
+		desc := strings.TrimSpace(item.Description)
+		if desc == "" || item.Quantity <= 0 || item.UnitPrice < 0 {
+			return nil, 0, ErrInvalidItem
+		}
+		lineAmount := round2(item.Quantity * item.UnitPrice)
+		sum += lineAmount
+		items = append(items, models.ReceiptItem{
+			Description: desc,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      lineAmount,
+		})
+	}
+	if math.Abs(round2(sum)-subtotal) >= 0.01 {
+		return nil, 0, ErrItemsMismatch
+	}
+	return items, subtotal, nil
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,17 @@
+package receipt
+
+// Item is a purchased item to itemize on a receipt
+type Item struct {
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// Details break a payment's amount down into items, tax and tip.
+// Items must add up to the amount less tax and tip.
+type Details struct {
+	Items     []Item  `json:"items,omitempty"`
+	TaxAmount float64 `json:"tax_amount,omitempty"`
+	Tip       float64 `json:"tip,omitempty"`
+	EmailTo   string  `json:"email_to,omitempty"`
+}
@@ -0,0 +1,15 @@
+package refundrequest
+
+import "errors"
+
+// Service errors
+var (
+	ErrChargeNotFound        = errors.New("charge not found")
+	ErrRefundsNotAccepted    = errors.New("this merchant doesn't accept refund requests")
+	ErrWindowClosed          = errors.New("the time to ask for a refund of this charge has passed")
+	ErrAlreadyRequested      = errors.New("a refund of this charge is already in progress")
+	ErrNothingToRefund       = errors.New("this charge has already been refunded in full")
+	ErrExceedsCharge         = errors.New("refund exceeds what is left of the charge")
+	ErrRefundRequestNotFound = errors.New("refund request not found")
+	ErrRefundRequestClosed   = errors.New("refund request is no longer pending")
+)
@@ -0,0 +1,44 @@
+package refundrequest
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/services/merchant"
+)
+
+// Refunder pays a refund from the merchant's wallet
+type Refunder interface {
+	RefundCharge(ctx context.Context, merchantID uint, input merchant.RefundInput) (*models.Transaction, error)
+}
+
+// Notifier tells merchants about the refunds waiting for them, and
+// customers what became of theirs
+type Notifier interface {
+	SendRefundRequested(ctx context.Context, req *models.RefundRequest) error
+	SendRefundRequestDecided(ctx context.Context, req *models.RefundRequest) error
+}
+
+// Service lets customers ask merchants for refunds, which the merchant's
+// policy approves or leaves for the merchant to decide
+type Service interface {
+	// GetPolicy returns the merchant's refund policy
+	GetPolicy(ctx context.Context, merchantUserID uint) (*models.RefundPolicy, error)
+	UpdatePolicy(ctx context.Context, merchantUserID uint, req PolicyRequest) (*models.RefundPolicy, error)
+
+	// Request asks for a refund of the customer's charge. Within the
+	// merchant's auto-approve limit it is refunded straight away, otherwise
+	// it waits for the merchant.
+	Request(ctx context.Context, customerID, transactionID uint, req CreateRequest) (*models.RefundRequest, error)
+	// ListForCustomer pages through the customer's requests
+	ListForCustomer(ctx context.Context, customerID uint, limit, offset int) ([]models.RefundRequest, int64, error)
+	// Cancel withdraws the customer's pending request
+	Cancel(ctx context.Context, customerID, id uint) (*models.RefundRequest, error)
+
+	// ListForMerchant pages through the requests made of the merchant,
+	// optionally only those with status
+	ListForMerchant(ctx context.Context, merchantUserID uint, status string, limit, offset int) ([]models.RefundRequest, int64, error)
+	// Approve pays the refund of one of the merchant's pending requests
+	Approve(ctx context.Context, merchantUserID, id uint, req DecisionRequest) (*models.RefundRequest, error)
+	// Reject turns down one of the merchant's pending requests
+	Reject(ctx context.Context, merchantUserID, id uint, req DecisionRequest) (*models.RefundRequest, error)
+}
@@ -0,0 +1,280 @@
+package refundrequest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/merchant"
+	"orus/internal/services/webhook"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type service struct {
+	repo         repositories.RefundRequestRepository
+	transactions repositories.TransactionRepository
+	refunder     Refunder
+	webhooks     webhook.Service
+	notifier     Notifier
+}
+
+// NewService creates the refund request service
+func NewService(
+	repo repositories.RefundRequestRepository,
+	transactions repositories.TransactionRepository,
+	refunder Refunder,
+	webhooks webhook.Service,
+	notifier Notifier,
+) Service {
+	return &service{
+		repo:         repo,
+		transactions: transactions,
+		refunder:     refunder,
+		webhooks:     webhooks,
+		notifier:     notifier,
+	}
+}
+
+func (s *service) GetPolicy(ctx context.Context, merchantUserID uint) (*models.RefundPolicy, error) {
+	return s.repo.GetPolicy(merchantUserID)
+}
+
+func (s *service) UpdatePolicy(ctx context.Context, merchantUserID uint, req PolicyRequest) (*models.RefundPolicy, error) {
+	policy, err := s.repo.GetPolicy(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if req.WindowDays != nil {
+		policy.WindowDays = *req.WindowDays
+	}
+	if req.AutoApproveLimit != nil {
+		policy.AutoApproveLimit = math.Round(*req.AutoApproveLimit*100) / 100
+	}
+	policy.UpdatedAt = time.Now()
+	if err := s.repo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *service) Request(ctx context.Context, customerID, transactionID uint, input CreateRequest) (*models.RefundRequest, error) {
+	charge, err := s.transactions.FindByID(transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChargeNotFound
+		}
+		return nil, err
+	}
+	if charge.SenderID != customerID || charge.MerchantID == nil || charge.Status != "completed" || charge.Type == models.TransactionTypeRefund {
+		return nil, ErrChargeNotFound
+	}
+	merchantUserID := charge.ReceiverID
+
+	policy, err := s.repo.GetPolicy(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Enabled {
+		return nil, ErrRefundsNotAccepted
+	}
+	if !policy.Open(charge.CreatedAt, time.Now()) {
+		return nil, ErrWindowClosed
+	}
+
+	open, err := s.repo.HasOpen(charge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrAlreadyRequested
+	}
+
+	refunded, err := s.transactions.GetRefundedAmount(merchantUserID, charge.ID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := math.Round((charge.Amount-refunded)*100) / 100
+	if remaining <= 0 {
+		return nil, ErrNothingToRefund
+	}
+	amount := math.Round(input.Amount*100) / 100
+	if input.Amount == 0 {
+		amount = remaining
+	}
+	if amount > remaining {
+		return nil, ErrExceedsCharge
+	}
+
+	req := &models.RefundRequest{
+		TransactionID:  charge.ID,
+		CustomerID:     customerID,
+		MerchantUserID: merchantUserID,
+		MerchantName:   charge.MerchantName,
+		Amount:         amount,
+		Currency:       charge.Currency,
+		Reason:         strings.TrimSpace(input.Reason),
+		Status:         models.RefundRequestPending,
+	}
+	if err := s.repo.Create(req); err != nil {
+		return nil, err
+	}
+	s.emit(ctx, webhook.EventRefundRequestCreated, req)
+
+	if policy.AutoApproves(amount) {
+		err := s.approve(ctx, req, nil, "")
+		if err == nil {
+			return req, nil
+		}
+		// Left for the merchant, who can pay it once they are able to
+		log.Printf("Failed to refund request %d within policy, leaving it to the merchant: %v", req.ID, err)
+	}
+
+	if err := s.notifier.SendRefundRequested(ctx, req); err != nil {
+		log.Printf("Failed to notify merchant %d of refund request %d: %v", merchantUserID, req.ID, err)
+	}
+	return req, nil
+}
+
+func (s *service) ListForCustomer(ctx context.Context, customerID uint, limit, offset int) ([]models.RefundRequest, int64, error) {
+	return s.repo.ListByCustomer(customerID, limit, offset)
+}
+
+func (s *service) Cancel(ctx context.Context, customerID, id uint) (*models.RefundRequest, error) {
+	req, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.CustomerID != customerID {
+		return nil, ErrRefundRequestNotFound
+	}
+	if err := s.decide(req, models.RefundRequestCanceled, nil, ""); err != nil {
+		return nil, err
+	}
+	s.emit(ctx, webhook.EventRefundRequestCanceled, req)
+	return req, nil
+}
+
+func (s *service) ListForMerchant(ctx context.Context, merchantUserID uint, status string, limit, offset int) ([]models.RefundRequest, int64, error) {
+	return s.repo.ListByMerchant(merchantUserID, status, limit, offset)
+}
+
+func (s *service) Approve(ctx context.Context, merchantUserID, id uint, input DecisionRequest) (*models.RefundRequest, error) {
+	req, err := s.forMerchant(merchantUserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.approve(ctx, req, &merchantUserID, strings.TrimSpace(input.Note)); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *service) Reject(ctx context.Context, merchantUserID, id uint, input DecisionRequest) (*models.RefundRequest, error) {
+	req, err := s.forMerchant(merchantUserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decide(req, models.RefundRequestRejected, &merchantUserID, strings.TrimSpace(input.Note)); err != nil {
+		return nil, err
+	}
+	s.emit(ctx, webhook.EventRefundRequestRejected, req)
+	s.notifyCustomer(ctx, req)
+	return req, nil
+}
+
+// approve claims the pending request and pays its refund from the
+// merchant's wallet. A refund that can't be paid returns the request to
+// pending.
+func (s *service) approve(ctx context.Context, req *models.RefundRequest, decidedBy *uint, note string) error {
+	if err := s.decide(req, models.RefundRequestApproved, decidedBy, note); err != nil {
+		return err
+	}
+
+	refund, err := s.refunder.RefundCharge(ctx, req.MerchantUserID, merchant.RefundInput{
+		TransactionID: strconv.FormatUint(uint64(req.TransactionID), 10),
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		if reopenErr := s.repo.Reopen(req.ID); reopenErr != nil {
+			log.Printf("Failed to reopen refund request %d: %v", req.ID, reopenErr)
+		}
+		req.Status = models.RefundRequestPending
+		req.AutoApproved = false
+		req.DecidedBy, req.DecisionNote, req.DecidedAt = nil, "", nil
+		return err
+	}
+
+	now := time.Now()
+	if err := s.repo.Complete(req.ID, refund.ID, now); err != nil {
+		// The refund went through; don't report failure to the merchant
+		log.Printf("Failed to mark refund request %d refunded: %v", req.ID, err)
+	}
+	req.Status = models.RefundRequestRefunded
+	req.RefundTransactionID = &refund.ID
+	req.RefundedAt = &now
+
+	s.emit(ctx, webhook.EventRefundRequestRefunded, req)
+	s.notifyCustomer(ctx, req)
+	return nil
+}
+
+// decide ends the pending request with status
+func (s *service) decide(req *models.RefundRequest, status string, decidedBy *uint, note string) error {
+	if req.Status != models.RefundRequestPending {
+		return ErrRefundRequestClosed
+	}
+	now := time.Now()
+	if err := s.repo.Decide(req.ID, status, decidedBy, note, now); err != nil {
+		if errors.Is(err, repositories.ErrRefundRequestNotPending) {
+			return ErrRefundRequestClosed
+		}
+		return err
+	}
+	req.Status = status
+	req.AutoApproved = status == models.RefundRequestApproved && decidedBy == nil
+	req.DecidedBy = decidedBy
+	req.DecisionNote = note
+	req.DecidedAt = &now
+	return nil
+}
+
+func (s *service) forMerchant(merchantUserID, id uint) (*models.RefundRequest, error) {
+	req, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.MerchantUserID != merchantUserID {
+		return nil, ErrRefundRequestNotFound
+	}
+	return req, nil
+}
+
+func (s *service) get(id uint) (*models.RefundRequest, error) {
+	req, err := s.repo.Get(id)
+	if errors.Is(err, repositories.ErrRefundRequestNotFound) {
+		return nil, ErrRefundRequestNotFound
+	}
+	return req, err
+}
+
+func (s *service) emit(ctx context.Context, event string, req *models.RefundRequest) {
+	if err := s.webhooks.Enqueue(ctx, req.MerchantUserID, event, req); err != nil {
+		log.Printf("Failed to queue %s webhook for refund request %d: %v", event, req.ID, err)
+	}
+}
+
+func (s *service) notifyCustomer(ctx context.Context, req *models.RefundRequest) {
+	if err := s.notifier.SendRefundRequestDecided(ctx, req); err != nil {
+		log.Printf("Failed to notify user %d of refund request %d: %v", req.CustomerID, req.ID, err)
+	}
+}
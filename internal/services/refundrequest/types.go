@@ -0,0 +1,21 @@
+package refundrequest
+
+// CreateRequest asks for a refund of a charge. Amount zero asks for all
+// that is left of it.
+type CreateRequest struct {
+	Amount float64 `json:"amount" validate:"gte=0"`
+	Reason string  `json:"reason" validate:"max=255"`
+}
+
+// PolicyRequest changes the merchant's refund policy; fields left out are
+// kept
+type PolicyRequest struct {
+	Enabled          *bool    `json:"enabled"`
+	WindowDays       *int     `json:"window_days" validate:"omitempty,gte=1,lte=365"`
+	AutoApproveLimit *float64 `json:"auto_approve_limit" validate:"omitempty,gte=0"`
+}
+
+// DecisionRequest carries the merchant's note to the customer
+type DecisionRequest struct {
+	Note string `json:"note" validate:"max=255"`
+}
@@ -0,0 +1,10 @@
+package reserve
+
+import "errors"
+
+// Service errors
+var (
+	ErrMerchantNotFound = errors.New("merchant profile not found")
+	ErrInvalidPercent   = errors.New("reserve percent must be at least 0 and below 1")
+	ErrInvalidHoldDays  = errors.New("reserve hold days must be between 1 and 365")
+)
@@ -0,0 +1,39 @@
+package reserve
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// Stream is the feed of completed transactions and the cursor into it
+type Stream interface {
+	GetCursor(name string) (time.Time, error)
+	SaveCursor(name string, position time.Time) error
+	ListCompletedSince(after time.Time, afterID uint, limit int) ([]models.Transaction, error)
+}
+
+// Service withholds a rolling reserve from the payments merchants receive
+// and releases it on schedule
+type Service interface {
+	// Get returns the merchant's reserve, what is held and when it is
+	// released
+	Get(ctx context.Context, merchantUserID uint) (*Summary, error)
+	// ListEntries pages through the amounts withheld from the merchant's
+	// payments, latest first
+	ListEntries(ctx context.Context, merchantUserID uint, limit, offset int) ([]models.ReserveEntry, int64, error)
+	// Set gives the merchant their own reserve; for admins
+	Set(ctx context.Context, merchantUserID uint, req SetRequest) (*Summary, error)
+
+	// ApplyRisk raises the merchant's reserve while risk monitoring
+	// restricts them with status, and gives back the one it replaced once
+	// they are normal again
+	ApplyRisk(ctx context.Context, merchantUserID uint, status string) error
+
+	// WithholdNew follows the completed payments and withholds the reserve
+	// from those to merchants, returning how many it withheld from
+	WithholdNew(ctx context.Context) (int, error)
+	// ReleaseDue releases the withheld amounts that are due and returns
+	// how many it released
+	ReleaseDue(ctx context.Context) (int, error)
+}
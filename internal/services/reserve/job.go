@@ -0,0 +1,31 @@
+package reserve
+
+import (
+	"context"
+	"log"
+)
+
+// Job withholds and releases merchant reserves from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the reserve service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "merchant-reserve" }
+
+func (j *Job) Run(ctx context.Context) error {
+	withheld, err := j.service.WithholdNew(ctx)
+	if withheld > 0 {
+		log.Printf("Withheld merchant reserve from %d payments", withheld)
+	}
+	if err != nil {
+		return err
+	}
+	released, err := j.service.ReleaseDue(ctx)
+	if released > 0 {
+		log.Printf("Released %d merchant reserve entries", released)
+	}
+	return err
+}
@@ -0,0 +1,276 @@
+package reserve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type service struct {
+	repo      repositories.ReserveRepository
+	stream    Stream
+	merchants repositories.MerchantRepository
+	config    Config
+}
+
+// NewService creates the merchant reserve service
+func NewService(repo repositories.ReserveRepository, stream Stream, merchants repositories.MerchantRepository, config Config) Service {
+	return &service{repo: repo, stream: stream, merchants: merchants, config: config}
+}
+
+func (s *service) Get(ctx context.Context, merchantUserID uint) (*Summary, error) {
+	if err := s.checkMerchant(merchantUserID); err != nil {
+		return nil, err
+	}
+	reserve, err := s.repo.GetReserve(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.summary(merchantUserID, s.effective(merchantUserID, reserve))
+}
+
+func (s *service) ListEntries(ctx context.Context, merchantUserID uint, limit, offset int) ([]models.ReserveEntry, int64, error) {
+	if err := s.checkMerchant(merchantUserID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListEntries(merchantUserID, limit, offset)
+}
+
+func (s *service) Set(ctx context.Context, merchantUserID uint, req SetRequest) (*Summary, error) {
+	if req.Percent < 0 || req.Percent >= 1 {
+		return nil, ErrInvalidPercent
+	}
+	if req.HoldDays < 1 || req.HoldDays > maxHoldDays {
+		return nil, ErrInvalidHoldDays
+	}
+	if err := s.checkMerchant(merchantUserID); err != nil {
+		return nil, err
+	}
+
+	// An admin's reserve replaces one raised by risk monitoring; clearing
+	// the merchant then leaves it in place
+	reserve := &models.MerchantReserve{
+		MerchantUserID: merchantUserID,
+		Percent:        req.Percent,
+		HoldDays:       req.HoldDays,
+		Source:         models.ReserveSourceAdmin,
+		Reason:         req.Reason,
+	}
+	if err := s.repo.SaveReserve(reserve); err != nil {
+		return nil, err
+	}
+	return s.summary(merchantUserID, reserve)
+}
+
+func (s *service) ApplyRisk(ctx context.Context, merchantUserID uint, status string) error {
+	current, err := s.repo.GetReserve(merchantUserID)
+	if err != nil {
+		return err
+	}
+
+	raised, restricted := riskReserves[status]
+	if !restricted {
+		if current == nil || current.Source != models.ReserveSourceRisk {
+			return nil
+		}
+		// Give back the reserve risk monitoring replaced
+		if current.RestoreSource == "" || current.RestoreSource == models.ReserveSourceDefault {
+			return s.repo.DeleteReserve(merchantUserID)
+		}
+		current.Percent = *current.RestorePercent
+		current.HoldDays = *current.RestoreDays
+		current.Source = current.RestoreSource
+		current.Reason = ""
+		current.RestorePercent, current.RestoreDays, current.RestoreSource = nil, nil, ""
+		return s.repo.SaveReserve(current)
+	}
+
+	effective := s.effective(merchantUserID, current)
+	if effective.Percent >= raised.Percent && effective.HoldDays >= raised.HoldDays {
+		return nil
+	}
+	if effective.Source != models.ReserveSourceRisk {
+		percent, days := effective.Percent, effective.HoldDays
+		effective.RestorePercent, effective.RestoreDays, effective.RestoreSource = &percent, &days, effective.Source
+	}
+	effective.Percent = math.Max(effective.Percent, raised.Percent)
+	if raised.HoldDays > effective.HoldDays {
+		effective.HoldDays = raised.HoldDays
+	}
+	effective.Source = models.ReserveSourceRisk
+	effective.Reason = fmt.Sprintf("risk status %s", status)
+	return s.repo.SaveReserve(effective)
+}
+
+func (s *service) WithholdNew(ctx context.Context) (int, error) {
+	position, err := s.stream.GetCursor(cursorName)
+	if err != nil {
+		return 0, err
+	}
+	// Reserves apply to payments from when the feature is first run on
+	if position.IsZero() {
+		return 0, s.stream.SaveCursor(cursorName, time.Now())
+	}
+
+	after := position.Add(-streamOverlap)
+	var afterID uint
+	withheld := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return withheld, err
+		}
+		batch, err := s.stream.ListCompletedSince(after, afterID, streamBatch)
+		if err != nil {
+			return withheld, err
+		}
+		entries, err := s.entriesFor(batch)
+		if err != nil {
+			return withheld, err
+		}
+		for i := range entries {
+			ok, err := s.repo.Withhold(&entries[i])
+			if err != nil {
+				return withheld, err
+			}
+			if ok {
+				withheld++
+			}
+		}
+		if len(batch) > 0 {
+			last := batch[len(batch)-1]
+			after, afterID = last.UpdatedAt, last.ID
+		}
+
+		// Save progress per batch so a long catch-up resumes where it stopped
+		if after.After(position) {
+			if err := s.stream.SaveCursor(cursorName, after); err != nil {
+				return withheld, err
+			}
+			position = after
+		}
+		if len(batch) < streamBatch {
+			return withheld, nil
+		}
+	}
+}
+
+// entriesFor works out what to withhold from the transactions: a share of
+// each sale to a merchant whose reserve isn't zero
+func (s *service) entriesFor(transactions []models.Transaction) ([]models.ReserveEntry, error) {
+	var receivers []uint
+	for i := range transactions {
+		if isSale(&transactions[i]) {
+			receivers = append(receivers, transactions[i].ReceiverID)
+		}
+	}
+	if len(receivers) == 0 {
+		return nil, nil
+	}
+	merchants, err := s.merchants.GetByUserIDs(receivers)
+	if err != nil {
+		return nil, err
+	}
+	isMerchant := make(map[uint]bool, len(merchants))
+	for _, merchant := range merchants {
+		isMerchant[merchant.UserID] = true
+	}
+	reserves, err := s.repo.GetReservesFor(receivers)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.ReserveEntry
+	for i := range transactions {
+		tx := &transactions[i]
+		if !isSale(tx) || !isMerchant[tx.ReceiverID] {
+			continue
+		}
+		var own *models.MerchantReserve
+		if reserve, ok := reserves[tx.ReceiverID]; ok {
+			own = &reserve
+		}
+		reserve := s.effective(tx.ReceiverID, own)
+		amount := math.Round(tx.Amount*reserve.Percent*100) / 100
+		if amount <= 0 {
+			continue
+		}
+		entries = append(entries, models.ReserveEntry{
+			MerchantUserID: tx.ReceiverID,
+			TransactionID:  tx.ID,
+			Amount:         amount,
+			ReleaseAt:      tx.UpdatedAt.AddDate(0, 0, reserve.HoldDays),
+		})
+	}
+	return entries, nil
+}
+
+func (s *service) ReleaseDue(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return released, err
+		}
+		n, err := s.repo.ReleaseDue(time.Now(), releaseBatch)
+		released += int(n)
+		if err != nil {
+			return released, err
+		}
+		if n < releaseBatch {
+			return released, nil
+		}
+	}
+}
+
+// effective returns the merchant's own reserve, or the default one if
+// they have none
+func (s *service) effective(merchantUserID uint, reserve *models.MerchantReserve) *models.MerchantReserve {
+	if reserve != nil {
+		return reserve
+	}
+	return &models.MerchantReserve{
+		MerchantUserID: merchantUserID,
+		Percent:        s.config.Percent,
+		HoldDays:       s.config.HoldDays,
+		Source:         models.ReserveSourceDefault,
+	}
+}
+
+func (s *service) summary(merchantUserID uint, reserve *models.MerchantReserve) (*Summary, error) {
+	held, err := s.repo.GetHeld(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := s.repo.GetSchedule(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	return &Summary{
+		Percent:  reserve.Percent,
+		HoldDays: reserve.HoldDays,
+		Source:   reserve.Source,
+		Reason:   reserve.Reason,
+		Held:     math.Round(held*100) / 100,
+		Releases: releases,
+	}, nil
+}
+
+func (s *service) checkMerchant(merchantUserID uint) error {
+	_, err := s.merchants.GetByUserID(merchantUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMerchantNotFound
+	}
+	return err
+}
+
+// isSale reports whether the transaction is a payment to someone other
+// than its sender for what they sell
+func isSale(tx *models.Transaction) bool {
+	return tx.ReceiverID != 0 && tx.SenderID != tx.ReceiverID && !nonSales[tx.Type]
+}
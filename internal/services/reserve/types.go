@@ -0,0 +1,71 @@
+package reserve
+
+import (
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+// Config is the reserve of merchants without their own
+type Config struct {
+	Percent  float64
+	HoldDays int
+}
+
+// Summary is a merchant's reserve: its parameters, what is held and when
+// it is released
+type Summary struct {
+	Percent  float64 `json:"percent"` // Fraction of each payment withheld
+	HoldDays int     `json:"hold_days"`
+	Source   string  `json:"source"`
+	Reason   string  `json:"reason,omitempty"`
+	Held     float64 `json:"held"`
+	// Releases is what is still held by the day it is released
+	Releases []repositories.ReserveRelease `json:"releases"`
+}
+
+// SetRequest gives a merchant their own reserve
+type SetRequest struct {
+	Percent  float64 `json:"percent" validate:"gte=0,lt=1"`
+	HoldDays int     `json:"hold_days" validate:"gte=1,lte=365"`
+	Reason   string  `json:"reason" validate:"max=500"`
+}
+
+// riskReserve is the reserve risk monitoring sets while a merchant is
+// restricted; one already higher is kept
+type riskReserve struct {
+	Percent  float64
+	HoldDays int
+}
+
+var riskReserves = map[string]riskReserve{
+	models.MerchantRiskTightened: {Percent: 0.10, HoldDays: 90},
+	models.MerchantRiskReview:    {Percent: 0.25, HoldDays: 120},
+}
+
+// nonSales are transaction types a merchant receives that aren't payments
+// for what they sell; nothing is withheld from them
+var nonSales = map[string]bool{
+	models.TransactionTypeRefund:     true,
+	models.TransactionTypeChargeback: true,
+	models.TransactionTypeReversal:   true,
+	models.TransactionTypePromotion:  true,
+	models.TransactionTypeTopup:      true,
+	"top_up":                         true,
+}
+
+const (
+	// maxHoldDays bounds how long a reserve can hold funds
+	maxHoldDays = 365
+
+	// cursorName is the reserve's position in the transaction stream
+	cursorName = "merchant_reserve"
+	// streamBatch is how many completed transactions are read at a time
+	streamBatch = 500
+	// streamOverlap re-reads the last stretch of the stream on every run,
+	// so payments that committed after a later one was read aren't
+	// missed. Ones already withheld from are skipped.
+	streamOverlap = 2 * time.Minute
+	// releaseBatch is how many entries are released at a time
+	releaseBatch = 200
+)
@@ -0,0 +1,24 @@
+package retention
+
+import (
+	"context"
+	"log"
+)
+
+// Job archives old transactions from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the retention service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "transaction-archival" }
+
+func (j *Job) Run(ctx context.Context) error {
+	moved, err := j.service.ArchiveOld(ctx)
+	if moved > 0 {
+		log.Printf("Archived %d transactions", moved)
+	}
+	return err
+}
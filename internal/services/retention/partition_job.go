@@ -0,0 +1,28 @@
+package retention
+
+import (
+	"context"
+	"log"
+	"orus/internal/repositories"
+)
+
+// PartitionJob creates the coming months' transaction partitions ahead of
+// time, so new transactions never land in the default partition
+type PartitionJob struct {
+	manager repositories.PartitionManager
+}
+
+// NewPartitionJob wraps the partition manager as a scheduled job
+func NewPartitionJob(manager repositories.PartitionManager) *PartitionJob {
+	return &PartitionJob{manager: manager}
+}
+
+func (j *PartitionJob) Name() string { return "transaction-partitions" }
+
+func (j *PartitionJob) Run(ctx context.Context) error {
+	created, err := j.manager.EnsurePartitions(repositories.PartitionMonthsAhead)
+	if created > 0 {
+		log.Printf("Created %d transaction partitions", created)
+	}
+	return err
+}
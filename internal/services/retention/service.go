@@ -0,0 +1,51 @@
+package retention
+
+import (
+	"context"
+	"orus/internal/repositories"
+	"time"
+)
+
+// archiveBatchSize bounds how many transactions one statement moves, so a
+// first run over years of history doesn't hold locks for long
+const archiveBatchSize = 1000
+
+// Service enforces the transaction retention policy
+type Service interface {
+	// ArchiveOld moves settled transactions past the retention age into the
+	// archive and returns how many it moved
+	ArchiveOld(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	repo   repositories.TransactionArchiveRepository
+	maxAge time.Duration
+}
+
+// NewService creates a retention service that archives transactions older
+// than maxAge. A zero maxAge keeps everything hot.
+func NewService(repo repositories.TransactionArchiveRepository, maxAge time.Duration) Service {
+	return &service{repo: repo, maxAge: maxAge}
+}
+
+func (s *service) ArchiveOld(ctx context.Context) (int64, error) {
+	if s.maxAge <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-s.maxAge)
+
+	var moved int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return moved, err
+		}
+		n, err := s.repo.Archive(cutoff, archiveBatchSize)
+		moved += n
+		if err != nil {
+			return moved, err
+		}
+		if n < archiveBatchSize {
+			return moved, nil
+		}
+	}
+}
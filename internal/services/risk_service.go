@@ -1,29 +0,0 @@
-package services
-
-import "orus/internal/models"
-
-// HighRiskThreshold defines the score above which a transaction is considered high risk
-const HighRiskThreshold = 0.8
-
-type RiskService struct{}
-
-func NewRiskService() *RiskService {
-	return &RiskService{}
-}
-
-func (s *RiskService) AssessTransaction(tx *models.Transaction) float64 {
-	// Implement risk scoring logic here
-	var riskScore float64 = 0.0
-
-	// Example risk factors
-	if tx.Amount > 10000 {
-		riskScore += 0.3
-	}
-
-	// Check if transaction is high risk
-	if riskScore > HighRiskThreshold {
-		// Log high risk transaction
-	}
-
-	return riskScore
-}
@@ -0,0 +1,10 @@
+package saga
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownCompensation = errors.New("no compensation is registered under this name")
+	ErrInvalidParam        = errors.New("compensation parameter is missing or invalid")
+	ErrNotAttention        = errors.New("only sagas that require attention can be retried")
+)
@@ -0,0 +1,31 @@
+package saga
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service runs multi-step payments as sagas: each step is recorded, and
+// when one fails the steps before it are undone, last first, by their
+// compensations
+type Service interface {
+	// Register makes a compensation available under name. Compensations
+	// are looked up by name when they run, so every instance registers the
+	// same ones at startup.
+	Register(name string, compensation Compensation)
+	// Execute runs the steps in order and returns the error of the one that
+	// failed. The steps done before it are compensated right away; any
+	// compensation that fails is retried by the job, and once attempts run
+	// out the saga and its transaction require attention.
+	Execute(ctx context.Context, def Definition) error
+
+	List(ctx context.Context, status string, limit, offset int) ([]models.Saga, int64, error)
+	Get(ctx context.Context, id uint) (*models.Saga, error)
+	// Retry compensates a saga that requires attention again, once what
+	// made its compensation fail has been fixed
+	Retry(ctx context.Context, id uint) (*models.Saga, error)
+
+	// CompensateDue retries the compensations that are due and takes over
+	// the sagas abandoned mid-run, returning how many it processed
+	CompensateDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,24 @@
+package saga
+
+import (
+	"context"
+	"log"
+)
+
+// Job retries failed saga compensations from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the saga service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "saga-compensation" }
+
+func (j *Job) Run(ctx context.Context) error {
+	processed, err := j.service.CompensateDue(ctx)
+	if processed > 0 {
+		log.Printf("Compensated %d sagas", processed)
+	}
+	return err
+}
@@ -0,0 +1,201 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"sync"
+	"time"
+)
+
+type service struct {
+	repo repositories.SagaRepository
+
+	mu            sync.RWMutex
+	compensations map[string]Compensation
+}
+
+// NewService creates the saga service
+func NewService(repo repositories.SagaRepository) Service {
+	return &service{repo: repo, compensations: make(map[string]Compensation)}
+}
+
+func (s *service) Register(name string, compensation Compensation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compensations[name] = compensation
+}
+
+func (s *service) Execute(ctx context.Context, def Definition) error {
+	saga := &models.Saga{
+		Name:          def.Name,
+		TransactionID: def.TransactionID,
+		Status:        models.SagaRunning,
+		Steps:         make([]models.SagaStep, len(def.Steps)),
+	}
+	for i, step := range def.Steps {
+		saga.Steps[i] = models.SagaStep{
+			Position:     i,
+			Name:         step.Name,
+			Status:       models.SagaStepPending,
+			Compensation: step.Compensation,
+			Params:       models.NewJSON(step.Params),
+		}
+	}
+	if err := s.repo.Create(saga); err != nil {
+		return err
+	}
+
+	for i, step := range def.Steps {
+		record := &saga.Steps[i]
+		if err := step.Run(ctx); err != nil {
+			record.Status = models.SagaStepFailed
+			record.Error = err.Error()
+			s.saveStep(record)
+
+			saga.Error = fmt.Sprintf("%s: %v", step.Name, err)
+			s.compensate(context.WithoutCancel(ctx), saga)
+			return err
+		}
+
+		completed := time.Now()
+		record.Status = models.SagaStepCompleted
+		record.CompletedAt = &completed
+		s.saveStep(record)
+	}
+
+	saga.Status = models.SagaCompleted
+	if err := s.repo.Save(saga); err != nil {
+		// Every step went through; only the saga's record is behind
+		log.Printf("Failed to complete saga %d: %v", saga.ID, err)
+	}
+	return nil
+}
+
+func (s *service) List(ctx context.Context, status string, limit, offset int) ([]models.Saga, int64, error) {
+	return s.repo.List(status, limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, id uint) (*models.Saga, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *service) Retry(ctx context.Context, id uint) (*models.Saga, error) {
+	saga, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if saga.Status != models.SagaRequiresAttention {
+		return nil, ErrNotAttention
+	}
+
+	saga.Attempts = 0
+	s.compensate(ctx, saga)
+	return saga, nil
+}
+
+func (s *service) CompensateDue(ctx context.Context) (int, error) {
+	processed := 0
+	for processed < batchSize {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		now := time.Now()
+		saga, err := s.repo.Claim(now, now.Add(-staleAfter), now.Add(leaseDuration))
+		if err != nil || saga == nil {
+			return processed, err
+		}
+
+		if saga.Status == models.SagaRunning {
+			log.Printf("Saga %d was abandoned mid-run; compensating it", saga.ID)
+			saga.Error = "abandoned while running"
+		}
+		s.compensate(ctx, saga)
+		processed++
+	}
+	return processed, nil
+}
+
+// compensate undoes the saga's completed steps, last first, and records
+// how far it got: compensated once every step is undone, otherwise
+// compensating until the job retries it, or requires_attention once
+// attempts run out
+func (s *service) compensate(ctx context.Context, saga *models.Saga) {
+	saga.Attempts++
+	err := s.undo(ctx, saga)
+
+	switch {
+	case err == nil:
+		saga.Status = models.SagaCompensated
+		saga.NextAttemptAt = nil
+		s.setTransactionStatus(saga, "failed")
+	case saga.Attempts >= maxAttempts:
+		log.Printf("Saga %d could not be compensated after %d attempts: %v", saga.ID, saga.Attempts, err)
+		saga.Status = models.SagaRequiresAttention
+		saga.NextAttemptAt = nil
+		s.setTransactionStatus(saga, models.TransactionStatusRequiresAttention)
+	default:
+		log.Printf("Saga %d compensation attempt %d failed: %v", saga.ID, saga.Attempts, err)
+		next := time.Now().Add(retryBackoff << (saga.Attempts - 1))
+		saga.Status = models.SagaCompensating
+		saga.NextAttemptAt = &next
+	}
+
+	if err := s.repo.Save(saga); err != nil {
+		log.Printf("Failed to save saga %d: %v", saga.ID, err)
+	}
+}
+
+// undo runs the compensations of the completed steps, last first, and
+// stops at the first that fails
+func (s *service) undo(ctx context.Context, saga *models.Saga) error {
+	for i := len(saga.Steps) - 1; i >= 0; i-- {
+		step := &saga.Steps[i]
+		if step.Status != models.SagaStepCompleted {
+			continue
+		}
+
+		if step.Compensation != "" {
+			if err := s.run(ctx, step); err != nil {
+				step.Error = err.Error()
+				s.saveStep(step)
+				return fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}
+
+		compensated := time.Now()
+		step.Status = models.SagaStepCompensated
+		step.Error = ""
+		step.CompensatedAt = &compensated
+		s.saveStep(step)
+	}
+	return nil
+}
+
+// run calls the step's compensation with the params it recorded
+func (s *service) run(ctx context.Context, step *models.SagaStep) error {
+	s.mu.RLock()
+	compensation, ok := s.compensations[step.Compensation]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCompensation, step.Compensation)
+	}
+	return compensation(ctx, step.Params.Map())
+}
+
+func (s *service) saveStep(step *models.SagaStep) {
+	if err := s.repo.SaveStep(step); err != nil {
+		log.Printf("Failed to save step %d of saga %d: %v", step.Position, step.SagaID, err)
+	}
+}
+
+func (s *service) setTransactionStatus(saga *models.Saga, status string) {
+	if saga.TransactionID == nil {
+		return
+	}
+	if err := s.repo.SetTransactionStatus(*saga.TransactionID, status); err != nil {
+		log.Printf("Failed to mark transaction %d %s: %v", *saga.TransactionID, status, err)
+	}
+}
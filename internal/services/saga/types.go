@@ -0,0 +1,81 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Compensation undoes a completed step from the params it recorded
+type Compensation func(ctx context.Context, params map[string]interface{}) error
+
+// Step is one step of a saga. Compensation names the registered
+// compensation that undoes it, called with Params; leave it empty when
+// the step has nothing to undo.
+type Step struct {
+	Name         string
+	Run          func(ctx context.Context) error
+	Compensation string
+	Params       map[string]interface{}
+}
+
+// Definition describes a saga to execute. TransactionID, when set, is
+// marked failed once the saga is compensated and requires_attention when
+// it can't be.
+type Definition struct {
+	Name          string
+	TransactionID *uint
+	Steps         []Step
+}
+
+// maxAttempts bounds how often a saga's compensation runs before it is
+// left for someone to look at
+const maxAttempts = 8
+
+// retryBackoff is the wait after the first failed compensation, doubled
+// after each one after that
+const retryBackoff = time.Minute
+
+// staleAfter is how long a saga may stay running before its instance is
+// presumed gone and the job compensates it
+const staleAfter = 10 * time.Minute
+
+// leaseDuration is how long the job may take compensating a saga before
+// another instance takes it over
+const leaseDuration = 5 * time.Minute
+
+// batchSize bounds the sagas compensated per job run
+const batchSize = 50
+
+// Uint reads an ID recorded in a step's params
+func Uint(params map[string]interface{}, key string) (uint, error) {
+	v, ok := number(params[key])
+	if !ok || v <= 0 || v != math.Trunc(v) {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidParam, key)
+	}
+	return uint(v), nil
+}
+
+// Float reads an amount recorded in a step's params
+func Float(params map[string]interface{}, key string) (float64, error) {
+	v, ok := number(params[key])
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidParam, key)
+	}
+	return v, nil
+}
+
+// number reads a param as it was given to Execute or as it comes back
+// from the database, where every number is a float64
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,17 @@
+package sandbox
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidAmount       = errors.New("amount must be greater than zero")
+	ErrNameRequired        = errors.New("customer name is required")
+	ErrInvalidSource       = errors.New("source must be wallet, card or bank")
+	ErrCustomerRequired    = errors.New("wallet charges need a sandbox customer")
+	ErrCardNumberRequired  = errors.New("card charges need a test card number")
+	ErrUnknownTestCard     = errors.New("card number is not a sandbox test card")
+	ErrUnknownScenario     = errors.New("unknown sandbox failure scenario")
+	ErrChargeNotRefundable = errors.New("only succeeded charges can be refunded")
+	ErrChargeNotPending    = errors.New("only pending charges can be settled")
+	ErrInvalidOutcome      = errors.New("outcome must be succeeded or failed")
+)
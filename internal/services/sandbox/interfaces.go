@@ -0,0 +1,32 @@
+package sandbox
+
+import (
+	"context"
+	"orus/internal/models"
+	"orus/internal/repositories"
+)
+
+// Service runs a merchant's test integration against simulated customers,
+// cards and bank transfers. Every call is scoped to the merchant that
+// authenticated with its sandbox API key.
+type Service interface {
+	CreateCustomer(ctx context.Context, merchant *models.Merchant, req CustomerRequest) (*models.SandboxCustomer, error)
+	GetCustomer(ctx context.Context, merchant *models.Merchant, customerID uint) (*models.SandboxCustomer, error)
+	ListCustomers(ctx context.Context, merchant *models.Merchant, limit, offset int) ([]models.SandboxCustomer, int64, error)
+	FundCustomer(ctx context.Context, merchant *models.Merchant, customerID uint, req FundRequest) (*models.SandboxCustomer, error)
+
+	// CreateCharge takes a payment and sends its charge webhook. Declined
+	// charges are saved as failed rather than returned as errors.
+	CreateCharge(ctx context.Context, merchant *models.Merchant, req ChargeRequest) (*models.SandboxCharge, error)
+	GetCharge(ctx context.Context, merchant *models.Merchant, reference string) (*ChargeView, error)
+	ListCharges(ctx context.Context, merchant *models.Merchant, status string, limit, offset int) ([]models.SandboxCharge, int64, error)
+	// SettleCharge resolves a pending bank charge, as the bank would later
+	SettleCharge(ctx context.Context, merchant *models.Merchant, reference string, req SettleRequest) (*models.SandboxCharge, error)
+	Refund(ctx context.Context, merchant *models.Merchant, reference string, req RefundRequest) (*models.SandboxRefund, error)
+
+	GetBalance(ctx context.Context, merchant *models.Merchant) (*repositories.SandboxBalance, error)
+	// Scenarios lists the test cards and failures integrators can trigger
+	Scenarios() *Scenarios
+	// Reset deletes the merchant's sandbox customers, charges and refunds
+	Reset(ctx context.Context, merchant *models.Merchant) error
+}
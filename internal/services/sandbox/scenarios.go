@@ -0,0 +1,85 @@
+package sandbox
+
+import "orus/internal/models"
+
+// Failure codes a sandbox charge or refund can end with
+const (
+	FailureCardDeclined       = "card_declined"
+	FailureInsufficientFunds  = "insufficient_funds"
+	FailureExpiredCard        = "expired_card"
+	FailureProcessingError    = "processing_error"
+	FailureBankTransferFailed = "bank_transfer_failed"
+	FailureRefundFailed       = "refund_failed"
+)
+
+// Test card numbers. Any other number is rejected.
+const (
+	CardSuccess           = "4242424242424242"
+	CardDeclined          = "4000000000000002"
+	CardInsufficientFunds = "4000000000009995"
+	CardExpired           = "4000000000000069"
+	CardProcessingError   = "4000000000000119"
+	// CardRefundFails charges successfully but every refund of it fails
+	CardRefundFails = "4000000000005126"
+)
+
+var failureMessages = map[string]string{
+	FailureCardDeclined:       "The card was declined.",
+	FailureInsufficientFunds:  "The payer does not have enough funds.",
+	FailureExpiredCard:        "The card has expired.",
+	FailureProcessingError:    "An error occurred while processing the payment.",
+	FailureBankTransferFailed: "The bank returned the transfer.",
+	FailureRefundFailed:       "The refund could not be sent to the payer.",
+}
+
+// testCards maps each test card to the failure it triggers; success is ""
+var testCards = map[string]string{
+	CardSuccess:           "",
+	CardDeclined:          FailureCardDeclined,
+	CardInsufficientFunds: FailureInsufficientFunds,
+	CardExpired:           FailureExpiredCard,
+	CardProcessingError:   FailureProcessingError,
+	CardRefundFails:       "",
+}
+
+var cardOrder = []string{CardSuccess, CardDeclined, CardInsufficientFunds, CardExpired, CardProcessingError, CardRefundFails}
+
+var failureOrder = []struct {
+	code, card, appliesTo string
+}{
+	{FailureCardDeclined, CardDeclined, "charge"},
+	{FailureInsufficientFunds, CardInsufficientFunds, "charge"},
+	{FailureExpiredCard, CardExpired, "charge"},
+	{FailureProcessingError, CardProcessingError, "charge"},
+	{FailureBankTransferFailed, "", "charge"},
+	{FailureRefundFailed, CardRefundFails, "refund"},
+}
+
+func (s *service) Scenarios() *Scenarios {
+	scenarios := &Scenarios{
+		Bank: []string{
+			"Bank charges settle immediately.",
+			"Amounts ending in .13 fail with " + FailureBankTransferFailed + ".",
+			"Amounts ending in .17 stay pending until settled through the settle endpoint.",
+		},
+	}
+	for _, number := range cardOrder {
+		outcome := testCards[number]
+		switch {
+		case number == CardRefundFails:
+			outcome = models.SandboxStatusSucceeded + ", refunds fail with " + FailureRefundFailed
+		case outcome == "":
+			outcome = models.SandboxStatusSucceeded
+		}
+		scenarios.TestCards = append(scenarios.TestCards, TestCard{Number: number, Outcome: outcome})
+	}
+	for _, failure := range failureOrder {
+		scenarios.Failures = append(scenarios.Failures, Scenario{
+			Code:       failure.code,
+			Message:    failureMessages[failure.code],
+			CardNumber: failure.card,
+			AppliesTo:  failure.appliesTo,
+		})
+	}
+	return scenarios
+}
@@ -0,0 +1,307 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/services/funding"
+	"orus/internal/services/webhook"
+	"orus/internal/utils"
+	"strings"
+)
+
+// sandboxBankAccount is the provider account sandbox bank charges debit
+const sandboxBankAccount = "acct_sandbox_payer"
+
+type service struct {
+	repo     repositories.SandboxRepository
+	bank     *funding.SandboxProvider
+	webhooks webhook.Service
+}
+
+// NewService creates the sandbox service. Bank charges always go through
+// the sandbox bank provider, whatever provider live funding uses.
+func NewService(repo repositories.SandboxRepository, webhooks webhook.Service) Service {
+	return &service{
+		repo:     repo,
+		bank:     funding.NewSandboxProvider(),
+		webhooks: webhooks,
+	}
+}
+
+func (s *service) CreateCustomer(ctx context.Context, merchant *models.Merchant, req CustomerRequest) (*models.SandboxCustomer, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if req.Balance < 0 {
+		return nil, ErrInvalidAmount
+	}
+	if req.Currency == "" {
+		req.Currency = "USD"
+	}
+
+	customer := &models.SandboxCustomer{
+		MerchantID: merchant.ID,
+		Name:       name,
+		Email:      strings.TrimSpace(req.Email),
+		Balance:    roundAmount(req.Balance),
+		Currency:   strings.ToUpper(req.Currency),
+	}
+	if err := s.repo.CreateCustomer(customer); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (s *service) GetCustomer(ctx context.Context, merchant *models.Merchant, customerID uint) (*models.SandboxCustomer, error) {
+	return s.repo.GetCustomer(merchant.ID, customerID)
+}
+
+func (s *service) ListCustomers(ctx context.Context, merchant *models.Merchant, limit, offset int) ([]models.SandboxCustomer, int64, error) {
+	return s.repo.ListCustomers(merchant.ID, limit, offset)
+}
+
+func (s *service) FundCustomer(ctx context.Context, merchant *models.Merchant, customerID uint, req FundRequest) (*models.SandboxCustomer, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return s.repo.FundCustomer(merchant.ID, customerID, roundAmount(req.Amount))
+}
+
+func (s *service) CreateCharge(ctx context.Context, merchant *models.Merchant, req ChargeRequest) (*models.SandboxCharge, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if req.Simulate != "" {
+		if _, ok := failureMessages[req.Simulate]; !ok || req.Simulate == FailureRefundFailed {
+			return nil, ErrUnknownScenario
+		}
+	}
+	if req.Currency == "" {
+		req.Currency = "USD"
+	}
+
+	reference, err := newReference("ch_test_")
+	if err != nil {
+		return nil, err
+	}
+	charge := &models.SandboxCharge{
+		Reference:   reference,
+		MerchantID:  merchant.ID,
+		CustomerID:  req.CustomerID,
+		Amount:      roundAmount(req.Amount),
+		Currency:    strings.ToUpper(req.Currency),
+		Source:      req.Source,
+		Status:      models.SandboxStatusSucceeded,
+		Description: req.Description,
+		Metadata:    req.Metadata,
+	}
+
+	switch req.Source {
+	case models.SandboxSourceWallet:
+		if req.CustomerID == nil {
+			return nil, ErrCustomerRequired
+		}
+		if _, err := s.repo.GetCustomer(merchant.ID, *req.CustomerID); err != nil {
+			return nil, err
+		}
+	case models.SandboxSourceCard:
+		number := strings.ReplaceAll(req.CardNumber, " ", "")
+		if number == "" {
+			return nil, ErrCardNumberRequired
+		}
+		failure, ok := testCards[number]
+		if !ok {
+			return nil, ErrUnknownTestCard
+		}
+		charge.CardLast4 = number[len(number)-4:]
+		fail(charge, failure)
+	case models.SandboxSourceBank:
+		transfer, err := s.bank.Debit(ctx, sandboxBankAccount, charge.Amount, reference)
+		if err != nil {
+			return nil, fmt.Errorf("sandbox bank debit failed: %w", err)
+		}
+		charge.ProviderReference = transfer.ID
+		switch transfer.Status {
+		case funding.TransferStatusFailed:
+			fail(charge, FailureBankTransferFailed)
+		case funding.TransferStatusPending:
+			charge.Status = models.SandboxStatusPending
+		}
+	default:
+		return nil, ErrInvalidSource
+	}
+	fail(charge, req.Simulate)
+
+	err = s.repo.CreateCharge(charge)
+	if errors.Is(err, repositories.ErrSandboxInsufficientFunds) {
+		// A short balance is a declined payment, not a bad request
+		fail(charge, FailureInsufficientFunds)
+		err = s.repo.CreateCharge(charge)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, merchant, chargeEvent(charge), charge)
+	return charge, nil
+}
+
+func (s *service) GetCharge(ctx context.Context, merchant *models.Merchant, reference string) (*ChargeView, error) {
+	charge, err := s.repo.GetCharge(merchant.ID, reference)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.repo.ListRefunds(merchant.ID, charge.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range refunds {
+		refunds[i].Charge = charge.Reference
+	}
+	return &ChargeView{SandboxCharge: charge, Refunds: refunds}, nil
+}
+
+func (s *service) ListCharges(ctx context.Context, merchant *models.Merchant, status string, limit, offset int) ([]models.SandboxCharge, int64, error) {
+	return s.repo.ListCharges(merchant.ID, status, limit, offset)
+}
+
+func (s *service) SettleCharge(ctx context.Context, merchant *models.Merchant, reference string, req SettleRequest) (*models.SandboxCharge, error) {
+	if req.Outcome == "" {
+		req.Outcome = models.SandboxStatusSucceeded
+	}
+	failure := ""
+	switch req.Outcome {
+	case models.SandboxStatusSucceeded:
+	case models.SandboxStatusFailed:
+		failure = FailureBankTransferFailed
+		if req.Simulate != "" {
+			if _, ok := failureMessages[req.Simulate]; !ok {
+				return nil, ErrUnknownScenario
+			}
+			failure = req.Simulate
+		}
+	default:
+		return nil, ErrInvalidOutcome
+	}
+
+	charge, err := s.repo.GetCharge(merchant.ID, reference)
+	if err != nil {
+		return nil, err
+	}
+	if charge.Status != models.SandboxStatusPending {
+		return nil, ErrChargeNotPending
+	}
+	if err := s.repo.SettleCharge(charge, req.Outcome, failure, failureMessages[failure]); err != nil {
+		if errors.Is(err, repositories.ErrSandboxChargeNotSettled) {
+			return nil, ErrChargeNotPending
+		}
+		return nil, err
+	}
+
+	s.notify(ctx, merchant, chargeEvent(charge), charge)
+	return charge, nil
+}
+
+func (s *service) Refund(ctx context.Context, merchant *models.Merchant, reference string, req RefundRequest) (*models.SandboxRefund, error) {
+	if req.Amount < 0 {
+		return nil, ErrInvalidAmount
+	}
+	if req.Simulate != "" && req.Simulate != FailureRefundFailed {
+		return nil, ErrUnknownScenario
+	}
+
+	charge, err := s.repo.GetCharge(merchant.ID, reference)
+	if err != nil {
+		return nil, err
+	}
+	if charge.Status != models.SandboxStatusSucceeded && charge.Status != models.SandboxStatusPartiallyRefunded {
+		return nil, ErrChargeNotRefundable
+	}
+	amount := roundAmount(req.Amount)
+	if amount == 0 {
+		amount = roundAmount(charge.Amount - charge.RefundedAmount)
+	}
+
+	refundReference, err := newReference("re_test_")
+	if err != nil {
+		return nil, err
+	}
+	refund := &models.SandboxRefund{
+		Reference:  refundReference,
+		MerchantID: merchant.ID,
+		Charge:     charge.Reference,
+		Amount:     amount,
+		Status:     models.SandboxStatusSucceeded,
+		Reason:     req.Reason,
+	}
+	if req.Simulate == FailureRefundFailed || charge.CardLast4 == CardRefundFails[len(CardRefundFails)-4:] {
+		refund.Status = models.SandboxStatusFailed
+		refund.FailureCode = FailureRefundFailed
+	}
+
+	if err := s.repo.Refund(charge, refund); err != nil {
+		return nil, err
+	}
+
+	if refund.Status == models.SandboxStatusFailed {
+		s.notify(ctx, merchant, webhook.EventRefundFailed, refund)
+	} else {
+		s.notify(ctx, merchant, webhook.EventChargeRefunded, charge)
+	}
+	return refund, nil
+}
+
+func (s *service) GetBalance(ctx context.Context, merchant *models.Merchant) (*repositories.SandboxBalance, error) {
+	return s.repo.GetBalance(merchant.ID)
+}
+
+func (s *service) Reset(ctx context.Context, merchant *models.Merchant) error {
+	return s.repo.Reset(merchant.ID)
+}
+
+// notify queues a sandbox webhook; failing to queue it doesn't fail the
+// request
+func (s *service) notify(ctx context.Context, merchant *models.Merchant, event string, data interface{}) {
+	if err := s.webhooks.EnqueueSandbox(ctx, merchant.UserID, event, data); err != nil {
+		log.Printf("Failed to queue sandbox %s webhook for merchant %d: %v", event, merchant.ID, err)
+	}
+}
+
+// fail marks a charge failed with a failure code; an empty code is a no-op
+func fail(charge *models.SandboxCharge, code string) {
+	if code == "" {
+		return
+	}
+	charge.Status = models.SandboxStatusFailed
+	charge.FailureCode = code
+	charge.FailureMessage = failureMessages[code]
+}
+
+func chargeEvent(charge *models.SandboxCharge) string {
+	switch charge.Status {
+	case models.SandboxStatusFailed:
+		return webhook.EventChargeFailed
+	case models.SandboxStatusPending:
+		return webhook.EventChargePending
+	default:
+		return webhook.EventChargeSucceeded
+	}
+}
+
+func newReference(prefix string) (string, error) {
+	id, err := utils.GenerateUniqueID(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate reference: %w", err)
+	}
+	return prefix + id, nil
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,72 @@
+package sandbox
+
+import "orus/internal/models"
+
+// CustomerRequest creates a sandbox customer with an opening balance
+type CustomerRequest struct {
+	Name     string  `json:"name"`
+	Email    string  `json:"email"`
+	Balance  float64 `json:"balance"`
+	Currency string  `json:"currency"`
+}
+
+// FundRequest adds simulated money to a sandbox customer
+type FundRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// ChargeRequest takes a sandbox payment from a customer's balance, a test
+// card or the simulated bank
+type ChargeRequest struct {
+	Amount      float64     `json:"amount"`
+	Currency    string      `json:"currency"`
+	Source      string      `json:"source"`
+	CustomerID  *uint       `json:"customer_id"`
+	CardNumber  string      `json:"card_number"`
+	Description string      `json:"description"`
+	Metadata    models.JSON `json:"metadata"`
+	// Simulate forces one of the failure scenarios whatever the source
+	Simulate string `json:"simulate"`
+}
+
+// RefundRequest returns part or all of a charge. A zero amount refunds
+// what is left.
+type RefundRequest struct {
+	Amount   float64 `json:"amount"`
+	Reason   string  `json:"reason"`
+	Simulate string  `json:"simulate"`
+}
+
+// SettleRequest resolves a pending bank charge
+type SettleRequest struct {
+	Outcome  string `json:"outcome"`
+	Simulate string `json:"simulate"`
+}
+
+// ChargeView is a charge with its refunds
+type ChargeView struct {
+	*models.SandboxCharge
+	Refunds []models.SandboxRefund `json:"refunds"`
+}
+
+// Scenario is a failure integrators can trigger in the sandbox
+type Scenario struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// CardNumber triggers the scenario when charged, if a test card does
+	CardNumber string `json:"card_number,omitempty"`
+	AppliesTo  string `json:"applies_to"`
+}
+
+// TestCard is a card number the sandbox card network recognises
+type TestCard struct {
+	Number  string `json:"number"`
+	Outcome string `json:"outcome"`
+}
+
+// Scenarios lists the sandbox test cards, bank amounts and forced failures
+type Scenarios struct {
+	TestCards []TestCard `json:"test_cards"`
+	Failures  []Scenario `json:"failures"`
+	Bank      []string   `json:"bank"`
+}
@@ -0,0 +1,10 @@
+package scanconfirm
+
+import "errors"
+
+// Service errors
+var (
+	ErrConfirmationNotFound = errors.New("charge confirmation not found")
+	ErrConfirmationClosed   = errors.New("charge is no longer awaiting confirmation")
+	ErrConfirmationExpired  = errors.New("the time to approve this charge has passed")
+)
@@ -0,0 +1,39 @@
+package scanconfirm
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Notifier prompts payers to approve a merchant's charge
+type Notifier interface {
+	SendScanChargeApprovalRequest(ctx context.Context, confirmation *models.ScanConfirmation) error
+}
+
+// Service lets payers approve the charges merchants take by scanning their
+// payment code before any money moves
+type Service interface {
+	GetSettings(ctx context.Context, userID uint) (*models.ScanConfirmationSettings, error)
+	UpdateSettings(ctx context.Context, userID uint, req SettingsRequest) (*models.ScanConfirmationSettings, error)
+
+	// Hold records the charge as awaiting the payer's approval and prompts
+	// them, if they asked to approve charges of its amount. It reports
+	// whether the charge was held.
+	Hold(ctx context.Context, confirmation *models.ScanConfirmation) (bool, error)
+	// ListPending returns the charges waiting for the payer's decision
+	ListPending(ctx context.Context, userID uint) ([]models.ScanConfirmation, error)
+	// GetForMerchant returns a held charge to the merchant that took it,
+	// for the point of sale to follow
+	GetForMerchant(ctx context.Context, merchantUserID, id uint) (*models.ScanConfirmation, error)
+
+	// Claim approves the payer's pending charge, once, leaving the caller
+	// to take it and report back with Complete or Fail
+	Claim(ctx context.Context, userID, id uint) (*models.ScanConfirmation, error)
+	Complete(ctx context.Context, id, transactionID uint) error
+	Fail(ctx context.Context, id uint, reason string) error
+	// Decline cancels the payer's pending charge
+	Decline(ctx context.Context, userID, id uint) (*models.ScanConfirmation, error)
+	// ExpireDue cancels the charges not approved in time and returns how
+	// many it cancelled
+	ExpireDue(ctx context.Context) (int, error)
+}
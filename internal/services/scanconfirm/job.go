@@ -0,0 +1,24 @@
+package scanconfirm
+
+import (
+	"context"
+	"log"
+)
+
+// Job cancels merchant charges not approved in time from the job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the scan confirmation service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "scan-confirmations" }
+
+func (j *Job) Run(ctx context.Context) error {
+	expired, err := j.service.ExpireDue(ctx)
+	if expired > 0 {
+		log.Printf("Expired %d merchant charges awaiting payer confirmation", expired)
+	}
+	return err
+}
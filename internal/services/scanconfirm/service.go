@@ -0,0 +1,159 @@
+package scanconfirm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+type service struct {
+	repo     repositories.ScanConfirmationRepository
+	notifier Notifier
+	config   Config
+}
+
+// NewService creates the scan confirmation service
+func NewService(repo repositories.ScanConfirmationRepository, notifier Notifier, config Config) Service {
+	return &service{repo: repo, notifier: notifier, config: config}
+}
+
+func (s *service) GetSettings(ctx context.Context, userID uint) (*models.ScanConfirmationSettings, error) {
+	return s.repo.GetSettings(userID)
+}
+
+func (s *service) UpdateSettings(ctx context.Context, userID uint, req SettingsRequest) (*models.ScanConfirmationSettings, error) {
+	settings, err := s.repo.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.AutoApproveLimit != nil {
+		settings.AutoApproveLimit = *req.AutoApproveLimit
+	}
+	settings.UpdatedAt = time.Now()
+	if err := s.repo.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *service) Hold(ctx context.Context, confirmation *models.ScanConfirmation) (bool, error) {
+	settings, err := s.repo.GetSettings(confirmation.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !settings.Requires(confirmation.Amount) {
+		return false, nil
+	}
+
+	confirmation.Status = models.ScanConfirmationPending
+	confirmation.ExpiresAt = time.Now().Add(s.config.Window)
+	if err := s.repo.Create(confirmation); err != nil {
+		return false, err
+	}
+	if err := s.notifier.SendScanChargeApprovalRequest(ctx, confirmation); err != nil {
+		log.Printf("Failed to prompt user %d to approve charge %d: %v", confirmation.UserID, confirmation.ID, err)
+	}
+	return true, nil
+}
+
+func (s *service) ListPending(ctx context.Context, userID uint) ([]models.ScanConfirmation, error) {
+	return s.repo.ListPending(userID, time.Now())
+}
+
+func (s *service) GetForMerchant(ctx context.Context, merchantUserID, id uint) (*models.ScanConfirmation, error) {
+	confirmation, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation.MerchantUserID != merchantUserID {
+		return nil, ErrConfirmationNotFound
+	}
+	return confirmation, nil
+}
+
+func (s *service) Claim(ctx context.Context, userID, id uint) (*models.ScanConfirmation, error) {
+	return s.close(userID, id, models.ScanConfirmationApproved)
+}
+
+func (s *service) Complete(ctx context.Context, id, transactionID uint) error {
+	return s.repo.Complete(id, transactionID)
+}
+
+func (s *service) Fail(ctx context.Context, id uint, reason string) error {
+	return s.repo.Fail(id, reason)
+}
+
+func (s *service) Decline(ctx context.Context, userID, id uint) (*models.ScanConfirmation, error) {
+	return s.close(userID, id, models.ScanConfirmationDeclined)
+}
+
+func (s *service) ExpireDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	confirmations, err := s.repo.FindExpired(now, expiryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, confirmation := range confirmations {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		err := s.repo.Close(confirmation.ID, models.ScanConfirmationExpired, now)
+		if errors.Is(err, repositories.ErrScanConfirmationNotPending) {
+			continue // Decided meanwhile
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// close decides the payer's pending confirmation. One past its window is
+// expired on the spot, in case the job hasn't yet.
+func (s *service) close(userID, id uint, status string) (*models.ScanConfirmation, error) {
+	confirmation, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation.UserID != userID {
+		return nil, ErrConfirmationNotFound
+	}
+	if confirmation.Status != models.ScanConfirmationPending {
+		return nil, ErrConfirmationClosed
+	}
+
+	now := time.Now()
+	if !now.Before(confirmation.ExpiresAt) {
+		status = models.ScanConfirmationExpired
+	}
+	if err := s.repo.Close(id, status, now); err != nil {
+		if errors.Is(err, repositories.ErrScanConfirmationNotPending) {
+			return nil, ErrConfirmationClosed
+		}
+		return nil, err
+	}
+	if status == models.ScanConfirmationExpired {
+		return nil, ErrConfirmationExpired
+	}
+
+	confirmation.Status = status
+	confirmation.DecidedAt = &now
+	return confirmation, nil
+}
+
+func (s *service) get(id uint) (*models.ScanConfirmation, error) {
+	confirmation, err := s.repo.Get(id)
+	if errors.Is(err, repositories.ErrScanConfirmationNotFound) {
+		return nil, ErrConfirmationNotFound
+	}
+	return confirmation, err
+}
@@ -0,0 +1,18 @@
+package scanconfirm
+
+import "time"
+
+// Config sets how long payers have to approve a charge
+type Config struct {
+	Window time.Duration
+}
+
+// SettingsRequest changes whether the payer approves merchant scans; fields
+// left out are kept
+type SettingsRequest struct {
+	Enabled          *bool    `json:"enabled"`
+	AutoApproveLimit *float64 `json:"auto_approve_limit" validate:"omitempty,gte=0"`
+}
+
+// expiryBatch is how many lapsed confirmations are expired at a time
+const expiryBatch = 200
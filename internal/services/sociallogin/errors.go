@@ -0,0 +1,28 @@
+package sociallogin
+
+import "errors"
+
+var (
+	// ErrUnknownProvider is returned for a provider that isn't supported
+	// or isn't configured
+	ErrUnknownProvider = errors.New("unknown sign-in provider")
+	// ErrProviderDisabled is returned when an admin turned the provider off
+	ErrProviderDisabled = errors.New("sign-in with this provider is disabled")
+	// ErrInvalidState is returned for a callback whose state wasn't issued
+	// by Begin, has been used, or has expired
+	ErrInvalidState = errors.New("sign-in request is invalid or has expired")
+	// ErrExchangeFailed is returned when the provider refused the code
+	ErrExchangeFailed = errors.New("provider refused the sign-in")
+	// ErrInvalidIDToken is returned for an ID token that doesn't verify
+	ErrInvalidIDToken = errors.New("provider returned an invalid ID token")
+	// ErrEmailUnverified is returned when the provider doesn't vouch for
+	// the account's email, which is needed to link or create an account
+	ErrEmailUnverified = errors.New("provider account has no verified email")
+	// ErrSignInToLink is returned when an account already uses the
+	// provider's email: its owner must sign in and link the provider
+	// account from there
+	ErrSignInToLink = errors.New("an account uses this email, sign in to link the provider account")
+	// ErrIdentityLinked is returned when linking a provider account that
+	// another user signs in with
+	ErrIdentityLinked = errors.New("provider account is linked to another user")
+)
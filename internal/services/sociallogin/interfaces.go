@@ -0,0 +1,47 @@
+package sociallogin
+
+import (
+	"context"
+
+	"orus/internal/models"
+	"orus/internal/services/auth"
+)
+
+// Service signs users in with Google and Apple through OpenID Connect
+type Service interface {
+	// Providers lists the providers users can sign in with now
+	Providers(ctx context.Context) []ProviderInfo
+	// Begin starts a sign-in, returning the provider's authorization URL
+	Begin(ctx context.Context, provider string) (*Authorization, error)
+	// BeginLink starts a sign-in that links the provider account to the
+	// signed-in user
+	BeginLink(ctx context.Context, provider string, userID uint) (*Authorization, error)
+	// Complete exchanges the callback's code, then signs in the account
+	// linked to the provider account. Failing that it links the account
+	// BeginLink was called for, or creates one with a wallet for a verified
+	// email no account uses yet.
+	Complete(ctx context.Context, provider string, req CallbackRequest, login auth.LoginContext) (*Result, error)
+	// ListIdentities returns the provider accounts linked to the user
+	ListIdentities(ctx context.Context, userID uint) ([]models.UserIdentity, error)
+}
+
+// Authenticator signs in the user a provider vouched for
+type Authenticator interface {
+	LoginExternal(user *models.User, login auth.LoginContext) (*models.User, string, string, error)
+}
+
+// FeatureFlags reports whether admins left a provider on
+type FeatureFlags interface {
+	Enabled(ctx context.Context, key string) bool
+}
+
+// WalletProvisioner creates new users' wallets
+type WalletProvisioner interface {
+	CreateWallet(ctx context.Context, userID uint, currency string) (*models.Wallet, error)
+}
+
+// QRProvisioner creates new users' QR codes
+type QRProvisioner interface {
+	GetUserReceiveQR(ctx context.Context, userID uint) (*models.QRCode, error)
+	GetUserPaymentCodeQR(ctx context.Context, userID uint) (*models.QRCode, error)
+}
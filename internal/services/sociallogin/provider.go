@@ -0,0 +1,288 @@
+package sociallogin
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"orus/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keysTTL is how long a provider's signing keys are used before they are
+// fetched again; a token naming an unknown key fetches them sooner
+const keysTTL = time.Hour
+
+// provider is an OpenID Connect provider and this app's client at it
+type provider struct {
+	name     string
+	flag     string
+	clientID string
+	authURL  string
+	tokenURL string
+	keysURL  string
+	issuers  []string
+	scopes   string
+	// params are added to the authorization URL
+	params url.Values
+	// pkce sends a code challenge; Apple doesn't support it
+	pkce bool
+	// clientSecret returns the secret to exchange a code with
+	clientSecret func() (string, error)
+
+	client *http.Client
+	keys   *keyCache
+}
+
+func newGoogle(cfg Config, client *http.Client) *provider {
+	return &provider{
+		name:         models.IdentityProviderGoogle,
+		flag:         models.FeatureGoogleLogin,
+		clientID:     cfg.GoogleClientID,
+		authURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		tokenURL:     "https://oauth2.googleapis.com/token",
+		keysURL:      "https://www.googleapis.com/oauth2/v3/certs",
+		issuers:      []string{"https://accounts.google.com", "accounts.google.com"},
+		scopes:       "openid email profile",
+		params:       url.Values{"prompt": {"select_account"}},
+		pkce:         true,
+		clientSecret: func() (string, error) { return cfg.GoogleClientSecret, nil },
+		client:       client,
+		keys:         &keyCache{},
+	}
+}
+
+func newApple(cfg Config, client *http.Client) (*provider, error) {
+	data, err := os.ReadFile(cfg.ApplePrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Apple private key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Apple private key: %w", err)
+	}
+
+	return &provider{
+		name:     models.IdentityProviderApple,
+		flag:     models.FeatureAppleLogin,
+		clientID: cfg.AppleClientID,
+		authURL:  "https://appleid.apple.com/auth/authorize",
+		tokenURL: "https://appleid.apple.com/auth/token",
+		keysURL:  "https://appleid.apple.com/auth/keys",
+		issuers:  []string{"https://appleid.apple.com"},
+		scopes:   "name email",
+		// Apple only returns the name and email to a form post
+		params: url.Values{"response_mode": {"form_post"}},
+		clientSecret: func() (string, error) {
+			return appleClientSecret(cfg, key)
+		},
+		client: client,
+		keys:   &keyCache{},
+	}, nil
+}
+
+// appleClientSecret signs the short-lived JWT Apple takes as the client
+// secret
+func appleClientSecret(cfg Config, key *ecdsa.PrivateKey) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    cfg.AppleTeamID,
+		Subject:   cfg.AppleClientID,
+		Audience:  jwt.ClaimStrings{"https://appleid.apple.com"},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+	})
+	token.Header["kid"] = cfg.AppleKeyID
+	return token.SignedString(key)
+}
+
+// authorizationURL is where the user signs in and consents
+func (p *provider) authorizationURL(redirectURI, state, nonce, challenge string) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.clientID},
+		"redirect_uri":  {redirectURI},
+		"scope":         {p.scopes},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	for key, values := range p.params {
+		query[key] = values
+	}
+	if challenge != "" {
+		query.Set("code_challenge", challenge)
+		query.Set("code_challenge_method", "S256")
+	}
+	return p.authURL + "?" + query.Encode()
+}
+
+// exchange trades the authorization code for the ID token
+func (p *provider) exchange(ctx context.Context, code, redirectURI, verifier string) (string, error) {
+	secret, err := p.clientSecret()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {p.clientID},
+		"client_secret": {secret},
+	}
+	if verifier != "" {
+		form.Set("code_verifier", verifier)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build %s token request: %w", p.name, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach %s: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken     string `json:"id_token"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse %s token response: %w", p.name, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("%w: %s %s", ErrExchangeFailed, body.Error, body.Description)
+	}
+	return body.IDToken, nil
+}
+
+// idTokenClaims are the ID token claims sign-in uses
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// verify checks the ID token was signed by the provider for this client
+// and this sign-in, and returns who it names
+func (p *provider) verify(ctx context.Context, raw, nonce string) (*identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.get(ctx, p, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if !slices.Contains(p.issuers, claims.Issuer) || claims.Nonce != nonce || claims.Subject == "" {
+		return nil, ErrInvalidIDToken
+	}
+
+	return &identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// keyCache holds a provider's ID token signing keys by key ID
+type keyCache struct {
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// get returns the key named kid, fetching the provider's keys when they
+// are stale or don't include it, as after the provider rotates them
+func (c *keyCache) get(ctx context.Context, p *provider, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.keys[kid]; ok && time.Since(c.fetchedAt) < keysTTL {
+		return key, nil
+	}
+	// A token can't make us fetch more than once a minute
+	if time.Since(c.fetchedAt) > time.Minute {
+		keys, err := fetchKeys(ctx, p.client, p.keysURL)
+		if err != nil {
+			return nil, err
+		}
+		c.keys, c.fetchedAt = keys, time.Now()
+	}
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown %s signing key %q", p.name, kid)
+	}
+	return key, nil
+}
+
+// fetchKeys reads the RSA keys of a JSON Web Key Set
+func fetchKeys(ctx context.Context, client *http.Client, keysURL string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keysURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build signing keys request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signing keys returned status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to parse signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.KeyType != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,319 @@
+package sociallogin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/auth"
+	"orus/internal/services/password"
+)
+
+type service struct {
+	providers  map[string]*provider
+	order      []string
+	redirect   string
+	identities repositories.UserIdentityRepository
+	users      repositories.UserRepository
+	passwords  *password.Hasher
+	auth       Authenticator
+	flags      FeatureFlags
+	wallets    WalletProvisioner
+	qr         QRProvisioner
+	cache      *cache.CacheService
+}
+
+// NewService creates the social login service with the providers cfg
+// configures. It fails when the Apple key can't be read.
+func NewService(
+	cfg Config,
+	identities repositories.UserIdentityRepository,
+	users repositories.UserRepository,
+	passwords *password.Hasher,
+	authenticator Authenticator,
+	flags FeatureFlags,
+	wallets WalletProvisioner,
+	qr QRProvisioner,
+	cacheSvc *cache.CacheService,
+) (Service, error) {
+	s := &service{
+		providers:  make(map[string]*provider),
+		redirect:   strings.TrimSuffix(cfg.RedirectBase, "/") + "/api/login/%s/callback",
+		identities: identities,
+		users:      users,
+		passwords:  passwords,
+		auth:       authenticator,
+		flags:      flags,
+		wallets:    wallets,
+		qr:         qr,
+		cache:      cacheSvc,
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if cfg.GoogleClientID != "" {
+		s.add(newGoogle(cfg, client))
+	}
+	if cfg.AppleClientID != "" {
+		apple, err := newApple(cfg, client)
+		if err != nil {
+			return nil, err
+		}
+		s.add(apple)
+	}
+	return s, nil
+}
+
+func (s *service) add(p *provider) {
+	s.providers[p.name] = p
+	s.order = append(s.order, p.name)
+}
+
+func (s *service) Providers(ctx context.Context) []ProviderInfo {
+	providers := []ProviderInfo{}
+	for _, name := range s.order {
+		if s.flags.Enabled(ctx, s.providers[name].flag) {
+			providers = append(providers, ProviderInfo{Name: name})
+		}
+	}
+	return providers
+}
+
+func (s *service) Begin(ctx context.Context, name string) (*Authorization, error) {
+	return s.begin(ctx, name, 0)
+}
+
+func (s *service) BeginLink(ctx context.Context, name string, userID uint) (*Authorization, error) {
+	return s.begin(ctx, name, userID)
+}
+
+func (s *service) begin(ctx context.Context, name string, linkUserID uint) (*Authorization, error) {
+	p, err := s.provider(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	pending := pendingSignIn{Provider: p.name, LinkUserID: linkUserID}
+	if pending.Nonce, err = randomString(); err != nil {
+		return nil, err
+	}
+	challenge := ""
+	if p.pkce {
+		if pending.Verifier, err = randomString(); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256([]byte(pending.Verifier))
+		challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	if err := s.cache.SetExact(ctx, stateKey(state), pending, StateTTL); err != nil {
+		return nil, err
+	}
+
+	return &Authorization{
+		URL:   p.authorizationURL(s.redirectURI(p), state, pending.Nonce, challenge),
+		State: state,
+	}, nil
+}
+
+func (s *service) Complete(ctx context.Context, name string, req CallbackRequest, login auth.LoginContext) (*Result, error) {
+	p, err := s.provider(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if req.Error != "" {
+		return nil, fmt.Errorf("%w: %s", ErrExchangeFailed, req.Error)
+	}
+
+	// The state is used once, by the provider it was issued for; taking it
+	// deletes it, so of two callbacks racing with it only one gets it
+	if req.State == "" {
+		return nil, ErrInvalidState
+	}
+	var pending pendingSignIn
+	found, err := s.cache.Take(ctx, stateKey(req.State), &pending)
+	if err != nil {
+		return nil, err
+	}
+	if !found || pending.Provider != p.name {
+		return nil, ErrInvalidState
+	}
+
+	rawToken, err := p.exchange(ctx, req.Code, s.redirectURI(p), pending.Verifier)
+	if err != nil {
+		return nil, err
+	}
+	who, err := p.verify(ctx, rawToken, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if who.Name == "" && req.User != "" {
+		var user appleUser
+		if json.Unmarshal([]byte(req.User), &user) == nil {
+			who.Name = strings.TrimSpace(user.Name.FirstName + " " + user.Name.LastName)
+		}
+	}
+
+	result, err := s.resolve(ctx, p, who, pending.LinkUserID)
+	if err != nil {
+		return nil, err
+	}
+	user, access, refresh, err := s.auth.LoginExternal(result.User, login)
+	// A challenged login returns the user to verify the code for
+	if user != nil {
+		result.User = user
+	}
+	result.AccessToken, result.RefreshToken = access, refresh
+	return result, err
+}
+
+// resolve finds the account the provider account signs in to: the one
+// linked to it, else the one linkUserID, signed in, asked to link it to,
+// else a new one. An account using the provider's email is never linked
+// on the email alone: whoever registered it may not own the address, and
+// would keep access to an account its owner then signs in to.
+func (s *service) resolve(ctx context.Context, p *provider, who *identity, linkUserID uint) (*Result, error) {
+	now := time.Now()
+	linked, err := s.identities.Get(ctx, p.name, who.Subject)
+	if err == nil {
+		if linkUserID != 0 && linked.UserID != linkUserID {
+			return nil, ErrIdentityLinked
+		}
+		if err := s.identities.Touch(ctx, linked.ID, who.Email, now); err != nil {
+			log.Printf("Failed to record %s sign-in of user %d: %v", p.name, linked.UserID, err)
+		}
+		user, err := s.users.GetByID(linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{User: user}, nil
+	}
+	if !errors.Is(err, repositories.ErrUserIdentityNotFound) {
+		return nil, err
+	}
+
+	result := &Result{}
+	if linkUserID != 0 {
+		user, err := s.users.GetByID(linkUserID)
+		if err != nil {
+			return nil, err
+		}
+		result.User, result.Linked = user, true
+	} else {
+		// Creating an account rests on the email being theirs
+		if !who.EmailVerified || who.Email == "" {
+			return nil, ErrEmailUnverified
+		}
+		_, err := s.users.GetByEmail(who.Email)
+		switch {
+		case err == nil:
+			return nil, ErrSignInToLink
+		case !errors.Is(err, repositories.ErrUserNotFound):
+			return nil, err
+		}
+		user, err := s.signUp(ctx, who)
+		if err != nil {
+			return nil, err
+		}
+		result.User, result.Created = user, true
+	}
+
+	identity := &models.UserIdentity{
+		UserID:      result.User.ID,
+		Provider:    p.name,
+		Subject:     who.Subject,
+		Email:       who.Email,
+		LastLoginAt: &now,
+	}
+	if err := s.identities.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// signUp creates the account of a new user, with their wallet and QR codes
+// like a registration. Its password is random, so it can only be signed in
+// to through the provider until the user sets one.
+func (s *service) signUp(ctx context.Context, who *identity) (*models.User, error) {
+	secret, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := s.passwords.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	name := who.Name
+	if name == "" {
+		name, _, _ = strings.Cut(who.Email, "@")
+	}
+	user := &models.User{
+		Name:     name,
+		Email:    who.Email,
+		Password: hash,
+		Role:     "user",
+		Status:   models.UserStatusActive,
+	}
+	if err := s.users.Create(user); err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.wallets.CreateWallet(ctx, user.ID, "USD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+	user.WalletID = &wallet.ID
+	if _, err := s.qr.GetUserReceiveQR(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to generate receive QR: %w", err)
+	}
+	if _, err := s.qr.GetUserPaymentCodeQR(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to generate payment QR: %w", err)
+	}
+	return user, nil
+}
+
+func (s *service) ListIdentities(ctx context.Context, userID uint) ([]models.UserIdentity, error) {
+	return s.identities.ListByUser(ctx, userID)
+}
+
+// provider returns the named provider if it is configured and on
+func (s *service) provider(ctx context.Context, name string) (*provider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	if !s.flags.Enabled(ctx, p.flag) {
+		return nil, ErrProviderDisabled
+	}
+	return p, nil
+}
+
+func (s *service) redirectURI(p *provider) string {
+	return fmt.Sprintf(s.redirect, p.name)
+}
+
+func stateKey(state string) string {
+	return "social_login:" + state
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,98 @@
+package sociallogin
+
+import (
+	"encoding/json"
+	"time"
+
+	"orus/internal/models"
+)
+
+// StateTTL bounds the time between starting a sign-in and its callback
+const StateTTL = 10 * time.Minute
+
+// Config holds the provider clients; see config.SocialConfig
+type Config struct {
+	// RedirectBase is the public base URL the callback is under
+	RedirectBase        string
+	GoogleClientID      string
+	GoogleClientSecret  string
+	AppleClientID       string
+	AppleTeamID         string
+	AppleKeyID          string
+	ApplePrivateKeyFile string
+}
+
+// ProviderInfo tells client apps which sign-in buttons to show
+type ProviderInfo struct {
+	Name string `json:"name"`
+}
+
+// Authorization is where to send the user to sign in with a provider
+type Authorization struct {
+	URL   string `json:"authorization_url"`
+	State string `json:"state"`
+}
+
+// CallbackRequest is what the provider redirects back with. Apple posts
+// it as a form, with the user's name in User on their first sign-in only.
+type CallbackRequest struct {
+	Code  string `json:"code" form:"code" query:"code"`
+	State string `json:"state" form:"state" query:"state"`
+	User  string `json:"user" form:"user"`
+	// Error is set when the user cancelled or the provider refused
+	Error string `json:"error" form:"error" query:"error"`
+}
+
+// Result is a completed social sign-in. Tokens are empty when the login
+// must still be verified with the one-time code.
+type Result struct {
+	User         *models.User
+	AccessToken  string
+	RefreshToken string
+	// Created is set for a new account, Linked when the provider account
+	// was linked to the signed-in user's
+	Created bool
+	Linked  bool
+}
+
+// pendingSignIn is kept between Begin and the callback
+type pendingSignIn struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier,omitempty"`
+	// LinkUserID is the signed-in user who asked to link the account
+	LinkUserID uint `json:"link_user_id,omitempty"`
+}
+
+// identity is who the provider says signed in
+type identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// flexBool reads a boolean claim Apple sends as "true" or "false"
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = v == "true"
+	}
+	return nil
+}
+
+// appleUser is the user field of Apple's first callback
+type appleUser struct {
+	Name struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"name"`
+}
@@ -0,0 +1,22 @@
+package spendcontrol
+
+import "errors"
+
+// Service errors
+var (
+	ErrInvalidScope         = errors.New("scope must be category or foreign")
+	ErrInvalidCategory      = errors.New("category must be gambling, crypto, adult, alcohol or tobacco")
+	ErrInvalidAction        = errors.New("action must be block or cap")
+	ErrInvalidCap           = errors.New("a cap needs an amount greater than zero and a daily or monthly period")
+	ErrControlNotFound      = errors.New("spending control not found")
+	ErrManagedControl       = errors.New("this control was set by your organization and only its admins can change it")
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrRoleForbidden        = errors.New("only organization owners and admins can manage member spending controls")
+	ErrMemberNotFound       = errors.New("member not found")
+
+	// Declines
+	ErrCategoryBlocked     = errors.New("payments to this merchant category are blocked")
+	ErrCategoryCapExceeded = errors.New("payment exceeds the spending cap for this merchant category")
+	ErrForeignBlocked      = errors.New("payments to foreign merchants are blocked")
+	ErrForeignCapExceeded  = errors.New("payment exceeds the spending cap for foreign merchants")
+)
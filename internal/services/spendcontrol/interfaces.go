@@ -0,0 +1,29 @@
+package spendcontrol
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages spending controls and enforces them on payments
+type Service interface {
+	// List returns every control on the user, including those their
+	// organizations set
+	List(ctx context.Context, userID uint) ([]models.SpendingControl, error)
+	// Set adds or replaces one of the user's own controls
+	Set(ctx context.Context, userID uint, req ControlRequest) (*models.SpendingControl, error)
+	// Delete removes one of the user's own controls
+	Delete(ctx context.Context, userID, controlID uint) error
+
+	// ListMember returns the controls an organization set on its member
+	ListMember(ctx context.Context, actorID, orgID, memberID uint) ([]models.SpendingControl, error)
+	// SetMember adds or replaces a control the organization sets on its
+	// member; for owners and admins
+	SetMember(ctx context.Context, actorID, orgID, memberID uint, req ControlRequest) (*models.SpendingControl, error)
+	// DeleteMember removes a control the organization set on its member
+	DeleteMember(ctx context.Context, actorID, orgID, memberID, controlID uint) error
+
+	// Check declines a payment to a merchant that the payer's controls
+	// block or that would take them over a cap
+	Check(ctx context.Context, tx *models.Transaction) error
+}
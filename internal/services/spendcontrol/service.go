@@ -0,0 +1,287 @@
+package spendcontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type service struct {
+	repo        repositories.SpendingControlRepository
+	enterprises repositories.EnterpriseRepository
+	users       repositories.UserRepository
+	merchants   repositories.MerchantRepository
+}
+
+// NewService creates the spending control service
+func NewService(
+	repo repositories.SpendingControlRepository,
+	enterprises repositories.EnterpriseRepository,
+	users repositories.UserRepository,
+	merchants repositories.MerchantRepository,
+) Service {
+	return &service{repo: repo, enterprises: enterprises, users: users, merchants: merchants}
+}
+
+func (s *service) List(ctx context.Context, userID uint) ([]models.SpendingControl, error) {
+	return s.repo.ListForUser(userID)
+}
+
+func (s *service) Set(ctx context.Context, userID uint, req ControlRequest) (*models.SpendingControl, error) {
+	return s.save(userID, 0, userID, req)
+}
+
+func (s *service) Delete(ctx context.Context, userID, controlID uint) error {
+	control, err := s.control(controlID, userID)
+	if err != nil {
+		return err
+	}
+	if control.IsManaged() {
+		return ErrManagedControl
+	}
+	return s.repo.Delete(control.ID)
+}
+
+func (s *service) ListMember(ctx context.Context, actorID, orgID, memberID uint) ([]models.SpendingControl, error) {
+	if err := s.authorize(actorID, orgID, memberID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSetBy(orgID, memberID)
+}
+
+func (s *service) SetMember(ctx context.Context, actorID, orgID, memberID uint, req ControlRequest) (*models.SpendingControl, error) {
+	if err := s.authorize(actorID, orgID, memberID); err != nil {
+		return nil, err
+	}
+	return s.save(memberID, orgID, actorID, req)
+}
+
+func (s *service) DeleteMember(ctx context.Context, actorID, orgID, memberID, controlID uint) error {
+	if err := s.authorize(actorID, orgID, memberID); err != nil {
+		return err
+	}
+	control, err := s.control(controlID, memberID)
+	if err != nil {
+		return err
+	}
+	// Only the organization's own controls; the member's are theirs
+	if control.EnterpriseID != orgID {
+		return ErrControlNotFound
+	}
+	return s.repo.Delete(control.ID)
+}
+
+// Check applies blocks before caps, so a payment both would decline is
+// reported as blocked. A payer or merchant without a country on file
+// can't be matched as foreign.
+func (s *service) Check(ctx context.Context, tx *models.Transaction) error {
+	if tx.SenderID == 0 || nonPurchases[tx.Type] {
+		return nil
+	}
+	controls, err := s.repo.ListForUser(tx.SenderID)
+	if err != nil || len(controls) == 0 {
+		return err
+	}
+
+	merchant, err := s.merchants.GetByUserID(tx.ReceiverID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Not a merchant payment
+		}
+		return fmt.Errorf("failed to get merchant: %w", err)
+	}
+
+	c := &check{service: s, tx: tx, merchant: merchant}
+	var applicable []models.SpendingControl
+	for _, control := range controls {
+		applies, err := c.applies(&control)
+		if err != nil {
+			return err
+		}
+		if applies {
+			applicable = append(applicable, control)
+		}
+	}
+
+	for _, control := range applicable {
+		if control.Action == models.SpendingActionBlock {
+			return declined(&control, ErrCategoryBlocked, ErrForeignBlocked)
+		}
+	}
+	for _, control := range applicable {
+		if control.Action != models.SpendingActionCap {
+			continue
+		}
+		spent, err := c.spent(&control)
+		if err != nil {
+			return err
+		}
+		if spent+tx.Amount > control.CapAmount {
+			return declined(&control, ErrCategoryCapExceeded, ErrForeignCapExceeded)
+		}
+	}
+	return nil
+}
+
+// check caches what a payment's controls are matched against
+type check struct {
+	service  *service
+	tx       *models.Transaction
+	merchant *models.Merchant
+
+	countriesKnown  bool
+	payerCountry    string
+	merchantCountry string
+}
+
+func (c *check) applies(control *models.SpendingControl) (bool, error) {
+	switch control.Scope {
+	case models.SpendingScopeCategory:
+		return normalizeCategory(c.merchant.BusinessType) == control.Category, nil
+	case models.SpendingScopeForeign:
+		if err := c.loadCountries(); err != nil {
+			return false, err
+		}
+		return c.payerCountry != "" && c.merchantCountry != "" && c.payerCountry != c.merchantCountry, nil
+	}
+	return false, nil
+}
+
+func (c *check) loadCountries() error {
+	if c.countriesKnown {
+		return nil
+	}
+	payer, err := c.service.users.GetByID(c.tx.SenderID)
+	if err != nil {
+		return fmt.Errorf("failed to get payer: %w", err)
+	}
+	c.payerCountry, _ = models.NormalizeCountryCode(payer.Country)
+
+	merchantUser, err := c.service.users.GetByID(c.merchant.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get merchant user: %w", err)
+	}
+	c.merchantCountry = models.MerchantCountry(c.merchant, merchantUser)
+	c.countriesKnown = true
+	return nil
+}
+
+// spent is what the payer has already paid within the cap's period
+func (c *check) spent(control *models.SpendingControl) (float64, error) {
+	since := periodStart(control.Period, time.Now().UTC())
+	if control.Scope == models.SpendingScopeForeign {
+		return c.service.repo.SumForeignSpend(c.tx.SenderID, c.payerCountry, since)
+	}
+	return c.service.repo.SumCategorySpend(c.tx.SenderID, control.Category, since)
+}
+
+func (s *service) save(userID, enterpriseID, setBy uint, req ControlRequest) (*models.SpendingControl, error) {
+	control := &models.SpendingControl{
+		UserID:       userID,
+		EnterpriseID: enterpriseID,
+		Scope:        req.Scope,
+		Action:       req.Action,
+		SetBy:        setBy,
+	}
+
+	switch req.Scope {
+	case models.SpendingScopeCategory:
+		category := normalizeCategory(req.Category)
+		if !models.ControlledCategories[category] {
+			return nil, ErrInvalidCategory
+		}
+		control.Category = category
+	case models.SpendingScopeForeign:
+	default:
+		return nil, ErrInvalidScope
+	}
+
+	switch req.Action {
+	case models.SpendingActionBlock:
+	case models.SpendingActionCap:
+		if req.CapAmount <= 0 || (req.Period != models.SpendingPeriodDaily && req.Period != models.SpendingPeriodMonthly) {
+			return nil, ErrInvalidCap
+		}
+		control.CapAmount = math.Round(req.CapAmount*100) / 100
+		control.Period = req.Period
+	default:
+		return nil, ErrInvalidAction
+	}
+
+	if err := s.repo.Save(control); err != nil {
+		return nil, err
+	}
+	return control, nil
+}
+
+// control loads one of the user's controls; others' are not found so
+// they aren't leaked
+func (s *service) control(controlID, userID uint) (*models.SpendingControl, error) {
+	control, err := s.repo.Get(controlID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSpendingControlNotFound) {
+			return nil, ErrControlNotFound
+		}
+		return nil, err
+	}
+	if control.UserID != userID {
+		return nil, ErrControlNotFound
+	}
+	return control, nil
+}
+
+// authorize requires the actor to be an owner or admin of the organization
+// and the user to be its member. Non-members get not found so
+// organizations aren't leaked.
+func (s *service) authorize(actorID, orgID, memberID uint) error {
+	actor, err := s.enterprises.GetMember(orgID, actorID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrEnterpriseMemberNotFound) {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+	allowed := false
+	for _, role := range managingRoles {
+		if actor.Role == role {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return ErrRoleForbidden
+	}
+
+	if _, err := s.enterprises.GetMember(orgID, memberID); err != nil {
+		if errors.Is(err, repositories.ErrEnterpriseMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// declined picks the decline for the control's scope
+func declined(control *models.SpendingControl, category, foreign error) error {
+	if control.Scope == models.SpendingScopeForeign {
+		return foreign
+	}
+	return category
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func periodStart(period string, now time.Time) time.Time {
+	if period == models.SpendingPeriodMonthly {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,25 @@
+package spendcontrol
+
+import "orus/internal/models"
+
+// ControlRequest blocks or caps payments to a merchant category or to
+// foreign merchants. Setting a control for a scope and category that
+// already has one replaces it.
+type ControlRequest struct {
+	Scope     string  `json:"scope" validate:"required,oneof=category foreign"`
+	Category  string  `json:"category" validate:"omitempty,max=30"`
+	Action    string  `json:"action" validate:"required,oneof=block cap"`
+	CapAmount float64 `json:"cap_amount" validate:"gte=0"`
+	Period    string  `json:"period" validate:"omitempty,oneof=daily monthly"`
+}
+
+// nonPurchases are transaction types a merchant sends back to a customer;
+// spending controls never apply to them
+var nonPurchases = map[string]bool{
+	models.TransactionTypeRefund:     true,
+	models.TransactionTypeChargeback: true,
+	models.TransactionTypeReversal:   true,
+}
+
+// managingRoles are the organization roles that set members' controls
+var managingRoles = []string{models.EnterpriseRoleOwner, models.EnterpriseRoleAdmin}
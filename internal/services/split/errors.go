@@ -0,0 +1,25 @@
+package split
+
+import "errors"
+
+// Service errors
+var (
+	ErrSplitNotFound          = errors.New("split not found")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrTransactionNotOwned    = errors.New("only bills you paid can be split")
+	ErrAlreadySplit           = errors.New("transaction has already been split")
+	ErrInvalidAmount          = errors.New("amount must be greater than zero")
+	ErrTitleRequired          = errors.New("title is required")
+	ErrNoParticipants         = errors.New("at least one participant is required")
+	ErrTooManyParticipants    = errors.New("too many participants")
+	ErrParticipantNotFound    = errors.New("participant not found")
+	ErrDuplicateParticipant   = errors.New("participant listed more than once")
+	ErrOrganizerIsParticipant = errors.New("organizer cannot be a participant")
+	ErrMixedShares            = errors.New("give every participant an amount or none")
+	ErrSharesExceedTotal      = errors.New("shares exceed the split total")
+	ErrNotParticipant         = errors.New("you have no share in this split")
+	ErrShareAlreadyPaid       = errors.New("share has already been paid")
+	ErrNotPayable             = errors.New("split is no longer accepting payments")
+	ErrNotOrganizer           = errors.New("only the organizer can do this")
+	ErrNotCancellable         = errors.New("splits can only be cancelled before anyone pays")
+)
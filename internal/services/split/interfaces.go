@@ -0,0 +1,32 @@
+package split
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// TransactionService defines the payment processing used to pay shares
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
+
+// Notifier tells participants about their shares and organizers about payments
+type Notifier interface {
+	SendSplitRequest(ctx context.Context, userID uint, split *models.Split, share *models.SplitShare, reminder bool) error
+	SendSplitPaid(ctx context.Context, userID uint, split *models.Split, share *models.SplitShare) error
+}
+
+// Service manages split bills between users
+type Service interface {
+	Create(ctx context.Context, organizerID uint, req CreateRequest) (*models.Split, error)
+	List(ctx context.Context, userID uint, role string, limit, offset int) ([]models.Split, int64, error)
+	Get(ctx context.Context, userID, splitID uint) (*models.Split, error)
+	// Pay settles the user's share from their wallet into the organizer's
+	Pay(ctx context.Context, userID, splitID uint) (*PaymentResult, error)
+	// Remind nudges participants who haven't paid yet
+	Remind(ctx context.Context, organizerID, splitID uint) (int, error)
+	Cancel(ctx context.Context, organizerID, splitID uint) (*models.Split, error)
+
+	// ProcessReminders sends due reminders for unpaid shares
+	ProcessReminders(ctx context.Context) (int, error)
+}
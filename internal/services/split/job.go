@@ -0,0 +1,24 @@
+package split
+
+import (
+	"context"
+	"log"
+)
+
+// Job reminds participants about unpaid split shares
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the split service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "split-reminders" }
+
+func (j *Job) Run(ctx context.Context) error {
+	sent, err := j.service.ProcessReminders(ctx)
+	if sent > 0 {
+		log.Printf("Sent %d split payment reminders", sent)
+	}
+	return err
+}
@@ -0,0 +1,420 @@
+package split
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxParticipants caps how many people a bill can be split with
+	MaxParticipants = 20
+	// ReminderInterval is the minimum time between reminders for one share
+	ReminderInterval = 24 * time.Hour
+	// ManualReminderCooldown stops organizers re-sending reminders too often
+	ManualReminderCooldown = time.Hour
+	// MaxReminders caps automatic reminders per share
+	MaxReminders = 5
+
+	reminderBatchSize = 100
+)
+
+type service struct {
+	repo           repositories.SplitRepository
+	txRepo         repositories.TransactionRepository
+	users          repositories.UserRepository
+	transactionSvc TransactionService
+	notifier       Notifier
+}
+
+// NewService creates a new split bill service
+func NewService(
+	repo repositories.SplitRepository,
+	txRepo repositories.TransactionRepository,
+	users repositories.UserRepository,
+	transactionSvc TransactionService,
+	notifier Notifier,
+) Service {
+	return &service{
+		repo:           repo,
+		txRepo:         txRepo,
+		users:          users,
+		transactionSvc: transactionSvc,
+		notifier:       notifier,
+	}
+}
+
+// Create splits a bill between the organizer and participants. Without
+// explicit amounts the bill is divided evenly, with the organizer absorbing
+// any leftover cents; with amounts, the organizer's share is the remainder.
+func (s *service) Create(ctx context.Context, organizerID uint, req CreateRequest) (*models.Split, error) {
+	split := &models.Split{
+		OrganizerID: organizerID,
+		Title:       strings.TrimSpace(req.Title),
+		Currency:    "USD",
+		TotalAmount: round2(req.Amount),
+		Status:      models.SplitStatusOpen,
+	}
+
+	if req.TransactionID != nil {
+		tx, err := s.txRepo.FindByID(*req.TransactionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTransactionNotFound
+			}
+			return nil, fmt.Errorf("failed to get transaction: %w", err)
+		}
+		if tx.SenderID != organizerID || tx.Status != "completed" {
+			return nil, ErrTransactionNotOwned
+		}
+		if _, err := s.repo.GetByTransactionID(tx.ID); err == nil {
+			return nil, ErrAlreadySplit
+		} else if !errors.Is(err, repositories.ErrSplitNotFound) {
+			return nil, err
+		}
+
+		split.TransactionID = &tx.ID
+		split.TotalAmount = round2(tx.Amount)
+		if tx.Currency != "" {
+			split.Currency = tx.Currency
+		}
+		if split.Title == "" {
+			split.Title = tx.Description
+		}
+		if split.Title == "" && tx.MerchantName != "" {
+			split.Title = tx.MerchantName
+		}
+	}
+
+	if split.TotalAmount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if split.Title == "" {
+		return nil, ErrTitleRequired
+	}
+
+	shares, organizerShare, err := s.buildShares(organizerID, split.TotalAmount, req.Participants)
+	if err != nil {
+		return nil, err
+	}
+	split.Shares = shares
+	split.ShareCount = len(shares)
+	split.OrganizerShare = organizerShare
+
+	if err := s.repo.Create(split); err != nil {
+		if split.TransactionID != nil {
+			if _, getErr := s.repo.GetByTransactionID(*split.TransactionID); getErr == nil {
+				return nil, ErrAlreadySplit
+			}
+		}
+		return nil, err
+	}
+
+	for i := range split.Shares {
+		s.notifyRequest(ctx, split, &split.Shares[i])
+	}
+	return split, nil
+}
+
+func (s *service) buildShares(organizerID uint, total float64, participants []ParticipantRequest) ([]models.SplitShare, float64, error) {
+	if len(participants) == 0 {
+		return nil, 0, ErrNoParticipants
+	}
+	if len(participants) > MaxParticipants {
+		return nil, 0, ErrTooManyParticipants
+	}
+
+	withAmount := 0
+	for _, p := range participants {
+		if p.Amount != nil {
+			withAmount++
+		}
+	}
+	if withAmount != 0 && withAmount != len(participants) {
+		return nil, 0, ErrMixedShares
+	}
+
+	seen := make(map[uint]bool, len(participants))
+	shares := make([]models.SplitShare, 0, len(participants))
+	for _, p := range participants {
+		userID, err := s.resolveParticipant(p)
+		if err != nil {
+			return nil, 0, err
+		}
+		if userID == organizerID {
+			return nil, 0, ErrOrganizerIsParticipant
+		}
+		if seen[userID] {
+			return nil, 0, ErrDuplicateParticipant
+		}
+		seen[userID] = true
+
+		share := models.SplitShare{UserID: userID, Status: models.SplitShareStatusPending}
+		if p.Amount != nil {
+			share.Amount = round2(*p.Amount)
+			if share.Amount <= 0 {
+				return nil, 0, ErrInvalidAmount
+			}
+		}
+		shares = append(shares, share)
+	}
+
+	totalCents := int64(math.Round(total * 100))
+	if withAmount == 0 {
+		each := totalCents / int64(len(shares)+1)
+		if each == 0 {
+			return nil, 0, ErrInvalidAmount
+		}
+		for i := range shares {
+			shares[i].Amount = float64(each) / 100
+		}
+		return shares, float64(totalCents-each*int64(len(shares))) / 100, nil
+	}
+
+	var owedCents int64
+	for _, share := range shares {
+		owedCents += int64(math.Round(share.Amount * 100))
+	}
+	if owedCents > totalCents {
+		return nil, 0, ErrSharesExceedTotal
+	}
+	return shares, float64(totalCents-owedCents) / 100, nil
+}
+
+func (s *service) resolveParticipant(p ParticipantRequest) (uint, error) {
+	if p.UserID != 0 {
+		user, err := s.users.GetByID(p.UserID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				return 0, ErrParticipantNotFound
+			}
+			return 0, fmt.Errorf("failed to get participant: %w", err)
+		}
+		return user.ID, nil
+	}
+
+	email := strings.TrimSpace(p.Email)
+	if email == "" {
+		return 0, ErrParticipantNotFound
+	}
+	user, err := s.users.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return 0, ErrParticipantNotFound
+		}
+		return 0, fmt.Errorf("failed to get participant: %w", err)
+	}
+	return user.ID, nil
+}
+
+func (s *service) List(ctx context.Context, userID uint, role string, limit, offset int) ([]models.Split, int64, error) {
+	return s.repo.GetByUserID(userID, role, limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, userID, splitID uint) (*models.Split, error) {
+	split, err := s.repo.GetByID(splitID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSplitNotFound) {
+			return nil, ErrSplitNotFound
+		}
+		return nil, err
+	}
+	// Don't reveal splits to outsiders
+	if split.OrganizerID != userID && shareFor(split, userID) == nil {
+		return nil, ErrSplitNotFound
+	}
+	return split, nil
+}
+
+func (s *service) Pay(ctx context.Context, userID, splitID uint) (*PaymentResult, error) {
+	split, err := s.Get(ctx, userID, splitID)
+	if err != nil {
+		return nil, err
+	}
+	share := shareFor(split, userID)
+	if share == nil {
+		return nil, ErrNotParticipant
+	}
+	if share.Status == models.SplitShareStatusPaid {
+		return nil, ErrShareAlreadyPaid
+	}
+	if !split.IsPayable() {
+		return nil, ErrNotPayable
+	}
+
+	// Claim the share before moving money so it can't be paid twice
+	ok, err := s.repo.ClaimShare(split.ID, share.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotPayable
+	}
+
+	now := time.Now()
+	tx, err := s.transactionSvc.ProcessTransaction(ctx, &models.Transaction{
+		Type:          models.TransactionTypeP2PTransfer,
+		SenderID:      userID,
+		ReceiverID:    split.OrganizerID,
+		Amount:        share.Amount,
+		Currency:      split.Currency,
+		Description:   fmt.Sprintf("Split: %s", split.Title),
+		Status:        "pending",
+		TransactionID: fmt.Sprintf("SPLIT-%d-%d-%d", split.ID, share.ID, now.UnixNano()),
+		PaymentType:   "split",
+		PaymentMethod: "wallet",
+		Metadata: models.NewJSON(map[string]interface{}{
+			"split_id": split.ID,
+		}),
+	})
+	if err != nil {
+		if releaseErr := s.repo.ReleaseShare(share.ID); releaseErr != nil {
+			log.Printf("Failed to release share %d of split %d after failed payment: %v", share.ID, split.ID, releaseErr)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.RecordPayment(split.ID, share.ID, tx.ID, share.Amount, tx.ProcessedAt); err != nil {
+		// The payment went through; don't report failure to the payer
+		log.Printf("Failed to record payment of share %d on split %d: %v", share.ID, split.ID, err)
+	}
+	if updated, err := s.repo.GetByID(split.ID); err == nil {
+		split = updated
+		share = shareFor(split, userID)
+	}
+
+	if err := s.notifier.SendSplitPaid(ctx, split.OrganizerID, split, share); err != nil {
+		log.Printf("Failed to notify organizer of split %d: %v", split.ID, err)
+	}
+	return &PaymentResult{Split: split, Share: share, Transaction: tx}, nil
+}
+
+func (s *service) Remind(ctx context.Context, organizerID, splitID uint) (int, error) {
+	split, err := s.organizerSplit(organizerID, splitID)
+	if err != nil {
+		return 0, err
+	}
+	if !split.IsPayable() {
+		return 0, ErrNotPayable
+	}
+
+	now := time.Now()
+	sent := 0
+	for i := range split.Shares {
+		share := &split.Shares[i]
+		if share.Status != models.SplitShareStatusPending {
+			continue
+		}
+		if share.LastReminderAt != nil && now.Sub(*share.LastReminderAt) < ManualReminderCooldown {
+			continue
+		}
+		if s.remind(ctx, split, share, now) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *service) Cancel(ctx context.Context, organizerID, splitID uint) (*models.Split, error) {
+	split, err := s.organizerSplit(organizerID, splitID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.Cancel(split.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotCancellable
+	}
+	split.Status = models.SplitStatusCancelled
+	return split, nil
+}
+
+func (s *service) ProcessReminders(ctx context.Context) (int, error) {
+	now := time.Now()
+	candidates, err := s.repo.GetReminderCandidates(now.Add(-ReminderInterval), MaxReminders, reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	splits := make(map[uint]*models.Split)
+	sent := 0
+	for i := range candidates {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		share := &candidates[i]
+		split, ok := splits[share.SplitID]
+		if !ok {
+			split, err = s.repo.GetByID(share.SplitID)
+			if err != nil {
+				log.Printf("Failed to load split %d for reminders: %v", share.SplitID, err)
+				continue
+			}
+			splits[share.SplitID] = split
+		}
+		if s.remind(ctx, split, share, now) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *service) remind(ctx context.Context, split *models.Split, share *models.SplitShare, now time.Time) bool {
+	if err := s.notifier.SendSplitRequest(ctx, share.UserID, split, share, true); err != nil {
+		log.Printf("Failed to remind user %d about split %d: %v", share.UserID, split.ID, err)
+		return false
+	}
+	share.LastReminderAt = &now
+	share.ReminderCount++
+	if err := s.repo.UpdateShare(share); err != nil {
+		log.Printf("Failed to record reminder for share %d: %v", share.ID, err)
+		return false
+	}
+	return true
+}
+
+func (s *service) notifyRequest(ctx context.Context, split *models.Split, share *models.SplitShare) {
+	if err := s.notifier.SendSplitRequest(ctx, share.UserID, split, share, false); err != nil {
+		log.Printf("Failed to notify user %d about split %d: %v", share.UserID, split.ID, err)
+	}
+}
+
+func (s *service) organizerSplit(organizerID, splitID uint) (*models.Split, error) {
+	split, err := s.repo.GetByID(splitID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSplitNotFound) {
+			return nil, ErrSplitNotFound
+		}
+		return nil, err
+	}
+	if split.OrganizerID != organizerID {
+		if shareFor(split, organizerID) != nil {
+			return nil, ErrNotOrganizer
+		}
+		return nil, ErrSplitNotFound
+	}
+	return split, nil
+}
+
+func shareFor(split *models.Split, userID uint) *models.SplitShare {
+	for i := range split.Shares {
+		if split.Shares[i].UserID == userID {
+			return &split.Shares[i]
+		}
+	}
+	return nil
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
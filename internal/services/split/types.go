@@ -0,0 +1,27 @@
+package split
+
+import "orus/internal/models"
+
+// ParticipantRequest identifies a participant by user ID or email. Amount is
+// their share; leave it out on every participant to split evenly.
+type ParticipantRequest struct {
+	UserID uint     `json:"user_id"`
+	Email  string   `json:"email"`
+	Amount *float64 `json:"amount"`
+}
+
+// CreateRequest starts a split from one of the organizer's transactions or
+// from a plain amount
+type CreateRequest struct {
+	TransactionID *uint                `json:"transaction_id"`
+	Amount        float64              `json:"amount"`
+	Title         string               `json:"title"`
+	Participants  []ParticipantRequest `json:"participants"`
+}
+
+// PaymentResult is a paid share with the split it belongs to
+type PaymentResult struct {
+	Split       *models.Split       `json:"split"`
+	Share       *models.SplitShare  `json:"share"`
+	Transaction *models.Transaction `json:"transaction"`
+}
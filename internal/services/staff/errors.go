@@ -0,0 +1,21 @@
+package staff
+
+import "errors"
+
+// Service errors
+var (
+	ErrStaffNotFound    = errors.New("staff member not found")
+	ErrMerchantNotFound = errors.New("merchant profile not found")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrSelfInvite       = errors.New("you cannot add yourself as staff")
+	ErrStaffExists      = errors.New("user is already on your staff")
+	ErrTooManyStaff     = errors.New("staff limit reached")
+	ErrNotInvited       = errors.New("invitation is no longer pending")
+	ErrStaffInactive    = errors.New("staff membership is not active")
+	ErrScopeDenied      = errors.New("operator is not allowed to do this")
+	ErrPINNotSet        = errors.New("operator has not set a PIN")
+	ErrInvalidPIN       = errors.New("PIN must be 4 to 6 digits")
+	ErrWrongPIN         = errors.New("incorrect operator PIN")
+	ErrPINLocked        = errors.New("too many incorrect PINs, try again later")
+	ErrInvalidPeriod    = errors.New("report period is invalid")
+)
@@ -0,0 +1,33 @@
+package staff
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// Service manages a merchant's point-of-sale staff: invitations, scopes,
+// operator PINs and per-operator shift reports
+type Service interface {
+	// Merchant side
+	Invite(ctx context.Context, ownerID uint, req InviteRequest) (*models.MerchantStaff, error)
+	List(ctx context.Context, ownerID uint) ([]models.MerchantStaff, error)
+	Update(ctx context.Context, ownerID, staffID uint, req UpdateRequest) (*models.MerchantStaff, error)
+	Revoke(ctx context.Context, ownerID, staffID uint) error
+
+	// Staff side
+	Memberships(ctx context.Context, userID uint) ([]Membership, error)
+	Accept(ctx context.Context, userID, staffID uint) (*models.MerchantStaff, error)
+	Decline(ctx context.Context, userID, staffID uint) error
+	SetPIN(ctx context.Context, userID, staffID uint, pin string) error
+	// GetMembership returns the caller's active staff membership
+	GetMembership(ctx context.Context, userID, staffID uint) (*models.MerchantStaff, error)
+
+	// AuthorizeOperator checks that staffID is an active operator of the
+	// merchant with the given scope, and that pin is theirs. Repeated wrong
+	// PINs lock the operator out for a while.
+	AuthorizeOperator(merchantUserID, staffID uint, pin, scope string) (*models.MerchantStaff, error)
+
+	// ShiftReport totals charges and refunds per operator over [from, to)
+	ShiftReport(ctx context.Context, merchantUserID uint, from, to time.Time) (*ShiftReport, error)
+}
@@ -0,0 +1,401 @@
+package staff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxStaff caps how many staff a merchant can have, including invites
+	MaxStaff = 50
+	// MaxPINAttempts wrong PINs in a row lock the operator out for PINLockout
+	MaxPINAttempts = 5
+	PINLockout     = 15 * time.Minute
+	// MaxReportPeriod caps the span of a shift report
+	MaxReportPeriod = 31 * 24 * time.Hour
+)
+
+var pinPattern = regexp.MustCompile(`^[0-9]{4,6}$`)
+
+type service struct {
+	repo      repositories.MerchantStaffRepository
+	users     repositories.UserRepository
+	merchants repositories.MerchantRepository
+}
+
+func NewService(repo repositories.MerchantStaffRepository, users repositories.UserRepository, merchants repositories.MerchantRepository) Service {
+	return &service{repo: repo, users: users, merchants: merchants}
+}
+
+func (s *service) Invite(ctx context.Context, ownerID uint, req InviteRequest) (*models.MerchantStaff, error) {
+	merchant, err := s.merchant(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.resolveUser(req.UserID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == ownerID {
+		return nil, ErrSelfInvite
+	}
+
+	name := strings.TrimSpace(req.DisplayName)
+	if name == "" {
+		name = displayName(user.Name)
+	}
+
+	// Re-inviting someone who declined or was revoked reuses their record
+	existing, err := s.repo.GetByMerchantAndUser(merchant.ID, user.ID)
+	if err != nil && !errors.Is(err, repositories.ErrStaffNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Status == models.StaffStatusInvited || existing.Status == models.StaffStatusActive {
+			return nil, ErrStaffExists
+		}
+		existing.Status = models.StaffStatusInvited
+		existing.DisplayName = name
+		existing.CanCharge, existing.CanRefund, existing.CanViewReports = true, false, false
+		applyScopes(existing, req.Scopes)
+		existing.InvitedBy = ownerID
+		existing.AcceptedAt = nil
+		existing.RevokedAt = nil
+		clearPIN(existing)
+		if err := s.repo.Update(existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	current, err := s.repo.ListByMerchant(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	open := 0
+	for _, member := range current {
+		if member.Status == models.StaffStatusInvited || member.Status == models.StaffStatusActive {
+			open++
+		}
+	}
+	if open >= MaxStaff {
+		return nil, ErrTooManyStaff
+	}
+
+	member := &models.MerchantStaff{
+		MerchantID:     merchant.ID,
+		MerchantUserID: ownerID,
+		UserID:         user.ID,
+		DisplayName:    name,
+		CanCharge:      true,
+		Status:         models.StaffStatusInvited,
+		InvitedBy:      ownerID,
+	}
+	applyScopes(member, req.Scopes)
+	if err := s.repo.Create(member); err != nil {
+		if _, getErr := s.repo.GetByMerchantAndUser(merchant.ID, user.ID); getErr == nil {
+			return nil, ErrStaffExists
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *service) List(ctx context.Context, ownerID uint) ([]models.MerchantStaff, error) {
+	return s.repo.ListByMerchant(ownerID)
+}
+
+func (s *service) Update(ctx context.Context, ownerID, staffID uint, req UpdateRequest) (*models.MerchantStaff, error) {
+	member, err := s.owned(ownerID, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status == models.StaffStatusRevoked || member.Status == models.StaffStatusDeclined {
+		return nil, ErrStaffInactive
+	}
+	if req.DisplayName != nil {
+		if name := strings.TrimSpace(*req.DisplayName); name != "" {
+			member.DisplayName = name
+		}
+	}
+	applyScopes(member, req.Scopes)
+	if err := s.repo.Update(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *service) Revoke(ctx context.Context, ownerID, staffID uint) error {
+	member, err := s.owned(ownerID, staffID)
+	if err != nil {
+		return err
+	}
+	if member.Status == models.StaffStatusRevoked {
+		return nil
+	}
+	now := time.Now()
+	member.Status = models.StaffStatusRevoked
+	member.RevokedAt = &now
+	clearPIN(member)
+	return s.repo.Update(member)
+}
+
+func (s *service) Memberships(ctx context.Context, userID uint) ([]Membership, error) {
+	staff, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	memberships := make([]Membership, 0, len(staff))
+	for _, member := range staff {
+		m := Membership{MerchantStaff: member}
+		if merchant, err := s.merchants.GetByUserID(member.MerchantUserID); err == nil {
+			m.BusinessName = merchant.BusinessName
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, nil
+}
+
+func (s *service) Accept(ctx context.Context, userID, staffID uint) (*models.MerchantStaff, error) {
+	member, err := s.mine(userID, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != models.StaffStatusInvited {
+		return nil, ErrNotInvited
+	}
+	now := time.Now()
+	member.Status = models.StaffStatusActive
+	member.AcceptedAt = &now
+	if err := s.repo.Update(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *service) Decline(ctx context.Context, userID, staffID uint) error {
+	member, err := s.mine(userID, staffID)
+	if err != nil {
+		return err
+	}
+	if member.Status != models.StaffStatusInvited {
+		return ErrNotInvited
+	}
+	member.Status = models.StaffStatusDeclined
+	return s.repo.Update(member)
+}
+
+func (s *service) SetPIN(ctx context.Context, userID, staffID uint, pin string) error {
+	if !pinPattern.MatchString(pin) {
+		return ErrInvalidPIN
+	}
+	member, err := s.GetMembership(ctx, userID, staffID)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	now := time.Now()
+	member.PINHash = string(hash)
+	member.PINSetAt = &now
+	member.FailedPINs = 0
+	member.PINLockedUntil = nil
+	return s.repo.Update(member)
+}
+
+func (s *service) GetMembership(ctx context.Context, userID, staffID uint) (*models.MerchantStaff, error) {
+	member, err := s.mine(userID, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != models.StaffStatusActive {
+		return nil, ErrStaffInactive
+	}
+	return member, nil
+}
+
+func (s *service) AuthorizeOperator(merchantUserID, staffID uint, pin, scope string) (*models.MerchantStaff, error) {
+	member, err := s.owned(merchantUserID, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != models.StaffStatusActive {
+		return nil, ErrStaffInactive
+	}
+	if !member.HasScope(scope) {
+		return nil, ErrScopeDenied
+	}
+	if member.PINHash == "" {
+		return nil, ErrPINNotSet
+	}
+
+	now := time.Now()
+	if member.PINLockedUntil != nil && now.Before(*member.PINLockedUntil) {
+		return nil, ErrPINLocked
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(member.PINHash), []byte(pin)); err != nil {
+		failed := member.FailedPINs + 1
+		var lockedUntil *time.Time
+		if failed >= MaxPINAttempts {
+			until := now.Add(PINLockout)
+			lockedUntil = &until
+			failed = 0
+		}
+		if err := s.repo.SetPINState(member.ID, failed, lockedUntil); err != nil {
+			return nil, err
+		}
+		if lockedUntil != nil {
+			return nil, ErrPINLocked
+		}
+		return nil, ErrWrongPIN
+	}
+	if member.FailedPINs > 0 || member.PINLockedUntil != nil {
+		if err := s.repo.SetPINState(member.ID, 0, nil); err != nil {
+			return nil, err
+		}
+	}
+	return member, nil
+}
+
+func (s *service) ShiftReport(ctx context.Context, merchantUserID uint, from, to time.Time) (*ShiftReport, error) {
+	if !to.After(from) || to.Sub(from) > MaxReportPeriod {
+		return nil, ErrInvalidPeriod
+	}
+	totals, err := s.repo.ShiftTotals(merchantUserID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.repo.ListByMerchant(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(staff))
+	for _, member := range staff {
+		names[member.ID] = member.DisplayName
+	}
+
+	report := &ShiftReport{From: from, To: to, Operators: make([]OperatorShift, 0, len(totals))}
+	for _, t := range totals {
+		shift := OperatorShift{
+			ShiftTotals: t,
+			Name:        "Merchant",
+			Net:         math.Round((t.ChargeTotal-t.RefundTotal)*100) / 100,
+		}
+		if t.OperatorID != nil {
+			shift.Name = names[*t.OperatorID]
+		}
+		report.Operators = append(report.Operators, shift)
+		report.ChargeTotal += t.ChargeTotal
+		report.RefundTotal += t.RefundTotal
+	}
+	report.ChargeTotal = math.Round(report.ChargeTotal*100) / 100
+	report.RefundTotal = math.Round(report.RefundTotal*100) / 100
+	report.Net = math.Round((report.ChargeTotal-report.RefundTotal)*100) / 100
+	return report, nil
+}
+
+func (s *service) merchant(ownerID uint) (*models.Merchant, error) {
+	merchant, err := s.merchants.GetByUserID(ownerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMerchantNotFound
+		}
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// owned loads a staff record belonging to the merchant
+func (s *service) owned(ownerID, staffID uint) (*models.MerchantStaff, error) {
+	member, err := s.get(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if member.MerchantUserID != ownerID {
+		return nil, ErrStaffNotFound
+	}
+	return member, nil
+}
+
+// mine loads a staff record belonging to the staff user
+func (s *service) mine(userID, staffID uint) (*models.MerchantStaff, error) {
+	member, err := s.get(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if member.UserID != userID {
+		return nil, ErrStaffNotFound
+	}
+	return member, nil
+}
+
+func (s *service) get(staffID uint) (*models.MerchantStaff, error) {
+	member, err := s.repo.GetByID(staffID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrStaffNotFound) {
+			return nil, ErrStaffNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *service) resolveUser(userID uint, email string) (*models.User, error) {
+	var user *models.User
+	var err error
+	if userID != 0 {
+		user, err = s.users.GetByID(userID)
+	} else if email = strings.TrimSpace(email); email != "" {
+		user, err = s.users.GetByEmail(email)
+	} else {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func applyScopes(member *models.MerchantStaff, scopes Scopes) {
+	if scopes.Charge != nil {
+		member.CanCharge = *scopes.Charge
+	}
+	if scopes.Refund != nil {
+		member.CanRefund = *scopes.Refund
+	}
+	if scopes.Reports != nil {
+		member.CanViewReports = *scopes.Reports
+	}
+}
+
+func clearPIN(member *models.MerchantStaff) {
+	member.PINHash = ""
+	member.PINSetAt = nil
+	member.FailedPINs = 0
+	member.PINLockedUntil = nil
+}
+
+// displayName shortens a full name to first name and last initial
+func displayName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) < 2 {
+		return name
+	}
+	last := []rune(parts[len(parts)-1])
+	return parts[0] + " " + string(last[0]) + "."
+}
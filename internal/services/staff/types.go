@@ -0,0 +1,52 @@
+package staff
+
+import (
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+// Scopes sets what a staff member may do. Nil fields are left unchanged on
+// update; on invite, charge defaults to allowed and the rest to denied.
+type Scopes struct {
+	Charge  *bool `json:"charge"`
+	Refund  *bool `json:"refund"`
+	Reports *bool `json:"reports"`
+}
+
+// InviteRequest invites a user, identified by ID or email, to the merchant's staff
+type InviteRequest struct {
+	UserID      uint   `json:"user_id"`
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name"`
+	Scopes
+}
+
+// UpdateRequest changes a staff member's name or scopes
+type UpdateRequest struct {
+	DisplayName *string `json:"display_name"`
+	Scopes
+}
+
+// Membership is a staff position as seen by the staff member
+type Membership struct {
+	models.MerchantStaff
+	BusinessName string `json:"business_name"`
+}
+
+// OperatorShift is one operator's activity in a shift report
+type OperatorShift struct {
+	repositories.ShiftTotals
+	Name string  `json:"name"`
+	Net  float64 `json:"net"`
+}
+
+// ShiftReport breaks a merchant's takings down by operator
+type ShiftReport struct {
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Operators   []OperatorShift `json:"operators"`
+	ChargeTotal float64         `json:"charge_total"`
+	RefundTotal float64         `json:"refund_total"`
+	Net         float64         `json:"net"`
+}
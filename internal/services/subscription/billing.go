@@ -0,0 +1,213 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"time"
+)
+
+const (
+	billingBatchSize = 100
+	// billingLease keeps other workers off a subscription while it is charged
+	billingLease = 10 * time.Minute
+)
+
+// RetryDelays is the dunning schedule: how long after each failed payment
+// the next attempt is made. Once it runs out the subscription is canceled.
+var RetryDelays = []time.Duration{
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	5 * 24 * time.Hour,
+}
+
+func (s *service) ProcessDue(ctx context.Context) (int, int, error) {
+	due, err := s.repo.GetDue(time.Now(), billingBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	charged, failed := 0, 0
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return charged, failed, err
+		}
+		subscription := &due[i]
+		claimed, err := s.repo.ClaimBilling(subscription, time.Now().Add(billingLease))
+		if err != nil {
+			log.Printf("Failed to claim subscription %d for billing: %v", subscription.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if s.bill(ctx, subscription) {
+			charged++
+		} else {
+			failed++
+		}
+	}
+	return charged, failed, nil
+}
+
+// bill charges the next period of a subscription and moves it on, or
+// schedules a retry and lets the customer know their payment failed
+func (s *service) bill(ctx context.Context, subscription *models.Subscription) bool {
+	plan, err := s.plan(subscription.PlanID)
+	if err != nil {
+		log.Printf("Failed to get plan for subscription %d: %v", subscription.ID, err)
+		return false
+	}
+
+	// A trial or past period ends where the next one starts
+	start := subscription.CurrentPeriodEnd
+	end := models.AddInterval(start, subscription.Interval, subscription.IntervalCount)
+	if subscription.Status == models.SubscriptionStatusPastDue {
+		// The failed period is the one being retried
+		start = subscription.CurrentPeriodStart
+		end = subscription.CurrentPeriodEnd
+	}
+
+	wasPastDue := subscription.Status == models.SubscriptionStatusPastDue
+	subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd = start, end
+	_, chargeErr := s.charge(ctx, subscription, plan, subscription.FailedAttempts+1)
+	now := time.Now()
+
+	if chargeErr == nil {
+		subscription.Status = models.SubscriptionStatusActive
+		subscription.FailedAttempts = 0
+		subscription.LastChargeAt = &now
+		subscription.NextBillingAt = &end
+		s.advance(subscription)
+		if wasPastDue {
+			s.email(ctx, subscription, EmailPaymentRecovered)
+		}
+		return true
+	}
+
+	subscription.FailedAttempts++
+	if subscription.FailedAttempts > len(RetryDelays) {
+		if _, err := s.close(subscription, nil, "payment_failed"); err != nil {
+			log.Printf("Failed to cancel unpaid subscription %d: %v", subscription.ID, err)
+			return false
+		}
+		subscription.Status = models.SubscriptionStatusCanceled
+		s.email(ctx, subscription, EmailCanceledUnpaid)
+		s.notifyMerchant(ctx, subscription, EventCanceled)
+		return false
+	}
+
+	retryAt := now.Add(RetryDelays[subscription.FailedAttempts-1])
+	subscription.Status = models.SubscriptionStatusPastDue
+	subscription.NextBillingAt = &retryAt
+	s.advance(subscription)
+	s.email(ctx, subscription, EmailPaymentFailed)
+	return false
+}
+
+// advance saves the outcome of a billing run, unless the customer or
+// merchant paused or canceled the subscription while it was being charged
+func (s *service) advance(subscription *models.Subscription) {
+	updates := map[string]interface{}{
+		"status":               subscription.Status,
+		"failed_attempts":      subscription.FailedAttempts,
+		"current_period_start": subscription.CurrentPeriodStart,
+		"current_period_end":   subscription.CurrentPeriodEnd,
+		"next_billing_at":      subscription.NextBillingAt,
+		"last_charge_at":       subscription.LastChargeAt,
+	}
+	saved, err := s.repo.Transition(subscription.ID, []string{
+		models.SubscriptionStatusTrialing,
+		models.SubscriptionStatusActive,
+		models.SubscriptionStatusPastDue,
+	}, updates)
+	if err != nil {
+		log.Printf("Failed to update subscription %d after billing: %v", subscription.ID, err)
+		return
+	}
+	if !saved {
+		log.Printf("Subscription %d changed status while being billed; billing outcome not applied", subscription.ID)
+	}
+}
+
+// charge takes one period's payment from the customer's wallet and records
+// the attempt either way
+func (s *service) charge(ctx context.Context, subscription *models.Subscription, plan *models.SubscriptionPlan, attempt int) (*models.Transaction, error) {
+	record := &models.SubscriptionCharge{
+		SubscriptionID: subscription.ID,
+		Amount:         subscription.Amount,
+		PeriodStart:    subscription.CurrentPeriodStart,
+		PeriodEnd:      subscription.CurrentPeriodEnd,
+		Attempt:        attempt,
+		Status:         models.SubscriptionChargeSucceeded,
+	}
+
+	tx, err := s.pay(ctx, subscription, plan)
+	if err != nil {
+		record.Status = models.SubscriptionChargeFailed
+		record.FailureReason = err.Error()
+	} else {
+		record.TransactionID = &tx.ID
+	}
+	if recordErr := s.repo.CreateCharge(record); recordErr != nil {
+		log.Printf("Failed to record charge for subscription %d: %v", subscription.ID, recordErr)
+	}
+
+	if err != nil {
+		s.notifyMerchant(ctx, subscription, EventPaymentFailed)
+		return nil, err
+	}
+	s.notifyMerchant(ctx, subscription, EventPaymentSucceeded)
+	return tx, nil
+}
+
+func (s *service) pay(ctx context.Context, subscription *models.Subscription, plan *models.SubscriptionPlan) (*models.Transaction, error) {
+	merchant, err := s.merchants.GetByUserID(subscription.MerchantID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+	now := time.Now()
+	return s.transactionSvc.ProcessTransaction(ctx, &models.Transaction{
+		Type:             "merchant_payment",
+		SenderID:         subscription.CustomerID,
+		ReceiverID:       subscription.MerchantID,
+		Amount:           subscription.Amount,
+		Currency:         subscription.Currency,
+		Description:      fmt.Sprintf("Subscription: %s", plan.Name),
+		Status:           "pending",
+		TransactionID:    fmt.Sprintf("SUB-%d-%d", subscription.ID, now.UnixNano()),
+		PaymentType:      "subscription",
+		PaymentMethod:    "wallet",
+		MerchantID:       &merchant.ID,
+		MerchantName:     merchant.BusinessName,
+		MerchantCategory: merchant.BusinessType,
+		Category:         "Subscription",
+		Metadata: models.NewJSON(map[string]interface{}{
+			"subscription_id":      subscription.ID,
+			"subscription_plan_id": plan.ID,
+		}),
+	})
+}
+
+func (s *service) email(ctx context.Context, subscription *models.Subscription, kind string) {
+	customer, err := s.users.GetByID(subscription.CustomerID)
+	if err != nil {
+		log.Printf("Failed to get customer for subscription %d email: %v", subscription.ID, err)
+		return
+	}
+	plan, err := s.plan(subscription.PlanID)
+	if err != nil {
+		log.Printf("Failed to get plan for subscription %d email: %v", subscription.ID, err)
+		return
+	}
+	if err := s.notifier.SendSubscriptionEmail(ctx, customer.Email, subscription, plan, kind); err != nil {
+		log.Printf("Failed to send %s email for subscription %d: %v", kind, subscription.ID, err)
+	}
+}
+
+func (s *service) notifyMerchant(ctx context.Context, subscription *models.Subscription, event string) {
+	if err := s.webhooks.Enqueue(ctx, subscription.MerchantID, event, subscription); err != nil {
+		log.Printf("Failed to queue %s webhook for subscription %d: %v", event, subscription.ID, err)
+	}
+}
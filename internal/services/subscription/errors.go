@@ -0,0 +1,21 @@
+package subscription
+
+import "errors"
+
+// Service errors
+var (
+	ErrPlanNotFound         = errors.New("subscription plan not found")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrNotMerchant          = errors.New("merchant profile not found")
+	ErrNameRequired         = errors.New("plan name is required")
+	ErrInvalidAmount        = errors.New("invalid amount")
+	ErrInvalidInterval      = errors.New("interval must be day, week, month or year, every 1 to 12")
+	ErrInvalidTrial         = errors.New("trial must be between 0 and 90 days")
+	ErrPlanArchived         = errors.New("subscription plan is no longer available")
+	ErrSelfSubscription     = errors.New("merchants cannot subscribe to their own plans")
+	ErrAlreadySubscribed    = errors.New("you already have a subscription to this plan")
+	ErrConsentRequired      = errors.New("you must authorize recurring charges to subscribe")
+	ErrNotCancelable        = errors.New("subscription is already canceled")
+	ErrNotPausable          = errors.New("only active subscriptions can be paused")
+	ErrNotPaused            = errors.New("subscription is not paused")
+)
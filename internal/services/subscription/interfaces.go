@@ -0,0 +1,50 @@
+package subscription
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// TransactionService defines the payment processing used to bill subscriptions
+type TransactionService interface {
+	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+}
+
+// Notifier emails customers about their subscriptions' billing
+type Notifier interface {
+	SendSubscriptionEmail(ctx context.Context, to string, subscription *models.Subscription, plan *models.SubscriptionPlan, kind string) error
+}
+
+// WebhookService notifies merchants about subscription events
+type WebhookService interface {
+	Enqueue(ctx context.Context, merchantUserID uint, event string, data interface{}) error
+}
+
+// Service manages merchant subscription plans and customers' recurring
+// wallet charges
+type Service interface {
+	// Merchant side
+	CreatePlan(ctx context.Context, merchantID uint, req PlanRequest) (*models.SubscriptionPlan, error)
+	ListPlans(ctx context.Context, merchantID uint, status string, limit, offset int) ([]models.SubscriptionPlan, int64, error)
+	GetPlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error)
+	// UpdatePlan changes a plan's name or description; pricing is fixed
+	UpdatePlan(ctx context.Context, merchantID, planID uint, req PlanUpdate) (*models.SubscriptionPlan, error)
+	ArchivePlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error)
+	ListSubscribers(ctx context.Context, merchantID uint, planID uint, status string, limit, offset int) ([]models.Subscription, int64, error)
+	GetSubscriber(ctx context.Context, merchantID, subscriptionID uint) (*Detail, error)
+	CancelSubscriber(ctx context.Context, merchantID, subscriptionID uint, reason string) (*models.Subscription, error)
+
+	// Customer side
+	// ViewPlan returns an active plan for a customer deciding to subscribe
+	ViewPlan(ctx context.Context, planID uint) (*PlanView, error)
+	Subscribe(ctx context.Context, customerID uint, req SubscribeRequest, client ClientInfo) (*Detail, error)
+	List(ctx context.Context, customerID uint, status string, limit, offset int) ([]models.Subscription, int64, error)
+	Get(ctx context.Context, customerID, subscriptionID uint) (*Detail, error)
+	Cancel(ctx context.Context, customerID, subscriptionID uint, reason string) (*models.Subscription, error)
+	Pause(ctx context.Context, customerID, subscriptionID uint) (*models.Subscription, error)
+	Resume(ctx context.Context, customerID, subscriptionID uint) (*models.Subscription, error)
+
+	// ProcessDue bills subscriptions whose next charge is due, retrying
+	// failed payments and canceling once the retries run out
+	ProcessDue(ctx context.Context) (charged, failed int, err error)
+}
@@ -0,0 +1,24 @@
+package subscription
+
+import (
+	"context"
+	"log"
+)
+
+// Job bills due subscriptions and retries failed payments
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the subscription service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "subscription-billing" }
+
+func (j *Job) Run(ctx context.Context) error {
+	charged, failed, err := j.service.ProcessDue(ctx)
+	if charged > 0 || failed > 0 {
+		log.Printf("Billed %d subscriptions, %d payments failed", charged, failed)
+	}
+	return err
+}
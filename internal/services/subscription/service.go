@@ -0,0 +1,468 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxPlanAmount is the largest amount a plan can charge per period
+	MaxPlanAmount = 10000.0
+	// MaxIntervalCount caps how many intervals a billing period can span
+	MaxIntervalCount = 12
+	// MaxTrialDays caps a plan's free trial
+	MaxTrialDays = 90
+
+	// recentCharges is how many billing attempts a subscription detail shows
+	recentCharges = 24
+)
+
+type service struct {
+	repo           repositories.SubscriptionRepository
+	users          repositories.UserRepository
+	merchants      repositories.MerchantRepository
+	transactionSvc TransactionService
+	notifier       Notifier
+	webhooks       WebhookService
+}
+
+// NewService creates a new subscription billing service
+func NewService(
+	repo repositories.SubscriptionRepository,
+	users repositories.UserRepository,
+	merchants repositories.MerchantRepository,
+	transactionSvc TransactionService,
+	notifier Notifier,
+	webhooks WebhookService,
+) Service {
+	return &service{
+		repo:           repo,
+		users:          users,
+		merchants:      merchants,
+		transactionSvc: transactionSvc,
+		notifier:       notifier,
+		webhooks:       webhooks,
+	}
+}
+
+func (s *service) CreatePlan(ctx context.Context, merchantID uint, req PlanRequest) (*models.SubscriptionPlan, error) {
+	if _, err := s.merchants.GetByUserID(merchantID); err != nil {
+		return nil, ErrNotMerchant
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	amount := round2(req.Amount)
+	if amount <= 0 || amount > MaxPlanAmount {
+		return nil, ErrInvalidAmount
+	}
+	count := req.IntervalCount
+	if count == 0 {
+		count = 1
+	}
+	switch req.Interval {
+	case models.SubscriptionIntervalDay, models.SubscriptionIntervalWeek,
+		models.SubscriptionIntervalMonth, models.SubscriptionIntervalYear:
+	default:
+		return nil, ErrInvalidInterval
+	}
+	if count < 1 || count > MaxIntervalCount {
+		return nil, ErrInvalidInterval
+	}
+	if req.TrialDays < 0 || req.TrialDays > MaxTrialDays {
+		return nil, ErrInvalidTrial
+	}
+
+	plan := &models.SubscriptionPlan{
+		MerchantID:    merchantID,
+		Name:          name,
+		Description:   strings.TrimSpace(req.Description),
+		Amount:        amount,
+		Currency:      "USD",
+		Interval:      req.Interval,
+		IntervalCount: count,
+		TrialDays:     req.TrialDays,
+		Status:        models.SubscriptionPlanStatusActive,
+	}
+	if err := s.repo.CreatePlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *service) ListPlans(ctx context.Context, merchantID uint, status string, limit, offset int) ([]models.SubscriptionPlan, int64, error) {
+	return s.repo.ListPlans(merchantID, status, limit, offset)
+}
+
+func (s *service) GetPlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error) {
+	plan, err := s.plan(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.MerchantID != merchantID {
+		return nil, ErrPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *service) UpdatePlan(ctx context.Context, merchantID, planID uint, req PlanUpdate) (*models.SubscriptionPlan, error) {
+	plan, err := s.GetPlan(ctx, merchantID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrNameRequired
+		}
+		plan.Name = name
+	}
+	if req.Description != nil {
+		plan.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := s.repo.UpdatePlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ArchivePlan stops new signups; existing subscribers keep being billed
+func (s *service) ArchivePlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error) {
+	plan, err := s.GetPlan(ctx, merchantID, planID)
+	if err != nil {
+		return nil, err
+	}
+	plan.Status = models.SubscriptionPlanStatusArchived
+	if err := s.repo.UpdatePlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *service) ListSubscribers(ctx context.Context, merchantID uint, planID uint, status string, limit, offset int) ([]models.Subscription, int64, error) {
+	return s.repo.List(repositories.SubscriptionFilter{MerchantID: merchantID, PlanID: planID, Status: status}, limit, offset)
+}
+
+func (s *service) GetSubscriber(ctx context.Context, merchantID, subscriptionID uint) (*Detail, error) {
+	subscription, err := s.get(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.MerchantID != merchantID {
+		return nil, ErrSubscriptionNotFound
+	}
+	return s.detail(subscription)
+}
+
+func (s *service) CancelSubscriber(ctx context.Context, merchantID, subscriptionID uint, reason string) (*models.Subscription, error) {
+	subscription, err := s.get(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.MerchantID != merchantID {
+		return nil, ErrSubscriptionNotFound
+	}
+	canceled, err := s.cancel(ctx, subscription, merchantID, reason)
+	if err != nil {
+		return nil, err
+	}
+	s.email(ctx, canceled, EmailCanceled)
+	return canceled, nil
+}
+
+func (s *service) ViewPlan(ctx context.Context, planID uint) (*PlanView, error) {
+	plan, err := s.plan(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.SubscriptionPlanStatusActive {
+		return nil, ErrPlanNotFound
+	}
+	view := &PlanView{SubscriptionPlan: plan}
+	if merchant, err := s.merchants.GetByUserID(plan.MerchantID); err == nil {
+		view.MerchantName = merchant.BusinessName
+	}
+	return view, nil
+}
+
+// Subscribe records the customer's consent and takes the first payment,
+// unless the plan starts with a trial
+func (s *service) Subscribe(ctx context.Context, customerID uint, req SubscribeRequest, client ClientInfo) (*Detail, error) {
+	plan, err := s.plan(req.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.SubscriptionPlanStatusActive {
+		return nil, ErrPlanArchived
+	}
+	if plan.MerchantID == customerID {
+		return nil, ErrSelfSubscription
+	}
+	if !req.Authorize {
+		return nil, ErrConsentRequired
+	}
+	live, err := s.repo.HasLive(customerID, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	if live {
+		return nil, ErrAlreadySubscribed
+	}
+	merchant, err := s.merchants.GetByUserID(plan.MerchantID)
+	if err != nil {
+		return nil, ErrNotMerchant
+	}
+
+	now := time.Now()
+	subscription := &models.Subscription{
+		PlanID:             plan.ID,
+		MerchantID:         plan.MerchantID,
+		CustomerID:         customerID,
+		Amount:             plan.Amount,
+		Currency:           plan.Currency,
+		Interval:           plan.Interval,
+		IntervalCount:      plan.IntervalCount,
+		Status:             models.SubscriptionStatusIncomplete,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   models.AddInterval(now, plan.Interval, plan.IntervalCount),
+	}
+	if plan.TrialDays > 0 {
+		trialEnd := now.AddDate(0, 0, plan.TrialDays)
+		subscription.Status = models.SubscriptionStatusTrialing
+		subscription.CurrentPeriodEnd = trialEnd
+		subscription.NextBillingAt = &trialEnd
+	}
+	consent := &models.SubscriptionConsent{
+		CustomerID:    customerID,
+		MerchantID:    plan.MerchantID,
+		PlanID:        plan.ID,
+		Amount:        plan.Amount,
+		Currency:      plan.Currency,
+		Interval:      plan.Interval,
+		IntervalCount: plan.IntervalCount,
+		Text:          consentText(merchant.BusinessName, plan),
+		IPAddress:     client.IPAddress,
+		UserAgent:     truncate(client.UserAgent, 255),
+		AcceptedAt:    now,
+	}
+	if err := s.repo.Create(subscription, consent); err != nil {
+		return nil, err
+	}
+
+	if subscription.Status == models.SubscriptionStatusIncomplete {
+		if _, err := s.charge(ctx, subscription, plan, 1); err != nil {
+			// Nothing was taken, so the signup is simply abandoned
+			if _, closeErr := s.close(subscription, nil, "initial_payment_failed"); closeErr != nil {
+				log.Printf("Failed to close subscription %d after its first payment failed: %v", subscription.ID, closeErr)
+			}
+			return nil, err
+		}
+		subscription.Status = models.SubscriptionStatusActive
+		subscription.LastChargeAt = &now
+		subscription.NextBillingAt = &subscription.CurrentPeriodEnd
+		if err := s.repo.Update(subscription); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.detail(subscription)
+}
+
+func (s *service) List(ctx context.Context, customerID uint, status string, limit, offset int) ([]models.Subscription, int64, error) {
+	return s.repo.List(repositories.SubscriptionFilter{CustomerID: customerID, Status: status}, limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, customerID, subscriptionID uint) (*Detail, error) {
+	subscription, err := s.own(customerID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(subscription)
+}
+
+func (s *service) Cancel(ctx context.Context, customerID, subscriptionID uint, reason string) (*models.Subscription, error) {
+	subscription, err := s.own(customerID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.cancel(ctx, subscription, customerID, reason)
+}
+
+// Pause stops billing until the customer resumes. The period already paid
+// for is kept.
+func (s *service) Pause(ctx context.Context, customerID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := s.own(customerID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	paused, err := s.repo.Transition(subscription.ID,
+		[]string{models.SubscriptionStatusActive, models.SubscriptionStatusTrialing},
+		map[string]interface{}{
+			"status":          models.SubscriptionStatusPaused,
+			"paused_at":       now,
+			"next_billing_at": nil,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !paused {
+		return nil, ErrNotPausable
+	}
+	return s.get(subscription.ID)
+}
+
+// Resume restarts billing. A customer who paused mid-period is next charged
+// when that period ends; otherwise a new period starts now.
+func (s *service) Resume(ctx context.Context, customerID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := s.own(customerID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	nextBilling := subscription.CurrentPeriodEnd
+	if now := time.Now(); nextBilling.Before(now) {
+		nextBilling = now
+	}
+	resumed, err := s.repo.Transition(subscription.ID,
+		[]string{models.SubscriptionStatusPaused},
+		map[string]interface{}{
+			"status":             models.SubscriptionStatusActive,
+			"paused_at":          nil,
+			"current_period_end": nextBilling,
+			"next_billing_at":    nextBilling,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, ErrNotPaused
+	}
+	return s.get(subscription.ID)
+}
+
+func (s *service) cancel(ctx context.Context, subscription *models.Subscription, by uint, reason string) (*models.Subscription, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "requested"
+	}
+	canceled, err := s.close(subscription, &by, truncate(reason, 255))
+	if err != nil {
+		return nil, err
+	}
+	s.notifyMerchant(ctx, canceled, EventCanceled)
+	return canceled, nil
+}
+
+// close cancels a subscription that hasn't been canceled yet and revokes
+// the customer's consent
+func (s *service) close(subscription *models.Subscription, by *uint, reason string) (*models.Subscription, error) {
+	now := time.Now()
+	closed, err := s.repo.Transition(subscription.ID,
+		[]string{
+			models.SubscriptionStatusIncomplete,
+			models.SubscriptionStatusTrialing,
+			models.SubscriptionStatusActive,
+			models.SubscriptionStatusPastDue,
+			models.SubscriptionStatusPaused,
+		},
+		map[string]interface{}{
+			"status":          models.SubscriptionStatusCanceled,
+			"canceled_at":     now,
+			"canceled_by":     by,
+			"cancel_reason":   reason,
+			"next_billing_at": nil,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrNotCancelable
+	}
+	if err := s.repo.RevokeConsent(subscription.ID, now); err != nil {
+		log.Printf("Failed to revoke consent for subscription %d: %v", subscription.ID, err)
+	}
+	return s.get(subscription.ID)
+}
+
+func (s *service) detail(subscription *models.Subscription) (*Detail, error) {
+	plan, err := s.plan(subscription.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := s.repo.ListCharges(subscription.ID, recentCharges)
+	if err != nil {
+		return nil, err
+	}
+	detail := &Detail{Subscription: subscription, Plan: plan, Charges: charges}
+	if consent, err := s.repo.GetConsent(subscription.ID); err == nil {
+		detail.Consent = consent
+	}
+	return detail, nil
+}
+
+// own loads one of the customer's subscriptions. Other customers'
+// subscriptions are reported as not found.
+func (s *service) own(customerID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := s.get(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.CustomerID != customerID {
+		return nil, ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+func (s *service) get(subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := s.repo.GetByID(subscriptionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *service) plan(planID uint) (*models.SubscriptionPlan, error) {
+	plan, err := s.repo.GetPlan(planID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSubscriptionPlanNotFound) {
+			return nil, ErrPlanNotFound
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func consentText(merchantName string, plan *models.SubscriptionPlan) string {
+	every := plan.Interval
+	if plan.IntervalCount > 1 {
+		every = fmt.Sprintf("%d %ss", plan.IntervalCount, plan.Interval)
+	}
+	text := fmt.Sprintf("I authorize %s to charge %.2f %s to my Orus wallet every %s for %q until I cancel.",
+		merchantName, plan.Amount, plan.Currency, every, plan.Name)
+	if plan.TrialDays > 0 {
+		text += fmt.Sprintf(" The first charge is taken after a %d day free trial.", plan.TrialDays)
+	}
+	return text
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
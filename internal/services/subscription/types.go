@@ -0,0 +1,61 @@
+package subscription
+
+import "orus/internal/models"
+
+// Emails sent to customers
+const (
+	EmailPaymentFailed    = "payment_failed"    // Dunning: a retry is scheduled
+	EmailPaymentRecovered = "payment_recovered" // A retry succeeded
+	EmailCanceledUnpaid   = "canceled_unpaid"   // Retries ran out
+	EmailCanceled         = "canceled"          // The merchant canceled
+)
+
+// Merchant webhook events
+const (
+	EventPaymentSucceeded = "subscription.payment_succeeded"
+	EventPaymentFailed    = "subscription.payment_failed"
+	EventCanceled         = "subscription.canceled"
+)
+
+// PlanRequest defines a new subscription plan
+type PlanRequest struct {
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	Amount        float64 `json:"amount"`
+	Interval      string  `json:"interval"`
+	IntervalCount int     `json:"interval_count"`
+	TrialDays     int     `json:"trial_days"`
+}
+
+// PlanUpdate changes a plan's presentation. Nil fields are left unchanged.
+type PlanUpdate struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// PlanView is a plan as shown to a prospective subscriber
+type PlanView struct {
+	*models.SubscriptionPlan
+	MerchantName string `json:"merchant_name"`
+}
+
+// SubscribeRequest signs a customer up to a plan. Authorize must be set to
+// consent to recurring charges.
+type SubscribeRequest struct {
+	PlanID    uint `json:"plan_id"`
+	Authorize bool `json:"authorize"`
+}
+
+// ClientInfo identifies where consent was given from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// Detail is a subscription with its plan, consent record and recent charges
+type Detail struct {
+	*models.Subscription
+	Plan    *models.SubscriptionPlan    `json:"plan"`
+	Consent *models.SubscriptionConsent `json:"consent,omitempty"`
+	Charges []models.SubscriptionCharge `json:"charges"`
+}
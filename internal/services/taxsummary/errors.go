@@ -0,0 +1,11 @@
+package taxsummary
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownFormat = errors.New("format must be json, csv or pdf")
+	ErrInvalidYear   = errors.New("year must be between 2000 and the current year")
+	ErrNotReady      = errors.New("tax summary is still being generated")
+	ErrFailed        = errors.New("tax summary could not be generated")
+)
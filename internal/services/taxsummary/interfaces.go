@@ -0,0 +1,21 @@
+package taxsummary
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service summarizes a user's tax year from their transactions
+type Service interface {
+	// Request summarizes the year in format. Accounts with many
+	// transactions get a report generated in the background instead,
+	// which they download once it completes.
+	Request(ctx context.Context, userID uint, year int, format string) (*Result, error)
+	GetReport(ctx context.Context, userID, id uint) (*models.TaxSummaryReport, error)
+	// Download returns a completed report's file
+	Download(ctx context.Context, userID, id uint) (*File, error)
+
+	// RunDue generates the pending background reports and returns how
+	// many it finished
+	RunDue(ctx context.Context) (int, error)
+}
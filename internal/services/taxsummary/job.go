@@ -0,0 +1,25 @@
+package taxsummary
+
+import (
+	"context"
+	"log"
+)
+
+// Job generates the tax summaries requested in the background from the
+// job scheduler
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the tax summary service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "tax-summaries" }
+
+func (j *Job) Run(ctx context.Context) error {
+	generated, err := j.service.RunDue(ctx)
+	if generated > 0 {
+		log.Printf("Generated %d tax summaries", generated)
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package taxsummary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"orus/internal/i18n"
+	"orus/internal/utils/pdf"
+	"strconv"
+	"strings"
+)
+
+var contentTypes = map[string]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv",
+	FormatPDF:  "application/pdf",
+}
+
+// render lays the summary out as a file in format, written in lang
+func render(summary *Summary, format, lang string) (*File, error) {
+	var content []byte
+	switch format {
+	case FormatJSON:
+		encoded, err := json.Marshal(summary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tax summary: %w", err)
+		}
+		content = encoded
+	case FormatCSV:
+		encoded, err := renderCSV(summary)
+		if err != nil {
+			return nil, err
+		}
+		content = encoded
+	case FormatPDF:
+		content = pdf.Render(summaryLines(summary, lang))
+	default:
+		return nil, ErrUnknownFormat
+	}
+	return &File{Name: fileName(summary.Year, format), ContentType: contentTypes[format], Content: content}, nil
+}
+
+// renderCSV writes a row per currency, month and category, then a total
+// row per currency with the month and category left empty
+func renderCSV(summary *Summary) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"year", "month", "category", "currency",
+		"income", "merchant_revenue", "refunds_received", "fees_paid", "transactions"})
+
+	row := func(month, category, currency string, a Amounts) {
+		w.Write([]string{
+			strconv.Itoa(summary.Year), month, category, currency,
+			strconv.FormatFloat(a.Income, 'f', 2, 64),
+			strconv.FormatFloat(a.MerchantRevenue, 'f', 2, 64),
+			strconv.FormatFloat(a.RefundsReceived, 'f', 2, 64),
+			strconv.FormatFloat(a.FeesPaid, 'f', 2, 64),
+			strconv.FormatInt(a.Transactions, 10),
+		})
+	}
+	for _, cs := range summary.Currencies {
+		for _, month := range cs.Months {
+			for _, cat := range month.Categories {
+				row(strconv.Itoa(month.Month), cat.Category, cs.Currency, cat.Amounts)
+			}
+		}
+		row("", "", cs.Currency, cs.Total)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write tax summary: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func summaryLines(summary *Summary, lang string) []string {
+	t := func(key string, args ...interface{}) string { return i18n.T(lang, key, args...) }
+
+	rule := strings.Repeat("-", pdf.LineWidth)
+	lines := []string{
+		t("doc.tax_summary", summary.Year),
+		"",
+		fmt.Sprintf("%-10s%s", t("doc.date")+":", i18n.FormatDate(lang, summary.GeneratedAt)),
+	}
+	if len(summary.Currencies) == 0 {
+		return append(lines, "", t("doc.no_transactions"))
+	}
+
+	for _, cs := range summary.Currencies {
+		decimals := i18n.CurrencyDecimals(cs.Currency)
+		money := func(amount float64) string { return i18n.FormatNumber(lang, amount, decimals) }
+		row := func(label string, a Amounts) string {
+			if len(label) > 18 {
+				label = label[:15] + "..."
+			}
+			return fmt.Sprintf("%-18s %14s %14s %14s %14s",
+				label, money(a.Income), money(a.MerchantRevenue), money(a.RefundsReceived), money(a.FeesPaid))
+		}
+		header := fmt.Sprintf("%-18s %14s %14s %14s %14s", "",
+			t("doc.income"), t("doc.merchant_revenue"), t("doc.refunds_received"), t("doc.fees_paid"))
+
+		lines = append(lines, "", cs.Currency, rule, header, rule)
+		for _, month := range cs.Months {
+			lines = append(lines, row(fmt.Sprintf("%d-%02d", summary.Year, month.Month), month.Amounts))
+		}
+		lines = append(lines, rule, row(t("doc.total"), cs.Total), "", t("doc.by_category"), rule, header, rule)
+		for _, cat := range cs.Categories {
+			lines = append(lines, row(cat.Category, cat.Amounts))
+		}
+		lines = append(lines, rule)
+	}
+	return lines
+}
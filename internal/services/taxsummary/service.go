@@ -0,0 +1,244 @@
+package taxsummary
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/i18n"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"slices"
+	"sort"
+	"time"
+)
+
+type service struct {
+	repo repositories.TaxSummaryRepository
+}
+
+// NewService creates the tax summary service
+func NewService(repo repositories.TaxSummaryRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Request(ctx context.Context, userID uint, year int, format string) (*Result, error) {
+	if !slices.Contains(Formats, format) {
+		return nil, ErrUnknownFormat
+	}
+	now := time.Now().UTC()
+	if year < firstYear || year > now.Year() {
+		return nil, ErrInvalidYear
+	}
+	from, to := yearRange(year)
+
+	count, err := s.repo.CountTransactions(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if count > asyncThreshold {
+		report, err := s.queue(userID, year, format, i18n.FromContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Report: report}, nil
+	}
+
+	summary, err := s.summarize(userID, year)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJSON {
+		return &Result{Summary: summary}, nil
+	}
+	file, err := render(summary, format, i18n.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &Result{File: file}, nil
+}
+
+// queue returns the user's unfinished report for the year and format, or
+// starts one
+func (s *service) queue(userID uint, year int, format, lang string) (*models.TaxSummaryReport, error) {
+	existing, err := s.repo.FindUnfinished(userID, year, format)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	report := &models.TaxSummaryReport{
+		UserID:   userID,
+		Year:     year,
+		Format:   format,
+		Language: lang,
+		Status:   models.TaxSummaryPending,
+	}
+	if err := s.repo.CreateReport(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *service) GetReport(ctx context.Context, userID, id uint) (*models.TaxSummaryReport, error) {
+	return s.repo.GetReport(userID, id)
+}
+
+func (s *service) Download(ctx context.Context, userID, id uint) (*File, error) {
+	report, err := s.repo.GetReport(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	switch report.Status {
+	case models.TaxSummaryCompleted:
+		return &File{Name: report.FileName, ContentType: contentTypes[report.Format], Content: report.Content}, nil
+	case models.TaxSummaryFailed:
+		return nil, ErrFailed
+	default:
+		return nil, ErrNotReady
+	}
+}
+
+func (s *service) RunDue(ctx context.Context) (int, error) {
+	generated := 0
+	for generated < batchSize {
+		if err := ctx.Err(); err != nil {
+			return generated, err
+		}
+		now := time.Now()
+		report, err := s.repo.Claim(now, now.Add(leaseDuration))
+		if err != nil || report == nil {
+			return generated, err
+		}
+
+		if err := s.generate(report); err != nil {
+			log.Printf("Tax summary %d failed: %v", report.ID, err)
+			report.Status = models.TaxSummaryFailed
+			report.Error = err.Error()
+		}
+		if err := s.repo.Finish(report); err != nil {
+			return generated, err
+		}
+		generated++
+	}
+	return generated, nil
+}
+
+// generate summarizes the report's year and keeps the file on it
+func (s *service) generate(report *models.TaxSummaryReport) error {
+	summary, err := s.summarize(report.UserID, report.Year)
+	if err != nil {
+		return err
+	}
+	file, err := render(summary, report.Format, report.Language)
+	if err != nil {
+		return err
+	}
+
+	completed := time.Now()
+	report.Status = models.TaxSummaryCompleted
+	report.FileName = file.Name
+	report.Content = file.Content
+	report.Error = ""
+	report.CompletedAt = &completed
+	return nil
+}
+
+// summarize adds up the user's year per currency, by month and category
+func (s *service) summarize(userID uint, year int) (*Summary, error) {
+	from, to := yearRange(year)
+	rows, err := s.repo.Summarize(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return buildSummary(year, rows), nil
+}
+
+func buildSummary(year int, rows []repositories.TaxSummaryRow) *Summary {
+	summary := &Summary{Year: year, Currencies: []CurrencySummary{}, GeneratedAt: time.Now().UTC()}
+
+	byCurrency := map[string]*CurrencySummary{}
+	categories := map[string]map[string]*CategorySummary{}
+	monthCategories := map[string]map[int]map[string]*CategorySummary{}
+	for _, row := range rows {
+		cs, ok := byCurrency[row.Currency]
+		if !ok {
+			cs = &CurrencySummary{Currency: row.Currency, Months: make([]MonthSummary, 12)}
+			for i := range cs.Months {
+				cs.Months[i] = MonthSummary{Month: i + 1, Categories: []CategorySummary{}}
+			}
+			byCurrency[row.Currency] = cs
+			categories[row.Currency] = map[string]*CategorySummary{}
+			monthCategories[row.Currency] = map[int]map[string]*CategorySummary{}
+		}
+		if row.Month < 1 || row.Month > 12 {
+			continue
+		}
+
+		cat, ok := categories[row.Currency][row.Category]
+		if !ok {
+			cat = &CategorySummary{Category: row.Category}
+			categories[row.Currency][row.Category] = cat
+		}
+		monthCats := monthCategories[row.Currency][row.Month]
+		if monthCats == nil {
+			monthCats = map[string]*CategorySummary{}
+			monthCategories[row.Currency][row.Month] = monthCats
+		}
+		monthCat, ok := monthCats[row.Category]
+		if !ok {
+			monthCat = &CategorySummary{Category: row.Category}
+			monthCats[row.Category] = monthCat
+		}
+
+		for _, amounts := range []*Amounts{&cs.Total, &cs.Months[row.Month-1].Amounts, &cat.Amounts, &monthCat.Amounts} {
+			amounts.add(row)
+		}
+	}
+
+	for currency, cs := range byCurrency {
+		cs.Categories = sortedCategories(categories[currency])
+		for month, cats := range monthCategories[currency] {
+			cs.Months[month-1].Categories = sortedCategories(cats)
+		}
+		summary.Currencies = append(summary.Currencies, *cs)
+	}
+	sort.Slice(summary.Currencies, func(i, j int) bool {
+		return summary.Currencies[i].Currency < summary.Currencies[j].Currency
+	})
+	return summary
+}
+
+func (a *Amounts) add(row repositories.TaxSummaryRow) {
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	switch row.Kind {
+	case repositories.TaxIncome:
+		a.Income = round(a.Income + row.Amount)
+	case repositories.TaxMerchantRevenue:
+		a.MerchantRevenue = round(a.MerchantRevenue + row.Amount)
+	case repositories.TaxRefundsReceived:
+		a.RefundsReceived = round(a.RefundsReceived + row.Amount)
+	case repositories.TaxFeesPaid:
+		a.FeesPaid = round(a.FeesPaid + row.Amount)
+	}
+	a.Transactions += row.Count
+}
+
+func sortedCategories(byName map[string]*CategorySummary) []CategorySummary {
+	categories := make([]CategorySummary, 0, len(byName))
+	for _, cat := range byName {
+		categories = append(categories, *cat)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Category < categories[j].Category })
+	return categories
+}
+
+// yearRange returns the UTC start of the year and of the next one
+func yearRange(year int) (time.Time, time.Time) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, 0)
+}
+
+// fileName names the summary's file
+func fileName(year int, format string) string {
+	return fmt.Sprintf("tax-summary-%d.%s", year, format)
+}
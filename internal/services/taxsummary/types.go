@@ -0,0 +1,85 @@
+package taxsummary
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// Summary formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatPDF  = "pdf"
+)
+
+// Formats lists the formats a summary can be downloaded in
+var Formats = []string{FormatJSON, FormatCSV, FormatPDF}
+
+// firstYear is the earliest year a summary can cover
+const firstYear = 2000
+
+// asyncThreshold is how many transactions a year may have before its
+// summary is generated in the background instead of during the request
+const asyncThreshold = 5000
+
+// leaseDuration is how long a background summary may take before another
+// instance assumes its instance died and takes it over
+const leaseDuration = 10 * time.Minute
+
+// batchSize bounds the background summaries generated per job run
+const batchSize = 20
+
+// Amounts adds up what a user earned, got back and paid over a period
+type Amounts struct {
+	Income          float64 `json:"income"`
+	MerchantRevenue float64 `json:"merchant_revenue"`
+	RefundsReceived float64 `json:"refunds_received"`
+	FeesPaid        float64 `json:"fees_paid"`
+	Transactions    int64   `json:"transactions"`
+}
+
+// CategorySummary is what one transaction category adds up to
+type CategorySummary struct {
+	Category string `json:"category"`
+	Amounts
+}
+
+// MonthSummary is what one month adds up to, in total and by category
+type MonthSummary struct {
+	Month int `json:"month"` // 1 to 12
+	Amounts
+	Categories []CategorySummary `json:"categories"`
+}
+
+// CurrencySummary is a year's summary of the amounts in one currency
+type CurrencySummary struct {
+	Currency   string            `json:"currency"`
+	Total      Amounts           `json:"total"`
+	Months     []MonthSummary    `json:"months"`
+	Categories []CategorySummary `json:"categories"`
+}
+
+// Summary is a user's tax year: income, merchant revenue, refunds
+// received and fees paid, per currency, by month and category. Months
+// are cut in UTC.
+type Summary struct {
+	Year        int               `json:"year"`
+	Currencies  []CurrencySummary `json:"currencies"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// File is a summary rendered for download
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Result is what requesting a summary returns: the summary, or its file,
+// when it was generated right away, or else the report generating it in
+// the background
+type Result struct {
+	Summary *Summary
+	File    *File
+	Report  *models.TaxSummaryReport
+}
@@ -0,0 +1,15 @@
+package terminal
+
+import "errors"
+
+// Service errors
+var (
+	ErrTerminalNotFound    = errors.New("terminal not found")
+	ErrMerchantNotFound    = errors.New("merchant profile not found")
+	ErrNameRequired        = errors.New("terminal name is required")
+	ErrTooManyTerminals    = errors.New("terminal limit reached")
+	ErrInvalidPairingCode  = errors.New("pairing code is invalid or has expired")
+	ErrInvalidCredentials  = errors.New("invalid terminal credentials")
+	ErrTerminalInactive    = errors.New("terminal is not active")
+	ErrTerminalDeactivated = errors.New("terminal has been deactivated")
+)
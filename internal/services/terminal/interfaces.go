@@ -0,0 +1,35 @@
+package terminal
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service manages merchants' point-of-sale terminals: registration,
+// device pairing, credentials and deactivation
+type Service interface {
+	// Merchant side
+	Register(ctx context.Context, ownerID uint, req RegisterRequest) (*Pairing, error)
+	// List returns a merchant's terminals, or every merchant's when
+	// merchantUserID is 0
+	List(ctx context.Context, merchantUserID uint, status string, limit, offset int) ([]models.Terminal, int64, error)
+	Get(ctx context.Context, ownerID, terminalID uint) (*models.Terminal, error)
+	// NewPairingCode issues a fresh pairing code. A paired terminal loses
+	// its credentials and must pair again.
+	NewPairingCode(ctx context.Context, ownerID, terminalID uint) (*Pairing, error)
+	Deactivate(ctx context.Context, ownerID, terminalID uint, reason string) (*models.Terminal, error)
+	Transactions(ctx context.Context, ownerID, terminalID uint, limit, offset int) ([]models.Transaction, int64, error)
+
+	// Admin side
+	AdminDeactivate(ctx context.Context, adminID, terminalID uint, reason string) (*models.Terminal, error)
+
+	// Device side
+	// Pair exchanges a pairing code for the terminal's credentials. The
+	// secret is only ever returned here.
+	Pair(ctx context.Context, req PairRequest) (*Credentials, error)
+	Authenticate(ctx context.Context, key, secret string) (*models.Terminal, error)
+
+	// ActiveTerminal checks that terminalID is one of the merchant's
+	// active terminals
+	ActiveTerminal(merchantUserID, terminalID uint) (*models.Terminal, error)
+}
@@ -0,0 +1,325 @@
+package terminal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxTerminals caps how many terminals a merchant can have in service
+	MaxTerminals = 100
+	// PairingCodeTTL is how long a pairing code can be used
+	PairingCodeTTL = 15 * time.Minute
+	// MaxNameLength caps a terminal's display name
+	MaxNameLength = 50
+
+	pairingCodeLength = 8
+	// Pairing codes are typed by hand, so easily confused characters are left out
+	pairingAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// lastSeenInterval limits how often authentication records activity
+	lastSeenInterval = time.Minute
+)
+
+type service struct {
+	repo      repositories.TerminalRepository
+	merchants repositories.MerchantRepository
+}
+
+func NewService(repo repositories.TerminalRepository, merchants repositories.MerchantRepository) Service {
+	return &service{repo: repo, merchants: merchants}
+}
+
+func (s *service) Register(ctx context.Context, ownerID uint, req RegisterRequest) (*Pairing, error) {
+	merchant, err := s.merchants.GetByUserID(ownerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMerchantNotFound
+		}
+		return nil, fmt.Errorf("failed to get merchant: %w", err)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if len(name) > MaxNameLength {
+		name = name[:MaxNameLength]
+	}
+
+	count, err := s.repo.CountActive(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxTerminals {
+		return nil, ErrTooManyTerminals
+	}
+
+	code, err := newPairingCode()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(PairingCodeTTL)
+	terminal := &models.Terminal{
+		MerchantID:       merchant.ID,
+		MerchantUserID:   ownerID,
+		Name:             name,
+		Status:           models.TerminalStatusPending,
+		PairingCodeHash:  hash(code),
+		PairingExpiresAt: &expiresAt,
+	}
+	if err := s.repo.Create(terminal); err != nil {
+		return nil, err
+	}
+	return &Pairing{Terminal: terminal, PairingCode: code, ExpiresAt: expiresAt}, nil
+}
+
+func (s *service) List(ctx context.Context, merchantUserID uint, status string, limit, offset int) ([]models.Terminal, int64, error) {
+	return s.repo.List(merchantUserID, status, limit, offset)
+}
+
+func (s *service) Get(ctx context.Context, ownerID, terminalID uint) (*models.Terminal, error) {
+	terminal, err := s.get(terminalID)
+	if err != nil {
+		return nil, err
+	}
+	// Other merchants can't tell a terminal exists
+	if terminal.MerchantUserID != ownerID {
+		return nil, ErrTerminalNotFound
+	}
+	return terminal, nil
+}
+
+func (s *service) NewPairingCode(ctx context.Context, ownerID, terminalID uint) (*Pairing, error) {
+	terminal, err := s.Get(ctx, ownerID, terminalID)
+	if err != nil {
+		return nil, err
+	}
+	if terminal.Status == models.TerminalStatusDeactivated {
+		return nil, ErrTerminalDeactivated
+	}
+
+	code, err := newPairingCode()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(PairingCodeTTL)
+	ok, err := s.repo.ResetPairing(terminal.ID, hash(code), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTerminalDeactivated
+	}
+
+	terminal, err = s.get(terminal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Pairing{Terminal: terminal, PairingCode: code, ExpiresAt: expiresAt}, nil
+}
+
+func (s *service) Deactivate(ctx context.Context, ownerID, terminalID uint, reason string) (*models.Terminal, error) {
+	terminal, err := s.Get(ctx, ownerID, terminalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.deactivate(terminal, ownerID, reason)
+}
+
+func (s *service) AdminDeactivate(ctx context.Context, adminID, terminalID uint, reason string) (*models.Terminal, error) {
+	terminal, err := s.get(terminalID)
+	if err != nil {
+		return nil, err
+	}
+	deactivated, err := s.deactivate(terminal, adminID, reason)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Terminal %d of merchant %d deactivated by admin %d", terminal.ID, terminal.MerchantUserID, adminID)
+	return deactivated, nil
+}
+
+func (s *service) Transactions(ctx context.Context, ownerID, terminalID uint, limit, offset int) ([]models.Transaction, int64, error) {
+	terminal, err := s.Get(ctx, ownerID, terminalID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListTransactions(terminal.ID, limit, offset)
+}
+
+func (s *service) Pair(ctx context.Context, req PairRequest) (*Credentials, error) {
+	code := normalizeCode(req.PairingCode)
+	if len(code) != pairingCodeLength {
+		return nil, ErrInvalidPairingCode
+	}
+	codeHash := hash(code)
+	terminal, err := s.repo.GetByPairingCode(codeHash)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTerminalNotFound) {
+			return nil, ErrInvalidPairingCode
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if terminal.PairingExpiresAt == nil || now.After(*terminal.PairingExpiresAt) {
+		return nil, ErrInvalidPairingCode
+	}
+
+	key, err := randomHex(10)
+	if err != nil {
+		return nil, err
+	}
+	key = "trm_" + key
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.Pair(terminal.ID, codeHash, map[string]interface{}{
+		"status":             models.TerminalStatusActive,
+		"pairing_code_hash":  "",
+		"pairing_expires_at": nil,
+		"credential_key":     key,
+		"secret_hash":        hash(secret),
+		"device_model":       truncate(strings.TrimSpace(req.DeviceModel), 100),
+		"device_serial":      truncate(strings.TrimSpace(req.DeviceSerial), 100),
+		"paired_at":          now,
+		"last_seen_at":       now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Another device used the code first, or it was replaced or deactivated
+	if !ok {
+		return nil, ErrInvalidPairingCode
+	}
+
+	return &Credentials{
+		TerminalID: terminal.ID,
+		MerchantID: terminal.MerchantID,
+		Name:       terminal.Name,
+		Key:        key,
+		Secret:     secret,
+	}, nil
+}
+
+func (s *service) Authenticate(ctx context.Context, key, secret string) (*models.Terminal, error) {
+	if key == "" || secret == "" {
+		return nil, ErrInvalidCredentials
+	}
+	terminal, err := s.repo.GetByCredentialKey(key)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTerminalNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(terminal.SecretHash)) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	switch terminal.Status {
+	case models.TerminalStatusActive:
+	case models.TerminalStatusDeactivated:
+		return nil, ErrTerminalDeactivated
+	default:
+		return nil, ErrTerminalInactive
+	}
+
+	now := time.Now()
+	if terminal.LastSeenAt == nil || now.Sub(*terminal.LastSeenAt) > lastSeenInterval {
+		if err := s.repo.Touch(terminal.ID, now); err != nil {
+			log.Printf("Failed to record activity for terminal %d: %v", terminal.ID, err)
+		} else {
+			terminal.LastSeenAt = &now
+		}
+	}
+	return terminal, nil
+}
+
+func (s *service) ActiveTerminal(merchantUserID, terminalID uint) (*models.Terminal, error) {
+	terminal, err := s.get(terminalID)
+	if err != nil {
+		return nil, err
+	}
+	if terminal.MerchantUserID != merchantUserID {
+		return nil, ErrTerminalNotFound
+	}
+	switch terminal.Status {
+	case models.TerminalStatusActive:
+		return terminal, nil
+	case models.TerminalStatusDeactivated:
+		return nil, ErrTerminalDeactivated
+	}
+	return nil, ErrTerminalInactive
+}
+
+func (s *service) deactivate(terminal *models.Terminal, by uint, reason string) (*models.Terminal, error) {
+	ok, err := s.repo.Deactivate(terminal.ID, by, truncate(strings.TrimSpace(reason), 255), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTerminalDeactivated
+	}
+	return s.get(terminal.ID)
+}
+
+func (s *service) get(terminalID uint) (*models.Terminal, error) {
+	terminal, err := s.repo.GetByID(terminalID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTerminalNotFound) {
+			return nil, ErrTerminalNotFound
+		}
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func newPairingCode() (string, error) {
+	buf := make([]byte, pairingCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate pairing code: %w", err)
+	}
+	for i, b := range buf {
+		// 256 is a multiple of the alphabet size, so there's no modulo bias
+		buf[i] = pairingAlphabet[int(b)%len(pairingAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// normalizeCode accepts codes typed in lower case or with separators
+func normalizeCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate terminal credentials: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package terminal
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// RegisterRequest registers a new terminal for the merchant
+type RegisterRequest struct {
+	Name string `json:"name"`
+}
+
+// Pairing is a terminal waiting to be paired. The code is shown once to
+// the merchant, who enters it on the device.
+type Pairing struct {
+	Terminal    *models.Terminal `json:"terminal"`
+	PairingCode string           `json:"pairing_code"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// PairRequest is sent by the device to claim a pairing code
+type PairRequest struct {
+	PairingCode  string `json:"pairing_code"`
+	DeviceModel  string `json:"device_model"`
+	DeviceSerial string `json:"device_serial"`
+}
+
+// Credentials authenticate a paired terminal through the X-Terminal-Key
+// and X-Terminal-Secret headers
+type Credentials struct {
+	TerminalID uint   `json:"terminal_id"`
+	MerchantID uint   `json:"merchant_id"`
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+	Secret     string `json:"secret"`
+}
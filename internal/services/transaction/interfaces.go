@@ -11,12 +11,34 @@ type WalletService interface {
 	Debit(ctx context.Context, userID uint, amount float64) error
 	Credit(ctx context.Context, userID uint, amount float64) error
 	UpdateBalanceOnly(ctx context.Context, userID uint, amount float64) error
+	EnforceLock(ctx context.Context, wallet *models.Wallet, operation, direction string, amount float64) error
 }
 
 type BalanceService interface {
 	ValidateBalance(ctx context.Context, userID uint, amount float64) error
 }
 
+// FraudService screens payments against merchant and platform fraud rules
+type FraudService interface {
+	Evaluate(ctx context.Context, tx *models.Transaction) error
+}
+
+// SpendingControlService declines payments the payer's spending controls
+// block or cap
+type SpendingControlService interface {
+	Check(ctx context.Context, tx *models.Transaction) error
+}
+
+// PromotionService credits the promotions a completed payment earns
+type PromotionService interface {
+	Apply(ctx context.Context, tx *models.Transaction) ([]models.PromotionReward, error)
+}
+
+// LoyaltyService accrues merchant loyalty points on completed payments
+type LoyaltyService interface {
+	Accrue(ctx context.Context, tx *models.Transaction) (*models.LoyaltyEntry, error)
+}
+
 type TransferRequest struct {
 	SenderID    uint                   `json:"-"` // Set by handler
 	ReceiverID  uint                   `json:"receiver_id"`
@@ -30,4 +52,5 @@ type Service interface {
 	Rollback(ctx context.Context, tx *models.Transaction) error
 	CreateTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
 	ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+	Reverse(ctx context.Context, req ReversalRequest) (*models.TransactionReversal, error)
 }
@@ -0,0 +1,192 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/resilience"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reversal errors
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrNotReversible       = errors.New("only completed payments and transfers can be reversed")
+	ErrAlreadyReversed     = errors.New("transaction has already been reversed")
+	ErrHasRefunds          = errors.New("transaction has refunds; reverse or settle them through refunds instead")
+	ErrReasonRequired      = errors.New("a reason is required to reverse a transaction")
+	// ErrReversalUnfunded means the recipient no longer holds the funds
+	ErrReversalUnfunded = errors.New("recipient's balance no longer covers the reversal")
+)
+
+// reversibleTypes are the wallet-to-wallet payments a reversal can undo.
+// Top-ups, withdrawals and card payments settle outside the platform, and
+// refunds and reversals are compensations themselves.
+var reversibleTypes = map[string]bool{
+	models.TransactionTypeQRPayment:      true,
+	models.TransactionTypeQRCode:         true,
+	models.TransactionTypeMerchantDirect: true,
+	models.TransactionTypeMerchantScan:   true,
+	models.TransactionTypeP2PTransfer:    true,
+	models.TransactionTypeTransfer:       true,
+	"merchant_payment":                   true,
+}
+
+// ReversalRequest reverses a completed transaction
+type ReversalRequest struct {
+	TransactionID uint
+	Reason        string
+	// ReversedBy is the admin or merchant user ordering the reversal
+	ReversedBy uint
+	Actor      string
+	// MerchantUserID limits the reversal to payments that merchant
+	// received; zero for admins
+	MerchantUserID uint
+	OperatorID     *uint
+}
+
+// Reverse undoes a completed transaction with a linked compensating
+// transaction: the amount goes back from the recipient to the sender, and
+// a fee the sender paid comes back out of fee revenue. A transaction with
+// refunds can't be reversed, as that would pay the refunded part twice.
+func (s *service) Reverse(ctx context.Context, req ReversalRequest) (_ *models.TransactionReversal, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "transaction reversal", s.timeout)
+	defer finish(&err)
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	var original *models.Transaction
+	var reversal *models.TransactionReversal
+	err = s.transactions.WithContext(ctx).ExecuteInTransaction(func(transactions repositories.TransactionRepository, wallets repositories.WalletRepository) error {
+		// Locking the original serialises concurrent reversals of it
+		var err error
+		original, err = transactions.GetForUpdate(req.TransactionID, req.MerchantUserID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrTransactionNotFound) {
+				return ErrTransactionNotFound
+			}
+			return err
+		}
+		if err := reversible(transactions, original); err != nil {
+			return err
+		}
+
+		fee := math.Round(original.Fee*100) / 100
+		if err := moveReversedFunds(transactions, wallets, original, fee); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		compensation := &models.Transaction{
+			Type:             models.TransactionTypeReversal,
+			SenderID:         original.ReceiverID,
+			ReceiverID:       original.SenderID,
+			Amount:           original.Amount,
+			Currency:         original.Currency,
+			Description:      "Reversal: " + reason,
+			Status:           "completed",
+			TransactionID:    fmt.Sprintf("REV-%d-%d", original.ID, now.UnixNano()),
+			Reference:        strconv.FormatUint(uint64(original.ID), 10),
+			MerchantID:       original.MerchantID,
+			MerchantName:     original.MerchantName,
+			MerchantCategory: original.MerchantCategory,
+			OperatorID:       req.OperatorID,
+			Category:         "Reversal",
+			ProcessedAt:      now,
+			Metadata: models.NewJSON(map[string]interface{}{
+				"original_transaction_id": original.ID,
+				"reason":                  reason,
+				"reversed_by":             req.ReversedBy,
+				"actor":                   req.Actor,
+				"fee_returned":            fee,
+			}),
+		}
+		if err := transactions.CreateTransaction(compensation); err != nil {
+			return fmt.Errorf("failed to record reversal transaction: %w", err)
+		}
+
+		if err := transactions.UpdateStatus(original.ID, "reversed"); err != nil {
+			return err
+		}
+		original.Status = "reversed"
+
+		reversal = &models.TransactionReversal{
+			OriginalTransactionID: original.ID,
+			ReversalTransactionID: compensation.ID,
+			Amount:                original.Amount,
+			FeeReturned:           fee,
+			Reason:                reason,
+			ReversedBy:            req.ReversedBy,
+			Actor:                 req.Actor,
+			OperatorID:            req.OperatorID,
+		}
+		return transactions.CreateReversal(reversal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The reversal is committed, so stale balances are cleared regardless
+	ctx = context.WithoutCancel(ctx)
+	s.cache.Delete(ctx,
+		s.cache.GenerateKey("wallet", "user", original.SenderID),
+		s.cache.GenerateKey("wallet", "user", original.ReceiverID),
+	)
+	return reversal, nil
+}
+
+// reversible checks the transaction can be reversed as it stands
+func reversible(transactions repositories.TransactionRepository, tx *models.Transaction) error {
+	if tx.Status == "reversed" {
+		return ErrAlreadyReversed
+	}
+	if tx.Status != "completed" || !reversibleTypes[tx.Type] {
+		return ErrNotReversible
+	}
+
+	refunded, err := transactions.HasCompletedRefunds(tx.ID)
+	if err != nil {
+		return err
+	}
+	if refunded {
+		return ErrHasRefunds
+	}
+	return nil
+}
+
+// moveReversedFunds takes the amount back from the recipient to the
+// sender like any transfer, then credits the sender the fee they paid. A
+// reversal never takes the recipient below zero.
+func moveReversedFunds(transactions repositories.TransactionRepository, wallets repositories.WalletRepository, tx *models.Transaction, fee float64) error {
+	recipient, _, err := wallets.TransferFunds(tx.ReceiverID, tx.SenderID, tx.Amount, 0)
+	if errors.Is(err, repositories.ErrOverdraftExceeded) || errors.Is(err, repositories.ErrInsufficientBalance) {
+		return ErrReversalUnfunded
+	}
+	if err != nil {
+		return err
+	}
+	if recipient.Balance < 0 {
+		return ErrReversalUnfunded
+	}
+
+	if fee <= 0 {
+		return nil
+	}
+	if _, err := wallets.AdjustBalance(tx.SenderID, fee); err != nil {
+		return fmt.Errorf("failed to return fee: %w", err)
+	}
+	return transactions.PostSystemEntry(models.SystemAccountFeeRevenue, &models.SystemLedgerEntry{
+		Kind:          models.SystemEntryFee,
+		Amount:        -fee,
+		TransactionID: &tx.ID,
+		Description:   fmt.Sprintf("Fee returned on reversal of transaction %d", tx.ID),
+	})
+}
@@ -4,80 +4,103 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
 	"time"
-
-	"gorm.io/gorm"
 )
 
 var (
 	ErrHighRiskTransaction = errors.New("transaction risk too high")
 	highRiskThreshold      = 0.8
 	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrNotAwaitingConfirmation is returned when a held transfer was
+	// completed or cancelled before it could be processed
+	ErrNotAwaitingConfirmation = errors.New("transfer is no longer awaiting confirmation")
 )
 
 type service struct {
-	db             *gorm.DB
+	transactions   repositories.TransactionRepository
 	walletService  WalletService
 	balanceService BalanceService
 	cache          *cache.CacheService
 	riskService    *RiskService
+	fraudService   FraudService
+	spending       SpendingControlService
+	promotions     PromotionService
+	loyalty        LoyaltyService
+	timeout        time.Duration
 }
 
 func NewService(
-	db *gorm.DB,
+	transactions repositories.TransactionRepository,
 	walletSvc WalletService,
 	balanceSvc BalanceService,
 	cache *cache.CacheService,
+	fraudSvc FraudService,
+	spending SpendingControlService,
+	promotions PromotionService,
+	loyalty LoyaltyService,
+	timeout time.Duration,
 ) Service {
 	return &service{
-		db:             db,
+		transactions:   transactions,
 		walletService:  walletSvc,
 		balanceService: balanceSvc,
 		cache:          cache,
 		riskService:    NewRiskService(),
+		fraudService:   fraudSvc,
+		spending:       spending,
+		promotions:     promotions,
+		loyalty:        loyalty,
+		timeout:        timeout,
 	}
 }
 
-func (s *service) ProcessTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error) {
+func (s *service) ProcessTransaction(ctx context.Context, tx *models.Transaction) (_ *models.Transaction, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "transaction", s.timeout)
+	defer finish(&err)
+
 	fmt.Printf("Processing transaction: %+v\n", tx)
 
 	// Validate transaction
-	if err := s.validateTransaction(tx); err != nil {
+	if err := s.validateTransaction(ctx, tx); err != nil {
 		return nil, err
 	}
 
-	// Process in a single database transaction
-	err := s.db.Transaction(func(dbTx *gorm.DB) error {
-		// Get wallets directly from database to avoid cache issues
-		var sourceWallet, destWallet models.Wallet
-
-		if err := repositories.DB.Where("user_id = ?", tx.SenderID).First(&sourceWallet).Error; err != nil {
-			fmt.Printf("Source wallet lookup failed: %v\n", err)
-			return fmt.Errorf("source wallet not found: %w", err)
-		}
+	// Check the sender's available balance so held funds can't be spent;
+	// any fee is paid by the sender on top of the amount
+	debited := tx.Amount + tx.Fee
+	if err := s.balanceService.ValidateBalance(ctx, tx.SenderID, debited); err != nil {
+		return nil, err
+	}
 
-		if err := repositories.DB.Where("user_id = ?", tx.ReceiverID).First(&destWallet).Error; err != nil {
-			fmt.Printf("Destination wallet lookup failed: %v\n", err)
-			return fmt.Errorf("destination wallet not found: %w", err)
+	// Process in a single database transaction
+	err = s.transactions.WithContext(ctx).ExecuteInTransaction(func(transactions repositories.TransactionRepository, wallets repositories.WalletRepository) error {
+		// Move the funds within the sender's overdraft limit
+		sourceWallet, destWallet, err := wallets.TransferFunds(tx.SenderID, tx.ReceiverID, tx.Amount, tx.Fee)
+		if err != nil {
+			if errors.Is(err, repositories.ErrOverdraftExceeded) || errors.Is(err, repositories.ErrInsufficientBalance) {
+				return ErrInsufficientBalance
+			}
+			return err
 		}
 
-		// Verify sufficient balance
-		if sourceWallet.Balance < tx.Amount {
-			return ErrInsufficientBalance
+		// Checked again under the row locks, as a lock may have been
+		// placed since the balance check; refusing rolls the move back
+		if err := s.walletService.EnforceLock(ctx, sourceWallet, tx.Type, models.WalletDirectionDebit, debited); err != nil {
+			return err
 		}
-
-		// Update balances directly
-		sourceWallet.Balance -= tx.Amount
-		if err := dbTx.Save(&sourceWallet).Error; err != nil {
+		if err := s.walletService.EnforceLock(ctx, destWallet, tx.Type, models.WalletDirectionCredit, tx.Amount); err != nil {
 			return err
 		}
 
-		destWallet.Balance += tx.Amount
-		if err := dbTx.Save(&destWallet).Error; err != nil {
-			return err
+		// A transfer held for device confirmation already has its record;
+		// it is completed in place, and only once
+		if tx.ID != 0 && tx.Status == models.TransactionStatusAwaitingConfirmation {
+			return completeHeld(transactions, tx)
 		}
 
 		// Update transaction status
@@ -85,7 +108,7 @@ func (s *service) ProcessTransaction(ctx context.Context, tx *models.Transaction
 		tx.ProcessedAt = time.Now()
 
 		// Create the transaction record
-		return dbTx.Create(tx).Error
+		return transactions.CreateTransaction(tx)
 	})
 
 	if err != nil {
@@ -93,15 +116,54 @@ func (s *service) ProcessTransaction(ctx context.Context, tx *models.Transaction
 		return nil, err
 	}
 
+	// The payment is committed, so what follows runs even if the deadline
+	// has just passed
+	ctx = context.WithoutCancel(ctx)
+
 	// Invalidate caches for both wallets
 	senderKey := s.cache.GenerateKey("wallet", "user", tx.SenderID)
 	receiverKey := s.cache.GenerateKey("wallet", "user", tx.ReceiverID)
 	s.cache.Delete(ctx, senderKey)
 	s.cache.Delete(ctx, receiverKey)
 
+	// Rewards are credited separately, so a failure never undoes the payment
+	if _, err := s.promotions.Apply(ctx, tx); err != nil {
+		log.Printf("Failed to apply promotions to transaction %d: %v", tx.ID, err)
+	}
+	if _, err := s.loyalty.Accrue(ctx, tx); err != nil {
+		log.Printf("Failed to accrue loyalty points for transaction %d: %v", tx.ID, err)
+	}
+
 	return tx, nil
 }
 
+// completeHeld completes a transfer that was awaiting confirmation. The
+// status guard refuses one already completed or cancelled, rolling back
+// the funds just moved.
+func completeHeld(transactions repositories.TransactionRepository, tx *models.Transaction) error {
+	processedAt := time.Now()
+	if err := transactions.CompleteHeld(tx.ID, processedAt); err != nil {
+		if errors.Is(err, repositories.ErrTransactionNotAwaitingConfirmation) {
+			return ErrNotAwaitingConfirmation
+		}
+		return err
+	}
+	tx.Status = "completed"
+	tx.ProcessedAt = processedAt
+
+	// Fees are booked on creation, which a held transfer has been through
+	// while still awaiting confirmation
+	if tx.Fee <= 0 {
+		return nil
+	}
+	return transactions.PostSystemEntry(models.SystemAccountFeeRevenue, &models.SystemLedgerEntry{
+		Kind:          models.SystemEntryFee,
+		Amount:        tx.Fee,
+		TransactionID: &tx.ID,
+		Description:   tx.Description,
+	})
+}
+
 func (s *service) Process(ctx context.Context, tx *models.Transaction) error {
 	if tx.Type == "debit" {
 		return s.walletService.Process(ctx, tx)
@@ -113,7 +175,10 @@ func (s *service) Rollback(ctx context.Context, tx *models.Transaction) error {
 	return s.walletService.Rollback(ctx, tx)
 }
 
-func (s *service) CreateTransaction(ctx context.Context, tx *models.Transaction) (*models.Transaction, error) {
+func (s *service) CreateTransaction(ctx context.Context, tx *models.Transaction) (_ *models.Transaction, err error) {
+	ctx, finish := resilience.WithDeadline(ctx, "transaction creation", s.timeout)
+	defer finish(&err)
+
 	// Validate transaction
 	if tx.Amount <= 0 {
 		return nil, errors.New("amount must be greater than zero")
@@ -129,20 +194,28 @@ func (s *service) CreateTransaction(ctx context.Context, tx *models.Transaction)
 	}
 
 	// Save to database
-	if err := s.db.Create(tx).Error; err != nil {
+	if err := s.transactions.WithContext(ctx).CreateTransaction(tx); err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	return tx, nil
 }
 
-func (s *service) validateTransaction(tx *models.Transaction) error {
+func (s *service) validateTransaction(ctx context.Context, tx *models.Transaction) error {
 	if tx.Amount <= 0 {
 		return errors.New("amount must be greater than zero")
 	}
 	if tx.SenderID == 0 && tx.ReceiverID == 0 {
 		return errors.New("transaction must have at least one party")
 	}
+	// The payer's own spending controls decline before the merchant's rules
+	if err := s.spending.Check(ctx, tx); err != nil {
+		return err
+	}
+	// Merchant fraud rules run ahead of the platform risk assessment
+	if err := s.fraudService.Evaluate(ctx, tx); err != nil {
+		return err
+	}
 	// Risk assessment
 	riskScore := s.riskService.AssessTransaction(tx)
 	if riskScore > highRiskThreshold {
@@ -0,0 +1,311 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ledger is what a fake database transaction wrote, kept only if it commits
+type ledger struct {
+	moves     []move
+	created   []*models.Transaction
+	completed []uint
+	entries   []*models.SystemLedgerEntry
+}
+
+type move struct {
+	from, to    uint
+	amount, fee float64
+}
+
+type fakeTransactions struct {
+	repositories.TransactionRepository
+	transferErr error
+	completeErr error
+	committed   ledger
+	pending     *ledger
+}
+
+func (f *fakeTransactions) WithContext(ctx context.Context) repositories.TransactionRepository {
+	return f
+}
+
+func (f *fakeTransactions) ExecuteInTransaction(fn func(repositories.TransactionRepository, repositories.WalletRepository) error) error {
+	f.pending = &ledger{}
+	defer func() { f.pending = nil }()
+	if err := fn(f, &fakeWalletRepo{owner: f}); err != nil {
+		return err
+	}
+	f.committed.moves = append(f.committed.moves, f.pending.moves...)
+	f.committed.created = append(f.committed.created, f.pending.created...)
+	f.committed.completed = append(f.committed.completed, f.pending.completed...)
+	f.committed.entries = append(f.committed.entries, f.pending.entries...)
+	return nil
+}
+
+func (f *fakeTransactions) CreateTransaction(tx *models.Transaction) error {
+	tx.ID = uint(100 + len(f.committed.created) + len(f.pending.created))
+	f.pending.created = append(f.pending.created, tx)
+	return nil
+}
+
+func (f *fakeTransactions) CompleteHeld(id uint, processedAt time.Time) error {
+	if f.completeErr != nil {
+		return f.completeErr
+	}
+	f.pending.completed = append(f.pending.completed, id)
+	return nil
+}
+
+func (f *fakeTransactions) PostSystemEntry(code string, entry *models.SystemLedgerEntry) error {
+	if code != models.SystemAccountFeeRevenue {
+		return errors.New("unexpected system account " + code)
+	}
+	f.pending.entries = append(f.pending.entries, entry)
+	return nil
+}
+
+type fakeWalletRepo struct {
+	repositories.WalletRepository
+	owner *fakeTransactions
+}
+
+func (f *fakeWalletRepo) TransferFunds(fromUserID, toUserID uint, amount, fee float64) (*models.Wallet, *models.Wallet, error) {
+	if f.owner.transferErr != nil {
+		return nil, nil, f.owner.transferErr
+	}
+	f.owner.pending.moves = append(f.owner.pending.moves, move{fromUserID, toUserID, amount, fee})
+	return &models.Wallet{UserID: fromUserID}, &models.Wallet{UserID: toUserID}, nil
+}
+
+type fakeWalletService struct {
+	WalletService
+	debitLock  error
+	creditLock error
+}
+
+func (f *fakeWalletService) EnforceLock(ctx context.Context, wallet *models.Wallet, operation, direction string, amount float64) error {
+	if direction == models.WalletDirectionDebit {
+		return f.debitLock
+	}
+	return f.creditLock
+}
+
+type fakeBalances struct {
+	err       error
+	validated float64
+}
+
+func (f *fakeBalances) ValidateBalance(ctx context.Context, userID uint, amount float64) error {
+	f.validated = amount
+	return f.err
+}
+
+type fakeScreening struct {
+	fraudErr    error
+	spendingErr error
+}
+
+func (f *fakeScreening) Evaluate(ctx context.Context, tx *models.Transaction) error {
+	return f.fraudErr
+}
+
+func (f *fakeScreening) Check(ctx context.Context, tx *models.Transaction) error {
+	return f.spendingErr
+}
+
+type fakeRewards struct {
+	applied, accrued int
+}
+
+func (f *fakeRewards) Apply(ctx context.Context, tx *models.Transaction) ([]models.PromotionReward, error) {
+	f.applied++
+	return nil, nil
+}
+
+func (f *fakeRewards) Accrue(ctx context.Context, tx *models.Transaction) (*models.LoyaltyEntry, error) {
+	f.accrued++
+	return nil, nil
+}
+
+// unreachableCache fails every call straight away, as the service only
+// invalidates it after committing
+func unreachableCache(t *testing.T) *cache.CacheService {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 50 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	return cache.NewCacheService(client, cache.Options{})
+}
+
+func TestProcessTransactionP2P(t *testing.T) {
+	errLocked := errors.New("wallet is locked for outgoing payments")
+	errFraud := errors.New("blocked by fraud rule")
+	errSpending := errors.New("over the spending limit")
+	errBalance := errors.New("insufficient available balance")
+
+	tests := []struct {
+		name         string
+		tx           models.Transaction
+		transactions fakeTransactions
+		wallets      fakeWalletService
+		balances     fakeBalances
+		screening    fakeScreening
+		wantErr      error
+		wantAnyErr   bool
+		wantDebited  float64
+		wantCreated  bool
+		wantHeldDone bool
+		wantFeeEntry float64
+	}{
+		{
+			name:        "free transfer",
+			tx:          models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			wantDebited: 25,
+			wantCreated: true,
+		},
+		{
+			name:        "sender pays the fee on top",
+			tx:          models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 100, Fee: 1.5},
+			wantDebited: 101.5,
+			wantCreated: true,
+		},
+		{
+			name:       "zero amount",
+			tx:         models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2},
+			wantAnyErr: true,
+		},
+		{
+			name:       "no parties",
+			tx:         models.Transaction{Type: models.TransactionTypeP2PTransfer, Amount: 10},
+			wantAnyErr: true,
+		},
+		{
+			name:      "spending control declines",
+			tx:        models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			screening: fakeScreening{spendingErr: errSpending},
+			wantErr:   errSpending,
+		},
+		{
+			name:      "fraud rule declines",
+			tx:        models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			screening: fakeScreening{fraudErr: errFraud},
+			wantErr:   errFraud,
+		},
+		{
+			name:     "held funds not spendable",
+			tx:       models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			balances: fakeBalances{err: errBalance},
+			wantErr:  errBalance,
+		},
+		{
+			name:         "overdraft exceeded under the row lock",
+			tx:           models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			transactions: fakeTransactions{transferErr: repositories.ErrOverdraftExceeded},
+			wantErr:      ErrInsufficientBalance,
+		},
+		{
+			name:    "sender locked since the balance check",
+			tx:      models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			wallets: fakeWalletService{debitLock: errLocked},
+			wantErr: errLocked,
+		},
+		{
+			name:    "receiver locked for credits",
+			tx:      models.Transaction{Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 25},
+			wallets: fakeWalletService{creditLock: errLocked},
+			wantErr: errLocked,
+		},
+		{
+			name: "held transfer completes in place",
+			tx: models.Transaction{ID: 7, Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 2000,
+				Status: models.TransactionStatusAwaitingConfirmation},
+			wantDebited:  2000,
+			wantHeldDone: true,
+		},
+		{
+			name: "held transfer books its fee on completion",
+			tx: models.Transaction{ID: 7, Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 2000, Fee: 4,
+				Status: models.TransactionStatusAwaitingConfirmation},
+			wantDebited:  2004,
+			wantHeldDone: true,
+			wantFeeEntry: 4,
+		},
+		{
+			name: "held transfer already completed",
+			tx: models.Transaction{ID: 7, Type: models.TransactionTypeP2PTransfer, SenderID: 1, ReceiverID: 2, Amount: 2000,
+				Status: models.TransactionStatusAwaitingConfirmation},
+			transactions: fakeTransactions{completeErr: repositories.ErrTransactionNotAwaitingConfirmation},
+			wantErr:      ErrNotAwaitingConfirmation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewards := &fakeRewards{}
+			svc := NewService(&tt.transactions, &tt.wallets, &tt.balances, unreachableCache(t),
+				&tt.screening, &tt.screening, rewards, rewards, time.Second)
+
+			tx := tt.tx
+			got, err := svc.ProcessTransaction(context.Background(), &tx)
+			committed := tt.transactions.committed
+
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil {
+					t.Fatalf("ProcessTransaction() succeeded, want an error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ProcessTransaction() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(committed.moves)+len(committed.created)+len(committed.completed)+len(committed.entries) != 0 {
+					t.Errorf("failed transfer committed %+v", committed)
+				}
+				if rewards.applied+rewards.accrued != 0 {
+					t.Errorf("failed transfer earned rewards")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessTransaction() error = %v", err)
+			}
+
+			if tt.balances.validated != tt.wantDebited {
+				t.Errorf("checked available balance for %v, want %v", tt.balances.validated, tt.wantDebited)
+			}
+			wantMove := move{tt.tx.SenderID, tt.tx.ReceiverID, tt.tx.Amount, tt.tx.Fee}
+			if len(committed.moves) != 1 || committed.moves[0] != wantMove {
+				t.Errorf("moved %+v, want %+v", committed.moves, wantMove)
+			}
+			if got.Status != "completed" || got.ProcessedAt.IsZero() {
+				t.Errorf("status = %q processed at %v, want completed", got.Status, got.ProcessedAt)
+			}
+			if created := len(committed.created) == 1; created != tt.wantCreated {
+				t.Errorf("created record = %v, want %v", created, tt.wantCreated)
+			}
+			if done := len(committed.completed) == 1 && committed.completed[0] == tt.tx.ID; done != tt.wantHeldDone {
+				t.Errorf("completed held %v, want %v", committed.completed, tt.wantHeldDone)
+			}
+
+			var booked float64
+			for _, entry := range committed.entries {
+				booked += entry.Amount
+				if entry.Kind != models.SystemEntryFee || entry.TransactionID == nil || *entry.TransactionID != got.ID {
+					t.Errorf("fee entry = %+v, want a fee on transaction %d", entry, got.ID)
+				}
+			}
+			if booked != tt.wantFeeEntry {
+				t.Errorf("booked %v of fees on completion, want %v", booked, tt.wantFeeEntry)
+			}
+
+			if rewards.applied != 1 || rewards.accrued != 1 {
+				t.Errorf("applied promotions %d times and accrued loyalty %d times, want once each", rewards.applied, rewards.accrued)
+			}
+		})
+	}
+}
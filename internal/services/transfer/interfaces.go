@@ -8,8 +8,7 @@ import (
 // WalletService defines the wallet operations used by the transfer service.
 type WalletService interface {
 	ValidateBalance(ctx context.Context, userID uint, amount float64) error
-	Debit(ctx context.Context, userID uint, amount float64) error
-	Credit(ctx context.Context, userID uint, amount float64) error
+	TransferFunds(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
 }
 
 // NotificationService is used to notify users about transfers.
@@ -17,6 +16,24 @@ type NotificationService interface {
 	SendTransferNotification(ctx context.Context, userID uint, tx *models.Transaction) error
 }
 
+// BeneficiaryService guards first payments to new recipients and keeps the
+// sender's contact stats up to date.
+type BeneficiaryService interface {
+	CheckBeneficiary(ctx context.Context, senderID, receiverID uint) error
+	RecordPayment(ctx context.Context, senderID, receiverID uint)
+}
+
+// DeviceConfirmation holds large transfers until the sender approves them
+// on a trusted device.
+type DeviceConfirmation interface {
+	Hold(ctx context.Context, tx *models.Transaction) (bool, error)
+}
+
+// PINService checks the sender's payment PIN on large transfers.
+type PINService interface {
+	RequireForTransfer(ctx context.Context, userID uint, amount float64) error
+}
+
 // Service handles P2P money transfers between users.
 type Service interface {
 	Transfer(ctx context.Context, senderID, receiverID uint, amount float64, description string) (*models.Transaction, error)
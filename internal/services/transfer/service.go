@@ -7,22 +7,25 @@ import (
 	"time"
 
 	"orus/internal/models"
-	"orus/internal/repositories"
-
-	"gorm.io/gorm"
 )
 
 // service implements the transfer Service interface.
 type service struct {
-	walletSvc WalletService
-	notifier  NotificationService
+	walletSvc     WalletService
+	notifier      NotificationService
+	beneficiaries BeneficiaryService
+	confirmations DeviceConfirmation
+	pins          PINService
 }
 
 // NewService creates a new transfer service instance.
-func NewService(walletSvc WalletService, notifier NotificationService) Service {
+func NewService(walletSvc WalletService, notifier NotificationService, beneficiaries BeneficiaryService, confirmations DeviceConfirmation, pins PINService) Service {
 	return &service{
-		walletSvc: walletSvc,
-		notifier:  notifier,
+		walletSvc:     walletSvc,
+		notifier:      notifier,
+		beneficiaries: beneficiaries,
+		confirmations: confirmations,
+		pins:          pins,
 	}
 }
 
@@ -34,6 +37,12 @@ func (s *service) Transfer(ctx context.Context, senderID, receiverID uint, amoun
 	if amount <= 0 {
 		return nil, errors.New("amount must be greater than zero")
 	}
+	if err := s.beneficiaries.CheckBeneficiary(ctx, senderID, receiverID); err != nil {
+		return nil, err
+	}
+	if err := s.pins.RequireForTransfer(ctx, senderID, amount); err != nil {
+		return nil, err
+	}
 
 	if err := s.walletSvc.ValidateBalance(ctx, senderID, amount); err != nil {
 		return nil, err
@@ -49,19 +58,22 @@ func (s *service) Transfer(ctx context.Context, senderID, receiverID uint, amoun
 		TransactionID: fmt.Sprintf("P2P-%d-%d-%d", senderID, receiverID, time.Now().UnixNano()),
 	}
 
-	err := repositories.DB.Transaction(func(dbTx *gorm.DB) error {
-		if err := s.walletSvc.Debit(ctx, senderID, amount); err != nil {
-			return err
-		}
-		if err := s.walletSvc.Credit(ctx, receiverID, amount); err != nil {
-			return err
-		}
-		tx.Status = "completed"
-		return dbTx.Create(tx).Error
-	})
+	// Large transfers wait for the sender to approve them on a trusted
+	// device, and are returned awaiting confirmation
+	held, err := s.confirmations.Hold(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
+	if held {
+		return tx, nil
+	}
+
+	tx, err = s.walletSvc.TransferFunds(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.beneficiaries.RecordPayment(ctx, senderID, receiverID)
 
 	if s.notifier != nil {
 		_ = s.notifier.SendTransferNotification(ctx, senderID, tx)
@@ -0,0 +1,221 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"orus/internal/models"
+)
+
+type fakeWallets struct {
+	balanceErr  error
+	transferErr error
+	transferred []*models.Transaction
+}
+
+func (f *fakeWallets) ValidateBalance(ctx context.Context, userID uint, amount float64) error {
+	return f.balanceErr
+}
+
+func (f *fakeWallets) TransferFunds(ctx context.Context, tx *models.Transaction) (*models.Transaction, error) {
+	if f.transferErr != nil {
+		return nil, f.transferErr
+	}
+	f.transferred = append(f.transferred, tx)
+	tx.ID = 42
+	tx.Status = "completed"
+	return tx, nil
+}
+
+type fakeNotifier struct {
+	notified []uint
+}
+
+func (f *fakeNotifier) SendTransferNotification(ctx context.Context, userID uint, tx *models.Transaction) error {
+	f.notified = append(f.notified, userID)
+	return nil
+}
+
+type fakeBeneficiaries struct {
+	checkErr error
+	recorded int
+}
+
+func (f *fakeBeneficiaries) CheckBeneficiary(ctx context.Context, senderID, receiverID uint) error {
+	return f.checkErr
+}
+
+func (f *fakeBeneficiaries) RecordPayment(ctx context.Context, senderID, receiverID uint) {
+	f.recorded++
+}
+
+type fakeConfirmations struct {
+	hold bool
+	err  error
+}
+
+func (f *fakeConfirmations) Hold(ctx context.Context, tx *models.Transaction) (bool, error) {
+	if f.hold {
+		tx.Status = models.TransactionStatusAwaitingConfirmation
+	}
+	return f.hold, f.err
+}
+
+type fakePINs struct {
+	err error
+}
+
+func (f *fakePINs) RequireForTransfer(ctx context.Context, userID uint, amount float64) error {
+	return f.err
+}
+
+func TestTransfer(t *testing.T) {
+	errBlocked := errors.New("new beneficiary blocked")
+	errPIN := errors.New("payment PIN required")
+	errBalance := errors.New("insufficient balance")
+	errTransfer := errors.New("transfer failed")
+
+	tests := []struct {
+		name          string
+		sender        uint
+		receiver      uint
+		amount        float64
+		wallets       fakeWallets
+		beneficiaries fakeBeneficiaries
+		confirmations fakeConfirmations
+		pins          fakePINs
+		wantErr       error
+		wantAnyErr    bool
+		wantStatus    string
+		wantMoved     bool
+	}{
+		{
+			name:       "completed",
+			sender:     1,
+			receiver:   2,
+			amount:     25,
+			wantStatus: "completed",
+			wantMoved:  true,
+		},
+		{
+			name:       "to self",
+			sender:     1,
+			receiver:   1,
+			amount:     25,
+			wantAnyErr: true,
+		},
+		{
+			name:       "zero amount",
+			sender:     1,
+			receiver:   2,
+			amount:     0,
+			wantAnyErr: true,
+		},
+		{
+			name:       "negative amount",
+			sender:     1,
+			receiver:   2,
+			amount:     -5,
+			wantAnyErr: true,
+		},
+		{
+			name:          "beneficiary check fails",
+			sender:        1,
+			receiver:      2,
+			amount:        25,
+			beneficiaries: fakeBeneficiaries{checkErr: errBlocked},
+			wantErr:       errBlocked,
+		},
+		{
+			name:     "PIN required",
+			sender:   1,
+			receiver: 2,
+			amount:   500,
+			pins:     fakePINs{err: errPIN},
+			wantErr:  errPIN,
+		},
+		{
+			name:     "insufficient balance",
+			sender:   1,
+			receiver: 2,
+			amount:   25,
+			wallets:  fakeWallets{balanceErr: errBalance},
+			wantErr:  errBalance,
+		},
+		{
+			name:          "held for device confirmation",
+			sender:        1,
+			receiver:      2,
+			amount:        2000,
+			confirmations: fakeConfirmations{hold: true},
+			wantStatus:    models.TransactionStatusAwaitingConfirmation,
+		},
+		{
+			name:     "wallet transfer fails",
+			sender:   1,
+			receiver: 2,
+			amount:   25,
+			wallets:  fakeWallets{transferErr: errTransfer},
+			wantErr:  errTransfer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &fakeNotifier{}
+			svc := NewService(&tt.wallets, notifier, &tt.beneficiaries, &tt.confirmations, &tt.pins)
+
+			tx, err := svc.Transfer(context.Background(), tt.sender, tt.receiver, tt.amount, "dinner")
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil {
+					t.Fatalf("Transfer() succeeded, want an error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Transfer() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.beneficiaries.recorded != 0 || len(notifier.notified) != 0 {
+					t.Errorf("failed transfer recorded %d payments and sent %d notifications", tt.beneficiaries.recorded, len(notifier.notified))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transfer() error = %v", err)
+			}
+
+			if tx.Type != models.TransactionTypeP2PTransfer {
+				t.Errorf("type = %q, want %q", tx.Type, models.TransactionTypeP2PTransfer)
+			}
+			if tx.SenderID != tt.sender || tx.ReceiverID != tt.receiver || tx.Amount != tt.amount {
+				t.Errorf("transaction = %d -> %d for %v, want %d -> %d for %v",
+					tx.SenderID, tx.ReceiverID, tx.Amount, tt.sender, tt.receiver, tt.amount)
+			}
+			if tx.Description != "dinner" {
+				t.Errorf("description = %q, want %q", tx.Description, "dinner")
+			}
+			if tx.Fee != 0 {
+				t.Errorf("fee = %v, want P2P transfers free", tx.Fee)
+			}
+			if tx.TransactionID == "" {
+				t.Error("transaction ID not set")
+			}
+			if tx.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", tx.Status, tt.wantStatus)
+			}
+
+			if moved := len(tt.wallets.transferred) == 1; moved != tt.wantMoved {
+				t.Errorf("funds moved = %v, want %v", moved, tt.wantMoved)
+			}
+			if tt.wantMoved {
+				if tt.beneficiaries.recorded != 1 {
+					t.Errorf("recorded %d payments to the beneficiary, want 1", tt.beneficiaries.recorded)
+				}
+				if len(notifier.notified) != 2 || notifier.notified[0] != tt.sender || notifier.notified[1] != tt.receiver {
+					t.Errorf("notified %v, want sender then receiver", notifier.notified)
+				}
+			} else if tt.beneficiaries.recorded != 0 || len(notifier.notified) != 0 {
+				t.Errorf("held transfer recorded %d payments and sent %d notifications", tt.beneficiaries.recorded, len(notifier.notified))
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package treasury
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownAccount     = errors.New("unknown system account")
+	ErrSameAccount        = errors.New("cannot transfer to the same account")
+	ErrCustodialAccount   = errors.New("custodial accounts hold customer funds and can't be used in treasury transfers")
+	ErrInvalidAmount      = errors.New("amount must be greater than zero")
+	ErrReasonRequired     = errors.New("reason is required")
+	ErrInvalidPeriod      = errors.New("invalid report period")
+	ErrInvalidStatus      = errors.New("invalid transfer status")
+	ErrSelfApproval       = errors.New("a transfer must be reviewed by someone other than its requester")
+	ErrTransferNotFound   = errors.New("treasury transfer not found")
+	ErrTransferNotPending = errors.New("treasury transfer is not pending")
+	ErrInsufficientFunds  = errors.New("insufficient funds in source account")
+)
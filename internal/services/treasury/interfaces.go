@@ -0,0 +1,22 @@
+package treasury
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// Service reports on the platform's system accounts and moves funds between
+// them under maker-checker control
+type Service interface {
+	EnsureAccounts(ctx context.Context) error
+	Report(ctx context.Context, from, to time.Time) (*Report, error)
+	ListEntries(ctx context.Context, code string, limit, offset int) ([]models.SystemLedgerEntry, int64, error)
+
+	ListTransfers(ctx context.Context, status string, limit, offset int) ([]models.TreasuryTransfer, int64, error)
+	// RequestTransfer records a pending transfer made by makerID
+	RequestTransfer(ctx context.Context, makerID uint, req TransferRequest) (*models.TreasuryTransfer, error)
+	// ApproveTransfer executes a pending transfer; the checker must not be its maker
+	ApproveTransfer(ctx context.Context, checkerID, transferID uint, note string) (*models.TreasuryTransfer, error)
+	RejectTransfer(ctx context.Context, checkerID, transferID uint, note string) (*models.TreasuryTransfer, error)
+}
@@ -0,0 +1,192 @@
+package treasury
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+type service struct {
+	repo repositories.TreasuryRepository
+}
+
+// NewService creates a new treasury service
+func NewService(repo repositories.TreasuryRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) EnsureAccounts(ctx context.Context) error {
+	return s.repo.EnsureAccounts()
+}
+
+func (s *service) Report(ctx context.Context, from, to time.Time) (*Report, error) {
+	if !to.After(from) || to.Sub(from) > MaxReportPeriod {
+		return nil, ErrInvalidPeriod
+	}
+
+	accounts, err := s.repo.ListAccounts()
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.GetPeriodTotals(from, to)
+	if err != nil {
+		return nil, err
+	}
+	byAccount := make(map[uint]repositories.SystemAccountTotals, len(totals))
+	for _, t := range totals {
+		byAccount[t.AccountID] = t
+	}
+
+	customerFunds, err := s.repo.GetCustomerFunds()
+	if err != nil {
+		return nil, err
+	}
+	_, pending, err := s.repo.ListTransfers(models.TreasuryTransferPending, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		From:            from,
+		To:              to,
+		Accounts:        make([]AccountReport, 0, len(accounts)),
+		CustomerFunds:   round2(customerFunds),
+		PendingApproval: pending,
+	}
+	for _, account := range accounts {
+		t := byAccount[account.ID]
+		report.Accounts = append(report.Accounts, AccountReport{
+			Code:     account.Code,
+			Name:     account.Name,
+			Type:     account.Type,
+			Currency: account.Currency,
+			Balance:  account.Balance,
+			Credits:  round2(t.Credits),
+			Debits:   round2(t.Debits),
+			Net:      round2(t.Credits - t.Debits),
+			Entries:  t.Entries,
+		})
+		if models.IsCustodialAccount(account.Code) {
+			report.CustomerFunds += account.Balance
+		} else {
+			report.PlatformFunds += account.Balance
+		}
+	}
+	report.CustomerFunds = round2(report.CustomerFunds)
+	report.PlatformFunds = round2(report.PlatformFunds)
+	return report, nil
+}
+
+func (s *service) ListEntries(ctx context.Context, code string, limit, offset int) ([]models.SystemLedgerEntry, int64, error) {
+	account, err := s.repo.GetAccountByCode(code)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSystemAccountNotFound) {
+			return nil, 0, ErrUnknownAccount
+		}
+		return nil, 0, err
+	}
+	return s.repo.GetEntries(account.ID, limit, offset)
+}
+
+func (s *service) ListTransfers(ctx context.Context, status string, limit, offset int) ([]models.TreasuryTransfer, int64, error) {
+	if status != "" && !transferStatuses[status] {
+		return nil, 0, ErrInvalidStatus
+	}
+	return s.repo.ListTransfers(status, limit, offset)
+}
+
+func (s *service) RequestTransfer(ctx context.Context, makerID uint, req TransferRequest) (*models.TreasuryTransfer, error) {
+	if !models.IsSystemAccountCode(req.FromAccount) || !models.IsSystemAccountCode(req.ToAccount) {
+		return nil, ErrUnknownAccount
+	}
+	if models.IsCustodialAccount(req.FromAccount) || models.IsCustodialAccount(req.ToAccount) {
+		return nil, ErrCustodialAccount
+	}
+	if req.FromAccount == req.ToAccount {
+		return nil, ErrSameAccount
+	}
+	amount := round2(req.Amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	transfer := &models.TreasuryTransfer{
+		FromAccountCode: req.FromAccount,
+		ToAccountCode:   req.ToAccount,
+		Amount:          amount,
+		Reason:          reason,
+		Status:          models.TreasuryTransferPending,
+		RequestedBy:     makerID,
+	}
+	if err := s.repo.CreateTransfer(transfer); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Treasury transfer %d requested by %d: %.2f from %s to %s",
+		transfer.ID, makerID, amount, transfer.FromAccountCode, transfer.ToAccountCode)
+	return transfer, nil
+}
+
+func (s *service) ApproveTransfer(ctx context.Context, checkerID, transferID uint, note string) (*models.TreasuryTransfer, error) {
+	if err := s.checkReviewer(checkerID, transferID); err != nil {
+		return nil, err
+	}
+	transfer, err := s.repo.ExecuteTransfer(transferID, checkerID, strings.TrimSpace(note))
+	if err != nil {
+		return nil, mapRepoError(err)
+	}
+
+	log.Printf("Treasury transfer %d approved by %d", transfer.ID, checkerID)
+	return transfer, nil
+}
+
+func (s *service) RejectTransfer(ctx context.Context, checkerID, transferID uint, note string) (*models.TreasuryTransfer, error) {
+	if err := s.checkReviewer(checkerID, transferID); err != nil {
+		return nil, err
+	}
+	transfer, err := s.repo.RejectTransfer(transferID, checkerID, strings.TrimSpace(note))
+	if err != nil {
+		return nil, mapRepoError(err)
+	}
+
+	log.Printf("Treasury transfer %d rejected by %d", transfer.ID, checkerID)
+	return transfer, nil
+}
+
+// checkReviewer enforces maker-checker: nobody reviews their own request
+func (s *service) checkReviewer(checkerID, transferID uint) error {
+	transfer, err := s.repo.GetTransfer(transferID)
+	if err != nil {
+		return mapRepoError(err)
+	}
+	if transfer.RequestedBy == checkerID {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+func mapRepoError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrTreasuryTransferNotFound):
+		return ErrTransferNotFound
+	case errors.Is(err, repositories.ErrTreasuryTransferNotOpen):
+		return ErrTransferNotPending
+	case errors.Is(err, repositories.ErrInsufficientSystemFunds):
+		return ErrInsufficientFunds
+	default:
+		return err
+	}
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,48 @@
+package treasury
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// MaxReportPeriod caps how long a treasury report period can be
+const MaxReportPeriod = 366 * 24 * time.Hour
+
+// AccountReport is a system account's balance with its activity in the period
+type AccountReport struct {
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Currency string  `json:"currency"`
+	Balance  float64 `json:"balance"`
+	Credits  float64 `json:"credits"`
+	Debits   float64 `json:"debits"`
+	Net      float64 `json:"net"`
+	Entries  int64   `json:"entries"`
+}
+
+// Report shows platform funds next to customer funds so the two can be
+// reconciled separately
+type Report struct {
+	From            time.Time       `json:"from"`
+	To              time.Time       `json:"to"`
+	Accounts        []AccountReport `json:"accounts"`
+	PlatformFunds   float64         `json:"platform_funds"`
+	CustomerFunds   float64         `json:"customer_funds"`
+	PendingApproval int64           `json:"pending_transfers"`
+}
+
+// TransferRequest asks to move funds from one system account to another
+type TransferRequest struct {
+	FromAccount string  `json:"from_account"`
+	ToAccount   string  `json:"to_account"`
+	Amount      float64 `json:"amount"`
+	Reason      string  `json:"reason"`
+}
+
+// transferStatuses are the statuses transfers can be filtered by
+var transferStatuses = map[string]bool{
+	models.TreasuryTransferPending:  true,
+	models.TreasuryTransferExecuted: true,
+	models.TreasuryTransferRejected: true,
+}
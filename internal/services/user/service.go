@@ -4,8 +4,7 @@ import (
 	"errors"
 	"orus/internal/models"
 	"orus/internal/repositories"
-
-	"golang.org/x/crypto/bcrypt"
+	"orus/internal/services/password"
 )
 
 type Service interface {
@@ -14,16 +13,24 @@ type Service interface {
 	Update(user *models.User) error
 	Delete(id uint) error
 	ChangePassword(userID uint, oldPassword, newPassword string) error
-	GetTransactions(userID uint, page, limit int) ([]models.Transaction, int64, error)
+	// GetTransactions pages through the user's transactions, including
+	// archived ones when asked
+	GetTransactions(userID uint, limit, offset int, includeArchived bool) ([]models.Transaction, int64, error)
 }
 
 type service struct {
-	repo repositories.UserRepository
+	repo         repositories.UserRepository
+	transactions repositories.TransactionRepository
+	archive      repositories.TransactionArchiveRepository
+	passwords    *password.Hasher
 }
 
-func NewService(repo repositories.UserRepository) Service {
+func NewService(repo repositories.UserRepository, transactions repositories.TransactionRepository, archive repositories.TransactionArchiveRepository, passwords *password.Hasher) Service {
 	return &service{
-		repo: repo,
+		repo:         repo,
+		transactions: transactions,
+		archive:      archive,
+		passwords:    passwords,
 	}
 }
 
@@ -42,8 +49,17 @@ func (s *service) Create(input *models.CreateUserInput) (*models.User, error) {
 		return nil, errors.New("user with this email already exists")
 	}
 
+	country := ""
+	if input.Country != "" {
+		code, ok := models.NormalizeCountryCode(input.Country)
+		if !ok {
+			return nil, errors.New("country must be a 2-letter ISO code")
+		}
+		country = code
+	}
+
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwords.Hash(input.Password)
 	if err != nil {
 		return nil, errors.New("failed to hash password")
 	}
@@ -53,7 +69,8 @@ func (s *service) Create(input *models.CreateUserInput) (*models.User, error) {
 		Name:     input.Name,
 		Email:    input.Email,
 		Phone:    input.Phone,
-		Password: string(hashedPassword),
+		Country:  country,
+		Password: hashedPassword,
 		Role:     input.Role,
 		Status:   "active",
 	}
@@ -80,23 +97,25 @@ func (s *service) ChangePassword(userID uint, oldPassword, newPassword string) e
 	}
 
 	// Verify old password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+	if ok, _ := s.passwords.Verify(user.Password, oldPassword); !ok {
 		return errors.New("incorrect password")
 	}
 
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwords.Hash(newPassword)
 	if err != nil {
 		return errors.New("failed to hash password")
 	}
 
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	user.TokenVersion++ // Invalidate existing tokens
 
 	return s.repo.Update(user)
 }
 
-func (s *service) GetTransactions(userID uint, page, limit int) ([]models.Transaction, int64, error) {
-	offset := (page - 1) * limit
-	return repositories.GetUserTransactionsPaginated(userID, limit, offset)
+func (s *service) GetTransactions(userID uint, limit, offset int, includeArchived bool) ([]models.Transaction, int64, error) {
+	if includeArchived {
+		return s.archive.GetUserHistory(userID, limit, offset)
+	}
+	return s.transactions.GetUserTransactions(userID, limit, offset)
 }
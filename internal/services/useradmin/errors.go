@@ -0,0 +1,15 @@
+package useradmin
+
+import "errors"
+
+// Service errors
+var (
+	ErrUnknownRole      = errors.New("unknown role")
+	ErrSuperAdminRole   = errors.New("only super admins can change super admin roles")
+	ErrSelfAction       = errors.New("admins cannot suspend or change the role of their own account")
+	ErrAlreadySuspended = errors.New("user is already suspended")
+	ErrNotSuspended     = errors.New("user is not suspended")
+	ErrReasonRequired   = errors.New("reason is required")
+	ErrInvalidName      = errors.New("name cannot be empty")
+	ErrInvalidCountry   = errors.New("country must be a 2-letter ISO code")
+)
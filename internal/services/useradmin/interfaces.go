@@ -0,0 +1,31 @@
+package useradmin
+
+import (
+	"context"
+	"orus/internal/models"
+	"time"
+)
+
+// Service lets admins manage user accounts. Every change is recorded on
+// the user's activity timeline with the admin who made it.
+type Service interface {
+	// UpdateUser changes a user's contact details
+	UpdateUser(ctx context.Context, actorID, userID uint, req UpdateUserRequest) (*models.User, error)
+
+	// Suspend blocks the user from logging in and paying and ends their
+	// sessions; Unsuspend reinstates them
+	Suspend(ctx context.Context, actorID, userID uint, reason string) (*models.User, error)
+	Unsuspend(ctx context.Context, actorID, userID uint) (*models.User, error)
+
+	// RevokeSessions bumps the user's token version, logging them out everywhere
+	RevokeSessions(ctx context.Context, actorID, userID uint) error
+
+	// ChangeRole gives the user a new role. Permissions come from the role,
+	// so the user's sessions end and they get the new set on next login.
+	// Only super-admins can grant or revoke super-admin.
+	ChangeRole(ctx context.Context, actor *models.UserClaims, userID uint, role string) (*RoleChange, error)
+
+	// Timeline merges the user's account events and transactions, newest
+	// first, from before before
+	Timeline(ctx context.Context, userID uint, before time.Time, limit int) (*Timeline, error)
+}
@@ -0,0 +1,273 @@
+package useradmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/rbac"
+	"sort"
+	"strings"
+	"time"
+)
+
+type service struct {
+	users        repositories.UserRepository
+	rbac         rbac.Service
+	activity     repositories.UserActivityRepository
+	transactions repositories.TransactionRepository
+	invalidator  *cache.Invalidator
+}
+
+// NewService creates a new user admin service
+func NewService(
+	users repositories.UserRepository,
+	rbacSvc rbac.Service,
+	activity repositories.UserActivityRepository,
+	transactions repositories.TransactionRepository,
+	invalidator *cache.Invalidator,
+) Service {
+	return &service{
+		users:        users,
+		rbac:         rbacSvc,
+		activity:     activity,
+		transactions: transactions,
+		invalidator:  invalidator,
+	}
+}
+
+func (s *service) UpdateUser(ctx context.Context, actorID, userID uint, req UpdateUserRequest) (*models.User, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrInvalidName
+		}
+		if name != user.Name {
+			user.Name = name
+			changed = append(changed, "name")
+		}
+	}
+	if req.Email != nil && *req.Email != user.Email {
+		if owner, err := s.users.GetByEmail(*req.Email); err == nil && owner.ID != user.ID {
+			return nil, repositories.ErrEmailTaken
+		}
+		user.Email = *req.Email
+		changed = append(changed, "email")
+	}
+	if req.Phone != nil && *req.Phone != user.Phone {
+		if owner, err := s.users.GetByPhone(*req.Phone); err == nil && owner.ID != user.ID {
+			return nil, repositories.ErrPhoneTaken
+		}
+		user.Phone = *req.Phone
+		changed = append(changed, "phone")
+	}
+	if req.Country != nil {
+		country := ""
+		if *req.Country != "" {
+			code, ok := models.NormalizeCountryCode(*req.Country)
+			if !ok {
+				return nil, ErrInvalidCountry
+			}
+			country = code
+		}
+		if country != user.Country {
+			user.Country = country
+			changed = append(changed, "country")
+		}
+	}
+
+	if len(changed) == 0 {
+		return user, nil
+	}
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.record(actorID, userID, models.UserActivityProfileUpdated, "changed "+strings.Join(changed, ", "))
+	return user, nil
+}
+
+func (s *service) Suspend(ctx context.Context, actorID, userID uint, reason string) (*models.User, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	if actorID == userID {
+		return nil, ErrSelfAction
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, ErrAlreadySuspended
+	}
+
+	now := time.Now()
+	if err := s.users.SetSuspension(userID, &now, reason); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d suspended user %d: %s", actorID, userID, reason)
+
+	s.record(actorID, userID, models.UserActivitySuspended, reason)
+	return s.users.GetByID(userID)
+}
+
+func (s *service) Unsuspend(ctx context.Context, actorID, userID uint) (*models.User, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsSuspended() {
+		return nil, ErrNotSuspended
+	}
+
+	if err := s.users.SetSuspension(userID, nil, ""); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %d reinstated user %d", actorID, userID)
+
+	s.record(actorID, userID, models.UserActivityUnsuspended, "")
+	return s.users.GetByID(userID)
+}
+
+func (s *service) RevokeSessions(ctx context.Context, actorID, userID uint) error {
+	if _, err := s.users.GetByID(userID); err != nil {
+		return err
+	}
+	if err := s.users.IncrementTokenVersion(userID); err != nil {
+		return err
+	}
+
+	s.record(actorID, userID, models.UserActivitySessionsRevoked, "")
+	return nil
+}
+
+func (s *service) ChangeRole(ctx context.Context, actor *models.UserClaims, userID uint, role string) (*RoleChange, error) {
+	permissions, err := s.rbac.Permissions(ctx, role)
+	if errors.Is(err, repositories.ErrRoleNotFound) {
+		return nil, ErrUnknownRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID == userID {
+		return nil, ErrSelfAction
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only super-admins can grant or revoke super-admin, so an admin can't
+	// promote themselves past maker-checker controls
+	if (role == "super_admin" || user.Role == "super_admin") && actor.Role != "super_admin" {
+		return nil, ErrSuperAdminRole
+	}
+	// Nor can they hand out a custom role granting more than they hold
+	if actor.Role != "super_admin" {
+		for _, p := range permissions {
+			if !actor.HasPermission(p) {
+				return nil, fmt.Errorf("%w: %s", rbac.ErrCannotGrant, p)
+			}
+		}
+	}
+
+	change := &RoleChange{
+		UserID:       user.ID,
+		PreviousRole: user.Role,
+		Role:         role,
+		Permissions:  permissions,
+	}
+	if user.Role == role {
+		return change, nil
+	}
+
+	user.Role = role
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+
+	// Tokens embed the role and permissions, so force a fresh login
+	if err := s.users.IncrementTokenVersion(user.ID); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Admin %d changed role of user %d from %s to %s", actor.UserID, user.ID, change.PreviousRole, role)
+	s.record(actor.UserID, user.ID, models.UserActivityRoleChanged, fmt.Sprintf("%s to %s", change.PreviousRole, role))
+
+	job := cache.UserInvalidationJob(user.ID, fmt.Sprintf("role change for user %d", user.ID))
+	if err := s.invalidator.Enqueue(job); err != nil {
+		log.Printf("Failed to schedule cache invalidation for user %d: %v", user.ID, err)
+	}
+
+	return change, nil
+}
+
+func (s *service) Timeline(ctx context.Context, userID uint, before time.Time, limit int) (*Timeline, error) {
+	if limit <= 0 || limit > MaxTimelineEntries {
+		limit = MaxTimelineEntries
+	}
+	if before.IsZero() {
+		before = time.Now()
+	}
+	if _, err := s.users.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	// Each source's newest limit entries hold the merged newest limit
+	activities, err := s.activity.ListByUser(userID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactions.GetUserTransactionsBefore(userID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]TimelineEntry, 0, len(activities)+len(transactions))
+	for i := range activities {
+		entries = append(entries, TimelineEntry{Kind: EntryActivity, At: activities[i].CreatedAt, Activity: &activities[i]})
+	}
+	for i := range transactions {
+		entries = append(entries, TimelineEntry{Kind: EntryTransaction, At: transactions[i].CreatedAt, Transaction: &transactions[i]})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.After(entries[j].At)
+	})
+
+	timeline := &Timeline{UserID: userID, Entries: entries}
+	if len(entries) > limit {
+		timeline.Entries = entries[:limit]
+	}
+	if len(activities) == limit || len(transactions) == limit {
+		next := timeline.Entries[len(timeline.Entries)-1].At
+		timeline.NextBefore = &next
+	}
+	return timeline, nil
+}
+
+// record adds an admin action to the user's timeline. The action has
+// already happened, so a failure is only logged.
+func (s *service) record(actorID, userID uint, action, details string) {
+	activity := &models.UserActivity{
+		UserID:  userID,
+		ActorID: &actorID,
+		Action:  action,
+		Details: details,
+	}
+	if err := s.activity.Record(activity); err != nil {
+		log.Printf("Failed to record %s activity for user %d: %v", action, userID, err)
+	}
+}
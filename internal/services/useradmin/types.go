@@ -0,0 +1,49 @@
+package useradmin
+
+import (
+	"orus/internal/models"
+	"time"
+)
+
+// MaxTimelineEntries caps one page of a user's activity timeline
+const MaxTimelineEntries = 100
+
+// UpdateUserRequest changes a user's details. Nil fields are left unchanged.
+type UpdateUserRequest struct {
+	Name    *string `json:"name"`
+	Email   *string `json:"email"`
+	Phone   *string `json:"phone"`
+	Country *string `json:"country"`
+}
+
+// RoleChange reports a user's role before and after a change, and the
+// permissions the new role grants
+type RoleChange struct {
+	UserID       uint     `json:"user_id"`
+	PreviousRole string   `json:"previous_role"`
+	Role         string   `json:"role"`
+	Permissions  []string `json:"permissions"`
+}
+
+// Timeline entry kinds
+const (
+	EntryActivity    = "activity"
+	EntryTransaction = "transaction"
+)
+
+// TimelineEntry is one event on a user's timeline: an account event or a
+// transaction they sent or received
+type TimelineEntry struct {
+	Kind        string               `json:"kind"`
+	At          time.Time            `json:"at"`
+	Activity    *models.UserActivity `json:"activity,omitempty"`
+	Transaction *models.Transaction  `json:"transaction,omitempty"`
+}
+
+// Timeline is a page of a user's activity. Pass NextBefore as before to get
+// the following page; it is nil on the last one.
+type Timeline struct {
+	UserID     uint            `json:"user_id"`
+	Entries    []TimelineEntry `json:"entries"`
+	NextBefore *time.Time      `json:"next_before,omitempty"`
+}
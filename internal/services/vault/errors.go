@@ -0,0 +1,11 @@
+package vault
+
+import "errors"
+
+// Vault errors
+var (
+	ErrInvalidCardNumber  = errors.New("invalid card number")
+	ErrDirectTokenization = errors.New("direct card tokenization is not supported - please use Stripe Elements or Mobile SDK")
+	ErrPurposeRequired    = errors.New("vault access needs a purpose")
+	ErrCorrupt            = errors.New("vaulted card can't be decrypted")
+)
@@ -0,0 +1,21 @@
+package vault
+
+import "context"
+
+// Service is the card token vault, the only code handling card numbers
+// and processor tokens. Everything else refers to cards by an opaque
+// reference, and every time a token leaves the vault it is logged with the
+// purpose given.
+type Service interface {
+	// Store tokenizes a card number, or takes a processor token from the
+	// client SDKs, and keeps the token encrypted
+	Store(ctx context.Context, userID uint, cardNumber string) (*Card, error)
+	// Token returns the processor token behind ref, to hand to the card
+	// processor for purpose
+	Token(ctx context.Context, ref, purpose string) (string, error)
+	Delete(ctx context.Context, ref, purpose string) error
+
+	// ImportLegacyCards moves tokens stored on cards before the vault into
+	// it, returning how many it moved
+	ImportLegacyCards(ctx context.Context) (int, error)
+}
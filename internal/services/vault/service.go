@@ -0,0 +1,210 @@
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+)
+
+// ciphertextPrefix versions the encryption so the scheme can change
+// without re-reading every card at once
+const ciphertextPrefix = "v1:"
+
+// importBatch bounds how many legacy cards one import pass reads
+const importBatch = 100
+
+type service struct {
+	repo      repositories.CardVaultRepository
+	tokenizer Tokenizer
+	aead      cipher.AEAD
+}
+
+// NewService creates the vault. Tokens are encrypted with AES-256-GCM
+// under a key derived from key, which must stay the same for the vaulted
+// cards to remain readable.
+func NewService(repo repositories.CardVaultRepository, key string) (Service, error) {
+	if key == "" {
+		return nil, fmt.Errorf("vault key is required")
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &service{
+		repo:      repo,
+		tokenizer: NewTokenizer(),
+		aead:      aead,
+	}, nil
+}
+
+func (s *service) Store(ctx context.Context, userID uint, cardNumber string) (*Card, error) {
+	tokenized, err := s.tokenizer.TokenizeCard(strings.ReplaceAll(cardNumber, " ", ""))
+	if err != nil {
+		return nil, err
+	}
+	vaulted, err := s.store(ctx, userID, tokenized, models.VaultActionStore, "link_card")
+	if err != nil {
+		return nil, err
+	}
+	return &Card{Reference: vaulted.Reference, Brand: vaulted.Brand, LastFour: vaulted.LastFour}, nil
+}
+
+func (s *service) store(ctx context.Context, userID uint, tokenized *TokenizedCard, action, purpose string) (*models.VaultedCard, error) {
+	ref, err := newReference()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := s.encrypt(ref, tokenized.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	vaulted := &models.VaultedCard{
+		Reference:  ref,
+		UserID:     userID,
+		Ciphertext: ciphertext,
+		Brand:      tokenized.CardType,
+		LastFour:   tokenized.LastFour,
+	}
+	if err := s.repo.Create(ctx, vaulted); err != nil {
+		return nil, err
+	}
+	s.recordAccess(ctx, vaulted, action, purpose)
+	return vaulted, nil
+}
+
+// Token reveals a processor token. The access is logged before the token
+// is returned, and a token whose access can't be logged isn't returned.
+func (s *service) Token(ctx context.Context, ref, purpose string) (string, error) {
+	if purpose == "" {
+		return "", ErrPurposeRequired
+	}
+	vaulted, err := s.repo.GetByReference(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	err = s.repo.RecordAccess(ctx, &models.VaultAccess{
+		Reference: ref,
+		UserID:    vaulted.UserID,
+		Action:    models.VaultActionDetokenize,
+		Purpose:   purpose,
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.decrypt(ref, vaulted.Ciphertext)
+}
+
+func (s *service) Delete(ctx context.Context, ref, purpose string) error {
+	if purpose == "" {
+		return ErrPurposeRequired
+	}
+	vaulted, err := s.repo.GetByReference(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, ref); err != nil {
+		return err
+	}
+	s.recordAccess(ctx, vaulted, models.VaultActionDelete, purpose)
+	return nil
+}
+
+// ImportLegacyCards vaults the plain-text tokens cards were linked with
+// before the vault existed. A card that fails is logged and left for the
+// next run.
+func (s *service) ImportLegacyCards(ctx context.Context) (int, error) {
+	imported := 0
+	for {
+		cards, err := s.repo.GetLegacyCards(ctx, importBatch)
+		if err != nil {
+			return imported, err
+		}
+		moved := 0
+		for _, card := range cards {
+			if err := s.importLegacyCard(ctx, card); err != nil {
+				log.Printf("Failed to vault the token of card %d: %v", card.ID, err)
+				continue
+			}
+			moved++
+		}
+		imported += moved
+		if len(cards) < importBatch || moved == 0 {
+			return imported, nil
+		}
+	}
+}
+
+func (s *service) importLegacyCard(ctx context.Context, card repositories.LegacyCard) error {
+	tokenized, err := s.tokenizer.TokenizeCard(card.CardNumber)
+	if err != nil {
+		return err
+	}
+	vaulted, err := s.store(ctx, card.UserID, tokenized, models.VaultActionImport, "legacy_import")
+	if err != nil {
+		return err
+	}
+	return s.repo.AttachLegacyCard(ctx, card.ID, vaulted.Reference)
+}
+
+func (s *service) recordAccess(ctx context.Context, vaulted *models.VaultedCard, action, purpose string) {
+	err := s.repo.RecordAccess(context.WithoutCancel(ctx), &models.VaultAccess{
+		Reference: vaulted.Reference,
+		UserID:    vaulted.UserID,
+		Action:    action,
+		Purpose:   purpose,
+	})
+	if err != nil {
+		log.Printf("Failed to log vault %s of %s: %v", action, vaulted.Reference, err)
+	}
+}
+
+// encrypt seals token, binding it to ref so ciphertexts can't be swapped
+// between cards
+func (s *service) encrypt(ref, token string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(token), []byte(ref))
+	return ciphertextPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *service) decrypt(ref, ciphertext string) (string, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, ciphertextPrefix)
+	if !ok {
+		return "", ErrCorrupt
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return "", ErrCorrupt
+	}
+	nonce, sealed := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	token, err := s.aead.Open(nil, nonce, sealed, []byte(ref))
+	if err != nil {
+		return "", ErrCorrupt
+	}
+	return string(token), nil
+}
+
+func newReference() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "card_" + hex.EncodeToString(b), nil
+}
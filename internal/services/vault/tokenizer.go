@@ -1,13 +1,10 @@
-package creditcard
+package vault
 
-import (
-	"errors"
-	"strings"
-)
+import "strings"
 
-// Tokenizer handles credit card tokenization
+// Tokenizer exchanges card numbers for processor tokens
 type Tokenizer interface {
-	TokenizeCard(card CreateCardInput) (*TokenizedCard, error)
+	TokenizeCard(cardNumber string) (*TokenizedCard, error)
 }
 
 type DefaultTokenizer struct {
@@ -35,12 +32,12 @@ func NewTokenizer() Tokenizer {
 	}
 }
 
-func (t *DefaultTokenizer) TokenizeCard(card CreateCardInput) (*TokenizedCard, error) {
+func (t *DefaultTokenizer) TokenizeCard(cardNumber string) (*TokenizedCard, error) {
 	// Check if this is a test token
-	if strings.HasPrefix(card.CardNumber, "tok_") {
-		cardType := t.getCardTypeFromToken(card.CardNumber)
+	if strings.HasPrefix(cardNumber, "tok_") {
+		cardType := t.getCardTypeFromToken(cardNumber)
 		return &TokenizedCard{
-			Token:    card.CardNumber,
+			Token:    cardNumber,
 			CardType: cardType,
 			LastFour: "4242", // Default for test tokens
 			IssuedBy: "Test Issuer",
@@ -48,22 +45,22 @@ func (t *DefaultTokenizer) TokenizeCard(card CreateCardInput) (*TokenizedCard, e
 	}
 
 	// Check if this is a test card number
-	if testCard, isTestCard := t.testCards[card.CardNumber]; isTestCard {
+	if testCard, isTestCard := t.testCards[cardNumber]; isTestCard {
 		return &TokenizedCard{
 			Token:    testCard.token,
 			CardType: testCard.cardType,
-			LastFour: card.CardNumber[len(card.CardNumber)-4:],
+			LastFour: cardNumber[len(cardNumber)-4:],
 			IssuedBy: "Test Bank",
 		}, nil
 	}
 
 	// Validate card number using Luhn algorithm
-	if !isValidCardNumber(card.CardNumber) {
-		return nil, errors.New("invalid card number: failed Luhn check")
+	if !isValidCardNumber(cardNumber) {
+		return nil, ErrInvalidCardNumber
 	}
 
 	// For production cards, return error indicating direct tokenization is not supported
-	return nil, errors.New("direct card tokenization is not supported - please use Stripe Elements or Mobile SDK")
+	return nil, ErrDirectTokenization
 }
 
 func (t *DefaultTokenizer) getCardTypeFromToken(token string) string {
@@ -0,0 +1,17 @@
+package vault
+
+// TokenizedCard represents a tokenized credit card
+type TokenizedCard struct {
+	Token    string
+	CardType string
+	LastFour string
+	IssuedBy string
+}
+
+// Card is what the rest of the codebase learns about a vaulted card
+type Card struct {
+	// Reference stands in for the card in every other table
+	Reference string
+	Brand     string
+	LastFour  string
+}
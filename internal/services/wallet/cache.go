@@ -0,0 +1,38 @@
+package wallet
+
+import (
+	"context"
+	"log"
+	"orus/internal/models"
+)
+
+// writeThrough caches wallets read from the database. The cache keeps
+// whichever copy has the highest version, so the order writers finish in
+// doesn't matter.
+func (s *service) writeThrough(ctx context.Context, wallets ...*models.Wallet) {
+	for _, wallet := range wallets {
+		if err := s.cache.CacheWallet(ctx, wallet); err != nil {
+			log.Printf("Failed to cache wallet of user %d: %v", wallet.UserID, err)
+		}
+	}
+}
+
+// refreshWalletCache writes the committed state of users' wallets through
+// to the cache after a change. The database bumps the version, so the
+// wallets are read back rather than cached from memory. If that fails the
+// entry is dropped instead. The change is committed by then, so this runs
+// even if the operation's deadline has just passed.
+func (s *service) refreshWalletCache(ctx context.Context, userIDs ...uint) {
+	ctx = context.WithoutCancel(ctx)
+	for _, userID := range userIDs {
+		wallet, err := s.repo.GetByUserID(userID)
+		if err == nil {
+			s.writeThrough(ctx, wallet)
+			continue
+		}
+		log.Printf("Failed to reload wallet of user %d for the cache: %v", userID, err)
+		if err := s.cache.InvalidateWallet(ctx, userID); err != nil {
+			log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+		}
+	}
+}
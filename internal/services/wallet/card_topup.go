@@ -0,0 +1,276 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	creditcard "orus/internal/services/credit-card"
+	"time"
+)
+
+// Card top-up risk rules stepping a top-up up to 3-D Secure
+const (
+	// newCardWindow is how long after verification a card counts as new
+	newCardWindow = 24 * time.Hour
+	// velocityWindow and velocityTopUps: this many card top-ups within the
+	// window steps the next one up
+	velocityWindow = 24 * time.Hour
+	velocityTopUps = 3
+)
+
+// TopUp charges the card and credits the wallet. A top-up above the 3-D
+// Secure threshold, or flagged by the risk rules, comes back requiring
+// action instead: the wallet is credited once the processor reports the
+// challenge passed.
+func (s *service) TopUp(ctx context.Context, userID, cardID uint, amount float64) (_ *models.CardTopUp, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet top-up")
+	defer finish(&err)
+
+	// Get user role from context
+	roleVal := ctx.Value(UserRoleContextKey)
+	role, ok := roleVal.(string)
+	if !ok || role == "" {
+		role = "user" // Default to user limits
+	}
+
+	limits := s.config.Limits[role]
+	if amount <= 0 || amount < limits.MinTransactionAmount {
+		return nil, ErrInvalidAmount
+	}
+
+	if amount > limits.MaxTransactionAmount {
+		return nil, fmt.Errorf("amount exceeds maximum limit of %v", limits.MaxTransactionAmount)
+	}
+
+	unlock, err := s.lockWallets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Get wallet by user ID instead of wallet ID
+	wallet, err := repo.GetByUserID(userID)
+	if err != nil {
+		// If wallet not found, create a new one
+		if err == repositories.ErrWalletNotFound {
+			wallet, err = s.CreateWallet(ctx, userID, "USD")
+			if err != nil {
+				return nil, fmt.Errorf("failed to create wallet: %w", err)
+			}
+		} else {
+			return nil, fmt.Errorf("wallet not found: %w", err)
+		}
+	}
+
+	if err := s.EnforceLock(ctx, wallet, "top_up", models.WalletDirectionCredit, amount); err != nil {
+		return nil, err
+	}
+
+	// Get card details
+	card, err := s.cardService.GetByID(cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card details: %w", err)
+	}
+
+	// Verify card ownership
+	if card.UserID != userID {
+		return nil, fmt.Errorf("card does not belong to user")
+	}
+	switch {
+	case card.Status != "active":
+		return nil, creditcard.ErrCardNotActive
+	case !card.IsVerified():
+		return nil, creditcard.ErrCardNotVerified
+	case card.IsExpired(time.Now()):
+		return nil, creditcard.ErrCardExpired
+	}
+
+	stepUp, err := s.stepUpReason(ctx, userID, card, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := fmt.Sprintf("TOP-%d-%d", userID, time.Now().UnixNano())
+	charge, err := s.cardService.ChargeTopUp(ctx, card, amount, reference, stepUp != "")
+	if err != nil {
+		s.metrics.RecordError("top_up", err.Error())
+		return nil, err
+	}
+
+	topUp := &models.CardTopUp{
+		UserID:       userID,
+		CardID:       cardID,
+		ChargeID:     charge.ID,
+		Amount:       amount,
+		Status:       models.CardTopUpSucceeded,
+		StepUpReason: stepUp,
+	}
+	topUpTx := &models.Transaction{
+		Type:          "top_up",
+		SenderID:      userID,
+		ReceiverID:    0, // No receiver for top-ups
+		Amount:        amount,
+		Status:        "completed",
+		TransactionID: fmt.Sprintf("TXN-%d-%d", userID, time.Now().UnixNano()),
+		Reference:     reference,
+		PaymentType:   "card_topup",
+		PaymentMethod: "credit_card",
+		CardID:        &cardID,
+		Category:      "Top Up",
+		Description:   fmt.Sprintf("Top up from card ending in %s", card.LastFour),
+		Metadata: models.NewJSON(map[string]interface{}{
+			"card_last_four": card.LastFour,
+			"card_type":      card.CardType,
+		}),
+	}
+	pending := charge.Status == creditcard.ChargeRequiresAction
+	if pending {
+		topUp.Status = models.CardTopUpRequiresAction
+		topUp.ClientSecret = charge.ClientSecret
+		topUp.NextActionURL = charge.NextActionURL
+		topUpTx.Status = "pending"
+	} else {
+		now := time.Now()
+		topUp.CompletedAt = &now
+	}
+
+	// Process top-up
+	err = s.topUps.ExecuteInTransaction(ctx, func(topUps repositories.CardTopUpRepository, tx repositories.WalletRepository) error {
+		if !pending {
+			if _, err := tx.AdjustBalance(userID, amount); err != nil {
+				return err
+			}
+		}
+		if err := tx.CreateTransaction(topUpTx); err != nil {
+			return err
+		}
+		topUp.TransactionID = topUpTx.ID
+		return topUps.Create(ctx, topUp)
+	})
+
+	if err != nil {
+		s.metrics.RecordError("top_up", err.Error())
+		// The charge can't be matched to a top-up, so give it back
+		s.refundTopUp(ctx, charge.ID)
+		return nil, ErrTransactionFailed
+	}
+	if pending {
+		return topUp, nil
+	}
+
+	// Write the new balance through to the cache
+	s.refreshWalletCache(ctx, userID)
+
+	s.metrics.RecordTransaction("top_up", amount)
+
+	return topUp, nil
+}
+
+// stepUpReason returns why the top-up needs 3-D Secure, or "" if it doesn't
+func (s *service) stepUpReason(ctx context.Context, userID uint, card *models.CreditCard, amount float64) (string, error) {
+	if s.config.ThreeDSThreshold > 0 && amount >= s.config.ThreeDSThreshold {
+		return models.StepUpAmount, nil
+	}
+	if card.VerifiedAt != nil && time.Since(*card.VerifiedAt) < newCardWindow {
+		return models.StepUpNewCard, nil
+	}
+	recent, err := s.topUps.CountSince(ctx, userID, time.Now().Add(-velocityWindow))
+	if err != nil {
+		return "", err
+	}
+	if recent >= velocityTopUps {
+		return models.StepUpVelocity, nil
+	}
+	return "", nil
+}
+
+func (s *service) GetTopUp(ctx context.Context, userID, topUpID uint) (*models.CardTopUp, error) {
+	return s.topUps.GetByIDAndUserID(ctx, topUpID, userID)
+}
+
+// HandleCardTopUpEvent applies the processor's outcome to a top-up waiting
+// on 3-D Secure. Redeliveries, and events for a top-up already completed,
+// return it unchanged. A wallet locked against credits since the challenge
+// started fails the top-up and refunds the charge.
+func (s *service) HandleCardTopUpEvent(ctx context.Context, event CardTopUpEvent) (*models.CardTopUp, error) {
+	if event.ChargeID == "" {
+		return nil, ErrInvalidTopUpEvent
+	}
+	switch event.Status {
+	case creditcard.ChargeSucceeded, creditcard.ChargeFailed:
+	default:
+		return nil, ErrInvalidTopUpEvent
+	}
+
+	var topUp *models.CardTopUp
+	var credited, refund bool
+	err := s.topUps.ExecuteInTransaction(ctx, func(topUps repositories.CardTopUpRepository, repo repositories.WalletRepository) error {
+		t, err := topUps.GetByChargeIDForUpdate(ctx, event.ChargeID)
+		if err != nil {
+			return err
+		}
+		topUp = t
+		if t.Status != models.CardTopUpRequiresAction {
+			return nil
+		}
+
+		now := time.Now()
+		t.ClientSecret = ""
+		t.NextActionURL = ""
+		t.CompletedAt = &now
+
+		if event.Status == creditcard.ChargeFailed {
+			t.Status = models.CardTopUpFailed
+			t.FailureReason = event.FailureReason
+			if err := topUps.UpdateTransactionStatus(ctx, t.TransactionID, "failed"); err != nil {
+				return err
+			}
+			return topUps.Update(ctx, t)
+		}
+
+		// Locked so the wallet can't be locked between the check and the credit
+		wallet, err := repo.LockByUserID(t.UserID)
+		if err != nil {
+			return err
+		}
+		if err := s.EnforceLock(ctx, wallet, "top_up", models.WalletDirectionCredit, t.Amount); err != nil {
+			t.Status = models.CardTopUpFailed
+			t.FailureReason = "wallet_locked"
+			refund = true
+			if err := topUps.UpdateTransactionStatus(ctx, t.TransactionID, "failed"); err != nil {
+				return err
+			}
+			return topUps.Update(ctx, t)
+		}
+
+		if _, err := repo.AdjustBalance(t.UserID, t.Amount); err != nil {
+			return err
+		}
+		if err := topUps.UpdateTransactionStatus(ctx, t.TransactionID, "completed"); err != nil {
+			return err
+		}
+		t.Status = models.CardTopUpSucceeded
+		credited = true
+		return topUps.Update(ctx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund {
+		s.refundTopUp(ctx, topUp.ChargeID)
+	}
+	if credited {
+		s.refreshWalletCache(ctx, topUp.UserID)
+		s.metrics.RecordTransaction("top_up", topUp.Amount)
+	}
+	return topUp, nil
+}
+
+func (s *service) refundTopUp(ctx context.Context, chargeID string) {
+	if err := s.cardService.RefundCharge(context.WithoutCancel(ctx), chargeID); err != nil {
+		log.Printf("CRITICAL: failed to refund top-up charge %s: %v", chargeID, err)
+	}
+}
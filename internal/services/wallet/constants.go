@@ -18,6 +18,14 @@ const (
 	DefaultTimeout         = 30 * time.Second
 )
 
+// Wallet locks
+const (
+	// LockWait is how long an operation waits for another to release a wallet
+	LockWait = 2 * time.Second
+	// LockExpirySlack keeps a wallet lock past its operation's deadline
+	LockExpirySlack = 5 * time.Second
+)
+
 // Cache keys and durations
 const (
 	WalletCachePrefix = "wallet:"
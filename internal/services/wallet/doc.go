@@ -3,6 +3,7 @@ Package wallet provides wallet management functionality for the application.
 
 The wallet service handles all wallet-related operations including:
 - Balance management (credit/debit)
+- Balance holds (total vs available balance)
 - Transaction history
 - Limits enforcement (daily/monthly)
 - Batch operations
@@ -17,14 +18,27 @@ Usage:
 	wallet, err := svc.CreateWallet(ctx, userID, "USD")
 
 	// Credit amount
-	err = svc.Credit(ctx, userID, amount)
+	err = svc.Credit(ctx, wallet.ID, amount)
 
 	// Debit amount
-	err = svc.Debit(ctx, userID, amount)
+	err = svc.Debit(ctx, wallet.ID, amount)
+
+	// Reserve funds and inspect the available balance
+	hold, err := svc.PlaceHold(ctx, userID, wallet.HoldRequest{Amount: amount, Type: models.HoldTypeEscrow})
+	details, err := svc.GetBalanceDetails(ctx, userID)
 
 	// Get transaction history
 	history, err := svc.GetTransactionHistory(ctx, walletID, limit, offset)
 
+Shared wallets:
+
+NewSharedService manages wallets owned by several users (a family or a team).
+Members are owners, spenders or viewers; spenders pay within their own limits
+and payments over the wallet's approval threshold wait for an owner:
+
+	shared := wallet.NewSharedService(sharedRepo, userRepo, svc, cache)
+	payment, err := shared.Pay(ctx, userID, walletID, wallet.SharedPaymentRequest{RecipientID: id, Amount: amount})
+
 Configuration:
 
 The service can be configured using WalletConfig:
@@ -44,7 +58,10 @@ The service returns specific errors for different scenarios:
 - ErrDailyLimitExceeded: When daily transaction limit is exceeded
 - ErrMonthlyLimitExceeded: When monthly transaction limit is exceeded
 - ErrWalletLocked: When wallet is locked
+- ErrDebitsLocked, ErrCreditsLocked: When a scoped lock stops the operation
+- ErrWalletBusy: When another operation holds the wallet's lock past LockWait
 - ErrInvalidOperation: For general invalid operations
+- *resilience.TimeoutError: When an operation runs past ProcessingTimeout
 
 Cache Management:
 
@@ -54,6 +71,35 @@ The service implements a caching strategy for:
 - Transaction history
 - Daily/monthly totals
 
+Wallets are written through to the cache after every change the service
+makes. The database bumps a wallet's version on each balance or status
+change and the cache never replaces a copy with an older one. Reads pick
+their consistency explicitly:
+
+	w, err := svc.ReadWallet(ctx, userID, wallet.ReadStrong) // from the database
+	w, err = svc.GetWallet(ctx, userID)                      // cached
+
+Locking:
+
+Top-ups, withdrawals and transfers hold a Redis lock on each wallet they
+move funds in, shared by every instance, so two instances can't act on
+the same balance at once. Transfers lock both wallets in user ID order.
+If Redis is down the operations go ahead unlocked and rely on the
+database alone.
+
+Wallet locks:
+
+Admins lock a wallet for fraud or legal reasons, and owners can lock
+their own. A lock stops debits, credits or both, and may expire on its
+own; the job returned by NewJob lifts expired locks. Every operation
+moving funds calls EnforceLock, which records each refusal:
+
+	lock, err := svc.LockWallet(ctx, &adminID, userID, wallet.LockRequest{
+	    Scope:  models.WalletLockDebits,
+	    Reason: models.WalletLockReasonFraud,
+	})
+	err = svc.EnforceLock(ctx, w, "card_authorization", models.WalletDirectionDebit, amount) // ErrDebitsLocked
+
 Metrics:
 
 The service collects metrics for:
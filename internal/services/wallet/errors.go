@@ -5,10 +5,41 @@ import "errors"
 // Service errors
 var (
 	// Wallet-specific errors
+	ErrInsufficientBalance  = errors.New("insufficient balance")
+	ErrInvalidAmount        = errors.New("invalid amount")
+	ErrWalletNotFound       = errors.New("wallet not found")
 	ErrInvalidCurrency      = errors.New("invalid currency")
 	ErrDailyLimitExceeded   = errors.New("daily limit exceeded")
 	ErrMonthlyLimitExceeded = errors.New("monthly limit exceeded")
 	ErrWalletLocked         = errors.New("wallet is locked")
 	ErrInvalidOperation     = errors.New("invalid operation")
 	ErrTransactionFailed    = errors.New("transaction failed")
+	ErrHoldNotActive        = errors.New("hold is not active")
+	ErrWalletBusy           = errors.New("wallet is busy with another operation, try again")
+	ErrInvalidTopUpEvent    = errors.New("top-up event needs a charge ID and a status of succeeded or failed")
+
+	// Wallet lock errors
+	ErrDebitsLocked      = errors.New("wallet is locked for outgoing payments")
+	ErrCreditsLocked     = errors.New("wallet is locked for incoming payments")
+	ErrInvalidLockScope  = errors.New("lock scope must be debits, credits or full")
+	ErrInvalidLockReason = errors.New("lock reason must be fraud, legal or user_requested")
+	ErrInvalidLockExpiry = errors.New("lock expiry must be in the future")
+	ErrLockNotOwned      = errors.New("this lock can only be lifted by support")
+
+	// Shared wallet errors
+	ErrSharedWalletNotFound    = errors.New("shared wallet not found")
+	ErrWalletRoleForbidden     = errors.New("your role on this wallet does not allow this")
+	ErrInvalidWalletRole       = errors.New("role must be owner, spender or viewer")
+	ErrInvalidWalletName       = errors.New("wallet name is required")
+	ErrInvalidLimit            = errors.New("limits cannot be negative")
+	ErrMemberNotFound          = errors.New("member not found")
+	ErrMemberExists            = errors.New("user is already a member")
+	ErrTooManyMembers          = errors.New("shared wallet has reached its member limit")
+	ErrLastOwner               = errors.New("a shared wallet must keep at least one owner")
+	ErrRecipientNotFound       = errors.New("recipient not found")
+	ErrMemberLimitExceeded     = errors.New("payment exceeds your per-transaction limit")
+	ErrMemberDailyLimit        = errors.New("payment exceeds your daily spending limit")
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrPaymentNotPending       = errors.New("payment is not pending approval")
+	ErrInsufficientSharedFunds = errors.New("insufficient funds in shared wallet")
 )
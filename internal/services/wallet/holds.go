@@ -0,0 +1,199 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"time"
+)
+
+// GetBalanceDetails returns the stored balance together with the amount
+// currently reserved by holds and what remains available to spend.
+func (s *service) GetBalanceDetails(ctx context.Context, userID uint) (_ *BalanceDetails, err error) {
+	ctx, repo, finish := s.operation(ctx, "balance lookup")
+	defer finish(&err)
+
+	// Always read from the database so holds and balance are consistent
+	wallet, err := repo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return s.balanceDetails(repo, wallet)
+}
+
+func (s *service) balanceDetails(repo repositories.WalletRepository, wallet *models.Wallet) (*BalanceDetails, error) {
+	holds, err := repo.GetActiveHolds(wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var held float64
+	for _, hold := range holds {
+		held += hold.Amount
+	}
+	held = math.Round(held*100) / 100
+
+	return &BalanceDetails{
+		WalletID:  wallet.ID,
+		Currency:  wallet.Currency,
+		Total:     wallet.Balance,
+		Held:      held,
+		Overdraft: wallet.OverdraftLimit,
+		Available: math.Round((wallet.Balance-held+wallet.OverdraftLimit)*100) / 100,
+		Holds:     holds,
+	}, nil
+}
+
+// availableBalance returns the spendable part of a wallet balance
+func (s *service) availableBalance(repo repositories.WalletRepository, wallet *models.Wallet) (float64, error) {
+	details, err := s.balanceDetails(repo, wallet)
+	if err != nil {
+		return 0, err
+	}
+	return details.Available, nil
+}
+
+// PlaceHold reserves funds on the user's wallet without moving them
+func (s *service) PlaceHold(ctx context.Context, userID uint, req HoldRequest) (_ *models.WalletHold, err error) {
+	ctx, repo, finish := s.operation(ctx, "hold placement")
+	defer finish(&err)
+
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if req.Type == "" {
+		req.Type = models.HoldTypeAuthorization
+	}
+
+	// The available balance is checked under the wallet's row lock, which
+	// debits take too, so neither can spend what the other counted on
+	var hold *models.WalletHold
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		wallet, err := tx.LockByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+		if err := s.EnforceLock(ctx, wallet, "place_hold", models.WalletDirectionDebit, req.Amount); err != nil {
+			return err
+		}
+
+		available, err := s.availableBalance(tx, wallet)
+		if err != nil {
+			return err
+		}
+		if available < req.Amount {
+			return ErrInsufficientBalance
+		}
+
+		hold = &models.WalletHold{
+			WalletID:  wallet.ID,
+			UserID:    userID,
+			Amount:    math.Round(req.Amount*100) / 100,
+			Type:      req.Type,
+			Status:    models.HoldStatusActive,
+			Reason:    req.Reason,
+			Reference: req.Reference,
+			ExpiresAt: req.ExpiresAt,
+		}
+		return tx.CreateHold(hold)
+	})
+	if err != nil {
+		s.metrics.RecordError("place_hold", err.Error())
+		return nil, err
+	}
+
+	s.refreshWalletCache(ctx, userID)
+	return hold, nil
+}
+
+// ReleaseHold frees previously reserved funds without debiting them
+func (s *service) ReleaseHold(ctx context.Context, userID uint, holdID uint) (err error) {
+	ctx, repo, finish := s.operation(ctx, "hold release")
+	defer finish(&err)
+
+	hold, err := s.getUserHold(repo, userID, holdID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	hold.Status = models.HoldStatusReleased
+	hold.ReleasedAt = &now
+	if err := repo.UpdateHold(hold); err != nil {
+		return err
+	}
+
+	s.refreshWalletCache(ctx, userID)
+	return nil
+}
+
+// CaptureHold debits the held amount from the wallet and closes the hold
+func (s *service) CaptureHold(ctx context.Context, userID uint, holdID uint) (err error) {
+	ctx, repo, finish := s.operation(ctx, "hold capture")
+	defer finish(&err)
+
+	var hold *models.WalletHold
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		wallet, err := tx.LockByUserID(userID)
+		if err != nil {
+			return err
+		}
+		// The hold is read under the wallet's lock so a second capture
+		// sees it closed
+		if hold, err = s.getUserHold(tx, userID, holdID); err != nil {
+			return err
+		}
+		if err := s.EnforceLock(ctx, wallet, "capture_hold", models.WalletDirectionDebit, hold.Amount); err != nil {
+			return err
+		}
+
+		// The hold is closed first so the funds it reserves are available
+		// to its own capture
+		now := time.Now()
+		hold.Status = models.HoldStatusCaptured
+		hold.ReleasedAt = &now
+		if err := tx.UpdateHold(hold); err != nil {
+			return err
+		}
+		if _, err := tx.AdjustBalance(userID, -hold.Amount); err != nil {
+			return err
+		}
+
+		return tx.CreateTransaction(&models.Transaction{
+			SenderID:    userID,
+			Type:        "debit",
+			Amount:      hold.Amount,
+			Status:      "completed",
+			Description: fmt.Sprintf("Captured %s hold", hold.Type),
+			Reference:   hold.Reference,
+			Metadata: models.NewJSON(map[string]interface{}{
+				"hold_id":   hold.ID,
+				"hold_type": hold.Type,
+			}),
+		})
+	})
+	if err != nil {
+		s.metrics.RecordError("capture_hold", err.Error())
+		return err
+	}
+
+	s.refreshWalletCache(ctx, userID)
+	s.metrics.RecordTransaction("capture_hold", hold.Amount)
+	return nil
+}
+
+func (s *service) getUserHold(repo repositories.WalletRepository, userID, holdID uint) (*models.WalletHold, error) {
+	hold, err := repo.GetHoldByID(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, repositories.ErrHoldNotFound
+	}
+	if !hold.IsActive(time.Now()) {
+		return nil, ErrHoldNotActive
+	}
+	return hold, nil
+}
@@ -2,32 +2,63 @@ package wallet
 
 import (
 	"context"
-	"database/sql"
 	"orus/internal/models"
-
-	"gorm.io/gorm"
 )
 
 // Service defines the main wallet service interface
 type Service interface {
 	// Core wallet operations
+	// GetWallet is ReadWallet with ReadCached
 	GetWallet(ctx context.Context, userID uint) (*models.Wallet, error)
-	Credit(ctx context.Context, userID uint, amount float64) error
-	Debit(ctx context.Context, userID uint, amount float64) error
+	// ReadWallet returns the user's wallet, from the cache or the database
+	// as the consistency asks
+	ReadWallet(ctx context.Context, userID uint, consistency ReadConsistency) (*models.Wallet, error)
+	// Credit and Debit move money in and out of the wallet with walletID,
+	// within the role's transaction limits
+	Credit(ctx context.Context, walletID uint, amount float64) error
+	Debit(ctx context.Context, walletID uint, amount float64) error
+	// Refund credits back what a Debit took, outside the limits
+	Refund(ctx context.Context, walletID uint, amount float64) error
 
-	// Card operations
-	TopUp(ctx context.Context, userID uint, cardID uint, amount float64) error
+	// Card operations. A top-up needing 3-D Secure comes back requiring
+	// action and is completed by HandleCardTopUpEvent.
+	TopUp(ctx context.Context, userID uint, cardID uint, amount float64) (*models.CardTopUp, error)
+	GetTopUp(ctx context.Context, userID, topUpID uint) (*models.CardTopUp, error)
+	HandleCardTopUpEvent(ctx context.Context, event CardTopUpEvent) (*models.CardTopUp, error)
 	Withdraw(ctx context.Context, userID uint, cardID uint, amount float64) error
 
 	// Balance operations
 	GetBalance(ctx context.Context, userID uint) (float64, error)
+	GetBalanceDetails(ctx context.Context, userID uint) (*BalanceDetails, error)
 	ValidateBalance(ctx context.Context, userID uint, amount float64) error
 	UpdateBalanceOnly(ctx context.Context, userID uint, amount float64) error
 
+	// Hold operations
+	PlaceHold(ctx context.Context, userID uint, req HoldRequest) (*models.WalletHold, error)
+	ReleaseHold(ctx context.Context, userID uint, holdID uint) error
+	CaptureHold(ctx context.Context, userID uint, holdID uint) error
+
 	// Wallet management
 	CreateWallet(ctx context.Context, userID uint, currency string) (*models.Wallet, error)
 	UpdateWallet(ctx context.Context, wallet *models.Wallet) error
 
+	// Wallet locks. A nil actorID is the wallet's owner acting on their own
+	// wallet: they may only place and lift user_requested locks.
+	LockWallet(ctx context.Context, actorID *uint, userID uint, req LockRequest) (*models.WalletLock, error)
+	UnlockWallet(ctx context.Context, actorID *uint, userID uint) (*models.WalletLock, error)
+	GetLock(ctx context.Context, userID uint) (*models.WalletLock, error)
+	LockEvents(ctx context.Context, userID uint, limit, offset int) ([]models.WalletLockEvent, int64, error)
+	// EnforceLock returns an error, and audits the refusal, if the wallet's
+	// lock stops money moving in direction. Services moving funds outside
+	// this one call it before touching the balance.
+	EnforceLock(ctx context.Context, wallet *models.Wallet, operation, direction string, amount float64) error
+	// ExpireLocks lifts locks past their expiry, returning how many it lifted
+	ExpireLocks(ctx context.Context) (int, error)
+
+	// TransferFunds moves tx.Amount from the sender's wallet to the
+	// receiver's and records tx completed, in one database transaction
+	TransferFunds(ctx context.Context, tx *models.Transaction) (*models.Transaction, error)
+
 	// Batch operations
 	ProcessBatchTransfers(ctx context.Context, transfers []TransferRequest) error
 
@@ -39,11 +70,30 @@ type Service interface {
 	GetWithdrawalFeePercent() float64
 }
 
-type DB interface {
-	First(dest interface{}, conds ...interface{}) *gorm.DB
-	Save(value interface{}) *gorm.DB
-	Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error
-	WithContext(ctx context.Context) *gorm.DB
-	Create(value interface{}) *gorm.DB
-	Where(query interface{}, args ...interface{}) *gorm.DB
+// Notifier tells users about locks on their wallet
+type Notifier interface {
+	SendWalletLockNotification(ctx context.Context, userID uint, lock *models.WalletLock, event string) error
+}
+
+// SharedService manages wallets owned by several users. Every operation
+// checks the caller's member role, and payments also their spending limits.
+type SharedService interface {
+	CreateSharedWallet(ctx context.Context, userID uint, req SharedWalletRequest) (*SharedWalletView, error)
+	ListSharedWallets(ctx context.Context, userID uint) ([]models.SharedWallet, error)
+	GetSharedWallet(ctx context.Context, userID, walletID uint) (*SharedWalletView, error)
+	UpdateSharedWallet(ctx context.Context, userID, walletID uint, req SharedWalletRequest) (*SharedWalletView, error)
+
+	AddMember(ctx context.Context, userID, walletID uint, req MemberRequest) (*models.WalletMember, error)
+	UpdateMember(ctx context.Context, userID, walletID, memberID uint, req MemberRequest) (*models.WalletMember, error)
+	// RemoveMember removes a member; any member may remove themselves
+	RemoveMember(ctx context.Context, userID, walletID, memberID uint) error
+
+	// Contribute moves funds from the caller's personal wallet into the shared wallet
+	Contribute(ctx context.Context, userID, walletID uint, amount float64) (*models.SharedWallet, error)
+	// Pay sends funds from the shared wallet, or holds the payment for an
+	// owner when it is over the wallet's approval threshold
+	Pay(ctx context.Context, userID, walletID uint, req SharedPaymentRequest) (*models.SharedWalletPayment, error)
+	ListPayments(ctx context.Context, userID, walletID uint, status string, limit, offset int) ([]models.SharedWalletPayment, int64, error)
+	ApprovePayment(ctx context.Context, userID, walletID, paymentID uint) (*models.SharedWalletPayment, error)
+	RejectPayment(ctx context.Context, userID, walletID, paymentID uint) (*models.SharedWalletPayment, error)
 }
@@ -0,0 +1,24 @@
+package wallet
+
+import (
+	"context"
+	"log"
+)
+
+// Job lifts wallet locks once their expiry has passed
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the wallet service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "wallet-lock-expiry" }
+
+func (j *Job) Run(ctx context.Context) error {
+	lifted, err := j.service.ExpireLocks(ctx)
+	if lifted > 0 {
+		log.Printf("Lifted %d expired wallet locks", lifted)
+	}
+	return err
+}
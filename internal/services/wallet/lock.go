@@ -0,0 +1,45 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orus/internal/repositories/cache"
+	"sort"
+	"time"
+)
+
+// lockWallets takes the distributed locks on users' wallets so no other
+// instance moves their funds until unlock is called. Locks are taken in
+// user ID order, so two transfers between the same wallets can't deadlock.
+// They expire shortly after ctx's deadline in case this instance dies
+// holding them.
+func (s *service) lockWallets(ctx context.Context, userIDs ...uint) (unlock func(), err error) {
+	ids := append([]uint(nil), userIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	ttl := s.config.ProcessingTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		ttl = time.Until(deadline)
+	}
+	ttl += LockExpirySlack
+
+	locks := make([]*cache.Lock, 0, len(ids))
+	unlock = func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock(ctx)
+		}
+	}
+	for _, id := range ids {
+		lock, err := s.cache.Lock(ctx, fmt.Sprintf("wallet:user:%d", id), ttl, LockWait)
+		if err != nil {
+			unlock()
+			if errors.Is(err, cache.ErrLockBusy) {
+				return nil, ErrWalletBusy
+			}
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	return unlock, nil
+}
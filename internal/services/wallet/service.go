@@ -9,29 +9,42 @@ import (
 	"orus/internal/models"
 	"orus/internal/repositories"
 	"orus/internal/repositories/cache"
+	"orus/internal/resilience"
 	creditcard "orus/internal/services/credit-card"
 	"time"
 )
 
 type service struct {
 	repo        repositories.WalletRepository
+	locks       repositories.WalletLockRepository
+	topUps      repositories.CardTopUpRepository
 	cache       *cache.CacheService
 	cardService creditcard.Service
 	config      WalletConfig
 	metrics     MetricsCollector
+	notifier    Notifier
 }
 
 // NewService creates a new wallet service
 func NewService(
 	repo repositories.WalletRepository,
+	locks repositories.WalletLockRepository,
+	topUps repositories.CardTopUpRepository,
 	cache *cache.CacheService,
 	cardService creditcard.Service,
 	config WalletConfig,
 	metrics MetricsCollector,
+	notifier Notifier,
 ) Service {
 	if repo == nil {
 		panic("repo is required")
 	}
+	if locks == nil {
+		panic("lock repo is required")
+	}
+	if topUps == nil {
+		panic("top-up repo is required")
+	}
 	if cache == nil {
 		panic("cache is required")
 	}
@@ -76,47 +89,70 @@ func NewService(
 
 	return &service{
 		repo:        repo,
+		locks:       locks,
+		topUps:      topUps,
 		cache:       cache,
 		cardService: cardService,
 		config:      config,
 		metrics:     metrics,
+		notifier:    notifier,
 	}
 }
 
+// operation bounds a wallet operation by the processing timeout and binds
+// the repository to it, so a slow query is cancelled rather than left
+// holding wallet rows locked. finish turns a missed deadline into a
+// *resilience.TimeoutError.
+func (s *service) operation(ctx context.Context, op string) (context.Context, repositories.WalletRepository, func(*error)) {
+	ctx, finish := resilience.WithDeadline(ctx, op, s.config.ProcessingTimeout)
+	return ctx, s.repo.WithContext(ctx), finish
+}
+
 func (s *service) GetWallet(ctx context.Context, userID uint) (*models.Wallet, error) {
-	// For critical operations like withdrawals, get fresh data from DB
-	if ctx.Value("critical_operation") != nil {
-		var wallet models.Wallet
-		if err := repositories.DB.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+	return s.ReadWallet(ctx, userID, ReadCached)
+}
+
+func (s *service) ReadWallet(ctx context.Context, userID uint, consistency ReadConsistency) (_ *models.Wallet, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet read")
+	defer finish(&err)
+
+	if consistency == ReadStrong {
+		wallet, err := repo.GetByUserID(userID)
+		if err != nil {
 			return nil, err
 		}
-		return &wallet, nil
+		s.writeThrough(ctx, wallet)
+		return wallet, nil
 	}
 
-	// Try to get from cache first
 	cacheKey := s.cache.GenerateKey("wallet", "user", userID)
-
-	// Check if we have it in cache
-	var cachedWallet *models.Wallet
-	if _, err := s.cache.Get(ctx, cacheKey, &cachedWallet); err == nil && cachedWallet != nil {
-		return cachedWallet, nil
+	if wallet, err := s.cache.GetWallet(ctx, userID); err == nil && wallet != nil {
+		s.metrics.RecordCacheHit(cacheKey)
+		return wallet, nil
 	}
+	s.metrics.RecordCacheMiss(cacheKey)
 
-	// If not in cache, get from database
-	wallet, err := s.repo.GetByUserID(userID)
+	// Concurrent misses share one database read, which one caller going
+	// away mustn't cancel for the rest
+	loaded, err := s.cache.Coalesce(cacheKey, func() (interface{}, error) {
+		wallet, err := s.repo.GetByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		s.writeThrough(ctx, wallet)
+		return wallet, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	// Store in cache for future use (with a shorter TTL to reduce stale data issues)
-	if err := s.cache.SetWithTTL(ctx, cacheKey, wallet, 1*time.Minute); err != nil {
-		log.Printf("Failed to cache wallet: %v", err)
-	}
-
-	return wallet, nil
+	wallet := *loaded.(*models.Wallet)
+	return &wallet, nil
 }
 
-func (s *service) CreateWallet(ctx context.Context, userID uint, currency string) (*models.Wallet, error) {
+func (s *service) CreateWallet(ctx context.Context, userID uint, currency string) (_ *models.Wallet, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet creation")
+	defer finish(&err)
+
 	wallet := &models.Wallet{
 		UserID:   userID,
 		Balance:  0,
@@ -124,17 +160,18 @@ func (s *service) CreateWallet(ctx context.Context, userID uint, currency string
 		Currency: currency,
 	}
 
-	if err := s.repo.Create(wallet); err != nil {
+	if err := repo.Create(wallet); err != nil {
 		return nil, fmt.Errorf("failed to create wallet: %w", err)
 	}
 
-	// Update cache
-	key := s.cache.GenerateKey("wallet", "user", userID)
-	s.cache.SetWithTTL(ctx, key, wallet, CacheDuration)
+	s.writeThrough(ctx, wallet)
 	return wallet, nil
 }
 
-func (s *service) Credit(ctx context.Context, walletID uint, amount float64) error {
+func (s *service) Credit(ctx context.Context, walletID uint, amount float64) (err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet credit")
+	defer finish(&err)
+
 	// Get user role from context with proper type assertion
 	roleVal := ctx.Value(UserRoleContextKey)
 	role, ok := roleVal.(string)
@@ -151,19 +188,34 @@ func (s *service) Credit(ctx context.Context, walletID uint, amount float64) err
 		return fmt.Errorf("amount exceeds maximum limit of %v", limits.MaxTransactionAmount)
 	}
 
-	wallet, err := s.repo.GetByID(walletID)
-	if err != nil {
-		return fmt.Errorf("failed to get wallet: %w", err)
-	}
+	return s.credit(ctx, repo, walletID, amount, "Wallet credit")
+}
+
+// Refund credits back an amount a debit took. It skips the per-transaction
+// limits, which the original debit already passed.
+func (s *service) Refund(ctx context.Context, walletID uint, amount float64) (err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet refund")
+	defer finish(&err)
 
-	if wallet.Status != "active" {
-		return ErrWalletLocked
+	if amount <= 0 {
+		return ErrInvalidAmount
 	}
+	return s.credit(ctx, repo, walletID, amount, "Wallet refund")
+}
 
-	// Perform the credit operation in a transaction
-	err = s.repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
-		wallet.Balance += amount
-		if err := tx.Update(wallet); err != nil {
+func (s *service) credit(ctx context.Context, repo repositories.WalletRepository, walletID uint, amount float64, description string) error {
+	// The wallet is read with its row locked, so the lock check holds until
+	// the balance is written
+	var wallet *models.Wallet
+	err := repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		var err error
+		if wallet, err = lockedWallet(tx, walletID); err != nil {
+			return err
+		}
+		if err := s.EnforceLock(ctx, wallet, "credit", models.WalletDirectionCredit, amount); err != nil {
+			return err
+		}
+		if _, err := tx.AdjustBalance(wallet.UserID, amount); err != nil {
 			return err
 		}
 
@@ -172,7 +224,7 @@ func (s *service) Credit(ctx context.Context, walletID uint, amount float64) err
 			SenderID:    walletID,
 			Type:        "credit",
 			Amount:      amount,
-			Description: "Wallet credit",
+			Description: description,
 			Status:      "completed",
 		}
 		return tx.CreateTransaction(txn)
@@ -180,12 +232,11 @@ func (s *service) Credit(ctx context.Context, walletID uint, amount float64) err
 
 	if err != nil {
 		s.metrics.RecordError("credit", err.Error())
-		return ErrTransactionFailed
+		return walletOperationError(err)
 	}
 
-	// Invalidate cache
-	senderKey := s.cache.GenerateKey("wallet", "user", walletID)
-	s.cache.Delete(ctx, senderKey)
+	// Write the new balance through to the cache
+	s.refreshWalletCache(ctx, wallet.UserID)
 
 	// Record metrics
 	s.metrics.RecordTransaction("credit", amount)
@@ -193,24 +244,26 @@ func (s *service) Credit(ctx context.Context, walletID uint, amount float64) err
 	return nil
 }
 
-func (s *service) Debit(ctx context.Context, walletID uint, amount float64) error {
+func (s *service) Debit(ctx context.Context, walletID uint, amount float64) (err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet debit")
+	defer finish(&err)
+
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
 
-	wallet, err := s.repo.GetByID(walletID)
-	if err != nil {
-		return fmt.Errorf("failed to get wallet: %w", err)
-	}
-
-	if wallet.Balance < amount {
-		return ErrInsufficientBalance
-	}
-
-	// Perform the debit operation in a transaction
-	err = s.repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
-		wallet.Balance -= amount
-		if err := tx.Update(wallet); err != nil {
+	// The wallet is read with its row locked, so the lock check holds until
+	// AdjustBalance checks the balance and debits it
+	var wallet *models.Wallet
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		var err error
+		if wallet, err = lockedWallet(tx, walletID); err != nil {
+			return err
+		}
+		if err := s.EnforceLock(ctx, wallet, "debit", models.WalletDirectionDebit, amount); err != nil {
+			return err
+		}
+		if _, err := tx.AdjustBalance(wallet.UserID, -amount); err != nil {
 			return err
 		}
 
@@ -227,12 +280,11 @@ func (s *service) Debit(ctx context.Context, walletID uint, amount float64) erro
 
 	if err != nil {
 		s.metrics.RecordError("debit", err.Error())
-		return ErrTransactionFailed
+		return walletOperationError(err)
 	}
 
-	// Invalidate cache
-	senderKey := s.cache.GenerateKey("wallet", "user", walletID)
-	s.cache.Delete(ctx, senderKey)
+	// Write the new balance through to the cache
+	s.refreshWalletCache(ctx, wallet.UserID)
 
 	// Record metrics
 	s.metrics.RecordTransaction("debit", amount)
@@ -240,50 +292,95 @@ func (s *service) Debit(ctx context.Context, walletID uint, amount float64) erro
 	return nil
 }
 
-func (s *service) GetBalance(ctx context.Context, walletID uint) (float64, error) {
-	wallet, err := s.repo.GetByID(walletID)
+// lockedWallet reads the wallet with its row locked for the rest of the
+// database transaction
+func lockedWallet(tx repositories.WalletRepository, walletID uint) (*models.Wallet, error) {
+	wallets, err := tx.LockByIDs(walletID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get wallet: %w", err)
+		return nil, err
+	}
+	wallet, ok := wallets[walletID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get wallet: %w", repositories.ErrWalletNotFound)
 	}
-	return wallet.Balance, nil
+	return wallet, nil
 }
 
-func (s *service) ValidateBalance(ctx context.Context, userID uint, amount float64) error {
+// walletOperationError keeps the refusals callers act on, reporting any
+// other failure of a credit or debit as ErrTransactionFailed
+func walletOperationError(err error) error {
+	switch {
+	case errors.Is(err, ErrInsufficientBalance), errors.Is(err, repositories.ErrWalletNotFound),
+		errors.Is(err, ErrWalletLocked), errors.Is(err, ErrDebitsLocked), errors.Is(err, ErrCreditsLocked):
+		return err
+	case errors.Is(err, repositories.ErrOverdraftExceeded), errors.Is(err, repositories.ErrInsufficientBalance):
+		return ErrInsufficientBalance
+	}
+	return ErrTransactionFailed
+}
+
+// GetBalance returns the available balance, i.e. the stored balance minus active holds
+func (s *service) GetBalance(ctx context.Context, userID uint) (float64, error) {
+	details, err := s.GetBalanceDetails(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return details.Available, nil
+}
+
+func (s *service) ValidateBalance(ctx context.Context, userID uint, amount float64) (err error) {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
 
-	wallet, err := s.GetWallet(ctx, userID)
+	ctx, repo, finish := s.operation(ctx, "balance check")
+	defer finish(&err)
+
+	wallet, err := repo.GetByUserID(userID)
 	if err != nil {
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	// Callers check the balance before moving funds out, so the lock is
+	// enforced here too, before anything is reserved or debited
+	if err := s.EnforceLock(ctx, wallet, "balance_check", models.WalletDirectionDebit, amount); err != nil {
 		return err
 	}
 
-	if wallet.Balance < amount {
+	available, err := s.availableBalance(repo, wallet)
+	if err != nil {
+		return err
+	}
+	if available < amount {
 		return ErrInsufficientBalance
 	}
 
 	return nil
 }
 
-func (s *service) UpdateWallet(ctx context.Context, wallet *models.Wallet) error {
+func (s *service) UpdateWallet(ctx context.Context, wallet *models.Wallet) (err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet update")
+	defer finish(&err)
+
 	if wallet == nil {
 		return fmt.Errorf("wallet cannot be nil")
 	}
 
 	wallet.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(wallet); err != nil {
+	if err := repo.Update(wallet); err != nil {
 		return fmt.Errorf("failed to update wallet: %w", err)
 	}
 
-	// Invalidate cache
-	senderKey := s.cache.GenerateKey("wallet", "user", wallet.UserID)
-	s.cache.Delete(ctx, senderKey)
+	s.refreshWalletCache(ctx, wallet.UserID)
 
 	return nil
 }
 
-func (s *service) ProcessBatchTransfers(ctx context.Context, transfers []TransferRequest) error {
+func (s *service) ProcessBatchTransfers(ctx context.Context, transfers []TransferRequest) (err error) {
+	ctx, repo, finish := s.operation(ctx, "batch transfer")
+	defer finish(&err)
+
 	if len(transfers) == 0 {
 		return nil
 	}
@@ -293,8 +390,9 @@ func (s *service) ProcessBatchTransfers(ctx context.Context, transfers []Transfe
 		Error    error
 	}
 	results := make([]transferResult, 0)
+	var moved []uint
 
-	err := s.repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
 		for _, transfer := range transfers {
 			// Validate transfer
 			if err := s.validateTransfer(ctx, transfer); err != nil {
@@ -314,10 +412,12 @@ func (s *service) ProcessBatchTransfers(ctx context.Context, transfers []Transfe
 			}
 
 			// Process transfer
-			if err := s.processTransfer(ctx, tx, transfer); err != nil {
+			users, err := s.processTransfer(ctx, tx, transfer)
+			if err != nil {
 				results = append(results, transferResult{Transfer: transfer, Error: err})
 				continue
 			}
+			moved = append(moved, users...)
 
 			results = append(results, transferResult{Transfer: transfer, Error: nil})
 		}
@@ -350,6 +450,9 @@ func (s *service) ProcessBatchTransfers(ctx context.Context, transfers []Transfe
 		return err
 	}
 
+	// Write the new balances through to the cache
+	s.refreshWalletCache(ctx, moved...)
+
 	return nil
 }
 
@@ -393,7 +496,10 @@ func (s *service) checkMonthlyLimit(ctx context.Context, userID uint, amount flo
 	return nil
 }
 
-func (s *service) GetTransactionHistory(ctx context.Context, userID uint, limit, offset int) ([]TransactionHistory, error) {
+func (s *service) GetTransactionHistory(ctx context.Context, userID uint, limit, offset int) (_ []TransactionHistory, err error) {
+	ctx, repo, finish := s.operation(ctx, "transaction history")
+	defer finish(&err)
+
 	// Generate cache key for common queries
 	cacheKey := fmt.Sprintf("tx_history:%d:%d:%d", userID, limit, offset)
 
@@ -408,7 +514,7 @@ func (s *service) GetTransactionHistory(ctx context.Context, userID uint, limit,
 
 	// Cache miss, fetch from database
 	var history []TransactionHistory
-	err := s.repo.GetTransactionHistory(ctx, userID, limit, offset, &history)
+	err = repo.GetTransactionHistory(ctx, userID, limit, offset, &history)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction history: %w", err)
 	}
@@ -436,18 +542,6 @@ func (s *service) recordTransaction(tx repositories.WalletRepository, walletID u
 	return tx.CreateTransaction(transaction)
 }
 
-// Add new cache invalidation helper
-func (s *service) invalidateWalletCaches(ctx context.Context, userIDs ...uint) {
-	for _, userID := range userIDs {
-		key := s.cache.GenerateKey("wallet", "user", userID)
-		if err := s.cache.Delete(ctx, key); err != nil {
-			log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
-		} else {
-			log.Printf("Cache INVALIDATE: wallet:%d", userID)
-		}
-	}
-}
-
 // Add helper method for transfer validation
 func (s *service) validateTransfer(ctx context.Context, transfer TransferRequest) error {
 	// Get user role from context
@@ -485,31 +579,51 @@ func (s *service) validateTransfer(ctx context.Context, transfer TransferRequest
 	return nil
 }
 
-// Add helper method for processing individual transfers
-func (s *service) processTransfer(ctx context.Context, tx repositories.WalletRepository, transfer TransferRequest) error {
-	// Debit from source wallet
-	if err := s.Debit(ctx, transfer.FromWalletID, transfer.Amount); err != nil {
-		return fmt.Errorf("failed to debit from wallet %d: %w", transfer.FromWalletID, err)
+// processTransfer moves one transfer of a batch within the batch's
+// database transaction and returns the users whose balances changed. Like
+// TransferFunds it refuses locked wallets and funds on hold, checked with
+// both rows locked so nothing changes before the move.
+func (s *service) processTransfer(ctx context.Context, tx repositories.WalletRepository, transfer TransferRequest) ([]uint, error) {
+	wallets, err := tx.LockByIDs(transfer.FromWalletID, transfer.ToWalletID)
+	if err != nil {
+		return nil, err
+	}
+	from, ok := wallets[transfer.FromWalletID]
+	if !ok {
+		return nil, fmt.Errorf("source wallet not found: %w", repositories.ErrWalletNotFound)
+	}
+	to, ok := wallets[transfer.ToWalletID]
+	if !ok {
+		return nil, fmt.Errorf("destination wallet not found: %w", repositories.ErrWalletNotFound)
 	}
 
-	// Credit to destination wallet
-	if err := s.Credit(ctx, transfer.ToWalletID, transfer.Amount); err != nil {
-		// Rollback the debit if credit fails
-		if rbErr := s.Debit(ctx, transfer.FromWalletID, transfer.Amount); rbErr != nil {
-			return fmt.Errorf("critical error: debit failed and rollback failed: %v, %v", err, rbErr)
-		}
-		return fmt.Errorf("failed to credit to wallet %d: %w", transfer.ToWalletID, err)
+	if err := s.EnforceLock(ctx, from, "transfer", models.WalletDirectionDebit, transfer.Amount); err != nil {
+		return nil, err
+	}
+	if err := s.EnforceLock(ctx, to, "transfer", models.WalletDirectionCredit, transfer.Amount); err != nil {
+		return nil, err
+	}
+	available, err := s.availableBalance(tx, from)
+	if err != nil {
+		return nil, err
+	}
+	if available < transfer.Amount {
+		return nil, ErrInsufficientBalance
+	}
+
+	if _, _, err := tx.TransferFunds(from.UserID, to.UserID, transfer.Amount, 0); err != nil {
+		return nil, fmt.Errorf("failed to transfer from wallet %d to wallet %d: %w", transfer.FromWalletID, transfer.ToWalletID, err)
 	}
 
 	// Record the transfer
 	if err := s.recordTransaction(tx, transfer.FromWalletID, transfer.Amount, "debit", transfer.Description); err != nil {
-		return err
+		return nil, err
 	}
 	if err := s.recordTransaction(tx, transfer.ToWalletID, transfer.Amount, "credit", transfer.Description); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return []uint{from.UserID, to.UserID}, nil
 }
 
 // Process implements TransactionProcessor interface
@@ -524,12 +638,28 @@ func (s *service) Process(ctx context.Context, tx *models.Transaction) error {
 func (s *service) Rollback(ctx context.Context, tx *models.Transaction) error {
 	// Reverse the transaction
 	if tx.Type == "debit" {
-		return s.Credit(ctx, tx.SenderID, tx.Amount)
+		return s.Refund(ctx, tx.SenderID, tx.Amount)
 	}
 	return s.Debit(ctx, tx.SenderID, tx.Amount)
 }
 
 func (s *service) Transfer(ctx context.Context, fromUserID, toUserID uint, amount float64, description string) (*models.Transaction, error) {
+	return s.TransferFunds(ctx, &models.Transaction{
+		SenderID:      fromUserID,
+		ReceiverID:    toUserID,
+		Amount:        amount,
+		Type:          "transfer",
+		Description:   description,
+		TransactionID: fmt.Sprintf("TRF-%d-%d-%d", fromUserID, toUserID, time.Now().UnixNano()),
+	})
+}
+
+func (s *service) TransferFunds(ctx context.Context, transaction *models.Transaction) (_ *models.Transaction, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet transfer")
+	defer finish(&err)
+
+	fromUserID, toUserID, amount := transaction.SenderID, transaction.ReceiverID, transaction.Amount
+
 	// Debug logs
 	log.Printf("Transfer request - From User: %d, To User: %d, Amount: %.2f\n", fromUserID, toUserID, amount)
 
@@ -542,76 +672,57 @@ func (s *service) Transfer(ctx context.Context, fromUserID, toUserID uint, amoun
 		return nil, errors.New("cannot transfer to self")
 	}
 
+	unlock, err := s.lockWallets(ctx, fromUserID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// Get source wallet directly from database to avoid cache issues
-	var sourceWallet models.Wallet
-	if err := repositories.DB.Where("user_id = ?", fromUserID).First(&sourceWallet).Error; err != nil {
+	sourceWallet, err := repo.GetByUserID(fromUserID)
+	if err != nil {
 		log.Printf("Source wallet error - User ID: %d, Error: %v\n", fromUserID, err)
 		return nil, fmt.Errorf("source wallet not found: %w", err)
 	}
 
 	// Get destination wallet directly from database
-	var destWallet models.Wallet
-	if err := repositories.DB.Where("user_id = ?", toUserID).First(&destWallet).Error; err != nil {
+	destWallet, err := repo.GetByUserID(toUserID)
+	if err != nil {
 		log.Printf("Destination wallet error - User ID: %d, Error: %v\n", toUserID, err)
 		return nil, fmt.Errorf("destination wallet not found: %w", err)
 	}
 
-	log.Printf("Wallets found - Source User: %d (Balance: %.2f), Dest User: %d (Balance: %.2f)\n",
-		sourceWallet.UserID, sourceWallet.Balance, destWallet.UserID, destWallet.Balance)
-
-	if sourceWallet.Status != "active" {
-		return nil, ErrWalletLocked
+	if err := s.EnforceLock(ctx, sourceWallet, transaction.Type, models.WalletDirectionDebit, amount); err != nil {
+		return nil, err
+	}
+	if err := s.EnforceLock(ctx, destWallet, transaction.Type, models.WalletDirectionCredit, amount); err != nil {
+		return nil, err
+	}
+	available, err := s.availableBalance(repo, sourceWallet)
+	if err != nil {
+		return nil, err
 	}
-	if sourceWallet.Balance < amount {
+	if available < amount {
 		return nil, ErrInsufficientBalance
 	}
 
-	var transaction *models.Transaction
-
-	// Execute transfer in a transaction
-	err := s.repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
-		// Debit source wallet
-		sourceWallet.Balance -= amount
-		if err := tx.Update(&sourceWallet); err != nil {
-			return err
-		}
-
-		// Credit destination wallet
-		destWallet.Balance += amount
-		if err := tx.Update(&destWallet); err != nil {
-			return err
-		}
-
-		// Record transaction
-		transferTx := &models.Transaction{
-			SenderID:      fromUserID,
-			ReceiverID:    toUserID,
-			Amount:        amount,
-			Type:          "transfer",
-			Status:        "completed",
-			Description:   description,
-			TransactionID: fmt.Sprintf("TRF-%d-%d-%d", fromUserID, toUserID, time.Now().UnixNano()),
-		}
-
-		if err := tx.CreateTransaction(transferTx); err != nil {
+	// Move the funds and record the transaction together
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		if _, _, err := tx.TransferFunds(fromUserID, toUserID, amount, 0); err != nil {
 			return err
 		}
-
-		transaction = transferTx
-		return nil
+		transaction.Status = "completed"
+		transaction.ProcessedAt = time.Now()
+		return tx.CreateTransaction(transaction)
 	})
 
 	if err != nil {
 		s.metrics.RecordError("transfer", err.Error())
-		return nil, ErrTransactionFailed
+		return nil, walletOperationError(err)
 	}
 
-	// Invalidate caches
-	senderKey := s.cache.GenerateKey("wallet", "user", fromUserID)
-	receiverKey := s.cache.GenerateKey("wallet", "user", toUserID)
-	s.cache.Delete(ctx, senderKey)
-	s.cache.Delete(ctx, receiverKey)
-	s.invalidateWalletCaches(ctx, fromUserID, toUserID)
+	// Write both new balances through to the cache
+	s.refreshWalletCache(ctx, fromUserID, toUserID)
 
 	// Record metrics
 	s.metrics.RecordTransaction("transfer", amount)
@@ -619,99 +730,10 @@ func (s *service) Transfer(ctx context.Context, fromUserID, toUserID uint, amoun
 	return transaction, nil
 }
 
-func (s *service) TopUp(ctx context.Context, userID, cardID uint, amount float64) error {
-	// Get user role from context
-	roleVal := ctx.Value(UserRoleContextKey)
-	role, ok := roleVal.(string)
-	if !ok || role == "" {
-		role = "user" // Default to user limits
-	}
-
-	limits := s.config.Limits[role]
-	if amount <= 0 || amount < limits.MinTransactionAmount {
-		return ErrInvalidAmount
-	}
-
-	if amount > limits.MaxTransactionAmount {
-		return fmt.Errorf("amount exceeds maximum limit of %v", limits.MaxTransactionAmount)
-	}
-
-	// Get wallet by user ID instead of wallet ID
-	wallet, err := s.repo.GetByUserID(userID)
-	if err != nil {
-		// If wallet not found, create a new one
-		if err == repositories.ErrWalletNotFound {
-			wallet, err = s.CreateWallet(ctx, userID, "USD")
-			if err != nil {
-				return fmt.Errorf("failed to create wallet: %w", err)
-			}
-		} else {
-			return fmt.Errorf("wallet not found: %w", err)
-		}
-	}
-
-	if wallet.Status != "active" {
-		return ErrWalletLocked
-	}
-
-	// Get card details
-	card, err := s.cardService.GetByID(cardID)
-	if err != nil {
-		return fmt.Errorf("failed to get card details: %w", err)
-	}
-
-	// Verify card ownership
-	if card.UserID != userID {
-		return fmt.Errorf("card does not belong to user")
-	}
-
-	cardLastFour := card.CardNumber[len(card.CardNumber)-4:]
-
-	// Process top-up
-	err = s.repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
-		// Round the balance to 2 decimal places when updating
-		wallet.Balance = math.Round((wallet.Balance+amount)*100) / 100
-		if err := tx.Update(wallet); err != nil {
-			return err
-		}
-
-		topUpTx := &models.Transaction{
-			Type:          "top_up",
-			SenderID:      userID,
-			ReceiverID:    0, // No receiver for top-ups
-			Amount:        amount,
-			Status:        "completed",
-			TransactionID: fmt.Sprintf("TXN-%d-%d", userID, time.Now().UnixNano()),
-			Reference:     fmt.Sprintf("TOP-%d-%d", userID, time.Now().UnixNano()),
-			PaymentType:   "card_topup",
-			PaymentMethod: "credit_card",
-			CardID:        &cardID,
-			Category:      "Top Up",
-			Description:   fmt.Sprintf("Top up from card ending in %s", cardLastFour),
-			Metadata: models.NewJSON(map[string]interface{}{
-				"card_last_four": cardLastFour,
-				"card_type":      card.CardType,
-			}),
-		}
-		return tx.CreateTransaction(topUpTx)
-	})
+func (s *service) Withdraw(ctx context.Context, userID uint, cardID uint, amount float64) (err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet withdrawal")
+	defer finish(&err)
 
-	if err != nil {
-		s.metrics.RecordError("top_up", err.Error())
-		return ErrTransactionFailed
-	}
-
-	// Invalidate all caches
-	senderKey := s.cache.GenerateKey("wallet", "user", userID)
-	s.cache.Delete(ctx, senderKey)
-	s.invalidateWalletCaches(ctx, userID)
-
-	s.metrics.RecordTransaction("top_up", amount)
-
-	return nil
-}
-
-func (s *service) Withdraw(ctx context.Context, userID uint, cardID uint, amount float64) error {
 	// Add card validation
 	card, err := s.cardService.GetByIDAndUserID(cardID, userID)
 	if err != nil {
@@ -738,24 +760,23 @@ func (s *service) Withdraw(ctx context.Context, userID uint, cardID uint, amount
 		return ErrInvalidAmount
 	}
 
-	// Get fresh wallet data directly from database, bypassing cache
-	var wallet models.Wallet
-	if err := repositories.DB.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
-		return fmt.Errorf("wallet not found: %w", err)
-	}
-
-	if wallet.Balance < totalAmount {
-		return ErrInsufficientBalance
-	}
-
-	if wallet.Status != "active" {
-		return ErrWalletLocked
+	unlock, err := s.lockWallets(ctx, userID)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	err = s.repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
-		// Round the balance to 2 decimal places when updating
-		wallet.Balance = math.Round((wallet.Balance-totalAmount)*100) / 100
-		if err := tx.Update(&wallet); err != nil {
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		// The wallet is read with its row locked, so the lock check holds
+		// until AdjustBalance checks the balance and debits it
+		wallet, err := tx.LockByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if err := s.EnforceLock(ctx, wallet, "withdrawal", models.WalletDirectionDebit, totalAmount); err != nil {
+			return err
+		}
+		if _, err := tx.AdjustBalance(userID, -totalAmount); err != nil {
 			return err
 		}
 
@@ -783,6 +804,7 @@ func (s *service) Withdraw(ctx context.Context, userID uint, cardID uint, amount
 				Status:      "completed",
 				Description: "Withdrawal fee",
 				Metadata: models.NewJSON(map[string]interface{}{
+					"fee_type":          "withdrawal",
 					"withdrawal_amount": amount,
 					"fee_percent":       feePercent,
 				}),
@@ -796,80 +818,50 @@ func (s *service) Withdraw(ctx context.Context, userID uint, cardID uint, amount
 
 	if err != nil {
 		s.metrics.RecordError("withdrawal", err.Error())
-		return ErrTransactionFailed
+		return walletOperationError(err)
 	}
 
-	// Invalidate all caches
-	senderKey := s.cache.GenerateKey("wallet", "user", userID)
-	s.cache.Delete(ctx, senderKey)
-	s.invalidateWalletCaches(ctx, userID)
+	// Write the new balance through to the cache
+	s.refreshWalletCache(ctx, userID)
 
 	s.metrics.RecordTransaction("withdrawal", amount)
 
 	return nil
 }
 
-func (s *service) LockWallet(ctx context.Context, walletID uint, reason string) error {
-	wallet, err := s.repo.GetByID(walletID)
-	if err != nil {
-		return fmt.Errorf("wallet not found: %w", err)
-	}
-
-	wallet.Status = "locked"
-	wallet.StatusReason = reason
-
-	if err := s.repo.Update(wallet); err != nil {
-		return fmt.Errorf("failed to lock wallet: %w", err)
-	}
-
-	senderKey := s.cache.GenerateKey("wallet", "user", walletID)
-	s.cache.Delete(ctx, senderKey)
-	return nil
-}
-
-func (s *service) UnlockWallet(ctx context.Context, walletID uint) error {
-	wallet, err := s.repo.GetByID(walletID)
-	if err != nil {
-		return fmt.Errorf("wallet not found: %w", err)
-	}
-
-	wallet.Status = "active"
-	wallet.StatusReason = ""
-
-	if err := s.repo.Update(wallet); err != nil {
-		return fmt.Errorf("failed to unlock wallet: %w", err)
-	}
-
-	senderKey := s.cache.GenerateKey("wallet", "user", walletID)
-	s.cache.Delete(ctx, senderKey)
-	return nil
-}
-
 func (s *service) GetWithdrawalFeePercent() float64 {
 	// Default to user fee if no role specified
 	return s.config.WithdrawalFees["user"]
 }
 
 // UpdateBalanceOnly updates a wallet balance directly, bypassing cache
-func (s *service) UpdateBalanceOnly(ctx context.Context, userID uint, amount float64) error {
+func (s *service) UpdateBalanceOnly(ctx context.Context, userID uint, amount float64) (err error) {
+	ctx, repo, finish := s.operation(ctx, "balance update")
+	defer finish(&err)
+
 	// Log the operation
 	fmt.Printf("Updating balance for user %d by %.2f\n", userID, amount)
 
-	// Get wallet directly from database to avoid cache issues
-	var wallet models.Wallet
-	if err := repositories.DB.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
-		fmt.Printf("Failed to find wallet for user %d: %v\n", userID, err)
-		return fmt.Errorf("wallet not found: %w", err)
+	direction := models.WalletDirectionCredit
+	if amount < 0 {
+		direction = models.WalletDirectionDebit
 	}
 
-	fmt.Printf("Found wallet ID %d for user %d with current balance %.2f\n",
-		wallet.ID, userID, wallet.Balance)
-
-	// Update balance
-	wallet.Balance += amount
-
-	// Save directly to database
-	if err := repositories.DB.Save(&wallet).Error; err != nil {
+	// The wallet is read with its row locked, so the lock check holds
+	// until the balance moves
+	var wallet *models.Wallet
+	err = repo.ExecuteInTransaction(func(tx repositories.WalletRepository) error {
+		locked, err := tx.LockByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("wallet not found: %w", err)
+		}
+		if err := s.EnforceLock(ctx, locked, "balance_update", direction, math.Abs(amount)); err != nil {
+			return err
+		}
+		wallet, err = tx.AdjustBalance(userID, amount)
+		return err
+	})
+	if err != nil {
 		fmt.Printf("Failed to update wallet balance: %v\n", err)
 		return err
 	}
@@ -877,10 +869,8 @@ func (s *service) UpdateBalanceOnly(ctx context.Context, userID uint, amount flo
 	fmt.Printf("Updated wallet ID %d for user %d to new balance %.2f\n",
 		wallet.ID, userID, wallet.Balance)
 
-	// Invalidate all caches
-	senderKey := s.cache.GenerateKey("wallet", "user", userID)
-	s.cache.Delete(ctx, senderKey)
-	s.invalidateWalletCaches(ctx, userID)
+	// Write the new balance through to the cache
+	s.refreshWalletCache(ctx, userID)
 
 	return nil
 }
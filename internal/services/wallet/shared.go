@@ -0,0 +1,547 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/repositories/cache"
+	"orus/internal/services/fxrates"
+	"strings"
+	"time"
+)
+
+// MaxSharedWalletMembers caps the size of a family or team wallet
+const MaxSharedWalletMembers = 20
+
+type sharedService struct {
+	repo    repositories.SharedWalletRepository
+	users   repositories.UserRepository
+	wallets Service
+	fx      fxrates.Service
+	cache   *cache.CacheService
+}
+
+// NewSharedService creates the shared wallet service. Personal wallet
+// balances are checked through wallets so holds are respected, and money
+// moving between wallets in different currencies is converted through fx.
+func NewSharedService(
+	repo repositories.SharedWalletRepository,
+	users repositories.UserRepository,
+	wallets Service,
+	fx fxrates.Service,
+	cache *cache.CacheService,
+) SharedService {
+	return &sharedService{
+		repo:    repo,
+		users:   users,
+		wallets: wallets,
+		fx:      fx,
+		cache:   cache,
+	}
+}
+
+func (s *sharedService) CreateSharedWallet(ctx context.Context, userID uint, req SharedWalletRequest) (*SharedWalletView, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidWalletName
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if len(currency) != 3 {
+		return nil, ErrInvalidCurrency
+	}
+	// Members fund the wallet from personal wallets, so its currency needs
+	// a rate to convert from theirs
+	if currency != DefaultCurrency {
+		if _, err := s.fx.Quote(ctx, DefaultCurrency, currency); err != nil {
+			if errors.Is(err, fxrates.ErrUnsupportedCurrency) {
+				return nil, ErrInvalidCurrency
+			}
+			return nil, err
+		}
+	}
+
+	wallet := &models.SharedWallet{
+		Name:      name,
+		Currency:  currency,
+		Status:    StatusActive,
+		CreatedBy: userID,
+	}
+	if req.ApprovalThreshold != nil {
+		if *req.ApprovalThreshold < 0 {
+			return nil, ErrInvalidLimit
+		}
+		wallet.ApprovalThreshold = round2(*req.ApprovalThreshold)
+	}
+
+	owner := &models.WalletMember{UserID: userID, Role: models.WalletRoleOwner, AddedBy: userID}
+	if err := s.repo.Create(wallet, owner); err != nil {
+		return nil, err
+	}
+	return &SharedWalletView{Wallet: wallet, Role: owner.Role, Members: []models.WalletMember{*owner}}, nil
+}
+
+func (s *sharedService) ListSharedWallets(ctx context.Context, userID uint) ([]models.SharedWallet, error) {
+	return s.repo.GetByUserID(userID)
+}
+
+func (s *sharedService) GetSharedWallet(ctx context.Context, userID, walletID uint) (*SharedWalletView, error) {
+	wallet, member, err := s.authorize(userID, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(wallet, member)
+}
+
+func (s *sharedService) UpdateSharedWallet(ctx context.Context, userID, walletID uint, req SharedWalletRequest) (*SharedWalletView, error) {
+	wallet, member, err := s.authorize(userID, walletID, models.WalletRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		wallet.Name = name
+	}
+	if req.ApprovalThreshold != nil {
+		if *req.ApprovalThreshold < 0 {
+			return nil, ErrInvalidLimit
+		}
+		wallet.ApprovalThreshold = round2(*req.ApprovalThreshold)
+	}
+	if err := s.repo.Update(wallet); err != nil {
+		return nil, err
+	}
+	return s.view(wallet, member)
+}
+
+func (s *sharedService) AddMember(ctx context.Context, userID, walletID uint, req MemberRequest) (*models.WalletMember, error) {
+	if _, _, err := s.authorize(userID, walletID, models.WalletRoleOwner); err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(req.UserID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetMember(walletID, user.ID); err == nil {
+		return nil, ErrMemberExists
+	} else if !errors.Is(err, repositories.ErrWalletMemberNotFound) {
+		return nil, err
+	}
+	members, err := s.repo.ListMembers(walletID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) >= MaxSharedWalletMembers {
+		return nil, ErrTooManyMembers
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.WalletRoleSpender
+	}
+	member := &models.WalletMember{SharedWalletID: walletID, UserID: user.ID, Role: role, AddedBy: userID}
+	if err := applyMemberRequest(member, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddMember(member); err != nil {
+		if _, getErr := s.repo.GetMember(walletID, user.ID); getErr == nil {
+			return nil, ErrMemberExists
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *sharedService) UpdateMember(ctx context.Context, userID, walletID, memberID uint, req MemberRequest) (*models.WalletMember, error) {
+	if _, _, err := s.authorize(userID, walletID, models.WalletRoleOwner); err != nil {
+		return nil, err
+	}
+	member, err := s.member(walletID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	demoting := member.Role == models.WalletRoleOwner && req.Role != "" && req.Role != models.WalletRoleOwner
+	if err := applyMemberRequest(member, req); err != nil {
+		return nil, err
+	}
+	if demoting {
+		if err := s.keepAnOwner(walletID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.UpdateMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *sharedService) RemoveMember(ctx context.Context, userID, walletID, memberID uint) error {
+	if userID == memberID {
+		if _, _, err := s.authorize(userID, walletID); err != nil {
+			return err
+		}
+	} else if _, _, err := s.authorize(userID, walletID, models.WalletRoleOwner); err != nil {
+		return err
+	}
+
+	member, err := s.member(walletID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.WalletRoleOwner {
+		if err := s.keepAnOwner(walletID); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.RemoveMember(walletID, memberID); err != nil {
+		if errors.Is(err, repositories.ErrWalletMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *sharedService) Contribute(ctx context.Context, userID, walletID uint, amount float64) (*models.SharedWallet, error) {
+	wallet, _, err := s.authorize(userID, walletID, models.WalletRoleOwner, models.WalletRoleSpender)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.Status != StatusActive {
+		return nil, ErrWalletLocked
+	}
+	amount = round2(amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if err := s.wallets.ValidateBalance(ctx, userID, amount); err != nil {
+		return nil, err
+	}
+	personal, err := s.wallets.GetWallet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tx := &models.Transaction{
+		Type:          models.TransactionTypeTransfer,
+		SenderID:      userID,
+		Amount:        amount,
+		Currency:      walletCurrency(personal.Currency),
+		Status:        "completed",
+		Description:   fmt.Sprintf("Added to %s", wallet.Name),
+		TransactionID: fmt.Sprintf("SHW-IN-%d-%d-%d", walletID, userID, now.UnixNano()),
+		PaymentType:   "shared_wallet",
+		PaymentMethod: "wallet",
+		ProcessedAt:   now,
+		Metadata: models.NewJSON(map[string]interface{}{
+			"shared_wallet_id": walletID,
+			"direction":        "in",
+		}),
+	}
+	if err := s.convert(ctx, tx, wallet.Currency); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Contribute(walletID, userID, amount, tx); err != nil {
+		if errors.Is(err, repositories.ErrInsufficientPersonalFunds) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, err
+	}
+	s.invalidate(ctx, userID)
+
+	return s.repo.GetByID(walletID)
+}
+
+// Pay sends money out of the shared wallet. Viewers can't pay; spenders are
+// held to their own limits and need an owner's approval above the wallet's
+// threshold; owners pay directly.
+func (s *sharedService) Pay(ctx context.Context, userID, walletID uint, req SharedPaymentRequest) (*models.SharedWalletPayment, error) {
+	wallet, member, err := s.authorize(userID, walletID, models.WalletRoleOwner, models.WalletRoleSpender)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.Status != StatusActive {
+		return nil, ErrWalletLocked
+	}
+	amount := round2(req.Amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if _, err := s.users.GetByID(req.RecipientID); err != nil {
+		return nil, ErrRecipientNotFound
+	}
+
+	if member.Role != models.WalletRoleOwner {
+		if member.PerTransactionLimit > 0 && amount > member.PerTransactionLimit {
+			return nil, ErrMemberLimitExceeded
+		}
+		if member.DailyLimit > 0 {
+			now := time.Now().UTC()
+			startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			spent, err := s.repo.SumMemberSpending(walletID, userID, startOfDay)
+			if err != nil {
+				return nil, err
+			}
+			if spent+amount > member.DailyLimit {
+				return nil, ErrMemberDailyLimit
+			}
+		}
+	}
+
+	payment := &models.SharedWalletPayment{
+		SharedWalletID: walletID,
+		RequestedBy:    userID,
+		RecipientID:    req.RecipientID,
+		Amount:         amount,
+		Description:    strings.TrimSpace(req.Description),
+	}
+
+	if member.Role != models.WalletRoleOwner && wallet.ApprovalThreshold > 0 && amount > wallet.ApprovalThreshold {
+		if wallet.Balance < amount {
+			return nil, ErrInsufficientSharedFunds
+		}
+		payment.Status = models.SharedPaymentPendingApproval
+		if err := s.repo.CreatePayment(payment); err != nil {
+			return nil, err
+		}
+		log.Printf("Shared wallet %d payment %d of %.2f awaits owner approval", walletID, payment.ID, amount)
+		return payment, nil
+	}
+
+	if err := s.execute(ctx, wallet, payment, nil); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *sharedService) ListPayments(ctx context.Context, userID, walletID uint, status string, limit, offset int) ([]models.SharedWalletPayment, int64, error) {
+	if _, _, err := s.authorize(userID, walletID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListPayments(walletID, status, limit, offset)
+}
+
+func (s *sharedService) ApprovePayment(ctx context.Context, userID, walletID, paymentID uint) (*models.SharedWalletPayment, error) {
+	wallet, _, err := s.authorize(userID, walletID, models.WalletRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.payment(walletID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != models.SharedPaymentPendingApproval {
+		return nil, ErrPaymentNotPending
+	}
+	if err := s.execute(ctx, wallet, payment, &userID); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *sharedService) RejectPayment(ctx context.Context, userID, walletID, paymentID uint) (*models.SharedWalletPayment, error) {
+	if _, _, err := s.authorize(userID, walletID, models.WalletRoleOwner); err != nil {
+		return nil, err
+	}
+	payment, err := s.repo.RejectPayment(walletID, paymentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrSharedWalletPaymentNotFound):
+			return nil, ErrPaymentNotFound
+		case errors.Is(err, repositories.ErrSharedWalletPaymentNotOpen):
+			return nil, ErrPaymentNotPending
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *sharedService) execute(ctx context.Context, wallet *models.SharedWallet, payment *models.SharedWalletPayment, reviewerID *uint) error {
+	description := payment.Description
+	if description == "" {
+		description = fmt.Sprintf("Payment from %s", wallet.Name)
+	}
+	now := time.Now()
+	tx := &models.Transaction{
+		Type:          models.TransactionTypeTransfer,
+		SenderID:      payment.RequestedBy,
+		ReceiverID:    payment.RecipientID,
+		Amount:        payment.Amount,
+		Currency:      wallet.Currency,
+		Status:        "completed",
+		Description:   description,
+		TransactionID: fmt.Sprintf("SHW-OUT-%d-%d", wallet.ID, now.UnixNano()),
+		PaymentType:   "shared_wallet",
+		PaymentMethod: "shared_wallet",
+		ProcessedAt:   now,
+	}
+	recipient, err := s.wallets.GetWallet(ctx, payment.RecipientID)
+	if err != nil {
+		return err
+	}
+	if err := s.convert(ctx, tx, walletCurrency(recipient.Currency)); err != nil {
+		return err
+	}
+
+	if err := s.repo.ExecutePayment(payment, tx, reviewerID); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInsufficientSharedFunds):
+			return ErrInsufficientSharedFunds
+		case errors.Is(err, repositories.ErrSharedWalletPaymentNotOpen):
+			return ErrPaymentNotPending
+		}
+		return err
+	}
+	s.invalidate(ctx, payment.RecipientID)
+	return nil
+}
+
+// convert records on tx the conversion of its amount into currency, which
+// the receiving wallet is credited in, when that isn't tx's own currency
+func (s *sharedService) convert(ctx context.Context, tx *models.Transaction, currency string) error {
+	if tx.Currency == currency {
+		return nil
+	}
+	conversion, err := s.fx.Convert(ctx, tx.Amount, tx.Currency, currency)
+	if err != nil {
+		if errors.Is(err, fxrates.ErrInvalidAmount) {
+			return ErrInvalidAmount
+		}
+		return err
+	}
+	conversion.Record(tx)
+	return nil
+}
+
+// walletCurrency is a personal wallet's currency, which older wallets
+// may have left empty
+func walletCurrency(currency string) string {
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// authorize loads the wallet and the caller's membership, requiring one of
+// roles when any are given. Non-members get not found so wallets aren't leaked.
+func (s *sharedService) authorize(userID, walletID uint, roles ...string) (*models.SharedWallet, *models.WalletMember, error) {
+	member, err := s.repo.GetMember(walletID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWalletMemberNotFound) {
+			return nil, nil, ErrSharedWalletNotFound
+		}
+		return nil, nil, err
+	}
+	wallet, err := s.repo.GetByID(walletID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSharedWalletNotFound) {
+			return nil, nil, ErrSharedWalletNotFound
+		}
+		return nil, nil, err
+	}
+
+	if len(roles) == 0 {
+		return wallet, member, nil
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return wallet, member, nil
+		}
+	}
+	return nil, nil, ErrWalletRoleForbidden
+}
+
+func (s *sharedService) view(wallet *models.SharedWallet, member *models.WalletMember) (*SharedWalletView, error) {
+	members, err := s.repo.ListMembers(wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedWalletView{Wallet: wallet, Role: member.Role, Members: members}, nil
+}
+
+func (s *sharedService) member(walletID, userID uint) (*models.WalletMember, error) {
+	member, err := s.repo.GetMember(walletID, userID)
+	if errors.Is(err, repositories.ErrWalletMemberNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	return member, err
+}
+
+func (s *sharedService) payment(walletID, paymentID uint) (*models.SharedWalletPayment, error) {
+	payment, err := s.repo.GetPayment(walletID, paymentID)
+	if errors.Is(err, repositories.ErrSharedWalletPaymentNotFound) {
+		return nil, ErrPaymentNotFound
+	}
+	return payment, err
+}
+
+func (s *sharedService) keepAnOwner(walletID uint) error {
+	owners, err := s.repo.CountOwners(walletID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func (s *sharedService) resolveUser(userID uint, email string) (*models.User, error) {
+	var user *models.User
+	var err error
+	if userID != 0 {
+		user, err = s.users.GetByID(userID)
+	} else if email = strings.TrimSpace(email); email != "" {
+		user, err = s.users.GetByEmail(email)
+	} else {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *sharedService) invalidate(ctx context.Context, userID uint) {
+	if err := s.cache.Delete(ctx, s.cache.GenerateKey("wallet", "user", userID)); err != nil {
+		log.Printf("Failed to invalidate wallet cache for user %d: %v", userID, err)
+	}
+}
+
+func applyMemberRequest(member *models.WalletMember, req MemberRequest) error {
+	if req.Role != "" {
+		if !models.IsValidWalletRole(req.Role) {
+			return ErrInvalidWalletRole
+		}
+		member.Role = req.Role
+	}
+	if req.PerTransactionLimit != nil {
+		if *req.PerTransactionLimit < 0 {
+			return ErrInvalidLimit
+		}
+		member.PerTransactionLimit = round2(*req.PerTransactionLimit)
+	}
+	if req.DailyLimit != nil {
+		if *req.DailyLimit < 0 {
+			return ErrInvalidLimit
+		}
+		member.DailyLimit = round2(*req.DailyLimit)
+	}
+	return nil
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -15,6 +15,36 @@ type TransferRequest struct {
 	Metadata     map[string]interface{}
 }
 
+// BalanceDetails splits a wallet balance into what is stored and what
+// can actually be spent once active holds are taken into account.
+type BalanceDetails struct {
+	WalletID  uint                `json:"wallet_id"`
+	Currency  string              `json:"currency"`
+	Total     float64             `json:"total_balance"`
+	Held      float64             `json:"held_balance"`
+	Overdraft float64             `json:"overdraft_limit"`
+	Available float64             `json:"available_balance"` // Includes any overdraft
+	Holds     []models.WalletHold `json:"holds"`
+}
+
+// HoldRequest describes funds to reserve on a wallet
+type HoldRequest struct {
+	Amount    float64
+	Type      string
+	Reason    string
+	Reference string
+	ExpiresAt *time.Time
+}
+
+// LockRequest describes a lock to place on a wallet. A nil ExpiresAt keeps
+// the lock until it is lifted.
+type LockRequest struct {
+	Scope     string
+	Reason    string
+	Note      string
+	ExpiresAt *time.Time
+}
+
 // WalletConfig holds configuration for wallet operations
 type WalletConfig struct {
 	DefaultCurrency   string
@@ -24,6 +54,17 @@ type WalletConfig struct {
 	Limits            map[string]TransactionLimits
 	WithdrawalFees    map[string]float64
 	ProcessingTimeout time.Duration
+	// ThreeDSThreshold steps card top-ups of at least this much up to 3-D
+	// Secure; zero leaves it to the risk rules
+	ThreeDSThreshold float64
+}
+
+// CardTopUpEvent is the card processor's webhook reporting how a 3-D
+// Secure challenge ended
+type CardTopUpEvent struct {
+	ChargeID      string `json:"charge_id"`
+	Status        string `json:"status"` // succeeded or failed
+	FailureReason string `json:"failure_reason"`
 }
 
 // TransactionLimits defines limits based on user role
@@ -86,8 +127,53 @@ type CacheOperator interface {
 	Delete(key string) error
 }
 
+// ReadConsistency says how fresh a wallet read must be
+type ReadConsistency int
+
+const (
+	// ReadCached may return the cached wallet. Changes made through the
+	// wallet service are written through at once; others show within
+	// cache.WalletTTL.
+	ReadCached ReadConsistency = iota
+	// ReadStrong reads the database, for checks money movements rely on.
+	// The result also refreshes the cache.
+	ReadStrong
+)
+
 type contextKey string
 
 const (
 	UserRoleContextKey contextKey = "userRole"
 )
+
+// SharedWalletRequest creates or updates a shared wallet. Omitted fields
+// are left unchanged on update.
+type SharedWalletRequest struct {
+	Name              string   `json:"name"`
+	Currency          string   `json:"currency"`
+	ApprovalThreshold *float64 `json:"approval_threshold"`
+}
+
+// MemberRequest adds or changes a shared wallet member, identified by user
+// ID or email when added. Limits of 0 mean no limit.
+type MemberRequest struct {
+	UserID              uint     `json:"user_id"`
+	Email               string   `json:"email"`
+	Role                string   `json:"role"`
+	PerTransactionLimit *float64 `json:"per_transaction_limit"`
+	DailyLimit          *float64 `json:"daily_limit"`
+}
+
+// SharedPaymentRequest pays a user from a shared wallet
+type SharedPaymentRequest struct {
+	RecipientID uint    `json:"recipient_id"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+}
+
+// SharedWalletView is a shared wallet as seen by one of its members
+type SharedWalletView struct {
+	Wallet  *models.SharedWallet  `json:"wallet"`
+	Role    string                `json:"role"`
+	Members []models.WalletMember `json:"members"`
+}
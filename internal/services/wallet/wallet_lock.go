@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"strings"
+	"time"
+)
+
+// expiryBatch bounds how many expired locks one run lifts
+const expiryBatch = 100
+
+var lockScopes = map[string]bool{
+	models.WalletLockDebits:  true,
+	models.WalletLockCredits: true,
+	models.WalletLockFull:    true,
+}
+
+var lockReasons = map[string]bool{
+	models.WalletLockReasonFraud:         true,
+	models.WalletLockReasonLegal:         true,
+	models.WalletLockReasonUserRequested: true,
+}
+
+// LockWallet locks the user's wallet, replacing any lock an admin placed
+// before. Owners locking their own wallet get a user_requested lock, and
+// can't replace one support placed.
+func (s *service) LockWallet(ctx context.Context, actorID *uint, userID uint, req LockRequest) (_ *models.WalletLock, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet lock")
+	defer finish(&err)
+
+	if actorID == nil {
+		req.Reason = models.WalletLockReasonUserRequested
+	}
+	if !lockScopes[req.Scope] {
+		return nil, ErrInvalidLockScope
+	}
+	if !lockReasons[req.Reason] {
+		return nil, ErrInvalidLockReason
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidLockExpiry
+	}
+
+	wallet, err := repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID == nil && wallet.Status != StatusActive {
+		return nil, ErrWalletLocked
+	}
+
+	lock := &models.WalletLock{
+		WalletID:  wallet.ID,
+		UserID:    userID,
+		Scope:     req.Scope,
+		Reason:    req.Reason,
+		Note:      strings.TrimSpace(req.Note),
+		LockedBy:  actorID,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.locks.Lock(ctx, lock); err != nil {
+		return nil, err
+	}
+
+	s.refreshWalletCache(ctx, userID)
+	s.notifyLock(ctx, lock, models.WalletLockEventLocked)
+	return lock, nil
+}
+
+// UnlockWallet lifts the lock on the user's wallet. Owners may only lift a
+// lock they placed themselves.
+func (s *service) UnlockWallet(ctx context.Context, actorID *uint, userID uint) (_ *models.WalletLock, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet unlock")
+	defer finish(&err)
+
+	wallet, err := repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID == nil {
+		active, err := s.locks.GetActive(ctx, wallet.ID)
+		if err != nil {
+			return nil, err
+		}
+		if active.Reason != models.WalletLockReasonUserRequested || active.LockedBy != nil {
+			return nil, ErrLockNotOwned
+		}
+	}
+
+	lock, err := s.locks.Release(ctx, wallet.ID, actorID, models.WalletLockEventReleased)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshWalletCache(ctx, userID)
+	s.notifyLock(ctx, lock, models.WalletLockEventReleased)
+	return lock, nil
+}
+
+func (s *service) GetLock(ctx context.Context, userID uint) (_ *models.WalletLock, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet lock lookup")
+	defer finish(&err)
+
+	wallet, err := repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.locks.GetActive(ctx, wallet.ID)
+}
+
+func (s *service) LockEvents(ctx context.Context, userID uint, limit, offset int) (_ []models.WalletLockEvent, _ int64, err error) {
+	ctx, repo, finish := s.operation(ctx, "wallet lock events")
+	defer finish(&err)
+
+	wallet, err := repo.GetByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.locks.ListEvents(ctx, wallet.ID, limit, offset)
+}
+
+func (s *service) EnforceLock(ctx context.Context, wallet *models.Wallet, operation, direction string, amount float64) error {
+	if !wallet.LockBlocks(direction, time.Now()) {
+		return nil
+	}
+
+	event := &models.WalletLockEvent{
+		WalletID:  wallet.ID,
+		Event:     models.WalletLockEventRefused,
+		Operation: operation,
+		Direction: direction,
+		Amount:    math.Round(amount*100) / 100,
+	}
+	if lock, err := s.locks.GetActive(context.WithoutCancel(ctx), wallet.ID); err == nil {
+		event.LockID = &lock.ID
+	}
+	if err := s.locks.RecordEvent(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("Failed to audit refused %s on wallet %d: %v", operation, wallet.ID, err)
+	}
+
+	if wallet.Status == StatusLocked {
+		switch wallet.LockScope {
+		case models.WalletLockDebits:
+			return ErrDebitsLocked
+		case models.WalletLockCredits:
+			return ErrCreditsLocked
+		}
+	}
+	return ErrWalletLocked
+}
+
+// ExpireLocks lifts the locks whose expiry has passed. A lock lifted or
+// replaced in the meantime is skipped.
+func (s *service) ExpireLocks(ctx context.Context) (int, error) {
+	expired, err := s.locks.GetExpired(ctx, time.Now(), expiryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	lifted := 0
+	for i := range expired {
+		if err := ctx.Err(); err != nil {
+			return lifted, err
+		}
+		lock, err := s.locks.Expire(ctx, expired[i].ID, time.Now())
+		if err != nil {
+			if !errors.Is(err, repositories.ErrWalletLockNotFound) {
+				log.Printf("Failed to lift expired lock %d on wallet %d: %v", expired[i].ID, expired[i].WalletID, err)
+			}
+			continue
+		}
+		lifted++
+		s.refreshWalletCache(ctx, lock.UserID)
+		s.notifyLock(ctx, lock, models.WalletLockEventExpired)
+	}
+	return lifted, nil
+}
+
+func (s *service) notifyLock(ctx context.Context, lock *models.WalletLock, event string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendWalletLockNotification(context.WithoutCancel(ctx), lock.UserID, lock, event); err != nil {
+		log.Printf("Failed to notify user %d of wallet lock %d: %v", lock.UserID, lock.ID, err)
+	}
+}
@@ -0,0 +1,9 @@
+package webhook
+
+import "errors"
+
+// Service errors
+var (
+	ErrNoWebhookURL = errors.New("merchant has no webhook URL")
+	ErrUnknownEvent = errors.New("unknown webhook event type")
+)
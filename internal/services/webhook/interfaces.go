@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"context"
+	"orus/internal/models"
+)
+
+// Service queues merchant webhook events and delivers them with retries
+type Service interface {
+	// Enqueue queues an event for the merchant's webhook endpoint. Merchants
+	// without an endpoint are skipped.
+	Enqueue(ctx context.Context, merchantUserID uint, event string, data interface{}) error
+	// EnqueueSandbox queues an event caused by the merchant's sandbox
+	// integration. It is delivered like any other, with livemode false.
+	EnqueueSandbox(ctx context.Context, merchantUserID uint, event string, data interface{}) error
+	// DeliverDue sends the queued events that are due and returns how many
+	// were delivered
+	DeliverDue(ctx context.Context) (int, error)
+
+	// SendTest signs a sample event of the given type and posts it to the
+	// merchant's endpoint straight away. Nothing is queued or retried.
+	SendTest(ctx context.Context, merchantUserID uint, event string) (*SendResult, error)
+	// Replay resends a past delivery's payload now, with the same event ID
+	// and a fresh signature. A failed delivery that replays successfully
+	// is marked delivered.
+	Replay(ctx context.Context, merchantUserID uint, eventID string) (*SendResult, error)
+	// ListDeliveries lists the merchant's deliveries, optionally by event
+	// type and status
+	ListDeliveries(ctx context.Context, merchantUserID uint, event, status string, limit, offset int) ([]models.WebhookDelivery, int64, error)
+	// TestEvents lists the event types SendTest accepts
+	TestEvents() []string
+}
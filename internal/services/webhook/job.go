@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+	"log"
+)
+
+// Job delivers queued merchant webhooks and retries failed ones
+type Job struct {
+	service Service
+}
+
+// NewJob wraps the webhook service as a scheduled job
+func NewJob(s Service) *Job { return &Job{service: s} }
+
+func (j *Job) Name() string { return "webhook-delivery" }
+
+func (j *Job) Run(ctx context.Context) error {
+	delivered, err := j.service.DeliverDue(ctx)
+	if delivered > 0 {
+		log.Printf("Delivered %d webhooks", delivered)
+	}
+	return err
+}
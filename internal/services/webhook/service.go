@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"orus/internal/models"
+	"orus/internal/repositories"
+	"orus/internal/utils"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before giving up
+	MaxAttempts = 8
+	// SignatureHeader carries "t=<unix>,v1=<hex>", an HMAC-SHA256 of
+	// "<t>.<body>" keyed with the merchant's webhook secret
+	SignatureHeader = "X-Orus-Signature"
+
+	deliveryBatchSize = 100
+	// deliveryLease keeps other workers off a delivery while it is sent
+	deliveryLease = 2 * time.Minute
+	// firstRetryDelay doubles after every failed attempt
+	firstRetryDelay = time.Minute
+	// responseBodyLimit caps how much of an endpoint's response test sends
+	// and replays report back
+	responseBodyLimit = 2 << 10
+)
+
+type service struct {
+	repo      repositories.WebhookDeliveryRepository
+	merchants repositories.MerchantRepository
+	client    *http.Client
+}
+
+func NewService(repo repositories.WebhookDeliveryRepository, merchants repositories.MerchantRepository) Service {
+	return &service{
+		repo:      repo,
+		merchants: merchants,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *service) Enqueue(ctx context.Context, merchantUserID uint, event string, data interface{}) error {
+	return s.enqueue(ctx, merchantUserID, event, data, false)
+}
+
+func (s *service) EnqueueSandbox(ctx context.Context, merchantUserID uint, event string, data interface{}) error {
+	return s.enqueue(ctx, merchantUserID, event, data, true)
+}
+
+func (s *service) enqueue(ctx context.Context, merchantUserID uint, event string, data interface{}, sandbox bool) error {
+	merchant, err := s.merchants.GetByUserID(merchantUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get merchant: %w", err)
+	}
+	if merchant.WebhookURL == "" {
+		return nil
+	}
+
+	id, err := utils.GenerateUniqueID(16)
+	if err != nil {
+		return fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	now := time.Now()
+	envelope := Envelope{ID: "evt_" + id, Type: event, CreatedAt: now, Livemode: !sandbox, Data: data}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	return s.repo.Create(&models.WebhookDelivery{
+		EventID:        envelope.ID,
+		MerchantUserID: merchantUserID,
+		Event:          event,
+		Payload:        string(payload),
+		Status:         models.WebhookStatusPending,
+		NextAttemptAt:  now,
+		Sandbox:        sandbox,
+	})
+}
+
+func (s *service) DeliverDue(ctx context.Context) (int, error) {
+	due, err := s.repo.GetDue(time.Now(), deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+		delivery := &due[i]
+		claimed, err := s.repo.Claim(delivery, time.Now().Add(deliveryLease))
+		if err != nil {
+			log.Printf("Failed to claim webhook %s: %v", delivery.EventID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if s.deliver(ctx, delivery) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver makes one attempt and records the outcome, scheduling a retry
+// on failure
+func (s *service) deliver(ctx context.Context, delivery *models.WebhookDelivery) bool {
+	delivery.Attempts++
+	statusCode, err := s.send(ctx, delivery)
+	delivery.LastStatusCode = statusCode
+
+	now := time.Now()
+	ok := err == nil
+	switch {
+	case ok:
+		delivery.Status = models.WebhookStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = models.WebhookStatusFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.NextAttemptAt = now.Add(firstRetryDelay << (delivery.Attempts - 1))
+		delivery.LastError = err.Error()
+	}
+
+	if err := s.repo.Update(delivery); err != nil {
+		log.Printf("Failed to record webhook %s attempt: %v", delivery.EventID, err)
+	}
+	return ok
+}
+
+func (s *service) send(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	// The endpoint is looked up on every attempt so a corrected URL is
+	// picked up by the retries
+	merchant, err := s.merchants.GetByUserID(delivery.MerchantUserID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get merchant: %w", err)
+	}
+	if merchant.WebhookURL == "" {
+		return 0, ErrNoWebhookURL
+	}
+
+	statusCode, _, err := s.post(ctx, merchant, delivery.Event, delivery.EventID, []byte(delivery.Payload), nil)
+	return statusCode, err
+}
+
+// post signs and sends one webhook body and returns the endpoint's status
+// code and the start of its response body. Extra headers are added as is.
+func (s *service) post(ctx context.Context, merchant *models.Merchant, event, eventID string, payload []byte, headers map[string]string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, merchant.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Orus-Event", event)
+	req.Header.Set("X-Orus-Event-ID", eventID)
+	req.Header.Set(SignatureHeader, Sign(merchant.WebhookSecret, time.Now(), payload))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// Sign builds the signature header value for a webhook body
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
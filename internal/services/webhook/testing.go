@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"orus/internal/models"
+	"orus/internal/utils"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TestHeader is set on test sends and replays so endpoints can tell them
+// from first deliveries
+const TestHeader = "X-Orus-Test"
+
+// sampleData builds the data of a sample event. The shapes match what the
+// live event carries.
+var sampleData = map[string]func(merchant *models.Merchant, now time.Time) interface{}{
+	EventCheckoutSessionCompleted: func(merchant *models.Merchant, now time.Time) interface{} {
+		return &models.CheckoutSession{
+			SessionID:       "cs_test_sample",
+			Source:          "api",
+			MerchantID:      merchant.UserID,
+			UserID:          1,
+			Amount:          25,
+			Currency:        "USD",
+			Description:     "Sample order",
+			ClientReference: "order_1001",
+			Status:          models.CheckoutSessionStatusCompleted,
+			ExpiresAt:       now.Add(time.Hour),
+			CompletedAt:     &now,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+	},
+	EventTransactionExpired: func(merchant *models.Merchant, now time.Time) interface{} {
+		return &models.Transaction{
+			Type:          models.TransactionTypeMerchantDirect,
+			TransactionID: "MTXN-sample",
+			SenderID:      1,
+			ReceiverID:    merchant.UserID,
+			MerchantID:    &merchant.ID,
+			MerchantName:  merchant.BusinessName,
+			Amount:        25,
+			Currency:      "USD",
+			Status:        models.TransactionStatusExpired,
+			Description:   "Sample payment",
+		}
+	},
+	EventRefundRequestCreated: func(merchant *models.Merchant, now time.Time) interface{} {
+		return sampleRefundRequest(merchant, now, models.RefundRequestPending)
+	},
+	EventRefundRequestRefunded: func(merchant *models.Merchant, now time.Time) interface{} {
+		req := sampleRefundRequest(merchant, now, models.RefundRequestRefunded)
+		refundID := uint(2)
+		req.AutoApproved = true
+		req.DecidedAt = &now
+		req.RefundTransactionID = &refundID
+		req.RefundedAt = &now
+		return req
+	},
+	EventRefundRequestRejected: func(merchant *models.Merchant, now time.Time) interface{} {
+		req := sampleRefundRequest(merchant, now, models.RefundRequestRejected)
+		req.DecidedBy = &merchant.UserID
+		req.DecisionNote = "Item was used"
+		req.DecidedAt = &now
+		return req
+	},
+	EventRefundRequestCanceled: func(merchant *models.Merchant, now time.Time) interface{} {
+		req := sampleRefundRequest(merchant, now, models.RefundRequestCanceled)
+		req.DecidedAt = &now
+		return req
+	},
+	"subscription.payment_succeeded": func(merchant *models.Merchant, now time.Time) interface{} {
+		return sampleSubscription(merchant, now, models.SubscriptionStatusActive)
+	},
+	"subscription.payment_failed": func(merchant *models.Merchant, now time.Time) interface{} {
+		subscription := sampleSubscription(merchant, now, models.SubscriptionStatusPastDue)
+		subscription.FailedAttempts = 1
+		return subscription
+	},
+	"subscription.canceled": func(merchant *models.Merchant, now time.Time) interface{} {
+		subscription := sampleSubscription(merchant, now, models.SubscriptionStatusCanceled)
+		subscription.NextBillingAt = nil
+		subscription.CanceledAt = &now
+		return subscription
+	},
+	EventChargeSucceeded: func(merchant *models.Merchant, now time.Time) interface{} {
+		return sampleCharge(merchant, now, models.SandboxStatusSucceeded, "")
+	},
+	EventChargeFailed: func(merchant *models.Merchant, now time.Time) interface{} {
+		return sampleCharge(merchant, now, models.SandboxStatusFailed, "card_declined")
+	},
+	EventChargePending: func(merchant *models.Merchant, now time.Time) interface{} {
+		return sampleCharge(merchant, now, models.SandboxStatusPending, "")
+	},
+	EventChargeRefunded: func(merchant *models.Merchant, now time.Time) interface{} {
+		charge := sampleCharge(merchant, now, models.SandboxStatusRefunded, "")
+		charge.RefundedAmount = charge.Amount
+		return charge
+	},
+	EventRefundFailed: func(merchant *models.Merchant, now time.Time) interface{} {
+		return &models.SandboxRefund{
+			Reference:   "re_test_sample",
+			MerchantID:  merchant.ID,
+			Charge:      "ch_test_sample",
+			Amount:      25,
+			Status:      models.SandboxStatusFailed,
+			FailureCode: "refund_failed",
+			CreatedAt:   now,
+		}
+	},
+}
+
+func sampleRefundRequest(merchant *models.Merchant, now time.Time, status string) *models.RefundRequest {
+	return &models.RefundRequest{
+		ID:             1,
+		TransactionID:  1,
+		CustomerID:     1,
+		MerchantUserID: merchant.UserID,
+		MerchantName:   merchant.BusinessName,
+		Amount:         25,
+		Currency:       "USD",
+		Reason:         "Wrong size",
+		Status:         status,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+func sampleSubscription(merchant *models.Merchant, now time.Time, status string) *models.Subscription {
+	next := now.AddDate(0, 1, 0)
+	return &models.Subscription{
+		ID:                 1,
+		PlanID:             1,
+		MerchantID:         merchant.UserID,
+		CustomerID:         1,
+		Amount:             9.99,
+		Currency:           "USD",
+		Interval:           "month",
+		IntervalCount:      1,
+		Status:             status,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   next,
+		NextBillingAt:      &next,
+		LastChargeAt:       &now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+func sampleCharge(merchant *models.Merchant, now time.Time, status, failureCode string) *models.SandboxCharge {
+	return &models.SandboxCharge{
+		Reference:   "ch_test_sample",
+		MerchantID:  merchant.ID,
+		Amount:      25,
+		Currency:    "USD",
+		Source:      models.SandboxSourceCard,
+		CardLast4:   "4242",
+		Status:      status,
+		FailureCode: failureCode,
+		Description: "Sample charge",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func (s *service) TestEvents() []string {
+	events := make([]string, 0, len(sampleData))
+	for event := range sampleData {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+func (s *service) SendTest(ctx context.Context, merchantUserID uint, event string) (*SendResult, error) {
+	sample, ok := sampleData[event]
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	merchant, err := s.endpoint(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := utils.GenerateUniqueID(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	now := time.Now()
+	envelope := Envelope{ID: "evt_test_" + id, Type: event, CreatedAt: now, Data: sample(merchant, now)}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	return s.sendNow(ctx, merchant, event, envelope.ID, payload, "test"), nil
+}
+
+func (s *service) Replay(ctx context.Context, merchantUserID uint, eventID string) (*SendResult, error) {
+	delivery, err := s.repo.GetByEventID(merchantUserID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	merchant, err := s.endpoint(merchantUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.sendNow(ctx, merchant, delivery.Event, delivery.EventID, []byte(delivery.Payload), "replay")
+	if result.Delivered && delivery.Status == models.WebhookStatusFailed {
+		now := time.Now()
+		delivery.Status = models.WebhookStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastStatusCode = result.StatusCode
+		delivery.LastError = ""
+		if err := s.repo.Update(delivery); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *service) ListDeliveries(ctx context.Context, merchantUserID uint, event, status string, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	return s.repo.ListByMerchant(merchantUserID, event, status, limit, offset)
+}
+
+// endpoint returns the merchant if it has a webhook URL to send to
+func (s *service) endpoint(merchantUserID uint) (*models.Merchant, error) {
+	merchant, err := s.merchants.GetByUserID(merchantUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoWebhookURL
+		}
+		return nil, fmt.Errorf("failed to get merchant: %w", err)
+	}
+	if merchant.WebhookURL == "" {
+		return nil, ErrNoWebhookURL
+	}
+	return merchant, nil
+}
+
+// sendNow posts a webhook once and reports the outcome. Endpoint failures
+// are part of the result rather than errors.
+func (s *service) sendNow(ctx context.Context, merchant *models.Merchant, event, eventID string, payload []byte, kind string) *SendResult {
+	start := time.Now()
+	statusCode, body, err := s.post(ctx, merchant, event, eventID, payload, map[string]string{TestHeader: kind})
+
+	result := &SendResult{
+		EventID:      eventID,
+		Event:        event,
+		URL:          merchant.WebhookURL,
+		Delivered:    err == nil,
+		StatusCode:   statusCode,
+		ResponseBody: body,
+		DurationMS:   time.Since(start).Milliseconds(),
+		Payload:      string(payload),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
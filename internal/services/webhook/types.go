@@ -0,0 +1,50 @@
+package webhook
+
+import "time"
+
+// Event types
+const (
+	EventCheckoutSessionCompleted = "checkout.session.completed"
+	EventTransactionExpired       = "transaction.expired" // A payment to the merchant was left pending and expired
+
+	// A customer's refund request, through to its refund or rejection
+	EventRefundRequestCreated  = "refund_request.created"
+	EventRefundRequestRefunded = "refund_request.refunded"
+	EventRefundRequestRejected = "refund_request.rejected"
+	EventRefundRequestCanceled = "refund_request.canceled"
+
+	// Sandbox events, only sent for activity on a sandbox API key
+	EventChargeSucceeded = "charge.succeeded"
+	EventChargeFailed    = "charge.failed"
+	EventChargePending   = "charge.pending"
+	EventChargeRefunded  = "charge.refunded"
+	EventRefundFailed    = "refund.failed"
+)
+
+// Envelope is the body of every webhook delivery
+type Envelope struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Livemode  bool        `json:"livemode"` // False for sandbox events
+	Data      interface{} `json:"data"`
+}
+
+// TestRequest picks the event a test webhook sends
+type TestRequest struct {
+	Event string `json:"event"`
+}
+
+// SendResult reports how the merchant's endpoint answered a test send or
+// a replay
+type SendResult struct {
+	EventID      string `json:"event_id"`
+	Event        string `json:"event"`
+	URL          string `json:"url"`
+	Delivered    bool   `json:"delivered"`
+	StatusCode   int    `json:"status_code,omitempty"`
+	ResponseBody string `json:"response_body,omitempty"` // First 2KB
+	Error        string `json:"error,omitempty"`
+	DurationMS   int64  `json:"duration_ms"`
+	Payload      string `json:"payload"`
+}
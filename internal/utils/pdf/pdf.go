@@ -0,0 +1,177 @@
+// Package pdf renders plain text documents such as invoices and receipts,
+// and printable QR code posters, as minimal PDF files.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	linesPerPage = 56
+	// LineWidth is how many characters fit on a line
+	LineWidth = 78
+)
+
+// Render lays lines out as monospaced text on US Letter pages
+func Render(lines []string) []byte {
+	var pages [][]string
+	for len(lines) > 0 {
+		n := linesPerPage
+		if len(lines) < n {
+			n = len(lines)
+		}
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+
+	contents := make([]string, len(pages))
+	for i, page := range pages {
+		var content bytes.Buffer
+		content.WriteString("BT\n/F1 9 Tf\n11 TL\n50 750 Td\n")
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDF(line))
+		}
+		content.WriteString("ET")
+		contents[i] = content.String()
+	}
+	return document(contents)
+}
+
+// Poster lays out a single US Letter page with the heading lines in large
+// type, a QR code drawn from its modules (true is dark) and the caption
+// lines below it, all centred.
+func Poster(heading []string, modules [][]bool, caption []string) []byte {
+	const (
+		pageWidth = 612.0
+		qrSize    = 360.0
+		qrTop     = 620.0
+	)
+	var content bytes.Buffer
+	centred := func(lines []string, size, top float64) {
+		for i, line := range lines {
+			x := (pageWidth - float64(len(line))*size*0.6) / 2 // Courier glyphs are 0.6 em wide
+			fmt.Fprintf(&content, "BT\n/F1 %g Tf\n%.2f %.2f Td\n(%s) Tj\nET\n",
+				size, x, top-float64(i)*size*1.3, escapePDF(line))
+		}
+	}
+
+	centred(heading, 24, 700)
+	if n := len(modules); n > 0 {
+		module := qrSize / float64(n)
+		left := (pageWidth - qrSize) / 2
+		for row, cells := range modules {
+			for col, dark := range cells {
+				if dark {
+					fmt.Fprintf(&content, "%.2f %.2f %.2f %.2f re\n",
+						left+float64(col)*module, qrTop-float64(row+1)*module, module, module)
+				}
+			}
+		}
+		content.WriteString("f\n")
+	}
+	centred(caption, 14, qrTop-qrSize-40)
+	return document([]string{content.String()})
+}
+
+// document writes pages, given as content streams, into a PDF.
+// It writes the PDF structure directly so no rendering dependency is needed.
+func document(pages []string) []byte {
+	// Object layout: 1 catalog, 2 page tree, 3 font, then a page and content stream per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+i*2)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range pages {
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			5+i*2))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// FormatNumber prints a number with up to two decimals and no trailing zeros
+func FormatNumber(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Wrap breaks text into lines of at most width characters on word boundaries
+func Wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// winAnsiExtras are the characters outside Latin-1 that WinAnsiEncoding
+// has codes for and translated documents use
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80,
+	'…': 0x85,
+	'‘': 0x91,
+	'’': 0x92,
+	'“': 0x93,
+	'”': 0x94,
+	'–': 0x96,
+	'—': 0x97,
+}
+
+// escapePDF escapes string delimiters, writes accented letters and other
+// non-ASCII characters as their WinAnsiEncoding codes, and drops those
+// Courier can't encode
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r <= 126:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case winAnsiExtras[r] != 0:
+			fmt.Fprintf(&b, "\\%03o", winAnsiExtras[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"orus/internal/utils/response"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 // Respond sends a JSON response with the specified status code.
 func Respond(c *fiber.Ctx, status int, data interface{}) error {
@@ -12,27 +16,27 @@ func Success(c *fiber.Ctx, data interface{}) error {
 	return Respond(c, fiber.StatusOK, data)
 }
 
-// BadRequest sends a JSON error response with status 400.
+// BadRequest sends the error envelope with status 400.
 func BadRequest(c *fiber.Ctx, message string) error {
-	return Respond(c, fiber.StatusBadRequest, fiber.Map{"error": message})
+	return response.Error(c, fiber.StatusBadRequest, message)
 }
 
-// Unauthorized sends a JSON error response with status 401.
+// Unauthorized sends the error envelope with status 401.
 func Unauthorized(c *fiber.Ctx, message string) error {
-	return Respond(c, fiber.StatusUnauthorized, fiber.Map{"error": message})
+	return response.Error(c, fiber.StatusUnauthorized, message)
 }
 
-// Forbidden sends a JSON error response with status 403.
+// Forbidden sends the error envelope with status 403.
 func Forbidden(c *fiber.Ctx, message string) error {
-	return Respond(c, fiber.StatusForbidden, fiber.Map{"error": message})
+	return response.Error(c, fiber.StatusForbidden, message)
 }
 
-// NotFound sends a JSON error response with status 404.
+// NotFound sends the error envelope with status 404.
 func NotFound(c *fiber.Ctx, message string) error {
-	return Respond(c, fiber.StatusNotFound, fiber.Map{"error": message})
+	return response.Error(c, fiber.StatusNotFound, message)
 }
 
-// InternalError sends a JSON error response with status 500.
+// InternalError sends the error envelope with status 500.
 func InternalError(c *fiber.Ctx, message string) error {
-	return Respond(c, fiber.StatusInternalServerError, fiber.Map{"error": message})
+	return response.Error(c, fiber.StatusInternalServerError, message)
 }
@@ -1,9 +1,20 @@
 package response
 
 import (
+	apperrors "orus/internal/errors"
+
 	"github.com/gofiber/fiber/v2"
 )
 
+// ErrorBody is the envelope every failed request returns under "error"
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Fields lists the invalid fields of a request body that failed
+	// validation
+	Fields []apperrors.FieldError `json:"fields,omitempty"`
+}
+
 func Success(c *fiber.Ctx, message string, data interface{}) error {
 	return c.JSON(fiber.Map{
 		"message": message,
@@ -11,12 +22,36 @@ func Success(c *fiber.Ctx, message string, data interface{}) error {
 	})
 }
 
-func Error(c *fiber.Ctx, status int, message string) error {
-	return c.Status(status).JSON(fiber.Map{
-		"error": message,
+// Fail sends the error envelope. The message is replaced by the code's
+// translation when the client prefers another language and one exists.
+func Fail(c *fiber.Ctx, status int, code, message string) error {
+	return fail(c, status, ErrorBody{Code: code, Message: message})
+}
+
+// Invalid sends the error envelope listing the invalid fields of the
+// request body
+func Invalid(c *fiber.Ctx, err *apperrors.ValidationError) error {
+	return fail(c, fiber.StatusBadRequest, ErrorBody{
+		Code:    apperrors.CodeValidationFailed,
+		Message: err.Error(),
+		Fields:  err.Fields,
 	})
 }
 
+func fail(c *fiber.Ctx, status int, body ErrorBody) error {
+	lang := apperrors.Language(c.Get(fiber.HeaderAcceptLanguage))
+	if translated, ok := apperrors.Message(body.Code, lang); ok {
+		body.Message = translated
+		c.Set(fiber.HeaderContentLanguage, lang)
+	}
+	return c.Status(status).JSON(fiber.Map{"error": body})
+}
+
+// Error sends the error envelope with the generic code for status
+func Error(c *fiber.Ctx, status int, message string) error {
+	return Fail(c, status, apperrors.CodeForStatus(status), message)
+}
+
 func BadRequest(c *fiber.Ctx, message string) error {
 	return Error(c, fiber.StatusBadRequest, message)
 }
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores files under a base directory on local disk
+type Local struct {
+	baseDir string
+}
+
+// NewLocal creates a local store rooted at baseDir
+func NewLocal(baseDir string) *Local {
+	return &Local{baseDir: baseDir}
+}
+
+func (s *Local) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	if err := os.WriteFile(target, content, 0o640); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func (s *Local) Get(ctx context.Context, key string) ([]byte, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(target)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, nil
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// path keeps keys inside the base directory
+func (s *Local) path(key string) (string, error) {
+	target := filepath.Join(s.baseDir, filepath.Clean("/"+key))
+	if !strings.HasPrefix(target, filepath.Clean(s.baseDir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return target, nil
+}
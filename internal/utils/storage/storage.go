@@ -0,0 +1,46 @@
+// Package storage keeps uploaded files on local disk or in any
+// S3-compatible object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when no file is stored under a key
+var ErrNotFound = errors.New("file not found")
+
+// Storage stores files by key
+type Storage interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures a storage backend
+type Config struct {
+	Driver string // "local" or "s3"
+
+	// Local
+	LocalDir string
+
+	// S3-compatible; Endpoint is e.g. https://s3.eu-west-1.amazonaws.com or a MinIO URL
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// New returns the storage backend configured by cfg
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocal(cfg.LocalDir), nil
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
+	}
+}
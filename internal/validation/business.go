@@ -3,8 +3,6 @@ package validation
 import (
 	"orus/internal/models"
 	"orus/internal/services/transaction"
-	"strconv"
-	"time"
 )
 
 // Transaction validates a transaction request
@@ -49,19 +47,6 @@ func (v *Validator) Wallet(op *models.WalletOperation) {
 	v.Range("amount", op.Amount, 0.01, 1000000)
 }
 
-// Payment validates payment requests
-func (v *Validator) Payment(req *models.PaymentRequest) {
-	if req.Amount <= 0 {
-		v.AddError("amount", "must be greater than 0")
-	}
-	if req.RecipientID == 0 {
-		v.AddError("recipient_id", "is required")
-	}
-	if req.PaymentType == "" {
-		v.AddError("payment_type", "is required")
-	}
-}
-
 // UserRegistration validates user registration data
 func (v *Validator) UserRegistration(input *models.CreateUserInput) {
 	if !emailRegex.MatchString(input.Email) {
@@ -78,26 +63,6 @@ func (v *Validator) UserRegistration(input *models.CreateUserInput) {
 	}
 }
 
-// CardValidation validates credit card data
-func (v *Validator) CardValidation(card *models.CreditCard) {
-	if !isValidCardNumber(card.CardNumber) {
-		v.AddError("card_number", "invalid number")
-	}
-	if !isValidExpiryDate(card.ExpiryMonth, card.ExpiryYear) {
-		v.AddError("expiry_date", "invalid date")
-	}
-}
-
-// QRPayment validates QR payment requests
-func (v *Validator) QRPayment(input *models.QRPaymentRequest) {
-	v.Required("qr_code", input.QRCode)
-	v.Range("amount", input.Amount, 0.01, 1000000)
-
-	if input.Amount <= 0 {
-		v.AddError("amount", "must be greater than 0")
-	}
-}
-
 // Transfer validates money transfer requests
 func (v *Validator) Transfer(req *transaction.TransferRequest) {
 	if req.ReceiverID == 0 {
@@ -121,40 +86,3 @@ func isValidRole(role string) bool {
 	}
 	return validRoles[role]
 }
-
-func isValidCardNumber(number string) bool {
-	sum := 0
-	isSecond := false
-
-	for i := len(number) - 1; i >= 0; i-- {
-		d := int(number[i] - '0')
-
-		if isSecond {
-			d *= 2
-			if d > 9 {
-				d -= 9
-			}
-		}
-
-		sum += d
-		isSecond = !isSecond
-	}
-
-	return sum%10 == 0
-}
-
-func isValidExpiryDate(month, year string) bool {
-	m, err := strconv.Atoi(month)
-	if err != nil || m < 1 || m > 12 {
-		return false
-	}
-
-	y, err := strconv.Atoi(year)
-	if err != nil {
-		return false
-	}
-
-	now := time.Now()
-	expiry := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
-	return expiry.After(now)
-}
@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// typeAliases maps legacy transaction type spellings onto their schema
+var typeAliases = map[string]string{
+	"topup":         "top_up",
+	"merchant_scan": "qr_payment",
+}
+
+// registry holds every schema version keyed by normalized transaction type
+var registry = mustLoadSchemas()
+
+func mustLoadSchemas() map[string]map[int]*Schema {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		panic(fmt.Sprintf("metadata: failed to read schemas: %v", err))
+	}
+
+	schemas := make(map[string]map[int]*Schema)
+	for _, entry := range entries {
+		raw, err := schemaFiles.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("metadata: failed to read %s: %v", entry.Name(), err))
+		}
+
+		var schema Schema
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			panic(fmt.Sprintf("metadata: invalid schema %s: %v", entry.Name(), err))
+		}
+		if schema.TransactionType == "" || schema.Version < 1 {
+			panic(fmt.Sprintf("metadata: schema %s must declare x-transaction-type and x-version", entry.Name()))
+		}
+		if err := schema.compile(); err != nil {
+			panic(fmt.Sprintf("metadata: schema %s: %v", entry.Name(), err))
+		}
+
+		if schemas[schema.TransactionType] == nil {
+			schemas[schema.TransactionType] = make(map[int]*Schema)
+		}
+		schemas[schema.TransactionType][schema.Version] = &schema
+	}
+	return schemas
+}
+
+// NormalizeType maps a transaction type onto the key its schema is registered under
+func NormalizeType(txType string) string {
+	key := strings.ToLower(strings.TrimSpace(txType))
+	if alias, ok := typeAliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+// Lookup returns a specific schema version for a transaction type
+func Lookup(txType string, version int) (*Schema, bool) {
+	versions, ok := registry[NormalizeType(txType)]
+	if !ok {
+		return nil, false
+	}
+	schema, ok := versions[version]
+	return schema, ok
+}
+
+// Current returns the latest schema version for a transaction type
+func Current(txType string) (*Schema, bool) {
+	versions, ok := registry[NormalizeType(txType)]
+	if !ok {
+		return nil, false
+	}
+	latest := 0
+	for version := range versions {
+		if version > latest {
+			latest = version
+		}
+	}
+	return versions[latest], true
+}
+
+// All returns every registered schema ordered by type and version
+func All() []*Schema {
+	var all []*Schema
+	for _, versions := range registry {
+		for _, schema := range versions {
+			all = append(all, schema)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].TransactionType != all[j].TransactionType {
+			return all[i].TransactionType < all[j].TransactionType
+		}
+		return all[i].Version < all[j].Version
+	})
+	return all
+}
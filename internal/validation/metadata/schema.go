@@ -0,0 +1,173 @@
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Schema is the subset of JSON Schema used for transaction metadata:
+// type, properties, required, additionalProperties, enum, pattern and minimum.
+type Schema struct {
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	TransactionType      string             `json:"x-transaction-type,omitempty"`
+	Version              int                `json:"x-version,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// compile prepares patterns so validation doesn't recompile them per write
+func (s *Schema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for name, prop := range s.Properties {
+		if err := prop.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// allowsAdditional reports whether properties outside the schema are permitted
+func (s *Schema) allowsAdditional() bool {
+	return s.AdditionalProperties == nil || *s.AdditionalProperties
+}
+
+// validate checks value against the schema, collecting field errors keyed by path.
+// Unknown object fields are reported separately so callers can reject or flag them.
+func (s *Schema) validate(path string, value interface{}, errs map[string]string, unknown *[]string) {
+	if !s.matchesType(value) {
+		errs[fieldName(path)] = fmt.Sprintf("must be of type %s", s.Type)
+		return
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		errs[fieldName(path)] = fmt.Sprintf("must be one of %v", s.Enum)
+	}
+
+	if str, ok := value.(string); ok && s.pattern != nil && !s.pattern.MatchString(str) {
+		errs[fieldName(path)] = fmt.Sprintf("must match %s", s.Pattern)
+	}
+
+	if num, ok := toFloat(value); ok && s.Minimum != nil && num < *s.Minimum {
+		errs[fieldName(path)] = fmt.Sprintf("must be at least %v", *s.Minimum)
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, name := range s.Required {
+		if _, present := obj[name]; !present {
+			errs[join(path, name)] = "is required"
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if isReserved(key) && path == "" {
+			continue
+		}
+		prop, known := s.Properties[key]
+		if !known {
+			if !s.allowsAdditional() {
+				*unknown = append(*unknown, join(path, key))
+			}
+			continue
+		}
+		prop.validate(join(path, key), obj[key], errs, unknown)
+	}
+}
+
+func (s *Schema) matchesType(value interface{}) bool {
+	switch s.Type {
+	case "":
+		return true
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		num, ok := toFloat(value)
+		return ok && num == float64(int64(num))
+	default:
+		return false
+	}
+}
+
+func (s *Schema) inEnum(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// toFloat accepts both decoded JSON numbers and the Go numeric types services write directly
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func fieldName(path string) string {
+	if path == "" {
+		return "metadata"
+	}
+	return path
+}
@@ -0,0 +1,141 @@
+package metadata
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Reserved keys stamped onto validated metadata
+const (
+	SchemaVersionKey = "_schema_version"
+	UnknownFieldsKey = "_unknown_fields"
+)
+
+// Mode controls how unknown fields and unregistered types are handled
+type Mode string
+
+const (
+	// ModeFlag keeps unknown fields but records them under UnknownFieldsKey
+	ModeFlag Mode = "flag"
+	// ModeStrict rejects unknown fields and transaction types without a schema
+	ModeStrict Mode = "strict"
+)
+
+var mode atomic.Value
+
+func init() {
+	SetMode(ModeFlag)
+}
+
+// SetMode switches between flagging and rejecting unknown fields
+func SetMode(m Mode) {
+	if m != ModeStrict {
+		m = ModeFlag
+	}
+	mode.Store(m)
+}
+
+// CurrentMode returns the active validation mode
+func CurrentMode() Mode {
+	return mode.Load().(Mode)
+}
+
+// ValidationError describes why metadata failed its schema
+type ValidationError struct {
+	TransactionType string
+	Version         int
+	Fields          map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field, msg := range e.Fields {
+		fields = append(fields, fmt.Sprintf("%s %s", field, msg))
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf("invalid %s metadata (v%d): %s", e.TransactionType, e.Version, strings.Join(fields, "; "))
+}
+
+// Validate checks metadata against the schema for the transaction type and
+// returns a copy stamped with the schema version. Metadata that already carries
+// a version is checked against that version so older records stay valid.
+func Validate(txType string, data map[string]interface{}) (map[string]interface{}, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	schema, err := schemaFor(txType, data)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return data, nil
+	}
+
+	errs := make(map[string]string)
+	var unknown []string
+	schema.validate("", data, errs, &unknown)
+
+	if len(unknown) > 0 && CurrentMode() == ModeStrict {
+		for _, field := range unknown {
+			errs[field] = "is not allowed"
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &ValidationError{
+			TransactionType: schema.TransactionType,
+			Version:         schema.Version,
+			Fields:          errs,
+		}
+	}
+
+	result := make(map[string]interface{}, len(data)+2)
+	for key, value := range data {
+		result[key] = value
+	}
+	result[SchemaVersionKey] = schema.Version
+	if len(unknown) > 0 {
+		result[UnknownFieldsKey] = unknown
+	} else {
+		delete(result, UnknownFieldsKey)
+	}
+	return result, nil
+}
+
+func schemaFor(txType string, data map[string]interface{}) (*Schema, error) {
+	if raw, ok := data[SchemaVersionKey]; ok {
+		version, ok := toFloat(raw)
+		if !ok {
+			return nil, &ValidationError{
+				TransactionType: NormalizeType(txType),
+				Fields:          map[string]string{SchemaVersionKey: "must be an integer"},
+			}
+		}
+		schema, found := Lookup(txType, int(version))
+		if !found {
+			return nil, &ValidationError{
+				TransactionType: NormalizeType(txType),
+				Version:         int(version),
+				Fields:          map[string]string{SchemaVersionKey: "unknown schema version"},
+			}
+		}
+		return schema, nil
+	}
+
+	schema, found := Current(txType)
+	if found {
+		return schema, nil
+	}
+	if CurrentMode() == ModeStrict {
+		return nil, fmt.Errorf("no metadata schema registered for transaction type %q", txType)
+	}
+	log.Printf("No metadata schema registered for transaction type %q, storing metadata unvalidated", txType)
+	return nil, nil
+}
+
+func isReserved(key string) bool {
+	return key == SchemaVersionKey || key == UnknownFieldsKey
+}
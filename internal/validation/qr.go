@@ -1,7 +1,6 @@
 package validation
 
 import (
-	"context"
 	"orus/internal/domain/qr"
 	"orus/internal/errors"
 	"orus/internal/models"
@@ -52,13 +51,3 @@ func ValidateQRPayment(qr *models.QRCode, amount float64) error {
 	}
 	return nil
 }
-
-func CheckQRLimits(ctx context.Context, qr *models.QRCode, amount float64) error {
-	if qr.DailyLimit != nil && amount > *qr.DailyLimit {
-		return &errors.DomainError{
-			Code:    "LIMIT_EXCEEDED",
-			Message: "daily limit exceeded",
-		}
-	}
-	return nil
-}
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"errors"
+	apperrors "orus/internal/errors"
+	"orus/internal/i18n"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// structs checks request bodies against their validate tags, naming fields
+// by their JSON names
+var structs = newStructValidator()
+
+func newStructValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// Struct checks a request body against its validate tags. It returns a
+// *apperrors.ValidationError listing every invalid field, with messages in
+// lang, or nil.
+func Struct(body interface{}, lang string) error {
+	err := structs.Struct(body)
+	if err == nil {
+		return nil
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	fields := make([]apperrors.FieldError, len(invalid))
+	for i, fe := range invalid {
+		fields[i] = apperrors.FieldError{Field: fieldPath(fe), Message: fieldMessage(fe, lang)}
+	}
+	return &apperrors.ValidationError{Fields: fields}
+}
+
+// fieldPath drops the struct's own name from a field's namespace, leaving
+// its JSON path
+func fieldPath(fe validator.FieldError) string {
+	path := fe.Namespace()
+	if i := strings.Index(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+func fieldMessage(fe validator.FieldError, lang string) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_without", "required_with", "required_if":
+		return i18n.T(lang, "validation.required")
+	case "gt":
+		return bound(fe, lang, "gt", param)
+	case "gte", "min":
+		return bound(fe, lang, "gte", param)
+	case "lt":
+		return bound(fe, lang, "lt", param)
+	case "lte", "max":
+		return bound(fe, lang, "lte", param)
+	case "len":
+		return i18n.T(lang, "validation.len", param)
+	case "oneof":
+		return i18n.T(lang, "validation.oneof", strings.ReplaceAll(param, " ", ", "))
+	case "email":
+		return i18n.T(lang, "validation.email")
+	case "url", "http_url":
+		return i18n.T(lang, "validation.url")
+	case "e164":
+		return i18n.T(lang, "validation.e164")
+	case "iso3166_1_alpha2":
+		return i18n.T(lang, "validation.country")
+	case "iso4217":
+		return i18n.T(lang, "validation.currency")
+	default:
+		return i18n.T(lang, "validation.invalid")
+	}
+}
+
+// bound words a limit on a number's value or on a string's or list's
+// length
+func bound(fe validator.FieldError, lang, relation, param string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return i18n.T(lang, "validation."+relation+".chars", param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return i18n.T(lang, "validation."+relation+".items", param)
+	default:
+		return i18n.T(lang, "validation."+relation, param)
+	}
+}
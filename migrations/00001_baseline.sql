@@ -0,0 +1,1196 @@
+-- Baseline schema: every table as AutoMigrate created it at startup before
+-- transactions were partitioned. IF NOT EXISTS lets databases it already
+-- created adopt this migration unchanged.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "wallets" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "balance" decimal DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "status" text DEFAULT 'active',
+    "status_reason" text DEFAULT '',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wallets_user_id" ON "wallets" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "email" text NOT NULL,
+    "password" text NOT NULL,
+    "name" text NOT NULL,
+    "phone" text NOT NULL,
+    "handle" varchar(30),
+    "handle_discoverable" boolean DEFAULT true,
+    "phone_discoverable" boolean DEFAULT true,
+    "country" varchar(2),
+    "user_type" text DEFAULT 'regular',
+    "role" text DEFAULT 'user',
+    "wallet_id" bigint DEFAULT null,
+    "status" text DEFAULT 'active',
+    "kyc_status" text DEFAULT 'pending',
+    "last_login_at" timestamptz,
+    "last_login_ip" text,
+    "two_factor_enabled" boolean DEFAULT false,
+    "failed_login_attempts" bigint DEFAULT 0,
+    "account_lockout_until" timestamptz,
+    "token_version" bigint DEFAULT 1,
+    "merchant_profile_status" text DEFAULT 'not_applicable',
+    "balance" decimal DEFAULT 0,
+    "last_active_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_wallet" FOREIGN KEY ("wallet_id") REFERENCES "wallets"("id"),
+    CONSTRAINT "uni_users_wallet_id" UNIQUE ("wallet_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_last_active_at" ON "users" ("last_active_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_handle" ON "users" ("handle");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "merchants" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "business_name" text NOT NULL,
+    "business_type" text NOT NULL,
+    "business_address" text,
+    "risk_score" bigint DEFAULT 0,
+    "compliance_level" text DEFAULT 'pending',
+    "status" text DEFAULT 'pending',
+    "processing_fee_rate" decimal DEFAULT 0,
+    "daily_transaction_limit" decimal,
+    "monthly_transaction_limit" decimal,
+    "min_transaction_amount" decimal,
+    "max_transaction_amount" decimal,
+    "webhook_url" text,
+    "webhook_secret" text,
+    "monthly_volume" decimal,
+    "metadata" jsonb,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "api_key" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_merchants_api_key" ON "merchants" ("api_key");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchants_user_id" ON "merchants" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "transactions" (
+    "id" bigserial,
+    "type" text NOT NULL,
+    "sender_id" bigint NOT NULL,
+    "receiver_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "description" text,
+    "status" text NOT NULL DEFAULT 'pending',
+    "fee" decimal DEFAULT 0,
+    "metadata" jsonb,
+    "currency" text DEFAULT 'USD',
+    "transaction_id" text,
+    "reference" text,
+    "payment_type" text,
+    "payment_method" text,
+    "merchant_id" bigint,
+    "merchant_name" text,
+    "merchant_category" text,
+    "operator_id" bigint,
+    "terminal_id" bigint,
+    "card_id" bigint,
+    "virtual_card_id" bigint,
+    "qr_code_id" text,
+    "category" varchar(50),
+    "processed_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_transactions_virtual_card_id" ON "transactions" ("virtual_card_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_terminal_id" ON "transactions" ("terminal_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_operator_id" ON "transactions" ("operator_id");
+
+CREATE TABLE IF NOT EXISTS "credit_cards" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "card_number" text NOT NULL,
+    "card_type" text NOT NULL,
+    "expiry_month" text NOT NULL,
+    "expiry_year" text NOT NULL,
+    "last_four" text NOT NULL,
+    "is_default" boolean DEFAULT false,
+    "status" text DEFAULT 'active',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_credit_cards_user_id" ON "credit_cards" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "kyc_verifications" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint NOT NULL,
+    "status" text DEFAULT 'pending',
+    "document_id" text,
+    "scan_url" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_kyc_verifications_deleted_at" ON "kyc_verifications" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "enterprises" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint,
+    "company_name" text NOT NULL,
+    "company_registration_no" text,
+    "parent_company" text,
+    "parent_enterprise_id" bigint,
+    "industry_type" text,
+    "annual_revenue" text,
+    "employee_count" bigint,
+    "billing_cycle" text DEFAULT 'monthly',
+    "contract_start_date" timestamptz,
+    "contract_end_date" timestamptz,
+    "custom_pricing_plan" jsonb,
+    "ip_whitelist" text[],
+    "compliance_officer" text,
+    "compliance_email" text,
+    "risk_level" text,
+    "last_audit_date" timestamptz,
+    "dedicated_manager" text,
+    "support_tier" text DEFAULT 'premium',
+    "monthly_volume" decimal,
+    "user_count" bigint,
+    "transaction_limit" decimal,
+    "verification_status" text DEFAULT 'pending',
+    "documents_submitted" boolean DEFAULT false,
+    "approved_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_enterprises_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_enterprises_subsidiaries" FOREIGN KEY ("parent_enterprise_id") REFERENCES "enterprises"("id"),
+    CONSTRAINT "uni_enterprises_user_id" UNIQUE ("user_id"),
+    CONSTRAINT "uni_enterprises_company_registration_no" UNIQUE ("company_registration_no")
+);
+CREATE INDEX IF NOT EXISTS "idx_enterprises_deleted_at" ON "enterprises" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "qr_codes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "code" text NOT NULL,
+    "user_id" bigint NOT NULL,
+    "user_type" text NOT NULL,
+    "type" text NOT NULL,
+    "amount" decimal,
+    "expires_at" timestamptz,
+    "max_uses" bigint NOT NULL DEFAULT 1,
+    "usage_count" bigint NOT NULL DEFAULT 0,
+    "status" text NOT NULL DEFAULT 'active',
+    "payment_purpose" text,
+    "daily_limit" decimal,
+    "monthly_limit" decimal,
+    "allowed_customers" integer[],
+    "metadata" jsonb,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_qr_codes_user_id" ON "qr_codes" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_qr_codes_code" ON "qr_codes" ("code");
+CREATE INDEX IF NOT EXISTS "idx_qr_codes_deleted_at" ON "qr_codes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "disputes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "transaction_id" bigint NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "merchant_user_id" bigint,
+    "user_id" bigint NOT NULL,
+    "reason" text NOT NULL,
+    "status" text DEFAULT 'open',
+    "refunded" boolean DEFAULT false,
+    "refund_amount" decimal,
+    "refund_tx_id" bigint,
+    "source" varchar(20) DEFAULT 'payment',
+    "response_due_at" timestamptz,
+    "merchant_response" text,
+    "responded_at" timestamptz,
+    "resolution" varchar(20),
+    "resolution_note" text,
+    "resolved_by" bigint,
+    "resolved_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_disputes_deleted_at" ON "disputes" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_disputes_status" ON "disputes" ("status");
+CREATE INDEX IF NOT EXISTS "idx_disputes_merchant_user_id" ON "disputes" ("merchant_user_id");
+
+CREATE TABLE IF NOT EXISTS "wallet_holds" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "type" text NOT NULL,
+    "status" text NOT NULL DEFAULT 'active',
+    "reason" text,
+    "reference" text,
+    "expires_at" timestamptz,
+    "released_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_wallet_holds_reference" ON "wallet_holds" ("reference");
+CREATE INDEX IF NOT EXISTS "idx_wallet_holds_status" ON "wallet_holds" ("status");
+CREATE INDEX IF NOT EXISTS "idx_wallet_holds_user_id" ON "wallet_holds" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_wallet_holds_wallet_id" ON "wallet_holds" ("wallet_id");
+
+CREATE TABLE IF NOT EXISTS "bank_accounts" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "provider" text NOT NULL,
+    "provider_account_id" text NOT NULL,
+    "institution_name" text,
+    "account_mask" varchar(4),
+    "account_type" text,
+    "holder_name" text,
+    "currency" text DEFAULT 'USD',
+    "status" text NOT NULL DEFAULT 'pending_verification',
+    "verification_method" text,
+    "verification_attempts" bigint DEFAULT 0,
+    "is_default" boolean DEFAULT false,
+    "verified_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_bank_accounts_provider_account_id" ON "bank_accounts" ("provider_account_id");
+CREATE INDEX IF NOT EXISTS "idx_bank_accounts_user_id" ON "bank_accounts" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "virtual_cards" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "wallet_id" bigint NOT NULL,
+    "provider" text NOT NULL,
+    "provider_card_id" text NOT NULL,
+    "type" text NOT NULL,
+    "status" text NOT NULL DEFAULT 'active',
+    "nickname" text,
+    "brand" text,
+    "last_four" varchar(4),
+    "expiry_month" bigint,
+    "expiry_year" bigint,
+    "currency" text DEFAULT 'USD',
+    "spend_limit" decimal DEFAULT 0,
+    "spend_limit_interval" text,
+    "frozen_at" timestamptz,
+    "closed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_virtual_cards_provider_card_id" ON "virtual_cards" ("provider_card_id");
+CREATE INDEX IF NOT EXISTS "idx_virtual_cards_wallet_id" ON "virtual_cards" ("wallet_id");
+CREATE INDEX IF NOT EXISTS "idx_virtual_cards_user_id" ON "virtual_cards" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "payment_links" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "code" text NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "description" text,
+    "status" text NOT NULL DEFAULT 'active',
+    "expires_at" timestamptz,
+    "max_uses" bigint DEFAULT -1,
+    "use_count" bigint DEFAULT 0,
+    "view_count" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payment_links_code" ON "payment_links" ("code");
+CREATE INDEX IF NOT EXISTS "idx_payment_links_merchant_id" ON "payment_links" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "checkout_sessions" (
+    "id" bigserial,
+    "session_id" text NOT NULL,
+    "source" varchar(20) NOT NULL DEFAULT 'payment_link',
+    "payment_link_id" bigint,
+    "merchant_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "description" text,
+    "client_reference" varchar(100),
+    "metadata" jsonb,
+    "success_url" text,
+    "cancel_url" text,
+    "status" text NOT NULL DEFAULT 'open',
+    "transaction_id" bigint,
+    "expires_at" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_checkout_sessions_client_reference" ON "checkout_sessions" ("client_reference");
+CREATE INDEX IF NOT EXISTS "idx_checkout_sessions_user_id" ON "checkout_sessions" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_checkout_sessions_merchant_id" ON "checkout_sessions" ("merchant_id");
+CREATE INDEX IF NOT EXISTS "idx_checkout_sessions_payment_link_id" ON "checkout_sessions" ("payment_link_id");
+CREATE INDEX IF NOT EXISTS "idx_checkout_sessions_source" ON "checkout_sessions" ("source");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_checkout_sessions_session_id" ON "checkout_sessions" ("session_id");
+
+CREATE TABLE IF NOT EXISTS "export_schedules" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "frequency" text NOT NULL DEFAULT 'monthly',
+    "day_of_month" bigint NOT NULL DEFAULT 1,
+    "day_of_week" bigint NOT NULL DEFAULT 1,
+    "format" text NOT NULL DEFAULT 'csv',
+    "destination" text NOT NULL,
+    "email" text,
+    "storage_connector" text,
+    "storage_path" text,
+    "webhook_url" text,
+    "webhook_secret" text,
+    "status" text NOT NULL DEFAULT 'active',
+    "next_run_at" timestamptz,
+    "last_run_at" timestamptz,
+    "last_run_status" text,
+    "consecutive_failures" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_export_schedules_next_run_at" ON "export_schedules" ("next_run_at");
+CREATE INDEX IF NOT EXISTS "idx_export_schedules_user_id" ON "export_schedules" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "export_runs" (
+    "id" bigserial,
+    "schedule_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "period_start" timestamptz,
+    "period_end" timestamptz,
+    "status" text NOT NULL,
+    "row_count" bigint,
+    "location" text,
+    "error" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_export_runs_user_id" ON "export_runs" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_export_runs_schedule_id" ON "export_runs" ("schedule_id");
+
+CREATE TABLE IF NOT EXISTS "invoices" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "number" text NOT NULL,
+    "public_code" text NOT NULL,
+    "customer_name" text,
+    "customer_email" text NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "status" text NOT NULL DEFAULT 'draft',
+    "subtotal" decimal,
+    "tax_rate" decimal,
+    "tax_amount" decimal,
+    "total" decimal,
+    "due_date" timestamptz,
+    "notes" text,
+    "sent_at" timestamptz,
+    "paid_at" timestamptz,
+    "paid_by" bigint,
+    "transaction_id" bigint,
+    "last_reminder_at" timestamptz,
+    "reminder_count" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_invoices_status" ON "invoices" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invoices_public_code" ON "invoices" ("public_code");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invoices_number" ON "invoices" ("number");
+CREATE INDEX IF NOT EXISTS "idx_invoices_merchant_id" ON "invoices" ("merchant_id");
+CREATE INDEX IF NOT EXISTS "idx_invoices_due_date" ON "invoices" ("due_date");
+
+CREATE TABLE IF NOT EXISTS "invoice_line_items" (
+    "id" bigserial,
+    "invoice_id" bigint NOT NULL,
+    "description" text NOT NULL,
+    "quantity" decimal NOT NULL,
+    "unit_price" decimal NOT NULL,
+    "amount" decimal NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_invoices_line_items" FOREIGN KEY ("invoice_id") REFERENCES "invoices"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_invoice_line_items_invoice_id" ON "invoice_line_items" ("invoice_id");
+
+CREATE TABLE IF NOT EXISTS "invoice_counters" (
+    "merchant_id" bigint,
+    "last_number" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("merchant_id")
+);
+
+CREATE TABLE IF NOT EXISTS "merchant_fraud_rules" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "max_charge_amount" decimal DEFAULT 0,
+    "max_charges_per_customer_daily" bigint DEFAULT 0,
+    "blocked_countries" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchant_fraud_rules_merchant_id" ON "merchant_fraud_rules" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "deposits" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "bank_account_id" bigint NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "provider_transfer_id" text NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "status" text NOT NULL DEFAULT 'initiated',
+    "credited" boolean DEFAULT false,
+    "return_code" text,
+    "return_reason" text,
+    "reversal_transaction_id" bigint,
+    "fee_transaction_id" bigint,
+    "settled_at" timestamptz,
+    "returned_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_deposits_user_id" ON "deposits" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_deposits_status" ON "deposits" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_deposits_provider_transfer_id" ON "deposits" ("provider_transfer_id");
+CREATE INDEX IF NOT EXISTS "idx_deposits_bank_account_id" ON "deposits" ("bank_account_id");
+
+CREATE TABLE IF NOT EXISTS "deposit_events" (
+    "id" bigserial,
+    "deposit_id" bigint NOT NULL,
+    "event_id" text NOT NULL,
+    "status" text NOT NULL,
+    "return_code" text,
+    "applied" boolean,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_deposit_events_deposit_id" ON "deposit_events" ("deposit_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_deposit_events_event_id" ON "deposit_events" ("event_id");
+
+CREATE TABLE IF NOT EXISTS "receipts" (
+    "id" bigserial,
+    "transaction_id" bigint NOT NULL,
+    "number" text NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "merchant_name" text,
+    "merchant_address" text,
+    "merchant_category" text,
+    "currency" text DEFAULT 'USD',
+    "subtotal" decimal,
+    "tax_amount" decimal,
+    "tip" decimal,
+    "fee" decimal,
+    "total" decimal,
+    "payment_method" text,
+    "emailed_to" text,
+    "emailed_at" timestamptz,
+    "issued_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_receipts_number" ON "receipts" ("number");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_receipts_transaction_id" ON "receipts" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_receipts_customer_id" ON "receipts" ("customer_id");
+CREATE INDEX IF NOT EXISTS "idx_receipts_merchant_id" ON "receipts" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "receipt_items" (
+    "id" bigserial,
+    "receipt_id" bigint NOT NULL,
+    "description" text NOT NULL,
+    "quantity" decimal NOT NULL,
+    "unit_price" decimal NOT NULL,
+    "amount" decimal NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_receipts_items" FOREIGN KEY ("receipt_id") REFERENCES "receipts"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_receipt_items_receipt_id" ON "receipt_items" ("receipt_id");
+
+CREATE TABLE IF NOT EXISTS "system_accounts" (
+    "id" bigserial,
+    "code" varchar(50) NOT NULL,
+    "name" text NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "balance" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_system_accounts_code" ON "system_accounts" ("code");
+
+CREATE TABLE IF NOT EXISTS "system_ledger_entries" (
+    "id" bigserial,
+    "account_id" bigint NOT NULL,
+    "kind" varchar(20) NOT NULL,
+    "amount" decimal NOT NULL,
+    "balance_after" decimal NOT NULL,
+    "transaction_id" bigint,
+    "transfer_id" bigint,
+    "description" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_system_ledger_entries_created_at" ON "system_ledger_entries" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_system_ledger_entries_transfer_id" ON "system_ledger_entries" ("transfer_id");
+CREATE INDEX IF NOT EXISTS "idx_system_ledger_entries_transaction_id" ON "system_ledger_entries" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_system_ledger_entries_kind" ON "system_ledger_entries" ("kind");
+CREATE INDEX IF NOT EXISTS "idx_system_ledger_entries_account_id" ON "system_ledger_entries" ("account_id");
+
+CREATE TABLE IF NOT EXISTS "treasury_transfers" (
+    "id" bigserial,
+    "from_account_code" varchar(50) NOT NULL,
+    "to_account_code" varchar(50) NOT NULL,
+    "amount" decimal NOT NULL,
+    "reason" text NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "requested_by" bigint NOT NULL,
+    "reviewed_by" bigint,
+    "review_note" text,
+    "reviewed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_treasury_transfers_status" ON "treasury_transfers" ("status");
+
+CREATE TABLE IF NOT EXISTS "splits" (
+    "id" bigserial,
+    "organizer_id" bigint NOT NULL,
+    "transaction_id" bigint,
+    "title" text NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "total_amount" decimal NOT NULL,
+    "organizer_share" decimal NOT NULL DEFAULT 0,
+    "collected_amount" decimal NOT NULL DEFAULT 0,
+    "share_count" bigint NOT NULL,
+    "paid_count" bigint NOT NULL DEFAULT 0,
+    "status" text NOT NULL DEFAULT 'open',
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_splits_organizer_id" ON "splits" ("organizer_id");
+CREATE INDEX IF NOT EXISTS "idx_splits_status" ON "splits" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_splits_transaction_id" ON "splits" ("transaction_id");
+
+CREATE TABLE IF NOT EXISTS "split_shares" (
+    "id" bigserial,
+    "split_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "status" text NOT NULL DEFAULT 'pending',
+    "transaction_id" bigint,
+    "paid_at" timestamptz,
+    "last_reminder_at" timestamptz,
+    "reminder_count" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_splits_shares" FOREIGN KEY ("split_id") REFERENCES "splits"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_split_shares_user_id" ON "split_shares" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_split_share_user" ON "split_shares" ("split_id","user_id");
+CREATE INDEX IF NOT EXISTS "idx_split_shares_status" ON "split_shares" ("status");
+
+CREATE TABLE IF NOT EXISTS "shared_wallets" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "balance" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "status" text DEFAULT 'active',
+    "approval_threshold" decimal DEFAULT 0,
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "wallet_members" (
+    "id" bigserial,
+    "shared_wallet_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "role" varchar(20) NOT NULL,
+    "per_transaction_limit" decimal DEFAULT 0,
+    "daily_limit" decimal DEFAULT 0,
+    "added_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wallet_member" ON "wallet_members" ("shared_wallet_id","user_id");
+CREATE INDEX IF NOT EXISTS "idx_wallet_members_user_id" ON "wallet_members" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "shared_wallet_payments" (
+    "id" bigserial,
+    "shared_wallet_id" bigint NOT NULL,
+    "requested_by" bigint NOT NULL,
+    "recipient_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "description" text,
+    "status" varchar(20) NOT NULL,
+    "reviewed_by" bigint,
+    "reviewed_at" timestamptz,
+    "transaction_id" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_shared_wallet_payments_created_at" ON "shared_wallet_payments" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_shared_wallet_payments_status" ON "shared_wallet_payments" ("status");
+CREATE INDEX IF NOT EXISTS "idx_shared_wallet_payments_requested_by" ON "shared_wallet_payments" ("requested_by");
+CREATE INDEX IF NOT EXISTS "idx_shared_wallet_payments_shared_wallet_id" ON "shared_wallet_payments" ("shared_wallet_id");
+
+CREATE TABLE IF NOT EXISTS "pots" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "wallet_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "balance" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "target_amount" decimal DEFAULT 0,
+    "locked_until" timestamptz,
+    "status" text NOT NULL DEFAULT 'active',
+    "round_up_enabled" boolean DEFAULT false,
+    "round_up_to" decimal DEFAULT 1,
+    "round_up_after_id" bigint DEFAULT 0,
+    "sweep_amount" decimal DEFAULT 0,
+    "sweep_frequency" varchar(10),
+    "next_sweep_at" timestamptz,
+    "closed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_pots_next_sweep_at" ON "pots" ("next_sweep_at");
+CREATE INDEX IF NOT EXISTS "idx_pots_round_up_enabled" ON "pots" ("round_up_enabled");
+CREATE INDEX IF NOT EXISTS "idx_pots_status" ON "pots" ("status");
+CREATE INDEX IF NOT EXISTS "idx_pots_wallet_id" ON "pots" ("wallet_id");
+CREATE INDEX IF NOT EXISTS "idx_pots_user_id" ON "pots" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "pot_entries" (
+    "id" bigserial,
+    "pot_id" bigint NOT NULL,
+    "kind" varchar(20) NOT NULL,
+    "amount" decimal NOT NULL,
+    "balance_after" decimal NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "source_transaction_id" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_pot_entry_source" ON "pot_entries" ("pot_id","source_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_pot_entries_pot_id" ON "pot_entries" ("pot_id");
+
+CREATE TABLE IF NOT EXISTS "contacts" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "contact_user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "nickname" text,
+    "added_via" varchar(10) NOT NULL,
+    "favorite" boolean DEFAULT false,
+    "payment_count" bigint DEFAULT 0,
+    "last_paid_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_contact_user_pair" ON "contacts" ("user_id","contact_user_id");
+CREATE INDEX IF NOT EXISTS "idx_contacts_favorite" ON "contacts" ("favorite");
+CREATE INDEX IF NOT EXISTS "idx_contacts_contact_user_id" ON "contacts" ("contact_user_id");
+
+CREATE TABLE IF NOT EXISTS "escrows" (
+    "id" bigserial,
+    "buyer_id" bigint NOT NULL,
+    "seller_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "description" text NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'funded',
+    "auto_release_at" timestamptz NOT NULL,
+    "funding_transaction_id" bigint,
+    "settle_transaction_id" bigint,
+    "settled_reason" varchar(30),
+    "settled_at" timestamptz,
+    "dispute_id" bigint,
+    "disputed_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_escrows_auto_release_at" ON "escrows" ("auto_release_at");
+CREATE INDEX IF NOT EXISTS "idx_escrows_status" ON "escrows" ("status");
+CREATE INDEX IF NOT EXISTS "idx_escrows_seller_id" ON "escrows" ("seller_id");
+CREATE INDEX IF NOT EXISTS "idx_escrows_buyer_id" ON "escrows" ("buyer_id");
+
+CREATE TABLE IF NOT EXISTS "dispute_evidences" (
+    "id" bigserial,
+    "dispute_id" bigint NOT NULL,
+    "chargeback_id" bigint,
+    "uploaded_by" bigint NOT NULL,
+    "party" varchar(10) NOT NULL,
+    "file_name" text NOT NULL,
+    "content_type" text NOT NULL,
+    "size" bigint NOT NULL,
+    "storage_key" text NOT NULL,
+    "description" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_dispute_evidences_chargeback_id" ON "dispute_evidences" ("chargeback_id");
+CREATE INDEX IF NOT EXISTS "idx_dispute_evidences_dispute_id" ON "dispute_evidences" ("dispute_id");
+
+CREATE TABLE IF NOT EXISTS "chargebacks" (
+    "id" bigserial,
+    "dispute_id" bigint NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "merchant_user_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "fee" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "reason_code" varchar(20),
+    "status" varchar(20) NOT NULL DEFAULT 'open',
+    "reserve_hold_id" bigint,
+    "fee_transaction_id" bigint,
+    "settle_transaction_id" bigint,
+    "representment_due_at" timestamptz,
+    "representment_note" text,
+    "represented_at" timestamptz,
+    "opened_by" bigint NOT NULL,
+    "resolution_note" text,
+    "resolved_by" bigint,
+    "resolved_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_chargebacks_status" ON "chargebacks" ("status");
+CREATE INDEX IF NOT EXISTS "idx_chargebacks_customer_id" ON "chargebacks" ("customer_id");
+CREATE INDEX IF NOT EXISTS "idx_chargebacks_merchant_user_id" ON "chargebacks" ("merchant_user_id");
+CREATE INDEX IF NOT EXISTS "idx_chargebacks_transaction_id" ON "chargebacks" ("transaction_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_chargebacks_dispute_id" ON "chargebacks" ("dispute_id");
+CREATE INDEX IF NOT EXISTS "idx_chargebacks_representment_due_at" ON "chargebacks" ("representment_due_at");
+
+CREATE TABLE IF NOT EXISTS "merchant_staffs" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "merchant_user_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "display_name" varchar(50),
+    "can_charge" boolean NOT NULL DEFAULT true,
+    "can_refund" boolean NOT NULL DEFAULT false,
+    "can_view_reports" boolean NOT NULL DEFAULT false,
+    "status" varchar(20) NOT NULL DEFAULT 'invited',
+    "pin_hash" text,
+    "pin_set_at" timestamptz,
+    "failed_pi_ns" bigint NOT NULL DEFAULT 0,
+    "pin_locked_until" timestamptz,
+    "invited_by" bigint NOT NULL,
+    "accepted_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchant_staff_user" ON "merchant_staffs" ("merchant_id","user_id");
+CREATE INDEX IF NOT EXISTS "idx_merchant_staffs_status" ON "merchant_staffs" ("status");
+CREATE INDEX IF NOT EXISTS "idx_merchant_staffs_user_id" ON "merchant_staffs" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_merchant_staffs_merchant_user_id" ON "merchant_staffs" ("merchant_user_id");
+
+CREATE TABLE IF NOT EXISTS "terminals" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "merchant_user_id" bigint NOT NULL,
+    "name" varchar(50) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "pairing_code_hash" varchar(64),
+    "pairing_expires_at" timestamptz,
+    "credential_key" varchar(40),
+    "secret_hash" varchar(64),
+    "device_model" text,
+    "device_serial" text,
+    "paired_at" timestamptz,
+    "last_seen_at" timestamptz,
+    "deactivated_at" timestamptz,
+    "deactivated_by" bigint,
+    "deactivation_reason" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_terminals_credential_key" ON "terminals" ("credential_key");
+CREATE INDEX IF NOT EXISTS "idx_terminals_pairing_code_hash" ON "terminals" ("pairing_code_hash");
+CREATE INDEX IF NOT EXISTS "idx_terminals_status" ON "terminals" ("status");
+CREATE INDEX IF NOT EXISTS "idx_terminals_merchant_user_id" ON "terminals" ("merchant_user_id");
+CREATE INDEX IF NOT EXISTS "idx_terminals_merchant_id" ON "terminals" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+    "id" bigserial,
+    "event_id" varchar(40) NOT NULL,
+    "merchant_user_id" bigint NOT NULL,
+    "event" varchar(50) NOT NULL,
+    "payload" text NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "attempts" bigint DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    "last_status_code" bigint,
+    "last_error" text,
+    "delivered_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_merchant_user_id" ON "webhook_deliveries" ("merchant_user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_webhook_deliveries_event_id" ON "webhook_deliveries" ("event_id");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+
+CREATE TABLE IF NOT EXISTS "subscription_plans" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    "amount" decimal NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "interval" varchar(10) NOT NULL,
+    "interval_count" bigint NOT NULL DEFAULT 1,
+    "trial_days" bigint DEFAULT 0,
+    "status" varchar(20) NOT NULL DEFAULT 'active',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscription_plans_status" ON "subscription_plans" ("status");
+CREATE INDEX IF NOT EXISTS "idx_subscription_plans_merchant_id" ON "subscription_plans" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+    "id" bigserial,
+    "plan_id" bigint NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text DEFAULT 'USD',
+    "interval" varchar(10) NOT NULL,
+    "interval_count" bigint NOT NULL DEFAULT 1,
+    "status" varchar(20) NOT NULL,
+    "current_period_start" timestamptz,
+    "current_period_end" timestamptz,
+    "next_billing_at" timestamptz,
+    "failed_attempts" bigint DEFAULT 0,
+    "last_charge_at" timestamptz,
+    "paused_at" timestamptz,
+    "canceled_at" timestamptz,
+    "canceled_by" bigint,
+    "cancel_reason" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_plan_id" ON "subscriptions" ("plan_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_next_billing_at" ON "subscriptions" ("next_billing_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_status" ON "subscriptions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_customer_id" ON "subscriptions" ("customer_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_merchant_id" ON "subscriptions" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "subscription_consents" (
+    "id" bigserial,
+    "subscription_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "plan_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text,
+    "interval" varchar(10),
+    "interval_count" bigint,
+    "text" text NOT NULL,
+    "ip_address" varchar(45),
+    "user_agent" text,
+    "accepted_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscription_consents_customer_id" ON "subscription_consents" ("customer_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscription_consents_subscription_id" ON "subscription_consents" ("subscription_id");
+
+CREATE TABLE IF NOT EXISTS "subscription_charges" (
+    "id" bigserial,
+    "subscription_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "period_start" timestamptz,
+    "period_end" timestamptz,
+    "attempt" bigint NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "transaction_id" bigint,
+    "failure_reason" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscription_charges_subscription_id" ON "subscription_charges" ("subscription_id");
+
+CREATE TABLE IF NOT EXISTS "promotions" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    "code" varchar(32),
+    "type" varchar(20) NOT NULL,
+    "rate" decimal DEFAULT 0,
+    "amount" decimal DEFAULT 0,
+    "max_reward" decimal DEFAULT 0,
+    "min_spend" decimal DEFAULT 0,
+    "merchant_id" bigint,
+    "merchant_category" varchar(50),
+    "funded_by" varchar(10) NOT NULL,
+    "budget" decimal NOT NULL,
+    "spent" decimal NOT NULL DEFAULT 0,
+    "max_per_user" bigint DEFAULT 0,
+    "starts_at" timestamptz,
+    "ends_at" timestamptz,
+    "status" varchar(20) NOT NULL DEFAULT 'active',
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_promotions_status" ON "promotions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_promotions_merchant_id" ON "promotions" ("merchant_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_promotions_code" ON "promotions" ("code");
+
+CREATE TABLE IF NOT EXISTS "promotion_enrollments" (
+    "id" bigserial,
+    "promotion_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_promotion_enrollments_user_id" ON "promotion_enrollments" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_promotion_enrollment" ON "promotion_enrollments" ("promotion_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "promotion_rewards" (
+    "id" bigserial,
+    "promotion_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "payment_transaction_id" bigint NOT NULL,
+    "reward_transaction_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "funded_by" varchar(10) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_promotion_reward_payment" ON "promotion_rewards" ("promotion_id","payment_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_promotion_rewards_created_at" ON "promotion_rewards" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_promotion_rewards_user_id" ON "promotion_rewards" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "loyalty_programs" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "earn_rate" decimal NOT NULL,
+    "burn_rate" bigint NOT NULL,
+    "min_redeem_points" bigint DEFAULT 0,
+    "max_redeem_percent" decimal NOT NULL DEFAULT 100,
+    "status" varchar(20) NOT NULL DEFAULT 'active',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_loyalty_programs_merchant_id" ON "loyalty_programs" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "loyalty_accounts" (
+    "id" bigserial,
+    "program_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "balance" bigint NOT NULL DEFAULT 0,
+    "earned" bigint NOT NULL DEFAULT 0,
+    "redeemed" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_loyalty_accounts_merchant_id" ON "loyalty_accounts" ("merchant_id");
+CREATE INDEX IF NOT EXISTS "idx_loyalty_accounts_customer_id" ON "loyalty_accounts" ("customer_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_loyalty_account" ON "loyalty_accounts" ("program_id","customer_id");
+
+CREATE TABLE IF NOT EXISTS "loyalty_entries" (
+    "id" bigserial,
+    "account_id" bigint NOT NULL,
+    "kind" varchar(10) NOT NULL,
+    "points" bigint NOT NULL,
+    "balance_after" bigint NOT NULL,
+    "value" decimal DEFAULT 0,
+    "transaction_id" bigint,
+    "description" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_loyalty_entries_created_at" ON "loyalty_entries" ("created_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_loyalty_entry_transaction" ON "loyalty_entries" ("kind","transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_loyalty_entries_account_id" ON "loyalty_entries" ("account_id");
+
+CREATE TABLE IF NOT EXISTS "user_daily_stats" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "day" date NOT NULL,
+    "sent_count" bigint NOT NULL DEFAULT 0,
+    "sent_volume" decimal NOT NULL DEFAULT 0,
+    "received_count" bigint NOT NULL DEFAULT 0,
+    "received_volume" decimal NOT NULL DEFAULT 0,
+    "last_transaction_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_daily_stats" ON "user_daily_stats" ("user_id","day");
+
+CREATE TABLE IF NOT EXISTS "merchant_daily_stats" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "day" date NOT NULL,
+    "count" bigint NOT NULL DEFAULT 0,
+    "volume" decimal NOT NULL DEFAULT 0,
+    "fees" decimal NOT NULL DEFAULT 0,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchant_daily_stats" ON "merchant_daily_stats" ("merchant_id","day");
+
+CREATE TABLE IF NOT EXISTS "daily_breakdowns" (
+    "id" bigserial,
+    "scope" varchar(20) NOT NULL,
+    "owner_id" bigint NOT NULL,
+    "day" date NOT NULL,
+    "key" varchar(50) NOT NULL,
+    "count" bigint NOT NULL DEFAULT 0,
+    "volume" decimal NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_daily_breakdown" ON "daily_breakdowns" ("scope","owner_id","day","key");
+
+CREATE TABLE IF NOT EXISTS "projected_transactions" (
+    "transaction_id" bigserial,
+    "projected_at" timestamptz,
+    PRIMARY KEY ("transaction_id")
+);
+
+CREATE TABLE IF NOT EXISTS "projection_cursors" (
+    "name" varchar(50),
+    "position" timestamptz NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "archived_transactions" (
+    "id" bigserial,
+    "type" text NOT NULL,
+    "sender_id" bigint NOT NULL,
+    "receiver_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "description" text,
+    "status" text NOT NULL DEFAULT 'pending',
+    "fee" decimal DEFAULT 0,
+    "metadata" jsonb,
+    "currency" text DEFAULT 'USD',
+    "transaction_id" text,
+    "reference" text,
+    "payment_type" text,
+    "payment_method" text,
+    "merchant_id" bigint,
+    "merchant_name" text,
+    "merchant_category" text,
+    "operator_id" bigint,
+    "terminal_id" bigint,
+    "card_id" bigint,
+    "virtual_card_id" bigint,
+    "qr_code_id" text,
+    "category" varchar(50),
+    "processed_at" timestamptz,
+    "updated_at" timestamptz,
+    "archived_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_archived_transactions_archived_at" ON "archived_transactions" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_archived_transactions_virtual_card_id" ON "archived_transactions" ("virtual_card_id");
+CREATE INDEX IF NOT EXISTS "idx_archived_transactions_terminal_id" ON "archived_transactions" ("terminal_id");
+CREATE INDEX IF NOT EXISTS "idx_archived_transactions_operator_id" ON "archived_transactions" ("operator_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "archived_transactions";
+DROP TABLE IF EXISTS "projection_cursors";
+DROP TABLE IF EXISTS "projected_transactions";
+DROP TABLE IF EXISTS "daily_breakdowns";
+DROP TABLE IF EXISTS "merchant_daily_stats";
+DROP TABLE IF EXISTS "user_daily_stats";
+DROP TABLE IF EXISTS "loyalty_entries";
+DROP TABLE IF EXISTS "loyalty_accounts";
+DROP TABLE IF EXISTS "loyalty_programs";
+DROP TABLE IF EXISTS "promotion_rewards";
+DROP TABLE IF EXISTS "promotion_enrollments";
+DROP TABLE IF EXISTS "promotions";
+DROP TABLE IF EXISTS "subscription_charges";
+DROP TABLE IF EXISTS "subscription_consents";
+DROP TABLE IF EXISTS "subscriptions";
+DROP TABLE IF EXISTS "subscription_plans";
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "terminals";
+DROP TABLE IF EXISTS "merchant_staffs";
+DROP TABLE IF EXISTS "chargebacks";
+DROP TABLE IF EXISTS "dispute_evidences";
+DROP TABLE IF EXISTS "escrows";
+DROP TABLE IF EXISTS "contacts";
+DROP TABLE IF EXISTS "pot_entries";
+DROP TABLE IF EXISTS "pots";
+DROP TABLE IF EXISTS "shared_wallet_payments";
+DROP TABLE IF EXISTS "wallet_members";
+DROP TABLE IF EXISTS "shared_wallets";
+DROP TABLE IF EXISTS "split_shares";
+DROP TABLE IF EXISTS "splits";
+DROP TABLE IF EXISTS "treasury_transfers";
+DROP TABLE IF EXISTS "system_ledger_entries";
+DROP TABLE IF EXISTS "system_accounts";
+DROP TABLE IF EXISTS "receipt_items";
+DROP TABLE IF EXISTS "receipts";
+DROP TABLE IF EXISTS "deposit_events";
+DROP TABLE IF EXISTS "deposits";
+DROP TABLE IF EXISTS "merchant_fraud_rules";
+DROP TABLE IF EXISTS "invoice_counters";
+DROP TABLE IF EXISTS "invoice_line_items";
+DROP TABLE IF EXISTS "invoices";
+DROP TABLE IF EXISTS "export_runs";
+DROP TABLE IF EXISTS "export_schedules";
+DROP TABLE IF EXISTS "checkout_sessions";
+DROP TABLE IF EXISTS "payment_links";
+DROP TABLE IF EXISTS "virtual_cards";
+DROP TABLE IF EXISTS "bank_accounts";
+DROP TABLE IF EXISTS "wallet_holds";
+DROP TABLE IF EXISTS "disputes";
+DROP TABLE IF EXISTS "qr_codes";
+DROP TABLE IF EXISTS "enterprises";
+DROP TABLE IF EXISTS "kyc_verifications";
+DROP TABLE IF EXISTS "credit_cards";
+DROP TABLE IF EXISTS "transactions";
+DROP TABLE IF EXISTS "merchants";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "wallets";
@@ -0,0 +1,109 @@
+-- Records when each transaction was created and range partitions the
+-- transactions table by month of it, so queries bounded on created_at only
+-- scan the months they need. cmd/migrate and the partition job keep the
+-- coming months' partitions created.
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE "archived_transactions" ADD COLUMN IF NOT EXISTS "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+CREATE INDEX IF NOT EXISTS "idx_archived_transactions_sender_created" ON "archived_transactions" ("sender_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_archived_transactions_receiver_created" ON "archived_transactions" ("receiver_id","created_at");
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+    ref record;
+    id_sequence text;
+    oldest timestamptz;
+    month_start timestamptz;
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'transactions'::regclass) THEN
+        RETURN;
+    END IF;
+
+    -- Rows from before created_at existed got the migration time; give
+    -- them back when they actually happened. Some older flows never set
+    -- processed_at, so fall back to the last update for those.
+    UPDATE "transactions" SET "created_at" = LEAST("created_at",
+        CASE WHEN "processed_at" > '0001-01-02' THEN "processed_at" ELSE "updated_at" END);
+    UPDATE "archived_transactions" SET "created_at" = LEAST("created_at",
+        CASE WHEN "processed_at" > '0001-01-02' THEN "processed_at" ELSE "updated_at" END);
+
+    -- A partitioned table can't be referenced by a foreign key on id alone
+    FOR ref IN
+        SELECT conrelid::regclass AS tbl, conname FROM pg_constraint
+        WHERE contype = 'f' AND confrelid = 'transactions'::regclass
+    LOOP
+        RAISE NOTICE 'dropping foreign key % on %', ref.conname, ref.tbl;
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', ref.tbl, ref.conname);
+    END LOOP;
+
+    id_sequence := pg_get_serial_sequence('transactions', 'id');
+    SELECT MIN("created_at") INTO oldest FROM "transactions";
+
+    ALTER TABLE "transactions" RENAME TO "transactions_unpartitioned";
+    ALTER TABLE "transactions_unpartitioned" RENAME CONSTRAINT "transactions_pkey" TO "transactions_unpartitioned_pkey";
+    CREATE TABLE "transactions" (
+        LIKE "transactions_unpartitioned" INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+        PRIMARY KEY ("id", "created_at")
+    ) PARTITION BY RANGE ("created_at");
+    CREATE TABLE "transactions_default" PARTITION OF "transactions" DEFAULT;
+    -- Dropping the old table would drop the sequence it owns
+    IF id_sequence IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY "transactions"."id"', id_sequence);
+    END IF;
+
+    -- One partition per UTC month from the oldest row, at most ten years
+    -- back, to three months ahead. Anything older lands in the default.
+    month_start := date_trunc('month', GREATEST(COALESCE(oldest, now()), now() - interval '120 months') AT TIME ZONE 'UTC') AT TIME ZONE 'UTC';
+    WHILE month_start <= now() + interval '3 months' LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF "transactions" FOR VALUES FROM (%L) TO (%L)',
+            'transactions_' || to_char(month_start AT TIME ZONE 'UTC', 'YYYY_MM'), month_start, month_start + interval '1 month');
+        month_start := month_start + interval '1 month';
+    END LOOP;
+
+    INSERT INTO "transactions" SELECT * FROM "transactions_unpartitioned";
+    DROP TABLE "transactions_unpartitioned";
+END
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS "idx_transactions_sender_created" ON "transactions" ("sender_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_transactions_receiver_created" ON "transactions" ("receiver_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_transactions_virtual_card_id" ON "transactions" ("virtual_card_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_terminal_id" ON "transactions" ("terminal_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_operator_id" ON "transactions" ("operator_id");
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+    id_sequence text;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'transactions'::regclass) THEN
+        RETURN;
+    END IF;
+
+    id_sequence := pg_get_serial_sequence('transactions', 'id');
+
+    ALTER TABLE "transactions" RENAME TO "transactions_partitioned";
+    ALTER TABLE "transactions_partitioned" RENAME CONSTRAINT "transactions_pkey" TO "transactions_partitioned_pkey";
+    CREATE TABLE "transactions" (
+        LIKE "transactions_partitioned" INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+        PRIMARY KEY ("id")
+    );
+    IF id_sequence IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY "transactions"."id"', id_sequence);
+    END IF;
+
+    INSERT INTO "transactions" SELECT * FROM "transactions_partitioned";
+    DROP TABLE "transactions_partitioned";
+END
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "created_at";
+ALTER TABLE "archived_transactions" DROP COLUMN IF EXISTS "created_at";
+CREATE INDEX IF NOT EXISTS "idx_transactions_virtual_card_id" ON "transactions" ("virtual_card_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_terminal_id" ON "transactions" ("terminal_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_operator_id" ON "transactions" ("operator_id");
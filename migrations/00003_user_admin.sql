@@ -0,0 +1,22 @@
+-- Lets admins suspend accounts and records account events for the admin
+-- activity timeline.
+
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "suspended_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "suspension_reason" text;
+
+CREATE TABLE IF NOT EXISTS "user_activities" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "actor_id" bigint,
+    "action" varchar(30) NOT NULL,
+    "details" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_activities_user_created" ON "user_activities" ("user_id","created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "user_activities";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "suspension_reason";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "suspended_at";
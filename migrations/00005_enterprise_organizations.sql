@@ -0,0 +1,73 @@
+-- Turns enterprises into organizations: member users with roles and
+-- spending policies, organization wallets, and their fundings and payments.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "enterprise_members" (
+    "id" bigserial,
+    "enterprise_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "role" varchar(20) NOT NULL,
+    "title" text,
+    "per_transaction_limit" decimal DEFAULT 0,
+    "daily_limit" decimal DEFAULT 0,
+    "monthly_limit" decimal DEFAULT 0,
+    "added_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_enterprise_member" ON "enterprise_members" ("enterprise_id","user_id");
+CREATE INDEX IF NOT EXISTS "idx_enterprise_members_user_id" ON "enterprise_members" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "enterprise_wallets" (
+    "id" bigserial,
+    "enterprise_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "balance" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "status" text DEFAULT 'active',
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_enterprise_wallets_enterprise_id" ON "enterprise_wallets" ("enterprise_id");
+
+CREATE TABLE IF NOT EXISTS "enterprise_fundings" (
+    "id" bigserial,
+    "enterprise_id" bigint NOT NULL,
+    "wallet_id" bigint NOT NULL,
+    "funded_by" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "transaction_id" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_enterprise_fundings_enterprise_created" ON "enterprise_fundings" ("enterprise_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_enterprise_fundings_wallet_id" ON "enterprise_fundings" ("wallet_id");
+
+CREATE TABLE IF NOT EXISTS "enterprise_payments" (
+    "id" bigserial,
+    "enterprise_id" bigint NOT NULL,
+    "wallet_id" bigint NOT NULL,
+    "requested_by" bigint NOT NULL,
+    "recipient_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "category" varchar(50),
+    "description" text,
+    "status" varchar(20) NOT NULL,
+    "transaction_id" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_enterprise_payments_enterprise_created" ON "enterprise_payments" ("enterprise_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_enterprise_payments_wallet_id" ON "enterprise_payments" ("wallet_id");
+CREATE INDEX IF NOT EXISTS "idx_enterprise_payments_requested_by" ON "enterprise_payments" ("requested_by");
+CREATE INDEX IF NOT EXISTS "idx_enterprise_payments_status" ON "enterprise_payments" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "enterprise_payments";
+DROP TABLE IF EXISTS "enterprise_fundings";
+DROP TABLE IF EXISTS "enterprise_wallets";
+DROP TABLE IF EXISTS "enterprise_members";
@@ -0,0 +1,47 @@
+-- Approval rules for organization wallets: matching payments wait in a
+-- queue for an approver, and every step is kept as an audit event.
+
+-- +goose Up
+ALTER TABLE "enterprise_payments" ADD COLUMN IF NOT EXISTS "rule_id" bigint;
+ALTER TABLE "enterprise_payments" ADD COLUMN IF NOT EXISTS "approver_role" varchar(20);
+ALTER TABLE "enterprise_payments" ADD COLUMN IF NOT EXISTS "reviewed_by" bigint;
+ALTER TABLE "enterprise_payments" ADD COLUMN IF NOT EXISTS "reviewed_at" timestamptz;
+ALTER TABLE "enterprise_payments" ADD COLUMN IF NOT EXISTS "review_note" text;
+
+CREATE TABLE IF NOT EXISTS "enterprise_approval_rules" (
+    "id" bigserial,
+    "enterprise_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "wallet_id" bigint,
+    "category" varchar(50),
+    "min_amount" decimal NOT NULL DEFAULT 0,
+    "approver_role" varchar(20) NOT NULL,
+    "active" boolean NOT NULL DEFAULT true,
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_enterprise_approval_rules_enterprise_id" ON "enterprise_approval_rules" ("enterprise_id");
+
+CREATE TABLE IF NOT EXISTS "enterprise_payment_events" (
+    "id" bigserial,
+    "enterprise_id" bigint NOT NULL,
+    "payment_id" bigint NOT NULL,
+    "actor_id" bigint NOT NULL,
+    "action" varchar(30) NOT NULL,
+    "note" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_enterprise_payment_events_enterprise_id" ON "enterprise_payment_events" ("enterprise_id");
+CREATE INDEX IF NOT EXISTS "idx_enterprise_payment_events_payment_id" ON "enterprise_payment_events" ("payment_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "enterprise_payment_events";
+DROP TABLE IF EXISTS "enterprise_approval_rules";
+ALTER TABLE "enterprise_payments" DROP COLUMN IF EXISTS "review_note";
+ALTER TABLE "enterprise_payments" DROP COLUMN IF EXISTS "reviewed_at";
+ALTER TABLE "enterprise_payments" DROP COLUMN IF EXISTS "reviewed_by";
+ALTER TABLE "enterprise_payments" DROP COLUMN IF EXISTS "approver_role";
+ALTER TABLE "enterprise_payments" DROP COLUMN IF EXISTS "rule_id";
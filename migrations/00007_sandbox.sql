@@ -0,0 +1,69 @@
+-- Sandbox mode: a second merchant API key whose requests run against
+-- isolated test customers, charges and refunds instead of real wallets.
+
+-- +goose Up
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "sandbox_api_key" text;
+CREATE INDEX IF NOT EXISTS "idx_merchants_sandbox_api_key" ON "merchants" ("sandbox_api_key");
+
+ALTER TABLE "webhook_deliveries" ADD COLUMN IF NOT EXISTS "sandbox" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS "sandbox_customers" (
+    "id" bigserial,
+    "merchant_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "email" text,
+    "balance" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sandbox_customers_merchant_id" ON "sandbox_customers" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "sandbox_charges" (
+    "id" bigserial,
+    "reference" varchar(40) NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "customer_id" bigint,
+    "amount" decimal NOT NULL,
+    "refunded_amount" decimal NOT NULL DEFAULT 0,
+    "currency" text DEFAULT 'USD',
+    "source" varchar(10) NOT NULL,
+    "card_last4" varchar(4),
+    "status" varchar(20) NOT NULL,
+    "failure_code" varchar(40),
+    "failure_message" text,
+    "provider_reference" text,
+    "description" text,
+    "metadata" jsonb,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sandbox_charges_reference" ON "sandbox_charges" ("reference");
+CREATE INDEX IF NOT EXISTS "idx_sandbox_charges_merchant_created" ON "sandbox_charges" ("merchant_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_sandbox_charges_status" ON "sandbox_charges" ("status");
+
+CREATE TABLE IF NOT EXISTS "sandbox_refunds" (
+    "id" bigserial,
+    "reference" varchar(40) NOT NULL,
+    "merchant_id" bigint NOT NULL,
+    "charge_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "failure_code" varchar(40),
+    "reason" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sandbox_refunds_reference" ON "sandbox_refunds" ("reference");
+CREATE INDEX IF NOT EXISTS "idx_sandbox_refunds_merchant_id" ON "sandbox_refunds" ("merchant_id");
+CREATE INDEX IF NOT EXISTS "idx_sandbox_refunds_charge_id" ON "sandbox_refunds" ("charge_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "sandbox_refunds";
+DROP TABLE IF EXISTS "sandbox_charges";
+DROP TABLE IF EXISTS "sandbox_customers";
+ALTER TABLE "webhook_deliveries" DROP COLUMN IF EXISTS "sandbox";
+DROP INDEX IF EXISTS "idx_merchants_sandbox_api_key";
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "sandbox_api_key";
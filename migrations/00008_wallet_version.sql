@@ -0,0 +1,32 @@
+-- Wallet versions: every change to a wallet's balance or status bumps its
+-- version, so cached copies can be ordered and stale ones detected. A
+-- trigger does the bump so no write path can forget it.
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 0;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "bump_wallet_version"() RETURNS trigger AS $$
+BEGIN
+    IF NEW."balance" IS DISTINCT FROM OLD."balance"
+        OR NEW."status" IS DISTINCT FROM OLD."status"
+        OR NEW."status_reason" IS DISTINCT FROM OLD."status_reason"
+        OR NEW."currency" IS DISTINCT FROM OLD."currency" THEN
+        NEW."version" := OLD."version" + 1;
+    ELSE
+        -- Saves of a stale copy must not move the version back
+        NEW."version" := OLD."version";
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS "wallets_bump_version" ON "wallets";
+CREATE TRIGGER "wallets_bump_version" BEFORE UPDATE ON "wallets"
+    FOR EACH ROW EXECUTE FUNCTION "bump_wallet_version"();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS "wallets_bump_version" ON "wallets";
+DROP FUNCTION IF EXISTS "bump_wallet_version"();
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "version";
@@ -0,0 +1,20 @@
+-- Per-subject overrides of the configured rate limits, so admins can give a
+-- busy merchant a bigger budget or throttle an abusive user.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "rate_limit_overrides" (
+    "id" bigserial,
+    "subject_type" varchar(10) NOT NULL,
+    "subject_id" bigint NOT NULL,
+    "class" varchar(10) NOT NULL,
+    "limit" integer NOT NULL,
+    "reason" text,
+    "updated_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_rate_limit_overrides_subject_class" ON "rate_limit_overrides" ("subject_type","subject_id","class");
+
+-- +goose Down
+DROP TABLE IF EXISTS "rate_limit_overrides";
@@ -0,0 +1,18 @@
+-- QR code lifecycle: owners can revoke and regenerate their codes, usage
+-- is tracked per code, and a job expires dynamic codes past their date.
+
+-- +goose Up
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "total_amount" decimal NOT NULL DEFAULT 0;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "last_used_at" timestamptz;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "revoked_at" timestamptz;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "revoke_reason" text;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "replaced_by_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_qr_codes_status_expires_at" ON "qr_codes" ("status","expires_at");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_qr_codes_status_expires_at";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "replaced_by_id";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "revoke_reason";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "revoked_at";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "last_used_at";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "total_amount";
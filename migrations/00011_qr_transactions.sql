@@ -0,0 +1,26 @@
+-- Payments made with each QR code, so per-code limits can be enforced and
+-- usage reported without scanning the whole transactions table.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "qr_transactions" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "qr_code_id" bigint,
+    "transaction_id" bigint,
+    "customer_id" bigint,
+    "amount" decimal,
+    "status" text DEFAULT 'pending',
+    "completed_at" timestamptz,
+    "failure_reason" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_qr_transactions_transaction_id" UNIQUE ("transaction_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_qr_transactions_qr_code_id" ON "qr_transactions" ("qr_code_id");
+CREATE INDEX IF NOT EXISTS "idx_qr_transactions_customer_id" ON "qr_transactions" ("customer_id");
+CREATE INDEX IF NOT EXISTS "idx_qr_transactions_deleted_at" ON "qr_transactions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_qr_transactions_qr_code_created" ON "qr_transactions" ("qr_code_id","created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "qr_transactions";
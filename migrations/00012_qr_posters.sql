@@ -0,0 +1,18 @@
+-- Merchant QR posters: printable static codes tied to a till or location,
+-- with preset amounts and a reference stamped on their payments.
+
+-- +goose Up
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "label" text;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "location" text;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "terminal_id" bigint;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "preset_amounts" jsonb;
+ALTER TABLE "qr_codes" ADD COLUMN IF NOT EXISTS "reference" text;
+CREATE INDEX IF NOT EXISTS "idx_qr_codes_terminal_id" ON "qr_codes" ("terminal_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_qr_codes_terminal_id";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "reference";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "preset_amounts";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "terminal_id";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "location";
+ALTER TABLE "qr_codes" DROP COLUMN IF EXISTS "label";
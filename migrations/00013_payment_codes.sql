@@ -0,0 +1,25 @@
+-- Numeric one-time payment codes, redeemed once by a merchant at the
+-- charge endpoint. Codes are stored as keyed hashes only.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "payment_codes" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "code_hash" varchar(64) NOT NULL,
+    "amount" decimal NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'active',
+    "expires_at" timestamptz NOT NULL,
+    "merchant_user_id" bigint,
+    "transaction_id" bigint,
+    "redeemed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payment_codes_user_id" ON "payment_codes" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_payment_codes_code_hash" ON "payment_codes" ("code_hash");
+CREATE INDEX IF NOT EXISTS "idx_payment_codes_status" ON "payment_codes" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payment_codes_merchant_user_id" ON "payment_codes" ("merchant_user_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "payment_codes";
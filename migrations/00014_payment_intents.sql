@@ -0,0 +1,26 @@
+-- Payment intents: signed payment requests shared as links or NFC tags,
+-- each paid at most once before it expires.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "payment_intents" (
+    "id" bigserial,
+    "recipient_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" varchar(3) NOT NULL DEFAULT 'USD',
+    "description" text,
+    "reference" varchar(64),
+    "status" varchar(20) NOT NULL DEFAULT 'open',
+    "expires_at" timestamptz NOT NULL,
+    "payer_id" bigint,
+    "transaction_id" bigint,
+    "paid_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_recipient_id" ON "payment_intents" ("recipient_id");
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_status" ON "payment_intents" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_payer_id" ON "payment_intents" ("payer_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "payment_intents";
@@ -0,0 +1,23 @@
+-- Reversals of completed transactions: the compensating transaction, who
+-- ordered it and why. Each transaction can be reversed once.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "transaction_reversals" (
+    "id" bigserial,
+    "original_transaction_id" bigint NOT NULL,
+    "reversal_transaction_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "fee_returned" decimal NOT NULL DEFAULT 0,
+    "reason" text NOT NULL,
+    "reversed_by" bigint NOT NULL,
+    "actor" varchar(20) NOT NULL,
+    "operator_id" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_transaction_reversals_original_transaction_id" UNIQUE ("original_transaction_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_transaction_reversals_reversal_transaction_id" ON "transaction_reversals" ("reversal_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_transaction_reversals_reversed_by" ON "transaction_reversals" ("reversed_by");
+
+-- +goose Down
+DROP TABLE IF EXISTS "transaction_reversals";
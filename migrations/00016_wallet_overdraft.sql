@@ -0,0 +1,45 @@
+-- Per-wallet overdraft limits, and an index over the overdrawn wallets the
+-- admin report and recovery sweep look for. A limit change bumps the
+-- wallet's version like a balance change, so cached copies pick it up.
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "overdraft_limit" decimal NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_wallets_negative_balance" ON "wallets" ("balance") WHERE "balance" < 0;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "bump_wallet_version"() RETURNS trigger AS $$
+BEGIN
+    IF NEW."balance" IS DISTINCT FROM OLD."balance"
+        OR NEW."status" IS DISTINCT FROM OLD."status"
+        OR NEW."status_reason" IS DISTINCT FROM OLD."status_reason"
+        OR NEW."currency" IS DISTINCT FROM OLD."currency"
+        OR NEW."overdraft_limit" IS DISTINCT FROM OLD."overdraft_limit" THEN
+        NEW."version" := OLD."version" + 1;
+    ELSE
+        -- Saves of a stale copy must not move the version back
+        NEW."version" := OLD."version";
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "bump_wallet_version"() RETURNS trigger AS $$
+BEGIN
+    IF NEW."balance" IS DISTINCT FROM OLD."balance"
+        OR NEW."status" IS DISTINCT FROM OLD."status"
+        OR NEW."status_reason" IS DISTINCT FROM OLD."status_reason"
+        OR NEW."currency" IS DISTINCT FROM OLD."currency" THEN
+        NEW."version" := OLD."version" + 1;
+    ELSE
+        NEW."version" := OLD."version";
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS "idx_wallets_negative_balance";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "overdraft_limit";
@@ -0,0 +1,91 @@
+-- Scoped wallet locks: the wallet carries its active lock's scope and
+-- expiry, wallet_locks keeps every lock placed and lifted, and
+-- wallet_lock_events audits them along with each operation a lock refused.
+-- A scope or expiry change bumps the wallet's version.
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "lock_scope" varchar(10) DEFAULT '';
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "locked_until" timestamptz;
+-- Locks from before scopes existed stop everything
+UPDATE "wallets" SET "lock_scope" = 'full' WHERE "status" = 'locked';
+
+CREATE TABLE IF NOT EXISTS "wallet_locks" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "scope" varchar(10) NOT NULL,
+    "reason" varchar(20) NOT NULL,
+    "note" text,
+    "locked_by" bigint,
+    "expires_at" timestamptz,
+    "released_at" timestamptz,
+    "released_by" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_wallet_locks_wallet_id" ON "wallet_locks" ("wallet_id");
+CREATE INDEX IF NOT EXISTS "idx_wallet_locks_user_id" ON "wallet_locks" ("user_id");
+-- One active lock per wallet
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wallet_locks_active" ON "wallet_locks" ("wallet_id") WHERE "released_at" IS NULL;
+-- Give existing locks a record so admins can lift them; their free-text
+-- reason is kept as the note
+INSERT INTO "wallet_locks" ("wallet_id", "user_id", "scope", "reason", "note", "created_at")
+SELECT "id", "user_id", 'full', 'fraud', "status_reason", NOW() FROM "wallets" WHERE "status" = 'locked';
+UPDATE "wallets" SET "status_reason" = 'fraud' WHERE "status" = 'locked';
+
+CREATE TABLE IF NOT EXISTS "wallet_lock_events" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "lock_id" bigint,
+    "event" varchar(20) NOT NULL,
+    "operation" varchar(30),
+    "direction" varchar(10),
+    "amount" decimal,
+    "actor_id" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_wallet_lock_events_wallet_created" ON "wallet_lock_events" ("wallet_id","created_at");
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "bump_wallet_version"() RETURNS trigger AS $$
+BEGIN
+    IF NEW."balance" IS DISTINCT FROM OLD."balance"
+        OR NEW."status" IS DISTINCT FROM OLD."status"
+        OR NEW."status_reason" IS DISTINCT FROM OLD."status_reason"
+        OR NEW."currency" IS DISTINCT FROM OLD."currency"
+        OR NEW."overdraft_limit" IS DISTINCT FROM OLD."overdraft_limit"
+        OR NEW."lock_scope" IS DISTINCT FROM OLD."lock_scope"
+        OR NEW."locked_until" IS DISTINCT FROM OLD."locked_until" THEN
+        NEW."version" := OLD."version" + 1;
+    ELSE
+        -- Saves of a stale copy must not move the version back
+        NEW."version" := OLD."version";
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "bump_wallet_version"() RETURNS trigger AS $$
+BEGIN
+    IF NEW."balance" IS DISTINCT FROM OLD."balance"
+        OR NEW."status" IS DISTINCT FROM OLD."status"
+        OR NEW."status_reason" IS DISTINCT FROM OLD."status_reason"
+        OR NEW."currency" IS DISTINCT FROM OLD."currency"
+        OR NEW."overdraft_limit" IS DISTINCT FROM OLD."overdraft_limit" THEN
+        NEW."version" := OLD."version" + 1;
+    ELSE
+        NEW."version" := OLD."version";
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS "wallet_lock_events";
+DROP TABLE IF EXISTS "wallet_locks";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "locked_until";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "lock_scope";
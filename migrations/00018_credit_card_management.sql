@@ -0,0 +1,29 @@
+-- Card management: billing details, and ownership verification with a
+-- refundable micro-charge. Top-ups need a verified card.
+
+-- +goose Up
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "billing_name" text DEFAULT '';
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "billing_address" text DEFAULT '';
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "billing_postal_code" text DEFAULT '';
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "billing_country" varchar(2) DEFAULT '';
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "verified_at" timestamptz;
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "verification_attempts" bigint DEFAULT 0;
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "verification_charge_id" text DEFAULT '';
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "verification_amount" decimal DEFAULT 0;
+-- At most one default card per user; keep the oldest where there are more
+UPDATE "credit_cards" SET "is_default" = false
+WHERE "is_default" AND "id" NOT IN (
+    SELECT MIN("id") FROM "credit_cards" WHERE "is_default" GROUP BY "user_id"
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_credit_cards_default" ON "credit_cards" ("user_id") WHERE "is_default";
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_credit_cards_default";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "verification_amount";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "verification_charge_id";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "verification_attempts";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "verified_at";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "billing_country";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "billing_postal_code";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "billing_address";
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "billing_name";
@@ -0,0 +1,36 @@
+-- Card token vault: processor tokens move off credit_cards into an
+-- encrypted store that logs every access. Cards keep an opaque reference;
+-- the tokens already on cards are moved in at startup, which clears
+-- card_number.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "vaulted_cards" (
+    "id" bigserial PRIMARY KEY,
+    "reference" varchar(40) NOT NULL,
+    "user_id" bigint NOT NULL,
+    "ciphertext" text NOT NULL,
+    "brand" text,
+    "last_four" varchar(4),
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_vaulted_cards_reference" ON "vaulted_cards" ("reference");
+CREATE INDEX IF NOT EXISTS "idx_vaulted_cards_user_id" ON "vaulted_cards" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "vault_accesses" (
+    "id" bigserial PRIMARY KEY,
+    "reference" varchar(40) NOT NULL,
+    "user_id" bigint NOT NULL,
+    "action" varchar(20) NOT NULL,
+    "purpose" varchar(50) NOT NULL,
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_vault_accesses_reference" ON "vault_accesses" ("reference");
+
+ALTER TABLE "credit_cards" ADD COLUMN IF NOT EXISTS "vault_ref" text DEFAULT '';
+ALTER TABLE "credit_cards" ALTER COLUMN "card_number" DROP NOT NULL;
+
+-- +goose Down
+-- Tokens already moved into the vault aren't copied back
+ALTER TABLE "credit_cards" DROP COLUMN IF EXISTS "vault_ref";
+DROP TABLE IF EXISTS "vault_accesses";
+DROP TABLE IF EXISTS "vaulted_cards";
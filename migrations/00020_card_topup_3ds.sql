@@ -0,0 +1,27 @@
+-- 3-D Secure for card top-ups: each card top-up is recorded with its
+-- processor charge, and one held for a challenge is credited only when the
+-- processor's webhook reports it passed.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "card_top_ups" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "card_id" bigint NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "charge_id" text NOT NULL,
+    "amount" decimal NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "step_up_reason" varchar(20),
+    "client_secret" text,
+    "next_action_url" text,
+    "failure_reason" text,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_card_top_ups_charge_id" ON "card_top_ups" ("charge_id");
+CREATE INDEX IF NOT EXISTS "idx_card_top_ups_user_id" ON "card_top_ups" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_card_top_ups_status" ON "card_top_ups" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "card_top_ups";
@@ -0,0 +1,45 @@
+-- Merchant API access controls: source IP ranges each API key may be used
+-- from, client certificates for merchants requiring mutual TLS, and a log
+-- of the requests refused by either.
+
+-- +goose Up
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "require_mtls" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS "merchant_ip_ranges" (
+    "id" bigserial PRIMARY KEY,
+    "merchant_id" bigint NOT NULL,
+    "cidr" varchar(50) NOT NULL,
+    "key_mode" varchar(10) NOT NULL,
+    "label" varchar(100),
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_ip_ranges_merchant_id" ON "merchant_ip_ranges" ("merchant_id");
+
+CREATE TABLE IF NOT EXISTS "merchant_client_certs" (
+    "id" bigserial PRIMARY KEY,
+    "merchant_id" bigint NOT NULL,
+    "fingerprint" varchar(64) NOT NULL,
+    "subject" text,
+    "not_after" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchant_client_cert" ON "merchant_client_certs" ("merchant_id", "fingerprint");
+
+CREATE TABLE IF NOT EXISTS "merchant_api_rejections" (
+    "id" bigserial PRIMARY KEY,
+    "merchant_id" bigint NOT NULL,
+    "key_mode" varchar(10) NOT NULL,
+    "reason" varchar(30) NOT NULL,
+    "ip" varchar(50),
+    "method" varchar(10),
+    "path" text,
+    "fingerprint" varchar(64),
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_api_rejections_recent" ON "merchant_api_rejections" ("merchant_id", "created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "merchant_api_rejections";
+DROP TABLE IF EXISTS "merchant_client_certs";
+DROP TABLE IF EXISTS "merchant_ip_ranges";
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "require_mtls";
@@ -0,0 +1,25 @@
+-- Login history: where each sign-in came from, so logins from a new
+-- country or device can be challenged and alerted, and reported by the
+-- account holder from the alert.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "login_events" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "ip" varchar(50),
+    "country" varchar(2),
+    "device_id" varchar(64),
+    "user_agent" text,
+    "new_country" boolean NOT NULL DEFAULT false,
+    "new_device" boolean NOT NULL DEFAULT false,
+    "status" varchar(20) NOT NULL,
+    "report_token_hash" varchar(64),
+    "reported_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_login_events_user" ON "login_events" ("user_id", "created_at");
+CREATE INDEX IF NOT EXISTS "idx_login_events_device_id" ON "login_events" ("device_id");
+CREATE INDEX IF NOT EXISTS "idx_login_events_report_token_hash" ON "login_events" ("report_token_hash");
+
+-- +goose Down
+DROP TABLE IF EXISTS "login_events";
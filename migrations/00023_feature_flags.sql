@@ -0,0 +1,15 @@
+-- Feature flags: admin overrides turning a flag on or off at runtime, over
+-- its default for the environment and the configuration.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "feature_flag_overrides" (
+    "key" varchar(50) PRIMARY KEY,
+    "enabled" boolean NOT NULL,
+    "reason" text,
+    "updated_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "feature_flag_overrides";
@@ -0,0 +1,24 @@
+-- Transaction categorization: what chose each transaction's category, and
+-- users' rules for categorizing their future payments. Transactions from
+-- before keep an empty category_source until the backfill job reaches them.
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "category_source" varchar(10) NOT NULL DEFAULT '';
+ALTER TABLE "archived_transactions" ADD COLUMN IF NOT EXISTS "category_source" varchar(10) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "idx_transactions_uncategorized" ON "transactions" ("id") WHERE "category_source" = '';
+
+CREATE TABLE IF NOT EXISTS "category_rules" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "receiver_id" bigint DEFAULT 0,
+    "pattern" varchar(100),
+    "category" varchar(50) NOT NULL,
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_category_rules_user_id" ON "category_rules" ("user_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "category_rules";
+DROP INDEX IF EXISTS "idx_transactions_uncategorized";
+ALTER TABLE "archived_transactions" DROP COLUMN IF EXISTS "category_source";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "category_source";
@@ -0,0 +1,44 @@
+-- Auto top-up: per-user rules charging a card when payments take the
+-- balance below a threshold, and the top-ups they set off. A payment sets
+-- off at most one top-up, enforced by the unique (rule_id,
+-- trigger_transaction_id) index.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "auto_top_up_rules" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "card_id" bigint DEFAULT 0,
+    "threshold" decimal NOT NULL,
+    "amount" decimal NOT NULL,
+    "daily_cap" decimal DEFAULT 0,
+    "monthly_cap" decimal DEFAULT 0,
+    "enabled" boolean DEFAULT true,
+    "checked_after_id" bigint DEFAULT 0,
+    "consecutive_failures" bigint DEFAULT 0,
+    "last_failure" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_auto_top_up_rules_user_id" ON "auto_top_up_rules" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_auto_top_up_rules_enabled" ON "auto_top_up_rules" ("enabled");
+
+CREATE TABLE IF NOT EXISTS "auto_top_up_runs" (
+    "id" bigserial PRIMARY KEY,
+    "rule_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "trigger_transaction_id" bigint NOT NULL,
+    "balance" decimal,
+    "amount" decimal NOT NULL,
+    "card_id" bigint,
+    "status" varchar(20) NOT NULL,
+    "card_top_up_id" bigint,
+    "failure_reason" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_auto_top_up_trigger" ON "auto_top_up_runs" ("rule_id", "trigger_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_auto_top_up_runs_user_id" ON "auto_top_up_runs" ("user_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "auto_top_up_runs";
+DROP TABLE IF EXISTS "auto_top_up_rules";
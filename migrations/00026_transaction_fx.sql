@@ -0,0 +1,23 @@
+-- Currency conversions: the exact rate applied to a transaction moving
+-- money between currencies, the provider it came from, and the amount
+-- credited in the other currency.
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "fx_rate" decimal(20,8) NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "fx_provider" varchar(20) NOT NULL DEFAULT '';
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "converted_amount" decimal NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "converted_currency" varchar(3) NOT NULL DEFAULT '';
+ALTER TABLE "archived_transactions" ADD COLUMN IF NOT EXISTS "fx_rate" decimal(20,8) NOT NULL DEFAULT 0;
+ALTER TABLE "archived_transactions" ADD COLUMN IF NOT EXISTS "fx_provider" varchar(20) NOT NULL DEFAULT '';
+ALTER TABLE "archived_transactions" ADD COLUMN IF NOT EXISTS "converted_amount" decimal NOT NULL DEFAULT 0;
+ALTER TABLE "archived_transactions" ADD COLUMN IF NOT EXISTS "converted_currency" varchar(3) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE "archived_transactions" DROP COLUMN IF EXISTS "converted_currency";
+ALTER TABLE "archived_transactions" DROP COLUMN IF EXISTS "converted_amount";
+ALTER TABLE "archived_transactions" DROP COLUMN IF EXISTS "fx_provider";
+ALTER TABLE "archived_transactions" DROP COLUMN IF EXISTS "fx_rate";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "converted_currency";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "converted_amount";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "fx_provider";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "fx_rate";
@@ -0,0 +1,38 @@
+-- Merchant risk monitoring: each merchant's risk status, the limit to give
+-- back once an admin clears them, and the history of assessments that
+-- scored and restricted them.
+
+-- +goose Up
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "risk_status" varchar(20) DEFAULT 'normal';
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "risk_restore_limit" decimal;
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "risk_assessed_at" timestamptz;
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "risk_cleared_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_merchants_risk_status" ON "merchants" ("risk_status");
+
+CREATE TABLE IF NOT EXISTS "merchant_risk_assessments" (
+    "id" bigserial PRIMARY KEY,
+    "merchant_user_id" bigint NOT NULL,
+    "score" bigint,
+    "charge_count" bigint,
+    "charge_volume" decimal,
+    "chargeback_rate" decimal,
+    "refund_rate" decimal,
+    "volume_spike" decimal,
+    "reasons" text,
+    "action" varchar(20) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "limit_before" decimal,
+    "limit_after" decimal,
+    "note" text,
+    "reviewed_by" bigint,
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_risk_assessments_merchant_user_id" ON "merchant_risk_assessments" ("merchant_user_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "merchant_risk_assessments";
+DROP INDEX IF EXISTS "idx_merchants_risk_status";
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "risk_cleared_at";
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "risk_assessed_at";
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "risk_restore_limit";
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "risk_status";
@@ -0,0 +1,41 @@
+-- Bulk admin operations: wallet locks, suspensions and QR code expiry
+-- applied to many targets by a background job, with one item per target
+-- recording what happened to it.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "bulk_operations" (
+    "id" bigserial PRIMARY KEY,
+    "action" varchar(30) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "requested_by" bigint NOT NULL,
+    "reason" text NOT NULL,
+    "source" varchar(10) NOT NULL,
+    "params" jsonb,
+    "filter" jsonb,
+    "total" bigint NOT NULL,
+    "processed" bigint DEFAULT 0,
+    "succeeded" bigint DEFAULT 0,
+    "failed" bigint DEFAULT 0,
+    "skipped" bigint DEFAULT 0,
+    "lease_until" timestamptz,
+    "started_at" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_bulk_operations_status" ON "bulk_operations" ("status");
+CREATE INDEX IF NOT EXISTS "idx_bulk_operations_requested_by" ON "bulk_operations" ("requested_by");
+
+CREATE TABLE IF NOT EXISTS "bulk_operation_items" (
+    "id" bigserial PRIMARY KEY,
+    "operation_id" bigint NOT NULL,
+    "target_id" bigint NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "error" text,
+    "processed_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_bulk_operation_target" ON "bulk_operation_items" ("operation_id", "target_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "bulk_operation_items";
+DROP TABLE IF EXISTS "bulk_operations";
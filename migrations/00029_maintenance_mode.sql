@@ -0,0 +1,19 @@
+-- Maintenance mode: a single row switching payment-mutating endpoints to
+-- 503 platform-wide, with the announcement shown to clients and the users
+-- still allowed to make changes.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "maintenance_modes" (
+    "id" bigserial PRIMARY KEY,
+    "enabled" boolean NOT NULL DEFAULT false,
+    "message" text,
+    "retry_after" bigint NOT NULL DEFAULT 0,
+    "ends_at" timestamptz,
+    "allowed_user_ids" jsonb,
+    "updated_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "maintenance_modes";
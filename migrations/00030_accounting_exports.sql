@@ -0,0 +1,26 @@
+-- Accounting exports: one row per day and journal format, recording its
+-- totals and delivery so the daily job exports each day once.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "accounting_exports" (
+    "id" bigserial PRIMARY KEY,
+    "date" date NOT NULL,
+    "format" varchar(20) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "entries" bigint NOT NULL DEFAULT 0,
+    "debits" decimal(20,2) NOT NULL DEFAULT 0,
+    "credits" decimal(20,2) NOT NULL DEFAULT 0,
+    "file_name" text,
+    "location" text,
+    "error" text,
+    "requested_by" bigint,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_accounting_exports_day" ON "accounting_exports" ("date", "format");
+CREATE INDEX IF NOT EXISTS "idx_accounting_exports_status" ON "accounting_exports" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "accounting_exports";
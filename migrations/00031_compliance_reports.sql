@@ -0,0 +1,53 @@
+-- Compliance reports: large transaction (CTR) and suspicious activity
+-- (SAR) report drafts for compliance officers, with the transactions each
+-- one covers. A transaction is in at most one report of each type.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "compliance_reports" (
+    "id" bigserial PRIMARY KEY,
+    "type" varchar(10) NOT NULL,
+    "reason" varchar(30) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'draft',
+    "subject_user_id" bigint NOT NULL,
+    "subject_name" text,
+    "subject_email" text,
+    "subject_phone" text,
+    "subject_country" varchar(2),
+    "subject_kyc_status" varchar(20),
+    "total_amount" decimal(20,2) NOT NULL,
+    "currency" varchar(3) NOT NULL,
+    "transaction_count" bigint NOT NULL,
+    "period_start" timestamptz,
+    "period_end" timestamptz,
+    "narrative" text,
+    "assigned_to" bigint,
+    "filing_reference" text,
+    "review_note" text,
+    "reviewed_by" bigint,
+    "reviewed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_compliance_reports_type" ON "compliance_reports" ("type");
+CREATE INDEX IF NOT EXISTS "idx_compliance_reports_status" ON "compliance_reports" ("status");
+CREATE INDEX IF NOT EXISTS "idx_compliance_reports_subject_user_id" ON "compliance_reports" ("subject_user_id");
+CREATE INDEX IF NOT EXISTS "idx_compliance_reports_assigned_to" ON "compliance_reports" ("assigned_to");
+
+CREATE TABLE IF NOT EXISTS "compliance_report_transactions" (
+    "id" bigserial PRIMARY KEY,
+    "report_id" bigint NOT NULL,
+    "report_type" varchar(10) NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "type" text,
+    "amount" decimal(20,2) NOT NULL,
+    "currency" varchar(3),
+    "sender_id" bigint,
+    "receiver_id" bigint,
+    "occurred_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_compliance_report_transactions_report_id" ON "compliance_report_transactions" ("report_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_compliance_report_transaction" ON "compliance_report_transactions" ("report_type", "transaction_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "compliance_report_transactions";
+DROP TABLE IF EXISTS "compliance_reports";
@@ -0,0 +1,24 @@
+-- Tax year summaries generated in the background for accounts with too
+-- many transactions to summarize during a request, kept for download.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "tax_summary_reports" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "year" bigint NOT NULL,
+    "format" varchar(10) NOT NULL,
+    "language" varchar(10),
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "file_name" text,
+    "content" bytea,
+    "error" text,
+    "lease_until" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_tax_summary_reports_user_id" ON "tax_summary_reports" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_tax_summary_reports_status" ON "tax_summary_reports" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "tax_summary_reports";
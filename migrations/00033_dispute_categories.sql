@@ -0,0 +1,15 @@
+-- Dispute filing: the category a customer files under, how much of the
+-- payment they dispute and references to what backs them up, plus an
+-- index for listing a customer's own disputes.
+
+-- +goose Up
+ALTER TABLE "disputes" ADD COLUMN IF NOT EXISTS "category" varchar(30) DEFAULT 'other';
+ALTER TABLE "disputes" ADD COLUMN IF NOT EXISTS "disputed_amount" decimal(20,2) DEFAULT 0;
+ALTER TABLE "disputes" ADD COLUMN IF NOT EXISTS "attachments" jsonb;
+CREATE INDEX IF NOT EXISTS "idx_disputes_user_id" ON "disputes" ("user_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_disputes_user_id";
+ALTER TABLE "disputes" DROP COLUMN IF EXISTS "attachments";
+ALTER TABLE "disputes" DROP COLUMN IF EXISTS "disputed_amount";
+ALTER TABLE "disputes" DROP COLUMN IF EXISTS "category";
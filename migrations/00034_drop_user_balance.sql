@@ -0,0 +1,22 @@
+-- Drops users.balance. Money lives in wallets; the only writer of this
+-- column was the merchant dispute refund, which now moves money between
+-- wallets like every other payment. That refund had credit and debit the
+-- wrong way round, so what it left here is not moved into wallets. Any
+-- non-zero balance is kept in legacy_user_balances for finance to
+-- reconcile by hand.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "legacy_user_balances" (
+    "user_id" bigint PRIMARY KEY,
+    "balance" decimal NOT NULL,
+    "recorded_at" timestamptz NOT NULL DEFAULT now()
+);
+INSERT INTO "legacy_user_balances" ("user_id", "balance")
+SELECT "id", "balance" FROM "users" WHERE "balance" IS NOT NULL AND "balance" <> 0
+ON CONFLICT ("user_id") DO NOTHING;
+ALTER TABLE "users" DROP COLUMN IF EXISTS "balance";
+
+-- +goose Down
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "balance" decimal DEFAULT 0;
+UPDATE "users" SET "balance" = l."balance" FROM "legacy_user_balances" l WHERE l."user_id" = "users"."id";
+DROP TABLE IF EXISTS "legacy_user_balances";
@@ -0,0 +1,36 @@
+-- Sagas record the steps of multi-step payments so failed ones are undone
+-- reliably, by the job when compensating right away fails.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "sagas" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(50) NOT NULL,
+    "transaction_id" bigint,
+    "status" varchar(20) NOT NULL DEFAULT 'running',
+    "error" text,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    "lease_until" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_sagas_transaction_id" ON "sagas" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_sagas_status" ON "sagas" ("status");
+
+CREATE TABLE IF NOT EXISTS "saga_steps" (
+    "id" bigserial PRIMARY KEY,
+    "saga_id" bigint NOT NULL,
+    "position" bigint NOT NULL,
+    "name" varchar(50) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "compensation" varchar(50),
+    "params" jsonb,
+    "error" text,
+    "completed_at" timestamptz,
+    "compensated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_saga_steps_saga_id" ON "saga_steps" ("saga_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "saga_steps";
+DROP TABLE IF EXISTS "sagas";
@@ -0,0 +1,8 @@
+-- Pending transaction expiry: the cleanup job and the admin view look up
+-- transactions still pending by age.
+
+-- +goose Up
+CREATE INDEX IF NOT EXISTS "idx_transactions_pending_created" ON "transactions" ("created_at") WHERE "status" = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_transactions_pending_created";
@@ -0,0 +1,28 @@
+-- Payment confirmations: both parties of a completed payment are told about
+-- it by email or push, unless they opted out. Confirmations are queued so a
+-- busy merchant's can go out as one digest.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "confirmation_settings" (
+    "user_id" bigint PRIMARY KEY,
+    "channel" varchar(10) NOT NULL DEFAULT 'email',
+    "opt_out" boolean NOT NULL DEFAULT false,
+    "updated_at" timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS "payment_confirmations" (
+    "id" bigserial PRIMARY KEY,
+    "transaction_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "role" varchar(10) NOT NULL,
+    "channel" varchar(10) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "sent_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payment_confirmations_tx_user" ON "payment_confirmations" ("transaction_id", "user_id");
+CREATE INDEX IF NOT EXISTS "idx_payment_confirmations_status" ON "payment_confirmations" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "payment_confirmations";
+DROP TABLE IF EXISTS "confirmation_settings";
@@ -0,0 +1,9 @@
+-- Merchant fee plans: the pricing tier a merchant's payments are charged
+-- at. A non-zero processing_fee_rate is their negotiated rate and replaces
+-- the plan's.
+
+-- +goose Up
+ALTER TABLE "merchants" ADD COLUMN IF NOT EXISTS "fee_plan" varchar(20) DEFAULT 'standard';
+
+-- +goose Down
+ALTER TABLE "merchants" DROP COLUMN IF EXISTS "fee_plan";
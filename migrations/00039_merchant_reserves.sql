@@ -0,0 +1,36 @@
+-- Rolling reserves: a share of each payment a merchant receives is held on
+-- their wallet for a number of days, per merchant or by default, and
+-- released on schedule.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "merchant_reserves" (
+    "merchant_user_id" bigint PRIMARY KEY,
+    "percent" decimal NOT NULL DEFAULT 0,
+    "hold_days" bigint NOT NULL DEFAULT 0,
+    "source" varchar(10) NOT NULL,
+    "reason" text,
+    "restore_percent" decimal,
+    "restore_days" bigint,
+    "restore_source" varchar(10),
+    "updated_at" timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS "reserve_entries" (
+    "id" bigserial PRIMARY KEY,
+    "merchant_user_id" bigint NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "hold_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "status" varchar(10) NOT NULL DEFAULT 'held',
+    "release_at" timestamptz NOT NULL,
+    "released_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reserve_entries_transaction_id" ON "reserve_entries" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_reserve_entries_merchant_user_id" ON "reserve_entries" ("merchant_user_id");
+CREATE INDEX IF NOT EXISTS "idx_reserve_entries_status" ON "reserve_entries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_reserve_entries_release_at" ON "reserve_entries" ("release_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "reserve_entries";
+DROP TABLE IF EXISTS "merchant_reserves";
@@ -0,0 +1,21 @@
+-- Spending controls: users, or organization admins for their members,
+-- block or cap payments to merchant categories and foreign merchants.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "spending_controls" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "enterprise_id" bigint NOT NULL DEFAULT 0,
+    "scope" varchar(20) NOT NULL,
+    "category" varchar(30) NOT NULL DEFAULT '',
+    "action" varchar(10) NOT NULL,
+    "cap_amount" decimal DEFAULT 0,
+    "period" varchar(10),
+    "set_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_spending_control" ON "spending_controls" ("user_id", "enterprise_id", "scope", "category");
+
+-- +goose Down
+DROP TABLE IF EXISTS "spending_controls";
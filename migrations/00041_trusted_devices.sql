@@ -0,0 +1,35 @@
+-- Trusted devices, and the large transfers waiting for their sender to
+-- approve them on one.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "trusted_devices" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "device_id" varchar(64) NOT NULL,
+    "name" varchar(100),
+    "push_token" text,
+    "last_used_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_trusted_device" ON "trusted_devices" ("user_id", "device_id");
+
+CREATE TABLE IF NOT EXISTS "transfer_confirmations" (
+    "id" bigserial PRIMARY KEY,
+    "transaction_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "device_id" varchar(64),
+    "decided_at" timestamptz,
+    "failure_reason" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transfer_confirmations_transaction_id" ON "transfer_confirmations" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_transfer_confirmations_user_id" ON "transfer_confirmations" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_transfer_confirmations_status" ON "transfer_confirmations" ("status");
+CREATE INDEX IF NOT EXISTS "idx_transfer_confirmations_expires_at" ON "transfer_confirmations" ("expires_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "transfer_confirmations";
+DROP TABLE IF EXISTS "trusted_devices";
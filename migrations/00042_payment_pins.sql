@@ -0,0 +1,15 @@
+-- Payment PINs: an optional numeric PIN, separate from the password, for
+-- large transfers and card removal.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "payment_pins" (
+    "user_id" bigint PRIMARY KEY,
+    "pin_hash" text NOT NULL,
+    "failed_attempts" bigint NOT NULL DEFAULT 0,
+    "locked_until" timestamptz,
+    "set_at" timestamptz,
+    "updated_at" timestamptz
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "payment_pins";
@@ -0,0 +1,21 @@
+-- Sessions, one per sign-in, so signing in on one device doesn't end the
+-- sessions on the others.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "sessions" (
+    "id" varchar(32) PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "ip" varchar(50),
+    "country" varchar(2),
+    "device_id" varchar(64),
+    "user_agent" text,
+    "token_version" bigint NOT NULL,
+    "last_used_at" timestamptz,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_sessions_user_id" ON "sessions" ("user_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "sessions";
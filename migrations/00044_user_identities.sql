@@ -0,0 +1,25 @@
+-- Google and Apple accounts users sign in with. Users who signed up with
+-- one have no phone number, so only phone numbers that are set must be
+-- unique.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "user_identities" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "provider" varchar(20) NOT NULL,
+    "subject" varchar(255) NOT NULL,
+    "email" text,
+    "last_login_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_identity" ON "user_identities" ("provider", "subject");
+CREATE INDEX IF NOT EXISTS "idx_user_identities_user_id" ON "user_identities" ("user_id");
+
+DROP INDEX IF EXISTS "idx_users_phone";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone") WHERE "phone" <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_users_phone";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone");
+
+DROP TABLE IF EXISTS "user_identities";
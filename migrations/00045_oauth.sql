@@ -0,0 +1,82 @@
+-- Third-party apps users authorize through OAuth 2.0: the apps, their
+-- authorization codes and tokens, users' consents, and the payments apps
+-- request for users to approve.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "oauth_clients" (
+    "id" bigserial PRIMARY KEY,
+    "client_id" varchar(64) NOT NULL,
+    "secret_hash" varchar(64) NOT NULL,
+    "owner_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "redirect_uris" jsonb,
+    "scopes" jsonb,
+    "status" varchar(20) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_clients_client_id" ON "oauth_clients" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_oauth_clients_owner_id" ON "oauth_clients" ("owner_id");
+
+CREATE TABLE IF NOT EXISTS "oauth_authorization_codes" (
+    "id" bigserial PRIMARY KEY,
+    "code_hash" varchar(64) NOT NULL,
+    "client_id" varchar(64) NOT NULL,
+    "user_id" bigint NOT NULL,
+    "redirect_uri" text NOT NULL,
+    "scope" text NOT NULL,
+    "code_challenge" varchar(64),
+    "expires_at" timestamptz,
+    "used_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_authorization_codes_code_hash" ON "oauth_authorization_codes" ("code_hash");
+
+CREATE TABLE IF NOT EXISTS "oauth_tokens" (
+    "id" bigserial PRIMARY KEY,
+    "client_id" varchar(64) NOT NULL,
+    "user_id" bigint NOT NULL,
+    "scope" text NOT NULL,
+    "access_hash" varchar(64) NOT NULL,
+    "refresh_hash" varchar(64) NOT NULL,
+    "access_expires_at" timestamptz,
+    "refresh_expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_tokens_access_hash" ON "oauth_tokens" ("access_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_tokens_refresh_hash" ON "oauth_tokens" ("refresh_hash");
+CREATE INDEX IF NOT EXISTS "idx_oauth_tokens_grant" ON "oauth_tokens" ("user_id", "client_id");
+
+CREATE TABLE IF NOT EXISTS "oauth_grants" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "client_id" varchar(64) NOT NULL,
+    "scope" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_grant" ON "oauth_grants" ("user_id", "client_id");
+
+CREATE TABLE IF NOT EXISTS "oauth_payment_requests" (
+    "id" bigserial PRIMARY KEY,
+    "client_id" varchar(64) NOT NULL,
+    "user_id" bigint NOT NULL,
+    "recipient_id" bigint NOT NULL,
+    "amount" decimal NOT NULL,
+    "description" text,
+    "status" varchar(20) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "transaction_id" bigint,
+    "decided_at" timestamptz,
+    "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_oauth_payment_requests_user_id" ON "oauth_payment_requests" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_oauth_payment_requests_status" ON "oauth_payment_requests" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "oauth_payment_requests";
+DROP TABLE IF EXISTS "oauth_grants";
+DROP TABLE IF EXISTS "oauth_tokens";
+DROP TABLE IF EXISTS "oauth_authorization_codes";
+DROP TABLE IF EXISTS "oauth_clients";
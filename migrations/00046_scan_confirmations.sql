@@ -0,0 +1,35 @@
+-- Payers can ask to approve the charges merchants take by scanning their
+-- payment code, above an amount of their choosing.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "scan_confirmation_settings" (
+    "user_id" bigint PRIMARY KEY,
+    "enabled" boolean NOT NULL DEFAULT false,
+    "auto_approve_limit" decimal NOT NULL DEFAULT 0,
+    "updated_at" timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS "scan_confirmations" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "merchant_user_id" bigint NOT NULL,
+    "merchant_name" text,
+    "amount" decimal NOT NULL,
+    "description" text,
+    "charge" jsonb,
+    "status" varchar(20) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "transaction_id" bigint,
+    "decided_at" timestamptz,
+    "failure_reason" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_scan_confirmations_user_id" ON "scan_confirmations" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_scan_confirmations_merchant_user_id" ON "scan_confirmations" ("merchant_user_id");
+CREATE INDEX IF NOT EXISTS "idx_scan_confirmations_status" ON "scan_confirmations" ("status");
+CREATE INDEX IF NOT EXISTS "idx_scan_confirmations_expires_at" ON "scan_confirmations" ("expires_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "scan_confirmations";
+DROP TABLE IF EXISTS "scan_confirmation_settings";
@@ -0,0 +1,39 @@
+-- Merchants set a refund policy, and customers ask for refunds that are
+-- paid within it or wait for the merchant.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "refund_policies" (
+    "merchant_user_id" bigint PRIMARY KEY,
+    "enabled" boolean NOT NULL DEFAULT true,
+    "window_days" integer NOT NULL DEFAULT 30,
+    "auto_approve_limit" decimal NOT NULL DEFAULT 0,
+    "updated_at" timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS "refund_requests" (
+    "id" bigserial PRIMARY KEY,
+    "transaction_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "merchant_user_id" bigint NOT NULL,
+    "merchant_name" text,
+    "amount" decimal NOT NULL,
+    "currency" varchar(3),
+    "reason" text,
+    "status" varchar(20) NOT NULL,
+    "auto_approved" boolean NOT NULL DEFAULT false,
+    "decided_by" bigint,
+    "decision_note" text,
+    "decided_at" timestamptz,
+    "refund_transaction_id" bigint,
+    "refunded_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_refund_requests_transaction_id" ON "refund_requests" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_refund_requests_customer_id" ON "refund_requests" ("customer_id");
+CREATE INDEX IF NOT EXISTS "idx_refund_requests_merchant_user_id" ON "refund_requests" ("merchant_user_id");
+CREATE INDEX IF NOT EXISTS "idx_refund_requests_status" ON "refund_requests" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "refund_requests";
+DROP TABLE IF EXISTS "refund_policies";
@@ -1,92 +0,0 @@
--- 001_init_schema.sql
-
-CREATE TABLE IF NOT EXISTS users (
-    id SERIAL PRIMARY KEY,
-    email VARCHAR(255) UNIQUE NOT NULL,
-    phone VARCHAR(20) UNIQUE,
-    password VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    token_version INT DEFAULT 0
-);
-
-CREATE TABLE IF NOT EXISTS wallets (
-    id SERIAL PRIMARY KEY,
-    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
-    balance DECIMAL(20, 2) NOT NULL DEFAULT 0,
-    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS transactions (
-    id SERIAL PRIMARY KEY,
-    transaction_id VARCHAR(255) UNIQUE,
-    type VARCHAR(50),
-    sender_id BIGINT NOT NULL,
-    receiver_id BIGINT NOT NULL,
-    amount DECIMAL(20, 2) NOT NULL,
-    fee DECIMAL(20, 2),
-    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
-    status VARCHAR(50) NOT NULL DEFAULT 'pending',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    CONSTRAINT fk_transactions_sender FOREIGN KEY (sender_id) REFERENCES users (id) ON DELETE CASCADE,
-    CONSTRAINT fk_transactions_receiver FOREIGN KEY (receiver_id) REFERENCES users (id) ON DELETE CASCADE
-);
-
-CREATE TABLE IF NOT EXISTS transaction_history (
-    id SERIAL PRIMARY KEY,
-    wallet_id BIGINT NOT NULL REFERENCES wallets (id) ON DELETE CASCADE,
-    amount DECIMAL(20, 2) NOT NULL,
-    type VARCHAR(50), -- credit/debit
-    balance DECIMAL(20, 2) NOT NULL, -- Balance after transaction
-    description TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS qr_codes (
-    id SERIAL PRIMARY KEY,
-    code VARCHAR(255) UNIQUE NOT NULL,
-    user_id INTEGER NOT NULL REFERENCES users (id),
-    user_type VARCHAR(50) NOT NULL, -- 'regular' or 'merchant'
-    type VARCHAR(50) NOT NULL, -- 'static' or 'dynamic'
-    amount DECIMAL(10, 2),
-    expires_at TIMESTAMP WITH TIME ZONE,
-    max_uses INTEGER NOT NULL DEFAULT 1,
-    usage_count INTEGER NOT NULL DEFAULT 0,
-    status VARCHAR(50) NOT NULL DEFAULT 'active',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS permissions (
-    id SERIAL PRIMARY KEY,
-    name VARCHAR(50) UNIQUE NOT NULL,
-    description TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS payments (
-    id SERIAL PRIMARY KEY,
-    transaction_id BIGINT NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
-    amount DECIMAL(20, 2) NOT NULL,
-    status VARCHAR(50) NOT NULL DEFAULT 'pending',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS merchants (
-    id SERIAL PRIMARY KEY,
-    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
-    business_name VARCHAR(255) NOT NULL,
-    business_type VARCHAR(50) NOT NULL,
-    business_address VARCHAR(255) NOT NULL,
-    daily_transaction_limit DECIMAL(20, 2) NOT NULL DEFAULT 10000,
-    monthly_transaction_limit DECIMAL(20, 2) NOT NULL DEFAULT 100000,
-    min_transaction_amount DECIMAL(20, 2) NOT NULL DEFAULT 1,
-    max_transaction_amount DECIMAL(20, 2) NOT NULL DEFAULT 5000,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
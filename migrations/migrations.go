@@ -0,0 +1,51 @@
+// Package migrations holds the versioned SQL migrations that define the
+// database schema. cmd/migrate applies them in order.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// FS holds the migration files, named <version>_<description>.sql
+//
+//go:embed *.sql
+var FS embed.FS
+
+// NewProvider returns a goose provider that migrates db with FS. Applied
+// versions are recorded in the goose_db_version table.
+func NewProvider(db *sql.DB) (*goose.Provider, error) {
+	return goose.NewProvider(goose.DialectPostgres, db, FS)
+}
+
+// Pending reports whether db is behind the latest migration. Unlike the
+// provider's checks it only reads, so it never creates the version table.
+func Pending(ctx context.Context, db *sql.DB) (bool, error) {
+	provider, err := NewProvider(db)
+	if err != nil {
+		return false, err
+	}
+	sources := provider.ListSources()
+	if len(sources) == 0 {
+		return false, nil
+	}
+	latest := sources[len(sources)-1].Version
+
+	var tracked bool
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('goose_db_version') IS NOT NULL").Scan(&tracked); err != nil {
+		return false, fmt.Errorf("failed to check migration table: %w", err)
+	}
+	if !tracked {
+		return true, nil
+	}
+	var applied int64
+	err = db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied").Scan(&applied)
+	if err != nil {
+		return false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return applied < latest, nil
+}
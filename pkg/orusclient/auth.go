@@ -0,0 +1,111 @@
+package orusclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrMFARequired is returned by Login when the account has two-factor
+// authentication; finish logging in with VerifyOTP
+var ErrMFARequired = errors.New("orusclient: a one-time code is required")
+
+// Tokens authenticate a user. The access token is short-lived; the client
+// uses the refresh token to get a new one when it expires.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginRequest identifies the user by email or phone number
+type LoginRequest struct {
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Password string `json:"password"`
+}
+
+// LoginResult is a logged-in user. When MFARequired is set no tokens were
+// issued and UserID is what VerifyOTP needs.
+type LoginResult struct {
+	Tokens
+	MFARequired bool `json:"mfa_required"`
+	UserID      uint `json:"user_id"`
+	User        struct {
+		ID          uint     `json:"id"`
+		Email       string   `json:"email"`
+		Role        string   `json:"role"`
+		Permissions []string `json:"permissions"`
+	} `json:"user"`
+}
+
+// Login authenticates a user and keeps their tokens for later calls. It
+// returns ErrMFARequired, along with the result, when a one-time code is
+// needed.
+func (c *Client) Login(ctx context.Context, in LoginRequest) (*LoginResult, error) {
+	var out LoginResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/login", body: in}, &out); err != nil {
+		return nil, err
+	}
+	if out.MFARequired {
+		return &out, ErrMFARequired
+	}
+	out.UserID = out.User.ID
+	c.setTokens(out.Tokens)
+	return &out, nil
+}
+
+// VerifyOTP completes a login that returned ErrMFARequired
+func (c *Client) VerifyOTP(ctx context.Context, userID uint, code string) (Tokens, error) {
+	in := struct {
+		UserID uint   `json:"user_id"`
+		Code   string `json:"code"`
+	}{userID, code}
+
+	var out Tokens
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/verify-otp", body: in}, &out); err != nil {
+		return Tokens{}, err
+	}
+	c.setTokens(out)
+	return out, nil
+}
+
+// Refresh exchanges the refresh token for new tokens. Calls do this
+// themselves when the access token has expired.
+func (c *Client) Refresh(ctx context.Context) (Tokens, error) {
+	if err := c.refresh(ctx); err != nil {
+		return Tokens{}, err
+	}
+	return c.Tokens(), nil
+}
+
+func (c *Client) refresh(ctx context.Context) error {
+	stale := c.Tokens()
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if current := c.Tokens(); current.AccessToken != stale.AccessToken {
+		// Another call refreshed while this one waited
+		return nil
+	}
+
+	in := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{stale.RefreshToken}
+	var out struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/refresh", body: in}, &out); err != nil {
+		return err
+	}
+	c.setTokens(Tokens{AccessToken: out.Token, RefreshToken: out.RefreshToken})
+	return nil
+}
+
+// Logout revokes the user's tokens on every device
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/logout", auth: true}, nil); err != nil {
+		return err
+	}
+	c.setTokens(Tokens{})
+	return nil
+}
@@ -0,0 +1,55 @@
+package orusclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Checkout sessions are authenticated with the merchant's API key; see
+// WithAPIKey.
+
+// CreateCheckoutSession opens a checkout session for an online order
+func (c *Client) CreateCheckoutSession(ctx context.Context, in CreateCheckoutSessionRequest) (*CheckoutSession, error) {
+	var out CheckoutSession
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/v1/checkout/sessions", body: in}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCheckoutSession returns a checkout session and its status
+func (c *Client) GetCheckoutSession(ctx context.Context, id string) (*CheckoutSession, error) {
+	var out CheckoutSession
+	if err := c.call(ctx, request{method: http.MethodGet, path: "/api/v1/checkout/sessions/" + url.PathEscape(id)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCheckoutSessions pages through the merchant's checkout sessions,
+// only those with status if it isn't empty
+func (c *Client) ListCheckoutSessions(ctx context.Context, status string, opts ListOptions) ([]CheckoutSession, Page, error) {
+	query := opts.values()
+	if status != "" {
+		query.Set("status", status)
+	}
+	var out struct {
+		Data []CheckoutSession `json:"data"`
+		Meta Page              `json:"meta"`
+	}
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/checkout/sessions", query: query}, &out); err != nil {
+		return nil, Page{}, err
+	}
+	return out.Data, out.Meta, nil
+}
+
+// ExpireCheckoutSession closes a checkout session that hasn't been paid
+func (c *Client) ExpireCheckoutSession(ctx context.Context, id string) (*CheckoutSession, error) {
+	var out CheckoutSession
+	path := "/api/v1/checkout/sessions/" + url.PathEscape(id) + "/expire"
+	if err := c.call(ctx, request{method: http.MethodPost, path: path}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
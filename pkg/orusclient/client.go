@@ -0,0 +1,320 @@
+// Package orusclient is a Go client for the Orus API. It signs requests
+// with a merchant API key or a user's tokens, refreshing the access token
+// when it expires, sends an idempotency key with every write and retries
+// requests that failed for transient reasons.
+//
+//	client := orusclient.New("https://api.orus.example", orusclient.WithAPIKey(key))
+//	session, err := client.CreateCheckoutSession(ctx, orusclient.CreateCheckoutSessionRequest{
+//		Amount:          25,
+//		ClientReference: "order-1042",
+//	})
+package orusclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Client calls the Orus API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	retry      RetryPolicy
+	userAgent  string
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	onRefresh    func(Tokens)
+
+	// refreshMu serializes refreshes so concurrent calls that all find the
+	// access token expired don't each spend the refresh token
+	refreshMu sync.Mutex
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey authenticates server-to-server calls, such as checkout
+// sessions, with a merchant API key
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithTokens authenticates as a user with tokens from an earlier login
+func WithTokens(t Tokens) Option {
+	return func(c *Client) {
+		c.accessToken = t.AccessToken
+		c.refreshToken = t.RefreshToken
+	}
+}
+
+// WithTokenRefreshHandler is called with the new tokens whenever the client
+// refreshes them, so they can be persisted
+func WithTokenRefreshHandler(fn func(Tokens)) Option {
+	return func(c *Client) { c.onRefresh = fn }
+}
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New creates a client for the API at baseURL, e.g. https://api.orus.example
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retry:      DefaultRetryPolicy,
+		userAgent:  "orusclient-go",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Tokens returns the user's current access and refresh tokens
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Tokens{AccessToken: c.accessToken, RefreshToken: c.refreshToken}
+}
+
+func (c *Client) setTokens(t Tokens) {
+	c.mu.Lock()
+	c.accessToken = t.AccessToken
+	c.refreshToken = t.RefreshToken
+	onRefresh := c.onRefresh
+	c.mu.Unlock()
+	if onRefresh != nil {
+		onRefresh(t)
+	}
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey makes the write made with ctx use key instead of a
+// generated one, so retrying it after a crash can't apply it twice
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// request describes one API call
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	// auth is false for calls, like login, made before the user has tokens
+	auth bool
+}
+
+// envelope is the shape of successful responses
+type envelope struct {
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// do sends req, retrying per the retry policy and refreshing the access
+// token once if it has expired, and decodes the response into out
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("orusclient: failed to encode request: %w", err)
+		}
+	}
+
+	// The key is fixed before the first attempt so every retry of this
+	// call carries the same one
+	key := ""
+	if req.method != http.MethodGet {
+		key, _ = ctx.Value(idempotencyKey{}).(string)
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+	}
+
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, body, key)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && req.auth && !refreshed && c.canRefresh() {
+			resp.Body.Close()
+			refreshed = true
+			if err := c.refresh(ctx); err != nil {
+				return err
+			}
+			attempt--
+			continue
+		}
+
+		if c.retry.shouldRetry(attempt, resp, err) {
+			delay := c.retry.delay(attempt, resp)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return decode(resp, out)
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte, key string) (*http.Response, error) {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("orusclient: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		httpReq.Header.Set("Idempotency-Key", key)
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+	if req.auth {
+		if token := c.Tokens().AccessToken; token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+func (c *Client) canRefresh() bool {
+	return c.Tokens().RefreshToken != ""
+}
+
+// decode reads a response, returning an *APIError for failures
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("orusclient: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{Status: resp.StatusCode}
+		var body struct {
+			Error *APIError `json:"error"`
+		}
+		if json.Unmarshal(data, &body) == nil && body.Error != nil {
+			apiErr.Code = body.Error.Code
+			apiErr.Message = body.Error.Message
+			apiErr.Fields = body.Error.Fields
+		} else {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("orusclient: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// call sends req and decodes the data of a {"message", "data"} response
+func (c *Client) call(ctx context.Context, req request, out any) error {
+	var env envelope
+	if err := c.do(ctx, req, &env); err != nil {
+		return err
+	}
+	if out == nil || len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("orusclient: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// APIError is a failed request. Branch on Code, which is stable; Message
+// is meant for people and may change.
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Fields lists the invalid fields of a VALIDATION_FAILED request
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError says why one field of a request was rejected. Field is its
+// JSON path, such as "address.city".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("orus: %d %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("orus: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// IsCode reports whether err is an API error with the given code, such as
+// "INSUFFICIENT_BALANCE"
+func IsCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Without a key the server can't deduplicate, but the call itself
+		// is still valid
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,94 @@
+package orusclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SendMoney pays another user from the user's wallet
+func (c *Client) SendMoney(ctx context.Context, in SendMoneyRequest) (*Transaction, error) {
+	var out Transaction
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/payment/send", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PayQR pays the owner of a scanned QR code
+func (c *Client) PayQR(ctx context.Context, in QRPaymentRequest) (*Transaction, error) {
+	var out Transaction
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/payment/scan", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GeneratePaymentCode issues a one-time code for paying at a till that
+// can't scan QR codes. The code is only returned here.
+func (c *Client) GeneratePaymentCode(ctx context.Context, in PaymentCodeRequest) (*PaymentCode, error) {
+	var out PaymentCode
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/payment/codes", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePaymentIntent asks for a payment to the user, returned with the
+// link and app URI to share it by
+func (c *Client) CreatePaymentIntent(ctx context.Context, in PaymentIntentRequest) (*PaymentIntent, error) {
+	var out PaymentIntent
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/payment/intents", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPaymentIntent previews the payment intent behind a token
+func (c *Client) GetPaymentIntent(ctx context.Context, token string) (*PaymentIntent, error) {
+	var out PaymentIntent
+	if err := c.call(ctx, request{method: http.MethodGet, path: "/api/pay/intents/" + url.PathEscape(token)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConfirmPaymentIntent pays the payment intent behind a token
+func (c *Client) ConfirmPaymentIntent(ctx context.Context, token string) (*Transaction, error) {
+	var out Transaction
+	in := struct {
+		Token string `json:"token"`
+	}{token}
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/payment/intents/confirm", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Charge takes a payment from a customer as the logged-in merchant
+func (c *Client) Charge(ctx context.Context, in ChargeRequest) (*Transaction, error) {
+	var out Transaction
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/merchant/payments/charge", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Refund returns all or part of a charge to the customer
+func (c *Client) Refund(ctx context.Context, in RefundRequest) (*Transaction, error) {
+	var out Transaction
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/merchant/payments/refund", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Reverse undoes a charge taken in error, returning all of it to the
+// customer. Charges with refunds can't be reversed.
+func (c *Client) Reverse(ctx context.Context, in ReverseRequest) (*Reversal, error) {
+	var out Reversal
+	if err := c.call(ctx, request{method: http.MethodPost, path: "/api/merchant/payments/reverse", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,69 @@
+package orusclient
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides which failed requests are sent again and how long to
+// wait between attempts. Writes carry the same idempotency key on every
+// attempt, so retrying one the server already applied doesn't repeat it.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first;
+	// 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt; it doubles after
+	// each further attempt, with jitter, up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts over about a second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// NoRetries sends every request once
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+// shouldRetry reports whether attempt, which returned resp or err, should
+// be followed by another
+func (p RetryPolicy) shouldRetry(attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// delay is how long to wait after attempt. A Retry-After header, sent with
+// rate limiting, takes precedence.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, p.MaxDelay)
+		}
+	}
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	// Jitter spreads out clients that failed at the same moment
+	return d/2 + rand.N(d/2+1)
+}
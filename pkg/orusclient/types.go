@@ -0,0 +1,207 @@
+package orusclient
+
+import "time"
+
+// Transaction is a payment, transfer or refund
+type Transaction struct {
+	ID               uint           `json:"ID"`
+	TransactionID    string         `json:"TransactionID"`
+	Type             string         `json:"Type"`
+	Status           string         `json:"Status"`
+	SenderID         uint           `json:"SenderID"`
+	ReceiverID       uint           `json:"ReceiverID"`
+	Amount           float64        `json:"Amount"`
+	Fee              float64        `json:"Fee"`
+	Currency         string         `json:"Currency"`
+	Description      string         `json:"Description"`
+	Reference        string         `json:"Reference"`
+	MerchantName     string         `json:"MerchantName"`
+	MerchantCategory string         `json:"MerchantCategory"`
+	Category         string         `json:"Category"`
+	Metadata         map[string]any `json:"Metadata"`
+	CreatedAt        time.Time      `json:"CreatedAt"`
+}
+
+// Balance is a wallet's balance, less the funds held for pending payments
+type Balance struct {
+	WalletID  uint    `json:"wallet_id"`
+	Currency  string  `json:"currency"`
+	Total     float64 `json:"total_balance"`
+	Held      float64 `json:"held_balance"`
+	Available float64 `json:"available_balance"`
+	Holds     []Hold  `json:"holds"`
+}
+
+// Hold reserves part of a wallet's balance
+type Hold struct {
+	ID        uint       `json:"id"`
+	Amount    float64    `json:"amount"`
+	Type      string     `json:"type"`
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason"`
+	Reference string     `json:"reference"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Page describes where a list response sits in the full result
+type Page struct {
+	CurrentPage int   `json:"current_page"`
+	PerPage     int   `json:"per_page"`
+	TotalItems  int64 `json:"total_items"`
+	TotalPages  int64 `json:"total_pages"`
+}
+
+// ListOptions pages through a list; zero values use the API's defaults
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+// SendMoneyRequest pays another user. Give ReceiverID or To, an @handle or
+// phone number.
+type SendMoneyRequest struct {
+	ReceiverID  uint    `json:"receiver_id,omitempty"`
+	To          string  `json:"to,omitempty"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description,omitempty"`
+}
+
+// QRPaymentRequest pays the owner of a QR code
+type QRPaymentRequest struct {
+	QRCode        string         `json:"qr_code"`
+	Amount        float64        `json:"amount"`
+	Description   string         `json:"description,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	LoyaltyPoints int64          `json:"loyalty_points,omitempty"`
+}
+
+// ReceiptItem is a line of an itemized charge
+type ReceiptItem struct {
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// PaymentCodeRequest asks for a numeric one-time payment code
+type PaymentCodeRequest struct {
+	Amount    float64 `json:"amount"`
+	Digits    int     `json:"digits,omitempty"`     // 6 to 8, 6 by default
+	ExpiresIn int     `json:"expires_in,omitempty"` // Seconds, 10 minutes by default
+}
+
+// PaymentCode is a one-time code a merchant can charge up to Amount with,
+// once, until ExpiresAt
+type PaymentCode struct {
+	ID        uint      `json:"id"`
+	Code      string    `json:"code"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PaymentIntentRequest asks for a payment to the logged-in user
+type PaymentIntentRequest struct {
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description,omitempty"`
+	Reference   string  `json:"reference,omitempty"`
+	ExpiresIn   int     `json:"expires_in,omitempty"` // Seconds, 15 minutes by default
+}
+
+// PaymentIntent is a signed payment request. Share Link, or write it to an
+// NFC tag; the payer confirms it with Token.
+type PaymentIntent struct {
+	ID            uint      `json:"id"`
+	RecipientID   uint      `json:"recipient_id"`
+	RecipientName string    `json:"recipient_name,omitempty"` // Set on previews
+	IsMerchant    bool      `json:"is_merchant,omitempty"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Description   string    `json:"description,omitempty"`
+	Reference     string    `json:"reference,omitempty"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Token         string    `json:"token,omitempty"` // Set on creation
+	Link          string    `json:"link,omitempty"`
+	AppURI        string    `json:"app_uri,omitempty"`
+}
+
+// ChargeRequest charges a customer directly, by the payment code they show
+// or the one-time code they read out
+type ChargeRequest struct {
+	Amount       float64       `json:"amount"`
+	Description  string        `json:"description,omitempty"`
+	PaymentType  string        `json:"payment_type,omitempty"`
+	PaymentCode  string        `json:"payment_code"`
+	Items        []ReceiptItem `json:"items,omitempty"`
+	TaxAmount    float64       `json:"tax_amount,omitempty"`
+	Tip          float64       `json:"tip,omitempty"`
+	ReceiptEmail string        `json:"receipt_email,omitempty"`
+	OperatorID   uint          `json:"operator_id,omitempty"`
+	OperatorPIN  string        `json:"operator_pin,omitempty"`
+	TerminalID   uint          `json:"terminal_id,omitempty"`
+}
+
+// RefundRequest refunds all or part of a charge
+type RefundRequest struct {
+	TransactionID string  `json:"transaction_id"`
+	Amount        float64 `json:"amount"`
+	Reason        string  `json:"reason,omitempty"`
+	OperatorID    uint    `json:"operator_id,omitempty"`
+	OperatorPIN   string  `json:"operator_pin,omitempty"`
+	TerminalID    uint    `json:"terminal_id,omitempty"`
+}
+
+// ReverseRequest reverses a charge taken in error
+type ReverseRequest struct {
+	TransactionID string `json:"transaction_id"`
+	Reason        string `json:"reason"`
+	OperatorID    uint   `json:"operator_id,omitempty"`
+	OperatorPIN   string `json:"operator_pin,omitempty"`
+}
+
+// Reversal links a reversed charge to the transaction that undid it
+type Reversal struct {
+	ID                    uint      `json:"id"`
+	OriginalTransactionID uint      `json:"original_transaction_id"`
+	ReversalTransactionID uint      `json:"reversal_transaction_id"`
+	Amount                float64   `json:"amount"`
+	FeeReturned           float64   `json:"fee_returned"`
+	Reason                string    `json:"reason"`
+	Actor                 string    `json:"actor"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// CreateCheckoutSessionRequest opens a checkout session for an online order
+type CreateCheckoutSessionRequest struct {
+	Amount           float64        `json:"amount"`
+	Description      string         `json:"description,omitempty"`
+	ClientReference  string         `json:"client_reference,omitempty"`
+	Metadata         map[string]any `json:"metadata,omitempty"`
+	SuccessURL       string         `json:"success_url,omitempty"`
+	CancelURL        string         `json:"cancel_url,omitempty"`
+	ExpiresInMinutes int            `json:"expires_in_minutes,omitempty"`
+}
+
+// CheckoutSession is an online order waiting for, or done with, payment.
+// Send the payer to RedirectURL or show them QRPayload.
+type CheckoutSession struct {
+	ID              string         `json:"id"`
+	Source          string         `json:"source"`
+	MerchantID      uint           `json:"merchant_id"`
+	UserID          uint           `json:"user_id"`
+	Amount          float64        `json:"amount"`
+	Currency        string         `json:"currency"`
+	Description     string         `json:"description"`
+	ClientReference string         `json:"client_reference,omitempty"`
+	Metadata        map[string]any `json:"metadata"`
+	SuccessURL      string         `json:"success_url,omitempty"`
+	CancelURL       string         `json:"cancel_url,omitempty"`
+	Status          string         `json:"status"`
+	TransactionID   *uint          `json:"transaction_id,omitempty"`
+	ExpiresAt       time.Time      `json:"expires_at"`
+	CompletedAt     *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	RedirectURL     string         `json:"redirect_url,omitempty"`
+	QRPayload       string         `json:"qr_payload,omitempty"`
+}
@@ -0,0 +1,44 @@
+package orusclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetBalance returns the user's wallet balance
+func (c *Client) GetBalance(ctx context.Context) (*Balance, error) {
+	var out struct {
+		Balance Balance `json:"balance"`
+	}
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/wallet/balance", auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out.Balance, nil
+}
+
+// ListTransactions pages through the transactions the user sent or
+// received
+func (c *Client) ListTransactions(ctx context.Context, opts ListOptions) ([]Transaction, Page, error) {
+	var out struct {
+		Data []Transaction `json:"data"`
+		Meta Page          `json:"meta"`
+	}
+	req := request{method: http.MethodGet, path: "/api/transactions", query: opts.values(), auth: true}
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, Page{}, err
+	}
+	return out.Data, out.Meta, nil
+}
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return v
+}